@@ -0,0 +1,48 @@
+"""List GeoServer connections, or show one.
+
+Usage:
+    cloudbench connections
+    cloudbench connections --output json
+    cloudbench connections "Production GeoServer" --output yaml
+    cloudbench connections --quiet | xargs -n1 cloudbench lint
+"""
+
+from django.core.management.base import CommandError
+
+from apps.core.config import Connection, config_manager
+from apps.core.output import OutputCommand
+
+COLUMNS = ["id", "name", "url", "username", "active", "source"]
+
+
+def _record(conn: Connection) -> dict:
+    return {
+        "id": conn.id,
+        "name": conn.name,
+        "url": conn.url,
+        "username": conn.username,
+        "active": conn.is_active,
+        "source": conn.source,
+    }
+
+
+class Command(OutputCommand):
+    """List the GeoServer connections of the current profile."""
+
+    help = "List GeoServer connections, or show one by ID or name"
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument("connection", nargs="?", help="Connection ID or name to show")
+        self.add_output_arguments(parser)
+
+    def handle(self, *args, **options):
+        """Print the connections."""
+        connections = config_manager.list_connections()
+        ref = options["connection"]
+        if ref:
+            connections = [c for c in connections if ref in (c.id, c.name)][:1]
+            if not connections:
+                raise CommandError(f"Connection not found: {ref}")
+
+        self.write_records([_record(c) for c in connections], options, COLUMNS)
@@ -3,6 +3,15 @@
 from rest_framework import serializers
 
 from apps.core.config import Connection
+from apps.core.confirmation import CONFIRM_AUTO, CONFIRM_POLICIES, requires_typed_confirmation
+from apps.core.connection_groups import (
+    COLOR_RE,
+    MAX_BADGE_LENGTH,
+    badge_color,
+    normalize_environment,
+)
+from apps.core.oidc import AUTH_BASIC, AUTH_OIDC, AUTH_TYPES, DEFAULT_SCOPE, is_signed_in
+from apps.geoserver.rest_xml import REST_FORMATS
 
 
 class ConnectionSerializer(serializers.Serializer):
@@ -11,9 +20,106 @@ class ConnectionSerializer(serializers.Serializer):
     id = serializers.CharField(read_only=True)
     name = serializers.CharField(max_length=255)
     url = serializers.URLField()
-    username = serializers.CharField(max_length=255)
-    password = serializers.CharField(max_length=255, write_only=True)
+    # Not needed when signing in through OIDC
+    username = serializers.CharField(
+        max_length=255, required=False, allow_blank=True, default=""
+    )
+    password = serializers.CharField(
+        max_length=255, write_only=True, required=False, allow_blank=True, default=""
+    )
     is_active = serializers.BooleanField(default=False)
+    autoTruncateCache = serializers.BooleanField(source="auto_truncate_cache", default=False)
+    maxConcurrentRequests = serializers.IntegerField(
+        source="max_concurrent_requests", min_value=0, default=0
+    )
+    maxRequestsPerSecond = serializers.FloatField(
+        source="max_requests_per_second", min_value=0, default=0
+    )
+    poolMaxIdle = serializers.IntegerField(source="pool_max_idle", min_value=0, default=10)
+    poolIdleTimeout = serializers.FloatField(source="pool_idle_timeout", min_value=0, default=5.0)
+    http2 = serializers.BooleanField(default=False)
+    responseTimeout = serializers.FloatField(source="response_timeout", min_value=1, default=30.0)
+    httpCompression = serializers.BooleanField(source="http_compression", default=True)
+    # JSON, XML, or "auto" to switch to XML when JSON can't be read
+    restFormat = serializers.ChoiceField(REST_FORMATS, source="rest_format", default="auto")
+    maxResponseMb = serializers.FloatField(source="max_response_mb", min_value=0, default=64)
+    publicUrl = serializers.URLField(
+        source="public_url", required=False, allow_blank=True, default=""
+    )
+    # Comma-separated node URLs of a cluster, each reloaded and reset in turn
+    clusterUrls = serializers.CharField(
+        source="cluster_urls", required=False, allow_blank=True, default=""
+    )
+    # Node catalog changes go to; the first cluster URL when blank
+    clusterPrimary = serializers.CharField(
+        source="cluster_primary", required=False, allow_blank=True, default=""
+    )
+    capabilityOverrides = serializers.DictField(
+        source="capability_overrides", child=serializers.BooleanField(), default=dict
+    )
+    group = serializers.CharField(max_length=255, required=False, allow_blank=True, default="")
+    environment = serializers.CharField(
+        max_length=32, required=False, allow_blank=True, default=""
+    )
+    badge = serializers.CharField(
+        max_length=MAX_BADGE_LENGTH, required=False, allow_blank=True, default=""
+    )
+    color = serializers.RegexField(COLOR_RE, required=False, allow_blank=True, default="")
+    readOnly = serializers.BooleanField(source="read_only", default=False)
+    confirmPolicy = serializers.ChoiceField(
+        CONFIRM_POLICIES, source="confirm_policy", default=CONFIRM_AUTO
+    )
+    authType = serializers.ChoiceField(AUTH_TYPES, source="auth_type", default=AUTH_BASIC)
+    oidcIssuer = serializers.URLField(
+        source="oidc_issuer", required=False, allow_blank=True, default=""
+    )
+    oidcClientId = serializers.CharField(
+        source="oidc_client_id", max_length=255, required=False, allow_blank=True, default=""
+    )
+    oidcScope = serializers.CharField(
+        source="oidc_scope", max_length=255, required=False, default=DEFAULT_SCOPE
+    )
+
+    def validate(self, attrs):
+        """Basic auth needs a username and password, OIDC an issuer and client ID."""
+
+        def value(key):
+            return attrs.get(key, getattr(self.instance, key, ""))
+
+        if value("auth_type") == AUTH_OIDC:
+            required = {"oidcIssuer": "oidc_issuer", "oidcClientId": "oidc_client_id"}
+        else:
+            required = {"username": "username", "password": "password"}
+        missing = {
+            name: "This field is required." for name, key in required.items() if not value(key)
+        }
+        if missing:
+            raise serializers.ValidationError(missing)
+
+        primary = value("cluster_primary").strip().rstrip("/")
+        nodes = [url.rstrip("/") for url in value("cluster_urls").split(",") if url]
+        if primary and primary not in nodes:
+            raise serializers.ValidationError(
+                {"clusterPrimary": "The primary must be one of the cluster URLs."}
+            )
+        attrs["cluster_primary"] = primary
+        return attrs
+
+    def validate_clusterUrls(self, value):
+        """Check each node URL and keep them comma-separated."""
+        urls = [url.strip() for url in value.split(",") if url.strip()]
+        invalid = [url for url in urls if not url.startswith(("http://", "https://"))]
+        if invalid:
+            raise serializers.ValidationError(f"Not an http(s) URL: {', '.join(invalid)}")
+        return ",".join(urls)
+
+    def validate_group(self, value):
+        """Trim the folder name."""
+        return value.strip()
+
+    def validate_environment(self, value):
+        """Store environments lowercase so "Production" matches "production"."""
+        return normalize_environment(value)
 
     def create(self, validated_data):
         """Create a new connection."""
@@ -42,5 +148,47 @@ class ConnectionResponseSerializer(serializers.Serializer):
     url = serializers.URLField()
     username = serializers.CharField()
     is_active = serializers.BooleanField()
+    autoTruncateCache = serializers.BooleanField(source="auto_truncate_cache")
+    maxConcurrentRequests = serializers.IntegerField(source="max_concurrent_requests")
+    maxRequestsPerSecond = serializers.FloatField(source="max_requests_per_second")
+    poolMaxIdle = serializers.IntegerField(source="pool_max_idle")
+    poolIdleTimeout = serializers.FloatField(source="pool_idle_timeout")
+    http2 = serializers.BooleanField()
+    responseTimeout = serializers.FloatField(source="response_timeout")
+    httpCompression = serializers.BooleanField(source="http_compression")
+    restFormat = serializers.CharField(source="rest_format")
+    maxResponseMb = serializers.FloatField(source="max_response_mb")
+    publicUrl = serializers.CharField(source="public_url")
+    clusterUrls = serializers.CharField(source="cluster_urls")
+    clusterPrimary = serializers.CharField(source="cluster_primary")
+    capabilityOverrides = serializers.DictField(
+        source="capability_overrides", child=serializers.BooleanField()
+    )
+    group = serializers.CharField()
+    environment = serializers.CharField()
+    badge = serializers.CharField()
+    color = serializers.CharField()
+    badgeColor = serializers.SerializerMethodField(method_name="get_badge_color")
+    readOnly = serializers.BooleanField(source="read_only")
+    confirmPolicy = serializers.CharField(source="confirm_policy")
+    typedConfirmation = serializers.SerializerMethodField(method_name="get_typed_confirmation")
+    authType = serializers.CharField(source="auth_type")
+    oidcIssuer = serializers.CharField(source="oidc_issuer")
+    oidcClientId = serializers.CharField(source="oidc_client_id")
+    oidcScope = serializers.CharField(source="oidc_scope")
+    signedIn = serializers.SerializerMethodField(method_name="get_signed_in")
+    source = serializers.CharField()  # "config", or "env"/"flag" for unsaved connections
 
     # Don't include password in responses
+
+    def get_badge_color(self, obj):
+        """Badge color, falling back to the environment's color."""
+        return badge_color(obj)
+
+    def get_typed_confirmation(self, obj):
+        """Whether destructive operations need the resource name typed."""
+        return requires_typed_confirmation(obj)
+
+    def get_signed_in(self, obj):
+        """Whether an OIDC connection has a refresh token; None for basic auth."""
+        return is_signed_in(obj) if obj.auth_type == AUTH_OIDC else None
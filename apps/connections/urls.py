@@ -22,4 +22,29 @@ urlpatterns = [
         views.ConnectionInfoView.as_view(),
         name="connection-info",
     ),
+    path(
+        "connections/<str:conn_id>/signin",
+        views.ConnectionSignInView.as_view(),
+        name="connection-signin",
+    ),
+    path(
+        "connections/<str:conn_id>/capabilities",
+        views.ConnectionCapabilitiesView.as_view(),
+        name="connection-capabilities",
+    ),
+    path(
+        "connections/<str:conn_id>/permissions",
+        views.ConnectionPermissionsView.as_view(),
+        name="connection-permissions",
+    ),
+    path(
+        "connections/<str:conn_id>/extensions",
+        views.ConnectionExtensionsView.as_view(),
+        name="connection-extensions",
+    ),
+    path(
+        "connections/<str:conn_id>/probe",
+        views.ConnectionProbeView.as_view(),
+        name="connection-probe",
+    ),
 ]
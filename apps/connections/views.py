@@ -8,14 +8,34 @@ from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.config import Connection, config_manager
+from apps.core.exceptions import GeoServerError, TokenError
 from apps.core.managers import client_manager
+from apps.core.oidc import (
+    AUTH_OIDC,
+    connection_auth,
+    is_signed_in,
+    pending_sign_in,
+    poll_sign_in,
+    sign_out,
+    start_sign_in,
+)
+from apps.geoserver.client import GeoServerClientManager, get_geoserver_client
+from apps.geoserver.probe import run_probe
 
 from .serializers import ConnectionResponseSerializer, ConnectionSerializer
 
 
-def test_geoserver_connection(url: str, username: str, password: str) -> tuple[bool, str, dict]:
+def test_geoserver_connection(
+    url: str, username: str, password: str, auth: httpx.Auth | None = None
+) -> tuple[bool, str, dict]:
     """Test a GeoServer connection.
 
+    Args:
+        url: GeoServer URL
+        username: Username for basic auth
+        password: Password for basic auth
+        auth: Auth used instead of basic auth, e.g. an OIDC bearer token
+
     Returns:
         Tuple of (success, message, server_info)
     """
@@ -30,7 +50,7 @@ def test_geoserver_connection(url: str, username: str, password: str) -> tuple[b
         with httpx.Client(timeout=10.0) as client:
             response = client.get(
                 f"{base_url}/rest/about/version.json",
-                auth=httpx.BasicAuth(username, password),
+                auth=auth or httpx.BasicAuth(username, password),
             )
 
             if response.status_code == 200:
@@ -56,16 +76,27 @@ def test_geoserver_connection(url: str, username: str, password: str) -> tuple[b
         return False, "Could not connect to server - check URL and network", {}
     except httpx.TimeoutException:
         return False, "Connection timed out", {}
+    except TokenError as e:
+        return False, e.message, {}
     except Exception as e:
         return False, f"Connection error: {str(e)}", {}
 
 
+def _not_saved_response(conn: Connection) -> Response:
+    """Reject changes to a connection defined by environment variables or flags."""
+    origin = "environment variables" if conn.source == "env" else "command line flags"
+    return Response(
+        {"error": f"Connection '{conn.name}' is defined by {origin} and can't be changed"},
+        status=status.HTTP_400_BAD_REQUEST,
+    )
+
+
 class ConnectionListView(APIView):
     """List all connections or create a new one."""
 
     def get(self, request):
         """List all GeoServer connections."""
-        connections = config_manager.config.connections
+        connections = config_manager.list_connections()
         serializer = ConnectionResponseSerializer(connections, many=True)
         return Response(serializer.data)
 
@@ -138,13 +169,18 @@ class ConnectionDetailView(APIView):
                 {"error": "Connection not found"}, status=status.HTTP_404_NOT_FOUND
             )
 
+        if conn.source != "config":
+            return _not_saved_response(conn)
+
         serializer = ConnectionSerializer(conn, data=request.data, partial=True)
         if serializer.is_valid():
             updated_conn = serializer.update(conn, serializer.validated_data)
             config_manager.update_connection(updated_conn)
 
-            # Remove cached client so it gets recreated with new credentials
+            # Remove cached clients so they get recreated with new credentials and limits
             client_manager.remove_client(conn_id)
+            client_manager.remove_client(f"gwc_{conn_id}")
+            GeoServerClientManager().remove_client(conn_id)
 
             response_serializer = ConnectionResponseSerializer(updated_conn)
             return Response(response_serializer.data)
@@ -159,8 +195,18 @@ class ConnectionDetailView(APIView):
                 {"error": "Connection not found"}, status=status.HTTP_404_NOT_FOUND
             )
 
+        if conn.source != "config":
+            return _not_saved_response(conn)
+
         config_manager.remove_connection(conn_id)
         client_manager.remove_client(conn_id)
+        client_manager.remove_client(f"gwc_{conn_id}")
+        GeoServerClientManager().remove_client(conn_id)
+        if conn.auth_type == AUTH_OIDC:
+            try:
+                sign_out(conn)
+            except TokenError:
+                pass  # The connection is gone either way
 
         return Response(status=status.HTTP_204_NO_CONTENT)
 
@@ -177,7 +223,7 @@ class ConnectionTestExistingView(APIView):
             )
 
         success, message, info = test_geoserver_connection(
-            conn.url, conn.username, conn.password
+            conn.url, conn.username, conn.password, connection_auth(conn)
         )
 
         return Response(
@@ -201,23 +247,22 @@ class ConnectionInfoView(APIView):
             )
 
         try:
-            client = client_manager.get_client(
-                conn_id, conn.url, conn.username, conn.password
-            )
-
-            # Get server version
-            version_response = client.get("/rest/about/version.json")
-            version_data = version_response.json() if version_response.status_code == 200 else {}
-
-            # Get manifest (detailed component versions)
-            manifest_response = client.get("/rest/about/manifest.json")
-            manifest_data = (
-                manifest_response.json() if manifest_response.status_code == 200 else {}
-            )
-
-            # Get status
-            status_response = client.get("/rest/about/status.json")
-            status_data = status_response.json() if status_response.status_code == 200 else {}
+            # The connection's own client, so requests share its cached transport
+            client = get_geoserver_client(conn_id)
+
+            def read(path: str) -> dict:
+                """Read a document, empty if the server answers with an error."""
+                try:
+                    return client._get_json(path)
+                except GeoServerError as e:
+                    if e.status_code is None:
+                        raise
+                    return {}
+
+            version_data = read("/rest/about/version.json")
+            # Detailed component versions
+            manifest_data = read("/rest/about/manifest.json")
+            status_data = read("/rest/about/status.json")
 
             return Response(
                 {
@@ -232,3 +277,138 @@ class ConnectionInfoView(APIView):
                 {"error": f"Failed to get server info: {str(e)}"},
                 status=status.HTTP_502_BAD_GATEWAY,
             )
+
+
+class ConnectionSignInView(APIView):
+    """Sign a connection in to its OIDC provider with the device flow."""
+
+    def _get_oidc_connection(self, conn_id):
+        """Get a connection using OIDC, or the error response to send."""
+        conn = config_manager.get_connection(conn_id)
+        if not conn:
+            return None, Response(
+                {"error": "Connection not found"}, status=status.HTTP_404_NOT_FOUND
+            )
+        if conn.auth_type != AUTH_OIDC:
+            return None, Response(
+                {"error": f"Connection '{conn.name}' doesn't sign in through OIDC"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        return conn, None
+
+    def get(self, request, conn_id):
+        """Report whether the connection is signed in, checking a waiting sign-in once."""
+        conn, error = self._get_oidc_connection(conn_id)
+        if error:
+            return error
+
+        device = pending_sign_in(conn_id)
+        try:
+            if device and poll_sign_in(conn, device):
+                device = None
+            signed_in = is_signed_in(conn)
+        except TokenError as e:
+            return Response({"error": e.message}, status=status.HTTP_400_BAD_REQUEST)
+        return Response(
+            {"signedIn": signed_in, "pending": device.to_dict() if device else None}
+        )
+
+    def post(self, request, conn_id):
+        """Start a sign-in; the user enters the returned code at the verification URL."""
+        conn, error = self._get_oidc_connection(conn_id)
+        if error:
+            return error
+
+        try:
+            device = start_sign_in(conn)
+        except TokenError as e:
+            return Response({"error": e.message}, status=status.HTTP_502_BAD_GATEWAY)
+        return Response({"signedIn": False, "pending": device.to_dict()})
+
+    def delete(self, request, conn_id):
+        """Sign out, forgetting the refresh token."""
+        conn, error = self._get_oidc_connection(conn_id)
+        if error:
+            return error
+
+        try:
+            sign_out(conn)
+        except TokenError as e:
+            return Response({"error": e.message}, status=status.HTTP_500_INTERNAL_SERVER_ERROR)
+        return Response(status=status.HTTP_204_NO_CONTENT)
+
+
+class ConnectionCapabilitiesView(APIView):
+    """Get the features supported by a connection's GeoServer version."""
+
+    def get(self, request, conn_id):
+        """Get the capability matrix, with config overrides applied."""
+        if not config_manager.get_connection(conn_id):
+            return Response(
+                {"error": "Connection not found"}, status=status.HTTP_404_NOT_FOUND
+            )
+
+        client = GeoServerClientManager().get_client(conn_id)
+        return Response(client.capabilities.to_dict())
+
+
+class ConnectionPermissionsView(APIView):
+    """Get what a connection's credentials may do on its server."""
+
+    def get(self, request, conn_id):
+        """Get the probed permissions; writes are refused for read-only users."""
+        if not config_manager.get_connection(conn_id):
+            return Response(
+                {"error": "Connection not found"}, status=status.HTTP_404_NOT_FOUND
+            )
+
+        client = GeoServerClientManager().get_client(conn_id)
+        return Response(client.permissions.to_dict())
+
+
+class ConnectionExtensionsView(APIView):
+    """List the extensions and community modules installed on a connection's server."""
+
+    def get(self, request, conn_id):
+        """Get the installed modules found in the server's jar manifests."""
+        if not config_manager.get_connection(conn_id):
+            return Response(
+                {"error": "Connection not found"}, status=status.HTTP_404_NOT_FOUND
+            )
+
+        client = GeoServerClientManager().get_client(conn_id)
+        try:
+            modules = client.get_installed_modules()
+        except GeoServerError as e:
+            return Response(
+                {"error": f"Failed to read manifests: {e.message}"},
+                status=e.status_code or status.HTTP_502_BAD_GATEWAY,
+            )
+        return Response({"modules": [module.to_dict() for module in modules]})
+
+
+class ConnectionProbeView(APIView):
+    """Measure a connection's latency, render time and download throughput."""
+
+    def post(self, request, conn_id):
+        """Time REST round trips, a small GetMap and a WFS download.
+
+        Request body, optional:
+        - layer: Sample layer as workspace:layer; the first vector layer by default
+
+        Failed measurements are reported on their steps; the findings say
+        whether the link or the server looks slow.
+        """
+        if not config_manager.get_connection(conn_id):
+            return Response(
+                {"error": "Connection not found"}, status=status.HTTP_404_NOT_FOUND
+            )
+
+        layer = (request.data.get("layer") or "").strip() or None
+        if layer and ":" not in layer:
+            return Response(
+                {"error": "layer must be given as workspace:layer"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        client = GeoServerClientManager().get_client(conn_id)
+        return Response(run_probe(client, layer).to_dict())
@@ -0,0 +1,77 @@
+"""SHA-256 checksums for uploaded and exported files.
+
+Exports are written with a checksum file next to them, in the format
+sha256sum reads ("<hex digest>  <file name>"), so an archive can be
+checked before it is restored, with this module or with sha256sum -c.
+"""
+
+import hashlib
+from collections.abc import Iterable
+from pathlib import Path
+
+# Suffix of the checksum file written next to an export
+CHECKSUM_SUFFIX = ".sha256"
+
+# Bytes read at a time when hashing files
+_READ_SIZE = 1024 * 1024
+
+
+def sha256_bytes(data: bytes) -> str:
+    """Get the hex SHA-256 digest of some bytes."""
+    return hashlib.sha256(data).hexdigest()
+
+
+def sha256_chunks(chunks: Iterable[bytes]) -> str:
+    """Get the hex SHA-256 digest of data read in pieces, e.g. a streamed download."""
+    digest = hashlib.sha256()
+    for chunk in chunks:
+        digest.update(chunk)
+    return digest.hexdigest()
+
+
+def sha256_file(path: Path) -> str:
+    """Get the hex SHA-256 digest of a file, reading it in pieces."""
+    digest = hashlib.sha256()
+    with open(path, "rb") as f:
+        while chunk := f.read(_READ_SIZE):
+            digest.update(chunk)
+    return digest.hexdigest()
+
+
+def checksum_path(path: Path) -> Path:
+    """Get the checksum file of a file, e.g. backup.zip -> backup.zip.sha256."""
+    return path.with_name(path.name + CHECKSUM_SUFFIX)
+
+
+def write_checksum_file(path: Path, digest: str | None = None) -> Path:
+    """Write the checksum file of a file.
+
+    Args:
+        path: File to record
+        digest: Its SHA-256 digest, when already known
+
+    Returns:
+        Path of the checksum file
+    """
+    target = checksum_path(path)
+    target.write_text(f"{digest or sha256_file(path)}  {path.name}\n")
+    return target
+
+
+def verify_checksum_file(path: Path) -> bool | None:
+    """Check a file against the checksum file written next to it.
+
+    Returns:
+        True when it matches, False when it doesn't and None when there's
+        no checksum file
+
+    Raises:
+        ValueError: If the checksum file can't be read
+    """
+    target = checksum_path(path)
+    if not target.exists():
+        return None
+    fields = target.read_text().split()
+    if not fields or len(fields[0]) != 64:
+        raise ValueError(f"{target.name} is not a SHA-256 checksum file")
+    return fields[0].lower() == sha256_file(path)
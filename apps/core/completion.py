@@ -0,0 +1,311 @@
+"""Shell completion and interactive pickers for management commands.
+
+`cloudbench completion bash|zsh|fish` prints a script that calls back into
+`cloudbench completion --complete` with the words typed so far. Arguments
+naming catalog resources are completed from the active connection; the
+lists are cached for a few minutes so completion stays fast.
+
+When a command is run interactively without a required argument, it can
+offer a fuzzy picker over the same candidates instead of failing.
+"""
+
+import json
+import logging
+import os
+import sys
+import time
+from collections.abc import Callable
+from typing import TextIO
+
+from .config import config_manager, get_cache_dir
+
+logger = logging.getLogger(__name__)
+
+CACHE_FILE = "completion.json"
+CACHE_TTL = 300  # Seconds
+
+SHELLS = ("bash", "zsh", "fish")
+
+# Command -> kinds of its positional arguments
+COMMAND_ARGS: dict[str, list[str]] = {
+    "completion": ["shell"],
+    "connections": ["connection"],
+    "export_map": ["connection", "layer"],
+    "import_styles": ["source", "connection"],
+    "lint": ["connection"],
+    "publish_qgis": ["source", "connection"],
+    "reload_catalog": ["connection"],
+    "style_diff": ["source", "connection"],
+    "workspace_usage": ["connection"],
+}
+
+# Option -> kind of its value
+OPTION_ARGS: dict[str, str] = {
+    "--against": "connection",
+    "--check": "check",
+    "--output": "output",
+    "-o": "output",
+    "--profile": "profile",
+    "--workspace": "workspace",
+    "-w": "workspace",
+    "--store": "store",
+    "--layer": "layer",
+    "--style": "style",
+}
+
+# Kinds listed from the GeoServer catalog
+CATALOG_KINDS = ("workspace", "store", "layer", "style")
+
+
+def _catalog_names(conn_id: str, kind: str) -> list[str]:
+    """List catalog names of a kind; store, layer and style names are qualified."""
+    from apps.geoserver.client import get_geoserver_client
+
+    client = get_geoserver_client(conn_id)
+    workspaces = sorted(ws["name"] for ws in client.list_workspaces() if ws.get("name"))
+    if kind == "workspace":
+        return workspaces
+
+    names = []
+    if kind == "style":
+        names.extend(s["name"] for s in client.list_styles() if s.get("name"))
+    for ws in workspaces:
+        if kind == "store":
+            items = client.list_datastores(ws) + client.list_coveragestores(ws)
+        elif kind == "layer":
+            items = client.list_layers(ws)
+        else:
+            items = client.list_styles(ws)
+        names.extend(f"{ws}:{item['name']}" for item in items if item.get("name"))
+    return sorted(set(names))
+
+
+def _cached(key: str, load: Callable[[], list[str]]) -> list[str]:
+    path = get_cache_dir() / CACHE_FILE
+    try:
+        cache = json.loads(path.read_text())
+    except (OSError, ValueError):
+        cache = {}
+
+    entry = cache.get(key)
+    if entry and time.time() - entry.get("time", 0) < CACHE_TTL:
+        return entry["values"]
+
+    values = load()
+    cache[key] = {"time": time.time(), "values": values}
+    try:
+        path.write_text(json.dumps(cache))
+    except OSError as e:
+        logger.debug("Could not write completion cache: %s", e)
+    return values
+
+
+def default_connection_id() -> str | None:
+    """Get the connection to complete catalog names from.
+
+    The active connection, or the only one when exactly one is defined.
+    """
+    active = config_manager.get_active_connection()
+    if active:
+        return active.id
+    connections = config_manager.list_connections()
+    return connections[0].id if len(connections) == 1 else None
+
+
+def candidates(kind: str, conn_id: str | None = None) -> list[str]:
+    """List the values an argument of a kind can take.
+
+    Args:
+        kind: Argument kind, e.g. "connection" or "layer"
+        conn_id: Connection for catalog kinds; the default connection when omitted
+
+    Returns:
+        Candidate values; empty when they can't be listed
+    """
+    if kind == "connection":
+        return [c.id for c in config_manager.list_connections()]
+    if kind == "profile":
+        return config_manager.list_profiles()
+    if kind == "shell":
+        return list(SHELLS)
+    if kind == "output":
+        from .output import FORMATS
+
+        return list(FORMATS)
+    if kind == "check":
+        from apps.geoserver.lint import CHECKS
+
+        return list(CHECKS)
+    if kind in CATALOG_KINDS:
+        conn_id = conn_id or default_connection_id()
+        if not conn_id:
+            return []
+        try:
+            return _cached(f"{conn_id}:{kind}", lambda: _catalog_names(conn_id, kind))
+        except Exception as e:
+            # Completion must never print a traceback into the shell
+            logger.debug("Could not list %s names: %s", kind, e)
+            return []
+    return []
+
+
+def complete_words(words: list[str], commands: list[str]) -> list[str]:
+    """Complete the last of the words typed after the program name.
+
+    Args:
+        words: Words typed so far; the last one is being completed and may be empty
+        commands: Available subcommand names
+
+    Returns:
+        Matching candidates
+    """
+    if not words:
+        words = [""]
+    current = words[-1]
+
+    if len(words) == 1:
+        options = commands
+    elif len(words) > 2 and words[-2] in OPTION_ARGS:
+        options = candidates(OPTION_ARGS[words[-2]])
+    elif current.startswith("-"):
+        options = list(OPTION_ARGS)
+    else:
+        # Count positionals, skipping options and their values
+        position = 0
+        skip = False
+        for word in words[1:-1]:
+            if skip:
+                skip = False
+            elif word.startswith("-"):
+                skip = word in OPTION_ARGS
+            else:
+                position += 1
+        kinds = COMMAND_ARGS.get(words[0], [])
+        options = candidates(kinds[position]) if position < len(kinds) else []
+
+    return [option for option in options if option.startswith(current)]
+
+
+def fuzzy_score(query: str, candidate: str) -> int | None:
+    """Score how well a query matches a candidate as a subsequence.
+
+    Returns:
+        Higher is better; None when the query doesn't match
+    """
+    query = query.lower()
+    text = candidate.lower()
+    if not query:
+        return 0
+    if query in text:
+        return 100 - text.index(query)
+
+    score = 0
+    position = -1
+    for char in query:
+        found = text.find(char, position + 1)
+        if found < 0:
+            return None
+        # Reward consecutive characters
+        score += 2 if found == position + 1 else 1
+        position = found
+    return score
+
+
+def fuzzy_filter(query: str, values: list[str]) -> list[str]:
+    """Order values by how well they match a query, dropping non-matches."""
+    scored = [(fuzzy_score(query, value), value) for value in values]
+    matches = [(score, value) for score, value in scored if score is not None]
+    return [value for _, value in sorted(matches, key=lambda sv: (-sv[0], sv[1]))]
+
+
+def fuzzy_pick(
+    values: list[str],
+    prompt: str,
+    stdin: TextIO | None = None,
+    stdout: TextIO | None = None,
+    limit: int = 10,
+) -> str | None:
+    """Let the user pick a value by typing part of it.
+
+    Each round lists the best matches; the user narrows the list by typing,
+    or picks with a number. A query matching one value picks it.
+
+    Args:
+        values: Values to pick from
+        prompt: What is being picked, e.g. "connection"
+        stdin: Input stream; sys.stdin when omitted
+        stdout: Output stream; sys.stdout when omitted
+        limit: Matches shown per round
+
+    Returns:
+        The picked value, or None when not interactive or cancelled
+    """
+    stdin = stdin or sys.stdin
+    stdout = stdout or sys.stdout
+    if not values or not stdin.isatty():
+        return None
+
+    matches = values
+    while True:
+        for i, value in enumerate(matches[:limit], 1):
+            stdout.write(f"  {i}) {value}\n")
+        if len(matches) > limit:
+            stdout.write(f"  ... {len(matches) - limit} more, type to narrow\n")
+        stdout.write(f"Pick a {prompt} (number or text, empty to cancel): ")
+        stdout.flush()
+
+        answer = stdin.readline().strip()
+        if not answer:
+            return None
+        if answer.isdigit() and 1 <= int(answer) <= min(limit, len(matches)):
+            return matches[int(answer) - 1]
+
+        narrowed = fuzzy_filter(answer, values)
+        if len(narrowed) == 1:
+            return narrowed[0]
+        if not narrowed:
+            stdout.write(f"No {prompt} matches '{answer}'\n")
+        else:
+            matches = narrowed
+
+
+_BASH = """# Complete qualified names like topp:roads as one word
+COMP_WORDBREAKS=${{COMP_WORDBREAKS//:}}
+
+_{prog}_complete() {{
+    local IFS=$'\\n'
+    COMPREPLY=($({prog} completion --complete -- "${{COMP_WORDS[@]:1:COMP_CWORD}}" \\
+        2>/dev/null))
+}}
+complete -o default -F _{prog}_complete {prog}
+"""
+
+_ZSH = """#compdef {prog}
+_{prog}_complete() {{
+    local -a candidates
+    candidates=("${{(@f)$({prog} completion --complete -- "${{(@)words[2,CURRENT]}}" \\
+        2>/dev/null)}}")
+    compadd -a candidates
+}}
+compdef _{prog}_complete {prog}
+"""
+
+_FISH = """function __{prog}_complete
+    set -l words (commandline -opc)[2..-1] (commandline -ct)
+    {prog} completion --complete -- $words 2>/dev/null
+end
+complete -c {prog} -f -a '(__{prog}_complete)'
+"""
+
+
+def completion_script(shell: str, prog: str = "cloudbench") -> str:
+    """Get the completion script for a shell."""
+    template = {"bash": _BASH, "zsh": _ZSH, "fish": _FISH}[shell]
+    return template.format(prog=prog)
+
+
+def clear_completion_cache() -> None:
+    """Forget cached catalog names."""
+    path = get_cache_dir() / CACHE_FILE
+    if os.path.exists(path):
+        os.remove(path)
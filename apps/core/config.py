@@ -5,22 +5,47 @@ Uses XDG Base Directory specification for config file location.
 """
 
 import json
+import logging
 import os
+import re
 import shutil
 import threading
 import uuid
 from datetime import datetime
 from pathlib import Path
-from typing import Any, TypeVar
+from typing import TYPE_CHECKING, Any, TypeVar
 
 from pydantic import BaseModel, Field
 
+from .config_migrations import (
+    CONFIG_SCHEMA_VERSION,
+    backup_config,
+    config_version,
+    migrate_config,
+)
+
+if TYPE_CHECKING:
+    from .connection_providers import ConnectionChain
+
+logger = logging.getLogger(__name__)
+
 T = TypeVar("T", bound=BaseModel)
 
 # Config directory names
 CONFIG_DIR = "kartoza-cloudbench"
 OLD_CONFIG_DIR = "kartoza-geoserver-client"  # For migration
 CONFIG_FILE = "config.json"
+PROFILES_DIR = "profiles"
+DEFAULT_PROFILE = "default"
+
+# Environment variables selecting the config, overridden by --profile/--config
+PROFILE_ENV = "CLOUDBENCH_PROFILE"
+CONFIG_ENV = "CLOUDBENCH_CONFIG"
+
+# Environment variable turning plain output on, as --plain does
+PLAIN_ENV = "CLOUDBENCH_PLAIN"
+
+_PROFILE_NAME_RE = re.compile(r"^[A-Za-z0-9][A-Za-z0-9_.-]*$")
 
 
 class Connection(BaseModel):
@@ -32,6 +57,62 @@ class Connection(BaseModel):
     username: str
     password: str
     is_active: bool = False
+    auto_truncate_cache: bool = False  # Truncate GWC tiles when layer data is replaced
+    max_concurrent_requests: int = 0  # 0 = unlimited
+    max_requests_per_second: float = 0  # 0 = unlimited
+    pool_max_idle: int = 10  # Keep-alive connections kept per host
+    pool_idle_timeout: float = 5.0  # Seconds before an idle connection is closed
+    http2: bool = False
+    response_timeout: float = 30.0  # Seconds to wait for a response
+    http_compression: bool = True  # gzip responses and large style uploads
+    # "json", "xml", or "auto": JSON until a response of it can't be read, then XML
+    rest_format: str = "auto"
+    max_response_mb: float = 64  # Refuse bigger responses; 0 = unlimited
+    public_url: str = ""  # Base URL clients use for OWS/preview, if it differs from url
+    cluster_urls: str = ""  # Comma-separated URLs of every node of a clustered GeoServer
+    cluster_primary: str = ""  # Node catalog changes go to; the first cluster URL when empty
+    capability_overrides: dict[str, bool] = Field(default_factory=dict)  # Force features on/off
+    group: str = ""  # Folder in the connections tree; ungrouped when empty
+    environment: str = ""  # e.g. "development", "staging" or "production"
+    badge: str = ""  # Short emoji or text shown next to the name
+    color: str = ""  # Badge color as #rrggbb; the environment's color when empty
+    read_only: bool = False  # Refuse requests that would change the server
+    confirm_policy: str = "auto"  # "auto", "simple" or "typed"; auto types on production
+    auth_type: str = "basic"  # "basic", or "oidc" for a bearer token from an OAuth2 proxy
+    oidc_issuer: str = ""  # e.g. https://sso.example.com/realms/gis
+    oidc_client_id: str = ""  # Public client allowed to use the device flow
+    oidc_scope: str = "openid offline_access"
+    source: str = Field(default="config", exclude=True)  # "config", "env" or "flag"
+
+    @property
+    def public_base_url(self) -> str:
+        """Base URL for OWS and preview links; the REST url unless overridden."""
+        return (self.public_url or self.url).rstrip("/")
+
+    @property
+    def is_cluster(self) -> bool:
+        """Whether the connection is a cluster of nodes sharing one catalog."""
+        return bool(self.cluster_urls.strip())
+
+    @property
+    def node_urls(self) -> list[str]:
+        """URLs of the cluster's nodes, or just the url when it isn't clustered."""
+        urls = [u.strip().rstrip("/") for u in self.cluster_urls.split(",") if u.strip()]
+        return urls or [self.url.rstrip("/")]
+
+    @property
+    def primary_url(self) -> str:
+        """Where REST requests go: the cluster's primary node, or the url."""
+        if not self.is_cluster:
+            return self.url.rstrip("/")
+        return self.cluster_primary.strip().rstrip("/") or self.node_urls[0]
+
+    @property
+    def secondary_urls(self) -> list[str]:
+        """The cluster's other nodes, which only get cache truncations, reloads and resets."""
+        if not self.is_cluster:
+            return []
+        return [url for url in self.node_urls if url != self.primary_url]
 
 
 class SyncOptions(BaseModel):
@@ -45,6 +126,8 @@ class SyncOptions(BaseModel):
     layergroups: bool = True
     workspace_filter: list[str] = Field(default_factory=list)
     datastore_strategy: str = "skip"  # "skip", "same_connection", "geopackage_copy"
+    prune: bool = False  # Delete managed resources the source no longer has
+    adopt: bool = False  # Also update existing resources the client didn't create
 
 
 class SyncConfiguration(BaseModel):
@@ -59,6 +142,119 @@ class SyncConfiguration(BaseModel):
     last_synced_at: str | None = None
 
 
+class CacheSchedule(BaseModel):
+    """Scheduled GeoWebCache seed/truncate task for a layer."""
+
+    id: str = Field(default_factory=lambda: str(uuid.uuid4()))
+    name: str
+    connection_id: str
+    layer: str  # workspace:layer
+    cron: str  # minute hour day-of-month month day-of-week
+    operation: str = "reseed"  # "seed", "reseed", "truncate"
+    grid_set: str = "EPSG:900913"
+    format: str = "image/png"
+    zoom_start: int = 0
+    zoom_stop: int = 10
+    threads: int = 2
+    bounds: list[float] | None = None  # lon/lat [minx, miny, maxx, maxy] to seed only an area
+    enabled: bool = True
+    created_at: str = Field(default_factory=lambda: datetime.now().isoformat())
+    last_run_at: str | None = None
+    last_status: str | None = None  # "success", "failed"
+    last_error: str | None = None
+
+
+class SnapshotSchedule(BaseModel):
+    """Scheduled snapshot of connections' catalogs, kept under a retention policy."""
+
+    id: str = Field(default_factory=lambda: str(uuid.uuid4()))
+    name: str
+    connection_ids: list[str] = Field(default_factory=list)
+    cron: str = "0 2 * * *"  # minute hour day-of-month month day-of-week
+    destination: str = "local"  # "local" or "s3"
+    directory: str = ""  # Folder the snapshots are written to, for "local"
+    s3_connection_id: str = ""  # Bucket the snapshots are uploaded to, for "s3"
+    bucket: str = ""
+    prefix: str = ""
+    include_data: bool = False
+    keep_daily: int = 7  # Newest snapshot of each of the last N days
+    keep_weekly: int = 4  # Newest snapshot of each of the last M weeks
+    enabled: bool = True
+    created_at: str = Field(default_factory=lambda: datetime.now().isoformat())
+    last_run_at: str | None = None
+    last_status: str | None = None  # "success", "failed"
+    last_error: str | None = None
+
+
+class TemplateDataStore(BaseModel):
+    """A store a workspace template creates.
+
+    "{workspace}" in a connection parameter is replaced by the new
+    workspace's name, e.g. to give each project its own PostGIS schema.
+    """
+
+    name: str
+    description: str = ""
+    enabled: bool = False  # Skeletons stay disabled until their password is filled in
+    connection_parameters: dict[str, str] = Field(default_factory=dict)
+
+
+class WorkspaceTemplate(BaseModel):
+    """Stores, styles and settings a new workspace starts with."""
+
+    name: str
+    description: str = ""
+    isolated: bool = False
+    services: list[str] = Field(default_factory=lambda: ["wms", "wfs"])  # The rest are disabled
+    contact: dict[str, str] = Field(default_factory=dict)  # e.g. {"contactOrganization": ...}
+    datastores: list[TemplateDataStore] = Field(default_factory=list)
+    styles: list[str] = Field(default_factory=list)  # Global styles copied into the workspace
+
+
+class NamingConvention(BaseModel):
+    """Rules the names of one type of resource must follow.
+
+    The prefix may use "{workspace}", e.g. "{workspace}_" requires store
+    and layer names to start with their workspace's name.
+    """
+
+    resource_type: str  # workspace, datastore, coveragestore, layer, style, layergroup or "*"
+    pattern: str = ""  # Regular expression the whole name must match
+    forbidden_characters: str = ""
+    prefix: str = ""
+    description: str = ""  # Explains the pattern when a name doesn't match it
+
+
+class DefaultStyleRule(BaseModel):
+    """Default styles of the layers published in a workspace, by geometry type.
+
+    A layer published without a rule gets GeoServer's generic style for
+    its geometry. Styles of the workspace are named "workspace:style".
+    """
+
+    workspace: str = "*"  # Workspace name, or "*" for workspaces without a rule of their own
+    point: str = ""
+    line: str = ""
+    polygon: str = ""
+    raster: str = ""
+    connections: list[str] = Field(default_factory=list)  # Connection IDs; all when empty
+
+
+class EventHook(BaseModel):
+    """A command or webhook run when resources change; see apps.core.hooks."""
+
+    name: str
+    events: list[str] = Field(default_factory=lambda: ["*"])  # e.g. "layer.published", "style.*"
+    command: str = ""  # Run without a shell; ${...} placeholders are filled in per argument
+    url: str = ""  # Webhook called when there's no command
+    method: str = "POST"
+    headers: dict[str, str] = Field(default_factory=dict)
+    body: str = ""  # Webhook body template; the event as JSON when empty
+    connections: list[str] = Field(default_factory=list)  # Connection IDs; all when empty
+    enabled: bool = True
+    timeout: float = 10.0  # Seconds before the command or request is given up
+
+
 class PGServiceState(BaseModel):
     """PostgreSQL service state tracking."""
 
@@ -158,15 +354,64 @@ class SavedQuery(BaseModel):
     updated_at: str | None = None
 
 
+class ThemeConfig(BaseModel):
+    """User-defined TUI color theme; empty colors come from the default theme."""
+
+    name: str
+    primary: str = ""
+    secondary: str = ""
+    accent: str = ""
+    background: str = ""
+    surface: str = ""
+    success: str = ""
+    warning: str = ""
+    error: str = ""
+    dark: bool = True
+
+
+class TuiLayout(BaseModel):
+    """Panel layout of the TUI browser, restored between sessions."""
+
+    split_width: int = 0  # Tree panel width in cells; 0 = default
+    collapsed: str = ""  # "", "tree" or "details"
+    expanded_paths: dict[str, list[str]] = Field(default_factory=dict)  # Per connection ID
+
+
+class TimeoutSettings(BaseModel):
+    """Seconds a request may go without sending or receiving a byte, by kind.
+
+    Catalog requests use each connection's response timeout instead. 0
+    waits as long as it takes.
+    """
+
+    upload: float = 300.0  # Includes GeoServer ingesting the file before it answers
+    download: float = 120.0  # Data directory files, backups and WFS/WCS exports
+    ows: float = 60.0  # WMS, WFS, WCS and WMTS requests, and the OWS proxy
+
+
 class Config(BaseModel):
     """Main application configuration."""
 
+    schema_version: int = Field(default=CONFIG_SCHEMA_VERSION, alias="schemaVersion")
     connections: list[Connection] = Field(default_factory=list)
     active_connection: str = ""
     last_local_path: str = Field(default_factory=lambda: str(Path.home()))
     theme: str = "default"
+    custom_themes: list[ThemeConfig] = Field(default_factory=list)
+    accessible_mode: bool = False  # Text instead of icons and color-only state in the TUI
+    tui_layout: TuiLayout = Field(default_factory=TuiLayout)
     sync_configs: list[SyncConfiguration] = Field(default_factory=list)
+    cache_schedules: list[CacheSchedule] = Field(default_factory=list)
+    snapshot_schedules: list[SnapshotSchedule] = Field(default_factory=list)
+    workspace_templates: list[WorkspaceTemplate] = Field(default_factory=list)
+    naming_conventions: list[NamingConvention] = Field(default_factory=list)
+    default_styles: list[DefaultStyleRule] = Field(default_factory=list)
+    event_hooks: list[EventHook] = Field(default_factory=list)
+    # Name stores after their uploaded file's slug ("Roads 2024.zip" -> "roads_2024")
+    slugify_upload_names: bool = True
     ping_interval_secs: int = 60
+    cog_auto_convert_mb: int = 0  # Convert GeoTIFFs over this size to COG unasked; 0 = ask
+    timeouts: TimeoutSettings = Field(default_factory=TimeoutSettings)
     pg_services: list[PGServiceState] = Field(default_factory=list)
     saved_queries: list[SavedQuery] = Field(default_factory=list)
     s3_connections: list[S3Connection] = Field(default_factory=list)
@@ -181,6 +426,7 @@ class Config(BaseModel):
 
         # Allow extra fields for forward compatibility
         extra = "allow"
+        populate_by_name = True
 
 
 # Type aliases for cleaner imports in views
@@ -205,6 +451,9 @@ class ConfigManager:
                     cls._instance = super().__new__(cls)
                     cls._instance._config = None
                     cls._instance._initialized = False
+                    cls._instance._profile = None
+                    cls._instance._config_file = None
+                    cls._instance._flag_connection = None
         return cls._instance
 
     @property
@@ -226,27 +475,94 @@ class ConfigManager:
         with self._lock:
             if self._config is None:
                 return
+            self._write(self._config)
 
-            path = self._config_path()
-            os.makedirs(os.path.dirname(path), exist_ok=True)
+    def _write(self, config: Config) -> None:
+        """Write a configuration to disk atomically."""
+        path = self._config_path()
+        os.makedirs(os.path.dirname(path), exist_ok=True)
 
-            # Atomic write using temp file
-            tmp_path = path + ".tmp"
-            with open(tmp_path, "w") as f:
-                # Use model_dump for Pydantic v2
-                json.dump(self._config.model_dump(by_alias=True), f, indent=2)
+        # Atomic write using temp file
+        tmp_path = path + ".tmp"
+        with open(tmp_path, "w") as f:
+            # Use model_dump for Pydantic v2
+            json.dump(config.model_dump(by_alias=True), f, indent=2)
 
-            os.replace(tmp_path, path)
+        os.replace(tmp_path, path)
 
-    def _config_path(self) -> str:
-        """Get the path to the config file."""
+    # Profile management
+    @property
+    def profile(self) -> str:
+        """Name of the profile in use; empty when a config file is given directly."""
+        if self._config_file or (not self._profile and os.environ.get(CONFIG_ENV)):
+            return ""
+        return self._profile or os.environ.get(PROFILE_ENV) or DEFAULT_PROFILE
+
+    @property
+    def plain_mode(self) -> bool:
+        """Whether plain output for screen readers was asked for with --plain.
+
+        Unlike accessible_mode in the config it lasts for this run only.
+        """
+        return os.environ.get(PLAIN_ENV, "").lower() not in ("", "0", "false", "no")
+
+    def use_profile(self, name: str) -> Config:
+        """Switch to a named profile, creating it on first save.
+
+        Raises:
+            ValueError: If the name is not a valid profile name
+        """
+        if not _PROFILE_NAME_RE.match(name):
+            raise ValueError(f"Invalid profile name: {name}")
+        with self._lock:
+            self._profile = name
+            self._config_file = None
+            return self.reload()
+
+    def use_config_file(self, path: str) -> Config:
+        """Switch to an explicit config file, as given with --config."""
+        with self._lock:
+            self._config_file = os.path.abspath(os.path.expanduser(path))
+            self._profile = None
+            return self.reload()
+
+    def list_profiles(self) -> list[str]:
+        """List the default profile and every named profile on disk."""
+        profiles_dir = os.path.join(self._config_dir(), PROFILES_DIR)
+        names = set()
+        if os.path.isdir(profiles_dir):
+            names = {
+                name[: -len(".json")]
+                for name in os.listdir(profiles_dir)
+                if name.endswith(".json")
+            }
+        names.discard(DEFAULT_PROFILE)
+        return [DEFAULT_PROFILE, *sorted(names)]
+
+    def _config_dir(self) -> str:
+        """Get the config directory."""
         config_home = os.environ.get("XDG_CONFIG_HOME")
         if not config_home:
             config_home = os.path.join(str(Path.home()), ".config")
-        return os.path.join(config_home, CONFIG_DIR, CONFIG_FILE)
+        return os.path.join(config_home, CONFIG_DIR)
+
+    def _config_path(self) -> str:
+        """Get the path to the config file of the current profile."""
+        if self._config_file:
+            return self._config_file
+        if not self._profile and os.environ.get(CONFIG_ENV):
+            return os.path.abspath(os.path.expanduser(os.environ[CONFIG_ENV]))
+
+        profile = self.profile
+        if profile == DEFAULT_PROFILE:
+            return os.path.join(self._config_dir(), CONFIG_FILE)
+        return os.path.join(self._config_dir(), PROFILES_DIR, f"{profile}.json")
 
     def _migrate_old_config(self) -> None:
         """Migrate config from old kartoza-geoserver-client directory."""
+        if self.profile != DEFAULT_PROFILE:
+            return
+
         home = str(Path.home())
         old_path = os.path.join(home, ".config", OLD_CONFIG_DIR, CONFIG_FILE)
         new_path = self._config_path()
@@ -269,18 +585,50 @@ class ConfigManager:
         try:
             with open(path) as f:
                 data = json.load(f)
-            return Config.model_validate(data)
         except (json.JSONDecodeError, ValueError):
             # Corrupted config, return default
             return Config()
 
+        version = config_version(data)
+        if version < CONFIG_SCHEMA_VERSION:
+            backup_path = backup_config(path, version)
+            logger.info("Backed up config to %s before migrating", backup_path)
+            data = migrate_config(data)
+
+        try:
+            config = Config.model_validate(data)
+        except ValueError:
+            # Keep the unreadable file around, the next save replaces it
+            backup_config(path, version)
+            return Config()
+
+        if version < CONFIG_SCHEMA_VERSION:
+            self._write(config)
+        return config
+
     # Connection management methods
+    def set_flag_connection(self, url: str, username: str = "", password: str = "") -> None:
+        """Define a connection from command line flags; it is never saved."""
+        from .connection_providers import FlagConnectionProvider
+
+        self._flag_connection = FlagConnectionProvider(url, username, password)
+
+    def connection_chain(self) -> "ConnectionChain":
+        """Get the providers connections are resolved from, in priority order."""
+        from .connection_providers import (
+            ConfigConnectionProvider,
+            ConnectionChain,
+            EnvConnectionProvider,
+        )
+
+        providers = [EnvConnectionProvider(), ConfigConnectionProvider(self)]
+        if self._flag_connection is not None:
+            providers.insert(0, self._flag_connection)
+        return ConnectionChain(providers)
+
     def get_connection(self, conn_id: str) -> Connection | None:
-        """Get a connection by ID."""
-        for conn in self.config.connections:
-            if conn.id == conn_id:
-                return conn
-        return None
+        """Get a connection by ID from flags, environment or the config file."""
+        return self.connection_chain().get(conn_id)
 
     def get_active_connection(self) -> Connection | None:
         """Get the currently active connection."""
@@ -317,8 +665,8 @@ class ConfigManager:
             self.save()
 
     def list_connections(self) -> list[Connection]:
-        """List all connections."""
-        return list(self.config.connections)
+        """List all connections, including ones from flags and environment."""
+        return self.connection_chain().list()
 
     # S3 connection management
     def list_s3_connections(self) -> list[S3Connection]:
@@ -398,6 +746,86 @@ class ConfigManager:
             self.config.sync_configs = [c for c in self.config.sync_configs if c.id != config_id]
             self.save()
 
+    # Cache schedule management
+    def list_cache_schedules(self) -> list[CacheSchedule]:
+        """List all cache schedules."""
+        return self.config.cache_schedules
+
+    def get_cache_schedule(self, schedule_id: str) -> CacheSchedule | None:
+        """Get a cache schedule by ID."""
+        for schedule in self.config.cache_schedules:
+            if schedule.id == schedule_id:
+                return schedule
+        return None
+
+    def add_cache_schedule(self, schedule: CacheSchedule) -> None:
+        """Add a new cache schedule."""
+        with self._lock:
+            self.config.cache_schedules.append(schedule)
+            self.save()
+
+    def update_cache_schedule(self, schedule: CacheSchedule) -> bool:
+        """Update an existing cache schedule."""
+        with self._lock:
+            for i, existing in enumerate(self.config.cache_schedules):
+                if existing.id == schedule.id:
+                    self.config.cache_schedules[i] = schedule
+                    self.save()
+                    return True
+            return False
+
+    def remove_cache_schedule(self, schedule_id: str) -> bool:
+        """Remove a cache schedule by ID. Returns True if found."""
+        with self._lock:
+            original_len = len(self.config.cache_schedules)
+            self.config.cache_schedules = [
+                s for s in self.config.cache_schedules if s.id != schedule_id
+            ]
+            if len(self.config.cache_schedules) < original_len:
+                self.save()
+                return True
+            return False
+
+    # Snapshot schedule management
+    def list_snapshot_schedules(self) -> list[SnapshotSchedule]:
+        """List all snapshot schedules."""
+        return self.config.snapshot_schedules
+
+    def get_snapshot_schedule(self, schedule_id: str) -> SnapshotSchedule | None:
+        """Get a snapshot schedule by ID."""
+        for schedule in self.config.snapshot_schedules:
+            if schedule.id == schedule_id:
+                return schedule
+        return None
+
+    def add_snapshot_schedule(self, schedule: SnapshotSchedule) -> None:
+        """Add a new snapshot schedule."""
+        with self._lock:
+            self.config.snapshot_schedules.append(schedule)
+            self.save()
+
+    def update_snapshot_schedule(self, schedule: SnapshotSchedule) -> bool:
+        """Update an existing snapshot schedule."""
+        with self._lock:
+            for i, existing in enumerate(self.config.snapshot_schedules):
+                if existing.id == schedule.id:
+                    self.config.snapshot_schedules[i] = schedule
+                    self.save()
+                    return True
+            return False
+
+    def remove_snapshot_schedule(self, schedule_id: str) -> bool:
+        """Remove a snapshot schedule by ID. Returns True if found."""
+        with self._lock:
+            original_len = len(self.config.snapshot_schedules)
+            self.config.snapshot_schedules = [
+                s for s in self.config.snapshot_schedules if s.id != schedule_id
+            ]
+            if len(self.config.snapshot_schedules) < original_len:
+                self.save()
+                return True
+            return False
+
     # PostgreSQL service state management
     def get_pg_service_state(self, name: str) -> PGServiceState | None:
         """Get PostgreSQL service state by name."""
@@ -625,6 +1053,52 @@ def get_config_data() -> Config:
     return config_manager.config
 
 
+def apply_config_args(argv: list[str]) -> list[str]:
+    """Take config and connection options out of a command line and apply them.
+
+    --profile and --config pick the config file; the choice is also exported
+    to the environment so that child processes, such as the development
+    server's autoreloader, use the same config. --gs-url, --gs-user and
+    --gs-password define a connection that is never saved. --plain turns
+    colors off for screen readers, in this process and its children.
+
+    Args:
+        argv: Command line arguments
+
+    Returns:
+        The arguments without the config options
+    """
+    options = ("--profile", "--config", "--gs-url", "--gs-user", "--gs-password")
+    values: dict[str, str] = {}
+    remaining = []
+    args = iter(argv)
+    for arg in args:
+        if arg == "--plain":
+            os.environ[PLAIN_ENV] = "1"
+            continue
+        option, _, value = arg.partition("=")
+        if option not in options:
+            remaining.append(arg)
+            continue
+        values[option] = value or next(args, "")
+
+    if "--profile" in values:
+        config_manager.use_profile(values["--profile"])
+        os.environ.pop(CONFIG_ENV, None)
+        os.environ[PROFILE_ENV] = values["--profile"]
+    if "--config" in values:
+        config_manager.use_config_file(values["--config"])
+        os.environ[CONFIG_ENV] = os.path.abspath(os.path.expanduser(values["--config"]))
+    if values.get("--gs-url"):
+        config_manager.set_flag_connection(
+            values["--gs-url"], values.get("--gs-user", ""), values.get("--gs-password", "")
+        )
+    if config_manager.plain_mode:
+        # Django's styles otherwise wrap messages in color codes
+        os.environ["DJANGO_COLORS"] = "nocolor"
+    return remaining
+
+
 def get_qgis_projects_dir() -> Path:
     """Get the directory for storing uploaded QGIS projects.
 
@@ -639,6 +1113,20 @@ def get_qgis_projects_dir() -> Path:
     return projects_dir
 
 
+def get_state_dir() -> Path:
+    """Get the directory for state that must outlive the cache.
+
+    Uses XDG_STATE_HOME/kartoza-cloudbench/
+    """
+    state_home = os.environ.get("XDG_STATE_HOME")
+    if not state_home:
+        state_home = os.path.join(str(Path.home()), ".local", "state")
+
+    state_dir = Path(state_home) / CONFIG_DIR
+    state_dir.mkdir(parents=True, exist_ok=True)
+    return state_dir
+
+
 def get_cache_dir() -> Path:
     """Get the cache directory for temporary files.
 
@@ -0,0 +1,103 @@
+"""Schema migrations for the config file.
+
+The config file carries a schemaVersion. Files written before versioning
+have none and count as version 0. On load, each migration upgrades the raw
+JSON one version at a time until it reaches CONFIG_SCHEMA_VERSION. The
+manager backs up the original file before writing the migrated one, so an
+upgrade never loses connections.
+
+To change the file layout, bump CONFIG_SCHEMA_VERSION and add a function
+to MIGRATIONS keyed by the version it upgrades from.
+"""
+
+import logging
+import os
+import shutil
+from collections.abc import Callable
+from typing import Any
+
+logger = logging.getLogger(__name__)
+
+CONFIG_SCHEMA_VERSION = 1
+SCHEMA_VERSION_KEY = "schemaVersion"
+
+
+_V1_LIST_FIELDS = (
+    "connections",
+    "sync_configs",
+    "cache_schedules",
+    "pg_services",
+    "saved_queries",
+    "s3_connections",
+    "qgis_projects",
+    "geonode_connections",
+    "qfieldcloud_connections",
+    "iceberg_connections",
+    "merginmaps_connections",
+)
+
+
+def _v0_to_v1(data: dict[str, Any]) -> dict[str, Any]:
+    # The Go backend writes empty slices as null, which fails validation and
+    # used to reset the whole config to defaults.
+    for key in _V1_LIST_FIELDS:
+        if key in data and data[key] is None:
+            data[key] = []
+    return data
+
+
+# Version migrated from -> migration
+MIGRATIONS: dict[int, Callable[[dict[str, Any]], dict[str, Any]]] = {
+    0: _v0_to_v1,
+}
+
+
+def config_version(data: dict[str, Any]) -> int:
+    """Get the schema version of raw config data."""
+    try:
+        return int(data.get(SCHEMA_VERSION_KEY) or 0)
+    except (TypeError, ValueError):
+        return 0
+
+
+def migrate_config(data: dict[str, Any]) -> dict[str, Any]:
+    """Upgrade raw config data to the current schema version.
+
+    Args:
+        data: Config file contents
+
+    Returns:
+        The migrated data. Data from a newer version is returned unchanged.
+    """
+    version = config_version(data)
+    if version > CONFIG_SCHEMA_VERSION:
+        logger.warning(
+            "Config schema version %d is newer than supported version %d",
+            version,
+            CONFIG_SCHEMA_VERSION,
+        )
+        return data
+
+    while version < CONFIG_SCHEMA_VERSION:
+        data = MIGRATIONS[version](data)
+        version += 1
+        data[SCHEMA_VERSION_KEY] = version
+        logger.info("Migrated config to schema version %d", version)
+    return data
+
+
+def backup_config(path: str, version: int) -> str:
+    """Copy a config file aside before it is migrated.
+
+    Args:
+        path: Config file path
+        version: Schema version of the file
+
+    Returns:
+        Path of the backup
+    """
+    backup_path = f"{path}.v{version}.bak"
+    # Keep the first backup; a later failed run must not overwrite it
+    if not os.path.exists(backup_path):
+        shutil.copy2(path, backup_path)
+    return backup_path
@@ -0,0 +1,42 @@
+"""Confirmation policy for destructive operations.
+
+Deleting from a production server deserves more than a click, so, like
+GitHub's repository deletion, the user can be asked to type the name of
+what is about to go. Each connection picks its policy; by default typing
+is only required on connections tagged as production. The web UI and the
+TUI both decide with these helpers.
+"""
+
+from typing import TYPE_CHECKING
+
+from .connection_groups import normalize_environment
+
+if TYPE_CHECKING:
+    from .config import Connection
+
+CONFIRM_AUTO = "auto"  # Typed on production connections, a plain confirmation elsewhere
+CONFIRM_SIMPLE = "simple"
+CONFIRM_TYPED = "typed"
+CONFIRM_POLICIES = (CONFIRM_AUTO, CONFIRM_SIMPLE, CONFIRM_TYPED)
+
+# Environment that gets typed confirmation under the auto policy
+PRODUCTION = "production"
+
+
+def requires_typed_confirmation(connection: "Connection") -> bool:
+    """Whether destructive operations on a connection need the name typed."""
+    if connection.confirm_policy == CONFIRM_TYPED:
+        return True
+    if connection.confirm_policy == CONFIRM_SIMPLE:
+        return False
+    return normalize_environment(connection.environment) == PRODUCTION
+
+
+def batch_confirmation_text(action: str, count: int) -> str:
+    """Text to type before a batch action, as there is no single name to type."""
+    return f"{action} {count} layer{'' if count == 1 else 's'}"
+
+
+def confirmation_matches(expected: str, typed: str) -> bool:
+    """Whether typed text confirms an operation; only surrounding spaces are forgiven."""
+    return bool(expected) and typed.strip() == expected
@@ -0,0 +1,79 @@
+"""Organizing connections into groups with badges.
+
+Users with many connections, say dev, staging and production servers for
+several clients, group them into folders and tag each with the
+environment it belongs to. An environment brings a default badge color,
+so a production server stands out without further setup; a connection's
+own color wins over it. The web UI and the TUI both group and color
+connections with these helpers.
+"""
+
+import re
+from typing import TYPE_CHECKING
+
+if TYPE_CHECKING:
+    from .config import Connection
+
+# Suggested environments, in the order they're offered
+ENVIRONMENTS = ("development", "staging", "production")
+
+# Environment -> default badge color
+ENVIRONMENT_COLORS = {
+    "development": "#38a169",
+    "staging": "#d69e2e",
+    "production": "#e53e3e",
+}
+
+# Badges are shown inline in narrow trees, so keep them to an emoji or a few letters
+MAX_BADGE_LENGTH = 4
+
+COLOR_RE = re.compile(r"^#[0-9a-fA-F]{6}$")
+
+
+def normalize_environment(environment: str) -> str:
+    """Normalize an environment tag, e.g. " Production " -> "production"."""
+    return environment.strip().lower()
+
+
+def validate_organization(color: str, badge: str) -> list[str]:
+    """Check a connection's color and badge.
+
+    Returns:
+        Problems found; empty when both are valid
+    """
+    errors = []
+    if color and not COLOR_RE.match(color):
+        errors.append(f"Color must look like #1a2b3c, not '{color}'")
+    if len(badge) > MAX_BADGE_LENGTH:
+        errors.append(f"Badge must be at most {MAX_BADGE_LENGTH} characters")
+    return errors
+
+
+def badge_color(connection: "Connection") -> str:
+    """Color of a connection's badge: its own, else its environment's, else none."""
+    return connection.color or ENVIRONMENT_COLORS.get(
+        normalize_environment(connection.environment), ""
+    )
+
+
+def group_connections(
+    connections: list["Connection"],
+) -> list[tuple[str, list["Connection"]]]:
+    """Group connections by folder.
+
+    Groups are sorted by name, case-insensitively, and keep the order of
+    their connections. Ungrouped connections come last under "".
+    """
+    groups: dict[str, list[Connection]] = {}
+    for conn in connections:
+        groups.setdefault(conn.group.strip(), []).append(conn)
+    named = sorted((name for name in groups if name), key=str.lower)
+    result = [(name, groups[name]) for name in named]
+    if "" in groups:
+        result.append(("", groups[""]))
+    return result
+
+
+def list_groups(connections: list["Connection"]) -> list[str]:
+    """Names of the groups in use, for suggesting existing folders."""
+    return [name for name, _ in group_connections(connections) if name]
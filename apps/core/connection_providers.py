@@ -0,0 +1,142 @@
+"""Connection provider chain.
+
+GeoServer connections are resolved from several sources, in order:
+
+1. CLI flags (--gs-url, --gs-user, --gs-password)
+2. Environment variables (GSCLIENT_URL, GSCLIENT_USER, GSCLIENT_PASSWORD,
+   optionally GSCLIENT_NAME and GSCLIENT_ENVIRONMENT)
+3. The config file
+
+Flag and environment connections exist only for the running process and
+are never written to the config file, which makes them suitable for
+one-off scripted use. The first provider that knows an ID wins.
+"""
+
+import os
+from collections.abc import Mapping
+
+from .config import ConfigManager, Connection
+from .connection_groups import normalize_environment
+
+ENV_URL = "GSCLIENT_URL"
+ENV_USER = "GSCLIENT_USER"
+ENV_PASSWORD = "GSCLIENT_PASSWORD"
+ENV_NAME = "GSCLIENT_NAME"
+ENV_ENVIRONMENT = "GSCLIENT_ENVIRONMENT"
+
+FLAG_CONNECTION_ID = "cli"
+ENV_CONNECTION_ID = "env"
+
+
+class ConnectionProvider:
+    """A source of GeoServer connections."""
+
+    name = ""
+
+    def connections(self) -> list[Connection]:
+        """List the connections this provider defines."""
+        raise NotImplementedError
+
+    def get(self, conn_id: str) -> Connection | None:
+        """Get a connection by ID."""
+        return next((c for c in self.connections() if c.id == conn_id), None)
+
+
+class FlagConnectionProvider(ConnectionProvider):
+    """A connection given on the command line."""
+
+    name = "flag"
+
+    def __init__(self, url: str, username: str = "", password: str = "", conn_name: str = ""):
+        """Initialize provider.
+
+        Args:
+            url: GeoServer URL
+            username: Username
+            password: Password
+            conn_name: Display name; derived from the URL when empty
+        """
+        self.connection = Connection(
+            id=FLAG_CONNECTION_ID,
+            name=conn_name or f"{url} (command line)",
+            url=url,
+            username=username,
+            password=password,
+            source=self.name,
+        )
+
+    def connections(self) -> list[Connection]:
+        """List the command line connection."""
+        return [self.connection]
+
+
+class EnvConnectionProvider(ConnectionProvider):
+    """A connection defined by GSCLIENT_* environment variables."""
+
+    name = "env"
+
+    def __init__(self, environ: Mapping[str, str] | None = None):
+        """Initialize provider.
+
+        Args:
+            environ: Environment to read; os.environ when omitted
+        """
+        self.environ = os.environ if environ is None else environ
+
+    def connections(self) -> list[Connection]:
+        """List the environment connection, if GSCLIENT_URL is set."""
+        url = self.environ.get(ENV_URL, "")
+        if not url:
+            return []
+        return [
+            Connection(
+                id=ENV_CONNECTION_ID,
+                name=self.environ.get(ENV_NAME) or f"{url} (environment)",
+                url=url,
+                username=self.environ.get(ENV_USER, ""),
+                password=self.environ.get(ENV_PASSWORD, ""),
+                environment=normalize_environment(self.environ.get(ENV_ENVIRONMENT, "")),
+                source=self.name,
+            )
+        ]
+
+
+class ConfigConnectionProvider(ConnectionProvider):
+    """Connections saved in the config file."""
+
+    name = "config"
+
+    def __init__(self, manager: ConfigManager):
+        """Initialize provider."""
+        self.manager = manager
+
+    def connections(self) -> list[Connection]:
+        """List the saved connections."""
+        return list(self.manager.config.connections)
+
+
+class ConnectionChain:
+    """Resolves connections from providers in priority order."""
+
+    def __init__(self, providers: list[ConnectionProvider]):
+        """Initialize chain with providers, highest priority first."""
+        self.providers = providers
+
+    def get(self, conn_id: str) -> Connection | None:
+        """Get a connection from the first provider that has it."""
+        for provider in self.providers:
+            conn = provider.get(conn_id)
+            if conn is not None:
+                return conn
+        return None
+
+    def list(self) -> list[Connection]:
+        """List connections of all providers; earlier providers shadow later ones."""
+        seen: set[str] = set()
+        result = []
+        for provider in self.providers:
+            for conn in provider.connections():
+                if conn.id not in seen:
+                    seen.add(conn.id)
+                    result.append(conn)
+        return result
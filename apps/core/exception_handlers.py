@@ -0,0 +1,53 @@
+"""REST framework exception handlers for Kartoza CloudBench."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import exception_handler
+
+from .exceptions import GeoServerError, S3Error
+
+
+def custom_exception_handler(exc, context):
+    """Custom exception handler for consistent error responses.
+
+    Returns errors in the format:
+    {
+        "error": "Error message",
+        "detail": "Optional detailed message"
+    }
+    """
+    # Call REST framework's default exception handler first
+    response = exception_handler(exc, context)
+
+    if response is not None:
+        # Standardize error format
+        error_data = {"error": str(exc)}
+
+        if hasattr(exc, "detail"):
+            if isinstance(exc.detail, dict):
+                error_data["detail"] = exc.detail
+            elif isinstance(exc.detail, list):
+                error_data["detail"] = exc.detail
+            else:
+                error_data["error"] = str(exc.detail)
+
+        response.data = error_data
+
+    return response
+
+
+def handle_geoserver_error(exc: GeoServerError) -> Response:
+    """Handle GeoServer errors and return appropriate response."""
+    status_code = exc.status_code or status.HTTP_502_BAD_GATEWAY
+    return Response(
+        {"error": exc.message, "type": "geoserver_error"},
+        status=status_code,
+    )
+
+
+def handle_s3_error(exc: S3Error) -> Response:
+    """Handle S3 errors and return appropriate response."""
+    return Response(
+        {"error": exc.message, "operation": exc.operation, "type": "s3_error"},
+        status=status.HTTP_502_BAD_GATEWAY,
+    )
@@ -1,37 +1,8 @@
-"""Custom exception handlers for Kartoza CloudBench."""
+"""Exceptions for Kartoza CloudBench.
 
-from rest_framework import status
-from rest_framework.response import Response
-from rest_framework.views import exception_handler
-
-
-def custom_exception_handler(exc, context):
-    """Custom exception handler for consistent error responses.
-
-    Returns errors in the format:
-    {
-        "error": "Error message",
-        "detail": "Optional detailed message"
-    }
-    """
-    # Call REST framework's default exception handler first
-    response = exception_handler(exc, context)
-
-    if response is not None:
-        # Standardize error format
-        error_data = {"error": str(exc)}
-
-        if hasattr(exc, "detail"):
-            if isinstance(exc.detail, dict):
-                error_data["detail"] = exc.detail
-            elif isinstance(exc.detail, list):
-                error_data["detail"] = exc.detail
-            else:
-                error_data["error"] = str(exc.detail)
-
-        response.data = error_data
-
-    return response
+Plain Python, so the GeoServer and GWC clients raising them can be used
+without Django; the REST framework handlers are in exception_handlers.
+"""
 
 
 class GeoServerError(Exception):
@@ -44,6 +15,47 @@ class GeoServerError(Exception):
         super().__init__(message)
 
 
+class ReadOnlyConnectionError(GeoServerError):
+    """Exception raised when a change is attempted on a read-only connection."""
+
+    def __init__(self, connection_name: str, method: str, path: str):
+        """Initialize read-only connection error."""
+        super().__init__(
+            f"Connection '{connection_name}' is read-only: {method} {path} was not sent",
+            status_code=403,
+        )
+
+
+class TokenError(GeoServerError):
+    """Exception raised when no bearer token can be obtained for a connection."""
+
+    def __init__(self, message: str):
+        """Initialize token error."""
+        super().__init__(message, status_code=401)
+
+
+class ResponseTooLargeError(GeoServerError):
+    """Exception raised when a response is bigger than the connection allows."""
+
+    def __init__(self, url: str, limit_mb: float, size: int | None = None):
+        """Initialize response too large error."""
+        received = f"{size / 1024 / 1024:.1f} MB, " if size else ""
+        super().__init__(
+            f"The response from {url} was {received}more than the {limit_mb:g} MB "
+            "the connection allows; raise its response size limit to read it",
+            status_code=502,
+        )
+
+
+class NamingError(GeoServerError):
+    """Exception raised when a name breaks the configured naming conventions."""
+
+    def __init__(self, label: str, name: str, problems: list[str]):
+        """Initialize naming error."""
+        self.problems = problems
+        super().__init__(f"Invalid {label} name '{name}': {'; '.join(problems)}", status_code=400)
+
+
 class S3Error(Exception):
     """Exception raised when S3 operations fail."""
 
@@ -68,20 +80,3 @@ class UploadError(Exception):
         self.message = message
         self.session_id = session_id
         super().__init__(message)
-
-
-def handle_geoserver_error(exc: GeoServerError) -> Response:
-    """Handle GeoServer errors and return appropriate response."""
-    status_code = exc.status_code or status.HTTP_502_BAD_GATEWAY
-    return Response(
-        {"error": exc.message, "type": "geoserver_error"},
-        status=status_code,
-    )
-
-
-def handle_s3_error(exc: S3Error) -> Response:
-    """Handle S3 errors and return appropriate response."""
-    return Response(
-        {"error": exc.message, "operation": exc.operation, "type": "s3_error"},
-        status=status.HTTP_502_BAD_GATEWAY,
-    )
@@ -0,0 +1,129 @@
+"""Headless daemon mode: the REST API without the web UI, for other tools.
+
+`cloudbench serve --headless` runs the API on a loopback address so QGIS
+plugins and scripts can drive CloudBench while it keeps the connections
+and their credentials. Every request must carry the daemon's bearer token,
+and everything outside /api/ (the web UI, admin and viewer) is hidden.
+
+The running daemon's URL and token are written to daemon.json in the state
+directory, readable only by the user, for clients to find it; the file is
+removed when the daemon stops.
+"""
+
+import hmac
+import ipaddress
+import json
+import logging
+import os
+import secrets
+from pathlib import Path
+from typing import Any
+
+from django.conf import settings
+from django.http import JsonResponse
+
+from .config import get_state_dir
+
+logger = logging.getLogger(__name__)
+
+# Bumped when an endpoint changes incompatibly, so clients can refuse a daemon they don't know
+API_VERSION = 1
+
+# Discovery file in the state directory
+DAEMON_FILE = "daemon.json"
+
+# Paths answered without a token, for process supervisors
+OPEN_PATHS = ("/health/",)
+
+
+def generate_token() -> str:
+    """A random bearer token."""
+    return secrets.token_urlsafe(32)
+
+
+def is_loopback(host: str) -> bool:
+    """Whether a host to listen on is only reachable from this machine."""
+    if host == "localhost":
+        return True
+    try:
+        return ipaddress.ip_address(host.strip("[]")).is_loopback
+    except ValueError:
+        return False
+
+
+def daemon_file_path() -> Path:
+    """Where the running daemon is advertised."""
+    return get_state_dir() / DAEMON_FILE
+
+
+def write_daemon_file(url: str, token: str, pid: int | None = None) -> Path:
+    """Advertise a running daemon, readable only by the current user."""
+    path = daemon_file_path()
+    info = {
+        "url": url,
+        "token": token,
+        "pid": pid if pid is not None else os.getpid(),
+        "apiVersion": API_VERSION,
+    }
+    fd = os.open(path, os.O_WRONLY | os.O_CREAT | os.O_TRUNC, 0o600)
+    with os.fdopen(fd, "w") as f:
+        json.dump(info, f, indent=2)
+    return path
+
+
+def read_daemon_file() -> dict[str, Any] | None:
+    """The advertised daemon, or None if none is running."""
+    try:
+        with open(daemon_file_path()) as f:
+            return json.load(f)
+    except (OSError, json.JSONDecodeError):
+        return None
+
+
+def remove_daemon_file(pid: int | None = None) -> None:
+    """Stop advertising the daemon, unless another process has replaced it."""
+    info = read_daemon_file()
+    if info is None or info.get("pid") != (pid if pid is not None else os.getpid()):
+        return
+    try:
+        daemon_file_path().unlink()
+    except OSError as e:
+        logger.warning("Could not remove %s: %s", DAEMON_FILE, e)
+
+
+def bearer_token(request) -> str:
+    """The token of an Authorization: Bearer header, or empty."""
+    scheme, _, token = request.headers.get("Authorization", "").partition(" ")
+    return token.strip() if scheme.lower() == "bearer" else ""
+
+
+def has_daemon_token(request) -> bool:
+    """Whether a request carries the bearer token of the running headless daemon."""
+    expected = getattr(settings, "CLOUDBENCH_API_TOKEN", "")
+    return bool(expected) and hmac.compare_digest(bearer_token(request), expected)
+
+
+class HeadlessMiddleware:
+    """Guard the API with the daemon token, and hide the rest, in headless mode.
+
+    Does nothing unless settings.CLOUDBENCH_HEADLESS is set.
+    """
+
+    def __init__(self, get_response):
+        """Initialize middleware."""
+        self.get_response = get_response
+
+    def __call__(self, request):
+        """Refuse requests without the token and paths outside the API."""
+        if not getattr(settings, "CLOUDBENCH_HEADLESS", False) or request.path in OPEN_PATHS:
+            return self.get_response(request)
+
+        if not has_daemon_token(request):
+            return JsonResponse(
+                {"error": "A valid bearer token is required"},
+                status=401,
+                headers={"WWW-Authenticate": "Bearer"},
+            )
+        if not request.path.startswith("/api/"):
+            return JsonResponse({"error": "Not found"}, status=404)
+        return self.get_response(request)
@@ -0,0 +1,327 @@
+"""Hooks run when resources change.
+
+A hook runs an external command or calls a webhook when one of the
+events below happens through this client, so notifications, CI jobs or
+cache purges can follow changes without patching the client. Hooks are
+kept in the config file, shared by the web UI, the TUI and the CLI.
+
+Commands and webhook bodies are templates: ${event}, ${connection},
+${connection_id}, ${workspace}, ${name}, ${time} and ${json} (the whole
+event as JSON) are replaced, as are the event's other details by name.
+Commands are split into arguments before the replacement and run
+without a shell, so values can't inject further commands. A webhook
+without a body template is sent the event as JSON.
+
+Commands can only be set in the config file. The API lists webhook
+headers masked, as they often hold credentials, and may switch or edit
+hooks but not give one a command it didn't have.
+
+Hooks run on a background pool; they never hold up or fail the change
+that fired them, their failures are only logged and listed among the
+recent deliveries.
+"""
+
+import json
+import logging
+import shlex
+import subprocess
+import threading
+import time
+from collections import deque
+from concurrent.futures import Future, ThreadPoolExecutor
+from dataclasses import dataclass, field
+from datetime import datetime, timezone
+from string import Template
+from typing import Any
+
+import httpx
+
+from .config import EventHook, config_manager
+
+logger = logging.getLogger(__name__)
+
+EVENTS = {
+    "layer.published": "A layer was published",
+    "layer.updated": "A layer or its resource was changed",
+    "layer.deleted": "A layer was deleted",
+    "style.created": "A style was created",
+    "style.updated": "A style's content was changed",
+    "style.deleted": "A style was deleted",
+    "workspace.created": "A workspace was created",
+    "workspace.deleted": "A workspace was deleted",
+    "upload.completed": "An upload was stored and published",
+}
+
+# Hooks run at once; further events wait for a free worker
+MAX_WORKERS = 4
+
+# Deliveries kept for the hooks list
+MAX_DELIVERIES = 50
+
+# Command output kept in a delivery
+MAX_OUTPUT = 2000
+
+# Shown instead of webhook header values; sent back, it keeps the current value
+HEADER_MASK = "********"
+
+
+@dataclass
+class HookEvent:
+    """Something that happened to a resource."""
+
+    name: str  # One of EVENTS
+    connection: str = ""
+    connection_id: str = ""
+    workspace: str | None = None
+    resource: str | None = None
+    details: dict[str, Any] = field(default_factory=dict)
+    time: datetime = field(default_factory=lambda: datetime.now(timezone.utc))
+
+    def to_dict(self) -> dict[str, Any]:
+        return {
+            "event": self.name,
+            "connection": self.connection,
+            "connectionId": self.connection_id,
+            "workspace": self.workspace,
+            "name": self.resource,
+            "time": self.time.isoformat(),
+            **self.details,
+        }
+
+    def variables(self) -> dict[str, str]:
+        """Values for the ${...} placeholders of commands and bodies."""
+        values = {key: "" if value is None else str(value) for key, value in self.details.items()}
+        values.update(
+            event=self.name,
+            connection=self.connection,
+            connection_id=self.connection_id,
+            workspace=self.workspace or "",
+            name=self.resource or "",
+            time=self.time.isoformat(),
+            json=json.dumps(self.to_dict()),
+        )
+        return values
+
+
+@dataclass
+class HookResult:
+    """The outcome of running one hook for one event."""
+
+    hook: str
+    event: str
+    success: bool
+    message: str = ""
+    duration_ms: float = 0.0
+    time: datetime = field(default_factory=lambda: datetime.now(timezone.utc))
+
+    def to_dict(self) -> dict[str, Any]:
+        return {
+            "hook": self.hook,
+            "event": self.event,
+            "success": self.success,
+            "message": self.message,
+            "durationMs": round(self.duration_ms, 1),
+            "time": self.time.isoformat(),
+        }
+
+
+def masked_hook(hook: EventHook) -> dict[str, Any]:
+    """A hook's settings with its webhook header values masked."""
+    data = hook.model_dump()
+    data["headers"] = {name: HEADER_MASK for name in hook.headers}
+    return data
+
+
+def merge_hook_update(updated: list[EventHook], current: list[EventHook]) -> list[EventHook]:
+    """Check hooks sent to the API, and restore the header values it masked.
+
+    Args:
+        updated: Hooks replacing the configured ones
+        current: Hooks currently configured
+
+    Returns:
+        The hooks to configure
+
+    Raises:
+        ValueError: If a hook gets a command it doesn't have in the config
+            file, or has neither a command nor a URL
+    """
+    by_name = {hook.name: hook for hook in current}
+    merged = []
+    for hook in updated:
+        existing = by_name.get(hook.name)
+        if hook.command and (existing is None or existing.command != hook.command):
+            raise ValueError(f"Hook {hook.name}: commands can only be set in the config file")
+        if not hook.command and not hook.url:
+            raise ValueError(f"Hook {hook.name} needs a command or a URL")
+        headers = {
+            name: (existing.headers.get(name, "") if existing else "")
+            if value == HEADER_MASK
+            else value
+            for name, value in hook.headers.items()
+        }
+        merged.append(hook.model_copy(update={"headers": headers}))
+    return merged
+
+
+def matches(hook: EventHook, event: HookEvent) -> bool:
+    """Whether a hook runs for an event."""
+    if not hook.enabled:
+        return False
+    if hook.connections and event.connection_id not in hook.connections:
+        return False
+    return any(
+        pattern == "*"
+        or pattern == event.name
+        or (pattern.endswith(".*") and event.name.startswith(pattern[:-1]))
+        for pattern in hook.events
+    )
+
+
+def render_command(hook: EventHook, event: HookEvent) -> list[str]:
+    """Split a hook's command into arguments and fill in the event."""
+    values = event.variables()
+    return [Template(arg).safe_substitute(values) for arg in shlex.split(hook.command)]
+
+
+def render_body(hook: EventHook, event: HookEvent) -> str:
+    """A webhook's request body: its template filled in, or the event as JSON."""
+    if not hook.body:
+        return json.dumps(event.to_dict())
+    return Template(hook.body).safe_substitute(event.variables())
+
+
+def run_hook(hook: EventHook, event: HookEvent) -> HookResult:
+    """Run one hook for an event, waiting for it to finish.
+
+    Returns:
+        What happened; failures are reported, not raised
+    """
+    started = time.monotonic()
+    try:
+        if hook.command:
+            message = _run_command(hook, event)
+        elif hook.url:
+            message = _call_webhook(hook, event)
+        else:
+            raise ValueError("Hook has neither a command nor a URL")
+        success = True
+    except (OSError, ValueError, subprocess.SubprocessError, httpx.HTTPError) as e:
+        message = str(e) or type(e).__name__
+        success = False
+        logger.warning("Hook %s failed for %s: %s", hook.name, event.name, message)
+    return HookResult(
+        hook=hook.name,
+        event=event.name,
+        success=success,
+        message=message,
+        duration_ms=(time.monotonic() - started) * 1000,
+    )
+
+
+def _run_command(hook: EventHook, event: HookEvent) -> str:
+    args = render_command(hook, event)
+    if not args:
+        raise ValueError("Hook command is empty")
+    completed = subprocess.run(
+        args,
+        input=json.dumps(event.to_dict()),
+        capture_output=True,
+        text=True,
+        timeout=hook.timeout,
+        check=False,
+    )
+    output = (completed.stdout + completed.stderr).strip()[-MAX_OUTPUT:]
+    if completed.returncode != 0:
+        raise ValueError(f"Exited with {completed.returncode}: {output}".rstrip(": "))
+    return output
+
+
+def _call_webhook(hook: EventHook, event: HookEvent) -> str:
+    headers = {"Content-Type": "application/json", **hook.headers}
+    response = httpx.request(
+        hook.method.upper(),
+        Template(hook.url).safe_substitute(event.variables()),
+        content=render_body(hook, event).encode("utf-8"),
+        headers=headers,
+        timeout=hook.timeout,
+    )
+    if response.status_code >= 400:
+        raise ValueError(f"HTTP {response.status_code}: {response.text[:MAX_OUTPUT]}")
+    return f"HTTP {response.status_code}"
+
+
+class HookRunner:
+    """Runs the configured hooks for events in the background."""
+
+    def __init__(self, max_workers: int = MAX_WORKERS) -> None:
+        self._executor = ThreadPoolExecutor(max_workers=max_workers, thread_name_prefix="hook")
+        self._deliveries: deque[HookResult] = deque(maxlen=MAX_DELIVERIES)
+        self._lock = threading.Lock()
+
+    def hooks_for(self, event: HookEvent) -> list[EventHook]:
+        """The configured hooks an event fires."""
+        return [hook for hook in config_manager.config.event_hooks if matches(hook, event)]
+
+    def emit(self, event: HookEvent) -> list[Future]:
+        """Run the hooks an event fires without waiting for them.
+
+        Returns:
+            A future per hook started, giving its HookResult
+        """
+        futures = []
+        for hook in self.hooks_for(event):
+            futures.append(self._executor.submit(self._deliver, hook, event))
+        return futures
+
+    def test(self, hook: EventHook, event: HookEvent) -> HookResult:
+        """Run a hook for an event now, whatever events it listens to."""
+        return self._deliver(hook, event)
+
+    def _deliver(self, hook: EventHook, event: HookEvent) -> HookResult:
+        result = run_hook(hook, event)
+        with self._lock:
+            self._deliveries.append(result)
+        return result
+
+    def recent(self) -> list[HookResult]:
+        """The latest deliveries, newest first."""
+        with self._lock:
+            return list(reversed(self._deliveries))
+
+
+hook_runner = HookRunner()
+
+
+def emit(
+    event: str,
+    connection: Any = None,
+    workspace: str | None = None,
+    name: str | None = None,
+    **details: Any,
+) -> None:
+    """Fire an event for a connection's resource.
+
+    Never raises: a broken hook configuration mustn't fail the change
+    that fired the event.
+
+    Args:
+        event: One of EVENTS
+        connection: The Connection the change was made on
+        workspace: Workspace of the resource, if any
+        name: Name of the resource
+        **details: Further values for the payload and templates
+    """
+    try:
+        hook_runner.emit(
+            HookEvent(
+                name=event,
+                connection=getattr(connection, "name", "") or "",
+                connection_id=getattr(connection, "id", "") or "",
+                workspace=workspace,
+                resource=name,
+                details=details,
+            )
+        )
+    except Exception:
+        logger.exception("Could not run hooks for %s", event)
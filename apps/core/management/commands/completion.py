@@ -0,0 +1,51 @@
+"""Print a shell completion script.
+
+Usage:
+    source <(cloudbench completion bash)
+    cloudbench completion zsh > "${fpath[1]}/_cloudbench"
+    cloudbench completion fish > ~/.config/fish/completions/cloudbench.fish
+    cloudbench completion --clear-cache
+"""
+
+from django.core.management import get_commands
+from django.core.management.base import BaseCommand, CommandError
+
+from apps.core.completion import (
+    SHELLS,
+    clear_completion_cache,
+    complete_words,
+    completion_script,
+)
+
+
+class Command(BaseCommand):
+    """Print shell completion scripts and answer their completion requests."""
+
+    help = "Print a bash, zsh or fish completion script"
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument("args", nargs="*", metavar="shell", help=f"One of {', '.join(SHELLS)}")
+        parser.add_argument(
+            "--complete",
+            action="store_true",
+            help="Complete the given words; used by the completion scripts",
+        )
+        parser.add_argument(
+            "--clear-cache", action="store_true", help="Forget cached catalog names"
+        )
+
+    def handle(self, *args, **options):
+        """Print the script, or the completions of the given words."""
+        if options["clear_cache"]:
+            clear_completion_cache()
+            return
+
+        if options["complete"]:
+            for candidate in complete_words(list(args), sorted(get_commands())):
+                self.stdout.write(candidate)
+            return
+
+        if len(args) != 1 or args[0] not in SHELLS:
+            raise CommandError(f"Choose a shell: {', '.join(SHELLS)}")
+        self.stdout.write(completion_script(args[0]), ending="")
@@ -0,0 +1,89 @@
+"""Run the CloudBench server, or the API alone as a headless daemon.
+
+Usage:
+    cloudbench serve
+    cloudbench serve --headless
+    cloudbench serve --headless --port 9090 --token "$CLOUDBENCH_API_TOKEN"
+
+In headless mode only the REST API is served, on a loopback address, and
+every request needs the bearer token printed at start-up and written to
+daemon.json in the state directory.
+"""
+
+import os
+
+from django.conf import settings
+from django.core.management.base import BaseCommand, CommandError
+
+from apps.core.headless import (
+    generate_token,
+    is_loopback,
+    remove_daemon_file,
+    write_daemon_file,
+)
+
+
+class Command(BaseCommand):
+    """Serve the web UI and API, or only the API for other tools to drive."""
+
+    help = (
+        "Run the server; with --headless, only the token-protected REST API on "
+        "localhost, for QGIS plugins and scripts"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument(
+            "--host", default="127.0.0.1", help="Address to listen on (default: %(default)s)"
+        )
+        parser.add_argument(
+            "--port", type=int, default=8080, help="Port to listen on (default: %(default)s)"
+        )
+        parser.add_argument(
+            "--headless",
+            action="store_true",
+            help="Serve only the API, and require the bearer token on every request",
+        )
+        parser.add_argument(
+            "--token",
+            default=os.environ.get("CLOUDBENCH_API_TOKEN", ""),
+            help="Bearer token of the headless API; "
+            "$CLOUDBENCH_API_TOKEN or a random one by default",
+        )
+        parser.add_argument(
+            "--allow-remote",
+            action="store_true",
+            help="Allow the headless API to listen on an address other machines can reach",
+        )
+
+    def handle(self, *args, **options):
+        """Start the server and block until it stops."""
+        import uvicorn
+
+        host, port = options["host"].strip("[]"), options["port"]
+        headless = options["headless"]
+        if headless and not is_loopback(host) and not options["allow_remote"]:
+            raise CommandError(
+                f"{host} is reachable from other machines; "
+                "pass --allow-remote to serve the API there anyway"
+            )
+
+        url = f"http://[{host}]:{port}" if ":" in host else f"http://{host}:{port}"
+        if headless:
+            token = options["token"] or generate_token()
+            settings.CLOUDBENCH_HEADLESS = True
+            settings.CLOUDBENCH_API_TOKEN = token
+            path = write_daemon_file(url, token)
+            self.stdout.write(f"Headless API on {url}/api/")
+            self.stdout.write(f"Token: {token}")
+            self.stdout.write(f"Written to {path}")
+        else:
+            self.stdout.write(f"CloudBench on {url}/")
+
+        from cloudbench.asgi import application
+
+        try:
+            uvicorn.run(application, host=host, port=port, log_level="info")
+        finally:
+            if headless:
+                remove_daemon_file()
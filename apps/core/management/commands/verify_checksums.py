@@ -0,0 +1,43 @@
+"""Check exported files against the checksum files written next to them.
+
+Usage:
+    cloudbench verify_checksums backup-topp-20240101-120000.zip
+    cloudbench verify_checksums layer-configs-*.json
+"""
+
+from pathlib import Path
+
+from django.core.management.base import BaseCommand, CommandError
+
+from apps.core.checksum import verify_checksum_file
+
+
+class Command(BaseCommand):
+    """Verify backups and exports before they are restored."""
+
+    help = "Check files against their .sha256 checksum files"
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument("files", nargs="+", help="Exported files to check")
+
+    def handle(self, *args, **options):
+        """Check each file, failing when any doesn't match."""
+        failed = 0
+        for name in options["files"]:
+            path = Path(name)
+            try:
+                matches = verify_checksum_file(path)
+            except (OSError, ValueError) as e:
+                self.stderr.write(f"{name}: {e}")
+                failed += 1
+                continue
+            if matches is None:
+                self.stdout.write(f"{name}: no checksum file")
+            elif matches:
+                self.stdout.write(f"{name}: OK")
+            else:
+                self.stderr.write(f"{name}: FAILED")
+                failed += 1
+        if failed:
+            raise CommandError(f"{failed} file(s) failed verification")
@@ -3,11 +3,52 @@
 Provides cached client instances for GeoServer, S3, and other services.
 """
 
+import logging
 import threading
+from dataclasses import dataclass
 from typing import Any
+from urllib.parse import urlsplit
 
 import httpx
 
+from .ratelimit import RequestLimiter
+
+logger = logging.getLogger(__name__)
+
+
+@dataclass(frozen=True)
+class TransportOptions:
+    """Connection pool tuning for an HTTP client.
+
+    Clients for the same host with the same options share one transport,
+    so keep-alive connections (and their TLS sessions) are reused across
+    GeoServer, GWC and ad-hoc clients.
+    """
+
+    max_idle_connections: int = 10  # keep-alive connections kept per host
+    idle_timeout: float = 5.0  # seconds an idle connection is kept open
+    http2: bool = False
+    response_timeout: float = 30.0  # seconds to wait for a response
+
+    @classmethod
+    def from_connection(cls, connection: Any) -> "TransportOptions":
+        """Build options from a connection's pool settings."""
+        return cls(
+            max_idle_connections=connection.pool_max_idle,
+            idle_timeout=connection.pool_idle_timeout,
+            http2=connection.http2,
+            response_timeout=connection.response_timeout,
+        )
+
+
+def compression_headers(enabled: bool) -> dict[str, str]:
+    """Default headers negotiating response compression.
+
+    httpx decodes gzip/deflate responses transparently; asking for
+    "identity" turns compression off for servers or proxies that mangle it.
+    """
+    return {"Accept-Encoding": "gzip, deflate" if enabled else "identity"}
+
 
 class ClientManager:
     """Thread-safe manager for HTTP client instances.
@@ -26,6 +67,9 @@ class ClientManager:
                     cls._instance = super().__new__(cls)
                     cls._instance._clients: dict[str, httpx.Client] = {}
                     cls._instance._async_clients: dict[str, httpx.AsyncClient] = {}
+                    cls._instance._limiters: dict[str, RequestLimiter] = {}
+                    cls._instance._transports: dict[tuple, httpx.HTTPTransport] = {}
+                    cls._instance._overrides: dict[tuple, httpx.BaseTransport] = {}
         return cls._instance
 
     def get_client(
@@ -34,6 +78,8 @@ class ClientManager:
         base_url: str,
         username: str | None = None,
         password: str | None = None,
+        transport_options: TransportOptions | None = None,
+        auth: httpx.Auth | None = None,
         **kwargs: Any,
     ) -> httpx.Client:
         """Get or create a synchronous HTTP client.
@@ -43,26 +89,78 @@ class ClientManager:
             base_url: Base URL for the client
             username: Optional username for basic auth
             password: Optional password for basic auth
+            transport_options: Optional connection pool tuning
+            auth: Optional auth used instead of basic auth, e.g. a bearer token
             **kwargs: Additional arguments passed to httpx.Client
 
         Returns:
             Cached or new httpx.Client instance
         """
+        options = transport_options or TransportOptions()
         with self._lock:
             if conn_id not in self._clients:
-                auth = None
-                if username and password:
+                if auth is None and username and password:
                     auth = httpx.BasicAuth(username, password)
 
                 self._clients[conn_id] = httpx.Client(
                     base_url=base_url,
                     auth=auth,
-                    timeout=httpx.Timeout(30.0, connect=10.0),
+                    timeout=httpx.Timeout(options.response_timeout, connect=10.0),
                     follow_redirects=True,
+                    transport=self.get_transport(base_url, options),
                     **kwargs,
                 )
             return self._clients[conn_id]
 
+    def get_transport(self, base_url: str, options: TransportOptions) -> httpx.BaseTransport:
+        """Get the shared transport for a host and pool configuration.
+
+        Args:
+            base_url: Any URL on the host
+            options: Connection pool tuning
+
+        Returns:
+            Shared httpx.HTTPTransport instance, or the transport the host
+            was overridden with
+        """
+        parts = urlsplit(base_url)
+        host = (parts.scheme, parts.hostname, parts.port)
+        key = (*host, options)
+        with self._lock:
+            if host in self._overrides:
+                return self._overrides[host]
+            if key not in self._transports:
+                limits = httpx.Limits(
+                    max_keepalive_connections=options.max_idle_connections,
+                    keepalive_expiry=options.idle_timeout,
+                )
+                try:
+                    transport = httpx.HTTPTransport(limits=limits, http2=options.http2)
+                except ImportError:
+                    # HTTP/2 needs the optional h2 package
+                    logger.warning("HTTP/2 unavailable (install httpx[http2]), using HTTP/1.1")
+                    transport = httpx.HTTPTransport(limits=limits)
+                self._transports[key] = transport
+            return self._transports[key]
+
+    def override_transport(self, base_url: str, transport: httpx.BaseTransport | None) -> None:
+        """Send new clients' requests for a host through another transport.
+
+        Used to point clients at a fake server in tests; clients created
+        before the override keep their transport.
+
+        Args:
+            base_url: Any URL on the host
+            transport: Transport to use, or None to remove the override
+        """
+        parts = urlsplit(base_url)
+        host = (parts.scheme, parts.hostname, parts.port)
+        with self._lock:
+            if transport is None:
+                self._overrides.pop(host, None)
+            else:
+                self._overrides[host] = transport
+
     def get_async_client(
         self,
         conn_id: str,
@@ -98,12 +196,40 @@ class ClientManager:
                 )
             return self._async_clients[conn_id]
 
+    def get_limiter(
+        self,
+        conn_id: str,
+        max_concurrent: int = 0,
+        requests_per_second: float = 0,
+    ) -> RequestLimiter:
+        """Get the request limiter shared by all clients of a connection.
+
+        The limiter is rebuilt when the configured limits change.
+
+        Args:
+            conn_id: Connection identifier
+            max_concurrent: Maximum requests in flight (0 = unlimited)
+            requests_per_second: Maximum requests per second (0 = unlimited)
+
+        Returns:
+            RequestLimiter instance
+        """
+        with self._lock:
+            limiter = self._limiters.get(conn_id)
+            if limiter is None or not limiter.matches(max_concurrent, requests_per_second):
+                limiter = RequestLimiter(max_concurrent, requests_per_second)
+                self._limiters[conn_id] = limiter
+            return limiter
+
     def remove_client(self, conn_id: str) -> None:
-        """Remove a cached client."""
+        """Remove a cached client.
+
+        Sync clients aren't closed here: their transport is shared with
+        other clients for the same host and is closed by clear_all().
+        """
         with self._lock:
-            if conn_id in self._clients:
-                self._clients[conn_id].close()
-                del self._clients[conn_id]
+            self._limiters.pop(conn_id, None)
+            self._clients.pop(conn_id, None)
             if conn_id in self._async_clients:
                 # Note: async client should be closed in async context
                 del self._async_clients[conn_id]
@@ -113,8 +239,12 @@ class ClientManager:
         with self._lock:
             for client in self._clients.values():
                 client.close()
+            for transport in self._transports.values():
+                transport.close()
             self._clients.clear()
             self._async_clients.clear()
+            self._limiters.clear()
+            self._transports.clear()
 
 
 # Global client manager instance
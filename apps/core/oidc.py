@@ -0,0 +1,354 @@
+"""OpenID Connect bearer tokens for GeoServers behind an OAuth2 proxy.
+
+Some GeoServers sit behind Keycloak or another OpenID Connect provider
+and only accept requests carrying a bearer token. A connection using
+OIDC signs in once with the device authorization flow: CloudBench shows
+a code to enter on the provider's page and receives an access and a
+refresh token. The refresh token is kept in the system keyring, never in
+the config file, and exchanged for a new access token whenever the old
+one is about to expire, so requests are authorized without asking again.
+"""
+
+import threading
+import time
+from collections.abc import Callable, Generator
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+import httpx
+
+from .exceptions import TokenError
+
+if TYPE_CHECKING:
+    from .config import Connection
+
+# Connection auth types
+AUTH_BASIC = "basic"
+AUTH_OIDC = "oidc"
+AUTH_TYPES = (AUTH_BASIC, AUTH_OIDC)
+
+# offline_access asks for a refresh token that outlives the browser session
+DEFAULT_SCOPE = "openid offline_access"
+
+# Keyring service the refresh tokens are stored under, one per connection ID
+KEYRING_SERVICE = "kartoza-cloudbench"
+
+DEVICE_GRANT = "urn:ietf:params:oauth:grant-type:device_code"
+
+# Access tokens are refreshed this many seconds before they expire
+EXPIRY_MARGIN = 30
+
+# Seconds to wait for the identity provider
+REQUEST_TIMEOUT = 15.0
+
+_lock = threading.Lock()
+_discovery: dict[str, dict[str, Any]] = {}
+# connection ID -> (access token, expiry as time.monotonic())
+_access_tokens: dict[str, tuple[str, float]] = {}
+# connection ID -> sign-in waiting for the user to enter the code
+_pending: dict[str, "DeviceAuthorization"] = {}
+
+
+@dataclass
+class DeviceAuthorization:
+    """A device sign-in waiting for the user to enter the code."""
+
+    device_code: str
+    user_code: str
+    verification_uri: str
+    verification_uri_complete: str | None = None
+    interval: int = 5
+    expires_at: float = field(default_factory=lambda: time.monotonic() + 600)
+
+    @classmethod
+    def from_response(cls, data: dict[str, Any]) -> "DeviceAuthorization":
+        """Build from a device authorization endpoint response."""
+        return cls(
+            device_code=data["device_code"],
+            user_code=data["user_code"],
+            verification_uri=data.get("verification_uri") or data.get("verification_url", ""),
+            verification_uri_complete=data.get("verification_uri_complete"),
+            interval=int(data.get("interval") or 5),
+            expires_at=time.monotonic() + int(data.get("expires_in") or 600),
+        )
+
+    @property
+    def expired(self) -> bool:
+        """Whether the code can no longer be entered."""
+        return time.monotonic() >= self.expires_at
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses; the device code stays on the server."""
+        return {
+            "userCode": self.user_code,
+            "verificationUri": self.verification_uri,
+            "verificationUriComplete": self.verification_uri_complete,
+            "interval": self.interval,
+            "expiresIn": max(0, int(self.expires_at - time.monotonic())),
+        }
+
+
+def _keyring() -> Any:
+    """Import the keyring package, which is needed to keep refresh tokens."""
+    try:
+        import keyring
+    except ImportError as e:
+        raise TokenError("OIDC sign-in needs the keyring package (pip install keyring)") from e
+    return keyring
+
+
+def load_refresh_token(conn_id: str) -> str | None:
+    """Get a connection's refresh token from the keyring."""
+    keyring = _keyring()
+    try:
+        return keyring.get_password(KEYRING_SERVICE, conn_id)
+    except keyring.errors.KeyringError as e:
+        raise TokenError(f"Could not read the system keyring: {e}") from e
+
+
+def store_refresh_token(conn_id: str, token: str) -> None:
+    """Keep a connection's refresh token in the keyring."""
+    keyring = _keyring()
+    try:
+        keyring.set_password(KEYRING_SERVICE, conn_id, token)
+    except keyring.errors.KeyringError as e:
+        raise TokenError(f"Could not store the refresh token in the system keyring: {e}") from e
+
+
+def delete_refresh_token(conn_id: str) -> None:
+    """Forget a connection's refresh token, if there is one."""
+    keyring = _keyring()
+    try:
+        keyring.delete_password(KEYRING_SERVICE, conn_id)
+    except keyring.errors.PasswordDeleteError:
+        pass
+    except keyring.errors.KeyringError as e:
+        raise TokenError(f"Could not update the system keyring: {e}") from e
+
+
+def discover(issuer: str) -> dict[str, Any]:
+    """Read the provider's endpoints from its OpenID configuration, once per issuer.
+
+    Args:
+        issuer: Issuer URL, e.g. https://sso.example.com/realms/gis for Keycloak
+
+    Raises:
+        TokenError: If the configuration can't be read
+    """
+    issuer = issuer.rstrip("/")
+    with _lock:
+        if issuer in _discovery:
+            return _discovery[issuer]
+    try:
+        response = httpx.get(
+            f"{issuer}/.well-known/openid-configuration", timeout=REQUEST_TIMEOUT
+        )
+        response.raise_for_status()
+        config = response.json()
+    except (httpx.HTTPError, ValueError) as e:
+        raise TokenError(f"Could not read the OpenID configuration of {issuer}: {e}") from e
+    with _lock:
+        _discovery[issuer] = config
+    return config
+
+
+def _endpoint(connection: "Connection", name: str) -> str:
+    """Get one of the provider's endpoints for a connection."""
+    if not connection.oidc_issuer or not connection.oidc_client_id:
+        raise TokenError(f"Connection '{connection.name}' needs an OIDC issuer and client ID")
+    url = discover(connection.oidc_issuer).get(name)
+    if not url:
+        raise TokenError(f"{connection.oidc_issuer} doesn't offer a {name}")
+    return url
+
+
+def _post(url: str, data: dict[str, str]) -> httpx.Response:
+    """POST a form to the identity provider."""
+    try:
+        return httpx.post(url, data=data, timeout=REQUEST_TIMEOUT)
+    except httpx.HTTPError as e:
+        raise TokenError(f"Could not reach the identity provider: {e}") from e
+
+
+def _error(response: httpx.Response) -> tuple[str, str]:
+    """Get the OAuth error code and description of a refused token request."""
+    try:
+        data = response.json()
+    except ValueError:
+        data = {}
+    code = data.get("error") or f"HTTP {response.status_code}"
+    return code, data.get("error_description") or code
+
+
+def _accept_tokens(connection: "Connection", data: dict[str, Any]) -> str:
+    """Cache the access token and keep a new refresh token, if one was issued."""
+    token = data.get("access_token")
+    if not token:
+        raise TokenError("The identity provider returned no access token")
+    expires_at = time.monotonic() + int(data.get("expires_in") or 300) - EXPIRY_MARGIN
+    with _lock:
+        _access_tokens[connection.id] = (token, expires_at)
+    if data.get("refresh_token"):
+        store_refresh_token(connection.id, data["refresh_token"])
+    return token
+
+
+def start_sign_in(connection: "Connection") -> DeviceAuthorization:
+    """Start a device sign-in; the user enters the code at the verification URL.
+
+    Raises:
+        TokenError: If the provider refuses to start a sign-in
+    """
+    response = _post(
+        _endpoint(connection, "device_authorization_endpoint"),
+        {"client_id": connection.oidc_client_id, "scope": connection.oidc_scope},
+    )
+    if response.status_code != 200:
+        raise TokenError(f"Sign-in could not start: {_error(response)[1]}")
+    device = DeviceAuthorization.from_response(response.json())
+    with _lock:
+        _pending[connection.id] = device
+    return device
+
+
+def pending_sign_in(conn_id: str) -> DeviceAuthorization | None:
+    """Get the sign-in started for a connection, if it's still waiting."""
+    with _lock:
+        return _pending.get(conn_id)
+
+
+def poll_sign_in(connection: "Connection", device: DeviceAuthorization) -> bool:
+    """Ask once whether the user has entered the code.
+
+    Returns:
+        True once signed in, False while the provider is still waiting
+
+    Raises:
+        TokenError: If the sign-in was denied or the code expired
+    """
+    if device.expired:
+        raise TokenError("The sign-in code expired; start again")
+    response = _post(
+        _endpoint(connection, "token_endpoint"),
+        {
+            "grant_type": DEVICE_GRANT,
+            "device_code": device.device_code,
+            "client_id": connection.oidc_client_id,
+        },
+    )
+    if response.status_code == 200:
+        _accept_tokens(connection, response.json())
+        with _lock:
+            _pending.pop(connection.id, None)
+        return True
+
+    code, description = _error(response)
+    if code == "authorization_pending":
+        return False
+    if code == "slow_down":
+        device.interval += 5
+        return False
+    with _lock:
+        _pending.pop(connection.id, None)
+    raise TokenError(f"Sign-in failed: {description}")
+
+
+def wait_for_sign_in(
+    connection: "Connection",
+    device: DeviceAuthorization,
+    cancelled: Callable[[], bool] | None = None,
+) -> bool:
+    """Poll until the user has entered the code, at the interval the provider asks for.
+
+    Returns:
+        True once signed in, False if cancelled
+
+    Raises:
+        TokenError: If the sign-in was denied or the code expired
+    """
+    while not (cancelled and cancelled()):
+        if poll_sign_in(connection, device):
+            return True
+        time.sleep(device.interval)
+    return False
+
+
+def sign_out(connection: "Connection") -> None:
+    """Forget a connection's tokens."""
+    with _lock:
+        _access_tokens.pop(connection.id, None)
+        _pending.pop(connection.id, None)
+    delete_refresh_token(connection.id)
+
+
+def is_signed_in(connection: "Connection") -> bool:
+    """Whether a refresh token is kept for the connection."""
+    try:
+        return load_refresh_token(connection.id) is not None
+    except TokenError:
+        return False
+
+
+def access_token(connection: "Connection", force_refresh: bool = False) -> str:
+    """Get a valid access token, refreshing it when it's about to expire.
+
+    Args:
+        connection: Connection using OIDC
+        force_refresh: Refresh even if the cached token looks valid, e.g.
+            after the server refused it
+
+    Raises:
+        TokenError: If the connection isn't signed in or the refresh failed
+    """
+    with _lock:
+        cached = _access_tokens.get(connection.id)
+    if cached and not force_refresh and time.monotonic() < cached[1]:
+        return cached[0]
+
+    refresh_token = load_refresh_token(connection.id)
+    if not refresh_token:
+        raise TokenError(f"Sign in to '{connection.name}' on the connections screen first")
+    response = _post(
+        _endpoint(connection, "token_endpoint"),
+        {
+            "grant_type": "refresh_token",
+            "refresh_token": refresh_token,
+            "client_id": connection.oidc_client_id,
+        },
+    )
+    if response.status_code != 200:
+        code, description = _error(response)
+        if code == "invalid_grant":
+            # The session ended on the provider; the token is of no further use
+            sign_out(connection)
+            raise TokenError(f"The session for '{connection.name}' expired; sign in again")
+        raise TokenError(f"Could not refresh the access token: {description}")
+    return _accept_tokens(connection, response.json())
+
+
+class BearerAuth(httpx.Auth):
+    """Adds a connection's access token to requests, refreshing it when refused."""
+
+    def __init__(self, connection: "Connection"):
+        """Initialize bearer auth.
+
+        Args:
+            connection: Connection using OIDC
+        """
+        self.connection = connection
+
+    def auth_flow(self, request: httpx.Request) -> Generator[httpx.Request, httpx.Response, None]:
+        """Send the request with a bearer token, retrying once with a fresh token on a 401."""
+        request.headers["Authorization"] = f"Bearer {access_token(self.connection)}"
+        response = yield request
+        if response.status_code == 401:
+            token = access_token(self.connection, force_refresh=True)
+            request.headers["Authorization"] = f"Bearer {token}"
+            yield request
+
+
+def connection_auth(connection: "Connection") -> httpx.Auth | None:
+    """Get the auth for a connection's requests; None means basic auth with its password."""
+    if connection.auth_type == AUTH_OIDC:
+        return BearerAuth(connection)
+    return None
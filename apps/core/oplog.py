@@ -0,0 +1,249 @@
+"""Write-ahead log of batch operations.
+
+Before a batch operation (mass truncate, bulk publish, sync) sends its
+first request, every step it plans is written to a log file, and each
+step is appended to it as it finishes. A run that ends with every step
+done deletes its log. One cut short by a network drop, Ctrl+C or a
+crash leaves the log behind, and resuming it runs only the steps that
+didn't finish, instead of starting over. Steps that failed are retried
+too, so a run whose requests failed while the network was down can be
+resumed once it is back.
+
+Logs are JSON lines in the state directory: a plan, then one line per
+finished step. Lines are flushed to disk as they are written, so the
+log survives whatever stops the run; a half-written last line is
+ignored.
+"""
+
+import json
+import logging
+import os
+import re
+import threading
+import uuid
+from dataclasses import dataclass, field
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import Any
+
+from .config import get_state_dir
+
+logger = logging.getLogger(__name__)
+
+OPERATIONS_DIR = "operations"
+
+
+@dataclass
+class Operation:
+    """A batch operation and the steps of it that finished."""
+
+    id: str
+    kind: str  # e.g. "truncate", "upload" or "sync"
+    conn_id: str
+    steps: list[str]
+    params: dict[str, Any] = field(default_factory=dict)  # What a resume needs to rerun it
+    created_at: str = field(default_factory=lambda: datetime.now(timezone.utc).isoformat())
+    done: dict[str, str] = field(default_factory=dict)  # Step -> message
+    failed: dict[str, str] = field(default_factory=dict)  # Step -> last error
+
+    @property
+    def pending(self) -> list[str]:
+        """Steps still to run, in plan order."""
+        return [step for step in self.steps if step not in self.done]
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "id": self.id,
+            "kind": self.kind,
+            "connectionId": self.conn_id,
+            "total": len(self.steps),
+            "done": len(self.done),
+            "pending": self.pending,
+            "failed": self.failed,
+            "params": self.params,
+            "createdAt": self.created_at,
+        }
+
+
+class OperationLog:
+    """Log files of the batch operations that haven't finished."""
+
+    _lock = threading.Lock()
+    # Operations running in this process, which aren't interrupted
+    _active: set[str] = set()
+
+    def __init__(self, directory: Path | None = None):
+        """Initialize the log.
+
+        Args:
+            directory: Directory holding a file per operation; the state
+                directory by default
+        """
+        self._directory = directory
+
+    def _dir(self) -> Path:
+        directory = self._directory or get_state_dir() / OPERATIONS_DIR
+        directory.mkdir(parents=True, exist_ok=True)
+        return directory
+
+    def path(self, op_id: str) -> Path:
+        """The log file of an operation."""
+        return self._dir() / f"{re.sub(r'[^A-Za-z0-9_.-]', '_', op_id)}.jsonl"
+
+    def _append(self, op_id: str, entry: dict[str, Any]) -> None:
+        with self._lock, self.path(op_id).open("a", encoding="utf-8") as f:
+            f.write(json.dumps(entry) + "\n")
+            f.flush()
+            os.fsync(f.fileno())
+
+    def begin(
+        self,
+        kind: str,
+        conn_id: str,
+        steps: list[str],
+        params: dict[str, Any] | None = None,
+    ) -> Operation:
+        """Log the plan of an operation before any of it runs.
+
+        Args:
+            kind: What the operation does, so it can be resumed the same way
+            conn_id: Connection ID it runs against
+            steps: Unique step names, e.g. layer names or file paths
+            params: Whatever else a resume needs, as JSON
+
+        Returns:
+            The operation, to record each step of
+        """
+        operation = Operation(
+            id=str(uuid.uuid4()),
+            kind=kind,
+            conn_id=conn_id,
+            steps=list(steps),
+            params=params or {},
+        )
+        self._append(
+            operation.id,
+            {
+                "plan": True,
+                "kind": operation.kind,
+                "connection": operation.conn_id,
+                "steps": operation.steps,
+                "params": operation.params,
+                "created": operation.created_at,
+            },
+        )
+        with self._lock:
+            self._active.add(operation.id)
+        return operation
+
+    def record(self, operation: Operation, step: str, ok: bool, message: str = "") -> None:
+        """Log a finished step; failed steps stay pending."""
+        try:
+            self._append(operation.id, {"step": step, "ok": ok, "message": message})
+        except OSError as e:
+            # A step missing from the log is only run again on resume; never fail it
+            logger.warning("Could not log step %s of %s: %s", step, operation.id, e)
+        with self._lock:
+            if ok:
+                operation.done[step] = message
+                operation.failed.pop(step, None)
+            else:
+                operation.failed[step] = message
+
+    def close(self, operation: Operation) -> bool:
+        """End a run, deleting the log if every step is done.
+
+        Returns:
+            Whether the operation is complete; if not, it can be resumed
+        """
+        with self._lock:
+            self._active.discard(operation.id)
+        if operation.pending:
+            return False
+        self.path(operation.id).unlink(missing_ok=True)
+        return True
+
+    def _read(self, path: Path) -> Operation | None:
+        try:
+            lines = path.read_text(encoding="utf-8").splitlines()
+        except OSError:
+            return None
+        operation = None
+        for line in lines:
+            try:
+                entry = json.loads(line)
+            except ValueError:
+                continue  # Cut off by a crash while it was written
+            if entry.get("plan"):
+                operation = Operation(
+                    id=path.stem,
+                    kind=entry.get("kind", ""),
+                    conn_id=entry.get("connection", ""),
+                    steps=list(entry.get("steps", [])),
+                    params=entry.get("params") or {},
+                    created_at=entry.get("created", ""),
+                )
+            elif operation is not None and "step" in entry:
+                if entry.get("ok"):
+                    operation.done[entry["step"]] = entry.get("message", "")
+                    operation.failed.pop(entry["step"], None)
+                else:
+                    operation.failed[entry["step"]] = entry.get("message", "")
+        return operation
+
+    def get(self, op_id: str) -> Operation | None:
+        """An interrupted operation, or None if there is none or it is running."""
+        with self._lock:
+            if op_id in self._active:
+                return None
+        return self._read(self.path(op_id))
+
+    def resume(self, op_id: str) -> Operation | None:
+        """Take an interrupted operation over to run its pending steps.
+
+        Returns:
+            The operation, or None if there is none or it is already running
+        """
+        operation = self._read(self.path(op_id))
+        with self._lock:
+            if operation is None or op_id in self._active:
+                return None
+            self._active.add(op_id)
+        return operation
+
+    def interrupted(self, conn_id: str | None = None, kind: str | None = None) -> list[Operation]:
+        """List the operations that can be resumed, oldest first."""
+        with self._lock:
+            active = set(self._active)
+        operations = [
+            op
+            for path in self._dir().glob("*.jsonl")
+            if path.stem not in active and (op := self._read(path)) is not None
+        ]
+        return sorted(
+            (
+                op
+                for op in operations
+                if (conn_id is None or op.conn_id == conn_id) and (kind is None or op.kind == kind)
+            ),
+            key=lambda op: op.created_at,
+        )
+
+    def discard(self, op_id: str) -> bool:
+        """Delete the log of an interrupted operation without resuming it.
+
+        Returns:
+            Whether there was such an operation
+        """
+        with self._lock:
+            if op_id in self._active:
+                return False
+        path = self.path(op_id)
+        if not path.exists():
+            return False
+        path.unlink(missing_ok=True)
+        return True
+
+
+operation_log = OperationLog()
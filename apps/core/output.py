@@ -0,0 +1,164 @@
+"""Output formatting for management commands.
+
+List and get commands print records (flat dicts) in one of several
+formats so their output can feed other tools:
+
+    cloudbench connections --output json | jq '.[].url'
+    cloudbench connections --quiet | xargs -n1 cloudbench lint
+
+Commands subclass OutputCommand to get the --output and --quiet options.
+"""
+
+import csv
+import io
+import json
+from typing import Any
+
+from django.core.management.base import BaseCommand
+
+FORMATS = ("table", "json", "yaml", "csv")
+
+Record = dict[str, Any]
+
+
+def _columns(records: list[Record], columns: list[str] | None) -> list[str]:
+    if columns:
+        return columns
+    seen: dict[str, None] = {}
+    for record in records:
+        seen.update(dict.fromkeys(record))
+    return list(seen)
+
+
+def _cell(value: Any) -> str:
+    if value is None:
+        return ""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, (list, tuple)):
+        return ",".join(_cell(v) for v in value)
+    if isinstance(value, dict):
+        return json.dumps(value)
+    return str(value)
+
+
+def format_table(records: list[Record], columns: list[str] | None = None) -> str:
+    """Format records as aligned text columns with a header row."""
+    columns = _columns(records, columns)
+    rows = [[c.upper() for c in columns]]
+    rows += [[_cell(record.get(c)) for c in columns] for record in records]
+    widths = [max(len(row[i]) for row in rows) for i in range(len(columns))]
+    return "\n".join(
+        "  ".join(cell.ljust(width) for cell, width in zip(row, widths)).rstrip()
+        for row in rows
+    )
+
+
+def format_csv(records: list[Record], columns: list[str] | None = None) -> str:
+    """Format records as CSV with a header row."""
+    columns = _columns(records, columns)
+    buffer = io.StringIO()
+    writer = csv.writer(buffer, lineterminator="\n")
+    writer.writerow(columns)
+    writer.writerows([_cell(record.get(c)) for c in columns] for record in records)
+    return buffer.getvalue().rstrip("\n")
+
+
+def _yaml_scalar(value: Any) -> str:
+    if value is None:
+        return "null"
+    if isinstance(value, (bool, int, float)):
+        return json.dumps(value)
+    # JSON strings are valid double-quoted YAML scalars
+    return json.dumps(str(value))
+
+
+def _yaml(value: Any, indent: int) -> list[str]:
+    pad = "  " * indent
+    if isinstance(value, dict):
+        if not value:
+            return [f"{pad}{{}}"]
+        lines = []
+        for key, item in value.items():
+            if isinstance(item, (dict, list)) and item:
+                lines.append(f"{pad}{key}:")
+                lines.extend(_yaml(item, indent + 1))
+            else:
+                lines.append(f"{pad}{key}: {_yaml(item, 0)[0]}")
+        return lines
+    if isinstance(value, list):
+        if not value:
+            return [f"{pad}[]"]
+        lines = []
+        for item in value:
+            nested = _yaml(item, indent + 1)
+            # Put the first line of the item on the dash line
+            lines.append(f"{pad}- {nested[0].lstrip()}")
+            lines.extend(nested[1:])
+        return lines
+    return [f"{pad}{_yaml_scalar(value)}"]
+
+
+def format_yaml(data: Any) -> str:
+    """Format JSON-compatible data as YAML."""
+    return "\n".join(_yaml(data, 0))
+
+
+def format_records(
+    records: list[Record], output: str = "table", columns: list[str] | None = None
+) -> str:
+    """Format records for printing.
+
+    Args:
+        records: Records to print
+        output: One of FORMATS
+        columns: Columns for table and CSV output; all keys when omitted
+
+    Returns:
+        The formatted text, without a trailing newline
+    """
+    if output == "json":
+        return json.dumps(records, indent=2)
+    if output == "yaml":
+        return format_yaml(records)
+    if output == "csv":
+        return format_csv(records, columns)
+    if output == "table":
+        return format_table(records, columns)
+    raise ValueError(f"Unknown output format: {output}")
+
+
+class OutputCommand(BaseCommand):
+    """Base for commands that print records."""
+
+    # Record key printed by --quiet
+    quiet_key = "id"
+
+    def add_output_arguments(self, parser) -> None:
+        """Add the --output and --quiet options."""
+        parser.add_argument(
+            "--output",
+            "-o",
+            choices=FORMATS,
+            default="table",
+            help="Output format (default: table)",
+        )
+        parser.add_argument(
+            "--quiet",
+            "-q",
+            action="store_true",
+            help=f"Print only the {self.quiet_key} of each result, one per line",
+        )
+
+    def write_records(
+        self, records: list[Record], options: dict[str, Any], columns: list[str] | None = None
+    ) -> None:
+        """Print records in the format chosen on the command line."""
+        if options.get("quiet"):
+            for record in records:
+                self.stdout.write(_cell(record.get(self.quiet_key)))
+            return
+
+        text = format_records(records, options.get("output") or "table", columns)
+        if text:
+            self.stdout.write(text)
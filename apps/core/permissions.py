@@ -0,0 +1,22 @@
+"""Permissions of API endpoints that can run things on this machine."""
+
+from rest_framework.permissions import BasePermission
+
+from .headless import has_daemon_token
+
+
+class IsAdminOrDaemonToken(BasePermission):
+    """Allow staff users, and requests carrying the headless daemon's token.
+
+    Guards endpoints such as the hooks, whose settings reach the local
+    shell and credentials. The peer address is not trusted, since behind a
+    reverse proxy every request comes from this machine.
+    """
+
+    message = "Only administrators or holders of the API token are allowed"
+
+    def has_permission(self, request, view) -> bool:
+        user = getattr(request, "user", None)
+        if user is not None and user.is_staff:
+            return True
+        return has_daemon_token(request)
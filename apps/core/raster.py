@@ -0,0 +1,207 @@
+"""Minimal RGBA raster images.
+
+Enough image handling to draw map previews in a terminal without an
+imaging library: decoding the PNGs GeoServer and tile servers return,
+nearest-neighbour resampling and alpha compositing.
+"""
+
+import struct
+import zlib
+from dataclasses import dataclass
+
+PNG_SIGNATURE = b"\x89PNG\r\n\x1a\n"
+
+# PNG color type -> samples per pixel
+_CHANNELS = {0: 1, 2: 3, 3: 1, 4: 2, 6: 4}
+
+
+@dataclass
+class Image:
+    """An RGBA image stored as rows of bytes, 4 per pixel."""
+
+    width: int
+    height: int
+    pixels: bytearray
+
+    @classmethod
+    def blank(
+        cls, width: int, height: int, color: tuple[int, int, int, int] = (0, 0, 0, 0)
+    ) -> "Image":
+        """Create an image filled with one color."""
+        return cls(width, height, bytearray(bytes(color) * (width * height)))
+
+    def get(self, x: int, y: int) -> tuple[int, int, int, int]:
+        """Get the RGBA color of a pixel."""
+        i = (y * self.width + x) * 4
+        r, g, b, a = self.pixels[i : i + 4]
+        return r, g, b, a
+
+    def set(self, x: int, y: int, color: tuple[int, int, int, int]) -> None:
+        """Set the RGBA color of a pixel."""
+        i = (y * self.width + x) * 4
+        self.pixels[i : i + 4] = bytes(color)
+
+    def resized(self, width: int, height: int) -> "Image":
+        """Resample to a new size with nearest-neighbour sampling."""
+        out = bytearray(width * height * 4)
+        for y in range(height):
+            sy = min(self.height - 1, y * self.height // height)
+            row = sy * self.width
+            for x in range(width):
+                sx = min(self.width - 1, x * self.width // width)
+                i = (row + sx) * 4
+                o = (y * width + x) * 4
+                out[o : o + 4] = self.pixels[i : i + 4]
+        return Image(width, height, out)
+
+    def composite_over(self, base: "Image") -> "Image":
+        """Draw this image over a base image of the same size."""
+        if (self.width, self.height) != (base.width, base.height):
+            raise ValueError("Images must have the same size")
+        out = bytearray(base.pixels)
+        src = self.pixels
+        for i in range(0, len(out), 4):
+            alpha = src[i + 3]
+            if alpha == 255:
+                out[i : i + 4] = src[i : i + 4]
+            elif alpha:
+                inv = 255 - alpha
+                for c in range(3):
+                    out[i + c] = (src[i + c] * alpha + out[i + c] * inv) // 255
+                out[i + 3] = max(out[i + 3], alpha)
+        return Image(base.width, base.height, out)
+
+
+def _paeth(a: int, b: int, c: int) -> int:
+    p = a + b - c
+    pa, pb, pc = abs(p - a), abs(p - b), abs(p - c)
+    if pa <= pb and pa <= pc:
+        return a
+    return b if pb <= pc else c
+
+
+def _unfilter(data: bytes, height: int, stride: int, bpp: int) -> bytearray:
+    """Undo the per-row PNG filters."""
+    out = bytearray(height * stride)
+    prev = bytearray(stride)
+    pos = 0
+    for y in range(height):
+        kind = data[pos]
+        row = bytearray(data[pos + 1 : pos + 1 + stride])
+        pos += stride + 1
+        if kind == 1:
+            for i in range(bpp, stride):
+                row[i] = (row[i] + row[i - bpp]) & 0xFF
+        elif kind == 2:
+            for i in range(stride):
+                row[i] = (row[i] + prev[i]) & 0xFF
+        elif kind == 3:
+            for i in range(stride):
+                left = row[i - bpp] if i >= bpp else 0
+                row[i] = (row[i] + ((left + prev[i]) >> 1)) & 0xFF
+        elif kind == 4:
+            for i in range(stride):
+                left = row[i - bpp] if i >= bpp else 0
+                up_left = prev[i - bpp] if i >= bpp else 0
+                row[i] = (row[i] + _paeth(left, prev[i], up_left)) & 0xFF
+        elif kind != 0:
+            raise ValueError(f"Unknown PNG filter type {kind}")
+        out[y * stride : (y + 1) * stride] = row
+        prev = row
+    return out
+
+
+def decode_png(data: bytes) -> Image:
+    """Decode a non-interlaced PNG into an RGBA image.
+
+    Supports greyscale, RGB, palette and alpha color types at 8 bits per
+    sample, and palettes at lower bit depths, which covers what map
+    servers produce.
+
+    Raises:
+        ValueError: If the data isn't a PNG this decoder understands
+    """
+    if not data.startswith(PNG_SIGNATURE):
+        raise ValueError("Not a PNG image")
+
+    pos = len(PNG_SIGNATURE)
+    header = None
+    palette = b""
+    transparency = b""
+    compressed = bytearray()
+    while pos + 8 <= len(data):
+        length, kind = struct.unpack(">I4s", data[pos : pos + 8])
+        chunk = data[pos + 8 : pos + 8 + length]
+        pos += length + 12  # Length, type, data and CRC
+        if kind == b"IHDR":
+            header = struct.unpack(">IIBBBBB", chunk)
+        elif kind == b"PLTE":
+            palette = chunk
+        elif kind == b"tRNS":
+            transparency = chunk
+        elif kind == b"IDAT":
+            compressed += chunk
+        elif kind == b"IEND":
+            break
+
+    if header is None:
+        raise ValueError("PNG has no header")
+    width, height, depth, color_type, _, _, interlace = header
+    if interlace:
+        raise ValueError("Interlaced PNGs are not supported")
+    if color_type not in _CHANNELS or (depth != 8 and not (color_type == 3 and depth < 8)):
+        raise ValueError(f"Unsupported PNG format: color type {color_type}, depth {depth}")
+
+    channels = _CHANNELS[color_type]
+    bits_per_pixel = channels * depth
+    stride = (width * bits_per_pixel + 7) // 8
+    raw = _unfilter(zlib.decompress(bytes(compressed)), height, stride, max(1, bits_per_pixel // 8))
+
+    pixels = bytearray(width * height * 4)
+    o = 0
+    for y in range(height):
+        row = raw[y * stride : (y + 1) * stride]
+        for x in range(width):
+            if color_type == 3:
+                if depth == 8:
+                    index = row[x]
+                else:
+                    per_byte = 8 // depth
+                    shift = 8 - depth * (x % per_byte + 1)
+                    index = (row[x // per_byte] >> shift) & ((1 << depth) - 1)
+                r, g, b = palette[index * 3 : index * 3 + 3] or b"\x00\x00\x00"
+                a = transparency[index] if index < len(transparency) else 255
+            elif color_type == 0:
+                r = g = b = row[x]
+                a = 255
+            elif color_type == 4:
+                r = g = b = row[x * 2]
+                a = row[x * 2 + 1]
+            elif color_type == 2:
+                r, g, b = row[x * 3 : x * 3 + 3]
+                a = 255
+            else:
+                r, g, b, a = row[x * 4 : x * 4 + 4]
+            pixels[o : o + 4] = bytes((r, g, b, a))
+            o += 4
+    return Image(width, height, pixels)
+
+
+def encode_png(image: Image) -> bytes:
+    """Encode an RGBA image as a PNG; used to build test fixtures and exports."""
+
+    def chunk(kind: bytes, body: bytes) -> bytes:
+        crc = zlib.crc32(kind + body) & 0xFFFFFFFF
+        return struct.pack(">I", len(body)) + kind + body + struct.pack(">I", crc)
+
+    stride = image.width * 4
+    raw = b"".join(
+        b"\x00" + bytes(image.pixels[y * stride : (y + 1) * stride]) for y in range(image.height)
+    )
+    header = struct.pack(">IIBBBBB", image.width, image.height, 8, 6, 0, 0, 0)
+    return (
+        PNG_SIGNATURE
+        + chunk(b"IHDR", header)
+        + chunk(b"IDAT", zlib.compress(raw))
+        + chunk(b"IEND", b"")
+    )
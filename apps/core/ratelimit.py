@@ -0,0 +1,111 @@
+"""Per-connection request limiting.
+
+Bulk operations (sync, bulk publish, feature counts) can fire hundreds of
+REST calls at a single GeoServer. A RequestLimiter caps how many requests
+are in flight at once and how many are started per second, so small
+instances aren't overwhelmed.
+"""
+
+import threading
+import time
+from collections.abc import Callable, Iterator
+from contextlib import contextmanager
+
+
+class TokenBucket:
+    """Token bucket allowing `rate` acquisitions per second on average.
+
+    Up to `burst` tokens can accumulate while idle, so short bursts are
+    served immediately.
+    """
+
+    def __init__(
+        self,
+        rate: float,
+        burst: int | None = None,
+        clock: Callable[[], float] = time.monotonic,
+        sleep: Callable[[float], None] = time.sleep,
+    ):
+        """Initialize the bucket.
+
+        Args:
+            rate: Tokens added per second (must be positive)
+            burst: Bucket capacity, defaults to max(1, rate)
+            clock: Monotonic clock, injectable for tests
+            sleep: Sleep function, injectable for tests
+        """
+        if rate <= 0:
+            raise ValueError("rate must be positive")
+        self.rate = rate
+        self.capacity = float(burst if burst is not None else max(1, int(rate)))
+        self._clock = clock
+        self._sleep = sleep
+        self._tokens = self.capacity
+        self._updated = clock()
+        self._lock = threading.Lock()
+
+    def _refill(self) -> None:
+        now = self._clock()
+        self._tokens = min(self.capacity, self._tokens + (now - self._updated) * self.rate)
+        self._updated = now
+
+    def acquire(self) -> float:
+        """Take one token, blocking until one is available.
+
+        Returns:
+            Seconds spent waiting
+        """
+        waited = 0.0
+        while True:
+            with self._lock:
+                self._refill()
+                if self._tokens >= 1:
+                    self._tokens -= 1
+                    return waited
+                delay = (1 - self._tokens) / self.rate
+            self._sleep(delay)
+            waited += delay
+
+
+class RequestLimiter:
+    """Limits concurrent and per-second requests for one connection.
+
+    A value of 0 for either limit disables it.
+    """
+
+    def __init__(self, max_concurrent: int = 0, requests_per_second: float = 0):
+        """Initialize the limiter.
+
+        Args:
+            max_concurrent: Maximum requests in flight at once (0 = unlimited)
+            requests_per_second: Maximum requests started per second (0 = unlimited)
+        """
+        self.max_concurrent = max_concurrent
+        self.requests_per_second = requests_per_second
+        self._semaphore = threading.BoundedSemaphore(max_concurrent) if max_concurrent > 0 else None
+        self._bucket = TokenBucket(requests_per_second) if requests_per_second > 0 else None
+
+    @property
+    def enabled(self) -> bool:
+        """Whether any limit is active."""
+        return self._semaphore is not None or self._bucket is not None
+
+    def matches(self, max_concurrent: int, requests_per_second: float) -> bool:
+        """Check whether this limiter was built with the given limits."""
+        return (
+            self.max_concurrent == max_concurrent
+            and self.requests_per_second == requests_per_second
+        )
+
+    @contextmanager
+    def slot(self) -> Iterator[None]:
+        """Hold a request slot for the duration of the block."""
+        if self._semaphore is not None:
+            self._semaphore.acquire()
+        try:
+            if self._bucket is not None:
+                self._bucket.acquire()
+            yield
+        finally:
+            if self._semaphore is not None:
+                self._semaphore.release()
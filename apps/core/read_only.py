@@ -0,0 +1,30 @@
+"""Read-only connections.
+
+A connection can be marked read-only as a guard rail, typically for a
+production server someone only needs to browse. The GeoServer and GWC
+clients check every request against it before sending it, so a create,
+edit or delete from any screen, batch job or sync fails with a clear
+error instead of reaching the server.
+"""
+
+from typing import TYPE_CHECKING
+
+from .exceptions import ReadOnlyConnectionError
+
+if TYPE_CHECKING:
+    from .config import Connection
+
+# Methods that never change the server
+READ_METHODS = frozenset({"GET", "HEAD", "OPTIONS"})
+
+
+def check_writable(connection: "Connection", method: str, path: str) -> None:
+    """Refuse a request that would change a read-only connection's server.
+
+    Raises:
+        ReadOnlyConnectionError: If the connection is read-only and the
+            method isn't a read
+    """
+    method = method.upper()
+    if connection.read_only and method not in READ_METHODS:
+        raise ReadOnlyConnectionError(connection.name, method, path)
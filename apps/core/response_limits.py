@@ -0,0 +1,68 @@
+"""Response size limits.
+
+A misbehaving server, or a proxy in front of one, can answer a small
+REST call with hundreds of megabytes. The GeoServer and GWC clients read
+response bodies in pieces and give up with a clear error once a body is
+bigger than the connection allows, instead of holding all of it in
+memory. Error responses are only read up to a few kilobytes, since all
+that's needed of them is a message. Deliberate downloads, like backup
+archives and data directory files, are streamed instead of limited.
+"""
+
+import httpx
+
+from .exceptions import ResponseTooLargeError
+
+# Default limit for a connection's responses, in megabytes; 0 is no limit
+DEFAULT_MAX_RESPONSE_MB = 64
+
+# Bytes of an error response kept for its message
+ERROR_BODY_BYTES = 64 * 1024
+
+# Bytes read at a time
+CHUNK_SIZE = 64 * 1024
+
+
+def limit_bytes(max_response_mb: float) -> int | None:
+    """Get a connection's limit in bytes; None when it has no limit."""
+    return int(max_response_mb * 1024 * 1024) if max_response_mb > 0 else None
+
+
+def read_limited(response: httpx.Response, max_response_mb: float) -> httpx.Response:
+    """Read a streamed response body, refusing one bigger than the limit.
+
+    The body of an error response is cut off after ERROR_BODY_BYTES
+    rather than refused, so its message can still be reported.
+
+    Args:
+        response: Response opened with stream=True, not read yet
+        max_response_mb: Limit in megabytes; 0 is no limit
+
+    Returns:
+        The response, with its body read
+
+    Raises:
+        ResponseTooLargeError: If a successful response is over the limit
+    """
+    limit = limit_bytes(max_response_mb)
+    truncate = response.status_code >= 400
+    if truncate:
+        limit = min(limit or ERROR_BODY_BYTES, ERROR_BODY_BYTES)
+
+    url = str(response.request.url)
+    length = response.headers.get("content-length", "")
+    if limit and not truncate and length.isdigit() and int(length) > limit:
+        raise ResponseTooLargeError(url, max_response_mb, int(length))
+
+    body = bytearray()
+    for chunk in response.iter_bytes(CHUNK_SIZE):
+        body += chunk
+        if limit and len(body) > limit:
+            if not truncate:
+                raise ResponseTooLargeError(url, max_response_mb)
+            del body[limit:]
+            break
+    # What Response.read() does, with the body gathered above
+    response._content = bytes(body)
+    return response
+
@@ -0,0 +1,71 @@
+"""Background schedulers of the serving process.
+
+Schedulers run their schedules at the start of every minute, so only
+one process may run them or each schedule would fire once per process.
+They're started from the ASGI and WSGI entry points, never by management
+commands, and only by the serving process that takes the lock file in
+the state directory; other workers of the same server leave them be.
+"""
+
+import logging
+from typing import IO
+
+from django.conf import settings
+
+from apps.core.config import get_state_dir
+
+try:
+    import fcntl
+except ImportError:  # Windows
+    fcntl = None
+    import msvcrt
+
+logger = logging.getLogger(__name__)
+
+# Lock file held by the process running the schedulers
+LOCK_FILE = "schedulers.lock"
+
+# Open lock file of this process, kept open so the lock lasts until it exits
+_lock_file: IO | None = None
+
+
+def acquire_scheduler_lock() -> bool:
+    """Take the schedulers' lock unless another process holds it.
+
+    Returns:
+        Whether this process holds the lock
+    """
+    global _lock_file
+    if _lock_file is not None:
+        return True
+    handle = open(get_state_dir() / LOCK_FILE, "a")
+    try:
+        if fcntl is not None:
+            fcntl.flock(handle, fcntl.LOCK_EX | fcntl.LOCK_NB)
+        else:
+            msvcrt.locking(handle.fileno(), msvcrt.LK_NBLCK, 1)
+    except OSError:
+        handle.close()
+        return False
+    _lock_file = handle
+    return True
+
+
+def start_schedulers() -> None:
+    """Start the enabled schedulers if no other process runs them."""
+    cache = getattr(settings, "CLOUDBENCH_CACHE_SCHEDULER_ENABLED", False)
+    snapshots = getattr(settings, "CLOUDBENCH_SNAPSHOT_SCHEDULER_ENABLED", False)
+    if not cache and not snapshots:
+        return
+    if not acquire_scheduler_lock():
+        logger.info("Schedulers already run in another process")
+        return
+
+    if cache:
+        from apps.gwc.scheduler import get_cache_scheduler
+
+        get_cache_scheduler().start()
+    if snapshots:
+        from apps.geoserver.snapshot_schedule import get_snapshot_scheduler
+
+        get_snapshot_scheduler().start()
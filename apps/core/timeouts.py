@@ -0,0 +1,79 @@
+"""Timeouts by kind of request.
+
+Every request has a timeout for connecting and one for going without
+sending or receiving a byte; there is no limit on how long a transfer
+that keeps moving may take. Catalog requests wait for each connection's
+response timeout, while uploads, downloads and OWS requests wait as long
+as the timeouts setting in the config file allows.
+
+Upload bodies are sent in chunks. Sent in one piece, a socket's timeout
+would cover the whole upload, and a big GeoTIFF on a slow link would
+fail midway however steadily it was going.
+"""
+
+from collections.abc import Iterator
+from typing import Any
+
+import httpx
+
+from .config import config_manager
+
+# Seconds to wait for a connection to the server, whatever the request
+CONNECT_TIMEOUT = 10.0
+
+# Bytes of an upload sent at a time; the idle timeout starts again with each
+UPLOAD_CHUNK_SIZE = 1024 * 1024
+
+# Kinds of request with their own timeout in the config
+KINDS = ("upload", "download", "ows")
+
+
+def request_timeout(kind: str) -> httpx.Timeout:
+    """Get the timeout for a kind of request, as set in the config.
+
+    Args:
+        kind: "upload", "download" or "ows"
+
+    Returns:
+        Timeout for connecting, and for each read and write; none when 0
+    """
+    seconds = getattr(config_manager.config.timeouts, kind)
+    return httpx.Timeout(seconds or None, connect=CONNECT_TIMEOUT)
+
+
+class ChunkedBody:
+    """An upload body read in chunks, from the start each time it's sent.
+
+    Auth that sends a refused request again, such as a refreshed OIDC
+    token after a 401, reads the body twice; a generator would be used up.
+    """
+
+    def __init__(self, data: bytes):
+        """Initialize the body.
+
+        Args:
+            data: Bytes to send
+        """
+        self.data = data
+
+    def __iter__(self) -> Iterator[bytes]:
+        for start in range(0, len(self.data), UPLOAD_CHUNK_SIZE):
+            yield self.data[start : start + UPLOAD_CHUNK_SIZE]
+
+
+def upload_body(data: bytes, headers: dict[str, str]) -> dict[str, Any]:
+    """Request arguments sending data in chunks, with its length known.
+
+    Args:
+        data: Body of the upload
+        headers: Other request headers, such as the content type
+
+    Returns:
+        content, headers and timeout arguments for the request
+    """
+    return {
+        # A length instead of chunked encoding, which some proxies refuse
+        "headers": {**headers, "Content-Length": str(len(data))},
+        "content": ChunkedBody(data),
+        "timeout": request_timeout("upload"),
+    }
@@ -7,4 +7,9 @@ from . import views
 urlpatterns = [
     path("settings/", views.SettingsView.as_view(), name="settings"),
     path("providers/", views.ProvidersView.as_view(), name="providers"),
+    path("hooks/", views.HooksView.as_view(), name="hooks"),
+    path("hooks/<str:name>/test/", views.HookTestView.as_view(), name="hook-test"),
+    path("daemon/", views.DaemonInfoView.as_view(), name="daemon-info"),
+    path("operations/", views.OperationListView.as_view(), name="operation-list"),
+    path("operations/<str:op_id>/", views.OperationDetailView.as_view(), name="operation-detail"),
 ]
@@ -1,11 +1,19 @@
 """Views for core app - settings and providers endpoints."""
 
+from django.conf import settings
 from rest_framework import status
 from rest_framework.response import Response
 from rest_framework.views import APIView
 
-from .config import config_manager
+from cloudbench import __version__
+
+from . import hooks
+from .headless import API_VERSION
+from .config import EventHook, config_manager
+from .oplog import operation_log
+from .permissions import IsAdminOrDaemonToken
 from .providers import get_providers_manager
+from .timeouts import KINDS as TIMEOUT_KINDS
 
 
 class ProvidersView(APIView):
@@ -78,14 +86,7 @@ class SettingsView(APIView):
 
         Returns theme, ping interval, and other app-wide settings.
         """
-        config = config_manager.config
-        return Response(
-            {
-                "theme": config.theme,
-                "pingIntervalSecs": config.ping_interval_secs,
-                "lastLocalPath": config.last_local_path,
-            }
-        )
+        return Response(self._settings())
 
     def put(self, request):
         """Update application settings.
@@ -94,7 +95,9 @@ class SettingsView(APIView):
         {
             "theme": "default",
             "pingIntervalSecs": 60,
-            "lastLocalPath": "/path/to/dir"
+            "lastLocalPath": "/path/to/dir",
+            "cogAutoConvertMb": 500,
+            "timeouts": {"upload": 600, "download": 120, "ows": 60}
         }
         """
         data = request.data
@@ -111,12 +114,146 @@ class SettingsView(APIView):
         if "lastLocalPath" in data:
             config_manager.config.last_local_path = data["lastLocalPath"]
 
+        if "cogAutoConvertMb" in data:
+            config_manager.config.cog_auto_convert_mb = max(0, int(data["cogAutoConvertMb"] or 0))
+
+        timeouts = data.get("timeouts") or {}
+        for kind in TIMEOUT_KINDS:
+            if kind in timeouts:
+                # 0 waits as long as it takes
+                seconds = max(0.0, float(timeouts[kind] or 0))
+                setattr(config_manager.config.timeouts, kind, seconds)
+
+        config_manager.save()
+
+        return Response(self._settings())
+
+    def _settings(self) -> dict:
+        config = config_manager.config
+        return {
+            "theme": config.theme,
+            "pingIntervalSecs": config.ping_interval_secs,
+            "lastLocalPath": config.last_local_path,
+            "cogAutoConvertMb": config.cog_auto_convert_mb,
+            "timeouts": config.timeouts.model_dump(),
+        }
+
+
+class HooksView(APIView):
+    """API endpoint for the hooks run when resources change."""
+
+    permission_classes = [IsAdminOrDaemonToken]
+
+    def get(self, request):
+        """List the configured hooks, the events they can listen to and recent deliveries."""
+        return Response(self._listing())
+
+    def put(self, request):
+        """Replace the configured hooks.
+
+        Expected body:
+        {
+            "hooks": [
+                {
+                    "name": "purge-cdn",
+                    "events": ["layer.updated", "style.*"],
+                    "url": "https://cdn.example.com/purge",
+                    "body": "{\"layer\": \"${workspace}:${name}\"}"
+                }
+            ]
+        }
+
+        Command hooks must keep the command they have in the config file,
+        and masked header values keep their current value.
+        """
+        try:
+            event_hooks = [EventHook(**hook) for hook in request.data.get("hooks", [])]
+            event_hooks = hooks.merge_hook_update(event_hooks, config_manager.config.event_hooks)
+        except (TypeError, ValueError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        config_manager.config.event_hooks = event_hooks
         config_manager.save()
+        return Response(self._listing())
+
+    def _listing(self) -> dict:
+        return {
+            "hooks": [hooks.masked_hook(hook) for hook in config_manager.config.event_hooks],
+            "events": [{"name": name, "description": text} for name, text in hooks.EVENTS.items()],
+            "deliveries": [result.to_dict() for result in hooks.hook_runner.recent()],
+        }
+
+
+class HookTestView(APIView):
+    """API endpoint to try a hook out."""
+
+    permission_classes = [IsAdminOrDaemonToken]
+
+    def post(self, request, name):
+        """Run a hook now with a sample event and return the outcome.
+
+        Expected body (optional):
+        {
+            "event": "layer.published",
+            "workspace": "topp",
+            "name": "roads"
+        }
+        """
+        hook = next((h for h in config_manager.config.event_hooks if h.name == name), None)
+        if hook is None:
+            return Response({"error": f"No hook named {name}"}, status=status.HTTP_404_NOT_FOUND)
+        event = hooks.HookEvent(
+            name=request.data.get("event") or "layer.published",
+            connection="test",
+            workspace=request.data.get("workspace") or "workspace",
+            resource=request.data.get("name") or "layer",
+            details={"test": True},
+        )
+        return Response(hooks.hook_runner.test(hook, event).to_dict())
+
 
+class DaemonInfoView(APIView):
+    """API endpoint describing the server, for clients of the headless daemon."""
+
+    def get(self, request):
+        """Get the version of CloudBench and of its API, and whether it runs headless."""
         return Response(
             {
-                "theme": config_manager.config.theme,
-                "pingIntervalSecs": config_manager.config.ping_interval_secs,
-                "lastLocalPath": config_manager.config.last_local_path,
+                "version": __version__,
+                "apiVersion": API_VERSION,
+                "headless": getattr(settings, "CLOUDBENCH_HEADLESS", False),
             }
         )
+
+
+class OperationListView(APIView):
+    """API endpoint for batch operations that can be resumed."""
+
+    def get(self, request):
+        """List interrupted operations, oldest first.
+
+        Query parameters:
+        - connection: Only operations on this connection
+        - kind: Only operations of this kind, e.g. truncate, upload or sync
+        """
+        operations = operation_log.interrupted(
+            request.query_params.get("connection") or None,
+            request.query_params.get("kind") or None,
+        )
+        return Response({"operations": [op.to_dict() for op in operations]})
+
+
+class OperationDetailView(APIView):
+    """API endpoint for one interrupted operation."""
+
+    def get(self, request, op_id):
+        """Get the steps an interrupted operation did and didn't finish."""
+        operation = operation_log.get(op_id)
+        if operation is None:
+            return Response({"error": "Operation not found"}, status=status.HTTP_404_NOT_FOUND)
+        return Response(operation.to_dict())
+
+    def delete(self, request, op_id):
+        """Forget an interrupted operation instead of resuming it."""
+        if not operation_log.discard(op_id):
+            return Response({"error": "Operation not found"}, status=status.HTTP_404_NOT_FOUND)
+        return Response(status=status.HTTP_204_NO_CONTENT)
@@ -0,0 +1,79 @@
+"""Storage figures for a connection, shown on the dashboards.
+
+Collects the tile cache disk quota, the seed tasks in progress and the
+data directory size of one connection. Each figure is fetched on its
+own, so a server without the disk quota module or the resource API
+still reports the others; a figure that can't be read carries an error
+instead.
+"""
+
+from dataclasses import dataclass
+from typing import Any
+
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.datadir import DEFAULT_REQUEST_BUDGET, DirectorySize, directory_size
+from apps.gwc.client import get_gwc_client
+from apps.gwc.usage import DiskQuotaUsage, SeedTaskCounts, count_seed_tasks, get_disk_quota_usage
+
+
+@dataclass
+class ConnectionStorage:
+    """Storage figures of one connection."""
+
+    connection_id: str
+    disk_quota: DiskQuotaUsage | None = None
+    disk_quota_error: str | None = None
+    seed_tasks: SeedTaskCounts | None = None
+    seed_tasks_error: str | None = None
+    data_directory: DirectorySize | None = None
+    data_directory_error: str | None = None
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "connectionId": self.connection_id,
+            "diskQuota": self.disk_quota.to_dict() if self.disk_quota else None,
+            "diskQuotaError": self.disk_quota_error,
+            "seedTasks": self.seed_tasks.to_dict() if self.seed_tasks else None,
+            "seedTasksError": self.seed_tasks_error,
+            "dataDirectory": self.data_directory.to_dict() if self.data_directory else None,
+            "dataDirectoryError": self.data_directory_error,
+        }
+
+
+def connection_storage(
+    conn_id: str,
+    include_data_directory: bool = True,
+    budget: int = DEFAULT_REQUEST_BUDGET,
+) -> ConnectionStorage:
+    """Collect the storage figures of a connection.
+
+    Args:
+        conn_id: Connection ID
+        include_data_directory: Walk the data directory; the slowest figure
+        budget: Request budget for the data directory walk
+
+    Raises:
+        ValueError: If the connection doesn't exist
+    """
+    client = get_geoserver_client(conn_id)
+    gwc_client = get_gwc_client(conn_id)
+    storage = ConnectionStorage(conn_id)
+
+    try:
+        storage.disk_quota = get_disk_quota_usage(gwc_client)
+    except Exception as e:
+        storage.disk_quota_error = str(e)
+
+    try:
+        storage.seed_tasks = count_seed_tasks(gwc_client)
+    except Exception as e:
+        storage.seed_tasks_error = str(e)
+
+    if include_data_directory:
+        try:
+            storage.data_directory = directory_size(client, budget=budget)
+        except Exception as e:
+            storage.data_directory_error = str(e)
+
+    return storage
@@ -25,4 +25,19 @@ urlpatterns = [
         views.DashboardGeoServerView.as_view(),
         name="dashboard-geoserver",
     ),
+    path(
+        "dashboard/storage/<str:conn_id>",
+        views.DashboardStorageView.as_view(),
+        name="dashboard-storage",
+    ),
+    path(
+        "dashboard/usage/<str:conn_id>",
+        views.DashboardWorkspaceUsageView.as_view(),
+        name="dashboard-workspace-usage",
+    ),
+    path(
+        "dashboard/compare",
+        views.DashboardCompareView.as_view(),
+        name="dashboard-compare",
+    ),
 ]
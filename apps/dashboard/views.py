@@ -4,18 +4,31 @@ Provides endpoints for:
 - Overall system status
 - Connection health monitoring
 - Server statistics
+- Tile cache and data directory storage
+- Storage used by each workspace
+- Configuration comparison across servers
 """
 
 import platform
 import sys
 from datetime import datetime
 
+from django.http import HttpResponse
 from rest_framework import status
 from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.config import get_config
+from apps.core.connection_groups import badge_color
+from apps.core.exceptions import GeoServerError
+from apps.core.output import format_csv
 from apps.geoserver.client import GeoServerClientManager
+from apps.geoserver.cluster import node_health
+from apps.geoserver.comparison import compare_servers
+
+from .storage import connection_storage
+from .workspace_usage import COLUMNS as USAGE_COLUMNS
+from .workspace_usage import connection_workspace_usage
 
 
 class DashboardView(APIView):
@@ -40,6 +53,10 @@ class DashboardView(APIView):
                 "connectionId": conn.id,
                 "connectionName": conn.name,
                 "url": conn.url,
+                "group": conn.group,
+                "environment": conn.environment,
+                "badge": conn.badge,
+                "badgeColor": badge_color(conn),
                 "online": False,
                 "responseTimeMs": 0,
                 "memoryUsed": 0,
@@ -93,6 +110,9 @@ class DashboardView(APIView):
                     "coverageCount": coverage_count,
                     "styleCount": style_count,
                 })
+                if conn.is_cluster:
+                    # Catalog counts come from the primary; health is per node
+                    server_status["nodes"] = [health.to_dict() for health in node_health(client)]
 
                 online_count += 1
                 total_layers += layer_count
@@ -113,6 +133,7 @@ class DashboardView(APIView):
             "totalStores": total_stores,
             "alertServers": alert_servers,
             "pingIntervalSecs": 30,  # Default refresh interval
+            "profile": config.profile,
         })
 
 
@@ -230,3 +251,88 @@ class DashboardGeoServerView(APIView):
                 {"error": str(e)},
                 status=status.HTTP_502_BAD_GATEWAY,
             )
+
+
+class DashboardStorageView(APIView):
+    """Get tile cache and data directory storage figures for a connection."""
+
+    def get(self, request, conn_id):
+        """Get disk quota usage, seed task counts and data directory size.
+
+        The data directory walk is the slowest part; pass
+        dataDirectory=false to skip it.
+        """
+        include_data_directory = request.query_params.get("dataDirectory") != "false"
+        try:
+            storage = connection_storage(conn_id, include_data_directory)
+        except ValueError as e:
+            return Response(
+                {"error": str(e)},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+        return Response({
+            **storage.to_dict(),
+            "timestamp": datetime.utcnow().isoformat(),
+        })
+
+
+class DashboardWorkspaceUsageView(APIView):
+    """Get the storage each workspace of a connection uses."""
+
+    def get(self, request, conn_id):
+        """Add up each workspace's tile cache and store sizes, largest first.
+
+        Sizing stores walks the data directory, so this can take a while on
+        large servers. Pass format=csv to download the table as CSV.
+        """
+        try:
+            report = connection_workspace_usage(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return Response(
+                {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
+            )
+
+        if request.query_params.get("format") == "csv":
+            response = HttpResponse(
+                format_csv(report.records(), USAGE_COLUMNS) + "\n", content_type="text/csv"
+            )
+            response["Content-Disposition"] = f'attachment; filename="{conn_id}_usage.csv"'
+            return response
+        return Response({
+            **report.to_dict(),
+            "timestamp": datetime.utcnow().isoformat(),
+        })
+
+
+class DashboardCompareView(APIView):
+    """Compare versions, extensions and settings across connections."""
+
+    def get(self, request):
+        """Get the comparison matrix.
+
+        Compares every connection unless a comma-separated list of
+        connection IDs is given in connections.
+        """
+        ids = request.query_params.get("connections")
+        if ids:
+            conn_ids = [conn_id for conn_id in ids.split(",") if conn_id]
+        else:
+            conn_ids = [conn.id for conn in get_config().list_connections()]
+        if len(conn_ids) < 2:
+            return Response(
+                {"error": "Select at least two connections to compare"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        try:
+            comparison = compare_servers(conn_ids)
+        except ValueError as e:
+            return Response(
+                {"error": str(e)},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+        return Response({
+            **comparison.to_dict(),
+            "timestamp": datetime.utcnow().isoformat(),
+        })
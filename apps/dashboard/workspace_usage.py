@@ -0,0 +1,206 @@
+"""Storage used by each workspace of a shared GeoServer.
+
+GeoServer has no per-workspace storage figure, so one is pieced together
+for chargeback and capacity planning:
+
+- the tile cache of each layer and layer group, from the usage the GWC
+  disk quota records per layer, added to the layer's workspace;
+- the files of each coverage store and file-based data store (shapefiles,
+  GeoPackages) kept in the data directory, sized through the resource
+  API. Folders, such as image mosaics and unpacked shapefiles, are walked
+  within a request budget.
+
+Stores whose data lives elsewhere, such as PostGIS databases or files
+outside the data directory, are counted but not sized.
+"""
+
+from dataclasses import dataclass, field
+from typing import Any
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import GeoServerClient, get_geoserver_client
+from apps.geoserver.datadir import directory_size
+from apps.gwc.client import get_gwc_client
+from apps.gwc.usage import DiskQuotaUsage, get_disk_quota_usage
+from apps.upload.verify import store_location
+
+# Workspace that tile caches of global layer groups are added to
+GLOBAL_WORKSPACE = ""
+
+# Requests a store's folder may take to be sized
+STORE_FOLDER_BUDGET = 100
+
+COLUMNS = [
+    "workspace",
+    "tileCacheBytes",
+    "storeBytes",
+    "totalBytes",
+    "cachedLayers",
+    "sizedStores",
+    "externalStores",
+    "complete",
+]
+
+
+@dataclass
+class WorkspaceUsage:
+    """Storage used by one workspace."""
+
+    workspace: str
+    tile_cache_bytes: int = 0
+    store_bytes: int = 0
+    cached_layers: int = 0  # Layers and groups the disk quota reports usage for
+    sized_stores: int = 0
+    external_stores: int = 0  # Stores whose data isn't in the data directory
+    complete: bool = True  # False when a store couldn't be sized in full
+
+    @property
+    def total_bytes(self) -> int:
+        return self.tile_cache_bytes + self.store_bytes
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "workspace": self.workspace,
+            "tileCacheBytes": self.tile_cache_bytes,
+            "storeBytes": self.store_bytes,
+            "totalBytes": self.total_bytes,
+            "cachedLayers": self.cached_layers,
+            "sizedStores": self.sized_stores,
+            "externalStores": self.external_stores,
+            "complete": self.complete,
+        }
+
+
+@dataclass
+class WorkspaceUsageReport:
+    """Storage used by every workspace of a connection."""
+
+    connection_id: str
+    workspaces: list[WorkspaceUsage] = field(default_factory=list)
+    tile_cache_error: str | None = None  # Set when the disk quota couldn't be read
+    store_error: str | None = None  # Set when no store could be sized
+    errors: list[str] = field(default_factory=list)  # Stores that couldn't be sized
+
+    @property
+    def total_bytes(self) -> int:
+        return sum(usage.total_bytes for usage in self.workspaces)
+
+    def records(self) -> list[dict[str, Any]]:
+        """One row per workspace, largest first, for tables and CSV."""
+        return [usage.to_dict() for usage in self.workspaces]
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "connectionId": self.connection_id,
+            "workspaces": self.records(),
+            "totalBytes": self.total_bytes,
+            "tileCacheError": self.tile_cache_error,
+            "storeError": self.store_error,
+            "errors": self.errors,
+        }
+
+
+def add_tile_cache_usage(usages: dict[str, WorkspaceUsage], quota: DiskQuotaUsage) -> None:
+    """Add each layer's tile cache usage to its workspace."""
+    for layer in quota.layers:
+        if layer.used_bytes is None:
+            continue
+        workspace, _, _ = layer.layer.rpartition(":")
+        usage = usages.setdefault(workspace, WorkspaceUsage(workspace))
+        usage.tile_cache_bytes += layer.used_bytes
+        usage.cached_layers += 1
+
+
+def store_size(client: GeoServerClient, path: str) -> tuple[int, bool]:
+    """Size a store's file or folder in the data directory.
+
+    Returns:
+        The size in bytes, and whether the whole folder was walked
+    """
+    info = client.get_resource_info(path)
+    if info["type"] == "directory":
+        size = directory_size(client, path, budget=STORE_FOLDER_BUDGET)
+        return size.bytes, size.complete
+    return info["size"], True
+
+
+def add_store_usage(
+    client: GeoServerClient,
+    usage: WorkspaceUsage,
+    report: WorkspaceUsageReport,
+) -> None:
+    """Size the coverage and data stores of a workspace."""
+    stores = [("coveragestore", s) for s in client.list_coveragestores(usage.workspace)]
+    stores += [("datastore", s) for s in client.list_datastores(usage.workspace)]
+    for store_type, store in stores:
+        name = store.get("name", "")
+        try:
+            path = store_location(client, usage.workspace, name, store_type)
+            if path is None:
+                usage.external_stores += 1
+                continue
+            size, complete = store_size(client, path)
+        except GeoServerError as e:
+            usage.complete = False
+            report.errors.append(f"{usage.workspace}:{name}: {e.message}")
+            continue
+        usage.store_bytes += size
+        usage.sized_stores += 1
+        usage.complete = usage.complete and complete
+
+
+def workspace_usage(
+    client: GeoServerClient,
+    quota: DiskQuotaUsage | None,
+    conn_id: str = "",
+    tile_cache_error: str | None = None,
+) -> WorkspaceUsageReport:
+    """Add up the storage of every workspace, largest first.
+
+    Args:
+        client: GeoServer client
+        quota: The GWC disk quota, or None when it couldn't be read
+        conn_id: Connection ID, for the report
+        tile_cache_error: Why the disk quota couldn't be read
+
+    Raises:
+        GeoServerError: If the workspaces can't be listed
+    """
+    report = WorkspaceUsageReport(conn_id, tile_cache_error=tile_cache_error)
+    usages = {
+        ws.get("name", ""): WorkspaceUsage(ws.get("name", "")) for ws in client.list_workspaces()
+    }
+    if quota is not None:
+        add_tile_cache_usage(usages, quota)
+
+    try:
+        # A server without the resource API can't size any store
+        client.get_resource_info("")
+    except GeoServerError as e:
+        report.store_error = e.message
+    else:
+        for workspace, usage in usages.items():
+            if workspace != GLOBAL_WORKSPACE:
+                add_store_usage(client, usage, report)
+
+    report.workspaces = sorted(
+        usages.values(), key=lambda usage: (-usage.total_bytes, usage.workspace)
+    )
+    return report
+
+
+def connection_workspace_usage(conn_id: str) -> WorkspaceUsageReport:
+    """Add up the storage of every workspace of a connection.
+
+    Raises:
+        ValueError: If the connection doesn't exist
+        GeoServerError: If the workspaces can't be listed
+    """
+    client = get_geoserver_client(conn_id)
+    try:
+        quota, error = get_disk_quota_usage(get_gwc_client(conn_id)), None
+    except Exception as e:
+        quota, error = None, str(e)
+    return workspace_usage(client, quota, conn_id, error)
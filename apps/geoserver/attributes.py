@@ -0,0 +1,289 @@
+"""Attribute tables of published vector layers.
+
+Pages through a layer's features with WFS GetFeature as GeoJSON and
+flattens them into rows of attribute values, the same shape as the S3
+attribute table. Sorting and column filters are passed to the server as
+sortBy and CQL_FILTER, so they apply to the whole layer rather than the
+page on screen. Geometries are left out of the rows.
+
+A column filter is either text, matched case-insensitively anywhere in
+the value, or a comparison such as ">= 100", "!= closed" or "= 2024-01-01".
+"""
+
+import csv
+import io
+import json
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from .cql import NUMERIC_TYPES, combine_filters, quote_identifier, quote_literal
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+    from .schema import FeatureTypeSchema
+
+# Rows per page unless the caller asks otherwise
+DEFAULT_LIMIT = 100
+
+# Most rows fetched in one request
+MAX_LIMIT = 1000
+
+# Most rows written to a CSV export
+EXPORT_MAX_ROWS = 100_000
+
+# Comparison operators accepted at the start of a column filter, longest first
+OPERATORS = (">=", "<=", "!=", "<>", "=", ">", "<")
+
+
+@dataclass
+class Attribute:
+    """A non-geometry attribute of a feature type."""
+
+    name: str
+    type: str  # Local type from DescribeFeatureType, e.g. "string" or "int"
+    nillable: bool = True
+
+    @property
+    def numeric(self) -> bool:
+        return self.type.lower() in NUMERIC_TYPES
+
+
+@dataclass
+class AttributePage:
+    """One page of a layer's attribute table."""
+
+    attributes: list[Attribute]
+    rows: list[dict[str, Any]] = field(default_factory=list)
+    total: int = 0
+    limit: int = DEFAULT_LIMIT
+    offset: int = 0
+    geometry_type: str | None = None
+
+    @property
+    def has_more(self) -> bool:
+        return self.offset + len(self.rows) < self.total
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "fields": [a.name for a in self.attributes],
+            "types": {a.name: a.type for a in self.attributes},
+            "nillable": {a.name: a.nillable for a in self.attributes},
+            "geometryType": self.geometry_type,
+            "rows": self.rows,
+            "total": self.total,
+            "limit": self.limit,
+            "offset": self.offset,
+            "hasMore": self.has_more,
+        }
+
+
+def read_attributes(schema: "FeatureTypeSchema") -> list[Attribute]:
+    """Read the non-geometry attributes of a feature type schema."""
+    return [
+        Attribute(name=a.name, type=a.local_type or "string", nillable=a.nillable)
+        for a in schema.fields
+    ]
+
+
+def _literal(value: str, attribute: Attribute) -> str:
+    if attribute.numeric:
+        try:
+            float(value)
+            return value
+        except ValueError:
+            pass
+    return quote_literal(value)
+
+
+def column_filter(attribute: Attribute, value: str) -> str | None:
+    """Build the CQL for one column filter, or None if the filter is empty."""
+    value = value.strip()
+    if not value:
+        return None
+    column = quote_identifier(attribute.name)
+    for operator in OPERATORS:
+        if value.startswith(operator):
+            operand = value[len(operator) :].strip()
+            if operand:
+                return f"{column} {operator} {_literal(operand, attribute)}"
+            break
+    return f"{column} ILIKE " + _literal(f"%{value}%", Attribute(attribute.name, "string"))
+
+
+def attribute_filter(attributes: list[Attribute], filters: dict[str, str]) -> str | None:
+    """Combine column filters into one CQL filter.
+
+    Raises:
+        ValueError: If a filter names a column the layer doesn't have
+    """
+    by_name = {a.name: a for a in attributes}
+    clauses = []
+    for name, value in filters.items():
+        if name not in by_name:
+            raise ValueError(f"Unknown column: {name}")
+        clause = column_filter(by_name[name], value)
+        if clause:
+            clauses.append(clause)
+    return " AND ".join(clauses) or None
+
+
+def parse_column_filters(text: str) -> dict[str, str]:
+    """Read column filters typed as "column: value", separated by semicolons.
+
+    For example "name: park; population: >= 1000".
+
+    Raises:
+        ValueError: If a filter has no column
+    """
+    filters = {}
+    for part in text.split(";"):
+        if not part.strip():
+            continue
+        column, sep, value = part.partition(":")
+        if not sep or not column.strip():
+            raise ValueError(f"Write filters as column: value, not '{part.strip()}'")
+        filters[column.strip()] = value.strip()
+    return filters
+
+
+def _rows(features: list[dict[str, Any]], attributes: list[Attribute]) -> list[dict[str, Any]]:
+    return [
+        {a.name: (feature.get("properties") or {}).get(a.name) for a in attributes}
+        for feature in features
+    ]
+
+
+def _csv_cell(value: Any) -> str:
+    if value is None:
+        return ""
+    if isinstance(value, (dict, list)):
+        return json.dumps(value)
+    return str(value)
+
+
+def _read_page(
+    client: "GeoServerClient",
+    workspace: str,
+    layer: str,
+    attributes: list[Attribute],
+    limit: int,
+    offset: int,
+    sort: str | None,
+    descending: bool,
+    filters: dict[str, str],
+    cql: str | None,
+) -> AttributePage:
+    if sort and sort not in {a.name for a in attributes}:
+        raise ValueError(f"Unknown column: {sort}")
+    limit = max(1, min(limit, MAX_LIMIT))
+
+    data = client.get_features(
+        workspace,
+        layer,
+        count=limit,
+        start_index=offset,
+        sort_by=f"{sort} {'DESC' if descending else 'ASC'}" if sort else None,
+        cql_filter=combine_filters(cql, attribute_filter(attributes, filters)),
+        property_names=[a.name for a in attributes],
+    )
+    rows = _rows(data.get("features") or [], attributes)
+    page = AttributePage(attributes, rows, limit=limit, offset=offset)
+    for key in ("numberMatched", "totalFeatures"):
+        if isinstance(data.get(key), int):
+            page.total = data[key]
+            break
+    else:
+        # The server didn't count the matches; assume more while pages come back full
+        page.total = offset + len(rows) + (1 if len(rows) == limit else 0)
+    return page
+
+
+def read_attribute_table(
+    client: "GeoServerClient",
+    workspace: str,
+    layer: str,
+    limit: int = DEFAULT_LIMIT,
+    offset: int = 0,
+    sort: str | None = None,
+    descending: bool = False,
+    filters: dict[str, str] | None = None,
+    cql: str | None = None,
+) -> AttributePage:
+    """Fetch one page of a layer's attribute table.
+
+    Args:
+        client: GeoServer client
+        workspace: Layer workspace
+        layer: Layer name
+        limit: Rows per page, at most MAX_LIMIT
+        offset: Index of the first row
+        sort: Optional column to sort by
+        descending: Sort in descending order
+        filters: Column filters by column name
+        cql: Optional CQL filter applied along with the column filters
+
+    Raises:
+        ValueError: If the sort column or a filter column is unknown
+        GeoServerError: If a WFS request fails
+    """
+    schema = client.describe_feature_type(workspace, layer)
+    page = _read_page(
+        client,
+        workspace,
+        layer,
+        read_attributes(schema),
+        limit,
+        offset,
+        sort,
+        descending,
+        filters or {},
+        cql,
+    )
+    page.geometry_type = schema.geometry_type
+    return page
+
+
+def export_attribute_csv(
+    client: "GeoServerClient",
+    workspace: str,
+    layer: str,
+    sort: str | None = None,
+    descending: bool = False,
+    filters: dict[str, str] | None = None,
+    cql: str | None = None,
+    max_rows: int = EXPORT_MAX_ROWS,
+) -> str:
+    """Export a layer's filtered and sorted attribute table as CSV.
+
+    Fetches MAX_LIMIT rows at a time and stops after max_rows.
+
+    Raises:
+        ValueError: If the sort column or a filter column is unknown
+        GeoServerError: If a WFS request fails
+    """
+    attributes = read_attributes(client.describe_feature_type(workspace, layer))
+    rows: list[dict[str, Any]] = []
+    while len(rows) < max_rows:
+        page = _read_page(
+            client,
+            workspace,
+            layer,
+            attributes,
+            min(MAX_LIMIT, max_rows - len(rows)),
+            len(rows),
+            sort,
+            descending,
+            filters or {},
+            cql,
+        )
+        rows += page.rows
+        if not page.rows or not page.has_more:
+            break
+
+    buffer = io.StringIO()
+    writer = csv.writer(buffer, lineterminator="\n")
+    writer.writerow([a.name for a in attributes])
+    for row in rows:
+        writer.writerow([_csv_cell(row[a.name]) for a in attributes])
+    return buffer.getvalue()
@@ -0,0 +1,273 @@
+"""Client for the Backup and Restore community module (/rest/br).
+
+The plugin archives a server's catalog, settings and GeoWebCache
+configuration into a zip on the server, or restores one, as a server-side
+job. Unlike the client-side layer export it keeps stores, styles and
+their data directory files together, so an archive can be restored on
+another server. CQL filters narrow a job to some workspaces, stores or
+layers, e.g. wsFilter "name IN ('topp')".
+
+Jobs run asynchronously. The flow is start -> poll the execution until it
+stops -> download the archive (backups only).
+"""
+
+import time
+from collections.abc import Callable, Iterator
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+KIND_BACKUP = "backup"
+KIND_RESTORE = "restore"
+
+# Option -> description, per job kind
+BACKUP_OPTIONS = {
+    "BK_BEST_EFFORT": "Skip resources that fail instead of stopping",
+    "BK_PARAM_PASSWORDS": "Replace store passwords with placeholders",
+    "BK_SKIP_SETTINGS": "Leave out global and service settings",
+    "BK_SKIP_GWC": "Leave out the tile cache configuration",
+}
+RESTORE_OPTIONS = {
+    "BK_DRY_RUN": "Check the archive without changing the catalog",
+    "BK_BEST_EFFORT": "Skip resources that fail instead of stopping",
+    "BK_PURGE_RESOURCES": "Delete catalog resources missing from the archive",
+    "BK_SKIP_SETTINGS": "Keep the current global and service settings",
+    "BK_SKIP_GWC": "Keep the current tile cache configuration",
+}
+OPTIONS = {KIND_BACKUP: BACKUP_OPTIONS, KIND_RESTORE: RESTORE_OPTIONS}
+
+# Spring Batch statuses after which polling stops
+FINISHED_STATUSES = ("COMPLETED", "FAILED", "STOPPED", "ABANDONED")
+
+
+def workspace_filter(workspaces: list[str]) -> str:
+    """Build a CQL filter matching workspaces by name."""
+    quoted = ", ".join("'" + ws.replace("'", "''") + "'" for ws in workspaces)
+    return f"name IN ({quoted})"
+
+
+def default_archive_file(workspaces: list[str] | None = None) -> str:
+    """Suggest a server-side archive path, e.g. /tmp/backup-topp-20240101-120000.zip."""
+    label = "-".join(workspaces or []) or "catalog"
+    return f"/tmp/backup-{label}-{time.strftime('%Y%m%d-%H%M%S')}.zip"
+
+
+@dataclass
+class Execution:
+    """State of a backup or restore job."""
+
+    id: int
+    kind: str
+    status: str
+    progress: str = ""  # Steps done, e.g. "3/9"
+    archive_file: str = ""
+    errors: list[str] = field(default_factory=list)
+
+    @property
+    def finished(self) -> bool:
+        """Whether the job has stopped, successfully or not."""
+        return self.status in FINISHED_STATUSES
+
+    @property
+    def succeeded(self) -> bool:
+        """Whether the job completed."""
+        return self.status == "COMPLETED"
+
+    @classmethod
+    def from_json(cls, kind: str, data: dict[str, Any]) -> "Execution":
+        """Build from a backup or restore document."""
+        execution = data.get("execution", {}) or {}
+        exit_status = execution.get("exitStatus", {}) or {}
+        errors = [exit_status["exitDescription"]] if exit_status.get("exitDescription") else []
+        return cls(
+            id=int(execution.get("id", 0)),
+            kind=kind,
+            status=execution.get("status", ""),
+            progress=str(execution.get("progress", "") or ""),
+            archive_file=data.get("archiveFile", "") or "",
+            errors=errors,
+        )
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "id": self.id,
+            "kind": self.kind,
+            "status": self.status,
+            "progress": self.progress,
+            "archiveFile": self.archive_file,
+            "errors": self.errors,
+            "finished": self.finished,
+            "succeeded": self.succeeded,
+        }
+
+
+class BackupRestoreClient:
+    """Client for the Backup and Restore plugin, sharing a GeoServer client's connection."""
+
+    def __init__(
+        self,
+        client: "GeoServerClient",
+        clock: Callable[[], float] = time.monotonic,
+        sleep: Callable[[float], None] = time.sleep,
+    ):
+        """Initialize backup and restore client.
+
+        Args:
+            client: GeoServer client whose connection and limiter are used
+            clock: Monotonic clock, for polling timeouts
+            sleep: Sleep function, for polling intervals
+        """
+        self._client = client
+        self._clock = clock
+        self._sleep = sleep
+        self._available: bool | None = None
+
+    def is_available(self) -> bool:
+        """Check whether the plugin is installed (cached)."""
+        if not self._client.capabilities.supports("backup_restore"):
+            return False  # Not in the server's manifests
+        if self._available is None:
+            try:
+                response = self._client._request("GET", "/rest/br/backup.json")
+                self._available = response.status_code == 200
+            except GeoServerError:
+                return False
+        return self._available
+
+    def _json(self, response: Any, action: str) -> dict[str, Any]:
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to {action}: {response.text}", status_code=response.status_code
+            )
+        return response.json() if response.content else {}
+
+    def start(
+        self,
+        kind: str,
+        archive_file: str,
+        ws_filter: str = "",
+        si_filter: str = "",
+        li_filter: str = "",
+        options: dict[str, bool] | None = None,
+    ) -> Execution:
+        """Start a backup or restore job.
+
+        Args:
+            kind: "backup" or "restore"
+            archive_file: Path of the zip archive on the server
+            ws_filter: CQL filter on workspaces
+            si_filter: CQL filter on stores
+            li_filter: CQL filter on layers
+            options: Plugin options (BK_*) to set
+
+        Returns:
+            The started execution
+
+        Raises:
+            ValueError: If the kind or an option is unknown
+        """
+        if kind not in OPTIONS:
+            raise ValueError(f"Unknown job kind: {kind}")
+        unknown = [name for name in options or {} if name not in OPTIONS[kind]]
+        if unknown:
+            raise ValueError(f"Unknown {kind} option(s): {', '.join(unknown)}")
+
+        job: dict[str, Any] = {"archiveFile": archive_file}
+        if kind == KIND_BACKUP:
+            job["overwrite"] = True
+        if options:
+            job["options"] = {
+                "option": [f"{name}={str(value).lower()}" for name, value in options.items()]
+            }
+        filters = {"wsFilter": ws_filter, "siFilter": si_filter, "liFilter": li_filter}
+        job.update({key: value for key, value in filters.items() if value})
+
+        response = self._client._request("POST", f"/rest/br/{kind}/", json={kind: job})
+        return Execution.from_json(kind, self._json(response, f"start {kind}").get(kind, {}))
+
+    def get_execution(self, kind: str, execution_id: int) -> Execution:
+        """Get a job's current state."""
+        response = self._client._request("GET", f"/rest/br/{kind}/{execution_id}.json")
+        return Execution.from_json(kind, self._json(response, f"get {kind}").get(kind, {}))
+
+    def cancel(self, kind: str, execution_id: int) -> None:
+        """Stop a running job."""
+        response = self._client._request("DELETE", f"/rest/br/{kind}/{execution_id}")
+        self._json(response, f"cancel {kind}")
+
+    def iter_archive(self, execution_id: int) -> Iterator[bytes]:
+        """Stream the zip archive a finished backup wrote, in pieces.
+
+        Archives of a whole catalog can be large, so they're never held in
+        memory or refused by the response size limit.
+        """
+        yield from self._client._download(f"/rest/br/backup/{execution_id}.zip", "backup")
+
+    def wait(
+        self,
+        kind: str,
+        execution_id: int,
+        timeout: float = 1800.0,
+        interval: float = 2.0,
+        on_progress: Callable[[Execution], None] | None = None,
+    ) -> Execution:
+        """Poll a job until it stops.
+
+        Args:
+            kind: "backup" or "restore"
+            execution_id: Execution ID
+            timeout: Seconds to wait before giving up
+            interval: Seconds between polls
+            on_progress: Called with the execution after each poll
+
+        Returns:
+            The stopped execution; check succeeded for the outcome
+
+        Raises:
+            GeoServerError: If the job doesn't stop in time
+        """
+        deadline = self._clock() + timeout
+        while True:
+            execution = self.get_execution(kind, execution_id)
+            if on_progress:
+                on_progress(execution)
+            if execution.finished:
+                return execution
+            if self._clock() >= deadline:
+                raise GeoServerError(
+                    f"{kind.title()} {execution_id} did not finish in time", status_code=504
+                )
+            self._sleep(interval)
+
+    def backup_workspaces(
+        self,
+        workspaces: list[str],
+        archive_file: str | None = None,
+        options: dict[str, bool] | None = None,
+        timeout: float = 1800.0,
+        on_progress: Callable[[Execution], None] | None = None,
+    ) -> Execution:
+        """Back up some workspaces and wait for the archive.
+
+        Args:
+            workspaces: Workspaces to include
+            archive_file: Path of the archive on the server; suggested when omitted
+            options: Plugin options (BK_*) to set
+            timeout: Seconds to wait for the backup to finish
+            on_progress: Called with the execution while polling
+
+        Returns:
+            The stopped execution
+        """
+        execution = self.start(
+            KIND_BACKUP,
+            archive_file or default_archive_file(workspaces),
+            ws_filter=workspace_filter(workspaces),
+            options=options,
+        )
+        return self.wait(KIND_BACKUP, execution.id, timeout, on_progress=on_progress)
@@ -0,0 +1,193 @@
+"""Batch actions on several layers at once.
+
+The tree views let the user mark layers and then act on all of them in
+one go: delete them, truncate their tile caches, download their
+configuration or gather them into a new layer group. Each layer is
+handled independently, so one failure doesn't stop the rest; the caller
+gets a result per layer to show in a single summary.
+"""
+
+import logging
+from collections.abc import Callable
+from dataclasses import asdict, dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from apps.gwc.client import GWCClient
+
+    from .client import GeoServerClient
+
+logger = logging.getLogger(__name__)
+
+# Action name -> description
+ACTIONS = {
+    "delete": "Delete layers",
+    "truncate": "Truncate tile caches",
+    "export": "Download layer configurations",
+    "group": "Add layers to a new layer group",
+}
+
+
+@dataclass
+class BatchResult:
+    """Outcome of a batch action on one target."""
+
+    target: str  # e.g. "topp:roads"
+    ok: bool
+    message: str = ""
+    data: dict[str, Any] = field(default_factory=dict)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return asdict(self)
+
+
+# Called after each target with (done, total, result)
+ProgressCallback = Callable[[int, int, BatchResult], None]
+
+
+def split_layer_name(layer: str) -> tuple[str, str]:
+    """Split a qualified layer name into workspace and name.
+
+    Raises:
+        ValueError: If the name isn't qualified with a workspace
+    """
+    workspace, sep, name = layer.partition(":")
+    if not sep or not workspace or not name:
+        raise ValueError(f"Layer '{layer}' must be given as workspace:name")
+    return workspace, name
+
+
+class BatchRunner:
+    """Runs an action on several layers of a connection."""
+
+    def __init__(self, client: "GeoServerClient", gwc_client: "GWCClient | None" = None):
+        """Initialize runner.
+
+        Args:
+            client: GeoServer client
+            gwc_client: GWC client; required for the truncate action
+        """
+        self.client = client
+        self.gwc_client = gwc_client
+
+    def run(
+        self,
+        action: str,
+        layers: list[str],
+        options: dict[str, Any] | None = None,
+        progress: ProgressCallback | None = None,
+    ) -> list[BatchResult]:
+        """Run an action.
+
+        Args:
+            action: One of ACTIONS
+            layers: Qualified layer names
+            options: Action options; "recurse" for delete, "name", "title"
+                and "workspace" for group
+            progress: Called after each target
+
+        Returns:
+            One result per layer, or a single result for the group action
+
+        Raises:
+            ValueError: If the action is unknown or its options are invalid
+        """
+        if action not in ACTIONS:
+            raise ValueError(f"Unknown action: {action}")
+        if not layers:
+            raise ValueError("No layers given")
+        options = options or {}
+
+        if action == "group":
+            result = self._group(layers, options)
+            if progress:
+                progress(1, 1, result)
+            return [result]
+
+        handler = {
+            "delete": self._delete,
+            "truncate": self._truncate,
+            "export": self._export,
+        }[action]
+
+        results = []
+        for layer in layers:
+            try:
+                result = handler(layer, options)
+            except GeoServerError as e:
+                result = BatchResult(layer, False, e.message)
+            except ValueError as e:
+                result = BatchResult(layer, False, str(e))
+            except Exception as e:
+                logger.exception("Batch %s failed for %s", action, layer)
+                result = BatchResult(layer, False, str(e))
+            results.append(result)
+            if progress:
+                progress(len(results), len(layers), result)
+        return results
+
+    def _delete(self, layer: str, options: dict[str, Any]) -> BatchResult:
+        workspace, name = split_layer_name(layer)
+        recurse = bool(options.get("recurse", False))
+        self.client.delete_layer(workspace, name, recurse=recurse)
+        return BatchResult(layer, True, "Deleted with its resource" if recurse else "Deleted")
+
+    def _truncate(self, layer: str, options: dict[str, Any]) -> BatchResult:
+        if self.gwc_client is None:
+            return BatchResult(layer, False, "GeoWebCache is not available")
+        try:
+            self.gwc_client.truncate_entire_layer(layer)
+        except GeoServerError as e:
+            # Layers without a tile cache report 400/404; nothing to truncate
+            if e.status_code in (400, 404):
+                return BatchResult(layer, True, "No tile cache")
+            raise
+        return BatchResult(layer, True, "Tile cache truncated")
+
+    def _export(self, layer: str, options: dict[str, Any]) -> BatchResult:
+        workspace, name = split_layer_name(layer)
+        config: dict[str, Any] = {"layer": self.client.get_layer(workspace, name)}
+        href = config["layer"].get("resource", {}).get("href")
+        if href:
+            config["resource"] = self.client.get_href(href)
+        return BatchResult(layer, True, "Configuration exported", config)
+
+    def _group(self, layers: list[str], options: dict[str, Any]) -> BatchResult:
+        name = str(options.get("name") or "").strip()
+        if not name:
+            raise ValueError("A layer group name is required")
+
+        workspaces = {split_layer_name(layer)[0] for layer in layers}
+        # Keep the group next to its layers when they share a workspace
+        workspace = options.get("workspace")
+        if workspace is None and len(workspaces) == 1:
+            workspace = workspaces.pop()
+        target = f"{workspace}:{name}" if workspace else name
+
+        try:
+            self.client.create_layergroup(
+                name, layers, workspace=workspace or None, title=options.get("title")
+            )
+        except GeoServerError as e:
+            return BatchResult(target, False, e.message)
+        return BatchResult(target, True, f"Created with {len(layers)} layer(s)")
+
+
+def run_batch(
+    client: "GeoServerClient",
+    gwc_client: "GWCClient | None",
+    action: str,
+    layers: list[str],
+    options: dict[str, Any] | None = None,
+    progress: ProgressCallback | None = None,
+) -> list[BatchResult]:
+    """Run a batch action on layers of a connection."""
+    return BatchRunner(client, gwc_client).run(action, layers, options, progress)
+
+
+def export_document(results: list[BatchResult]) -> dict[str, Any]:
+    """Collect the configurations of a successful export into one document."""
+    return {result.target: result.data for result in results if result.ok and result.data}
@@ -0,0 +1,212 @@
+"""Layer attribution and WMS watermark settings.
+
+Attribution credits a layer's source in WMS capabilities documents: a
+title, a link and optionally a logo, which map clients show next to the
+map. The watermark is an image GeoServer draws on every GetMap response,
+set once in the WMS service settings. Together they cover the branding
+that data providers commonly require of those publishing their data.
+"""
+
+import posixpath
+from dataclasses import dataclass
+from typing import Any
+from urllib.parse import urlparse
+
+# Where the watermark is drawn on maps
+WATERMARK_POSITIONS = (
+    "TOP_LEFT",
+    "TOP_CENTER",
+    "TOP_RIGHT",
+    "MID_LEFT",
+    "MID_CENTER",
+    "MID_RIGHT",
+    "BOT_LEFT",
+    "BOT_CENTER",
+    "BOT_RIGHT",
+)
+
+# Logo file extension -> MIME type capabilities documents give for it
+LOGO_TYPES = {
+    ".png": "image/png",
+    ".jpg": "image/jpeg",
+    ".jpeg": "image/jpeg",
+    ".gif": "image/gif",
+    ".svg": "image/svg+xml",
+}
+
+
+def _text(value: Any) -> str | None:
+    text = str(value).strip() if value is not None else ""
+    return text or None
+
+
+def _size(value: Any, name: str) -> int | None:
+    """Read an optional logo dimension in pixels.
+
+    Raises:
+        ValueError: If the value isn't a positive whole number
+    """
+    if value in (None, ""):
+        return None
+    try:
+        size = int(value)
+    except (TypeError, ValueError) as e:
+        raise ValueError(f"The logo {name} must be a whole number of pixels") from e
+    if size <= 0:
+        raise ValueError(f"The logo {name} must be positive")
+    return size
+
+
+def _check_url(url: str, what: str) -> None:
+    if urlparse(url).scheme not in ("http", "https"):
+        raise ValueError(f"The {what} must be an http(s) URL")
+
+
+def logo_type(url: str) -> str | None:
+    """Guess a logo's MIME type from its URL, or None if it's unknown."""
+    extension = posixpath.splitext(urlparse(url).path)[1].lower()
+    return LOGO_TYPES.get(extension)
+
+
+@dataclass
+class LayerAttribution:
+    """Attribution of a layer; unset fields are left out of capabilities."""
+
+    title: str | None = None
+    href: str | None = None
+    logo_url: str | None = None
+    logo_type: str | None = None
+    logo_width: int | None = None
+    logo_height: int | None = None
+
+    @classmethod
+    def from_api(cls, data: dict[str, Any] | None) -> "LayerAttribution":
+        """Read the attribution object of a REST layer."""
+        data = data or {}
+        return cls(
+            title=_text(data.get("title")),
+            href=_text(data.get("href")),
+            logo_url=_text(data.get("logoURL")),
+            logo_type=_text(data.get("logoType")),
+            logo_width=data.get("logoWidth") or None,
+            logo_height=data.get("logoHeight") or None,
+        )
+
+    def to_api(self) -> dict[str, Any]:
+        """Write the attribution object of a REST layer update."""
+        return {
+            "title": self.title,
+            "href": self.href,
+            "logoURL": self.logo_url,
+            "logoType": self.logo_type,
+            "logoWidth": self.logo_width,
+            "logoHeight": self.logo_height,
+        }
+
+    @classmethod
+    def from_dict(cls, data: dict[str, Any]) -> "LayerAttribution":
+        """Build a validated attribution from an API request.
+
+        The logo's type is guessed from its URL when not given, since
+        capabilities documents must name it.
+
+        Raises:
+            ValueError: If a URL or logo size is invalid
+        """
+        attribution = cls(
+            title=_text(data.get("attributionTitle")),
+            href=_text(data.get("attributionHref")),
+            logo_url=_text(data.get("attributionLogo")),
+            logo_type=_text(data.get("attributionLogoType")),
+            logo_width=_size(data.get("attributionLogoWidth"), "width"),
+            logo_height=_size(data.get("attributionLogoHeight"), "height"),
+        )
+        if attribution.href:
+            _check_url(attribution.href, "attribution link")
+        if attribution.logo_url:
+            _check_url(attribution.logo_url, "logo")
+            attribution.logo_type = attribution.logo_type or logo_type(attribution.logo_url)
+            if not attribution.logo_type:
+                raise ValueError(
+                    f"Can't tell the logo's image type; use one of {', '.join(LOGO_TYPES)}"
+                )
+        else:
+            attribution.logo_type = attribution.logo_width = attribution.logo_height = None
+        return attribution
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "attributionTitle": self.title,
+            "attributionHref": self.href,
+            "attributionLogo": self.logo_url,
+            "attributionLogoType": self.logo_type,
+            "attributionLogoWidth": self.logo_width,
+            "attributionLogoHeight": self.logo_height,
+        }
+
+
+@dataclass
+class Watermark:
+    """The image drawn on every map the WMS service renders."""
+
+    enabled: bool = False
+    url: str | None = None  # URL, or a path in the data directory
+    position: str = "BOT_RIGHT"
+    transparency: int = 0  # Percent; 0 draws the image as it is
+
+    @classmethod
+    def from_api(cls, data: dict[str, Any] | None) -> "Watermark":
+        """Read the watermark object of the WMS settings."""
+        data = data or {}
+        return cls(
+            enabled=bool(data.get("enabled")),
+            url=_text(data.get("URL") or data.get("url")),
+            position=data.get("position") or "BOT_RIGHT",
+            transparency=int(data.get("transparency") or 0),
+        )
+
+    def to_api(self) -> dict[str, Any]:
+        """Write the watermark object of a WMS settings update."""
+        return {
+            "enabled": self.enabled,
+            "URL": self.url,
+            "position": self.position,
+            "transparency": self.transparency,
+        }
+
+    @classmethod
+    def from_dict(cls, data: dict[str, Any]) -> "Watermark":
+        """Build validated watermark settings from an API request.
+
+        Raises:
+            ValueError: If a setting is invalid
+        """
+        try:
+            transparency = int(data.get("transparency") or 0)
+        except (TypeError, ValueError) as e:
+            raise ValueError("The transparency must be a whole number") from e
+        watermark = cls(
+            enabled=bool(data.get("enabled")),
+            url=_text(data.get("url")),
+            position=str(data.get("position") or "BOT_RIGHT").upper(),
+            transparency=transparency,
+        )
+        if watermark.position not in WATERMARK_POSITIONS:
+            raise ValueError(
+                f"Unknown position: {watermark.position} (use {', '.join(WATERMARK_POSITIONS)})"
+            )
+        if not 0 <= watermark.transparency <= 100:
+            raise ValueError("The transparency must be between 0 and 100")
+        if watermark.enabled and not watermark.url:
+            raise ValueError("An enabled watermark needs an image URL or path")
+        return watermark
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "enabled": self.enabled,
+            "url": self.url,
+            "position": self.position,
+            "transparency": self.transparency,
+        }
@@ -0,0 +1,117 @@
+"""GeoServer version-aware feature gating.
+
+REST behaviour differs between GeoServer releases. The capability matrix
+maps each optional feature to the first release that supports it; the
+client and UIs consult it instead of comparing versions themselves.
+Features that come with a plugin are instead looked up in the server's
+installed modules. Per-connection overrides in the config win over both,
+for builds with backported fixes or plugins the manifests don't reveal.
+"""
+
+import re
+from dataclasses import dataclass, field
+from typing import Any
+
+from .manifests import EXTENSION_FEATURES
+
+# feature -> (first supporting version, description)
+FEATURES: dict[str, tuple[tuple[int, int], str]] = {
+    "resource_api": (
+        (2, 9),
+        "Data directory files at /rest/resource",
+    ),
+    "json_datastore": (
+        (2, 12),
+        "Datastore connection parameters accepted as JSON",
+    ),
+    "gwc_parameter_truncate": (
+        (2, 13),
+        "GeoWebCache truncation by parameter filter",
+    ),
+    "system_status": (
+        (2, 16),
+        "System status metrics at /rest/about/system-status",
+    ),
+}
+
+
+def parse_version(version: str | None) -> tuple[int, int] | None:
+    """Parse a GeoServer version string into (major, minor).
+
+    Args:
+        version: Version such as "2.24.1", "2.25-SNAPSHOT" or "2.x"
+
+    Returns:
+        The major and minor numbers, or None if the version is unknown
+    """
+    match = re.match(r"\s*(\d+)\.(\d+)", version or "")
+    if not match:
+        return None
+    return int(match.group(1)), int(match.group(2))
+
+
+@dataclass
+class ServerCapabilities:
+    """Features supported by a GeoServer instance."""
+
+    version: str | None = None
+    features: dict[str, bool] = field(default_factory=dict)
+
+    def supports(self, feature: str) -> bool:
+        """Check whether a feature is supported.
+
+        Unknown features are assumed supported, so new code paths aren't
+        disabled before they're added to the matrix.
+        """
+        return self.features.get(feature, True)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "version": self.version,
+            "features": [
+                {
+                    "name": name,
+                    "description": description,
+                    "minVersion": ".".join(str(n) for n in minimum),
+                    "supported": self.supports(name),
+                }
+                for name, (minimum, description) in FEATURES.items()
+            ]
+            + [
+                {
+                    "name": name,
+                    "description": description,
+                    "minVersion": None,
+                    "extension": module,
+                    "supported": self.supports(name),
+                }
+                for name, (module, description) in EXTENSION_FEATURES.items()
+            ],
+        }
+
+
+def build_capabilities(
+    version: str | None,
+    overrides: dict[str, bool] | None = None,
+    extensions: dict[str, bool] | None = None,
+) -> ServerCapabilities:
+    """Derive the capability matrix for a GeoServer version.
+
+    Args:
+        version: GeoServer version string; None if it couldn't be read
+        overrides: Per-feature overrides from the connection config
+        extensions: Plugin-provided features found in the server's manifests;
+            None if they couldn't be read, so plugins are assumed installed
+
+    Returns:
+        The server capabilities. If the version is unknown every feature is
+        assumed supported, matching the behaviour of current releases.
+    """
+    parsed = parse_version(version)
+    features = {
+        name: parsed is None or parsed >= minimum for name, (minimum, _) in FEATURES.items()
+    }
+    features.update(extensions or {})
+    features.update(overrides or {})
+    return ServerCapabilities(version=version, features=features)
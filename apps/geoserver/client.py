@@ -3,15 +3,171 @@
 Provides a comprehensive Python client for the GeoServer REST API.
 """
 
+import gzip
+import logging
 import threading
+from collections.abc import Collection, Iterator
+from contextlib import ExitStack, contextmanager
+from datetime import datetime
+from enum import Enum
 from typing import Any
 from xml.etree import ElementTree as ET
 
 import httpx
 
+from apps.core import hooks
 from apps.core.config import Connection
 from apps.core.exceptions import GeoServerError
-from apps.core.managers import client_manager
+from apps.core.managers import TransportOptions, client_manager, compression_headers
+from apps.core.oidc import connection_auth
+from apps.core.read_only import check_writable
+from apps.core.response_limits import CHUNK_SIZE, read_limited
+from apps.core.timeouts import request_timeout, upload_body
+
+from .backup import BackupRestoreClient
+from .branding import LayerAttribution, Watermark
+from .capabilities import ServerCapabilities, build_capabilities
+from .default_styles import feature_type_geometry, rules_for, style_for
+from .geofence import GeofenceClient
+from .hrefs import rest_path, store_from_href
+from .importer import ImporterClient
+from .managed import content_hash, managed_state, store_hash
+from .manifests import InstalledModule, extension_features, installed_modules
+from .ows import parse_exception_report, parse_hits, service_path, service_url
+from .permissions import ServerPermissions, probe_permissions
+from .recent import activity_log
+from .rest_xml import encode_rest_xml, is_core_path, parse_rest_xml, xml_path
+from .schema import FeatureTypeSchema, parse_feature_type
+from .sld import CONTENT_TYPES, SLD_10, SLD_11, detect_sld_version
+from .store_secrets import resolve_secrets
+from .style_package import PACKAGE_CONTENT_TYPE
+
+logger = logging.getLogger(__name__)
+
+# Request bodies smaller than this aren't worth compressing
+COMPRESS_MIN_BYTES = 64 * 1024
+
+# Statuses a server refusing gzip-encoded bodies answers with
+GZIP_REFUSED_STATUSES = (400, 415)
+
+# Statuses a create fails with when the name is taken; GeoServer answers
+# most such conflicts with a 500 rather than a 409
+EXISTS_STATUSES = (409, 500)
+
+
+class CreateResult(str, Enum):
+    """Outcome of a create, telling an existing resource apart when if_absent is set."""
+
+    CREATED = "created"
+    ALREADY_EXISTS = "already_exists"
+
+
+class StreamedBody:
+    """A response body read in pieces, releasing its connection when closed.
+
+    Django's StreamingHttpResponse closes its content once the response
+    is done, even if the client went away before the first piece was
+    read, so the connection and its request slot are always given back.
+    """
+
+    def __init__(self, response: httpx.Response, stack: ExitStack):
+        self._pieces = response.iter_bytes(CHUNK_SIZE)
+        self._stack = stack
+
+    def __iter__(self) -> Iterator[bytes]:
+        try:
+            yield from self._pieces
+        finally:
+            self.close()
+
+    def close(self) -> None:
+        """Close the response and release its connection; safe to call twice."""
+        self._stack.close()
+
+
+def parse_feature_count(response: httpx.Response) -> int | None:
+    """Read the match count from a WFS resultType=hits response.
+
+    Understands GeoJSON (numberMatched/totalFeatures) and XML feature
+    collections (WFS 2.0 numberMatched, WFS 1.1 numberOfFeatures).
+
+    Args:
+        response: WFS GetFeature response
+
+    Returns:
+        The count, or None if the response doesn't carry one
+
+    Raises:
+        GeoServerError: If the request failed or returned an OWS exception
+    """
+    if response.status_code >= 400:
+        raise GeoServerError(
+            f"Feature count failed: {response.text}", status_code=response.status_code
+        )
+
+    if "json" in response.headers.get("content-type", ""):
+        try:
+            data = response.json()
+        except ValueError:
+            return None
+        for key in ("numberMatched", "totalFeatures"):
+            value = data.get(key)
+            if isinstance(value, int):
+                return value
+        return None
+
+    report = parse_exception_report(response.text)
+    if report is not None:
+        raise GeoServerError(f"Feature count failed: {report.message}", status_code=400)
+
+    return parse_hits(response.text)
+
+
+def datastore_xml(
+    name: str,
+    connection_params: dict[str, str],
+    description: str = "",
+    enabled: bool = True,
+) -> bytes:
+    """Build an XML dataStore payload.
+
+    Args:
+        name: Data store name
+        connection_params: Connection parameters
+        description: Store description
+        enabled: Whether store is enabled
+
+    Returns:
+        Encoded XML document
+    """
+    store = ET.Element("dataStore")
+    ET.SubElement(store, "name").text = name
+    ET.SubElement(store, "description").text = description
+    ET.SubElement(store, "enabled").text = "true" if enabled else "false"
+    params = ET.SubElement(store, "connectionParameters")
+    for key, value in connection_params.items():
+        ET.SubElement(params, "entry", key=key).text = str(value)
+    return ET.tostring(store, encoding="utf-8")
+
+
+def layergroup_entries(group: dict[str, Any]) -> list[dict[str, str]]:
+    """List the layers and nested groups a layer group draws, in drawing order.
+
+    Args:
+        group: Layer group, as returned by GeoServerClient.get_layergroup
+
+    Returns:
+        Entries with "type" ("layer" or "layerGroup") and "name"
+    """
+    published = (group.get("publishables") or {}).get("published") or []
+    # GeoServer JSON collapses single-element lists into objects
+    if not isinstance(published, list):
+        published = [published]
+    return [
+        {"type": entry.get("@type", "layer"), "name": entry["name"]}
+        for entry in published
+        if isinstance(entry, dict) and entry.get("name")  # Style-only entries have no layer
+    ]
 
 
 class GeoServerClient:
@@ -24,12 +180,29 @@ class GeoServerClient:
             connection: GeoServer connection configuration
         """
         self.connection = connection
+        # A cluster's catalog is changed through its primary node only
         self._client = client_manager.get_client(
             connection.id,
-            connection.url,
+            connection.primary_url,
             connection.username,
             connection.password,
+            transport_options=TransportOptions.from_connection(connection),
+            auth=connection_auth(connection),
+            headers=compression_headers(connection.http_compression),
+        )
+        self._gzip_uploads = connection.http_compression
+        # Core resources are read and written as XML; see apps.geoserver.rest_xml
+        self._rest_xml = connection.rest_format == "xml"
+        self._limiter = client_manager.get_limiter(
+            connection.id,
+            connection.max_concurrent_requests,
+            connection.max_requests_per_second,
         )
+        self._capabilities: ServerCapabilities | None = None
+        self._permissions: ServerPermissions | None = None
+        self._importer: ImporterClient | None = None
+        self._backup_restore: BackupRestoreClient | None = None
+        self._geofence: GeofenceClient | None = None
 
     def _request(
         self,
@@ -48,20 +221,171 @@ class GeoServerClient:
             httpx.Response
 
         Raises:
-            GeoServerError: If the request fails
+            GeoServerError: If the request fails, or would change a read-only connection
         """
         # Ensure path starts with /rest
         if not path.startswith("/rest"):
             path = f"/rest{path}"
+        check_writable(self.connection, method, path)
+        if self._rest_xml and is_core_path(path):
+            path = xml_path(path)
+            if "json" in kwargs:
+                kwargs["content"] = encode_rest_xml(kwargs.pop("json"))
+                kwargs["headers"] = {**kwargs.get("headers", {}), "Content-Type": "application/xml"}
+
+        with self._open(method, path, **kwargs) as response:
+            return read_limited(response, self.connection.max_response_mb)
+
+    @contextmanager
+    def _open(self, method: str, path: str, **kwargs: Any) -> Iterator[httpx.Response]:
+        """Send an HTTP request, leaving the response body to be read in pieces.
 
+        Raises:
+            GeoServerError: If the request fails or the body can't be read
+        """
         try:
-            response = self._client.request(method, path, **kwargs)
-            return response
+            with self._limiter.slot(), self._client.stream(method, path, **kwargs) as response:
+                yield response
         except httpx.HTTPError as e:
             raise GeoServerError(f"HTTP error: {str(e)}")
 
+    def _download(self, path: str, action: str) -> Iterator[bytes]:
+        """Stream a REST download in pieces, however big it is.
+
+        Args:
+            path: API path of the file
+            action: What is downloaded, for the error message
+
+        Raises:
+            GeoServerError: If the file can't be downloaded
+        """
+        with self._open("GET", path, timeout=request_timeout("download")) as response:
+            if response.status_code >= 400:
+                read_limited(response, self.connection.max_response_mb)
+                raise GeoServerError(
+                    f"Failed to download {action}: {response.status_code} {response.text}",
+                    status_code=response.status_code,
+                )
+            yield from response.iter_bytes(CHUNK_SIZE)
+
+    def _ows_request(
+        self,
+        service: str,
+        workspace: str | None,
+        params: dict[str, Any],
+        layer: str | None = None,
+        kind: str = "ows",
+    ) -> httpx.Response:
+        """Make a GET request to a (virtual) OWS service endpoint.
+
+        Args:
+            service: Service name (wms, wfs, wcs, wmts or ows)
+            workspace: Workspace for the virtual service, or None for the global one
+            params: Query parameters
+            layer: Optional layer for a layer virtual service
+            kind: Timeout setting to wait for, "ows" or "download"
+
+        Returns:
+            httpx.Response
+
+        Raises:
+            GeoServerError: If the request fails
+        """
+        with self._open(
+            "GET",
+            service_path(service, workspace, layer),
+            params=params,
+            timeout=request_timeout(kind),
+        ) as response:
+            return read_limited(response, self.connection.max_response_mb)
+
+    def open_ows(
+        self,
+        path: str,
+        params: list[tuple[str, str]],
+        headers: dict[str, str] | None = None,
+    ) -> tuple[httpx.Response, StreamedBody]:
+        """Send an OWS GET request whose response is read as it arrives, for proxying.
+
+        Args:
+            path: Service path, checked by apps.geoserver.ows_proxy.proxy_path
+            params: Query parameters, in order; a name may repeat
+            headers: Extra request headers
+
+        Returns:
+            The response, whose headers can be read at once, and its body in
+            pieces; the connection is released once the body has been read
+            or is closed
+
+        Raises:
+            GeoServerError: If GeoServer can't be reached
+        """
+        with ExitStack() as stack:
+            response = stack.enter_context(
+                self._open(
+                    "GET",
+                    path,
+                    params=params,
+                    headers=headers or {},
+                    timeout=request_timeout("ows"),
+                )
+            )
+            # Handed to the body, which closes it instead of the with block
+            return response, StreamedBody(response, stack.pop_all())
+
+    def service_url(
+        self,
+        service: str,
+        workspace: str | None = None,
+        layer: str | None = None,
+    ) -> str:
+        """Get the public URL of a (virtual) OWS service on this server.
+
+        Args:
+            service: Service name (wms, wfs, wcs, wmts or ows)
+            workspace: Optional workspace for a workspace virtual service
+            layer: Optional layer for a layer virtual service
+
+        Returns:
+            Absolute service URL
+        """
+        return service_url(self.connection.public_base_url, service, workspace, layer)
+
+    def _put_compressed(
+        self,
+        path: str,
+        body: bytes,
+        headers: dict[str, str],
+    ) -> httpx.Response:
+        """PUT a body, gzip-compressing large payloads when enabled.
+
+        A compressed body refused as if the server can't decode it is sent
+        again uncompressed; if that goes through, compression is switched
+        off for this client. Other failures are returned as they are.
+        """
+        if self._gzip_uploads and len(body) >= COMPRESS_MIN_BYTES:
+            response = self._request(
+                "PUT",
+                path,
+                content=gzip.compress(body),
+                headers={**headers, "Content-Encoding": "gzip"},
+            )
+            if response.status_code not in GZIP_REFUSED_STATUSES:
+                return response
+
+            retry = self._request("PUT", path, content=body, headers=headers)
+            if retry.status_code < 400:
+                self._gzip_uploads = False
+            return retry
+
+        return self._request("PUT", path, content=body, headers=headers)
+
     def _get_json(self, path: str, **kwargs: Any) -> dict[str, Any]:
-        """Make a GET request and return JSON response."""
+        """Make a GET request and return JSON response.
+
+        On a connection set to "auto", a JSON response that can't be read
+        switches the client to XML, and the request is made again.
+        """
         response = self._request("GET", path, **kwargs)
         if response.status_code == 404:
             raise GeoServerError("Resource not found", status_code=404)
@@ -69,8 +393,172 @@ class GeoServerClient:
             raise GeoServerError(
                 f"Request failed: {response.text}", status_code=response.status_code
             )
+        try:
+            return self._parse(response)
+        except ValueError as e:
+            if self._fall_back_to_xml(path):
+                return self._get_json(path, **kwargs)
+            raise GeoServerError(f"Unreadable response from {path}: {e}", status_code=502)
+
+    def _parse(self, response: httpx.Response) -> dict[str, Any]:
+        """Read a REST response body, as JSON or as XML in the shape of JSON.
+
+        Raises:
+            ValueError: If the body can't be read
+        """
+        if "xml" in response.headers.get("content-type", ""):
+            return parse_rest_xml(response.content)
         return response.json()
 
+    def _fall_back_to_xml(self, path: str) -> bool:
+        """Switch an "auto" connection to XML after JSON for path couldn't be read.
+
+        Returns:
+            Whether the request should be made again, now as XML
+        """
+        if self._rest_xml or self.connection.rest_format != "auto" or not is_core_path(path):
+            return False
+        logger.warning(
+            "Unreadable JSON from %s on %s; using XML for the REST API",
+            path,
+            self.connection.name,
+        )
+        self._rest_xml = True
+        return True
+
+    def _exists(self, path: str) -> bool:
+        """Whether a REST resource exists.
+
+        Raises:
+            GeoServerError: If the request fails other than with a 404
+        """
+        response = self._request("GET", path)
+        if response.status_code == 404:
+            return False
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Request failed: {response.text}", status_code=response.status_code
+            )
+        return True
+
+    def _create_failed(
+        self, response: httpx.Response, what: str, path: str, if_absent: bool
+    ) -> CreateResult:
+        """Handle a failed create, which with if_absent may mean it already exists.
+
+        A create retried after a timeout, or racing another client, fails
+        because the earlier one went through; that isn't an error when the
+        caller only wants the resource to exist.
+
+        Raises:
+            GeoServerError: Unless if_absent is set and the resource at path exists
+        """
+        if if_absent and response.status_code in EXISTS_STATUSES and self._exists(path):
+            return CreateResult.ALREADY_EXISTS
+        raise GeoServerError(
+            f"Failed to create {what}: {response.text}", status_code=response.status_code
+        )
+
+    def _changed(
+        self,
+        kind: str,
+        workspace: str | None,
+        name: str,
+        added: bool = False,
+        hash: str | None = None,
+    ) -> None:
+        """Record a layer or style change and fire its hooks.
+
+        Added resources become managed; see apps.geoserver.managed. A
+        hash of what was sent updates that of a managed resource.
+        """
+        activity_log.record(self.connection.id, kind, workspace, name, added=added)
+        if added:
+            managed_state.add(self.connection.id, kind, workspace, name, hash or "")
+        elif hash is not None:
+            managed_state.touch(self.connection.id, kind, workspace, name, hash)
+        if kind == "layer":
+            event = "layer.published" if added else "layer.updated"
+        else:
+            event = "style.created" if added else "style.updated"
+        hooks.emit(event, self.connection, workspace, name)
+
+    def _apply_default_style(
+        self,
+        workspace: str,
+        layer: str,
+        geometry: str | None = None,
+        datastore: str | None = None,
+    ) -> str | None:
+        """Give a new layer its workspace's default style for its geometry type.
+
+        A feature type's geometry is read from the catalog when not
+        given. The layer is published either way, so a style that can't
+        be set is only logged.
+
+        Returns:
+            The style set, or None when no rule names one
+        """
+        rules = rules_for(self.connection.id, workspace)
+        if not rules:
+            return None
+        try:
+            if geometry is None and datastore:
+                geometry = feature_type_geometry(self.get_featuretype(workspace, datastore, layer))
+            style = style_for(rules, geometry)
+            if style:
+                self.update_layer(workspace, layer, default_style=style)
+        except GeoServerError as e:
+            logger.warning("Could not set the default style of %s:%s: %s", workspace, layer, e)
+            return None
+        return style
+
+    def _featuretype_names(self, workspace: str, datastore: str) -> set[str]:
+        """Names of a store's published feature types; none if it can't be read."""
+        try:
+            return {ft["name"] for ft in self.list_featuretypes(workspace, datastore)}
+        except GeoServerError:
+            return set()
+
+    def _style_uploaded_featuretypes(
+        self, workspace: str, datastore: str, before: set[str]
+    ) -> None:
+        """Give the feature types an upload published their default styles.
+
+        Args:
+            workspace: Workspace name
+            datastore: Data store the upload went into
+            before: Feature types the store had published before it
+        """
+        for name in sorted(self._featuretype_names(workspace, datastore) - before):
+            self._apply_default_style(workspace, name, datastore=datastore)
+
+    def _removed(self, kind: str, workspace: str | None, name: str) -> None:
+        """Forget a deleted layer or style and fire its hooks."""
+        activity_log.forget(self.connection.id, kind, workspace, name)
+        managed_state.remove(self.connection.id, kind, workspace, name)
+        hooks.emit(f"{kind}.deleted", self.connection, workspace, name)
+
+    def get_href(self, href: str) -> dict[str, Any]:
+        """Fetch the resource an href in a REST response links to.
+
+        The href is resolved against this connection's URL rather than
+        followed as-is, since proxies may report a different host or prefix.
+
+        Args:
+            href: href from a REST response
+
+        Returns:
+            Response JSON
+
+        Raises:
+            GeoServerError: If the href isn't a REST link or the request fails
+        """
+        path = rest_path(href)
+        if path is None:
+            raise GeoServerError(f"Not a REST API link: {href}", status_code=400)
+        return self._get_json(path)
+
     # === Workspaces ===
 
     def list_workspaces(self) -> list[dict[str, Any]]:
@@ -102,14 +590,23 @@ class GeoServerClient:
         name: str,
         isolated: bool = False,
         default: bool = False,
-    ) -> None:
+        if_absent: bool = False,
+    ) -> CreateResult:
         """Create a new workspace.
 
         Args:
             name: Workspace name
             isolated: Whether workspace is isolated
             default: Whether to set as default workspace
+            if_absent: Return ALREADY_EXISTS instead of failing if it already exists
+
+        Returns:
+            CREATED, or ALREADY_EXISTS when if_absent is set and it already exists
         """
+        path = f"/rest/workspaces/{name}.json"
+        if if_absent and self._exists(path):
+            return CreateResult.ALREADY_EXISTS
+
         payload = {"workspace": {"name": name, "isolated": isolated}}
         response = self._request(
             "POST",
@@ -118,10 +615,10 @@ class GeoServerClient:
             params={"default": str(default).lower()} if default else None,
         )
         if response.status_code >= 400:
-            raise GeoServerError(
-                f"Failed to create workspace: {response.text}",
-                status_code=response.status_code,
-            )
+            return self._create_failed(response, "workspace", path, if_absent)
+        managed_state.add(self.connection.id, "workspace", None, name, content_hash(payload))
+        hooks.emit("workspace.created", self.connection, name=name)
+        return CreateResult.CREATED
 
     def update_workspace(
         self,
@@ -166,6 +663,8 @@ class GeoServerClient:
                 f"Failed to delete workspace: {response.text}",
                 status_code=response.status_code,
             )
+        managed_state.remove(self.connection.id, "workspace", None, name)
+        hooks.emit("workspace.deleted", self.connection, name=name, recurse=recurse)
 
     # === Data Stores ===
 
@@ -204,38 +703,66 @@ class GeoServerClient:
         connection_params: dict[str, str],
         description: str = "",
         enabled: bool = True,
-    ) -> None:
+        if_absent: bool = False,
+    ) -> CreateResult:
         """Create a new data store.
 
         Args:
             workspace: Workspace name
             name: Data store name
             connection_params: Connection parameters (dbtype, host, port, database, user, passwd, schema)
+                The password can be an env: or keyring: reference, see store_secrets
             description: Store description
             enabled: Whether store is enabled
-        """
-        # Format connection parameters
-        entries = [{"@key": k, "$": v} for k, v in connection_params.items()]
+            if_absent: Return ALREADY_EXISTS instead of failing if it already exists
 
-        payload = {
-            "dataStore": {
-                "name": name,
-                "description": description,
-                "enabled": enabled,
-                "connectionParameters": {"entry": entries},
+        Returns:
+            CREATED, or ALREADY_EXISTS when if_absent is set and it already exists
+        """
+        path = f"/rest/workspaces/{workspace}/datastores/{name}.json"
+        if if_absent and self._exists(path):
+            return CreateResult.ALREADY_EXISTS
+        try:
+            connection_params = resolve_secrets(connection_params)
+        except ValueError as e:
+            raise GeoServerError(str(e), status_code=400) from e
+
+        if self.capabilities.supports("json_datastore"):
+            # Format connection parameters
+            entries = [{"@key": k, "$": v} for k, v in connection_params.items()]
+
+            payload = {
+                "dataStore": {
+                    "name": name,
+                    "description": description,
+                    "enabled": enabled,
+                    "connectionParameters": {"entry": entries},
+                }
             }
-        }
 
-        response = self._request(
-            "POST",
-            f"/rest/workspaces/{workspace}/datastores.json",
-            json=payload,
-        )
-        if response.status_code >= 400:
-            raise GeoServerError(
-                f"Failed to create datastore: {response.text}",
-                status_code=response.status_code,
+            response = self._request(
+                "POST",
+                f"/rest/workspaces/{workspace}/datastores.json",
+                json=payload,
+            )
+        else:
+            # Older releases mis-read JSON connection parameters, XML is reliable
+            response = self._request(
+                "POST",
+                f"/rest/workspaces/{workspace}/datastores",
+                content=datastore_xml(name, connection_params, description, enabled),
+                headers={"Content-Type": "text/xml"},
             )
+        if response.status_code >= 400:
+            return self._create_failed(response, "datastore", path, if_absent)
+        managed_state.add(
+            self.connection.id,
+            "datastore",
+            workspace,
+            name,
+            store_hash(connection_params, name=name, description=description, enabled=enabled),
+        )
+        return CreateResult.CREATED
 
     def delete_datastore(
         self, workspace: str, name: str, recurse: bool = False
@@ -257,6 +784,7 @@ class GeoServerClient:
                 f"Failed to delete datastore: {response.text}",
                 status_code=response.status_code,
             )
+        managed_state.remove(self.connection.id, "datastore", workspace, name)
 
     # === Coverage Stores ===
 
@@ -298,17 +826,26 @@ class GeoServerClient:
         url: str | None = None,
         description: str = "",
         enabled: bool = True,
-    ) -> None:
+        if_absent: bool = False,
+    ) -> CreateResult:
         """Create a new coverage store.
 
         Args:
             workspace: Workspace name
             name: Coverage store name
             store_type: Store type (GeoTIFF, WorldImage, etc.)
-            url: URL to coverage data
+            url: URL to coverage data; cog:// URLs need the COG plugin
             description: Store description
             enabled: Whether store is enabled
+            if_absent: Return ALREADY_EXISTS instead of failing if it already exists
+
+        Returns:
+            CREATED, or ALREADY_EXISTS when if_absent is set and it already exists
         """
+        path = f"/rest/workspaces/{workspace}/coveragestores/{name}.json"
+        if if_absent and self._exists(path):
+            return CreateResult.ALREADY_EXISTS
+
         payload = {
             "coverageStore": {
                 "name": name,
@@ -319,6 +856,10 @@ class GeoServerClient:
         if description:
             payload["coverageStore"]["description"] = description
         if url:
+            if url.startswith("cog://") and not self.capabilities.supports("cog"):
+                raise GeoServerError(
+                    "Cloud Optimized GeoTIFF stores need the COG plugin", status_code=501
+                )
             payload["coverageStore"]["url"] = url
 
         response = self._request(
@@ -327,10 +868,11 @@ class GeoServerClient:
             json=payload,
         )
         if response.status_code >= 400:
-            raise GeoServerError(
-                f"Failed to create coveragestore: {response.text}",
-                status_code=response.status_code,
-            )
+            return self._create_failed(response, "coveragestore", path, if_absent)
+        managed_state.add(
+            self.connection.id, "coveragestore", workspace, name, content_hash(payload)
+        )
+        return CreateResult.CREATED
 
     def delete_coveragestore(
         self, workspace: str, name: str, recurse: bool = False
@@ -352,6 +894,7 @@ class GeoServerClient:
                 f"Failed to delete coveragestore: {response.text}",
                 status_code=response.status_code,
             )
+        managed_state.remove(self.connection.id, "coveragestore", workspace, name)
 
     # === Feature Types ===
 
@@ -398,37 +941,77 @@ class GeoServerClient:
         name: str,
         native_name: str | None = None,
         title: str | None = None,
-        srs: str = "EPSG:4326",
-    ) -> None:
+        srs: str | None = "EPSG:4326",
+        if_absent: bool = False,
+    ) -> CreateResult:
         """Create/publish a feature type.
 
+        The layer gets its workspace's default style for its geometry
+        type, if the config names one; see apps.geoserver.default_styles.
+
         Args:
             workspace: Workspace name
             datastore: Data store name
             name: Feature type name
             native_name: Native table name (defaults to name)
             title: Layer title
-            srs: Coordinate reference system
+            srs: Coordinate reference system; None keeps the native one
+            if_absent: Return ALREADY_EXISTS instead of failing if it already exists
+
+        Returns:
+            CREATED, or ALREADY_EXISTS when if_absent is set and it already exists
         """
-        payload = {
+        path = f"/rest/workspaces/{workspace}/datastores/{datastore}/featuretypes/{name}.json"
+        if if_absent and self._exists(path):
+            return CreateResult.ALREADY_EXISTS
+
+        payload: dict[str, Any] = {
             "featureType": {
                 "name": name,
                 "nativeName": native_name or name,
                 "title": title or name,
-                "srs": srs,
             }
         }
+        if srs:
+            payload["featureType"]["srs"] = srs
 
         response = self._request(
             "POST",
             f"/rest/workspaces/{workspace}/datastores/{datastore}/featuretypes.json",
             json=payload,
         )
+        if response.status_code >= 400:
+            return self._create_failed(response, "featuretype", path, if_absent)
+        self._changed("layer", workspace, name, added=True, hash=content_hash(payload))
+        self._apply_default_style(workspace, name, datastore=datastore)
+        return CreateResult.CREATED
+
+    def update_featuretype(
+        self,
+        workspace: str,
+        datastore: str,
+        name: str,
+        updates: dict[str, Any],
+    ) -> None:
+        """Update a feature type.
+
+        Args:
+            workspace: Workspace name
+            datastore: Data store name
+            name: Feature type name
+            updates: Feature type fields to update
+        """
+        response = self._request(
+            "PUT",
+            f"/rest/workspaces/{workspace}/datastores/{datastore}/featuretypes/{name}.json",
+            json={"featureType": updates},
+        )
         if response.status_code >= 400:
             raise GeoServerError(
-                f"Failed to create featuretype: {response.text}",
+                f"Failed to update featuretype: {response.text}",
                 status_code=response.status_code,
             )
+        self._changed("layer", workspace, name)
 
     def delete_featuretype(
         self, workspace: str, datastore: str, name: str, recurse: bool = False
@@ -451,6 +1034,7 @@ class GeoServerClient:
                 f"Failed to delete featuretype: {response.text}",
                 status_code=response.status_code,
             )
+        self._removed("layer", workspace, name)
 
     # === Coverages ===
 
@@ -490,6 +1074,33 @@ class GeoServerClient:
         )
         return data.get("coverage", {})
 
+    def update_coverage(
+        self,
+        workspace: str,
+        coveragestore: str,
+        name: str,
+        updates: dict[str, Any],
+    ) -> None:
+        """Update a coverage.
+
+        Args:
+            workspace: Workspace name
+            coveragestore: Coverage store name
+            name: Coverage name
+            updates: Coverage fields to update
+        """
+        response = self._request(
+            "PUT",
+            f"/rest/workspaces/{workspace}/coveragestores/{coveragestore}/coverages/{name}.json",
+            json={"coverage": updates},
+        )
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to update coverage: {response.text}",
+                status_code=response.status_code,
+            )
+        self._changed("layer", workspace, name)
+
     # === Layers ===
 
     def list_layers(self, workspace: str | None = None) -> list[dict[str, Any]]:
@@ -564,6 +1175,7 @@ class GeoServerClient:
                 f"Failed to update layer: {response.text}",
                 status_code=response.status_code,
             )
+        self._changed("layer", workspace, name)
 
     def delete_layer(self, workspace: str, name: str, recurse: bool = False) -> None:
         """Delete a layer.
@@ -583,57 +1195,304 @@ class GeoServerClient:
                 f"Failed to delete layer: {response.text}",
                 status_code=response.status_code,
             )
+        self._removed("layer", workspace, name)
 
-    def get_layer_feature_count(self, workspace: str, layer: str) -> int:
+    def get_layer_feature_count(
+        self,
+        workspace: str,
+        layer: str,
+        cql_filter: str | None = None,
+        bbox: list[float] | tuple[float, ...] | None = None,
+        bbox_srs: str | None = None,
+    ) -> int:
         """Get the feature count for a vector layer via WFS.
 
+        Asks for a JSON hits response first and falls back to the XML one
+        for servers without the JSON output format.
+
         Args:
             workspace: Workspace name
             layer: Layer name
+            cql_filter: Optional CQL filter restricting the counted features
+            bbox: Optional (minx, miny, maxx, maxy) restricting the counted features
+            bbox_srs: SRS of the bbox, e.g. EPSG:4326 (defaults to the layer's SRS)
 
         Returns:
-            Number of features in the layer
+            Number of matching features in the layer
+
+        Raises:
+            ValueError: If both a CQL filter and a bbox are given, or the bbox is malformed
+            GeoServerError: If the WFS request fails
         """
-        # Use WFS GetFeature with resultType=hits
-        response = self._request(
-            "GET",
-            f"/wfs",
-            params={
-                "service": "WFS",
-                "version": "2.0.0",
-                "request": "GetFeature",
-                "typeNames": f"{workspace}:{layer}",
-                "resultType": "hits",
-            },
+        params: dict[str, Any] = {
+            "service": "WFS",
+            "version": "2.0.0",
+            "request": "GetFeature",
+            "typeNames": f"{workspace}:{layer}",
+            "resultType": "hits",
+        }
+        if cql_filter and bbox:
+            # GeoServer rejects CQL_FILTER and BBOX in the same request
+            raise ValueError("cql_filter and bbox cannot be combined; use BBOX() in the filter")
+        if cql_filter:
+            params["CQL_FILTER"] = cql_filter
+        if bbox:
+            if len(bbox) != 4:
+                raise ValueError("bbox must have 4 values")
+            coords = ",".join(str(float(v)) for v in bbox)
+            params["bbox"] = f"{coords},{bbox_srs}" if bbox_srs else coords
+
+        # Use WFS GetFeature with resultType=hits on the workspace virtual service
+        response = self._ows_request(
+            "wfs", workspace, params={**params, "outputFormat": "application/json"}
         )
+        try:
+            count = parse_feature_count(response)
+        except GeoServerError:
+            # JSON output may not be available; the XML request reports real errors
+            count = None
+        if count is None:
+            response = self._ows_request("wfs", workspace, params=params)
+            count = parse_feature_count(response)
+
+        return count or 0
+
+    def _wfs_json(self, workspace: str, params: dict[str, Any], action: str) -> dict[str, Any]:
+        """Make a WFS request on a workspace virtual service that answers in JSON."""
+        params = {"service": "WFS", "version": "2.0.0", **params}
+        params["outputFormat"] = "application/json"
+        response = self._ows_request("wfs", workspace, params=params)
+        # GeoServer reports WFS errors as XML, sometimes with status 200
+        if response.status_code >= 400 or "json" not in response.headers.get("content-type", ""):
+            report = parse_exception_report(response.content)
+            message = report.message if report else response.text[:200]
+            status_code = response.status_code if response.status_code >= 400 else 400
+            raise GeoServerError(f"{action} failed: {message}", status_code=status_code)
+        return response.json()
 
-        if response.status_code == 200:
-            # Parse XML response to get numberMatched
-            try:
-                root = ET.fromstring(response.text)
-                # WFS 2.0 returns numberMatched attribute
-                count = root.get("numberMatched")
-                if count:
-                    return int(count)
-            except (ET.ParseError, ValueError):
-                pass
-
-        return 0
-
-    # === Styles ===
-
-    def list_styles(self, workspace: str | None = None) -> list[dict[str, Any]]:
-        """List all styles, optionally filtered by workspace.
+    def describe_feature_type(
+        self, workspace: str, layer: str, include_srs: bool = False
+    ) -> FeatureTypeSchema:
+        """Get the schema of a vector layer via WFS DescribeFeatureType.
 
         Args:
-            workspace: Optional workspace name to filter by
+            workspace: Workspace name
+            layer: Layer name
+            include_srs: Also read the layer's SRS from the REST catalog,
+                which DescribeFeatureType doesn't report
 
         Returns:
-            List of style dictionaries
+            Attribute names, types and nullability, with the geometry type
         """
-        if workspace:
-            data = self._get_json(f"/rest/workspaces/{workspace}/styles.json")
-        else:
+        data = self._wfs_json(
+            workspace,
+            {"request": "DescribeFeatureType", "typeNames": f"{workspace}:{layer}"},
+            "DescribeFeatureType",
+        )
+        schema = parse_feature_type(data)
+        if include_srs:
+            schema.srs = self.get_layer_srs(workspace, layer)
+        return schema
+
+    def get_features(
+        self,
+        workspace: str,
+        layer: str,
+        count: int,
+        start_index: int = 0,
+        sort_by: str | None = None,
+        cql_filter: str | None = None,
+        property_names: list[str] | None = None,
+    ) -> dict[str, Any]:
+        """Get a page of a vector layer's features via WFS GetFeature.
+
+        Args:
+            workspace: Workspace name
+            layer: Layer name
+            count: Most features returned
+            start_index: Index of the first feature returned
+            sort_by: Optional WFS sortBy, e.g. "name DESC"
+            cql_filter: Optional CQL filter restricting the features
+            property_names: Optional attributes to return; all when omitted
+
+        Returns:
+            GeoJSON feature collection
+        """
+        params: dict[str, Any] = {
+            "request": "GetFeature",
+            "typeNames": f"{workspace}:{layer}",
+            "count": count,
+            "startIndex": start_index,
+        }
+        if sort_by:
+            params["sortBy"] = sort_by
+        if cql_filter:
+            params["CQL_FILTER"] = cql_filter
+        if property_names:
+            params["propertyName"] = ",".join(property_names)
+        return self._wfs_json(workspace, params, "GetFeature")
+
+    def get_map(
+        self,
+        workspace: str,
+        layer: str | list[str],
+        bbox: tuple[float, float, float, float],
+        width: int,
+        height: int,
+        srs: str = "EPSG:3857",
+        style: str = "",
+        cql_filter: str | None = None,
+    ) -> bytes:
+        """Render a layer with a WMS GetMap request as a transparent PNG.
+
+        Args:
+            workspace: Workspace name
+            layer: Layer or layer group name, or several names drawn
+                bottom to top in their default styles
+            bbox: (minx, miny, maxx, maxy) in the SRS
+            width: Image width in pixels
+            height: Image height in pixels
+            srs: SRS of the bbox and image
+            style: Style name; the layer's default when empty
+            cql_filter: Optional CQL filter restricting the drawn features
+
+        Returns:
+            PNG image bytes
+
+        Raises:
+            GeoServerError: If GeoServer returns an error instead of an image
+        """
+        layers = [layer] if isinstance(layer, str) else layer
+        params = {
+            "service": "WMS",
+            "version": "1.1.1",
+            "request": "GetMap",
+            "layers": ",".join(f"{workspace}:{name}" for name in layers),
+            "styles": style,
+            "bbox": ",".join(str(float(v)) for v in bbox),
+            "srs": srs,
+            "width": width,
+            "height": height,
+            "format": "image/png",
+            "transparent": "true",
+        }
+        if cql_filter:
+            params["CQL_FILTER"] = cql_filter
+        response = self._ows_request("wms", workspace, params=params)
+        # GeoServer reports WMS errors as XML, often with status 200
+        content_type = response.headers.get("content-type", "")
+        if response.status_code >= 400 or not content_type.startswith("image/"):
+            report = parse_exception_report(response.content)
+            message = report.message if report else response.text[:200]
+            raise GeoServerError(f"GetMap failed: {message}", status_code=response.status_code)
+        return response.content
+
+    def get_legend_graphic(
+        self, workspace: str, layer: str, style: str = "", width: int = 20, height: int = 20
+    ) -> bytes:
+        """Get a layer's legend with a WMS GetLegendGraphic request as a PNG.
+
+        Args:
+            workspace: Workspace name
+            layer: Layer name
+            style: Style name; the layer's default when empty
+            width: Width of each legend symbol in pixels
+            height: Height of each legend symbol in pixels
+
+        Returns:
+            PNG image bytes
+
+        Raises:
+            GeoServerError: If GeoServer returns an error instead of an image
+        """
+        params = {
+            "service": "WMS",
+            "version": "1.1.1",
+            "request": "GetLegendGraphic",
+            "layer": f"{workspace}:{layer}",
+            "style": style,
+            "format": "image/png",
+            "width": width,
+            "height": height,
+        }
+        response = self._ows_request("wms", workspace, params=params)
+        content_type = response.headers.get("content-type", "")
+        if response.status_code >= 400 or not content_type.startswith("image/"):
+            report = parse_exception_report(response.content)
+            message = report.message if report else response.text[:200]
+            raise GeoServerError(
+                f"GetLegendGraphic failed: {message}", status_code=response.status_code
+            )
+        return response.content
+
+    def _ows_download(
+        self, service: str, workspace: str, params: dict[str, Any], content_type: str, action: str
+    ) -> bytes:
+        """Download a file from an OWS service, checking it isn't an exception report."""
+        response = self._ows_request(service, workspace, params=params, kind="download")
+        # GeoServer reports OWS errors as XML, sometimes with status 200
+        if response.status_code >= 400 or content_type not in response.headers.get(
+            "content-type", ""
+        ):
+            report = parse_exception_report(response.content)
+            message = report.message if report else response.text[:200]
+            status_code = response.status_code if response.status_code >= 400 else 400
+            raise GeoServerError(f"{action} failed: {message}", status_code=status_code)
+        return response.content
+
+    def get_layer_shapefile(self, workspace: str, layer: str) -> bytes:
+        """Download every feature of a vector layer as a zipped shapefile via WFS.
+
+        Args:
+            workspace: Workspace name
+            layer: Layer name
+
+        Returns:
+            Zip archive bytes
+        """
+        params = {
+            "service": "WFS",
+            "version": "2.0.0",
+            "request": "GetFeature",
+            "typeNames": f"{workspace}:{layer}",
+            "outputFormat": "SHAPE-ZIP",
+        }
+        return self._ows_download("wfs", workspace, params, "zip", "Shapefile download")
+
+    def get_coverage_geotiff(self, workspace: str, coverage: str) -> bytes:
+        """Download a raster layer at full resolution as a GeoTIFF via WCS.
+
+        Args:
+            workspace: Workspace name
+            coverage: Coverage (layer) name
+
+        Returns:
+            GeoTIFF bytes
+        """
+        params = {
+            "service": "WCS",
+            "version": "2.0.1",
+            "request": "GetCoverage",
+            # WCS 2.0 separates the workspace with a double underscore
+            "coverageId": f"{workspace}__{coverage}",
+            "format": "image/tiff",
+        }
+        return self._ows_download("wcs", workspace, params, "image/tiff", "GeoTIFF download")
+
+    # === Styles ===
+
+    def list_styles(self, workspace: str | None = None) -> list[dict[str, Any]]:
+        """List all styles, optionally filtered by workspace.
+
+        Args:
+            workspace: Optional workspace name to filter by
+
+        Returns:
+            List of style dictionaries
+        """
+        if workspace:
+            data = self._get_json(f"/rest/workspaces/{workspace}/styles.json")
+        else:
             data = self._get_json("/rest/styles.json")
 
         styles = data.get("styles", {})
@@ -666,12 +1525,16 @@ class GeoServerClient:
             name: Style name
             workspace: Optional workspace name
 
+        SLD 1.1 styles are read as stored; GeoServer would convert them
+        to SLD 1.0 otherwise.
+
         Returns:
             Tuple of (content, format) where format is 'sld' or 'css'
         """
         # First get style metadata to determine format
         style = self.get_style(name, workspace)
         style_format = style.get("format", "sld")
+        version = (style.get("languageVersion") or {}).get("version")
 
         # Map format to file extension
         ext_map = {
@@ -687,7 +1550,13 @@ class GeoServerClient:
         else:
             path = f"/rest/styles/{name}.{ext}"
 
-        response = self._request("GET", path)
+        headers = {}
+        if style_format == "sld" and version == SLD_11:
+            # The .sld extension would ask for SLD 1.0
+            path = path.rsplit(".", 1)[0]
+            headers["Accept"] = CONTENT_TYPES[SLD_11]
+
+        response = self._request("GET", path, headers=headers)
         if response.status_code >= 400:
             raise GeoServerError(
                 f"Failed to get style content: {response.text}",
@@ -702,37 +1571,52 @@ class GeoServerClient:
         content: str,
         style_format: str = "sld",
         workspace: str | None = None,
-    ) -> None:
+        if_absent: bool = False,
+    ) -> CreateResult:
         """Create a new style.
 
+        An existing style found with if_absent keeps its content.
+
         Args:
             name: Style name
-            content: Style content (SLD or CSS)
+            content: Style content (SLD or CSS); the SLD version is read from it
             style_format: Style format ('sld' or 'css')
             workspace: Optional workspace name
+            if_absent: Return ALREADY_EXISTS instead of failing if it already exists
+
+        Returns:
+            CREATED, or ALREADY_EXISTS when if_absent is set and it already exists
         """
+        self._check_style_format(style_format)
+        prefix = f"/rest/workspaces/{workspace}" if workspace else "/rest"
+        if if_absent and self._exists(f"{prefix}/styles/{name}.json"):
+            return CreateResult.ALREADY_EXISTS
+
         # First create the style entry
-        payload = {
+        payload: dict[str, Any] = {
             "style": {
                 "name": name,
                 "format": style_format,
             }
         }
+        if style_format == "sld":
+            payload["style"]["languageVersion"] = {"version": detect_sld_version(content)}
 
-        if workspace:
-            path = f"/rest/workspaces/{workspace}/styles.json"
-        else:
-            path = "/rest/styles.json"
-
-        response = self._request("POST", path, json=payload)
+        response = self._request("POST", f"{prefix}/styles.json", json=payload)
         if response.status_code >= 400:
-            raise GeoServerError(
-                f"Failed to create style: {response.text}",
-                status_code=response.status_code,
+            return self._create_failed(
+                response, "style", f"{prefix}/styles/{name}.json", if_absent
             )
 
         # Then upload the style content
-        self.update_style_content(name, content, style_format, workspace)
+        self._put_style_content(name, content, style_format, workspace)
+        self._changed("style", workspace, name, added=True, hash=content_hash(content))
+        return CreateResult.CREATED
+
+    def _check_style_format(self, style_format: str) -> None:
+        """Refuse a style format whose extension isn't installed."""
+        if style_format == "css" and not self.capabilities.supports("css_styles"):
+            raise GeoServerError("CSS styles need the CSS extension", status_code=501)
 
     def update_style_content(
         self,
@@ -743,19 +1627,35 @@ class GeoServerClient:
     ) -> None:
         """Update style content.
 
+        SLD 1.1 content is sent as Symbology Encoding, which GeoServer
+        stores as it is instead of as SLD 1.0.
+
         Args:
             name: Style name
-            content: New style content
+            content: New style content; the SLD version is read from it
             style_format: Style format ('sld' or 'css')
             workspace: Optional workspace name
         """
+        self._put_style_content(name, content, style_format, workspace)
+        self._changed("style", workspace, name, hash=content_hash(content))
+
+    def _put_style_content(
+        self,
+        name: str,
+        content: str,
+        style_format: str,
+        workspace: str | None,
+    ) -> None:
+        """Upload a style's content without recording the change."""
+        self._check_style_format(style_format)
+
         # Determine content type
+        version = detect_sld_version(content) if style_format == "sld" else None
         content_type_map = {
-            "sld": "application/vnd.ogc.sld+xml",
             "css": "application/vnd.geoserver.geocss+css",
             "mbstyle": "application/vnd.geoserver.mbstyle+json",
         }
-        content_type = content_type_map.get(style_format, "application/vnd.ogc.sld+xml")
+        content_type = content_type_map.get(style_format, CONTENT_TYPES[version or SLD_10])
 
         # Build path
         ext_map = {"sld": "sld", "css": "css", "mbstyle": "json"}
@@ -765,12 +1665,14 @@ class GeoServerClient:
             path = f"/rest/workspaces/{workspace}/styles/{name}.{ext}"
         else:
             path = f"/rest/styles/{name}.{ext}"
+        if version == SLD_11:
+            # Like on reads, the .sld extension stands for SLD 1.0
+            path = path.rsplit(".", 1)[0]
 
-        response = self._request(
-            "PUT",
+        response = self._put_compressed(
             path,
-            content=content.encode("utf-8"),
-            headers={"Content-Type": content_type},
+            content.encode("utf-8"),
+            {"Content-Type": content_type},
         )
         if response.status_code >= 400:
             raise GeoServerError(
@@ -778,8 +1680,43 @@ class GeoServerClient:
                 status_code=response.status_code,
             )
 
+    def upload_style_package(
+        self,
+        name: str,
+        data: bytes,
+        workspace: str | None = None,
+        exists: bool = False,
+    ) -> None:
+        """Create or update a style from a zip of its SLD and the graphics it uses.
+
+        GeoServer stores the graphics next to the SLD, where its relative
+        references resolve.
+
+        Args:
+            name: Style name
+            data: Zip holding one .sld file plus its icons and fonts
+            workspace: Optional workspace name
+            exists: Update the style instead of creating it
+        """
+        base = f"/rest/workspaces/{workspace}/styles" if workspace else "/rest/styles"
+        body = upload_body(data, {"Content-Type": PACKAGE_CONTENT_TYPE})
+        if exists:
+            response = self._request("PUT", f"{base}/{name}", **body)
+        else:
+            response = self._request("POST", base, params={"name": name}, **body)
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to upload style package: {response.text}",
+                status_code=response.status_code,
+            )
+        self._changed("style", workspace, name, added=not exists, hash=content_hash(data))
+
     def delete_style(
-        self, name: str, workspace: str | None = None, purge: bool = False
+        self,
+        name: str,
+        workspace: str | None = None,
+        purge: bool = False,
+        recurse: bool = False,
     ) -> None:
         """Delete a style.
 
@@ -787,6 +1724,8 @@ class GeoServerClient:
             name: Style name
             workspace: Optional workspace name
             purge: Purge style file from disk
+            recurse: Remove references to the style from layers; GeoServer
+                refuses to delete a style still used by a layer otherwise
         """
         if workspace:
             path = f"/rest/workspaces/{workspace}/styles/{name}"
@@ -796,13 +1735,14 @@ class GeoServerClient:
         response = self._request(
             "DELETE",
             path,
-            params={"purge": str(purge).lower()},
+            params={"purge": str(purge).lower(), "recurse": str(recurse).lower()},
         )
         if response.status_code >= 400:
             raise GeoServerError(
                 f"Failed to delete style: {response.text}",
                 status_code=response.status_code,
             )
+        self._removed("style", workspace, name)
 
     # === Layer Groups ===
 
@@ -841,6 +1781,103 @@ class GeoServerClient:
             data = self._get_json(f"/rest/layergroups/{name}.json")
         return data.get("layerGroup", {})
 
+    def get_layergroup_bounds(self, name: str, workspace: str | None = None) -> dict[str, Any]:
+        """Get a layer group's native and lat/lon bounding boxes.
+
+        GeoServer keeps a group's bounds in its native CRS only, so the
+        lat/lon box is the one covering the layers the group draws.
+
+        Args:
+            name: Layer group name
+            workspace: Optional workspace name
+
+        Returns:
+            Dictionary with nativeBoundingBox and latLonBoundingBox (either may be None)
+        """
+        group = self.get_layergroup(name, workspace)
+        bounds: dict[str, Any] = {
+            "nativeBoundingBox": group.get("bounds"),
+            "latLonBoundingBox": None,
+        }
+
+        boxes = []
+        for entry in layergroup_entries(group):
+            entry_workspace, _, entry_name = entry["name"].rpartition(":")
+            entry_workspace = entry_workspace or workspace
+            try:
+                if entry["type"] == "layerGroup":
+                    box = self.get_layergroup_bounds(entry_name, entry_workspace)
+                elif entry_workspace:
+                    box = self.get_layer_bounds(entry_workspace, entry_name)
+                else:
+                    continue
+            except GeoServerError:
+                continue  # The rest of the group still gives an extent
+            if box.get("latLonBoundingBox"):
+                boxes.append(box["latLonBoundingBox"])
+
+        if boxes:
+            bounds["latLonBoundingBox"] = {
+                "minx": min(b["minx"] for b in boxes),
+                "miny": min(b["miny"] for b in boxes),
+                "maxx": max(b["maxx"] for b in boxes),
+                "maxy": max(b["maxy"] for b in boxes),
+                "crs": "EPSG:4326",
+            }
+        return bounds
+
+    def create_layergroup(
+        self,
+        name: str,
+        layers: list[str],
+        workspace: str | None = None,
+        title: str | None = None,
+        mode: str = "SINGLE",
+        nested: Collection[str] = (),
+        if_absent: bool = False,
+    ) -> CreateResult:
+        """Create a layer group; GeoServer computes its bounds from the layers.
+
+        Args:
+            name: Layer group name
+            layers: Qualified layer names (workspace:layer), drawn in order
+            workspace: Optional workspace name; a global group when omitted
+            title: Optional title
+            mode: Layer group mode, e.g. SINGLE or NAMED
+            nested: Names in layers that are layer groups rather than layers
+            if_absent: Return ALREADY_EXISTS instead of failing if it already exists
+
+        Returns:
+            CREATED, or ALREADY_EXISTS when if_absent is set and it already exists
+        """
+        prefix = f"/rest/workspaces/{workspace}" if workspace else "/rest"
+        if if_absent and self._exists(f"{prefix}/layergroups/{name}.json"):
+            return CreateResult.ALREADY_EXISTS
+
+        published = [
+            {"@type": "layerGroup" if layer in nested else "layer", "name": layer}
+            for layer in layers
+        ]
+        payload: dict[str, Any] = {
+            "layerGroup": {
+                "name": name,
+                "mode": mode,
+                "publishables": {"published": published},
+            }
+        }
+        if title:
+            payload["layerGroup"]["title"] = title
+
+        response = self._request("POST", f"{prefix}/layergroups.json", json=payload)
+        if response.status_code >= 400:
+            return self._create_failed(
+                response, "layer group", f"{prefix}/layergroups/{name}.json", if_absent
+            )
+        managed_state.add(
+            self.connection.id, "layergroup", workspace, name, content_hash(payload)
+        )
+        return CreateResult.CREATED
+
     # === Layer Styles ===
 
     def get_layer_styles(self, workspace: str, layer: str) -> dict[str, Any]:
@@ -894,7 +1931,7 @@ class GeoServerClient:
             }
         }
 
-        if additional_styles:
+        if additional_styles is not None:
             payload["layer"]["styles"] = {
                 "style": [{"name": s} for s in additional_styles]
             }
@@ -909,6 +1946,38 @@ class GeoServerClient:
                 f"Failed to update layer styles: {response.text}",
                 status_code=response.status_code,
             )
+        self._changed("layer", workspace, layer)
+
+    def replace_layer_style(
+        self,
+        workspace: str,
+        layer: str,
+        style: str,
+        replacement: str,
+    ) -> None:
+        """Swap a style for another on a layer, keeping its usage.
+
+        The replacement becomes the default style if the old style was the
+        default, and an additional style otherwise.
+
+        Args:
+            workspace: Workspace name
+            layer: Layer name
+            style: Style to remove, plain or workspace-qualified
+            replacement: Style to use instead
+        """
+        names = (style, f"{workspace}:{style}")
+        current = self.get_layer_styles(workspace, layer)
+        default_style = current["defaultStyle"]
+        additional = [s for s in current["additionalStyles"] if s not in names]
+
+        if default_style in names:
+            default_style = replacement
+        elif replacement not in additional:
+            additional.append(replacement)
+
+        additional = [s for s in additional if s != default_style]
+        self.update_layer_styles(workspace, layer, default_style, additional)
 
     # === Layer Metadata ===
 
@@ -932,24 +2001,21 @@ class GeoServerClient:
         # Determine if this is a featuretype or coverage
         bbox = None
         if "featureType" in resource_class and resource_href:
-            # Extract path from href and fetch feature type
             # href looks like: http://server/geoserver/rest/workspaces/ws/datastores/ds/featuretypes/ft.json
             try:
-                ft_response = self._client.get(resource_href)
-                if ft_response.status_code == 200:
-                    ft_data = ft_response.json().get("featureType", {})
-                    bbox = ft_data.get("nativeBoundingBox") or ft_data.get("latLonBoundingBox")
+                ft_data = self.get_href(resource_href).get("featureType", {})
+                bbox = ft_data.get("nativeBoundingBox") or ft_data.get("latLonBoundingBox")
             except Exception:
                 pass
         elif "coverage" in resource_class and resource_href:
             try:
-                cov_response = self._client.get(resource_href)
-                if cov_response.status_code == 200:
-                    cov_data = cov_response.json().get("coverage", {})
-                    bbox = cov_data.get("nativeBoundingBox") or cov_data.get("latLonBoundingBox")
+                cov_data = self.get_href(resource_href).get("coverage", {})
+                bbox = cov_data.get("nativeBoundingBox") or cov_data.get("latLonBoundingBox")
             except Exception:
                 pass
 
+        store = store_from_href(resource_href)
+
         return {
             "name": layer_data.get("name"),
             "type": layer_data.get("type"),
@@ -959,8 +2025,117 @@ class GeoServerClient:
             "defaultStyle": layer_data.get("defaultStyle", {}),
             "bbox": bbox,
             "resource": resource,
+            "store": (
+                {"workspace": store[0], "type": store[1], "name": store[2]} if store else None
+            ),
+            **LayerAttribution.from_api(layer_data.get("attribution")).to_dict(),
         }
 
+    def get_layer_bounds(self, workspace: str, layer: str) -> dict[str, Any]:
+        """Get a layer's native and lat/lon bounding boxes.
+
+        Args:
+            workspace: Workspace name
+            layer: Layer name
+
+        Returns:
+            Dictionary with nativeBoundingBox and latLonBoundingBox (either may be None)
+        """
+        layer_data = self.get_layer(workspace, layer)
+        resource = layer_data.get("resource", {})
+        resource_class = resource.get("@class", "")
+        resource_href = resource.get("href", "")
+
+        bounds: dict[str, Any] = {"nativeBoundingBox": None, "latLonBoundingBox": None}
+        if not resource_href:
+            return bounds
+
+        key = "featureType" if "featureType" in resource_class else "coverage"
+        try:
+            data = self.get_href(resource_href).get(key, {})
+            bounds["nativeBoundingBox"] = data.get("nativeBoundingBox")
+            bounds["latLonBoundingBox"] = data.get("latLonBoundingBox")
+        except Exception:
+            pass
+
+        return bounds
+
+    def get_layer_srs(self, workspace: str, layer: str) -> str | None:
+        """Get the declared SRS of a layer, e.g. EPSG:4326.
+
+        Args:
+            workspace: Workspace name
+            layer: Layer name
+
+        Returns:
+            The SRS, or None if the layer's resource can't be read
+        """
+        layer_data = self.get_layer(workspace, layer)
+        resource = layer_data.get("resource", {})
+        resource_href = resource.get("href", "")
+        if not resource_href:
+            return None
+
+        key = "featureType" if "featureType" in resource.get("@class", "") else "coverage"
+        try:
+            return self.get_href(resource_href).get(key, {}).get("srs")
+        except Exception:
+            return None
+
+    def update_layer_resource(self, workspace: str, layer: str, updates: dict[str, Any]) -> None:
+        """Update the feature type or coverage a layer publishes.
+
+        Args:
+            workspace: Workspace name
+            layer: Layer name
+            updates: Resource fields to update, e.g. title, abstract and keywords
+
+        Raises:
+            GeoServerError: If the layer doesn't publish a feature type or coverage
+        """
+        resource = self.get_layer(workspace, layer).get("resource", {})
+        store = store_from_href(resource.get("href", ""))
+        name = resource.get("name", layer).split(":")[-1]
+        if store and store[1] == "datastores":
+            self.update_featuretype(store[0], store[2], name, updates)
+        elif store and store[1] == "coveragestores":
+            self.update_coverage(store[0], store[2], name, updates)
+        else:
+            raise GeoServerError(
+                f"{layer} doesn't publish a feature type or coverage", status_code=400
+            )
+
+    def get_layer_attribution(self, workspace: str, layer: str) -> LayerAttribution:
+        """Get the attribution a layer's capabilities entry shows.
+
+        Args:
+            workspace: Workspace name
+            layer: Layer name
+        """
+        return LayerAttribution.from_api(self.get_layer(workspace, layer).get("attribution"))
+
+    def update_layer_attribution(
+        self, workspace: str, layer: str, attribution: LayerAttribution
+    ) -> None:
+        """Set a layer's attribution, clearing the fields left unset.
+
+        Args:
+            workspace: Workspace name
+            layer: Layer name
+            attribution: Attribution to set
+        """
+        response = self._request(
+            "PUT",
+            f"/rest/workspaces/{workspace}/layers/{layer}.json",
+            json={"layer": {"attribution": attribution.to_api()}},
+        )
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to update attribution of {layer}: {response.text}",
+                status_code=response.status_code,
+            )
+        self._changed("layer", workspace, layer)
+
     # === File Uploads ===
 
     def upload_shapefile(
@@ -969,77 +2144,127 @@ class GeoServerClient:
         datastore: str,
         data: bytes,
         charset: str = "UTF-8",
+        update: str | None = None,
+        configure: str = "first",
     ) -> None:
         """Upload a shapefile ZIP to create a data store.
 
+        A published shapefile gets its workspace's default style for its
+        geometry type, if the config names one.
+
         Args:
             workspace: Workspace name
-            datastore: Data store name to create
+            datastore: Data store name to create, or an existing one
             data: ZIP file bytes containing shapefile
             charset: Character encoding
+            update: "append" or "overwrite" for data going into an existing store
+            configure: "first" to publish the shapefile or "none" to leave it
+                to be published later
         """
+        styled = configure != "none" and bool(rules_for(self.connection.id, workspace))
+        before = self._featuretype_names(workspace, datastore) if styled and update else set()
+        params = {"charset": charset}
+        if configure != "first":
+            params["configure"] = configure
+        if update:
+            params["update"] = update
         response = self._request(
             "PUT",
             f"/rest/workspaces/{workspace}/datastores/{datastore}/file.shp",
-            content=data,
-            headers={"Content-Type": "application/zip"},
-            params={"charset": charset},
+            params=params,
+            **upload_body(data, {"Content-Type": "application/zip"}),
         )
         if response.status_code >= 400:
             raise GeoServerError(
                 f"Failed to upload shapefile: {response.text}",
                 status_code=response.status_code,
             )
+        if not update:
+            managed_state.add(
+                self.connection.id, "datastore", workspace, datastore, content_hash(data)
+            )
+        if styled:
+            self._style_uploaded_featuretypes(workspace, datastore, before)
 
     def upload_geotiff(
         self,
         workspace: str,
         coveragestore: str,
         data: bytes,
+        coverage_name: str | None = None,
     ) -> None:
         """Upload a GeoTIFF to create a coverage store.
 
+        The coverage is published with its workspace's default raster
+        style, if the config names one.
+
         Args:
             workspace: Workspace name
             coveragestore: Coverage store name to create
             data: GeoTIFF file bytes
+            coverage_name: Name of the published coverage (defaults to the store name)
         """
         response = self._request(
             "PUT",
             f"/rest/workspaces/{workspace}/coveragestores/{coveragestore}/file.geotiff",
-            content=data,
-            headers={"Content-Type": "image/tiff"},
+            params={"coverageName": coverage_name} if coverage_name else None,
+            **upload_body(data, {"Content-Type": "image/tiff"}),
         )
         if response.status_code >= 400:
             raise GeoServerError(
                 f"Failed to upload GeoTIFF: {response.text}",
                 status_code=response.status_code,
             )
+        managed_state.add(
+            self.connection.id, "coveragestore", workspace, coveragestore, content_hash(data)
+        )
+        layer = coverage_name or coveragestore
+        self._changed("layer", workspace, layer, added=True)
+        self._apply_default_style(workspace, layer, geometry="raster")
 
     def upload_geopackage(
         self,
         workspace: str,
         datastore: str,
         data: bytes,
+        configure: str = "first",
+        update: str | None = None,
     ) -> None:
         """Upload a GeoPackage to create a data store.
 
+        Tables it publishes get their workspace's default style for their
+        geometry type, if the config names one.
+
         Args:
             workspace: Workspace name
-            datastore: Data store name to create
+            datastore: Data store name to create, or an existing one
             data: GeoPackage file bytes
+            configure: "first" to publish the first table, "all" for every
+                table or "none" to leave them all to be published later
+            update: "append" or "overwrite" for data going into an existing store
         """
+        styled = configure != "none" and bool(rules_for(self.connection.id, workspace))
+        before = self._featuretype_names(workspace, datastore) if styled and update else set()
+        params = {"configure": configure}
+        if update:
+            params["update"] = update
         response = self._request(
             "PUT",
             f"/rest/workspaces/{workspace}/datastores/{datastore}/file.gpkg",
-            content=data,
-            headers={"Content-Type": "application/geopackage+sqlite3"},
+            params=params,
+            **upload_body(data, {"Content-Type": "application/geopackage+sqlite3"}),
         )
         if response.status_code >= 400:
             raise GeoServerError(
                 f"Failed to upload GeoPackage: {response.text}",
                 status_code=response.status_code,
             )
+        if not update:
+            managed_state.add(
+                self.connection.id, "datastore", workspace, datastore, content_hash(data)
+            )
+        if styled:
+            self._style_uploaded_featuretypes(workspace, datastore, before)
 
     # === Available (Unpublished) Feature Types ===
 
@@ -1062,7 +2287,7 @@ class GeoServerClient:
         )
 
         if response.status_code == 200:
-            data = response.json()
+            data = self._parse(response)
             feature_type_names = data.get("list", {}).get("string", [])
             # Handle single string case (GeoServer returns string instead of list for single item)
             if isinstance(feature_type_names, str):
@@ -1071,6 +2296,444 @@ class GeoServerClient:
 
         return []
 
+    # === Server Info ===
+
+    def get_about(self, node_url: str | None = None) -> dict[str, Any]:
+        """Get the version information of the server components.
+
+        Args:
+            node_url: Node of a cluster to ask instead of the primary
+
+        Returns:
+            The /rest/about/version document
+        """
+        return self._get_node_json("/rest/about/version.json", node_url)
+
+    def get_server_version(self) -> str | None:
+        """Get the GeoServer version.
+
+        Returns:
+            Version string such as "2.24.1", or None if it isn't reported
+        """
+        resources = self.get_about().get("about", {}).get("resource", [])
+        if isinstance(resources, dict):
+            resources = [resources]
+        for resource in resources:
+            if resource.get("@name") == "GeoServer":
+                return resource.get("Version")
+        return None
+
+    def get_manifests(self) -> list[dict[str, Any]]:
+        """Get the manifests of the jars the server loaded.
+
+        GeoServer serves them at /rest/about/manifest, one resource per jar.
+
+        Returns:
+            Manifest dictionaries, each named by its jar in "@name"
+        """
+        data = self._get_json("/rest/about/manifest.json")
+        resources = data.get("about", {}).get("resource", [])
+        return [resources] if isinstance(resources, dict) else resources
+
+    def get_installed_modules(self) -> list[InstalledModule]:
+        """Get the extensions and community modules installed on the server."""
+        return installed_modules(self.get_manifests())
+
+    def list_monitor_requests(self, since: datetime, count: int) -> list[dict[str, Any]]:
+        """List requests recorded by the Monitoring extension, newest first.
+
+        Args:
+            since: Oldest request start time to include
+            count: Maximum number of requests returned
+
+        Returns:
+            Request dictionaries (startTime, path, resources, ...)
+        """
+        data = self._get_json(
+            "/rest/monitor/requests.json",
+            params={
+                "from": since.strftime("%Y-%m-%dT%H:%M:%S"),
+                "order": "startTime;DESC",
+                "count": count,
+            },
+        )
+        if isinstance(data, dict):
+            data = data.get("requests") or data.get("request") or []
+        return [data] if isinstance(data, dict) else data
+
+    def get_global_settings(self) -> dict[str, Any]:
+        """Get the global settings (proxy base URL, charset, JAI, ...).
+
+        Returns:
+            The "global" object of /rest/settings
+        """
+        return self._get_json("/rest/settings.json").get("global", {})
+
+    def get_service_settings(self, service: str) -> dict[str, Any]:
+        """Get the global settings of an OGC service.
+
+        Args:
+            service: Service name: wms, wfs, wcs or wmts
+
+        Returns:
+            The service settings object
+        """
+        data = self._get_json(f"/rest/services/{service}/settings.json")
+        return data.get(service, {})
+
+    def update_service_settings(self, service: str, settings: dict[str, Any]) -> None:
+        """Change some global settings of an OGC service.
+
+        Args:
+            service: Service name: wms, wfs, wcs or wmts
+            settings: Settings to change, e.g. {"title": ..., "abstrct": ...};
+                those left out keep their values
+        """
+        response = self._request(
+            "PUT", f"/rest/services/{service}/settings.json", json={service: settings}
+        )
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to update {service.upper()} settings: {response.text}",
+                status_code=response.status_code,
+            )
+
+    def get_contact(self) -> dict[str, Any]:
+        """Get the server's contact information, shown in every capabilities document."""
+        return self._get_json("/rest/settings/contact.json").get("contact", {})
+
+    def update_contact(self, contact: dict[str, Any]) -> None:
+        """Change the server's contact information.
+
+        Args:
+            contact: GeoServer contact fields to change, e.g. contactPerson,
+                contactOrganization and contactEmail; those left out keep their values
+        """
+        response = self._request("PUT", "/rest/settings/contact.json", json={"contact": contact})
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to update contact: {response.text}",
+                status_code=response.status_code,
+            )
+
+    def get_wms_watermark(self) -> Watermark:
+        """Get the watermark the WMS service draws on maps."""
+        return Watermark.from_api(self.get_service_settings("wms").get("watermark"))
+
+    def update_wms_watermark(self, watermark: Watermark) -> None:
+        """Set the watermark the WMS service draws on maps.
+
+        Args:
+            watermark: Watermark settings
+        """
+        response = self._request(
+            "PUT",
+            "/rest/services/wms/settings.json",
+            json={"wms": {"watermark": watermark.to_api()}},
+        )
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to update the WMS watermark: {response.text}",
+                status_code=response.status_code,
+            )
+
+    def update_workspace_service(self, workspace: str, service: str, enabled: bool) -> None:
+        """Turn an OGC service on or off for a workspace's virtual services.
+
+        Args:
+            workspace: Workspace name
+            service: Service name: wms, wfs, wcs or wmts
+            enabled: Whether the workspace offers the service
+        """
+        payload = {
+            service: {"workspace": {"name": workspace}, "name": service.upper(), "enabled": enabled}
+        }
+        response = self._request(
+            "PUT", f"/rest/services/{service}/workspaces/{workspace}/settings.json", json=payload
+        )
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to update {service.upper()} settings of {workspace}: {response.text}",
+                status_code=response.status_code,
+            )
+
+    def update_workspace_contact(self, workspace: str, contact: dict[str, str]) -> None:
+        """Set the contact information a workspace's capabilities documents show.
+
+        Args:
+            workspace: Workspace name
+            contact: GeoServer contact fields, e.g. contactPerson, contactOrganization
+                and contactEmail
+        """
+        payload = {"settings": {"workspace": {"name": workspace}, "contact": contact}}
+        response = self._request("PUT", f"/rest/workspaces/{workspace}/settings.json", json=payload)
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to update contact of {workspace}: {response.text}",
+                status_code=response.status_code,
+            )
+
+    @property
+    def capabilities(self) -> ServerCapabilities:
+        """Features supported by the server, read once per client."""
+        if self._capabilities is None:
+            try:
+                version = self.get_server_version()
+            except GeoServerError:
+                version = None
+            try:
+                extensions = extension_features(self.get_manifests())
+            except GeoServerError:
+                extensions = None
+            self._capabilities = build_capabilities(
+                version, self.connection.capability_overrides, extensions
+            )
+        return self._capabilities
+
+    @property
+    def permissions(self) -> ServerPermissions:
+        """What the connection's credentials may do, probed once per client."""
+        if self._permissions is None:
+            self._permissions = probe_permissions(self)
+        return self._permissions
+
+    @property
+    def importer(self) -> ImporterClient:
+        """Client for the Importer extension, sharing this connection."""
+        if self._importer is None:
+            self._importer = ImporterClient(self)
+        return self._importer
+
+    @property
+    def backup_restore(self) -> BackupRestoreClient:
+        """Client for the Backup and Restore plugin, sharing this connection."""
+        if self._backup_restore is None:
+            self._backup_restore = BackupRestoreClient(self)
+        return self._backup_restore
+
+    @property
+    def geofence(self) -> GeofenceClient:
+        """Client for the GeoFence rule API, sharing this connection."""
+        if self._geofence is None:
+            self._geofence = GeofenceClient(self)
+        return self._geofence
+
+    def get_system_status(self, node_url: str | None = None) -> list[dict[str, Any]]:
+        """Get system status metrics (CPU, memory, disk).
+
+        Args:
+            node_url: Node of a cluster to ask instead of the primary
+
+        Returns:
+            List of metric dictionaries
+
+        Raises:
+            GeoServerError: If the server doesn't support system status
+        """
+        if not self.capabilities.supports("system_status"):
+            raise GeoServerError(
+                "System status requires GeoServer 2.16 or newer", status_code=501
+            )
+        data = self._get_node_json("/rest/about/system-status.json", node_url)
+        metrics = data.get("metrics", {}).get("metric", [])
+        return metrics if isinstance(metrics, list) else [metrics]
+
+    def _node_request(self, method: str, path: str, node_url: str | None) -> httpx.Response:
+        """Make a REST request to one node of a cluster, or to the primary.
+
+        Nodes share the connection's credentials, limits and HTTP client.
+        """
+        if node_url is None:
+            return self._request(method, path)
+        check_writable(self.connection, method, path)
+        with self._open(method, f"{node_url.rstrip('/')}{path}") as response:
+            return read_limited(response, self.connection.max_response_mb)
+
+    def _get_node_json(self, path: str, node_url: str | None) -> dict[str, Any]:
+        """Make a GET request to one node of a cluster, or the primary, and return JSON."""
+        if node_url is None:
+            return self._get_json(path)
+        response = self._node_request("GET", path, node_url)
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Request to {node_url} failed: {response.text}",
+                status_code=response.status_code,
+            )
+        return self._parse(response)
+
+    def reload_catalog(self, node_url: str | None = None) -> None:
+        """Reload the catalog and configuration from the data directory.
+
+        Each node of a cluster reloads separately; see apps.geoserver.cluster.
+
+        Args:
+            node_url: Node of a cluster to reload instead of the primary
+        """
+        response = self._node_request("POST", "/rest/reload", node_url)
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to reload the catalog: {response.text}",
+                status_code=response.status_code,
+            )
+
+    def reset_caches(self, node_url: str | None = None) -> None:
+        """Drop the store, raster and schema caches, without reloading the catalog.
+
+        Args:
+            node_url: Node of a cluster to reset instead of the primary
+        """
+        response = self._node_request("POST", "/rest/reset", node_url)
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to reset caches: {response.text}",
+                status_code=response.status_code,
+            )
+
+    # === Resources ===
+
+    def get_resource_info(self, path: str) -> dict[str, Any]:
+        """Get the type and size of a data directory resource with a HEAD request.
+
+        Args:
+            path: Path relative to the data directory; "" for its root
+
+        Returns:
+            Dictionary with type ("directory", "resource" or "undefined") and size in bytes
+
+        Raises:
+            GeoServerError: If the server doesn't support the resource API
+        """
+        if not self.capabilities.supports("resource_api"):
+            raise GeoServerError(
+                "The resource API requires GeoServer 2.9 or newer", status_code=501
+            )
+        response = self._request("HEAD", f"/rest/resource/{path.strip('/')}")
+        if response.status_code == 404:
+            return {"type": "undefined", "size": 0}
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Resource request failed: {response.status_code}",
+                status_code=response.status_code,
+            )
+        return {
+            "type": response.headers.get("Resource-Type", "resource"),
+            "size": int(response.headers.get("Content-Length") or 0),
+        }
+
+    def download_resource(self, path: str) -> bytes:
+        """Download a data directory file.
+
+        Args:
+            path: File path relative to the data directory
+
+        Returns:
+            File contents
+
+        Raises:
+            GeoServerError: If the file doesn't exist or can't be read
+        """
+        if not self.capabilities.supports("resource_api"):
+            raise GeoServerError(
+                "The resource API requires GeoServer 2.9 or newer", status_code=501
+            )
+        response = self._request(
+            "GET", f"/rest/resource/{path.strip('/')}", timeout=request_timeout("download")
+        )
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to download {path}: {response.status_code}",
+                status_code=response.status_code,
+            )
+        return response.content
+
+    def iter_resource(self, path: str) -> Iterator[bytes]:
+        """Stream a data directory file in pieces, without the response size limit.
+
+        Args:
+            path: File path relative to the data directory
+
+        Yields:
+            Pieces of the file contents
+
+        Raises:
+            GeoServerError: If the file doesn't exist or can't be read
+        """
+        if not self.capabilities.supports("resource_api"):
+            raise GeoServerError(
+                "The resource API requires GeoServer 2.9 or newer", status_code=501
+            )
+        yield from self._download(f"/rest/resource/{path.strip('/')}", path)
+
+    def list_resource_directory(self, path: str) -> list[str]:
+        """List the names of the children of a data directory folder.
+
+        Args:
+            path: Folder path relative to the data directory; "" for its root
+
+        Returns:
+            Child names
+        """
+        data = self._get_json(f"/rest/resource/{path.strip('/')}", params={"format": "json"})
+        children = data.get("ResourceDirectory", {}).get("children") or {}
+        entries = children.get("child", []) if isinstance(children, dict) else []
+        if isinstance(entries, dict):
+            entries = [entries]
+        return [entry["name"] for entry in entries if entry.get("name")]
+
+    def upload_resource(self, path: str, data: bytes, content_type: str) -> None:
+        """Write a data directory file, replacing any file at the path.
+
+        Args:
+            path: File path relative to the data directory
+            data: File contents
+            content_type: MIME type of the contents
+
+        Raises:
+            GeoServerError: If the file can't be written
+        """
+        if not self.capabilities.supports("resource_api"):
+            raise GeoServerError(
+                "The resource API requires GeoServer 2.9 or newer", status_code=501
+            )
+        response = self._request(
+            "PUT",
+            f"/rest/resource/{path.strip('/')}",
+            **upload_body(data, {"Content-Type": content_type}),
+        )
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to upload {path}: {response.text}",
+                status_code=response.status_code,
+            )
+
+    def move_resource(self, source: str, target: str) -> None:
+        """Move a data directory file.
+
+        Args:
+            source: Current path relative to the data directory
+            target: New path relative to the data directory
+
+        Raises:
+            GeoServerError: If the file doesn't exist or can't be moved
+        """
+        if not self.capabilities.supports("resource_api"):
+            raise GeoServerError(
+                "The resource API requires GeoServer 2.9 or newer", status_code=501
+            )
+        # The body names the resource to move to the requested path
+        response = self._request(
+            "PUT",
+            f"/rest/resource/{target.strip('/')}",
+            params={"operation": "move"},
+            content=f"/{source.strip('/')}".encode(),
+            headers={"Content-Type": "text/plain"},
+        )
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to move {source} to {target}: {response.text}",
+                status_code=response.status_code,
+            )
+
 
 class GeoServerClientManager:
     """Thread-safe manager for GeoServer clients."""
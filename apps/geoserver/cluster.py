@@ -0,0 +1,203 @@
+"""Nodes of a clustered GeoServer: health, catalog reload and cache reset.
+
+A cluster connection lists the URLs of its nodes in cluster_urls; the
+catalog is changed through one of them, the primary. GeoServer nodes
+sharing a data directory each keep their own copy of the catalog in
+memory, so a change made on the data directory, or through one node,
+only shows on the others once they reload. Reload and reset are sent to
+every node at once, and each node's outcome is reported; a connection
+without cluster URLs is a single node at its url.
+"""
+
+import time
+from concurrent.futures import ThreadPoolExecutor
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Action -> what it does, for messages
+ACTIONS = {
+    "reload": "Reload the catalog and configuration from the data directory",
+    "reset": "Drop the store, raster and schema caches",
+}
+
+# Nodes sent an action at once
+MAX_WORKERS = 8
+
+COLUMNS = ["url", "ok", "seconds", "error"]
+
+# System status metrics read for a node's health
+MEMORY_USED = "MEMORY_USED"
+MEMORY_TOTAL = "MEMORY_TOTAL"
+CPU_LOAD = "CPU_LOAD"
+
+
+@dataclass
+class NodeResult:
+    """Outcome of an action on one node."""
+
+    url: str
+    ok: bool
+    seconds: float = 0.0
+    error: str = ""
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "url": self.url,
+            "ok": self.ok,
+            "seconds": round(self.seconds, 3),
+            "error": self.error,
+        }
+
+
+@dataclass
+class ClusterResult:
+    """Outcome of an action on every node of a connection."""
+
+    connection_id: str
+    action: str
+    nodes: list[NodeResult] = field(default_factory=list)
+
+    @property
+    def ok(self) -> bool:
+        """Whether every node succeeded."""
+        return all(node.ok for node in self.nodes)
+
+    @property
+    def failed(self) -> list[NodeResult]:
+        return [node for node in self.nodes if not node.ok]
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "connectionId": self.connection_id,
+            "action": self.action,
+            "ok": self.ok,
+            "nodes": [node.to_dict() for node in self.nodes],
+        }
+
+
+@dataclass
+class NodeHealth:
+    """Whether a node answers, and how loaded it is."""
+
+    url: str
+    primary: bool = False
+    online: bool = False
+    response_time_ms: int = 0
+    memory_used: int = 0
+    memory_total: int = 0
+    cpu_load: float = 0.0  # Percent
+    error: str = ""
+    status_error: str = ""  # Why memory and CPU couldn't be read from an online node
+
+    @property
+    def memory_used_pct(self) -> float:
+        if not self.memory_total:
+            return 0.0
+        return round(self.memory_used * 100 / self.memory_total, 1)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "url": self.url,
+            "primary": self.primary,
+            "online": self.online,
+            "responseTimeMs": self.response_time_ms,
+            "memoryUsed": self.memory_used,
+            "memoryTotal": self.memory_total,
+            "memoryUsedPct": self.memory_used_pct,
+            "cpuLoad": self.cpu_load,
+            "error": self.error,
+            "statusError": self.status_error,
+        }
+
+
+def metric_values(metrics: list[dict[str, Any]]) -> dict[str, float]:
+    """Numeric values of available system status metrics, by name."""
+    values = {}
+    for metric in metrics:
+        if not metric.get("available", True):
+            continue
+        try:
+            values[metric.get("name", "")] = float(metric.get("value", ""))
+        except (TypeError, ValueError):
+            continue
+    return values
+
+
+def check_node(client: "GeoServerClient", url: str | None, primary: bool) -> NodeHealth:
+    """Time a request to a node and read its memory and CPU load.
+
+    Args:
+        client: GeoServer client of the connection
+        url: Node URL, or None for the connection's only server
+        primary: Whether the node is the one catalog changes go to
+    """
+    health = NodeHealth(url or client.connection.url.rstrip("/"), primary)
+    start = time.monotonic()
+    try:
+        client.get_about(node_url=url)
+    except GeoServerError as e:
+        health.error = e.message
+        return health
+    health.online = True
+    health.response_time_ms = int((time.monotonic() - start) * 1000)
+
+    try:
+        values = metric_values(client.get_system_status(node_url=url))
+    except GeoServerError as e:
+        health.status_error = e.message
+        return health
+    health.memory_used = int(values.get(MEMORY_USED, 0))
+    health.memory_total = int(values.get(MEMORY_TOTAL, 0))
+    health.cpu_load = round(values.get(CPU_LOAD, 0.0), 1)
+    return health
+
+
+def node_health(client: "GeoServerClient") -> list[NodeHealth]:
+    """Check every node of a connection at once, primary first."""
+    connection = client.connection
+    if not connection.is_cluster:
+        return [check_node(client, None, True)]
+    urls = [connection.primary_url] + connection.secondary_urls
+    with ThreadPoolExecutor(max_workers=max(1, min(MAX_WORKERS, len(urls)))) as executor:
+        return list(
+            executor.map(lambda url: check_node(client, url, url == connection.primary_url), urls)
+        )
+
+
+def run_on_nodes(client: "GeoServerClient", action: str) -> ClusterResult:
+    """Reload or reset every node of a connection.
+
+    A failed node doesn't stop the others.
+
+    Args:
+        client: GeoServer client of the connection
+        action: "reload" or "reset"
+
+    Raises:
+        ValueError: If the action is unknown
+    """
+    if action not in ACTIONS:
+        raise ValueError(f"Unknown action: {action} (use {' or '.join(ACTIONS)})")
+    send = client.reload_catalog if action == "reload" else client.reset_caches
+    clustered = client.connection.is_cluster
+    urls = client.connection.node_urls
+
+    def run(url: str) -> NodeResult:
+        start = time.monotonic()
+        try:
+            send(node_url=url if clustered else None)
+        except GeoServerError as e:
+            return NodeResult(url, False, time.monotonic() - start, e.message)
+        return NodeResult(url, True, time.monotonic() - start)
+
+    with ThreadPoolExecutor(max_workers=max(1, min(MAX_WORKERS, len(urls)))) as executor:
+        nodes = list(executor.map(run, urls))
+    return ClusterResult(client.connection.id, action, nodes)
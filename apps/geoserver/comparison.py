@@ -0,0 +1,202 @@
+"""Comparing the configuration of several GeoServers.
+
+Before syncing catalogs between servers it helps to know where they
+differ: a layer that relies on an extension, a WFS feature limit or a
+proxy base URL behaves differently on a server that doesn't share it.
+A snapshot reads a server's component versions, installed extensions,
+global settings and OGC service settings into flat key/value sections,
+and snapshots of several servers are lined up into a matrix whose rows
+flag the settings that differ. Titles, abstracts, keywords and contact
+details are left out since they are expected to differ.
+"""
+
+from concurrent.futures import ThreadPoolExecutor
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+from .client import get_geoserver_client
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# OGC services whose settings are compared
+SERVICES = ("wms", "wfs", "wcs", "wmts")
+
+# Sections in the order they're shown
+SECTIONS = ("Server", "Extensions", "Global") + tuple(s.upper() for s in SERVICES)
+
+# Descriptive settings that are expected to differ between servers
+IGNORED_KEYS = {
+    "id",
+    "name",
+    "title",
+    "abstrct",
+    "keywords",
+    "metadata",
+    "metadataLink",
+    "onlineResource",
+    "contact",
+    "fees",
+    "accessConstraints",
+    "workspace",
+}
+
+# Snapshots taken at the same time
+MAX_WORKERS = 4
+
+
+def _text(value: Any) -> str:
+    """Render a settings value so equal settings compare equal."""
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    if isinstance(value, dict):
+        return ", ".join(_text(v) for v in value.values())
+    if isinstance(value, list):
+        return ", ".join(_text(v) for v in value)
+    return "" if value is None else str(value)
+
+
+def flatten_settings(settings: dict[str, Any], prefix: str = "") -> dict[str, str]:
+    """Flatten a settings document into dotted keys, e.g. "jai.memoryCapacity".
+
+    Lists are joined into one value, so the WMS versions compare as "1.1.1, 1.3.0".
+    """
+    flat: dict[str, str] = {}
+    for key, value in settings.items():
+        if key in IGNORED_KEYS or key.startswith("@"):
+            continue
+        path = f"{prefix}{key}"
+        if isinstance(value, dict):
+            flat.update(flatten_settings(value, f"{path}."))
+        else:
+            flat[path] = _text(value)
+    return flat
+
+
+@dataclass
+class ServerSnapshot:
+    """The compared settings of one server."""
+
+    connection_id: str
+    name: str
+    sections: dict[str, dict[str, str]] = field(default_factory=dict)
+    errors: dict[str, str] = field(default_factory=dict)  # Section -> why it couldn't be read
+
+
+def take_snapshot(client: "GeoServerClient") -> ServerSnapshot:
+    """Read the compared settings of a server.
+
+    Each section is read on its own; one that fails, like the settings of
+    a disabled service, is recorded as an error and the rest still read.
+    """
+    snapshot = ServerSnapshot(client.connection.id, client.connection.name)
+
+    def versions() -> dict[str, str]:
+        resources = client.get_about().get("about", {}).get("resource", [])
+        if isinstance(resources, dict):
+            resources = [resources]
+        return {r["@name"]: _text(r.get("Version")) for r in resources if r.get("@name")}
+
+    readers = {
+        "Server": versions,
+        "Extensions": lambda: {
+            m.label: m.version or "installed" for m in client.get_installed_modules()
+        },
+        "Global": lambda: flatten_settings(client.get_global_settings()),
+    }
+    for service in SERVICES:
+        readers[service.upper()] = lambda s=service: flatten_settings(
+            client.get_service_settings(s)
+        )
+
+    for section, read in readers.items():
+        try:
+            snapshot.sections[section] = read()
+        except GeoServerError as e:
+            snapshot.errors[section] = str(e)
+    return snapshot
+
+
+@dataclass
+class ComparisonRow:
+    """One setting across the compared servers."""
+
+    section: str
+    key: str
+    values: dict[str, str | None]  # Connection ID -> value; None if it couldn't be read
+
+    @property
+    def differs(self) -> bool:
+        """Whether the servers that reported the setting disagree on it."""
+        return len({v for v in self.values.values() if v is not None}) > 1
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "section": self.section,
+            "key": self.key,
+            "values": self.values,
+            "differs": self.differs,
+        }
+
+
+@dataclass
+class ServerComparison:
+    """Settings of several servers lined up side by side."""
+
+    connections: list[tuple[str, str]]  # (ID, name) in column order
+    rows: list[ComparisonRow] = field(default_factory=list)
+    errors: dict[str, dict[str, str]] = field(default_factory=dict)
+
+    @property
+    def differences(self) -> list[ComparisonRow]:
+        """Rows whose values differ."""
+        return [row for row in self.rows if row.differs]
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "connections": [{"id": cid, "name": name} for cid, name in self.connections],
+            "rows": [row.to_dict() for row in self.rows],
+            "differenceCount": len(self.differences),
+            "errors": self.errors,
+        }
+
+
+def compare_snapshots(snapshots: list[ServerSnapshot]) -> ServerComparison:
+    """Line up server snapshots into a comparison matrix.
+
+    A setting one server has and another lacks, like an extension
+    installed on only one of them, is compared as an empty value there.
+    """
+    comparison = ServerComparison(
+        connections=[(s.connection_id, s.name) for s in snapshots],
+        errors={s.connection_id: s.errors for s in snapshots if s.errors},
+    )
+    for section in SECTIONS:
+        keys = sorted(
+            {key for s in snapshots for key in s.sections.get(section, {})}, key=str.lower
+        )
+        for key in keys:
+            values: dict[str, str | None] = {}
+            for s in snapshots:
+                if section in s.errors:
+                    values[s.connection_id] = None
+                else:
+                    values[s.connection_id] = s.sections.get(section, {}).get(key, "")
+            comparison.rows.append(ComparisonRow(section, key, values))
+    return comparison
+
+
+def compare_servers(conn_ids: list[str]) -> ServerComparison:
+    """Compare the configuration of several connections.
+
+    Raises:
+        ValueError: If a connection doesn't exist
+    """
+    clients = [get_geoserver_client(conn_id) for conn_id in conn_ids]
+    with ThreadPoolExecutor(max_workers=MAX_WORKERS) as executor:
+        snapshots = list(executor.map(take_snapshot, clients))
+    return compare_snapshots(snapshots)
@@ -0,0 +1,101 @@
+"""Previewing a layer group's composition before it is saved.
+
+The layers of a draft group are drawn with a single GetMap request in
+the order they are listed, bottom to top as GeoServer draws a group, so
+the draw order can be checked before the group exists. The map covers
+all the layers, widened to the image's aspect ratio so nothing is
+stretched.
+"""
+
+from concurrent.futures import ThreadPoolExecutor
+from typing import TYPE_CHECKING
+
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Layers whose bounds are read at once
+MAX_WORKERS = 8
+
+# Largest preview side in pixels
+MAX_SIZE = 1024
+
+# Share of the extent added on each side, so edge features aren't cut off
+PADDING = 0.02
+
+BBox = tuple[float, float, float, float]
+
+
+def local_name(layer: str, workspace: str) -> str:
+    """A layer's name within the workspace, accepting workspace:layer names."""
+    prefix, _, name = layer.rpartition(":")
+    if prefix and prefix != workspace:
+        raise GeoServerError(f"Layer {layer} is not in workspace {workspace}", status_code=400)
+    return name
+
+
+def composition_bbox(client: "GeoServerClient", workspace: str, layers: list[str]) -> BBox:
+    """The lat/lon box covering all the layers.
+
+    Raises:
+        GeoServerError: If none of the layers has lat/lon bounds
+    """
+
+    def bounds(layer: str) -> dict | None:
+        try:
+            return client.get_layer_bounds(workspace, layer).get("latLonBoundingBox")
+        except GeoServerError:
+            return None  # The other layers still give an extent
+
+    with ThreadPoolExecutor(max_workers=max(1, min(MAX_WORKERS, len(layers)))) as executor:
+        boxes = [box for box in executor.map(bounds, layers) if box]
+    if not boxes:
+        raise GeoServerError("None of the layers has bounds to preview", status_code=400)
+    return (
+        min(float(b["minx"]) for b in boxes),
+        min(float(b["miny"]) for b in boxes),
+        max(float(b["maxx"]) for b in boxes),
+        max(float(b["maxy"]) for b in boxes),
+    )
+
+
+def fit_bbox(bbox: BBox, width: int, height: int) -> BBox:
+    """Pad a box and widen it to an image's aspect ratio, keeping its center."""
+    minx, miny, maxx, maxy = bbox
+    # A single point still needs an area to draw
+    span_x = max(maxx - minx, 1e-6) * (1 + 2 * PADDING)
+    span_y = max(maxy - miny, 1e-6) * (1 + 2 * PADDING)
+    if span_x / span_y < width / height:
+        span_x = span_y * width / height
+    else:
+        span_y = span_x * height / width
+    cx, cy = (minx + maxx) / 2, (miny + maxy) / 2
+    return (cx - span_x / 2, cy - span_y / 2, cx + span_x / 2, cy + span_y / 2)
+
+
+def render_composition(
+    client: "GeoServerClient", workspace: str, layers: list[str], width: int, height: int
+) -> bytes:
+    """Draw layers over each other in order, the first at the bottom.
+
+    Args:
+        client: GeoServer client
+        workspace: Workspace holding the layers
+        layers: Layer names, plain or as workspace:layer
+        width: Image width in pixels, at most MAX_SIZE
+        height: Image height in pixels, at most MAX_SIZE
+
+    Returns:
+        PNG image bytes
+
+    Raises:
+        GeoServerError: If the layers can't be placed or drawn
+    """
+    if not layers:
+        raise GeoServerError("No layers to preview", status_code=400)
+    width = min(max(1, width), MAX_SIZE)
+    height = min(max(1, height), MAX_SIZE)
+    names = [local_name(layer, workspace) for layer in layers]
+    bbox = fit_bbox(composition_bbox(client, workspace, names), width, height)
+    return client.get_map(workspace, names, bbox, width, height, srs="EPSG:4326")
@@ -0,0 +1,303 @@
+"""CQL filter building for vector layers.
+
+The builder lists a layer's attributes from DescribeFeatureType with the
+operators that suit each attribute's type, suggests values from a sample
+of the layer's features, and turns the chosen conditions into an ECQL
+expression. GeoServer checks the expression with a WFS hits request,
+which also says how many features it matches, so a filter is known to
+work before the map preview, attribute table or feature count use it.
+"""
+
+import re
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Any
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+    from .schema import FeatureTypeSchema, SchemaAttribute
+
+KIND_TEXT = "text"
+KIND_NUMBER = "number"
+KIND_DATE = "date"
+KIND_BOOLEAN = "boolean"
+KIND_GEOMETRY = "geometry"
+
+# Attribute local types compared as numbers rather than text
+NUMERIC_TYPES = {"int", "integer", "long", "short", "byte", "number", "double", "float", "decimal"}
+
+# Attribute local types holding dates or times
+DATE_TYPES = {"date", "datetime", "date-time", "time"}
+
+# Operators offered for each kind of attribute
+OPERATORS = {
+    KIND_TEXT: ["=", "<>", "ILIKE", "LIKE", "IN", "IS NULL", "IS NOT NULL"],
+    KIND_NUMBER: ["=", "<>", "<", "<=", ">", ">=", "BETWEEN", "IN", "IS NULL", "IS NOT NULL"],
+    KIND_DATE: ["=", "<", "<=", ">", ">=", "BETWEEN", "IS NULL", "IS NOT NULL"],
+    KIND_BOOLEAN: ["=", "<>", "IS NULL", "IS NOT NULL"],
+    KIND_GEOMETRY: ["BBOX"],
+}
+
+# Operators that take no value
+UNARY_OPERATORS = {"IS NULL", "IS NOT NULL"}
+
+MATCH_ALL = "all"
+MATCH_ANY = "any"
+
+# Most suggested values per attribute
+DEFAULT_SUGGESTIONS = 20
+
+# Features read to find suggested values
+SUGGESTION_SAMPLE = 500
+
+
+def attribute_kind(attribute: "SchemaAttribute") -> str:
+    """Classify a feature type attribute."""
+    if attribute.geometry:
+        return KIND_GEOMETRY
+    local_type = attribute.local_type.lower()
+    if local_type in NUMERIC_TYPES:
+        return KIND_NUMBER
+    if local_type in DATE_TYPES:
+        return KIND_DATE
+    if local_type == "boolean":
+        return KIND_BOOLEAN
+    return KIND_TEXT
+
+
+@dataclass
+class FilterAttribute:
+    """An attribute a filter condition can test."""
+
+    name: str
+    type: str
+    kind: str
+    nillable: bool = True
+
+    @property
+    def operators(self) -> list[str]:
+        # Null tests are pointless on attributes that can't be null
+        if self.nillable:
+            return OPERATORS[self.kind]
+        return [op for op in OPERATORS[self.kind] if op not in UNARY_OPERATORS]
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "name": self.name,
+            "type": self.type,
+            "kind": self.kind,
+            "nillable": self.nillable,
+            "operators": self.operators,
+        }
+
+
+@dataclass
+class Condition:
+    """One attribute test of a filter, e.g. population >= 1000."""
+
+    attribute: str
+    operator: str
+    value: str = ""
+
+    @classmethod
+    def from_dict(cls, data: dict[str, Any]) -> "Condition":
+        """Read a condition from an API request."""
+        return cls(
+            attribute=str(data.get("attribute", "")),
+            operator=str(data.get("operator", "")),
+            value=str(data.get("value") or ""),
+        )
+
+
+def filter_attributes(schema: "FeatureTypeSchema") -> list[FilterAttribute]:
+    """Read the attributes of a feature type schema."""
+    return [
+        FilterAttribute(
+            name=a.name,
+            type=a.local_type or a.type,
+            kind=attribute_kind(a),
+            nillable=a.nillable,
+        )
+        for a in schema.attributes
+    ]
+
+
+def describe_filter_attributes(
+    client: "GeoServerClient",
+    workspace: str,
+    layer: str,
+) -> list[FilterAttribute]:
+    """Fetch the attributes of a layer with the operators each one takes."""
+    return filter_attributes(client.describe_feature_type(workspace, layer))
+
+
+def quote_identifier(name: str) -> str:
+    """Quote an attribute name for ECQL."""
+    return '"' + name.replace('"', '""') + '"'
+
+
+def quote_literal(value: str) -> str:
+    """Quote a text literal for CQL."""
+    return "'" + value.replace("'", "''") + "'"
+
+
+def _literal(value: str, attribute: FilterAttribute) -> str:
+    value = value.strip()
+    if attribute.kind == KIND_NUMBER:
+        try:
+            float(value)
+        except ValueError:
+            raise ValueError(f"{attribute.name} needs a number, not '{value}'") from None
+        return value
+    if attribute.kind == KIND_BOOLEAN:
+        if value.lower() not in ("true", "false"):
+            raise ValueError(f"{attribute.name} needs true or false, not '{value}'")
+        return value.lower()
+    return quote_literal(value)
+
+
+def condition_cql(condition: Condition, attribute: FilterAttribute) -> str:
+    """Build the CQL of one condition.
+
+    BETWEEN takes two values separated by a comma or "and", IN a comma
+    separated list and BBOX "minx, miny, maxx, maxy". LIKE and ILIKE
+    match anywhere in the value unless it has % wildcards of its own.
+
+    Raises:
+        ValueError: If the operator doesn't suit the attribute or the value doesn't fit
+    """
+    operator = condition.operator.strip().upper()
+    if operator not in attribute.operators:
+        raise ValueError(f"{operator or 'No operator'} doesn't apply to {attribute.name}")
+    column = quote_identifier(attribute.name)
+    if operator in UNARY_OPERATORS:
+        return f"{column} {operator}"
+
+    value = condition.value.strip()
+    if not value:
+        raise ValueError(f"{attribute.name} {operator} needs a value")
+
+    if operator == "BETWEEN":
+        bounds = [v for v in re.split(r"\s*,\s*|\s+and\s+", value, flags=re.IGNORECASE) if v]
+        if len(bounds) != 2:
+            raise ValueError(f"{attribute.name} BETWEEN needs two values, e.g. 10, 20")
+        low, high = (_literal(v, attribute) for v in bounds)
+        return f"{column} BETWEEN {low} AND {high}"
+    if operator == "IN":
+        items = [_literal(v, attribute) for v in value.split(",") if v.strip()]
+        return f"{column} IN ({', '.join(items)})"
+    if operator == "BBOX":
+        coords = [v.strip() for v in value.split(",")]
+        try:
+            numbers = [float(v) for v in coords]
+        except ValueError:
+            numbers = []
+        if len(numbers) != 4:
+            raise ValueError(f"{attribute.name} BBOX needs minx, miny, maxx, maxy")
+        return f"BBOX({column}, {', '.join(coords)})"
+    if operator in ("LIKE", "ILIKE"):
+        pattern = value if "%" in value else f"%{value}%"
+        return f"{column} {operator} {quote_literal(pattern)}"
+    return f"{column} {operator} {_literal(value, attribute)}"
+
+
+def build_cql(
+    conditions: list[Condition],
+    attributes: list[FilterAttribute],
+    match: str = MATCH_ALL,
+) -> str | None:
+    """Combine conditions into one CQL expression.
+
+    Args:
+        conditions: Conditions to combine
+        attributes: Attributes of the layer
+        match: MATCH_ALL to AND the conditions, MATCH_ANY to OR them
+
+    Returns:
+        The expression, or None without conditions
+
+    Raises:
+        ValueError: If a condition names an unknown attribute or doesn't fit it
+    """
+    if match not in (MATCH_ALL, MATCH_ANY):
+        raise ValueError(f"Unknown match: {match}")
+    by_name = {a.name: a for a in attributes}
+    clauses = []
+    for condition in conditions:
+        if condition.attribute not in by_name:
+            raise ValueError(f"Unknown attribute: {condition.attribute}")
+        clauses.append(condition_cql(condition, by_name[condition.attribute]))
+    return (" AND " if match == MATCH_ALL else " OR ").join(clauses) or None
+
+
+def combine_filters(*filters: str | None) -> str | None:
+    """AND several CQL expressions, any of which may be missing."""
+    present = [f.strip() for f in filters if f and f.strip()]
+    if len(present) < 2:
+        return present[0] if present else None
+    return " AND ".join(f"({f})" for f in present)
+
+
+def suggest_values(
+    client: "GeoServerClient",
+    workspace: str,
+    layer: str,
+    attribute: str,
+    prefix: str = "",
+    limit: int = DEFAULT_SUGGESTIONS,
+    sample: int = SUGGESTION_SAMPLE,
+) -> list[str]:
+    """Suggest values of an attribute from a sample of the layer's features.
+
+    WFS has no distinct-values request, so the values are read from the
+    first features sorted by the attribute, narrowed to those starting
+    with the prefix.
+
+    Raises:
+        ValueError: If the attribute is unknown or a geometry
+        GeoServerError: If a WFS request fails
+    """
+    attributes = {a.name: a for a in describe_filter_attributes(client, workspace, layer)}
+    found = attributes.get(attribute)
+    if found is None:
+        raise ValueError(f"Unknown attribute: {attribute}")
+    if found.kind == KIND_GEOMETRY:
+        raise ValueError("Geometry attributes have no value suggestions")
+
+    cql = None
+    if prefix and found.kind == KIND_TEXT:
+        escaped = re.sub(r"([%_\\])", r"\\\1", prefix)
+        cql = f"{quote_identifier(attribute)} ILIKE {quote_literal(escaped + '%')}"
+    data = client.get_features(
+        workspace,
+        layer,
+        count=sample,
+        sort_by=f"{attribute} ASC",
+        cql_filter=cql,
+        property_names=[attribute],
+    )
+
+    values: dict[str, None] = {}
+    for feature in data.get("features") or []:
+        value = (feature.get("properties") or {}).get(attribute)
+        if value is None:
+            continue
+        text = str(value).lower() if isinstance(value, bool) else str(value)
+        if prefix and not text.lower().startswith(prefix.lower()):
+            continue
+        values[text] = None
+        if len(values) >= limit:
+            break
+    return list(values)
+
+
+def validate_cql(client: "GeoServerClient", workspace: str, layer: str, cql: str) -> int:
+    """Check a CQL expression against a layer.
+
+    Returns:
+        Number of features the expression matches
+
+    Raises:
+        GeoServerError: If GeoServer rejects the expression
+    """
+    return client.get_layer_feature_count(workspace, layer, cql_filter=cql)
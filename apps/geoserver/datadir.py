@@ -0,0 +1,94 @@
+"""Data directory size through the REST resource API.
+
+GeoServer doesn't report how much disk its data directory uses, but the
+resource API lets a client walk it and read each file's size. A data
+directory can hold many thousands of files, mostly cached tiles, so the
+walk stops after a request budget and reports a lower bound instead of
+keeping the dashboard waiting.
+"""
+
+from collections import deque
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Requests made before a walk gives up and reports a partial size
+DEFAULT_REQUEST_BUDGET = 500
+
+
+@dataclass
+class DirectorySize:
+    """Size of a data directory folder."""
+
+    bytes: int = 0
+    files: int = 0
+    directories: int = 0
+    complete: bool = True  # False when the walk ran out of requests
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "bytes": self.bytes,
+            "files": self.files,
+            "directories": self.directories,
+            "complete": self.complete,
+        }
+
+
+def directory_size(
+    client: "GeoServerClient",
+    path: str = "",
+    budget: int = DEFAULT_REQUEST_BUDGET,
+) -> DirectorySize:
+    """Add up the sizes of the files under a data directory folder.
+
+    Folders are walked breadth first, so a partial result covers the
+    shallow configuration files before the deep tile cache.
+
+    Args:
+        client: GeoServer client
+        path: Folder relative to the data directory; the root when empty
+        budget: Maximum number of requests to make
+
+    Returns:
+        The size found, with complete=False if the budget ran out
+
+    Raises:
+        GeoServerError: If the resource API isn't available
+    """
+    size = DirectorySize()
+    pending = deque([path.strip("/")])
+    requests = 0
+
+    while pending:
+        folder = pending.popleft()
+        if requests >= budget:
+            size.complete = False
+            break
+        requests += 1
+        try:
+            children = client.list_resource_directory(folder)
+        except GeoServerError:
+            if not folder:
+                raise  # No resource API at all
+            continue  # Removed or unreadable while walking
+        size.directories += 1
+
+        for name in children:
+            if requests >= budget:
+                size.complete = False
+                break
+            child = f"{folder}/{name}" if folder else name
+            requests += 1
+            info = client.get_resource_info(child)
+            if info["type"] == "directory":
+                pending.append(child)
+            elif info["type"] == "resource":
+                size.files += 1
+                size.bytes += info["size"]
+
+    return size
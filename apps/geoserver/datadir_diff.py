@@ -0,0 +1,212 @@
+"""What a change made to the data directory's configuration files.
+
+The REST API doesn't always store what it is sent: GeoServer normalizes
+some fields, fills in defaults and silently drops others it doesn't
+know. Reading the configuration files it wrote is the only way to see
+what a change really did, so the XML files of the folders a change
+touches are read through the resource API before and after it, and
+compared.
+
+Reading a file is a request, so a snapshot stops after a request budget
+and the comparison is then marked incomplete.
+"""
+
+import difflib
+from collections import deque
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Files compared; data files such as shapefiles and GeoTIFFs are skipped
+CONFIG_SUFFIXES = (".xml", ".sld", ".css", ".json", ".properties")
+
+# Requests one snapshot may make
+DEFAULT_BUDGET = 300
+
+# Folders of configuration outside any workspace
+GLOBAL_FOLDERS = ["styles", "layergroups"]
+
+
+@dataclass
+class DataDirSnapshot:
+    """Configuration files of some data directory folders, by path."""
+
+    files: dict[str, str] = field(default_factory=dict)
+    complete: bool = True  # False when the budget ran out
+
+
+@dataclass
+class FileChange:
+    """A configuration file added, removed or changed."""
+
+    path: str
+    change: str  # "added", "removed" or "changed"
+    diff: str  # Unified diff of the file's contents
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {"path": self.path, "change": self.change, "diff": self.diff}
+
+
+@dataclass
+class DataDirDiff:
+    """Configuration files a change added, removed or changed."""
+
+    folders: list[str] = field(default_factory=list)
+    changes: list[FileChange] = field(default_factory=list)
+    complete: bool = True  # False when a snapshot ran out of requests
+    error: str | None = None  # Set when the files couldn't be read
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "folders": self.folders,
+            "changes": [change.to_dict() for change in self.changes],
+            "complete": self.complete,
+            "error": self.error,
+        }
+
+
+def take_snapshot(
+    client: "GeoServerClient",
+    folders: list[str],
+    shallow: list[str] | None = None,
+    budget: int = DEFAULT_BUDGET,
+) -> DataDirSnapshot:
+    """Read the configuration files under some data directory folders.
+
+    Args:
+        client: GeoServer client
+        folders: Folders walked with their subfolders
+        shallow: Folders whose own files are read, but not their subfolders;
+            "" for the data directory's root
+        budget: Maximum number of requests to make
+
+    Raises:
+        GeoServerError: If the resource API isn't available
+    """
+    snapshot = DataDirSnapshot()
+    pending = deque([(folder.strip("/"), True) for folder in folders])
+    pending.extend((folder.strip("/"), False) for folder in shallow or [])
+    requests = 0
+
+    # Fails early, rather than as a missing folder, without the resource API
+    client.get_resource_info("")
+
+    while pending:
+        folder, recursive = pending.popleft()
+        if requests >= budget:
+            snapshot.complete = False
+            break
+        requests += 1
+        try:
+            children = client.list_resource_directory(folder)
+        except GeoServerError:
+            continue  # Not created yet, or already removed
+
+        for name in children:
+            if requests >= budget:
+                snapshot.complete = False
+                break
+            path = f"{folder}/{name}" if folder else name
+            requests += 1
+            if name.lower().endswith(CONFIG_SUFFIXES):
+                try:
+                    content = client.download_resource(path)
+                except GeoServerError:
+                    continue
+                snapshot.files[path] = content.decode("utf-8", errors="replace")
+            elif recursive and client.get_resource_info(path)["type"] == "directory":
+                pending.append((path, True))
+
+    return snapshot
+
+
+def file_diff(path: str, before: str, after: str) -> str:
+    """Unified diff of a file's contents."""
+    return "\n".join(
+        difflib.unified_diff(
+            before.splitlines(),
+            after.splitlines(),
+            fromfile=f"a/{path}",
+            tofile=f"b/{path}",
+            lineterm="",
+        )
+    )
+
+
+def compare_snapshots(before: DataDirSnapshot, after: DataDirSnapshot) -> list[FileChange]:
+    """Files added, removed or changed between two snapshots, by path."""
+    changes = []
+    for path in sorted(before.files.keys() | after.files.keys()):
+        old, new = before.files.get(path), after.files.get(path)
+        if old == new:
+            continue
+        if old is None:
+            change = "added"
+        elif new is None:
+            change = "removed"
+        else:
+            change = "changed"
+        changes.append(FileChange(path, change, file_diff(path, old or "", new or "")))
+    return changes
+
+
+class DataDirWatch:
+    """Snapshots taken around a change, compared into a DataDirDiff.
+
+    Usage:
+        watch = DataDirWatch(client, ["workspaces/topp"])
+        watch.start()
+        client.update_layer(...)
+        diff = watch.finish()
+    """
+
+    def __init__(
+        self,
+        client: "GeoServerClient",
+        folders: list[str],
+        shallow: list[str] | None = None,
+        budget: int = DEFAULT_BUDGET,
+    ):
+        """Initialize the watch.
+
+        Args:
+            client: GeoServer client
+            folders: Folders walked with their subfolders
+            shallow: Folders whose own files are read, but not their subfolders
+            budget: Maximum number of requests each snapshot may make
+        """
+        self.client = client
+        self.folders = folders
+        self.shallow = shallow or []
+        self.budget = budget
+        self.diff = DataDirDiff(folders=folders + [f for f in self.shallow if f not in folders])
+        self._before: DataDirSnapshot | None = None
+
+    def _snapshot(self) -> DataDirSnapshot:
+        return take_snapshot(self.client, self.folders, self.shallow, self.budget)
+
+    def start(self) -> None:
+        """Read the files before the change; a failure is reported, not raised."""
+        try:
+            self._before = self._snapshot()
+        except GeoServerError as e:
+            self.diff.error = e.message
+
+    def finish(self) -> DataDirDiff:
+        """Read the files after the change and compare them with before."""
+        if self._before is None:
+            return self.diff
+        try:
+            after = self._snapshot()
+        except GeoServerError as e:
+            self.diff.error = e.message
+            return self.diff
+        self.diff.changes = compare_snapshots(self._before, after)
+        self.diff.complete = self._before.complete and after.complete
+        return self.diff
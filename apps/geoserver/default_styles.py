@@ -0,0 +1,105 @@
+"""Default styles of newly published layers.
+
+GeoServer gives a new layer one of its generic point, line, polygon or
+raster styles. The rules in the config file's "default_styles" choose
+the style per workspace instead: when a feature type or coverage is
+published, the layer gets the style its workspace's rule names for its
+geometry type, falling back to the rule for "*". The rules can be
+edited in the config file, the web UI's settings and the TUI.
+"""
+
+from typing import Any
+
+from apps.core.config import DefaultStyleRule, config_manager
+
+# Geometry types a rule names a style for
+GEOMETRY_TYPES = ("point", "line", "polygon", "raster")
+
+# Workspace of rules applying to workspaces without their own
+ANY_WORKSPACE = "*"
+
+# JTS geometry class -> geometry type
+GEOMETRY_CLASSES = {
+    "Point": "point",
+    "MultiPoint": "point",
+    "LineString": "line",
+    "LinearRing": "line",
+    "MultiLineString": "line",
+    "CircularString": "line",
+    "CompoundCurve": "line",
+    "MultiCurve": "line",
+    "Polygon": "polygon",
+    "MultiPolygon": "polygon",
+    "CurvePolygon": "polygon",
+    "MultiSurface": "polygon",
+}
+
+
+def rules_for(
+    conn_id: str, workspace: str, rules: list[DefaultStyleRule] | None = None
+) -> list[DefaultStyleRule]:
+    """The rules for a workspace on a connection, its own before those for "*".
+
+    Args:
+        conn_id: Connection ID
+        workspace: Workspace the layer is published in
+        rules: Rules to pick from; the configured ones when None
+    """
+    if rules is None:
+        rules = config_manager.config.default_styles
+    applying = [r for r in rules if not r.connections or conn_id in r.connections]
+    return [r for r in applying if r.workspace == workspace] + [
+        r for r in applying if r.workspace == ANY_WORKSPACE
+    ]
+
+
+def style_for(rules: list[DefaultStyleRule], geometry: str | None) -> str | None:
+    """The style the first rule naming one gives a geometry type."""
+    if geometry not in GEOMETRY_TYPES:
+        return None
+    return next((getattr(r, geometry) for r in rules if getattr(r, geometry)), None)
+
+
+def feature_type_geometry(featuretype: dict[str, Any]) -> str | None:
+    """The geometry type of a feature type's first geometry attribute.
+
+    Args:
+        featuretype: Feature type details from the REST API, whose
+            attributes have the Java class of their values as "binding"
+
+    Returns:
+        "point", "line" or "polygon", or None for no or a generic geometry
+    """
+    attributes = (featuretype.get("attributes") or {}).get("attribute", [])
+    if isinstance(attributes, dict):
+        attributes = [attributes]
+    for attribute in attributes:
+        geometry = GEOMETRY_CLASSES.get(str(attribute.get("binding", "")).rsplit(".", 1)[-1])
+        if geometry:
+            return geometry
+    return None
+
+
+def parse_rules(data: list[dict[str, Any]]) -> list[DefaultStyleRule]:
+    """Rules edited in the web UI or TUI, checked before they're saved.
+
+    Raises:
+        ValueError: If a rule has no workspace or names no style
+        TypeError: If a rule isn't an object
+    """
+    rules = []
+    for item in data:
+        rule = DefaultStyleRule(**item)
+        rule.workspace = rule.workspace.strip()
+        if not rule.workspace:
+            raise ValueError(f'A rule needs a workspace, or "{ANY_WORKSPACE}" for any')
+        if not any(getattr(rule, geometry) for geometry in GEOMETRY_TYPES):
+            raise ValueError(f"The rule for {rule.workspace} names no style")
+        rules.append(rule)
+    return rules
+
+
+def save_rules(rules: list[DefaultStyleRule]) -> None:
+    """Replace the configured rules and write the config file."""
+    config_manager.config.default_styles = rules
+    config_manager.save()
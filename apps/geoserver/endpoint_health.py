@@ -0,0 +1,201 @@
+"""Check a layer's OGC endpoints the way clients use them.
+
+A layer can look fine in the REST API and still fail its clients: WMS or
+WFS may be disabled for its workspace, its store may no longer reach the
+data, or its declared SRS may not transform. Each endpoint is sent one
+lightweight request like those of its clients:
+
+- WMS: a 1x1 pixel GetMap at the centre of the layer, in EPSG:4326 and
+  again in the layer's own SRS;
+- WFS: a GetFeature hits request, for vector layers;
+- WMTS: a sample tile from GeoWebCache, for cached layers;
+- the legend: a GetLegendGraphic request.
+
+Each endpoint passes, fails with the reason GeoServer gave, or is
+skipped when the layer doesn't offer it.
+"""
+
+import time
+from collections.abc import Callable
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+from apps.gwc.endpoints import check_endpoints
+
+if TYPE_CHECKING:
+    from apps.gwc.client import GWCClient
+
+    from .client import GeoServerClient
+
+PASS = "pass"
+FAIL = "fail"
+SKIP = "skip"
+
+# Endpoints checked, in order, with their labels
+SERVICES = {
+    "wms": "WMS GetMap (EPSG:4326)",
+    "wms-native": "WMS GetMap (native SRS)",
+    "wfs": "WFS hits",
+    "wmts": "WMTS tile",
+    "legend": "Legend",
+}
+
+LONLAT_SRS = "EPSG:4326"
+
+# Share of the layer's extent the 1x1 GetMap covers, so little data is read
+CENTER_FRACTION = 0.01
+
+
+class _Skipped(Exception):
+    """The layer doesn't offer the endpoint."""
+
+
+@dataclass
+class EndpointResult:
+    """The outcome of one endpoint's request."""
+
+    service: str  # A key of SERVICES
+    status: str  # PASS, FAIL or SKIP
+    detail: str = ""
+    elapsed_ms: int = 0
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "service": self.service,
+            "label": SERVICES[self.service],
+            "status": self.status,
+            "detail": self.detail,
+            "elapsedMs": self.elapsed_ms,
+        }
+
+
+@dataclass
+class EndpointReport:
+    """Which of a layer's endpoints work."""
+
+    workspace: str
+    layer: str
+    results: list[EndpointResult] = field(default_factory=list)
+
+    @property
+    def ok(self) -> bool:
+        return all(result.status != FAIL for result in self.results)
+
+    def count(self, status: str) -> int:
+        return sum(result.status == status for result in self.results)
+
+    def record(self) -> dict[str, Any]:
+        """A row of the pass/fail matrix: the layer, then each endpoint's status."""
+        return {
+            "layer": f"{self.workspace}:{self.layer}",
+            **{result.service: result.status for result in self.results},
+        }
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "workspace": self.workspace,
+            "layer": self.layer,
+            "ok": self.ok,
+            "passed": self.count(PASS),
+            "failed": self.count(FAIL),
+            "skipped": self.count(SKIP),
+            "results": [result.to_dict() for result in self.results],
+        }
+
+
+def center_bbox(bbox: dict[str, Any], fraction: float = CENTER_FRACTION) -> tuple[float, ...]:
+    """A small box at the centre of a bounding box, as (minx, miny, maxx, maxy)."""
+    minx, miny, maxx, maxy = (float(bbox[k]) for k in ("minx", "miny", "maxx", "maxy"))
+    cx, cy = (minx + maxx) / 2, (miny + maxy) / 2
+    # A point layer's bounds can have no area; keep the box from collapsing
+    half_w = max((maxx - minx) * fraction / 2, 1e-6)
+    half_h = max((maxy - miny) * fraction / 2, 1e-6)
+    return (cx - half_w, cy - half_h, cx + half_w, cy + half_h)
+
+
+def _check(service: str, run: Callable[[], str], clock: Callable[[], float]) -> EndpointResult:
+    started = clock()
+    try:
+        status, detail = PASS, run()
+    except _Skipped as e:
+        status, detail = SKIP, str(e)
+    except GeoServerError as e:
+        status, detail = FAIL, e.message
+    return EndpointResult(service, status, detail, round((clock() - started) * 1000))
+
+
+def verify_endpoints(
+    client: "GeoServerClient",
+    workspace: str,
+    layer: str,
+    gwc_client: "GWCClient | None" = None,
+    clock: Callable[[], float] = time.perf_counter,
+) -> EndpointReport:
+    """Send each of a layer's endpoints a lightweight request.
+
+    Args:
+        client: GeoServer client
+        workspace: Workspace name
+        layer: Layer name
+        gwc_client: GWC client for the tile check; skipped without one
+        clock: Time source, in seconds
+
+    Raises:
+        GeoServerError: If the layer can't be read
+    """
+    layer_data = client.get_layer(workspace, layer)
+    reference = layer_data.get("resource", {})
+    vector = "featureType" in reference.get("@class", "")
+    resource: dict[str, Any] = {}
+    if reference.get("href"):
+        try:
+            resource = client.get_href(reference["href"]).get(
+                "featureType" if vector else "coverage", {}
+            )
+        except GeoServerError:
+            pass  # The GetMap checks report what's wrong with the resource
+    latlon = resource.get("latLonBoundingBox")
+    native = resource.get("nativeBoundingBox")
+    srs = resource.get("srs")
+    name = f"{workspace}:{layer}"
+
+    def wms() -> str:
+        if not latlon:
+            raise GeoServerError("The layer has no lat/lon bounds to draw")
+        image = client.get_map(workspace, layer, center_bbox(latlon), 1, 1, srs=LONLAT_SRS)
+        return f"{len(image)} byte image"
+
+    def wms_native() -> str:
+        if not srs or srs == LONLAT_SRS:
+            raise _Skipped(f"The layer's SRS is {srs or 'unknown'}")
+        if not native:
+            raise GeoServerError(f"The layer has no bounds in {srs}")
+        image = client.get_map(workspace, layer, center_bbox(native), 1, 1, srs=srs)
+        return f"{len(image)} byte image in {srs}"
+
+    def wfs() -> str:
+        if not vector:
+            raise _Skipped("Only vector layers are served over WFS")
+        return f"{client.get_layer_feature_count(workspace, layer)} features"
+
+    def wmts() -> str:
+        if gwc_client is None:
+            raise _Skipped("No tile cache to check")
+        check = check_endpoints(gwc_client, name)
+        if check.issues and not check.grid_sets:
+            raise _Skipped(check.issues[0])
+        if check.issues:
+            raise GeoServerError("; ".join(check.issues))
+        return f"{check.size} byte {check.format} tile in {check.grid_set}"
+
+    def legend() -> str:
+        return f"{len(client.get_legend_graphic(workspace, layer))} byte image"
+
+    checks = {"wms": wms, "wms-native": wms_native, "wfs": wfs, "wmts": wmts, "legend": legend}
+    report = EndpointReport(workspace, layer)
+    for service in SERVICES:
+        report.results.append(_check(service, checks[service], clock))
+    return report
@@ -0,0 +1,295 @@
+"""Client for the GeoFence extension's rule admin API (/rest/geofence).
+
+GeoFence replaces GeoServer's basic data security, which grants read and
+write per workspace and layer to roles, with an ordered list of rules.
+Each rule matches requests by user, role, client address, OWS service and
+request, workspace and layer, leaving out (null) what it doesn't care
+about, and allows, denies or limits them. The first matching rule by
+priority (lowest first) decides. A LIMIT rule narrows access granted by a
+later ALLOW rule, e.g. to an area or by hiding layers from capabilities.
+"""
+
+import ipaddress
+import re
+from dataclasses import dataclass, fields
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+ACCESS_ALLOW = "ALLOW"
+ACCESS_DENY = "DENY"
+ACCESS_LIMIT = "LIMIT"
+ACCESS_TYPES = (ACCESS_ALLOW, ACCESS_DENY, ACCESS_LIMIT)
+
+# How layers a LIMIT rule applies to show in capabilities and answer requests
+CATALOG_MODES = ("HIDE", "CHALLENGE", "MIXED")
+
+SERVICES = ("WMS", "WFS", "WCS", "WMTS", "WPS", "GWC")
+
+# Rule fields matching requests: attribute -> GeoFence JSON name
+_MATCH_FIELDS = {
+    "user": "userName",
+    "role": "roleName",
+    "address_range": "addressRange",
+    "service": "service",
+    "request": "request",
+    "workspace": "workspace",
+    "layer": "layer",
+}
+
+_AREA_RE = re.compile(
+    r"^(?:SRID=(?P<srid>\d+);)?\s*(?P<type>MULTIPOLYGON|POLYGON)\s*(?P<body>\(.*\))$",
+    re.IGNORECASE | re.DOTALL,
+)
+
+
+def normalize_area(area: str) -> str:
+    """Turn a WKT polygon into the EWKT multipolygon GeoFence stores.
+
+    Args:
+        area: POLYGON or MULTIPOLYGON WKT, optionally prefixed with SRID=<code>;
+            coordinates are taken as EPSG:4326 without one
+
+    Raises:
+        ValueError: If the area isn't a polygon or multipolygon
+    """
+    match = _AREA_RE.match(area.strip())
+    if not match:
+        raise ValueError("The area must be a POLYGON or MULTIPOLYGON in WKT")
+    body = match.group("body").strip()
+    if match.group("type").upper() == "POLYGON":
+        body = f"({body})"
+    return f"SRID={match.group('srid') or 4326};MULTIPOLYGON {body}"
+
+
+def _text(value: Any) -> str | None:
+    """Empty values and "*" mean any, which GeoFence writes as null."""
+    text = str(value).strip() if value is not None else ""
+    return None if text in ("", "*") else text
+
+
+@dataclass
+class GeofenceRule:
+    """A GeoFence data security rule; None in a match field means any."""
+
+    access: str = ACCESS_ALLOW
+    priority: int = 0  # Lower is checked first
+    id: int | None = None
+    user: str | None = None
+    role: str | None = None
+    address_range: str | None = None  # CIDR, e.g. 10.0.0.0/8
+    service: str | None = None
+    request: str | None = None
+    workspace: str | None = None
+    layer: str | None = None
+    area: str | None = None  # EWKT multipolygon; LIMIT rules only
+    catalog_mode: str | None = None  # LIMIT rules only
+
+    def validate(self) -> None:
+        """Check the rule is one GeoFence would accept and normalize its area.
+
+        Raises:
+            ValueError: If a field is invalid
+        """
+        if self.access not in ACCESS_TYPES:
+            raise ValueError(f"Unknown access: {self.access} (use {', '.join(ACCESS_TYPES)})")
+        if self.priority < 0:
+            raise ValueError("The priority can't be negative")
+        if self.layer and not self.workspace:
+            raise ValueError("A rule for a layer needs its workspace")
+        if self.address_range:
+            try:
+                ipaddress.ip_network(self.address_range, strict=False)
+            except ValueError as e:
+                raise ValueError(f"Invalid address range: {e}") from e
+        if self.access != ACCESS_LIMIT and (self.area or self.catalog_mode):
+            raise ValueError("Only LIMIT rules can restrict the area or catalog mode")
+        if self.catalog_mode and self.catalog_mode not in CATALOG_MODES:
+            raise ValueError(
+                f"Unknown catalog mode: {self.catalog_mode} (use {', '.join(CATALOG_MODES)})"
+            )
+        if self.area:
+            self.area = normalize_area(self.area)
+        if self.service:
+            self.service = self.service.upper()
+
+    @classmethod
+    def from_dict(cls, data: dict[str, Any], rule_id: int | None = None) -> "GeofenceRule":
+        """Build a validated rule from an API request.
+
+        Raises:
+            ValueError: If a field is invalid
+        """
+        try:
+            priority = int(data.get("priority") or 0)
+        except (TypeError, ValueError) as e:
+            raise ValueError("The priority must be a whole number") from e
+        rule = cls(
+            access=str(data.get("access") or ACCESS_ALLOW).upper(),
+            priority=priority,
+            id=rule_id,
+            user=_text(data.get("user")),
+            role=_text(data.get("role")),
+            address_range=_text(data.get("addressRange")),
+            service=_text(data.get("service")),
+            request=_text(data.get("request")),
+            workspace=_text(data.get("workspace")),
+            layer=_text(data.get("layer")),
+            area=_text(data.get("area")),
+            catalog_mode=_text(data.get("catalogMode")),
+        )
+        rule.validate()
+        return rule
+
+    @classmethod
+    def from_api(cls, data: dict[str, Any]) -> "GeofenceRule":
+        """Read a rule from GeoFence's JSON."""
+        limits = data.get("limits") or {}
+        rule_id = data.get("id")
+        return cls(
+            access=data.get("access") or ACCESS_ALLOW,
+            priority=int(data.get("priority") or 0),
+            id=int(rule_id) if rule_id is not None else None,
+            area=limits.get("allowedArea") or None,
+            catalog_mode=limits.get("catalogMode") or None,
+            **{attr: _text(data.get(name)) for attr, name in _MATCH_FIELDS.items()},
+        )
+
+    def to_api(self) -> dict[str, Any]:
+        """Write the rule as GeoFence's JSON, with null for any."""
+        rule: dict[str, Any] = {"priority": self.priority, "access": self.access}
+        rule.update({name: getattr(self, attr) for attr, name in _MATCH_FIELDS.items()})
+        if self.access == ACCESS_LIMIT and (self.area or self.catalog_mode):
+            limits: dict[str, Any] = {}
+            if self.area:
+                limits["allowedArea"] = self.area
+            if self.catalog_mode:
+                limits["catalogMode"] = self.catalog_mode
+            rule["limits"] = limits
+        return rule
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "id": self.id,
+            "priority": self.priority,
+            "access": self.access,
+            "user": self.user,
+            "role": self.role,
+            "addressRange": self.address_range,
+            "service": self.service,
+            "request": self.request,
+            "workspace": self.workspace,
+            "layer": self.layer,
+            "area": self.area,
+            "catalogMode": self.catalog_mode,
+        }
+
+    def clears(self, previous: "GeofenceRule") -> bool:
+        """Check whether the rule leaves out a field the previous version set.
+
+        GeoFence updates only the fields a request carries, so a field can't
+        be cleared in place.
+        """
+        return any(
+            getattr(previous, f.name) is not None and getattr(self, f.name) is None
+            for f in fields(self)
+            if f.name != "id"
+        )
+
+
+class GeofenceClient:
+    """Client for the GeoFence rule API, sharing a GeoServer client's connection."""
+
+    def __init__(self, client: "GeoServerClient"):
+        """Initialize GeoFence client.
+
+        Args:
+            client: GeoServer client whose connection and limiter are used
+        """
+        self._client = client
+        self._available: bool | None = None
+
+    def is_available(self) -> bool:
+        """Check whether GeoFence is installed with its REST API (cached)."""
+        if not self._client.capabilities.supports("geofence"):
+            return False  # Not in the server's manifests
+        if self._available is None:
+            try:
+                response = self._client._request("GET", "/rest/geofence/rules/count.json")
+                self._available = response.status_code == 200
+            except GeoServerError:
+                return False
+        return self._available
+
+    def _check(self, response: Any, action: str) -> Any:
+        if response.status_code == 404:
+            raise GeoServerError(f"Failed to {action}: rule not found", status_code=404)
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to {action}: {response.text}", status_code=response.status_code
+            )
+        return response
+
+    def list_rules(self) -> list[GeofenceRule]:
+        """List every rule, in priority order."""
+        response = self._client._request("GET", "/rest/geofence/rules.json")
+        data = self._check(response, "list rules").json()
+        rules = data.get("rules") or []
+        if isinstance(rules, dict):
+            rules = [rules]
+        return sorted(
+            (GeofenceRule.from_api(rule) for rule in rules),
+            key=lambda rule: (rule.priority, rule.id or 0),
+        )
+
+    def get_rule(self, rule_id: int) -> GeofenceRule:
+        """Get one rule."""
+        response = self._client._request("GET", f"/rest/geofence/rules/id/{rule_id}.json")
+        data = self._check(response, f"get rule {rule_id}").json()
+        return GeofenceRule.from_api(data.get("Rule", data))
+
+    def create_rule(self, rule: GeofenceRule) -> int:
+        """Add a rule; rules at its priority and below move down one.
+
+        Returns:
+            The new rule's id
+        """
+        response = self._client._request(
+            "POST", "/rest/geofence/rules", json={"Rule": rule.to_api()}
+        )
+        self._check(response, "create rule")
+        # GeoFence answers with the id as plain text
+        rule.id = int(response.text.strip())
+        return rule.id
+
+    def update_rule(self, rule: GeofenceRule) -> int:
+        """Save changes to a rule.
+
+        A rule that no longer sets a field it did is replaced at the same
+        priority instead, since GeoFence can't clear a field in place.
+
+        Returns:
+            The rule's id, which is new when it was replaced
+        """
+        if rule.id is None:
+            raise ValueError("Only saved rules can be updated")
+        previous = self.get_rule(rule.id)
+        if rule.clears(previous):
+            old_id = rule.id
+            new_id = self.create_rule(rule)
+            self.delete_rule(old_id)
+            return new_id
+        response = self._client._request(
+            "POST", f"/rest/geofence/rules/id/{rule.id}", json={"Rule": rule.to_api()}
+        )
+        self._check(response, f"update rule {rule.id}")
+        return rule.id
+
+    def delete_rule(self, rule_id: int) -> None:
+        """Delete a rule."""
+        response = self._client._request("DELETE", f"/rest/geofence/rules/id/{rule_id}")
+        self._check(response, f"delete rule {rule_id}")
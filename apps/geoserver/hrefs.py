@@ -0,0 +1,60 @@
+"""Helpers for the href links in GeoServer REST responses.
+
+GeoServer builds hrefs from its proxy base URL setting or the request's
+forwarded headers, so behind reverse proxies they often don't match the
+URL the connection uses: a different host, scheme or path prefix
+(/geoserver, /maps/geoserver, none at all). Only the part from /rest/
+onwards is reliable, so hrefs are reduced to that path and resolved
+against the connection's own URL.
+"""
+
+import re
+from urllib.parse import unquote, urlsplit
+
+_STORE_RE = re.compile(
+    r"/workspaces/(?P<workspace>[^/]+)/(?P<type>datastores|coveragestores|wmsstores|wmtsstores)"
+    r"/(?P<store>[^/]+?)(?:\.(?:json|xml))?(?:/|$)"
+)
+
+
+def rest_path(href: str) -> str | None:
+    """Reduce an href to its REST path, whatever host or prefix it carries.
+
+    Args:
+        href: Absolute or relative href, e.g.
+            https://proxy/maps/geoserver/rest/workspaces/topp/datastores/roads.json
+
+    Returns:
+        Path starting at /rest/, e.g. /rest/workspaces/topp/datastores/roads.json,
+        or None if the href doesn't point into the REST API
+    """
+    path = urlsplit(href or "").path
+    # The last /rest/ segment wins, in case a prefix itself contains "rest"
+    index = path.rfind("/rest/")
+    if index < 0:
+        return None
+    return path[index:]
+
+
+def store_from_href(href: str) -> tuple[str, str, str] | None:
+    """Extract the workspace and store a resource href belongs to.
+
+    Args:
+        href: Feature type, coverage or store href
+
+    Returns:
+        Tuple of (workspace, store type, store name) where store type is
+        "datastores", "coveragestores", "wmsstores" or "wmtsstores", or None
+        if the href doesn't name a store
+    """
+    path = rest_path(href)
+    if path is None:
+        return None
+    match = _STORE_RE.search(path)
+    if not match:
+        return None
+    return (
+        unquote(match.group("workspace")),
+        match.group("type"),
+        unquote(match.group("store")),
+    )
@@ -0,0 +1,81 @@
+"""Impact reports for recursive deletes.
+
+Deleting a workspace with recurse=true removes everything in it. The
+report lists what will go so it can be shown before the user confirms.
+"""
+
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+
+@dataclass
+class WorkspaceImpact:
+    """Resources removed by recursively deleting a workspace."""
+
+    workspace: str
+    stores: list[dict[str, str]] = field(default_factory=list)
+    layers: list[str] = field(default_factory=list)
+    styles: list[str] = field(default_factory=list)
+    layer_groups: list[str] = field(default_factory=list)
+    caches: list[str] = field(default_factory=list)
+
+    @property
+    def is_empty(self) -> bool:
+        """Whether nothing but the workspace itself would be deleted."""
+        return not (self.stores or self.layers or self.styles or self.layer_groups)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "workspace": self.workspace,
+            "stores": self.stores,
+            "layers": self.layers,
+            "styles": self.styles,
+            "layerGroups": self.layer_groups,
+            "caches": self.caches,
+            "counts": {
+                "stores": len(self.stores),
+                "layers": len(self.layers),
+                "styles": len(self.styles),
+                "layerGroups": len(self.layer_groups),
+                "caches": len(self.caches),
+            },
+        }
+
+
+def _names(items: list[dict[str, Any]]) -> list[str]:
+    return sorted(item["name"] for item in items if item.get("name"))
+
+
+def workspace_impact(
+    client: "GeoServerClient",
+    workspace: str,
+    cached_layers: list[str] | None = None,
+) -> WorkspaceImpact:
+    """Collect what a recursive delete of a workspace would remove.
+
+    Args:
+        client: GeoServer client
+        workspace: Workspace name
+        cached_layers: Full names of the workspace's GWC tile layers
+
+    Returns:
+        The impact report
+    """
+    stores = [
+        {"name": name, "type": "datastore"} for name in _names(client.list_datastores(workspace))
+    ] + [
+        {"name": name, "type": "coveragestore"}
+        for name in _names(client.list_coveragestores(workspace))
+    ]
+    return WorkspaceImpact(
+        workspace=workspace,
+        stores=stores,
+        layers=_names(client.list_layers(workspace)),
+        styles=_names(client.list_styles(workspace)),
+        layer_groups=_names(client.list_layergroups(workspace)),
+        caches=sorted(cached_layers or []),
+    )
@@ -0,0 +1,359 @@
+"""Client for the GeoServer Importer extension (/rest/imports).
+
+The importer detects formats, assigns SRS and ingests many datasets in one
+job, which the file.* upload endpoints can't: a zip of several shapefiles
+becomes one layer per shapefile, and mixed archives (shapefiles next to
+GeoTIFFs or GeoPackages) are split into vector and raster stores.
+
+An import is a context holding one task per dataset. The flow is
+create import -> add files (creates tasks) -> adjust tasks -> run -> poll.
+"""
+
+import io
+import time
+import zipfile
+from collections.abc import Callable
+from dataclasses import dataclass, field
+from pathlib import PurePosixPath
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+from apps.core.timeouts import request_timeout
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Import states after which polling stops
+FINISHED_STATES = ("COMPLETE", "INIT_ERROR")
+
+# Task states that are still waiting to be, or being, ingested
+ACTIVE_TASK_STATES = ("READY", "RUNNING")
+
+# Extensions the importer treats as separate datasets
+DATASET_EXTENSIONS = (".shp", ".tif", ".tiff", ".gpkg", ".csv", ".geojson", ".kml")
+
+
+def archive_datasets(filename: str, data: bytes) -> list[str]:
+    """List the datasets contained in a zip archive.
+
+    Args:
+        filename: Uploaded file name
+        data: File contents
+
+    Returns:
+        Paths of the dataset files in the archive; empty for non-zip files
+    """
+    if not filename.lower().endswith(".zip"):
+        return []
+    try:
+        with zipfile.ZipFile(io.BytesIO(data)) as archive:
+            names = archive.namelist()
+    except zipfile.BadZipFile:
+        return []
+    return [
+        name
+        for name in names
+        if not name.startswith("__MACOSX/")
+        and PurePosixPath(name).suffix.lower() in DATASET_EXTENSIONS
+    ]
+
+
+def needs_importer(filename: str, data: bytes) -> bool:
+    """Check whether an upload holds more than the file.* endpoints can publish.
+
+    The file.* endpoints publish a single dataset per store, so archives
+    with several shapefiles, or a mix of formats, need the importer.
+    """
+    return len(archive_datasets(filename, data)) > 1
+
+
+@dataclass
+class ImportTask:
+    """Outcome of one dataset in an import."""
+
+    id: int
+    state: str
+    layer: str = ""
+    store: str = ""
+    store_type: str = ""
+    error: str = ""
+
+    @classmethod
+    def from_json(cls, data: dict[str, Any]) -> "ImportTask":
+        """Build from an importer task document."""
+        target = data.get("target", {}) or {}
+        store_type, store = "", ""
+        for key in ("dataStore", "coverageStore"):
+            if key in target:
+                store_type, store = key, (target[key] or {}).get("name", "")
+                break
+        return cls(
+            id=int(data.get("id", 0)),
+            state=data.get("state", ""),
+            layer=(data.get("layer", {}) or {}).get("name", ""),
+            store=store,
+            store_type=store_type,
+            error=data.get("errorMessage", "") or "",
+        )
+
+
+@dataclass
+class ImportResult:
+    """Outcome of a finished import."""
+
+    id: int
+    state: str
+    tasks: list[ImportTask] = field(default_factory=list)
+
+    @property
+    def layers(self) -> list[str]:
+        """Names of the layers that were published."""
+        return [t.layer for t in self.tasks if t.state == "COMPLETE" and t.layer]
+
+    @property
+    def stores(self) -> list[str]:
+        """Names of the stores the published layers are in."""
+        return sorted({t.store for t in self.tasks if t.state == "COMPLETE" and t.store})
+
+    @property
+    def failed(self) -> list[ImportTask]:
+        """Tasks that didn't complete."""
+        return [t for t in self.tasks if t.state != "COMPLETE"]
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "importId": self.id,
+            "importState": self.state,
+            "importedLayers": self.layers,
+            "importedStores": self.stores,
+            "failedTasks": [
+                {"id": t.id, "state": t.state, "layer": t.layer, "error": t.error}
+                for t in self.failed
+            ],
+        }
+
+
+class ImporterClient:
+    """Client for the REST Importer, sharing a GeoServer client's connection."""
+
+    def __init__(
+        self,
+        client: "GeoServerClient",
+        clock: Callable[[], float] = time.monotonic,
+        sleep: Callable[[float], None] = time.sleep,
+    ):
+        """Initialize importer client.
+
+        Args:
+            client: GeoServer client whose connection and limiter are used
+            clock: Monotonic clock, for polling timeouts
+            sleep: Sleep function, for polling intervals
+        """
+        self._client = client
+        self._clock = clock
+        self._sleep = sleep
+        self._available: bool | None = None
+
+    def is_available(self) -> bool:
+        """Check whether the importer extension is installed (cached)."""
+        if not self._client.capabilities.supports("importer"):
+            return False  # Not in the server's manifests
+        if self._available is None:
+            try:
+                response = self._client._request("GET", "/rest/imports.json")
+                self._available = response.status_code == 200
+            except GeoServerError:
+                return False
+        return self._available
+
+    def _json(self, response: Any, action: str) -> dict[str, Any]:
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to {action}: {response.text}", status_code=response.status_code
+            )
+        return response.json() if response.content else {}
+
+    def create_import(self, workspace: str, store: str | None = None) -> int:
+        """Create an empty import context.
+
+        Args:
+            workspace: Target workspace
+            store: Existing store to import into; omitted to create new stores
+
+        Returns:
+            Import ID
+        """
+        context: dict[str, Any] = {"targetWorkspace": {"workspace": {"name": workspace}}}
+        if store:
+            context["targetStore"] = {"dataStore": {"name": store}}
+        response = self._client._request(
+            "POST", "/rest/imports.json", json={"import": context}
+        )
+        return int(self._json(response, "create import")["import"]["id"])
+
+    def add_file(self, import_id: int, filename: str, data: bytes) -> list[ImportTask]:
+        """Upload a file, creating one task per dataset it contains.
+
+        Args:
+            import_id: Import ID
+            filename: File name, used for format detection
+            data: File contents
+
+        Returns:
+            The created tasks
+        """
+        response = self._client._request(
+            "POST",
+            f"/rest/imports/{import_id}/tasks.json",
+            # Read from a file object, the body is sent in pieces rather than at once
+            files={"filedata": (filename, io.BytesIO(data))},
+            timeout=request_timeout("upload"),
+        )
+        body = self._json(response, "add import task")
+        tasks = body.get("tasks") or [body.get("task", {})]
+        return [ImportTask.from_json(t) for t in tasks if t]
+
+    def set_task_target(
+        self,
+        import_id: int,
+        task_id: int,
+        store: str,
+        store_type: str = "dataStore",
+    ) -> None:
+        """Point a task at an existing store.
+
+        Args:
+            import_id: Import ID
+            task_id: Task ID
+            store: Store name
+            store_type: "dataStore" or "coverageStore"
+        """
+        response = self._client._request(
+            "PUT",
+            f"/rest/imports/{import_id}/tasks/{task_id}/target.json",
+            json={store_type: {"name": store}},
+        )
+        self._json(response, "set import target")
+
+    def set_task_srs(self, import_id: int, task_id: int, srs: str) -> None:
+        """Assign an SRS to a task whose data has none (state NO_CRS).
+
+        Args:
+            import_id: Import ID
+            task_id: Task ID
+            srs: SRS code, e.g. EPSG:4326
+        """
+        response = self._client._request(
+            "PUT",
+            f"/rest/imports/{import_id}/tasks/{task_id}/layer.json",
+            json={"layer": {"srs": srs}},
+        )
+        self._json(response, "set import SRS")
+
+    def run_import(self, import_id: int) -> None:
+        """Start running an import in the background."""
+        response = self._client._request(
+            "POST", f"/rest/imports/{import_id}", params={"async": "true"}
+        )
+        self._json(response, "run import")
+
+    def get_import(self, import_id: int) -> dict[str, Any]:
+        """Get an import context."""
+        response = self._client._request(
+            "GET", f"/rest/imports/{import_id}.json", params={"expand": "all"}
+        )
+        return self._json(response, "get import").get("import", {})
+
+    def get_task(self, import_id: int, task_id: int) -> ImportTask:
+        """Get a task's current state."""
+        response = self._client._request(
+            "GET", f"/rest/imports/{import_id}/tasks/{task_id}.json"
+        )
+        return ImportTask.from_json(self._json(response, "get import task").get("task", {}))
+
+    def get_task_progress(self, import_id: int, task_id: int) -> dict[str, Any]:
+        """Get a running task's progress (progress, total, state)."""
+        response = self._client._request(
+            "GET", f"/rest/imports/{import_id}/tasks/{task_id}/progress.json"
+        )
+        return self._json(response, "get import progress")
+
+    def delete_import(self, import_id: int) -> None:
+        """Delete an import context (published layers are kept)."""
+        self._client._request("DELETE", f"/rest/imports/{import_id}")
+
+    def wait_for_import(
+        self,
+        import_id: int,
+        timeout: float = 600.0,
+        interval: float = 1.0,
+        on_progress: Callable[[int, int], None] | None = None,
+    ) -> dict[str, Any]:
+        """Poll an import until it finishes.
+
+        Args:
+            import_id: Import ID
+            timeout: Seconds to wait before giving up
+            interval: Seconds between polls
+            on_progress: Called with (finished tasks, total tasks) after each poll
+
+        Returns:
+            The finished import context
+
+        Raises:
+            GeoServerError: If the import doesn't finish in time
+        """
+        deadline = self._clock() + timeout
+        while True:
+            context = self.get_import(import_id)
+            tasks = context.get("tasks", [])
+            active = sum(1 for t in tasks if t.get("state") in ACTIVE_TASK_STATES)
+            if on_progress:
+                on_progress(len(tasks) - active, len(tasks))
+            # Tasks that can't run (e.g. NO_CRS) leave the import PENDING, so
+            # it's finished once nothing is left to ingest
+            state = context.get("state")
+            if state in FINISHED_STATES or (state != "RUNNING" and not active):
+                return context
+            if self._clock() >= deadline:
+                raise GeoServerError(f"Import {import_id} did not finish in time", status_code=504)
+            self._sleep(interval)
+
+    def import_file(
+        self,
+        workspace: str,
+        filename: str,
+        data: bytes,
+        store: str | None = None,
+        srs: str | None = None,
+        timeout: float = 600.0,
+        on_progress: Callable[[int, int], None] | None = None,
+    ) -> ImportResult:
+        """Import every dataset in a file and wait for the result.
+
+        Args:
+            workspace: Target workspace
+            filename: File name
+            data: File contents
+            store: Existing store to import vector data into
+            srs: SRS assigned to datasets that don't declare one
+            timeout: Seconds to wait for the import to finish
+            on_progress: Called with (finished tasks, total tasks) while polling
+
+        Returns:
+            The import outcome
+        """
+        import_id = self.create_import(workspace, store)
+        try:
+            for task in self.add_file(import_id, filename, data):
+                if srs and task.state == "NO_CRS":
+                    self.set_task_srs(import_id, task.id, srs)
+            self.run_import(import_id)
+            context = self.wait_for_import(import_id, timeout, on_progress=on_progress)
+        except GeoServerError:
+            self.delete_import(import_id)
+            raise
+
+        tasks = [self.get_task(import_id, int(t["id"])) for t in context.get("tasks", [])]
+        return ImportResult(id=import_id, state=context.get("state", ""), tasks=tasks)
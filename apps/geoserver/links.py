@@ -0,0 +1,185 @@
+"""Copyable links and identifiers for catalog resources.
+
+Users regularly need a resource's URL elsewhere: a WMS GetMap link for a
+quick look in the browser, a WFS GetFeature link for a script, the WMTS
+capabilities for a desktop GIS, or the REST URL for curl. These helpers
+build the strings worth copying for each kind of tree node, so the web
+UI and the TUI offer the same choices.
+"""
+
+from dataclasses import asdict, dataclass
+from typing import Any
+from urllib.parse import urlencode
+
+from apps.core.config import Connection
+
+from .ows import service_url
+
+# Lat/lon bounds used for GetMap links when a layer's own aren't known
+WORLD_BBOX = {"minx": -180.0, "miny": -90.0, "maxx": 180.0, "maxy": 90.0}
+
+# Node type -> REST path under /rest, formatted with workspace, store and name
+REST_PATHS = {
+    "workspace": "workspaces/{workspace}",
+    "datastore": "workspaces/{workspace}/datastores/{name}",
+    "coveragestore": "workspaces/{workspace}/coveragestores/{name}",
+    "featuretype": "workspaces/{workspace}/datastores/{store}/featuretypes/{name}",
+    "coverage": "workspaces/{workspace}/coveragestores/{store}/coverages/{name}",
+    "layer": "workspaces/{workspace}/layers/{name}",
+    "style": "workspaces/{workspace}/styles/{name}",
+    "layergroup": "workspaces/{workspace}/layergroups/{name}",
+}
+
+# Global resources live directly under /rest
+GLOBAL_REST_PATHS = {
+    "style": "styles/{name}",
+    "layergroup": "layergroups/{name}",
+}
+
+
+@dataclass
+class CopyTarget:
+    """A string that can be copied for a resource."""
+
+    kind: str  # "name", "wms", "wfs", "wmts", "xyz", "tms", "wmsc", "rest" or "url"
+    label: str
+    value: str
+
+    def to_dict(self) -> dict[str, str]:
+        """Serialize for API responses."""
+        return asdict(self)
+
+
+def _query(params: dict[str, Any]) -> str:
+    # Keep qualified names and bbox commas readable in the copied URL
+    return urlencode(params, safe=":,/")
+
+
+def wms_getmap_url(
+    base_url: str,
+    workspace: str,
+    name: str,
+    bbox: dict[str, Any] | None = None,
+    width: int = 768,
+) -> str:
+    """Build a WMS 1.1.1 GetMap URL showing a layer in EPSG:4326.
+
+    Args:
+        base_url: Public GeoServer base URL
+        workspace: Workspace of the layer
+        name: Layer or layer group name
+        bbox: Lat/lon bounds with minx, miny, maxx and maxy; the world when omitted
+        width: Image width in pixels; the height follows the bounds' aspect ratio
+    """
+    bbox = bbox or WORLD_BBOX
+    minx, miny = float(bbox["minx"]), float(bbox["miny"])
+    maxx, maxy = float(bbox["maxx"]), float(bbox["maxy"])
+    ratio = (maxy - miny) / (maxx - minx) if maxx > minx else 0.5
+    height = max(1, round(width * ratio))
+    params = {
+        "service": "WMS",
+        "version": "1.1.1",
+        "request": "GetMap",
+        "layers": f"{workspace}:{name}",
+        "styles": "",
+        "bbox": f"{minx},{miny},{maxx},{maxy}",
+        "width": width,
+        "height": height,
+        "srs": "EPSG:4326",
+        "format": "image/png",
+    }
+    return f"{service_url(base_url, 'wms', workspace)}?{_query(params)}"
+
+
+def wfs_getfeature_url(base_url: str, workspace: str, name: str, count: int = 50) -> str:
+    """Build a WFS 2.0 GetFeature URL returning the first features as GeoJSON."""
+    params = {
+        "service": "WFS",
+        "version": "2.0.0",
+        "request": "GetFeature",
+        "typeNames": f"{workspace}:{name}",
+        "count": count,
+        "outputFormat": "application/json",
+    }
+    return f"{service_url(base_url, 'wfs', workspace)}?{_query(params)}"
+
+
+def wmts_capabilities_url(base_url: str) -> str:
+    """Build the GeoWebCache WMTS GetCapabilities URL."""
+    params = {"service": "WMTS", "version": "1.0.0", "request": "GetCapabilities"}
+    return f"{base_url.rstrip('/')}/gwc/service/wmts?{_query(params)}"
+
+
+def rest_url(
+    base_url: str,
+    node_type: str,
+    workspace: str | None = None,
+    name: str | None = None,
+    store: str | None = None,
+) -> str | None:
+    """Build the REST URL of a resource, or None for node types without one."""
+    if node_type == "connection":
+        return f"{base_url.rstrip('/')}/rest"
+    template = REST_PATHS.get(node_type) if workspace else GLOBAL_REST_PATHS.get(node_type)
+    if template is None:
+        return None
+    path = template.format(workspace=workspace, store=store, name=name)
+    return f"{base_url.rstrip('/')}/rest/{path}.json"
+
+
+def copy_targets(
+    connection: Connection,
+    node_type: str,
+    workspace: str | None = None,
+    name: str | None = None,
+    store: str | None = None,
+    store_type: str | None = None,
+    bbox: dict[str, Any] | None = None,
+) -> list[CopyTarget]:
+    """List the strings worth copying for a tree node, most useful first.
+
+    Args:
+        connection: Connection the resource belongs to
+        node_type: Tree node type, e.g. "layer" or "workspace"
+        workspace: Workspace of the resource
+        name: Resource name
+        store: Store of a feature type or coverage
+        store_type: "datastore" or "coveragestore" for layers, when known;
+            coverage layers get no WFS link
+        bbox: Lat/lon bounds of a layer, for a GetMap link framing it
+    """
+    public = connection.public_base_url
+    targets = []
+
+    if node_type == "connection":
+        targets.append(CopyTarget("url", "GeoServer URL", public))
+        targets.append(CopyTarget("wmts", "WMTS capabilities URL", wmts_capabilities_url(public)))
+    elif node_type == "workspace" and workspace:
+        targets.append(CopyTarget("name", "Workspace name", workspace))
+        targets.append(
+            CopyTarget(
+                "wms",
+                "WMS capabilities URL",
+                f"{service_url(public, 'wms', workspace)}?service=WMS&request=GetCapabilities",
+            )
+        )
+    elif name:
+        qualified = f"{workspace}:{name}" if workspace else name
+        targets.append(CopyTarget("name", "Qualified name", qualified))
+        if node_type in ("layer", "layergroup") and workspace:
+            targets.append(
+                CopyTarget("wms", "WMS GetMap URL", wms_getmap_url(public, workspace, name, bbox))
+            )
+        if node_type in ("layer", "featuretype") and workspace and store_type != "coveragestore":
+            targets.append(
+                CopyTarget("wfs", "WFS GetFeature URL", wfs_getfeature_url(public, workspace, name))
+            )
+        if node_type in ("layer", "layergroup"):
+            targets.append(
+                CopyTarget("wmts", "WMTS capabilities URL", wmts_capabilities_url(public))
+            )
+
+    url = rest_url(connection.url, node_type, workspace, name, store)
+    if url:
+        targets.append(CopyTarget("rest", "REST URL", url))
+    return targets
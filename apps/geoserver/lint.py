@@ -0,0 +1,259 @@
+"""Catalog reference integrity checks.
+
+GeoServer resources reference each other by name: layer groups list
+layers, layers name their default style, feature types belong to a store
+and GeoWebCache keeps a tile layer per published layer. Deleting or
+disabling one side through the REST API or the data directory leaves the
+other pointing at nothing, which only shows up later as rendering or
+capabilities errors. The linter walks a connection's catalog, reports
+those broken references and suggests a fix for each.
+"""
+
+import logging
+from dataclasses import asdict, dataclass
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+from .store_status import add_enabled
+
+if TYPE_CHECKING:
+    from apps.gwc.client import GWCClient
+
+    from .client import GeoServerClient
+
+logger = logging.getLogger(__name__)
+
+# Check name -> description
+CHECKS = {
+    "layergroup_missing_layer": "Layer groups containing deleted layers",
+    "missing_default_style": "Layers whose default style is missing",
+    "disabled_store": "Feature types whose store is disabled",
+    "orphaned_cache": "Tile caches with no catalog layer",
+}
+
+
+@dataclass
+class LintIssue:
+    """A broken reference found in the catalog."""
+
+    check: str
+    severity: str  # "error" or "warning"
+    resource: str  # e.g. "topp:roads_group"
+    message: str
+    fix: str
+
+    def to_dict(self) -> dict[str, str]:
+        """Serialize for API responses."""
+        return asdict(self)
+
+
+def _qualified(workspace: str | None, name: str) -> str:
+    return f"{workspace}:{name}" if workspace and ":" not in name else name
+
+
+def _as_list(value: Any) -> list[Any]:
+    # GeoServer JSON collapses single-element lists into objects
+    if not value:
+        return []
+    return value if isinstance(value, list) else [value]
+
+
+class CatalogLinter:
+    """Scans a connection's catalog for broken references."""
+
+    def __init__(self, client: "GeoServerClient", gwc_client: "GWCClient | None" = None):
+        """Initialize linter.
+
+        Args:
+            client: GeoServer client
+            gwc_client: GWC client; the orphaned cache check is skipped without one
+        """
+        self.client = client
+        self.gwc_client = gwc_client
+        self.workspaces = [ws["name"] for ws in client.list_workspaces() if ws.get("name")]
+        # Layer name -> workspace, for every published layer
+        self.layers: dict[str, str] = {}
+        for ws in self.workspaces:
+            for layer in client.list_layers(ws):
+                if layer.get("name"):
+                    self.layers[_qualified(ws, layer["name"])] = ws
+
+    def run(self, checks: list[str] | None = None) -> list[LintIssue]:
+        """Run checks and collect their issues.
+
+        Args:
+            checks: Check names to run; all checks when omitted
+
+        Returns:
+            Issues, errors first
+        """
+        checks = checks or list(CHECKS)
+        unknown = [c for c in checks if c not in CHECKS]
+        if unknown:
+            raise ValueError(f"Unknown check(s): {', '.join(unknown)}")
+
+        issues: list[LintIssue] = []
+        for check in checks:
+            issues.extend(getattr(self, f"check_{check}")())
+        return sorted(issues, key=lambda i: (i.severity != "error", i.check, i.resource))
+
+    def _layer_groups(self) -> list[tuple[str | None, str]]:
+        groups = [(None, g["name"]) for g in self.client.list_layergroups() if g.get("name")]
+        for ws in self.workspaces:
+            groups.extend(
+                (ws, g["name"]) for g in self.client.list_layergroups(ws) if g.get("name")
+            )
+        return groups
+
+    def check_layergroup_missing_layer(self) -> list[LintIssue]:
+        """Find layer group entries that point at deleted layers or groups."""
+        groups = self._layer_groups()
+        group_names = {_qualified(ws, name) for ws, name in groups}
+        local_names = {name.split(":", 1)[-1] for name in self.layers}
+        issues = []
+
+        for ws, name in groups:
+            try:
+                group = self.client.get_layergroup(name, ws)
+            except GeoServerError as e:
+                logger.warning("Could not read layer group %s: %s", name, e)
+                continue
+
+            published = _as_list((group.get("publishables") or {}).get("published"))
+            for entry in published:
+                if not isinstance(entry, dict) or not entry.get("name"):
+                    continue  # Style-only entries have no layer
+                ref = entry["name"]
+                if entry.get("@type") == "layerGroup":
+                    exists = ref in group_names or _qualified(ws, ref) in group_names
+                else:
+                    exists = ref in self.layers or (":" not in ref and ref in local_names)
+                if not exists:
+                    resource = _qualified(ws, name)
+                    issues.append(
+                        LintIssue(
+                            check="layergroup_missing_layer",
+                            severity="error",
+                            resource=resource,
+                            message=f"Layer group '{resource}' references missing '{ref}'",
+                            fix=f"Remove '{ref}' from the group or republish it",
+                        )
+                    )
+        return issues
+
+    def _style_names(self) -> set[str]:
+        names = {s["name"] for s in self.client.list_styles() if s.get("name")}
+        for ws in self.workspaces:
+            names.update(f"{ws}:{s['name']}" for s in self.client.list_styles(ws) if s.get("name"))
+        return names
+
+    def check_missing_default_style(self) -> list[LintIssue]:
+        """Find layers whose default style no longer exists."""
+        styles = self._style_names()
+        issues = []
+
+        for full_name, ws in sorted(self.layers.items()):
+            try:
+                layer = self.client.get_layer(ws, full_name.split(":", 1)[1])
+            except GeoServerError as e:
+                logger.warning("Could not read layer %s: %s", full_name, e)
+                continue
+
+            default = layer.get("defaultStyle") or {}
+            style = default.get("name", "") if isinstance(default, dict) else str(default)
+            if style and ":" not in style and isinstance(default, dict):
+                style = _qualified(default.get("workspace"), style)
+            if style in styles:
+                continue
+
+            message = (
+                f"Layer '{full_name}' has no default style"
+                if not style
+                else f"Default style '{style}' of layer '{full_name}' does not exist"
+            )
+            issues.append(
+                LintIssue(
+                    check="missing_default_style",
+                    severity="error",
+                    resource=full_name,
+                    message=message,
+                    fix="Assign an existing default style to the layer",
+                )
+            )
+        return issues
+
+    def check_disabled_store(self) -> list[LintIssue]:
+        """Find feature types served from a disabled data store."""
+        issues = []
+
+        for ws in self.workspaces:
+            stores = self.client.list_datastores(ws)
+            for entry in add_enabled(self.client, ws, "datastore", stores, limit=None):
+                store = entry.get("name")
+                # Unreadable stores are logged by add_enabled
+                if not store or entry["enabled"] is not False:
+                    continue
+                try:
+                    feature_types = self.client.list_featuretypes(ws, store)
+                except GeoServerError as e:
+                    logger.warning("Could not read data store %s:%s: %s", ws, store, e)
+                    continue
+
+                for ft in feature_types:
+                    resource = _qualified(ws, ft.get("name", ""))
+                    issues.append(
+                        LintIssue(
+                            check="disabled_store",
+                            severity="warning",
+                            resource=resource,
+                            message=f"Feature type '{resource}' is in disabled store '{store}'",
+                            fix=f"Enable store '{ws}:{store}' or remove the layer",
+                        )
+                    )
+        return issues
+
+    def check_orphaned_cache(self) -> list[LintIssue]:
+        """Find GWC tile layers whose catalog layer or group is gone."""
+        if self.gwc_client is None:
+            return []
+
+        try:
+            cached = self.gwc_client.list_layers()
+        except GeoServerError as e:
+            logger.warning("Could not list GWC layers: %s", e)
+            return []
+
+        known = set(self.layers) | {_qualified(ws, name) for ws, name in self._layer_groups()}
+        return [
+            LintIssue(
+                check="orphaned_cache",
+                severity="warning",
+                resource=name,
+                message=f"Tile layer '{name}' has no matching layer or layer group",
+                fix="Truncate the tile layer and remove it from GeoWebCache",
+            )
+            for name in sorted(cached)
+            if name not in known
+        ]
+
+
+def lint_catalog(
+    client: "GeoServerClient",
+    gwc_client: "GWCClient | None" = None,
+    checks: list[str] | None = None,
+) -> list[LintIssue]:
+    """Scan a connection's catalog for broken references.
+
+    Args:
+        client: GeoServer client
+        gwc_client: GWC client, for the orphaned cache check
+        checks: Check names to run; all checks when omitted
+
+    Returns:
+        Issues, errors first
+
+    Raises:
+        ValueError: If a check name is unknown
+    """
+    return CatalogLinter(client, gwc_client).run(checks)
@@ -0,0 +1,417 @@
+"""Load tests of a layer's OWS endpoints.
+
+The render benchmark times one request at a time; this finds out how a
+layer holds up under concurrent clients. It sends WMS GetMap, WMTS
+GetTile or WFS GetFeature requests from a growing number of threads, a
+stage of a fixed duration per concurrency level (the ramp), and reports
+each stage's throughput, error rate and latency percentiles and
+histogram, so the point where latency climbs or errors start shows up.
+
+Requests go through the connection's clients, so a connection with a
+concurrent request limit caps the concurrency actually reached.
+"""
+
+import math
+import random
+import threading
+import time
+from concurrent.futures import ThreadPoolExecutor
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any, Callable
+
+from apps.core.exceptions import GeoServerError
+from apps.gwc.endpoints import PREFERRED_FORMAT, wmts_tile_params
+from apps.gwc.estimate import lonlat_to_web_mercator
+
+from .render_benchmark import (
+    MAX_ZOOM,
+    ORIGIN_SHIFT,
+    Bounds,
+    fitting_zoom,
+    percentile,
+    sample_bboxes,
+    zoom_resolution,
+)
+
+if TYPE_CHECKING:
+    from apps.gwc.client import GWCClient
+
+    from .client import GeoServerClient
+
+REQUEST_KINDS = ("getmap", "gettile", "getfeature")
+
+# Defaults: concurrency per stage and seconds per stage
+DEFAULT_RAMP = (1, 2, 4, 8)
+DEFAULT_STAGE_SECONDS = 10.0
+
+# Upper bounds, to keep a mistyped ramp from flooding the server
+MAX_CONCURRENCY = 64
+MAX_STAGE_SECONDS = 300.0
+
+# GetMap image size, the same as a tile
+MAP_SIZE = 256
+
+# Web Mercator grid set GWC defines by default
+TILE_GRID_SET = "EPSG:900913"
+
+# Features per GetFeature request
+FEATURE_COUNT = 100
+
+# Places sampled in the layer for GetMap and GetTile requests
+PLACES = 50
+
+# Upper bounds of the latency histogram buckets in milliseconds; a last bucket is open-ended
+HISTOGRAM_BOUNDS_MS = (50, 100, 250, 500, 1000, 2500, 5000)
+
+# Characters of the terminal chart
+BAR = "█"
+TAIL = "░"
+
+
+def parse_ramp(text: str) -> list[int]:
+    """Parse a ramp such as "1,4,8" into concurrency levels.
+
+    Raises:
+        ValueError: If a level isn't a whole number between 1 and MAX_CONCURRENCY
+    """
+    try:
+        ramp = [int(level) for level in text.replace(",", " ").split()]
+    except ValueError:
+        raise ValueError("The ramp must be whole numbers, e.g. 1,4,8") from None
+    if not ramp:
+        raise ValueError("The ramp needs at least one concurrency level")
+    for level in ramp:
+        if not 1 <= level <= MAX_CONCURRENCY:
+            raise ValueError(f"Concurrency levels must be between 1 and {MAX_CONCURRENCY}")
+    return ramp
+
+
+def tile_for_point(x: float, y: float, zoom: int) -> tuple[int, int]:
+    """The (row, col) of the EPSG:900913 tile holding a point; rows count from the top."""
+    span = zoom_resolution(zoom) * 256
+    last = 2**zoom - 1
+    col = math.floor((x + ORIGIN_SHIFT) / span)
+    row = math.floor((ORIGIN_SHIFT - y) / span)
+    return max(0, min(last, row)), max(0, min(last, col))
+
+
+def histogram_label(index: int) -> str:
+    """Label of a histogram bucket, e.g. "<=100 ms" or ">5000 ms"."""
+    if index < len(HISTOGRAM_BOUNDS_MS):
+        return f"<={HISTOGRAM_BOUNDS_MS[index]} ms"
+    return f">{HISTOGRAM_BOUNDS_MS[-1]} ms"
+
+
+def histogram(seconds: list[float]) -> list[int]:
+    """Count latencies per HISTOGRAM_BOUNDS_MS bucket."""
+    counts = [0] * (len(HISTOGRAM_BOUNDS_MS) + 1)
+    for value in seconds:
+        ms = value * 1000
+        index = next(
+            (i for i, bound in enumerate(HISTOGRAM_BOUNDS_MS) if ms <= bound),
+            len(HISTOGRAM_BOUNDS_MS),
+        )
+        counts[index] += 1
+    return counts
+
+
+@dataclass
+class LoadSample:
+    """One timed request."""
+
+    seconds: float
+    error: str | None = None
+
+
+@dataclass
+class StageStats:
+    """Throughput and latency at one concurrency level."""
+
+    concurrency: int
+    seconds: float  # Wall time of the stage
+    requests: int
+    errors: int
+    p50: float | None
+    p90: float | None
+    p99: float | None
+    max: float | None
+    histogram: list[int]
+    first_error: str | None = None
+
+    @classmethod
+    def from_samples(
+        cls, concurrency: int, seconds: float, samples: list[LoadSample]
+    ) -> "StageStats":
+        """Summarize the requests of a stage; percentiles cover successful ones."""
+        times = [s.seconds for s in samples if s.error is None]
+        errors = [s.error for s in samples if s.error is not None]
+        return cls(
+            concurrency=concurrency,
+            seconds=seconds,
+            requests=len(samples),
+            errors=len(errors),
+            p50=percentile(times, 0.5),
+            p90=percentile(times, 0.9),
+            p99=percentile(times, 0.99),
+            max=max(times) if times else None,
+            histogram=histogram(times),
+            first_error=errors[0] if errors else None,
+        )
+
+    @property
+    def throughput(self) -> float:
+        """Requests per second."""
+        return self.requests / self.seconds if self.seconds > 0 else 0.0
+
+    @property
+    def error_rate(self) -> float:
+        """Fraction of requests that failed."""
+        return self.errors / self.requests if self.requests else 0.0
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for JSON reports."""
+        return {
+            "concurrency": self.concurrency,
+            "seconds": round(self.seconds, 3),
+            "requests": self.requests,
+            "errors": self.errors,
+            "errorRate": round(self.error_rate, 4),
+            "throughput": round(self.throughput, 2),
+            "p50": self.p50,
+            "p90": self.p90,
+            "p99": self.p99,
+            "max": self.max,
+            "histogram": {histogram_label(i): count for i, count in enumerate(self.histogram)},
+            "firstError": self.first_error,
+        }
+
+    def to_record(self) -> dict[str, Any]:
+        """Flatten for CSV and table output, with times in milliseconds."""
+
+        def ms(value: float | None) -> int | None:
+            return None if value is None else round(value * 1000)
+
+        record = {
+            "concurrency": self.concurrency,
+            "requests": self.requests,
+            "errors": self.errors,
+            "error_rate": f"{self.error_rate:.1%}",
+            "req_per_s": f"{self.throughput:.1f}",
+            "p50_ms": ms(self.p50),
+            "p90_ms": ms(self.p90),
+            "p99_ms": ms(self.p99),
+            "max_ms": ms(self.max),
+        }
+        for i, count in enumerate(self.histogram):
+            record[histogram_label(i).replace(" ", "")] = count
+        return record
+
+
+@dataclass
+class LoadTestReport:
+    """Results of a load test of a layer."""
+
+    workspace: str
+    layer: str
+    kind: str
+    stage_seconds: float
+    zoom: int | None = None  # GetMap and GetTile requests only
+    stages: list[StageStats] = field(default_factory=list)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for JSON reports."""
+        return {
+            "workspace": self.workspace,
+            "layer": self.layer,
+            "request": self.kind,
+            "stageSeconds": self.stage_seconds,
+            "zoom": self.zoom,
+            "stages": [stage.to_dict() for stage in self.stages],
+        }
+
+    def records(self) -> list[dict[str, Any]]:
+        """One flat record per stage, for CSV and table output."""
+        return [stage.to_record() for stage in self.stages]
+
+    @property
+    def error_rate(self) -> float:
+        """Fraction of all requests that failed."""
+        requests = sum(stage.requests for stage in self.stages)
+        return sum(stage.errors for stage in self.stages) / requests if requests else 0.0
+
+
+def format_chart(report: LoadTestReport, width: int = 40) -> str:
+    """Draw the stages' latencies and the overall histogram with block characters.
+
+    Each stage's bar is solid up to its p50 and shaded up to its p99, on a
+    scale shared by all stages.
+    """
+    lines = ["Latency by concurrency (solid p50, shaded to p99)"]
+    longest = max((stage.p99 or 0.0 for stage in report.stages), default=0.0)
+    for stage in report.stages:
+        if stage.p50 is None or stage.p99 is None or longest <= 0:
+            bar = ""
+        else:
+            solid = max(1, round(stage.p50 / longest * width))
+            bar = BAR * solid + TAIL * max(0, round(stage.p99 / longest * width) - solid)
+        notes = f"{stage.throughput:.1f} req/s"
+        if stage.errors:
+            notes += f", {stage.error_rate:.1%} errors"
+        p50 = "-" if stage.p50 is None else f"{stage.p50 * 1000:.0f}"
+        p99 = "-" if stage.p99 is None else f"{stage.p99 * 1000:.0f}"
+        lines.append(f"{stage.concurrency:>4} | {bar:<{width}} {p50}/{p99} ms  {notes}")
+
+    counts = [sum(column) for column in zip(*(stage.histogram for stage in report.stages))]
+    if counts and max(counts):
+        lines += ["", "Latency histogram (all stages)"]
+        for i, count in enumerate(counts):
+            bar = BAR * math.ceil(count / max(counts) * width)
+            lines.append(f"{histogram_label(i):>9} | {bar:<{width}} {count}")
+    return "\n".join(lines)
+
+
+class _Requester:
+    """Sends one kind of request at places taken in turn from a sampled list."""
+
+    def __init__(
+        self,
+        client: "GeoServerClient",
+        gwc_client: "GWCClient | None",
+        workspace: str,
+        layer: str,
+        kind: str,
+        zoom: int | None,
+        bboxes: list[Bounds],
+    ) -> None:
+        self.client = client
+        self.gwc_client = gwc_client
+        self.workspace = workspace
+        self.layer = layer
+        self.kind = kind
+        self.zoom = zoom
+        self.bboxes = bboxes
+        self._next = 0
+        self._lock = threading.Lock()
+
+    def _bbox(self) -> Bounds:
+        with self._lock:
+            bbox = self.bboxes[self._next % len(self.bboxes)]
+            self._next += 1
+        return bbox
+
+    def send(self) -> None:
+        """Send a request.
+
+        Raises:
+            GeoServerError: If it fails
+        """
+        if self.kind == "getfeature":
+            self.client.get_features(self.workspace, self.layer, FEATURE_COUNT)
+            return
+        bbox = self._bbox()
+        if self.kind == "getmap":
+            self.client.get_map(self.workspace, self.layer, bbox, MAP_SIZE, MAP_SIZE)
+            return
+        row, col = tile_for_point((bbox[0] + bbox[2]) / 2, (bbox[1] + bbox[3]) / 2, self.zoom)
+        params = wmts_tile_params(
+            f"{self.workspace}:{self.layer}",
+            TILE_GRID_SET,
+            PREFERRED_FORMAT,
+            f"{TILE_GRID_SET}:{self.zoom}",
+            row,
+            col,
+        )
+        response = self.gwc_client.get_tile_service("wmts", params)
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"GetTile failed: HTTP {response.status_code}", status_code=response.status_code
+            )
+
+
+def run_load_test(
+    client: "GeoServerClient",
+    gwc_client: "GWCClient | None",
+    workspace: str,
+    layer: str,
+    kind: str = "getmap",
+    ramp: list[int] | None = None,
+    stage_seconds: float = DEFAULT_STAGE_SECONDS,
+    zoom: int | None = None,
+    seed: int = 0,
+    clock: Callable[[], float] = time.perf_counter,
+    on_stage: Callable[[StageStats], None] | None = None,
+) -> LoadTestReport:
+    """Send concurrent requests at a layer, stage by stage up a concurrency ramp.
+
+    Args:
+        client: GeoServer client
+        gwc_client: GWC client, needed for GetTile requests
+        workspace: Workspace name
+        layer: Layer name
+        kind: One of REQUEST_KINDS
+        ramp: Concurrency of each stage; DEFAULT_RAMP when None
+        stage_seconds: Duration of each stage
+        zoom: Web Mercator zoom level of GetMap and GetTile requests; two
+            levels below the one fitting the layer when None
+        seed: Seed of the sampled places, the same for runs to compare
+        clock: Time source, in seconds
+        on_stage: Called after each stage, e.g. to show progress
+
+    Raises:
+        ValueError: If the settings are invalid
+        GeoServerError: If the layer's bounds can't be read
+    """
+    if kind not in REQUEST_KINDS:
+        raise ValueError(f"Unknown request type: {kind}")
+    if kind == "gettile" and gwc_client is None:
+        raise ValueError("GetTile requests need a GWC client")
+    ramp = list(ramp or DEFAULT_RAMP)
+    if any(not 1 <= level <= MAX_CONCURRENCY for level in ramp):
+        raise ValueError(f"Concurrency levels must be between 1 and {MAX_CONCURRENCY}")
+    if not 0 < stage_seconds <= MAX_STAGE_SECONDS:
+        raise ValueError(f"Stages must last between 0 and {MAX_STAGE_SECONDS:.0f} seconds")
+    if zoom is not None and not 0 <= zoom <= MAX_ZOOM:
+        raise ValueError(f"The zoom level must be between 0 and {MAX_ZOOM}")
+
+    bboxes: list[Bounds] = []
+    if kind != "getfeature":
+        latlon = client.get_layer_bounds(workspace, layer).get("latLonBoundingBox")
+        if not latlon:
+            raise GeoServerError(f"{workspace}:{layer} has no lat/lon bounds", status_code=400)
+        bounds = lonlat_to_web_mercator(
+            tuple(float(latlon[key]) for key in ("minx", "miny", "maxx", "maxy"))
+        )
+        if zoom is None:
+            zoom = min(MAX_ZOOM, fitting_zoom(bounds, MAP_SIZE) + 2)
+        bboxes = sample_bboxes(bounds, zoom, MAP_SIZE, PLACES, random.Random(seed))
+    else:
+        zoom = None
+
+    requester = _Requester(client, gwc_client, workspace, layer, kind, zoom, bboxes)
+    report = LoadTestReport(workspace, layer, kind, stage_seconds, zoom)
+    for concurrency in ramp:
+        samples: list[LoadSample] = []
+        lock = threading.Lock()
+        started = clock()
+        deadline = started + stage_seconds
+
+        def work() -> None:
+            while clock() < deadline:
+                sent = clock()
+                try:
+                    requester.send()
+                    sample = LoadSample(clock() - sent)
+                except GeoServerError as e:
+                    sample = LoadSample(clock() - sent, error=str(e))
+                with lock:
+                    samples.append(sample)
+
+        with ThreadPoolExecutor(max_workers=concurrency) as pool:
+            for future in [pool.submit(work) for _ in range(concurrency)]:
+                future.result()
+
+        stage = StageStats.from_samples(concurrency, clock() - started, samples)
+        report.stages.append(stage)
+        if on_stage:
+            on_stage(stage)
+    return report
@@ -0,0 +1,237 @@
+"""Resources this client created on each connection.
+
+Like Terraform's state, a file per connection lists the workspaces,
+stores, layers, styles and layer groups created through the client, with
+a hash of what was last sent for each. Operations that replace or delete
+resources to match a source, such as a sync with pruning, only touch the
+resources listed there, so whatever other admins made by hand is left
+alone. Resources made before the state file existed can be adopted.
+
+The hashes tell whether a source changed since it was last applied, and
+for styles, whether someone edited the copy on the server since.
+"""
+
+import hashlib
+import json
+import logging
+import re
+import threading
+import time
+from dataclasses import dataclass
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import TYPE_CHECKING, Any
+
+from apps.core.config import get_state_dir
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+logger = logging.getLogger(__name__)
+
+STATE_DIR = "state"
+
+KINDS = ("workspace", "datastore", "coveragestore", "layer", "style", "layergroup")
+
+# Connection parameters left out of store hashes
+SECRET_PARAMS = frozenset({"passwd", "password", "secret_key", "access_key"})
+
+
+def content_hash(content: Any) -> str:
+    """Hash style content, file bytes or a JSON payload."""
+    if isinstance(content, str):
+        data = content.encode("utf-8")
+    elif isinstance(content, bytes):
+        data = content
+    else:
+        data = json.dumps(content, sort_keys=True, default=str).encode("utf-8")
+    return hashlib.sha256(data).hexdigest()
+
+
+def store_hash(connection_params: dict[str, Any], **fields: Any) -> str:
+    """Hash a store's settings without its secrets."""
+    params = {k: v for k, v in connection_params.items() if k not in SECRET_PARAMS}
+    return content_hash({**fields, "connectionParameters": params})
+
+
+@dataclass
+class ManagedResource:
+    """A resource created or adopted by the client."""
+
+    kind: str  # One of KINDS
+    workspace: str | None  # None for global styles and layer groups, and workspaces
+    name: str
+    hash: str
+    created: float
+    updated: float
+    adopted: bool = False  # Existed before the client took it over
+
+    @property
+    def key(self) -> str:
+        return _key(self.kind, self.workspace, self.name)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "kind": self.kind,
+            "workspace": self.workspace,
+            "name": self.name,
+            "hash": self.hash,
+            "created": datetime.fromtimestamp(self.created, timezone.utc).isoformat(),
+            "updated": datetime.fromtimestamp(self.updated, timezone.utc).isoformat(),
+            "adopted": self.adopted,
+        }
+
+
+def _key(kind: str, workspace: str | None, name: str) -> str:
+    return f"{kind}:{workspace or ''}:{name}"
+
+
+class ManagedState:
+    """State files listing the resources managed on each connection."""
+
+    _lock = threading.Lock()
+
+    def __init__(self, directory: Path | None = None):
+        """Initialize the state.
+
+        Args:
+            directory: Directory holding a JSON file per connection; the
+                state directory by default
+        """
+        self._directory = directory
+
+    def path(self, conn_id: str) -> Path:
+        """The state file of a connection."""
+        directory = self._directory or get_state_dir() / STATE_DIR
+        directory.mkdir(parents=True, exist_ok=True)
+        return directory / f"{re.sub(r'[^A-Za-z0-9_.-]', '_', conn_id)}.json"
+
+    def _load(self, conn_id: str) -> dict[str, dict[str, Any]]:
+        try:
+            data = json.loads(self.path(conn_id).read_text())
+        except (OSError, ValueError):
+            return {}
+        resources = data.get("resources") if isinstance(data, dict) else None
+        return resources if isinstance(resources, dict) else {}
+
+    def _save(self, conn_id: str, resources: dict[str, dict[str, Any]]) -> None:
+        path = self.path(conn_id)
+        tmp_path = path.with_suffix(".tmp")
+        try:
+            tmp_path.write_text(
+                json.dumps({"connection": conn_id, "resources": resources}, indent=2)
+            )
+            tmp_path.replace(path)
+        except OSError as e:
+            # Losing track of a resource only makes it unmanaged; never fail the change
+            logger.warning("Could not write the state of %s: %s", conn_id, e)
+
+    def add(
+        self,
+        conn_id: str,
+        kind: str,
+        workspace: str | None,
+        name: str,
+        hash: str = "",
+        adopted: bool = False,
+    ) -> None:
+        """Mark a resource as created, replaced or adopted by the client.
+
+        Args:
+            conn_id: Connection ID
+            kind: One of KINDS
+            workspace: Workspace name; None for workspaces and global resources
+            name: Resource name
+            hash: Hash of what was sent, see content_hash
+            adopted: The resource existed before and is taken over
+        """
+        now = time.time()
+        with self._lock:
+            resources = self._load(conn_id)
+            key = _key(kind, workspace, name)
+            # Replacing a managed resource keeps when and how it came under management
+            entry = resources.get(key) or {"created": now, "adopted": adopted}
+            entry.update(kind=kind, workspace=workspace, name=name, hash=hash, updated=now)
+            resources[key] = entry
+            self._save(conn_id, resources)
+
+    def touch(self, conn_id: str, kind: str, workspace: str | None, name: str, hash: str) -> None:
+        """Update the hash of a managed resource; others stay unmanaged."""
+        with self._lock:
+            resources = self._load(conn_id)
+            entry = resources.get(_key(kind, workspace, name))
+            if entry is None:
+                return
+            entry["hash"] = hash
+            entry["updated"] = time.time()
+            self._save(conn_id, resources)
+
+    def remove(self, conn_id: str, kind: str, workspace: str | None, name: str) -> None:
+        """Forget a deleted resource; a workspace takes its contents along."""
+        with self._lock:
+            resources = self._load(conn_id)
+            gone = [
+                key
+                for key, entry in resources.items()
+                if key == _key(kind, workspace, name)
+                or (kind == "workspace" and entry.get("workspace") == name)
+            ]
+            for key in gone:
+                del resources[key]
+            if gone:
+                self._save(conn_id, resources)
+
+    def get(
+        self, conn_id: str, kind: str, workspace: str | None, name: str
+    ) -> ManagedResource | None:
+        """A managed resource, or None if the client doesn't manage it."""
+        with self._lock:
+            entry = self._load(conn_id).get(_key(kind, workspace, name))
+        return ManagedResource(**entry) if entry else None
+
+    def is_managed(self, conn_id: str, kind: str, workspace: str | None, name: str) -> bool:
+        return self.get(conn_id, kind, workspace, name) is not None
+
+    def resources(
+        self, conn_id: str, kind: str | None = None, workspace: str | None = None
+    ) -> list[ManagedResource]:
+        """List a connection's managed resources, optionally of a kind or workspace."""
+        with self._lock:
+            entries = list(self._load(conn_id).values())
+        found = [ManagedResource(**entry) for entry in entries]
+        return sorted(
+            (
+                r
+                for r in found
+                if (kind is None or r.kind == kind)
+                and (
+                    workspace is None
+                    or r.workspace == workspace
+                    or (r.kind == "workspace" and r.name == workspace)
+                )
+            ),
+            key=lambda r: (r.kind, r.workspace or "", r.name),
+        )
+
+
+managed_state = ManagedState()
+
+
+def drifted_styles(client: "GeoServerClient") -> list[ManagedResource]:
+    """List managed styles whose content on the server no longer matches the state.
+
+    Styles that can't be read, e.g. because someone deleted them, are
+    listed too.
+    """
+    drifted = []
+    for resource in managed_state.resources(client.connection.id, "style"):
+        try:
+            content, _ = client.get_style_content(resource.name, resource.workspace)
+        except GeoServerError:
+            drifted.append(resource)
+            continue
+        if resource.hash and content_hash(content) != resource.hash:
+            drifted.append(resource)
+    return drifted
@@ -0,0 +1,174 @@
+"""Load test a layer's OWS endpoints with a concurrency ramp.
+
+Usage:
+    cloudbench bench topp:roads "Production GeoServer"
+    cloudbench bench topp:roads conn_123 --request gettile --ramp 1,8,32 --duration 30
+    cloudbench bench topp:roads conn_123 --output json > roads.json
+    cloudbench bench topp:roads conn_123 --report roads.csv --max-error-rate 0.01
+"""
+
+import json
+from pathlib import Path
+
+from django.core.management.base import BaseCommand, CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.core.output import FORMATS, format_csv, format_records, format_yaml
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.load_test import (
+    DEFAULT_RAMP,
+    DEFAULT_STAGE_SECONDS,
+    REQUEST_KINDS,
+    StageStats,
+    format_chart,
+    parse_ramp,
+    run_load_test,
+)
+from apps.gwc.client import get_gwc_client
+
+
+class Command(BaseCommand):
+    """Send concurrent GetMap, GetTile or GetFeature requests at a layer and report latencies."""
+
+    help = (
+        "Load test a layer with concurrent GetMap, GetTile or GetFeature requests, "
+        "stage by stage up a concurrency ramp"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument("layer", help="Layer to load, as workspace:layer")
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument(
+            "--request",
+            "-r",
+            choices=REQUEST_KINDS,
+            default="getmap",
+            help="Request type (default: getmap)",
+        )
+        parser.add_argument(
+            "--ramp",
+            default=",".join(map(str, DEFAULT_RAMP)),
+            help="Concurrent clients of each stage (default: %(default)s)",
+        )
+        parser.add_argument(
+            "--duration",
+            "-d",
+            type=float,
+            default=DEFAULT_STAGE_SECONDS,
+            help="Seconds per stage (default: %(default)s)",
+        )
+        parser.add_argument(
+            "--zoom",
+            "-z",
+            type=int,
+            help="Zoom level of GetMap and GetTile requests; two below the one fitting the layer "
+            "by default",
+        )
+        parser.add_argument(
+            "--seed", type=int, default=0, help="Seed of the places requested (default: 0)"
+        )
+        parser.add_argument(
+            "--output",
+            "-o",
+            choices=FORMATS,
+            default="table",
+            help="Output format; a table and chart by default",
+        )
+        parser.add_argument("--report", help="Also save the report to a .json or .csv file")
+        parser.add_argument(
+            "--max-error-rate",
+            type=float,
+            help="Exit with status 1 if more than this fraction of requests fail, e.g. 0.01",
+        )
+
+    def handle(self, *args, **options):
+        """Run the stages and print the report."""
+        workspace, _, layer = options["layer"].partition(":")
+        if not workspace or not layer:
+            raise CommandError("The layer must be given as workspace:layer")
+        report_path = Path(options["report"]) if options["report"] else None
+        if report_path and report_path.suffix not in (".json", ".csv"):
+            raise CommandError("The report file must end in .json or .csv")
+        try:
+            ramp = parse_ramp(options["ramp"])
+        except ValueError as e:
+            raise CommandError(str(e)) from e
+
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+        if conn.max_concurrent_requests and max(ramp) > conn.max_concurrent_requests:
+            self.stderr.write(
+                self.style.WARNING(
+                    f"{conn.name} allows {conn.max_concurrent_requests} concurrent requests; "
+                    "higher stages queue behind that limit"
+                )
+            )
+
+        def on_stage(stage: StageStats) -> None:
+            self.stderr.write(
+                f"{stage.concurrency} client(s): {stage.requests} requests, "
+                f"{stage.throughput:.1f} req/s, {stage.errors} error(s)"
+            )
+
+        try:
+            report = run_load_test(
+                get_geoserver_client(conn.id),
+                get_gwc_client(conn.id) if options["request"] == "gettile" else None,
+                workspace,
+                layer,
+                kind=options["request"],
+                ramp=ramp,
+                stage_seconds=options["duration"],
+                zoom=options["zoom"],
+                seed=options["seed"],
+                on_stage=on_stage,
+            )
+        except ValueError as e:
+            raise CommandError(str(e)) from e
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+
+        if options["output"] == "json":
+            self.stdout.write(json.dumps(report.to_dict(), indent=2))
+        elif options["output"] == "yaml":
+            self.stdout.write(format_yaml(report.to_dict()))
+        else:
+            self.stdout.write(format_records(report.records(), options["output"]))
+            if options["output"] == "table":
+                self.stdout.write("\n" + format_chart(report))
+                for stage in report.stages:
+                    if stage.first_error:
+                        self.stdout.write(
+                            self.style.ERROR(
+                                f"First error at {stage.concurrency} client(s): "
+                                f"{stage.first_error}"
+                            )
+                        )
+
+        if report_path:
+            if report_path.suffix == ".json":
+                text = json.dumps(report.to_dict(), indent=2)
+            else:
+                text = format_csv(report.records())
+            report_path.write_text(text + "\n")
+            self.stderr.write(f"Report saved to {report_path}")
+
+        limit = options["max_error_rate"]
+        if limit is not None and report.error_rate > limit:
+            self.stderr.write(
+                self.style.ERROR(f"{report.error_rate:.1%} of requests failed (limit {limit:.1%})")
+            )
+            raise SystemExit(1)
@@ -0,0 +1,125 @@
+"""Create a workspace, optionally from a workspace template.
+
+Usage:
+    cloudbench create_workspace roads "Production GeoServer"
+    cloudbench create_workspace roads conn_123 --template "standard project"
+    cloudbench create_workspace roads conn_123 --template "standard project" --output json
+    cloudbench create_workspace roads conn_123 --template "standard project" --if-absent
+    cloudbench create_workspace --list-templates
+"""
+
+from django.core.management.base import CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError, NamingError
+from apps.core.output import OutputCommand
+from apps.geoserver.client import CreateResult, get_geoserver_client
+from apps.geoserver.naming import validate_name
+from apps.geoserver.workspace_templates import (
+    TemplateStep,
+    create_workspace_from_template,
+    get_template,
+    list_templates,
+    template_to_dict,
+)
+
+COLUMNS = ["kind", "name", "ok", "existed", "error"]
+TEMPLATE_COLUMNS = ["name", "description", "services", "datastores", "styles"]
+
+
+class Command(OutputCommand):
+    """Create a workspace and set it up from a template's stores, styles and services."""
+
+    quiet_key = "name"
+    help = "Create a workspace, optionally set up from a workspace template"
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument("name", nargs="?", help="Workspace name")
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument("--template", "-t", help="Workspace template to set it up from")
+        isolation = parser.add_mutually_exclusive_group()
+        isolation.add_argument(
+            "--isolated",
+            action="store_true",
+            default=None,
+            help="Make the workspace isolated; the template decides by default",
+        )
+        isolation.add_argument(
+            "--not-isolated", dest="isolated", action="store_false", help="Don't isolate it"
+        )
+        parser.add_argument(
+            "--if-absent",
+            action="store_true",
+            help=(
+                "Keep the workspace, and a template's stores and styles, if they already "
+                "exist; safe to run again after a timeout"
+            ),
+        )
+        parser.add_argument(
+            "--list-templates", action="store_true", help="List the workspace templates and exit"
+        )
+        self.add_output_arguments(parser)
+
+    def handle(self, *args, **options):
+        """Create the workspace and print each setup step."""
+        if options["list_templates"]:
+            self.write_records(
+                [template_to_dict(t) for t in list_templates()], options, TEMPLATE_COLUMNS
+            )
+            return
+
+        name = options["name"]
+        if not name:
+            raise CommandError("A workspace name is required")
+        try:
+            validate_name("workspace", name)
+        except NamingError as e:
+            raise CommandError(e.message) from e
+
+        template = None
+        if options["template"]:
+            template = get_template(options["template"])
+            if template is None:
+                names = ", ".join(t.name for t in list_templates())
+                raise CommandError(
+                    f"Unknown workspace template: {options['template']} (available: {names})"
+                )
+
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+
+        client = get_geoserver_client(conn.id)
+        try:
+            if template is None:
+                result = client.create_workspace(
+                    name, isolated=bool(options["isolated"]), if_absent=options["if_absent"]
+                )
+                steps = [
+                    TemplateStep("workspace", name, existed=result == CreateResult.ALREADY_EXISTS)
+                ]
+            else:
+                steps = create_workspace_from_template(
+                    client,
+                    name,
+                    template,
+                    isolated=options["isolated"],
+                    if_absent=options["if_absent"],
+                )
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+
+        self.write_records([s.to_dict() for s in steps], options, COLUMNS)
+        if any(not s.ok for s in steps):
+            raise SystemExit(1)
@@ -0,0 +1,97 @@
+"""Write a Mapbox GL style or MapProxy snippet for published layers.
+
+Usage:
+    cloudbench export_map "Production GeoServer" topp:basemap topp:roads
+    cloudbench export_map conn_123 topp:roads --format mapproxy --dir ./deploy
+    cloudbench export_map conn_123 topp:roads --name city --stdout | jq .sources
+"""
+
+from pathlib import Path
+
+from django.core.management.base import BaseCommand, CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.gwc.map_export import (
+    DEFAULT_GRID_SET,
+    DEFAULT_TILE_FORMAT,
+    FORMATS,
+    collect_layers,
+    export_map,
+    write_export,
+)
+
+
+class Command(BaseCommand):
+    """Generate a web map configuration reading the layers' cached tiles."""
+
+    help = (
+        "Write a Mapbox GL style or a MapProxy configuration snippet drawing layers and "
+        "layer groups from GeoWebCache, first layer at the bottom"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument("layers", nargs="*", help="Qualified layer or layer group names")
+        parser.add_argument(
+            "--format", "-f", choices=FORMATS, default="mapbox", help="Configuration to write"
+        )
+        parser.add_argument(
+            "--name", help="Style name, also naming the file; the connection's name by default"
+        )
+        parser.add_argument(
+            "--gridset",
+            default=DEFAULT_GRID_SET,
+            help="Web Mercator grid set the layers are cached in (default: %(default)s)",
+        )
+        parser.add_argument(
+            "--tile-format",
+            default=DEFAULT_TILE_FORMAT,
+            help="Tile MIME type (default: %(default)s)",
+        )
+        parser.add_argument(
+            "--dir", help="Folder to write to; the file browser's last folder by default"
+        )
+        parser.add_argument(
+            "--stdout", action="store_true", help="Print the configuration instead of writing it"
+        )
+
+    def handle(self, *args, **options):
+        """Generate the configuration and write or print it."""
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+        if not options["layers"]:
+            raise CommandError("Name at least one layer, e.g. topp:roads")
+
+        try:
+            export = export_map(
+                conn.public_base_url,
+                collect_layers(get_geoserver_client(conn.id), options["layers"]),
+                options["format"],
+                options["name"] or conn.name,
+                options["gridset"],
+                options["tile_format"],
+            )
+            if options["stdout"]:
+                self.stdout.write(export.content, ending="")
+                return
+            directory = Path(options["dir"] or config_manager.config.last_local_path)
+            path = write_export(export, directory.expanduser())
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+        except (OSError, ValueError) as e:
+            raise CommandError(str(e)) from e
+        self.stdout.write(f"Wrote {path}")
@@ -0,0 +1,106 @@
+"""Import a local folder of SLD and CSS styles, a style package, or a style by URL.
+
+Usage:
+    cloudbench import_styles ./styles "Production GeoServer" --workspace topp
+    cloudbench import_styles ./markers.zip conn_123 --workspace topp
+    cloudbench import_styles ./styles conn_123 --recursive --output json
+    cloudbench import_styles ./styles conn_123 --dry-run
+    cloudbench import_styles https://github.com/org/styles/blob/main/roads.sld conn_123
+"""
+
+from pathlib import Path
+
+from django.core.management.base import CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.core.output import OutputCommand
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.style_import import (
+    count_results,
+    fetch_style,
+    import_styles,
+    is_style_url,
+    read_style_file,
+    scan_style_directory,
+)
+
+COLUMNS = ["path", "name", "status", "error", "warning"]
+
+
+class Command(OutputCommand):
+    """Create or update a style for every .sld, .css and .zip file of a folder, or from a URL."""
+
+    quiet_key = "name"
+    help = (
+        "Import a folder of .sld and .css files and .zip style packages, a single file, "
+        "or a style URL, skipping unchanged styles"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument(
+            "source", help="Folder of style files, a style file or package, or an http(s) URL"
+        )
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument(
+            "--workspace", "-w", help="Workspace to import into; global styles by default"
+        )
+        parser.add_argument(
+            "--recursive", "-r", action="store_true", help="Also import files in subfolders"
+        )
+        parser.add_argument(
+            "--dry-run", action="store_true", help="List the styles found without importing"
+        )
+        parser.add_argument(
+            "--authorization",
+            help="Authorization header for a private URL, e.g. \"token ...\"; "
+            "defaults to $CLOUDBENCH_STYLE_AUTHORIZATION",
+        )
+        self.add_output_arguments(parser)
+
+    def handle(self, *args, **options):
+        """Import the styles and print what happened to each."""
+        source = options["source"]
+        try:
+            if is_style_url(source):
+                files = [fetch_style(source, options["authorization"])]
+            elif Path(source).is_file():
+                files = [read_style_file(source)]
+            else:
+                files = scan_style_directory(source, options["recursive"])
+        except ValueError as e:
+            raise CommandError(str(e)) from e
+        if not files:
+            raise CommandError(f"No .sld, .css or .zip files in {source}")
+
+        if options["dry_run"]:
+            records = [{"path": f.path, "name": f.name, "format": f.format} for f in files]
+            self.write_records(records, options)
+            return
+
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+
+        try:
+            results = import_styles(get_geoserver_client(conn.id), files, options["workspace"])
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+
+        self.write_records([r.to_dict() for r in results], options, COLUMNS)
+        counts = count_results(results)
+        if not options["quiet"] and options["output"] == "table":
+            self.stdout.write("\n" + ", ".join(f"{n} {status}" for status, n in counts.items()))
+        if counts["failed"]:
+            raise SystemExit(1)
@@ -0,0 +1,86 @@
+"""Check a connection's catalog for broken references.
+
+Usage:
+    cloudbench lint "Production GeoServer"
+    cloudbench lint conn_123 --check missing_default_style --output json
+    cloudbench lint conn_123 --quiet
+"""
+
+from django.core.management.base import CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.core.output import FORMATS, OutputCommand
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.lint import CHECKS, lint_catalog
+from apps.gwc.client import get_gwc_client
+
+
+class Command(OutputCommand):
+    """Scan a GeoServer connection for broken catalog references."""
+
+    quiet_key = "resource"
+    help = "Scan a GeoServer connection for broken layer, style, store and cache references"
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument(
+            "--check",
+            action="append",
+            choices=list(CHECKS),
+            dest="checks",
+            help="Check to run (repeatable); all checks by default",
+        )
+        parser.add_argument(
+            "--json", action="store_true", help="Print issues as JSON (same as --output json)"
+        )
+        parser.add_argument(
+            "--output",
+            "-o",
+            choices=FORMATS,
+            help="Output format; issues with their fixes by default",
+        )
+        parser.add_argument(
+            "--quiet", "-q", action="store_true", help="Print only the affected resources"
+        )
+
+    def handle(self, *args, **options):
+        """Run the checks and print the issues."""
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+
+        try:
+            issues = lint_catalog(
+                get_geoserver_client(conn.id), get_gwc_client(conn.id), options["checks"]
+            )
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+
+        if options["json"]:
+            options["output"] = "json"
+        if options["output"] or options["quiet"]:
+            self.write_records([issue.to_dict() for issue in issues], options)
+        elif not issues:
+            self.stdout.write(self.style.SUCCESS(f"No broken references in {conn.name}"))
+        else:
+            for issue in issues:
+                style = self.style.ERROR if issue.severity == "error" else self.style.WARNING
+                self.stdout.write(style(f"[{issue.severity}] {issue.message}"))
+                self.stdout.write(f"    fix: {issue.fix}")
+            errors = sum(1 for i in issues if i.severity == "error")
+            self.stdout.write(f"\n{errors} error(s), {len(issues) - errors} warning(s)")
+
+        if any(issue.severity == "error" for issue in issues):
+            raise SystemExit(1)
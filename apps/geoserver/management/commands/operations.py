@@ -0,0 +1,121 @@
+"""List, resume and discard batch operations that were cut short.
+
+Usage:
+    cloudbench operations
+    cloudbench operations --kind truncate --output json
+    cloudbench operations --resume 3f2c9e1a-...
+    cloudbench operations --discard 3f2c9e1a-...
+
+Exits with status 1 when a resumed operation still has steps left,
+because some of them failed again.
+"""
+
+from django.core.management.base import CommandError
+
+from apps.core.exceptions import GeoServerError
+from apps.core.oplog import Operation, operation_log
+from apps.core.output import OutputCommand
+from apps.geoserver.client import get_geoserver_client
+from apps.gwc.client import get_gwc_client
+from apps.gwc.truncate import mass_truncate
+from apps.sync.services import SyncJobManager, get_sync_service, operation_config
+from apps.upload.batch import resume_batch
+
+COLUMNS = ["id", "kind", "connection", "done", "pending", "created"]
+
+KINDS = ("truncate", "upload", "sync")
+
+
+class Command(OutputCommand):
+    """Show the operation log of mass truncations, bulk publishes and syncs."""
+
+    quiet_key = "id"
+    help = (
+        "List mass truncations, bulk publishes and syncs that were interrupted, "
+        "and resume them from the last finished step"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument("--kind", choices=KINDS, help="Only operations of this kind")
+        parser.add_argument("--connection", help="Only operations on this connection ID")
+        parser.add_argument(
+            "--resume", metavar="ID", help="Run the steps an operation didn't finish"
+        )
+        parser.add_argument(
+            "--discard", metavar="ID", help="Forget an operation without resuming it"
+        )
+        self.add_output_arguments(parser)
+
+    def handle(self, *args, **options):
+        """Resume or discard an operation, or list those that can be resumed."""
+        if options["discard"]:
+            if not operation_log.discard(options["discard"]):
+                raise CommandError(f"No interrupted operation {options['discard']}")
+            self.stderr.write(f"Discarded {options['discard']}")
+            return
+        if options["resume"]:
+            operation = operation_log.resume(options["resume"])
+            if operation is None:
+                raise CommandError(f"No interrupted operation {options['resume']}")
+            if not self._resume(operation):
+                raise SystemExit(1)
+            return
+
+        operations = operation_log.interrupted(options["connection"], options["kind"])
+        self.write_records(
+            [
+                {
+                    "id": op.id,
+                    "kind": op.kind,
+                    "connection": op.conn_id,
+                    "done": len(op.done),
+                    "pending": len(op.pending),
+                    "created": op.created_at,
+                }
+                for op in operations
+            ],
+            options,
+            COLUMNS,
+        )
+
+    def _resume(self, operation: Operation) -> bool:
+        """Run the pending steps in the foreground; whether none are left."""
+        self.stderr.write(
+            f"Resuming {operation.kind} on {operation.conn_id}: "
+            f"{len(operation.pending)} of {len(operation.steps)} step(s) left"
+        )
+        try:
+            if operation.kind == "truncate":
+                results = mass_truncate(
+                    get_gwc_client(operation.conn_id), operation.pending, operation=operation
+                )
+                for result in results:
+                    self._write_step(result.target, result.ok, result.message)
+            elif operation.kind == "upload":
+                for result in resume_batch(get_geoserver_client(operation.conn_id), operation):
+                    ok = result["status"] == "success"
+                    self._write_step(result["path"], ok, result.get("error", ""))
+            elif operation.kind == "sync":
+                config = operation_config(operation)
+                job = SyncJobManager().create_job(config.id)
+                get_sync_service().run_sync(config, job.id, operation)
+                for step in operation.steps:
+                    if step in operation.failed:
+                        self._write_step(step, False, operation.failed[step])
+            else:
+                raise CommandError(f"Can't resume operations of kind {operation.kind}")
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+        finally:
+            complete = operation_log.close(operation)
+        self.stderr.write(
+            "Done" if complete else f"{len(operation.pending)} step(s) left; resume again later"
+        )
+        return complete
+
+    def _write_step(self, step: str, ok: bool, message: str) -> None:
+        if ok:
+            self.stdout.write(f"{step}: {message or 'done'}")
+        else:
+            self.stderr.write(self.style.ERROR(f"{step}: {message}"))
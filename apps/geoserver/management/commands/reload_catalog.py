@@ -0,0 +1,56 @@
+"""Reload the catalog, or reset caches, on every node of a connection.
+
+Usage:
+    cloudbench reload_catalog "Production GeoServer"
+    cloudbench reload_catalog conn_123 --reset
+    cloudbench reload_catalog conn_123 --output json
+"""
+
+from django.core.management.base import CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.output import OutputCommand
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.cluster import COLUMNS, run_on_nodes
+
+
+class Command(OutputCommand):
+    """Send a catalog reload or cache reset to each node and report the outcomes."""
+
+    quiet_key = "url"
+    help = (
+        "Reload the catalog from the data directory, or with --reset drop the store and "
+        "raster caches, on every node of a connection's cluster"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument(
+            "--reset",
+            action="store_true",
+            help="Reset the caches instead of reloading the catalog",
+        )
+        self.add_output_arguments(parser)
+
+    def handle(self, *args, **options):
+        """Run the action on each node and print one row per node."""
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+
+        action = "reset" if options["reset"] else "reload"
+        result = run_on_nodes(get_geoserver_client(conn.id), action)
+        self.write_records([node.to_dict() for node in result.nodes], options, COLUMNS)
+        if not result.ok:
+            raise SystemExit(1)
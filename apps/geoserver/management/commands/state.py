@@ -0,0 +1,117 @@
+"""List, check, adopt and forget the resources the client manages on a connection.
+
+Usage:
+    cloudbench state "Production GeoServer"
+    cloudbench state conn_123 --kind style --workspace topp --output json
+    cloudbench state conn_123 --check
+    cloudbench state conn_123 --adopt style:topp:roads
+    cloudbench state conn_123 --forget workspace::scratch
+"""
+
+from django.core.management.base import CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.core.output import OutputCommand
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.managed import KINDS, content_hash, drifted_styles, managed_state
+
+COLUMNS = ["kind", "workspace", "name", "adopted", "updated", "drifted"]
+
+
+def parse_resource(ref: str) -> tuple[str, str | None, str]:
+    """Read a kind:workspace:name reference; the workspace is empty for global resources."""
+    parts = ref.split(":")
+    if len(parts) != 3 or parts[0] not in KINDS or not parts[2]:
+        raise CommandError(
+            f"Resources are given as kind:workspace:name with a kind of {', '.join(KINDS)}: {ref}"
+        )
+    kind, workspace, name = parts
+    return kind, workspace or None, name
+
+
+class Command(OutputCommand):
+    """Show the state file listing the resources the client created on a connection."""
+
+    quiet_key = "name"
+    help = "List the resources the client manages on a connection; sync only prunes these"
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument("--kind", choices=KINDS, help="Only resources of this kind")
+        parser.add_argument("--workspace", "-w", help="Only this workspace and what it holds")
+        parser.add_argument(
+            "--check",
+            action="store_true",
+            help="Compare managed styles with the server and mark those edited since",
+        )
+        parser.add_argument(
+            "--adopt",
+            action="append",
+            default=[],
+            metavar="KIND:WORKSPACE:NAME",
+            help="Take over an existing resource so sync may update and prune it (repeatable)",
+        )
+        parser.add_argument(
+            "--forget",
+            action="append",
+            default=[],
+            metavar="KIND:WORKSPACE:NAME",
+            help="Stop managing a resource, leaving it on the server (repeatable)",
+        )
+        self.add_output_arguments(parser)
+
+    def handle(self, *args, **options):
+        """Apply any adoptions and forgets, then print the managed resources."""
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+
+        adopt = [parse_resource(r) for r in options["adopt"]]
+        forget = [parse_resource(r) for r in options["forget"]]
+        client = get_geoserver_client(conn.id) if adopt or options["check"] else None
+
+        for kind, workspace, name in adopt:
+            hash = ""
+            try:
+                if kind == "style":
+                    # Lets --check tell later edits apart
+                    hash = content_hash(client.get_style_content(name, workspace)[0])
+                elif kind == "workspace":
+                    client.get_workspace(name)
+            except GeoServerError as e:
+                raise CommandError(f"Can't adopt {kind} {name}: {e.message}") from e
+            managed_state.add(conn.id, kind, workspace, name, hash, adopted=True)
+        for kind, workspace, name in forget:
+            if not managed_state.is_managed(conn.id, kind, workspace, name):
+                raise CommandError(f"Not managed: {kind}:{workspace or ''}:{name}")
+            managed_state.remove(conn.id, kind, workspace, name)
+
+        drifted: set[str] = set()
+        if options["check"]:
+            try:
+                drifted = {r.key for r in drifted_styles(client)}
+            except GeoServerError as e:
+                raise CommandError(e.message) from e
+
+        resources = managed_state.resources(conn.id, options["kind"], options["workspace"])
+        records = []
+        for resource in resources:
+            record = resource.to_dict()
+            record["drifted"] = resource.key in drifted if options["check"] else None
+            records.append(record)
+        self.write_records(records, options, COLUMNS)
+
+        if drifted:
+            raise SystemExit(1)
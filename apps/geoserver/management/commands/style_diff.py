@@ -0,0 +1,153 @@
+"""Show what a local style file or a sync would change in a connection's styles.
+
+Usage:
+    cloudbench style_diff ./roads.sld "Production GeoServer" --workspace topp
+    cloudbench style_diff ./styles conn_123 --recursive
+    cloudbench style_diff conn_staging --against conn_prod --workspace topp
+    cloudbench style_diff conn_staging --against conn_prod --name roads --output json
+
+Exits with status 1 when anything would change, like diff.
+"""
+
+import json
+from pathlib import Path
+
+from django.core.management.base import BaseCommand, CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import Connection, config_manager
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.style_diff import (
+    CONTEXT_LINES,
+    StyleDiff,
+    diff_connection_style,
+    diff_connection_styles,
+    diff_local_style,
+)
+from apps.geoserver.style_import import read_style_file, scan_style_directory
+
+
+def find_connection(ref: str | None) -> Connection:
+    """A connection by ID or name, picked interactively if not given."""
+    ref = ref or fuzzy_pick([c.name for c in config_manager.list_connections()], "connection")
+    if not ref:
+        raise CommandError("A connection is required")
+    conn = config_manager.get_connection(ref) or next(
+        (c for c in config_manager.list_connections() if c.name == ref), None
+    )
+    if conn is None:
+        raise CommandError(f"Connection not found: {ref}")
+    return conn
+
+
+class Command(BaseCommand):
+    """Print unified diffs of styles against local files or another connection."""
+
+    help = (
+        "Diff local .sld and .css files against a connection's styles, or a connection's "
+        "styles against the copies a sync would replace on another"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument(
+            "source",
+            help="Style file or folder, or with --against the connection styles are copied from",
+        )
+        parser.add_argument(
+            "connection",
+            nargs="?",
+            help="Connection whose styles the files would replace; picked interactively "
+            "if omitted",
+        )
+        parser.add_argument(
+            "--against", help="Connection ID or name a sync would copy the source's styles to"
+        )
+        parser.add_argument("--workspace", "-w", help="Workspace of the styles; global by default")
+        parser.add_argument("--name", help="Only this style of the source connection")
+        parser.add_argument(
+            "--recursive", "-r", action="store_true", help="Also diff files in subfolders"
+        )
+        parser.add_argument(
+            "--context",
+            "-U",
+            type=int,
+            default=CONTEXT_LINES,
+            help="Unchanged lines around each change (default: %(default)s)",
+        )
+        parser.add_argument(
+            "--all", action="store_true", help="Also list styles that wouldn't change"
+        )
+        parser.add_argument(
+            "--output",
+            "-o",
+            choices=["diff", "json"],
+            default="diff",
+            help="A colorized unified diff, or JSON with one entry per style",
+        )
+
+    def handle(self, *args, **options):
+        """Compute the diffs and print them."""
+        workspace = options["workspace"]
+        context = max(0, options["context"])
+        try:
+            if options["against"]:
+                if options["connection"]:
+                    raise CommandError("Give either a connection or --against, not both")
+                source = get_geoserver_client(find_connection(options["source"]).id)
+                dest = get_geoserver_client(find_connection(options["against"]).id)
+                if options["name"]:
+                    diffs = [
+                        diff_connection_style(source, dest, options["name"], workspace, context)
+                    ]
+                else:
+                    diffs = diff_connection_styles(source, dest, workspace, context)
+            else:
+                path = Path(options["source"]).expanduser()
+                try:
+                    if path.is_file():
+                        files = [read_style_file(path)]
+                    else:
+                        files = scan_style_directory(path, options["recursive"])
+                except ValueError as e:
+                    raise CommandError(str(e)) from e
+                if not files:
+                    raise CommandError(f"No .sld, .css or .zip files in {path}")
+                client = get_geoserver_client(find_connection(options["connection"]).id)
+                diffs = [diff_local_style(client, f, workspace, context) for f in files]
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+
+        changed = [d for d in diffs if d.status != "unchanged"]
+        shown = diffs if options["all"] else changed
+        if options["output"] == "json":
+            self.stdout.write(json.dumps([d.to_dict() for d in shown], indent=2))
+        else:
+            for diff in shown:
+                self._write_diff(diff)
+            self.stderr.write(
+                f"{len(changed)} of {len(diffs)} style(s) would change: "
+                f"+{sum(d.additions for d in diffs)} -{sum(d.deletions for d in diffs)}"
+            )
+
+        if changed:
+            raise SystemExit(1)
+
+    def _write_diff(self, diff: StyleDiff) -> None:
+        """Print one style's diff, colored when writing to a terminal."""
+        heading = f"{diff.name}: {diff.status}"
+        if diff.old_format and diff.old_format != diff.new_format:
+            heading += f" ({diff.old_format} -> {diff.new_format})"
+        self.stdout.write(self.style.MIGRATE_HEADING(heading))
+        for line in diff.lines:
+            if line.startswith(("---", "+++")):
+                self.stdout.write(self.style.HTTP_INFO(line))
+            elif line.startswith("@@"):
+                self.stdout.write(self.style.MIGRATE_LABEL(line))
+            elif line.startswith("+"):
+                self.stdout.write(self.style.SUCCESS(line))
+            elif line.startswith("-"):
+                self.stdout.write(self.style.ERROR(line))
+            else:
+                self.stdout.write(line)
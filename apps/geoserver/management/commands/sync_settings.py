@@ -0,0 +1,88 @@
+"""Copy contact details and service metadata from one connection to others.
+
+Usage:
+    cloudbench sync_settings "Production GeoServer" conn_staging conn_dev --dry-run
+    cloudbench sync_settings conn_main --all --section contact --section wms
+    cloudbench sync_settings conn_main --all --output json
+
+Prints one row per field that differs from the source's. Exits with
+status 1 when a dry run finds differences, or when a section couldn't
+be read or written.
+"""
+
+from django.core.management.base import CommandError
+
+from apps.core.config import config_manager
+from apps.core.output import OutputCommand
+from apps.geoserver.settings_sync import SECTIONS, apply_settings_sync, plan_settings_sync
+
+from .style_diff import find_connection
+
+COLUMNS = ["connection", "section", "key", "current", "value"]
+
+
+class Command(OutputCommand):
+    """Apply one server's contact details and service metadata to others."""
+
+    quiet_key = "connection"
+    help = (
+        "Copy the contact details and the OGC services' titles, abstracts and keywords "
+        "of a connection to others, listing the differences first"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument("source", help="Connection ID or name whose settings are copied")
+        parser.add_argument("targets", nargs="*", help="Connection IDs or names to copy them to")
+        parser.add_argument(
+            "--all", action="store_true", help="Copy them to every other connection"
+        )
+        parser.add_argument(
+            "--section",
+            action="append",
+            choices=SECTIONS,
+            help="Only sync this section; repeat for several (default: all)",
+        )
+        parser.add_argument(
+            "--dry-run", action="store_true", help="Only list the fields that would change"
+        )
+        self.add_output_arguments(parser)
+
+    def handle(self, *args, **options):
+        """List the differences and write them unless it's a dry run."""
+        source = find_connection(options["source"])
+        if options["all"]:
+            targets = [c.id for c in config_manager.list_connections() if c.id != source.id]
+        else:
+            targets = [find_connection(ref).id for ref in options["targets"]]
+        if not targets:
+            raise CommandError("Give the connections to sync to, or --all")
+
+        try:
+            plan = plan_settings_sync(source.id, targets, options["section"])
+        except ValueError as e:
+            raise CommandError(str(e)) from e
+        for section, error in plan.source_errors.items():
+            self.stderr.write(self.style.WARNING(f"{source.name}: {section} not read: {error}"))
+
+        self.write_records(
+            [
+                {"connection": server.name, **change.to_dict()}
+                for server in plan.servers
+                for change in server.changes
+            ],
+            options,
+            COLUMNS,
+        )
+        if not options["dry_run"]:
+            apply_settings_sync(plan)
+
+        failed = False
+        for server in plan.servers:
+            for section, error in server.errors.items():
+                self.stderr.write(self.style.ERROR(f"{server.name}: {section}: {error}"))
+                failed = True
+        verb = "would change" if options["dry_run"] else "changed"
+        self.stderr.write(f"{plan.change_count} field(s) {verb} on {len(plan.servers)} server(s)")
+        if failed or (options["dry_run"] and plan.change_count):
+            raise SystemExit(1)
@@ -0,0 +1,106 @@
+"""Write a Terria init file cataloguing a connection's layers.
+
+Usage:
+    cloudbench terria_init "Production GeoServer"
+    cloudbench terria_init conn_123 topp tiger --dir ./terriamap/wwwroot/init
+    cloudbench terria_init conn_123 --s3 minio --bucket maps --prefix terria/init
+    cloudbench terria_init conn_123 --tilesets minio/lidar/city
+    cloudbench terria_init conn_123 --stdout | jq .homeCamera
+"""
+
+from pathlib import Path
+
+from botocore.exceptions import ClientError
+from django.core.management.base import BaseCommand, CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.s3.client import get_s3_client
+from apps.s3.three_d import tileset_items
+from apps.terria.init_file import generate_init, upload_init, write_init
+
+
+class Command(BaseCommand):
+    """Generate a Terria init file for hosting the 3D viewer anywhere."""
+
+    help = (
+        "Write a Terria init file with a catalog group per workspace, a WMS item per layer "
+        "with its legend and extent, and a camera on the layers, to a folder or a bucket"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument(
+            "workspaces", nargs="*", help="Workspaces to include; every workspace by default"
+        )
+        parser.add_argument(
+            "--name", help="Catalog name, also naming the file; the connection's name by default"
+        )
+        parser.add_argument(
+            "--dir", help="Folder to write to; the file browser's last folder by default"
+        )
+        parser.add_argument("--s3", help="S3 connection to upload to instead of a folder")
+        parser.add_argument("--bucket", help="Bucket to upload to, with --s3")
+        parser.add_argument("--prefix", default="", help="Key prefix to upload under, with --s3")
+        parser.add_argument(
+            "--tilesets",
+            metavar="S3_CONNECTION/BUCKET[/PREFIX]",
+            help="Add the 3D Tiles tilesets of a bucket, read from its public URLs",
+        )
+        parser.add_argument(
+            "--stdout", action="store_true", help="Print the init file instead of writing it"
+        )
+
+    def handle(self, *args, **options):
+        """Generate the init file and write, upload or print it."""
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+        if options["s3"] and not options["bucket"]:
+            raise CommandError("--s3 needs a --bucket")
+
+        try:
+            tilesets = None
+            if options["tilesets"]:
+                s3_conn, _, path = options["tilesets"].partition("/")
+                bucket, _, prefix = path.partition("/")
+                if not bucket:
+                    raise CommandError("--tilesets needs an S3 connection and a bucket")
+                s3 = get_s3_client(s3_conn)
+                tilesets = tileset_items(
+                    s3, bucket, prefix, lambda key: s3.object_url(bucket, key)
+                )
+            init = generate_init(
+                get_geoserver_client(conn.id),
+                conn.public_base_url,
+                options["name"] or conn.name,
+                options["workspaces"] or None,
+                tilesets,
+            )
+            if options["stdout"]:
+                self.stdout.write(init.content, ending="")
+                return
+            if options["s3"]:
+                location = upload_init(
+                    init, get_s3_client(options["s3"]), options["bucket"], options["prefix"]
+                )
+            else:
+                directory = Path(options["dir"] or config_manager.config.last_local_path)
+                location = str(write_init(init, directory.expanduser()))
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+        except (OSError, ValueError, ClientError) as e:
+            raise CommandError(str(e)) from e
+        self.stdout.write(f"Wrote {init.layers} layers to {location}")
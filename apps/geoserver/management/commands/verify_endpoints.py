@@ -0,0 +1,86 @@
+"""Check which of a connection's layers answer on their OGC endpoints.
+
+Usage:
+    cloudbench verify_endpoints "Production GeoServer"
+    cloudbench verify_endpoints conn_123 topp topp:roads --failed
+    cloudbench verify_endpoints conn_123 --output csv > endpoints.csv
+"""
+
+from django.core.management.base import CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.core.output import OutputCommand
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.endpoint_health import SERVICES, verify_endpoints
+from apps.gwc.client import get_gwc_client
+
+
+class Command(OutputCommand):
+    """Print a pass/fail matrix of layers by WMS, WFS, WMTS and legend endpoint."""
+
+    quiet_key = "layer"
+    help = (
+        "Send each layer's WMS, WFS, WMTS and legend endpoints a lightweight request "
+        "and print which pass, fail or are skipped"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument(
+            "layers",
+            nargs="*",
+            help="Layers as workspace:layer, or workspaces to check all layers of; all by default",
+        )
+        parser.add_argument(
+            "--failed", action="store_true", help="Print only layers with a failing endpoint"
+        )
+        self.add_output_arguments(parser)
+
+    def handle(self, *args, **options):
+        """Check the layers and print the matrix."""
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+
+        try:
+            client = get_geoserver_client(conn.id)
+            gwc_client = get_gwc_client(conn.id)
+            reports = [
+                verify_endpoints(client, workspace, layer, gwc_client)
+                for workspace, layer in self._layers(client, options["layers"])
+            ]
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+
+        failing = [report for report in reports if not report.ok]
+        shown = failing if options["failed"] else reports
+        self.write_records([report.record() for report in shown], options, ["layer", *SERVICES])
+        if failing:
+            raise SystemExit(1)
+
+    @staticmethod
+    def _layers(client, names: list[str]) -> list[tuple[str, str]]:
+        """Workspace and name of each layer to check."""
+        workspaces = [name for name in names if ":" not in name]
+        layers = [tuple(name.split(":", 1)) for name in names if ":" in name]
+        if not names:
+            workspaces = [ws["name"] for ws in client.list_workspaces() if ws.get("name")]
+        for workspace in workspaces:
+            for entry in client.list_layers(workspace):
+                # Workspace layer lists name layers without their workspace
+                layer = entry.get("name", "").rpartition(":")[2]
+                if layer:
+                    layers.append((workspace, layer))
+        return layers
@@ -0,0 +1,72 @@
+"""Report the storage each workspace of a connection uses.
+
+Usage:
+    cloudbench workspace_usage "Production GeoServer"
+    cloudbench workspace_usage conn_123 --sort tileCacheBytes
+    cloudbench workspace_usage conn_123 --output csv > usage.csv
+"""
+
+from django.core.management.base import CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.core.output import OutputCommand
+from apps.dashboard.workspace_usage import COLUMNS, connection_workspace_usage
+
+# Columns the report can be sorted by, largest first
+SORT_KEYS = ("totalBytes", "tileCacheBytes", "storeBytes", "workspace")
+
+
+class Command(OutputCommand):
+    """Add up each workspace's tile cache and store sizes."""
+
+    quiet_key = "workspace"
+    help = (
+        "Report each workspace's tile cache usage from the GWC disk quota and the size "
+        "of its stores' files in the data directory, for chargeback and capacity planning"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument(
+            "--sort",
+            choices=SORT_KEYS,
+            default="totalBytes",
+            help="Column to sort by; sizes largest first (default: %(default)s)",
+        )
+        self.add_output_arguments(parser)
+
+    def handle(self, *args, **options):
+        """Size the workspaces and print the report."""
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+
+        try:
+            report = connection_workspace_usage(conn.id)
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+
+        key = options["sort"]
+        records = sorted(
+            report.records(),
+            key=lambda r: r[key] if key == "workspace" else -r[key],
+        )
+        self.write_records(records, options, COLUMNS)
+        if report.tile_cache_error:
+            self.stderr.write(f"Tile caches not counted: {report.tile_cache_error}")
+        if report.store_error:
+            self.stderr.write(f"Stores not sized: {report.store_error}")
+        for error in report.errors:
+            self.stderr.write(f"Not sized: {error}")
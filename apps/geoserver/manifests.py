@@ -0,0 +1,122 @@
+"""Installed extensions and community modules.
+
+GeoServer lists the manifest of every jar it loaded at /rest/about/manifest.
+Matching those names against the known plugins tells which extensions
+and community modules a server has, so features that need one, like the
+Importer or CSS styles, can be hidden up front instead of failing when
+called. Core modules and third-party libraries are left out of the
+inventory.
+"""
+
+import re
+from dataclasses import dataclass
+from typing import Any
+
+KIND_EXTENSION = "extension"
+KIND_COMMUNITY = "community"
+
+# Jar name prefix -> (label, kind); longer prefixes are tried first
+KNOWN_MODULES: dict[str, tuple[str, str]] = {
+    "gs-importer": ("Importer", KIND_EXTENSION),
+    "gs-monitor": ("Monitoring", KIND_EXTENSION),
+    "gs-css": ("CSS Styling", KIND_EXTENSION),
+    "gs-mbstyle": ("MBStyle Styling", KIND_EXTENSION),
+    "gs-ysld": ("YSLD Styling", KIND_EXTENSION),
+    "gs-cog": ("COG (Cloud Optimized GeoTIFF)", KIND_EXTENSION),
+    "gs-wps": ("WPS", KIND_EXTENSION),
+    "gs-vectortiles": ("Vector Tiles", KIND_EXTENSION),
+    "gs-sldservice": ("SLD REST Service", KIND_EXTENSION),
+    "gs-control-flow": ("Control Flow", KIND_EXTENSION),
+    "gs-authkey": ("Authentication Key", KIND_EXTENSION),
+    "gs-printing": ("Printing", KIND_EXTENSION),
+    "gs-querylayer": ("Query Layer", KIND_EXTENSION),
+    "gs-feature-pregeneralized": ("Pregeneralized Features", KIND_EXTENSION),
+    "gs-imagemosaic-jdbc": ("ImageMosaic JDBC", KIND_EXTENSION),
+    "gs-netcdf": ("NetCDF", KIND_EXTENSION),
+    "gs-gdal": ("GDAL Coverage Formats", KIND_EXTENSION),
+    "gs-libjpeg-turbo": ("libjpeg-turbo", KIND_EXTENSION),
+    "gs-excel": ("Excel WFS Output", KIND_EXTENSION),
+    "gs-geopkg-output": ("GeoPackage Output", KIND_EXTENSION),
+    "gs-web-resource": ("Resource Browser", KIND_EXTENSION),
+    "gs-geofence": ("GeoFence", KIND_EXTENSION),
+    "gs-backup-restore": ("Backup and Restore", KIND_COMMUNITY),
+    "gs-ogcapi": ("OGC API", KIND_COMMUNITY),
+    "gs-gwc-s3": ("GWC S3 Blob Store", KIND_COMMUNITY),
+    "gs-jdbcconfig": ("JDBC Catalog", KIND_COMMUNITY),
+    "gs-jdbcstore": ("JDBC Resource Store", KIND_COMMUNITY),
+    "gs-stac-datastore": ("STAC Data Store", KIND_COMMUNITY),
+    "gs-smart-data-loader": ("Smart Data Loader", KIND_COMMUNITY),
+    "gs-features-templating": ("Features Templating", KIND_COMMUNITY),
+    "gs-datadir-catalog-loader": ("Parallel Catalog Loader", KIND_COMMUNITY),
+}
+
+# Features provided by a plugin: feature -> (jar name prefix, description)
+EXTENSION_FEATURES: dict[str, tuple[str, str]] = {
+    "importer": ("gs-importer", "Bulk imports through the Importer extension"),
+    "monitoring": ("gs-monitor", "Request monitoring through the Monitoring extension"),
+    "css_styles": ("gs-css", "Styles written in GeoServer CSS"),
+    "cog": ("gs-cog", "Cloud Optimized GeoTIFF stores read over HTTP or S3"),
+    "backup_restore": ("gs-backup-restore", "Server-side archives through Backup and Restore"),
+    "geofence": ("gs-geofence", "Data security rules through the GeoFence extension"),
+}
+
+_VERSION_SUFFIX_RE = re.compile(r"-\d[\w.\-]*$")
+
+
+@dataclass
+class InstalledModule:
+    """An extension or community module found on a server."""
+
+    name: str  # Jar name without its version, e.g. "gs-importer-core"
+    label: str
+    kind: str
+    version: str = ""
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {"name": self.name, "label": self.label, "kind": self.kind, "version": self.version}
+
+
+def module_name(jar: str) -> str:
+    """Strip the version from a jar name, e.g. "gs-css-2.24.1" -> "gs-css"."""
+    return _VERSION_SUFFIX_RE.sub("", jar)
+
+
+def _known(name: str) -> tuple[str, str] | None:
+    for prefix in sorted(KNOWN_MODULES, key=len, reverse=True):
+        if name == prefix or name.startswith(f"{prefix}-"):
+            return KNOWN_MODULES[prefix]
+    return None
+
+
+def installed_modules(manifests: list[dict[str, Any]]) -> list[InstalledModule]:
+    """Pick the extensions and community modules out of a server's jar manifests.
+
+    A plugin often ships several jars (gs-importer-core, gs-importer-rest,
+    ...); each is reported once under the plugin's label, sorted by kind
+    and label.
+    """
+    modules: dict[str, InstalledModule] = {}
+    for manifest in manifests:
+        name = module_name(manifest.get("@name", ""))
+        known = _known(name)
+        if not known:
+            continue
+        label, kind = known
+        if label not in modules:
+            modules[label] = InstalledModule(
+                name=name,
+                label=label,
+                kind=kind,
+                version=str(manifest.get("Project-Version") or manifest.get("Version") or ""),
+            )
+    return sorted(modules.values(), key=lambda m: (m.kind != KIND_EXTENSION, m.label.lower()))
+
+
+def extension_features(manifests: list[dict[str, Any]]) -> dict[str, bool]:
+    """Which plugin-provided features a server's jar manifests make available."""
+    names = [module_name(manifest.get("@name", "")) for manifest in manifests]
+    return {
+        feature: any(name == prefix or name.startswith(f"{prefix}-") for name in names)
+        for feature, (prefix, _) in EXTENSION_FEATURES.items()
+    }
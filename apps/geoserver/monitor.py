@@ -0,0 +1,102 @@
+"""Layer request figures from the Monitoring extension.
+
+The extension records every OWS request with the layers it touched. The
+REST API can't count requests per layer, so the recent requests are
+fetched newest first and counted here. The fetch stops at a sample size;
+a layer on a busy server then gets a lower bound, flagged as incomplete.
+"""
+
+from dataclasses import dataclass
+from datetime import datetime, timedelta, timezone
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Days of requests counted
+DEFAULT_DAYS = 30
+
+# Most requests fetched per count
+DEFAULT_SAMPLE = 5000
+
+
+@dataclass
+class LayerRequestStats:
+    """Recorded requests for one layer."""
+
+    layer: str
+    days: int
+    requests: int = 0
+    last_request: str | None = None  # Start time of the newest request, as reported
+    complete: bool = True  # False when the sample ran out before the period did
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "layer": self.layer,
+            "days": self.days,
+            "requests": self.requests,
+            "lastRequest": self.last_request,
+            "complete": self.complete,
+        }
+
+
+def _resources(request: dict[str, Any]) -> list[str]:
+    resources = request.get("resources") or []
+    if isinstance(resources, dict):
+        # XStream-style wrapping: {"string": [...]} or a single name
+        resources = resources.get("string", [])
+    if isinstance(resources, str):
+        resources = [resources]
+    return resources
+
+
+def count_layer_requests(
+    requests: list[dict[str, Any]],
+    workspace: str,
+    layer: str,
+    days: int = DEFAULT_DAYS,
+    sample: int = DEFAULT_SAMPLE,
+) -> LayerRequestStats:
+    """Count the requests that touched a layer.
+
+    Args:
+        requests: Monitored requests, newest first
+        workspace: Layer workspace
+        layer: Layer name; virtual service requests may name it unqualified
+        days: Length of the period the requests cover
+        sample: Sample size the requests were fetched with
+
+    Returns:
+        The request figures of the layer
+    """
+    names = {f"{workspace}:{layer}", layer}
+    stats = LayerRequestStats(layer=f"{workspace}:{layer}", days=days)
+    for request in requests:
+        if names.intersection(_resources(request)):
+            stats.requests += 1
+            if stats.last_request is None:
+                stats.last_request = str(request.get("startTime") or "") or None
+    stats.complete = len(requests) < sample
+    return stats
+
+
+def layer_request_stats(
+    client: "GeoServerClient",
+    workspace: str,
+    layer: str,
+    days: int = DEFAULT_DAYS,
+    sample: int = DEFAULT_SAMPLE,
+) -> LayerRequestStats:
+    """Fetch and count the recent requests that touched a layer.
+
+    Raises:
+        GeoServerError: If the Monitoring extension isn't installed
+    """
+    if not client.capabilities.supports("monitoring"):
+        raise GeoServerError("Request figures need the Monitoring extension", status_code=501)
+    since = datetime.now(timezone.utc) - timedelta(days=days)
+    requests = client.list_monitor_requests(since, sample)
+    return count_layer_requests(requests, workspace, layer, days, sample)
@@ -0,0 +1,117 @@
+"""Naming conventions for catalog resources.
+
+Teams often agree on how workspaces, stores, layers and styles are named:
+a project code prefix, lowercase only, no spaces. The conventions in the
+config file's "naming_conventions" make those agreements checkable; every
+create and rename in the web UI, the TUI and the command line checks the
+new name against the conventions for its resource type, plus those for
+"*", before anything is sent to GeoServer.
+"""
+
+import re
+
+from apps.core.config import NamingConvention, config_manager
+from apps.core.exceptions import NamingError
+
+# Label of each resource type conventions can apply to
+RESOURCE_TYPES = {
+    "workspace": "workspace",
+    "datastore": "data store",
+    "coveragestore": "coverage store",
+    "layer": "layer",
+    "style": "style",
+    "layergroup": "layer group",
+}
+
+# Resource type of conventions applying to every resource
+ANY_TYPE = "*"
+
+# Placeholder for the resource's workspace in a required prefix
+WORKSPACE_PLACEHOLDER = "{workspace}"
+
+
+def conventions_for(
+    resource_type: str, conventions: list[NamingConvention] | None = None
+) -> list[NamingConvention]:
+    """Get the conventions names of a resource type must follow.
+
+    Args:
+        resource_type: Key of RESOURCE_TYPES
+        conventions: Conventions to pick from; the configured ones when None
+    """
+    if conventions is None:
+        conventions = config_manager.config.naming_conventions
+    return [c for c in conventions if c.resource_type in (resource_type, ANY_TYPE)]
+
+
+def check_name(
+    resource_type: str,
+    name: str,
+    workspace: str | None = None,
+    conventions: list[NamingConvention] | None = None,
+) -> list[str]:
+    """List how a name breaks the naming conventions.
+
+    Args:
+        resource_type: Key of RESOURCE_TYPES
+        name: Name to check
+        workspace: Workspace of the resource, for prefixes using "{workspace}"
+        conventions: Conventions to check; the configured ones when None
+
+    Returns:
+        One message per broken rule, empty when the name is fine
+    """
+    problems: list[str] = []
+    for convention in conventions_for(resource_type, conventions):
+        prefix = convention.prefix
+        if WORKSPACE_PLACEHOLDER in prefix:
+            # A workspace's own name, or one checked without its workspace
+            prefix = prefix.replace(WORKSPACE_PLACEHOLDER, workspace) if workspace else ""
+        if prefix and not name.startswith(prefix):
+            problems.append(f"must start with '{prefix}'")
+
+        forbidden = [c for c in dict.fromkeys(name) if c in convention.forbidden_characters]
+        if forbidden:
+            problems.append(f"must not contain {', '.join(repr(c) for c in forbidden)}")
+
+        if convention.pattern:
+            try:
+                matches = re.fullmatch(convention.pattern, name) is not None
+            except re.error as e:
+                problems.append(f"naming pattern {convention.pattern!r} is invalid: {e}")
+                continue
+            if not matches:
+                problems.append(convention.description or f"must match {convention.pattern}")
+    return list(dict.fromkeys(problems))
+
+
+def validate_name(resource_type: str, name: str, workspace: str | None = None) -> None:
+    """Check a new name against the configured naming conventions.
+
+    Args:
+        resource_type: Key of RESOURCE_TYPES
+        name: Name of the resource being created or renamed
+        workspace: Workspace of the resource, for prefixes using "{workspace}"
+
+    Raises:
+        NamingError: If the name breaks a convention
+    """
+    problems = check_name(resource_type, name, workspace)
+    if problems:
+        raise NamingError(RESOURCE_TYPES.get(resource_type, resource_type), name, problems)
+
+
+def conventions_to_dict(conventions: list[NamingConvention] | None = None) -> list[dict]:
+    """Serialize the configured conventions for API responses."""
+    if conventions is None:
+        conventions = config_manager.config.naming_conventions
+    return [
+        {
+            "resourceType": c.resource_type,
+            "pattern": c.pattern,
+            "forbiddenCharacters": c.forbidden_characters,
+            "prefix": c.prefix,
+            "description": c.description,
+        }
+        for c in conventions
+    ]
@@ -0,0 +1,227 @@
+"""OGC service helpers: URL building and response parsing.
+
+GeoServer exposes each OWS service globally (/wms) and as virtual
+services scoped to a workspace (/{workspace}/wms) or a single layer
+(/{workspace}/{layer}/wms). Virtual services only see the workspace's own
+layers, so isolated workspaces and identically named layers in different
+workspaces resolve unambiguously.
+
+Responses are parsed namespace-agnostically, so WFS 1.x/2.0, WMS 1.1/1.3
+and OWS 1.x documents are all understood.
+"""
+
+from dataclasses import dataclass, field
+from xml.etree import ElementTree as ET
+
+OWS_SERVICES = ("wms", "wfs", "wcs", "wmts", "ows")
+
+
+def service_path(
+    service: str,
+    workspace: str | None = None,
+    layer: str | None = None,
+) -> str:
+    """Build the path of a (virtual) OWS service endpoint.
+
+    Args:
+        service: Service name (wms, wfs, wcs, wmts or ows)
+        workspace: Optional workspace for a workspace virtual service
+        layer: Optional layer for a layer virtual service (needs workspace)
+
+    Returns:
+        Path such as "/wms", "/topp/wfs" or "/topp/states/wms"
+
+    Raises:
+        ValueError: If the service is unknown or a layer is given without a workspace
+    """
+    service = service.lower()
+    if service not in OWS_SERVICES:
+        raise ValueError(f"Unknown OWS service: {service}")
+    if layer and not workspace:
+        raise ValueError("A layer virtual service needs a workspace")
+
+    parts = [p for p in (workspace, layer, service) if p]
+    return "/" + "/".join(parts)
+
+
+def service_url(
+    base_url: str,
+    service: str,
+    workspace: str | None = None,
+    layer: str | None = None,
+) -> str:
+    """Build the full URL of a (virtual) OWS service endpoint.
+
+    Args:
+        base_url: GeoServer base URL, e.g. http://localhost:8080/geoserver
+        service: Service name (wms, wfs, wcs, wmts or ows)
+        workspace: Optional workspace for a workspace virtual service
+        layer: Optional layer for a layer virtual service
+
+    Returns:
+        Absolute service URL
+    """
+    return base_url.rstrip("/") + service_path(service, workspace, layer)
+
+
+@dataclass
+class OWSException:
+    """A single exception from an OWS exception report."""
+
+    code: str = ""
+    locator: str = ""
+    text: str = ""
+
+
+@dataclass
+class ExceptionReport:
+    """An OWS ExceptionReport or WMS ServiceExceptionReport."""
+
+    exceptions: list[OWSException] = field(default_factory=list)
+
+    @property
+    def message(self) -> str:
+        """All exception texts joined into one message."""
+        texts = [e.text or e.code for e in self.exceptions if e.text or e.code]
+        return "; ".join(texts) or "Unknown OWS exception"
+
+
+@dataclass
+class Capabilities:
+    """The parts of a GetCapabilities document the client uses."""
+
+    service: str
+    version: str
+    title: str = ""
+    layers: list[str] = field(default_factory=list)
+
+
+def _local(tag: str) -> str:
+    """Strip the namespace from an element tag."""
+    return tag.rsplit("}", 1)[-1]
+
+
+def _parse(text: str | bytes) -> ET.Element | None:
+    try:
+        return ET.fromstring(text)
+    except ET.ParseError:
+        return None
+
+
+def _child_text(element: ET.Element, name: str) -> str:
+    # {*} matches the tag in any namespace, or none
+    child = element.find(f"{{*}}{name}")
+    return (child.text or "").strip() if child is not None else ""
+
+
+def parse_exception_report(text: str | bytes) -> ExceptionReport | None:
+    """Parse an OWS exception report.
+
+    Args:
+        text: Response body
+
+    Returns:
+        The report, or None if the body isn't an exception report
+    """
+    root = _parse(text)
+    if root is None or _local(root.tag) not in ("ExceptionReport", "ServiceExceptionReport"):
+        return None
+
+    report = ExceptionReport()
+    for element in root.iter():
+        name = _local(element.tag)
+        if name == "Exception":
+            message = " ".join(
+                t.strip()
+                for child in element
+                if _local(child.tag) == "ExceptionText"
+                for t in child.itertext()
+                if t.strip()
+            )
+        elif name == "ServiceException":
+            message = "".join(element.itertext()).strip()
+        else:
+            continue
+        report.exceptions.append(
+            OWSException(
+                code=element.get("exceptionCode") or element.get("code") or "",
+                locator=element.get("locator") or "",
+                text=message,
+            )
+        )
+    return report
+
+
+def parse_hits(text: str | bytes) -> int | None:
+    """Read the match count from a WFS resultType=hits feature collection.
+
+    Args:
+        text: Response body
+
+    Returns:
+        The count, or None if the body has no numeric count
+    """
+    root = _parse(text)
+    if root is None or _local(root.tag) != "FeatureCollection":
+        return None
+
+    # WFS 2.0 uses numberMatched, WFS 1.1 numberOfFeatures
+    for attr in ("numberMatched", "numberOfFeatures"):
+        value = root.get(attr)
+        if value and value.isdigit():
+            return int(value)
+    return None
+
+
+def parse_capabilities(text: str | bytes) -> Capabilities | None:
+    """Parse the service identification and layer names of a capabilities document.
+
+    Args:
+        text: WMS, WFS or WCS GetCapabilities response body
+
+    Returns:
+        The capabilities summary, or None if the body isn't a capabilities document
+    """
+    root = _parse(text)
+    if root is None:
+        return None
+
+    root_name = _local(root.tag)
+    services = {
+        "WMS_Capabilities": "WMS",
+        "WMT_MS_Capabilities": "WMS",
+        "WFS_Capabilities": "WFS",
+        "Capabilities": "WCS",
+    }
+    if root_name not in services:
+        return None
+
+    capabilities = Capabilities(service=services[root_name], version=root.get("version", ""))
+
+    for container in ("Service", "ServiceIdentification"):
+        element = root.find(f"{{*}}{container}")
+        if element is not None:
+            capabilities.title = _child_text(element, "Title")
+            break
+
+    if capabilities.service == "WMS":
+        # Only named layers can be requested; group containers may be unnamed
+        for layer in root.iter():
+            if _local(layer.tag) == "Layer":
+                name = _child_text(layer, "Name")
+                if name:
+                    capabilities.layers.append(name)
+    elif capabilities.service == "WFS":
+        for feature_type in root.iter():
+            if _local(feature_type.tag) == "FeatureType":
+                name = _child_text(feature_type, "Name")
+                if name:
+                    capabilities.layers.append(name)
+    else:
+        for summary in root.iter():
+            if _local(summary.tag) == "CoverageSummary":
+                name = _child_text(summary, "CoverageId") or _child_text(summary, "Identifier")
+                if name:
+                    capabilities.layers.append(name)
+
+    return capabilities
@@ -0,0 +1,100 @@
+"""Forwarding OGC requests from the browser to GeoServer.
+
+Map previews and the embedded viewer would otherwise fetch GetMap,
+GetFeature and GetTile straight from GeoServer, which then has to be
+reachable from the browser and, for secured layers, be sent the
+connection's credentials. Proxied through the backend instead, requests
+go to the connection's own URL with its credentials added server-side,
+and responses are streamed back as they arrive.
+
+Only the read requests listed below, to OWS services and GeoWebCache's
+tile services, are forwarded: the REST API, a WFS transaction or a WPS
+process can't be reached through the proxy with the connection's
+credentials.
+"""
+
+import re
+
+from .ows import OWS_SERVICES
+
+# Tile services of the embedded GeoWebCache
+GWC_SERVICES = ("gwc/service/wms", "gwc/service/wmts")
+
+# OWS paths: a service, optionally of a workspace or a layer virtual service
+OWS_PATH_RE = re.compile(r"^(?:[\w.-]+/){0,2}(?:" + "|".join(OWS_SERVICES) + r")$")
+
+# Requests forwarded, per service; anything else, such as a WFS transaction, is refused
+ALLOWED_REQUESTS = {
+    "wms": frozenset({"getcapabilities", "getmap", "getfeatureinfo", "getlegendgraphic"}),
+    "wfs": frozenset({"getcapabilities", "describefeaturetype", "getfeature"}),
+    "wcs": frozenset({"getcapabilities", "describecoverage", "getcoverage"}),
+    "wmts": frozenset({"getcapabilities", "gettile"}),
+}
+
+# Browser headers passed on, so GeoServer and GeoWebCache can answer 304 Not Modified
+FORWARDED_REQUEST_HEADERS = ("If-None-Match", "If-Modified-Since", "Accept-Language")
+
+# GeoServer headers passed back; lengths and encodings change as the body is decoded
+FORWARDED_RESPONSE_HEADERS = (
+    "Content-Type",
+    "Content-Disposition",
+    "Cache-Control",
+    "Expires",
+    "Last-Modified",
+    "ETag",
+    "geowebcache-cache-result",
+)
+
+
+def proxy_path(target: str) -> str:
+    """The GeoServer path a proxied request goes to.
+
+    Args:
+        target: Path after the proxy's URL, e.g. "", "topp/wms" or "gwc/service/wmts"
+
+    Returns:
+        Path such as "/ows", "/topp/wms" or "/gwc/service/wmts"
+
+    Raises:
+        ValueError: If the path isn't an OWS or tile service
+    """
+    target = target.strip("/") or "ows"
+    segments = target.split("/")
+    if any(segment in ("", ".", "..") for segment in segments) or segments[0] == "rest":
+        raise ValueError(f"Not an OWS service: {target}")
+    if target.lower() in GWC_SERVICES or OWS_PATH_RE.match(target.lower()):
+        return f"/{target}"
+    raise ValueError(f"Not an OWS service: {target}")
+
+
+def check_request(path: str, params: dict[str, list[str]]) -> None:
+    """Refuse anything but the read requests in ALLOWED_REQUESTS.
+
+    Args:
+        path: GeoServer path as returned by proxy_path, whose last segment
+            names the service unless it's /ows
+        params: Query parameters, each with its values
+
+    Raises:
+        ValueError: If the service or request isn't one that is forwarded
+    """
+    lower = {key.lower(): values for key, values in params.items()}
+    services = {v.lower() for v in lower.get("service", [])}
+    path_service = path.rsplit("/", 1)[-1].lower()
+    if path_service != "ows":
+        services.add(path_service)
+    if len(services) != 1:
+        raise ValueError("Requests need one SERVICE to be proxied")
+    service = services.pop()
+    requests = lower.get("request", [])
+    if not requests:
+        raise ValueError("Requests need a REQUEST to be proxied")
+    for request in requests:
+        if request.lower() not in ALLOWED_REQUESTS.get(service, ()):
+            raise ValueError(f"{service.upper()} {request} requests can't be proxied")
+
+
+def forwarded_headers(headers: dict[str, str], names: tuple[str, ...]) -> dict[str, str]:
+    """The headers among the names given, matched without regard to case."""
+    lower = {key.lower(): value for key, value in headers.items()}
+    return {name: lower[name.lower()] for name in names if name.lower() in lower}
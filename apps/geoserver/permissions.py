@@ -0,0 +1,93 @@
+"""Effective permissions of a connection's credentials.
+
+GeoServer secures REST by URL and method, so an account may be allowed
+to browse the catalog but have every change refused. Rather than let
+someone fill in a dialog only to see a 403, the UIs probe what the
+credentials may do once per connection and grey out the actions that
+would fail.
+
+Admin-only security endpoints answer the question directly. For other
+accounts, a PUT to a workspace that doesn't exist shows whether writes
+pass the security filter: GeoServer checks the method before looking the
+workspace up, so a refusal comes back as 401 or 403 and an allowed write
+as 404, without anything being changed.
+"""
+
+import uuid
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Readable by administrators only
+ADMIN_PROBE_PATH = "/rest/security/acl/rest.json"
+
+# Statuses the security filter answers with when a request isn't allowed
+DENIED_STATUSES = frozenset({401, 403})
+
+
+@dataclass
+class ServerPermissions:
+    """What a connection's credentials may do on its server."""
+
+    can_read: bool
+    can_write: bool
+    is_admin: bool = False
+    reason: str | None = None
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "canRead": self.can_read,
+            "canWrite": self.can_write,
+            "isAdmin": self.is_admin,
+            "reason": self.reason,
+        }
+
+
+def probe_permissions(client: "GeoServerClient") -> ServerPermissions:
+    """Find out whether the client's credentials may change the server.
+
+    Args:
+        client: GeoServer client of the connection
+
+    Returns:
+        The permissions. If the server can't be reached, writes are
+        assumed allowed so an outage doesn't disable the UI.
+    """
+    connection = client.connection
+    if connection.read_only:
+        return ServerPermissions(
+            can_read=True,
+            can_write=False,
+            reason="Read-only: changes to this server are refused",
+        )
+
+    try:
+        if client._request("GET", ADMIN_PROBE_PATH).status_code == 200:
+            return ServerPermissions(can_read=True, can_write=True, is_admin=True)
+
+        read = client._request("GET", "/rest/workspaces.json")
+        can_read = read.status_code not in DENIED_STATUSES
+
+        # The workspace can't exist, so an allowed PUT changes nothing
+        probe = f"cloudbench-permission-probe-{uuid.uuid4().hex[:12]}"
+        write = client._request(
+            "PUT",
+            f"/rest/workspaces/{probe}.json",
+            json={"workspace": {"name": probe}},
+        )
+    except GeoServerError:
+        return ServerPermissions(can_read=True, can_write=True)
+
+    if write.status_code in DENIED_STATUSES:
+        user = connection.username or "anonymous"
+        return ServerPermissions(
+            can_read=can_read,
+            can_write=False,
+            reason=f"The server only lets {user} read (HTTP {write.status_code} on a test write)",
+        )
+    return ServerPermissions(can_read=can_read, can_write=True)
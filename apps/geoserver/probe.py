@@ -0,0 +1,304 @@
+"""Probing a connection's latency, render time and bandwidth.
+
+When a server feels slow, the cause is either the link to it or the
+server itself. The probe takes three measurements that separate the two:
+
+- REST round trips to /rest/about/version, which the server answers at
+  once, so their time is mostly the link's latency;
+- a small WMS GetMap, whose time beyond a round trip is rendering;
+- a WFS GetFeature of a sample of features, streamed, whose time to the
+  first byte is the server's query time and whose rate after that is the
+  link's throughput.
+
+The sample layer is the one given, or the first vector layer found.
+"""
+
+import statistics
+import time
+from collections.abc import Callable
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+from .ows import service_path
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# REST round trips timed; the median is reported
+REST_SAMPLES = 3
+
+# GetMap image width and height
+MAP_SIZE = 256
+
+# Features asked for by the sample GetFeature
+FEATURE_COUNT = 1000
+
+# Download stopped after this many bytes, which is plenty to measure throughput
+MAX_DOWNLOAD_BYTES = 20 * 1024 * 1024
+
+# Layers looked at for a vector one when no layer is given
+MAX_LAYERS_CHECKED = 10
+
+# Thresholds of the findings
+SLOW_LATENCY_MS = 300.0
+SLOW_SERVER_MS = 1000.0
+SLOW_THROUGHPUT = 1024 * 1024  # Bytes per second
+MIN_THROUGHPUT_BYTES = 256 * 1024  # Smaller downloads are over before the rate shows
+
+
+def format_rate(bytes_per_second: float) -> str:
+    """A transfer rate such as 3.2 MB/s."""
+    for unit in ("B", "KB", "MB"):
+        if bytes_per_second < 1024:
+            return f"{bytes_per_second:.1f} {unit}/s"
+        bytes_per_second /= 1024
+    return f"{bytes_per_second:.1f} GB/s"
+
+
+@dataclass
+class ProbeStep:
+    """One timed request of a probe."""
+
+    name: str  # rest, getmap or getfeature
+    label: str
+    ms: float | None = None  # Whole request, the median for REST round trips
+    first_byte_ms: float | None = None
+    bytes: int = 0
+    detail: str = ""
+    error: str = ""
+
+    @property
+    def ok(self) -> bool:
+        return not self.error and self.ms is not None
+
+    @property
+    def throughput(self) -> float | None:
+        """Bytes per second after the first byte, for downloads big enough to tell."""
+        if self.first_byte_ms is None or self.ms is None or self.bytes < MIN_THROUGHPUT_BYTES:
+            return None
+        seconds = (self.ms - self.first_byte_ms) / 1000
+        return self.bytes / seconds if seconds > 0 else None
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "name": self.name,
+            "label": self.label,
+            "ms": round(self.ms, 1) if self.ms is not None else None,
+            "firstByteMs": (
+                round(self.first_byte_ms, 1) if self.first_byte_ms is not None else None
+            ),
+            "bytes": self.bytes,
+            "throughput": round(self.throughput) if self.throughput is not None else None,
+            "detail": self.detail,
+            "error": self.error,
+        }
+
+
+@dataclass
+class ProbeResult:
+    """The measurements of a probe and what they point at."""
+
+    connection: str
+    layer: str | None = None  # As workspace:layer
+    steps: list[ProbeStep] = field(default_factory=list)
+
+    def step(self, name: str) -> ProbeStep | None:
+        return next((s for s in self.steps if s.name == name and s.ok), None)
+
+    def findings(self) -> list[str]:
+        """Plain explanations of what slows the connection down, if anything does."""
+        rest, getmap, features = self.step("rest"), self.step("getmap"), self.step("getfeature")
+        round_trip = rest.ms if rest else 0.0
+        findings = []
+        if rest and rest.ms > SLOW_LATENCY_MS:
+            findings.append(
+                f"REST round trips take {rest.ms:.0f} ms although the server answers them "
+                "at once: the link is slow or far away"
+            )
+        if getmap and getmap.ms - round_trip > SLOW_SERVER_MS:
+            findings.append(
+                f"Rendering takes {getmap.ms - round_trip:.0f} ms beyond a round trip: "
+                "the server is busy or the layer is heavy to draw"
+            )
+        if features and features.first_byte_ms - round_trip > SLOW_SERVER_MS:
+            findings.append(
+                f"Features start arriving {features.first_byte_ms - round_trip:.0f} ms "
+                "after a round trip: the server or its data store is slow to query"
+            )
+        if features and features.throughput is not None and features.throughput < SLOW_THROUGHPUT:
+            findings.append(
+                f"Downloads run at {format_rate(features.throughput)}: "
+                "bandwidth limits large responses"
+            )
+        if not findings and all(step.ok for step in self.steps):
+            findings.append("The link and the server respond normally")
+        return findings
+
+    def records(self) -> list[dict[str, Any]]:
+        """One row per step, for tables."""
+        return [
+            {
+                "step": step.label,
+                "ms": round(step.ms, 1) if step.ms is not None else None,
+                "first_byte_ms": (
+                    round(step.first_byte_ms, 1) if step.first_byte_ms is not None else None
+                ),
+                "bytes": step.bytes,
+                "throughput": (
+                    format_rate(step.throughput) if step.throughput is not None else None
+                ),
+                "detail": step.error or step.detail,
+            }
+            for step in self.steps
+        ]
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "connection": self.connection,
+            "layer": self.layer,
+            "steps": [step.to_dict() for step in self.steps],
+            "findings": self.findings(),
+        }
+
+
+def is_vector_layer(client: "GeoServerClient", layer: str) -> bool:
+    """Whether a workspace:layer has features to download; False if unknown."""
+    workspace, _, name = layer.partition(":")
+    try:
+        return client.get_layer(workspace, name).get("type") == "VECTOR"
+    except GeoServerError:
+        return False
+
+
+def pick_layer(client: "GeoServerClient") -> tuple[str, bool] | None:
+    """The first vector layer among the first layers, else the first layer.
+
+    Returns:
+        (workspace:layer, whether it is a vector layer), or None without layers
+    """
+    names = [layer.get("name", "") for layer in client.list_layers()[:MAX_LAYERS_CHECKED]]
+    names = [name for name in names if ":" in name]
+    for name in names:
+        if is_vector_layer(client, name):
+            return name, True
+    return (names[0], False) if names else None
+
+
+def probe_rest(client: "GeoServerClient", clock: Callable[[], float]) -> ProbeStep:
+    """Time REST round trips to the version resource."""
+    step = ProbeStep("rest", "REST round trip", detail=f"median of {REST_SAMPLES}")
+    times = []
+    try:
+        for _ in range(REST_SAMPLES):
+            started = clock()
+            response = client._request("GET", "/rest/about/version.json")
+            times.append((clock() - started) * 1000)
+            if response.status_code >= 400:
+                raise GeoServerError(f"HTTP {response.status_code}", response.status_code)
+            step.bytes = len(response.content)
+    except GeoServerError as e:
+        step.error = e.message
+        return step
+    step.ms = statistics.median(times)
+    return step
+
+
+def probe_getmap(client: "GeoServerClient", layer: str, clock: Callable[[], float]) -> ProbeStep:
+    """Time a small GetMap of a layer's whole extent."""
+    step = ProbeStep("getmap", "GetMap render", detail=f"{layer}, {MAP_SIZE}x{MAP_SIZE}")
+    workspace, _, name = layer.partition(":")
+    try:
+        latlon = client.get_layer_bounds(workspace, name).get("latLonBoundingBox")
+        if not latlon:
+            raise GeoServerError(f"{layer} has no lat/lon bounds")
+        bbox = (latlon["minx"], latlon["miny"], latlon["maxx"], latlon["maxy"])
+        started = clock()
+        image = client.get_map(workspace, name, bbox, MAP_SIZE, MAP_SIZE, srs="EPSG:4326")
+        step.ms = (clock() - started) * 1000
+    except GeoServerError as e:
+        step.error = e.message
+        return step
+    step.bytes = len(image)
+    return step
+
+
+def probe_download(
+    client: "GeoServerClient", layer: str, clock: Callable[[], float]
+) -> ProbeStep:
+    """Time a GetFeature download, apart from the wait for its first byte."""
+    step = ProbeStep(
+        "getfeature", "WFS download", detail=f"{layer}, up to {FEATURE_COUNT} features"
+    )
+    workspace, _, name = layer.partition(":")
+    params = {
+        "service": "WFS",
+        "version": "2.0.0",
+        "request": "GetFeature",
+        "typeNames": layer,
+        "count": FEATURE_COUNT,
+        "outputFormat": "application/json",
+    }
+    try:
+        started = clock()
+        with client._open("GET", service_path("wfs", workspace), params=params) as response:
+            if response.status_code >= 400:
+                raise GeoServerError(f"HTTP {response.status_code}", response.status_code)
+            for chunk in response.iter_bytes():
+                if step.first_byte_ms is None:
+                    step.first_byte_ms = (clock() - started) * 1000
+                step.bytes += len(chunk)
+                if step.bytes >= MAX_DOWNLOAD_BYTES:
+                    break
+        step.ms = (clock() - started) * 1000
+    except GeoServerError as e:
+        step.error = e.message
+    return step
+
+
+def run_probe(
+    client: "GeoServerClient",
+    layer: str | None = None,
+    clock: Callable[[], float] = time.monotonic,
+) -> ProbeResult:
+    """Measure a connection's latency, render time and throughput.
+
+    Every measurement is made even when another fails; failures are
+    reported on their steps.
+
+    Args:
+        client: GeoServer client of the connection
+        layer: Sample layer as workspace:layer; the first vector layer by default
+        clock: Seconds, monotonic
+
+    Returns:
+        The timed steps
+    """
+    result = ProbeResult(client.connection.name)
+    result.steps.append(probe_rest(client, clock))
+
+    if layer is not None:
+        is_vector = is_vector_layer(client, layer)
+    else:
+        try:
+            picked = pick_layer(client)
+        except GeoServerError as e:
+            result.steps.append(ProbeStep("getmap", "GetMap render", error=e.message))
+            return result
+        if picked is None:
+            result.steps.append(ProbeStep("getmap", "GetMap render", error="No layers to probe"))
+            return result
+        layer, is_vector = picked
+    result.layer = layer
+
+    result.steps.append(probe_getmap(client, layer, clock))
+    if is_vector:
+        result.steps.append(probe_download(client, layer, clock))
+    else:
+        result.steps.append(
+            ProbeStep("getfeature", "WFS download", error=f"{layer} is not a vector layer")
+        )
+    return result
@@ -0,0 +1,144 @@
+"""Publishing the feature types of a data store.
+
+A GeoPackage (or any multi-table store) is uploaded as one data store
+whose tables are only published as layers when asked. This lists the
+unpublished tables and publishes the chosen ones, or all of them, naming
+each layer by a set of rules: an optional prefix and suffix around the
+table name, cleaned into a valid layer name, and numbered when the name
+is already taken in the workspace. The table name stays the layer title.
+"""
+
+import logging
+import re
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+from .naming import validate_name
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+logger = logging.getLogger(__name__)
+
+
+def sanitize_name(text: str) -> str:
+    """Turn a file, folder or table name into a valid GeoServer name.
+
+    Args:
+        text: Name to clean, e.g. "Roads 2024 (final)"
+
+    Returns:
+        Lowercase name of letters, digits and underscores, starting with a
+        letter, e.g. "roads_2024_final"
+    """
+    name = re.sub(r"[^a-z0-9_]+", "_", text.lower()).strip("_")
+    if not name or not name[0].isalpha():
+        name = f"layer_{name}".rstrip("_")
+    return name
+
+
+@dataclass
+class NamingRules:
+    """How layer names are made from table names."""
+
+    prefix: str = ""
+    suffix: str = ""
+    sanitize: bool = True  # Lowercase letters, digits and underscores only
+
+    @classmethod
+    def from_dict(cls, data: dict[str, Any] | None) -> "NamingRules":
+        """Read naming rules from an API request."""
+        data = data or {}
+        return cls(
+            prefix=str(data.get("prefix") or ""),
+            suffix=str(data.get("suffix") or ""),
+            sanitize=bool(data.get("sanitize", True)),
+        )
+
+    def apply(self, table: str) -> str:
+        """Name the layer of a table, e.g. "Roads 2024" -> "osm_roads_2024"."""
+        name = f"{self.prefix}{table}{self.suffix}"
+        return sanitize_name(name) if self.sanitize else name
+
+
+def plan_layer_names(
+    tables: list[str],
+    rules: NamingRules,
+    taken: set[str],
+) -> dict[str, str]:
+    """Name the layers of tables, avoiding names already in the workspace.
+
+    Args:
+        tables: Tables to publish
+        rules: Naming rules
+        taken: Layer names already used in the workspace
+
+    Returns:
+        Layer name by table, in table order
+    """
+    used = set(taken)
+    names = {}
+    for table in tables:
+        base = rules.apply(table)
+        name = base
+        counter = 2
+        while name in used:
+            name = f"{base}_{counter}"
+            counter += 1
+        used.add(name)
+        names[table] = name
+    return names
+
+
+def publish_feature_types(
+    client: "GeoServerClient",
+    workspace: str,
+    store: str,
+    tables: list[str] | None = None,
+    rules: NamingRules | None = None,
+) -> list[dict[str, Any]]:
+    """Publish unpublished tables of a data store as layers.
+
+    A failing table is recorded and the rest are still published.
+
+    Args:
+        client: GeoServer client
+        workspace: Workspace of the store
+        store: Data store name
+        tables: Tables to publish; all unpublished tables when None
+        rules: Naming rules, the defaults when None
+
+    Returns:
+        One result per table with table, layer, status ("published" or
+        "error") and error
+
+    Raises:
+        ValueError: If a table isn't an unpublished table of the store
+        GeoServerError: If the store's tables can't be listed
+    """
+    available = client.list_available_featuretypes(workspace, store)
+    if tables is None:
+        tables = available
+    unknown = [t for t in tables if t not in available]
+    if unknown:
+        raise ValueError(f"Not unpublished tables of {store}: {', '.join(unknown)}")
+
+    taken = {layer.get("name", "") for layer in client.list_layers(workspace)}
+    names = plan_layer_names(tables, rules or NamingRules(), taken)
+
+    results = []
+    for table, name in names.items():
+        result: dict[str, Any] = {"table": table, "layer": name, "status": "published"}
+        try:
+            validate_name("layer", name, workspace)
+            # Without an SRS GeoServer keeps the table's own
+            client.create_featuretype(
+                workspace, store, name, native_name=table, title=table, srs=None
+            )
+        except GeoServerError as e:
+            logger.warning("Publishing %s from %s failed: %s", table, store, e.message)
+            result.update(status="error", error=e.message)
+        results.append(result)
+    return results
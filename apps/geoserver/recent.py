@@ -0,0 +1,273 @@
+"""Recently added and modified layers and styles of a connection.
+
+GeoServer stamps layers and styles with dateCreated and dateModified
+since 2.15, but only those created or changed since, and older versions
+not at all. Changes made through this client are therefore also kept in
+a local activity log, so the tree can list what you worked on last
+whichever server it talks to. Where both know an item, the later time
+wins.
+"""
+
+import json
+import logging
+import threading
+import time
+from concurrent.futures import ThreadPoolExecutor
+from dataclasses import dataclass
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import TYPE_CHECKING, Any
+
+from apps.core.config import get_cache_dir
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+logger = logging.getLogger(__name__)
+
+ACTIVITY_FILE = "recent.json"
+
+# Items listed in each view by default
+DEFAULT_LIMIT = 10
+
+# Entries kept per connection in the local log
+MAX_ENTRIES = 200
+
+# Layers and styles read for their timestamps; large catalogs rely on the local log
+MAX_SCANNED = 200
+MAX_WORKERS = 8
+
+
+def parse_timestamp(value: Any) -> datetime | None:
+    """Read a GeoServer timestamp such as "2024-05-23 09:39:21.478 UTC".
+
+    Returns:
+        The time in UTC, or None if the value isn't a timestamp
+    """
+    if not isinstance(value, str) or not value.strip():
+        return None
+    text = value.strip()
+    parts = text.split(" ")
+    if len(parts) == 3 and parts[2].isalpha():
+        # GeoServer appends the zone name, which is UTC in REST responses
+        text = " ".join(parts[:2])
+    for pattern in ("%Y-%m-%d %H:%M:%S.%f", "%Y-%m-%d %H:%M:%S"):
+        try:
+            return datetime.strptime(text, pattern).replace(tzinfo=timezone.utc)
+        except ValueError:
+            continue
+    try:
+        parsed = datetime.fromisoformat(text.replace("Z", "+00:00"))
+    except ValueError:
+        return None
+    return parsed if parsed.tzinfo else parsed.replace(tzinfo=timezone.utc)
+
+
+@dataclass
+class RecentItem:
+    """A layer or style and when it was last added or changed."""
+
+    type: str  # "layer" or "style"
+    name: str
+    workspace: str | None
+    time: datetime
+    source: str  # "server" (GeoServer's timestamps) or "local" (the activity log)
+
+    @property
+    def key(self) -> tuple[str, str | None, str]:
+        return (self.type, self.workspace, self.name)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "type": self.type,
+            "name": self.name,
+            "workspace": self.workspace,
+            "time": self.time.isoformat(),
+            "source": self.source,
+        }
+
+
+class ActivityLog:
+    """Local record of layers and styles added or changed through the client."""
+
+    _lock = threading.Lock()
+
+    def __init__(self, path: Path | None = None):
+        """Initialize the log.
+
+        Args:
+            path: JSON file to keep the log in; the cache directory by default
+        """
+        self._path = path
+
+    @property
+    def path(self) -> Path:
+        return self._path or get_cache_dir() / ACTIVITY_FILE
+
+    def _load(self) -> dict[str, dict[str, dict[str, Any]]]:
+        try:
+            data = json.loads(self.path.read_text())
+        except (OSError, ValueError):
+            return {}
+        return data if isinstance(data, dict) else {}
+
+    def _save(self, data: dict[str, dict[str, dict[str, Any]]]) -> None:
+        try:
+            self.path.write_text(json.dumps(data))
+        except (OSError, TypeError) as e:
+            # The log is a convenience; never fail the change that was made
+            logger.debug("Could not write the activity log: %s", e)
+
+    @staticmethod
+    def _key(kind: str, workspace: str | None, name: str) -> str:
+        return f"{kind}:{workspace or ''}:{name}"
+
+    def record(
+        self, conn_id: str, kind: str, workspace: str | None, name: str, added: bool = False
+    ) -> None:
+        """Note that a layer or style was added or changed now.
+
+        Args:
+            conn_id: Connection ID
+            kind: "layer" or "style"
+            workspace: Workspace name; None for global styles
+            name: Layer or style name
+            added: Whether it was created rather than changed
+        """
+        now = time.time()
+        with self._lock:
+            data = self._load()
+            entries = data.setdefault(conn_id, {})
+            key = self._key(kind, workspace, name)
+            entry = entries.get(key) or {"type": kind, "workspace": workspace, "name": name}
+            entry["modified"] = now
+            if added:
+                entry["added"] = now
+            entries[key] = entry
+            if len(entries) > MAX_ENTRIES:
+                newest = sorted(entries.items(), key=lambda e: e[1]["modified"], reverse=True)
+                data[conn_id] = dict(newest[:MAX_ENTRIES])
+            self._save(data)
+
+    def forget(self, conn_id: str, kind: str, workspace: str | None, name: str) -> None:
+        """Drop a deleted layer or style from the log."""
+        with self._lock:
+            data = self._load()
+            if data.get(conn_id, {}).pop(self._key(kind, workspace, name), None) is not None:
+                self._save(data)
+
+    def items(self, conn_id: str, action: str) -> list[RecentItem]:
+        """List the logged items of a connection.
+
+        Args:
+            conn_id: Connection ID
+            action: "added" or "modified"
+
+        Returns:
+            Items with a time for the action, in no particular order
+        """
+        with self._lock:
+            entries = self._load().get(conn_id, {})
+        return [
+            RecentItem(
+                type=entry["type"],
+                name=entry["name"],
+                workspace=entry.get("workspace"),
+                time=datetime.fromtimestamp(entry[action], timezone.utc),
+                source="local",
+            )
+            for entry in entries.values()
+            if isinstance(entry.get(action), (int, float))
+        ]
+
+
+activity_log = ActivityLog()
+
+
+def _catalog(client: "GeoServerClient") -> list[tuple[str, str | None, str]]:
+    """List the (kind, workspace, name) of every layer and style, up to MAX_SCANNED."""
+    found: list[tuple[str, str | None, str]] = []
+    workspaces = [ws["name"] for ws in client.list_workspaces() if ws.get("name")]
+    for ws in workspaces:
+        found.extend(("layer", ws, item["name"]) for item in client.list_layers(ws))
+        found.extend(("style", ws, item["name"]) for item in client.list_styles(ws))
+        if len(found) >= MAX_SCANNED:
+            return found[:MAX_SCANNED]
+    found.extend(("style", None, item["name"]) for item in client.list_styles())
+    return found[:MAX_SCANNED]
+
+
+def _timestamps(
+    client: "GeoServerClient", item: tuple[str, str | None, str]
+) -> tuple[datetime | None, datetime | None]:
+    """Read the (created, modified) times GeoServer keeps for a layer or style."""
+    kind, workspace, name = item
+    try:
+        if kind == "layer":
+            details = client.get_layer(workspace or "", name)
+        else:
+            details = client.get_style(name, workspace)
+    except GeoServerError:
+        return None, None
+    return parse_timestamp(details.get("dateCreated")), parse_timestamp(
+        details.get("dateModified")
+    )
+
+
+def server_items(client: "GeoServerClient") -> dict[str, list[RecentItem]]:
+    """List the layers and styles GeoServer has timestamps for.
+
+    Returns:
+        Items by action ("added", "modified"); empty when timestamps are off
+    """
+    catalog = _catalog(client)
+    with ThreadPoolExecutor(max_workers=MAX_WORKERS) as executor:
+        stamps = list(executor.map(lambda item: _timestamps(client, item), catalog))
+
+    result: dict[str, list[RecentItem]] = {"added": [], "modified": []}
+    for (kind, workspace, name), (created, modified) in zip(catalog, stamps):
+        if created:
+            result["added"].append(RecentItem(kind, name, workspace, created, "server"))
+        # Never-modified items only have a creation time
+        if modified or created:
+            changed = modified or created
+            result["modified"].append(RecentItem(kind, name, workspace, changed, "server"))
+    return result
+
+
+def _latest(items: list[RecentItem], limit: int) -> list[RecentItem]:
+    """Keep the latest time of each item and the newest items."""
+    latest: dict[tuple[str, str | None, str], RecentItem] = {}
+    for item in items:
+        if item.key not in latest or item.time > latest[item.key].time:
+            latest[item.key] = item
+    return sorted(latest.values(), key=lambda i: i.time, reverse=True)[:limit]
+
+
+def recent_items(
+    client: "GeoServerClient", limit: int = DEFAULT_LIMIT, log: ActivityLog | None = None
+) -> dict[str, list[RecentItem]]:
+    """List a connection's most recently added and modified layers and styles.
+
+    Args:
+        client: GeoServer client
+        limit: Items per list
+        log: Activity log; the shared one by default
+
+    Returns:
+        {"added": [...], "modified": [...]}, newest first
+    """
+    log = log or activity_log
+    conn_id = client.connection.id
+    try:
+        server = server_items(client)
+    except GeoServerError as e:
+        # Still list what was done through the client
+        logger.debug("Could not read timestamps from GeoServer: %s", e)
+        server = {"added": [], "modified": []}
+    return {
+        action: _latest(server[action] + log.items(conn_id, action), limit)
+        for action in ("added", "modified")
+    }
@@ -0,0 +1,274 @@
+"""Render time benchmark for layers and styles.
+
+A style with many rules, heavy labelling or unfiltered detail at small
+scales can take seconds per map, which caching only hides for the tiles
+it has seeded. This times a series of WMS GetMap requests in Web
+Mercator at a few zoom levels, each at some places sampled inside the
+layer's bounds, and reports the median (p50) and 95th percentile (p95)
+render times and the image sizes, so slow scales show up before a layer
+is cached or published.
+"""
+
+import math
+import random
+import time
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any, Callable
+
+from apps.core.exceptions import GeoServerError
+from apps.gwc.estimate import lonlat_to_web_mercator
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Half the circumference of the Web Mercator world, in meters
+ORIGIN_SHIFT = math.pi * 6378137.0
+MAX_ZOOM = 22
+
+# Defaults: zoom levels below the one fitting the layer, places per zoom, image size
+DEFAULT_ZOOM_STEPS = (0, 2, 4)
+DEFAULT_SAMPLES = 3
+DEFAULT_SIZE = 512
+IMAGE_SIZES = (256, 512, 1024)
+
+# Upper bound on GetMap requests per run, to keep benchmarks from loading the server
+MAX_REQUESTS = 100
+
+# Maps slower than this at p95 are worth caching or simplifying
+SLOW_SECONDS = 1.0
+
+# OGC standardized rendering pixel size, for scale denominators
+PIXEL_SIZE = 0.00028
+
+Bounds = tuple[float, float, float, float]
+
+
+def zoom_resolution(zoom: int) -> float:
+    """Meters per pixel of a Web Mercator zoom level with 256 pixel tiles."""
+    return 2 * ORIGIN_SHIFT / 256 / 2**zoom
+
+
+def fitting_zoom(bounds: Bounds, size: int) -> int:
+    """The deepest zoom level at which the bounds fit in a square image.
+
+    Args:
+        bounds: Bounds in EPSG:3857
+        size: Image width and height in pixels
+    """
+    extent = max(bounds[2] - bounds[0], bounds[3] - bounds[1], 1.0)
+    zoom = math.floor(math.log2(2 * ORIGIN_SHIFT * size / 256 / extent))
+    return max(0, min(MAX_ZOOM, zoom))
+
+
+def percentile(values: list[float], fraction: float) -> float | None:
+    """Nearest-rank percentile, or None without values."""
+    if not values:
+        return None
+    ordered = sorted(values)
+    rank = max(1, math.ceil(fraction * len(ordered)))
+    return ordered[rank - 1]
+
+
+def sample_bboxes(
+    bounds: Bounds, zoom: int, size: int, count: int, rng: random.Random
+) -> list[Bounds]:
+    """Pick map extents at a zoom level centered at places inside the bounds.
+
+    A zoom level showing all of the bounds gets one extent centered on them,
+    since every sample would draw the same features.
+
+    Args:
+        bounds: Layer bounds in EPSG:3857
+        zoom: Web Mercator zoom level
+        size: Image width and height in pixels
+        count: Number of extents
+        rng: Random source, seeded for repeatable runs
+    """
+    half = zoom_resolution(zoom) * size / 2
+    minx, miny, maxx, maxy = bounds
+    if 2 * half >= max(maxx - minx, maxy - miny):
+        centers = [((minx + maxx) / 2, (miny + maxy) / 2)]
+    else:
+        centers = [(rng.uniform(minx, maxx), rng.uniform(miny, maxy)) for _ in range(count)]
+    return [(x - half, y - half, x + half, y + half) for x, y in centers]
+
+
+@dataclass
+class RenderSample:
+    """One timed GetMap request."""
+
+    zoom: int
+    bbox: Bounds
+    seconds: float
+    bytes: int = 0
+    error: str | None = None
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "zoom": self.zoom,
+            "bbox": list(self.bbox),
+            "seconds": round(self.seconds, 4),
+            "bytes": self.bytes,
+            "error": self.error,
+        }
+
+
+@dataclass
+class ZoomStats:
+    """Render times and image sizes at one zoom level."""
+
+    zoom: int
+    scale: float  # Scale denominator
+    requests: int
+    errors: int
+    p50: float | None
+    p95: float | None
+    mean_bytes: int
+
+    @property
+    def slow(self) -> bool:
+        """Whether maps at this zoom take longer than SLOW_SECONDS at p95."""
+        return self.p95 is not None and self.p95 > SLOW_SECONDS
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "zoom": self.zoom,
+            "scale": round(self.scale),
+            "requests": self.requests,
+            "errors": self.errors,
+            "p50": self.p50,
+            "p95": self.p95,
+            "meanBytes": self.mean_bytes,
+            "slow": self.slow,
+        }
+
+
+@dataclass
+class BenchmarkReport:
+    """Render times of a layer with a style."""
+
+    workspace: str
+    layer: str
+    style: str  # Empty for the layer's default style
+    size: int
+    samples: list[RenderSample] = field(default_factory=list)
+
+    def zoom_stats(self) -> list[ZoomStats]:
+        """Statistics per zoom level, shallowest first."""
+        stats = []
+        for zoom in sorted({s.zoom for s in self.samples}):
+            samples = [s for s in self.samples if s.zoom == zoom]
+            rendered = [s for s in samples if s.error is None]
+            times = [s.seconds for s in rendered]
+            stats.append(
+                ZoomStats(
+                    zoom=zoom,
+                    scale=zoom_resolution(zoom) / PIXEL_SIZE,
+                    requests=len(samples),
+                    errors=len(samples) - len(rendered),
+                    p50=percentile(times, 0.5),
+                    p95=percentile(times, 0.95),
+                    mean_bytes=sum(s.bytes for s in rendered) // len(rendered) if rendered else 0,
+                )
+            )
+        return stats
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        times = [s.seconds for s in self.samples if s.error is None]
+        return {
+            "workspace": self.workspace,
+            "layer": self.layer,
+            "style": self.style,
+            "size": self.size,
+            "p50": percentile(times, 0.5),
+            "p95": percentile(times, 0.95),
+            "slowSeconds": SLOW_SECONDS,
+            "zooms": [stats.to_dict() for stats in self.zoom_stats()],
+            "samples": [sample.to_dict() for sample in self.samples],
+        }
+
+
+def plan_zooms(bounds: Bounds, size: int, zooms: list[int] | None) -> list[int]:
+    """Zoom levels to render: those given, or a few from the one fitting the layer.
+
+    Raises:
+        ValueError: If a zoom level is out of range
+    """
+    if zooms:
+        for zoom in zooms:
+            if not 0 <= zoom <= MAX_ZOOM:
+                raise ValueError(f"Zoom levels must be between 0 and {MAX_ZOOM}")
+        return sorted(set(zooms))
+    start = fitting_zoom(bounds, size)
+    return sorted({min(MAX_ZOOM, start + step) for step in DEFAULT_ZOOM_STEPS})
+
+
+def run_benchmark(
+    client: "GeoServerClient",
+    workspace: str,
+    layer: str,
+    style: str = "",
+    zooms: list[int] | None = None,
+    samples: int = DEFAULT_SAMPLES,
+    size: int = DEFAULT_SIZE,
+    seed: int = 0,
+    clock: Callable[[], float] = time.perf_counter,
+    on_sample: Callable[[RenderSample], None] | None = None,
+) -> BenchmarkReport:
+    """Time GetMap requests of a layer across zoom levels and places.
+
+    One untimed request first warms up the layer's store connection and
+    style, so the first sample isn't slowed by them.
+
+    Args:
+        client: GeoServer client
+        workspace: Workspace name
+        layer: Layer name
+        style: Style to render with; the layer's default when empty
+        zooms: Web Mercator zoom levels; a few from the one fitting the layer when None
+        samples: Places sampled per zoom level
+        size: Image width and height in pixels
+        seed: Seed of the sampled places, the same for runs to compare
+        clock: Time source, in seconds
+        on_sample: Called after each request, e.g. to show progress
+
+    Raises:
+        ValueError: If the settings are invalid or ask for too many requests
+        GeoServerError: If the layer's bounds can't be read or the warm-up fails
+    """
+    if size not in IMAGE_SIZES:
+        raise ValueError(f"The image size must be one of {', '.join(map(str, IMAGE_SIZES))}")
+    if samples < 1:
+        raise ValueError("At least one sample per zoom level is needed")
+
+    latlon = client.get_layer_bounds(workspace, layer).get("latLonBoundingBox")
+    if not latlon:
+        raise GeoServerError(f"{workspace}:{layer} has no lat/lon bounds", status_code=400)
+    bounds = lonlat_to_web_mercator(
+        (float(latlon["minx"]), float(latlon["miny"]), float(latlon["maxx"]), float(latlon["maxy"]))
+    )
+    levels = plan_zooms(bounds, size, zooms)
+    if len(levels) * samples > MAX_REQUESTS:
+        raise ValueError(f"A benchmark can make at most {MAX_REQUESTS} requests")
+
+    rng = random.Random(seed)
+    plan = [
+        (zoom, bbox) for zoom in levels for bbox in sample_bboxes(bounds, zoom, size, samples, rng)
+    ]
+    client.get_map(workspace, layer, plan[0][1], size, size, style=style)
+
+    report = BenchmarkReport(workspace, layer, style, size)
+    for zoom, bbox in plan:
+        started = clock()
+        try:
+            image = client.get_map(workspace, layer, bbox, size, size, style=style)
+            sample = RenderSample(zoom, bbox, clock() - started, len(image))
+        except GeoServerError as e:
+            sample = RenderSample(zoom, bbox, clock() - started, error=str(e))
+        report.samples.append(sample)
+        if on_sample:
+            on_sample(sample)
+    return report
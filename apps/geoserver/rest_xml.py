@@ -0,0 +1,160 @@
+"""XML for the GeoServer REST API, in the shape of its JSON.
+
+Some proxies and GeoServer versions mangle JSON on certain endpoints: a
+proxy that rewrites bodies may cut it off, and old releases emit
+invalid JSON for some resources. Their XML is reliable everywhere, so a
+connection can talk XML instead; see Connection.rest_format.
+
+GeoServer builds its JSON from the same model as its XML, which makes
+the two convertible: an element becomes a key, repeated elements a
+list, attributes "@" keys and the text beside them "$". The XML doesn't
+say which elements are collections, so those of the core resources are
+listed here to come out as lists even when they hold one item, as they
+do in JSON.
+"""
+
+import re
+from typing import Any
+from xml.etree import ElementTree as ET
+
+# Values of Connection.rest_format
+REST_FORMATS = ("auto", "json", "xml")
+
+# REST paths of the core catalog resources, which can be read and written as XML
+CORE_PATH = re.compile(
+    r"^/rest/(workspaces|namespaces|layers|layergroups|styles|settings|services|about)\b"
+)
+
+# Collection element -> the element repeated in it, always read as a list
+LIST_ELEMENTS = {
+    "workspaces": "workspace",
+    "namespaces": "namespace",
+    "dataStores": "dataStore",
+    "coverageStores": "coverageStore",
+    "featureTypes": "featureType",
+    "coverages": "coverage",
+    "layers": "layer",
+    "layerGroups": "layerGroup",
+    "styles": "style",
+    "publishables": "published",
+    "keywords": "string",
+    "attributes": "attribute",
+    "connectionParameters": "entry",
+    "metadata": "entry",
+    "resources": "resource",
+    "list": "string",
+}
+
+# Elements holding numbers, which JSON has as numbers rather than text
+NUMBER_ELEMENTS = {"minx", "miny", "maxx", "maxy"}
+
+
+def is_core_path(path: str) -> bool:
+    """Whether a REST path is a core resource that has an XML form."""
+    return bool(CORE_PATH.match(path))
+
+
+def xml_path(path: str) -> str:
+    """The XML form of a REST path, e.g. /rest/layers.json -> /rest/layers.xml."""
+    return f"{path[: -len('.json')]}.xml" if path.endswith(".json") else path
+
+
+def _value(element: ET.Element) -> Any:
+    """Convert an element's content the way GeoServer's JSON has it."""
+    children = list(element)
+    attributes = {f"@{key}": value for key, value in element.attrib.items()}
+    if not children:
+        text = element.text or ""
+        if element.tag in NUMBER_ELEMENTS:
+            try:
+                text = float(text) if any(c in text for c in ".eE") else int(text)
+            except ValueError:
+                pass
+        elif text in ("true", "false"):
+            text = text == "true"
+        if not attributes:
+            return text
+        return {**attributes, "$": text} if text != "" else attributes
+
+    value: dict[str, Any] = dict(attributes)
+    listed = LIST_ELEMENTS.get(element.tag)
+    for child in children:
+        item = _value(child)
+        if child.tag not in value:
+            value[child.tag] = [item] if child.tag == listed else item
+        elif isinstance(value[child.tag], list):
+            value[child.tag].append(item)
+        else:
+            value[child.tag] = [value[child.tag], item]
+    return value
+
+
+def parse_rest_xml(content: bytes | str) -> dict[str, Any]:
+    """Read a REST response in XML as GeoServer would have sent it in JSON.
+
+    Args:
+        content: XML document
+
+    Returns:
+        The document as a dict with its root element as the only key
+
+    Raises:
+        ValueError: If the document isn't XML
+    """
+    try:
+        root = ET.fromstring(content)
+    except ET.ParseError as e:
+        raise ValueError(f"Invalid XML: {e}") from e
+    return {root.tag: _value(root)}
+
+
+def _build(parent: ET.Element, key: str, value: Any) -> None:
+    """Add the element or elements for a key to parent."""
+    if isinstance(value, list):
+        for item in value:
+            _build(parent, key, item)
+        return
+    element = ET.SubElement(parent, key)
+    _fill(element, value)
+
+
+def _fill(element: ET.Element, value: Any) -> None:
+    """Set an element's attributes, text and children from a JSON value."""
+    if isinstance(value, dict):
+        for key, item in value.items():
+            if item is None:
+                continue
+            if key.startswith("@"):
+                element.set(key[1:], _text(item))
+            elif key == "$":
+                element.text = _text(item)
+            else:
+                _build(element, key, item)
+    elif value is not None:
+        element.text = _text(value)
+
+
+def _text(value: Any) -> str:
+    if isinstance(value, bool):
+        return "true" if value else "false"
+    return str(value)
+
+
+def encode_rest_xml(payload: dict[str, Any]) -> bytes:
+    """Write a REST request body given in the JSON shape as XML.
+
+    Args:
+        payload: Body with one root key, e.g. {"workspace": {"name": "topp"}}
+
+    Returns:
+        Encoded XML document
+
+    Raises:
+        ValueError: If the payload doesn't have exactly one root key
+    """
+    if len(payload) != 1:
+        raise ValueError("An XML request body needs exactly one root element")
+    tag, value = next(iter(payload.items()))
+    root = ET.Element(tag)
+    _fill(root, value)
+    return ET.tostring(root, encoding="utf-8")
@@ -0,0 +1,240 @@
+"""Feature type schemas from WFS DescribeFeatureType.
+
+DescribeFeatureType lists each attribute of a published vector layer with
+its XML Schema type, whether it may be null and how often it occurs. The
+geometry attribute's GML type gives the geometry type; the SRS isn't part
+of the response and is read from the REST catalog when it's wanted. The
+same schema drives the attribute table, the CQL builder and the choice of
+attributes a layer publishes.
+"""
+
+import re
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Java bindings GeoServer expects for attribute types in featureType payloads
+BINDINGS = {
+    "string": "java.lang.String",
+    "boolean": "java.lang.Boolean",
+    "byte": "java.lang.Byte",
+    "short": "java.lang.Short",
+    "int": "java.lang.Integer",
+    "integer": "java.math.BigInteger",
+    "long": "java.lang.Long",
+    "float": "java.lang.Float",
+    "double": "java.lang.Double",
+    "decimal": "java.math.BigDecimal",
+    "number": "java.lang.Double",
+    "date": "java.sql.Date",
+    "datetime": "java.sql.Timestamp",
+    "time": "java.sql.Time",
+}
+
+# Package of the JTS geometry classes in GeoServer 2.14 and later
+GEOMETRY_PACKAGE = "org.locationtech.jts.geom"
+
+_SRID_RE = re.compile(r"(?:EPSG|CRS):*(\d+)$", re.IGNORECASE)
+
+
+def parse_srid(srs: str | None) -> int | None:
+    """Read the numeric code of an SRS such as EPSG:4326 or urn:ogc:def:crs:EPSG::4326."""
+    match = _SRID_RE.search((srs or "").strip())
+    return int(match.group(1)) if match else None
+
+
+def _occurs(value: Any, default: int) -> int:
+    # maxOccurs may be "unbounded"
+    try:
+        return int(value)
+    except (TypeError, ValueError):
+        return default
+
+
+@dataclass
+class SchemaAttribute:
+    """One attribute of a feature type."""
+
+    name: str
+    type: str  # Qualified type, e.g. xsd:string or gml:MultiPolygon
+    local_type: str  # Unqualified type, e.g. string or MultiPolygon
+    nillable: bool = True
+    min_occurs: int = 0
+    max_occurs: int = 1
+
+    @property
+    def geometry(self) -> bool:
+        return self.type.startswith("gml:")
+
+    @property
+    def binding(self) -> str:
+        """Java class GeoServer binds the attribute to."""
+        if self.geometry:
+            return f"{GEOMETRY_PACKAGE}.{self.local_type.replace('PropertyType', '')}"
+        return BINDINGS.get(self.local_type.lower(), "java.lang.String")
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "name": self.name,
+            "type": self.type,
+            "localType": self.local_type,
+            "nillable": self.nillable,
+            "minOccurs": self.min_occurs,
+            "maxOccurs": self.max_occurs,
+            "geometry": self.geometry,
+        }
+
+
+@dataclass
+class FeatureTypeSchema:
+    """Attributes of a published vector layer."""
+
+    type_name: str
+    namespace: str | None = None
+    attributes: list[SchemaAttribute] = field(default_factory=list)
+    srs: str | None = None
+
+    @property
+    def geometry_attribute(self) -> SchemaAttribute | None:
+        """The default geometry, taken as the first geometry attribute."""
+        return next((a for a in self.attributes if a.geometry), None)
+
+    @property
+    def geometry_type(self) -> str | None:
+        geometry = self.geometry_attribute
+        return geometry.local_type if geometry else None
+
+    @property
+    def srid(self) -> int | None:
+        return parse_srid(self.srs)
+
+    @property
+    def fields(self) -> list[SchemaAttribute]:
+        """The non-geometry attributes."""
+        return [a for a in self.attributes if not a.geometry]
+
+    def attribute(self, name: str) -> SchemaAttribute | None:
+        """Look up an attribute by name."""
+        return next((a for a in self.attributes if a.name == name), None)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        geometry = self.geometry_attribute
+        return {
+            "typeName": self.type_name,
+            "namespace": self.namespace,
+            "attributes": [a.to_dict() for a in self.attributes],
+            "geometryAttribute": geometry.name if geometry else None,
+            "geometryType": self.geometry_type,
+            "srs": self.srs,
+            "srid": self.srid,
+        }
+
+
+def parse_feature_type(data: dict[str, Any]) -> FeatureTypeSchema:
+    """Parse a JSON DescribeFeatureType response.
+
+    Args:
+        data: Response with a featureTypes list, as GeoServer returns for
+            outputFormat=application/json
+
+    Returns:
+        Schema of the first feature type, empty if the response has none
+    """
+    feature_types = data.get("featureTypes") or []
+    if not feature_types:
+        return FeatureTypeSchema(type_name="")
+    feature_type = feature_types[0]
+    attributes = []
+    for prop in feature_type.get("properties") or []:
+        name = prop.get("name")
+        if not name:
+            continue
+        qualified = str(prop.get("type") or "")
+        local_type = str(prop.get("localType") or qualified.split(":")[-1])
+        attributes.append(
+            SchemaAttribute(
+                name=name,
+                type=qualified or local_type,
+                local_type=local_type,
+                nillable=bool(prop.get("nillable", True)),
+                min_occurs=_occurs(prop.get("minOccurs"), 0),
+                max_occurs=_occurs(prop.get("maxOccurs"), 1),
+            )
+        )
+    return FeatureTypeSchema(
+        type_name=str(feature_type.get("typeName") or ""),
+        namespace=data.get("targetNamespace"),
+        attributes=attributes,
+    )
+
+
+def published_attributes(schema: FeatureTypeSchema, names: list[str]) -> dict[str, Any]:
+    """Build the featureType attributes that publish only some attributes.
+
+    Args:
+        schema: Schema of the layer
+        names: Attributes to keep, in the order to publish them
+
+    Returns:
+        The "attributes" member of a featureType payload
+
+    Raises:
+        ValueError: If a name isn't an attribute of the layer
+    """
+    kept = []
+    for name in names:
+        attribute = schema.attribute(name)
+        if attribute is None:
+            raise ValueError(f"Unknown attribute: {name}")
+        kept.append(
+            {
+                "name": attribute.name,
+                "minOccurs": attribute.min_occurs,
+                "maxOccurs": attribute.max_occurs,
+                "nillable": attribute.nillable,
+                "binding": attribute.binding,
+            }
+        )
+    return {"attribute": kept}
+
+
+def publish_attributes(
+    client: "GeoServerClient",
+    workspace: str,
+    layer: str,
+    names: list[str],
+) -> FeatureTypeSchema:
+    """Limit the attributes a published vector layer exposes.
+
+    Attributes left out disappear from WFS, feature info and the attribute
+    table. They can only be chosen from the layer's current schema, so
+    bringing one back means republishing the layer.
+
+    Args:
+        client: GeoServer client
+        workspace: Layer workspace
+        layer: Layer name
+        names: Attributes to keep, in order
+
+    Returns:
+        The schema after the change
+
+    Raises:
+        ValueError: If a name is unknown, the geometry is left out or the
+            layer isn't published from a data store
+        GeoServerError: If a request fails
+    """
+    schema = client.describe_feature_type(workspace, layer)
+    geometry = schema.geometry_attribute
+    if geometry and geometry.name not in names:
+        raise ValueError(f"The geometry attribute {geometry.name} must be kept")
+    attributes = published_attributes(schema, names)
+    store = client.get_layer_metadata(workspace, layer).get("store")
+    if not store or store["type"] != "datastores":
+        raise ValueError(f"{workspace}:{layer} is not published from a data store")
+    client.update_featuretype(store["workspace"], store["name"], layer, {"attributes": attributes})
+    return client.describe_feature_type(workspace, layer, include_srs=True)
@@ -0,0 +1,223 @@
+"""Syncing contact details and service metadata between GeoServers.
+
+An organization running several GeoServers usually wants each of them
+to present the same contact details, service titles and abstracts in its
+capabilities documents. A settings sync reads those from a source
+connection and writes them to the targets, leaving the rest of each
+server's settings alone; the server comparison covers those.
+
+Only fields the source sets are copied: a field empty on the source is
+left as it is on the targets. A plan lists, for each target, the fields
+that would change and their current values, so the differences can be
+previewed before anything is written.
+"""
+
+import json
+from concurrent.futures import ThreadPoolExecutor
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+from .client import get_geoserver_client
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Synced sections: the server's contact information and each OGC service
+SECTIONS = ("contact", "wms", "wfs", "wcs", "wmts")
+
+# Fields of the contact information that are synced
+CONTACT_FIELDS = (
+    "contactPerson",
+    "contactPosition",
+    "contactOrganization",
+    "contactEmail",
+    "contactVoice",
+    "contactFacsimile",
+    "onlineResource",
+    "addressType",
+    "address",
+    "addressCity",
+    "addressState",
+    "addressPostalCode",
+    "addressCountry",
+)
+
+# Descriptive fields of each service's settings that are synced
+SERVICE_FIELDS = (
+    "title",
+    "abstrct",
+    "keywords",
+    "maintainer",
+    "onlineResource",
+    "fees",
+    "accessConstraints",
+)
+
+# Servers read at the same time
+MAX_WORKERS = 4
+
+
+def _text(value: Any) -> str:
+    """Render a field for the preview, e.g. keywords as "a, b"."""
+    if isinstance(value, dict):
+        return ", ".join(_text(v) for v in value.values())
+    if isinstance(value, list):
+        return ", ".join(_text(v) for v in value)
+    return "" if value is None else str(value)
+
+
+def _same(a: Any, b: Any) -> bool:
+    return json.dumps(a, sort_keys=True) == json.dumps(b, sort_keys=True)
+
+
+def read_section(client: "GeoServerClient", section: str) -> dict[str, Any]:
+    """Read the synced fields of one section that a server sets.
+
+    Args:
+        client: Server to read
+        section: One of SECTIONS
+    """
+    if section == "contact":
+        data, fields = client.get_contact(), CONTACT_FIELDS
+    else:
+        data, fields = client.get_service_settings(section), SERVICE_FIELDS
+    return {key: data[key] for key in fields if _text(data.get(key)).strip()}
+
+
+@dataclass
+class SettingChange:
+    """One field a sync would change on a server."""
+
+    section: str
+    key: str
+    current: Any  # None when the server doesn't set it
+    value: Any
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "section": self.section,
+            "key": self.key,
+            "current": _text(self.current),
+            "value": _text(self.value),
+        }
+
+
+@dataclass
+class ServerSettingsDiff:
+    """What a settings sync would change on one server."""
+
+    connection_id: str
+    name: str
+    changes: list[SettingChange] = field(default_factory=list)
+    errors: dict[str, str] = field(default_factory=dict)  # Section -> why it failed
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "connectionId": self.connection_id,
+            "name": self.name,
+            "changes": [c.to_dict() for c in self.changes],
+            "errors": self.errors,
+        }
+
+
+@dataclass
+class SettingsSyncPlan:
+    """The source's settings and what copying them changes on each target."""
+
+    source_id: str
+    sections: list[str]
+    source: dict[str, dict[str, Any]] = field(default_factory=dict)
+    source_errors: dict[str, str] = field(default_factory=dict)  # Sections that couldn't be read
+    servers: list[ServerSettingsDiff] = field(default_factory=list)
+
+    @property
+    def change_count(self) -> int:
+        return sum(len(s.changes) for s in self.servers)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "sourceId": self.source_id,
+            "sections": self.sections,
+            "sourceErrors": self.source_errors,
+            "servers": [s.to_dict() for s in self.servers],
+            "changeCount": self.change_count,
+        }
+
+
+def diff_server(
+    source: dict[str, dict[str, Any]], client: "GeoServerClient"
+) -> ServerSettingsDiff:
+    """Compare a server's settings with the source's.
+
+    A section that can't be read, like the settings of a service the
+    server doesn't run, is recorded as an error and the rest compared.
+    """
+    diff = ServerSettingsDiff(client.connection.id, client.connection.name)
+    for section, wanted in source.items():
+        try:
+            current = read_section(client, section)
+        except GeoServerError as e:
+            diff.errors[section] = e.message
+            continue
+        for key, value in wanted.items():
+            if not _same(current.get(key), value):
+                diff.changes.append(SettingChange(section, key, current.get(key), value))
+    return diff
+
+
+def plan_settings_sync(
+    source_id: str, target_ids: list[str], sections: list[str] | None = None
+) -> SettingsSyncPlan:
+    """Work out what copying a connection's settings would change on others.
+
+    Args:
+        source_id: Connection whose settings are copied
+        target_ids: Connections they're copied to
+        sections: Sections to sync; all of SECTIONS when None
+
+    Raises:
+        ValueError: If a connection doesn't exist or a section is unknown
+    """
+    sections = list(sections or SECTIONS)
+    unknown = [s for s in sections if s not in SECTIONS]
+    if unknown:
+        raise ValueError(f"Unknown section(s): {', '.join(unknown)}")
+    plan = SettingsSyncPlan(source_id, sections)
+
+    source = get_geoserver_client(source_id)
+    for section in sections:
+        try:
+            plan.source[section] = read_section(source, section)
+        except GeoServerError as e:
+            plan.source_errors[section] = e.message
+
+    clients = [get_geoserver_client(t) for t in target_ids if t != source_id]
+    with ThreadPoolExecutor(max_workers=MAX_WORKERS) as executor:
+        plan.servers = list(executor.map(lambda c: diff_server(plan.source, c), clients))
+    return plan
+
+
+def apply_settings_sync(plan: SettingsSyncPlan) -> None:
+    """Write the changes of a plan, one request per section and server.
+
+    Sections that fail are added to their server's errors; the other
+    sections and servers are still written.
+    """
+    for server in plan.servers:
+        client = get_geoserver_client(server.connection_id)
+        by_section: dict[str, dict[str, Any]] = {}
+        for change in server.changes:
+            by_section.setdefault(change.section, {})[change.key] = change.value
+        for section, values in by_section.items():
+            try:
+                if section == "contact":
+                    client.update_contact(values)
+                else:
+                    client.update_service_settings(section, values)
+            except GeoServerError as e:
+                server.errors[section] = e.message
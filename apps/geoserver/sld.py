@@ -0,0 +1,177 @@
+"""SLD 1.0 and SLD 1.1 / Symbology Encoding versions of styles.
+
+GeoServer stores SLD 1.1 styles as they were uploaded, but hands out and
+takes in SLD 1.0 unless told otherwise: reading a 1.1 style through its
+.sld resource converts it down, and saving it back as 1.0 loses what only
+Symbology Encoding can express. The version is therefore read from the
+document itself, and 1.1 styles are read and written with the
+application/vnd.ogc.se+xml content type.
+
+Styles can also be converted between the two versions, which differ in
+where their elements live: 1.1 keeps the layer and style structure in the
+SLD namespace and moves symbolizers to the SE namespace, renames
+CssParameter to SvgParameter and wraps titles and abstracts in a
+Description.
+"""
+
+import xml.etree.ElementTree as ET
+
+SLD_10 = "1.0.0"
+SLD_11 = "1.1.0"
+SLD_VERSIONS = (SLD_10, SLD_11)
+
+# Content type GeoServer reads and writes each version as
+CONTENT_TYPES = {
+    SLD_10: "application/vnd.ogc.sld+xml",
+    SLD_11: "application/vnd.ogc.se+xml",
+}
+
+SLD_NS = "http://www.opengis.net/sld"
+SE_NS = "http://www.opengis.net/se"
+
+# Prefixes written out; the SLD namespace is the default one
+_PREFIXES = {
+    "": SLD_NS,
+    "se": SE_NS,
+    "ogc": "http://www.opengis.net/ogc",
+    "xlink": "http://www.w3.org/1999/xlink",
+    "xsi": "http://www.w3.org/2001/XMLSchema-instance",
+}
+_SCHEMA_LOCATION = "{http://www.w3.org/2001/XMLSchema-instance}schemaLocation"
+_SCHEMAS = {
+    SLD_10: f"{SLD_NS} http://schemas.opengis.net/sld/1.0.0/StyledLayerDescriptor.xsd",
+    SLD_11: f"{SLD_NS} http://schemas.opengis.net/sld/1.1.0/StyledLayerDescriptor.xsd",
+}
+
+# Elements SLD 1.1 keeps in the SLD namespace; everything else moves to SE
+SLD_ELEMENTS = {
+    "StyledLayerDescriptor",
+    "NamedLayer",
+    "UserLayer",
+    "NamedStyle",
+    "UserStyle",
+    "IsDefault",
+    "LayerFeatureConstraints",
+    "FeatureTypeConstraint",
+    "RemoteOWS",
+    "InlineFeature",
+    "UseSLDLibrary",
+}
+
+# Elements whose Title and Abstract SLD 1.1 wraps in a Description
+_DESCRIBED = {"StyledLayerDescriptor", "UserStyle", "Rule"}
+
+for _prefix, _uri in _PREFIXES.items():
+    ET.register_namespace(_prefix, _uri)
+
+
+def _local(tag: str) -> str:
+    return tag.rsplit("}", 1)[-1]
+
+
+def _parse(content: str) -> ET.Element:
+    """Parse a style, keeping its comments."""
+    parser = ET.XMLParser(target=ET.TreeBuilder(insert_comments=True))
+    return ET.fromstring(content.encode("utf-8"), parser=parser)
+
+
+def detect_sld_version(content: str) -> str:
+    """Get the SLD version of a style document.
+
+    The version attribute of StyledLayerDescriptor decides; without one a
+    document using the SE namespace is 1.1. Content that isn't XML is
+    taken as 1.0, GeoServer's default, and left for it to reject.
+    """
+    try:
+        root = _parse(content)
+    except ET.ParseError:
+        return SLD_10
+    version = root.get("version", "").strip()
+    if version in SLD_VERSIONS:
+        return version
+    if any(isinstance(el.tag, str) and el.tag.startswith(f"{{{SE_NS}}}") for el in root.iter()):
+        return SLD_11
+    return SLD_10
+
+
+def _to_11(element: ET.Element) -> None:
+    for child in list(element):
+        if isinstance(child.tag, str):
+            _to_11(child)
+    if not element.tag.startswith(f"{{{SLD_NS}}}"):
+        return
+    name = _local(element.tag)
+    if name == "CssParameter":
+        name = "SvgParameter"
+    element.tag = f"{{{SLD_NS if name in SLD_ELEMENTS else SE_NS}}}{name}"
+
+    if name in _DESCRIBED:
+        texts = [c for c in element if c.tag in (f"{{{SE_NS}}}Title", f"{{{SE_NS}}}Abstract")]
+        if texts:
+            # Indented one level deeper than the texts were
+            indent = texts[0].tail or ""
+            description = ET.Element(f"{{{SE_NS}}}Description")
+            description.text = indent + "  " if indent else None
+            description.tail = texts[-1].tail
+            element.insert(list(element).index(texts[0]), description)
+            for text in texts:
+                element.remove(text)
+                text.tail = description.text
+                description.append(text)
+            texts[-1].tail = indent
+
+
+def _to_10(element: ET.Element) -> None:
+    for description in [c for c in element if c.tag == f"{{{SE_NS}}}Description"]:
+        index = list(element).index(description)
+        texts = list(description)
+        element.remove(description)
+        for offset, text in enumerate(texts):
+            text.tail = description.tail
+            element.insert(index + offset, text)
+
+    for child in list(element):
+        if isinstance(child.tag, str):
+            _to_10(child)
+    if not element.tag.startswith(f"{{{SE_NS}}}"):
+        return
+    name = _local(element.tag)
+    if name == "SvgParameter":
+        name = "CssParameter"
+    element.tag = f"{{{SLD_NS}}}{name}"
+
+
+def convert_sld(content: str, version: str) -> str:
+    """Convert a style to another SLD version.
+
+    Args:
+        content: SLD document
+        version: SLD_10 or SLD_11
+
+    Returns:
+        The document in that version; unchanged when it already is
+
+    Raises:
+        ValueError: If the version is unknown or the content isn't a valid SLD
+    """
+    if version not in SLD_VERSIONS:
+        raise ValueError(f"Unknown SLD version: {version} (use {' or '.join(SLD_VERSIONS)})")
+    if detect_sld_version(content) == version:
+        return content
+    try:
+        root = _parse(content)
+    except ET.ParseError as e:
+        raise ValueError(f"Invalid XML: {e}") from e
+    if _local(root.tag) != "StyledLayerDescriptor":
+        raise ValueError(f"Root element is {root.tag}, expected StyledLayerDescriptor")
+
+    if version == SLD_11:
+        _to_11(root)
+    else:
+        _to_10(root)
+    root.set("version", version)
+    if root.get(_SCHEMA_LOCATION):
+        root.set(_SCHEMA_LOCATION, _SCHEMAS[version])
+
+    body = ET.tostring(root, encoding="unicode")
+    return f'<?xml version="1.0" encoding="UTF-8"?>\n{body}\n'
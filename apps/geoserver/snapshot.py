@@ -0,0 +1,514 @@
+"""Dated snapshots of catalog resources.
+
+A snapshot captures the configuration of a set of tree nodes, and
+optionally their data, into a folder named after the connection and the
+time it was taken. Inside, files mirror the REST API's hierarchy
+(workspaces/topp/datastores/roads/featuretypes/roads.json) and hold the
+JSON it returns, so a single file can be sent back to recreate the
+resource. A manifest.json lists what was asked for, the files captured
+for each resource with their SHA-256 checksums, and what failed.
+
+Each resource is captured independently; one that can't be read is
+recorded in the manifest and doesn't stop the rest.
+
+Restoring a snapshot sends its files back in dependency order,
+workspaces and styles before the stores, resources and layers that use
+them. Only missing resources are created; existing ones are left as they
+are, and layer data files aren't uploaded.
+"""
+
+import io
+import json
+import logging
+import zipfile
+from dataclasses import dataclass, field
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import TYPE_CHECKING, Any
+
+from apps.core.checksum import sha256_bytes
+from apps.core.exceptions import GeoServerError
+
+from .hrefs import store_from_href
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+logger = logging.getLogger(__name__)
+
+MANIFEST_FILE = "manifest.json"
+
+# Node types a snapshot can capture
+SNAPSHOT_TYPES = ("workspace", "datastore", "coveragestore", "layer", "style", "layergroup")
+
+# Style format -> file extension of its content
+STYLE_EXTENSIONS = {"sld": "sld", "css": "css", "mbstyle": "mbstyle", "ysld": "ysld"}
+
+# Order resources are restored in, each after those it refers to
+RESTORE_ORDER = (
+    "workspace",
+    "style",
+    "datastore",
+    "featuretype",
+    "coveragestore",
+    "coverage",
+    "layer",
+    "layergroup",
+)
+
+# Links to child collections and timestamps GeoServer refuses or sets itself
+READ_ONLY_KEYS = ("featureTypes", "coverages", "dateCreated", "dateModified")
+
+
+def _json(data: dict[str, Any]) -> bytes:
+    return json.dumps(data, indent=2).encode()
+
+
+def _safe(name: str) -> str:
+    # Names end up as path segments
+    return name.replace("/", "_").replace("\\", "_")
+
+
+@dataclass
+class SnapshotResource:
+    """A resource asked for in a snapshot and the files captured for it."""
+
+    type: str
+    name: str
+    workspace: str | None = None
+    files: list[str] = field(default_factory=list)
+    error: str = ""
+
+    @property
+    def key(self) -> tuple[str, str | None, str]:
+        return (self.type, self.workspace, self.name)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for the manifest."""
+        data: dict[str, Any] = {
+            "type": self.type,
+            "workspace": self.workspace,
+            "name": self.name,
+            "files": self.files,
+        }
+        if self.error:
+            data["error"] = self.error
+        return data
+
+
+@dataclass
+class Snapshot:
+    """Captured files of a snapshot, by path within its folder."""
+
+    folder: str
+    connection: dict[str, str]
+    created: datetime
+    include_data: bool
+    resources: list[SnapshotResource] = field(default_factory=list)
+    files: dict[str, bytes] = field(default_factory=dict)
+
+    @property
+    def failed(self) -> list[SnapshotResource]:
+        return [r for r in self.resources if r.error]
+
+    def manifest(self) -> dict[str, Any]:
+        """Describe what the snapshot captured."""
+        return {
+            "connection": self.connection,
+            "created": self.created.isoformat(),
+            "includeData": self.include_data,
+            "resources": [r.to_dict() for r in self.resources],
+            "files": {
+                path: {"size": len(data), "sha256": sha256_bytes(data)}
+                for path, data in self.files.items()
+            },
+        }
+
+    def to_zip(self) -> bytes:
+        """Pack the snapshot folder, manifest included, into a zip archive."""
+        buffer = io.BytesIO()
+        with zipfile.ZipFile(buffer, "w", zipfile.ZIP_DEFLATED) as archive:
+            archive.writestr(f"{self.folder}/{MANIFEST_FILE}", _json(self.manifest()))
+            for path, data in self.files.items():
+                archive.writestr(f"{self.folder}/{path}", data)
+        return buffer.getvalue()
+
+    def write_to(self, directory: Path) -> Path:
+        """Write the snapshot folder, manifest included, into a directory.
+
+        Returns:
+            Path of the snapshot folder
+        """
+        root = directory / self.folder
+        for path, data in self.files.items():
+            target = root / path
+            target.parent.mkdir(parents=True, exist_ok=True)
+            target.write_bytes(data)
+        root.mkdir(parents=True, exist_ok=True)
+        (root / MANIFEST_FILE).write_bytes(_json(self.manifest()))
+        return root
+
+
+class SnapshotBuilder:
+    """Captures resources of a connection into a snapshot."""
+
+    def __init__(self, client: "GeoServerClient", include_data: bool = False):
+        """Initialize builder.
+
+        Args:
+            client: GeoServer client
+            include_data: Also download layer data, shapefiles for vector
+                layers and GeoTIFFs for raster layers
+        """
+        self.client = client
+        self.include_data = include_data
+
+    def build(
+        self, targets: list[dict[str, Any]], created: datetime | None = None
+    ) -> Snapshot:
+        """Capture resources.
+
+        Args:
+            targets: Resources as {"type", "workspace", "name"}; workspaces
+                only need a name, and global styles no workspace
+            created: Time of the snapshot; now by default
+
+        Returns:
+            The snapshot
+
+        Raises:
+            ValueError: If no resources are given or one isn't a resource
+                a snapshot can capture
+        """
+        if not targets:
+            raise ValueError("No resources given")
+        resources = [self._resource(target) for target in targets]
+
+        created = created or datetime.now(timezone.utc)
+        connection = self.client.connection
+        snapshot = Snapshot(
+            folder=f"snapshot-{connection.id}-{created.strftime('%Y%m%d-%H%M%S')}",
+            connection={"id": connection.id, "name": connection.name, "url": connection.url},
+            created=created,
+            include_data=self.include_data,
+        )
+        captured: set[tuple[str, str | None, str]] = set()
+        for resource in resources:
+            if resource.key in captured:
+                continue
+            captured.add(resource.key)
+            snapshot.resources.append(resource)
+            try:
+                self._capture(snapshot, resource)
+            except GeoServerError as e:
+                resource.error = e.message
+            except Exception as e:
+                logger.exception("Snapshot of %s %s failed", resource.type, resource.name)
+                resource.error = str(e)
+        return snapshot
+
+    @staticmethod
+    def _resource(target: dict[str, Any]) -> SnapshotResource:
+        node_type = target.get("type", "")
+        name = target.get("name") or ""
+        workspace = target.get("workspace") or None
+        if node_type not in SNAPSHOT_TYPES:
+            raise ValueError(f"Can't snapshot a {node_type or 'node without a type'}")
+        if node_type == "workspace":
+            workspace = workspace or name
+            name = name or workspace or ""
+        if not name:
+            raise ValueError(f"A {node_type} needs a name")
+        if not workspace and node_type != "style":
+            raise ValueError(f"{node_type.capitalize()} '{name}' needs a workspace")
+        return SnapshotResource(node_type, name, workspace)
+
+    def _add(
+        self, snapshot: Snapshot, resource: SnapshotResource, path: str, data: bytes
+    ) -> None:
+        snapshot.files[path] = data
+        if path not in resource.files:  # Layers rewrite their store's feature type
+            resource.files.append(path)
+
+    def _capture(self, snapshot: Snapshot, resource: SnapshotResource) -> None:
+        {
+            "workspace": self._workspace,
+            "datastore": self._datastore,
+            "coveragestore": self._coveragestore,
+            "layer": self._layer,
+            "style": self._style,
+            "layergroup": self._layergroup,
+        }[resource.type](snapshot, resource)
+
+    def _workspace(self, snapshot: Snapshot, resource: SnapshotResource) -> None:
+        """Capture a workspace with everything in it."""
+        ws = resource.name
+        base = f"workspaces/{_safe(ws)}"
+        self._add(
+            snapshot,
+            resource,
+            f"{base}/workspace.json",
+            _json({"workspace": self.client.get_workspace(ws)}),
+        )
+        for store in self.client.list_datastores(ws):
+            self._datastore(snapshot, resource, ws, store["name"])
+        for store in self.client.list_coveragestores(ws):
+            self._coveragestore(snapshot, resource, ws, store["name"])
+        for layer in self.client.list_layers(ws):
+            self._layer(snapshot, resource, ws, layer["name"])
+        for style in self.client.list_styles(ws):
+            self._style(snapshot, resource, ws, style["name"])
+        for group in self.client.list_layergroups(ws):
+            self._layergroup(snapshot, resource, ws, group["name"])
+
+    def _datastore(
+        self,
+        snapshot: Snapshot,
+        resource: SnapshotResource,
+        workspace: str | None = None,
+        name: str | None = None,
+    ) -> None:
+        """Capture a data store with its feature types."""
+        ws, store = workspace or resource.workspace or "", name or resource.name
+        base = f"workspaces/{_safe(ws)}/datastores/{_safe(store)}"
+        details = self.client.get_datastore(ws, store)
+        self._add(snapshot, resource, f"{base}/datastore.json", _json({"dataStore": details}))
+        for featuretype in self.client.list_featuretypes(ws, store):
+            ft_name = featuretype["name"]
+            data = {"featureType": self.client.get_featuretype(ws, store, ft_name)}
+            self._add(snapshot, resource, f"{base}/featuretypes/{_safe(ft_name)}.json", _json(data))
+
+    def _coveragestore(
+        self,
+        snapshot: Snapshot,
+        resource: SnapshotResource,
+        workspace: str | None = None,
+        name: str | None = None,
+    ) -> None:
+        """Capture a coverage store with its coverages."""
+        ws, store = workspace or resource.workspace or "", name or resource.name
+        base = f"workspaces/{_safe(ws)}/coveragestores/{_safe(store)}"
+        details = self.client.get_coveragestore(ws, store)
+        self._add(
+            snapshot, resource, f"{base}/coveragestore.json", _json({"coverageStore": details})
+        )
+        for coverage in self.client.list_coverages(ws, store):
+            cov_name = coverage["name"]
+            data = {"coverage": self.client.get_coverage(ws, store, cov_name)}
+            self._add(snapshot, resource, f"{base}/coverages/{_safe(cov_name)}.json", _json(data))
+
+    def _layer(
+        self,
+        snapshot: Snapshot,
+        resource: SnapshotResource,
+        workspace: str | None = None,
+        name: str | None = None,
+    ) -> None:
+        """Capture a layer with its feature type or coverage, and its data when asked."""
+        ws, layer = workspace or resource.workspace or "", name or resource.name
+        base = f"workspaces/{_safe(ws)}"
+        details = self.client.get_layer(ws, layer)
+        self._add(
+            snapshot, resource, f"{base}/layers/{_safe(layer)}.json", _json({"layer": details})
+        )
+
+        href = (details.get("resource") or {}).get("href", "")
+        store = store_from_href(href)
+        if store:
+            _, store_type, store_name = store
+            kind = "featuretypes" if store_type == "datastores" else "coverages"
+            path = f"{base}/{store_type}/{_safe(store_name)}/{kind}/{_safe(layer)}.json"
+            self._add(snapshot, resource, path, _json(self.client.get_href(href)))
+
+        if not self.include_data:
+            return
+        layer_type = str(details.get("type", "")).upper()
+        if layer_type == "VECTOR":
+            data = self.client.get_layer_shapefile(ws, layer)
+            self._add(snapshot, resource, f"{base}/layers/{_safe(layer)}.zip", data)
+        elif layer_type == "RASTER":
+            data = self.client.get_coverage_geotiff(ws, layer)
+            self._add(snapshot, resource, f"{base}/layers/{_safe(layer)}.tif", data)
+
+    def _style(
+        self,
+        snapshot: Snapshot,
+        resource: SnapshotResource,
+        workspace: str | None = None,
+        name: str | None = None,
+    ) -> None:
+        """Capture a style with its content."""
+        ws = workspace if name else resource.workspace
+        style = name or resource.name
+        base = f"workspaces/{_safe(ws)}/styles" if ws else "styles"
+        details = self.client.get_style(style, ws)
+        self._add(snapshot, resource, f"{base}/{_safe(style)}.json", _json({"style": details}))
+        content, style_format = self.client.get_style_content(style, ws)
+        extension = STYLE_EXTENSIONS.get(style_format, style_format)
+        self._add(snapshot, resource, f"{base}/{_safe(style)}.{extension}", content.encode())
+
+    def _layergroup(
+        self,
+        snapshot: Snapshot,
+        resource: SnapshotResource,
+        workspace: str | None = None,
+        name: str | None = None,
+    ) -> None:
+        """Capture a layer group."""
+        ws, group = workspace or resource.workspace or "", name or resource.name
+        details = self.client.get_layergroup(group, ws)
+        path = f"workspaces/{_safe(ws)}/layergroups/{_safe(group)}.json"
+        self._add(snapshot, resource, path, _json({"layerGroup": details}))
+
+
+def build_snapshot(
+    client: "GeoServerClient",
+    targets: list[dict[str, Any]],
+    include_data: bool = False,
+    created: datetime | None = None,
+) -> Snapshot:
+    """Capture resources of a connection into a snapshot."""
+    return SnapshotBuilder(client, include_data).build(targets, created)
+
+
+@dataclass
+class RestoreReport:
+    """What restoring a snapshot created, left alone and failed to create."""
+
+    folder: str
+    created: list[str] = field(default_factory=list)
+    existing: list[str] = field(default_factory=list)
+    failed: dict[str, str] = field(default_factory=dict)  # Path -> error
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "folder": self.folder,
+            "created": self.created,
+            "existing": self.existing,
+            "failed": self.failed,
+        }
+
+
+@dataclass
+class _RestoreItem:
+    """A snapshot file and the REST collection its resource is created in."""
+
+    kind: str  # One of RESTORE_ORDER
+    path: str
+    collection: str
+    name: str
+    workspace: str | None = None
+
+
+def _restore_item(path: str) -> _RestoreItem | None:
+    """Classify a snapshot file by the resource it holds; None for other files."""
+    parts = path.split("/")
+    if not parts[-1].endswith(".json"):
+        return None  # Style content and layer data
+    name = parts[-1][: -len(".json")]
+    if len(parts) == 2 and parts[0] == "styles":
+        return _RestoreItem("style", path, "/rest/styles", name)
+    if parts[0] != "workspaces" or len(parts) < 3:
+        return None
+    ws = parts[1]
+    if len(parts) == 3 and name == "workspace":
+        return _RestoreItem("workspace", path, "/rest/workspaces", ws, ws)
+    if len(parts) == 4 and parts[2] in ("styles", "layers", "layergroups"):
+        return _RestoreItem(parts[2][:-1], path, "/rest/" + "/".join(parts[:3]), name, ws)
+    if len(parts) == 5 and parts[2] in ("datastores", "coveragestores"):
+        # The store's own file, e.g. datastores/roads/datastore.json
+        return _RestoreItem(parts[2][:-1], path, "/rest/" + "/".join(parts[:3]), parts[3], ws)
+    if len(parts) == 6 and parts[4] in ("featuretypes", "coverages"):
+        return _RestoreItem(parts[4][:-1], path, "/rest/" + "/".join(parts[:5]), name, ws)
+    return None
+
+
+def _strip_read_only(data: dict[str, Any]) -> dict[str, Any]:
+    return {
+        root: {k: v for k, v in body.items() if k not in READ_ONLY_KEYS}
+        if isinstance(body, dict)
+        else body
+        for root, body in data.items()
+    }
+
+
+def read_snapshot_zip(archive: bytes) -> tuple[str, dict[str, bytes]]:
+    """Read a snapshot zip into its folder name and files by path within it.
+
+    Raises:
+        ValueError: If the archive isn't a snapshot
+    """
+    try:
+        with zipfile.ZipFile(io.BytesIO(archive)) as zf:
+            names = [n for n in zf.namelist() if not n.endswith("/")]
+            folder = names[0].split("/", 1)[0] if names else ""
+            if f"{folder}/{MANIFEST_FILE}" not in names:
+                raise ValueError("The archive has no snapshot manifest")
+            prefix = f"{folder}/"
+            files = {n[len(prefix):]: zf.read(n) for n in names if n.startswith(prefix)}
+    except zipfile.BadZipFile as e:
+        raise ValueError(f"Not a zip archive: {e}") from e
+    return folder, files
+
+
+def _send(client: "GeoServerClient", method: str, path: str, data: dict[str, Any]) -> None:
+    response = client._request(method, path, json=_strip_read_only(data))
+    if response.status_code >= 400:
+        raise GeoServerError(
+            f"{method} {path} failed: {response.text}", status_code=response.status_code
+        )
+
+
+def restore_snapshot(client: "GeoServerClient", archive: bytes) -> RestoreReport:
+    """Recreate the resources of a snapshot zip that a connection is missing.
+
+    Each resource is restored independently; one that fails is recorded
+    and doesn't stop the rest. A layer's settings are restored only with
+    the feature type or coverage publishing it.
+
+    Raises:
+        ValueError: If the archive isn't a snapshot
+    """
+    from .client import CreateResult
+
+    folder, files = read_snapshot_zip(archive)
+    report = RestoreReport(folder)
+    items = [item for item in map(_restore_item, sorted(files)) if item]
+    items.sort(key=lambda item: RESTORE_ORDER.index(item.kind))
+    # Feature types and coverages created now, as (workspace, name), whose layers get their settings
+    published: set[tuple[str | None, str]] = set()
+
+    for item in items:
+        try:
+            data = json.loads(files[item.path])
+            if item.kind == "style":
+                style_format = data.get("style", {}).get("format") or "sld"
+                extension = STYLE_EXTENSIONS.get(style_format, style_format)
+                content = files.get(f"{item.path[: -len('.json')]}.{extension}")
+                if content is None:
+                    raise ValueError(f"No .{extension} content next to {item.path}")
+                result = client.create_style(
+                    item.name, content.decode(), style_format, item.workspace, if_absent=True
+                )
+                created = result == CreateResult.CREATED
+            elif item.kind == "layer":
+                created = (item.workspace, item.name) in published
+                if created:
+                    _send(client, "PUT", f"{item.collection}/{item.name}.json", data)
+                elif not client._exists(f"{item.collection}/{item.name}.json"):
+                    raise ValueError("Its feature type or coverage wasn't restored")
+            else:
+                created = not client._exists(f"{item.collection}/{item.name}.json")
+                if created:
+                    _send(client, "POST", f"{item.collection}.json", data)
+                    if item.kind in ("featuretype", "coverage"):
+                        published.add((item.workspace, item.name))
+        except GeoServerError as e:
+            report.failed[item.path] = e.message
+            continue
+        except (ValueError, UnicodeDecodeError) as e:
+            report.failed[item.path] = str(e)
+            continue
+        (report.created if created else report.existing).append(item.path)
+    return report
@@ -0,0 +1,389 @@
+"""Scheduled catalog snapshots kept under a retention policy.
+
+A snapshot schedule captures the whole catalog of each of its
+connections, every workspace and global style, on a cron schedule, and
+stores each as a zip in a folder or an S3 bucket, named
+snapshot-<connection id>-<YYYYMMDD-HHMMSS>.zip with a .sha256 checksum
+beside it. After each run a connection's older snapshots are pruned:
+the newest of each of the last N days with a snapshot is kept, and the
+newest of each of the last M weeks. The stored snapshots are listed
+for restoring one onto a connection.
+"""
+
+import logging
+import re
+import threading
+from dataclasses import dataclass
+from datetime import datetime
+from pathlib import Path
+from typing import TYPE_CHECKING, Any
+
+from apps.core.checksum import CHECKSUM_SUFFIX, sha256_bytes
+from apps.core.config import SnapshotSchedule, get_config
+from apps.core.exceptions import GeoServerError
+from apps.gwc.scheduler import CronExpression
+
+from .client import get_geoserver_client
+from .snapshot import build_snapshot
+
+if TYPE_CHECKING:
+    from apps.s3.client import S3Client
+
+    from .client import GeoServerClient
+
+logger = logging.getLogger(__name__)
+
+DESTINATIONS = ("local", "s3")
+
+# Stored snapshot names, as written by Snapshot.folder
+ARCHIVE_NAME = re.compile(r"^snapshot-(?P<connection>.+)-(?P<stamp>\d{8}-\d{6})\.zip$")
+STAMP_FORMAT = "%Y%m%d-%H%M%S"
+
+# Objects listed while looking for snapshots in a bucket
+MAX_LISTED = 10000
+
+
+@dataclass
+class SnapshotArchive:
+    """A stored snapshot zip."""
+
+    name: str
+    connection_id: str
+    created: datetime
+    size: int = 0
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "name": self.name,
+            "connectionId": self.connection_id,
+            "created": self.created.isoformat(),
+            "size": self.size,
+        }
+
+
+def parse_archive_name(name: str, size: int = 0) -> SnapshotArchive | None:
+    """Read the connection and time of a snapshot from its file name, or None."""
+    match = ARCHIVE_NAME.match(name)
+    if not match:
+        return None
+    try:
+        created = datetime.strptime(match["stamp"], STAMP_FORMAT)
+    except ValueError:
+        return None
+    return SnapshotArchive(name, match["connection"], created, size)
+
+
+class LocalArchives:
+    """Snapshot zips in a folder."""
+
+    def __init__(self, directory: Path):
+        self.directory = directory
+
+    def list(self) -> list[SnapshotArchive]:
+        """Snapshots in the folder; none if it doesn't exist yet."""
+        if not self.directory.is_dir():
+            return []
+        found = (parse_archive_name(p.name, p.stat().st_size) for p in self.directory.iterdir())
+        return [archive for archive in found if archive]
+
+    def write(self, name: str, data: bytes) -> None:
+        self.directory.mkdir(parents=True, exist_ok=True)
+        (self.directory / name).write_bytes(data)
+        (self.directory / f"{name}{CHECKSUM_SUFFIX}").write_text(f"{sha256_bytes(data)}  {name}\n")
+
+    def read(self, name: str) -> bytes:
+        return (self.directory / name).read_bytes()
+
+    def delete(self, name: str) -> None:
+        (self.directory / name).unlink(missing_ok=True)
+        (self.directory / f"{name}{CHECKSUM_SUFFIX}").unlink(missing_ok=True)
+
+
+class S3Archives:
+    """Snapshot zips under a key prefix of a bucket."""
+
+    def __init__(self, s3: "S3Client", bucket: str, prefix: str = ""):
+        self.s3 = s3
+        self.bucket = bucket
+        self.prefix = prefix.strip("/")
+
+    def _key(self, name: str) -> str:
+        return f"{self.prefix}/{name}" if self.prefix else name
+
+    def list(self) -> list[SnapshotArchive]:
+        prefix = f"{self.prefix}/" if self.prefix else ""
+        archives = []
+        token = None
+        listed = 0
+        while listed < MAX_LISTED:
+            page = self.s3.list_objects(self.bucket, prefix, continuation_token=token)
+            for obj in page["objects"]:
+                archive = parse_archive_name(obj["key"][len(prefix):], obj.get("size", 0))
+                if archive:
+                    archives.append(archive)
+            listed += len(page["objects"])
+            token = page.get("nextContinuationToken")
+            if not page.get("isTruncated") or not token:
+                break
+        return archives
+
+    def write(self, name: str, data: bytes) -> None:
+        self.s3.put_object(self.bucket, self._key(name), data, content_type="application/zip")
+        self.s3.put_object(
+            self.bucket,
+            self._key(f"{name}{CHECKSUM_SUFFIX}"),
+            f"{sha256_bytes(data)}  {name}\n".encode(),
+            content_type="text/plain",
+        )
+
+    def read(self, name: str) -> bytes:
+        return self.s3.get_object(self.bucket, self._key(name))
+
+    def delete(self, name: str) -> None:
+        self.s3.delete_object(self.bucket, self._key(name))
+        self.s3.delete_object(self.bucket, self._key(f"{name}{CHECKSUM_SUFFIX}"))
+
+
+def archive_store(schedule: SnapshotSchedule) -> LocalArchives | S3Archives:
+    """Where a schedule's snapshots are stored.
+
+    Raises:
+        ValueError: If the schedule's S3 connection doesn't exist
+    """
+    if schedule.destination == "s3":
+        from apps.s3.client import get_s3_client
+
+        s3 = get_s3_client(schedule.s3_connection_id)
+        return S3Archives(s3, schedule.bucket, schedule.prefix)
+    return LocalArchives(Path(schedule.directory).expanduser())
+
+
+def validate_snapshot_schedule(schedule: SnapshotSchedule) -> None:
+    """Validate a snapshot schedule.
+
+    Raises:
+        ValueError: If the schedule is invalid
+    """
+    CronExpression(schedule.cron)
+    if not schedule.connection_ids:
+        raise ValueError("Choose at least one connection to snapshot")
+    config = get_config()
+    missing = [c for c in schedule.connection_ids if not config.get_connection(c)]
+    if missing:
+        raise ValueError(f"Connection not found: {', '.join(missing)}")
+    if schedule.destination not in DESTINATIONS:
+        raise ValueError(
+            f"Invalid destination '{schedule.destination}', expected one of "
+            f"{', '.join(DESTINATIONS)}"
+        )
+    if schedule.destination == "local" and not schedule.directory:
+        raise ValueError("A folder is required for local snapshots")
+    if schedule.destination == "s3" and not (schedule.s3_connection_id and schedule.bucket):
+        raise ValueError("An S3 connection and bucket are required for S3 snapshots")
+    if schedule.keep_daily < 1 or schedule.keep_weekly < 0:
+        raise ValueError("Keep at least one daily snapshot and zero or more weeklies")
+
+
+def retained(
+    archives: list[SnapshotArchive], keep_daily: int, keep_weekly: int
+) -> list[SnapshotArchive]:
+    """The snapshots a retention policy keeps, newest first.
+
+    Args:
+        archives: One connection's snapshots
+        keep_daily: Keep the newest snapshot of each of this many most recent days
+        keep_weekly: Keep the newest snapshot of each of this many most recent ISO weeks
+    """
+    newest_first = sorted(archives, key=lambda a: a.created, reverse=True)
+    kept: dict[str, SnapshotArchive] = {}
+    for period, keep in (
+        (lambda a: a.created.date(), keep_daily),
+        (lambda a: a.created.isocalendar()[:2], keep_weekly),
+    ):
+        seen: set[Any] = set()
+        for archive in newest_first:
+            if len(seen) >= keep:
+                break
+            if period(archive) not in seen:
+                seen.add(period(archive))
+                kept[archive.name] = archive
+    return [a for a in newest_first if a.name in kept]
+
+
+def catalog_targets(client: "GeoServerClient") -> list[dict[str, Any]]:
+    """Snapshot targets covering a connection's whole catalog."""
+    targets: list[dict[str, Any]] = [
+        {"type": "workspace", "name": ws["name"]} for ws in client.list_workspaces()
+    ]
+    targets += [{"type": "style", "name": style["name"]} for style in client.list_styles()]
+    return targets
+
+
+def snapshot_connection(
+    schedule: SnapshotSchedule,
+    connection_id: str,
+    store: LocalArchives | S3Archives,
+    now: datetime | None = None,
+) -> tuple[SnapshotArchive, list[str]]:
+    """Store a snapshot of a connection's catalog and prune its older snapshots.
+
+    Returns:
+        The stored snapshot, and the names of the snapshots pruned
+
+    Raises:
+        GeoServerError: If the catalog can't be listed
+        ValueError: If the catalog is empty
+    """
+    client = get_geoserver_client(connection_id)
+    snapshot = build_snapshot(
+        client, catalog_targets(client), include_data=schedule.include_data, created=now
+    )
+    name = f"{snapshot.folder}.zip"
+    data = snapshot.to_zip()
+    store.write(name, data)
+    if snapshot.failed:
+        logger.warning(
+            "Snapshot %s left out %d resources; see its manifest", name, len(snapshot.failed)
+        )
+
+    archives = [a for a in store.list() if a.connection_id == connection_id]
+    kept = {a.name for a in retained(archives, schedule.keep_daily, schedule.keep_weekly)}
+    kept.add(name)
+    pruned = sorted(a.name for a in archives if a.name not in kept)
+    for old in pruned:
+        store.delete(old)
+    return parse_archive_name(name, len(data)), pruned
+
+
+def list_snapshots(
+    schedule: SnapshotSchedule, connection_id: str | None = None
+) -> list[SnapshotArchive]:
+    """A schedule's stored snapshots, newest first, optionally of one connection."""
+    archives = archive_store(schedule).list()
+    if connection_id:
+        archives = [a for a in archives if a.connection_id == connection_id]
+    return sorted(archives, key=lambda a: a.created, reverse=True)
+
+
+def next_run_time(schedule: SnapshotSchedule, after: datetime | None = None) -> str | None:
+    """Return the next run time of a schedule as an ISO string."""
+    if not schedule.enabled:
+        return None
+    try:
+        next_run = CronExpression(schedule.cron).next_run(after or datetime.now())
+    except ValueError:
+        return None
+    return next_run.isoformat() if next_run else None
+
+
+class SnapshotScheduler:
+    """Background scheduler that runs snapshot schedules."""
+
+    _instance: "SnapshotScheduler | None" = None
+    _lock = threading.RLock()
+
+    def __new__(cls) -> "SnapshotScheduler":
+        """Ensure singleton instance."""
+        if cls._instance is None:
+            with cls._lock:
+                if cls._instance is None:
+                    cls._instance = super().__new__(cls)
+                    cls._instance._thread: threading.Thread | None = None
+                    cls._instance._stop = threading.Event()
+                    cls._instance._last_tick: datetime | None = None
+        return cls._instance
+
+    @property
+    def running(self) -> bool:
+        """Whether the scheduler thread is alive."""
+        return self._thread is not None and self._thread.is_alive()
+
+    def start(self) -> None:
+        """Start the scheduler thread if it is not already running."""
+        with self._lock:
+            if self.running:
+                return
+            self._stop.clear()
+            # Schedules of the minute the server starts in may have run before it
+            self._last_tick = datetime.now().replace(second=0, microsecond=0)
+            self._thread = threading.Thread(
+                target=self._loop, name="snapshot-scheduler", daemon=True
+            )
+            self._thread.start()
+
+    def stop(self) -> None:
+        """Stop the scheduler thread."""
+        self._stop.set()
+
+    def _loop(self) -> None:
+        """Check schedules at the start of every minute."""
+        while not self._stop.is_set():
+            now = datetime.now().replace(second=0, microsecond=0)
+            if now != self._last_tick:
+                self._last_tick = now
+                self.tick(now)
+            self._stop.wait(60 - datetime.now().second + 1)
+
+    def tick(self, now: datetime) -> list[str]:
+        """Run every enabled schedule that matches the given minute.
+
+        Returns:
+            IDs of the schedules that were triggered
+        """
+        triggered = []
+        for schedule in list(get_config().list_snapshot_schedules()):
+            if not schedule.enabled:
+                continue
+            try:
+                if not CronExpression(schedule.cron).matches(now):
+                    continue
+            except ValueError as e:
+                logger.warning("Skipping snapshot schedule %s: %s", schedule.id, e)
+                continue
+            triggered.append(schedule.id)
+            threading.Thread(
+                target=self.run_schedule, args=(schedule.id,), daemon=True
+            ).start()
+        return triggered
+
+    def run_schedule(self, schedule_id: str) -> SnapshotSchedule | None:
+        """Snapshot each of a schedule's connections now and record the outcome.
+
+        One connection failing doesn't stop the others; the errors are
+        recorded together.
+
+        Returns:
+            The updated schedule, or None if it does not exist
+        """
+        config = get_config()
+        schedule = config.get_snapshot_schedule(schedule_id)
+        if not schedule:
+            return None
+
+        errors = []
+        try:
+            store = archive_store(schedule)
+        except ValueError as e:
+            errors.append(str(e))
+        else:
+            for connection_id in schedule.connection_ids:
+                try:
+                    archive, pruned = snapshot_connection(schedule, connection_id, store)
+                    logger.info("Stored %s, pruned %d older snapshots", archive.name, len(pruned))
+                except GeoServerError as e:
+                    errors.append(f"{connection_id}: {e.message}")
+                except Exception as e:
+                    logger.exception("Snapshot of %s failed", connection_id)
+                    errors.append(f"{connection_id}: {e}")
+
+        schedule.last_status = "failed" if errors else "success"
+        schedule.last_error = "; ".join(errors) or None
+        schedule.last_run_at = datetime.now().isoformat()
+        config.update_snapshot_schedule(schedule)
+        return schedule
+
+
+def get_snapshot_scheduler() -> SnapshotScheduler:
+    """Get the snapshot scheduler singleton."""
+    return SnapshotScheduler()
@@ -0,0 +1,145 @@
+"""Passwords among data store connection parameters.
+
+GeoServer returns a store's connection parameters with its database
+password, in plain text or in a form it can decrypt. Store details
+shown in the web UI and the API have those values replaced by a mask,
+and sending the mask back when editing a store keeps the password the
+store already has.
+
+When creating a store the password can be named rather than given: a
+value of "env:GSCLIENT_STORE_NAME" reads that environment variable, and
+"keyring:USER" the system keyring, so the password never sits in a
+template, script or shell history. References only reach variables and
+keyring entries set aside for store passwords, so a store pointed at
+another host can't be used to read the backend's own secrets.
+"""
+
+import copy
+import os
+from typing import Any
+
+from apps.core.exceptions import GeoServerError
+from apps.core.hooks import HEADER_MASK as MASK
+
+from .managed import SECRET_PARAMS
+
+# Keyring service "keyring:USER" references read, apart from the login tokens
+KEYRING_SERVICE = "kartoza-cloudbench-stores"
+
+# Prefix of the only environment variables "env:" references may name
+ENV_VAR_PREFIX = "GSCLIENT_STORE_"
+
+ENV_PREFIX = "env:"
+KEYRING_PREFIX = "keyring:"
+
+
+def is_secret(key: str) -> bool:
+    """Whether a connection parameter holds a secret."""
+    return key.lower() in SECRET_PARAMS
+
+
+def _entries(store: dict[str, Any]) -> list[dict[str, Any]]:
+    """A store's connection parameter entries, as GeoServer's JSON lists them."""
+    entries = (store.get("connectionParameters") or {}).get("entry", [])
+    return entries if isinstance(entries, list) else [entries]
+
+
+def mask_datastore(store: dict[str, Any]) -> dict[str, Any]:
+    """A copy of a data store's details with its secrets masked."""
+    masked = copy.deepcopy(store)
+    for entry in _entries(masked):
+        if is_secret(entry.get("@key", "")) and entry.get("$"):
+            entry["$"] = MASK
+    return masked
+
+
+def keep_secrets(params: dict[str, Any], existing: dict[str, Any]) -> dict[str, Any]:
+    """Put a store's current secrets in place of the masks in an edit.
+
+    Args:
+        params: Connection parameters sent to update the store
+        existing: The store's details as GeoServer has them
+
+    Returns:
+        The parameters with masked values replaced by the stored ones
+
+    Raises:
+        GeoServerError: If a masked parameter has no value in the store
+    """
+    current = {entry.get("@key"): entry.get("$", "") for entry in _entries(existing)}
+    kept = dict(params)
+    for key, value in params.items():
+        if value != MASK:
+            continue
+        if key not in current:
+            raise GeoServerError(
+                f"{key} is masked but the store has no value to keep; give the password",
+                status_code=400,
+            )
+        kept[key] = current[key]
+    return kept
+
+
+def _keyring() -> Any:
+    """Import the keyring package, which is needed to read keyring references."""
+    try:
+        import keyring
+    except ImportError as e:
+        raise ValueError(
+            "keyring: passwords need the keyring package (pip install keyring)"
+        ) from e
+    return keyring
+
+
+def resolve_secret(value: str) -> str:
+    """Read the password an "env:" or "keyring:" reference names.
+
+    Args:
+        value: Parameter value; anything else is returned as it is
+
+    Raises:
+        ValueError: If the variable or keyring entry doesn't exist, or
+            the reference names one not set aside for store passwords
+    """
+    if value.startswith(ENV_PREFIX):
+        name = value[len(ENV_PREFIX) :]
+        if not name.startswith(ENV_VAR_PREFIX):
+            raise ValueError(f"env: passwords are read from {ENV_VAR_PREFIX}* variables only")
+        if name not in os.environ:
+            raise ValueError(f"Environment variable {name} is not set")
+        return os.environ[name]
+    if value.startswith(KEYRING_PREFIX):
+        reference = value[len(KEYRING_PREFIX) :]
+        if "/" in reference:
+            raise ValueError(
+                f"keyring: passwords are read from the {KEYRING_SERVICE} service; "
+                "give only the user"
+            )
+        keyring = _keyring()
+        try:
+            secret = keyring.get_password(KEYRING_SERVICE, reference)
+        except keyring.errors.KeyringError as e:
+            raise ValueError(f"Could not read the system keyring: {e}") from e
+        if secret is None:
+            raise ValueError(f"No password for {reference} in the system keyring")
+        return secret
+    return value
+
+
+def resolve_secrets(params: dict[str, Any]) -> dict[str, Any]:
+    """Connection parameters with their secret references read.
+
+    Raises:
+        ValueError: If a reference can't be read, or a mask is given
+            where there is no existing value to keep
+    """
+    resolved = dict(params)
+    for key, value in params.items():
+        if not is_secret(key) or not isinstance(value, str):
+            continue
+        if value == MASK:
+            raise ValueError(
+                f"{key} is masked; give the password or an env: or keyring: reference"
+            )
+        resolved[key] = resolve_secret(value)
+    return resolved
@@ -0,0 +1,104 @@
+"""Enabled flags of a workspace's stores.
+
+GeoServer's store listings only carry each store's name and link, so
+telling enabled stores from disabled ones takes a request per store.
+Those requests run on a small worker pool rather than one after another,
+and workspaces with more stores than a limit skip them altogether,
+leaving the flag unknown, so listing a very large workspace stays a
+single request. Listing entries that already carry the flag are kept as
+they are.
+"""
+
+import logging
+from concurrent.futures import ThreadPoolExecutor
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+logger = logging.getLogger(__name__)
+
+STORE_KINDS = ("datastore", "coveragestore")
+
+# Store details read at once; the connection's rate limit still applies
+DEFAULT_WORKERS = 8
+
+# Workspaces with more stores than this aren't checked store by store
+MAX_STATUS_STORES = 200
+
+
+def list_stores(client: "GeoServerClient", workspace: str, kind: str) -> list[dict[str, Any]]:
+    """List a workspace's data stores or coverage stores, as GeoServer does."""
+    if kind == "datastore":
+        return client.list_datastores(workspace)
+    if kind == "coveragestore":
+        return client.list_coveragestores(workspace)
+    raise ValueError(f"Unknown store kind: {kind}")
+
+
+def add_enabled(
+    client: "GeoServerClient",
+    workspace: str,
+    kind: str,
+    stores: list[dict[str, Any]],
+    max_workers: int = DEFAULT_WORKERS,
+    limit: int | None = MAX_STATUS_STORES,
+) -> list[dict[str, Any]]:
+    """Add an "enabled" flag to store listing entries.
+
+    Args:
+        client: GeoServer client
+        workspace: Workspace name
+        kind: "datastore" or "coveragestore"
+        stores: Entries from the workspace's store listing
+        max_workers: Store details read at once
+        limit: Most stores read one by one; with more, the flags are
+            left unknown. None reads every store.
+
+    Returns:
+        Copies of the entries, in the same order, with "enabled" True,
+        False or None when it couldn't be read or was skipped
+    """
+    if kind not in STORE_KINDS:
+        raise ValueError(f"Unknown store kind: {kind}")
+    get_store = client.get_datastore if kind == "datastore" else client.get_coveragestore
+
+    entries = [dict(store) for store in stores]
+    missing = [entry for entry in entries if "enabled" not in entry]
+    if limit is not None and len(missing) > limit:
+        for entry in missing:
+            entry["enabled"] = None
+        return entries
+
+    def read(entry: dict[str, Any]) -> bool | None:
+        name = entry.get("name")
+        if not name:
+            return None
+        try:
+            return bool(get_store(workspace, name).get("enabled", True))
+        except GeoServerError as e:
+            logger.warning("Could not read %s %s:%s: %s", kind, workspace, name, e)
+            return None
+
+    if missing:
+        with ThreadPoolExecutor(max_workers=max(1, min(max_workers, len(missing)))) as executor:
+            for entry, enabled in zip(missing, executor.map(read, missing)):
+                entry["enabled"] = enabled
+    return entries
+
+
+def list_stores_with_status(
+    client: "GeoServerClient",
+    workspace: str,
+    kind: str,
+    max_workers: int = DEFAULT_WORKERS,
+    limit: int | None = MAX_STATUS_STORES,
+) -> list[dict[str, Any]]:
+    """List a workspace's stores of one kind with whether each is enabled.
+
+    See add_enabled for the arguments and the flag's values.
+    """
+    stores = list_stores(client, workspace, kind)
+    return add_enabled(client, workspace, kind, stores, max_workers, limit)
@@ -0,0 +1,337 @@
+"""Icons and other graphics kept next to styles in the data directory.
+
+Point styles draw their symbols from ExternalGraphic references, which
+GeoServer resolves against the folder the style's SLD lives in: styles/
+for global styles and workspaces/<workspace>/styles/ for workspace ones.
+Graphics referenced by a path relative to that folder travel with the
+styles when a data directory is copied to another server, unlike absolute
+file paths. The resource API is used to list the graphics in a styles
+folder, upload new ones and move them, rewriting the references of the
+styles that use a graphic when it moves.
+"""
+
+import posixpath
+import re
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+from urllib.parse import urlparse
+from xml.sax.saxutils import escape
+
+from apps.core.exceptions import GeoServerError
+
+from .datadir import DEFAULT_REQUEST_BUDGET
+from .style_package import external_graphics, graphic_path
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Graphics listed in a styles folder, and the ones that can be uploaded
+ASSET_EXTENSIONS = {".png", ".svg", ".jpg", ".jpeg", ".gif"}
+UPLOAD_CONTENT_TYPES = {".png": "image/png", ".svg": "image/svg+xml"}
+
+# Files a styles folder holds besides graphics; no need to ask what they are
+_STYLE_EXTENSIONS = {".sld", ".css", ".json", ".xml", ".ttf", ".otf"}
+
+_PNG_SIGNATURE = b"\x89PNG\r\n\x1a\n"
+
+
+def styles_folder(workspace: str | None = None) -> str:
+    """Get the data directory folder the styles of a workspace are kept in."""
+    return f"workspaces/{workspace}/styles" if workspace else "styles"
+
+
+def _extension(path: str) -> str:
+    return posixpath.splitext(path)[1].lower()
+
+
+def clean_asset_path(path: str) -> str:
+    """Normalize a graphic's path relative to its styles folder.
+
+    Raises:
+        ValueError: If the path leaves the styles folder or isn't an image
+    """
+    cleaned = posixpath.normpath((path or "").strip().replace("\\", "/").strip("/"))
+    if cleaned in ("", ".") or cleaned == ".." or cleaned.startswith("../"):
+        raise ValueError(f"{path}: not a path inside the styles folder")
+    if _extension(cleaned) not in ASSET_EXTENSIONS:
+        extensions = ", ".join(sorted(ASSET_EXTENSIONS))
+        raise ValueError(f"{path}: not an image ({extensions})")
+    return cleaned
+
+
+@dataclass
+class StyleAsset:
+    """A graphic in a styles folder and the styles drawing with it."""
+
+    path: str  # Relative to the styles folder
+    size: int = 0
+    styles: list[str] = field(default_factory=list)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {"path": self.path, "size": self.size, "styles": self.styles}
+
+
+@dataclass
+class UnresolvedGraphic:
+    """A graphic reference of a style that no asset in its folder answers."""
+
+    style: str
+    href: str
+    reason: str  # "missing" or "absolute"
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {"style": self.style, "href": self.href, "reason": self.reason}
+
+
+@dataclass
+class AssetListing:
+    """The graphics of a styles folder and the references styles make to them."""
+
+    folder: str
+    assets: list[StyleAsset] = field(default_factory=list)
+    unresolved: list[UnresolvedGraphic] = field(default_factory=list)
+    complete: bool = True  # False when the folder walk ran out of requests
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "folder": self.folder,
+            "assets": [a.to_dict() for a in self.assets],
+            "unresolved": [u.to_dict() for u in self.unresolved],
+            "complete": self.complete,
+        }
+
+
+def _walk_assets(
+    client: "GeoServerClient", folder: str, budget: int
+) -> tuple[dict[str, int], bool]:
+    """Find the graphics under a folder, with their sizes.
+
+    Returns:
+        Tuple of (sizes by path relative to the folder, whether the walk finished)
+    """
+    sizes: dict[str, int] = {}
+    pending = [""]
+    requests = 0
+    while pending:
+        relative = pending.pop(0)
+        if requests >= budget:
+            return sizes, False
+        requests += 1
+        try:
+            children = client.list_resource_directory(f"{folder}/{relative}".rstrip("/"))
+        except GeoServerError:
+            if not relative:
+                raise
+            continue
+        for name in children:
+            path = posixpath.join(relative, name) if relative else name
+            extension = _extension(name)
+            if extension in _STYLE_EXTENSIONS:
+                continue
+            if requests >= budget:
+                return sizes, False
+            requests += 1
+            info = client.get_resource_info(f"{folder}/{path}")
+            if info["type"] == "directory":
+                pending.append(path)
+            elif info["type"] == "resource" and extension in ASSET_EXTENSIONS:
+                sizes[path] = info["size"]
+    return sizes, True
+
+
+def style_graphics(
+    client: "GeoServerClient", workspace: str | None = None
+) -> dict[str, list[str]]:
+    """Read the graphic references of each SLD style in a workspace.
+
+    Styles that can't be read or parsed are left out.
+
+    Returns:
+        References by style name, for styles that have any
+    """
+    graphics: dict[str, list[str]] = {}
+    for style in client.list_styles(workspace):
+        name = style.get("name")
+        if not name:
+            continue
+        try:
+            content, style_format = client.get_style_content(name, workspace)
+            if style_format != "sld":
+                continue
+            hrefs = external_graphics(content)
+        except (GeoServerError, ValueError):
+            continue
+        if hrefs:
+            graphics[name] = hrefs
+    return graphics
+
+
+def _is_local_file(href: str) -> bool:
+    """Check whether a reference names a file on the server rather than a URL."""
+    scheme = urlparse(href).scheme
+    return scheme in ("", "file") or len(scheme) == 1  # C:/icons/pin.png
+
+
+def list_assets(
+    client: "GeoServerClient",
+    workspace: str | None = None,
+    budget: int = DEFAULT_REQUEST_BUDGET,
+) -> AssetListing:
+    """List the graphics of a styles folder and which styles refer to them.
+
+    Args:
+        client: GeoServer client
+        workspace: Workspace whose styles folder is listed; the global one when None
+        budget: Maximum number of resource requests for walking the folder
+
+    Raises:
+        GeoServerError: If the resource API isn't available
+    """
+    folder = styles_folder(workspace)
+    sizes, complete = _walk_assets(client, folder, budget)
+    assets = {path: StyleAsset(path, size) for path, size in sorted(sizes.items())}
+    listing = AssetListing(folder, list(assets.values()), complete=complete)
+
+    for style, hrefs in sorted(style_graphics(client, workspace).items()):
+        for href in hrefs:
+            path = graphic_path(href, "")
+            if path in assets:
+                if style not in assets[path].styles:
+                    assets[path].styles.append(style)
+            elif path is None and _is_local_file(href):
+                listing.unresolved.append(UnresolvedGraphic(style, href, "absolute"))
+            elif path is not None and complete:
+                listing.unresolved.append(UnresolvedGraphic(style, href, "missing"))
+    return listing
+
+
+def upload_asset(
+    client: "GeoServerClient",
+    path: str,
+    data: bytes,
+    workspace: str | None = None,
+) -> str:
+    """Upload a PNG or SVG graphic into a styles folder.
+
+    A graphic already at the path is replaced.
+
+    Args:
+        client: GeoServer client
+        path: Path relative to the styles folder, e.g. icons/hospital.png
+        data: Image contents
+        workspace: Workspace whose styles folder it goes into; the global one when None
+
+    Returns:
+        The cleaned path relative to the styles folder
+
+    Raises:
+        ValueError: If the path or contents aren't a PNG or SVG image
+        GeoServerError: If the upload fails
+    """
+    path = clean_asset_path(path)
+    extension = _extension(path)
+    if extension not in UPLOAD_CONTENT_TYPES:
+        raise ValueError(f"{path}: only PNG and SVG graphics can be uploaded")
+    if extension == ".png" and not data.startswith(_PNG_SIGNATURE):
+        raise ValueError(f"{path}: not a PNG image")
+    if extension == ".svg" and b"<svg" not in data[:4096]:
+        raise ValueError(f"{path}: not an SVG image")
+    client.upload_resource(
+        f"{styles_folder(workspace)}/{path}", data, UPLOAD_CONTENT_TYPES[extension]
+    )
+    return path
+
+
+def rewrite_graphic_hrefs(content: str, source: str, target: str) -> tuple[str, int]:
+    """Point the references of an SLD at a graphic to its new path.
+
+    Only the attribute values change, so the rest of the document keeps
+    its formatting. A file: prefix on a reference is kept.
+
+    Args:
+        content: SLD content
+        source: Old path of the graphic relative to the styles folder
+        target: New path relative to the styles folder
+
+    Returns:
+        Tuple of (rewritten content, number of references changed)
+    """
+    changed = 0
+    for href in external_graphics(content):
+        if graphic_path(href, "") != source:
+            continue
+        new_href = f"file:{target}" if href.startswith("file:") else target
+        pattern = re.compile(r"(:href\s*=\s*)([\"'])" + re.escape(escape(href)) + r"\2")
+        content, count = pattern.subn(
+            lambda m: f"{m.group(1)}{m.group(2)}{escape(new_href)}{m.group(2)}", content
+        )
+        changed += count
+    return content, changed
+
+
+@dataclass
+class AssetMove:
+    """Outcome of moving a graphic: the styles rewritten to follow it."""
+
+    source: str
+    target: str
+    updated: list[str] = field(default_factory=list)
+    failed: dict[str, str] = field(default_factory=dict)  # Style -> error
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "source": self.source,
+            "target": self.target,
+            "updated": self.updated,
+            "failed": self.failed,
+        }
+
+
+def move_asset(
+    client: "GeoServerClient",
+    source: str,
+    target: str,
+    workspace: str | None = None,
+) -> AssetMove:
+    """Move a graphic within a styles folder and update the styles using it.
+
+    The styles referring to the graphic are found before it moves. A
+    style that can't be updated is reported rather than stopping the
+    others, and keeps pointing at the old path.
+
+    Args:
+        client: GeoServer client
+        source: Path of the graphic relative to the styles folder
+        target: New path relative to the styles folder
+        workspace: Workspace whose styles folder holds it; the global one when None
+
+    Raises:
+        ValueError: If either path isn't an image inside the styles folder
+        GeoServerError: If the graphic can't be moved
+    """
+    source, target = clean_asset_path(source), clean_asset_path(target)
+    if source == target:
+        raise ValueError(f"{source} is already there")
+    users = [
+        style
+        for style, hrefs in style_graphics(client, workspace).items()
+        if any(graphic_path(href, "") == source for href in hrefs)
+    ]
+    folder = styles_folder(workspace)
+    client.move_resource(f"{folder}/{source}", f"{folder}/{target}")
+
+    move = AssetMove(source, target)
+    for style in sorted(users):
+        try:
+            content, style_format = client.get_style_content(style, workspace)
+            content, changed = rewrite_graphic_hrefs(content, source, target)
+            if changed:
+                client.update_style_content(style, content, style_format, workspace)
+                move.updated.append(style)
+        except GeoServerError as e:
+            move.failed[style] = e.message
+    return move
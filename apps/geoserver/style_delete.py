@@ -0,0 +1,85 @@
+"""Deleting a style that layers still use.
+
+GeoServer only deletes a style in use with recurse=true, leaving its
+layers on the server's default style. The layers can instead be given
+another style first, in the same role, and the tiles they cached with
+the deleted style are truncated afterwards.
+"""
+
+from dataclasses import dataclass, field
+from typing import Any
+
+from apps.core.exceptions import GeoServerError
+from apps.gwc.invalidation import (
+    find_style_layers,
+    is_auto_truncate_enabled,
+    truncate_style_layers,
+)
+
+from .client import get_geoserver_client
+
+
+@dataclass
+class StyleDeletion:
+    """Layers a style deletion touched."""
+
+    affected: list[dict[str, str]] = field(default_factory=list)
+    reassigned: list[str] = field(default_factory=list)
+    truncated: list[str] = field(default_factory=list)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "affectedLayers": self.affected,
+            "reassignedLayers": self.reassigned,
+            "truncatedLayers": self.truncated,
+        }
+
+
+def delete_style(
+    conn_id: str,
+    workspace: str,
+    style: str,
+    purge: bool = False,
+    reassign_to: str = "",
+) -> StyleDeletion:
+    """Delete a style, giving the layers that use it another one first.
+
+    Args:
+        conn_id: Connection ID
+        workspace: Workspace of the style, or "" for a global style
+        style: Style name
+        purge: Also remove the style file from disk
+        reassign_to: Style the layers get instead, in the same role
+            (default or additional); without it they fall back to
+            GeoServer's default style
+
+    Raises:
+        ValueError: If the layers would be reassigned to the style itself
+        GeoServerError: If a request fails
+    """
+    if reassign_to and reassign_to in (style, f"{workspace}:{style}"):
+        raise ValueError("Cannot reassign layers to the style being deleted")
+
+    client = get_geoserver_client(conn_id)
+    try:
+        affected = find_style_layers(conn_id, workspace, style)
+    except (GeoServerError, ValueError) as e:
+        # Only needed up front when layers have to be reassigned;
+        # otherwise GeoServer refuses to delete a style still in use
+        if reassign_to:
+            raise GeoServerError(f"Could not find layers using the style: {e}") from e
+        affected = []
+
+    deletion = StyleDeletion(affected=affected)
+    if reassign_to:
+        for item in affected:
+            layer_workspace, layer = item["layer"].split(":", 1)
+            client.replace_layer_style(layer_workspace, layer, style, reassign_to)
+            deletion.reassigned.append(item["layer"])
+
+    client.delete_style(style, workspace, purge=purge, recurse=bool(affected))
+
+    if affected and is_auto_truncate_enabled(conn_id):
+        deletion.truncated = truncate_style_layers(conn_id, workspace, style, affected)
+    return deletion
@@ -0,0 +1,216 @@
+"""Differences between versions of a style.
+
+Before a local file replaces a style, or a sync copies styles from one
+server to another, a unified diff shows exactly which lines would
+change. Styles are compared as text with their line endings normalized,
+so a file saved on Windows doesn't show every line as changed.
+
+Each side also carries a hash of its content, as recorded for managed
+resources, so promoting styles can tell which ones really differ.
+
+The old side is always what is there now: the server's style when
+importing a file, and the destination's when syncing.
+"""
+
+import difflib
+from concurrent.futures import ThreadPoolExecutor
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+from .managed import content_hash
+from .style_import import StyleFile
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Unchanged lines shown around each change
+CONTEXT_LINES = 3
+
+# Styles read at once from each server when comparing connections
+MAX_WORKERS = 4
+
+STATUSES = ("new", "changed", "unchanged")
+
+
+@dataclass
+class StyleDiff:
+    """The changes replacing one version of a style with another would make."""
+
+    name: str
+    workspace: str | None
+    old_label: str
+    new_label: str
+    old_format: str | None  # None when the style doesn't exist yet
+    new_format: str
+    lines: list[str]  # Unified diff, without line endings
+    old_hash: str | None = None  # None when the style doesn't exist yet
+    new_hash: str = ""
+
+    @property
+    def additions(self) -> int:
+        return sum(1 for line in self.lines if line.startswith("+") and not line.startswith("+++"))
+
+    @property
+    def deletions(self) -> int:
+        return sum(1 for line in self.lines if line.startswith("-") and not line.startswith("---"))
+
+    @property
+    def status(self) -> str:
+        """One of STATUSES."""
+        if self.old_format is None:
+            return "new"
+        if self.lines or self.old_format != self.new_format:
+            return "changed"
+        return "unchanged"
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "name": self.name,
+            "workspace": self.workspace,
+            "from": self.old_label,
+            "to": self.new_label,
+            "fromFormat": self.old_format,
+            "toFormat": self.new_format,
+            "fromHash": self.old_hash,
+            "toHash": self.new_hash,
+            "status": self.status,
+            "additions": self.additions,
+            "deletions": self.deletions,
+            "diff": "\n".join(self.lines),
+        }
+
+
+def diff_lines(
+    old: str, new: str, old_label: str, new_label: str, context: int = CONTEXT_LINES
+) -> list[str]:
+    """A unified diff of two texts; empty when they are the same."""
+    return list(
+        difflib.unified_diff(
+            old.splitlines(),
+            new.splitlines(),
+            fromfile=old_label,
+            tofile=new_label,
+            lineterm="",
+            n=context,
+        )
+    )
+
+
+def style_label(client: "GeoServerClient", name: str, workspace: str | None) -> str:
+    """Name a connection's style in diff headers, e.g. Production/topp/roads."""
+    return "/".join(part for part in (client.connection.name, workspace, name) if part)
+
+
+def read_server_style(
+    client: "GeoServerClient", name: str, workspace: str | None
+) -> tuple[str, str] | None:
+    """A style's content and format, or None if the server has no such style.
+
+    Raises:
+        GeoServerError: If the style can't be read for another reason
+    """
+    try:
+        return client.get_style_content(name, workspace)
+    except GeoServerError as e:
+        if e.status_code == 404:
+            return None
+        raise
+
+
+def diff_style(
+    old: tuple[str, str] | None,
+    new: tuple[str, str],
+    name: str,
+    workspace: str | None,
+    old_label: str,
+    new_label: str,
+    context: int = CONTEXT_LINES,
+) -> StyleDiff:
+    """Compare two versions of a style, each given as (content, format).
+
+    A missing old version is compared as empty, so every line shows as added.
+    """
+    old_content, old_format = old if old is not None else ("", None)
+    return StyleDiff(
+        name=name,
+        workspace=workspace,
+        old_label=old_label,
+        new_label=new_label,
+        old_format=old_format,
+        new_format=new[1],
+        lines=diff_lines(old_content, new[0], old_label, new_label, context),
+        old_hash=content_hash(old_content) if old is not None else None,
+        new_hash=content_hash(new[0]),
+    )
+
+
+def diff_local_style(
+    client: "GeoServerClient",
+    style_file: StyleFile,
+    workspace: str | None = None,
+    context: int = CONTEXT_LINES,
+) -> StyleDiff:
+    """Compare the server's version of a style with a local file about to replace it.
+
+    Raises:
+        GeoServerError: If the server's style can't be read
+    """
+    return diff_style(
+        read_server_style(client, style_file.name, workspace),
+        (style_file.content, style_file.format),
+        style_file.name,
+        workspace,
+        style_label(client, style_file.name, workspace),
+        style_file.path,
+        context,
+    )
+
+
+def diff_connection_style(
+    source: "GeoServerClient",
+    dest: "GeoServerClient",
+    name: str,
+    workspace: str | None = None,
+    context: int = CONTEXT_LINES,
+) -> StyleDiff:
+    """Compare the destination's version of a style with the source's that a sync would copy.
+
+    Raises:
+        GeoServerError: If the source has no such style, or either can't be read
+    """
+    new = source.get_style_content(name, workspace)
+    return diff_style(
+        read_server_style(dest, name, workspace),
+        new,
+        name,
+        workspace,
+        style_label(dest, name, workspace),
+        style_label(source, name, workspace),
+        context,
+    )
+
+
+def diff_connection_styles(
+    source: "GeoServerClient",
+    dest: "GeoServerClient",
+    workspace: str | None = None,
+    context: int = CONTEXT_LINES,
+) -> list[StyleDiff]:
+    """Compare every style of the source with the destination's, in name order.
+
+    Styles only the destination has are left out, as a sync without
+    pruning leaves them alone.
+
+    Raises:
+        GeoServerError: If the source's styles can't be listed or read
+    """
+    names = sorted({s.get("name") for s in source.list_styles(workspace) if s.get("name")})
+    with ThreadPoolExecutor(max_workers=max(1, min(MAX_WORKERS, len(names)))) as executor:
+        return list(
+            executor.map(
+                lambda name: diff_connection_style(source, dest, name, workspace, context), names
+            )
+        )
@@ -0,0 +1,168 @@
+"""Editing style content in an external editor.
+
+Power users often prefer their own editor, with its syntax highlighting
+and macros, to the built-in style wizard. An edit downloads the style to
+a temp file, lets the caller run $VISUAL or $EDITOR on it, then checks
+the file and uploads it back. A file that fails the checks is kept so
+the next edit picks up where the user left off instead of losing work.
+"""
+
+import json
+import os
+import shlex
+import sys
+import tempfile
+import xml.etree.ElementTree as ET
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import TYPE_CHECKING
+
+from apps.gwc.invalidation import invalidate_style_cache
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Style format -> file suffix, so editors pick the right syntax mode
+STYLE_SUFFIXES = {"sld": ".sld", "css": ".css", "mbstyle": ".json"}
+
+
+def editor_command(path: Path, environ: dict[str, str] | None = None) -> list[str]:
+    """Build the command that opens a file in the user's editor.
+
+    $VISUAL wins over $EDITOR; either may carry arguments, e.g. "code -w".
+    """
+    environ = os.environ if environ is None else environ
+    editor = environ.get("VISUAL") or environ.get("EDITOR")
+    if not editor:
+        editor = "notepad" if sys.platform == "win32" else "vi"
+    return [*shlex.split(editor), str(path)]
+
+
+def validate_style(content: str, style_format: str) -> list[str]:
+    """Check style content before it is uploaded.
+
+    Only catches what would make GeoServer reject the upload outright or
+    store a style that can't render; GeoServer still has the last word.
+
+    Returns:
+        Problems found; empty when the content looks valid
+    """
+    if not content.strip():
+        return ["The style is empty"]
+
+    if style_format == "sld":
+        try:
+            root = ET.fromstring(content)
+        except ET.ParseError as e:
+            return [f"Invalid XML: {e}"]
+        if not root.tag.endswith("StyledLayerDescriptor"):
+            return [f"Root element is {root.tag}, expected StyledLayerDescriptor"]
+        return []
+
+    if style_format == "mbstyle":
+        try:
+            data = json.loads(content)
+        except ValueError as e:
+            return [f"Invalid JSON: {e}"]
+        if not isinstance(data, dict) or "layers" not in data:
+            return ["A MapBox style needs a top-level \"layers\" list"]
+        return []
+
+    if style_format == "css":
+        depth = 0
+        for number, line in enumerate(content.splitlines(), 1):
+            depth += line.count("{") - line.count("}")
+            if depth < 0:
+                return [f"Line {number}: unexpected '}}'"]
+        return ["Unclosed '{'"] if depth else []
+
+    return []
+
+
+@dataclass
+class StyleEditResult:
+    """Outcome of an external edit."""
+
+    status: str  # "unchanged", "invalid" or "uploaded"
+    errors: list[str] = field(default_factory=list)
+    affected_layers: list[dict[str, str]] = field(default_factory=list)
+    truncated: list[str] = field(default_factory=list)
+
+
+class ExternalStyleEdit:
+    """One style being edited in an external editor."""
+
+    def __init__(
+        self,
+        client: "GeoServerClient",
+        conn_id: str,
+        name: str,
+        workspace: str | None = None,
+    ):
+        """Initialize edit.
+
+        Args:
+            client: GeoServer client
+            conn_id: Connection ID, for tile cache invalidation after upload
+            name: Style name
+            workspace: Style workspace; a global style when omitted
+        """
+        self.client = client
+        self.conn_id = conn_id
+        self.name = name
+        self.workspace = workspace
+        self.style_format = "sld"
+        self.original = ""
+        self.path: Path | None = None
+
+    def download(self) -> Path:
+        """Write the style content to a temp file.
+
+        Returns:
+            Path of the file to edit
+        """
+        self.original, self.style_format = self.client.get_style_content(
+            self.name, self.workspace
+        )
+        prefix = f"{self.workspace}_{self.name}_" if self.workspace else f"{self.name}_"
+        fd, filename = tempfile.mkstemp(
+            prefix=prefix, suffix=STYLE_SUFFIXES.get(self.style_format, ".sld")
+        )
+        with os.fdopen(fd, "w", encoding="utf-8") as f:
+            f.write(self.original)
+        self.path = Path(filename)
+        return self.path
+
+    def command(self) -> list[str]:
+        """Command that opens the temp file in the user's editor."""
+        if self.path is None:
+            raise RuntimeError("Download the style before editing it")
+        return editor_command(self.path)
+
+    def finish(self) -> StyleEditResult:
+        """Check the edited file and upload it if it changed.
+
+        The temp file is removed unless the content is invalid.
+        """
+        if self.path is None:
+            raise RuntimeError("Download the style before editing it")
+        content = self.path.read_text(encoding="utf-8")
+
+        if content == self.original:
+            self.cleanup()
+            return StyleEditResult("unchanged")
+
+        errors = validate_style(content, self.style_format)
+        if errors:
+            return StyleEditResult("invalid", errors)
+
+        self.client.update_style_content(self.name, content, self.style_format, self.workspace)
+        self.cleanup()
+        affected, truncated = invalidate_style_cache(self.conn_id, self.workspace or "", self.name)
+        return StyleEditResult("uploaded", affected_layers=affected, truncated=truncated)
+
+    def cleanup(self) -> None:
+        """Remove the temp file."""
+        if self.path is not None:
+            self.path.unlink(missing_ok=True)
+            self.path = None
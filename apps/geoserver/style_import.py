@@ -0,0 +1,412 @@
+"""Bulk import of a local folder of styles, or of styles by URL.
+
+Every .sld and .css file in a folder becomes a style named after the file
+(roads.sld -> roads) in one workspace, or among the global styles. A style
+that already exists is only uploaded again when its content changed, so a
+folder kept under version control can be imported over and over and only
+what was edited reaches GeoServer. Content is compared by SHA-256 after
+normalizing line endings and surrounding whitespace, which editors and
+GeoServer don't preserve reliably.
+
+Styles can also be referenced by an http(s) URL, such as a raw file in a
+Git repository, so a team can keep its styles in Git and push them to any
+server by reference. Private repositories take an Authorization header,
+e.g. "token ..." for GitHub or "Bearer ..." for GitLab. The header set in
+the environment is only sent to GitHub, GitLab and the hosts configured
+as trusted, and URLs of other hosts with private or loopback addresses
+are refused, so a URL can't be used to reach the server's own network.
+
+A .zip file is a style package, an SLD with the icons and fonts it uses
+(see style_package). Packages are always uploaded, as their graphics may
+have changed even when the SLD didn't.
+"""
+
+import ipaddress
+import logging
+import os
+import re
+import socket
+from collections.abc import Callable
+from concurrent.futures import ThreadPoolExecutor
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import TYPE_CHECKING, Any
+from urllib.parse import unquote, urlparse
+
+import httpx
+
+from apps.core.checksum import sha256_bytes
+from apps.core.exceptions import GeoServerError
+from apps.gwc.invalidation import invalidate_style_cache
+
+from .naming import validate_name
+from .style_edit import validate_style
+from .style_package import PACKAGE_SUFFIX, read_style_package
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+logger = logging.getLogger(__name__)
+
+# File extension -> style format
+STYLE_FORMATS = {".sld": "sld", ".css": "css"}
+
+# Styles uploaded at once
+MAX_WORKERS = 4
+
+# Largest style accepted from a URL
+MAX_URL_BYTES = 5 * 1024 * 1024
+URL_TIMEOUT = 30.0
+
+# Redirects followed when downloading a style
+MAX_REDIRECTS = 5
+
+# Environment variable holding the Authorization header used when none is given
+AUTHORIZATION_ENV = "CLOUDBENCH_STYLE_AUTHORIZATION"
+
+# Hosts the environment's Authorization header is sent to
+TRUSTED_HOSTS = ("github.com", "raw.githubusercontent.com", "gitlab.com")
+
+# Environment variable listing more trusted hosts, comma-separated, such as a
+# self-hosted GitLab; they may also have private addresses
+TRUSTED_HOSTS_ENV = "CLOUDBENCH_STYLE_HOSTS"
+
+# Outcomes of importing one file, in the order they are reported
+STATUSES = ("created", "updated", "skipped", "failed")
+
+
+def style_name(filename: str) -> str:
+    """Infer a style name from a file name, e.g. "Main Roads.sld" -> "Main_Roads"."""
+    return re.sub(r"[^\w.-]+", "_", Path(filename).stem).strip("_")
+
+
+def content_hash(content: str) -> str:
+    """Hash style content, ignoring line endings and surrounding whitespace."""
+    lines = content.replace("\r\n", "\n").replace("\r", "\n").strip().split("\n")
+    return sha256_bytes("\n".join(line.rstrip() for line in lines).encode("utf-8"))
+
+
+@dataclass
+class StyleFile:
+    """A style file to import."""
+
+    path: str  # Relative to the folder, the uploaded file name or the URL
+    name: str
+    format: str
+    content: str  # The SLD of a package
+    package: bytes | None = None  # Zip of a style package
+    missing_graphics: list[str] = field(default_factory=list)  # Not in the package
+
+    @classmethod
+    def from_bytes(cls, path: str, data: bytes) -> "StyleFile":
+        """Read a style file or style package.
+
+        Raises:
+            ValueError: If the file isn't a .sld, .css or .zip file, isn't
+                UTF-8 or isn't a valid style package
+        """
+        if Path(path).suffix.lower() == PACKAGE_SUFFIX:
+            try:
+                package = read_style_package(data)
+            except ValueError as e:
+                raise ValueError(f"{path}: {e}") from e
+            return cls(path, style_name(path), "sld", package.content, data, package.missing)
+
+        style_format = STYLE_FORMATS.get(Path(path).suffix.lower())
+        if style_format is None:
+            raise ValueError(f"{path}: not a .sld, .css or .zip file")
+        try:
+            content = data.decode("utf-8-sig")
+        except UnicodeDecodeError as e:
+            raise ValueError(f"{path}: not UTF-8 text") from e
+        return cls(path, style_name(path), style_format, content)
+
+
+@dataclass
+class StyleImportResult:
+    """Outcome of importing one style file."""
+
+    path: str
+    name: str
+    status: str  # One of STATUSES
+    error: str = ""
+    warning: str = ""  # E.g. graphics missing from a package
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        data: dict[str, Any] = {"path": self.path, "name": self.name, "status": self.status}
+        if self.error:
+            data["error"] = self.error
+        if self.warning:
+            data["warning"] = self.warning
+        return data
+
+
+def is_style_file(filename: str) -> bool:
+    """Check whether a file is a style file or style package by its extension."""
+    suffix = Path(filename).suffix.lower()
+    return suffix in STYLE_FORMATS or suffix == PACKAGE_SUFFIX
+
+
+def is_style_url(text: str) -> bool:
+    """Check whether a style reference is an http(s) URL rather than a path."""
+    return urlparse(text.strip()).scheme in ("http", "https")
+
+
+def raw_url(url: str) -> str:
+    """Turn a GitHub or GitLab file page into the URL of the file's content.
+
+    Other URLs, including raw ones, are returned unchanged.
+    """
+    parsed = urlparse(url)
+    parts = parsed.path.split("/")
+    # github.com/{owner}/{repo}/blob/{ref}/{path}
+    if parsed.netloc == "github.com" and len(parts) > 5 and parts[3] == "blob":
+        path = "/".join(parts[1:3] + parts[4:])
+        return f"https://raw.githubusercontent.com/{path}"
+    # {gitlab host}/{group}/{project}/-/blob/{ref}/{path}
+    if "/-/blob/" in parsed.path:
+        return parsed._replace(path=parsed.path.replace("/-/blob/", "/-/raw/", 1)).geturl()
+    return url
+
+
+def trusted_hosts() -> set[str]:
+    """Hosts styles are trusted from: GitHub, GitLab and $CLOUDBENCH_STYLE_HOSTS."""
+    configured = os.environ.get(TRUSTED_HOSTS_ENV, "").split(",")
+    return set(TRUSTED_HOSTS) | {h.strip().lower() for h in configured if h.strip()}
+
+
+def _check_host(url: str) -> None:
+    """Refuse a URL whose untrusted host has a private or loopback address.
+
+    Raises:
+        ValueError: If the host can't be resolved or isn't public
+    """
+    host = (urlparse(url).hostname or "").lower()
+    if host in trusted_hosts():
+        return
+    try:
+        addresses = {info[4][0] for info in socket.getaddrinfo(host, None)}
+    except (socket.gaierror, UnicodeError) as e:
+        raise ValueError(f"{url}: can't resolve {host or 'the host'}") from e
+    for address in addresses:
+        # Drop the zone of scoped IPv6 addresses, e.g. fe80::1%eth0
+        if not ipaddress.ip_address(address.split("%", 1)[0]).is_global:
+            raise ValueError(f"{url}: {host} is a private address")
+
+
+def _download(url: str, headers: dict[str, str]) -> tuple[bytes, str]:
+    """Download a file, refusing ones larger than MAX_URL_BYTES.
+
+    Redirects are followed one by one so each host is checked, and the
+    Authorization header isn't sent on to another host.
+
+    Returns:
+        Tuple of (content, content type)
+    """
+    for _ in range(MAX_REDIRECTS + 1):
+        _check_host(url)
+        with httpx.stream("GET", url, headers=headers, timeout=URL_TIMEOUT) as response:
+            if response.is_redirect:
+                location = str(response.url.join(response.headers["location"]))
+                if urlparse(location).hostname != urlparse(url).hostname:
+                    headers = {k: v for k, v in headers.items() if k != "Authorization"}
+                url = location
+                continue
+            if response.status_code >= 400:
+                raise ValueError(f"{url}: HTTP {response.status_code}")
+            data = bytearray()
+            for chunk in response.iter_bytes():
+                data.extend(chunk)
+                if len(data) > MAX_URL_BYTES:
+                    raise ValueError(f"{url}: larger than {MAX_URL_BYTES // (1024 * 1024)} MB")
+            return bytes(data), response.headers.get("content-type", "")
+    raise ValueError(f"{url}: more than {MAX_REDIRECTS} redirects")
+
+
+def fetch_style(url: str, authorization: str | None = None) -> StyleFile:
+    """Download a style from an http(s) URL.
+
+    The style is named after the file in the URL and its format comes from
+    the file extension, or from the content type or content when the URL
+    has none.
+
+    Args:
+        url: URL of an SLD or CSS file; GitHub and GitLab file pages work too
+        authorization: Authorization header for private repositories;
+            when omitted, $CLOUDBENCH_STYLE_AUTHORIZATION for trusted hosts
+
+    Raises:
+        ValueError: If the URL isn't http(s) or the style can't be downloaded
+    """
+    url = url.strip()
+    if not is_style_url(url):
+        raise ValueError(f"Not an http(s) URL: {url}")
+    headers = {"User-Agent": "Kartoza-CloudBench"}
+    download_url = raw_url(url)
+    if not authorization and (urlparse(download_url).hostname or "") in trusted_hosts():
+        authorization = os.environ.get(AUTHORIZATION_ENV)
+    if authorization:
+        headers["Authorization"] = authorization
+    try:
+        data, content_type = _download(download_url, headers)
+    except httpx.HTTPError as e:
+        raise ValueError(f"{url}: {e}") from e
+
+    filename = unquote(urlparse(url).path.rstrip("/").rsplit("/", 1)[-1]) or "style"
+    if not is_style_file(filename):
+        if "zip" in content_type or data.startswith(b"PK\x03\x04"):
+            filename += PACKAGE_SUFFIX
+        else:
+            looks_like_xml = "xml" in content_type or data.lstrip().startswith(b"<")
+            filename = f"{filename}.{'sld' if looks_like_xml else 'css'}"
+    style_file = StyleFile.from_bytes(filename, data)
+    style_file.path = url  # Results report where the style came from
+    return style_file
+
+
+def read_style_file(path: str | Path) -> StyleFile:
+    """Read a single local style file or style package.
+
+    Raises:
+        ValueError: If the file can't be read or isn't a style
+    """
+    path = Path(path)
+    try:
+        data = path.read_bytes()
+    except OSError as e:
+        raise ValueError(f"{path}: {e.strerror or e}") from e
+    return StyleFile.from_bytes(path.name, data)
+
+
+def scan_style_directory(root: str | Path, recursive: bool = False) -> list[StyleFile]:
+    """Read the style files of a local folder.
+
+    Args:
+        root: Folder to scan
+        recursive: Also scan subfolders
+
+    Returns:
+        Style files, sorted by path
+
+    Raises:
+        ValueError: If the folder doesn't exist or a file can't be read
+    """
+    root = Path(root)
+    if not root.is_dir():
+        raise ValueError(f"Not a folder: {root}")
+    files = []
+    for dirpath, dirnames, filenames in os.walk(root):
+        # Skip hidden folders such as .git
+        dirnames[:] = [d for d in dirnames if not d.startswith(".")] if recursive else []
+        for filename in filenames:
+            if filename.startswith(".") or not is_style_file(filename):
+                continue
+            path = Path(dirpath) / filename
+            try:
+                data = path.read_bytes()
+            except OSError as e:
+                raise ValueError(f"{path}: {e.strerror or e}") from e
+            files.append(StyleFile.from_bytes(path.relative_to(root).as_posix(), data))
+    return sorted(files, key=lambda f: f.path)
+
+
+def import_styles(
+    client: "GeoServerClient",
+    files: list[StyleFile],
+    workspace: str | None = None,
+    on_result: Callable[[StyleImportResult], None] | None = None,
+) -> list[StyleImportResult]:
+    """Create or update styles from files, skipping those that are unchanged.
+
+    Failures are recorded per file; one bad file doesn't stop the rest.
+    Tile caches of layers drawn with an updated style are truncated when
+    the connection truncates automatically.
+
+    Args:
+        client: GeoServer client
+        files: Style files
+        workspace: Workspace to import into; the global styles when omitted
+        on_result: Called with each file's result as it finishes
+
+    Returns:
+        One result per file, in file order
+    """
+    existing = {style.get("name") for style in client.list_styles(workspace)}
+    seen: dict[str, str] = {}
+    duplicates: dict[str, str] = {}
+    for style_file in files:
+        if style_file.name in seen:
+            duplicates[style_file.path] = seen[style_file.name]
+        else:
+            seen[style_file.name] = style_file.path
+
+    def run(style_file: StyleFile) -> StyleImportResult:
+        if style_file.path in duplicates:
+            result = StyleImportResult(
+                style_file.path,
+                style_file.name,
+                "failed",
+                f"{duplicates[style_file.path]} is also named {style_file.name}",
+            )
+        else:
+            result = _import_one(client, style_file, workspace, style_file.name in existing)
+        if on_result:
+            on_result(result)
+        return result
+
+    with ThreadPoolExecutor(max_workers=MAX_WORKERS) as executor:
+        return list(executor.map(run, files))
+
+
+def _import_one(
+    client: "GeoServerClient", style_file: StyleFile, workspace: str | None, exists: bool
+) -> StyleImportResult:
+    path, name = style_file.path, style_file.name
+    if not name:
+        return StyleImportResult(path, name, "failed", "No style name in the file name")
+    errors = validate_style(style_file.content, style_file.format)
+    if errors:
+        return StyleImportResult(path, name, "failed", "; ".join(errors))
+
+    if style_file.package is not None:
+        warning = ""
+        if style_file.missing_graphics:
+            warning = "Graphics missing from the package: " + ", ".join(
+                style_file.missing_graphics
+            )
+        try:
+            if not exists:
+                validate_name("style", name, workspace)
+            client.upload_style_package(name, style_file.package, workspace, exists=exists)
+        except GeoServerError as e:
+            logger.warning("Importing style %s from %s failed: %s", name, path, e.message)
+            return StyleImportResult(path, name, "failed", e.message)
+        if not exists:
+            return StyleImportResult(path, name, "created", warning=warning)
+        invalidate_style_cache(client.connection.id, workspace or "", name)
+        return StyleImportResult(path, name, "updated", warning=warning)
+
+    try:
+        if not exists:
+            validate_name("style", name, workspace)
+            client.create_style(name, style_file.content, style_file.format, workspace)
+            return StyleImportResult(path, name, "created")
+
+        current, current_format = client.get_style_content(name, workspace)
+        if current_format == style_file.format and content_hash(current) == content_hash(
+            style_file.content
+        ):
+            return StyleImportResult(path, name, "skipped")
+        client.update_style_content(name, style_file.content, style_file.format, workspace)
+    except GeoServerError as e:
+        logger.warning("Importing style %s from %s failed: %s", name, path, e.message)
+        return StyleImportResult(path, name, "failed", e.message)
+
+    invalidate_style_cache(client.connection.id, workspace or "", name)
+    return StyleImportResult(path, name, "updated")
+
+
+def count_results(results: list[StyleImportResult]) -> dict[str, int]:
+    """Count results by status, e.g. {"created": 2, "updated": 1, ...}."""
+    return {status: sum(1 for r in results if r.status == status) for status in STATUSES}
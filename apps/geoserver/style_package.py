@@ -0,0 +1,122 @@
+"""SLD packages: a zip of a style with the icons and fonts it uses.
+
+A style drawing points with its own icons refers to them from
+ExternalGraphic elements, usually by a path relative to the SLD. GeoServer
+takes such a style as one zip, stores the graphics next to the SLD in its
+styles directory and resolves the references against them. Before a
+package is sent its SLD is checked for references to graphics the zip
+doesn't hold, which would otherwise only show up as blank symbols on the
+map.
+"""
+
+import io
+import posixpath
+import xml.etree.ElementTree as ET
+import zipfile
+from dataclasses import dataclass, field
+from urllib.parse import unquote, urlparse
+
+PACKAGE_SUFFIX = ".zip"
+PACKAGE_CONTENT_TYPE = "application/zip"
+
+_XLINK_HREF = "{http://www.w3.org/1999/xlink}href"
+
+
+@dataclass
+class StylePackage:
+    """The SLD of a zipped style and the files packed with it."""
+
+    sld_path: str  # Path of the SLD in the zip
+    content: str
+    files: list[str] = field(default_factory=list)  # Every other file in the zip
+    missing: list[str] = field(default_factory=list)  # Graphics referenced but not packed
+
+
+def external_graphics(content: str) -> list[str]:
+    """List the graphics an SLD refers to from its ExternalGraphic elements.
+
+    Works for SLD 1.0 and 1.1 alike. References computed from feature
+    attributes (containing "${") can't be resolved up front and are left out.
+
+    Raises:
+        ValueError: If the content isn't XML
+    """
+    try:
+        root = ET.fromstring(content)
+    except ET.ParseError as e:
+        raise ValueError(f"Invalid XML: {e}") from e
+    hrefs: list[str] = []
+    for element in root.iter():
+        if not isinstance(element.tag, str) or not element.tag.endswith("ExternalGraphic"):
+            continue
+        for child in element:
+            href = (child.get(_XLINK_HREF) or "").strip()
+            if href and "${" not in href and href not in hrefs:
+                hrefs.append(href)
+    return hrefs
+
+
+def graphic_path(href: str, sld_dir: str) -> str | None:
+    """Get the path a graphic reference points to; None for remote or absolute ones.
+
+    Args:
+        href: ExternalGraphic reference, e.g. icons/hospital.png or file:school.svg
+        sld_dir: Folder of the SLD, which relative references resolve from
+    """
+    parsed = urlparse(href)
+    if parsed.scheme == "file":
+        href = unquote(parsed.path)
+    elif parsed.scheme:
+        # http(s) and data URLs aren't packed
+        return None
+    if href.startswith("/"):
+        return None
+    return posixpath.normpath(posixpath.join(sld_dir, href))
+
+
+def missing_graphics(content: str, sld_path: str, files: list[str]) -> list[str]:
+    """List the relative graphic references of an SLD that aren't in its package.
+
+    Args:
+        content: The SLD
+        sld_path: Path of the SLD in the zip; references resolve from its folder
+        files: Paths of the files in the zip
+    """
+    packed = {posixpath.normpath(f) for f in files}
+    sld_dir = posixpath.dirname(sld_path)
+    missing = []
+    for href in external_graphics(content):
+        path = graphic_path(href, sld_dir)
+        if path is not None and path not in packed:
+            missing.append(href)
+    return missing
+
+
+def read_style_package(data: bytes) -> StylePackage:
+    """Read the SLD out of a style package and check its graphics are packed.
+
+    Raises:
+        ValueError: If the data isn't a zip, doesn't hold exactly one .sld
+            file, or the SLD isn't UTF-8 XML
+    """
+    try:
+        archive = zipfile.ZipFile(io.BytesIO(data))
+    except zipfile.BadZipFile as e:
+        raise ValueError(f"Not a zip file: {e}") from e
+    with archive:
+        names = [
+            info.filename
+            for info in archive.infolist()
+            if not info.is_dir() and not info.filename.startswith("__MACOSX/")
+        ]
+        slds = [name for name in names if name.lower().endswith(".sld")]
+        if len(slds) != 1:
+            found = ", ".join(slds) if slds else "none"
+            raise ValueError(f"A style package needs exactly one .sld file (found {found})")
+        try:
+            content = archive.read(slds[0]).decode("utf-8-sig")
+        except UnicodeDecodeError as e:
+            raise ValueError(f"{slds[0]}: not UTF-8 text") from e
+
+    files = [name for name in names if name != slds[0]]
+    return StylePackage(slds[0], content, files, missing_graphics(content, slds[0], files))
@@ -0,0 +1,323 @@
+"""Layer previews drawn in a terminal.
+
+The TUI can't show the web UI's OpenLayers map, but a WMS GetMap image
+drawn with half-block characters in truecolor is good enough to check a
+style or spot data in the wrong place. A viewport in Web Mercator keeps
+zoom and pan simple, basemap tiles from the same provider as the web
+preview give the layer context, and terminals that understand sixel
+graphics can show the same view at full resolution.
+"""
+
+import math
+import os
+from dataclasses import dataclass
+from typing import Any, Callable
+
+import httpx
+
+from apps.core.exceptions import GeoServerError
+from apps.core.raster import Image, decode_png
+
+from .client import GeoServerClient
+
+# Same basemap as the web UI's map preview
+BASEMAP_URL = "https://basemaps.cartocdn.com/light_all/{z}/{x}/{y}.png"
+TILE_SIZE = 256
+MAX_TILE_ZOOM = 19
+
+# Half the circumference of the Web Mercator world, in meters
+ORIGIN_SHIFT = math.pi * 6378137.0
+MAX_LATITUDE = 85.0511287798
+
+# Overrides graphics detection: "sixel", "halfblock" or "ascii"
+GRAPHICS_ENV = "CLOUDBENCH_GRAPHICS"
+GRAPHICS_MODES = ("sixel", "halfblock", "ascii")
+
+# Terminals known to draw sixel graphics
+SIXEL_TERMINALS = {"WezTerm", "mlterm", "foot", "contour", "iTerm.app", "konsole"}
+
+# Light to dark, for drawing without colors
+ASCII_RAMP = " .:-=+*#%@"
+
+# Background drawn under transparent pixels when no basemap is shown
+BACKGROUND = (24, 24, 24, 255)
+
+TileFetcher = Callable[[int, int, int], Image | None]
+
+
+def lonlat_to_mercator(lon: float, lat: float) -> tuple[float, float]:
+    """Project a lon/lat position to Web Mercator meters."""
+    lat = max(-MAX_LATITUDE, min(MAX_LATITUDE, lat))
+    x = lon * ORIGIN_SHIFT / 180.0
+    y = math.log(math.tan((90.0 + lat) * math.pi / 360.0)) * 6378137.0
+    return x, y
+
+
+@dataclass
+class MapViewport:
+    """What part of the map is shown: a center and a scale, in EPSG:3857."""
+
+    center_x: float = 0.0
+    center_y: float = 0.0
+    resolution: float = 2 * ORIGIN_SHIFT / 256  # Meters per pixel
+
+    MIN_RESOLUTION = 0.05
+    MAX_RESOLUTION = 2 * ORIGIN_SHIFT / 64
+
+    @classmethod
+    def from_latlon_bbox(
+        cls, bbox: dict[str, Any] | None, width: int, height: int
+    ) -> "MapViewport":
+        """Frame lat/lon bounds in an image of the given size, with a small margin.
+
+        Args:
+            bbox: Bounds with minx, miny, maxx and maxy; the world when omitted
+            width: Image width in pixels
+            height: Image height in pixels
+        """
+        if not bbox:
+            return cls()
+        minx, miny = lonlat_to_mercator(float(bbox["minx"]), float(bbox["miny"]))
+        maxx, maxy = lonlat_to_mercator(float(bbox["maxx"]), float(bbox["maxy"]))
+        resolution = max((maxx - minx) / max(width, 1), (maxy - miny) / max(height, 1)) * 1.1
+        viewport = cls((minx + maxx) / 2, (miny + maxy) / 2)
+        viewport.resolution = min(cls.MAX_RESOLUTION, max(cls.MIN_RESOLUTION, resolution))
+        return viewport
+
+    def zoom(self, factor: float) -> None:
+        """Zoom in for factors above 1 and out for factors below 1."""
+        resolution = self.resolution / factor
+        self.resolution = min(self.MAX_RESOLUTION, max(self.MIN_RESOLUTION, resolution))
+
+    def pan(self, dx: float, dy: float, width: int, height: int) -> None:
+        """Move the view by fractions of its size; positive dy moves north."""
+        self.center_x += dx * width * self.resolution
+        self.center_y += dy * height * self.resolution
+        limit = ORIGIN_SHIFT
+        self.center_x = max(-limit, min(limit, self.center_x))
+        self.center_y = max(-limit, min(limit, self.center_y))
+
+    def bbox(self, width: int, height: int) -> tuple[float, float, float, float]:
+        """Bounds of an image of the given size showing this view."""
+        half_w = width * self.resolution / 2
+        half_h = height * self.resolution / 2
+        return (
+            self.center_x - half_w,
+            self.center_y - half_h,
+            self.center_x + half_w,
+            self.center_y + half_h,
+        )
+
+    def tile_zoom(self) -> int:
+        """Basemap zoom level closest to this view's scale."""
+        zoom = math.log2(2 * ORIGIN_SHIFT / (TILE_SIZE * self.resolution))
+        return max(0, min(MAX_TILE_ZOOM, round(zoom)))
+
+
+class BasemapTiles:
+    """Fetches and caches basemap tiles."""
+
+    def __init__(self, url: str = BASEMAP_URL, timeout: float = 10.0, max_tiles: int = 256):
+        """Initialize the tile source.
+
+        Args:
+            url: XYZ tile URL template with {z}, {x} and {y}
+            timeout: Request timeout in seconds
+            max_tiles: Tiles kept in memory before the oldest are dropped
+        """
+        self.url = url
+        self.timeout = timeout
+        self.max_tiles = max_tiles
+        self._cache: dict[tuple[int, int, int], Image | None] = {}
+
+    def __call__(self, z: int, x: int, y: int) -> Image | None:
+        """Get a tile, or None when it can't be fetched."""
+        key = (z, x, y)
+        if key not in self._cache:
+            if len(self._cache) >= self.max_tiles:
+                self._cache.pop(next(iter(self._cache)))
+            self._cache[key] = self._fetch(z, x, y)
+        return self._cache[key]
+
+    def _fetch(self, z: int, x: int, y: int) -> Image | None:
+        url = self.url.format(z=z, x=x, y=y)
+        response = httpx.get(
+            url, timeout=self.timeout, headers={"User-Agent": "Kartoza-CloudBench"}
+        )
+        if response.status_code != 200:
+            return None
+        try:
+            return decode_png(response.content)
+        except ValueError:
+            return None
+
+
+def render_basemap(viewport: MapViewport, width: int, height: int, fetch: TileFetcher) -> Image:
+    """Draw the basemap for a view by sampling the tiles under each pixel."""
+    zoom = viewport.tile_zoom()
+    tiles_per_side = 2**zoom
+    # Basemap pixels per meter at the tile zoom level
+    scale = tiles_per_side * TILE_SIZE / (2 * ORIGIN_SHIFT)
+    minx, _, _, maxy = viewport.bbox(width, height)
+
+    image = Image.blank(width, height, BACKGROUND)
+    for py in range(height):
+        world_y = (ORIGIN_SHIFT - (maxy - (py + 0.5) * viewport.resolution)) * scale
+        tile_y, pixel_y = divmod(math.floor(world_y), TILE_SIZE)
+        if not 0 <= tile_y < tiles_per_side:
+            continue
+        for px in range(width):
+            world_x = (minx + (px + 0.5) * viewport.resolution + ORIGIN_SHIFT) * scale
+            tile_x, pixel_x = divmod(math.floor(world_x), TILE_SIZE)
+            tile = fetch(zoom, tile_x % tiles_per_side, tile_y)
+            if tile is not None:
+                tx = pixel_x * tile.width // TILE_SIZE
+                ty = pixel_y * tile.height // TILE_SIZE
+                image.set(px, py, tile.get(tx, ty))
+    return image
+
+
+@dataclass
+class MapPreview:
+    """A rendered view of a layer."""
+
+    image: Image
+    basemap_error: str | None = None
+
+
+def render_preview(
+    client: GeoServerClient,
+    workspace: str,
+    layer: str,
+    viewport: MapViewport,
+    width: int,
+    height: int,
+    basemap: TileFetcher | None = None,
+    cql_filter: str | None = None,
+) -> MapPreview:
+    """Render a layer for a view, over the basemap when one is given.
+
+    A basemap that can't be fetched is reported rather than raised, so the
+    layer still shows when the machine has no internet access. A CQL
+    filter limits the layer to the matching features.
+
+    Raises:
+        GeoServerError: If GeoServer can't render the layer
+    """
+    data = client.get_map(
+        workspace, layer, viewport.bbox(width, height), width, height, cql_filter=cql_filter
+    )
+    try:
+        image = decode_png(data)
+    except ValueError as e:
+        raise GeoServerError(f"Cannot decode map image: {e}")
+    if (image.width, image.height) != (width, height):
+        image = image.resized(width, height)
+
+    base = Image.blank(width, height, BACKGROUND)
+    basemap_error = None
+    if basemap is not None:
+        try:
+            base = render_basemap(viewport, width, height, basemap)
+        except httpx.HTTPError as e:
+            basemap_error = str(e)
+    return MapPreview(image.composite_over(base), basemap_error)
+
+
+def render_halfblock(image: Image) -> str:
+    """Draw an image with upper half blocks, two pixels per character cell.
+
+    The top pixel is the foreground color and the bottom one the
+    background, both as 24-bit ANSI colors.
+    """
+    lines = []
+    for y in range(0, image.height, 2):
+        cells = []
+        for x in range(image.width):
+            top = image.get(x, y)[:3]
+            bottom = image.get(x, y + 1)[:3] if y + 1 < image.height else BACKGROUND[:3]
+            cells.append("\x1b[38;2;{};{};{}m\x1b[48;2;{};{};{}m▀".format(*top, *bottom))
+        lines.append("".join(cells) + "\x1b[0m")
+    return "\n".join(lines)
+
+
+def render_ascii(image: Image) -> str:
+    """Draw an image with characters of increasing density, two pixel rows per line."""
+    lines = []
+    for y in range(0, image.height, 2):
+        chars = []
+        for x in range(image.width):
+            pixels = [image.get(x, y)]
+            if y + 1 < image.height:
+                pixels.append(image.get(x, y + 1))
+            luminance = sum(0.2126 * r + 0.7152 * g + 0.0722 * b for r, g, b, _ in pixels)
+            darkness = 1 - luminance / (255 * len(pixels))
+            chars.append(ASCII_RAMP[min(len(ASCII_RAMP) - 1, int(darkness * len(ASCII_RAMP)))])
+        lines.append("".join(chars))
+    return "\n".join(lines)
+
+
+def encode_sixel(image: Image) -> str:
+    """Encode an image as sixel graphics, quantized to a 6x6x6 color cube."""
+
+    def color_index(r: int, g: int, b: int) -> int:
+        return (r * 5 // 255) * 36 + (g * 5 // 255) * 6 + (b * 5 // 255)
+
+    indexes = [
+        color_index(*image.get(x, y)[:3]) for y in range(image.height) for x in range(image.width)
+    ]
+    out = [f'\x1bPq"1;1;{image.width};{image.height}']
+    for index in sorted(set(indexes)):
+        r, g, b = index // 36, index // 6 % 6, index % 6
+        out.append(f"#{index};2;{r * 20};{g * 20};{b * 20}")
+
+    for band in range(0, image.height, 6):
+        rows = range(band, min(band + 6, image.height))
+        band_colors = sorted(
+            {indexes[y * image.width + x] for y in rows for x in range(image.width)}
+        )
+        for n, index in enumerate(band_colors):
+            out.append(f"#{index}")
+            run_char, run_length = "", 0
+            for x in range(image.width):
+                bits = sum(
+                    1 << (y - band) for y in rows if indexes[y * image.width + x] == index
+                )
+                char = chr(63 + bits)
+                if char == run_char:
+                    run_length += 1
+                    continue
+                if run_length:
+                    out.append(_sixel_run(run_char, run_length))
+                run_char, run_length = char, 1
+            out.append(_sixel_run(run_char, run_length))
+            if n < len(band_colors) - 1:
+                out.append("$")
+        out.append("-")
+    out.append("\x1b\\")
+    return "".join(out)
+
+
+def _sixel_run(char: str, length: int) -> str:
+    return f"!{length}{char}" if length > 3 else char * length
+
+
+def detect_graphics(environ: dict[str, str] | None = None) -> str:
+    """Pick the best way to draw images in the current terminal.
+
+    Returns:
+        "sixel", "halfblock" or "ascii"
+    """
+    environ = os.environ if environ is None else environ
+    override = environ.get(GRAPHICS_ENV, "").lower()
+    if override in GRAPHICS_MODES:
+        return override
+
+    term = environ.get("TERM", "")
+    if term == "dumb" or "NO_COLOR" in environ:
+        return "ascii"
+    if "sixel" in term or term in ("foot", "mlterm", "contour"):
+        return "sixel"
+    if environ.get("TERM_PROGRAM") in SIXEL_TERMINALS or "KONSOLE_VERSION" in environ:
+        return "sixel"
+    return "halfblock"
@@ -0,0 +1,10 @@
+"""A fake GeoServer for testing code that uses the GeoServer and GWC clients.
+
+The clients' requests are answered in-process from responses recorded
+from a real GeoServer, so client methods, and projects embedding them,
+can be tested end to end without a running server.
+"""
+
+from .fake import DEFAULT_URL, FakeGeoServer, FakeResponse
+
+__all__ = ["DEFAULT_URL", "FakeGeoServer", "FakeResponse"]
@@ -0,0 +1,167 @@
+"""An in-process fake GeoServer answering from recorded REST responses."""
+
+import json
+import uuid
+from dataclasses import dataclass
+from pathlib import Path
+from types import TracebackType
+from typing import Any
+
+import httpx
+
+from apps.core.config import Connection
+from apps.core.managers import client_manager
+
+# Responses recorded from a GeoServer 2.24 with the sample data directory,
+# keyed by path below the GeoServer URL
+FIXTURES_FILE = Path(__file__).with_name("fixtures.json")
+
+DEFAULT_URL = "http://geoserver.test/geoserver"
+
+# Content types of recorded bodies that aren't JSON, by file extension
+_TEXT_TYPES = {
+    ".sld": "application/vnd.ogc.sld+xml",
+    ".css": "application/vnd.geoserver.geocss+css",
+    ".xml": "application/xml",
+}
+
+
+@dataclass
+class FakeResponse:
+    """A response the fake server gives to a method and path."""
+
+    status_code: int = 200
+    body: Any = None  # JSON-serializable data, or text
+    content_type: str | None = None
+
+    def to_httpx(self, request: httpx.Request) -> httpx.Response:
+        """Build the httpx response for a request."""
+        if isinstance(self.body, (str, bytes)):
+            suffix = Path(request.url.path).suffix
+            content_type = self.content_type or _TEXT_TYPES.get(suffix, "text/plain")
+            return httpx.Response(
+                self.status_code,
+                content=self.body,
+                headers={"Content-Type": content_type},
+                request=request,
+            )
+        if self.body is None:
+            return httpx.Response(self.status_code, request=request)
+        return httpx.Response(self.status_code, json=self.body, request=request)
+
+
+class FakeGeoServer:
+    """A GeoServer REST API and GWC REST API served without a network.
+
+    GETs are answered from the recorded fixtures, including GeoServer's
+    quirk of listing an empty collection as an empty string, e.g.
+    {"dataStores": ""}. Changes succeed without altering the fixtures;
+    every request is kept in `requests` so tests can check what was sent.
+    Responses for any method and path can be replaced with `add()`.
+
+    Use it as a context manager: clients created inside it for its
+    connection talk to the fake server.
+
+        with FakeGeoServer() as server:
+            client = GeoServerClient(server.connection())
+            client.list_workspaces()
+    """
+
+    def __init__(self, url: str = DEFAULT_URL, fixtures: bool = True):
+        """Initialize the fake server.
+
+        Args:
+            url: GeoServer URL the fake server answers for
+            fixtures: Whether to answer GETs from the recorded fixtures
+        """
+        self.url = url.rstrip("/")
+        self.requests: list[httpx.Request] = []
+        self.transport = httpx.MockTransport(self.handle)
+        self._base_path = httpx.URL(self.url).path.rstrip("/")
+        self._responses: dict[tuple[str, str], FakeResponse] = {}
+        if fixtures:
+            for path, body in json.loads(FIXTURES_FILE.read_text()).items():
+                self._responses[("GET", path)] = FakeResponse(body=body)
+        self._connection_ids: list[str] = []
+
+    def add(
+        self,
+        method: str,
+        path: str,
+        body: Any = None,
+        status_code: int = 200,
+        content_type: str | None = None,
+    ) -> None:
+        """Answer a method and path with a response, replacing any fixture.
+
+        Args:
+            method: HTTP method
+            path: Path below the GeoServer URL, e.g. /rest/workspaces.json
+            body: JSON-serializable data, text, or None for an empty body
+            status_code: HTTP status
+            content_type: Content type of a text body; guessed from the
+                path's extension when omitted
+        """
+        self._responses[(method.upper(), path)] = FakeResponse(status_code, body, content_type)
+
+    def connection(self, **fields: Any) -> Connection:
+        """Get a connection to the fake server.
+
+        Args:
+            **fields: Connection fields to set, e.g. read_only=True
+        """
+        conn_id = fields.pop("id", f"fake_{uuid.uuid4().hex[:8]}")
+        self._connection_ids.append(conn_id)
+        return Connection(
+            **{
+                "id": conn_id,
+                "name": "Fake GeoServer",
+                "url": self.url,
+                "username": "admin",
+                "password": "geoserver",
+                **fields,
+            }
+        )
+
+    def requested(self, method: str, path: str) -> list[httpx.Request]:
+        """Get the requests made with a method to a path below the GeoServer URL."""
+        return [
+            request
+            for request in self.requests
+            if request.method == method.upper() and self._path(request) == path
+        ]
+
+    def handle(self, request: httpx.Request) -> httpx.Response:
+        """Answer a request, as the transport of the fake server's clients."""
+        self.requests.append(request)
+        path = self._path(request)
+        response = self._responses.get((request.method, path))
+        if response is not None:
+            return response.to_httpx(request)
+        if request.method == "GET":
+            return FakeResponse(404, f"No such resource: {path}").to_httpx(request)
+        return FakeResponse(201 if request.method == "POST" else 200).to_httpx(request)
+
+    def _path(self, request: httpx.Request) -> str:
+        """Path of a request below the GeoServer URL."""
+        path = request.url.path
+        if self._base_path and path.startswith(self._base_path):
+            path = path[len(self._base_path) :]
+        return path
+
+    def __enter__(self) -> "FakeGeoServer":
+        """Route new clients for the fake server's host to it."""
+        client_manager.override_transport(self.url, self.transport)
+        return self
+
+    def __exit__(
+        self,
+        exc_type: type[BaseException] | None,
+        exc: BaseException | None,
+        traceback: TracebackType | None,
+    ) -> None:
+        """Stop routing to the fake server and drop its cached clients."""
+        client_manager.override_transport(self.url, None)
+        for conn_id in self._connection_ids:
+            client_manager.remove_client(conn_id)
+            client_manager.remove_client(f"gwc_{conn_id}")
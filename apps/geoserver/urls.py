@@ -6,6 +6,11 @@ from . import views
 
 urlpatterns = [
     # Workspaces
+    path(
+        "workspace-templates",
+        views.WorkspaceTemplateListView.as_view(),
+        name="workspace-template-list",
+    ),
     path(
         "workspaces/<str:conn_id>",
         views.WorkspaceListView.as_view(),
@@ -16,6 +21,11 @@ urlpatterns = [
         views.WorkspaceDetailView.as_view(),
         name="workspace-detail",
     ),
+    path(
+        "workspaces/<str:conn_id>/<str:workspace>/impact",
+        views.WorkspaceImpactView.as_view(),
+        name="workspace-impact",
+    ),
     # Data Stores
     path(
         "datastores/<str:conn_id>/<str:workspace>",
@@ -32,6 +42,11 @@ urlpatterns = [
         views.DataStoreAvailableView.as_view(),
         name="datastore-available",
     ),
+    path(
+        "datastores/<str:conn_id>/<str:workspace>/<str:store>/publish",
+        views.DataStorePublishView.as_view(),
+        name="datastore-publish",
+    ),
     # Coverage Stores
     path(
         "coveragestores/<str:conn_id>/<str:workspace>",
@@ -81,6 +96,57 @@ urlpatterns = [
         views.LayerCountView.as_view(),
         name="layer-count",
     ),
+    path(
+        "layers/<str:conn_id>/<str:workspace>/<str:layer>/cache-usage",
+        views.LayerCacheUsageView.as_view(),
+        name="layer-cache-usage",
+    ),
+    path(
+        "layers/<str:conn_id>/<str:workspace>/<str:layer>/requests",
+        views.LayerRequestStatsView.as_view(),
+        name="layer-requests",
+    ),
+    path(
+        "layers/<str:conn_id>/<str:workspace>/<str:layer>/benchmark",
+        views.LayerBenchmarkView.as_view(),
+        name="layer-benchmark",
+    ),
+    path(
+        "layers/<str:conn_id>/<str:workspace>/<str:layer>/verify",
+        views.LayerEndpointsView.as_view(),
+        name="layer-verify",
+    ),
+    path(
+        "layers/<str:conn_id>/<str:workspace>/<str:layer>/attributes",
+        views.LayerAttributesView.as_view(),
+        name="layer-attributes",
+    ),
+    path(
+        "layers/<str:conn_id>/<str:workspace>/<str:layer>/attributes/export",
+        views.LayerAttributesExportView.as_view(),
+        name="layer-attributes-export",
+    ),
+    path(
+        "layers/<str:conn_id>/<str:workspace>/<str:layer>/schema",
+        views.LayerSchemaView.as_view(),
+        name="layer-schema",
+    ),
+    # CQL filter builder
+    path(
+        "layers/<str:conn_id>/<str:workspace>/<str:layer>/filter/attributes",
+        views.LayerFilterAttributesView.as_view(),
+        name="layer-filter-attributes",
+    ),
+    path(
+        "layers/<str:conn_id>/<str:workspace>/<str:layer>/filter/values",
+        views.LayerFilterValuesView.as_view(),
+        name="layer-filter-values",
+    ),
+    path(
+        "layers/<str:conn_id>/<str:workspace>/<str:layer>/filter/validate",
+        views.LayerFilterValidateView.as_view(),
+        name="layer-filter-validate",
+    ),
     # Layer Metadata
     path(
         "layermetadata/<str:conn_id>/<str:workspace>/<str:layer>",
@@ -104,6 +170,29 @@ urlpatterns = [
         views.StyleDetailView.as_view(),
         name="style-detail",
     ),
+    # Bulk import of style files into a workspace or the global styles
+    path(
+        "styleimport/<str:conn_id>",
+        views.StyleImportView.as_view(),
+        name="style-import",
+    ),
+    # Unified diffs of styles against local files or another connection
+    path(
+        "stylediff/<str:conn_id>",
+        views.StyleDiffView.as_view(),
+        name="style-diff",
+    ),
+    # Icons and other graphics in the styles folders
+    path(
+        "styleassets/<str:conn_id>",
+        views.StyleAssetListView.as_view(),
+        name="style-assets",
+    ),
+    path(
+        "styleassets/<str:conn_id>/move",
+        views.StyleAssetMoveView.as_view(),
+        name="style-asset-move",
+    ),
     # Layer Groups
     path(
         "layergroups/<str:conn_id>/<str:workspace>",
@@ -115,6 +204,11 @@ urlpatterns = [
         views.LayerGroupDetailView.as_view(),
         name="layergroup-detail",
     ),
+    path(
+        "layergrouppreview/<str:conn_id>/<str:workspace>",
+        views.LayerGroupPreviewView.as_view(),
+        name="layergroup-preview",
+    ),
     # File Uploads
     path(
         "upload/shapefile/<str:conn_id>/<str:workspace>",
@@ -131,4 +225,128 @@ urlpatterns = [
         views.UploadGeoPackageView.as_view(),
         name="upload-geopackage",
     ),
+    # Catalog integrity checks
+    path(
+        "lint/<str:conn_id>",
+        views.CatalogLintView.as_view(),
+        name="catalog-lint",
+    ),
+    # Naming conventions for new and renamed resources
+    path(
+        "naming-conventions",
+        views.NamingConventionsView.as_view(),
+        name="naming-conventions",
+    ),
+    # Default styles of newly published layers
+    path(
+        "default-styles",
+        views.DefaultStylesView.as_view(),
+        name="default-styles",
+    ),
+    # WMS watermark drawn on every map
+    path(
+        "wmswatermark/<str:conn_id>",
+        views.WmsWatermarkView.as_view(),
+        name="wms-watermark",
+    ),
+    # Batch actions on marked layers
+    path(
+        "batch/<str:conn_id>",
+        views.BatchActionView.as_view(),
+        name="batch-action",
+    ),
+    # Copyable URLs and names of a tree node
+    path(
+        "links/<str:conn_id>",
+        views.CopyTargetsView.as_view(),
+        name="copy-targets",
+    ),
+    # Recently added and modified layers and styles
+    path(
+        "recent/<str:conn_id>",
+        views.RecentItemsView.as_view(),
+        name="recent-items",
+    ),
+    # Resources the client created or adopted, which sync may update or prune
+    path(
+        "state/<str:conn_id>",
+        views.ManagedStateView.as_view(),
+        name="managed-state",
+    ),
+    # Dated snapshot of several resources' configurations and data
+    path(
+        "snapshot/<str:conn_id>",
+        views.SnapshotView.as_view(),
+        name="snapshot",
+    ),
+    # Scheduled catalog snapshots, their stored snapshots and restores
+    path(
+        "snapshots/schedules",
+        views.SnapshotScheduleListView.as_view(),
+        name="snapshot-schedule-list",
+    ),
+    path(
+        "snapshots/schedules/<str:schedule_id>",
+        views.SnapshotScheduleDetailView.as_view(),
+        name="snapshot-schedule-detail",
+    ),
+    path(
+        "snapshots/schedules/<str:schedule_id>/run",
+        views.SnapshotScheduleRunView.as_view(),
+        name="snapshot-schedule-run",
+    ),
+    path(
+        "snapshots/schedules/<str:schedule_id>/snapshots",
+        views.SnapshotScheduleSnapshotsView.as_view(),
+        name="snapshot-schedule-snapshots",
+    ),
+    path(
+        "snapshots/schedules/<str:schedule_id>/restore",
+        views.SnapshotRestoreView.as_view(),
+        name="snapshot-restore",
+    ),
+    # Server-side archives through the Backup and Restore plugin
+    path(
+        "br/<str:conn_id>/backup/<int:execution_id>/archive",
+        views.BackupArchiveView.as_view(),
+        name="backup-archive",
+    ),
+    path(
+        "br/<str:conn_id>/<str:kind>",
+        views.BackupRestoreView.as_view(),
+        name="backup-restore",
+    ),
+    path(
+        "br/<str:conn_id>/<str:kind>/<int:execution_id>",
+        views.BackupRestoreExecutionView.as_view(),
+        name="backup-restore-execution",
+    ),
+    # Data security rules through the GeoFence extension
+    path(
+        "geofence/<str:conn_id>/rules",
+        views.GeofenceRuleListView.as_view(),
+        name="geofence-rules",
+    ),
+    path(
+        "geofence/<str:conn_id>/rules/<int:rule_id>",
+        views.GeofenceRuleDetailView.as_view(),
+        name="geofence-rule-detail",
+    ),
+    # Catalog reload and cache reset on every node of a cluster
+    path(
+        "cluster/<str:conn_id>/<str:action>",
+        views.ClusterActionView.as_view(),
+        name="cluster-action",
+    ),
+    # OGC requests proxied with the connection's credentials
+    path(
+        "geoserver/<str:conn_id>/ows",
+        views.OwsProxyView.as_view(),
+        name="ows-proxy",
+    ),
+    path(
+        "geoserver/<str:conn_id>/ows/<path:target>",
+        views.OwsProxyView.as_view(),
+        name="ows-proxy-service",
+    ),
 ]
@@ -3,30 +3,78 @@
 Imports all view classes for URL routing.
 """
 
+from .backup import BackupArchiveView, BackupRestoreExecutionView, BackupRestoreView
+from .batch import BatchActionView
+from .cluster import ClusterActionView
 from .coverages import CoverageDetailView, CoverageListView
 from .coveragestores import CoverageStoreDetailView, CoverageStoreListView
-from .datastores import DataStoreAvailableView, DataStoreDetailView, DataStoreListView
+from .default_styles import DefaultStylesView
+from .datastores import (
+    DataStoreAvailableView,
+    DataStoreDetailView,
+    DataStoreListView,
+    DataStorePublishView,
+)
 from .featuretypes import FeatureTypeDetailView, FeatureTypeListView
-from .layergroups import LayerGroupDetailView, LayerGroupListView
+from .filters import LayerFilterAttributesView, LayerFilterValidateView, LayerFilterValuesView
+from .geofence import GeofenceRuleDetailView, GeofenceRuleListView
+from .layergroups import LayerGroupDetailView, LayerGroupListView, LayerGroupPreviewView
 from .layers import (
+    LayerAttributesExportView,
+    LayerAttributesView,
+    LayerBenchmarkView,
+    LayerCacheUsageView,
     LayerCountView,
     LayerDetailView,
+    LayerEndpointsView,
     LayerListView,
     LayerMetadataView,
+    LayerRequestStatsView,
+    LayerSchemaView,
     LayerStylesView,
 )
-from .styles import StyleDetailView, StyleListView
+from .links import CopyTargetsView
+from .lint import CatalogLintView
+from .naming import NamingConventionsView
+from .ows_proxy import OwsProxyView
+from .recent import RecentItemsView
+from .services import WmsWatermarkView
+from .snapshot import (
+    SnapshotRestoreView,
+    SnapshotScheduleDetailView,
+    SnapshotScheduleListView,
+    SnapshotScheduleRunView,
+    SnapshotScheduleSnapshotsView,
+    SnapshotView,
+)
+from .state import ManagedStateView
+from .styles import (
+    StyleAssetListView,
+    StyleAssetMoveView,
+    StyleDetailView,
+    StyleDiffView,
+    StyleImportView,
+    StyleListView,
+)
 from .uploads import UploadGeoPackageView, UploadGeoTiffView, UploadShapefileView
-from .workspaces import WorkspaceDetailView, WorkspaceListView
+from .workspaces import (
+    WorkspaceDetailView,
+    WorkspaceImpactView,
+    WorkspaceListView,
+    WorkspaceTemplateListView,
+)
 
 __all__ = [
     # Workspaces
     "WorkspaceListView",
     "WorkspaceDetailView",
+    "WorkspaceImpactView",
+    "WorkspaceTemplateListView",
     # Data Stores
     "DataStoreListView",
     "DataStoreDetailView",
     "DataStoreAvailableView",
+    "DataStorePublishView",
     # Coverage Stores
     "CoverageStoreListView",
     "CoverageStoreDetailView",
@@ -40,16 +88,65 @@ __all__ = [
     "LayerListView",
     "LayerDetailView",
     "LayerCountView",
+    "LayerCacheUsageView",
+    "LayerRequestStatsView",
+    "LayerBenchmarkView",
+    "LayerEndpointsView",
+    "LayerAttributesView",
+    "LayerAttributesExportView",
+    "LayerSchemaView",
+    "LayerFilterAttributesView",
+    "LayerFilterValuesView",
+    "LayerFilterValidateView",
     "LayerMetadataView",
     "LayerStylesView",
     # Styles
     "StyleListView",
     "StyleDetailView",
+    "StyleImportView",
+    "StyleDiffView",
+    "StyleAssetListView",
+    "StyleAssetMoveView",
     # Layer Groups
     "LayerGroupListView",
     "LayerGroupDetailView",
+    "LayerGroupPreviewView",
     # Uploads
     "UploadShapefileView",
     "UploadGeoTiffView",
     "UploadGeoPackageView",
+    # Catalog integrity
+    "CatalogLintView",
+    # Naming conventions
+    "NamingConventionsView",
+    # Default styles of new layers
+    "DefaultStylesView",
+    # Service settings
+    "WmsWatermarkView",
+    # Backup and Restore plugin
+    "BackupRestoreView",
+    "BackupRestoreExecutionView",
+    "BackupArchiveView",
+    # GeoFence data security rules
+    "GeofenceRuleListView",
+    "GeofenceRuleDetailView",
+    # Batch actions
+    "BatchActionView",
+    # Copyable links
+    "CopyTargetsView",
+    # Recently added and modified
+    "RecentItemsView",
+    # Managed resources
+    "ManagedStateView",
+    # Snapshots
+    "SnapshotView",
+    "SnapshotScheduleListView",
+    "SnapshotScheduleDetailView",
+    "SnapshotScheduleRunView",
+    "SnapshotScheduleSnapshotsView",
+    "SnapshotRestoreView",
+    # Catalog reload and cache reset on every node
+    "ClusterActionView",
+    # OGC requests proxied with the connection's credentials
+    "OwsProxyView",
 ]
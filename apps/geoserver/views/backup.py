@@ -0,0 +1,132 @@
+"""Backup and Restore plugin views for GeoServer API."""
+
+import itertools
+
+from django.http import StreamingHttpResponse
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.exceptions import GeoServerError
+
+from ..backup import KIND_BACKUP, OPTIONS, default_archive_file, workspace_filter
+from ..client import get_geoserver_client
+from .base import handle_geoserver_error
+
+
+def _plugin_client(conn_id: str):
+    """Backup and Restore client of a connection.
+
+    Raises:
+        ValueError: If the connection doesn't exist
+        GeoServerError: If the plugin isn't installed
+    """
+    backup_restore = get_geoserver_client(conn_id).backup_restore
+    if not backup_restore.is_available():
+        raise GeoServerError("The Backup and Restore plugin isn't installed", status_code=501)
+    return backup_restore
+
+
+class BackupRestoreView(APIView):
+    """Start server-side backups and restores."""
+
+    def get(self, request, conn_id, kind):
+        """List the options of a job kind and suggest an archive path."""
+        if kind not in OPTIONS:
+            return Response({"error": f"Unknown job: {kind}"}, status=status.HTTP_404_NOT_FOUND)
+        workspaces = [ws for ws in request.query_params.get("workspaces", "").split(",") if ws]
+        return Response(
+            {
+                "options": [
+                    {"name": name, "description": desc} for name, desc in OPTIONS[kind].items()
+                ],
+                "archiveFile": default_archive_file(workspaces),
+            }
+        )
+
+    def post(self, request, conn_id, kind):
+        """Start a job; poll BackupRestoreExecutionView for its progress.
+
+        Request body:
+        - archiveFile: Path of the zip archive on the server
+        - workspaces: Workspaces to include; all when omitted
+        - storeFilter, layerFilter: Optional CQL filters on stores and layers
+        - options: Plugin options, e.g. {"BK_BEST_EFFORT": true}
+        """
+        if kind not in OPTIONS:
+            return Response({"error": f"Unknown job: {kind}"}, status=status.HTTP_404_NOT_FOUND)
+        workspaces = request.data.get("workspaces") or []
+        archive_file = request.data.get("archiveFile") or ""
+        if not archive_file:
+            if kind != KIND_BACKUP:
+                return Response(
+                    {"error": "An archive file is required"},
+                    status=status.HTTP_400_BAD_REQUEST,
+                )
+            archive_file = default_archive_file(workspaces)
+
+        try:
+            backup_restore = _plugin_client(conn_id)
+            execution = backup_restore.start(
+                kind,
+                archive_file,
+                ws_filter=workspace_filter(workspaces) if workspaces else "",
+                si_filter=request.data.get("storeFilter") or "",
+                li_filter=request.data.get("layerFilter") or "",
+                options=request.data.get("options") or None,
+            )
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+        return Response(execution.to_dict(), status=status.HTTP_202_ACCEPTED)
+
+
+class BackupRestoreExecutionView(APIView):
+    """Follow or cancel a backup or restore job."""
+
+    def get(self, request, conn_id, kind, execution_id):
+        """Get a job's status and progress."""
+        if kind not in OPTIONS:
+            return Response({"error": f"Unknown job: {kind}"}, status=status.HTTP_404_NOT_FOUND)
+        try:
+            execution = _plugin_client(conn_id).get_execution(kind, execution_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(execution.to_dict())
+
+    def delete(self, request, conn_id, kind, execution_id):
+        """Stop a running job."""
+        if kind not in OPTIONS:
+            return Response({"error": f"Unknown job: {kind}"}, status=status.HTTP_404_NOT_FOUND)
+        try:
+            _plugin_client(conn_id).cancel(kind, execution_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(status=status.HTTP_204_NO_CONTENT)
+
+
+class BackupArchiveView(APIView):
+    """Download the archive of a finished backup."""
+
+    def get(self, request, conn_id, execution_id):
+        """Stream the zip archive as an attachment."""
+        try:
+            chunks = _plugin_client(conn_id).iter_archive(execution_id)
+            # Start the download here, so a missing archive is still an error response
+            first = next(chunks, b"")
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+        response = StreamingHttpResponse(
+            itertools.chain([first], chunks), content_type="application/zip"
+        )
+        response["Content-Disposition"] = f'attachment; filename="backup-{execution_id}.zip"'
+        return response
@@ -5,6 +5,12 @@ from rest_framework.response import Response
 
 from apps.core.exceptions import GeoServerError
 
+from ..client import CreateResult, get_geoserver_client
+from ..datadir_diff import GLOBAL_FOLDERS, DataDirWatch
+
+# Methods whose effect on the data directory can be reported
+WRITE_METHODS = ("POST", "PUT", "PATCH", "DELETE")
+
 
 def handle_geoserver_error(error: GeoServerError) -> Response:
     """Convert GeoServerError to Response.
@@ -21,6 +27,37 @@ def handle_geoserver_error(error: GeoServerError) -> Response:
     )
 
 
+def created_response(result: CreateResult, label: str, verb: str = "created") -> Response:
+    """Respond to a create, with a 200 rather than a 201 if it already existed.
+
+    Args:
+        result: What the create call returned
+        label: What was created, e.g. "Workspace roads"
+        verb: How the message says it was created
+
+    Returns:
+        Response with a message and whether it already existed
+    """
+    if result == CreateResult.ALREADY_EXISTS:
+        return Response({"message": f"{label} already exists", "alreadyExists": True})
+    return Response(
+        {"message": f"{label} {verb}", "alreadyExists": False},
+        status=status.HTTP_201_CREATED,
+    )
+
+
+def get_if_absent_param(request) -> bool:
+    """Extract the ifAbsent flag, asking to keep an existing resource, from a request body.
+
+    Args:
+        request: The HTTP request
+
+    Returns:
+        Boolean value of ifAbsent
+    """
+    return bool(request.data.get("ifAbsent", False))
+
+
 def get_recurse_param(request) -> bool:
     """Extract recurse parameter from request.
 
@@ -31,3 +68,68 @@ def get_recurse_param(request) -> bool:
         Boolean value of recurse parameter
     """
     return request.query_params.get("recurse", "false").lower() == "true"
+
+
+def get_datadir_diff_param(request) -> bool:
+    """Extract the datadirDiff parameter, asking what a change wrote, from a request.
+
+    Args:
+        request: The HTTP request
+
+    Returns:
+        Boolean value of datadirDiff parameter
+    """
+    return request.query_params.get("datadirDiff", "false").lower() == "true"
+
+
+class DataDirDiffMixin:
+    """Report the configuration files a change wrote, when asked with ?datadirDiff=true.
+
+    The data directory folders the change can touch are read through the
+    resource API before and after it, and the differences are added to
+    the response as datadirDiff.
+    """
+
+    def datadir_folders(self, request, kwargs) -> tuple[list[str], list[str]]:
+        """Folders walked, and folders whose own files only are read, around a change."""
+        workspace = kwargs.get("workspace")
+        if workspace:
+            return [f"workspaces/{workspace}"], []
+        return list(GLOBAL_FOLDERS), [""]
+
+    def initial(self, request, *args, **kwargs):
+        """Read the files before the change."""
+        super().initial(request, *args, **kwargs)
+        self._datadir_watch = None
+        if request.method not in WRITE_METHODS or not get_datadir_diff_param(request):
+            return
+        try:
+            client = get_geoserver_client(kwargs["conn_id"])
+        except ValueError:
+            return  # The handler reports the unknown connection
+        folders, shallow = self.datadir_folders(request, kwargs)
+        self._datadir_watch = DataDirWatch(client, folders, shallow)
+        self._datadir_watch.start()
+
+    def finalize_response(self, request, response, *args, **kwargs):
+        """Add what the change wrote to a successful response."""
+        watch = getattr(self, "_datadir_watch", None)
+        if watch is not None and response.status_code < 400:
+            diff = watch.finish().to_dict()
+            if response.status_code == status.HTTP_204_NO_CONTENT:
+                response = Response({"datadirDiff": diff})
+            elif isinstance(getattr(response, "data", None), dict):
+                response.data["datadirDiff"] = diff
+        return super().finalize_response(request, response, *args, **kwargs)
+
+
+def get_enabled_param(request) -> bool:
+    """Extract the enabled parameter, asking for store enabled flags, from a request.
+
+    Args:
+        request: The HTTP request
+
+    Returns:
+        Boolean value of enabled parameter
+    """
+    return request.query_params.get("enabled", "false").lower() == "true"
@@ -0,0 +1,62 @@
+"""Batch action views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.exceptions import GeoServerError
+from apps.gwc.client import get_gwc_client
+
+from ..batch import ACTIONS, run_batch
+from ..client import get_geoserver_client
+from .base import handle_geoserver_error
+
+
+class BatchActionView(APIView):
+    """Run one action on several layers."""
+
+    def get(self, request, conn_id):
+        """List the available actions."""
+        return Response([{"name": name, "description": desc} for name, desc in ACTIONS.items()])
+
+    def post(self, request, conn_id):
+        """Run a batch action.
+
+        Request body:
+        - action: One of delete, truncate, export, group
+        - layers: Qualified layer names (workspace:layer)
+        - options: Action options; recurse for delete, name, title and
+          workspace for group
+        """
+        action = request.data.get("action", "")
+        layers = request.data.get("layers") or []
+        options = request.data.get("options") or {}
+        if action not in ACTIONS:
+            return Response(
+                {"error": f"Unknown action: {action}"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        try:
+            client = get_geoserver_client(conn_id)
+            gwc_client = get_gwc_client(conn_id) if action == "truncate" else None
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        try:
+            results = run_batch(client, gwc_client, action, layers, options)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+        return Response(
+            {
+                "action": action,
+                "results": [result.to_dict() for result in results],
+                "counts": {
+                    "ok": sum(1 for r in results if r.ok),
+                    "failed": sum(1 for r in results if not r.ok),
+                },
+            }
+        )
@@ -0,0 +1,36 @@
+"""Catalog reload and cache reset views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from ..client import get_geoserver_client
+from ..cluster import ACTIONS, run_on_nodes
+
+
+class ClusterActionView(APIView):
+    """Reload the catalog or reset caches on every node of a connection."""
+
+    def post(self, request, conn_id, action):
+        """Send the action to each node at once and report each one's outcome.
+
+        action is "reload" or "reset". A connection with cluster URLs
+        sends it to each of them, otherwise to its url. The response is a
+        200 when every node succeeded and a 502 when any failed, with the
+        per-node results either way.
+        """
+        if action not in ACTIONS:
+            return Response(
+                {"error": f"action must be one of {', '.join(ACTIONS)}"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        result = run_on_nodes(client, action)
+        return Response(
+            result.to_dict(),
+            status=status.HTTP_200_OK if result.ok else status.HTTP_502_BAD_GATEWAY,
+        )
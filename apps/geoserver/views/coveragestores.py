@@ -5,19 +5,37 @@ from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.exceptions import GeoServerError
+from apps.gwc.invalidation import clear_store_caches
 
 from ..client import get_geoserver_client
-from .base import get_recurse_param, handle_geoserver_error
-
-
-class CoverageStoreListView(APIView):
+from ..naming import validate_name
+from ..store_status import list_stores_with_status
+from .base import (
+    DataDirDiffMixin,
+    created_response,
+    get_enabled_param,
+    get_if_absent_param,
+    get_recurse_param,
+    handle_geoserver_error,
+)
+
+
+class CoverageStoreListView(DataDirDiffMixin, APIView):
     """List and create coverage stores in a workspace."""
 
     def get(self, request, conn_id, workspace):
-        """List all coverage stores."""
+        """List all coverage stores.
+
+        With ?enabled=true each store says whether it is enabled, which
+        reads every store; the flag is null when it couldn't be read or
+        the workspace has too many stores to check.
+        """
         try:
             client = get_geoserver_client(conn_id)
-            stores = client.list_coveragestores(workspace)
+            if get_enabled_param(request):
+                stores = list_stores_with_status(client, workspace, "coveragestore")
+            else:
+                stores = client.list_coveragestores(workspace)
             return Response(stores)
         except GeoServerError:
             return Response([])
@@ -37,19 +55,23 @@ class CoverageStoreListView(APIView):
                     {"error": "name is required"},
                     status=status.HTTP_400_BAD_REQUEST,
                 )
-
-            client.create_coveragestore(
-                workspace, name, store_type, url, description, enabled
-            )
-            return Response(
-                {"message": f"Coverage store {name} created"},
-                status=status.HTTP_201_CREATED,
+            validate_name("coveragestore", name, workspace)
+
+            result = client.create_coveragestore(
+                workspace,
+                name,
+                store_type,
+                url,
+                description,
+                enabled,
+                if_absent=get_if_absent_param(request),
             )
+            return created_response(result, f"Coverage store {name}")
         except GeoServerError as e:
             return handle_geoserver_error(e)
 
 
-class CoverageStoreDetailView(APIView):
+class CoverageStoreDetailView(DataDirDiffMixin, APIView):
     """Get, update, or delete a coverage store."""
 
     def get(self, request, conn_id, workspace, store):
@@ -97,6 +119,8 @@ class CoverageStoreDetailView(APIView):
         try:
             client = get_geoserver_client(conn_id)
             recurse = get_recurse_param(request)
+            if recurse:
+                clear_store_caches(conn_id, workspace, store, "coveragestore")
             client.delete_coveragestore(workspace, store, recurse=recurse)
             return Response(status=status.HTTP_204_NO_CONTENT)
         except GeoServerError as e:
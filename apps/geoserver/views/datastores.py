@@ -5,19 +5,39 @@ from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.exceptions import GeoServerError
+from apps.gwc.invalidation import clear_store_caches
 
 from ..client import get_geoserver_client
-from .base import get_recurse_param, handle_geoserver_error
-
-
-class DataStoreListView(APIView):
+from ..naming import validate_name
+from ..publish import NamingRules, plan_layer_names, publish_feature_types
+from ..store_secrets import MASK, keep_secrets, mask_datastore, resolve_secrets
+from ..store_status import list_stores_with_status
+from .base import (
+    DataDirDiffMixin,
+    created_response,
+    get_enabled_param,
+    get_if_absent_param,
+    get_recurse_param,
+    handle_geoserver_error,
+)
+
+
+class DataStoreListView(DataDirDiffMixin, APIView):
     """List and create data stores in a workspace."""
 
     def get(self, request, conn_id, workspace):
-        """List all data stores."""
+        """List all data stores.
+
+        With ?enabled=true each store says whether it is enabled, which
+        reads every store; the flag is null when it couldn't be read or
+        the workspace has too many stores to check.
+        """
         try:
             client = get_geoserver_client(conn_id)
-            datastores = client.list_datastores(workspace)
+            if get_enabled_param(request):
+                datastores = list_stores_with_status(client, workspace, "datastore")
+            else:
+                datastores = client.list_datastores(workspace)
             return Response(datastores)
         except GeoServerError:
             return Response([])
@@ -42,38 +62,53 @@ class DataStoreListView(APIView):
                     {"error": "connectionParameters is required"},
                     status=status.HTTP_400_BAD_REQUEST,
                 )
-
-            client.create_datastore(
-                workspace, name, connection_params, description, enabled
-            )
-            return Response(
-                {"message": f"Data store {name} created"},
-                status=status.HTTP_201_CREATED,
+            validate_name("datastore", name, workspace)
+
+            result = client.create_datastore(
+                workspace,
+                name,
+                connection_params,
+                description,
+                enabled,
+                if_absent=get_if_absent_param(request),
             )
+            return created_response(result, f"Data store {name}")
         except GeoServerError as e:
             return handle_geoserver_error(e)
 
 
-class DataStoreDetailView(APIView):
+class DataStoreDetailView(DataDirDiffMixin, APIView):
     """Get, update, or delete a data store."""
 
     def get(self, request, conn_id, workspace, store):
-        """Get data store details."""
+        """Get data store details, with passwords masked."""
         try:
             client = get_geoserver_client(conn_id)
             ds = client.get_datastore(workspace, store)
-            return Response({"dataStore": ds})
+            return Response({"dataStore": mask_datastore(ds)})
         except GeoServerError as e:
             return handle_geoserver_error(e)
 
     def put(self, request, conn_id, workspace, store):
-        """Update a data store."""
+        """Update a data store.
+
+        A password sent back masked, as GET gave it, keeps the store's
+        current one; env: and keyring: references are read as on create.
+        """
         try:
             client = get_geoserver_client(conn_id)
             # For updates, we need to rebuild connection parameters
             connection_params = request.data.get("connectionParameters")
             description = request.data.get("description")
             enabled = request.data.get("enabled")
+            if connection_params:
+                if MASK in connection_params.values():
+                    existing = client.get_datastore(workspace, store)
+                    connection_params = keep_secrets(connection_params, existing)
+                try:
+                    connection_params = resolve_secrets(connection_params)
+                except ValueError as e:
+                    return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
 
             # Build update payload
             payload = {"dataStore": {"name": store}}
@@ -99,6 +134,8 @@ class DataStoreDetailView(APIView):
         try:
             client = get_geoserver_client(conn_id)
             recurse = get_recurse_param(request)
+            if recurse:
+                clear_store_caches(conn_id, workspace, store, "datastore")
             client.delete_datastore(workspace, store, recurse=recurse)
             return Response(status=status.HTTP_204_NO_CONTENT)
         except GeoServerError as e:
@@ -113,6 +150,50 @@ class DataStoreAvailableView(APIView):
         try:
             client = get_geoserver_client(conn_id)
             available = client.list_available_featuretypes(workspace, store)
-            return Response(available)
+            return Response({"available": available})
         except GeoServerError:
-            return Response([])
+            return Response({"available": []})
+
+
+class DataStorePublishView(APIView):
+    """Publish the unpublished tables of a data store, e.g. a GeoPackage."""
+
+    def post(self, request, conn_id, workspace, store):
+        """Publish tables as layers named by naming rules.
+
+        Request body:
+        {
+            "featureTypes": ["roads", "rivers"],  # Optional, all when omitted
+            "naming": {"prefix": "osm_", "suffix": "", "sanitize": true},
+            "dryRun": false  # True to only return the layer names
+        }
+        """
+        tables = request.data.get("featureTypes")
+        if tables is not None and not isinstance(tables, list):
+            return Response(
+                {"error": "featureTypes must be a list"}, status=status.HTTP_400_BAD_REQUEST
+            )
+        rules = NamingRules.from_dict(request.data.get("naming"))
+        try:
+            client = get_geoserver_client(conn_id)
+            if request.data.get("dryRun"):
+                if tables is None:
+                    tables = client.list_available_featuretypes(workspace, store)
+                taken = {layer.get("name", "") for layer in client.list_layers(workspace)}
+                names = plan_layer_names([str(t) for t in tables], rules, taken)
+                return Response({"names": names})
+            results = publish_feature_types(
+                client,
+                workspace,
+                store,
+                [str(t) for t in tables] if tables is not None else None,
+                rules,
+            )
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+        published = [r["layer"] for r in results if r["status"] == "published"]
+        errors = [f"{r['table']}: {r['error']}" for r in results if r["status"] == "error"]
+        return Response({"published": published, "errors": errors, "results": results})
@@ -0,0 +1,41 @@
+"""Default style views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.config import config_manager
+
+from ..default_styles import GEOMETRY_TYPES, parse_rules, save_rules
+
+
+class DefaultStylesView(APIView):
+    """List and replace the default styles of newly published layers."""
+
+    def get(self, request):
+        """List the rules choosing a new layer's style by workspace and geometry type."""
+        return Response(self._listing())
+
+    def put(self, request):
+        """Replace the rules.
+
+        Expected body:
+        {
+            "rules": [
+                {"workspace": "roads", "line": "roads:road_lines", "raster": "dem"},
+                {"workspace": "*", "point": "poi", "connections": ["conn_prod"]}
+            ]
+        }
+        """
+        try:
+            rules = parse_rules(request.data.get("rules", []))
+        except (TypeError, ValueError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        save_rules(rules)
+        return Response(self._listing())
+
+    def _listing(self) -> dict:
+        return {
+            "rules": [rule.model_dump() for rule in config_manager.config.default_styles],
+            "geometryTypes": list(GEOMETRY_TYPES),
+        }
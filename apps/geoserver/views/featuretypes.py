@@ -5,12 +5,21 @@ from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.exceptions import GeoServerError
+from apps.gwc.invalidation import invalidate_layer_cache
 
-from ..client import get_geoserver_client
-from .base import get_recurse_param, handle_geoserver_error
+from ..client import CreateResult, get_geoserver_client
+from ..naming import validate_name
+from ..schema import publish_attributes
+from .base import (
+    DataDirDiffMixin,
+    created_response,
+    get_if_absent_param,
+    get_recurse_param,
+    handle_geoserver_error,
+)
 
 
-class FeatureTypeListView(APIView):
+class FeatureTypeListView(DataDirDiffMixin, APIView):
     """List and create feature types in a data store."""
 
     def get(self, request, conn_id, workspace, store):
@@ -23,7 +32,12 @@ class FeatureTypeListView(APIView):
             return Response([])
 
     def post(self, request, conn_id, workspace, store):
-        """Publish a feature type."""
+        """Publish a feature type.
+
+        An optional "attributes" list limits the published attributes to
+        those named, e.g. {"name": "roads", "attributes": ["geom", "name"]}.
+        With "ifAbsent" a feature type already published is left as it is.
+        """
         try:
             client = get_geoserver_client(conn_id)
             name = request.data.get("name")
@@ -36,18 +50,34 @@ class FeatureTypeListView(APIView):
                     {"error": "name is required"},
                     status=status.HTTP_400_BAD_REQUEST,
                 )
+            validate_name("layer", name, workspace)
 
-            client.create_featuretype(workspace, store, name, native_name, title, srs)
-            return Response(
-                {"message": f"Feature type {name} published"},
-                status=status.HTTP_201_CREATED,
+            result = client.create_featuretype(
+                workspace,
+                store,
+                name,
+                native_name,
+                title,
+                srs,
+                if_absent=get_if_absent_param(request),
             )
+            attributes = request.data.get("attributes")
+            if attributes and result == CreateResult.CREATED:
+                # The schema can only be described once the layer is published
+                try:
+                    publish_attributes(client, workspace, name, [str(a) for a in attributes])
+                except ValueError as e:
+                    return Response(
+                        {"error": f"Feature type {name} published with all attributes: {e}"},
+                        status=status.HTTP_400_BAD_REQUEST,
+                    )
+            return created_response(result, f"Feature type {name}", "published")
         except GeoServerError as e:
             return handle_geoserver_error(e)
 
 
-class FeatureTypeDetailView(APIView):
-    """Get or delete a feature type."""
+class FeatureTypeDetailView(DataDirDiffMixin, APIView):
+    """Get, update, or delete a feature type."""
 
     def get(self, request, conn_id, workspace, store, featuretype):
         """Get feature type details."""
@@ -58,6 +88,28 @@ class FeatureTypeDetailView(APIView):
         except GeoServerError as e:
             return handle_geoserver_error(e)
 
+    def put(self, request, conn_id, workspace, store, featuretype):
+        """Update a feature type.
+
+        The body is a partial featureType object, e.g.
+        {"title": "Roads", "abstract": "...", "cqlFilter": "..."}.
+        Cached tiles for the layer are truncated when the connection has
+        auto-truncate enabled.
+        """
+        try:
+            client = get_geoserver_client(conn_id)
+            updates = request.data.get("featureType", request.data)
+            client.update_featuretype(workspace, store, featuretype, updates)
+            truncated = invalidate_layer_cache(conn_id, workspace, featuretype)
+            return Response(
+                {
+                    "message": f"Feature type {featuretype} updated",
+                    "truncatedLayers": truncated,
+                }
+            )
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
     def delete(self, request, conn_id, workspace, store, featuretype):
         """Delete a feature type."""
         try:
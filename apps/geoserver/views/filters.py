@@ -0,0 +1,106 @@
+"""CQL filter builder views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.exceptions import GeoServerError
+
+from ..client import get_geoserver_client
+from ..cql import (
+    MATCH_ALL,
+    Condition,
+    build_cql,
+    describe_filter_attributes,
+    suggest_values,
+    validate_cql,
+)
+from .base import handle_geoserver_error
+
+
+class LayerFilterAttributesView(APIView):
+    """List the attributes of a layer a filter can test."""
+
+    def get(self, request, conn_id, workspace, layer):
+        """Get the attributes with the operators each one takes."""
+        try:
+            client = get_geoserver_client(conn_id)
+            attributes = describe_filter_attributes(client, workspace, layer)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response({"attributes": [a.to_dict() for a in attributes]})
+
+
+class LayerFilterValuesView(APIView):
+    """Suggest values of a layer attribute."""
+
+    def get(self, request, conn_id, workspace, layer):
+        """Get sample values of an attribute.
+
+        Query parameters:
+        - attribute: Attribute name
+        - prefix: Optional start of the values
+        """
+        attribute = request.query_params.get("attribute", "")
+        if not attribute:
+            return Response(
+                {"error": "attribute is required"}, status=status.HTTP_400_BAD_REQUEST
+            )
+        try:
+            client = get_geoserver_client(conn_id)
+            values = suggest_values(
+                client, workspace, layer, attribute, prefix=request.query_params.get("prefix", "")
+            )
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response({"attribute": attribute, "values": values})
+
+
+class LayerFilterValidateView(APIView):
+    """Build a CQL filter and check it against a layer."""
+
+    def post(self, request, conn_id, workspace, layer):
+        """Validate a filter and count the features it matches.
+
+        Request body, either:
+        {"conditions": [{"attribute": "pop", "operator": ">=", "value": "1000"}],
+         "match": "all"}
+        or {"cql": "pop >= 1000"}.
+
+        An invalid filter is not an error response; it comes back with
+        valid false and GeoServer's message.
+        """
+        data = request.data
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        try:
+            cql = (data.get("cql") or "").strip()
+            if not cql:
+                attributes = describe_filter_attributes(client, workspace, layer)
+                conditions = [Condition.from_dict(c) for c in data.get("conditions") or []]
+                cql = build_cql(conditions, attributes, data.get("match") or MATCH_ALL) or ""
+        except ValueError as e:
+            return Response({"cql": "", "valid": False, "error": str(e), "count": None})
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+        if not cql:
+            return Response(
+                {"error": "Add a condition or a CQL filter"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        try:
+            count = validate_cql(client, workspace, layer, cql)
+        except GeoServerError as e:
+            if e.status_code != 400:
+                return handle_geoserver_error(e)
+            # GeoServer rejected the filter itself
+            return Response({"cql": cql, "valid": False, "error": e.message, "count": None})
+        return Response({"cql": cql, "valid": True, "error": None, "count": count})
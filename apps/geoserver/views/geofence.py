@@ -0,0 +1,105 @@
+"""GeoFence data security rule views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.exceptions import GeoServerError
+
+from ..client import get_geoserver_client
+from ..geofence import ACCESS_TYPES, CATALOG_MODES, SERVICES, GeofenceRule
+from .base import handle_geoserver_error
+
+
+def _geofence_client(conn_id: str):
+    """GeoFence client of a connection.
+
+    Raises:
+        ValueError: If the connection doesn't exist
+        GeoServerError: If GeoFence isn't installed
+    """
+    geofence = get_geoserver_client(conn_id).geofence
+    if not geofence.is_available():
+        raise GeoServerError("The GeoFence extension isn't installed", status_code=501)
+    return geofence
+
+
+class GeofenceRuleListView(APIView):
+    """List and add GeoFence rules."""
+
+    def get(self, request, conn_id):
+        """List the rules in priority order, with the values their fields take.
+
+        Servers without GeoFence answer with available false and no rules,
+        so clients can hide rule management.
+        """
+        try:
+            geofence = get_geoserver_client(conn_id).geofence
+            available = geofence.is_available()
+            rules = geofence.list_rules() if available else []
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(
+            {
+                "available": available,
+                "rules": [rule.to_dict() for rule in rules],
+                "accessTypes": list(ACCESS_TYPES),
+                "catalogModes": list(CATALOG_MODES),
+                "services": list(SERVICES),
+            }
+        )
+
+    def post(self, request, conn_id):
+        """Add a rule.
+
+        Request body: priority, access (ALLOW, DENY or LIMIT) and the fields
+        matching requests (user, role, addressRange, service, request,
+        workspace, layer), each empty or "*" for any. LIMIT rules may add an
+        area (WKT polygon, EPSG:4326 unless prefixed with SRID=<code>;) and
+        a catalogMode.
+        """
+        try:
+            rule = GeofenceRule.from_dict(request.data)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        try:
+            rule_id = _geofence_client(conn_id).create_rule(rule)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response({"id": rule_id}, status=status.HTTP_201_CREATED)
+
+
+class GeofenceRuleDetailView(APIView):
+    """Update or delete a GeoFence rule."""
+
+    def put(self, request, conn_id, rule_id):
+        """Replace a rule's fields with those given, as on create.
+
+        The response carries the rule's id, which changes when a field it
+        had is cleared: GeoFence can't clear fields, so the rule is replaced.
+        """
+        try:
+            rule = GeofenceRule.from_dict(request.data, rule_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        try:
+            new_id = _geofence_client(conn_id).update_rule(rule)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response({"id": new_id})
+
+    def delete(self, request, conn_id, rule_id):
+        """Delete a rule."""
+        try:
+            _geofence_client(conn_id).delete_rule(rule_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(status=status.HTTP_204_NO_CONTENT)
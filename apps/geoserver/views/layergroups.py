@@ -1,11 +1,14 @@
 """Layer group views for GeoServer API."""
 
+from django.http import HttpResponse
+from rest_framework import status
 from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.exceptions import GeoServerError
 
 from ..client import get_geoserver_client
+from ..composition import render_composition
 from .base import handle_geoserver_error
 
 
@@ -33,3 +36,36 @@ class LayerGroupDetailView(APIView):
             return Response({"layerGroup": group})
         except GeoServerError as e:
             return handle_geoserver_error(e)
+
+
+class LayerGroupPreviewView(APIView):
+    """Draw a draft layer group before it is saved."""
+
+    def post(self, request, conn_id, workspace):
+        """Render layers over each other as a PNG.
+
+        Request body:
+        - layers: layer names, drawn in order with the first at the bottom
+        - width, height: image size in pixels (default 512 x 320)
+        """
+        layers = request.data.get("layers") or []
+        if not isinstance(layers, list) or not all(isinstance(name, str) for name in layers):
+            return Response(
+                {"error": "layers must be a list of layer names"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        try:
+            width = int(request.data.get("width", 512))
+            height = int(request.data.get("height", 320))
+        except (TypeError, ValueError):
+            return Response(
+                {"error": "width and height must be integers"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        try:
+            client = get_geoserver_client(conn_id)
+            image = render_composition(client, workspace, layers, width, height)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return HttpResponse(image, content_type="image/png")
@@ -1,13 +1,22 @@
 """Layer views for GeoServer API."""
 
+from django.http import HttpResponse
 from rest_framework import status
 from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.exceptions import GeoServerError
+from apps.gwc.client import get_gwc_client
+from apps.gwc.usage import get_layer_quota_usage
 
+from ..attributes import DEFAULT_LIMIT, export_attribute_csv, read_attribute_table
+from ..branding import LayerAttribution
 from ..client import get_geoserver_client
-from .base import get_recurse_param, handle_geoserver_error
+from ..endpoint_health import verify_endpoints
+from ..monitor import DEFAULT_DAYS, layer_request_stats
+from ..render_benchmark import DEFAULT_SAMPLES, DEFAULT_SIZE, run_benchmark
+from ..schema import publish_attributes
+from .base import DataDirDiffMixin, get_recurse_param, handle_geoserver_error
 
 
 class LayerListView(APIView):
@@ -23,7 +32,7 @@ class LayerListView(APIView):
             return Response([])
 
 
-class LayerDetailView(APIView):
+class LayerDetailView(DataDirDiffMixin, APIView):
     """Get, update, or delete a layer."""
 
     def get(self, request, conn_id, workspace, layer):
@@ -71,17 +80,234 @@ class LayerCountView(APIView):
     """Get feature count for a layer."""
 
     def get(self, request, conn_id, workspace, layer):
-        """Get layer feature count."""
+        """Get layer feature count.
+
+        Optional query parameters:
+        - cqlFilter: CQL filter restricting the counted features
+        - bbox: "minx,miny,maxx,maxy" restricting the counted features
+        - bboxSrs: SRS of the bbox, e.g. EPSG:4326
+        """
+        cql_filter = request.query_params.get("cqlFilter") or None
+        bbox_param = request.query_params.get("bbox")
+        bbox_srs = request.query_params.get("bboxSrs") or None
+
         try:
+            bbox = [float(v) for v in bbox_param.split(",")] if bbox_param else None
             client = get_geoserver_client(conn_id)
-            count = client.get_layer_feature_count(workspace, layer)
+            count = client.get_layer_feature_count(
+                workspace, layer, cql_filter=cql_filter, bbox=bbox, bbox_srs=bbox_srs
+            )
             return Response({"count": count})
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+
+class LayerCacheUsageView(APIView):
+    """Get the cached tile size of a layer from the GWC disk quota."""
+
+    def get(self, request, conn_id, workspace, layer):
+        """Get the layer's tile cache quota and usage."""
+        try:
+            usage = get_layer_quota_usage(get_gwc_client(conn_id), f"{workspace}:{layer}")
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
         except GeoServerError as e:
             return handle_geoserver_error(e)
+        return Response(usage.to_dict())
 
 
-class LayerMetadataView(APIView):
-    """Get layer metadata including bounding box."""
+class LayerRequestStatsView(APIView):
+    """Get request figures of a layer from the Monitoring extension."""
+
+    def get(self, request, conn_id, workspace, layer):
+        """Count the layer's recent requests.
+
+        Optional query parameters:
+        - days: Length of the counted period (default 30)
+        """
+        try:
+            days = int(request.query_params.get("days", DEFAULT_DAYS))
+            client = get_geoserver_client(conn_id)
+            stats = layer_request_stats(client, workspace, layer, days=days)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(stats.to_dict())
+
+
+class LayerBenchmarkView(APIView):
+    """Time how long a layer takes to render."""
+
+    def post(self, request, conn_id, workspace, layer):
+        """Time GetMap requests across zoom levels and places in the layer.
+
+        Request body, every field optional:
+        - style: Style to render with (default: the layer's default style)
+        - zooms: Web Mercator zoom levels (default: three from the one
+          fitting the layer)
+        - samples: Places per zoom level (default 3)
+        - size: Image width and height, 256, 512 or 1024 (default 512)
+        - seed: Seed of the sampled places, to repeat a run (default 0)
+        """
+        data = request.data
+        try:
+            zooms = [int(zoom) for zoom in data.get("zooms") or []] or None
+            samples = int(data.get("samples") or DEFAULT_SAMPLES)
+            size = int(data.get("size") or DEFAULT_SIZE)
+            seed = int(data.get("seed") or 0)
+        except (TypeError, ValueError):
+            return Response(
+                {"error": "zooms, samples, size and seed must be whole numbers"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        try:
+            client = get_geoserver_client(conn_id)
+            report = run_benchmark(
+                client,
+                workspace,
+                layer,
+                style=data.get("style") or "",
+                zooms=zooms,
+                samples=samples,
+                size=size,
+                seed=seed,
+            )
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(report.to_dict())
+
+
+class LayerEndpointsView(APIView):
+    """Check which of a layer's OGC endpoints work."""
+
+    def post(self, request, conn_id, workspace, layer):
+        """Send the layer's WMS, WFS, WMTS and legend endpoints a lightweight request each.
+
+        Returns a result per endpoint: pass, fail with GeoServer's reason,
+        or skip when the layer doesn't offer it.
+        """
+        try:
+            client = get_geoserver_client(conn_id)
+            report = verify_endpoints(client, workspace, layer, get_gwc_client(conn_id))
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(report.to_dict())
+
+
+def _attribute_query(request) -> tuple[str | None, bool, dict[str, str], str | None]:
+    """Read the sort column, sort order, column filters and CQL filter of a request."""
+    params = request.query_params
+    filters = {
+        key.removeprefix("filter."): value
+        for key, value in params.items()
+        if key.startswith("filter.")
+    }
+    descending = params.get("order", "asc").lower() == "desc"
+    return params.get("sort") or None, descending, filters, params.get("cql") or None
+
+
+class LayerAttributesView(APIView):
+    """Page through the attribute table of a vector layer via WFS."""
+
+    def get(self, request, conn_id, workspace, layer):
+        """Get a page of attribute rows.
+
+        Optional query parameters:
+        - limit: Rows per page (default 100, at most 1000)
+        - offset: Index of the first row
+        - sort: Column to sort by
+        - order: asc or desc
+        - filter.<column>: Text to match, or a comparison such as ">= 10"
+        - cql: CQL filter applied along with the column filters
+        """
+        sort, descending, filters, cql = _attribute_query(request)
+        try:
+            client = get_geoserver_client(conn_id)
+            page = read_attribute_table(
+                client,
+                workspace,
+                layer,
+                limit=int(request.query_params.get("limit", DEFAULT_LIMIT)),
+                offset=int(request.query_params.get("offset", 0)),
+                sort=sort,
+                descending=descending,
+                filters=filters,
+                cql=cql,
+            )
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(page.to_dict())
+
+
+class LayerAttributesExportView(APIView):
+    """Download the attribute table of a vector layer as CSV."""
+
+    def get(self, request, conn_id, workspace, layer):
+        """Export the filtered, sorted attribute rows.
+
+        Takes the sort, order, filter.<column> and cql parameters of the
+        attribute table.
+        """
+        sort, descending, filters, cql = _attribute_query(request)
+        try:
+            client = get_geoserver_client(conn_id)
+            csv = export_attribute_csv(client, workspace, layer, sort, descending, filters, cql)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+        response = HttpResponse(csv, content_type="text/csv")
+        response["Content-Disposition"] = f'attachment; filename="{workspace}_{layer}.csv"'
+        return response
+
+
+class LayerSchemaView(DataDirDiffMixin, APIView):
+    """Get a vector layer's schema or choose the attributes it publishes."""
+
+    def get(self, request, conn_id, workspace, layer):
+        """Get attribute names, types and nullability with the geometry type and SRS."""
+        try:
+            client = get_geoserver_client(conn_id)
+            schema = client.describe_feature_type(workspace, layer, include_srs=True)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(schema.to_dict())
+
+    def put(self, request, conn_id, workspace, layer):
+        """Publish only some attributes.
+
+        Request body: {"attributes": ["the_geom", "name", "population"]}
+        """
+        names = request.data.get("attributes")
+        if not isinstance(names, list) or not names:
+            return Response(
+                {"error": "attributes must be a list of attribute names"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        try:
+            client = get_geoserver_client(conn_id)
+            schema = publish_attributes(client, workspace, layer, [str(n) for n in names])
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(schema.to_dict())
+
+
+class LayerMetadataView(DataDirDiffMixin, APIView):
+    """Get or update layer metadata, including bounding box and attribution."""
 
     def get(self, request, conn_id, workspace, layer):
         """Get layer metadata."""
@@ -92,8 +318,49 @@ class LayerMetadataView(APIView):
         except GeoServerError as e:
             return handle_geoserver_error(e)
 
+    def put(self, request, conn_id, workspace, layer):
+        """Update layer metadata.
+
+        Request body, every field optional:
+        - enabled, advertised, queryable: layer flags
+        - title, abstract, keywords, srs, metadataLinks: of the feature type
+          or coverage
+        - attributionTitle, attributionHref, attributionLogo,
+          attributionLogoWidth, attributionLogoHeight: replace the whole
+          attribution when any is given; the logo's type is guessed from
+          its URL
+        """
+        data = request.data
+        attribution = None
+        if any(key.startswith("attribution") for key in data):
+            try:
+                attribution = LayerAttribution.from_dict(data)
+            except ValueError as e:
+                return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
+        resource = {key: data[key] for key in ("title", "abstract") if key in data}
+        if data.get("srs"):
+            resource["srs"] = data["srs"]
+        if "keywords" in data:
+            resource["keywords"] = {"string": [k for k in data["keywords"] or [] if k]}
+        if "metadataLinks" in data:
+            resource["metadataLinks"] = {"metadataLink": data["metadataLinks"] or []}
+        flags = {key: data[key] for key in ("enabled", "advertised", "queryable") if key in data}
+
+        try:
+            client = get_geoserver_client(conn_id)
+            if flags:
+                client.update_layer(workspace, layer, **flags)
+            if resource:
+                client.update_layer_resource(workspace, layer, resource)
+            if attribution is not None:
+                client.update_layer_attribution(workspace, layer, attribution)
+            return Response(client.get_layer_metadata(workspace, layer))
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
 
-class LayerStylesView(APIView):
+class LayerStylesView(DataDirDiffMixin, APIView):
     """Get or update layer style associations."""
 
     def get(self, request, conn_id, workspace, layer):
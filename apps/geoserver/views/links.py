@@ -0,0 +1,52 @@
+"""Copyable link views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.exceptions import GeoServerError
+
+from ..client import get_geoserver_client
+from ..links import copy_targets
+
+
+class CopyTargetsView(APIView):
+    """List the URLs and names worth copying for a tree node."""
+
+    def get(self, request, conn_id):
+        """Build the copy targets.
+
+        Query parameters:
+        - type: Tree node type, e.g. layer, layergroup, style or workspace
+        - workspace, name, store: Identify the resource
+        - storeType: datastore or coveragestore, for layers
+        """
+        node_type = request.query_params.get("type", "")
+        workspace = request.query_params.get("workspace") or None
+        name = request.query_params.get("name") or None
+        if not node_type:
+            return Response({"error": "type is required"}, status=status.HTTP_400_BAD_REQUEST)
+
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        bbox = None
+        if node_type == "layer" and workspace and name:
+            # Frame the GetMap link on the layer; fall back to the world
+            try:
+                bbox = client.get_layer_bounds(workspace, name).get("latLonBoundingBox")
+            except GeoServerError:
+                pass
+
+        targets = copy_targets(
+            client.connection,
+            node_type,
+            workspace,
+            name,
+            store=request.query_params.get("store") or None,
+            store_type=request.query_params.get("storeType") or None,
+            bbox=bbox,
+        )
+        return Response([target.to_dict() for target in targets])
@@ -0,0 +1,49 @@
+"""Catalog integrity check views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.exceptions import GeoServerError
+from apps.gwc.client import get_gwc_client
+
+from ..client import get_geoserver_client
+from ..lint import CHECKS, lint_catalog
+from .base import handle_geoserver_error
+
+
+class CatalogLintView(APIView):
+    """Scan a connection's catalog for broken references."""
+
+    def get(self, request, conn_id):
+        """Run the integrity checks.
+
+        Query parameters:
+        - checks: Comma-separated check names; all checks when omitted
+        """
+        checks = [c for c in request.query_params.get("checks", "").split(",") if c] or None
+        unknown = [c for c in checks or [] if c not in CHECKS]
+        if unknown:
+            return Response(
+                {"error": f"Unknown check(s): {', '.join(unknown)}"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        try:
+            client = get_geoserver_client(conn_id)
+            issues = lint_catalog(client, get_gwc_client(conn_id), checks)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+        return Response(
+            {
+                "issues": [issue.to_dict() for issue in issues],
+                "counts": {
+                    "error": sum(1 for i in issues if i.severity == "error"),
+                    "warning": sum(1 for i in issues if i.severity == "warning"),
+                },
+                "checks": [{"name": name, "description": desc} for name, desc in CHECKS.items()],
+            }
+        )
@@ -0,0 +1,42 @@
+"""Naming convention views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.config import config_manager
+
+from ..naming import RESOURCE_TYPES, check_name, conventions_to_dict
+
+
+class NamingConventionsView(APIView):
+    """List the naming conventions and check names against them."""
+
+    def get(self, request):
+        """List the configured naming conventions."""
+        return Response(
+            {
+                "conventions": conventions_to_dict(),
+                "slugifyUploadNames": config_manager.config.slugify_upload_names,
+            }
+        )
+
+    def post(self, request):
+        """Check a name before creating or renaming a resource.
+
+        Expected body:
+        {
+            "resourceType": "datastore",
+            "name": "roads",
+            "workspace": "topp"  # Optional, for prefixes using {workspace}
+        }
+        """
+        resource_type = request.data.get("resourceType")
+        name = request.data.get("name")
+        if resource_type not in RESOURCE_TYPES or not name:
+            return Response(
+                {"error": f"name and a resourceType of {', '.join(RESOURCE_TYPES)} are required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        problems = check_name(resource_type, name, request.data.get("workspace") or None)
+        return Response({"valid": not problems, "problems": problems})
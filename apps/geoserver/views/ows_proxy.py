@@ -0,0 +1,54 @@
+"""OGC request proxy views for GeoServer API."""
+
+from django.http import StreamingHttpResponse
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.exceptions import GeoServerError
+
+from ..client import get_geoserver_client
+from ..ows_proxy import (
+    FORWARDED_REQUEST_HEADERS,
+    FORWARDED_RESPONSE_HEADERS,
+    check_request,
+    forwarded_headers,
+    proxy_path,
+)
+from .base import handle_geoserver_error
+
+
+class OwsProxyView(APIView):
+    """Forward OGC requests to a connection's GeoServer with its credentials."""
+
+    def get(self, request, conn_id, target=""):
+        """Stream GeoServer's answer to a GetMap, GetFeature, GetTile or other read.
+
+        target is the service path: empty for /ows, "topp/wms" for a
+        workspace's WMS, or "gwc/service/wmts" for cached tiles. The query
+        string is passed on as it is.
+        """
+        try:
+            path = proxy_path(target)
+            params = dict(request.GET.lists())
+            check_request(path, params)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        try:
+            response, body = client.open_ows(
+                path,
+                [(key, value) for key, values in params.items() for value in values],
+                forwarded_headers(request.headers, FORWARDED_REQUEST_HEADERS),
+            )
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+        proxied = StreamingHttpResponse(body, status=response.status_code)
+        for name, value in forwarded_headers(response.headers, FORWARDED_RESPONSE_HEADERS).items():
+            proxied[name] = value
+        return proxied
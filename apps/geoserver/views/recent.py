@@ -0,0 +1,39 @@
+"""Recently added and modified resource views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from ..client import get_geoserver_client
+from ..recent import DEFAULT_LIMIT, recent_items
+
+
+class RecentItemsView(APIView):
+    """List the layers and styles most recently added and modified."""
+
+    def get(self, request, conn_id):
+        """List recent items, newest first.
+
+        Query parameters:
+        - limit: Items per list (default 10)
+        """
+        try:
+            limit = int(request.query_params.get("limit", DEFAULT_LIMIT))
+        except ValueError:
+            return Response(
+                {"error": "limit must be a number"}, status=status.HTTP_400_BAD_REQUEST
+            )
+        if limit < 1:
+            return Response(
+                {"error": "limit must be positive"}, status=status.HTTP_400_BAD_REQUEST
+            )
+
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        items = recent_items(client, limit)
+        return Response(
+            {action: [item.to_dict() for item in found] for action, found in items.items()}
+        )
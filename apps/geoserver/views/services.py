@@ -0,0 +1,48 @@
+"""OGC service settings views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.exceptions import GeoServerError
+
+from ..branding import WATERMARK_POSITIONS, Watermark
+from ..client import get_geoserver_client
+from .base import handle_geoserver_error
+
+
+class WmsWatermarkView(APIView):
+    """Get or set the watermark the WMS service draws on maps."""
+
+    def get(self, request, conn_id):
+        """Get the watermark settings with the positions it may take."""
+        try:
+            watermark = get_geoserver_client(conn_id).get_wms_watermark()
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response({**watermark.to_dict(), "positions": list(WATERMARK_POSITIONS)})
+
+    def put(self, request, conn_id):
+        """Set the watermark.
+
+        Expected body:
+        {
+            "enabled": true,
+            "url": "https://example.com/logo.png",  # Or a data directory path
+            "position": "BOT_RIGHT",
+            "transparency": 30  # Percent
+        }
+        """
+        try:
+            watermark = Watermark.from_dict(request.data)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        try:
+            get_geoserver_client(conn_id).update_wms_watermark(watermark)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(watermark.to_dict())
@@ -0,0 +1,260 @@
+"""Snapshot download, schedule and restore views for GeoServer API."""
+
+from botocore.exceptions import ClientError
+from django.http import HttpResponse
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.config import SnapshotSchedule, get_config
+from apps.core.exceptions import GeoServerError
+
+from ..client import get_geoserver_client
+from ..snapshot import build_snapshot, restore_snapshot
+from ..snapshot_schedule import (
+    archive_store,
+    get_snapshot_scheduler,
+    list_snapshots,
+    next_run_time,
+    parse_archive_name,
+    validate_snapshot_schedule,
+)
+from .base import handle_geoserver_error
+
+
+class SnapshotView(APIView):
+    """Download the configuration, and optionally data, of several resources."""
+
+    def post(self, request, conn_id):
+        """Capture resources into a dated snapshot folder, sent as a zip.
+
+        Request body:
+        - resources: [{type, workspace, name}], type being workspace,
+          datastore, coveragestore, layer, style or layergroup
+        - includeData: Also download layer data (shapefiles and GeoTIFFs)
+
+        Resources that can't be read are listed with their error in the
+        manifest; the X-Snapshot-Failed header counts them.
+        """
+        resources = request.data.get("resources") or []
+        if not isinstance(resources, list):
+            return Response(
+                {"error": "resources must be a list"}, status=status.HTTP_400_BAD_REQUEST
+            )
+
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        try:
+            snapshot = build_snapshot(
+                client, resources, include_data=bool(request.data.get("includeData"))
+            )
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
+        response = HttpResponse(snapshot.to_zip(), content_type="application/zip")
+        response["Content-Disposition"] = f'attachment; filename="{snapshot.folder}.zip"'
+        response["X-Snapshot-Failed"] = str(len(snapshot.failed))
+        return response
+
+
+# API field -> SnapshotSchedule attribute
+SCHEDULE_FIELDS = {
+    "name": "name",
+    "connectionIds": "connection_ids",
+    "cron": "cron",
+    "destination": "destination",
+    "directory": "directory",
+    "s3ConnectionId": "s3_connection_id",
+    "bucket": "bucket",
+    "prefix": "prefix",
+    "includeData": "include_data",
+    "keepDaily": "keep_daily",
+    "keepWeekly": "keep_weekly",
+    "enabled": "enabled",
+}
+
+
+def _schedule_to_dict(schedule: SnapshotSchedule) -> dict:
+    """Serialize a snapshot schedule for the API."""
+    data = {key: getattr(schedule, attr) for key, attr in SCHEDULE_FIELDS.items()}
+    data.update(
+        {
+            "id": schedule.id,
+            "createdAt": schedule.created_at,
+            "lastRunAt": schedule.last_run_at,
+            "lastStatus": schedule.last_status,
+            "lastError": schedule.last_error,
+            "nextRunAt": next_run_time(schedule),
+        }
+    )
+    return data
+
+
+def _apply_schedule_data(schedule: SnapshotSchedule, data: dict) -> None:
+    """Apply API fields from a request body to a schedule."""
+    for key, attr in SCHEDULE_FIELDS.items():
+        if key in data:
+            setattr(schedule, attr, data[key])
+    schedule.connection_ids = [str(c) for c in schedule.connection_ids]
+    schedule.keep_daily = int(schedule.keep_daily)
+    schedule.keep_weekly = int(schedule.keep_weekly)
+    schedule.include_data = bool(schedule.include_data)
+
+
+class SnapshotScheduleListView(APIView):
+    """List and create scheduled catalog snapshots."""
+
+    def get(self, request):
+        """List all snapshot schedules.
+
+        Query params:
+        - connectionId: Only schedules snapshotting this connection
+        """
+        schedules = get_config().list_snapshot_schedules()
+        conn_id = request.query_params.get("connectionId")
+        if conn_id:
+            schedules = [s for s in schedules if conn_id in s.connection_ids]
+        return Response({"schedules": [_schedule_to_dict(s) for s in schedules]})
+
+    def post(self, request):
+        """Create a snapshot schedule.
+
+        Expected body:
+        {
+            "name": "Nightly catalog",
+            "connectionIds": ["conn_123"],
+            "cron": "0 2 * * *",
+            "destination": "s3",  // local or s3
+            "directory": "",  // Folder, for local
+            "s3ConnectionId": "minio",  // For s3
+            "bucket": "backups",
+            "prefix": "geoserver",
+            "includeData": false,
+            "keepDaily": 7,
+            "keepWeekly": 4
+        }
+        """
+        if not request.data.get("name"):
+            return Response(
+                {"error": "Missing required fields: name"}, status=status.HTTP_400_BAD_REQUEST
+            )
+        try:
+            schedule = SnapshotSchedule(name=request.data["name"])
+            _apply_schedule_data(schedule, request.data)
+            validate_snapshot_schedule(schedule)
+        except (TypeError, ValueError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
+        get_config().add_snapshot_schedule(schedule)
+        return Response(_schedule_to_dict(schedule), status=status.HTTP_201_CREATED)
+
+
+class SnapshotScheduleDetailView(APIView):
+    """Get, update, or delete a scheduled catalog snapshot."""
+
+    def get(self, request, schedule_id):
+        """Get a snapshot schedule."""
+        schedule = get_config().get_snapshot_schedule(schedule_id)
+        if not schedule:
+            return Response({"error": "Schedule not found"}, status=status.HTTP_404_NOT_FOUND)
+        return Response(_schedule_to_dict(schedule))
+
+    def put(self, request, schedule_id):
+        """Update a snapshot schedule."""
+        config = get_config()
+        existing = config.get_snapshot_schedule(schedule_id)
+        if not existing:
+            return Response({"error": "Schedule not found"}, status=status.HTTP_404_NOT_FOUND)
+
+        schedule = existing.model_copy(deep=True)
+        try:
+            _apply_schedule_data(schedule, request.data)
+            validate_snapshot_schedule(schedule)
+        except (TypeError, ValueError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
+        config.update_snapshot_schedule(schedule)
+        return Response(_schedule_to_dict(schedule))
+
+    def delete(self, request, schedule_id):
+        """Delete a snapshot schedule; its stored snapshots are kept."""
+        if not get_config().remove_snapshot_schedule(schedule_id):
+            return Response({"error": "Schedule not found"}, status=status.HTTP_404_NOT_FOUND)
+        return Response(status=status.HTTP_204_NO_CONTENT)
+
+
+class SnapshotScheduleRunView(APIView):
+    """Run a scheduled catalog snapshot immediately."""
+
+    def post(self, request, schedule_id):
+        """Snapshot the schedule's connections now and prune their older snapshots."""
+        schedule = get_snapshot_scheduler().run_schedule(schedule_id)
+        if not schedule:
+            return Response({"error": "Schedule not found"}, status=status.HTTP_404_NOT_FOUND)
+        return Response(_schedule_to_dict(schedule))
+
+
+class SnapshotScheduleSnapshotsView(APIView):
+    """List the snapshots a schedule has stored, to pick one to restore."""
+
+    def get(self, request, schedule_id):
+        """List stored snapshots, newest first.
+
+        Query params:
+        - connectionId: Only snapshots of this connection
+        """
+        schedule = get_config().get_snapshot_schedule(schedule_id)
+        if not schedule:
+            return Response({"error": "Schedule not found"}, status=status.HTTP_404_NOT_FOUND)
+        try:
+            snapshots = list_snapshots(schedule, request.query_params.get("connectionId"))
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except (OSError, ClientError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_502_BAD_GATEWAY)
+        return Response({"snapshots": [s.to_dict() for s in snapshots]})
+
+
+class SnapshotRestoreView(APIView):
+    """Restore a stored snapshot onto a connection."""
+
+    def post(self, request, schedule_id):
+        """Recreate the resources of a stored snapshot that a connection is missing.
+
+        Request body:
+        - name: File name of the snapshot, as listed
+        - connectionId: Connection to restore onto (default: the one it was taken of)
+
+        Existing resources are left as they are. The response lists the
+        files whose resources were created, already existed, or failed.
+        """
+        schedule = get_config().get_snapshot_schedule(schedule_id)
+        if not schedule:
+            return Response({"error": "Schedule not found"}, status=status.HTTP_404_NOT_FOUND)
+        archive = parse_archive_name(request.data.get("name") or "")
+        if not archive:
+            return Response(
+                {"error": "name must be a stored snapshot's file name"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        try:
+            client = get_geoserver_client(request.data.get("connectionId") or archive.connection_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        try:
+            report = restore_snapshot(client, archive_store(schedule).read(archive.name))
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except FileNotFoundError:
+            return Response(
+                {"error": f"Snapshot not found: {archive.name}"}, status=status.HTTP_404_NOT_FOUND
+            )
+        except (OSError, ClientError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_502_BAD_GATEWAY)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(report.to_dict())
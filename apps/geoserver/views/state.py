@@ -0,0 +1,33 @@
+"""Managed resource state views for GeoServer API."""
+
+from rest_framework import status
+from rest_framework.response import Response
+from rest_framework.views import APIView
+
+from apps.core.config import config_manager
+
+from ..managed import KINDS, managed_state
+
+
+class ManagedStateView(APIView):
+    """List the resources the client created or adopted on a connection."""
+
+    def get(self, request, conn_id):
+        """List managed resources.
+
+        Query parameters:
+        - kind: Only resources of this kind, e.g. style
+        - workspace: Only this workspace and the resources in it
+        """
+        if config_manager.get_connection(conn_id) is None:
+            return Response({"error": "Connection not found"}, status=status.HTTP_404_NOT_FOUND)
+        kind = request.query_params.get("kind") or None
+        if kind and kind not in KINDS:
+            return Response(
+                {"error": f"kind must be one of {', '.join(KINDS)}"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        resources = managed_state.resources(
+            conn_id, kind, request.query_params.get("workspace") or None
+        )
+        return Response({"resources": [r.to_dict() for r in resources]})
@@ -5,12 +5,38 @@ from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.exceptions import GeoServerError
+from apps.gwc.invalidation import invalidate_style_cache
 
 from ..client import get_geoserver_client
-from .base import handle_geoserver_error
+from ..naming import validate_name
+from ..sld import convert_sld, detect_sld_version
+from ..style_assets import list_assets, move_asset, upload_asset
+from ..style_delete import delete_style
+from ..style_diff import diff_connection_style, diff_connection_styles, diff_local_style
+from ..style_import import StyleFile, count_results, fetch_style, import_styles
+from .base import DataDirDiffMixin, created_response, get_if_absent_param, handle_geoserver_error
 
 
-class StyleListView(APIView):
+def _package_response(message: str, style_file: StyleFile) -> dict:
+    """Describe an uploaded style package, warning about graphics it lacks."""
+    response: dict = {"message": message}
+    if style_file.missing_graphics:
+        response["missingGraphics"] = style_file.missing_graphics
+    return response
+
+
+def _in_sld_version(content: str, style_format: str, version: str | None) -> str:
+    """Convert SLD content to the version asked for, if any.
+
+    Raises:
+        ValueError: If the version is unknown or the content can't be converted
+    """
+    if style_format != "sld" or not version:
+        return content
+    return convert_sld(content, version)
+
+
+class StyleListView(DataDirDiffMixin, APIView):
     """List and create styles in a workspace."""
 
     def get(self, request, conn_id, workspace):
@@ -23,33 +49,62 @@ class StyleListView(APIView):
             return Response([])
 
     def post(self, request, conn_id, workspace):
-        """Create a new style."""
+        """Create a new style.
+
+        The content is given directly, or as a url (with an optional
+        authorization header) the style is downloaded from; the name then
+        defaults to the file name in the URL and the format to its extension.
+        A zipped style package at the url is uploaded with its graphics.
+        An SLD is converted to the sldVersion given ("1.0.0" or "1.1.0"),
+        and kept in its own version otherwise. With "ifAbsent" an existing
+        style keeps its content.
+        """
         try:
             client = get_geoserver_client(conn_id)
             name = request.data.get("name")
             content = request.data.get("content")
             style_format = request.data.get("format", "sld")
 
+            url = request.data.get("url")
+            if url and not content:
+                try:
+                    style_file = fetch_style(url, request.data.get("authorization"))
+                except ValueError as e:
+                    return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+                name = name or style_file.name
+                if style_file.package is not None:
+                    validate_name("style", name, workspace)
+                    client.upload_style_package(name, style_file.package, workspace)
+                    return Response(
+                        _package_response(f"Style {name} created", style_file),
+                        status=status.HTTP_201_CREATED,
+                    )
+                content, style_format = style_file.content, style_file.format
+
             if not name or not content:
                 return Response(
-                    {"error": "name and content are required"},
+                    {"error": "name and content (or url) are required"},
                     status=status.HTTP_400_BAD_REQUEST,
                 )
+            validate_name("style", name, workspace)
+            try:
+                content = _in_sld_version(content, style_format, request.data.get("sldVersion"))
+            except ValueError as e:
+                return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
 
-            client.create_style(name, content, style_format, workspace)
-            return Response(
-                {"message": f"Style {name} created"},
-                status=status.HTTP_201_CREATED,
+            result = client.create_style(
+                name, content, style_format, workspace, if_absent=get_if_absent_param(request)
             )
+            return created_response(result, f"Style {name}")
         except GeoServerError as e:
             return handle_geoserver_error(e)
 
 
-class StyleDetailView(APIView):
+class StyleDetailView(DataDirDiffMixin, APIView):
     """Get, update, or delete a style."""
 
     def get(self, request, conn_id, workspace, style):
-        """Get style details and content."""
+        """Get style details and content, with the SLD version of an SLD."""
         try:
             client = get_geoserver_client(conn_id)
             style_info = client.get_style(style, workspace)
@@ -58,34 +113,286 @@ class StyleDetailView(APIView):
                 "style": style_info,
                 "content": content,
                 "format": style_format,
+                "sldVersion": detect_sld_version(content) if style_format == "sld" else None,
             })
         except GeoServerError as e:
             return handle_geoserver_error(e)
 
     def put(self, request, conn_id, workspace, style):
-        """Update style content."""
+        """Update style content.
+
+        The response lists the layers rendering with this style so the
+        client can offer to truncate their caches; when the connection has
+        auto-truncate enabled they are truncated straight away. Like on
+        create, the content can be downloaded from a url instead, and an SLD
+        is converted to the sldVersion given.
+        """
         try:
             client = get_geoserver_client(conn_id)
             content = request.data.get("content")
             style_format = request.data.get("format", "sld")
 
+            url = request.data.get("url")
+            if url and not content:
+                try:
+                    style_file = fetch_style(url, request.data.get("authorization"))
+                except ValueError as e:
+                    return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+                if style_file.package is not None:
+                    client.upload_style_package(style, style_file.package, workspace, exists=True)
+                    affected, truncated = invalidate_style_cache(conn_id, workspace, style)
+                    response = _package_response("Style updated", style_file)
+                    response.update({"affectedLayers": affected, "truncatedLayers": truncated})
+                    return Response(response)
+                content, style_format = style_file.content, style_file.format
+
             if not content:
                 return Response(
-                    {"error": "content is required"},
+                    {"error": "content (or url) is required"},
                     status=status.HTTP_400_BAD_REQUEST,
                 )
+            try:
+                content = _in_sld_version(content, style_format, request.data.get("sldVersion"))
+            except ValueError as e:
+                return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
 
             client.update_style_content(style, content, style_format, workspace)
-            return Response({"message": "Style updated"})
+            affected, truncated = invalidate_style_cache(conn_id, workspace, style)
+            return Response(
+                {
+                    "message": "Style updated",
+                    "affectedLayers": affected,
+                    "truncatedLayers": truncated,
+                }
+            )
         except GeoServerError as e:
             return handle_geoserver_error(e)
 
     def delete(self, request, conn_id, workspace, style):
-        """Delete a style."""
+        """Delete a style, optionally reassigning the layers that use it.
+
+        Query parameters:
+        - purge: Also remove the style file from disk
+        - reassignTo: Style given to the layers that use this one, in the
+          same role (default or additional); without it they fall back to
+          GeoServer's default style
+
+        When layers used the style, the response lists them, which of them
+        were reassigned and which tile caches were truncated.
+        """
+        purge = request.query_params.get("purge", "false").lower() == "true"
+        reassign_to = request.query_params.get("reassignTo", "").strip()
         try:
-            client = get_geoserver_client(conn_id)
-            purge = request.query_params.get("purge", "false").lower() == "true"
-            client.delete_style(style, workspace, purge=purge)
+            deletion = delete_style(conn_id, workspace, style, purge, reassign_to)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+        if not deletion.affected:
             return Response(status=status.HTTP_204_NO_CONTENT)
+        return Response(deletion.to_dict())
+
+
+class StyleImportView(APIView):
+    """Import several style files at once."""
+
+    def post(self, request, conn_id):
+        """Create or update a style for each uploaded .sld, .css or .zip file.
+
+        Multipart body:
+        - files: Style files, each named after its style (roads.sld -> roads);
+          a .zip is a style package of an SLD and its graphics
+        - workspace: Workspace to import into; global styles when empty
+
+        Styles whose content didn't change are skipped. The response lists
+        the outcome of each file, with a warning for packages missing
+        graphics their SLD refers to, and counts them.
+        """
+        uploads = request.FILES.getlist("files")
+        if not uploads:
+            return Response(
+                {"error": "files are required"}, status=status.HTTP_400_BAD_REQUEST
+            )
+
+        try:
+            files = [StyleFile.from_bytes(f.name, f.read()) for f in uploads]
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        workspace = (request.data.get("workspace") or "").strip() or None
+        try:
+            results = import_styles(client, files, workspace)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response(
+            {
+                "results": [r.to_dict() for r in results],
+                "counts": count_results(results),
+            }
+        )
+
+
+class StyleDiffView(APIView):
+    """Show what replacing a connection's styles would change."""
+
+    def get(self, request, conn_id):
+        """Compare another connection's styles with this one's, as a sync would copy them.
+
+        Query parameters:
+        - against: ID of the connection the styles would be copied to
+        - workspace: Workspace of the styles; global styles when empty
+        - name: Only this style; every style of the workspace by default
+
+        The destination's version is the old side of each diff.
+        """
+        against = request.query_params.get("against", "")
+        if not against:
+            return Response({"error": "against is required"}, status=status.HTTP_400_BAD_REQUEST)
+        workspace = request.query_params.get("workspace") or None
+        name = request.query_params.get("name")
+
+        try:
+            source = get_geoserver_client(conn_id)
+            dest = get_geoserver_client(against)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        try:
+            if name:
+                diffs = [diff_connection_style(source, dest, name, workspace)]
+            else:
+                diffs = diff_connection_styles(source, dest, workspace)
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response({"diffs": [d.to_dict() for d in diffs]})
+
+    def post(self, request, conn_id):
+        """Compare uploaded style files with the server's styles they would replace.
+
+        Multipart body:
+        - files: Style files, each named after its style, as for an import
+        - workspace: Workspace of the styles; global styles when empty
+        """
+        uploads = request.FILES.getlist("files")
+        if not uploads:
+            return Response(
+                {"error": "files are required"}, status=status.HTTP_400_BAD_REQUEST
+            )
+
+        try:
+            files = [StyleFile.from_bytes(f.name, f.read()) for f in uploads]
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        workspace = (request.data.get("workspace") or "").strip() or None
+        try:
+            diffs = [diff_local_style(client, f, workspace) for f in files]
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response({"diffs": [d.to_dict() for d in diffs]})
+
+
+class StyleAssetListView(APIView):
+    """List and upload the graphics kept next to styles."""
+
+    def get(self, request, conn_id):
+        """List the graphics of a styles folder and the styles using each.
+
+        Query parameters:
+        - workspace: Workspace whose styles folder is listed; the global one when empty
+
+        References no graphic in the folder answers, or that name absolute
+        file paths which won't exist on other servers, are listed as unresolved.
+        """
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        workspace = (request.query_params.get("workspace") or "").strip() or None
+        try:
+            return Response(list_assets(client, workspace).to_dict())
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+
+    def post(self, request, conn_id):
+        """Upload PNG or SVG graphics into a styles folder.
+
+        Multipart body:
+        - files: Graphics, replacing those of the same name
+        - folder: Optional subfolder of the styles folder, e.g. icons
+        - workspace: Workspace whose styles folder they go into; global when empty
+        """
+        uploads = request.FILES.getlist("files")
+        if not uploads:
+            return Response(
+                {"error": "files are required"}, status=status.HTTP_400_BAD_REQUEST
+            )
+
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        workspace = (request.data.get("workspace") or "").strip() or None
+        folder = (request.data.get("folder") or "").strip().strip("/")
+        uploaded = []
+        try:
+            for upload in uploads:
+                path = f"{folder}/{upload.name}" if folder else upload.name
+                uploaded.append(upload_asset(client, path, upload.read(), workspace))
+        except ValueError as e:
+            return Response(
+                {"error": str(e), "uploaded": uploaded}, status=status.HTTP_400_BAD_REQUEST
+            )
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
+        return Response({"uploaded": uploaded}, status=status.HTTP_201_CREATED)
+
+
+class StyleAssetMoveView(APIView):
+    """Move a graphic and update the styles referring to it."""
+
+    def post(self, request, conn_id):
+        """Move a graphic within its styles folder.
+
+        Request body:
+        - source: Path of the graphic relative to the styles folder
+        - target: Its new path
+        - workspace: Workspace whose styles folder holds it; global when empty
+
+        The response lists the styles updated to the new path and those
+        that failed to update.
+        """
+        source = (request.data.get("source") or "").strip()
+        target = (request.data.get("target") or "").strip()
+        if not source or not target:
+            return Response(
+                {"error": "source and target are required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        workspace = (request.data.get("workspace") or "").strip() or None
+        try:
+            move = move_asset(client, source, target, workspace)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
         except GeoServerError as e:
             return handle_geoserver_error(e)
+        return Response(move.to_dict())
@@ -5,8 +5,10 @@ from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.exceptions import GeoServerError
+from apps.gwc.invalidation import invalidate_store_cache
 
 from ..client import get_geoserver_client
+from ..naming import validate_name
 from .base import handle_geoserver_error
 
 
@@ -34,9 +36,11 @@ class UploadShapefileView(APIView):
                     status=status.HTTP_400_BAD_REQUEST,
                 )
 
+            validate_name("datastore", store_name, workspace)
             client.upload_shapefile(workspace, store_name, file.read(), charset)
+            truncated = invalidate_store_cache(conn_id, workspace, store_name, "datastore")
             return Response(
-                {"message": f"Shapefile uploaded as {store_name}"},
+                {"message": f"Shapefile uploaded as {store_name}", "truncatedLayers": truncated},
                 status=status.HTTP_201_CREATED,
             )
         except GeoServerError as e:
@@ -66,9 +70,11 @@ class UploadGeoTiffView(APIView):
                     status=status.HTTP_400_BAD_REQUEST,
                 )
 
+            validate_name("coveragestore", store_name, workspace)
             client.upload_geotiff(workspace, store_name, file.read())
+            truncated = invalidate_store_cache(conn_id, workspace, store_name, "coveragestore")
             return Response(
-                {"message": f"GeoTIFF uploaded as {store_name}"},
+                {"message": f"GeoTIFF uploaded as {store_name}", "truncatedLayers": truncated},
                 status=status.HTTP_201_CREATED,
             )
         except GeoServerError as e:
@@ -98,9 +104,11 @@ class UploadGeoPackageView(APIView):
                     status=status.HTTP_400_BAD_REQUEST,
                 )
 
+            validate_name("datastore", store_name, workspace)
             client.upload_geopackage(workspace, store_name, file.read())
+            truncated = invalidate_store_cache(conn_id, workspace, store_name, "datastore")
             return Response(
-                {"message": f"GeoPackage uploaded as {store_name}"},
+                {"message": f"GeoPackage uploaded as {store_name}", "truncatedLayers": truncated},
                 status=status.HTTP_201_CREATED,
             )
         except GeoServerError as e:
@@ -5,14 +5,34 @@ from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.exceptions import GeoServerError
+from apps.gwc.invalidation import cached_workspace_layers, clear_workspace_caches
 
 from ..client import get_geoserver_client
-from .base import get_recurse_param, handle_geoserver_error
+from ..impact import workspace_impact
+from ..naming import validate_name
+from ..workspace_templates import (
+    create_workspace_from_template,
+    get_template,
+    list_templates,
+    template_to_dict,
+)
+from .base import (
+    DataDirDiffMixin,
+    created_response,
+    get_if_absent_param,
+    get_recurse_param,
+    handle_geoserver_error,
+)
 
 
-class WorkspaceListView(APIView):
+class WorkspaceListView(DataDirDiffMixin, APIView):
     """List and create workspaces."""
 
+    def datadir_folders(self, request, kwargs):
+        """The new workspace's folder, and workspaces/ for its default.xml."""
+        name = request.data.get("name")
+        return ([f"workspaces/{name}"] if name else []), ["workspaces"]
+
     def get(self, request, conn_id):
         """List all workspaces."""
         try:
@@ -23,31 +43,79 @@ class WorkspaceListView(APIView):
             return Response([])
 
     def post(self, request, conn_id):
-        """Create a new workspace."""
+        """Create a new workspace.
+
+        With "ifAbsent" an existing workspace is kept rather than refused;
+        a template then only adds the stores and styles it doesn't have yet.
+        """
         try:
             client = get_geoserver_client(conn_id)
             name = request.data.get("name")
             isolated = request.data.get("isolated", False)
             default = request.data.get("default", False)
+            if_absent = get_if_absent_param(request)
 
             if not name:
                 return Response(
                     {"error": "name is required"},
                     status=status.HTTP_400_BAD_REQUEST,
                 )
+            validate_name("workspace", name)
+
+            template_name = request.data.get("template")
+            if template_name:
+                template = get_template(template_name)
+                if template is None:
+                    return Response(
+                        {"error": f"Unknown workspace template: {template_name}"},
+                        status=status.HTTP_400_BAD_REQUEST,
+                    )
+                steps = create_workspace_from_template(
+                    client,
+                    name,
+                    template,
+                    isolated=request.data.get("isolated"),
+                    services=request.data.get("services"),
+                    if_absent=if_absent,
+                )
+                existed = steps[0].existed
+                verb = "set up" if existed else "created"
+                return Response(
+                    {
+                        "message": f"Workspace {name} {verb} from {template.name}",
+                        "alreadyExists": existed,
+                        "steps": [step.to_dict() for step in steps],
+                    },
+                    status=status.HTTP_200_OK if existed else status.HTTP_201_CREATED,
+                )
 
-            client.create_workspace(name, isolated=isolated, default=default)
-            return Response(
-                {"message": f"Workspace {name} created"},
-                status=status.HTTP_201_CREATED,
+            result = client.create_workspace(
+                name, isolated=isolated, default=default, if_absent=if_absent
             )
+            return created_response(result, f"Workspace {name}")
         except GeoServerError as e:
             return handle_geoserver_error(e)
 
 
-class WorkspaceDetailView(APIView):
+class WorkspaceTemplateListView(APIView):
+    """List the templates workspaces can be created from."""
+
+    def get(self, request):
+        """List the configured and built-in workspace templates."""
+        return Response([template_to_dict(t) for t in list_templates()])
+
+
+class WorkspaceDetailView(DataDirDiffMixin, APIView):
     """Get, update, or delete a workspace."""
 
+    def datadir_folders(self, request, kwargs):
+        """The workspace's folder, its new one when renamed, and workspaces/."""
+        folders = [f"workspaces/{kwargs['workspace']}"]
+        new_name = request.data.get("name")
+        if new_name and new_name != kwargs["workspace"]:
+            folders.append(f"workspaces/{new_name}")
+        return folders, ["workspaces"]
+
     def get(self, request, conn_id, workspace):
         """Get workspace details."""
         try:
@@ -63,6 +131,8 @@ class WorkspaceDetailView(APIView):
             client = get_geoserver_client(conn_id)
             new_name = request.data.get("name")
             isolated = request.data.get("isolated")
+            if new_name and new_name != workspace:
+                validate_name("workspace", new_name)
 
             client.update_workspace(workspace, new_name=new_name, isolated=isolated)
             return Response({"message": "Workspace updated"})
@@ -70,11 +140,34 @@ class WorkspaceDetailView(APIView):
             return handle_geoserver_error(e)
 
     def delete(self, request, conn_id, workspace):
-        """Delete a workspace."""
+        """Delete a workspace.
+
+        Recursive deletes clear the tile caches of the contained layers
+        first, while GeoWebCache still knows about them.
+        """
         try:
             client = get_geoserver_client(conn_id)
             recurse = get_recurse_param(request)
+            if recurse:
+                clear_workspace_caches(conn_id, workspace)
             client.delete_workspace(workspace, recurse=recurse)
             return Response(status=status.HTTP_204_NO_CONTENT)
         except GeoServerError as e:
             return handle_geoserver_error(e)
+
+
+class WorkspaceImpactView(APIView):
+    """Report what a recursive workspace delete would remove."""
+
+    def get(self, request, conn_id, workspace):
+        """List the stores, layers, styles, layer groups and caches in a workspace."""
+        try:
+            client = get_geoserver_client(conn_id)
+            try:
+                cached = cached_workspace_layers(conn_id, workspace)
+            except GeoServerError:
+                # GWC may be disabled; the rest of the report still applies
+                cached = []
+            return Response(workspace_impact(client, workspace, cached).to_dict())
+        except GeoServerError as e:
+            return handle_geoserver_error(e)
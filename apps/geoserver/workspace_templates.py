@@ -0,0 +1,183 @@
+"""Workspace templates.
+
+Teams that start a workspace per project tend to set each one up the same
+way: a PostGIS store for the project's schema, the house styles, only the
+OGC services they publish and their contact details. A template, named in
+the config file's "workspace_templates", records that setup once; a new
+workspace created from it gets all of it in one step. "standard project"
+is built in and can be replaced by a template of the same name.
+"""
+
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Any
+
+from apps.core.config import TemplateDataStore, WorkspaceTemplate, config_manager
+from apps.core.exceptions import GeoServerError
+
+from .client import CreateResult
+
+if TYPE_CHECKING:
+    from .client import GeoServerClient
+
+# Services a template can turn on or off per workspace
+SERVICES = ("wms", "wfs", "wcs", "wmts")
+
+# Placeholder for the workspace name in store connection parameters
+WORKSPACE_PLACEHOLDER = "{workspace}"
+
+BUILTIN_TEMPLATES = [
+    WorkspaceTemplate(
+        name="standard project",
+        description="PostGIS store for the project's schema, default styles, WMS and WFS",
+        services=["wms", "wfs"],
+        datastores=[
+            TemplateDataStore(
+                name="postgis",
+                description="Project schema; fill in the password, then enable",
+                connection_parameters={
+                    "dbtype": "postgis",
+                    "host": "localhost",
+                    "port": "5432",
+                    "database": "gis",
+                    "schema": WORKSPACE_PLACEHOLDER,
+                    "user": "geoserver",
+                    "passwd": "",
+                },
+            )
+        ],
+        styles=["point", "line", "polygon"],
+    ),
+]
+
+
+@dataclass
+class TemplateStep:
+    """Outcome of one step of setting up a workspace from a template."""
+
+    kind: str  # "workspace", "service", "contact", "datastore" or "style"
+    name: str
+    error: str = ""
+    existed: bool = False  # Left as it was, having been created before
+
+    @property
+    def ok(self) -> bool:
+        """Whether the step succeeded."""
+        return not self.error
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        data: dict[str, Any] = {"kind": self.kind, "name": self.name, "ok": self.ok}
+        if self.error:
+            data["error"] = self.error
+        if self.existed:
+            data["existed"] = True
+        return data
+
+
+def list_templates() -> list[WorkspaceTemplate]:
+    """List the configured templates, then the built-in ones not replaced by them."""
+    configured = list(config_manager.config.workspace_templates)
+    names = {template.name for template in configured}
+    return configured + [t for t in BUILTIN_TEMPLATES if t.name not in names]
+
+
+def get_template(name: str) -> WorkspaceTemplate | None:
+    """Get a template by name."""
+    return next((t for t in list_templates() if t.name == name), None)
+
+
+def template_to_dict(template: WorkspaceTemplate) -> dict[str, Any]:
+    """Serialize a template for API responses, without store passwords."""
+    return {
+        "name": template.name,
+        "description": template.description,
+        "isolated": template.isolated,
+        "services": template.services,
+        "contact": template.contact,
+        "datastores": [store.name for store in template.datastores],
+        "styles": template.styles,
+    }
+
+
+def _fill(value: str, workspace: str) -> str:
+    return value.replace(WORKSPACE_PLACEHOLDER, workspace)
+
+
+def create_workspace_from_template(
+    client: "GeoServerClient",
+    name: str,
+    template: WorkspaceTemplate,
+    isolated: bool | None = None,
+    services: list[str] | None = None,
+    if_absent: bool = False,
+) -> list[TemplateStep]:
+    """Create a workspace and set it up as a template describes.
+
+    Once the workspace exists, a failed step is recorded and the rest
+    still run, so one missing style doesn't leave the workspace half done.
+    With if_absent the template can be applied again, say after a timeout:
+    the workspace, stores and styles already there are left as they are.
+
+    Args:
+        client: GeoServer client
+        name: Workspace name
+        template: Template to apply
+        isolated: Whether the workspace is isolated; the template decides when omitted
+        services: Services to enable instead of the template's
+        if_absent: Keep an existing workspace, stores and styles instead of failing
+
+    Returns:
+        One step per thing set up, starting with the workspace
+
+    Raises:
+        GeoServerError: If the workspace itself can't be created
+    """
+    created = client.create_workspace(
+        name,
+        isolated=template.isolated if isolated is None else isolated,
+        if_absent=if_absent,
+    )
+    steps = [TemplateStep("workspace", name, existed=created == CreateResult.ALREADY_EXISTS)]
+
+    def run(kind: str, step_name: str, action: Any, *args: Any, **kwargs: Any) -> None:
+        try:
+            result = action(*args, **kwargs)
+        except GeoServerError as e:
+            steps.append(TemplateStep(kind, step_name, e.message))
+        else:
+            steps.append(
+                TemplateStep(kind, step_name, existed=result == CreateResult.ALREADY_EXISTS)
+            )
+
+    enabled = template.services if services is None else services
+    for service in SERVICES:
+        run("service", service, client.update_workspace_service, name, service, service in enabled)
+
+    if template.contact:
+        run("contact", name, client.update_workspace_contact, name, template.contact)
+
+    for store in template.datastores:
+        params = {k: _fill(v, name) for k, v in store.connection_parameters.items()}
+        run(
+            "datastore",
+            store.name,
+            client.create_datastore,
+            name,
+            store.name,
+            params,
+            description=store.description,
+            enabled=store.enabled,
+            if_absent=if_absent,
+        )
+
+    for style in template.styles:
+
+        def copy_style(style: str = style) -> CreateResult:
+            content, style_format = client.get_style_content(style)
+            return client.create_style(
+                style, content, style_format, workspace=name, if_absent=if_absent
+            )
+
+        run("style", style, copy_style)
+
+    return steps
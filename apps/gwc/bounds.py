@@ -0,0 +1,151 @@
+"""Seed bounds drawn on a map or typed in, converted to a grid set's SRS.
+
+GeoWebCache expects a seed request's bounds in the SRS of the grid set
+being seeded. Areas are picked in lon/lat, so they're converted first:
+Web Mercator is projected directly, and any other SRS through GDAL's
+gdaltransform, sampling along the edges since straight lines in lon/lat
+bend in most projections.
+"""
+
+import math
+import subprocess
+from typing import TYPE_CHECKING, Any
+
+from .estimate import EARTH_RADIUS, WEB_MERCATOR_SRS, lonlat_to_web_mercator, parse_gridset
+
+if TYPE_CHECKING:
+    from .client import GWCClient
+
+Bounds = tuple[float, float, float, float]
+
+# SRS the map preview and typed coordinates use
+LONLAT_SRS = "EPSG:4326"
+
+# Points sampled along each edge when converting through GDAL
+EDGE_SAMPLES = 10
+
+# Seconds to wait for gdaltransform
+TRANSFORM_TIMEOUT = 30
+
+
+def parse_bounds(value: Any) -> Bounds:
+    """Read bounds given as [minx, miny, maxx, maxy] or {"minX": ..., "maxY": ...}.
+
+    Raises:
+        ValueError: If the bounds are malformed or empty
+    """
+    if isinstance(value, dict):
+        value = [value.get(key) for key in ("minX", "minY", "maxX", "maxY")]
+    try:
+        minx, miny, maxx, maxy = (float(v) for v in value)
+    except (TypeError, ValueError):
+        raise ValueError("bounds must have 4 numbers: minx, miny, maxx, maxy") from None
+    if not all(math.isfinite(v) for v in (minx, miny, maxx, maxy)):
+        raise ValueError("bounds must be finite numbers")
+    if minx >= maxx or miny >= maxy:
+        raise ValueError("bounds must have minx < maxx and miny < maxy")
+    return minx, miny, maxx, maxy
+
+
+def _normalize_srs(srs: str) -> str:
+    srs = srs.strip().upper()
+    if srs in ("CRS:84", "EPSG:4326"):
+        return LONLAT_SRS
+    if srs in WEB_MERCATOR_SRS:
+        return "EPSG:3857"
+    return srs
+
+
+def web_mercator_to_lonlat(bounds: Bounds) -> Bounds:
+    """Unproject Web Mercator bounds to lon/lat degrees."""
+
+    def unproject(x: float, y: float) -> tuple[float, float]:
+        lon = math.degrees(x / EARTH_RADIUS)
+        lat = math.degrees(2 * math.atan(math.exp(y / EARTH_RADIUS)) - math.pi / 2)
+        return lon, lat
+
+    minx, miny = unproject(bounds[0], bounds[1])
+    maxx, maxy = unproject(bounds[2], bounds[3])
+    return minx, miny, maxx, maxy
+
+
+def _edge_points(bounds: Bounds) -> list[tuple[float, float]]:
+    """Sample points along the edges of bounds."""
+    minx, miny, maxx, maxy = bounds
+    points = []
+    for i in range(EDGE_SAMPLES + 1):
+        x = minx + (maxx - minx) * i / EDGE_SAMPLES
+        y = miny + (maxy - miny) * i / EDGE_SAMPLES
+        points += [(x, miny), (x, maxy), (minx, y), (maxx, y)]
+    return points
+
+
+def _gdal_transform(bounds: Bounds, source: str, target: str) -> Bounds:
+    """Convert bounds between any two SRS with gdaltransform."""
+    points = "".join(f"{x} {y}\n" for x, y in _edge_points(bounds))
+    try:
+        result = subprocess.run(
+            ["gdaltransform", "-s_srs", source, "-t_srs", target],
+            input=points,
+            capture_output=True,
+            text=True,
+            timeout=TRANSFORM_TIMEOUT,
+        )
+    except FileNotFoundError:
+        raise ValueError(
+            f"Converting bounds to {target} needs GDAL's gdaltransform; "
+            f"enter them in {target} instead"
+        ) from None
+    except subprocess.TimeoutExpired:
+        raise ValueError(f"Converting bounds to {target} timed out") from None
+    if result.returncode != 0:
+        raise ValueError(f"Could not convert bounds to {target}: {result.stderr.strip()}")
+
+    xs, ys = [], []
+    for line in result.stdout.splitlines():
+        parts = line.split()
+        if len(parts) >= 2:
+            x, y = float(parts[0]), float(parts[1])
+            if math.isfinite(x) and math.isfinite(y):
+                xs.append(x)
+                ys.append(y)
+    if not xs:
+        raise ValueError(f"The bounds lie outside the area {target} covers")
+    return min(xs), min(ys), max(xs), max(ys)
+
+
+def transform_bounds(bounds: Bounds, source_srs: str, target_srs: str) -> Bounds:
+    """Convert bounds from one SRS to another.
+
+    Args:
+        bounds: (minx, miny, maxx, maxy) in source_srs
+        source_srs: SRS of the bounds, e.g. "EPSG:4326"
+        target_srs: SRS of the grid set being seeded
+
+    Returns:
+        The smallest bounds in target_srs covering the given bounds
+
+    Raises:
+        ValueError: If the bounds can't be converted
+    """
+    source, target = _normalize_srs(source_srs), _normalize_srs(target_srs)
+    if source == target:
+        return bounds
+    if source == LONLAT_SRS and target == "EPSG:3857":
+        return lonlat_to_web_mercator(bounds)
+    if source == "EPSG:3857" and target == LONLAT_SRS:
+        return web_mercator_to_lonlat(bounds)
+    return _gdal_transform(bounds, source, target)
+
+
+def gridset_bounds(
+    gwc_client: "GWCClient", grid_set: str, bounds: Bounds, srs: str = LONLAT_SRS
+) -> Bounds:
+    """Convert bounds to the SRS of a grid set, for a seed request.
+
+    Raises:
+        ValueError: If the bounds can't be converted
+        GeoServerError: If the grid set can't be read
+    """
+    gridset = parse_gridset(gwc_client.get_gridset(grid_set))
+    return transform_bounds(bounds, srs, gridset.srs or grid_set)
@@ -1,15 +1,25 @@
 """GeoWebCache REST API client.
 
 Provides operations for tile cache management including seeding and truncation.
+On a cluster, requests go to the primary node, except truncations: each
+node may keep its own tile cache, so those are sent to every node.
 """
 
 from typing import Any
+from xml.sax.saxutils import escape
 
 import httpx
 
 from apps.core.config import Connection
 from apps.core.exceptions import GeoServerError
-from apps.core.managers import client_manager
+from apps.core.managers import TransportOptions, client_manager, compression_headers
+from apps.core.oidc import connection_auth
+from apps.core.read_only import check_writable
+from apps.core.response_limits import read_limited
+
+# Grid sets and formats a layer gets when caching is turned on, as in GeoServer's defaults
+DEFAULT_GRID_SETS = ["EPSG:4326", "EPSG:900913"]
+DEFAULT_FORMATS = ["image/png", "image/jpeg"]
 
 
 class GWCClient:
@@ -24,9 +34,17 @@ class GWCClient:
         self.connection = connection
         self._client = client_manager.get_client(
             f"gwc_{connection.id}",
-            connection.url,
+            connection.primary_url,
             connection.username,
             connection.password,
+            transport_options=TransportOptions.from_connection(connection),
+            auth=connection_auth(connection),
+            headers=compression_headers(connection.http_compression),
+        )
+        self._limiter = client_manager.get_limiter(
+            connection.id,
+            connection.max_concurrent_requests,
+            connection.max_requests_per_second,
         )
 
     def _request(
@@ -46,18 +64,50 @@ class GWCClient:
             httpx.Response
 
         Raises:
-            GeoServerError: If the request fails
+            GeoServerError: If the request fails, or would change a read-only connection
         """
         # Ensure path starts with /gwc/rest
         if not path.startswith("/gwc/rest"):
             path = f"/gwc/rest{path}"
+        check_writable(self.connection, method, path)
+        return self._send(method, path, **kwargs)
 
+    def _send(self, method: str, url: str, **kwargs: Any) -> httpx.Response:
+        """Send a request to a path on the primary, or to a node's full URL."""
         try:
-            response = self._client.request(method, path, **kwargs)
-            return response
+            with self._limiter.slot(), self._client.stream(method, url, **kwargs) as response:
+                return read_limited(response, self.connection.max_response_mb)
         except httpx.HTTPError as e:
             raise GeoServerError(f"GWC HTTP error: {str(e)}")
 
+    def _truncate_request(self, path: str, **kwargs: Any) -> httpx.Response:
+        """POST a truncation to the primary and then to every other node of a cluster.
+
+        Returns:
+            The primary's response; the other nodes are only asked once it succeeded
+
+        Raises:
+            GeoServerError: If a node other than the primary fails
+        """
+        response = self._request("POST", path, **kwargs)
+        if response.status_code >= 400:
+            return response
+
+        failed = []
+        for url in self.connection.secondary_urls:
+            try:
+                node_response = self._send("POST", f"{url}{path}", **kwargs)
+            except GeoServerError as e:
+                failed.append(f"{url}: {e.message}")
+                continue
+            if node_response.status_code >= 400:
+                failed.append(f"{url}: {node_response.status_code} {node_response.text}")
+        if failed:
+            raise GeoServerError(
+                f"Truncated on the primary but not on {'; '.join(failed)}", status_code=502
+            )
+        return response
+
     def _get_json(self, path: str, **kwargs: Any) -> dict[str, Any]:
         """Make a GET request and return JSON response."""
         response = self._request("GET", path, **kwargs)
@@ -69,6 +119,46 @@ class GWCClient:
             )
         return response.json()
 
+    def get_tile_service(self, service: str, params: dict[str, Any]) -> httpx.Response:
+        """Make a GET request to a GWC tile service rather than the REST API.
+
+        Args:
+            service: Tile service path under /gwc/service, e.g. "wmts" or "tms/1.0.0"
+            params: Query parameters
+
+        Returns:
+            httpx.Response, whatever its status
+        """
+        try:
+            with (
+                self._limiter.slot(),
+                self._client.stream("GET", f"/gwc/service/{service}", params=params) as response,
+            ):
+                return read_limited(response, self.connection.max_response_mb)
+        except httpx.HTTPError as e:
+            raise GeoServerError(f"GWC HTTP error: {str(e)}")
+
+    def get_home_page(self) -> str:
+        """Get the GeoWebCache home page, which shows its runtime statistics.
+
+        Returns:
+            The page's HTML
+
+        Raises:
+            GeoServerError: If the page can't be read
+        """
+        try:
+            with self._limiter.slot(), self._client.stream("GET", "/gwc/") as response:
+                page = read_limited(response, self.connection.max_response_mb)
+        except httpx.HTTPError as e:
+            raise GeoServerError(f"GWC HTTP error: {str(e)}")
+        if page.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to read the GWC home page: {page.status_code}",
+                status_code=page.status_code,
+            )
+        return page.text
+
     # === Layers ===
 
     def list_layers(self) -> list[dict[str, Any]]:
@@ -94,6 +184,49 @@ class GWCClient:
         data = self._get_json(f"/gwc/rest/layers/{encoded_name}.json")
         return data.get("GeoServerLayer", {})
 
+    def enable_layer(
+        self,
+        layer_name: str,
+        grid_sets: list[str] | None = None,
+        formats: list[str] | None = None,
+    ) -> dict[str, Any]:
+        """Turn on tile caching for a layer or layer group that isn't cached yet.
+
+        Args:
+            layer_name: Full layer or layer group name (workspace:name)
+            grid_sets: Grid sets to cache; GeoServer's defaults when omitted
+            formats: Tile formats to cache; PNG and JPEG when omitted
+
+        Returns:
+            Status dictionary
+        """
+        encoded_name = layer_name.replace(":", "%3A")
+        mime_formats = "".join(f"<string>{escape(f)}</string>" for f in formats or DEFAULT_FORMATS)
+        subsets = "".join(
+            f"<gridSubset><gridSetName>{escape(g)}</gridSetName></gridSubset>"
+            for g in grid_sets or DEFAULT_GRID_SETS
+        )
+        payload = (
+            f"<GeoServerLayer><enabled>true</enabled><name>{escape(layer_name)}</name>"
+            f"<mimeFormats>{mime_formats}</mimeFormats>"
+            f"<gridSubsets>{subsets}</gridSubsets></GeoServerLayer>"
+        )
+
+        response = self._request(
+            "PUT",
+            f"/gwc/rest/layers/{encoded_name}.xml",
+            content=payload,
+            headers={"Content-Type": "text/xml"},
+        )
+
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to enable tile caching: {response.text}",
+                status_code=response.status_code,
+            )
+
+        return {"status": "enabled", "layer": layer_name}
+
     # === Seeding ===
 
     def seed_layer(
@@ -105,6 +238,7 @@ class GWCClient:
         format: str = "image/png",
         num_threads: int = 4,
         seed_type: str = "seed",
+        bounds: tuple[float, float, float, float] | None = None,
     ) -> dict[str, Any]:
         """Start a seeding task for a layer.
 
@@ -116,6 +250,8 @@ class GWCClient:
             format: Tile format (image/png, image/jpeg, etc.)
             num_threads: Number of seeding threads
             seed_type: Type of operation (seed, reseed, truncate)
+            bounds: Optional (minx, miny, maxx, maxy) in the grid set's SRS
+                to seed only part of the layer
 
         Returns:
             Task ID dictionary
@@ -133,6 +269,8 @@ class GWCClient:
                 "threadCount": num_threads,
             }
         }
+        if bounds:
+            payload["seedRequest"]["bounds"] = {"coords": {"double": list(bounds)}}
 
         response = self._request(
             "POST",
@@ -186,11 +324,7 @@ class GWCClient:
 
         payload = {"seedRequest": seed_request}
 
-        response = self._request(
-            "POST",
-            f"/gwc/rest/seed/{encoded_name}.json",
-            json=payload,
-        )
+        response = self._truncate_request(f"/gwc/rest/seed/{encoded_name}.json", json=payload)
 
         if response.status_code >= 400:
             raise GeoServerError(
@@ -200,6 +334,68 @@ class GWCClient:
 
         return {"status": "truncated", "layer": layer_name}
 
+    def truncate_entire_layer(self, layer_name: str) -> dict[str, Any]:
+        """Truncate every cached tile of a layer across all grid sets and formats.
+
+        Args:
+            layer_name: Full layer name (workspace:layer)
+
+        Returns:
+            Status dictionary
+        """
+        payload = f"<truncateLayer><layerName>{escape(layer_name)}</layerName></truncateLayer>"
+
+        response = self._truncate_request(
+            "/gwc/rest/masstruncate",
+            content=payload,
+            headers={"Content-Type": "text/xml"},
+        )
+
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to truncate layer cache: {response.text}",
+                status_code=response.status_code,
+            )
+
+        return {"status": "truncated", "layer": layer_name}
+
+    def truncate_layer_parameters(
+        self,
+        layer_name: str,
+        parameters: dict[str, str],
+    ) -> dict[str, Any]:
+        """Truncate the cached tiles of a layer for one set of parameter filters.
+
+        Args:
+            layer_name: Full layer name (workspace:layer)
+            parameters: Parameter filter values, e.g. {"STYLES": "topp:roads"}
+
+        Returns:
+            Status dictionary
+        """
+        entries = "".join(
+            f"<entry><string>{escape(key)}</string><string>{escape(value)}</string></entry>"
+            for key, value in parameters.items()
+        )
+        payload = (
+            f"<truncateParameters><layerName>{escape(layer_name)}</layerName>"
+            f"<parameters>{entries}</parameters></truncateParameters>"
+        )
+
+        response = self._truncate_request(
+            "/gwc/rest/masstruncate",
+            content=payload,
+            headers={"Content-Type": "text/xml"},
+        )
+
+        if response.status_code >= 400:
+            raise GeoServerError(
+                f"Failed to truncate layer cache: {response.text}",
+                status_code=response.status_code,
+            )
+
+        return {"status": "truncated", "layer": layer_name, "parameters": parameters}
+
     def get_seed_status(self, layer_name: str) -> list[dict[str, Any]]:
         """Get running seed tasks for a layer.
 
@@ -213,6 +409,15 @@ class GWCClient:
         data = self._get_json(f"/gwc/rest/seed/{encoded_name}.json")
         return data.get("long-array-array", [])
 
+    def list_seed_tasks(self) -> list[list[int]]:
+        """Get the seed, reseed and truncate tasks of all layers.
+
+        Returns:
+            Task statuses as [tiles done, total tiles, seconds left, task id, state]
+        """
+        data = self._get_json("/gwc/rest/seed.json")
+        return data.get("long-array-array", [])
+
     def kill_seed_tasks(self, layer_name: str) -> dict[str, Any]:
         """Kill all running seed tasks for a layer.
 
@@ -284,42 +489,6 @@ class GWCClient:
         except GeoServerError:
             return {"enabled": False, "usage": "unknown"}
 
-    # === Masstruncate ===
-
-    def mass_truncate(
-        self,
-        workspace: str | None = None,
-        layer: str | None = None,
-    ) -> dict[str, Any]:
-        """Mass truncate tiles.
-
-        Args:
-            workspace: Optional workspace to truncate
-            layer: Optional layer pattern to truncate
-
-        Returns:
-            Status dictionary
-        """
-        params = {}
-        if workspace:
-            params["workspace"] = workspace
-        if layer:
-            params["layer"] = layer
-
-        response = self._request(
-            "POST",
-            "/gwc/rest/masstruncate",
-            params=params,
-        )
-
-        if response.status_code >= 400:
-            raise GeoServerError(
-                f"Mass truncate failed: {response.text}",
-                status_code=response.status_code,
-            )
-
-        return {"status": "truncated"}
-
 
 def get_gwc_client(conn_id: str) -> GWCClient:
     """Get a GWC client for a connection.
@@ -0,0 +1,285 @@
+"""Client URLs for a cached layer's tile services, and a check that they work.
+
+GeoWebCache serves each cached layer through WMTS (KVP and REST), TMS and
+WMS-C, and desktop and web clients each want a different one. These
+helpers build ready-to-paste URLs for a layer's grid set and format, and
+check the layer the way a client would: it must be advertised in the
+WMTS capabilities with that grid set and format, and a sample tile must
+come back as an image of the format asked for.
+"""
+
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+from urllib.parse import urlencode
+from xml.etree import ElementTree as ET
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.links import CopyTarget, wmts_capabilities_url
+from apps.geoserver.ows import parse_exception_report
+
+if TYPE_CHECKING:
+    from .client import GWCClient
+
+# File extensions GWC uses in TMS URLs where the MIME subtype differs
+TMS_EXTENSIONS = {
+    "application/vnd.mapbox-vector-tile": "pbf",
+    "application/json;type=geojson": "geojson",
+    "image/vnd.jpeg-png": "jpeg-png",
+    "image/vnd.jpeg-png8": "jpeg-png8",
+}
+
+# Format tried first when the layer caches several
+PREFERRED_FORMAT = "image/png"
+
+WMTS_CAPABILITIES = {"service": "WMTS", "version": "1.0.0", "request": "GetCapabilities"}
+
+
+def _query(params: dict[str, Any]) -> str:
+    # Keep qualified names and URL template placeholders readable
+    return urlencode(params, safe=":,/{}")
+
+
+def tms_extension(tile_format: str) -> str:
+    """Get the file extension GWC's TMS service uses for a tile format."""
+    if tile_format in TMS_EXTENSIONS:
+        return TMS_EXTENSIONS[tile_format]
+    return tile_format.split("/")[-1].split(";")[0]
+
+
+def wmts_tile_params(
+    layer: str, grid_set: str, tile_format: str, matrix: str, row: Any, col: Any
+) -> dict[str, Any]:
+    """Build the query parameters of a WMTS KVP GetTile request."""
+    return {
+        "service": "WMTS",
+        "version": "1.0.0",
+        "request": "GetTile",
+        "layer": layer,
+        "style": "",
+        "tilematrixset": grid_set,
+        "tilematrix": matrix,
+        "tilerow": row,
+        "tilecol": col,
+        "format": tile_format,
+    }
+
+
+def wmts_tile_url(
+    base_url: str, layer: str, grid_set: str, tile_format: str, matrix: str, row: Any, col: Any
+) -> str:
+    """Build a WMTS KVP GetTile URL; row and col may be template placeholders."""
+    params = wmts_tile_params(layer, grid_set, tile_format, matrix, row, col)
+    return f"{base_url.rstrip('/')}/gwc/service/wmts?{_query(params)}"
+
+
+def tile_endpoints(
+    base_url: str, layer: str, grid_set: str, tile_format: str
+) -> list[CopyTarget]:
+    """List the tile service URLs a client can use for a cached layer.
+
+    Args:
+        base_url: Public GeoServer base URL
+        layer: Full layer name (workspace:layer)
+        grid_set: Grid set to request tiles in
+        tile_format: Tile MIME type
+
+    Returns:
+        Capabilities URLs and tile URL templates, WMTS first
+    """
+    base = base_url.rstrip("/")
+    extension = tms_extension(tile_format)
+    tms_layer = f"{base}/gwc/service/tms/1.0.0/{layer}@{grid_set}@{extension}"
+    wmsc_params = {
+        "service": "WMS",
+        "version": "1.1.1",
+        "request": "GetCapabilities",
+        "tiled": "true",
+    }
+    return [
+        CopyTarget("wmts", "WMTS capabilities URL (KVP)", wmts_capabilities_url(base)),
+        CopyTarget(
+            "wmts",
+            "WMTS capabilities URL (REST)",
+            f"{base}/gwc/service/wmts/rest/WMTSCapabilities.xml",
+        ),
+        # WMTS rows count from the top, like XYZ tiles
+        CopyTarget(
+            "xyz",
+            "XYZ tile URL template",
+            wmts_tile_url(base, layer, grid_set, tile_format, f"{grid_set}:{{z}}", "{y}", "{x}"),
+        ),
+        CopyTarget("tms", "TMS capabilities URL", f"{base}/gwc/service/tms/1.0.0"),
+        # TMS rows count from the bottom; {-y} is the flipped row in most clients
+        CopyTarget("tms", "TMS tile URL template", f"{tms_layer}/{{z}}/{{x}}/{{-y}}.{extension}"),
+        CopyTarget(
+            "wmsc", "WMS-C capabilities URL", f"{base}/gwc/service/wms?{_query(wmsc_params)}"
+        ),
+    ]
+
+
+@dataclass
+class WMTSLayer:
+    """What the WMTS capabilities advertise for a layer."""
+
+    formats: list[str] = field(default_factory=list)
+    # Grid set -> (tile matrix, row, col) of a tile within the layer's limits
+    sample_tiles: dict[str, tuple[str, int, int]] = field(default_factory=dict)
+
+
+def _local(tag: str) -> str:
+    return tag.rsplit("}", 1)[-1]
+
+
+def _text(element: ET.Element, name: str) -> str:
+    child = element.find(f"{{*}}{name}")
+    return (child.text or "").strip() if child is not None else ""
+
+
+def parse_wmts_layer(text: str | bytes, layer: str) -> WMTSLayer | None:
+    """Read a layer's formats and grid sets from a WMTS capabilities document.
+
+    Args:
+        text: GetCapabilities response body
+        layer: Full layer name
+
+    Returns:
+        The layer's entry, or None if the layer isn't advertised
+
+    Raises:
+        ValueError: If the body isn't a WMTS capabilities document
+    """
+    try:
+        root = ET.fromstring(text)
+    except ET.ParseError:
+        raise ValueError("The WMTS capabilities aren't valid XML") from None
+    contents = root.find("{*}Contents")
+    if _local(root.tag) != "Capabilities" or contents is None:
+        raise ValueError("The WMTS service didn't return a capabilities document")
+
+    for element in contents.findall("{*}Layer"):
+        if _text(element, "Identifier") != layer:
+            continue
+        found = WMTSLayer(formats=[f.text.strip() for f in element.findall("{*}Format") if f.text])
+        for link in element.findall("{*}TileMatrixSetLink"):
+            grid_set = _text(link, "TileMatrixSet")
+            # GWC lists the tile range of each zoom level; the first is the smallest
+            limits = link.find("{*}TileMatrixSetLimits/{*}TileMatrixLimits")
+            if limits is not None:
+                found.sample_tiles[grid_set] = (
+                    _text(limits, "TileMatrix"),
+                    int(_text(limits, "MinTileRow") or 0),
+                    int(_text(limits, "MinTileCol") or 0),
+                )
+            else:
+                found.sample_tiles[grid_set] = (f"{grid_set}:0", 0, 0)
+        return found
+    return None
+
+
+@dataclass
+class EndpointCheck:
+    """The outcome of checking a cached layer like a tile client would."""
+
+    layer: str
+    grid_set: str = ""
+    format: str = ""
+    grid_sets: list[str] = field(default_factory=list)
+    formats: list[str] = field(default_factory=list)
+    tile_url: str = ""
+    status_code: int | None = None
+    content_type: str = ""
+    size: int = 0
+    issues: list[str] = field(default_factory=list)
+
+    @property
+    def ok(self) -> bool:
+        return not self.issues
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "layer": self.layer,
+            "gridSet": self.grid_set,
+            "format": self.format,
+            "gridSets": self.grid_sets,
+            "formats": self.formats,
+            "tileUrl": self.tile_url,
+            "statusCode": self.status_code,
+            "contentType": self.content_type,
+            "size": self.size,
+            "issues": self.issues,
+            "ok": self.ok,
+        }
+
+
+def check_endpoints(
+    gwc_client: "GWCClient",
+    layer: str,
+    grid_set: str | None = None,
+    tile_format: str | None = None,
+) -> EndpointCheck:
+    """Check a cached layer is advertised and serves a sample tile.
+
+    Args:
+        gwc_client: GWC client
+        layer: Full layer name (workspace:layer)
+        grid_set: Grid set to check; the layer's first when omitted
+        tile_format: Tile format to check; PNG or the layer's first when omitted
+
+    Returns:
+        The check, with an issue for each mismatch or failure found
+
+    Raises:
+        GeoServerError: If the WMTS capabilities can't be read
+    """
+    check = EndpointCheck(layer=layer, grid_set=grid_set or "", format=tile_format or "")
+
+    response = gwc_client.get_tile_service("wmts", WMTS_CAPABILITIES)
+    if response.status_code >= 400:
+        raise GeoServerError(
+            f"Failed to read the WMTS capabilities: {response.text}",
+            status_code=response.status_code,
+        )
+    try:
+        advertised = parse_wmts_layer(response.content, layer)
+    except ValueError as e:
+        raise GeoServerError(str(e), status_code=502) from None
+    if advertised is None:
+        check.issues.append(f"{layer} isn't in the WMTS capabilities; is tile caching enabled?")
+        return check
+
+    check.grid_sets = list(advertised.sample_tiles)
+    check.formats = advertised.formats
+    if not check.grid_set and check.grid_sets:
+        check.grid_set = check.grid_sets[0]
+    if not check.format and check.formats:
+        check.format = PREFERRED_FORMAT if PREFERRED_FORMAT in check.formats else check.formats[0]
+
+    if check.grid_set not in advertised.sample_tiles:
+        cached = ", ".join(check.grid_sets) or "none"
+        check.issues.append(f"Grid set {check.grid_set} isn't cached (cached: {cached})")
+    if check.format not in check.formats:
+        cached = ", ".join(check.formats) or "none"
+        check.issues.append(f"Format {check.format} isn't cached (cached: {cached})")
+    if check.issues:
+        return check
+
+    sample = (layer, check.grid_set, check.format, *advertised.sample_tiles[check.grid_set])
+    check.tile_url = wmts_tile_url(gwc_client.connection.public_base_url, *sample)
+    tile = gwc_client.get_tile_service("wmts", wmts_tile_params(*sample))
+    check.status_code = tile.status_code
+    check.content_type = tile.headers.get("content-type", "").split(";")[0].strip()
+    check.size = len(tile.content)
+
+    if tile.status_code != 200:
+        report = parse_exception_report(tile.content)
+        reason = report.message if report else tile.text[:200]
+        check.issues.append(f"The sample tile failed with HTTP {tile.status_code}: {reason}")
+    elif check.content_type != check.format.split(";")[0]:
+        check.issues.append(
+            f"The sample tile came back as {check.content_type or 'an unknown type'}, "
+            f"not {check.format}"
+        )
+    elif not check.size:
+        check.issues.append("The sample tile was empty")
+    return check
@@ -0,0 +1,389 @@
+"""Tile seeding estimates for GeoWebCache.
+
+Computes how many tiles a seed task will generate for a layer's bounds,
+grid set and zoom range, and an approximate storage footprint, so the
+user can judge the cost of a seed before launching it.
+"""
+
+import math
+from dataclasses import dataclass, field
+from typing import Any
+
+# Rough average tile sizes in bytes, used when no measured value is available
+AVERAGE_TILE_BYTES = {
+    "image/png": 18 * 1024,
+    "image/png8": 9 * 1024,
+    "image/jpeg": 14 * 1024,
+    "image/vnd.jpeg-png": 14 * 1024,
+    "image/vnd.jpeg-png8": 10 * 1024,
+    "image/webp": 10 * 1024,
+    "image/gif": 10 * 1024,
+    "application/vnd.mapbox-vector-tile": 12 * 1024,
+    "application/json;type=geojson": 30 * 1024,
+}
+DEFAULT_TILE_BYTES = 16 * 1024
+
+# GeoWebCache's default OGC standardized pixel size (0.28mm)
+DEFAULT_PIXEL_SIZE = 0.00028
+
+# Web Mercator constants
+WEB_MERCATOR_SRS = {"EPSG:3857", "EPSG:900913", "EPSG:102113", "EPSG:102100"}
+EARTH_RADIUS = 6378137.0
+MAX_MERCATOR_LAT = 85.0511287798066
+
+# Tolerance used when snapping bounds to the tile grid
+GRID_EPSILON = 1e-9
+
+
+@dataclass
+class GridSetDefinition:
+    """The parts of a GWC grid set needed to compute tile ranges."""
+
+    name: str
+    srs: str
+    extent: tuple[float, float, float, float]
+    resolutions: list[float]
+    tile_width: int = 256
+    tile_height: int = 256
+    align_top_left: bool = False
+
+
+@dataclass
+class ZoomEstimate:
+    """Tile range for a single zoom level."""
+
+    zoom: int
+    columns: int
+    rows: int
+
+    @property
+    def tiles(self) -> int:
+        """Number of tiles at this zoom level."""
+        return self.columns * self.rows
+
+    def to_dict(self) -> dict[str, Any]:
+        """Convert to dictionary."""
+        return {
+            "zoom": self.zoom,
+            "columns": self.columns,
+            "rows": self.rows,
+            "tiles": self.tiles,
+        }
+
+
+@dataclass
+class TileRange:
+    """Tile columns and rows covering some bounds at a single zoom level.
+
+    Rows count down from the top of the grid, as in WMTS requests.
+    """
+
+    zoom: int
+    first_column: int
+    last_column: int
+    first_row: int
+    last_row: int
+
+    @property
+    def columns(self) -> int:
+        """Number of tile columns."""
+        return self.last_column - self.first_column + 1
+
+    @property
+    def rows(self) -> int:
+        """Number of tile rows."""
+        return self.last_row - self.first_row + 1
+
+
+@dataclass
+class SeedEstimate:
+    """Estimated tile count and storage for a seed task."""
+
+    grid_set: str
+    format: str
+    bounds: tuple[float, float, float, float]
+    bounds_source: str
+    avg_tile_bytes: int
+    zoom_levels: list[ZoomEstimate] = field(default_factory=list)
+
+    @property
+    def total_tiles(self) -> int:
+        """Total number of tiles across all zoom levels."""
+        return sum(z.tiles for z in self.zoom_levels)
+
+    @property
+    def estimated_bytes(self) -> int:
+        """Approximate storage needed for all tiles."""
+        return self.total_tiles * self.avg_tile_bytes
+
+    def to_dict(self) -> dict[str, Any]:
+        """Convert to dictionary."""
+        return {
+            "gridSet": self.grid_set,
+            "format": self.format,
+            "bounds": list(self.bounds),
+            "boundsSource": self.bounds_source,
+            "avgTileBytes": self.avg_tile_bytes,
+            "totalTiles": self.total_tiles,
+            "estimatedBytes": self.estimated_bytes,
+            "zoomLevels": [z.to_dict() for z in self.zoom_levels],
+        }
+
+
+def _unwrap_list(value: Any) -> list[Any]:
+    """Unwrap GWC's JSON list encodings ({"double": [...]} or a bare list)."""
+    if isinstance(value, dict):
+        for key in ("double", "coords", "float", "string"):
+            if key in value:
+                return _unwrap_list(value[key])
+        return []
+    if isinstance(value, list):
+        return value
+    if value is None:
+        return []
+    return [value]
+
+
+def parse_gridset(data: dict[str, Any]) -> GridSetDefinition:
+    """Parse a grid set as returned by /gwc/rest/gridsets/{name}.json.
+
+    Resolutions are derived from scale denominators when the grid set
+    only defines scales.
+
+    Args:
+        data: Grid set dictionary (the "gridSet" object)
+
+    Returns:
+        GridSetDefinition
+
+    Raises:
+        ValueError: If the grid set lacks an extent or resolutions
+    """
+    srs = data.get("srs", {})
+    if isinstance(srs, dict):
+        number = srs.get("number")
+        srs_name = f"EPSG:{number}" if number is not None else ""
+    else:
+        srs_name = str(srs)
+
+    extent = [float(v) for v in _unwrap_list(data.get("extent", {}))]
+    if len(extent) != 4:
+        raise ValueError(f"Grid set {data.get('name')} has no usable extent")
+
+    resolutions = [float(r) for r in _unwrap_list(data.get("resolutions"))]
+    if not resolutions:
+        scales = [float(s) for s in _unwrap_list(data.get("scaleDenominators"))]
+        meters_per_unit = float(data.get("metersPerUnit") or 1.0)
+        pixel_size = float(data.get("pixelSize") or DEFAULT_PIXEL_SIZE)
+        resolutions = [s * pixel_size / meters_per_unit for s in scales]
+    if not resolutions:
+        raise ValueError(f"Grid set {data.get('name')} has no resolutions")
+
+    return GridSetDefinition(
+        name=data.get("name", ""),
+        srs=srs_name,
+        extent=(extent[0], extent[1], extent[2], extent[3]),
+        resolutions=resolutions,
+        tile_width=int(data.get("tileWidth", 256)),
+        tile_height=int(data.get("tileHeight", 256)),
+        align_top_left=bool(data.get("alignTopLeft", False)),
+    )
+
+
+def lonlat_to_web_mercator(
+    bounds: tuple[float, float, float, float],
+) -> tuple[float, float, float, float]:
+    """Project a lon/lat bbox to Web Mercator.
+
+    Args:
+        bounds: (minx, miny, maxx, maxy) in degrees
+
+    Returns:
+        Bounds in EPSG:3857 metres
+    """
+
+    def project(lon: float, lat: float) -> tuple[float, float]:
+        lat = max(-MAX_MERCATOR_LAT, min(MAX_MERCATOR_LAT, lat))
+        x = math.radians(lon) * EARTH_RADIUS
+        y = math.log(math.tan(math.pi / 4 + math.radians(lat) / 2)) * EARTH_RADIUS
+        return x, y
+
+    minx, miny = project(bounds[0], bounds[1])
+    maxx, maxy = project(bounds[2], bounds[3])
+    return minx, miny, maxx, maxy
+
+
+def _axis_range(
+    low: float,
+    high: float,
+    origin: float,
+    span: float,
+    count: int,
+) -> tuple[int, int]:
+    """Compute the inclusive tile index range covering [low, high] on one axis."""
+    first = math.floor((low - origin) / span + GRID_EPSILON)
+    last = math.ceil((high - origin) / span - GRID_EPSILON) - 1
+    first = max(0, min(count - 1, first))
+    last = max(first, min(count - 1, last))
+    return first, last
+
+
+def tile_ranges(
+    gridset: GridSetDefinition,
+    bounds: tuple[float, float, float, float],
+    zoom_start: int,
+    zoom_stop: int,
+) -> list[TileRange]:
+    """Compute the tiles covering bounds within a grid set, per zoom level.
+
+    Args:
+        gridset: Grid set definition
+        bounds: (minx, miny, maxx, maxy) in the grid set's SRS
+        zoom_start: First zoom level (inclusive)
+        zoom_stop: Last zoom level (inclusive)
+
+    Returns:
+        List of TileRange, one per zoom level that exists in the grid set
+    """
+    gminx, gminy, gmaxx, gmaxy = gridset.extent
+
+    # Clip bounds to the grid set extent
+    minx = max(bounds[0], gminx)
+    miny = max(bounds[1], gminy)
+    maxx = min(bounds[2], gmaxx)
+    maxy = min(bounds[3], gmaxy)
+    if minx >= maxx or miny >= maxy:
+        return []
+
+    ranges = []
+    last_zoom = min(zoom_stop, len(gridset.resolutions) - 1)
+    for zoom in range(max(0, zoom_start), last_zoom + 1):
+        res = gridset.resolutions[zoom]
+        span_x = res * gridset.tile_width
+        span_y = res * gridset.tile_height
+
+        grid_cols = max(1, math.ceil((gmaxx - gminx) / span_x - GRID_EPSILON))
+        grid_rows = max(1, math.ceil((gmaxy - gminy) / span_y - GRID_EPSILON))
+
+        col_first, col_last = _axis_range(minx, maxx, gminx, span_x, grid_cols)
+        if gridset.align_top_left:
+            # Rows count downwards from the top edge
+            row_first, row_last = _axis_range(gmaxy - maxy, gmaxy - miny, 0.0, span_y, grid_rows)
+        else:
+            bottom_first, bottom_last = _axis_range(miny, maxy, gminy, span_y, grid_rows)
+            row_first, row_last = grid_rows - 1 - bottom_last, grid_rows - 1 - bottom_first
+
+        ranges.append(TileRange(zoom, col_first, col_last, row_first, row_last))
+    return ranges
+
+
+def estimate_tiles(
+    gridset: GridSetDefinition,
+    bounds: tuple[float, float, float, float],
+    zoom_start: int,
+    zoom_stop: int,
+) -> list[ZoomEstimate]:
+    """Compute the tile range per zoom level for bounds within a grid set.
+
+    Args:
+        gridset: Grid set definition
+        bounds: (minx, miny, maxx, maxy) in the grid set's SRS
+        zoom_start: First zoom level (inclusive)
+        zoom_stop: Last zoom level (inclusive)
+
+    Returns:
+        List of ZoomEstimate, one per zoom level that exists in the grid set
+    """
+    return [
+        ZoomEstimate(zoom=r.zoom, columns=r.columns, rows=r.rows)
+        for r in tile_ranges(gridset, bounds, zoom_start, zoom_stop)
+    ]
+
+
+def average_tile_bytes(tile_format: str) -> int:
+    """Return the assumed average tile size for a format."""
+    return AVERAGE_TILE_BYTES.get(tile_format.lower(), DEFAULT_TILE_BYTES)
+
+
+def resolve_bounds(
+    gridset: GridSetDefinition,
+    latlon_bbox: dict[str, Any] | None,
+    native_bbox: dict[str, Any] | None,
+) -> tuple[tuple[float, float, float, float], str]:
+    """Pick layer bounds expressed in the grid set's SRS.
+
+    Args:
+        gridset: Target grid set
+        latlon_bbox: Layer latLonBoundingBox (GeoServer REST shape)
+        native_bbox: Layer nativeBoundingBox (GeoServer REST shape)
+
+    Returns:
+        Tuple of (bounds, source) where source describes where the bounds came from
+    """
+
+    def as_tuple(bbox: dict[str, Any]) -> tuple[float, float, float, float]:
+        return (
+            float(bbox["minx"]),
+            float(bbox["miny"]),
+            float(bbox["maxx"]),
+            float(bbox["maxy"]),
+        )
+
+    srs = gridset.srs.upper()
+
+    if latlon_bbox and srs in ("EPSG:4326", "CRS:84"):
+        return as_tuple(latlon_bbox), "latLonBoundingBox"
+
+    if latlon_bbox and srs in WEB_MERCATOR_SRS:
+        return lonlat_to_web_mercator(as_tuple(latlon_bbox)), "latLonBoundingBox"
+
+    if native_bbox:
+        crs = native_bbox.get("crs")
+        if isinstance(crs, dict):
+            crs = crs.get("$", "")
+        if isinstance(crs, str) and crs.upper() == srs:
+            return as_tuple(native_bbox), "nativeBoundingBox"
+
+    return gridset.extent, "gridSetExtent"
+
+
+def estimate_seed(
+    gridset_data: dict[str, Any],
+    zoom_start: int,
+    zoom_stop: int,
+    tile_format: str = "image/png",
+    latlon_bbox: dict[str, Any] | None = None,
+    native_bbox: dict[str, Any] | None = None,
+    bounds: tuple[float, float, float, float] | None = None,
+    avg_tile_bytes: int | None = None,
+) -> SeedEstimate:
+    """Estimate a seed task.
+
+    Args:
+        gridset_data: Grid set dictionary from GWC
+        zoom_start: First zoom level
+        zoom_stop: Last zoom level
+        tile_format: Tile MIME type
+        latlon_bbox: Layer lat/lon bounding box
+        native_bbox: Layer native bounding box
+        bounds: Explicit bounds in the grid set SRS (overrides layer bounds)
+        avg_tile_bytes: Average tile size override
+
+    Returns:
+        SeedEstimate
+    """
+    gridset = parse_gridset(gridset_data)
+
+    if bounds is not None:
+        source = "request"
+    else:
+        bounds, source = resolve_bounds(gridset, latlon_bbox, native_bbox)
+
+    return SeedEstimate(
+        grid_set=gridset.name,
+        format=tile_format,
+        bounds=bounds,
+        bounds_source=source,
+        avg_tile_bytes=avg_tile_bytes or average_tile_bytes(tile_format),
+        zoom_levels=estimate_tiles(gridset, bounds, zoom_start, zoom_stop),
+    )
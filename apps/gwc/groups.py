@@ -0,0 +1,56 @@
+"""Tile caches of layer groups.
+
+GeoWebCache caches a layer group under its qualified name just like a
+layer, and seeds and truncates it the same way, but its REST API doesn't
+say which cached names are groups. These helpers match the cached names
+of a workspace against its layer groups, so listings can show group
+caches with the layers drawn into their tiles.
+"""
+
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import layergroup_entries
+
+if TYPE_CHECKING:
+    from apps.geoserver.client import GeoServerClient
+
+
+@dataclass
+class CachedGroup:
+    """A layer group with a tile cache, and what its tiles draw."""
+
+    name: str
+    layers: list[dict[str, str]] = field(default_factory=list)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {"name": self.name, "layers": self.layers}
+
+
+def cached_groups(
+    client: "GeoServerClient", workspace: str, cached: list[str]
+) -> list[CachedGroup]:
+    """Find the layer groups of a workspace among cached layer names.
+
+    Args:
+        client: GeoServer client
+        workspace: Workspace name
+        cached: Cached layer names (workspace:name), from GWCClient.list_layers
+
+    Returns:
+        The workspace's cached groups, with the layers each one draws
+    """
+    cached_names = set(cached)
+    groups = []
+    for group in client.list_layergroups(workspace):
+        name = group.get("name")
+        if not name or f"{workspace}:{name}" not in cached_names:
+            continue
+        try:
+            layers = layergroup_entries(client.get_layergroup(name, workspace))
+        except GeoServerError:
+            layers = []  # Still listed as a group, without its layers
+        groups.append(CachedGroup(name=f"{workspace}:{name}", layers=layers))
+    return groups
@@ -0,0 +1,295 @@
+"""Tile cache hit ratios per grid set and zoom level.
+
+GeoWebCache's runtime statistics count the tile requests it served from
+the cache, but only for the whole server. This breaks the hit ratio down
+for one layer: it requests tiles at places inside the layer's bounds
+through WMTS, at each zoom level of each grid set, and reads the
+geowebcache-cache-result header GWC adds to tile responses, HIT when the
+tile came from the cache and MISS when it had to be rendered. Zoom levels
+that mostly miss and have few enough tiles to seed are suggested for
+pre-seeding.
+
+A sampled tile that misses is rendered and cached like any client's, so
+an analysis run straight after another reports more hits.
+"""
+
+import random
+import re
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+
+from .endpoints import PREFERRED_FORMAT, wmts_tile_params
+from .estimate import TileRange, parse_gridset, resolve_bounds, tile_ranges
+
+if TYPE_CHECKING:
+    from apps.geoserver.client import GeoServerClient
+
+    from .client import GWCClient
+
+CACHE_RESULT_HEADER = "geowebcache-cache-result"
+
+# Defaults: zoom levels and tiles sampled per zoom level
+DEFAULT_ZOOM_START = 0
+DEFAULT_ZOOM_STOP = 14
+DEFAULT_SAMPLES = 5
+
+# Upper bound on tile requests per analysis
+MAX_PROBES = 300
+
+# Zoom levels hitting less than this are worth pre-seeding, if they have at most
+# SEED_TILE_LIMIT tiles; deeper levels are better left to fill on demand
+SEED_HIT_RATIO = 0.5
+SEED_TILE_LIMIT = 100_000
+
+
+@dataclass
+class RuntimeStats:
+    """Server-wide figures from GeoWebCache's runtime statistics."""
+
+    requests: int | None = None
+    hit_ratio: float | None = None  # 0 to 1
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {"requests": self.requests, "hitRatio": self.hit_ratio}
+
+
+def parse_runtime_stats(html: str) -> RuntimeStats | None:
+    """Read the request count and hit ratio from GWC's home page.
+
+    Returns:
+        The statistics, or None if the page doesn't show them (runtime
+        statistics can be turned off)
+    """
+    text = " ".join(re.sub(r"<[^>]+>", " ", html).split())
+    requests = re.search(r"Total number of requests:\s*([\d,]+)", text)
+    ratio = re.search(r"Cache hit ratio:\s*([\d.]+)\s*% of requests", text)
+    if not requests and not ratio:
+        return None
+    return RuntimeStats(
+        requests=int(requests.group(1).replace(",", "")) if requests else None,
+        hit_ratio=float(ratio.group(1)) / 100 if ratio else None,
+    )
+
+
+@dataclass
+class ZoomHitRatio:
+    """Sampled cache results at one zoom level of a grid set."""
+
+    grid_set: str
+    zoom: int
+    tiles: int  # Tiles covering the layer
+    sampled: int = 0
+    hits: int = 0
+    misses: int = 0
+    errors: int = 0  # Failed, or not answered from the cache or a render
+
+    @property
+    def hit_ratio(self) -> float | None:
+        """Fraction of sampled tiles found in the cache, or None without results."""
+        answered = self.hits + self.misses
+        return self.hits / answered if answered else None
+
+    @property
+    def worth_seeding(self) -> bool:
+        """Whether the level mostly misses and is small enough to seed."""
+        return (
+            self.hit_ratio is not None
+            and self.hit_ratio < SEED_HIT_RATIO
+            and self.tiles <= SEED_TILE_LIMIT
+        )
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "gridSet": self.grid_set,
+            "zoom": self.zoom,
+            "tiles": self.tiles,
+            "sampled": self.sampled,
+            "hits": self.hits,
+            "misses": self.misses,
+            "errors": self.errors,
+            "hitRatio": self.hit_ratio,
+            "worthSeeding": self.worth_seeding,
+        }
+
+
+@dataclass
+class SeedSuggestion:
+    """A run of consecutive zoom levels of a grid set worth pre-seeding."""
+
+    grid_set: str
+    zoom_start: int
+    zoom_stop: int
+    tiles: int
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "gridSet": self.grid_set,
+            "zoomStart": self.zoom_start,
+            "zoomStop": self.zoom_stop,
+            "tiles": self.tiles,
+        }
+
+
+@dataclass
+class HitRatioReport:
+    """Sampled hit ratios of a cached layer."""
+
+    layer: str
+    format: str
+    runtime: RuntimeStats | None = None
+    zooms: list[ZoomHitRatio] = field(default_factory=list)
+
+    @property
+    def hit_ratio(self) -> float | None:
+        """Fraction of all sampled tiles found in the cache."""
+        hits = sum(zoom.hits for zoom in self.zooms)
+        answered = hits + sum(zoom.misses for zoom in self.zooms)
+        return hits / answered if answered else None
+
+    def suggestions(self) -> list[SeedSuggestion]:
+        """Group the zoom levels worth seeding into ranges per grid set."""
+        suggestions: list[SeedSuggestion] = []
+        for zoom in self.zooms:
+            if not zoom.worth_seeding:
+                continue
+            last = suggestions[-1] if suggestions else None
+            if last and last.grid_set == zoom.grid_set and last.zoom_stop == zoom.zoom - 1:
+                last.zoom_stop = zoom.zoom
+                last.tiles += zoom.tiles
+            else:
+                suggestions.append(SeedSuggestion(zoom.grid_set, zoom.zoom, zoom.zoom, zoom.tiles))
+        return suggestions
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "layer": self.layer,
+            "format": self.format,
+            "hitRatio": self.hit_ratio,
+            "runtime": self.runtime.to_dict() if self.runtime else None,
+            "zooms": [zoom.to_dict() for zoom in self.zooms],
+            "seedSuggestions": [s.to_dict() for s in self.suggestions()],
+            "seedHitRatio": SEED_HIT_RATIO,
+            "seedTileLimit": SEED_TILE_LIMIT,
+        }
+
+
+def sample_tiles(tile_range: TileRange, count: int, rng: random.Random) -> list[tuple[int, int]]:
+    """Pick distinct (row, col) tiles of a range; all of them if there are no more than count."""
+    total = tile_range.columns * tile_range.rows
+    picks = range(total) if total <= count else rng.sample(range(total), count)
+    columns = tile_range.columns
+    return [
+        (tile_range.first_row + i // columns, tile_range.first_column + i % columns) for i in picks
+    ]
+
+
+def _probe(
+    gwc_client: "GWCClient",
+    layer: str,
+    grid_set: str,
+    tile_format: str,
+    zoom: int,
+    row: int,
+    col: int,
+) -> str:
+    """Request a tile and return its cache result: HIT, MISS or another value on failure."""
+    params = wmts_tile_params(layer, grid_set, tile_format, f"{grid_set}:{zoom}", row, col)
+    try:
+        response = gwc_client.get_tile_service("wmts", params)
+    except GeoServerError:
+        return "ERROR"
+    if response.status_code >= 400:
+        return "ERROR"
+    return response.headers.get(CACHE_RESULT_HEADER, "").upper()
+
+
+def analyze_hit_ratio(
+    gwc_client: "GWCClient",
+    gs_client: "GeoServerClient",
+    workspace: str,
+    layer: str,
+    grid_sets: list[str] | None = None,
+    zoom_start: int = DEFAULT_ZOOM_START,
+    zoom_stop: int = DEFAULT_ZOOM_STOP,
+    samples: int = DEFAULT_SAMPLES,
+    tile_format: str | None = None,
+    seed: int = 0,
+) -> HitRatioReport:
+    """Sample a cached layer's tiles to find its hit ratio per grid set and zoom level.
+
+    Args:
+        gwc_client: GWC client
+        gs_client: GeoServer client, for the layer's bounds
+        workspace: Workspace name
+        layer: Layer name
+        grid_sets: Grid sets to sample; all the layer caches when None
+        zoom_start: First zoom level
+        zoom_stop: Last zoom level
+        samples: Tiles sampled per zoom level
+        tile_format: Tile format; PNG or the layer's first when None
+        seed: Seed of the sampled tiles
+
+    Raises:
+        ValueError: If the settings are invalid or ask for too many requests
+        GeoServerError: If the layer isn't cached or its grid sets can't be read
+    """
+    if samples < 1:
+        raise ValueError("At least one tile per zoom level is needed")
+    if zoom_stop < zoom_start:
+        raise ValueError("zoomStop must be greater than or equal to zoomStart")
+
+    name = f"{workspace}:{layer}"
+    cache = gwc_client.get_layer(name)
+    cached_grid_sets = [s.get("gridSetName", "") for s in cache.get("gridSubsets") or []]
+    formats = cache.get("mimeFormats") or []
+    for grid_set in grid_sets or []:
+        if grid_set not in cached_grid_sets:
+            raise ValueError(f"{name} isn't cached in {grid_set}")
+    if tile_format and tile_format not in formats:
+        raise ValueError(f"{name} isn't cached as {tile_format}")
+    if not tile_format:
+        tile_format = PREFERRED_FORMAT if PREFERRED_FORMAT in formats else next(iter(formats), "")
+    if not tile_format:
+        raise ValueError(f"{name} has no cached tile formats")
+
+    bounds = gs_client.get_layer_bounds(workspace, layer)
+    plan: list[tuple[str, TileRange]] = []
+    for grid_set in grid_sets or cached_grid_sets:
+        gridset = parse_gridset(gwc_client.get_gridset(grid_set))
+        layer_bounds, _ = resolve_bounds(
+            gridset, bounds.get("latLonBoundingBox"), bounds.get("nativeBoundingBox")
+        )
+        plan += [(grid_set, r) for r in tile_ranges(gridset, layer_bounds, zoom_start, zoom_stop)]
+    probes = sum(min(samples, r.columns * r.rows) for _, r in plan)
+    if probes > MAX_PROBES:
+        raise ValueError(
+            f"That would request {probes} tiles; narrow the zoom levels, grid sets or "
+            f"samples to stay within {MAX_PROBES}"
+        )
+
+    try:
+        runtime = parse_runtime_stats(gwc_client.get_home_page())
+    except GeoServerError:
+        runtime = None  # Statistics are a bonus; the samples still stand
+
+    rng = random.Random(seed)
+    report = HitRatioReport(name, tile_format, runtime)
+    for grid_set, tile_range in plan:
+        zoom = ZoomHitRatio(grid_set, tile_range.zoom, tile_range.columns * tile_range.rows)
+        for row, col in sample_tiles(tile_range, samples, rng):
+            result = _probe(gwc_client, name, grid_set, tile_format, tile_range.zoom, row, col)
+            zoom.sampled += 1
+            if result == "HIT":
+                zoom.hits += 1
+            elif result == "MISS":
+                zoom.misses += 1
+            else:
+                zoom.errors += 1
+        report.zooms.append(zoom)
+    return report
@@ -0,0 +1,330 @@
+"""Automatic tile cache invalidation.
+
+When layer data is replaced (a file re-uploaded into an existing store or
+a feature type updated), the cached tiles for the affected layers are
+stale. Connections with auto_truncate_cache enabled have those layers
+truncated in GeoWebCache straight after the change.
+
+Style edits are handled the same way, except that only the tiles rendered
+with the edited style are dropped: layers using it as their default style
+are truncated entirely, layers offering it as an additional style only
+have the matching STYLES parameter set truncated.
+
+Deleting a store or workspace recursively clears the caches of the layers
+it contains first, regardless of auto-truncate, so no orphaned tiles or
+running seed tasks are left behind.
+"""
+
+import logging
+
+from collections.abc import Callable
+from typing import Any
+
+from apps.core.config import get_config
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+
+from .client import get_gwc_client
+
+logger = logging.getLogger(__name__)
+
+
+def is_auto_truncate_enabled(conn_id: str) -> bool:
+    """Check whether a connection truncates its tile cache on data changes."""
+    conn = get_config().get_connection(conn_id)
+    return bool(conn and conn.auto_truncate_cache)
+
+
+def truncate_layers(conn_id: str, workspace: str, layers: list[str]) -> list[str]:
+    """Truncate the tile cache of several layers, ignoring uncached layers.
+
+    Args:
+        conn_id: Connection ID
+        workspace: Workspace name
+        layers: Layer names within the workspace
+
+    Returns:
+        Full names of the layers that were truncated
+    """
+    client = get_gwc_client(conn_id)
+    truncated = []
+    for layer in layers:
+        layer_name = f"{workspace}:{layer}"
+        if _truncate(layer_name, client.truncate_entire_layer):
+            truncated.append(layer_name)
+    return truncated
+
+
+def _truncate(layer_name: str, truncate: Callable[[str], Any]) -> bool:
+    """Run a truncate call, treating layers without a tile cache as a no-op."""
+    try:
+        truncate(layer_name)
+        return True
+    except GeoServerError as e:
+        # Layers without a tile cache report 400/404; nothing to invalidate
+        if e.status_code not in (400, 404):
+            logger.warning("Failed to truncate tile cache for %s: %s", layer_name, e.message)
+        return False
+
+
+def invalidate_layer_cache(conn_id: str, workspace: str, layer: str) -> list[str]:
+    """Truncate a layer's tile cache if the connection has auto-truncate enabled.
+
+    Args:
+        conn_id: Connection ID
+        workspace: Workspace name
+        layer: Layer name
+
+    Returns:
+        Full names of the layers that were truncated
+    """
+    if not is_auto_truncate_enabled(conn_id):
+        return []
+    return truncate_layers(conn_id, workspace, [layer])
+
+
+def invalidate_store_cache(
+    conn_id: str,
+    workspace: str,
+    store: str,
+    store_type: str = "datastore",
+) -> list[str]:
+    """Truncate the tile cache of every layer published from a store.
+
+    Does nothing unless the connection has auto-truncate enabled.
+
+    Args:
+        conn_id: Connection ID
+        workspace: Workspace name
+        store: Data store or coverage store name
+        store_type: "datastore" or "coveragestore"
+
+    Returns:
+        Full names of the layers that were truncated
+    """
+    if not is_auto_truncate_enabled(conn_id):
+        return []
+
+    try:
+        layers = _store_layers(conn_id, workspace, store, store_type)
+    except (GeoServerError, ValueError) as e:
+        logger.warning("Could not list layers of %s:%s for cache invalidation: %s", workspace, store, e)
+        return []
+
+    return truncate_layers(conn_id, workspace, layers)
+
+
+def _store_layers(conn_id: str, workspace: str, store: str, store_type: str) -> list[str]:
+    """List the names of the layers published from a store."""
+    client = get_geoserver_client(conn_id)
+    if store_type == "coveragestore":
+        resources = client.list_coverages(workspace, store)
+    else:
+        resources = client.list_featuretypes(workspace, store)
+    return [r.get("name") for r in resources if r.get("name")]
+
+
+def cached_workspace_layers(conn_id: str, workspace: str) -> list[str]:
+    """List the cached layers and layer groups of a workspace.
+
+    Returns:
+        Full names (workspace:layer) of the tile layers GWC knows about
+    """
+    prefix = f"{workspace}:"
+    return [name for name in get_gwc_client(conn_id).list_layers() if name.startswith(prefix)]
+
+
+def clear_layer_caches(conn_id: str, layer_names: list[str]) -> list[str]:
+    """Stop seeding and drop every cached tile of layers about to be deleted.
+
+    Unlike invalidation this runs whether or not auto-truncate is enabled:
+    once a layer is gone its tiles can no longer be reached through GWC.
+
+    Args:
+        conn_id: Connection ID
+        layer_names: Full layer names (workspace:layer)
+
+    Returns:
+        Full names of the layers whose caches were cleared
+    """
+    client = get_gwc_client(conn_id)
+    cleared = []
+    for layer_name in layer_names:
+        try:
+            client.kill_seed_tasks(layer_name)
+        except GeoServerError:
+            pass  # Nothing was seeding
+        if _truncate(layer_name, client.truncate_entire_layer):
+            cleared.append(layer_name)
+    return cleared
+
+
+def clear_store_caches(
+    conn_id: str,
+    workspace: str,
+    store: str,
+    store_type: str = "datastore",
+) -> list[str]:
+    """Clear the tile caches of every layer in a store before deleting it.
+
+    Failures are logged rather than raised so they never block the delete.
+
+    Returns:
+        Full names of the layers whose caches were cleared
+    """
+    try:
+        layers = _store_layers(conn_id, workspace, store, store_type)
+        return clear_layer_caches(conn_id, [f"{workspace}:{layer}" for layer in layers])
+    except (GeoServerError, ValueError) as e:
+        logger.warning("Could not clear tile caches of %s:%s: %s", workspace, store, e)
+        return []
+
+
+def clear_workspace_caches(conn_id: str, workspace: str) -> list[str]:
+    """Clear the tile caches of every layer and layer group in a workspace.
+
+    Failures are logged rather than raised so they never block the delete.
+
+    Returns:
+        Full names of the layers whose caches were cleared
+    """
+    try:
+        return clear_layer_caches(conn_id, cached_workspace_layers(conn_id, workspace))
+    except (GeoServerError, ValueError) as e:
+        logger.warning("Could not clear tile caches of workspace %s: %s", workspace, e)
+        return []
+
+
+def _references_style(ref: Any, names: tuple[str, ...]) -> bool:
+    """Check whether a layer style reference points at one of the given names."""
+    if isinstance(ref, dict):
+        ref = ref.get("name")
+    return ref in names
+
+
+def find_style_layers(conn_id: str, workspace: str, style: str) -> list[dict[str, str]]:
+    """Find the layers of a workspace that render with a style.
+
+    A global style, with an empty workspace, is looked for on the
+    layers of every workspace.
+
+    Args:
+        conn_id: Connection ID
+        workspace: Workspace name, or "" for a global style
+        style: Style name
+
+    Returns:
+        List of {"layer": "workspace:layer", "usage": "default" | "additional"}
+    """
+    client = get_geoserver_client(conn_id)
+    names = (style, f"{workspace}:{style}") if workspace else (style,)
+    affected = []
+
+    for entry in client.list_layers(workspace):
+        name = entry.get("name")
+        if not name:
+            continue
+        layer_workspace = workspace
+        if not workspace:
+            # Layers of every workspace are listed by their full names
+            layer_workspace, _, name = name.rpartition(":")
+            if not layer_workspace:
+                continue
+        try:
+            layer = client.get_layer(layer_workspace, name)
+        except GeoServerError:
+            continue
+
+        if _references_style(layer.get("defaultStyle"), names):
+            usage = "default"
+        else:
+            styles = (layer.get("styles") or {}).get("style", [])
+            if isinstance(styles, dict):
+                styles = [styles]
+            if not any(_references_style(s, names) for s in styles):
+                continue
+            usage = "additional"
+
+        affected.append({"layer": f"{layer_workspace}:{name}", "usage": usage})
+
+    return affected
+
+
+def _supports_parameter_truncate(conn_id: str) -> bool:
+    """Check whether GeoWebCache can truncate by parameter filter."""
+    try:
+        return get_geoserver_client(conn_id).capabilities.supports("gwc_parameter_truncate")
+    except (GeoServerError, ValueError):
+        # Truncating whole layers is slower but can't leave stale tiles behind
+        return False
+
+
+def truncate_style_layers(
+    conn_id: str,
+    workspace: str,
+    style: str,
+    affected: list[dict[str, str]],
+) -> list[str]:
+    """Truncate the tiles rendered with a style on each affected layer.
+
+    Servers without parameter filter truncation have additional usages
+    truncated entirely instead.
+
+    Args:
+        conn_id: Connection ID
+        workspace: Workspace of the style
+        style: Style name
+        affected: Layers as returned by find_style_layers
+
+    Returns:
+        Full names of the layers that were truncated
+    """
+    client = get_gwc_client(conn_id)
+    # Global styles are requested by their bare name
+    parameters = {"STYLES": f"{workspace}:{style}" if workspace else style}
+    by_parameter = _supports_parameter_truncate(conn_id)
+    truncated = []
+
+    for item in affected:
+        layer_name = item["layer"]
+        if item.get("usage") == "default" or not by_parameter:
+            # Default style tiles are cached without a STYLES parameter
+            ok = _truncate(layer_name, client.truncate_entire_layer)
+        else:
+            ok = _truncate(
+                layer_name,
+                lambda name: client.truncate_layer_parameters(name, parameters),
+            )
+        if ok:
+            truncated.append(layer_name)
+
+    return truncated
+
+
+def invalidate_style_cache(
+    conn_id: str,
+    workspace: str,
+    style: str,
+) -> tuple[list[dict[str, str]], list[str]]:
+    """Find the layers affected by a style change and truncate them if enabled.
+
+    The affected layers are always returned so callers can offer a manual
+    truncate when the connection does not truncate automatically.
+
+    Args:
+        conn_id: Connection ID
+        workspace: Workspace of the style
+        style: Style name
+
+    Returns:
+        Tuple of (affected layers, full names of truncated layers)
+    """
+    try:
+        affected = find_style_layers(conn_id, workspace, style)
+    except (GeoServerError, ValueError) as e:
+        logger.warning("Could not find layers using style %s:%s: %s", workspace, style, e)
+        return [], []
+
+    if not affected or not is_auto_truncate_enabled(conn_id):
+        return affected, []
+    return affected, truncate_style_layers(conn_id, workspace, style, affected)
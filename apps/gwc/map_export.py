@@ -0,0 +1,292 @@
+"""Web map configurations for published layers.
+
+Downstream web maps rarely talk to GeoServer's catalog; they want a
+Mapbox GL style, or a MapProxy configuration to put in front of the
+server. These helpers write either for a selection of layers and layer
+groups, reading their tiles from GeoWebCache:
+
+- a Mapbox GL style (version 8) with a raster source per layer, whose
+  tiles come from the layer's WMTS endpoint as an XYZ template;
+- a MapProxy snippet with a tile source per layer reading GeoWebCache's
+  TMS endpoint, a cache on the GLOBAL_WEBMERCATOR grid and a layer
+  serving it.
+
+Both need a Web Mercator grid set, as Mapbox GL and MapProxy's built-in
+grid only draw in Web Mercator. The first layer given is drawn at the
+bottom, as in a layer group.
+"""
+
+import json
+import math
+from dataclasses import dataclass
+from pathlib import Path
+from typing import TYPE_CHECKING, Any
+
+from apps.core.output import format_yaml
+from apps.geoserver.publish import sanitize_name
+
+from .endpoints import tms_extension, wmts_tile_url
+
+if TYPE_CHECKING:
+    from apps.geoserver.client import GeoServerClient
+
+FORMATS = ("mapbox", "mapproxy")
+
+# File written for each format, after the export's name
+FILE_SUFFIXES = {"mapbox": ".style.json", "mapproxy": ".mapproxy.yaml"}
+
+# GWC grid sets on the Web Mercator tiling Mapbox GL and GLOBAL_WEBMERCATOR use
+WEB_MERCATOR_GRID_SETS = ("EPSG:900913", "EPSG:3857", "WebMercatorQuad")
+
+DEFAULT_GRID_SET = "EPSG:900913"
+DEFAULT_TILE_FORMAT = "image/png"
+TILE_SIZE = 256
+
+# Deepest zoom a style opens at when its layers cover a small area
+MAX_INITIAL_ZOOM = 18
+
+
+@dataclass
+class ExportLayer:
+    """A layer or layer group to put in a web map."""
+
+    workspace: str
+    name: str
+    kind: str = "layer"  # layer or layergroup
+    bbox: dict[str, Any] | None = None  # Lat/lon bounds, when known
+
+    @property
+    def qualified_name(self) -> str:
+        return f"{self.workspace}:{self.name}"
+
+    @property
+    def key(self) -> str:
+        """Identifier for sources and caches, e.g. topp_roads."""
+        return sanitize_name(f"{self.workspace}_{self.name}")
+
+
+@dataclass
+class MapExport:
+    """A generated web map configuration."""
+
+    format: str
+    filename: str
+    content: str
+    path: Path | None = None  # Where it was written
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "format": self.format,
+            "filename": self.filename,
+            "content": self.content,
+            "path": str(self.path) if self.path else None,
+        }
+
+
+def collect_layers(client: "GeoServerClient", names: list[str]) -> list[ExportLayer]:
+    """Look up whether each name is a layer or a layer group, and its bounds.
+
+    Args:
+        client: GeoServer client
+        names: Qualified names (workspace:name)
+
+    Raises:
+        ValueError: If a name has no workspace
+        GeoServerError: If a layer can't be read
+    """
+    groups: dict[str, set[str]] = {}
+    layers = []
+    for qualified in names:
+        workspace, _, name = qualified.partition(":")
+        if not name:
+            raise ValueError(f"{qualified} isn't a qualified name (workspace:name)")
+        if workspace not in groups:
+            groups[workspace] = {g.get("name", "") for g in client.list_layergroups(workspace)}
+        if name in groups[workspace]:
+            bounds = client.get_layergroup_bounds(name, workspace)
+            kind = "layergroup"
+        else:
+            bounds = client.get_layer_bounds(workspace, name)
+            kind = "layer"
+        layers.append(ExportLayer(workspace, name, kind, bounds.get("latLonBoundingBox")))
+    return layers
+
+
+def _union_bbox(layers: list[ExportLayer]) -> list[float] | None:
+    boxes = [layer.bbox for layer in layers if layer.bbox]
+    if not boxes:
+        return None
+    return [
+        max(-180.0, min(float(b["minx"]) for b in boxes)),
+        max(-85.0511, min(float(b["miny"]) for b in boxes)),
+        min(180.0, max(float(b["maxx"]) for b in boxes)),
+        min(85.0511, max(float(b["maxy"]) for b in boxes)),
+    ]
+
+
+def _bbox_list(layer: ExportLayer) -> list[float] | None:
+    if not layer.bbox:
+        return None
+    return [float(layer.bbox[k]) for k in ("minx", "miny", "maxx", "maxy")]
+
+
+def _check_grid_set(grid_set: str) -> None:
+    if grid_set not in WEB_MERCATOR_GRID_SETS:
+        raise ValueError(
+            f"Grid set {grid_set} isn't Web Mercator; use one of "
+            + ", ".join(WEB_MERCATOR_GRID_SETS)
+        )
+
+
+def mapbox_style(
+    base_url: str,
+    layers: list[ExportLayer],
+    name: str,
+    grid_set: str = DEFAULT_GRID_SET,
+    tile_format: str = DEFAULT_TILE_FORMAT,
+) -> dict[str, Any]:
+    """Build a Mapbox GL style drawing the layers from their WMTS tiles.
+
+    Args:
+        base_url: Public GeoServer base URL
+        layers: Layers to draw, bottom first
+        name: Style name
+        grid_set: Web Mercator grid set the layers are cached in
+        tile_format: Tile MIME type
+
+    Raises:
+        ValueError: If the grid set isn't Web Mercator
+    """
+    _check_grid_set(grid_set)
+    style: dict[str, Any] = {"version": 8, "name": name}
+    bbox = _union_bbox(layers)
+    if bbox:
+        minx, miny, maxx, maxy = bbox
+        style["center"] = [round((minx + maxx) / 2, 6), round((miny + maxy) / 2, 6)]
+        span = max(maxx - minx, maxy - miny)
+        style["zoom"] = (
+            min(MAX_INITIAL_ZOOM, max(0, math.floor(math.log2(360 / span)))) if span > 0 else 0
+        )
+
+    sources: dict[str, Any] = {}
+    style_layers = []
+    for layer in layers:
+        # WMTS rows count from the top, like XYZ tiles
+        tiles = wmts_tile_url(
+            base_url,
+            layer.qualified_name,
+            grid_set,
+            tile_format,
+            f"{grid_set}:{{z}}",
+            "{y}",
+            "{x}",
+        )
+        source: dict[str, Any] = {"type": "raster", "tiles": [tiles], "tileSize": TILE_SIZE}
+        if layer.bbox:
+            source["bounds"] = _bbox_list(layer)
+        sources[layer.key] = source
+        style_layers.append(
+            {
+                "id": layer.key,
+                "type": "raster",
+                "source": layer.key,
+                "metadata": {"geoserver:layer": layer.qualified_name},
+            }
+        )
+    style["sources"] = sources
+    style["layers"] = style_layers
+    return style
+
+
+def mapproxy_config(
+    base_url: str,
+    layers: list[ExportLayer],
+    grid_set: str = DEFAULT_GRID_SET,
+    tile_format: str = DEFAULT_TILE_FORMAT,
+) -> dict[str, Any]:
+    """Build the layers, caches and sources of a MapProxy configuration.
+
+    Args:
+        base_url: Public GeoServer base URL
+        layers: Layers to serve, bottom first
+        grid_set: Web Mercator grid set the layers are cached in
+        tile_format: Tile MIME type
+
+    Raises:
+        ValueError: If the grid set isn't Web Mercator
+    """
+    _check_grid_set(grid_set)
+    extension = tms_extension(tile_format)
+    base = base_url.rstrip("/")
+    config: dict[str, Any] = {"layers": [], "caches": {}, "sources": {}}
+    for layer in layers:
+        tms_layer = f"{layer.qualified_name}@{grid_set}@{extension}"
+        config["layers"].append(
+            {"name": layer.key, "title": layer.qualified_name, "sources": [f"{layer.key}_cache"]}
+        )
+        config["caches"][f"{layer.key}_cache"] = {
+            "grids": ["GLOBAL_WEBMERCATOR"],
+            "format": tile_format,
+            "sources": [f"{layer.key}_tiles"],
+        }
+        # TMS rows count from the bottom, like MapProxy's tms_path
+        source: dict[str, Any] = {
+            "type": "tile",
+            "url": f"{base}/gwc/service/tms/1.0.0/{tms_layer}/%(tms_path)s.{extension}",
+            "grid": "GLOBAL_WEBMERCATOR",
+        }
+        bbox = _bbox_list(layer)
+        if bbox:
+            source["coverage"] = {"bbox": bbox, "srs": "EPSG:4326"}
+        config["sources"][f"{layer.key}_tiles"] = source
+    return config
+
+
+def export_map(
+    base_url: str,
+    layers: list[ExportLayer],
+    export_format: str,
+    name: str,
+    grid_set: str = DEFAULT_GRID_SET,
+    tile_format: str = DEFAULT_TILE_FORMAT,
+) -> MapExport:
+    """Generate a Mapbox GL style or MapProxy snippet for the layers.
+
+    Args:
+        base_url: Public GeoServer base URL
+        layers: Layers to include, bottom first
+        export_format: One of FORMATS
+        name: Name of the style, also used for the file name
+        grid_set: Web Mercator grid set the layers are cached in
+        tile_format: Tile MIME type
+
+    Raises:
+        ValueError: If the format is unknown, no layers are given or the
+            grid set isn't Web Mercator
+    """
+    if export_format not in FORMATS:
+        raise ValueError(f"Unknown format {export_format}; use one of {', '.join(FORMATS)}")
+    if not layers:
+        raise ValueError("No layers to export")
+    filename = f"{sanitize_name(name)}{FILE_SUFFIXES[export_format]}"
+    if export_format == "mapbox":
+        style = mapbox_style(base_url, layers, name, grid_set, tile_format)
+        content = json.dumps(style, indent=2) + "\n"
+    else:
+        content = format_yaml(mapproxy_config(base_url, layers, grid_set, tile_format)) + "\n"
+    return MapExport(export_format, filename, content)
+
+
+def write_export(export: MapExport, directory: Path) -> Path:
+    """Write an export into a folder, replacing an earlier export of the same name.
+
+    Raises:
+        OSError: If the folder doesn't exist or can't be written
+    """
+    if not directory.is_dir():
+        raise OSError(f"Folder not found: {directory}")
+    path = directory / export.filename
+    path.write_text(export.content, encoding="utf-8")
+    export.path = path
+    return path
@@ -0,0 +1,310 @@
+"""Scheduled GeoWebCache seed and truncate tasks.
+
+Cache schedules are stored in the config file with a cron-like expression
+(minute hour day-of-month month day-of-week). A background thread checks
+the schedules once a minute and fires the matching GWC operation.
+"""
+
+import logging
+import threading
+from datetime import datetime, timedelta
+
+from apps.core.config import CacheSchedule, get_config
+from apps.core.exceptions import GeoServerError
+
+from .bounds import gridset_bounds, parse_bounds
+from .client import get_gwc_client
+
+logger = logging.getLogger(__name__)
+
+CRON_ALIASES = {
+    "@hourly": "0 * * * *",
+    "@daily": "0 0 * * *",
+    "@midnight": "0 0 * * *",
+    "@weekly": "0 0 * * 0",
+    "@monthly": "0 0 1 * *",
+    "@yearly": "0 0 1 1 *",
+    "@annually": "0 0 1 1 *",
+}
+
+# (name, min, max) for each cron field
+CRON_FIELDS = [
+    ("minute", 0, 59),
+    ("hour", 0, 23),
+    ("day of month", 1, 31),
+    ("month", 1, 12),
+    ("day of week", 0, 7),  # 0 and 7 are both Sunday
+]
+
+VALID_OPERATIONS = ("seed", "reseed", "truncate")
+
+# How far ahead next_run searches before giving up (covers Feb 29 schedules)
+MAX_LOOKAHEAD = timedelta(days=366 * 5)
+
+
+def _parse_field(expr: str, name: str, low: int, high: int) -> set[int]:
+    """Parse a single cron field into the set of values it matches."""
+    values: set[int] = set()
+    for part in expr.split(","):
+        step = 1
+        if "/" in part:
+            part, step_str = part.split("/", 1)
+            if not step_str.isdigit() or int(step_str) == 0:
+                raise ValueError(f"Invalid step in {name} field: {expr}")
+            step = int(step_str)
+
+        if part == "*":
+            start, end = low, high
+        elif "-" in part:
+            start_str, end_str = part.split("-", 1)
+            if not (start_str.isdigit() and end_str.isdigit()):
+                raise ValueError(f"Invalid range in {name} field: {expr}")
+            start, end = int(start_str), int(end_str)
+        elif part.isdigit():
+            start = int(part)
+            end = high if step > 1 else start
+        else:
+            raise ValueError(f"Invalid {name} field: {expr}")
+
+        if start < low or end > high or start > end:
+            raise ValueError(f"{name.capitalize()} out of range ({low}-{high}): {expr}")
+
+        values.update(range(start, end + 1, step))
+    return values
+
+
+class CronExpression:
+    """A five-field cron expression."""
+
+    def __init__(self, expression: str):
+        """Parse a cron expression.
+
+        Args:
+            expression: Cron expression or alias such as @daily
+
+        Raises:
+            ValueError: If the expression is invalid
+        """
+        self.expression = expression.strip()
+        expanded = CRON_ALIASES.get(self.expression.lower(), self.expression)
+        parts = expanded.split()
+        if len(parts) != 5:
+            raise ValueError(
+                f"Cron expression must have 5 fields (minute hour day month weekday): {expression}"
+            )
+
+        parsed = [
+            _parse_field(part, name, low, high)
+            for part, (name, low, high) in zip(parts, CRON_FIELDS)
+        ]
+        self.minutes, self.hours, self.days, self.months, weekdays = parsed
+        self.weekdays = {day % 7 for day in weekdays}
+        # Standard cron semantics: if both day fields are restricted, either may match
+        self._day_restricted = parts[2] != "*"
+        self._weekday_restricted = parts[4] != "*"
+
+    def matches(self, dt: datetime) -> bool:
+        """Check whether a datetime (to the minute) matches the expression."""
+        if dt.minute not in self.minutes or dt.hour not in self.hours:
+            return False
+        return dt.month in self.months and self._day_matches(dt)
+
+    def next_run(self, after: datetime) -> datetime | None:
+        """Return the next matching time strictly after the given datetime."""
+        candidate = after.replace(second=0, microsecond=0) + timedelta(minutes=1)
+        limit = after + MAX_LOOKAHEAD
+        while candidate <= limit:
+            if candidate.month not in self.months:
+                # Jump to the first day of the next month
+                year = candidate.year + (candidate.month // 12)
+                month = candidate.month % 12 + 1
+                candidate = candidate.replace(year=year, month=month, day=1, hour=0, minute=0)
+                continue
+            if not self._day_matches(candidate):
+                candidate = candidate.replace(hour=0, minute=0) + timedelta(days=1)
+                continue
+            if candidate.hour not in self.hours:
+                candidate = candidate.replace(minute=0) + timedelta(hours=1)
+                continue
+            if candidate.minute not in self.minutes:
+                candidate += timedelta(minutes=1)
+                continue
+            return candidate
+        return None
+
+    def _day_matches(self, dt: datetime) -> bool:
+        """Check the day-of-month/day-of-week part of the expression."""
+        # Python weekday(): Monday=0; cron: Sunday=0
+        weekday = (dt.weekday() + 1) % 7
+        day_match = dt.day in self.days
+        weekday_match = weekday in self.weekdays
+        if self._day_restricted and self._weekday_restricted:
+            return day_match or weekday_match
+        return day_match and weekday_match
+
+
+def validate_schedule(schedule: CacheSchedule) -> None:
+    """Validate a cache schedule.
+
+    Raises:
+        ValueError: If the schedule is invalid
+    """
+    CronExpression(schedule.cron)
+    if schedule.operation not in VALID_OPERATIONS:
+        raise ValueError(
+            f"Invalid operation '{schedule.operation}', expected one of {', '.join(VALID_OPERATIONS)}"
+        )
+    if ":" not in schedule.layer:
+        raise ValueError("Layer must be a full layer name (workspace:layer)")
+    if schedule.zoom_stop < schedule.zoom_start:
+        raise ValueError("zoomStop must be greater than or equal to zoomStart")
+    if schedule.bounds is not None:
+        parse_bounds(schedule.bounds)
+
+
+def next_run_time(schedule: CacheSchedule, after: datetime | None = None) -> str | None:
+    """Return the next run time of a schedule as an ISO string."""
+    if not schedule.enabled:
+        return None
+    try:
+        next_run = CronExpression(schedule.cron).next_run(after or datetime.now())
+    except ValueError:
+        return None
+    return next_run.isoformat() if next_run else None
+
+
+class CacheScheduler:
+    """Background scheduler that runs cache schedules."""
+
+    _instance: "CacheScheduler | None" = None
+    _lock = threading.RLock()
+
+    def __new__(cls) -> "CacheScheduler":
+        """Ensure singleton instance."""
+        if cls._instance is None:
+            with cls._lock:
+                if cls._instance is None:
+                    cls._instance = super().__new__(cls)
+                    cls._instance._thread: threading.Thread | None = None
+                    cls._instance._stop = threading.Event()
+                    cls._instance._last_tick: datetime | None = None
+        return cls._instance
+
+    @property
+    def running(self) -> bool:
+        """Whether the scheduler thread is alive."""
+        return self._thread is not None and self._thread.is_alive()
+
+    def start(self) -> None:
+        """Start the scheduler thread if it is not already running."""
+        with self._lock:
+            if self.running:
+                return
+            self._stop.clear()
+            # Schedules of the minute the server starts in may have run before it
+            self._last_tick = datetime.now().replace(second=0, microsecond=0)
+            self._thread = threading.Thread(
+                target=self._loop, name="gwc-cache-scheduler", daemon=True
+            )
+            self._thread.start()
+
+    def stop(self) -> None:
+        """Stop the scheduler thread."""
+        self._stop.set()
+
+    def _loop(self) -> None:
+        """Check schedules at the start of every minute."""
+        while not self._stop.is_set():
+            now = datetime.now().replace(second=0, microsecond=0)
+            if now != self._last_tick:
+                self._last_tick = now
+                self.tick(now)
+            # Sleep until just after the next minute boundary
+            wait = 60 - datetime.now().second + 1
+            self._stop.wait(wait)
+
+    def tick(self, now: datetime) -> list[str]:
+        """Run every enabled schedule that matches the given minute.
+
+        Args:
+            now: Current time, truncated to the minute
+
+        Returns:
+            IDs of the schedules that were triggered
+        """
+        triggered = []
+        for schedule in list(get_config().list_cache_schedules()):
+            if not schedule.enabled:
+                continue
+            try:
+                if not CronExpression(schedule.cron).matches(now):
+                    continue
+            except ValueError as e:
+                logger.warning("Skipping cache schedule %s: %s", schedule.id, e)
+                continue
+            triggered.append(schedule.id)
+            threading.Thread(
+                target=self.run_schedule, args=(schedule.id,), daemon=True
+            ).start()
+        return triggered
+
+    def run_schedule(self, schedule_id: str) -> CacheSchedule | None:
+        """Run a schedule immediately and record the outcome.
+
+        Args:
+            schedule_id: Schedule ID
+
+        Returns:
+            The updated schedule, or None if it does not exist
+        """
+        config = get_config()
+        schedule = config.get_cache_schedule(schedule_id)
+        if not schedule:
+            return None
+
+        try:
+            client = get_gwc_client(schedule.connection_id)
+            # Truncating an area goes through the seed endpoint, which takes bounds
+            if schedule.operation == "truncate" and not schedule.bounds:
+                client.truncate_layer(
+                    schedule.layer,
+                    grid_set=schedule.grid_set,
+                    zoom_start=schedule.zoom_start,
+                    zoom_stop=schedule.zoom_stop,
+                    format=schedule.format,
+                )
+            else:
+                bounds = None
+                if schedule.bounds:
+                    bounds = gridset_bounds(
+                        client, schedule.grid_set, parse_bounds(schedule.bounds)
+                    )
+                client.seed_layer(
+                    schedule.layer,
+                    grid_set=schedule.grid_set,
+                    zoom_start=schedule.zoom_start,
+                    zoom_stop=schedule.zoom_stop,
+                    format=schedule.format,
+                    num_threads=schedule.threads,
+                    seed_type=schedule.operation,
+                    bounds=bounds,
+                )
+            schedule.last_status = "success"
+            schedule.last_error = None
+        except GeoServerError as e:
+            logger.error("Cache schedule %s failed: %s", schedule.id, e.message)
+            schedule.last_status = "failed"
+            schedule.last_error = e.message
+        except Exception as e:
+            logger.exception("Cache schedule %s failed", schedule.id)
+            schedule.last_status = "failed"
+            schedule.last_error = str(e)
+
+        schedule.last_run_at = datetime.now().isoformat()
+        config.update_cache_schedule(schedule)
+        return schedule
+
+
+def get_cache_scheduler() -> CacheScheduler:
+    """Get the cache scheduler singleton."""
+    return CacheScheduler()
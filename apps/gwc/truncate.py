@@ -0,0 +1,239 @@
+"""Truncating the tile caches of many layers at once.
+
+Layers are truncated concurrently by a small worker pool rather than one
+after another, and a failure on one layer doesn't stop the rest: every
+layer gets a result and the caller a summary. Layers without a tile cache
+count as done, since there is nothing to drop.
+
+Mass truncations started from the web UI run as background jobs whose
+progress is polled, like upload sessions. Each is written to the
+operation log first (see apps.core.oplog), so one cut short can be
+resumed with only the layers it didn't get to.
+"""
+
+import logging
+import threading
+import uuid
+from concurrent.futures import ThreadPoolExecutor
+from dataclasses import dataclass, field
+from datetime import datetime
+from fnmatch import fnmatchcase
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+from apps.core.oplog import Operation, operation_log
+from apps.geoserver.batch import BatchResult, ProgressCallback
+
+if TYPE_CHECKING:
+    from .client import GWCClient
+
+logger = logging.getLogger(__name__)
+
+# Layers truncated at once; GWC deletes tiles on disk, so more mostly adds I/O contention
+DEFAULT_WORKERS = 4
+
+# Finished jobs kept for polling
+MAX_FINISHED_JOBS = 20
+
+
+def matching_layers(
+    gwc_client: "GWCClient", workspace: str | None = None, pattern: str | None = None
+) -> list[str]:
+    """List the tile layers of a workspace, or of the server, matching a name pattern.
+
+    Args:
+        gwc_client: GWC client
+        workspace: Only layers of this workspace
+        pattern: Shell-style pattern on the layer name without its
+            workspace, e.g. "roads_*"
+
+    Returns:
+        Full layer names, sorted
+    """
+    layers = []
+    for name in gwc_client.list_layers():
+        ws, _, short = name.rpartition(":")
+        if workspace and ws != workspace:
+            continue
+        if pattern and not fnmatchcase(short, pattern):
+            continue
+        layers.append(name)
+    return sorted(layers)
+
+
+def truncate_layer(gwc_client: "GWCClient", layer: str) -> BatchResult:
+    """Truncate one layer's tile cache, reporting failures in the result."""
+    try:
+        gwc_client.truncate_entire_layer(layer)
+    except GeoServerError as e:
+        # Layers without a tile cache report 400/404; nothing to truncate
+        if e.status_code in (400, 404):
+            return BatchResult(layer, True, "No tile cache")
+        return BatchResult(layer, False, e.message)
+    except Exception as e:
+        logger.exception("Truncating %s failed", layer)
+        return BatchResult(layer, False, str(e))
+    return BatchResult(layer, True, "Tile cache truncated")
+
+
+def mass_truncate(
+    gwc_client: "GWCClient",
+    layers: list[str],
+    max_workers: int = DEFAULT_WORKERS,
+    progress: ProgressCallback | None = None,
+    operation: Operation | None = None,
+) -> list[BatchResult]:
+    """Truncate the tile caches of several layers concurrently.
+
+    Args:
+        gwc_client: GWC client
+        layers: Full layer names
+        max_workers: Layers truncated at once
+        progress: Called after each layer, from the worker threads, with
+            (done, total, result)
+        operation: Logged operation to record each layer in
+
+    Returns:
+        One result per layer, in the order given
+    """
+    lock = threading.Lock()
+    done = 0
+
+    def run(layer: str) -> BatchResult:
+        nonlocal done
+        result = truncate_layer(gwc_client, layer)
+        if operation is not None:
+            operation_log.record(operation, layer, result.ok, result.message)
+        with lock:
+            done += 1
+            if progress:
+                progress(done, len(layers), result)
+        return result
+
+    with ThreadPoolExecutor(max_workers=max(1, max_workers)) as executor:
+        return list(executor.map(run, layers))
+
+
+def summarize(results: list[BatchResult]) -> dict[str, Any]:
+    """Count the outcomes of a mass truncation."""
+    failed = [r for r in results if not r.ok]
+    skipped = [r for r in results if r.ok and r.message == "No tile cache"]
+    return {
+        "total": len(results),
+        "truncated": len(results) - len(failed) - len(skipped),
+        "skipped": len(skipped),
+        "failed": len(failed),
+    }
+
+
+@dataclass
+class MassTruncateJob:
+    """A mass truncation running in the background."""
+
+    id: str
+    conn_id: str
+    layers: list[str]
+    operation_id: str = ""
+    status: str = "running"  # running, completed
+    results: list[BatchResult] = field(default_factory=list)
+    created_at: str = field(default_factory=lambda: datetime.utcnow().isoformat())
+    completed_at: str = ""
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "id": self.id,
+            "connectionId": self.conn_id,
+            "operationId": self.operation_id,
+            "status": self.status,
+            "total": len(self.layers),
+            "done": len(self.results),
+            "results": [r.to_dict() for r in self.results],
+            "summary": summarize(self.results),
+            "createdAt": self.created_at,
+            "completedAt": self.completed_at,
+        }
+
+
+class MassTruncateJobManager:
+    """Tracks background mass truncations."""
+
+    _instance: "MassTruncateJobManager | None" = None
+    _lock = threading.RLock()
+
+    def __new__(cls) -> "MassTruncateJobManager":
+        """Ensure singleton instance."""
+        if cls._instance is None:
+            with cls._lock:
+                if cls._instance is None:
+                    cls._instance = super().__new__(cls)
+                    cls._instance._jobs: dict[str, MassTruncateJob] = {}
+        return cls._instance
+
+    def start(
+        self,
+        conn_id: str,
+        gwc_client: "GWCClient",
+        layers: list[str],
+        max_workers: int = DEFAULT_WORKERS,
+        operation: Operation | None = None,
+    ) -> MassTruncateJob:
+        """Start truncating layers in a background thread.
+
+        Args:
+            conn_id: Connection ID
+            gwc_client: GWC client
+            layers: Full layer names
+            max_workers: Layers truncated at once
+            operation: An interrupted truncation to resume; layers are
+                then its pending ones
+
+        Returns:
+            The job, to poll with get_job()
+        """
+        if operation is None:
+            operation = operation_log.begin("truncate", conn_id, layers)
+        else:
+            layers = operation.pending
+        job = MassTruncateJob(
+            id=str(uuid.uuid4()),
+            conn_id=conn_id,
+            layers=list(layers),
+            operation_id=operation.id,
+        )
+        with self._lock:
+            self._forget_finished()
+            self._jobs[job.id] = job
+
+        def record(done: int, total: int, result: BatchResult) -> None:
+            with self._lock:
+                job.results.append(result)
+
+        def run() -> None:
+            try:
+                mass_truncate(
+                    gwc_client, job.layers, max_workers, progress=record, operation=operation
+                )
+            finally:
+                operation_log.close(operation)
+                with self._lock:
+                    job.status = "completed"
+                    job.completed_at = datetime.utcnow().isoformat()
+
+        threading.Thread(target=run, daemon=True).start()
+        return job
+
+    def get_job(self, job_id: str) -> MassTruncateJob | None:
+        """Get a job by ID."""
+        return self._jobs.get(job_id)
+
+    def _forget_finished(self) -> None:
+        """Drop the oldest finished jobs beyond MAX_FINISHED_JOBS."""
+        finished = [job for job in self._jobs.values() if job.status == "completed"]
+        for job in finished[: max(0, len(finished) - MAX_FINISHED_JOBS)]:
+            del self._jobs[job.id]
+
+
+def get_mass_truncate_jobs() -> MassTruncateJobManager:
+    """Get the mass truncation job manager."""
+    return MassTruncateJobManager()
@@ -22,12 +22,40 @@ urlpatterns = [
         views.GWCSeedView.as_view(),
         name="gwc-seed",
     ),
+    path(
+        "gwc/estimate/<str:conn_id>/<str:workspace>/<str:layer>",
+        views.GWCSeedEstimateView.as_view(),
+        name="gwc-seed-estimate",
+    ),
+    # Client URLs and a sample tile check
+    path(
+        "gwc/endpoints/<str:conn_id>/<str:workspace>/<str:layer>",
+        views.GWCTileEndpointsView.as_view(),
+        name="gwc-tile-endpoints",
+    ),
+    # Web map configurations
+    path(
+        "gwc/mapexport/<str:conn_id>",
+        views.GWCMapExportView.as_view(),
+        name="gwc-map-export",
+    ),
+    # Sampled cache hit ratios
+    path(
+        "gwc/hitratio/<str:conn_id>/<str:workspace>/<str:layer>",
+        views.GWCHitRatioView.as_view(),
+        name="gwc-hit-ratio",
+    ),
     # Truncating
     path(
         "gwc/truncate/<str:conn_id>/<str:workspace>/<str:layer>",
         views.GWCTruncateView.as_view(),
         name="gwc-truncate",
     ),
+    path(
+        "gwc/styles/<str:conn_id>/<str:workspace>/<str:style>",
+        views.GWCStyleCacheView.as_view(),
+        name="gwc-style-cache",
+    ),
     # Grid Sets
     path(
         "gwc/gridsets/<str:conn_id>",
@@ -51,4 +79,25 @@ urlpatterns = [
         views.GWCMassTruncateView.as_view(),
         name="gwc-masstruncate",
     ),
+    path(
+        "gwc/masstruncate/<str:conn_id>/<str:job_id>",
+        views.GWCMassTruncateJobView.as_view(),
+        name="gwc-masstruncate-job",
+    ),
+    # Scheduled cache tasks
+    path(
+        "gwc/schedules",
+        views.GWCScheduleListView.as_view(),
+        name="gwc-schedule-list",
+    ),
+    path(
+        "gwc/schedules/<str:schedule_id>",
+        views.GWCScheduleDetailView.as_view(),
+        name="gwc-schedule-detail",
+    ),
+    path(
+        "gwc/schedules/<str:schedule_id>/run",
+        views.GWCScheduleRunView.as_view(),
+        name="gwc-schedule-run",
+    ),
 ]
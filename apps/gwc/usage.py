@@ -0,0 +1,174 @@
+"""Tile cache usage figures for the dashboard.
+
+Reads GeoWebCache's disk quota configuration into byte counts and counts
+the seed tasks in progress, so the dashboard can show how much of its
+tile cache budget each connection is using and whether seeding is under
+way. GeoWebCache reports usage alongside the quota only on some
+versions, so used space is optional throughout.
+"""
+
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+if TYPE_CHECKING:
+    from .client import GWCClient
+
+# Quota units used by GeoWebCache
+UNIT_BYTES = {
+    "B": 1,
+    "KiB": 1024,
+    "MiB": 1024**2,
+    "GiB": 1024**3,
+    "TiB": 1024**4,
+}
+
+# Task states in GWC seed status arrays
+TASK_ABORTED = -1
+TASK_PENDING = 0
+TASK_RUNNING = 1
+TASK_DONE = 2
+
+
+def quota_bytes(quota: Any) -> int | None:
+    """Convert a GWC quota to bytes.
+
+    GeoWebCache writes quotas either as {"value": ..., "units": ...} or as
+    {"bytes": ...}.
+
+    Returns:
+        The size in bytes, or None if the quota is missing or unreadable
+    """
+    if not isinstance(quota, dict):
+        return None
+    try:
+        if "bytes" in quota:
+            return int(quota["bytes"])
+        if "value" in quota:
+            return int(float(quota["value"]) * UNIT_BYTES.get(quota.get("units", "B"), 1))
+    except (TypeError, ValueError):
+        pass
+    return None
+
+
+@dataclass
+class LayerQuotaUsage:
+    """Quota and usage of one layer's tile cache."""
+
+    layer: str
+    limit_bytes: int | None = None
+    used_bytes: int | None = None
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {"layer": self.layer, "limitBytes": self.limit_bytes, "usedBytes": self.used_bytes}
+
+
+@dataclass
+class DiskQuotaUsage:
+    """Disk quota configuration and usage of a GWC instance."""
+
+    enabled: bool = False
+    limit_bytes: int | None = None
+    used_bytes: int | None = None
+    expiration_policy: str = ""
+    layers: list[LayerQuotaUsage] = field(default_factory=list)
+
+    @property
+    def used_pct(self) -> float | None:
+        """Used share of the global quota, when both are known."""
+        if not self.limit_bytes or self.used_bytes is None:
+            return None
+        return round(100 * self.used_bytes / self.limit_bytes, 1)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "enabled": self.enabled,
+            "limitBytes": self.limit_bytes,
+            "usedBytes": self.used_bytes,
+            "usedPct": self.used_pct,
+            "expirationPolicy": self.expiration_policy,
+            "layers": [layer.to_dict() for layer in self.layers],
+        }
+
+
+def parse_disk_quota(config: dict[str, Any]) -> DiskQuotaUsage:
+    """Read a gwcQuotaConfiguration document.
+
+    When the server reports per-layer usage but not the global figure,
+    the global usage is the sum of the layers'.
+    """
+    usage = DiskQuotaUsage(
+        enabled=str(config.get("enabled", False)).lower() == "true",
+        limit_bytes=quota_bytes(config.get("globalQuota")),
+        used_bytes=quota_bytes(config.get("globalUsage") or config.get("usedQuota")),
+        expiration_policy=config.get("globalExpirationPolicyName") or "",
+    )
+
+    layer_quotas = config.get("layerQuotas") or []
+    if isinstance(layer_quotas, dict):
+        # XStream-style wrapping: {"LayerQuota": [...]} or a single entry
+        layer_quotas = layer_quotas.get("LayerQuota", [])
+        if isinstance(layer_quotas, dict):
+            layer_quotas = [layer_quotas]
+    for entry in layer_quotas:
+        if not isinstance(entry, dict) or not entry.get("layer"):
+            continue
+        usage.layers.append(
+            LayerQuotaUsage(
+                layer=entry["layer"],
+                limit_bytes=quota_bytes(entry.get("quota")),
+                used_bytes=quota_bytes(entry.get("usedQuota") or entry.get("usage")),
+            )
+        )
+
+    if usage.used_bytes is None:
+        reported = [layer.used_bytes for layer in usage.layers if layer.used_bytes is not None]
+        if reported:
+            usage.used_bytes = sum(reported)
+    return usage
+
+
+def get_disk_quota_usage(gwc_client: "GWCClient") -> DiskQuotaUsage:
+    """Fetch and read the disk quota of a GWC instance."""
+    return parse_disk_quota(gwc_client.get_disk_quota())
+
+
+def get_layer_quota_usage(gwc_client: "GWCClient", layer: str) -> LayerQuotaUsage:
+    """Fetch the cached size of one layer's tiles.
+
+    The disk quota tracks sizes, not tile counts. A layer the quota
+    doesn't list gets an entry with unknown figures.
+
+    Args:
+        gwc_client: GWC client
+        layer: Qualified layer name, e.g. "topp:roads"
+    """
+    for usage in get_disk_quota_usage(gwc_client).layers:
+        if usage.layer == layer:
+            return usage
+    return LayerQuotaUsage(layer)
+
+
+@dataclass
+class SeedTaskCounts:
+    """Seed tasks of a GWC instance by state."""
+
+    running: int = 0
+    pending: int = 0
+
+    def to_dict(self) -> dict[str, int]:
+        """Serialize for API responses."""
+        return {"running": self.running, "pending": self.pending}
+
+
+def count_seed_tasks(gwc_client: "GWCClient") -> SeedTaskCounts:
+    """Count the running and pending seed, reseed and truncate tasks."""
+    counts = SeedTaskCounts()
+    for task in gwc_client.list_seed_tasks():
+        state = task[4] if len(task) > 4 else TASK_RUNNING
+        if state == TASK_RUNNING:
+            counts.running += 1
+        elif state == TASK_PENDING:
+            counts.pending += 1
+    return counts
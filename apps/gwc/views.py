@@ -1,20 +1,52 @@
 """Views for GeoWebCache management.
 
 Provides endpoints for:
-- Listing cached layers
+- Listing cached layers and layer groups
+- Turning on caching for a layer or layer group
 - Seeding tiles
+- Estimating seed size
+- Client tile URLs and a sample tile check
+- Mapbox GL styles and MapProxy configurations for web maps
 - Truncating tiles
+- Truncating many layers in the background
+- Truncating tiles rendered with an edited style
 - Managing grid sets
 - Disk quota monitoring
+- Scheduled seed/truncate tasks
 """
 
+from pathlib import Path
+
 from rest_framework import status
 from rest_framework.response import Response
 from rest_framework.views import APIView
 
+from apps.core.config import CacheSchedule, get_config
 from apps.core.exceptions import GeoServerError
+from apps.core.oplog import operation_log
+from apps.geoserver.client import get_geoserver_client
 
+from .bounds import LONLAT_SRS, gridset_bounds, parse_bounds, transform_bounds
 from .client import get_gwc_client
+from .endpoints import check_endpoints, tile_endpoints
+from .estimate import estimate_seed, parse_gridset
+from .groups import cached_groups
+from .hit_ratio import (
+    DEFAULT_SAMPLES,
+    DEFAULT_ZOOM_START,
+    DEFAULT_ZOOM_STOP,
+    analyze_hit_ratio,
+)
+from .invalidation import find_style_layers, truncate_style_layers
+from .map_export import (
+    DEFAULT_GRID_SET,
+    DEFAULT_TILE_FORMAT,
+    collect_layers,
+    export_map,
+    write_export,
+)
+from .scheduler import get_cache_scheduler, next_run_time, validate_schedule
+from .truncate import get_mass_truncate_jobs, matching_layers
 
 
 class GWCLayerListView(APIView):
@@ -23,18 +55,24 @@ class GWCLayerListView(APIView):
     def get(self, request, conn_id, workspace):
         """List cached layers.
 
-        Filters layers by workspace prefix.
+        Filters layers by workspace prefix. The workspace's cached layer
+        groups are also listed under "groups", with the layers they draw.
         """
         try:
             client = get_gwc_client(conn_id)
             layers = client.list_layers()
 
             # Filter by workspace if provided
+            groups = []
             if workspace:
                 workspace_prefix = f"{workspace}:"
                 layers = [l for l in layers if l.startswith(workspace_prefix)]
+                try:
+                    groups = cached_groups(get_geoserver_client(conn_id), workspace, layers)
+                except GeoServerError:
+                    pass  # The cached names are still listed
 
-            return Response({"layers": layers})
+            return Response({"layers": layers, "groups": [g.to_dict() for g in groups]})
         except GeoServerError as e:
             return Response(
                 {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
@@ -42,7 +80,7 @@ class GWCLayerListView(APIView):
 
 
 class GWCLayerDetailView(APIView):
-    """Get GWC layer details."""
+    """Get GWC layer details, or turn on caching for a layer."""
 
     def get(self, request, conn_id, workspace, layer):
         """Get cached layer configuration."""
@@ -56,6 +94,35 @@ class GWCLayerDetailView(APIView):
                 {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
             )
 
+    def put(self, request, conn_id, workspace, layer):
+        """Turn on tile caching for a layer or layer group.
+
+        Expected body (both optional, GeoServer's defaults when omitted):
+        {
+            "gridSets": ["EPSG:4326", "EPSG:900913"],
+            "formats": ["image/png", "image/jpeg"]
+        }
+        """
+        grid_sets = request.data.get("gridSets")
+        formats = request.data.get("formats")
+        for value in (grid_sets, formats):
+            if value is not None and not (
+                isinstance(value, list) and all(isinstance(v, str) and v for v in value)
+            ):
+                return Response(
+                    {"error": "gridSets and formats must be lists of names"},
+                    status=status.HTTP_400_BAD_REQUEST,
+                )
+
+        try:
+            client = get_gwc_client(conn_id)
+            result = client.enable_layer(f"{workspace}:{layer}", grid_sets, formats)
+            return Response(result, status=status.HTTP_201_CREATED)
+        except GeoServerError as e:
+            return Response(
+                {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
+            )
+
 
 class GWCSeedView(APIView):
     """Seed tiles for a layer."""
@@ -70,20 +137,35 @@ class GWCSeedView(APIView):
             "zoomStop": 10,
             "format": "image/png",
             "threads": 4,
-            "type": "seed"  // seed, reseed, or truncate
+            "type": "seed",  // seed, reseed, or truncate
+            "bounds": {"minX": 0, "minY": 0, "maxX": 1, "maxY": 1, "srs": "EPSG:4326"}
         }
+
+        Bounds are optional and converted to the grid set's SRS. They may
+        also be a [minx, miny, maxx, maxy] list, in lon/lat unless a
+        "boundsSrs" is given.
         """
         try:
             client = get_gwc_client(conn_id)
             layer_name = f"{workspace}:{layer}"
 
-            grid_set = request.data.get("gridSet", "EPSG:4326")
+            grid_set = request.data.get("gridSet") or request.data.get("gridSetId", "EPSG:4326")
             zoom_start = request.data.get("zoomStart", 0)
             zoom_stop = request.data.get("zoomStop", 10)
             tile_format = request.data.get("format", "image/png")
-            threads = request.data.get("threads", 4)
+            threads = request.data.get("threads") or request.data.get("threadCount", 4)
             seed_type = request.data.get("type", "seed")
 
+            bounds = request.data.get("bounds")
+            if bounds is not None:
+                srs = request.data.get("boundsSrs") or LONLAT_SRS
+                if isinstance(bounds, dict):
+                    srs = bounds.get("srs") or srs
+                try:
+                    bounds = gridset_bounds(client, grid_set, parse_bounds(bounds), srs)
+                except ValueError as e:
+                    return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
             result = client.seed_layer(
                 layer_name,
                 grid_set=grid_set,
@@ -92,6 +174,7 @@ class GWCSeedView(APIView):
                 format=tile_format,
                 num_threads=threads,
                 seed_type=seed_type,
+                bounds=bounds,
             )
 
             return Response(result, status=status.HTTP_202_ACCEPTED)
@@ -139,6 +222,220 @@ class GWCSeedView(APIView):
             )
 
 
+class GWCTileEndpointsView(APIView):
+    """Client URLs for a cached layer, checked with a sample tile."""
+
+    def get(self, request, conn_id, workspace, layer):
+        """Check the layer's tile services and list URLs for its grid set and format.
+
+        Query params:
+        - gridSet: Optional grid set; the layer's first when omitted
+        - tileFormat: Optional tile format; PNG or the layer's first when omitted
+        """
+        try:
+            client = get_gwc_client(conn_id)
+            layer_name = f"{workspace}:{layer}"
+            check = check_endpoints(
+                client,
+                layer_name,
+                grid_set=request.query_params.get("gridSet") or None,
+                tile_format=request.query_params.get("tileFormat") or None,
+            )
+            endpoints = tile_endpoints(
+                client.connection.public_base_url,
+                layer_name,
+                check.grid_set or request.query_params.get("gridSet", "EPSG:900913"),
+                check.format or request.query_params.get("tileFormat", "image/png"),
+            )
+            return Response(
+                {"check": check.to_dict(), "endpoints": [e.to_dict() for e in endpoints]}
+            )
+        except GeoServerError as e:
+            return Response(
+                {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
+            )
+
+
+class GWCMapExportView(APIView):
+    """Mapbox GL styles and MapProxy snippets for published layers."""
+
+    def post(self, request, conn_id):
+        """Generate a web map configuration and write it to the local folder.
+
+        Expected body:
+        {
+            "layers": ["topp:roads", "topp:basemap"],  // Bottom first
+            "format": "mapbox",  // mapbox or mapproxy
+            "name": "optional_style_name",
+            "gridSet": "EPSG:900913",
+            "tileFormat": "image/png",
+            "directory": "optional_folder",  // The file browser's folder by default
+            "write": true  // false to only return the content
+        }
+        """
+        data = request.data
+        layers = data.get("layers") or []
+        if not layers:
+            return Response({"error": "No layers given"}, status=status.HTTP_400_BAD_REQUEST)
+        config = get_config()
+        connection = config.get_connection(conn_id)
+        if not connection:
+            return Response({"error": "Connection not found"}, status=status.HTTP_404_NOT_FOUND)
+
+        try:
+            client = get_geoserver_client(conn_id)
+            export = export_map(
+                connection.public_base_url,
+                collect_layers(client, layers),
+                data.get("format", "mapbox"),
+                data.get("name") or connection.name,
+                data.get("gridSet") or DEFAULT_GRID_SET,
+                data.get("tileFormat") or DEFAULT_TILE_FORMAT,
+            )
+            if data.get("write", True):
+                directory = Path(data.get("directory") or config.last_local_path).expanduser()
+                write_export(export, directory)
+            return Response(export.to_dict())
+        except (OSError, ValueError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return Response(
+                {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
+            )
+
+
+class GWCHitRatioView(APIView):
+    """Cache hit ratios of a layer, sampled with WMTS requests."""
+
+    def post(self, request, conn_id, workspace, layer):
+        """Sample tiles per grid set and zoom level and report how many were cached.
+
+        Sampled tiles that miss are rendered and cached by GWC.
+
+        Expected body (all optional):
+        {
+            "gridSets": ["EPSG:900913"],  // all the layer's grid sets by default
+            "zoomStart": 0,
+            "zoomStop": 14,
+            "samples": 5,
+            "format": "image/png"
+        }
+        """
+        grid_sets = request.data.get("gridSets") or None
+        if grid_sets is not None and not isinstance(grid_sets, list):
+            return Response(
+                {"error": "gridSets must be a list"}, status=status.HTTP_400_BAD_REQUEST
+            )
+        try:
+            zoom_start = int(request.data.get("zoomStart", DEFAULT_ZOOM_START))
+            zoom_stop = int(request.data.get("zoomStop", DEFAULT_ZOOM_STOP))
+            samples = int(request.data.get("samples", DEFAULT_SAMPLES))
+        except (TypeError, ValueError):
+            return Response(
+                {"error": "zoomStart, zoomStop and samples must be whole numbers"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        try:
+            report = analyze_hit_ratio(
+                get_gwc_client(conn_id),
+                get_geoserver_client(conn_id),
+                workspace,
+                layer,
+                grid_sets=grid_sets,
+                zoom_start=zoom_start,
+                zoom_stop=zoom_stop,
+                samples=samples,
+                tile_format=request.data.get("format") or None,
+            )
+            return Response(report.to_dict())
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return Response(
+                {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
+            )
+
+
+class GWCSeedEstimateView(APIView):
+    """Estimate tile count and storage for a seed task."""
+
+    def post(self, request, conn_id, workspace, layer):
+        """Estimate a seed before launching it.
+
+        Layer bounds are taken from GeoServer unless explicit bounds are
+        given in the grid set's SRS.
+
+        Expected body:
+        {
+            "gridSet": "EPSG:900913",
+            "zoomStart": 0,
+            "zoomStop": 14,
+            "format": "image/png",
+            "bounds": [minx, miny, maxx, maxy],  // optional
+            "boundsSrs": "EPSG:4326",  // optional, defaults to the grid set's SRS
+            "avgTileBytes": 16384  // optional
+        }
+        """
+        try:
+            client = get_gwc_client(conn_id)
+
+            grid_set = request.data.get("gridSet", "EPSG:4326")
+            tile_format = request.data.get("format", "image/png")
+            bounds = request.data.get("bounds")
+            avg_tile_bytes = request.data.get("avgTileBytes")
+            try:
+                zoom_start = int(request.data.get("zoomStart", 0))
+                zoom_stop = int(request.data.get("zoomStop", 10))
+                if bounds is not None:
+                    bounds = parse_bounds(bounds)
+                if avg_tile_bytes is not None:
+                    avg_tile_bytes = int(avg_tile_bytes)
+            except (TypeError, ValueError) as e:
+                return Response(
+                    {"error": f"Invalid estimate parameters: {e}"},
+                    status=status.HTTP_400_BAD_REQUEST,
+                )
+
+            if zoom_stop < zoom_start:
+                return Response(
+                    {"error": "zoomStop must be greater than or equal to zoomStart"},
+                    status=status.HTTP_400_BAD_REQUEST,
+                )
+
+            gridset_data = client.get_gridset(grid_set)
+            bounds_srs = request.data.get("boundsSrs")
+            if bounds is not None and bounds_srs:
+                gridset_srs = parse_gridset(gridset_data).srs or grid_set
+                bounds = transform_bounds(bounds, bounds_srs, gridset_srs)
+
+            layer_bounds: dict = {}
+            if bounds is None:
+                gs_client = get_geoserver_client(conn_id)
+                layer_bounds = gs_client.get_layer_bounds(workspace, layer)
+
+            estimate = estimate_seed(
+                gridset_data,
+                zoom_start,
+                zoom_stop,
+                tile_format=tile_format,
+                latlon_bbox=layer_bounds.get("latLonBoundingBox"),
+                native_bbox=layer_bounds.get("nativeBoundingBox"),
+                bounds=bounds,
+                avg_tile_bytes=avg_tile_bytes,
+            )
+
+            return Response(estimate.to_dict())
+        except ValueError as e:
+            return Response(
+                {"error": str(e)}, status=status.HTTP_422_UNPROCESSABLE_ENTITY
+            )
+        except GeoServerError as e:
+            return Response(
+                {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
+            )
+
+
 class GWCTruncateView(APIView):
     """Truncate tiles for a layer."""
 
@@ -221,25 +518,274 @@ class GWCDiskQuotaView(APIView):
 
 
 class GWCMassTruncateView(APIView):
-    """Mass truncate tiles."""
+    """Truncate the tile caches of many layers in the background."""
 
     def post(self, request, conn_id):
-        """Mass truncate tiles.
+        """Start truncating the matching layers.
 
         Expected body:
         {
             "workspace": "optional_workspace",
-            "layer": "optional_layer_pattern"
+            "layer": "optional_layer_pattern",
+            "layers": ["optional", "explicit", "layer", "names"]
+        }
+        or, to truncate the layers an interrupted truncation didn't get to:
+        {
+            "resume": "operation-id"
         }
         """
         try:
             client = get_gwc_client(conn_id)
-            workspace = request.data.get("workspace")
-            layer = request.data.get("layer")
+            resume = request.data.get("resume")
+            if resume:
+                operation = operation_log.get(resume)
+                if (
+                    operation is None
+                    or operation.kind != "truncate"
+                    or operation.conn_id != conn_id
+                    or (operation := operation_log.resume(resume)) is None
+                ):
+                    return Response(
+                        {"error": "No interrupted truncation with that ID"},
+                        status=status.HTTP_404_NOT_FOUND,
+                    )
+                job = get_mass_truncate_jobs().start(conn_id, client, [], operation=operation)
+                return Response(job.to_dict(), status=status.HTTP_202_ACCEPTED)
+
+            layers = request.data.get("layers")
+            if not layers:
+                layers = matching_layers(
+                    client, request.data.get("workspace"), request.data.get("layer")
+                )
+            if not layers:
+                return Response(
+                    {"error": "No cached layers match"}, status=status.HTTP_404_NOT_FOUND
+                )
+
+            job = get_mass_truncate_jobs().start(conn_id, client, layers)
+            return Response(job.to_dict(), status=status.HTTP_202_ACCEPTED)
+        except GeoServerError as e:
+            return Response(
+                {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
+            )
 
-            result = client.mass_truncate(workspace=workspace, layer=layer)
-            return Response(result)
+
+class GWCMassTruncateJobView(APIView):
+    """Progress of a mass truncation."""
+
+    def get(self, request, conn_id, job_id):
+        """Get per-layer results so far and a summary."""
+        job = get_mass_truncate_jobs().get_job(job_id)
+        if not job or job.conn_id != conn_id:
+            return Response({"error": "Job not found"}, status=status.HTTP_404_NOT_FOUND)
+        return Response(job.to_dict())
+
+
+class GWCStyleCacheView(APIView):
+    """Inspect and truncate the tile caches affected by a style."""
+
+    def get(self, request, conn_id, workspace, style):
+        """List the layers rendering with a style and how they use it."""
+        try:
+            layers = find_style_layers(conn_id, workspace, style)
+            return Response({"layers": layers})
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return Response(
+                {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
+            )
+
+    def post(self, request, conn_id, workspace, style):
+        """Truncate the cached tiles rendered with a style.
+
+        Expected body (optional):
+        {
+            "layers": ["workspace:layer"]  // restrict to these layers
+        }
+        """
+        try:
+            affected = find_style_layers(conn_id, workspace, style)
+            selected = request.data.get("layers")
+            if selected is not None:
+                affected = [a for a in affected if a["layer"] in selected]
+
+            truncated = truncate_style_layers(conn_id, workspace, style, affected)
+            return Response({"status": "truncated", "truncatedLayers": truncated})
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
         except GeoServerError as e:
             return Response(
                 {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
             )
+
+
+def _schedule_to_dict(schedule: CacheSchedule) -> dict:
+    """Serialize a cache schedule for the API."""
+    return {
+        "id": schedule.id,
+        "name": schedule.name,
+        "connectionId": schedule.connection_id,
+        "layer": schedule.layer,
+        "cron": schedule.cron,
+        "operation": schedule.operation,
+        "gridSet": schedule.grid_set,
+        "format": schedule.format,
+        "zoomStart": schedule.zoom_start,
+        "zoomStop": schedule.zoom_stop,
+        "threads": schedule.threads,
+        "bounds": schedule.bounds,
+        "enabled": schedule.enabled,
+        "createdAt": schedule.created_at,
+        "lastRunAt": schedule.last_run_at,
+        "lastStatus": schedule.last_status,
+        "lastError": schedule.last_error,
+        "nextRunAt": next_run_time(schedule),
+    }
+
+
+def _apply_schedule_data(schedule: CacheSchedule, data: dict) -> None:
+    """Apply API fields from a request body to a schedule."""
+    fields = {
+        "name": "name",
+        "connectionId": "connection_id",
+        "layer": "layer",
+        "cron": "cron",
+        "operation": "operation",
+        "gridSet": "grid_set",
+        "format": "format",
+        "zoomStart": "zoom_start",
+        "zoomStop": "zoom_stop",
+        "threads": "threads",
+        "bounds": "bounds",
+        "enabled": "enabled",
+    }
+    for key, attr in fields.items():
+        if key in data:
+            setattr(schedule, attr, data[key])
+    schedule.zoom_start = int(schedule.zoom_start)
+    schedule.zoom_stop = int(schedule.zoom_stop)
+    schedule.threads = int(schedule.threads)
+    if schedule.bounds is not None:
+        schedule.bounds = list(parse_bounds(schedule.bounds))
+
+
+class GWCScheduleListView(APIView):
+    """List and create scheduled cache tasks."""
+
+    def get(self, request):
+        """List all cache schedules.
+
+        Query params:
+        - connectionId: Optional connection filter
+        """
+        schedules = get_config().list_cache_schedules()
+        conn_id = request.query_params.get("connectionId")
+        if conn_id:
+            schedules = [s for s in schedules if s.connection_id == conn_id]
+        return Response({"schedules": [_schedule_to_dict(s) for s in schedules]})
+
+    def post(self, request):
+        """Create a cache schedule.
+
+        Expected body:
+        {
+            "name": "Nightly roads reseed",
+            "connectionId": "conn_123",
+            "layer": "topp:roads",
+            "cron": "0 2 * * *",
+            "operation": "reseed",  // seed, reseed, or truncate
+            "gridSet": "EPSG:900913",
+            "format": "image/png",
+            "zoomStart": 0,
+            "zoomStop": 12,
+            "threads": 2,
+            "enabled": true
+        }
+        """
+        data = request.data
+        missing = [k for k in ("name", "connectionId", "layer", "cron") if not data.get(k)]
+        if missing:
+            return Response(
+                {"error": f"Missing required fields: {', '.join(missing)}"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        config = get_config()
+        if not config.get_connection(data["connectionId"]):
+            return Response(
+                {"error": "Connection not found"},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+
+        try:
+            schedule = CacheSchedule(
+                name=data["name"],
+                connection_id=data["connectionId"],
+                layer=data["layer"],
+                cron=data["cron"],
+            )
+            _apply_schedule_data(schedule, data)
+            validate_schedule(schedule)
+        except (TypeError, ValueError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
+        config.add_cache_schedule(schedule)
+        return Response(_schedule_to_dict(schedule), status=status.HTTP_201_CREATED)
+
+
+class GWCScheduleDetailView(APIView):
+    """Get, update, or delete a scheduled cache task."""
+
+    def get(self, request, schedule_id):
+        """Get a cache schedule."""
+        schedule = get_config().get_cache_schedule(schedule_id)
+        if not schedule:
+            return Response(
+                {"error": "Schedule not found"},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+        return Response(_schedule_to_dict(schedule))
+
+    def put(self, request, schedule_id):
+        """Update a cache schedule."""
+        config = get_config()
+        existing = config.get_cache_schedule(schedule_id)
+        if not existing:
+            return Response(
+                {"error": "Schedule not found"},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+
+        schedule = existing.model_copy()
+        try:
+            _apply_schedule_data(schedule, request.data)
+            validate_schedule(schedule)
+        except (TypeError, ValueError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
+        config.update_cache_schedule(schedule)
+        return Response(_schedule_to_dict(schedule))
+
+    def delete(self, request, schedule_id):
+        """Delete a cache schedule."""
+        if not get_config().remove_cache_schedule(schedule_id):
+            return Response(
+                {"error": "Schedule not found"},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+        return Response(status=status.HTTP_204_NO_CONTENT)
+
+
+class GWCScheduleRunView(APIView):
+    """Run a scheduled cache task immediately."""
+
+    def post(self, request, schedule_id):
+        """Trigger a cache schedule now."""
+        schedule = get_cache_scheduler().run_schedule(schedule_id)
+        if not schedule:
+            return Response(
+                {"error": "Schedule not found"},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+        return Response(_schedule_to_dict(schedule))
@@ -3,7 +3,7 @@
 Provides endpoints for:
 - Starting a preview session
 - Getting layer information
-- Getting layer metadata from GeoServer
+- Getting layer and layer group metadata from GeoServer
 """
 
 import uuid
@@ -174,7 +174,8 @@ class PreviewLayerView(APIView):
         try:
             # Get the GeoServer URL from the connection
             client = get_geoserver_client(session.conn_id)
-            geoserver_url = client.connection.url.rstrip("/")
+            geoserver_url = client.connection.public_base_url
+            service = "wcs" if session.layer_type == "raster" else "wfs"
 
             return Response({
                 "name": session.layer_name,
@@ -182,6 +183,10 @@ class PreviewLayerView(APIView):
                 "store_name": session.store_name or "",
                 "store_type": session.store_type or "datastore",
                 "geoserver_url": geoserver_url,
+                "wms_url": client.service_url("wms", session.workspace),
+                "data_url": client.service_url(service, session.workspace),
+                # Map requests go through the backend, which adds the credentials
+                "proxy_url": f"/api/geoserver/{session.conn_id}/ows",
                 "type": session.layer_type,
                 "use_cache": session.use_cache,
                 "grid_set": session.grid_set,
@@ -194,6 +199,30 @@ class PreviewLayerView(APIView):
             )
 
 
+def _group_metadata(client, session: PreviewSession) -> dict:
+    """Metadata for a layer group, whose extent covers the layers it draws."""
+    group = client.get_layergroup(session.layer_name, session.workspace)
+    metadata = {
+        "layer_enabled": group.get("enabled", True) is not False,
+        "layer_queryable": group.get("queryDisabled") is not True,
+        "layer_advertised": group.get("advertised", True) is not False,
+        "layer_title": group.get("title", ""),
+        "layer_abstract": group.get("abstractTxt", ""),
+        "store_format": "Layer Group",
+    }
+
+    crs = (group.get("bounds") or {}).get("crs")
+    if isinstance(crs, dict):
+        crs = crs.get("$")
+    if crs:
+        metadata["layer_srs"] = crs
+
+    bbox = client.get_layergroup_bounds(session.layer_name, session.workspace)["latLonBoundingBox"]
+    if bbox:
+        metadata["latlon_bbox"] = {key: bbox[key] for key in ("minx", "miny", "maxx", "maxy")}
+    return metadata
+
+
 class PreviewMetadataView(APIView):
     """Get layer metadata for a preview session."""
 
@@ -212,6 +241,9 @@ class PreviewMetadataView(APIView):
         try:
             client = get_geoserver_client(session.conn_id)
 
+            if session.layer_type == "group":
+                return Response(_group_metadata(client, session))
+
             # Use the client's get_layer_metadata method
             layer_meta = client.get_layer_metadata(session.workspace, session.layer_name)
 
@@ -244,9 +276,8 @@ class PreviewMetadataView(APIView):
                 if resource_href:
                     try:
                         # Fetch resource details
-                        response = client._client.get(resource_href)
-                        if response.status_code == 200:
-                            data = response.json()
+                        data = client.get_href(resource_href)
+                        if data:
                             # Try featureType first, then coverage
                             ft = data.get("featureType", {})
                             cov = data.get("coverage", {})
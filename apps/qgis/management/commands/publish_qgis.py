@@ -0,0 +1,98 @@
+"""Publish a QGIS project's layers, styles and layer tree to GeoServer.
+
+Usage:
+    cloudbench publish_qgis ./city.qgz "Production GeoServer" --workspace city
+    cloudbench publish_qgis ./city.qgs conn_123 -w city --group "City Map"
+    cloudbench publish_qgis ./city.qgz conn_123 -w city --dry-run
+"""
+
+from pathlib import Path
+
+from django.core.management.base import CommandError
+
+from apps.core.completion import fuzzy_pick
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.core.output import OutputCommand
+from apps.geoserver.client import get_geoserver_client
+from apps.qgis.project import read_project
+from apps.qgis.publish import publish_project
+
+COLUMNS = ["source", "layer", "store", "status", "style", "message"]
+
+
+class Command(OutputCommand):
+    """Publish every layer of a .qgs or .qgz project and group them as its layer tree does."""
+
+    quiet_key = "layer"
+    help = (
+        "Publish a QGIS project's PostGIS, GeoPackage, shapefile and GeoTIFF layers, "
+        "their basic symbology as SLD and a layer group of its layer tree"
+    )
+
+    def add_arguments(self, parser):
+        """Add command arguments."""
+        parser.add_argument("source", help="A .qgs or .qgz project file")
+        parser.add_argument(
+            "connection", nargs="?", help="Connection ID or name; picked interactively if omitted"
+        )
+        parser.add_argument("--workspace", "-w", required=True, help="Workspace to publish into")
+        parser.add_argument(
+            "--group", help="Title of the project's layer group; the project's title by default"
+        )
+        parser.add_argument(
+            "--dry-run", action="store_true", help="List the project's layers without publishing"
+        )
+        self.add_output_arguments(parser)
+
+    def handle(self, *args, **options):
+        """Publish the project and print what happened to each layer."""
+        try:
+            project = read_project(Path(options["source"]).expanduser())
+        except (OSError, ValueError) as e:
+            raise CommandError(str(e)) from e
+
+        if options["dry_run"]:
+            records = [
+                {
+                    "source": project.layers[layer_id].name,
+                    "provider": project.layers[layer_id].provider,
+                    "kind": project.layers[layer_id].kind,
+                    "datasource": project.layers[layer_id].source,
+                }
+                for layer_id in dict.fromkeys(project.tree.layer_ids())
+                if layer_id in project.layers
+            ]
+            self.write_records(records, options)
+            return
+
+        ref = options["connection"] or fuzzy_pick(
+            [c.name for c in config_manager.list_connections()], "connection"
+        )
+        if not ref:
+            raise CommandError("A connection is required")
+        conn = config_manager.get_connection(ref) or next(
+            (c for c in config_manager.list_connections() if c.name == ref), None
+        )
+        if conn is None:
+            raise CommandError(f"Connection not found: {ref}")
+
+        try:
+            result = publish_project(
+                get_geoserver_client(conn.id), project, options["workspace"], options["group"]
+            )
+        except GeoServerError as e:
+            raise CommandError(e.message) from e
+
+        self.write_records([outcome.to_dict() for outcome in result.layers], options, COLUMNS)
+        if not options["quiet"] and options["output"] == "table":
+            counts = ", ".join(
+                f"{result.count(status)} {status}" for status in ("published", "skipped", "error")
+            )
+            self.stdout.write(f"\n{counts}")
+            for group in result.groups:
+                self.stdout.write(f"Layer group {group}")
+        for error in result.errors:
+            self.stderr.write(error)
+        if result.errors or result.count("error"):
+            raise SystemExit(1)
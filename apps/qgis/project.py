@@ -0,0 +1,232 @@
+"""Reading QGIS project files.
+
+A .qgs file is XML; a .qgz file is a zip holding one. The parts read here
+are the map layers under <projectlayers>, each with its data source,
+provider, CRS and renderer, and the <layer-tree-group> that orders them
+into the layer panel's tree of groups.
+
+Data sources are written by the provider: PostGIS layers as a connection
+string ending in the table and geometry column, file layers as a path
+optionally followed by |layername=... for multi-table files.
+"""
+
+import shlex
+import xml.etree.ElementTree as ET
+import zipfile
+from dataclasses import dataclass, field
+from pathlib import Path
+
+# Providers read as PostGIS tables or as files
+POSTGRES_PROVIDERS = ("postgres",)
+FILE_PROVIDERS = ("ogr", "gdal")
+
+
+@dataclass
+class PostgresSource:
+    """A PostGIS table a layer reads."""
+
+    host: str = ""
+    port: str = ""  # Empty for the service's or PostgreSQL's default
+    database: str = ""
+    user: str = ""
+    password: str = ""
+    service: str = ""
+    schema: str = "public"
+    table: str = ""
+    geometry_column: str = ""
+
+
+@dataclass
+class FileSource:
+    """A file a layer reads, with the table of multi-table files."""
+
+    path: Path
+    layer: str = ""
+
+    @property
+    def extension(self) -> str:
+        return self.path.suffix.lower().lstrip(".")
+
+
+@dataclass
+class ProjectLayer:
+    """A map layer of a project."""
+
+    id: str
+    name: str
+    kind: str  # vector or raster
+    provider: str
+    source: str
+    geometry: str = ""  # Point, Line or Polygon for vector layers
+    srs: str = ""
+    renderer: ET.Element | None = None
+
+    def postgres_source(self) -> PostgresSource | None:
+        """The PostGIS table of the layer, or None for other providers."""
+        if self.provider not in POSTGRES_PROVIDERS:
+            return None
+        return parse_postgres_source(self.source)
+
+    def file_source(self, project_dir: Path) -> FileSource | None:
+        """The file of the layer, or None for other providers."""
+        if self.provider not in FILE_PROVIDERS:
+            return None
+        return parse_file_source(self.source, project_dir)
+
+
+@dataclass
+class TreeNode:
+    """A group or layer of the layer tree; layers have a layer ID."""
+
+    name: str
+    layer_id: str | None = None
+    children: list["TreeNode"] = field(default_factory=list)
+
+    def layer_ids(self) -> list[str]:
+        """The layers under the node, top of the layer panel first."""
+        if self.layer_id:
+            return [self.layer_id]
+        return [layer_id for child in self.children for layer_id in child.layer_ids()]
+
+
+@dataclass
+class QGISProjectFile:
+    """The layers of a project and their tree."""
+
+    path: Path
+    title: str
+    layers: dict[str, ProjectLayer]
+    tree: TreeNode
+
+
+def _unquote(value: str) -> str:
+    return value[1:-1] if len(value) > 1 and value[0] == value[-1] in "'\"" else value
+
+
+def parse_postgres_source(source: str) -> PostgresSource:
+    """Read a PostGIS data source string.
+
+    Example:
+        dbname='gis' host=db port=5432 user='me' key='id' srid=4326 type=Polygon
+        table="public"."roads" (geom)
+    """
+    result = PostgresSource()
+    table_part = ""
+    if " table=" in f" {source}":
+        source, _, table_part = f" {source}".partition(" table=")
+    try:
+        tokens = shlex.split(source)
+    except ValueError:
+        tokens = source.split()
+    keys = {
+        "host": "host",
+        "port": "port",
+        "dbname": "database",
+        "user": "user",
+        "password": "password",
+        "service": "service",
+    }
+    for token in tokens:
+        key, _, value = token.partition("=")
+        if key in keys:
+            setattr(result, keys[key], value)
+
+    table_part = table_part.partition(" sql=")[0].strip()
+    if "(" in table_part:
+        table_part, _, column = table_part.partition("(")
+        result.geometry_column = column.rstrip(")").strip()
+    parts = [_unquote(p.strip()) for p in table_part.strip().split(".", 1)]
+    if len(parts) == 2:
+        result.schema, result.table = parts
+    elif parts and parts[0]:
+        result.table = parts[0]
+    return result
+
+
+def parse_file_source(source: str, project_dir: Path) -> FileSource:
+    """Read a file data source, resolving relative paths from the project's folder."""
+    path_part, *options = source.split("|")
+    layer = ""
+    for option in options:
+        key, _, value = option.partition("=")
+        if key == "layername":
+            layer = value
+    path = Path(path_part).expanduser()
+    if not path.is_absolute():
+        path = (project_dir / path).resolve()
+    return FileSource(path, layer)
+
+
+def _read_layer(element: ET.Element) -> ProjectLayer:
+    geometry = element.get("geometry", "")
+    # Older projects only record the geometry on the renderer's symbols
+    if not geometry and element.get("type") == "vector":
+        symbol = element.find(".//renderer-v2//symbol")
+        geometry = {"marker": "Point", "line": "Line", "fill": "Polygon"}.get(
+            symbol.get("type", "") if symbol is not None else "", ""
+        )
+    return ProjectLayer(
+        id=element.findtext("id", ""),
+        name=element.findtext("layername", ""),
+        kind=element.get("type", ""),
+        provider=element.findtext("provider", ""),
+        source=element.findtext("datasource", ""),
+        geometry=geometry,
+        srs=element.findtext("srs/spatialrefsys/authid", ""),
+        renderer=element.find("renderer-v2"),
+    )
+
+
+def _read_tree(element: ET.Element) -> TreeNode:
+    node = TreeNode(element.get("name", ""))
+    for child in element:
+        if child.tag == "layer-tree-group":
+            node.children.append(_read_tree(child))
+        elif child.tag == "layer-tree-layer":
+            node.children.append(TreeNode(child.get("name", ""), layer_id=child.get("id")))
+    return node
+
+
+def parse_project(text: str | bytes, path: Path) -> QGISProjectFile:
+    """Read the layers and layer tree of a project's XML.
+
+    Raises:
+        ValueError: If the XML isn't a QGIS project
+    """
+    try:
+        root = ET.fromstring(text)
+    except ET.ParseError as e:
+        raise ValueError(f"{path.name} is not a valid QGIS project: {e}") from e
+    if root.tag != "qgis":
+        raise ValueError(f"{path.name} is not a QGIS project")
+
+    layers = {}
+    for element in root.iterfind("projectlayers/maplayer"):
+        layer = _read_layer(element)
+        layers[layer.id] = layer
+
+    tree_element = root.find("layer-tree-group")
+    if tree_element is not None:
+        tree = _read_tree(tree_element)
+    else:
+        tree = TreeNode("", children=[TreeNode(layer.name, layer.id) for layer in layers.values()])
+    title = root.findtext("title") or root.get("projectname") or path.stem
+    return QGISProjectFile(path, title, layers, tree)
+
+
+def read_project(path: Path) -> QGISProjectFile:
+    """Read a .qgs file, or the .qgs inside a .qgz file.
+
+    Raises:
+        ValueError: If the file isn't a readable QGIS project
+    """
+    if path.suffix.lower() == ".qgz":
+        try:
+            with zipfile.ZipFile(path) as archive:
+                name = next((n for n in archive.namelist() if n.lower().endswith(".qgs")), None)
+                if name is None:
+                    raise ValueError(f"{path.name} holds no .qgs file")
+                return parse_project(archive.read(name), path)
+        except zipfile.BadZipFile as e:
+            raise ValueError(f"{path.name} is not a valid .qgz file") from e
+    return parse_project(path.read_bytes(), path)
@@ -0,0 +1,352 @@
+"""Publishing a QGIS project's layers to GeoServer.
+
+Each layer of the project is published from where it reads its data:
+
+- PostGIS tables through a PostGIS data store of the workspace pointing at
+  the same database and schema, created when there is none;
+- GeoPackages and shapefiles next to the project by uploading them into a
+  data store named after the file, reused when it already exists;
+- GeoTIFFs by uploading them as a coverage store.
+
+Layers of other providers, such as WMS or XYZ tiles, are skipped. Basic
+symbology becomes an SLD style of the workspace (see symbology), and the
+project's layer tree becomes a layer group, with a nested group for every
+group of the tree.
+"""
+
+import io
+import logging
+import zipfile
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.publish import NamingRules, plan_layer_names, sanitize_name
+from apps.postgres.service import get_service
+
+from .project import FileSource, PostgresSource, ProjectLayer, QGISProjectFile, TreeNode
+from .symbology import renderer_to_sld
+
+if TYPE_CHECKING:
+    from apps.geoserver.client import GeoServerClient
+
+logger = logging.getLogger(__name__)
+
+# Files of a shapefile uploaded with it
+SHAPEFILE_PARTS = ("shp", "shx", "dbf", "prj", "cpg", "qix")
+
+RASTER_EXTENSIONS = ("tif", "tiff")
+
+STATUSES = ("published", "skipped", "error")
+
+
+@dataclass
+class LayerOutcome:
+    """What publishing one project layer did."""
+
+    source: str  # The layer's name in the project
+    layer: str = ""
+    store: str = ""
+    status: str = "published"
+    style: str = ""  # Converted style; empty when the layer keeps the default
+    message: str = ""
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "source": self.source,
+            "layer": self.layer,
+            "store": self.store,
+            "status": self.status,
+            "style": self.style,
+            "message": self.message,
+        }
+
+
+@dataclass
+class ProjectPublishResult:
+    """The layers and layer groups published from a project."""
+
+    workspace: str
+    layers: list[LayerOutcome] = field(default_factory=list)
+    groups: list[str] = field(default_factory=list)  # Innermost first, the project's last
+    errors: list[str] = field(default_factory=list)
+
+    def count(self, status: str) -> int:
+        return sum(1 for outcome in self.layers if outcome.status == status)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "workspace": self.workspace,
+            "layers": [outcome.to_dict() for outcome in self.layers],
+            "groups": self.groups,
+            "errors": self.errors,
+            "counts": {status: self.count(status) for status in STATUSES},
+        }
+
+
+def _unique(name: str, taken: set[str]) -> str:
+    """A name not yet taken, numbered if needed, and taken from now on."""
+    unique = plan_layer_names([name], NamingRules(sanitize=False), taken)[name]
+    taken.add(unique)
+    return unique
+
+
+def _store_params(store: dict[str, Any]) -> dict[str, str]:
+    entries = store.get("connectionParameters", {}).get("entry", [])
+    if isinstance(entries, dict):
+        entries = [entries]
+    return {e.get("@key"): str(e.get("$", "")) for e in entries if isinstance(e, dict)}
+
+
+class ProjectPublisher:
+    """Publishes the layers of one project into one workspace."""
+
+    def __init__(self, client: "GeoServerClient", project: QGISProjectFile, workspace: str):
+        """Initialize the publisher.
+
+        Args:
+            client: GeoServer client
+            project: The project read from its file
+            workspace: Workspace to publish into
+        """
+        self.client = client
+        self.project = project
+        self.workspace = workspace
+        self.result = ProjectPublishResult(workspace)
+        # Layers and layer groups share their names within a workspace
+        self._layers = {layer.get("name", "") for layer in client.list_layers(workspace)} | {
+            group.get("name", "") for group in client.list_layergroups(workspace)
+        }
+        self._datastores = {s.get("name", "") for s in client.list_datastores(workspace)}
+        self._coveragestores = {s.get("name", "") for s in client.list_coveragestores(workspace)}
+        self._styles = {s.get("name", "") for s in client.list_styles(workspace)}
+        self._postgis_stores: dict[tuple[str, str, str, str], str] | None = None
+
+    def publish(self, group_name: str | None = None) -> ProjectPublishResult:
+        """Publish every layer of the layer tree, then group them as the tree does."""
+        published: dict[str, str] = {}
+        for layer_id in dict.fromkeys(self.project.tree.layer_ids()):
+            layer = self.project.layers.get(layer_id)
+            if layer is None:
+                continue
+            outcome = self.publish_layer(layer)
+            self.result.layers.append(outcome)
+            if outcome.status == "published":
+                published[layer_id] = f"{self.workspace}:{outcome.layer}"
+
+        root = TreeNode(group_name or self.project.title, children=self.project.tree.children)
+        if published:
+            try:
+                self._publish_group(root, published)
+            except GeoServerError as e:
+                self.result.errors.append(e.message)
+        return self.result
+
+    def publish_layer(self, layer: ProjectLayer) -> LayerOutcome:
+        """Publish one layer and its style; failures are recorded on the outcome."""
+        outcome = LayerOutcome(layer.name)
+        name = _unique(sanitize_name(layer.name), self._layers)
+        try:
+            postgres = layer.postgres_source()
+            source = layer.file_source(self.project.path.parent)
+            if postgres is not None:
+                outcome.store = self._publish_postgres(postgres, name, layer.name)
+            elif source is not None and layer.kind == "raster":
+                outcome.store = self._publish_raster(source, name)
+            elif source is not None:
+                outcome.store = self._publish_file(source, name, layer.name)
+            else:
+                self._layers.discard(name)
+                outcome.status = "skipped"
+                outcome.message = f"{layer.provider or 'This'} layers can't be published"
+                return outcome
+        except (GeoServerError, OSError, ValueError) as e:
+            self._layers.discard(name)
+            outcome.status = "error"
+            outcome.message = e.message if isinstance(e, GeoServerError) else str(e)
+            logger.warning("Publishing %s failed: %s", layer.name, outcome.message)
+            return outcome
+        outcome.layer = name
+
+        if layer.kind == "vector":
+            self._publish_style(layer, outcome)
+        return outcome
+
+    def _publish_style(self, layer: ProjectLayer, outcome: LayerOutcome) -> None:
+        """Convert the layer's symbology and make it the layer's default style."""
+        sld = renderer_to_sld(outcome.layer, layer.renderer)
+        if sld is None:
+            outcome.message = "Symbology not converted; the default style is used"
+            return
+        style = _unique(outcome.layer, self._styles)
+        try:
+            self.client.create_style(style, sld, "sld", self.workspace)
+            self.client.update_layer_styles(
+                self.workspace, outcome.layer, f"{self.workspace}:{style}"
+            )
+        except GeoServerError as e:
+            outcome.message = f"Style not applied: {e.message}"
+            return
+        outcome.style = style
+
+    def _postgis_store(self, source: PostgresSource) -> str:
+        """The workspace's PostGIS store for a table's database and schema, created if missing."""
+        if self._postgis_stores is None:
+            self._postgis_stores = {}
+            for store in self._datastores:
+                params = _store_params(self.client.get_datastore(self.workspace, store))
+                if params.get("dbtype") == "postgis":
+                    key = (
+                        params.get("host", ""),
+                        params.get("port", "5432"),
+                        params.get("database", ""),
+                        params.get("schema", "public"),
+                    )
+                    self._postgis_stores.setdefault(key, store)
+
+        key = (source.host, source.port, source.database, source.schema)
+        if key in self._postgis_stores:
+            return self._postgis_stores[key]
+
+        store = _unique(sanitize_name(f"{source.database}_{source.schema}"), self._datastores)
+        self.client.create_datastore(
+            self.workspace,
+            store,
+            {
+                "dbtype": "postgis",
+                "host": source.host,
+                "port": source.port,
+                "database": source.database,
+                "user": source.user,
+                "passwd": source.password,
+                "schema": source.schema,
+                "Expose primary keys": "true",
+            },
+            description=f"PostGIS store for the QGIS project {self.project.title}",
+        )
+        self._postgis_stores[key] = store
+        return store
+
+    def _publish_postgres(self, source: PostgresSource, name: str, title: str) -> str:
+        if source.service:
+            service = get_service(source.service)
+            if service is None:
+                raise ValueError(f"PostgreSQL service not found: {source.service}")
+            source.host = source.host or service.host
+            source.port = source.port or str(service.port)
+            source.database = source.database or service.dbname
+            source.user = source.user or service.user
+            source.password = source.password or service.password
+        if not source.table or not source.database:
+            raise ValueError("The layer's table or database is missing from its source")
+        source.port = source.port or "5432"
+        store = self._postgis_store(source)
+        self.client.create_featuretype(
+            self.workspace, store, name, native_name=source.table, title=title, srs=None
+        )
+        return store
+
+    def _publish_file(self, source: FileSource, name: str, title: str) -> str:
+        if source.extension not in ("gpkg", "shp"):
+            raise ValueError(f".{source.extension} files can't be published")
+        if not source.path.is_file():
+            raise ValueError(f"File not found: {source.path}")
+
+        store = sanitize_name(source.path.stem)
+        if store not in self._datastores:
+            if source.extension == "gpkg":
+                self.client.upload_geopackage(
+                    self.workspace, store, source.path.read_bytes(), configure="none"
+                )
+            else:
+                self.client.upload_shapefile(
+                    self.workspace, store, self._zip_shapefile(source), configure="none"
+                )
+            self._datastores.add(store)
+        table = source.layer or source.path.stem
+        self.client.create_featuretype(
+            self.workspace, store, name, native_name=table, title=title, srs=None
+        )
+        return store
+
+    def _publish_raster(self, source: FileSource, name: str) -> str:
+        if source.extension not in RASTER_EXTENSIONS:
+            raise ValueError(f".{source.extension} rasters can't be published")
+        if not source.path.is_file():
+            raise ValueError(f"File not found: {source.path}")
+        store = _unique(name, self._coveragestores)
+        self.client.upload_geotiff(
+            self.workspace, store, source.path.read_bytes(), coverage_name=name
+        )
+        return store
+
+    def _zip_shapefile(self, source: FileSource) -> bytes:
+        buffer = io.BytesIO()
+        with zipfile.ZipFile(buffer, "w", zipfile.ZIP_DEFLATED) as archive:
+            for extension in SHAPEFILE_PARTS:
+                for part in (extension, extension.upper()):
+                    path = source.path.with_suffix(f".{part}")
+                    if path.is_file():
+                        archive.write(path, f"{source.path.stem}.{extension}")
+                        break
+        return buffer.getvalue()
+
+    def _publish_group(self, node: TreeNode, published: dict[str, str]) -> str | None:
+        """Create the layer group of a tree group, after those of its subgroups.
+
+        Returns:
+            The group's qualified name, or None when none of its layers was published
+        """
+        members: list[str] = []
+        nested: set[str] = set()
+        for child in node.children:
+            if child.layer_id:
+                if child.layer_id in published:
+                    members.append(published[child.layer_id])
+            else:
+                group = self._publish_group(child, published)
+                if group:
+                    members.append(group)
+                    nested.add(group)
+        if not members:
+            return None
+
+        name = _unique(sanitize_name(node.name or self.project.title), self._layers)
+        # The layer panel lists the top layer first; GeoServer draws the first layer first
+        self.client.create_layergroup(
+            name,
+            list(reversed(members)),
+            self.workspace,
+            title=node.name or self.project.title,
+            nested=nested,
+        )
+        qualified = f"{self.workspace}:{name}"
+        self.result.groups.append(qualified)
+        return qualified
+
+
+def publish_project(
+    client: "GeoServerClient",
+    project: QGISProjectFile,
+    workspace: str,
+    group_name: str | None = None,
+) -> ProjectPublishResult:
+    """Publish a QGIS project's layers, styles and layer tree to a workspace.
+
+    A layer that fails is recorded and the rest are still published.
+
+    Args:
+        client: GeoServer client
+        project: The project read from its file
+        workspace: Workspace to publish into
+        group_name: Title of the layer group of the whole tree; the project's title by default
+
+    Returns:
+        What happened to each layer, and the layer groups created
+
+    Raises:
+        GeoServerError: If the workspace's layers and stores can't be listed
+    """
+    return ProjectPublisher(client, project, workspace).publish(group_name)
@@ -0,0 +1,260 @@
+"""Converting basic QGIS symbology to SLD.
+
+The renderers converted are single symbol, categorized and graduated,
+with symbols made of simple marker, simple line and simple fill symbol
+layers. Sizes and widths are turned from the millimetres QGIS uses by
+default into pixels at 96 DPI. Other renderers, such as rule based or
+heatmap ones, and other symbol layers, such as SVG markers, aren't
+converted; their layers keep GeoServer's default style.
+"""
+
+import xml.etree.ElementTree as ET
+
+from apps.geoserver.sld import SLD_10, SLD_NS
+
+OGC_NS = "http://www.opengis.net/ogc"
+
+# Pixels per millimetre at 96 DPI
+PIXELS_PER_MM = 96 / 25.4
+
+# QGIS marker names GeoServer's well-known marks can draw
+MARKS = {
+    "circle": "circle",
+    "square": "square",
+    "rectangle": "square",
+    "triangle": "triangle",
+    "equilateral_triangle": "triangle",
+    "star": "star",
+    "cross": "cross",
+    "cross2": "x",
+    "diamond": "square",
+}
+
+# Defaults QGIS falls back to when a symbol layer leaves a property out
+DEFAULT_SIZE_MM = 2.0
+DEFAULT_WIDTH_MM = 0.26
+
+
+def _sld(tag: str) -> str:
+    return f"{{{SLD_NS}}}{tag}"
+
+
+def _ogc(tag: str) -> str:
+    return f"{{{OGC_NS}}}{tag}"
+
+
+def symbol_properties(symbol_layer: ET.Element) -> dict[str, str]:
+    """The properties of a symbol layer, in either project file format."""
+    props = {p.get("k", ""): p.get("v", "") for p in symbol_layer.iterfind("prop")}
+    for option in symbol_layer.iterfind("Option/Option"):
+        if option.get("name"):
+            props[option.get("name", "")] = option.get("value", "")
+    return props
+
+
+def qgis_color(value: str) -> tuple[str, float] | None:
+    """A QGIS "r,g,b,a" color as a hex color and an opacity."""
+    try:
+        r, g, b, a = (int(part) for part in value.split(",")[:4])
+    except ValueError:
+        return None
+    return f"#{r:02x}{g:02x}{b:02x}", round(a / 255, 2)
+
+
+def to_pixels(value: str, unit: str, default_mm: float) -> float:
+    """A size in the symbol's unit as pixels."""
+    try:
+        size = float(value)
+    except ValueError:
+        size = default_mm
+        unit = "MM"
+    if unit in ("Pixel", "Point"):
+        return round(size * (96 / 72 if unit == "Point" else 1), 2)
+    return round(size * PIXELS_PER_MM, 2)
+
+
+def _param(parent: ET.Element, name: str, value: str | float) -> None:
+    ET.SubElement(parent, _sld("CssParameter"), name=name).text = str(value)
+
+
+def _fill(parent: ET.Element, color: str) -> None:
+    parsed = qgis_color(color)
+    if parsed is None:
+        return
+    fill = ET.SubElement(parent, _sld("Fill"))
+    _param(fill, "fill", parsed[0])
+    if parsed[1] < 1:
+        _param(fill, "fill-opacity", parsed[1])
+
+
+def _stroke(parent: ET.Element, color: str, width: float, style: str = "solid") -> None:
+    parsed = qgis_color(color)
+    if parsed is None or style == "no":
+        return
+    stroke = ET.SubElement(parent, _sld("Stroke"))
+    _param(stroke, "stroke", parsed[0])
+    _param(stroke, "stroke-width", width)
+    if parsed[1] < 1:
+        _param(stroke, "stroke-opacity", parsed[1])
+    if style in ("dash", "dot", "dash dot"):
+        _param(stroke, "stroke-dasharray", {"dash": "6 3", "dot": "1 3"}.get(style, "6 3 1 3"))
+
+
+def _symbolizer(symbol_layer: ET.Element) -> ET.Element | None:
+    """The SLD symbolizer of a QGIS symbol layer, or None if it can't be converted."""
+    props = symbol_properties(symbol_layer)
+    kind = symbol_layer.get("class")
+    if kind == "SimpleMarker":
+        symbolizer = ET.Element(_sld("PointSymbolizer"))
+        graphic = ET.SubElement(symbolizer, _sld("Graphic"))
+        mark = ET.SubElement(graphic, _sld("Mark"))
+        well_known_name = ET.SubElement(mark, _sld("WellKnownName"))
+        well_known_name.text = MARKS.get(props.get("name", ""), "circle")
+        _fill(mark, props.get("color", ""))
+        _stroke(
+            mark,
+            props.get("outline_color", ""),
+            to_pixels(props.get("outline_width", ""), props.get("outline_width_unit", ""), 0),
+            props.get("outline_style", "solid"),
+        )
+        ET.SubElement(graphic, _sld("Size")).text = str(
+            to_pixels(props.get("size", ""), props.get("size_unit", ""), DEFAULT_SIZE_MM)
+        )
+        return symbolizer
+    if kind == "SimpleLine":
+        symbolizer = ET.Element(_sld("LineSymbolizer"))
+        _stroke(
+            symbolizer,
+            props.get("line_color", props.get("color", "")),
+            to_pixels(
+                props.get("line_width", ""), props.get("line_width_unit", ""), DEFAULT_WIDTH_MM
+            ),
+            props.get("line_style", "solid"),
+        )
+        return symbolizer
+    if kind == "SimpleFill":
+        symbolizer = ET.Element(_sld("PolygonSymbolizer"))
+        if props.get("style", "solid") != "no":
+            _fill(symbolizer, props.get("color", ""))
+        _stroke(
+            symbolizer,
+            props.get("outline_color", ""),
+            to_pixels(
+                props.get("outline_width", ""),
+                props.get("outline_width_unit", ""),
+                DEFAULT_WIDTH_MM,
+            ),
+            props.get("outline_style", "solid"),
+        )
+        return symbolizer
+    return None
+
+
+def _symbolizers(symbol: ET.Element | None) -> list[ET.Element] | None:
+    """The symbolizers of a symbol, bottom layer first; None if any can't be converted."""
+    if symbol is None:
+        return None
+    symbolizers = []
+    for symbol_layer in symbol.iterfind("layer"):
+        if symbol_layer.get("enabled", "1") == "0":
+            continue
+        symbolizer = _symbolizer(symbol_layer)
+        if symbolizer is None:
+            return None
+        symbolizers.append(symbolizer)
+    return symbolizers or None
+
+
+def _rule(
+    feature_style: ET.Element,
+    title: str,
+    symbolizers: list[ET.Element],
+    condition: ET.Element | None = None,
+) -> None:
+    rule = ET.SubElement(feature_style, _sld("Rule"))
+    if title:
+        ET.SubElement(rule, _sld("Title")).text = title
+    if condition is not None and condition.tag == _sld("ElseFilter"):
+        rule.append(condition)
+    elif condition is not None:
+        ET.SubElement(rule, _ogc("Filter")).append(condition)
+    rule.extend(symbolizers)
+
+
+def _comparison(operator: str, attribute: str, value: str) -> ET.Element:
+    element = ET.Element(_ogc(operator))
+    ET.SubElement(element, _ogc("PropertyName")).text = attribute
+    ET.SubElement(element, _ogc("Literal")).text = value
+    return element
+
+
+def renderer_to_sld(name: str, renderer: ET.Element | None) -> str | None:
+    """Convert a layer's renderer to an SLD 1.0 style.
+
+    Args:
+        name: Style name
+        renderer: The layer's <renderer-v2> element
+
+    Returns:
+        The SLD document, or None if the renderer or one of its symbols
+        can't be converted
+    """
+    if renderer is None:
+        return None
+    symbols = {s.get("name"): s for s in renderer.iterfind("symbols/symbol")}
+    rules: list[tuple[str, list[ET.Element], ET.Element | None]] = []
+    kind = renderer.get("type")
+
+    if kind == "singleSymbol":
+        symbolizers = _symbolizers(symbols.get("0"))
+        if symbolizers is None:
+            return None
+        rules.append(("", symbolizers, None))
+    elif kind == "categorizedSymbol":
+        attribute = renderer.get("attr", "")
+        for category in renderer.iterfind("categories/category"):
+            if category.get("render", "true") == "false":
+                continue
+            symbolizers = _symbolizers(symbols.get(category.get("symbol")))
+            if symbolizers is None:
+                return None
+            value = category.get("value", "")
+            # The empty category draws every other value
+            if value:
+                condition = _comparison("PropertyIsEqualTo", attribute, value)
+            else:
+                condition = ET.Element(_sld("ElseFilter"))
+            rules.append((category.get("label") or value, symbolizers, condition))
+    elif kind == "graduatedSymbol":
+        attribute = renderer.get("attr", "")
+        for index, class_range in enumerate(renderer.iterfind("ranges/range")):
+            if class_range.get("render", "true") == "false":
+                continue
+            symbolizers = _symbolizers(symbols.get(class_range.get("symbol")))
+            if symbolizers is None:
+                return None
+            condition = ET.Element(_ogc("And"))
+            # The first range includes its lower bound, the rest only their upper one
+            lower = "PropertyIsGreaterThanOrEqualTo" if index == 0 else "PropertyIsGreaterThan"
+            condition.append(_comparison(lower, attribute, class_range.get("lower", "")))
+            condition.append(
+                _comparison("PropertyIsLessThanOrEqualTo", attribute, class_range.get("upper", ""))
+            )
+            rules.append((class_range.get("label", ""), symbolizers, condition))
+    else:
+        return None
+    if not rules:
+        return None
+
+    root = ET.Element(_sld("StyledLayerDescriptor"), version=SLD_10)
+    named_layer = ET.SubElement(root, _sld("NamedLayer"))
+    ET.SubElement(named_layer, _sld("Name")).text = name
+    user_style = ET.SubElement(named_layer, _sld("UserStyle"))
+    ET.SubElement(user_style, _sld("Name")).text = name
+    feature_style = ET.SubElement(user_style, _sld("FeatureTypeStyle"))
+    for title, symbolizers, condition in rules:
+        _rule(feature_style, title, symbolizers, condition)
+
+    ET.indent(root)
+    body = ET.tostring(root, encoding="unicode")
+    return f'<?xml version="1.0" encoding="UTF-8"?>\n{body}\n'
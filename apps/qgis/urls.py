@@ -16,6 +16,11 @@ urlpatterns = [
         views.QGISProjectDetailView.as_view(),
         name="qgis-project-detail",
     ),
+    path(
+        "qgis/projects/<str:project_id>/publish",
+        views.QGISProjectPublishView.as_view(),
+        name="qgis-project-publish",
+    ),
     # SQL View Publishing
     path(
         "sqlview",
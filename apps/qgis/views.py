@@ -2,6 +2,7 @@
 
 Provides endpoints for:
 - Listing and managing QGIS project files
+- Publishing QGIS projects to GeoServer
 - Publishing SQL views as GeoServer layers
 """
 
@@ -15,7 +16,11 @@ from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.config import QGISProject, get_config, get_qgis_projects_dir
-from apps.geoserver.client import GeoServerClientManager
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import GeoServerClientManager, get_geoserver_client
+
+from .project import read_project
+from .publish import publish_project
 
 
 class QGISProjectListView(APIView):
@@ -138,6 +143,59 @@ class QGISProjectDetailView(APIView):
         )
 
 
+class QGISProjectPublishView(APIView):
+    """Publish a QGIS project's layers, styles and layer tree to GeoServer."""
+
+    def post(self, request, project_id):
+        """Publish the project into a workspace.
+
+        Expected body:
+        {
+            "connectionId": "conn_123",
+            "workspace": "topp",
+            "groupName": "Optional title of the project's layer group"
+        }
+
+        Layers reading files are published only when the files are found
+        next to the stored project.
+        """
+        conn_id = request.data.get("connectionId")
+        workspace = request.data.get("workspace")
+        if not conn_id or not workspace:
+            return Response(
+                {"error": "connectionId and workspace are required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        project = next(
+            (p for p in get_config().config.qgis_projects if p.id == project_id), None
+        )
+        if project is None:
+            return Response(
+                {"error": "Project not found"},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+
+        try:
+            project_file = read_project(Path(project.path))
+        except (OSError, ValueError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
+        try:
+            client = get_geoserver_client(conn_id)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        try:
+            result = publish_project(
+                client, project_file, workspace, request.data.get("groupName") or None
+            )
+        except GeoServerError as e:
+            return Response(
+                {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
+            )
+        return Response(result.to_dict())
+
+
 class SQLViewPublishView(APIView):
     """Publish SQL views as GeoServer layers."""
 
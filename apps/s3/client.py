@@ -8,6 +8,7 @@ import io
 import threading
 from dataclasses import dataclass
 from typing import Any, BinaryIO
+from urllib.parse import quote
 
 import boto3
 from botocore.config import Config
@@ -78,6 +79,7 @@ class S3Client:
         """
         self.endpoint = endpoint
         self.region = region
+        self.path_style = path_style
 
         # Configure boto3 for S3-compatible storage
         config = Config(
@@ -90,6 +92,7 @@ class S3Client:
         if not endpoint_url.startswith("http"):
             protocol = "https" if use_ssl else "http"
             endpoint_url = f"{protocol}://{endpoint}"
+        self.endpoint_url = endpoint_url.rstrip("/")
 
         self.client = boto3.client(
             "s3",
@@ -319,6 +322,21 @@ class S3Client:
             ExpiresIn=expiration,
         )
 
+    def object_url(self, bucket: str, key: str) -> str:
+        """Get the unsigned URL of an object, readable when the bucket is public.
+
+        Args:
+            bucket: Bucket name
+            key: Object key
+
+        Returns:
+            Path-style or virtual-hosted URL, as the connection addresses buckets
+        """
+        if self.path_style:
+            return f"{self.endpoint_url}/{bucket}/{quote(key)}"
+        scheme, _, host = self.endpoint_url.partition("://")
+        return f"{scheme}://{bucket}.{host}/{quote(key)}"
+
     def copy_object(
         self,
         source_bucket: str,
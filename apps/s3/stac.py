@@ -0,0 +1,429 @@
+"""STAC catalog generation for S3 buckets.
+
+Walks a bucket/prefix, extracts footprints and datetimes from
+Cloud Optimized GeoTIFF and GeoParquet objects, and builds a static
+STAC catalog (catalog.json, collection.json and one item per asset)
+that can be written back into the bucket or served dynamically.
+"""
+
+import json
+import posixpath
+from datetime import datetime, timezone
+from typing import Any
+
+from apps.upload.preview import run_json
+
+from .client import S3Client
+
+STAC_VERSION = "1.0.0"
+
+# Object key suffixes recognised as STAC assets
+COG_EXTENSIONS = (".tif", ".tiff")
+GEOPARQUET_EXTENSIONS = (".parquet", ".geoparquet")
+
+# Media types advertised for each asset kind
+MEDIA_TYPES = {
+    "cog": "image/tiff; application=geotiff; profile=cloud-optimized",
+    "geoparquet": "application/vnd.apache.parquet",
+}
+
+# Catalog files written at the root of the catalog prefix
+CATALOG_FILE = "catalog.json"
+COLLECTION_FILE = "collection.json"
+ITEMS_DIR = "items"
+
+# Seconds to allow gdalinfo or ogrinfo to read a remote object
+GDAL_TIMEOUT = 60
+
+
+def detect_asset_kind(key: str) -> str | None:
+    """Detect which kind of STAC asset an object key refers to.
+
+    Args:
+        key: S3 object key
+
+    Returns:
+        "cog", "geoparquet" or None if the object is not a supported asset
+    """
+    lower = key.lower()
+    if lower.endswith(COG_EXTENSIONS):
+        return "cog"
+    if lower.endswith(GEOPARQUET_EXTENSIONS):
+        return "geoparquet"
+    return None
+
+
+def item_id_for_key(key: str, prefix: str = "") -> str:
+    """Derive a stable STAC item ID from an object key.
+
+    Args:
+        key: S3 object key
+        prefix: Catalog prefix to strip from the key
+
+    Returns:
+        Item ID made of the relative path without extension
+    """
+    relative = key[len(prefix):] if prefix and key.startswith(prefix) else key
+    stem, _ = posixpath.splitext(relative.lstrip("/"))
+    return stem.replace("/", "__")
+
+
+def bbox_to_polygon(bbox: list[float]) -> dict[str, Any]:
+    """Convert a [minx, miny, maxx, maxy] bbox to a GeoJSON polygon.
+
+    Args:
+        bbox: Bounding box in lon/lat order
+
+    Returns:
+        GeoJSON Polygon geometry
+    """
+    minx, miny, maxx, maxy = bbox
+    return {
+        "type": "Polygon",
+        "coordinates": [
+            [[minx, miny], [maxx, miny], [maxx, maxy], [minx, maxy], [minx, miny]]
+        ],
+    }
+
+
+def polygon_bbox(geometry: dict[str, Any]) -> list[float]:
+    """Compute the bbox of a GeoJSON polygon.
+
+    Args:
+        geometry: GeoJSON Polygon geometry
+
+    Returns:
+        Bounding box as [minx, miny, maxx, maxy]
+    """
+    ring = geometry.get("coordinates", [[]])[0]
+    xs = [pt[0] for pt in ring]
+    ys = [pt[1] for pt in ring]
+    return [min(xs), min(ys), max(xs), max(ys)]
+
+
+def merge_bboxes(bboxes: list[list[float]]) -> list[float]:
+    """Merge several bboxes into their union.
+
+    Args:
+        bboxes: List of [minx, miny, maxx, maxy] boxes
+
+    Returns:
+        Union bbox, or the whole world when the list is empty
+    """
+    if not bboxes:
+        return [-180.0, -90.0, 180.0, 90.0]
+    return [
+        min(b[0] for b in bboxes),
+        min(b[1] for b in bboxes),
+        max(b[2] for b in bboxes),
+        max(b[3] for b in bboxes),
+    ]
+
+
+def parse_tiff_datetime(value: str) -> str | None:
+    """Parse a TIFFTAG_DATETIME value ("YYYY:MM:DD HH:MM:SS") to ISO 8601.
+
+    Args:
+        value: Raw TIFF datetime string
+
+    Returns:
+        ISO 8601 UTC datetime string, or None if unparseable
+    """
+    try:
+        parsed = datetime.strptime(value.strip(), "%Y:%m:%d %H:%M:%S")
+    except ValueError:
+        return None
+    return parsed.replace(tzinfo=timezone.utc).isoformat().replace("+00:00", "Z")
+
+
+def _read_json(command: list[str]) -> dict[str, Any] | None:
+    """Run a GDAL utility that prints JSON; None if it's missing or fails."""
+    try:
+        return run_json(command, GDAL_TIMEOUT)
+    except ValueError:
+        return None
+
+
+def extract_cog_metadata(url: str) -> dict[str, Any]:
+    """Extract footprint and datetime from a COG via gdalinfo.
+
+    Args:
+        url: HTTP(S) URL readable by GDAL (e.g. a presigned URL)
+
+    Returns:
+        Dictionary with optional "geometry", "bbox", "datetime" and "epsg" keys
+    """
+    info = _read_json(["gdalinfo", "-json", f"/vsicurl/{url}"])
+    if not info:
+        return {}
+
+    metadata: dict[str, Any] = {}
+
+    extent = info.get("wgs84Extent")
+    if extent and extent.get("type") == "Polygon":
+        metadata["geometry"] = extent
+        metadata["bbox"] = polygon_bbox(extent)
+
+    tags = info.get("metadata", {}).get("", {})
+    if "TIFFTAG_DATETIME" in tags:
+        parsed = parse_tiff_datetime(tags["TIFFTAG_DATETIME"])
+        if parsed:
+            metadata["datetime"] = parsed
+
+    srs = info.get("coordinateSystem", {})
+    epsg = srs.get("epsg") if isinstance(srs, dict) else None
+    if epsg:
+        metadata["epsg"] = epsg
+
+    return metadata
+
+
+def extract_geoparquet_metadata(url: str) -> dict[str, Any]:
+    """Extract footprint from a GeoParquet file via ogrinfo.
+
+    GeoParquet defaults to OGC:CRS84, so the layer extent is used
+    directly as the lon/lat footprint.
+
+    Args:
+        url: HTTP(S) URL readable by GDAL (e.g. a presigned URL)
+
+    Returns:
+        Dictionary with optional "geometry", "bbox" and "featureCount" keys
+    """
+    info = _read_json(["ogrinfo", "-json", "-so", "-al", f"/vsicurl/{url}"])
+    if not info:
+        return {}
+
+    metadata: dict[str, Any] = {}
+    bboxes = []
+    feature_count = 0
+
+    for layer in info.get("layers", []):
+        feature_count += layer.get("featureCount", 0) or 0
+        for field in layer.get("geometryFields", []):
+            extent = field.get("extent")
+            if extent and len(extent) == 4:
+                bboxes.append([float(v) for v in extent])
+
+    if bboxes:
+        bbox = merge_bboxes(bboxes)
+        metadata["bbox"] = bbox
+        metadata["geometry"] = bbox_to_polygon(bbox)
+
+    metadata["featureCount"] = feature_count
+    return metadata
+
+
+class STACCatalogGenerator:
+    """Build a static STAC catalog from the objects under a bucket prefix."""
+
+    def __init__(
+        self,
+        client: S3Client,
+        bucket: str,
+        prefix: str = "",
+        catalog_id: str | None = None,
+        title: str | None = None,
+        description: str = "",
+    ):
+        """Initialize the generator.
+
+        Args:
+            client: S3 client for the connection
+            bucket: Bucket to walk
+            prefix: Key prefix to walk; catalog files are written under it
+            catalog_id: Catalog/collection ID (defaults to bucket and prefix)
+            title: Human readable title
+            description: Catalog description
+        """
+        self.client = client
+        self.bucket = bucket
+        self.prefix = prefix.strip("/") + "/" if prefix.strip("/") else ""
+        default_id = f"{bucket}-{self.prefix.strip('/')}" if self.prefix else bucket
+        self.catalog_id = catalog_id or default_id.replace("/", "-")
+        self.title = title or self.catalog_id
+        self.description = description or f"STAC catalog for s3://{bucket}/{self.prefix}"
+
+    def list_assets(self) -> list[dict[str, Any]]:
+        """Walk the bucket prefix and collect supported assets.
+
+        Returns:
+            List of object dictionaries with an extra "kind" key
+        """
+        assets = []
+        token = None
+        while True:
+            page = self.client.list_objects(
+                bucket=self.bucket,
+                prefix=self.prefix,
+                delimiter="",
+                continuation_token=token,
+            )
+            for obj in page["objects"]:
+                kind = detect_asset_kind(obj["key"])
+                if kind:
+                    assets.append({**obj, "kind": kind})
+            if not page.get("isTruncated"):
+                break
+            token = page.get("nextContinuationToken")
+        return assets
+
+    def build_item(self, asset: dict[str, Any]) -> dict[str, Any]:
+        """Build a STAC item for a single asset.
+
+        Args:
+            asset: Object dictionary from list_assets()
+
+        Returns:
+            STAC Item dictionary
+        """
+        key = asset["key"]
+        kind = asset["kind"]
+        url = self.client.generate_presigned_url(self.bucket, key)
+
+        if kind == "cog":
+            metadata = extract_cog_metadata(url)
+        else:
+            metadata = extract_geoparquet_metadata(url)
+
+        bbox = metadata.get("bbox") or merge_bboxes([])
+        geometry = metadata.get("geometry") or bbox_to_polygon(bbox)
+        item_datetime = metadata.get("datetime") or asset.get("lastModified") or (
+            datetime.now(timezone.utc).isoformat()
+        )
+
+        properties: dict[str, Any] = {"datetime": item_datetime}
+        stac_extensions = []
+        if metadata.get("epsg"):
+            properties["proj:epsg"] = metadata["epsg"]
+            stac_extensions.append(
+                "https://stac-extensions.github.io/projection/v1.1.0/schema.json"
+            )
+        if "featureCount" in metadata:
+            properties["table:row_count"] = metadata["featureCount"]
+            stac_extensions.append(
+                "https://stac-extensions.github.io/table/v1.2.0/schema.json"
+            )
+
+        item_id = item_id_for_key(key, self.prefix)
+        return {
+            "type": "Feature",
+            "stac_version": STAC_VERSION,
+            "stac_extensions": stac_extensions,
+            "id": item_id,
+            "collection": self.catalog_id,
+            "geometry": geometry,
+            "bbox": bbox,
+            "properties": properties,
+            "assets": {
+                "data": {
+                    "href": f"s3://{self.bucket}/{key}",
+                    "type": MEDIA_TYPES[kind],
+                    "roles": ["data"],
+                    "file:size": asset.get("size", 0),
+                }
+            },
+            "links": [
+                {"rel": "root", "href": f"../{CATALOG_FILE}", "type": "application/json"},
+                {"rel": "parent", "href": f"../{COLLECTION_FILE}", "type": "application/json"},
+                {"rel": "collection", "href": f"../{COLLECTION_FILE}", "type": "application/json"},
+                {"rel": "self", "href": f"./{item_id}.json", "type": "application/geo+json"},
+            ],
+        }
+
+    def build_collection(self, items: list[dict[str, Any]]) -> dict[str, Any]:
+        """Build the STAC collection summarising all items.
+
+        Args:
+            items: STAC items in the collection
+
+        Returns:
+            STAC Collection dictionary
+        """
+        datetimes = sorted(i["properties"]["datetime"] for i in items)
+        interval = [datetimes[0], datetimes[-1]] if datetimes else [None, None]
+
+        return {
+            "type": "Collection",
+            "stac_version": STAC_VERSION,
+            "id": self.catalog_id,
+            "title": self.title,
+            "description": self.description,
+            "license": "proprietary",
+            "extent": {
+                "spatial": {"bbox": [merge_bboxes([i["bbox"] for i in items])]},
+                "temporal": {"interval": [interval]},
+            },
+            "links": [
+                {"rel": "root", "href": f"./{CATALOG_FILE}", "type": "application/json"},
+                {"rel": "parent", "href": f"./{CATALOG_FILE}", "type": "application/json"},
+                {"rel": "self", "href": f"./{COLLECTION_FILE}", "type": "application/json"},
+            ]
+            + [
+                {
+                    "rel": "item",
+                    "href": f"./{ITEMS_DIR}/{i['id']}.json",
+                    "type": "application/geo+json",
+                }
+                for i in items
+            ],
+        }
+
+    def build_catalog(self) -> dict[str, Any]:
+        """Build the root STAC catalog.
+
+        Returns:
+            STAC Catalog dictionary
+        """
+        return {
+            "type": "Catalog",
+            "stac_version": STAC_VERSION,
+            "id": self.catalog_id,
+            "title": self.title,
+            "description": self.description,
+            "links": [
+                {"rel": "root", "href": f"./{CATALOG_FILE}", "type": "application/json"},
+                {"rel": "self", "href": f"./{CATALOG_FILE}", "type": "application/json"},
+                {"rel": "child", "href": f"./{COLLECTION_FILE}", "type": "application/json"},
+            ],
+        }
+
+    def generate(self) -> dict[str, dict[str, Any]]:
+        """Generate the full catalog.
+
+        Returns:
+            Mapping of relative path (e.g. "items/foo.json") to STAC document
+        """
+        items = [self.build_item(asset) for asset in self.list_assets()]
+
+        documents: dict[str, dict[str, Any]] = {
+            CATALOG_FILE: self.build_catalog(),
+            COLLECTION_FILE: self.build_collection(items),
+        }
+        for item in items:
+            documents[f"{ITEMS_DIR}/{item['id']}.json"] = item
+        return documents
+
+    def write(self, documents: dict[str, dict[str, Any]]) -> list[str]:
+        """Write generated documents back into the bucket.
+
+        Args:
+            documents: Output of generate()
+
+        Returns:
+            List of object keys written
+        """
+        written = []
+        for path, doc in documents.items():
+            key = f"{self.prefix}{path}"
+            content_type = (
+                "application/geo+json" if path.startswith(ITEMS_DIR) else "application/json"
+            )
+            self.client.put_object(
+                bucket=self.bucket,
+                key=key,
+                body=json.dumps(doc, indent=2).encode("utf-8"),
+                content_type=content_type,
+            )
+            written.append(key)
+        return written
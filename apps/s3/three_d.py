@@ -0,0 +1,319 @@
+"""Point clouds and 3D Tiles in S3 buckets.
+
+LAS and LAZ point clouds are converted next to the original in the
+bucket, either to a Cloud Optimized Point Cloud (COPC) with PDAL, for
+clients that stream point clouds by range requests, or to 3D Tiles with
+py3dtiles, which Cesium and Terria draw. Tilesets found in a bucket are
+registered in the Terria catalog beside GeoServer's layers, with the
+region their root tile covers as their extent.
+"""
+
+import json
+import math
+import mimetypes
+import posixpath
+import shutil
+import subprocess
+import tempfile
+from collections.abc import Callable
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Any
+
+from apps.core.config import get_cache_dir
+from apps.core.exceptions import S3Error
+
+from .client import S3Client
+
+# Point clouds that can be converted; COPC files are LAZ files too
+POINT_CLOUD_EXTENSIONS = (".las", ".laz")
+COPC_SUFFIX = ".copc.laz"
+
+# Root file of a 3D Tiles tileset
+TILESET_FILE = "tileset.json"
+
+# Folder a point cloud's tileset is written to, after its name
+TILES_FOLDER_SUFFIX = "_3dtiles"
+
+# Formats point clouds can be converted to
+POINT_CLOUD_FORMATS = ("copc", "3dtiles")
+
+# 3D Tiles are drawn on the globe in Earth-centred coordinates
+TILES_SRS = "4978"
+
+# Objects listed while looking for 3D data
+MAX_LISTED = 10000
+
+# Media types of converted files
+MEDIA_TYPES = {
+    ".json": "application/json",
+    ".laz": "application/vnd.laszip+copc",
+}
+
+
+@dataclass
+class ThreeDAsset:
+    """A point cloud or tileset in a bucket."""
+
+    kind: str  # "pointcloud", "copc" or "3dtiles"
+    key: str
+    size: int = 0
+
+    @property
+    def name(self) -> str:
+        """File name, or the tileset's folder name."""
+        if self.kind == "3dtiles":
+            return posixpath.basename(posixpath.dirname(self.key)) or self.key
+        return posixpath.basename(self.key)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {"kind": self.kind, "key": self.key, "name": self.name, "size": self.size}
+
+
+def detect_3d_kind(key: str) -> str | None:
+    """Which kind of 3D data an object key refers to, or None."""
+    lower = key.lower()
+    if lower.endswith(COPC_SUFFIX):
+        return "copc"
+    if lower.endswith(POINT_CLOUD_EXTENSIONS):
+        return "pointcloud"
+    if posixpath.basename(lower) == TILESET_FILE:
+        return "3dtiles"
+    return None
+
+
+def _stem(key: str) -> str:
+    if key.lower().endswith(COPC_SUFFIX):
+        return key[: -len(COPC_SUFFIX)]
+    return posixpath.splitext(key)[0]
+
+
+def copc_key(key: str) -> str:
+    """Key of a point cloud's COPC, e.g. lidar/city.laz -> lidar/city.copc.laz."""
+    return f"{_stem(key)}{COPC_SUFFIX}"
+
+
+def tiles_prefix(key: str) -> str:
+    """Folder of a point cloud's tileset, e.g. lidar/city.laz -> lidar/city_3dtiles."""
+    return f"{_stem(key)}{TILES_FOLDER_SUFFIX}"
+
+
+def conversion_target(key: str, target_format: str) -> str:
+    """Key a point cloud's conversion is uploaded to.
+
+    Returns:
+        Key of the COPC file, or of the tileset's tileset.json
+
+    Raises:
+        ValueError: If the object isn't a point cloud or the format is unknown
+    """
+    if target_format not in POINT_CLOUD_FORMATS:
+        raise ValueError(
+            f"Unknown format {target_format}; use one of {', '.join(POINT_CLOUD_FORMATS)}"
+        )
+    kind = detect_3d_kind(key)
+    if kind not in ("pointcloud", "copc"):
+        raise ValueError(f"{key} isn't a LAS or LAZ point cloud")
+    if kind == target_format:
+        raise ValueError(f"{key} is already a COPC")
+    if target_format == "copc":
+        return copc_key(key)
+    return f"{tiles_prefix(key)}/{TILESET_FILE}"
+
+
+def find_3d_assets(s3: S3Client, bucket: str, prefix: str = "") -> list[ThreeDAsset]:
+    """List the point clouds and tilesets under a prefix.
+
+    Tilesets nested in another tileset's folder are its children, not
+    tilesets of their own, and are left out.
+    """
+    assets = []
+    token = None
+    listed = 0
+    while listed < MAX_LISTED:
+        page = s3.list_objects(bucket, prefix, delimiter="", continuation_token=token)
+        for obj in page["objects"]:
+            kind = detect_3d_kind(obj["key"])
+            if kind:
+                assets.append(ThreeDAsset(kind, obj["key"], obj.get("size", 0)))
+        listed += len(page["objects"])
+        token = page.get("nextContinuationToken")
+        if not page.get("isTruncated") or not token:
+            break
+
+    folders = [
+        f"{posixpath.dirname(a.key)}/" if "/" in a.key else ""
+        for a in assets
+        if a.kind == "3dtiles"
+    ]
+    return [
+        asset
+        for asset in assets
+        if asset.kind != "3dtiles"
+        or not any(
+            asset.key.startswith(folder) and asset.key != f"{folder}{TILESET_FILE}"
+            for folder in folders
+        )
+    ]
+
+
+def pdal_available() -> bool:
+    """Whether pdal is on the PATH."""
+    return shutil.which("pdal") is not None
+
+
+def py3dtiles_available() -> bool:
+    """Whether py3dtiles is on the PATH."""
+    return shutil.which("py3dtiles") is not None
+
+
+def _run(command: list[str], what: str, tool: str, timeout: float) -> None:
+    try:
+        result = subprocess.run(command, capture_output=True, text=True, timeout=timeout)
+    except FileNotFoundError as e:
+        raise S3Error(f"{tool} not found. Please install {tool}.", "convert") from e
+    except subprocess.TimeoutExpired as e:
+        raise S3Error(f"{what} timed out", "convert") from e
+    if result.returncode != 0:
+        message = result.stderr.strip().splitlines()[-1:] or ["unknown error"]
+        raise S3Error(f"{what} failed: {message[0]}", "convert")
+
+
+def convert_to_copc(source: Path, target: Path, timeout: float = 3600) -> Path:
+    """Convert a LAS or LAZ point cloud to COPC with PDAL.
+
+    Raises:
+        S3Error: If PDAL is missing or the conversion fails
+    """
+    command = ["pdal", "translate", "--writer", "writers.copc", str(source), str(target)]
+    _run(command, f"COPC conversion of {source.name}", "PDAL", timeout)
+    return target
+
+
+def convert_to_3dtiles(source: Path, target: Path, timeout: float = 3600) -> Path:
+    """Convert a LAS or LAZ point cloud to a 3D Tiles tileset with py3dtiles.
+
+    Args:
+        source: Point cloud to convert
+        target: Folder to write the tileset into
+        timeout: Seconds to allow py3dtiles
+
+    Raises:
+        S3Error: If py3dtiles is missing or the conversion fails
+    """
+    command = [
+        "py3dtiles",
+        "convert",
+        "--out", str(target),
+        "--overwrite",
+        "--srs_out", TILES_SRS,
+        str(source),
+    ]
+    _run(command, f"3D Tiles conversion of {source.name}", "py3dtiles", timeout)
+    if not (target / TILESET_FILE).is_file():
+        raise S3Error(f"3D Tiles conversion of {source.name} wrote no tileset", "convert")
+    return target
+
+
+def _media_type(path: Path) -> str:
+    guessed, _ = mimetypes.guess_type(path.name)
+    return MEDIA_TYPES.get(path.suffix.lower()) or guessed or "application/octet-stream"
+
+
+def convert_point_cloud(s3: S3Client, bucket: str, key: str, target_format: str) -> str:
+    """Convert a point cloud in a bucket and upload the result beside it.
+
+    Args:
+        s3: S3 client
+        bucket: Bucket of the point cloud
+        key: Key of a LAS or LAZ file
+        target_format: "copc" or "3dtiles"
+
+    Returns:
+        Key of the COPC file, or of the tileset's tileset.json
+
+    Raises:
+        ValueError: If the object isn't a point cloud or the format is unknown
+        S3Error: If the conversion fails
+    """
+    target = conversion_target(key, target_format)
+
+    with tempfile.TemporaryDirectory(dir=get_cache_dir()) as tmp:
+        source = Path(tmp) / posixpath.basename(key)
+        with source.open("wb") as f:
+            shutil.copyfileobj(s3.get_object_stream(bucket, key), f)
+
+        if target_format == "copc":
+            copc = convert_to_copc(source, Path(tmp) / f"converted{COPC_SUFFIX}")
+            with copc.open("rb") as f:
+                s3.put_object(bucket, target, f, content_type=_media_type(copc))
+            return target
+
+        folder = convert_to_3dtiles(source, Path(tmp) / "3dtiles")
+        prefix = tiles_prefix(key)
+        for path in sorted(folder.rglob("*")):
+            if path.is_file():
+                with path.open("rb") as f:
+                    s3.put_object(
+                        bucket,
+                        f"{prefix}/{path.relative_to(folder).as_posix()}",
+                        f,
+                        content_type=_media_type(path),
+                    )
+        return target
+
+
+def tileset_rectangle(tileset: dict[str, Any]) -> dict[str, float] | None:
+    """Extent of a tileset's root tile in degrees, when given as a region."""
+    region = tileset.get("root", {}).get("boundingVolume", {}).get("region")
+    if not region or len(region) < 4:
+        return None
+    west, south, east, north = (math.degrees(float(r)) for r in region[:4])
+    return {"west": west, "south": south, "east": east, "north": north}
+
+
+def tileset_item(
+    asset: ThreeDAsset, url: str, tileset: dict[str, Any] | None = None
+) -> dict[str, Any]:
+    """Terria catalog item drawing a tileset.
+
+    Args:
+        asset: The tileset
+        url: URL the viewer reads tileset.json from
+        tileset: The tileset's contents, for its extent
+    """
+    item: dict[str, Any] = {
+        "type": "3d-tiles",
+        "name": asset.name,
+        "id": f"3dtiles:{asset.key}",
+        "url": url,
+        "info": [{"name": "Tileset", "content": asset.key}],
+    }
+    extent = tileset_rectangle(tileset or {})
+    if extent:
+        item["rectangle"] = extent
+    return item
+
+
+def tileset_items(
+    s3: S3Client, bucket: str, prefix: str, url_for: Callable[[str], str]
+) -> list[dict[str, Any]]:
+    """Terria catalog items of the tilesets under a prefix.
+
+    Args:
+        s3: S3 client
+        bucket: Bucket to look in
+        prefix: Key prefix to look under
+        url_for: Called with a tileset.json key, returns the URL the viewer reads it from
+    """
+    items = []
+    for asset in find_3d_assets(s3, bucket, prefix):
+        if asset.kind != "3dtiles":
+            continue
+        try:
+            tileset = json.loads(s3.get_object(bucket, asset.key))
+        except (ValueError, UnicodeDecodeError):
+            tileset = None
+        items.append(tileset_item(asset, url_for(asset.key), tileset))
+    return items
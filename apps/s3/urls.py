@@ -93,4 +93,20 @@ urlpatterns = [
         views.S3PresignedURLView.as_view(),
         name="s3-presigned-url",
     ),
+    # STAC catalogs
+    path(
+        "s3/stac/<str:conn_id>/<str:bucket>",
+        views.S3STACGenerateView.as_view(),
+        name="s3-stac-generate",
+    ),
+    path(
+        "stac/<str:conn_id>/<str:bucket>",
+        views.STACView.as_view(),
+        name="stac-root",
+    ),
+    re_path(
+        r"^stac/(?P<conn_id>[^/]+)/(?P<bucket>[^/]+)/(?P<path>.+)$",
+        views.STACView.as_view(),
+        name="stac-document",
+    ),
 ]
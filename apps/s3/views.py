@@ -7,6 +7,8 @@ Provides endpoints for:
 - File preview and proxy
 - DuckDB queries
 - Format conversion
+- Point cloud conversion to COPC and 3D Tiles
+- STAC catalog generation
 """
 
 import json
@@ -24,9 +26,18 @@ from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.config import S3Connection, get_config
+from apps.core.exceptions import S3Error
 
 from .client import S3Client, S3ClientManager, get_s3_client
 from .duckdb import get_duckdb_engine
+from .stac import CATALOG_FILE, ITEMS_DIR, STACCatalogGenerator
+from .three_d import (
+    POINT_CLOUD_FORMATS,
+    conversion_target,
+    convert_point_cloud,
+    pdal_available,
+    py3dtiles_available,
+)
 
 
 # ============================================================================
@@ -568,6 +579,34 @@ class ConversionJobManager:
                     job.error = error
 
 
+def start_point_cloud_job(
+    conn_id: str, bucket: str, key: str, target_format: str
+) -> ConversionJob:
+    """Convert a point cloud to COPC or 3D Tiles beside it, in the background.
+
+    Raises:
+        ValueError: If the object isn't a point cloud or the format is unknown
+    """
+    target = conversion_target(key, target_format)
+    manager = ConversionJobManager()
+    job = manager.create_job(f"s3://{bucket}/{key}", f"s3://{bucket}/{target}", target_format)
+
+    def run():
+        manager.update_job(job.id, status="running")
+        try:
+            convert_point_cloud(get_s3_client(conn_id), bucket, key, target_format)
+        except S3Error as e:
+            manager.update_job(job.id, status="failed", error=e.message)
+        except Exception as e:
+            # Nobody would see the error of a background thread otherwise
+            manager.update_job(job.id, status="failed", error=str(e))
+        else:
+            manager.update_job(job.id, status="completed", progress=100.0)
+
+    threading.Thread(target=run, daemon=True).start()
+    return job
+
+
 class S3ConversionToolsView(APIView):
     """Check available conversion tools."""
 
@@ -620,6 +659,10 @@ class S3ConversionToolsView(APIView):
         except (FileNotFoundError, subprocess.TimeoutExpired):
             tools["tippecanoe"] = {"available": False}
 
+        # Point cloud conversion to COPC and 3D Tiles
+        tools["pdal"] = {"available": pdal_available(), "formats": ["copc"]}
+        tools["py3dtiles"] = {"available": py3dtiles_available(), "formats": ["3dtiles"]}
+
         return Response({"tools": tools})
 
 
@@ -638,6 +681,9 @@ class S3ConversionJobsView(APIView):
             "targetKey": "path/to/output.parquet",
             "format": "parquet"
         }
+
+        Point clouds converted to "copc" or "3dtiles" are written beside the
+        source, so they need no target.
         """
         conn_id = request.data.get("connectionId")
         source_bucket = request.data.get("sourceBucket")
@@ -646,6 +692,21 @@ class S3ConversionJobsView(APIView):
         target_key = request.data.get("targetKey")
         format = request.data.get("format", "parquet")
 
+        if format in POINT_CLOUD_FORMATS:
+            if not all([conn_id, source_bucket, source_key]):
+                return Response(
+                    {"error": "Missing required parameters"},
+                    status=status.HTTP_400_BAD_REQUEST,
+                )
+            try:
+                job = start_point_cloud_job(conn_id, source_bucket, source_key, format)
+            except ValueError as e:
+                return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+            return Response(
+                {"jobId": job.id, "status": job.status, "targetPath": job.target_path},
+                status=status.HTTP_202_ACCEPTED,
+            )
+
         if not all([conn_id, source_bucket, source_key, target_bucket, target_key]):
             return Response(
                 {"error": "Missing required parameters"},
@@ -705,6 +766,11 @@ class S3UploadView(APIView):
         Expects multipart/form-data with:
         - file: The file to upload
         - key: Optional key path (defaults to filename)
+        - convert: "true" to convert a point cloud after upload
+        - targetFormat: "copc" or "3dtiles", with convert
+
+        Returns the conversionJobId of a point cloud conversion started, or
+        the conversionError if it couldn't be.
         """
         if "file" not in request.FILES:
             return Response(
@@ -728,11 +794,23 @@ class S3UploadView(APIView):
                 body=uploaded_file.read(),
                 content_type=content_type,
             )
-            return Response({
+            response = {
                 "key": key,
                 "etag": result.get("etag"),
                 "bucket": bucket,
-            }, status=status.HTTP_201_CREATED)
+            }
+            target_format = request.data.get("targetFormat")
+            if (
+                str(request.data.get("convert", "")).lower() == "true"
+                and target_format in POINT_CLOUD_FORMATS
+            ):
+                try:
+                    job = start_point_cloud_job(conn_id, bucket, key, target_format)
+                    response["conversionJobId"] = job.id
+                except ValueError as e:
+                    # The file is uploaded either way
+                    response["conversionError"] = str(e)
+            return Response(response, status=status.HTTP_201_CREATED)
         except ValueError as e:
             return Response(
                 {"error": str(e)},
@@ -772,3 +850,132 @@ class S3PresignedURLView(APIView):
                 {"error": str(e)},
                 status=status.HTTP_502_BAD_GATEWAY,
             )
+
+
+# ============================================================================
+# STAC Catalog Views
+# ============================================================================
+
+# Generated catalogs cached for dynamic serving, keyed by (conn_id, bucket, prefix)
+_stac_cache: dict[tuple[str, str, str], dict[str, dict[str, Any]]] = {}
+_stac_cache_lock = threading.Lock()
+
+
+def _split_stac_path(path: str) -> tuple[str, str]:
+    """Split a dynamic STAC path into (prefix, document path).
+
+    Example: "imagery/items/scene.json" -> ("imagery", "items/scene.json")
+    A path not ending in a JSON document is a prefix, whose catalog is
+    served: "imagery" -> ("imagery", "catalog.json").
+    """
+    path = path.strip("/")
+    if f"{ITEMS_DIR}/" in f"/{path}":
+        head, _, tail = f"/{path}".rpartition(f"/{ITEMS_DIR}/")
+        return head.strip("/"), f"{ITEMS_DIR}/{tail}"
+    head, _, name = path.rpartition("/")
+    if not name.endswith(".json"):
+        return path, CATALOG_FILE
+    return head, name
+
+
+class S3STACGenerateView(APIView):
+    """Generate a static STAC catalog and write it into the bucket."""
+
+    def post(self, request, conn_id, bucket):
+        """Walk a bucket prefix and write catalog.json, collection.json and items.
+
+        Expected body:
+        {
+            "prefix": "imagery/",
+            "catalogId": "optional-id",
+            "title": "Optional title",
+            "description": "Optional description",
+            "write": true
+        }
+        """
+        prefix = request.data.get("prefix", "")
+        write = request.data.get("write", True)
+
+        try:
+            client = get_s3_client(conn_id)
+            generator = STACCatalogGenerator(
+                client,
+                bucket,
+                prefix=prefix,
+                catalog_id=request.data.get("catalogId"),
+                title=request.data.get("title"),
+                description=request.data.get("description", ""),
+            )
+            documents = generator.generate()
+
+            with _stac_cache_lock:
+                _stac_cache[(conn_id, bucket, generator.prefix.strip("/"))] = documents
+
+            written = generator.write(documents) if write else []
+            item_count = sum(1 for path in documents if path.startswith(ITEMS_DIR))
+
+            return Response({
+                "catalogId": generator.catalog_id,
+                "itemCount": item_count,
+                "written": written,
+                "catalogUrl": f"s3://{bucket}/{generator.prefix}{CATALOG_FILE}",
+            })
+        except ValueError as e:
+            return Response(
+                {"error": str(e)},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+        except Exception as e:
+            return Response(
+                {"error": str(e)},
+                status=status.HTTP_502_BAD_GATEWAY,
+            )
+
+
+class STACView(APIView):
+    """Serve a STAC catalog for a bucket prefix dynamically."""
+
+    def get(self, request, conn_id, bucket, path=""):
+        """Return a catalog, collection or item document.
+
+        Paths are relative to the bucket, e.g. "imagery/catalog.json",
+        "imagery/collection.json" or "imagery/items/<id>.json".
+        Pass ?refresh=true to regenerate instead of using the cached catalog.
+        """
+        prefix, doc_path = _split_stac_path(path)
+        refresh = request.query_params.get("refresh", "false").lower() == "true"
+        cache_key = (conn_id, bucket, prefix)
+
+        try:
+            with _stac_cache_lock:
+                documents = None if refresh else _stac_cache.get(cache_key)
+
+            if documents is None:
+                client = get_s3_client(conn_id)
+                documents = STACCatalogGenerator(client, bucket, prefix=prefix).generate()
+                with _stac_cache_lock:
+                    _stac_cache[cache_key] = documents
+
+            doc = documents.get(doc_path)
+            if doc is None:
+                return Response(
+                    {"error": f"STAC document not found: {doc_path}"},
+                    status=status.HTTP_404_NOT_FOUND,
+                )
+
+            content_type = (
+                "application/geo+json"
+                if doc_path.startswith(ITEMS_DIR)
+                else "application/json"
+            )
+            return HttpResponse(json.dumps(doc), content_type=content_type)
+        except ValueError as e:
+            return Response(
+                {"error": str(e)},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+        except Exception as e:
+            return Response(
+                {"error": str(e)},
+                status=status.HTTP_502_BAD_GATEWAY,
+            )
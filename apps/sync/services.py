@@ -2,6 +2,14 @@
 
 Provides functionality to synchronize GeoServer resources
 between multiple servers.
+
+Only resources the client manages on a destination (see
+apps.geoserver.managed) are updated or pruned; resources other admins
+created there are left alone unless adopted.
+
+A sync is written to the operation log first (see apps.core.oplog), one
+step per destination and resource type, so an interrupted sync can be
+resumed without syncing again what it already did.
 """
 
 import threading
@@ -11,7 +19,10 @@ from datetime import datetime
 from typing import Any
 
 from apps.core.config import SyncConfiguration, SyncOptions, get_config
-from apps.geoserver.client import GeoServerClientManager
+from apps.core.oplog import Operation, operation_log
+from apps.geoserver.client import CreateResult, GeoServerClientManager
+from apps.geoserver.managed import content_hash, managed_state
+from apps.geoserver.style_diff import read_server_style
 
 
 @dataclass
@@ -118,7 +129,13 @@ class SyncService:
         dest = self.client_manager.get_client(dest_id)
 
         results = {
-            "workspaces": {"created": 0, "skipped": 0, "errors": []},
+            "workspaces": {
+                "created": 0,
+                "skipped": 0,
+                "deleted": 0,
+                "unmanaged": [],
+                "errors": [],
+            },
         }
 
         # Get source workspaces
@@ -144,14 +161,37 @@ class SyncService:
                 continue
 
             try:
-                dest.create_workspace(ws_name)
-                results["workspaces"]["created"] += 1
+                # Created meanwhile, by another client or a timed-out earlier run
+                if dest.create_workspace(ws_name, if_absent=True) == CreateResult.ALREADY_EXISTS:
+                    results["workspaces"]["skipped"] += 1
+                else:
+                    results["workspaces"]["created"] += 1
             except Exception as e:
                 results["workspaces"]["errors"].append({
                     "workspace": ws_name,
                     "error": str(e),
                 })
 
+        if options.prune:
+            source_names = {ws.get("name") for ws in source_workspaces}
+            for ws_name in sorted(dest_workspaces - source_names):
+                if not ws_name or (
+                    options.workspace_filter and ws_name not in options.workspace_filter
+                ):
+                    continue
+                if not managed_state.is_managed(dest_id, "workspace", None, ws_name):
+                    results["workspaces"]["unmanaged"].append(ws_name)
+                    continue
+                try:
+                    # Not recursive: GeoServer keeps a workspace that still holds anything
+                    dest.delete_workspace(ws_name)
+                    results["workspaces"]["deleted"] += 1
+                except Exception as e:
+                    results["workspaces"]["errors"].append({
+                        "workspace": ws_name,
+                        "error": str(e),
+                    })
+
         return results
 
     def sync_styles(
@@ -159,22 +199,36 @@ class SyncService:
         source_id: str,
         dest_id: str,
         workspace: str | None = None,
+        options: SyncOptions | None = None,
     ) -> dict[str, Any]:
         """Sync styles from source to destination.
 
+        Existing styles are only updated if the client manages them on the
+        destination, or when adopting, and only if the source changed
+        since they were last synced.
+
         Args:
             source_id: Source connection ID
             dest_id: Destination connection ID
             workspace: Optional workspace to sync
+            options: Sync options; prune and adopt are off by default
 
         Returns:
             Sync results
         """
+        options = options or SyncOptions()
         source = self.client_manager.get_client(source_id)
         dest = self.client_manager.get_client(dest_id)
 
         results = {
-            "styles": {"created": 0, "updated": 0, "skipped": 0, "errors": []},
+            "styles": {
+                "created": 0,
+                "updated": 0,
+                "skipped": 0,
+                "deleted": 0,
+                "unmanaged": [],
+                "errors": [],
+            },
         }
 
         # Get source styles
@@ -190,16 +244,128 @@ class SyncService:
 
             try:
                 # Get style content
-                style_content = source.get_style(style_name, workspace)
+                content, style_format = source.get_style_content(style_name, workspace)
 
                 if style_name in dest_styles:
-                    # Update existing
-                    dest.update_style(style_name, style_content, workspace)
+                    managed = managed_state.get(dest_id, "style", workspace, style_name)
+                    if managed is None and not options.adopt:
+                        results["styles"]["unmanaged"].append(style_name)
+                        continue
+                    if managed is not None and managed.hash == content_hash(content):
+                        results["styles"]["skipped"] += 1
+                        continue
+                    dest.update_style_content(style_name, content, style_format, workspace)
+                    if managed is None:
+                        managed_state.add(
+                            dest_id,
+                            "style",
+                            workspace,
+                            style_name,
+                            content_hash(content),
+                            adopted=True,
+                        )
                     results["styles"]["updated"] += 1
                 else:
-                    # Create new
-                    dest.create_style(style_name, style_content, workspace)
-                    results["styles"]["created"] += 1
+                    # Create new, unless it appeared since the styles were listed
+                    created = dest.create_style(
+                        style_name, content, style_format, workspace, if_absent=True
+                    )
+                    if created == CreateResult.ALREADY_EXISTS:
+                        results["styles"]["skipped"] += 1
+                    else:
+                        results["styles"]["created"] += 1
+            except Exception as e:
+                results["styles"]["errors"].append({
+                    "style": style_name,
+                    "error": str(e),
+                })
+
+        if options.prune:
+            source_names = {s.get("name") for s in source_styles}
+            for style_name in sorted(dest_styles - source_names):
+                if not style_name:
+                    continue
+                if not managed_state.is_managed(dest_id, "style", workspace, style_name):
+                    results["styles"]["unmanaged"].append(style_name)
+                    continue
+                try:
+                    dest.delete_style(style_name, workspace)
+                    results["styles"]["deleted"] += 1
+                except Exception as e:
+                    results["styles"]["errors"].append({
+                        "style": style_name,
+                        "error": str(e),
+                    })
+
+        return results
+
+    def promote_styles(
+        self,
+        source_id: str,
+        dest_id: str,
+        names: list[str],
+        workspace: str | None = None,
+    ) -> dict[str, Any]:
+        """Push chosen styles from source to destination, leaving everything else alone.
+
+        Unlike sync_styles, only the named styles are copied and each is
+        compared with the destination's current content by hash, so a
+        style is updated whether or not the client manages it there. Styles
+        that are updated without being managed are adopted.
+
+        Args:
+            source_id: Source connection ID
+            dest_id: Destination connection ID
+            names: Styles to promote
+            workspace: Optional workspace of the styles
+
+        Returns:
+            Promotion results
+        """
+        source = self.client_manager.get_client(source_id)
+        dest = self.client_manager.get_client(dest_id)
+
+        results = {
+            "styles": {
+                "created": 0,
+                "updated": 0,
+                "skipped": 0,
+                "errors": [],
+            },
+        }
+
+        for style_name in names:
+            try:
+                content, style_format = source.get_style_content(style_name, workspace)
+                current = read_server_style(dest, style_name, workspace)
+
+                if current is None:
+                    created = dest.create_style(
+                        style_name, content, style_format, workspace, if_absent=True
+                    )
+                    if created == CreateResult.ALREADY_EXISTS:
+                        results["styles"]["skipped"] += 1
+                    else:
+                        results["styles"]["created"] += 1
+                    continue
+
+                if content_hash(current[0]) == content_hash(content) and (
+                    current[1] == style_format
+                ):
+                    results["styles"]["skipped"] += 1
+                    continue
+
+                dest.update_style_content(style_name, content, style_format, workspace)
+                if not managed_state.is_managed(dest_id, "style", workspace, style_name):
+                    managed_state.add(
+                        dest_id,
+                        "style",
+                        workspace,
+                        style_name,
+                        content_hash(content),
+                        adopted=True,
+                    )
+                results["styles"]["updated"] += 1
             except Exception as e:
                 results["styles"]["errors"].append({
                     "style": style_name,
@@ -212,12 +378,15 @@ class SyncService:
         self,
         config: SyncConfiguration,
         job_id: str,
+        operation: Operation | None = None,
     ) -> dict[str, Any]:
         """Run a full synchronization based on config.
 
         Args:
             config: Sync configuration
             job_id: Job ID for tracking
+            operation: Logged operation, see begin_sync; steps it already
+                did are skipped
 
         Returns:
             Complete sync results
@@ -235,7 +404,7 @@ class SyncService:
             dest_results = {}
 
             # Sync workspaces
-            if config.options.workspaces:
+            if config.options.workspaces and not _done(operation, dest_id, "workspaces"):
                 self.job_manager.update_job(
                     job_id,
                     current_step="Syncing workspaces",
@@ -244,18 +413,20 @@ class SyncService:
                 dest_results["workspaces"] = self.sync_workspaces(
                     config.source_id, dest_id, config.options
                 )
+                _record(operation, dest_id, "workspaces", dest_results["workspaces"])
             current_step += 1
 
             # Sync styles
-            if config.options.styles:
+            if config.options.styles and not _done(operation, dest_id, "styles"):
                 self.job_manager.update_job(
                     job_id,
                     current_step="Syncing styles",
                     progress=current_step / total_steps,
                 )
                 dest_results["styles"] = self.sync_styles(
-                    config.source_id, dest_id
+                    config.source_id, dest_id, options=config.options
                 )
+                _record(operation, dest_id, "styles", dest_results["styles"])
             current_step += 1
 
             # Other sync operations would go here...
@@ -266,6 +437,58 @@ class SyncService:
         return results
 
 
+def _step(dest_id: str, resource: str) -> str:
+    return f"{dest_id}/{resource}"
+
+
+def _done(operation: Operation | None, dest_id: str, resource: str) -> bool:
+    """Whether an earlier run of a logged sync already synced a resource type."""
+    return operation is not None and _step(dest_id, resource) in operation.done
+
+
+def _record(
+    operation: Operation | None, dest_id: str, resource: str, results: dict[str, Any]
+) -> None:
+    """Log a synced resource type; one with errors is synced again on resume."""
+    if operation is None:
+        return
+    errors = [e["error"] for counts in results.values() for e in counts.get("errors", [])]
+    operation_log.record(operation, _step(dest_id, resource), not errors, "; ".join(errors))
+
+
+def begin_sync(config: SyncConfiguration) -> Operation:
+    """Log a sync before it runs."""
+    steps = [
+        _step(dest_id, resource)
+        for dest_id in config.destination_ids
+        for resource in ("workspaces", "styles")
+        if getattr(config.options, resource)
+    ]
+    return operation_log.begin(
+        "sync",
+        config.source_id,
+        steps,
+        {
+            "configId": config.id,
+            "name": config.name,
+            "destinationIds": config.destination_ids,
+            "options": config.options.model_dump(),
+        },
+    )
+
+
+def operation_config(operation: Operation) -> SyncConfiguration:
+    """The configuration an interrupted sync ran with."""
+    params = operation.params
+    return SyncConfiguration(
+        id=params.get("configId", "temp"),
+        name=params.get("name", ""),
+        source_id=operation.conn_id,
+        destination_ids=params.get("destinationIds", []),
+        options=SyncOptions(**params.get("options", {})),
+    )
+
+
 def get_sync_service() -> SyncService:
     """Get the sync service singleton."""
     return SyncService()
@@ -22,6 +22,18 @@ urlpatterns = [
         views.SyncStartView.as_view(),
         name="sync-start",
     ),
+    # Promote styles only
+    path(
+        "sync/styles/promote",
+        views.SyncStylePromoteView.as_view(),
+        name="sync-style-promote",
+    ),
+    # Contact details and service metadata only
+    path(
+        "sync/settings",
+        views.SyncSettingsView.as_view(),
+        name="sync-settings",
+    ),
     # Status
     path(
         "sync/status",
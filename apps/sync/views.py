@@ -3,6 +3,8 @@
 Provides endpoints for:
 - Sync configuration management
 - Starting sync operations
+- Promoting chosen styles
+- Syncing contact details and service metadata
 - Monitoring sync status
 """
 
@@ -16,7 +18,10 @@ from rest_framework.views import APIView
 
 from apps.core.config import SyncConfiguration, SyncOptions, get_config
 
-from .services import SyncJobManager, get_sync_service
+from apps.core.oplog import operation_log
+from apps.geoserver.settings_sync import apply_settings_sync, plan_settings_sync
+
+from .services import SyncJobManager, begin_sync, get_sync_service, operation_config
 
 
 class SyncConfigListView(APIView):
@@ -55,6 +60,8 @@ class SyncConfigListView(APIView):
             layergroups=options_data.get("layergroups", True),
             workspace_filter=options_data.get("workspaceFilter", []),
             datastore_strategy=options_data.get("datastoreStrategy", "skip"),
+            prune=bool(options_data.get("prune", False)),
+            adopt=bool(options_data.get("adopt", False)),
         )
 
         sync_config = SyncConfiguration(
@@ -130,6 +137,8 @@ class SyncConfigDetailView(APIView):
                 layergroups=options_data.get("layergroups", True),
                 workspace_filter=options_data.get("workspaceFilter", []),
                 datastore_strategy=options_data.get("datastoreStrategy", "skip"),
+            prune=bool(options_data.get("prune", False)),
+            adopt=bool(options_data.get("adopt", False)),
             )
 
         config.update_sync_config(sync_config)
@@ -159,10 +168,24 @@ class SyncStartView(APIView):
             "destinationIds": ["dest-conn-id"],
             "options": {...}
         }
+        or, to finish what an interrupted sync didn't:
+        {
+            "resumeId": "operation-id"
+        }
         """
         config_id = request.data.get("configId")
+        resume_id = request.data.get("resumeId")
 
-        if config_id:
+        if resume_id:
+            found = operation_log.get(resume_id)
+            if found is None or found.kind != "sync":
+                return Response(
+                    {"error": "No interrupted sync with that ID"},
+                    status=status.HTTP_404_NOT_FOUND,
+                )
+            sync_config = operation_config(found)
+            config_id = None if sync_config.id == "temp" else sync_config.id
+        elif config_id:
             # Use saved configuration
             config = get_config()
             sync_config = config.get_sync_config(config_id)
@@ -183,6 +206,8 @@ class SyncStartView(APIView):
                 layergroups=options_data.get("layergroups", True),
                 workspace_filter=options_data.get("workspaceFilter", []),
                 datastore_strategy=options_data.get("datastoreStrategy", "skip"),
+            prune=bool(options_data.get("prune", False)),
+            adopt=bool(options_data.get("adopt", False)),
             )
 
             sync_config = SyncConfiguration(
@@ -199,6 +224,16 @@ class SyncStartView(APIView):
                 status=status.HTTP_400_BAD_REQUEST,
             )
 
+        if resume_id:
+            operation = operation_log.resume(resume_id)
+            if operation is None:
+                return Response(
+                    {"error": "The sync is already being resumed"},
+                    status=status.HTTP_409_CONFLICT,
+                )
+        else:
+            operation = begin_sync(sync_config)
+
         # Create job
         job_manager = SyncJobManager()
         job = job_manager.create_job(sync_config.id)
@@ -208,7 +243,7 @@ class SyncStartView(APIView):
             try:
                 job_manager.update_job(job.id, status="running")
                 service = get_sync_service()
-                results = service.run_sync(sync_config, job.id)
+                results = service.run_sync(sync_config, job.id, operation)
                 job_manager.update_job(
                     job.id,
                     status="completed",
@@ -229,6 +264,8 @@ class SyncStartView(APIView):
                     status="failed",
                     error=str(e),
                 )
+            finally:
+                operation_log.close(operation)
 
         thread = threading.Thread(target=run_sync, daemon=True)
         thread.start()
@@ -236,12 +273,100 @@ class SyncStartView(APIView):
         return Response(
             {
                 "jobId": job.id,
+                "operationId": operation.id,
                 "status": job.status,
             },
             status=status.HTTP_202_ACCEPTED,
         )
 
 
+class SyncStylePromoteView(APIView):
+    """Push chosen styles to other servers without touching anything else."""
+
+    def post(self, request):
+        """Promote styles.
+
+        Expected body:
+        {
+            "sourceId": "source-conn-id",
+            "destinationIds": ["dest-conn-id"],
+            "workspace": "topp",
+            "styles": ["roads", "rivers"]
+        }
+
+        An empty workspace promotes global styles. Styles whose content
+        already matches the destination's are skipped.
+        """
+        source_id = request.data.get("sourceId", "")
+        destination_ids = request.data.get("destinationIds", [])
+        names = [n for n in request.data.get("styles", []) if n]
+        workspace = request.data.get("workspace") or None
+
+        if not source_id or not destination_ids:
+            return Response(
+                {"error": "Source and destination IDs are required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        if not names:
+            return Response(
+                {"error": "Choose at least one style"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        service = get_sync_service()
+        results = {}
+        try:
+            for dest_id in destination_ids:
+                results[dest_id] = service.promote_styles(source_id, dest_id, names, workspace)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+
+        return Response({
+            "sourceId": source_id,
+            "destinationIds": destination_ids,
+            "results": results,
+        })
+
+
+class SyncSettingsView(APIView):
+    """Copy contact details and service metadata to other servers."""
+
+    def post(self, request):
+        """Preview or apply a settings sync.
+
+        Expected body:
+        {
+            "sourceId": "source-conn-id",
+            "destinationIds": ["dest-conn-id"],
+            "sections": ["contact", "wms"],  # Optional, all when omitted
+            "dryRun": true  # Only list the differences
+        }
+
+        Each destination lists the fields that differ from the source's,
+        with their current and new values. Without dryRun those fields
+        are written, and sections that failed are in its errors.
+        """
+        source_id = request.data.get("sourceId", "")
+        destination_ids = request.data.get("destinationIds", [])
+        sections = request.data.get("sections") or None
+
+        if not source_id or not destination_ids:
+            return Response(
+                {"error": "Source and destination IDs are required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        try:
+            plan = plan_settings_sync(source_id, destination_ids, sections)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+
+        dry_run = bool(request.data.get("dryRun"))
+        if not dry_run:
+            apply_settings_sync(plan)
+        return Response({**plan.to_dict(), "applied": not dry_run})
+
+
 class SyncStatusView(APIView):
     """Get sync job status."""
 
@@ -0,0 +1,255 @@
+"""Terria init files for a GeoServer connection.
+
+The Terria endpoints build catalogs on request, so a viewer reading them
+only works while CloudBench runs. An init file is the whole catalog
+written out instead: a group per workspace with a WMS item per layer,
+its legend and its extent, a group of 3D Tiles tilesets from S3 buckets,
+and a camera on the extent of all of them. Put in a folder or an S3
+bucket next to a TerriaMap build, it lets the 3D viewer be hosted
+anywhere that can reach GeoServer.
+"""
+
+import json
+from dataclasses import dataclass
+from pathlib import Path
+from typing import TYPE_CHECKING, Any
+from urllib.parse import urlencode
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.ows import service_url
+from apps.geoserver.publish import sanitize_name
+
+if TYPE_CHECKING:
+    from apps.geoserver.client import GeoServerClient
+    from apps.s3.client import S3Client
+
+# Camera on the whole globe, when no layer's extent is known
+WORLD = {"west": -180.0, "south": -90.0, "east": 180.0, "north": 90.0}
+
+# Base map the viewer opens with
+DEFAULT_BASE_MAP = "basemap-positron"
+
+LEGEND_FORMAT = "image/png"
+
+# Group of the 3D Tiles tilesets, after the workspaces
+TILESETS_GROUP = "3D Tiles"
+
+
+@dataclass
+class InitFile:
+    """A generated Terria init file."""
+
+    filename: str
+    content: str
+    layers: int = 0
+    location: str | None = None  # Path or s3:// URL it was written to
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "filename": self.filename,
+            "content": self.content,
+            "layers": self.layers,
+            "location": self.location,
+        }
+
+
+def rectangle(bbox: dict[str, Any] | None) -> dict[str, float] | None:
+    """A lat/lon bounding box as a Terria rectangle, or None if it isn't usable."""
+    if not bbox:
+        return None
+    try:
+        west, south, east, north = (float(bbox[k]) for k in ("minx", "miny", "maxx", "maxy"))
+    except (KeyError, TypeError, ValueError):
+        return None
+    return {
+        "west": max(-180.0, west),
+        "south": max(-90.0, south),
+        "east": min(180.0, east),
+        "north": min(90.0, north),
+    }
+
+
+def union_rectangle(rectangles: list[dict[str, float]]) -> dict[str, float]:
+    """The rectangle covering all those given; the whole globe if none are."""
+    if not rectangles:
+        return dict(WORLD)
+    return {
+        "west": min(r["west"] for r in rectangles),
+        "south": min(r["south"] for r in rectangles),
+        "east": max(r["east"] for r in rectangles),
+        "north": max(r["north"] for r in rectangles),
+    }
+
+
+def legend_url(base_url: str, workspace: str, layer: str, style: str | None = None) -> str:
+    """GetLegendGraphic URL of a layer on its workspace's WMS."""
+    params = {
+        "service": "WMS",
+        "version": "1.1.1",
+        "request": "GetLegendGraphic",
+        "format": LEGEND_FORMAT,
+        "layer": f"{workspace}:{layer}",
+    }
+    if style:
+        params["style"] = style
+    return f"{service_url(base_url, 'wms', workspace)}?{urlencode(params)}"
+
+
+def read_layer(
+    client: "GeoServerClient", workspace: str, name: str
+) -> tuple[dict[str, Any], dict[str, Any]]:
+    """A layer and its feature type or coverage; the resource is empty if it can't be read.
+
+    Raises:
+        GeoServerError: If the layer can't be read
+    """
+    layer = client.get_layer(workspace, name)
+    resource = layer.get("resource", {})
+    href = resource.get("href", "")
+    if not href:
+        return layer, {}
+    key = "featureType" if "featureType" in resource.get("@class", "") else "coverage"
+    try:
+        return layer, client.get_href(href).get(key, {})
+    except GeoServerError:
+        return layer, {}
+
+
+def layer_item(
+    base_url: str, workspace: str, layer: dict[str, Any], resource: dict[str, Any]
+) -> dict[str, Any]:
+    """Terria WMS catalog item of a layer, with its legend and extent.
+
+    Args:
+        base_url: Public GeoServer base URL
+        workspace: Workspace of the layer
+        layer: Layer as read from the REST API
+        resource: The layer's feature type or coverage, or {} if unknown
+    """
+    name = layer.get("name", "")
+    style = (layer.get("defaultStyle") or {}).get("name")
+    item: dict[str, Any] = {
+        "type": "wms",
+        "name": resource.get("title") or name,
+        "id": f"{workspace}:{name}",
+        "url": service_url(base_url, "wms", workspace),
+        "layers": name,
+        "parameters": {"transparent": True, "format": "image/png"},
+        "legends": [
+            {"url": legend_url(base_url, workspace, name, style), "urlMimeType": LEGEND_FORMAT}
+        ],
+        "info": [
+            {"name": "Workspace", "content": workspace},
+            {"name": "Layer", "content": name},
+        ],
+    }
+    if resource.get("abstract"):
+        item["description"] = resource["abstract"]
+    extent = rectangle(resource.get("latLonBoundingBox"))
+    if extent:
+        item["rectangle"] = extent
+    return item
+
+
+def build_init(
+    client: "GeoServerClient",
+    base_url: str,
+    name: str,
+    workspaces: list[str] | None = None,
+    tilesets: list[dict[str, Any]] | None = None,
+) -> dict[str, Any]:
+    """Build the init JSON of a connection's layers.
+
+    Args:
+        client: GeoServer client
+        base_url: Public GeoServer base URL the viewer requests maps from
+        name: Name of the catalog's top group
+        workspaces: Workspaces to include; every workspace by default
+        tilesets: 3D Tiles catalog items listed after the layers
+
+    Raises:
+        GeoServerError: If the workspaces or their layers can't be listed
+    """
+    if not workspaces:
+        workspaces = [ws["name"] for ws in client.list_workspaces() if ws.get("name")]
+
+    groups = []
+    extents = []
+    for workspace in workspaces:
+        members = []
+        for entry in client.list_layers(workspace):
+            # Workspace layer lists name layers without their workspace
+            layer_name = entry.get("name", "").rpartition(":")[2]
+            if not layer_name:
+                continue
+            layer, resource = read_layer(client, workspace, layer_name)
+            layer.setdefault("name", layer_name)
+            item = layer_item(base_url, workspace, layer, resource)
+            if "rectangle" in item:
+                extents.append(item["rectangle"])
+            members.append(item)
+        if members:
+            groups.append({"type": "group", "name": workspace, "members": members})
+    if tilesets:
+        groups.append({"type": "group", "name": TILESETS_GROUP, "members": tilesets})
+        extents.extend(item["rectangle"] for item in tilesets if "rectangle" in item)
+
+    camera = union_rectangle(extents)
+    return {
+        "catalog": [{"type": "group", "name": name, "isOpen": True, "members": groups}],
+        "homeCamera": camera,
+        "initialCamera": camera,
+        "baseMaps": {"defaultBaseMapId": DEFAULT_BASE_MAP, "previewBaseMapId": DEFAULT_BASE_MAP},
+    }
+
+
+def generate_init(
+    client: "GeoServerClient",
+    base_url: str,
+    name: str,
+    workspaces: list[str] | None = None,
+    tilesets: list[dict[str, Any]] | None = None,
+) -> InitFile:
+    """Generate the init file of a connection's layers, named after the catalog.
+
+    Raises:
+        GeoServerError: If the workspaces or their layers can't be listed
+        ValueError: If there are no layers or tilesets to include
+    """
+    init = build_init(client, base_url, name, workspaces, tilesets)
+    layers = sum(len(group["members"]) for group in init["catalog"][0]["members"])
+    if not layers:
+        raise ValueError("No layers or tilesets to put in the catalog")
+    content = json.dumps(init, indent=2) + "\n"
+    return InitFile(f"{sanitize_name(name)}.json", content, layers)
+
+
+def write_init(init: InitFile, directory: Path) -> Path:
+    """Write an init file into a folder, replacing an earlier one of the same name.
+
+    Raises:
+        OSError: If the folder doesn't exist or can't be written
+    """
+    if not directory.is_dir():
+        raise OSError(f"Folder not found: {directory}")
+    path = directory / init.filename
+    path.write_text(init.content, encoding="utf-8")
+    init.location = str(path)
+    return path
+
+
+def upload_init(init: InitFile, s3: "S3Client", bucket: str, prefix: str = "") -> str:
+    """Upload an init file to a bucket, under a key prefix.
+
+    Returns:
+        The s3:// URL it was uploaded to
+
+    Raises:
+        botocore.exceptions.ClientError: If the upload fails
+    """
+    prefix = prefix.strip("/")
+    key = f"{prefix}/{init.filename}" if prefix else init.filename
+    s3.put_object(bucket, key, init.content.encode("utf-8"), content_type="application/json")
+    init.location = f"s3://{bucket}/{key}"
+    return init.location
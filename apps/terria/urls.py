@@ -17,6 +17,12 @@ urlpatterns = [
         views.TerriaConnectionCatalogView.as_view(),
         name="terria-connection-catalog",
     ),
+    # Init file to host the viewer elsewhere
+    path(
+        "terria/connection/<str:conn_id>/init",
+        views.TerriaInitFileView.as_view(),
+        name="terria-init-file",
+    ),
     # Workspace catalog
     path(
         "terria/workspace/<str:conn_id>/<str:workspace>",
@@ -29,6 +35,12 @@ urlpatterns = [
         views.TerriaLayerCatalogView.as_view(),
         name="terria-layer-catalog",
     ),
+    # 3D Tiles tilesets in a bucket
+    path(
+        "terria/s3/<str:conn_id>/<str:bucket>",
+        views.TerriaS3TilesetsView.as_view(),
+        name="terria-s3-tilesets",
+    ),
     # Proxy
     path(
         "terria/proxy",
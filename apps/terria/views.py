@@ -4,18 +4,31 @@ Provides endpoints for:
 - Exporting GeoServer layers as Terria catalog items
 - Proxy for CORS-restricted requests
 - Terria catalog JSON generation
+- Terria init files to host the viewer elsewhere
+- 3D Tiles tilesets in S3 buckets as Terria catalog items
 """
 
 import httpx
+from pathlib import Path
 from typing import Any
 
+from botocore.exceptions import ClientError
 from django.http import HttpResponse, StreamingHttpResponse
 from rest_framework import status
 from rest_framework.response import Response
 from rest_framework.views import APIView
 
 from apps.core.config import get_config
-from apps.geoserver.client import GeoServerClientManager
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import GeoServerClientManager, get_geoserver_client
+from apps.geoserver.ows import service_url
+from apps.s3.client import get_s3_client
+from apps.s3.three_d import tileset_items
+
+from .init_file import TILESETS_GROUP, generate_init, upload_init, write_init
+
+# Where an init file can go
+INIT_DESTINATIONS = ("download", "local", "s3")
 
 
 def generate_terria_item(
@@ -36,8 +49,8 @@ def generate_terria_item(
     layer_name = layer.get("name", "")
     layer_title = layer.get("title", layer_name)
 
-    # Build WMS URL
-    wms_url = f"{geoserver_url}/{workspace}/wms"
+    # Use the workspace virtual service so same-named layers resolve correctly
+    wms_url = service_url(geoserver_url, "wms", workspace)
 
     return {
         "type": "wms",
@@ -108,7 +121,7 @@ class TerriaConnectionCatalogView(APIView):
                 try:
                     layers = client.list_layers(ws_name)
                     items = [
-                        generate_terria_item(layer, conn.url, ws_name)
+                        generate_terria_item(layer, conn.public_base_url, ws_name)
                         for layer in layers
                     ]
 
@@ -163,7 +176,7 @@ class TerriaWorkspaceCatalogView(APIView):
 
             layers = client.list_layers(workspace)
             items = [
-                generate_terria_item(layer, conn.url, workspace)
+                generate_terria_item(layer, conn.public_base_url, workspace)
                 for layer in layers
             ]
 
@@ -210,7 +223,7 @@ class TerriaLayerCatalogView(APIView):
                     status=status.HTTP_404_NOT_FOUND,
                 )
 
-            item = generate_terria_item(layer_info, conn.url, workspace)
+            item = generate_terria_item(layer_info, conn.public_base_url, workspace)
 
             return Response(item)
         except ValueError as e:
@@ -306,3 +319,113 @@ class TerriaInitView(APIView):
         }
 
         return Response(init_config)
+
+
+class TerriaInitFileView(APIView):
+    """Write a connection's catalog as a Terria init file."""
+
+    def post(self, request, conn_id):
+        """Generate an init file and download it, or write it to a folder or bucket.
+
+        Expected body:
+        {
+            "workspaces": ["topp"],  // Every workspace by default
+            "name": "optional_catalog_name",  // The connection's name by default
+            "destination": "download",  // download, local or s3
+            "directory": "optional_folder",  // local: the file browser's folder by default
+            "s3Connection": "s3_conn_id",  // s3: connection, bucket and key prefix
+            "bucket": "maps",
+            "prefix": "terria/init",
+            "tilesets": {  // Optional 3D Tiles, read from the bucket's public URLs
+                "s3Connection": "s3_conn_id",
+                "bucket": "lidar",
+                "prefix": "city"
+            }
+        }
+        """
+        data = request.data
+        destination = data.get("destination", "download")
+        if destination not in INIT_DESTINATIONS:
+            return Response(
+                {"error": f"Unknown destination; use one of {', '.join(INIT_DESTINATIONS)}"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        if destination == "s3" and not (data.get("s3Connection") and data.get("bucket")):
+            return Response(
+                {"error": "An S3 connection and bucket are required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        config = get_config()
+        connection = config.get_connection(conn_id)
+        if not connection:
+            return Response({"error": "Connection not found"}, status=status.HTTP_404_NOT_FOUND)
+
+        try:
+            tilesets = None
+            source = data.get("tilesets")
+            if source:
+                if not (source.get("s3Connection") and source.get("bucket")):
+                    raise ValueError("Tilesets need an S3 connection and bucket")
+                s3 = get_s3_client(source["s3Connection"])
+                tilesets = tileset_items(
+                    s3,
+                    source["bucket"],
+                    source.get("prefix", ""),
+                    lambda key: s3.object_url(source["bucket"], key),
+                )
+            init = generate_init(
+                get_geoserver_client(conn_id),
+                connection.public_base_url,
+                data.get("name") or connection.name,
+                data.get("workspaces") or None,
+                tilesets,
+            )
+            if destination == "download":
+                response = HttpResponse(init.content, content_type="application/json")
+                response["Content-Disposition"] = f'attachment; filename="{init.filename}"'
+                return response
+            if destination == "local":
+                directory = Path(data.get("directory") or config.last_local_path).expanduser()
+                write_init(init, directory)
+            else:
+                upload_init(
+                    init,
+                    get_s3_client(data["s3Connection"]),
+                    data["bucket"],
+                    data.get("prefix", ""),
+                )
+            return Response(init.to_dict())
+        except (OSError, ValueError) as e:
+            return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
+        except GeoServerError as e:
+            return Response(
+                {"error": e.message}, status=e.status_code or status.HTTP_502_BAD_GATEWAY
+            )
+        except ClientError as e:
+            return Response({"error": str(e)}, status=status.HTTP_502_BAD_GATEWAY)
+
+
+class TerriaS3TilesetsView(APIView):
+    """3D Tiles tilesets in a bucket as a Terria catalog group."""
+
+    def get(self, request, conn_id, bucket):
+        """List a bucket's tilesets, read by the viewer through the S3 proxy.
+
+        Query params:
+        - prefix: Key prefix to look under
+        """
+        prefix = request.query_params.get("prefix", "")
+        try:
+            items = tileset_items(
+                get_s3_client(conn_id),
+                bucket,
+                prefix,
+                lambda key: f"/api/s3/proxy/{conn_id}/{bucket}/{key}",
+            )
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except ClientError as e:
+            return Response({"error": str(e)}, status=status.HTTP_502_BAD_GATEWAY)
+        return Response(
+            {"catalog": [{"type": "group", "name": TILESETS_GROUP, "members": items}]}
+        )
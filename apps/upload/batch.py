@@ -0,0 +1,413 @@
+"""Batch upload of a directory of geospatial files.
+
+A directory is walked and each supported file classified; a mapping
+(workspace, store and layer names derived from the file path) is proposed
+for the user to edit, then the mapped files are published concurrently.
+
+Each batch is written to the operation log first (see apps.core.oplog),
+keyed by file path, so a batch cut short can be resumed with only the
+files that weren't published.
+"""
+
+import logging
+import os
+from collections.abc import Callable
+from concurrent.futures import ThreadPoolExecutor, as_completed
+from dataclasses import asdict, dataclass, field
+from pathlib import Path, PurePosixPath
+from typing import Any
+
+from apps.core import hooks
+from apps.core.exceptions import GeoServerError
+from apps.core.oplog import Operation, operation_log
+from apps.geoserver.client import GeoServerClient
+from apps.geoserver.naming import validate_name
+from apps.geoserver.publish import publish_feature_types, sanitize_name
+from apps.gwc.invalidation import invalidate_store_cache
+
+from .cog import auto_convert
+from .metadata import LayerMetadata, apply_metadata, suggest_metadata
+from .verify import verify_upload
+
+logger = logging.getLogger(__name__)
+
+# File kind by extension; zips are zipped shapefiles
+FILE_KINDS = {
+    ".zip": "shapefile",
+    ".tif": "geotiff",
+    ".tiff": "geotiff",
+    ".gpkg": "geopackage",
+    ".sld": "style",
+}
+
+DEFAULT_CONCURRENCY = 4
+
+
+def classify_file(path: str) -> str | None:
+    """Get the kind of a file from its name.
+
+    Args:
+        path: File path or name
+
+    Returns:
+        "shapefile", "geotiff", "geopackage" or "style", or None if unsupported
+    """
+    return FILE_KINDS.get(PurePosixPath(path).suffix.lower())
+
+
+def _stem(path: str) -> str:
+    name = PurePosixPath(path).stem
+    # roads.shp.zip -> roads
+    if name.lower().endswith(".shp"):
+        name = name[:-4]
+    return name
+
+
+@dataclass
+class BatchItem:
+    """How one file of a batch is published."""
+
+    path: str
+    kind: str
+    workspace: str
+    store: str = ""  # Unused for styles
+    layer: str = ""  # Style name for styles; unused for GeoPackages
+    # Layer metadata of shapefiles and GeoTIFFs; empty fields are suggested on publish
+    title: str = ""
+    abstract: str = ""
+    keywords: list[str] = field(default_factory=list)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return asdict(self)
+
+    @property
+    def metadata(self) -> LayerMetadata:
+        """Metadata of the layer the file publishes."""
+        return LayerMetadata(self.title, self.abstract, list(self.keywords))
+
+    @classmethod
+    def from_dict(cls, data: dict[str, Any]) -> "BatchItem":
+        """Build from an API request, validating the names."""
+        metadata = LayerMetadata.from_dict(data)
+        item = cls(
+            path=str(data.get("path", "")),
+            kind=str(data.get("kind", "")),
+            workspace=str(data.get("workspace", "")).strip(),
+            store=str(data.get("store", "")).strip(),
+            layer=str(data.get("layer", "")).strip(),
+            title=metadata.title,
+            abstract=metadata.abstract,
+            keywords=metadata.keywords,
+        )
+        item.validate()
+        return item
+
+    def validate(self) -> None:
+        """Check the mapping is complete for the file kind.
+
+        Raises:
+            ValueError: If a required name is missing or the kind is unknown
+        """
+        if self.kind not in FILE_KINDS.values():
+            raise ValueError(f"{self.path}: unsupported file kind '{self.kind}'")
+        if not self.workspace:
+            raise ValueError(f"{self.path}: workspace is required")
+        if self.kind == "style":
+            if not self.layer:
+                raise ValueError(f"{self.path}: style name is required")
+        elif not self.store:
+            raise ValueError(f"{self.path}: store name is required")
+
+
+def propose_mapping(
+    paths: list[str],
+    workspace: str,
+    folder_workspaces: bool = False,
+) -> list[BatchItem]:
+    """Propose how each file of a directory is published.
+
+    Store and layer names come from the file name. Names are made unique
+    within each workspace, so roads.zip and roads.tif don't collide.
+
+    Args:
+        paths: File paths relative to the directory root
+        workspace: Default workspace
+        folder_workspaces: Use each file's top-level folder as its workspace
+
+    Returns:
+        One item per supported file, in path order
+    """
+    items = []
+    used: set[tuple[str, str]] = set()
+
+    for path in sorted(paths):
+        kind = classify_file(path)
+        if kind is None:
+            continue
+
+        parts = PurePosixPath(path).parts
+        target = workspace
+        if folder_workspaces and len(parts) > 1:
+            target = sanitize_name(parts[0])
+
+        name = sanitize_name(_stem(path))
+        # Styles live in their own namespace
+        key_kind = "style" if kind == "style" else "store"
+        unique = name
+        counter = 2
+        while (target, f"{key_kind}:{unique}") in used:
+            unique = f"{name}_{counter}"
+            counter += 1
+        used.add((target, f"{key_kind}:{unique}"))
+
+        if kind == "style":
+            items.append(BatchItem(path, kind, target, layer=unique))
+        elif kind == "geopackage":
+            items.append(BatchItem(path, kind, target, store=unique))
+        else:
+            metadata = suggest_metadata(path, kind)
+            items.append(
+                BatchItem(
+                    path,
+                    kind,
+                    target,
+                    store=unique,
+                    layer=unique,
+                    title=metadata.title,
+                    abstract=metadata.abstract,
+                    keywords=metadata.keywords,
+                )
+            )
+
+    return items
+
+
+def walk_directory(root: str | Path) -> list[str]:
+    """List the supported files below a local directory.
+
+    Args:
+        root: Directory to walk
+
+    Returns:
+        Paths relative to root, with forward slashes, sorted
+    """
+    root = Path(root)
+    paths = []
+    for dirpath, dirnames, filenames in os.walk(root):
+        # Skip hidden folders such as .git
+        dirnames[:] = [d for d in dirnames if not d.startswith(".")]
+        for filename in filenames:
+            if filename.startswith(".") or classify_file(filename) is None:
+                continue
+            paths.append((Path(dirpath) / filename).relative_to(root).as_posix())
+    return sorted(paths)
+
+
+def publish_item(
+    client: GeoServerClient,
+    connection_id: str,
+    item: BatchItem,
+    data: bytes,
+    verify: bool = False,
+) -> dict[str, Any]:
+    """Publish one mapped file.
+
+    Args:
+        client: GeoServer client
+        connection_id: Connection ID, for tile cache invalidation
+        item: The file's mapping
+        data: File contents
+        verify: Compare checksums with the files GeoServer stored
+
+    Returns:
+        Result with the published store, layer or style name
+    """
+    result: dict[str, Any] = {"path": item.path, "kind": item.kind, "workspace": item.workspace}
+
+    if item.kind == "style":
+        validate_name("style", item.layer, item.workspace)
+        client.create_style(item.layer, data.decode("utf-8"), workspace=item.workspace)
+        result["style"] = item.layer
+        return result
+
+    store_type = "coveragestore" if item.kind == "geotiff" else "datastore"
+    validate_name(store_type, item.store, item.workspace)
+    if item.layer:
+        validate_name("layer", item.layer, item.workspace)
+
+    # Layer to set the metadata of; all of the store's when None
+    metadata_layer: str | None = None
+    if item.kind == "shapefile":
+        client.upload_shapefile(item.workspace, item.store, data)
+        # The published feature type is named after the shapefile in the zip
+        feature_types = client.list_featuretypes(item.workspace, item.store)
+        if item.layer and len(feature_types) == 1:
+            metadata_layer = item.layer
+            current = feature_types[0].get("name", "")
+            if current and current != item.layer:
+                client.update_featuretype(
+                    item.workspace, item.store, current, {"name": item.layer}
+                )
+    elif item.kind == "geotiff":
+        # Nobody to ask in a batch, so only the configured threshold applies
+        data = auto_convert(item.path, data)
+        client.upload_geotiff(item.workspace, item.store, data, coverage_name=item.layer or None)
+        metadata_layer = item.layer or None
+    else:
+        # Every table becomes a layer named after it, not just the first
+        client.upload_geopackage(item.workspace, item.store, data, configure="none")
+        published = publish_feature_types(client, item.workspace, item.store)
+        result["layers"] = [p["layer"] for p in published if p["status"] == "published"]
+        failed = [f"{p['table']}: {p['error']}" for p in published if p["status"] == "error"]
+        if failed:
+            result["warning"] = "Some tables were not published: " + "; ".join(failed)
+
+    if item.kind != "geopackage":
+        # GeoPackage tables are published with their table names as titles
+        metadata = item.metadata.merged(suggest_metadata(item.path, item.kind))
+        try:
+            apply_metadata(
+                client, item.workspace, item.store, store_type, metadata, metadata_layer
+            )
+        except GeoServerError as e:
+            result["warning"] = f"Layer metadata was not set: {e.message}"
+
+    result["store"] = item.store
+    result["layer"] = item.layer
+    truncated = invalidate_store_cache(connection_id, item.workspace, item.store, store_type)
+    if truncated:
+        result["truncatedLayers"] = truncated
+    if verify:
+        verification = verify_upload(
+            client, item.workspace, item.store, store_type, item.path, data
+        )
+        result["verification"] = verification.to_dict()
+    hooks.emit(
+        "upload.completed",
+        client.connection,
+        item.workspace,
+        item.store,
+        filename=item.path,
+        store_type=item.kind,
+    )
+    return result
+
+
+def ensure_workspaces(client: GeoServerClient, items: list[BatchItem]) -> list[str]:
+    """Create the workspaces a batch targets that don't exist yet.
+
+    Returns:
+        Names of the created workspaces
+    """
+    existing = {ws.get("name") for ws in client.list_workspaces()}
+    created = []
+    missing = sorted({item.workspace for item in items} - existing)
+    # Check every name first so a batch doesn't stop halfway through
+    for name in missing:
+        validate_name("workspace", name)
+    for name in missing:
+        client.create_workspace(name)
+        created.append(name)
+    return created
+
+
+def run_batch(
+    client: GeoServerClient,
+    connection_id: str,
+    items: list[BatchItem],
+    read_file: Callable[[BatchItem], bytes],
+    max_workers: int = DEFAULT_CONCURRENCY,
+    on_result: Callable[[dict[str, Any]], None] | None = None,
+    verify: bool = False,
+    operation: Operation | None = None,
+) -> list[dict[str, Any]]:
+    """Publish mapped files concurrently.
+
+    Failures are recorded per file; one bad file doesn't stop the batch.
+
+    Args:
+        client: GeoServer client
+        connection_id: Connection ID
+        items: Mapped files
+        read_file: Returns a file's contents
+        max_workers: Files published at once
+        on_result: Called with each file's result as it finishes
+        verify: Compare checksums with the files GeoServer stored
+        operation: Logged operation to record each file in
+
+    Returns:
+        One result per item, in item order, with status "success" or "error"
+    """
+    ensure_workspaces(client, items)
+
+    def publish(item: BatchItem) -> dict[str, Any]:
+        try:
+            result = publish_item(client, connection_id, item, read_file(item), verify)
+            result["status"] = "success"
+        except (GeoServerError, OSError, UnicodeDecodeError) as e:
+            logger.warning("Batch upload of %s failed: %s", item.path, e)
+            result = {"path": item.path, "kind": item.kind, "status": "error", "error": str(e)}
+        if operation is not None:
+            ok = result["status"] == "success"
+            operation_log.record(operation, item.path, ok, "" if ok else result["error"])
+        if on_result:
+            on_result(result)
+        return result
+
+    results: dict[str, dict[str, Any]] = {}
+    with ThreadPoolExecutor(max_workers=max(1, max_workers)) as executor:
+        futures = {executor.submit(publish, item): item for item in items}
+        for future in as_completed(futures):
+            results[futures[future].path] = future.result()
+
+    return [results[item.path] for item in items]
+
+
+def begin_batch(
+    connection_id: str, root: str | Path, items: list[BatchItem], verify: bool = False
+) -> Operation:
+    """Log a batch of a local directory before it is published."""
+    return operation_log.begin(
+        "upload",
+        connection_id,
+        [item.path for item in items],
+        {
+            "root": str(root),
+            "items": [item.to_dict() for item in items],
+            "verify": verify,
+        },
+    )
+
+
+def pending_items(operation: Operation) -> list[BatchItem]:
+    """The files of an interrupted batch that weren't published."""
+    pending = set(operation.pending)
+    return [
+        BatchItem(**data) for data in operation.params.get("items", []) if data["path"] in pending
+    ]
+
+
+def resume_batch(
+    client: GeoServerClient,
+    operation: Operation,
+    on_result: Callable[[dict[str, Any]], None] | None = None,
+) -> list[dict[str, Any]]:
+    """Publish the files an interrupted batch didn't, from the same directory.
+
+    Returns:
+        One result per file published this time
+    """
+    root = Path(operation.params.get("root", ""))
+    try:
+        return run_batch(
+            client,
+            operation.conn_id,
+            pending_items(operation),
+            read_file=lambda item: (root / item.path).read_bytes(),
+            on_result=on_result,
+            verify=bool(operation.params.get("verify")),
+            operation=operation,
+        )
+    finally:
+        operation_log.close(operation)
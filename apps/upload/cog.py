@@ -0,0 +1,306 @@
+"""Cloud Optimized GeoTIFF advice and conversion before upload.
+
+A GeoTIFF stored in strips or without overviews makes GeoServer read far
+more of the file than a map request needs. The layout is read from the
+TIFF directories without an imaging library, and such files can be
+converted to a COG (tiled, with overviews) locally with GDAL before they
+are sent. Files over a configured size can be converted without asking.
+"""
+
+import logging
+import shutil
+import struct
+import subprocess
+import tempfile
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, BinaryIO
+
+from apps.core.config import config_manager, get_cache_dir
+from apps.core.exceptions import UploadError
+
+logger = logging.getLogger(__name__)
+
+# TIFF tags read from each directory
+TAG_SUBFILE_TYPE = 254
+TAG_WIDTH = 256
+TAG_HEIGHT = 257
+TAG_BITS_PER_SAMPLE = 258
+TAG_COMPRESSION = 259
+TAG_SAMPLES_PER_PIXEL = 277
+TAG_TILE_WIDTH = 322
+
+# NewSubfileType bits
+SUBFILE_REDUCED = 1  # An overview
+SUBFILE_MASK = 4  # A transparency mask
+
+# Field type -> (struct code, size) for the integer types layouts use
+_FIELD_TYPES = {1: ("B", 1), 3: ("H", 2), 4: ("I", 4), 16: ("Q", 8)}
+
+# Images no larger than this in either dimension don't need overviews
+OVERVIEW_MIN_SIZE = 1024
+
+# Rough gdal_translate COG throughput, for the time estimate
+CONVERT_BYTES_PER_SEC = 30 * 1024 * 1024
+
+# Overviews add about a third to the full resolution image
+OVERVIEW_FACTOR = 4 / 3
+
+# DEFLATE size relative to uncompressed imagery, for the size estimate
+DEFLATE_RATIO = 0.6
+
+# Give up on files with more directories than this (or a loop of them)
+_MAX_IFDS = 64
+
+
+@dataclass
+class TiffLayout:
+    """How a TIFF stores its full resolution image."""
+
+    width: int
+    height: int
+    tiled: bool
+    overviews: int
+    compression: int  # 1 = none
+    bands: int = 1
+    bits: int = 8
+    cog: bool = False  # Written by GDAL with the COG layout
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "width": self.width,
+            "height": self.height,
+            "tiled": self.tiled,
+            "overviews": self.overviews,
+            "compressed": self.compression != 1,
+            "bands": self.bands,
+            "bits": self.bits,
+            "cog": self.cog,
+        }
+
+
+@dataclass
+class CogAdvice:
+    """Whether a GeoTIFF should be converted to a COG before upload."""
+
+    file_size: int
+    layout: TiffLayout | None = None
+    reasons: list[str] = field(default_factory=list)
+    estimated_size: int = 0
+    estimated_seconds: int = 0
+    auto_convert: bool = False  # Over the configured threshold
+    gdal_available: bool = False
+
+    @property
+    def needs_conversion(self) -> bool:
+        return bool(self.reasons)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "fileSize": self.file_size,
+            "layout": self.layout.to_dict() if self.layout else None,
+            "needsConversion": self.needs_conversion,
+            "reasons": self.reasons,
+            "estimatedSize": self.estimated_size,
+            "estimatedSeconds": self.estimated_seconds,
+            "autoConvert": self.auto_convert,
+            "gdalAvailable": self.gdal_available,
+        }
+
+
+def _read(f: BinaryIO, offset: int, fmt: str) -> tuple:
+    f.seek(offset)
+    size = struct.calcsize(fmt)
+    data = f.read(size)
+    if len(data) < size:
+        raise ValueError("Truncated TIFF file")
+    return struct.unpack(fmt, data)
+
+
+def read_tiff_layout(f: BinaryIO) -> TiffLayout:
+    """Read the layout of a classic or BigTIFF file.
+
+    Args:
+        f: Seekable binary file
+
+    Returns:
+        Layout of the first (full resolution) image and its overview count
+
+    Raises:
+        ValueError: If the file isn't a TIFF
+    """
+    f.seek(0)
+    head = f.read(1024)
+    if head[:2] == b"II":
+        order = "<"
+    elif head[:2] == b"MM":
+        order = ">"
+    else:
+        raise ValueError("Not a TIFF file")
+    (magic,) = struct.unpack(order + "H", head[2:4])
+    if magic == 42:
+        count_fmt, entry_fmt, offset_fmt, entry_size = "H", "HHI", "I", 12
+        (ifd,) = struct.unpack(order + "I", head[4:8])
+    elif magic == 43:
+        count_fmt, entry_fmt, offset_fmt, entry_size = "Q", "HHQ", "Q", 20
+        (ifd,) = struct.unpack(order + "Q", head[8:16])
+    else:
+        raise ValueError("Not a TIFF file")
+    inline = struct.calcsize(order + offset_fmt)
+    count_size = struct.calcsize(order + count_fmt)
+
+    directories = []
+    seen = set()
+    while ifd and ifd not in seen and len(directories) < _MAX_IFDS:
+        seen.add(ifd)
+        (entries,) = _read(f, ifd, order + count_fmt)
+        tags = {}
+        for i in range(entries):
+            entry = ifd + count_size + i * entry_size
+            tag, type_, count = _read(f, entry, order + entry_fmt)
+            if type_ not in _FIELD_TYPES or count < 1:
+                continue
+            code, size = _FIELD_TYPES[type_]
+            # Only the first value matters; it's inline when all values fit
+            value_at = entry + struct.calcsize(order + entry_fmt)
+            if count * size > inline:
+                (value_at,) = _read(f, value_at, order + offset_fmt)
+            (tags[tag],) = _read(f, value_at, order + code)
+        directories.append(tags)
+        (ifd,) = _read(f, ifd + count_size + entries * entry_size, order + offset_fmt)
+
+    if not directories:
+        raise ValueError("TIFF file has no images")
+    full = directories[0]
+    overviews = sum(
+        1
+        for tags in directories[1:]
+        if tags.get(TAG_SUBFILE_TYPE, 0) & SUBFILE_REDUCED
+        and not tags.get(TAG_SUBFILE_TYPE, 0) & SUBFILE_MASK
+    )
+    return TiffLayout(
+        width=full.get(TAG_WIDTH, 0),
+        height=full.get(TAG_HEIGHT, 0),
+        tiled=TAG_TILE_WIDTH in full,
+        overviews=overviews,
+        compression=full.get(TAG_COMPRESSION, 1),
+        bands=full.get(TAG_SAMPLES_PER_PIXEL, 1),
+        bits=full.get(TAG_BITS_PER_SAMPLE, 8),
+        # GDAL's ghost area just after the header
+        cog=b"LAYOUT=IFDS_BEFORE_DATA" in head,
+    )
+
+
+def gdal_available() -> bool:
+    """Whether gdal_translate is on the PATH."""
+    return shutil.which("gdal_translate") is not None
+
+
+def threshold_bytes() -> int:
+    """Size over which GeoTIFFs are converted without asking; 0 = always ask."""
+    return max(0, config_manager.config.cog_auto_convert_mb) * 1024 * 1024
+
+
+def advise(path: Path) -> CogAdvice:
+    """Check whether a GeoTIFF would benefit from COG conversion.
+
+    Args:
+        path: GeoTIFF file
+
+    Returns:
+        Advice with the reasons to convert, if any, and size and time
+        estimates for the conversion
+    """
+    size = path.stat().st_size
+    advice = CogAdvice(file_size=size, gdal_available=gdal_available())
+    try:
+        with open(path, "rb") as f:
+            layout = read_tiff_layout(f)
+    except (OSError, ValueError) as e:
+        logger.warning("Could not read the layout of %s: %s", path.name, e)
+        return advice
+    advice.layout = layout
+
+    if not layout.tiled:
+        advice.reasons.append("Stored in strips rather than tiles")
+    if not layout.overviews and max(layout.width, layout.height) > OVERVIEW_MIN_SIZE:
+        advice.reasons.append("Has no overviews")
+    if not advice.needs_conversion:
+        return advice
+
+    estimated = size * (OVERVIEW_FACTOR if not layout.overviews else 1)
+    if layout.compression == 1:
+        estimated *= DEFLATE_RATIO
+    advice.estimated_size = int(estimated)
+    advice.estimated_seconds = max(1, round(size / CONVERT_BYTES_PER_SEC))
+    threshold = threshold_bytes()
+    advice.auto_convert = bool(threshold) and size > threshold
+    return advice
+
+
+def convert_to_cog(source: Path, target: Path, timeout: float = 3600) -> Path:
+    """Convert a GeoTIFF to a Cloud Optimized GeoTIFF with GDAL.
+
+    Args:
+        source: GeoTIFF to convert
+        target: Path of the COG to write
+        timeout: Seconds to allow gdal_translate
+
+    Returns:
+        The target path
+
+    Raises:
+        UploadError: If GDAL is missing or the conversion fails
+    """
+    command = [
+        "gdal_translate",
+        "-of", "COG",
+        "-co", "COMPRESS=DEFLATE",
+        "-co", "OVERVIEWS=AUTO",
+        "-co", "BIGTIFF=IF_SAFER",
+        str(source),
+        str(target),
+    ]
+    try:
+        result = subprocess.run(command, capture_output=True, text=True, timeout=timeout)
+    except FileNotFoundError as e:
+        raise UploadError("gdal_translate not found. Please install GDAL.") from e
+    except subprocess.TimeoutExpired as e:
+        raise UploadError(f"COG conversion of {source.name} timed out") from e
+    if result.returncode != 0:
+        message = result.stderr.strip().splitlines()[-1:] or ["unknown error"]
+        raise UploadError(f"COG conversion of {source.name} failed: {message[0]}")
+    return target
+
+
+def auto_convert(name: str, data: bytes) -> bytes:
+    """Convert GeoTIFF contents to a COG when they're over the threshold.
+
+    Used where there's nobody to ask, such as batch uploads. Files that
+    don't need converting, or can't be converted, are returned unchanged.
+
+    Args:
+        name: File name, for logging
+        data: GeoTIFF contents
+
+    Returns:
+        The COG contents, or the original contents
+    """
+    threshold = threshold_bytes()
+    if not threshold or len(data) <= threshold or not gdal_available():
+        return data
+    with tempfile.TemporaryDirectory(dir=get_cache_dir()) as tmp:
+        source = Path(tmp) / "source.tif"
+        source.write_bytes(data)
+        advice = advise(source)
+        if not advice.needs_conversion:
+            return data
+        try:
+            target = convert_to_cog(source, Path(tmp) / "cog.tif")
+        except UploadError as e:
+            logger.warning("Uploading %s as it is: %s", name, e.message)
+            return data
+        logger.info("Converted %s to a COG before upload", name)
+        return target.read_bytes()
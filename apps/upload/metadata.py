@@ -0,0 +1,202 @@
+"""Titles, abstracts and keywords for layers published from uploads.
+
+GeoServer names a layer after its file and leaves its title, abstract and
+keywords empty, so catalogs and capabilities documents list "roads_2024"
+with nothing to say about it. These are suggested from the file instead:
+a title from its name, an abstract from where it came from and what it
+holds, and keywords from the folders it was in and its kind of data. The
+suggestions are shown for editing before upload; fields left empty are
+filled in from the uploaded file when the layer is published.
+"""
+
+import re
+from dataclasses import asdict, dataclass, field
+from pathlib import PurePosixPath
+from typing import TYPE_CHECKING, Any
+
+if TYPE_CHECKING:
+    from apps.geoserver.client import GeoServerClient
+
+    from .preview import FileSummary
+
+# Keywords for each kind of upload
+KIND_KEYWORDS = {
+    "shapefile": ["vector", "shapefile"],
+    "geopackage": ["vector", "geopackage"],
+    "geotiff": ["raster", "geotiff"],
+}
+
+# Folder names that say nothing about the data in them
+GENERIC_FOLDERS = {"data", "gis", "files", "upload", "uploads", "tmp", "temp", "shp", "shapefiles"}
+
+# Extensions taken off a file name for its title, e.g. roads.shp.zip
+_SUFFIXES = (".zip", ".shp", ".tiff", ".tif", ".gpkg")
+
+
+@dataclass
+class LayerMetadata:
+    """Title, abstract and keywords of a published layer."""
+
+    title: str = ""
+    abstract: str = ""
+    keywords: list[str] = field(default_factory=list)
+
+    @property
+    def is_empty(self) -> bool:
+        """Whether there is nothing to set."""
+        return not (self.title or self.abstract or self.keywords)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return asdict(self)
+
+    @classmethod
+    def from_dict(cls, data: dict[str, Any] | None) -> "LayerMetadata":
+        """Read metadata from an API request; keywords may be a comma-separated string."""
+        data = data or {}
+        keywords = data.get("keywords") or []
+        if isinstance(keywords, str):
+            keywords = keywords.split(",")
+        return cls(
+            title=str(data.get("title") or "").strip(),
+            abstract=str(data.get("abstract") or "").strip(),
+            keywords=[k for k in (str(k).strip() for k in keywords) if k],
+        )
+
+    def merged(self, suggestion: "LayerMetadata") -> "LayerMetadata":
+        """Fill the empty fields from a suggestion."""
+        return LayerMetadata(
+            title=self.title or suggestion.title,
+            abstract=self.abstract or suggestion.abstract,
+            keywords=self.keywords or suggestion.keywords,
+        )
+
+    def to_resource(self) -> dict[str, Any]:
+        """Fields of a feature type or coverage update, leaving out empty ones."""
+        resource: dict[str, Any] = {}
+        if self.title:
+            resource["title"] = self.title
+        if self.abstract:
+            resource["abstract"] = self.abstract
+        if self.keywords:
+            resource["keywords"] = {"string": self.keywords}
+        return resource
+
+
+def _stem(filename: str) -> str:
+    name = PurePosixPath(filename).name
+    for suffix in _SUFFIXES:
+        if name.lower().endswith(suffix):
+            name = name[: -len(suffix)]
+    return name
+
+
+def prettify_name(filename: str) -> str:
+    """Make a title of a file name, e.g. "main_roads-2024.shp.zip" -> "Main Roads 2024".
+
+    Words in capitals, such as OSM, are kept as they are.
+    """
+    # mainRoads -> main Roads
+    name = re.sub(r"(?<=[a-z])(?=[A-Z])", " ", _stem(filename))
+    words = [w for w in re.split(r"[\s_.\-]+", name) if w]
+    return " ".join(w if w.isupper() else w[:1].upper() + w[1:] for w in words)
+
+
+def _number(value: float) -> str:
+    return f"{value:.6g}"
+
+
+def _describe(summary: "FileSummary") -> list[str]:
+    """Sentences about what a file holds."""
+    sentences = []
+    if summary.layers:
+        known = [
+            layer.feature_count for layer in summary.layers if layer.feature_count is not None
+        ]
+        geometry = summary.layers[0].geometry_type
+        if len(known) == len(summary.layers):
+            kind = f"{geometry} features" if geometry else "features"
+            sentences.append(f"{sum(known):,} {kind}.")
+        elif geometry:
+            sentences.append(f"{geometry} features.")
+    if summary.size:
+        width, height = summary.size
+        bands = f" in {len(summary.bands)} band(s)" if summary.bands else ""
+        sentences.append(f"Raster of {width:,} x {height:,} pixels{bands}.")
+    if summary.crs:
+        sentences.append(f"Coordinate reference system: {summary.crs}.")
+    if summary.extent:
+        sentences.append(f"Extent: {', '.join(_number(v) for v in summary.extent)}.")
+    return sentences
+
+
+def suggest_metadata(
+    path: str, kind: str | None, summary: "FileSummary | None" = None
+) -> LayerMetadata:
+    """Suggest the title, abstract and keywords of the layer a file publishes.
+
+    Args:
+        path: File name, or its path relative to the folder being uploaded
+        kind: Kind of file, as classified for upload
+        summary: What the file holds, from describe_file(), when it was read
+
+    Returns:
+        The suggestion
+    """
+    name = PurePosixPath(path).name
+    abstract = [f"Published from {name}."]
+    if summary is not None and not summary.error:
+        abstract += _describe(summary)
+
+    keywords: dict[str, None] = {}
+    for folder in PurePosixPath(path).parts[:-1]:
+        if folder.lower() not in GENERIC_FOLDERS and folder not in (".", ".."):
+            keywords[prettify_name(folder).lower()] = None
+    for keyword in KIND_KEYWORDS.get(kind or "", []):
+        keywords[keyword] = None
+    if summary is not None:
+        for layer in summary.layers:
+            if layer.geometry_type:
+                keywords[layer.geometry_type.lower()] = None
+
+    return LayerMetadata(prettify_name(path), " ".join(abstract), [k for k in keywords if k])
+
+
+def apply_metadata(
+    client: "GeoServerClient",
+    workspace: str,
+    store: str,
+    store_type: str,
+    metadata: LayerMetadata,
+    layer: str | None = None,
+) -> list[str]:
+    """Set the title, abstract and keywords of the layers an upload published.
+
+    Args:
+        client: GeoServer client
+        workspace: Workspace of the store
+        store: Store the upload created
+        store_type: "datastore" or "coveragestore"
+        metadata: Metadata to set; empty fields are left alone
+        layer: Feature type or coverage to update; all of the store's when None
+
+    Returns:
+        Names of the updated feature types or coverages
+
+    Raises:
+        GeoServerError: If the store can't be listed or a layer updated
+    """
+    resource = metadata.to_resource()
+    if not resource:
+        return []
+    if store_type == "coveragestore":
+        names = [layer] if layer else [c["name"] for c in client.list_coverages(workspace, store)]
+        for name in names:
+            client.update_coverage(workspace, store, name, resource)
+    else:
+        names = (
+            [layer] if layer else [f["name"] for f in client.list_featuretypes(workspace, store)]
+        )
+        for name in names:
+            client.update_featuretype(workspace, store, name, resource)
+    return names
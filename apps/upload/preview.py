@@ -0,0 +1,298 @@
+"""Previews of local geospatial files before upload.
+
+Rasters and vector sources are summarised from gdalinfo and ogrinfo JSON
+output: driver, CRS, extent and the band or field list. Where GDAL is
+installed a small thumbnail is drawn too, so the right file can be picked
+without opening a desktop GIS. Style files are previewed as text, with
+the language used for syntax highlighting.
+"""
+
+import json
+import subprocess
+import tempfile
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any
+
+from apps.core.config import get_cache_dir
+from apps.core.raster import Image, decode_png
+
+from .batch import classify_file
+
+# Style files longer than this are cut short in the preview
+STYLE_PREVIEW_CHARS = 64 * 1024
+
+# Syntax highlighting language by style file suffix
+STYLE_LANGUAGES = {".sld": "xml", ".css": "css"}
+
+# Largest file the web UI sends to be previewed
+PREVIEW_MAX_BYTES = 200 * 1024 * 1024
+
+# Color of features burned into vector thumbnails
+VECTOR_COLOR = (221, 153, 51, 255)
+
+
+@dataclass
+class LayerSummary:
+    """A layer of a vector source."""
+
+    name: str
+    geometry_type: str | None = None
+    feature_count: int | None = None
+    fields: list[tuple[str, str]] = field(default_factory=list)  # (name, type)
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "name": self.name,
+            "geometryType": self.geometry_type,
+            "featureCount": self.feature_count,
+            "fields": [{"name": name, "type": type_} for name, type_ in self.fields],
+        }
+
+
+@dataclass
+class FileSummary:
+    """What a local file holds."""
+
+    name: str
+    kind: str | None  # As classified for upload
+    driver: str | None = None
+    crs: str | None = None
+    extent: tuple[float, float, float, float] | None = None  # minx, miny, maxx, maxy
+    size: tuple[int, int] | None = None  # Raster width, height
+    bands: list[str] = field(default_factory=list)
+    layers: list[LayerSummary] = field(default_factory=list)
+    style_text: str | None = None
+    style_language: str | None = None
+    error: str | None = None
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "name": self.name,
+            "kind": self.kind,
+            "driver": self.driver,
+            "crs": self.crs,
+            "extent": list(self.extent) if self.extent else None,
+            "size": list(self.size) if self.size else None,
+            "bands": self.bands,
+            "layers": [layer.to_dict() for layer in self.layers],
+            "styleText": self.style_text,
+            "styleLanguage": self.style_language,
+            "error": self.error,
+        }
+
+
+def gdal_path(path: Path) -> str:
+    """Get the name GDAL opens a file by; zipped shapefiles are read in place."""
+    if path.suffix.lower() == ".zip":
+        return f"/vsizip/{path}"
+    return str(path)
+
+
+def run_json(command: list[str], timeout: float) -> dict[str, Any]:
+    """Run a GDAL utility that prints JSON.
+
+    Raises:
+        ValueError: If the utility is missing, fails or prints something else
+    """
+    try:
+        result = subprocess.run(command, capture_output=True, text=True, timeout=timeout)
+    except FileNotFoundError as e:
+        raise ValueError(f"{command[0]} not found. Please install GDAL.") from e
+    except subprocess.TimeoutExpired as e:
+        raise ValueError(f"{command[0]} timed out") from e
+    if result.returncode != 0:
+        message = result.stderr.strip().splitlines()[-1:] or ["unknown error"]
+        raise ValueError(message[0])
+    try:
+        return json.loads(result.stdout)
+    except json.JSONDecodeError as e:
+        raise ValueError(f"Unexpected {command[0]} output; GDAL 3.7 or newer is needed") from e
+
+
+def _crs_name(crs: dict[str, Any] | None) -> str | None:
+    """Name a coordinateSystem object, e.g. "EPSG:4326"."""
+    if not crs:
+        return None
+    ident = (crs.get("projjson") or {}).get("id") or {}
+    if ident.get("authority") and ident.get("code"):
+        return f"{ident['authority']}:{ident['code']}"
+    wkt = crs.get("wkt") or ""
+    # PROJCRS["WGS 84 / UTM zone 33N", ... -> the quoted name
+    if '"' in wkt:
+        return wkt.split('"')[1]
+    return None
+
+
+def parse_gdalinfo(info: dict[str, Any], summary: FileSummary) -> None:
+    """Fill a summary from `gdalinfo -json` output."""
+    summary.driver = info.get("driverShortName")
+    summary.crs = _crs_name(info.get("coordinateSystem"))
+    epsg = (info.get("stac") or {}).get("proj:epsg")
+    if epsg and not (summary.crs or "").startswith("EPSG:"):
+        summary.crs = f"EPSG:{epsg}"
+    if info.get("size"):
+        summary.size = (info["size"][0], info["size"][1])
+
+    corners = info.get("cornerCoordinates") or {}
+    points = [
+        corners[k] for k in ("lowerLeft", "upperRight", "upperLeft", "lowerRight") if k in corners
+    ]
+    if points:
+        xs = [p[0] for p in points]
+        ys = [p[1] for p in points]
+        summary.extent = (min(xs), min(ys), max(xs), max(ys))
+
+    for band in info.get("bands") or []:
+        label = f"Band {band.get('band')}: {band.get('type', '?')}"
+        detail = band.get("description") or band.get("colorInterpretation")
+        if detail:
+            label += f" ({detail})"
+        summary.bands.append(label)
+
+
+def parse_ogrinfo(info: dict[str, Any], summary: FileSummary) -> None:
+    """Fill a summary from `ogrinfo -json -so` output."""
+    summary.driver = info.get("driverShortName")
+    extents = []
+    for layer in info.get("layers") or []:
+        geometry = (layer.get("geometryFields") or [{}])[0]
+        summary.layers.append(
+            LayerSummary(
+                name=layer.get("name", ""),
+                geometry_type=geometry.get("type"),
+                feature_count=layer.get("featureCount"),
+                fields=[(f.get("name", ""), f.get("type", "")) for f in layer.get("fields") or []],
+            )
+        )
+        if summary.crs is None:
+            summary.crs = _crs_name(geometry.get("coordinateSystem"))
+        if geometry.get("extent"):
+            extents.append(geometry["extent"])
+    if extents:
+        summary.extent = (
+            min(e[0] for e in extents),
+            min(e[1] for e in extents),
+            max(e[2] for e in extents),
+            max(e[3] for e in extents),
+        )
+
+
+def describe_file(path: Path, timeout: float = 60) -> FileSummary:
+    """Summarise a local file for the preview pane.
+
+    Problems reading the file are reported on the summary rather than
+    raised, so the rest of the preview can still be shown.
+
+    Args:
+        path: File to describe
+        timeout: Seconds to allow each GDAL utility
+
+    Returns:
+        The summary
+    """
+    suffix = path.suffix.lower()
+    kind = "style" if suffix in STYLE_LANGUAGES else classify_file(path.name)
+    summary = FileSummary(name=path.name, kind=kind)
+    try:
+        if kind == "style":
+            text = path.read_text(encoding="utf-8", errors="replace")
+            if len(text) > STYLE_PREVIEW_CHARS:
+                text = text[:STYLE_PREVIEW_CHARS] + "\n..."
+            summary.style_text = text
+            summary.style_language = STYLE_LANGUAGES[suffix]
+        elif kind == "geotiff":
+            parse_gdalinfo(run_json(["gdalinfo", "-json", str(path)], timeout), summary)
+        elif kind in ("shapefile", "geopackage"):
+            command = ["ogrinfo", "-json", "-so", "-ro", gdal_path(path)]
+            parse_ogrinfo(run_json(command, timeout), summary)
+        else:
+            summary.error = f"Unsupported file type: {path.name}"
+    except (OSError, ValueError) as e:
+        summary.error = str(e)
+    return summary
+
+
+def _run(command: list[str], timeout: float) -> None:
+    """Run a GDAL utility that writes a file.
+
+    Raises:
+        ValueError: If the utility is missing or fails
+    """
+    try:
+        result = subprocess.run(command, capture_output=True, text=True, timeout=timeout)
+    except (FileNotFoundError, subprocess.TimeoutExpired) as e:
+        raise ValueError(f"{command[0]} failed: {e}") from e
+    if result.returncode != 0:
+        message = result.stderr.strip().splitlines()[-1:] or ["unknown error"]
+        raise ValueError(message[0])
+
+
+def render_thumbnail(
+    path: Path, summary: FileSummary, width: int = 64, height: int = 32, timeout: float = 60
+) -> Image:
+    """Draw a small picture of a raster or vector file with GDAL.
+
+    Rasters are scaled down and stretched to 8 bits, using the first three
+    bands as RGB when there are that many. Vector features are burned into
+    a blank image covering their extent.
+
+    Args:
+        path: File to draw
+        summary: The file's summary, from describe_file()
+        width: Thumbnail width in pixels
+        height: Thumbnail height in pixels
+        timeout: Seconds to allow each GDAL utility
+
+    Returns:
+        The thumbnail
+
+    Raises:
+        ValueError: If the file can't be drawn
+    """
+    with tempfile.TemporaryDirectory(dir=get_cache_dir()) as tmp:
+        png = Path(tmp) / "thumbnail.png"
+        if summary.kind == "geotiff":
+            bands = ["-b", "1", "-b", "2", "-b", "3"] if len(summary.bands) >= 3 else ["-b", "1"]
+            _run(
+                [
+                    "gdal_translate",
+                    "-of", "PNG",
+                    "-ot", "Byte",
+                    "-scale",
+                    "-outsize", str(width), str(height),
+                    *bands,
+                    str(path),
+                    str(png),
+                ],
+                timeout,
+            )
+        elif summary.kind in ("shapefile", "geopackage") and summary.extent:
+            burned = Path(tmp) / "burned.tif"
+            minx, miny, maxx, maxy = summary.extent
+            _run(
+                [
+                    "gdal_rasterize",
+                    "-burn", str(VECTOR_COLOR[0]),
+                    "-burn", str(VECTOR_COLOR[1]),
+                    "-burn", str(VECTOR_COLOR[2]),
+                    "-burn", str(VECTOR_COLOR[3]),
+                    "-ot", "Byte",
+                    "-init", "0",
+                    "-te", str(minx), str(miny), str(maxx), str(maxy),
+                    "-ts", str(width), str(height),
+                    *[arg for layer in summary.layers for arg in ("-l", layer.name)],
+                    gdal_path(path),
+                    str(burned),
+                ],
+                timeout,
+            )
+            _run(["gdal_translate", "-of", "PNG", str(burned), str(png)], timeout)
+        else:
+            raise ValueError("Nothing to draw")
+        image = decode_png(png.read_bytes())
+    if (image.width, image.height) != (width, height):
+        image = image.resized(width, height)
+    return image
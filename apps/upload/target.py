@@ -0,0 +1,164 @@
+"""Choosing the store an upload goes into.
+
+An upload either creates a new store or goes into an existing one of the
+right type: data is appended to an existing data store, while a GeoTIFF
+replaces the file of an existing coverage store. Store names are unique
+within a workspace across data and coverage stores, so a new name must
+not be taken by either. The layer an upload publishes is previewed from
+the file name, as GeoServer names it.
+"""
+
+import re
+from dataclasses import dataclass
+from pathlib import PurePosixPath
+from typing import TYPE_CHECKING, Any
+
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.naming import check_name
+from apps.geoserver.publish import sanitize_name
+
+from .batch import BatchItem, classify_file
+
+if TYPE_CHECKING:
+    from apps.geoserver.client import GeoServerClient
+
+# Store type by file kind
+STORE_TYPES = {
+    "shapefile": "datastore",
+    "geopackage": "datastore",
+    "geotiff": "coveragestore",
+}
+
+# What happens to the store
+MODE_CREATE = "create"
+MODE_APPEND = "append"  # Features are added to the data store
+MODE_REPLACE = "replace"  # The coverage store's file is replaced
+
+_STORE_LABELS = {"datastore": "data store", "coveragestore": "coverage store"}
+
+# GeoServer accepts more, but these names are safe in URLs and OWS requests
+_NAME_RE = re.compile(r"^[A-Za-z_][A-Za-z0-9_.-]*$")
+
+
+@dataclass
+class UploadTarget:
+    """Where an uploaded file goes and what it publishes."""
+
+    workspace: str
+    store: str
+    kind: str | None
+    store_type: str | None
+    mode: str = MODE_CREATE
+    layer: str | None = None  # None when chosen after upload (GeoPackage tables)
+    error: str | None = None
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "workspace": self.workspace,
+            "store": self.store,
+            "kind": self.kind,
+            "storeType": self.store_type,
+            "mode": self.mode,
+            "layer": self.layer,
+            "error": self.error,
+        }
+
+
+def existing_stores(client: "GeoServerClient", workspace: str) -> dict[str, str]:
+    """Get the store type of each store in a workspace, by name."""
+    stores = {s.get("name", ""): "datastore" for s in client.list_datastores(workspace)}
+    for store in client.list_coveragestores(workspace):
+        stores[store.get("name", "")] = "coveragestore"
+    return stores
+
+
+def default_store_name(filename: str) -> str:
+    """Name a new store after a file, e.g. "Roads 2024.shp.zip" -> "roads_2024".
+
+    The name is the file's slug unless "slugify_upload_names" is turned
+    off in the config file; then it is the file name without extensions.
+    """
+    name = PurePosixPath(filename).name
+    # roads.shp.zip -> roads
+    for suffix in (".zip", ".shp", ".tiff", ".tif", ".gpkg"):
+        if name.lower().endswith(suffix):
+            name = name[: -len(suffix)]
+    return sanitize_name(name) if config_manager.config.slugify_upload_names else name
+
+
+def resolve_target(
+    filename: str,
+    workspace: str,
+    store: str | None,
+    existing: dict[str, str],
+) -> UploadTarget:
+    """Work out where a file would go and what layer it would publish.
+
+    Args:
+        filename: Name of the uploaded file
+        workspace: Target workspace
+        store: Chosen store name; a name from the file when empty
+        existing: Store type by name in the workspace, from existing_stores()
+
+    Returns:
+        The target, with an error when the file can't go there
+    """
+    kind = classify_file(filename)
+    store_type = STORE_TYPES.get(kind or "")
+    store = (store or "").strip() or default_store_name(filename)
+    target = UploadTarget(workspace, store, kind, store_type)
+
+    if store_type is None:
+        target.error = f"Unsupported file type: {filename}"
+        return target
+    if not _NAME_RE.match(store):
+        target.error = (
+            "Store names start with a letter or underscore and use only letters, "
+            "digits, underscores, dots and hyphens"
+        )
+        return target
+
+    problems = check_name(store_type, store, workspace)
+    if problems:
+        target.error = f"{store} breaks the naming conventions: {'; '.join(problems)}"
+        return target
+
+    current = existing.get(store)
+    if current and current != store_type:
+        target.error = f"{store} is already a {_STORE_LABELS[current]} in {workspace}"
+        return target
+    if current:
+        target.mode = MODE_APPEND if store_type == "datastore" else MODE_REPLACE
+
+    if kind == "shapefile":
+        # The feature type is named after the shapefile; appending into a
+        # feature type of that name adds to it
+        target.layer = PurePosixPath(filename).name.split(".")[0]
+    elif kind == "geotiff":
+        target.layer = store
+    return target
+
+
+def check_targets(client: "GeoServerClient", items: list[BatchItem]) -> dict[str, UploadTarget]:
+    """Resolve the target store of each mapped file of a batch upload.
+
+    Returns:
+        Target by file path, for files that go into stores
+    """
+    existing: dict[str, dict[str, str]] = {}
+    targets = {}
+    for item in items:
+        if item.kind not in STORE_TYPES:
+            continue
+        if item.workspace not in existing:
+            try:
+                existing[item.workspace] = existing_stores(client, item.workspace)
+            except GeoServerError:
+                # The workspace is created by the batch
+                existing[item.workspace] = {}
+        targets[item.path] = resolve_target(
+            item.path, item.workspace, item.store, existing[item.workspace]
+        )
+    return targets
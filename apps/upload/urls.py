@@ -8,6 +8,9 @@ urlpatterns = [
     # Chunked upload endpoints
     path("upload/init", views.UploadInitView.as_view(), name="upload-init"),
     path("upload/chunk", views.UploadChunkView.as_view(), name="upload-chunk"),
+    path("upload/target", views.UploadTargetView.as_view(), name="upload-target"),
+    path("upload/preview", views.UploadPreviewView.as_view(), name="upload-preview"),
+    path("upload/inspect", views.UploadInspectView.as_view(), name="upload-inspect"),
     path("upload/complete", views.UploadCompleteView.as_view(), name="upload-complete"),
     path(
         "upload/session/<str:session_id>/progress",
@@ -19,6 +22,10 @@ urlpatterns = [
         views.UploadCancelView.as_view(),
         name="upload-cancel",
     ),
+    # Directory batch upload endpoints
+    path("upload/batch/plan", views.BatchPlanView.as_view(), name="upload-batch-plan"),
+    path("upload/batch/prepare", views.BatchPrepareView.as_view(), name="upload-batch-prepare"),
+    path("upload/batch/item", views.BatchUploadItemView.as_view(), name="upload-batch-item"),
     # Simple upload endpoint
     path("upload", views.SimpleUploadView.as_view(), name="upload-simple"),
 ]
@@ -0,0 +1,191 @@
+"""Checking uploaded files arrived intact.
+
+The SHA-256 of what was sent is compared with the files GeoServer stored,
+read back through the resource API. GeoServer doesn't publish checksums,
+so the size from a HEAD request is checked first and a file is only
+downloaded when its size matches. Zipped shapefiles are unpacked on the
+server, so each file of the zip is checked on its own.
+
+Only uploads that create a store or replace a coverage store's file can
+be checked: appended data is merged into existing files.
+"""
+
+import io
+import zipfile
+from dataclasses import dataclass, field
+from pathlib import PurePosixPath
+from typing import TYPE_CHECKING, Any
+
+from apps.core.checksum import sha256_bytes, sha256_chunks
+from apps.core.exceptions import GeoServerError
+
+if TYPE_CHECKING:
+    from apps.geoserver.client import GeoServerClient
+
+# Per-file outcomes
+STATUS_MATCH = "match"
+STATUS_MISMATCH = "mismatch"
+STATUS_MISSING = "missing"
+
+# Store location prefix for files in the data directory
+_DATA_DIR_PREFIX = "file:"
+
+
+@dataclass
+class FileCheck:
+    """The outcome of checking one stored file."""
+
+    name: str
+    expected: str
+    size: int
+    actual: str | None = None
+    status: str = STATUS_MISSING
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "name": self.name,
+            "expected": self.expected,
+            "actual": self.actual,
+            "status": self.status,
+        }
+
+
+@dataclass
+class UploadVerification:
+    """Whether an upload's files match what GeoServer stored."""
+
+    files: list[FileCheck] = field(default_factory=list)
+    error: str | None = None  # Why the files couldn't be checked
+
+    @property
+    def verified(self) -> bool:
+        return not self.error and bool(self.files) and all(
+            f.status == STATUS_MATCH for f in self.files
+        )
+
+    def to_dict(self) -> dict[str, Any]:
+        """Serialize for API responses."""
+        return {
+            "verified": self.verified,
+            "files": [f.to_dict() for f in self.files],
+            "error": self.error,
+        }
+
+
+def expected_files(filename: str, data: bytes) -> list[FileCheck]:
+    """List the files an upload stores, with their SHA-256 and size.
+
+    Zipped shapefiles are unpacked by GeoServer, so their members are
+    listed rather than the zip.
+
+    Raises:
+        zipfile.BadZipFile: If a zip file can't be read
+    """
+    if not filename.lower().endswith(".zip"):
+        return [FileCheck(PurePosixPath(filename).name, sha256_bytes(data), len(data))]
+    files = []
+    with zipfile.ZipFile(io.BytesIO(data)) as archive:
+        for info in archive.infolist():
+            if not info.is_dir():
+                member = archive.read(info)
+                files.append(
+                    FileCheck(PurePosixPath(info.filename).name, sha256_bytes(member), len(member))
+                )
+    return sorted(files, key=lambda f: f.name)
+
+
+def _resource_path(location: str) -> str | None:
+    """Get the resource API path of a store location, e.g. file:data/topp/roads."""
+    if not location.startswith(_DATA_DIR_PREFIX):
+        return None
+    path = location[len(_DATA_DIR_PREFIX):]
+    # Absolute paths may lie outside the data directory
+    if path.startswith("/") or ".." in PurePosixPath(path).parts:
+        return None
+    return path.rstrip("/")
+
+
+def store_location(
+    client: "GeoServerClient", workspace: str, store: str, store_type: str
+) -> str | None:
+    """Get where an uploaded store keeps its file, relative to the data directory.
+
+    Returns:
+        The data directory path of the store's file or directory, or None
+        when the store doesn't point into the data directory
+    """
+    if store_type == "coveragestore":
+        location = client.get_coveragestore(workspace, store).get("url", "")
+    else:
+        entries = (
+            client.get_datastore(workspace, store).get("connectionParameters", {}).get("entry", [])
+        )
+        if isinstance(entries, dict):
+            entries = [entries]
+        params = {e.get("@key"): e.get("$", "") for e in entries if isinstance(e, dict)}
+        location = params.get("url") or params.get("database") or ""
+    return _resource_path(location)
+
+
+def verify_upload(
+    client: "GeoServerClient",
+    workspace: str,
+    store: str,
+    store_type: str,
+    filename: str,
+    data: bytes,
+) -> UploadVerification:
+    """Compare an upload with the files GeoServer stored for it.
+
+    Args:
+        client: GeoServer client
+        workspace: Workspace of the store
+        store: Store the file was uploaded into
+        store_type: "datastore" or "coveragestore"
+        filename: Name of the uploaded file
+        data: The bytes that were sent
+
+    Returns:
+        Per-file outcomes, or an error when the store can't be checked
+    """
+    verification = UploadVerification()
+    try:
+        verification.files = expected_files(filename, data)
+        location = store_location(client, workspace, store, store_type)
+    except zipfile.BadZipFile:
+        verification.error = f"{filename} is not a valid zip file"
+        return verification
+    except GeoServerError as e:
+        verification.error = e.message
+        return verification
+    if location is None:
+        verification.error = "The store's files are outside the data directory"
+        return verification
+
+    # A store points at its file, or at the directory of an unpacked shapefile
+    folder = location
+    stored = PurePosixPath(location)
+    if stored.suffix:
+        folder = str(stored.parent)
+        if not filename.lower().endswith(".zip"):
+            # GeoServer names the file it stores after the store (dem.tif -> dem.geotiff)
+            verification.files[0].name = stored.name
+
+    for check in verification.files:
+        path = f"{folder}/{check.name}"
+        try:
+            info = client.get_resource_info(path)
+            if info["type"] == "undefined":
+                continue
+            if info["size"] and info["size"] != check.size:
+                check.status = STATUS_MISMATCH
+                continue
+            check.actual = sha256_chunks(client.iter_resource(path))
+        except GeoServerError as e:
+            if e.status_code == 404:
+                continue
+            verification.error = e.message
+            return verification
+        check.status = STATUS_MATCH if check.actual == check.expected else STATUS_MISMATCH
+    return verification
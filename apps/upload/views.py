@@ -3,19 +3,25 @@
 Provides endpoints for:
 - Initializing upload sessions
 - Uploading file chunks
+- Choosing and checking the target store
+- Advising on COG conversion of GeoTIFFs
 - Completing uploads
 - Tracking progress
 - Canceling uploads
 """
 
+import base64
 import hashlib
+import json
 import os
 import shutil
+import tempfile
 import threading
 import uuid
 from dataclasses import dataclass, field
 from datetime import datetime
 from pathlib import Path
+from typing import Any
 
 from django.conf import settings
 from rest_framework import status
@@ -23,9 +29,26 @@ from rest_framework.parsers import FormParser, MultiPartParser
 from rest_framework.response import Response
 from rest_framework.views import APIView
 
+from apps.core import hooks
 from apps.core.config import get_cache_dir
-from apps.core.exceptions import UploadError
-from apps.geoserver.client import get_geoserver_client
+from apps.core.exceptions import GeoServerError, NamingError, UploadError
+from apps.core.raster import encode_png
+from apps.geoserver.client import GeoServerClient, get_geoserver_client
+from apps.geoserver.importer import needs_importer
+from apps.geoserver.naming import validate_name
+from apps.gwc.invalidation import invalidate_layer_cache, invalidate_store_cache
+
+from . import cog, preview
+from .batch import BatchItem, classify_file, ensure_workspaces, propose_mapping, publish_item
+from .metadata import LayerMetadata, apply_metadata, suggest_metadata
+from .target import (
+    MODE_APPEND,
+    MODE_CREATE,
+    default_store_name,
+    existing_stores,
+    resolve_target,
+)
+from .verify import UploadVerification, verify_upload
 
 
 @dataclass
@@ -45,6 +68,8 @@ class UploadSession:
     created_at: datetime = field(default_factory=datetime.now)
     completed: bool = False
     error: str = ""
+    publish_done: int = 0  # Importer tasks finished
+    publish_total: int = 0  # Importer tasks created
 
     @property
     def progress(self) -> float:
@@ -151,8 +176,12 @@ class UploadSessionManager:
                 missing = set(range(session.total_chunks)) - session.received_chunks
                 raise UploadError(f"Missing chunks: {missing}", session_id)
 
-            # Assemble file
+            # Already assembled for inspection
             final_path = session.temp_dir / session.filename
+            if session.completed and final_path.exists():
+                return final_path
+
+            # Assemble file
             with open(final_path, "wb") as outfile:
                 for i in range(session.total_chunks):
                     chunk_path = session.temp_dir / f"chunk_{i:06d}"
@@ -167,6 +196,38 @@ class UploadSessionManager:
 session_manager = UploadSessionManager()
 
 
+def import_archive(
+    client: GeoServerClient,
+    connection_id: str,
+    workspace: str,
+    filename: str,
+    data: bytes,
+    session: UploadSession | None = None,
+) -> dict | None:
+    """Publish a multi-dataset archive through the Importer extension.
+
+    Returns:
+        The upload result fields, or None if the file doesn't need the
+        importer or the server doesn't have it
+    """
+    if not needs_importer(filename, data) or not client.importer.is_available():
+        return None
+
+    def on_progress(done: int, total: int) -> None:
+        if session:
+            session.publish_done, session.publish_total = done, total
+
+    outcome = client.importer.import_file(workspace, filename, data, on_progress=on_progress)
+    result = {"storeType": "import", **outcome.to_dict()}
+
+    truncated = []
+    for layer in outcome.layers:
+        truncated += invalidate_layer_cache(connection_id, workspace, layer)
+    if truncated:
+        result["truncatedLayers"] = truncated
+    return result
+
+
 class UploadInitView(APIView):
     """Initialize a chunked upload session."""
 
@@ -286,6 +347,161 @@ class UploadChunkView(APIView):
             return Response({"error": str(e)}, status=status.HTTP_400_BAD_REQUEST)
 
 
+def _is_geotiff(filename: str) -> bool:
+    return filename.lower().endswith((".tif", ".tiff"))
+
+
+def _set_layer_metadata(
+    client: GeoServerClient,
+    workspace: str,
+    store: str,
+    filename: str,
+    file_path: Path | None,
+    requested: Any,
+) -> dict[str, Any]:
+    """Set the metadata of the layer a new store published.
+
+    Fields the request left empty are suggested from the file, reading
+    its CRS, extent and feature count when it is on disk.
+
+    Returns:
+        Result entries: the metadata set, and a warning if it couldn't be
+    """
+    kind = classify_file(filename)
+    metadata = LayerMetadata.from_dict(requested if isinstance(requested, dict) else None)
+    if not (metadata.title and metadata.abstract and metadata.keywords):
+        summary = preview.describe_file(file_path) if file_path else None
+        metadata = metadata.merged(suggest_metadata(filename, kind, summary))
+    store_type = "coveragestore" if kind == "geotiff" else "datastore"
+    try:
+        apply_metadata(client, workspace, store, store_type, metadata)
+    except GeoServerError as e:
+        return {"metadataWarning": f"Layer metadata was not set: {e.message}"}
+    return {"metadata": metadata.to_dict()}
+
+
+class UploadTargetView(APIView):
+    """Check the store an upload would go into."""
+
+    def post(self, request):
+        """Preview the store and layer of an upload.
+
+        Expected body:
+        {
+            "connectionId": "conn_123",
+            "workspace": "topp",
+            "filename": "roads.zip",
+            "storeName": "roads"  # Optional, named after the file when empty
+        }
+
+        Returns the target with its mode (create, append or replace), the
+        layer it publishes and an error when the store can't be used.
+        """
+        connection_id = request.data.get("connectionId")
+        workspace = request.data.get("workspace")
+        filename = request.data.get("filename")
+        if not connection_id or not workspace or not filename:
+            return Response(
+                {"error": "connectionId, workspace and filename are required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        try:
+            client = get_geoserver_client(connection_id)
+            existing = existing_stores(client, workspace)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return Response({"error": e.message}, status=e.status_code or 502)
+
+        target = resolve_target(filename, workspace, request.data.get("storeName"), existing)
+        return Response(target.to_dict())
+
+
+class UploadPreviewView(APIView):
+    """Describe a local file before it's uploaded."""
+
+    parser_classes = [MultiPartParser, FormParser]
+
+    def post(self, request):
+        """Summarise a file and draw a thumbnail of it.
+
+        Expected form data:
+        - file: The file to preview
+        - path: Optional path of the file in the folder it was picked from
+
+        Returns the file summary, the suggested title, abstract and keywords
+        of the layer it would publish and, where GDAL can draw the file, a
+        PNG thumbnail as a data URL.
+        """
+        uploaded_file = request.FILES.get("file")
+        if not uploaded_file:
+            return Response(
+                {"error": "file is required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+        if uploaded_file.size > preview.PREVIEW_MAX_BYTES:
+            return Response(
+                {"error": "File is too large to preview"},
+                status=status.HTTP_413_REQUEST_ENTITY_TOO_LARGE,
+            )
+
+        with tempfile.TemporaryDirectory(dir=get_cache_dir()) as tmp:
+            path = Path(tmp) / Path(uploaded_file.name).name
+            with open(path, "wb") as f:
+                for chunk in uploaded_file.chunks():
+                    f.write(chunk)
+            summary = preview.describe_file(path)
+            metadata = suggest_metadata(
+                request.data.get("path") or uploaded_file.name, summary.kind, summary
+            )
+            thumbnail = None
+            if not summary.error and summary.kind != "style" and cog.gdal_available():
+                try:
+                    png = encode_png(preview.render_thumbnail(path, summary, 256, 256))
+                    thumbnail = "data:image/png;base64," + base64.b64encode(png).decode("ascii")
+                except ValueError:
+                    # The summary is still worth showing
+                    pass
+        return Response(
+            {
+                "summary": summary.to_dict(),
+                "thumbnail": thumbnail,
+                "metadata": None if summary.kind == "style" else metadata.to_dict(),
+            }
+        )
+
+
+class UploadInspectView(APIView):
+    """Advise on converting an uploaded GeoTIFF to a COG before publishing."""
+
+    def post(self, request):
+        """Inspect an uploaded file whose chunks have all arrived.
+
+        Expected body:
+        {
+            "sessionId": "uuid"
+        }
+
+        Returns the COG advice for GeoTIFFs, null for other files.
+        """
+        session_id = request.data.get("sessionId")
+        session = session_manager.get_session(session_id) if session_id else None
+        if not session:
+            return Response(
+                {"error": "Upload session not found"},
+                status=status.HTTP_404_NOT_FOUND,
+            )
+        if not _is_geotiff(session.filename):
+            return Response({"sessionId": session_id, "cog": None})
+
+        try:
+            file_path = session_manager.assemble_file(session_id)
+        except UploadError as e:
+            return Response({"error": e.message}, status=status.HTTP_400_BAD_REQUEST)
+        return Response({"sessionId": session_id, "cog": cog.advise(file_path).to_dict()})
+
+
 class UploadCompleteView(APIView):
     """Complete a chunked upload and publish to GeoServer."""
 
@@ -296,7 +512,11 @@ class UploadCompleteView(APIView):
         {
             "sessionId": "uuid",
             "publish": true,
-            "storeName": "my_store"
+            "storeName": "my_store",
+            "cog": true,  # GeoTIFFs only; omit to convert when over the threshold
+            "verify": true,  # Compare checksums with the files GeoServer stored
+            # Of the layer a new store publishes; empty fields are suggested
+            "metadata": {"title": "Roads", "abstract": "...", "keywords": ["roads"]}
         }
         """
         session_id = request.data.get("sessionId")
@@ -334,9 +554,18 @@ class UploadCompleteView(APIView):
                 "path": str(file_path),
             }
 
+            if _is_geotiff(session.filename):
+                convert = request.data.get("cog")
+                if convert is None:
+                    convert = cog.advise(file_path).auto_convert
+                if convert:
+                    file_path = cog.convert_to_cog(file_path, file_path.with_suffix(".cog.tif"))
+                    result["convertedToCog"] = True
+
             # Publish to GeoServer if requested
             if publish and session.connection_id and session.workspace:
-                final_store_name = store_name or session.store_name or Path(session.filename).stem
+                final_store_name = store_name or session.store_name or ""
+                update = None
 
                 client = get_geoserver_client(session.connection_id)
 
@@ -344,24 +573,107 @@ class UploadCompleteView(APIView):
                 with open(file_path, "rb") as f:
                     data = f.read()
 
+                imported = import_archive(
+                    client,
+                    session.connection_id,
+                    session.workspace,
+                    session.filename,
+                    data,
+                    session,
+                )
+
+                # The store may exist already; data stores are appended to
+                if not imported:
+                    target = resolve_target(
+                        session.filename,
+                        session.workspace,
+                        final_store_name,
+                        existing_stores(client, session.workspace),
+                    )
+                    # Unsupported files get a warning below
+                    if target.error and target.store_type:
+                        return Response(
+                            {"error": target.error}, status=status.HTTP_400_BAD_REQUEST
+                        )
+                    final_store_name = target.store
+                    if target.mode == MODE_APPEND:
+                        update = "append"
+                    result["mode"] = target.mode
+
                 # Determine file type and upload
                 filename_lower = session.filename.lower()
-                if filename_lower.endswith(".zip") or filename_lower.endswith(".shp"):
-                    client.upload_shapefile(session.workspace, final_store_name, data)
+                if imported:
+                    result.update(imported)
+                elif filename_lower.endswith(".zip") or filename_lower.endswith(".shp"):
+                    client.upload_shapefile(
+                        session.workspace, final_store_name, data, update=update
+                    )
                     result["storeType"] = "shapefile"
                 elif filename_lower.endswith(".tif") or filename_lower.endswith(".tiff"):
                     client.upload_geotiff(session.workspace, final_store_name, data)
                     result["storeType"] = "geotiff"
                 elif filename_lower.endswith(".gpkg"):
-                    client.upload_geopackage(session.workspace, final_store_name, data)
+                    client.upload_geopackage(
+                        session.workspace, final_store_name, data, configure="none", update=update
+                    )
                     result["storeType"] = "geopackage"
                 else:
                     result["warning"] = f"Unknown file type: {session.filename}"
 
-                result["storeName"] = final_store_name
+                if "storeType" in result and not imported:
+                    truncated = invalidate_store_cache(
+                        session.connection_id,
+                        session.workspace,
+                        final_store_name,
+                        "coveragestore" if result["storeType"] == "geotiff" else "datastore",
+                    )
+                    if truncated:
+                        result["truncatedLayers"] = truncated
+
+                if result.get("storeType") in ("shapefile", "geotiff") and (
+                    result.get("mode") == MODE_CREATE
+                ):
+                    result.update(
+                        _set_layer_metadata(
+                            client,
+                            session.workspace,
+                            final_store_name,
+                            session.filename,
+                            file_path,
+                            request.data.get("metadata"),
+                        )
+                    )
+
+                if request.data.get("verify") and "storeType" in result and not imported:
+                    if update:
+                        verification = UploadVerification(
+                            error="Appended data is merged into the store's files"
+                        )
+                    else:
+                        verification = verify_upload(
+                            client,
+                            session.workspace,
+                            final_store_name,
+                            "coveragestore" if result["storeType"] == "geotiff" else "datastore",
+                            session.filename,
+                            data,
+                        )
+                    result["verification"] = verification.to_dict()
+
+                if not imported:
+                    result["storeName"] = final_store_name
                 result["workspace"] = session.workspace
                 result["published"] = True
+                hooks.emit(
+                    "upload.completed",
+                    client.connection,
+                    session.workspace,
+                    result.get("storeName", ""),
+                    filename=session.filename,
+                    store_type=result.get("storeType", ""),
+                )
 
+            result["success"] = True
             return Response(result)
 
         except Exception as e:
@@ -397,6 +709,11 @@ class UploadProgressView(APIView):
                 "progress": session.progress,
                 "completed": session.completed,
                 "error": session.error,
+                # Publishing progress, as polled while completing
+                "sent": session.publish_done,
+                "total": session.publish_total,
+                "done": bool(session.publish_total)
+                and session.publish_done >= session.publish_total,
             }
         )
 
@@ -430,6 +747,7 @@ class SimpleUploadView(APIView):
         - workspace: Target workspace
         - connectionId: GeoServer connection ID
         - storeName: Optional store name (defaults to filename)
+        - metadata: Optional JSON title, abstract and keywords of the layer
         """
         uploaded_file = request.FILES.get("file")
         workspace = request.data.get("workspace")
@@ -449,7 +767,7 @@ class SimpleUploadView(APIView):
             )
 
         filename = uploaded_file.name
-        final_store_name = store_name or Path(filename).stem
+        final_store_name = store_name or default_store_name(filename)
 
         try:
             client = get_geoserver_client(connection_id)
@@ -462,8 +780,23 @@ class SimpleUploadView(APIView):
                 "storeName": final_store_name,
             }
 
+            imported = import_archive(client, connection_id, workspace, filename, data)
+            if imported:
+                del result["storeName"]
+                result.update(imported)
+                result["published"] = True
+                hooks.emit(
+                    "upload.completed", client.connection, workspace, "", filename=filename
+                )
+                return Response(result, status=status.HTTP_201_CREATED)
+
             # Determine file type and upload
             filename_lower = filename.lower()
+            validate_name(
+                "coveragestore" if _is_geotiff(filename) else "datastore",
+                final_store_name,
+                workspace,
+            )
             if filename_lower.endswith(".zip") or filename_lower.endswith(".shp"):
                 client.upload_shapefile(workspace, final_store_name, data)
                 result["storeType"] = "shapefile"
@@ -471,7 +804,7 @@ class SimpleUploadView(APIView):
                 client.upload_geotiff(workspace, final_store_name, data)
                 result["storeType"] = "geotiff"
             elif filename_lower.endswith(".gpkg"):
-                client.upload_geopackage(workspace, final_store_name, data)
+                client.upload_geopackage(workspace, final_store_name, data, configure="none")
                 result["storeType"] = "geopackage"
             else:
                 return Response(
@@ -479,11 +812,145 @@ class SimpleUploadView(APIView):
                     status=status.HTTP_400_BAD_REQUEST,
                 )
 
+            truncated = invalidate_store_cache(
+                connection_id,
+                workspace,
+                final_store_name,
+                "coveragestore" if result["storeType"] == "geotiff" else "datastore",
+            )
+            if truncated:
+                result["truncatedLayers"] = truncated
+
+            if result["storeType"] in ("shapefile", "geotiff"):
+                try:
+                    requested = json.loads(request.data.get("metadata") or "{}")
+                except json.JSONDecodeError:
+                    requested = None
+                result.update(
+                    _set_layer_metadata(
+                        client, workspace, final_store_name, filename, None, requested
+                    )
+                )
+
             result["published"] = True
+            hooks.emit(
+                "upload.completed",
+                client.connection,
+                workspace,
+                final_store_name,
+                filename=filename,
+                store_type=result["storeType"],
+            )
             return Response(result, status=status.HTTP_201_CREATED)
 
+        except NamingError as e:
+            return Response({"error": e.message}, status=status.HTTP_400_BAD_REQUEST)
         except Exception as e:
             return Response(
                 {"error": f"Upload failed: {str(e)}"},
                 status=status.HTTP_500_INTERNAL_SERVER_ERROR,
             )
+
+
+class BatchPlanView(APIView):
+    """Propose a mapping for uploading a directory."""
+
+    def post(self, request):
+        """Classify files and derive workspace, store and layer names.
+
+        Expected body:
+        {
+            "paths": ["roads/roads.zip", "dem.tif", "styles/roads.sld"],
+            "workspace": "topp",
+            "folderWorkspaces": false
+        }
+        """
+        paths = request.data.get("paths") or []
+        workspace = request.data.get("workspace", "")
+        folder_workspaces = bool(request.data.get("folderWorkspaces", False))
+
+        if not isinstance(paths, list) or not workspace:
+            return Response(
+                {"error": "paths and workspace are required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        items = propose_mapping([str(p) for p in paths], workspace, folder_workspaces)
+        skipped = len(paths) - len(items)
+        return Response({"items": [item.to_dict() for item in items], "skipped": skipped})
+
+
+class BatchPrepareView(APIView):
+    """Create the workspaces a batch upload targets."""
+
+    def post(self, request):
+        """Create missing workspaces before the files are uploaded.
+
+        Expected body:
+        {
+            "connectionId": "conn_123",
+            "workspaces": ["topp", "roads"]
+        }
+        """
+        connection_id = request.data.get("connectionId")
+        workspaces = request.data.get("workspaces") or []
+        if not connection_id:
+            return Response(
+                {"error": "connectionId is required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        try:
+            client = get_geoserver_client(connection_id)
+            items = [BatchItem("", "", str(ws)) for ws in workspaces if ws]
+            created = ensure_workspaces(client, items)
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return Response({"error": e.message}, status=e.status_code or 502)
+
+        return Response({"created": created})
+
+
+class BatchUploadItemView(APIView):
+    """Publish one file of a batch upload according to its mapping."""
+
+    parser_classes = [MultiPartParser, FormParser]
+
+    def post(self, request):
+        """Upload and publish a mapped file.
+
+        Expected form data:
+        - file: The file to upload
+        - connectionId: GeoServer connection ID
+        - item: JSON mapping {path, kind, workspace, store, layer}
+        """
+        uploaded_file = request.FILES.get("file")
+        connection_id = request.data.get("connectionId")
+
+        if not uploaded_file or not connection_id:
+            return Response(
+                {"error": "file and connectionId are required"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        try:
+            item = BatchItem.from_dict(json.loads(request.data.get("item") or "{}"))
+        except (ValueError, TypeError, AttributeError) as e:
+            return Response({"error": f"Invalid mapping: {e}"}, status=status.HTTP_400_BAD_REQUEST)
+
+        try:
+            client = get_geoserver_client(connection_id)
+            result = publish_item(client, connection_id, item, uploaded_file.read())
+        except ValueError as e:
+            return Response({"error": str(e)}, status=status.HTTP_404_NOT_FOUND)
+        except GeoServerError as e:
+            return Response({"error": e.message}, status=e.status_code or 502)
+        except UnicodeDecodeError:
+            return Response(
+                {"error": f"{item.path}: style is not UTF-8 text"},
+                status=status.HTTP_400_BAD_REQUEST,
+            )
+
+        result["status"] = "success"
+        return Response(result, status=status.HTTP_201_CREATED)
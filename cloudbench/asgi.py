@@ -13,3 +13,8 @@ from django.core.asgi import get_asgi_application
 os.environ.setdefault("DJANGO_SETTINGS_MODULE", "cloudbench.settings.production")
 
 application = get_asgi_application()
+
+# Run scheduled tasks in the serving process, never in management commands
+from apps.core.schedulers import start_schedulers  # noqa: E402
+
+start_schedulers()
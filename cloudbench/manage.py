@@ -15,7 +15,14 @@ def main():
             "available on your PYTHONPATH environment variable? Did you "
             "forget to activate a virtual environment?"
         ) from exc
-    execute_from_command_line(sys.argv)
+
+    from apps.core.config import apply_config_args
+
+    try:
+        argv = apply_config_args(sys.argv)
+    except ValueError as exc:
+        sys.exit(str(exc))
+    execute_from_command_line(argv)
 
 
 if __name__ == "__main__":
@@ -66,6 +66,7 @@ MIDDLEWARE = [
     "django.contrib.messages.middleware.MessageMiddleware",
     "django.middleware.clickjacking.XFrameOptionsMiddleware",
     "apps.core.middleware.COOPCOEPMiddleware",
+    "apps.core.headless.HeadlessMiddleware",
 ]
 
 ROOT_URLCONF = "cloudbench.urls"
@@ -160,7 +161,7 @@ REST_FRAMEWORK = {
         "rest_framework.permissions.AllowAny",
     ],
     "DEFAULT_PAGINATION_CLASS": None,
-    "EXCEPTION_HANDLER": "apps.core.exceptions.custom_exception_handler",
+    "EXCEPTION_HANDLER": "apps.core.exception_handlers.custom_exception_handler",
 }
 
 # Session settings for Web UI auth
@@ -195,6 +196,11 @@ CLOUDBENCH_CONFIG_DIR = os.environ.get(
     os.path.expanduser("~/.config/kartoza-cloudbench"),
 )
 
+# Headless daemon mode: API only, every request needs the bearer token
+# (set by `cloudbench serve --headless`, or by these variables under another server)
+CLOUDBENCH_HEADLESS = os.environ.get("CLOUDBENCH_HEADLESS", "").lower() in ("true", "1", "yes")
+CLOUDBENCH_API_TOKEN = os.environ.get("CLOUDBENCH_API_TOKEN", "")
+
 # Encryption settings for credential storage
 # Generate with: python -c "from cryptography.fernet import Fernet; print(Fernet.generate_key().decode())"
 CLOUDBENCH_ENCRYPTION_KEY = os.environ.get("CLOUDBENCH_ENCRYPTION_KEY", "")
@@ -209,6 +215,16 @@ CLOUDBENCH_CACHE_DIR = os.environ.get(
     os.path.expanduser("~/.cache/kartoza-cloudbench"),
 )
 
+# Background scheduler for scheduled GWC seed/truncate tasks
+CLOUDBENCH_CACHE_SCHEDULER_ENABLED = os.environ.get(
+    "CLOUDBENCH_CACHE_SCHEDULER_ENABLED", "True"
+).lower() in ("true", "1", "yes")
+
+# Background scheduler for scheduled catalog snapshots
+CLOUDBENCH_SNAPSHOT_SCHEDULER_ENABLED = os.environ.get(
+    "CLOUDBENCH_SNAPSHOT_SCHEDULER_ENABLED", "True"
+).lower() in ("true", "1", "yes")
+
 # Chunked upload settings
 UPLOAD_CHUNK_SIZE = 5 * 1024 * 1024  # 5MB chunks
 UPLOAD_TEMP_DIR = os.path.join(CLOUDBENCH_CACHE_DIR, "uploads")
@@ -40,6 +40,10 @@ CLOUDBENCH_CONFIG_DIR = _test_dir
 CLOUDBENCH_DATA_DIR = _test_dir
 CLOUDBENCH_CACHE_DIR = _test_dir
 
+# Scheduled cache tasks and snapshots are triggered explicitly in tests
+CLOUDBENCH_CACHE_SCHEDULER_ENABLED = False
+CLOUDBENCH_SNAPSHOT_SCHEDULER_ENABLED = False
+
 # Disable logging during tests (can be overridden)
 LOGGING = {
     "version": 1,
@@ -13,3 +13,8 @@ from django.core.wsgi import get_wsgi_application
 os.environ.setdefault("DJANGO_SETTINGS_MODULE", "cloudbench.settings.production")
 
 application = get_wsgi_application()
+
+# Run scheduled tasks in the serving process, never in management commands
+from apps.core.schedulers import start_schedulers  # noqa: E402
+
+start_schedulers()
@@ -64,6 +64,7 @@
             pydantic
             uvicorn
             gunicorn
+            keyring
           ];
 
           postInstall = ''
@@ -96,6 +97,7 @@
             click
             httpx
             pydantic
+            keyring
           ];
 
           meta = with final.lib; {
@@ -220,6 +222,7 @@
             pydantic
             uvicorn
             gunicorn
+            keyring
             # TUI
             textual
             rich
@@ -0,0 +1,45 @@
+"""Kartoza GeoServer client SDK.
+
+The GeoServer and GeoWebCache REST clients CloudBench is built on, for
+use in other Python projects without Django, the web UI or the TUI:
+
+    from kartoza_geoserver import connect
+
+    client = connect("https://maps.example.com/geoserver", "admin", "geoserver")
+    for workspace in client.list_workspaces():
+        print(workspace["name"])
+
+Everything in __all__ is the public API and follows semantic versioning
+with the kartoza-cloudbench package: it only changes incompatibly in a
+new major version. Anything imported from apps.* directly may change in
+any release.
+"""
+
+from apps.core.config import Connection
+from apps.core.exceptions import (
+    GeoServerError,
+    ReadOnlyConnectionError,
+    ResponseTooLargeError,
+    TokenError,
+)
+from apps.geoserver.capabilities import ServerCapabilities
+from apps.geoserver.client import GeoServerClient
+from apps.gwc.client import GWCClient
+from cloudbench import __version__
+
+from .connect import connect, connect_gwc, connection
+
+__all__ = [
+    "Connection",
+    "GWCClient",
+    "GeoServerClient",
+    "GeoServerError",
+    "ReadOnlyConnectionError",
+    "ResponseTooLargeError",
+    "ServerCapabilities",
+    "TokenError",
+    "__version__",
+    "connect",
+    "connect_gwc",
+    "connection",
+]
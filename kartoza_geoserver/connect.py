@@ -0,0 +1,54 @@
+"""Building clients from a URL, credentials and keyword options."""
+
+import uuid
+from typing import Any
+
+from apps.core.config import Connection
+from apps.geoserver.client import GeoServerClient
+from apps.gwc.client import GWCClient
+
+
+def connection(url: str, username: str = "", password: str = "", **options: Any) -> Connection:
+    """Describe a GeoServer to connect to.
+
+    Args:
+        url: GeoServer URL, e.g. https://maps.example.com/geoserver
+        username: User for basic auth
+        password: Password for basic auth
+        **options: Any other Connection field, e.g. read_only=True,
+            response_timeout=60 or max_concurrent_requests=4
+
+    Raises:
+        TypeError: If an option isn't a Connection field
+    """
+    unknown = sorted(set(options) - set(Connection.model_fields))
+    if unknown:
+        raise TypeError(f"Unknown connection options: {', '.join(unknown)}")
+    # Each connection gets its own ID, since clients are cached by it
+    options.setdefault("id", f"sdk_{uuid.uuid4().hex[:12]}")
+    options.setdefault("name", url)
+    return Connection(url=url.rstrip("/"), username=username, password=password, **options)
+
+
+def connect(url: str, username: str = "", password: str = "", **options: Any) -> GeoServerClient:
+    """Create a GeoServer REST client.
+
+    Args:
+        url: GeoServer URL, e.g. https://maps.example.com/geoserver
+        username: User for basic auth
+        password: Password for basic auth
+        **options: Any other Connection field, e.g. read_only=True,
+            response_timeout=60 or max_concurrent_requests=4
+
+    Raises:
+        TypeError: If an option isn't a Connection field
+    """
+    return GeoServerClient(connection(url, username, password, **options))
+
+
+def connect_gwc(url: str, username: str = "", password: str = "", **options: Any) -> GWCClient:
+    """Create a GeoWebCache REST client for the tile cache built into GeoServer.
+
+    Takes the same arguments as connect().
+    """
+    return GWCClient(connection(url, username, password, **options))
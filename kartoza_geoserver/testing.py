@@ -0,0 +1,13 @@
+"""A fake GeoServer for testing code built on the SDK.
+
+    from kartoza_geoserver import connect
+    from kartoza_geoserver.testing import FakeGeoServer
+
+    with FakeGeoServer() as server:
+        client = connect(server.url, "admin", "geoserver")
+        assert [ws["name"] for ws in client.list_workspaces()] == ["nurc", "topp", "empty"]
+"""
+
+from apps.geoserver.testing import DEFAULT_URL, FakeGeoServer, FakeResponse
+
+__all__ = ["DEFAULT_URL", "FakeGeoServer", "FakeResponse"]
@@ -9,7 +9,7 @@ This module provides comprehensive fixtures for:
 
 import os
 import tempfile
-from collections.abc import Generator
+from collections.abc import Callable, Generator
 from typing import Any
 from unittest.mock import MagicMock, patch
 
@@ -235,21 +235,62 @@ def sample_mergin_connection() -> Any:
 # Mock External Services
 # ============================================================================
 
+# Connection the clients of make_geoserver_client and make_gwc_client use
+TEST_CONNECTION = {
+    "id": "test-conn-001",
+    "name": "Test GeoServer",
+    "url": "http://localhost:8080/geoserver",
+    "username": "admin",
+    "password": "geoserver",
+}
+
 
 @pytest.fixture
-def mock_geoserver_client() -> Generator[MagicMock, None, None]:
-    """Mock GeoServer client for testing without a real server."""
-    with patch("apps.geoserver.client.GeoServerClient") as mock:
-        client = MagicMock()
-        client.get_workspaces.return_value = [
-            {"name": "test_workspace", "href": "http://localhost:8080/geoserver/rest/workspaces/test_workspace"}
-        ]
-        client.get_layers.return_value = [
-            {"name": "test_layer", "href": "http://localhost:8080/geoserver/rest/layers/test_layer"}
-        ]
-        client.test_connection.return_value = True
-        mock.return_value = client
-        yield client
+def make_geoserver_client() -> Generator[Callable[..., Any], None, None]:
+    """Make GeoServer clients that talk to mocks instead of a server.
+
+    The clients are real, so their methods call one another as they
+    would against a server, but `_request` is a MagicMock for the test
+    to give responses. With mock_requests=False the real `_request`
+    runs and `_client`, the pooled httpx client, answers instead. Other
+    keyword arguments are Connection fields. Changes aren't recorded in
+    the activity log or managed state.
+    """
+    from apps.core.config import Connection
+    from apps.geoserver.client import GeoServerClient
+
+    def make(mock_requests: bool = True, **fields: Any) -> GeoServerClient:
+        with patch("apps.geoserver.client.client_manager"):
+            client = GeoServerClient(Connection(**{**TEST_CONNECTION, **fields}))
+        if mock_requests:
+            client._request = MagicMock()
+        return client
+
+    with patch("apps.geoserver.client.activity_log"), patch(
+        "apps.geoserver.client.managed_state"
+    ):
+        yield make
+
+
+@pytest.fixture
+def make_gwc_client() -> Callable[..., Any]:
+    """Make GeoWebCache clients that talk to mocks instead of a server.
+
+    As make_geoserver_client: `_request` is a MagicMock unless
+    mock_requests=False, and other keyword arguments are Connection
+    fields.
+    """
+    from apps.core.config import Connection
+    from apps.gwc.client import GWCClient
+
+    def make(mock_requests: bool = True, **fields: Any) -> GWCClient:
+        with patch("apps.gwc.client.client_manager"):
+            client = GWCClient(Connection(**{**TEST_CONNECTION, **fields}))
+        if mock_requests:
+            client._request = MagicMock()
+        return client
+
+    return make
 
 
 @pytest.fixture
@@ -0,0 +1,197 @@
+"""Integration tests for the GeoServer and GWC clients.
+
+The clients talk to a fake GeoServer answering from recorded REST
+responses, so requests and response handling are tested end to end
+without a running server.
+"""
+
+import json
+from collections.abc import Generator
+
+import pytest
+
+from apps.core.exceptions import GeoServerError, ReadOnlyConnectionError, ResponseTooLargeError
+from apps.geoserver.client import GeoServerClient
+from apps.geoserver.testing import FakeGeoServer
+from apps.gwc.client import GWCClient
+
+
+@pytest.fixture
+def server() -> Generator[FakeGeoServer, None, None]:
+    """A fake GeoServer clients are routed to."""
+    with FakeGeoServer() as fake:
+        yield fake
+
+
+@pytest.fixture
+def client(server: FakeGeoServer) -> GeoServerClient:
+    """A GeoServer client for the fake server."""
+    return GeoServerClient(server.connection())
+
+
+class TestWorkspaces:
+    """Tests for reading and changing workspaces."""
+
+    def test_list(self, client: GeoServerClient) -> None:
+        """Test workspaces are listed by name."""
+        names = [ws["name"] for ws in client.list_workspaces()]
+
+        assert names == ["nurc", "topp", "empty"]
+
+    def test_get(self, client: GeoServerClient) -> None:
+        """Test a workspace's details are unwrapped."""
+        assert client.get_workspace("empty")["isolated"] is True
+
+    def test_missing(self, client: GeoServerClient) -> None:
+        """Test a workspace the server doesn't know is a 404."""
+        with pytest.raises(GeoServerError) as error:
+            client.get_workspace("missing")
+
+        assert error.value.status_code == 404
+
+    def test_create(self, server: FakeGeoServer, client: GeoServerClient) -> None:
+        """Test creating a workspace posts its name."""
+        client.create_workspace("sandbox", isolated=True)
+
+        (request,) = server.requested("POST", "/rest/workspaces.json")
+        assert json.loads(request.content) == {"workspace": {"name": "sandbox", "isolated": True}}
+
+    def test_delete_recursively(self, server: FakeGeoServer, client: GeoServerClient) -> None:
+        """Test a recursive delete asks the server to recurse."""
+        client.delete_workspace("topp", recurse=True)
+
+        (request,) = server.requested("DELETE", "/rest/workspaces/topp")
+        assert request.url.params["recurse"] == "true"
+
+    def test_refused(self, server: FakeGeoServer, client: GeoServerClient) -> None:
+        """Test a refused change reports the server's message."""
+        server.add("POST", "/rest/workspaces.json", "Workspace 'topp' already exists", 409)
+
+        with pytest.raises(GeoServerError, match="already exists") as error:
+            client.create_workspace("topp")
+
+        assert error.value.status_code == 409
+
+
+class TestEmptyCollections:
+    """Tests for GeoServer listing empty collections as empty strings."""
+
+    @pytest.mark.parametrize(
+        ("method", "args"),
+        [
+            ("list_datastores", ("nurc",)),
+            ("list_coveragestores", ("empty",)),
+            ("list_layers", ("empty",)),
+            ("list_styles", ("empty",)),
+            ("list_layergroups", ("topp",)),
+        ],
+    )
+    def test_empty_string(self, client: GeoServerClient, method: str, args: tuple) -> None:
+        """Test an empty string is read as an empty list."""
+        assert getattr(client, method)(*args) == []
+
+
+class TestStoresAndLayers:
+    """Tests for reading stores, resources and layers."""
+
+    def test_datastores(self, client: GeoServerClient) -> None:
+        """Test data stores and their connection parameters are read."""
+        names = [store["name"] for store in client.list_datastores("topp")]
+        store = client.get_datastore("topp", "states_shapefile")
+
+        assert names == ["states_shapefile", "taz_shapes"]
+        assert store["type"] == "Shapefile"
+
+    def test_featuretype(self, client: GeoServerClient) -> None:
+        """Test a feature type is read with its attributes."""
+        (featuretype,) = client.list_featuretypes("topp", "states_shapefile")
+        details = client.get_featuretype("topp", "states_shapefile", featuretype["name"])
+
+        attributes = [a["name"] for a in details["attributes"]["attribute"]]
+        assert attributes == ["the_geom", "STATE_NAME", "PERSONS"]
+
+    def test_coverages(self, client: GeoServerClient) -> None:
+        """Test coverage stores and their coverages are read."""
+        (store,) = client.list_coveragestores("nurc")
+
+        assert client.list_coverages("nurc", store["name"])[0]["name"] == "mosaic"
+
+    def test_layers(self, client: GeoServerClient) -> None:
+        """Test layers are listed globally and per workspace."""
+        assert [layer["name"] for layer in client.list_layers()] == ["nurc:mosaic", "topp:states"]
+        assert client.get_layer("topp", "states")["type"] == "VECTOR"
+        assert client.get_layer("nurc", "mosaic")["type"] == "RASTER"
+
+
+class TestStyles:
+    """Tests for reading style bodies."""
+
+    def test_sld(self, client: GeoServerClient) -> None:
+        """Test a global SLD style is read in its own format."""
+        content, style_format = client.get_style_content("polygon")
+
+        assert style_format == "sld"
+        assert "<PolygonSymbolizer>" in content
+
+    def test_css(self, client: GeoServerClient) -> None:
+        """Test a workspace CSS style is read from its .css body."""
+        content, style_format = client.get_style_content("population", "topp")
+
+        assert style_format == "css"
+        assert "fill: #4DFF4D" in content
+
+
+class TestServerInfo:
+    """Tests for reading what the server is."""
+
+    def test_version(self, client: GeoServerClient) -> None:
+        """Test the GeoServer version is picked out of the about resources."""
+        assert client.get_server_version() == "2.24.1"
+
+    def test_capabilities(self, client: GeoServerClient) -> None:
+        """Test features follow from the version and installed extensions."""
+        capabilities = client.capabilities
+
+        assert capabilities.supports("system_status")
+        assert capabilities.supports("css_styles")
+        assert not capabilities.supports("backup_restore")
+
+
+class TestGuards:
+    """Tests for the checks made around every request."""
+
+    def test_read_only(self, server: FakeGeoServer) -> None:
+        """Test a read-only connection's change never reaches the server."""
+        client = GeoServerClient(server.connection(read_only=True))
+
+        with pytest.raises(ReadOnlyConnectionError):
+            client.delete_workspace("topp")
+
+        assert server.requests == []
+
+    def test_response_size_limit(self, server: FakeGeoServer) -> None:
+        """Test a response over the connection's limit is refused."""
+        server.add("GET", "/rest/layers.json", {"layers": {"layer": ["x" * 1024 * 1024]}})
+        client = GeoServerClient(server.connection(max_response_mb=0.5))
+
+        with pytest.raises(ResponseTooLargeError, match="0.5 MB"):
+            client.list_layers()
+
+
+class TestGWC:
+    """Tests for the GeoWebCache client."""
+
+    def test_layers(self, server: FakeGeoServer) -> None:
+        """Test cached layers are listed and read by their full name."""
+        gwc = GWCClient(server.connection())
+
+        assert gwc.list_layers() == ["nurc:mosaic", "topp:states"]
+        assert gwc.get_layer("topp:states")["mimeFormats"] == ["image/png", "image/jpeg"]
+
+    def test_gridsets(self, server: FakeGeoServer) -> None:
+        """Test the grid sets are listed."""
+        assert "EPSG:900913" in GWCClient(server.connection()).list_gridsets()
+
+    def test_no_seed_tasks(self, server: FakeGeoServer) -> None:
+        """Test a layer without running tasks has an empty status."""
+        assert GWCClient(server.connection()).get_seed_status("topp:states") == []
@@ -0,0 +1,122 @@
+"""Unit tests for the Backup and Restore plugin client."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.backup import BackupRestoreClient, Execution, workspace_filter
+from apps.geoserver.capabilities import build_capabilities
+
+
+def _response(status_code: int = 200, body: dict | None = None) -> MagicMock:
+    response = MagicMock(status_code=status_code, content=b"{}" if body is not None else b"")
+    response.json.return_value = body or {}
+    return response
+
+
+def _execution(status: str, progress: str = "", **extra) -> dict:
+    return {"backup": {"execution": {"id": 3, "status": status, "progress": progress}, **extra}}
+
+
+class FakeClock:
+    """Manually advanced clock; sleeping advances time."""
+
+    def __init__(self) -> None:
+        self.now = 0.0
+
+    def __call__(self) -> float:
+        return self.now
+
+    def sleep(self, seconds: float) -> None:
+        self.now += seconds
+
+
+def _plugin(responses: list[MagicMock]) -> tuple[BackupRestoreClient, MagicMock]:
+    client = MagicMock()
+    client._request.side_effect = responses
+    clock = FakeClock()
+    return BackupRestoreClient(client, clock=clock, sleep=clock.sleep), client
+
+
+class TestBackupRestore:
+    """Tests for starting and following backup and restore jobs."""
+
+    def test_workspace_filter_quotes_names(self) -> None:
+        """Test workspace names are quoted for CQL."""
+        assert workspace_filter(["topp", "o'neil"]) == "name IN ('topp', 'o''neil')"
+
+    def test_start_sends_filters_and_options(self) -> None:
+        """Test a backup carries its filters and options."""
+        plugin, client = _plugin([_response(201, _execution("STARTING"))])
+
+        execution = plugin.start(
+            "backup",
+            "/tmp/topp.zip",
+            ws_filter="name IN ('topp')",
+            options={"BK_BEST_EFFORT": True},
+        )
+
+        method, path = client._request.call_args.args
+        assert (method, path) == ("POST", "/rest/br/backup/")
+        job = client._request.call_args.kwargs["json"]["backup"]
+        assert job["archiveFile"] == "/tmp/topp.zip"
+        assert job["wsFilter"] == "name IN ('topp')"
+        assert "siFilter" not in job
+        assert job["options"] == {"option": ["BK_BEST_EFFORT=true"]}
+        assert execution.id == 3
+
+    def test_unknown_option_rejected(self) -> None:
+        """Test options of the other job kind aren't sent."""
+        plugin, client = _plugin([])
+
+        with pytest.raises(ValueError, match="BK_DRY_RUN"):
+            plugin.start("backup", "/tmp/a.zip", options={"BK_DRY_RUN": True})
+        client._request.assert_not_called()
+
+    def test_wait_polls_until_finished(self) -> None:
+        """Test polling stops once the job completes."""
+        plugin, _ = _plugin(
+            [
+                _response(200, _execution("STARTED", "2/9")),
+                _response(200, _execution("COMPLETED", "9/9", archiveFile="/tmp/a.zip")),
+            ]
+        )
+        seen: list[str] = []
+
+        execution = plugin.wait("backup", 3, on_progress=lambda e: seen.append(e.progress))
+
+        assert execution.succeeded
+        assert execution.archive_file == "/tmp/a.zip"
+        assert seen == ["2/9", "9/9"]
+
+    def test_wait_times_out(self) -> None:
+        """Test a job that keeps running gives up after the timeout."""
+        plugin, _ = _plugin([_response(200, _execution("STARTED"))] * 3)
+
+        with pytest.raises(GeoServerError, match="did not finish"):
+            plugin.wait("backup", 3, timeout=2.0, interval=1.0)
+
+    def test_failure_reports_exit_description(self) -> None:
+        """Test a failed job carries its error."""
+        execution = Execution.from_json(
+            "restore",
+            {
+                "execution": {
+                    "id": 1,
+                    "status": "FAILED",
+                    "exitStatus": {"exitCode": "FAILED", "exitDescription": "Bad archive"},
+                }
+            },
+        )
+
+        assert execution.finished and not execution.succeeded
+        assert execution.to_dict()["errors"] == ["Bad archive"]
+
+    def test_not_probed_without_plugin(self) -> None:
+        """Test the plugin isn't probed when the manifests say it's missing."""
+        plugin, client = _plugin([])
+        client.capabilities = build_capabilities("2.24.1", extensions={"backup_restore": False})
+
+        assert not plugin.is_available()
+        client._request.assert_not_called()
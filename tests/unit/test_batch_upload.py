@@ -0,0 +1,183 @@
+"""Unit tests for directory batch uploads."""
+
+from pathlib import Path
+from unittest.mock import MagicMock, call, patch
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.upload.batch import (
+    BatchItem,
+    classify_file,
+    propose_mapping,
+    publish_item,
+    run_batch,
+    sanitize_name,
+    walk_directory,
+)
+
+
+class TestClassification:
+    """Tests for file classification and naming."""
+
+    def test_classify_file(self) -> None:
+        """Test supported extensions map to kinds, case-insensitively."""
+        assert classify_file("data/roads.zip") == "shapefile"
+        assert classify_file("DEM.TIF") == "geotiff"
+        assert classify_file("dem.tiff") == "geotiff"
+        assert classify_file("base.gpkg") == "geopackage"
+        assert classify_file("styles/line.sld") == "style"
+        assert classify_file("readme.txt") is None
+
+    def test_sanitize_name(self) -> None:
+        """Test names are lowercased and reduced to letters, digits and underscores."""
+        assert sanitize_name("Roads 2024 (final)") == "roads_2024_final"
+        assert sanitize_name("2024-roads") == "layer_2024_roads"
+        assert sanitize_name("---") == "layer"
+
+
+class TestProposeMapping:
+    """Tests for proposing the upload mapping."""
+
+    def test_names_from_files(self) -> None:
+        """Test store, layer and style names come from the file names."""
+        items = propose_mapping(["Roads.zip", "base.gpkg", "line.sld", "notes.txt"], "topp")
+
+        assert [i.to_dict() for i in items] == [
+            {"path": "Roads.zip", "kind": "shapefile", "workspace": "topp",
+             "store": "roads", "layer": "roads", "title": "Roads",
+             "abstract": "Published from Roads.zip.", "keywords": ["vector", "shapefile"]},
+            {"path": "base.gpkg", "kind": "geopackage", "workspace": "topp",
+             "store": "base", "layer": "", "title": "", "abstract": "", "keywords": []},
+            {"path": "line.sld", "kind": "style", "workspace": "topp",
+             "store": "", "layer": "line", "title": "", "abstract": "", "keywords": []},
+        ]
+
+    def test_names_are_unique_per_workspace(self) -> None:
+        """Test files with the same stem get distinct store names."""
+        items = propose_mapping(["a/roads.zip", "b/roads.zip", "roads.tif", "roads.sld"], "topp")
+
+        assert [i.store for i in items if i.kind != "style"] == ["roads", "roads_2", "roads_3"]
+        # Styles don't collide with stores
+        assert [i.layer for i in items if i.kind == "style"] == ["roads"]
+
+    def test_folder_workspaces(self) -> None:
+        """Test top-level folders become workspaces when requested."""
+        items = propose_mapping(["Hydro Data/rivers.zip", "roads.zip"], "topp", True)
+
+        assert [(i.workspace, i.store) for i in items] == [
+            ("hydro_data", "rivers"),
+            ("topp", "roads"),
+        ]
+
+    def test_walk_directory(self, tmp_path: Path) -> None:
+        """Test supported files are found recursively, skipping hidden ones."""
+        (tmp_path / "vector").mkdir()
+        (tmp_path / ".git").mkdir()
+        for name in ["vector/roads.zip", "dem.tif", "readme.md", ".hidden.sld", ".git/x.zip"]:
+            (tmp_path / name).write_bytes(b"")
+
+        assert walk_directory(tmp_path) == ["dem.tif", "vector/roads.zip"]
+
+
+class TestBatchItem:
+    """Tests for mapping validation."""
+
+    def test_from_dict_strips_names(self) -> None:
+        """Test names from requests are trimmed."""
+        item = BatchItem.from_dict(
+            {"path": "roads.zip", "kind": "shapefile", "workspace": " topp ", "store": "roads "}
+        )
+
+        assert (item.workspace, item.store) == ("topp", "roads")
+
+    def test_missing_store_rejected(self) -> None:
+        """Test data files need a store name."""
+        with pytest.raises(ValueError):
+            BatchItem("roads.zip", "shapefile", "topp").validate()
+
+    def test_style_needs_name_not_store(self) -> None:
+        """Test styles need a name but no store."""
+        BatchItem("line.sld", "style", "topp", layer="line").validate()
+        with pytest.raises(ValueError):
+            BatchItem("line.sld", "style", "topp", store="line").validate()
+
+    def test_unknown_kind_rejected(self) -> None:
+        """Test unsupported kinds are rejected."""
+        with pytest.raises(ValueError):
+            BatchItem.from_dict({"path": "a.txt", "kind": "text", "workspace": "topp"})
+
+
+class TestPublish:
+    """Tests for publishing mapped files."""
+
+    @patch("apps.upload.batch.invalidate_store_cache", return_value=[])
+    def test_shapefile_layer_renamed(self, _invalidate: MagicMock) -> None:
+        """Test the published feature type is renamed to the mapped layer name."""
+        client = MagicMock()
+        client.list_featuretypes.return_value = [{"name": "Roads_2024"}]
+        item = BatchItem("roads.zip", "shapefile", "topp", store="roads", layer="roads")
+
+        result = publish_item(client, "conn", item, b"zip")
+
+        client.upload_shapefile.assert_called_once_with("topp", "roads", b"zip")
+        # Renamed first, then given its metadata under the new name
+        assert client.update_featuretype.call_args_list == [
+            call("topp", "roads", "Roads_2024", {"name": "roads"}),
+            call(
+                "topp",
+                "roads",
+                "roads",
+                {
+                    "title": "Roads",
+                    "abstract": "Published from roads.zip.",
+                    "keywords": {"string": ["vector", "shapefile"]},
+                },
+            ),
+        ]
+        assert result["layer"] == "roads"
+
+    @patch("apps.upload.batch.invalidate_store_cache", return_value=[])
+    def test_geotiff_coverage_name(self, invalidate: MagicMock) -> None:
+        """Test GeoTIFFs are published under the mapped coverage name."""
+        client = MagicMock()
+        item = BatchItem("dem.tif", "geotiff", "topp", store="dem", layer="elevation")
+
+        publish_item(client, "conn", item, b"tif")
+
+        client.upload_geotiff.assert_called_once_with(
+            "topp", "dem", b"tif", coverage_name="elevation"
+        )
+        invalidate.assert_called_once_with("conn", "topp", "dem", "coveragestore")
+
+    def test_style_created(self) -> None:
+        """Test SLD files become workspace styles."""
+        client = MagicMock()
+        item = BatchItem("line.sld", "style", "topp", layer="line")
+
+        result = publish_item(client, "conn", item, b"<sld/>")
+
+        client.create_style.assert_called_once_with("line", "<sld/>", workspace="topp")
+        assert result["style"] == "line"
+
+    @patch("apps.upload.batch.invalidate_store_cache", return_value=[])
+    def test_failures_are_isolated(self, _invalidate: MagicMock) -> None:
+        """Test one failing file doesn't stop the rest of the batch."""
+        client = MagicMock()
+        client.list_workspaces.return_value = [{"name": "topp"}]
+        client.list_featuretypes.return_value = []
+        client.upload_geotiff.side_effect = GeoServerError("bad tiff", status_code=400)
+        items = [
+            BatchItem("dem.tif", "geotiff", "topp", store="dem", layer="dem"),
+            BatchItem("roads.zip", "shapefile", "new", store="roads", layer="roads"),
+        ]
+        seen = []
+
+        results = run_batch(
+            client, "conn", items, read_file=lambda item: b"data", on_result=seen.append
+        )
+
+        assert [r["status"] for r in results] == ["error", "success"]
+        assert "bad tiff" in results[0]["error"]
+        assert len(seen) == 2
+        client.create_workspace.assert_called_once_with("new")
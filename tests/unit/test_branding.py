@@ -0,0 +1,168 @@
+"""Unit tests for layer attribution and the WMS watermark."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.branding import LayerAttribution, Watermark, logo_type
+
+
+class TestLayerAttribution:
+    """Tests for reading and validating attribution."""
+
+    def test_logo_type_is_guessed_from_the_url(self) -> None:
+        """Test the logo's MIME type comes from its extension."""
+        attribution = LayerAttribution.from_dict(
+            {
+                "attributionTitle": " Data by Kartoza ",
+                "attributionHref": "https://kartoza.com",
+                "attributionLogo": "https://kartoza.com/logo.PNG?v=2",
+                "attributionLogoWidth": "120",
+            }
+        )
+
+        assert attribution.title == "Data by Kartoza"
+        assert attribution.logo_type == "image/png"
+        assert attribution.logo_width == 120
+        assert attribution.logo_height is None
+
+    def test_logo_sizes_are_dropped_without_a_logo(self) -> None:
+        """Test sizes of a removed logo aren't kept."""
+        attribution = LayerAttribution.from_dict(
+            {"attributionLogo": "", "attributionLogoWidth": 50, "attributionLogoHeight": 20}
+        )
+
+        assert attribution == LayerAttribution()
+
+    @pytest.mark.parametrize(
+        ("data", "message"),
+        [
+            ({"attributionHref": "kartoza.com"}, "attribution link"),
+            ({"attributionLogo": "file:///logo.png"}, "logo must be"),
+            ({"attributionLogo": "https://kartoza.com/logo"}, "image type"),
+            ({"attributionLogo": "https://k.com/l.png", "attributionLogoWidth": "wide"}, "whole"),
+            ({"attributionLogo": "https://k.com/l.png", "attributionLogoHeight": 0}, "positive"),
+        ],
+    )
+    def test_invalid_attribution_is_refused(self, data: dict, message: str) -> None:
+        """Test invalid links and logo sizes are reported."""
+        with pytest.raises(ValueError, match=message):
+            LayerAttribution.from_dict(data)
+
+    def test_api_round_trip(self) -> None:
+        """Test attribution is written and read back in GeoServer's names."""
+        attribution = LayerAttribution("Kartoza", "https://k.com", "https://k.com/l.svg")
+        attribution.logo_type = logo_type(attribution.logo_url)
+
+        api = attribution.to_api()
+
+        assert api["logoURL"] == "https://k.com/l.svg"
+        assert api["logoType"] == "image/svg+xml"
+        assert LayerAttribution.from_api(api) == attribution
+
+
+class TestWatermark:
+    """Tests for reading and validating the watermark."""
+
+    def test_from_api_reads_the_url_key(self) -> None:
+        """Test the URL key GeoServer writes is read."""
+        watermark = Watermark.from_api(
+            {"enabled": True, "URL": "logo.png", "position": "TOP_LEFT", "transparency": 40}
+        )
+
+        assert watermark == Watermark(True, "logo.png", "TOP_LEFT", 40)
+        assert watermark.to_api()["URL"] == "logo.png"
+
+    def test_unset_watermark_defaults(self) -> None:
+        """Test servers without a watermark object read as disabled."""
+        assert Watermark.from_api(None) == Watermark()
+
+    @pytest.mark.parametrize(
+        ("data", "message"),
+        [
+            ({"position": "CORNER"}, "Unknown position"),
+            ({"transparency": 120}, "between 0 and 100"),
+            ({"transparency": "half"}, "whole number"),
+            ({"enabled": True, "url": " "}, "needs an image"),
+        ],
+    )
+    def test_invalid_watermark_is_refused(self, data: dict, message: str) -> None:
+        """Test invalid settings are reported."""
+        with pytest.raises(ValueError, match=message):
+            Watermark.from_dict(data)
+
+
+class TestBrandingClient:
+    """Tests for the attribution and watermark REST calls."""
+
+    def test_update_layer_attribution(self, make_geoserver_client) -> None:
+        """Test attribution is set on the layer."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        client.update_layer_attribution("topp", "roads", LayerAttribution(title="Kartoza"))
+
+        args, kwargs = client._request.call_args
+        assert args == ("PUT", "/rest/workspaces/topp/layers/roads.json")
+        assert kwargs["json"]["layer"]["attribution"]["title"] == "Kartoza"
+        assert kwargs["json"]["layer"]["attribution"]["href"] is None
+
+    def test_update_wms_watermark(self, make_geoserver_client) -> None:
+        """Test the watermark is set in the WMS settings."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        client.update_wms_watermark(Watermark(True, "logo.png"))
+
+        args, kwargs = client._request.call_args
+        assert args == ("PUT", "/rest/services/wms/settings.json")
+        assert kwargs["json"] == {
+            "wms": {
+                "watermark": {
+                    "enabled": True,
+                    "URL": "logo.png",
+                    "position": "BOT_RIGHT",
+                    "transparency": 0,
+                }
+            }
+        }
+
+    def test_update_layer_resource_follows_the_store(self, make_geoserver_client) -> None:
+        """Test a layer's feature type is updated in its own store."""
+        client = make_geoserver_client()
+        layer = MagicMock(status_code=200)
+        layer.json.return_value = {
+            "layer": {
+                "resource": {
+                    "@class": "featureType",
+                    "name": "topp:roads_2024",
+                    "href": "http://gs/rest/workspaces/topp/datastores/pg/featuretypes/"
+                    "roads_2024.json",
+                }
+            }
+        }
+        client._request.side_effect = [layer, MagicMock(status_code=200)]
+
+        client.update_layer_resource("topp", "roads", {"title": "Roads"})
+
+        args, kwargs = client._request.call_args
+        assert args == ("PUT", "/rest/workspaces/topp/datastores/pg/featuretypes/roads_2024.json")
+        assert kwargs["json"] == {"featureType": {"title": "Roads"}}
+
+    def test_update_layer_resource_refuses_cascaded_layers(self, make_geoserver_client) -> None:
+        """Test layers without a feature type or coverage are refused."""
+        client = make_geoserver_client()
+        layer = MagicMock(status_code=200)
+        layer.json.return_value = {
+            "layer": {
+                "resource": {
+                    "href": "http://gs/rest/workspaces/topp/wmsstores/remote/wmslayers/a.json"
+                }
+            }
+        }
+        client._request.return_value = layer
+
+        with pytest.raises(GeoServerError) as exc_info:
+            client.update_layer_resource("topp", "a", {"title": "A"})
+        assert exc_info.value.status_code == 400
@@ -0,0 +1,118 @@
+"""Unit tests for GeoServer version-aware feature gating."""
+
+from unittest.mock import MagicMock
+from xml.etree import ElementTree as ET
+
+from apps.geoserver.capabilities import build_capabilities, parse_version
+from apps.geoserver.client import datastore_xml
+
+
+class TestParseVersion:
+    """Tests for version parsing."""
+
+    def test_release_and_snapshot_versions(self) -> None:
+        """Test major and minor numbers are extracted."""
+        assert parse_version("2.24.1") == (2, 24)
+        assert parse_version("2.25-SNAPSHOT") == (2, 25)
+
+    def test_unknown_versions(self) -> None:
+        """Test unparseable versions return None."""
+        assert parse_version(None) is None
+        assert parse_version("") is None
+        assert parse_version("Unknown") is None
+
+
+class TestBuildCapabilities:
+    """Tests for the capability matrix."""
+
+    def test_old_version_lacks_newer_features(self) -> None:
+        """Test features are gated on their minimum version."""
+        capabilities = build_capabilities("2.11.4")
+
+        assert not capabilities.supports("json_datastore")
+        assert not capabilities.supports("system_status")
+
+    def test_current_version_supports_everything(self) -> None:
+        """Test a recent release supports every known feature."""
+        capabilities = build_capabilities("2.24.1")
+
+        assert all(capabilities.features.values())
+
+    def test_unknown_version_assumes_support(self) -> None:
+        """Test an unreadable version doesn't disable features."""
+        capabilities = build_capabilities(None)
+
+        assert capabilities.supports("system_status")
+
+    def test_overrides_win(self) -> None:
+        """Test config overrides replace the derived values."""
+        capabilities = build_capabilities(
+            "2.11.0", {"system_status": True, "json_datastore": False}
+        )
+
+        assert capabilities.supports("system_status")
+        assert not capabilities.supports("json_datastore")
+
+    def test_to_dict(self) -> None:
+        """Test serialization lists every feature with its minimum version."""
+        data = build_capabilities("2.14.0").to_dict()
+
+        features = {f["name"]: f for f in data["features"]}
+        assert data["version"] == "2.14.0"
+        assert features["system_status"]["minVersion"] == "2.16"
+        assert features["system_status"]["supported"] is False
+        assert features["gwc_parameter_truncate"]["supported"] is True
+
+
+class TestClientGating:
+    """Tests for the client consulting the capability matrix."""
+
+    def test_datastore_xml(self) -> None:
+        """Test the XML datastore payload carries keyed entries."""
+        root = ET.fromstring(datastore_xml("roads", {"dbtype": "postgis", "port": "5432"}))
+
+        assert root.findtext("name") == "roads"
+        assert root.findtext("enabled") == "true"
+        entries = {e.get("key"): e.text for e in root.iter("entry")}
+        assert entries == {"dbtype": "postgis", "port": "5432"}
+
+    def test_create_datastore_uses_xml_on_old_servers(self, make_geoserver_client) -> None:
+        """Test servers without JSON datastore support get XML."""
+        client = make_geoserver_client()
+        client._capabilities = build_capabilities("2.11.0")
+        client._request.return_value = MagicMock(status_code=201)
+
+        client.create_datastore("topp", "roads", {"dbtype": "postgis"})
+
+        args, kwargs = client._request.call_args
+        assert args == ("POST", "/rest/workspaces/topp/datastores")
+        assert kwargs["headers"] == {"Content-Type": "text/xml"}
+        assert b"<dataStore>" in kwargs["content"]
+
+    def test_create_datastore_uses_json_on_current_servers(self, make_geoserver_client) -> None:
+        """Test current servers get the JSON payload."""
+        client = make_geoserver_client()
+        client._capabilities = build_capabilities("2.24.0")
+        client._request.return_value = MagicMock(status_code=201)
+
+        client.create_datastore("topp", "roads", {"dbtype": "postgis"})
+
+        args, kwargs = client._request.call_args
+        assert args == ("POST", "/rest/workspaces/topp/datastores.json")
+        assert kwargs["json"]["dataStore"]["name"] == "roads"
+
+    def test_server_version_from_about(self, make_geoserver_client) -> None:
+        """Test the GeoServer resource is picked from the about document."""
+        client = make_geoserver_client()
+        about = MagicMock(status_code=200)
+        about.json.return_value = {
+            "about": {
+                "resource": [
+                    {"@name": "GeoTools", "Version": "30.1"},
+                    {"@name": "GeoServer", "Version": "2.24.1"},
+                ]
+            }
+        }
+        client._request.return_value = about
+
+        assert client.get_server_version() == "2.24.1"
@@ -0,0 +1,127 @@
+"""Unit tests for the catalog reference integrity checker."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.lint import CatalogLinter, lint_catalog
+
+
+def _client() -> MagicMock:
+    """A catalog with one workspace holding roads and rivers."""
+    client = MagicMock()
+    client.list_workspaces.return_value = [{"name": "topp"}]
+    client.list_layers.return_value = [{"name": "roads"}, {"name": "rivers"}]
+    client.list_layergroups.side_effect = lambda ws=None: [{"name": "base"}] if ws else []
+    client.list_styles.side_effect = lambda ws=None: [{"name": "dashed"}] if ws else [
+        {"name": "line"}
+    ]
+    client.list_datastores.return_value = []
+    return client
+
+
+class TestLayerGroups:
+    """Tests for layer groups referencing deleted layers."""
+
+    def test_missing_layer_reported(self) -> None:
+        """Test a group entry whose layer is gone is reported."""
+        client = _client()
+        client.get_layergroup.return_value = {
+            "publishables": {
+                "published": [
+                    {"@type": "layer", "name": "topp:roads"},
+                    {"@type": "layer", "name": "topp:lakes"},
+                    {"@type": "layerGroup", "name": "topp:base"},
+                    "",  # Style-only entry
+                ]
+            }
+        }
+
+        issues = CatalogLinter(client).check_layergroup_missing_layer()
+
+        assert [(i.resource, i.severity) for i in issues] == [("topp:base", "error")]
+        assert "topp:lakes" in issues[0].message
+
+    def test_single_entry_group(self) -> None:
+        """Test a group whose single entry is collapsed into an object."""
+        client = _client()
+        client.get_layergroup.return_value = {
+            "publishables": {"published": {"@type": "layer", "name": "topp:roads"}}
+        }
+
+        assert CatalogLinter(client).check_layergroup_missing_layer() == []
+
+
+class TestDefaultStyles:
+    """Tests for layers with missing default styles."""
+
+    def test_missing_style_reported(self) -> None:
+        """Test global and workspace styles resolve, missing ones are reported."""
+        client = _client()
+        client.get_layer.side_effect = lambda ws, name: {
+            "roads": {"defaultStyle": {"name": "dashed", "workspace": "topp"}},
+            "rivers": {"defaultStyle": {"name": "topp:deleted"}},
+        }[name]
+
+        issues = CatalogLinter(client).check_missing_default_style()
+
+        assert [i.resource for i in issues] == ["topp:rivers"]
+        assert "topp:deleted" in issues[0].message
+
+
+class TestDisabledStores:
+    """Tests for feature types in disabled stores."""
+
+    def test_feature_types_of_disabled_store(self) -> None:
+        """Test only feature types of disabled stores are reported."""
+        client = _client()
+        client.list_datastores.return_value = [{"name": "on"}, {"name": "off"}]
+        client.get_datastore.side_effect = lambda ws, name: {"enabled": name == "on"}
+        client.list_featuretypes.return_value = [{"name": "roads"}]
+
+        issues = CatalogLinter(client).check_disabled_store()
+
+        assert [(i.resource, i.severity) for i in issues] == [("topp:roads", "warning")]
+        client.list_featuretypes.assert_called_once_with("topp", "off")
+
+
+class TestOrphanedCaches:
+    """Tests for tile layers without a catalog layer."""
+
+    def test_orphaned_tile_layer(self) -> None:
+        """Test tile layers of layers and groups are known, others reported."""
+        gwc = MagicMock()
+        gwc.list_layers.return_value = ["topp:roads", "topp:base", "topp:old"]
+
+        issues = CatalogLinter(_client(), gwc).check_orphaned_cache()
+
+        assert [i.resource for i in issues] == ["topp:old"]
+
+    def test_gwc_unavailable(self) -> None:
+        """Test the check is skipped when GWC can't be reached."""
+        gwc = MagicMock()
+        gwc.list_layers.side_effect = GeoServerError("GWC HTTP error")
+
+        assert CatalogLinter(_client(), gwc).check_orphaned_cache() == []
+        assert CatalogLinter(_client()).check_orphaned_cache() == []
+
+
+class TestRun:
+    """Tests for running checks together."""
+
+    def test_errors_sorted_first(self) -> None:
+        """Test errors come before warnings."""
+        client = _client()
+        client.get_layer.return_value = {"defaultStyle": {"name": "gone"}}
+        gwc = MagicMock()
+        gwc.list_layers.return_value = ["topp:old"]
+
+        issues = lint_catalog(client, gwc, ["orphaned_cache", "missing_default_style"])
+
+        assert [i.severity for i in issues] == ["error", "error", "warning"]
+
+    def test_unknown_check(self) -> None:
+        """Test unknown check names are rejected."""
+        with pytest.raises(ValueError):
+            lint_catalog(_client(), checks=["spelling"])
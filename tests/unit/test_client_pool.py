@@ -0,0 +1,72 @@
+"""Unit tests for shared HTTP transports."""
+
+from apps.core.config import Connection
+from apps.core.managers import ClientManager, TransportOptions
+
+
+class TestTransportPool:
+    """Tests for transport sharing in the client manager."""
+
+    def test_same_host_shares_transport(self) -> None:
+        """Test clients for one host reuse a transport."""
+        manager = ClientManager()
+        options = TransportOptions()
+
+        first = manager.get_transport("https://maps.example.com/geoserver", options)
+        second = manager.get_transport("https://maps.example.com/other", options)
+
+        assert first is second
+
+    def test_different_options_use_separate_transports(self) -> None:
+        """Test pool settings are part of the transport key."""
+        manager = ClientManager()
+
+        first = manager.get_transport("https://maps.example.com", TransportOptions())
+        second = manager.get_transport(
+            "https://maps.example.com", TransportOptions(max_idle_connections=2)
+        )
+
+        assert first is not second
+
+    def test_different_hosts_use_separate_transports(self) -> None:
+        """Test each host gets its own transport."""
+        manager = ClientManager()
+        options = TransportOptions()
+
+        first = manager.get_transport("https://a.example.com", options)
+        second = manager.get_transport("https://b.example.com", options)
+
+        assert first is not second
+
+    def test_options_from_connection(self) -> None:
+        """Test pool settings are read from the connection."""
+        conn = Connection(
+            name="Test",
+            url="http://localhost:8080/geoserver",
+            username="admin",
+            password="geoserver",
+            pool_max_idle=4,
+            pool_idle_timeout=15,
+            http2=True,
+            response_timeout=60,
+        )
+
+        options = TransportOptions.from_connection(conn)
+
+        assert options == TransportOptions(
+            max_idle_connections=4, idle_timeout=15, http2=True, response_timeout=60
+        )
+
+    def test_removed_client_keeps_shared_transport_open(self) -> None:
+        """Test removing one client doesn't break others on the same host."""
+        manager = ClientManager()
+        options = TransportOptions(max_idle_connections=3)
+        manager.get_client("conn_pool_a", "http://pool.example.com", transport_options=options)
+        other = manager.get_client(
+            "conn_pool_b", "http://pool.example.com", transport_options=options
+        )
+
+        manager.remove_client("conn_pool_a")
+
+        assert not other.is_closed
+        manager.remove_client("conn_pool_b")
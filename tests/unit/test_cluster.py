@@ -0,0 +1,220 @@
+"""Unit tests for cluster connections: primary node, reload, reset, truncation and health."""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.config import Connection
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.cluster import check_node, node_health, run_on_nodes
+from apps.gwc.client import GWCClient
+
+NODES = "http://node1:8080/geoserver/, http://node2:8080/geoserver,"
+
+
+def _connection(cluster_urls: str = "", cluster_primary: str = "") -> Connection:
+    return Connection(
+        id="conn_1",
+        name="Cluster",
+        url="http://lb.example.com/geoserver",
+        username="admin",
+        password="geoserver",
+        cluster_urls=cluster_urls,
+        cluster_primary=cluster_primary,
+    )
+
+
+@pytest.fixture
+def gwc_client(make_gwc_client) -> GWCClient:
+    """A GWC client of the cluster whose primary truncates and nodes are mocked."""
+    client = make_gwc_client(cluster_urls=NODES)
+    client._request.return_value = MagicMock(status_code=200)
+    client._send = MagicMock()
+    return client
+
+
+class TestNodeUrls:
+    """Test reading the nodes of a connection."""
+
+    def test_cluster(self) -> None:
+        """Test node URLs are split, trimmed and stripped of trailing slashes."""
+        assert _connection(NODES).node_urls == [
+            "http://node1:8080/geoserver",
+            "http://node2:8080/geoserver",
+        ]
+
+    def test_single_server(self) -> None:
+        """Test a connection without cluster URLs is its own only node."""
+        assert _connection().node_urls == ["http://lb.example.com/geoserver"]
+        assert not _connection().is_cluster
+
+    def test_primary(self) -> None:
+        """Test the first node is the primary unless another is designated."""
+        assert _connection(NODES).primary_url == "http://node1:8080/geoserver"
+        assert _connection(NODES).secondary_urls == ["http://node2:8080/geoserver"]
+
+        connection = _connection(NODES, cluster_primary="http://node2:8080/geoserver/")
+        assert connection.primary_url == "http://node2:8080/geoserver"
+        assert connection.secondary_urls == ["http://node1:8080/geoserver"]
+
+    def test_single_server_primary(self) -> None:
+        """Test an unclustered connection's requests go to its url alone."""
+        assert _connection().primary_url == "http://lb.example.com/geoserver"
+        assert _connection().secondary_urls == []
+
+
+class TestRunOnNodes:
+    """Test sending reload and reset to every node."""
+
+    def test_every_node(self) -> None:
+        """Test each node is reloaded and a failed one doesn't stop the others."""
+        client = MagicMock()
+        client.connection = _connection(NODES)
+
+        def reload(node_url):
+            if "node2" in node_url:
+                raise GeoServerError("HTTP error: connection refused")
+
+        client.reload_catalog.side_effect = reload
+
+        result = run_on_nodes(client, "reload")
+
+        assert not result.ok
+        assert [(n.url, n.ok) for n in result.nodes] == [
+            ("http://node1:8080/geoserver", True),
+            ("http://node2:8080/geoserver", False),
+        ]
+        assert result.failed[0].error == "HTTP error: connection refused"
+        assert result.to_dict()["nodes"][1]["error"] == "HTTP error: connection refused"
+        client.reset_caches.assert_not_called()
+
+    def test_single_server(self) -> None:
+        """Test an unclustered connection is reset through its own url."""
+        client = MagicMock()
+        client.connection = _connection()
+
+        result = run_on_nodes(client, "reset")
+
+        assert result.ok
+        client.reset_caches.assert_called_once_with(node_url=None)
+
+    def test_unknown_action(self) -> None:
+        """Test only reload and reset can be sent."""
+        with pytest.raises(ValueError, match="Unknown action"):
+            run_on_nodes(MagicMock(), "restart")
+
+
+class TestNodeRequests:
+    """Test the client addressing one node of a cluster."""
+
+    def test_node_url(self, make_geoserver_client) -> None:
+        """Test a node's request goes to its URL rather than the connection's."""
+        client = make_geoserver_client(cluster_urls=NODES)
+        client._open = MagicMock()
+        client._open.return_value.__enter__.return_value = MagicMock(status_code=200)
+
+        with patch("apps.geoserver.client.read_limited", lambda r, limit: r):
+            client.reset_caches(node_url="http://node2:8080/geoserver")
+
+        client._open.assert_called_once_with("POST", "http://node2:8080/geoserver/rest/reset")
+        client._request.assert_not_called()
+
+    def test_read_only(self, make_geoserver_client) -> None:
+        """Test nodes of a read-only connection aren't sent changes either."""
+        client = make_geoserver_client(cluster_urls=NODES, read_only=True)
+        client._open = MagicMock()
+
+        with pytest.raises(GeoServerError, match="read-only"):
+            client.reload_catalog(node_url="http://node1:8080/geoserver")
+
+        client._open.assert_not_called()
+
+
+class TestTruncateFanOut:
+    """Test tile cache truncations reaching every node."""
+
+    def test_every_node(self, gwc_client) -> None:
+        """Test the other nodes are truncated after the primary."""
+        gwc_client._send.return_value = MagicMock(status_code=200)
+
+        gwc_client.truncate_entire_layer("topp:roads")
+
+        gwc_client._request.assert_called_once()
+        assert gwc_client._send.call_args.args[:2] == (
+            "POST",
+            "http://node2:8080/geoserver/gwc/rest/masstruncate",
+        )
+
+    def test_node_fails(self, gwc_client) -> None:
+        """Test a node left with stale tiles is reported."""
+        gwc_client._send.side_effect = GeoServerError("GWC HTTP error: connection refused")
+
+        with pytest.raises(GeoServerError, match="not on http://node2") as e:
+            gwc_client.truncate_layer("topp:roads")
+
+        assert e.value.status_code == 502
+
+    def test_primary_fails(self, gwc_client) -> None:
+        """Test the other nodes aren't asked when the primary fails."""
+        gwc_client._request.return_value = MagicMock(status_code=500, text="boom")
+
+        with pytest.raises(GeoServerError, match="Failed to truncate"):
+            gwc_client.truncate_layer("topp:roads")
+
+        gwc_client._send.assert_not_called()
+
+    def test_single_server(self, make_gwc_client) -> None:
+        """Test an unclustered connection is truncated once."""
+        client = make_gwc_client()
+        client._request.return_value = MagicMock(status_code=200)
+        client._send = MagicMock()
+
+        client.truncate_entire_layer("topp:roads")
+
+        client._send.assert_not_called()
+
+
+class TestNodeHealth:
+    """Test checking each node of a cluster."""
+
+    def test_every_node(self) -> None:
+        """Test each node's memory and CPU, primary first, and an offline node."""
+        client = MagicMock()
+        client.connection = _connection(NODES, cluster_primary="http://node2:8080/geoserver")
+
+        def about(node_url):
+            if "node1" in node_url:
+                raise GeoServerError("HTTP error: connection refused")
+            return {}
+
+        client.get_about.side_effect = about
+        client.get_system_status.return_value = [
+            {"name": "MEMORY_USED", "value": "1024", "available": True},
+            {"name": "MEMORY_TOTAL", "value": "4096", "available": True},
+            {"name": "CPU_LOAD", "value": "12.345", "available": True},
+            {"name": "SWAP_USED", "value": "", "available": False},
+        ]
+
+        nodes = node_health(client)
+
+        assert [(n.url, n.primary, n.online) for n in nodes] == [
+            ("http://node2:8080/geoserver", True, True),
+            ("http://node1:8080/geoserver", False, False),
+        ]
+        assert nodes[0].memory_used_pct == 25.0
+        assert nodes[0].cpu_load == 12.3
+        assert nodes[1].error == "HTTP error: connection refused"
+        assert nodes[0].to_dict()["memoryTotal"] == 4096
+
+    def test_without_system_status(self) -> None:
+        """Test a node without system status is online without metrics."""
+        client = MagicMock()
+        client.connection = _connection()
+        client.get_system_status.side_effect = GeoServerError("Requires 2.16", 501)
+
+        health = check_node(client, None, True)
+
+        assert health.online
+        assert health.url == "http://lb.example.com/geoserver"
+        assert health.status_error == "Requires 2.16"
+        assert health.memory_used_pct == 0.0
@@ -0,0 +1,149 @@
+"""Unit tests for COG advice and conversion before GeoTIFF upload."""
+
+import io
+import struct
+from pathlib import Path
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.exceptions import UploadError
+from apps.upload import cog
+
+
+def _tiff(*directories: dict[int, int], big: bool = False, ghost: bytes = b"") -> bytes:
+    """Build a little-endian TIFF of image directories holding SHORT/LONG tags."""
+    if big:
+        header = struct.pack("<2sHHHQ", b"II", 43, 8, 0, 0)
+        count_fmt, entry_fmt, next_fmt = "<Q", "<HHQQ", "<Q"
+    else:
+        header = struct.pack("<2sHI", b"II", 42, 0)
+        count_fmt, entry_fmt, next_fmt = "<H", "<HHII", "<I"
+    out = bytearray(header + ghost)
+    first = len(out)
+    for i, tags in enumerate(directories):
+        out += struct.pack(count_fmt, len(tags))
+        for tag, value in sorted(tags.items()):
+            out += struct.pack(entry_fmt, tag, 4, 1, value)
+        following = len(out) + struct.calcsize(next_fmt)
+        out += struct.pack(next_fmt, following if i < len(directories) - 1 else 0)
+    offset_at = 8 if big else 4
+    struct.pack_into(next_fmt, out, offset_at, first)
+    return bytes(out)
+
+
+STRIPPED = {256: 4000, 257: 3000, 259: 1, 277: 3, 258: 8}
+TILED = {**STRIPPED, 259: 8, 322: 256, 323: 256}
+OVERVIEW = {254: 1, 256: 2000, 257: 1500, 322: 256}
+MASK = {254: 5, 256: 2000, 257: 1500}
+
+
+class TestTiffLayout:
+    """Tests for reading how a TIFF is laid out."""
+
+    def test_stripped(self) -> None:
+        """Test a striped image without overviews."""
+        layout = cog.read_tiff_layout(io.BytesIO(_tiff(STRIPPED)))
+
+        assert (layout.width, layout.height, layout.bands) == (4000, 3000, 3)
+        assert not layout.tiled and layout.overviews == 0 and layout.compression == 1
+
+    def test_overviews_exclude_masks(self) -> None:
+        """Test reduced-resolution images count as overviews but masks don't."""
+        layout = cog.read_tiff_layout(io.BytesIO(_tiff(TILED, OVERVIEW, MASK)))
+
+        assert layout.tiled and layout.overviews == 1
+
+    def test_bigtiff_and_ghost_area(self) -> None:
+        """Test BigTIFFs are read and GDAL's COG layout marker is found."""
+        ghost = b"GDAL_STRUCTURAL_METADATA_SIZE=000044 bytes\nLAYOUT=IFDS_BEFORE_DATA\n"
+        layout = cog.read_tiff_layout(io.BytesIO(_tiff(TILED, OVERVIEW, big=True, ghost=ghost)))
+
+        assert layout.cog and layout.overviews == 1
+
+    def test_not_a_tiff(self) -> None:
+        """Test other files are refused."""
+        with pytest.raises(ValueError, match="Not a TIFF"):
+            cog.read_tiff_layout(io.BytesIO(b"\x89PNG\r\n\x1a\n"))
+
+
+class TestAdvise:
+    """Tests for advising on COG conversion."""
+
+    @patch("apps.upload.cog.gdal_available", return_value=True)
+    @patch("apps.upload.cog.threshold_bytes", return_value=0)
+    def test_stripped_without_overviews(
+        self, _threshold: MagicMock, _gdal: MagicMock, tmp_path: Path
+    ) -> None:
+        """Test both problems are reported with estimates, and nothing is automatic."""
+        path = tmp_path / "dem.tif"
+        path.write_bytes(_tiff(STRIPPED))
+
+        advice = cog.advise(path)
+
+        assert advice.reasons == ["Stored in strips rather than tiles", "Has no overviews"]
+        expected = advice.file_size * cog.OVERVIEW_FACTOR * cog.DEFLATE_RATIO
+        assert advice.estimated_size == int(expected)
+        assert advice.estimated_seconds == 1
+        assert not advice.auto_convert
+
+    @patch("apps.upload.cog.threshold_bytes", return_value=10)
+    def test_already_optimized(self, _threshold: MagicMock, tmp_path: Path) -> None:
+        """Test tiled images with overviews are left alone."""
+        path = tmp_path / "dem.tif"
+        path.write_bytes(_tiff(TILED, OVERVIEW))
+
+        advice = cog.advise(path)
+
+        assert not advice.needs_conversion and not advice.auto_convert
+
+    @patch("apps.upload.cog.threshold_bytes", return_value=10)
+    def test_over_threshold(self, _threshold: MagicMock, tmp_path: Path) -> None:
+        """Test files over the configured size are converted without asking."""
+        path = tmp_path / "dem.tif"
+        path.write_bytes(_tiff(TILED))
+
+        advice = cog.advise(path)
+
+        assert advice.reasons == ["Has no overviews"] and advice.auto_convert
+
+    def test_unreadable(self, tmp_path: Path) -> None:
+        """Test files that can't be read get no advice."""
+        path = tmp_path / "dem.tif"
+        path.write_bytes(b"not a tiff")
+
+        advice = cog.advise(path)
+
+        assert advice.layout is None and not advice.needs_conversion
+
+
+class TestConvert:
+    """Tests for converting with GDAL."""
+
+    @patch("apps.upload.cog.subprocess.run")
+    def test_gdal_translate(self, run: MagicMock, tmp_path: Path) -> None:
+        """Test the COG driver is used with overviews."""
+        run.return_value = MagicMock(returncode=0, stderr="")
+
+        cog.convert_to_cog(tmp_path / "a.tif", tmp_path / "b.tif")
+
+        command = run.call_args.args[0]
+        assert command[:3] == ["gdal_translate", "-of", "COG"]
+        assert "OVERVIEWS=AUTO" in command
+
+    @patch("apps.upload.cog.subprocess.run")
+    def test_failure(self, run: MagicMock, tmp_path: Path) -> None:
+        """Test GDAL's last error line is reported."""
+        run.return_value = MagicMock(returncode=1, stderr="Warning\nERROR 4: not recognized\n")
+
+        with pytest.raises(UploadError, match="not recognized"):
+            cog.convert_to_cog(tmp_path / "a.tif", tmp_path / "b.tif")
+
+    @patch("apps.upload.cog.convert_to_cog")
+    @patch("apps.upload.cog.threshold_bytes", return_value=0)
+    def test_auto_convert_needs_threshold(self, _threshold: MagicMock, convert: MagicMock) -> None:
+        """Test batch uploads are left alone unless a threshold is set."""
+        data = _tiff(STRIPPED)
+
+        assert cog.auto_convert("dem.tif", data) is data
+        convert.assert_not_called()
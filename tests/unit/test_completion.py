@@ -0,0 +1,76 @@
+"""Unit tests for shell completion and fuzzy picking."""
+
+import io
+from unittest.mock import MagicMock, patch
+
+from apps.core.completion import complete_words, completion_script, fuzzy_filter, fuzzy_pick
+
+
+class _Tty(io.StringIO):
+    """Input that claims to be a terminal."""
+
+    def isatty(self) -> bool:
+        """Pretend to be interactive."""
+        return True
+
+
+class TestCompleteWords:
+    """Tests for completing command lines."""
+
+    def test_command_names(self) -> None:
+        """Test the first word completes subcommands."""
+        assert complete_words(["li"], ["connections", "lint", "migrate"]) == ["lint"]
+
+    def test_option_value(self) -> None:
+        """Test values of known options are completed."""
+        assert complete_words(["lint", "conn_1", "--output", "j"], []) == ["json"]
+
+    def test_positional_connection(self) -> None:
+        """Test positionals are completed by the command's argument kinds."""
+        with patch("apps.core.completion.candidates", return_value=["conn_1", "prod"]) as c:
+            assert complete_words(["lint", "--check", "disabled_store", "c"], []) == ["conn_1"]
+        c.assert_called_once_with("connection")
+
+    def test_catalog_names_cached(self, tmp_path) -> None:
+        """Test catalog names are listed once and then served from the cache."""
+        client = MagicMock()
+        client.list_workspaces.return_value = [{"name": "topp"}]
+        client.list_layers.return_value = [{"name": "roads"}]
+
+        with patch("apps.core.completion.get_cache_dir", return_value=tmp_path), patch(
+            "apps.core.completion.default_connection_id", return_value="conn_1"
+        ), patch("apps.geoserver.client.get_geoserver_client", return_value=client):
+            assert complete_words(["x", "--layer", "topp:r"], []) == ["topp:roads"]
+            assert complete_words(["x", "--layer", ""], []) == ["topp:roads"]
+
+        client.list_workspaces.assert_called_once()
+
+    def test_scripts(self) -> None:
+        """Test each shell's script calls back into the completer."""
+        for shell in ("bash", "zsh", "fish"):
+            assert "completion --complete" in completion_script(shell)
+
+
+class TestFuzzyPick:
+    """Tests for the interactive picker."""
+
+    def test_filter_ranks_substrings_first(self) -> None:
+        """Test substring matches beat scattered ones and non-matches drop."""
+        values = ["staging", "production", "prod-eu", "dev"]
+        assert fuzzy_filter("prd", values) == ["prod-eu", "production"]
+        assert fuzzy_filter("prod", values)[0] == "prod-eu"
+
+    def test_pick_by_narrowing_then_number(self) -> None:
+        """Test typing narrows the list and a number picks from it."""
+        out = io.StringIO()
+        values = ["staging", "production", "prod-eu"]
+        assert fuzzy_pick(values, "connection", _Tty("prod\n2\n"), out) == "production"
+
+    def test_unique_match_picks(self) -> None:
+        """Test a query matching one value picks it straight away."""
+        picked = fuzzy_pick(["staging", "production"], "x", _Tty("stag\n"), io.StringIO())
+        assert picked == "staging"
+
+    def test_not_interactive(self) -> None:
+        """Test nothing is picked without a terminal."""
+        assert fuzzy_pick(["staging"], "x", io.StringIO("staging\n"), io.StringIO()) is None
@@ -0,0 +1,100 @@
+"""Unit tests for previewing a layer group's composition."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.composition import (
+    MAX_SIZE,
+    composition_bbox,
+    fit_bbox,
+    local_name,
+    render_composition,
+)
+
+BOUNDS = {
+    "landuse": {"minx": 16.0, "miny": -35.0, "maxx": 20.0, "maxy": -30.0},
+    "roads": {"minx": 18.0, "miny": -34.0, "maxx": 33.0, "maxy": -22.0},
+}
+
+
+def _client() -> MagicMock:
+    client = MagicMock()
+
+    def bounds(workspace: str, layer: str) -> dict:
+        if layer not in BOUNDS:
+            raise GeoServerError("Layer not found", status_code=404)
+        return {"latLonBoundingBox": BOUNDS[layer]}
+
+    client.get_layer_bounds.side_effect = bounds
+    client.get_map.return_value = b"png"
+    return client
+
+
+class TestComposition:
+    """Tests for drawing draft layer groups."""
+
+    def test_bbox_covers_all_layers(self) -> None:
+        """Test the box is the union of the layers that have bounds."""
+        bbox = composition_bbox(_client(), "topp", ["landuse", "roads", "gone"])
+
+        assert bbox == (16.0, -35.0, 33.0, -22.0)
+
+    def test_bbox_needs_some_bounds(self) -> None:
+        """Test layers without bounds can't be placed."""
+        with pytest.raises(GeoServerError) as e:
+            composition_bbox(_client(), "topp", ["gone"])
+
+        assert e.value.status_code == 400
+
+    def test_fit_keeps_center_and_aspect(self) -> None:
+        """Test the padded box matches the image's aspect ratio around the same center."""
+        minx, miny, maxx, maxy = fit_bbox((0.0, 0.0, 10.0, 10.0), 400, 200)
+
+        assert (minx + maxx) / 2 == pytest.approx(5.0)
+        assert (miny + maxy) / 2 == pytest.approx(5.0)
+        assert (maxx - minx) / (maxy - miny) == pytest.approx(2.0)
+        assert maxy - miny > 10.0
+
+    def test_local_name_accepts_workspace_prefix(self) -> None:
+        """Test qualified names of the workspace are stripped and others refused."""
+        assert local_name("topp:roads", "topp") == "roads"
+        assert local_name("roads", "topp") == "roads"
+        with pytest.raises(GeoServerError):
+            local_name("other:roads", "topp")
+
+    def test_renders_layers_in_order(self) -> None:
+        """Test one GetMap draws the layers in the given order, first at the bottom."""
+        client = _client()
+
+        image = render_composition(client, "topp", ["roads", "topp:landuse"], 5000, 300)
+
+        assert image == b"png"
+        args, kwargs = client.get_map.call_args
+        assert args[:2] == ("topp", ["roads", "landuse"])
+        assert args[3:] == (MAX_SIZE, 300)
+        assert kwargs["srs"] == "EPSG:4326"
+
+    def test_needs_layers(self) -> None:
+        """Test an empty group isn't drawn."""
+        client = _client()
+
+        with pytest.raises(GeoServerError):
+            render_composition(client, "topp", [], 512, 320)
+        client.get_map.assert_not_called()
+
+
+class TestGetMapLayers:
+    """Tests for GetMap requests drawing several layers."""
+
+    def test_joins_layers_bottom_to_top(self, make_geoserver_client) -> None:
+        """Test a list of layers becomes one comma separated LAYERS parameter."""
+        client = make_geoserver_client()
+        response = MagicMock(status_code=200, headers={"content-type": "image/png"}, content=b"x")
+        client._ows_request = MagicMock(return_value=response)
+
+        client.get_map("topp", ["landuse", "roads"], (0, 0, 1, 1), 10, 10)
+
+        params = client._ows_request.call_args.kwargs["params"]
+        assert params["layers"] == "topp:landuse,topp:roads"
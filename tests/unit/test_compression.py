@@ -0,0 +1,92 @@
+"""Unit tests for HTTP compression handling."""
+
+import gzip
+from unittest.mock import MagicMock
+
+from apps.core.managers import compression_headers
+from apps.geoserver.client import COMPRESS_MIN_BYTES
+
+
+def _responses(*codes: int) -> list[MagicMock]:
+    return [MagicMock(status_code=code) for code in codes]
+
+
+class TestCompression:
+    """Tests for request and response compression."""
+
+    def test_accept_encoding_toggle(self) -> None:
+        """Test response compression can be switched off."""
+        assert compression_headers(True) == {"Accept-Encoding": "gzip, deflate"}
+        assert compression_headers(False) == {"Accept-Encoding": "identity"}
+
+    def test_small_bodies_sent_uncompressed(self, make_geoserver_client) -> None:
+        """Test bodies below the threshold aren't compressed."""
+        client = make_geoserver_client()
+        client._request.side_effect = _responses(200)
+
+        client._put_compressed("/rest/styles/a.sld", b"<sld/>", {})
+
+        kwargs = client._request.call_args.kwargs
+        assert kwargs["content"] == b"<sld/>"
+        assert "Content-Encoding" not in kwargs["headers"]
+
+    def test_large_bodies_gzipped(self, make_geoserver_client) -> None:
+        """Test large bodies are sent gzip-encoded."""
+        body = b"x" * COMPRESS_MIN_BYTES
+        client = make_geoserver_client()
+        client._request.side_effect = _responses(200)
+
+        client._put_compressed(
+            "/rest/styles/a.sld", body, {"Content-Type": "application/vnd.ogc.sld+xml"}
+        )
+
+        kwargs = client._request.call_args.kwargs
+        assert gzip.decompress(kwargs["content"]) == body
+        assert kwargs["headers"]["Content-Encoding"] == "gzip"
+        assert kwargs["headers"]["Content-Type"] == "application/vnd.ogc.sld+xml"
+
+    def test_falls_back_when_server_rejects_gzip(self, make_geoserver_client) -> None:
+        """Test an uncompressed retry disables compression if it succeeds."""
+        body = b"x" * COMPRESS_MIN_BYTES
+        client = make_geoserver_client()
+        client._request.side_effect = _responses(415, 200)
+
+        response = client._put_compressed("/rest/styles/a.sld", body, {})
+
+        assert response.status_code == 200
+        assert client._request.call_args.kwargs["content"] == body
+        assert client._gzip_uploads is False
+
+    def test_genuine_errors_keep_compression(self, make_geoserver_client) -> None:
+        """Test compression stays on when the body itself is invalid."""
+        body = b"x" * COMPRESS_MIN_BYTES
+        client = make_geoserver_client()
+        client._request.side_effect = _responses(400, 400)
+
+        response = client._put_compressed("/rest/styles/a.sld", body, {})
+
+        assert response.status_code == 400
+        assert client._gzip_uploads is True
+
+    def test_other_failures_not_resent(self, make_geoserver_client) -> None:
+        """Test failures other than a refused encoding are sent once and keep compression."""
+        body = b"x" * COMPRESS_MIN_BYTES
+        for status in (403, 409, 500):
+            client = make_geoserver_client()
+            client._request.side_effect = _responses(status, 200)
+
+            response = client._put_compressed("/rest/styles/a.sld", body, {})
+
+            assert response.status_code == status
+            client._request.assert_called_once()
+            assert client._gzip_uploads is True
+
+    def test_disabled_connection_never_compresses(self, make_geoserver_client) -> None:
+        """Test the per-connection toggle disables upload compression."""
+        body = b"x" * COMPRESS_MIN_BYTES
+        client = make_geoserver_client(http_compression=False)
+        client._request.side_effect = _responses(200)
+
+        client._put_compressed("/rest/styles/a.sld", body, {})
+
+        assert client._request.call_args.kwargs["content"] == body
@@ -5,10 +5,17 @@ Tests the ConfigManager singleton and all connection types.
 
 import json
 import os
+from typing import Any
+from unittest.mock import patch
 
 import pytest
 
 from apps.core.config import (
+    CONFIG_DIR,
+    CONFIG_ENV,
+    DEFAULT_PROFILE,
+    PLAIN_ENV,
+    PROFILE_ENV,
     Config,
     ConfigManager,
     Connection,
@@ -22,7 +29,9 @@ from apps.core.config import (
     SavedQuery,
     SyncConfiguration,
     SyncOptions,
+    apply_config_args,
 )
+from apps.core.config_migrations import CONFIG_SCHEMA_VERSION, migrate_config
 
 
 class TestConnection:
@@ -369,3 +378,128 @@ class TestQGISProject:
         )
         assert project.name == "test_project.qgz"
         assert project.title == "Test Project"
+
+
+class TestConfigMigration:
+    """Tests for config schema versioning."""
+
+    def test_migrate_unversioned_config(self) -> None:
+        """Test Go-style null lists are upgraded and the version is set."""
+        data = migrate_config({"connections": None, "theme": "dark"})
+        assert data == {"connections": [], "theme": "dark", "schemaVersion": CONFIG_SCHEMA_VERSION}
+
+    def test_newer_config_untouched(self) -> None:
+        """Test a config from a newer version is not downgraded."""
+        data = {"schemaVersion": CONFIG_SCHEMA_VERSION + 1, "connections": None}
+        assert migrate_config(dict(data)) == data
+
+    def test_load_migrates_and_backs_up(
+        self, config_manager: ConfigManager, sample_connection: Connection
+    ) -> None:
+        """Test loading an old config keeps connections and writes a backup."""
+        path = config_manager._config_path()
+        os.makedirs(os.path.dirname(path), exist_ok=True)
+        old = {"connections": [sample_connection.model_dump()], "s3_connections": None}
+        with open(path, "w") as f:
+            json.dump(old, f)
+
+        config = config_manager.reload()
+
+        assert [c.id for c in config.connections] == [sample_connection.id]
+        assert config.schema_version == CONFIG_SCHEMA_VERSION
+        with open(f"{path}.v0.bak") as f:
+            assert json.load(f) == old
+        with open(path) as f:
+            assert json.load(f)["schemaVersion"] == CONFIG_SCHEMA_VERSION
+
+    def test_saved_config_is_versioned(self, config_manager: ConfigManager) -> None:
+        """Test new config files carry the current schema version."""
+        config_manager.save()
+        with open(config_manager._config_path()) as f:
+            assert json.load(f)["schemaVersion"] == CONFIG_SCHEMA_VERSION
+
+
+class TestConfigProfiles:
+    """Tests for named config profiles."""
+
+    def test_default_profile(self, config_manager: ConfigManager) -> None:
+        """Test the default profile uses config.json."""
+        assert config_manager.profile == DEFAULT_PROFILE
+        assert config_manager._config_path().endswith(os.path.join(CONFIG_DIR, "config.json"))
+
+    def test_profiles_keep_own_connections(
+        self, config_manager: ConfigManager, sample_connection: Connection
+    ) -> None:
+        """Test connections added to one profile don't show in another."""
+        config_manager.use_profile("work")
+        config_manager.add_connection(sample_connection)
+        assert config_manager._config_path().endswith(os.path.join("profiles", "work.json"))
+
+        config_manager.use_profile(DEFAULT_PROFILE)
+        assert config_manager.list_connections() == []
+        assert config_manager.list_profiles() == [DEFAULT_PROFILE, "work"]
+
+        config_manager.use_profile("work")
+        assert [c.id for c in config_manager.list_connections()] == [sample_connection.id]
+
+    def test_invalid_profile_name(self, config_manager: ConfigManager) -> None:
+        """Test profile names can't escape the profiles directory."""
+        with pytest.raises(ValueError):
+            config_manager.use_profile("../secrets")
+
+    def test_profile_from_environment(
+        self, config_manager: ConfigManager, monkeypatch: pytest.MonkeyPatch
+    ) -> None:
+        """Test the profile can be picked with an environment variable."""
+        monkeypatch.setenv(PROFILE_ENV, "personal")
+        assert config_manager.profile == "personal"
+        assert config_manager._config_path().endswith(os.path.join("profiles", "personal.json"))
+
+    def test_config_args(
+        self, config_manager: ConfigManager, tmp_path: Any, monkeypatch: pytest.MonkeyPatch
+    ) -> None:
+        """Test --config is taken out of the command line and applied."""
+        monkeypatch.delenv(CONFIG_ENV, raising=False)
+        path = str(tmp_path / "client-x.json")
+
+        with patch("apps.core.config.config_manager", config_manager):
+            argv = apply_config_args(["manage.py", "runserver", f"--config={path}"])
+
+        assert argv == ["manage.py", "runserver"]
+        assert config_manager._config_path() == path
+        assert config_manager.profile == ""
+        assert os.environ[CONFIG_ENV] == path
+
+    def test_plain_arg(
+        self, config_manager: ConfigManager, monkeypatch: pytest.MonkeyPatch
+    ) -> None:
+        """Test --plain is taken out of the command line and turns colors off."""
+        # Set, not deleted, so that both are restored afterwards
+        monkeypatch.setenv(PLAIN_ENV, "")
+        monkeypatch.setenv("DJANGO_COLORS", "")
+        assert not config_manager.plain_mode
+
+        with patch("apps.core.config.config_manager", config_manager):
+            argv = apply_config_args(["manage.py", "operations", "--plain"])
+
+        assert argv == ["manage.py", "operations"]
+        assert config_manager.plain_mode
+        assert os.environ["DJANGO_COLORS"] == "nocolor"
+
+
+class TestTuiLayout:
+    """Tests for the saved TUI panel layout."""
+
+    def test_layout_persisted(self, config_manager: ConfigManager) -> None:
+        """Test the split, collapsed panel and expanded paths survive a reload."""
+        layout = config_manager.config.tui_layout
+        layout.split_width = 52
+        layout.collapsed = "details"
+        layout.expanded_paths["conn_1"] = ["topp", "nurc"]
+        config_manager.save()
+
+        reloaded = config_manager.reload().tui_layout
+
+        assert reloaded.split_width == 52
+        assert reloaded.collapsed == "details"
+        assert reloaded.expanded_paths == {"conn_1": ["topp", "nurc"]}
@@ -0,0 +1,51 @@
+"""Unit tests for the confirmation policy of destructive operations."""
+
+from apps.core.config import Connection
+from apps.core.confirmation import (
+    batch_confirmation_text,
+    confirmation_matches,
+    requires_typed_confirmation,
+)
+
+
+def _conn(environment: str = "", confirm_policy: str = "auto") -> Connection:
+    return Connection(
+        name="gs",
+        url="http://gs/geoserver",
+        username="admin",
+        password="x",
+        environment=environment,
+        confirm_policy=confirm_policy,
+    )
+
+
+class TestConfirmationPolicy:
+    """Tests for deciding when the name must be typed."""
+
+    def test_auto_types_on_production(self) -> None:
+        """Test the default policy only asks production connections."""
+        assert requires_typed_confirmation(_conn("production"))
+        assert requires_typed_confirmation(_conn("Production"))
+        assert not requires_typed_confirmation(_conn("staging"))
+        assert not requires_typed_confirmation(_conn())
+
+    def test_explicit_policies(self) -> None:
+        """Test a connection's own policy overrides its environment."""
+        assert requires_typed_confirmation(_conn("development", "typed"))
+        assert not requires_typed_confirmation(_conn("production", "simple"))
+
+
+class TestTypedText:
+    """Tests for checking typed confirmations."""
+
+    def test_matches(self) -> None:
+        """Test only the exact name, give or take surrounding spaces, confirms."""
+        assert confirmation_matches("topp", " topp ")
+        assert not confirmation_matches("topp", "Topp")
+        assert not confirmation_matches("topp", "top")
+        assert not confirmation_matches("", "")
+
+    def test_batch_text(self) -> None:
+        """Test batch actions are confirmed with the action and layer count."""
+        assert batch_confirmation_text("delete", 3) == "delete 3 layers"
+        assert batch_confirmation_text("truncate", 1) == "truncate 1 layer"
@@ -0,0 +1,79 @@
+"""Unit tests for connection groups, environments and badges."""
+
+from apps.core.config import Connection
+from apps.core.connection_groups import (
+    badge_color,
+    group_connections,
+    list_groups,
+    normalize_environment,
+    validate_organization,
+)
+from apps.core.connection_providers import ENV_CONNECTION_ID, EnvConnectionProvider
+
+
+def _conn(name: str, group: str = "", **kwargs) -> Connection:
+    return Connection(name=name, url="http://gs/geoserver", group=group, **kwargs)
+
+
+class TestGroupConnections:
+    """Tests for grouping connections into folders."""
+
+    def test_groups_sorted_and_ungrouped_last(self) -> None:
+        """Test groups are sorted case-insensitively and keep connection order."""
+        connections = [
+            _conn("loose"),
+            _conn("b-prod", "client b"),
+            _conn("a-dev", "Client A"),
+            _conn("b-dev", "client b"),
+            _conn("a-prod", " Client A "),
+        ]
+
+        groups = group_connections(connections)
+
+        assert [(name, [c.name for c in members]) for name, members in groups] == [
+            ("Client A", ["a-dev", "a-prod"]),
+            ("client b", ["b-prod", "b-dev"]),
+            ("", ["loose"]),
+        ]
+        assert list_groups(connections) == ["Client A", "client b"]
+
+    def test_no_groups(self) -> None:
+        """Test ungrouped connections stay in a single unnamed group."""
+        groups = group_connections([_conn("one"), _conn("two")])
+
+        assert [name for name, _ in groups] == [""]
+
+
+class TestBadges:
+    """Tests for badge colors and validation."""
+
+    def test_own_color_wins(self) -> None:
+        """Test a connection's color overrides its environment's."""
+        assert badge_color(_conn("x", environment="production")) == "#e53e3e"
+        assert badge_color(_conn("x", environment="production", color="#123456")) == "#123456"
+        assert badge_color(_conn("x", environment="qa")) == ""
+
+    def test_validation(self) -> None:
+        """Test colors must be #rrggbb and badges short."""
+        assert validate_organization("#a1B2c3", "🚀") == []
+        assert validate_organization("", "") == []
+        assert len(validate_organization("red", "TOOLONG")) == 2
+
+    def test_normalize_environment(self) -> None:
+        """Test environment tags are trimmed and lower-cased."""
+        assert normalize_environment(" Production ") == "production"
+
+
+class TestEnvEnvironment:
+    """Tests for tagging the environment-defined connection."""
+
+    def test_environment_variable(self) -> None:
+        """Test GSCLIENT_ENVIRONMENT tags the connection."""
+        provider = EnvConnectionProvider(
+            {"GSCLIENT_URL": "http://gs/geoserver", "GSCLIENT_ENVIRONMENT": "Staging"}
+        )
+
+        conn = provider.get(ENV_CONNECTION_ID)
+
+        assert conn is not None
+        assert conn.environment == "staging"
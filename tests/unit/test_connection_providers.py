@@ -0,0 +1,82 @@
+"""Unit tests for resolving connections from flags, environment and config."""
+
+import json
+
+import pytest
+
+from apps.core.config import ConfigManager, Connection
+from apps.core.connection_providers import (
+    ENV_CONNECTION_ID,
+    FLAG_CONNECTION_ID,
+    ConnectionChain,
+    EnvConnectionProvider,
+    FlagConnectionProvider,
+)
+
+
+class TestEnvConnectionProvider:
+    """Tests for connections defined by environment variables."""
+
+    def test_connection_from_environment(self) -> None:
+        """Test GSCLIENT_* variables define a connection."""
+        provider = EnvConnectionProvider(
+            {
+                "GSCLIENT_URL": "http://gs:8080/geoserver",
+                "GSCLIENT_USER": "admin",
+                "GSCLIENT_PASSWORD": "secret",
+            }
+        )
+
+        conn = provider.get(ENV_CONNECTION_ID)
+
+        assert conn is not None
+        assert (conn.url, conn.username, conn.password) == (
+            "http://gs:8080/geoserver",
+            "admin",
+            "secret",
+        )
+        assert conn.source == "env"
+
+    def test_no_url_no_connection(self) -> None:
+        """Test nothing is defined without GSCLIENT_URL."""
+        assert EnvConnectionProvider({"GSCLIENT_USER": "admin"}).connections() == []
+
+
+class TestConnectionChain:
+    """Tests for resolving connections in priority order."""
+
+    def test_earlier_provider_wins(self) -> None:
+        """Test a flag connection shadows an environment one with the same ID."""
+        flag = FlagConnectionProvider("http://flag/geoserver")
+        flag.connection.id = ENV_CONNECTION_ID
+        env = EnvConnectionProvider({"GSCLIENT_URL": "http://env/geoserver"})
+
+        chain = ConnectionChain([flag, env])
+
+        assert [c.url for c in chain.list()] == ["http://flag/geoserver"]
+        assert chain.get(ENV_CONNECTION_ID).url == "http://flag/geoserver"
+        assert chain.get("missing") is None
+
+
+class TestConfigManagerConnections:
+    """Tests for unsaved connections in the config manager."""
+
+    def test_unsaved_connections_listed_not_saved(
+        self,
+        config_manager: ConfigManager,
+        sample_connection: Connection,
+        monkeypatch: pytest.MonkeyPatch,
+    ) -> None:
+        """Test flag and env connections are listed but never written to disk."""
+        monkeypatch.setenv("GSCLIENT_URL", "http://env/geoserver")
+        config_manager.set_flag_connection("http://flag/geoserver", "admin", "geoserver")
+        config_manager.add_connection(sample_connection)
+
+        ids = [c.id for c in config_manager.list_connections()]
+        assert ids == [FLAG_CONNECTION_ID, ENV_CONNECTION_ID, sample_connection.id]
+        assert config_manager.get_connection(FLAG_CONNECTION_ID).username == "admin"
+
+        with open(config_manager._config_path()) as f:
+            saved = json.load(f)
+        assert [c["id"] for c in saved["connections"]] == [sample_connection.id]
+        assert "source" not in saved["connections"][0]
@@ -0,0 +1,198 @@
+"""Unit tests for the CQL filter builder."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.geoserver.attributes import read_attribute_table
+from apps.geoserver.cql import (
+    KIND_BOOLEAN,
+    KIND_DATE,
+    KIND_GEOMETRY,
+    KIND_NUMBER,
+    KIND_TEXT,
+    MATCH_ANY,
+    Condition,
+    attribute_kind,
+    build_cql,
+    combine_filters,
+    condition_cql,
+    filter_attributes,
+    suggest_values,
+)
+from apps.geoserver.schema import SchemaAttribute, parse_feature_type
+
+PROPERTIES = [
+    {"name": "the_geom", "type": "gml:MultiPolygon", "localType": "MultiPolygon"},
+    {"name": "name", "type": "xsd:string", "localType": "string"},
+    {"name": "population", "type": "xsd:int", "localType": "int"},
+    {"name": "founded", "type": "xsd:date", "localType": "date"},
+    {"name": "capital", "type": "xsd:boolean", "localType": "boolean"},
+]
+
+SCHEMA = parse_feature_type({"featureTypes": [{"typeName": "states", "properties": PROPERTIES}]})
+
+ATTRIBUTES = {a.name: a for a in filter_attributes(SCHEMA)}
+
+
+def _client(*values: object) -> MagicMock:
+    client = MagicMock()
+    client.describe_feature_type.return_value = SCHEMA
+    client.get_features.return_value = {
+        "features": [{"properties": {"name": v, "capital": v}} for v in values]
+    }
+    return client
+
+
+class TestAttributeKinds:
+    """Tests for classifying layer attributes."""
+
+    def test_kinds(self) -> None:
+        """Test each attribute type gets the operators that suit it."""
+        assert {name: a.kind for name, a in ATTRIBUTES.items()} == {
+            "the_geom": KIND_GEOMETRY,
+            "name": KIND_TEXT,
+            "population": KIND_NUMBER,
+            "founded": KIND_DATE,
+            "capital": KIND_BOOLEAN,
+        }
+        assert ATTRIBUTES["the_geom"].operators == ["BBOX"]
+        assert "ILIKE" not in ATTRIBUTES["population"].operators
+
+    def test_missing_local_type(self) -> None:
+        """Test an attribute without a local type is treated as text."""
+        assert attribute_kind(SchemaAttribute("x", "xsd:anyType", "")) == KIND_TEXT
+
+    def test_required_attributes(self) -> None:
+        """Test null tests aren't offered on attributes that can't be null."""
+        prop = {"name": "id", "localType": "int", "nillable": False}
+        schema = parse_feature_type({"featureTypes": [{"properties": [prop]}]})
+
+        assert "IS NULL" not in filter_attributes(schema)[0].operators
+
+
+class TestConditions:
+    """Tests for turning conditions into CQL."""
+
+    def test_comparisons(self) -> None:
+        """Test numbers stay bare and text is quoted."""
+        assert condition_cql(Condition("population", ">=", " 1000 "), ATTRIBUTES["population"]) == (
+            '"population" >= 1000'
+        )
+        assert condition_cql(Condition("name", "=", "O'Hara"), ATTRIBUTES["name"]) == (
+            "\"name\" = 'O''Hara'"
+        )
+        assert condition_cql(Condition("capital", "=", "TRUE"), ATTRIBUTES["capital"]) == (
+            '"capital" = true'
+        )
+
+    def test_like_matches_anywhere(self) -> None:
+        """Test LIKE wraps the value in wildcards unless it has its own."""
+        name = ATTRIBUTES["name"]
+
+        assert condition_cql(Condition("name", "ilike", "park"), name) == (
+            "\"name\" ILIKE '%park%'"
+        )
+        assert condition_cql(Condition("name", "LIKE", "Park%"), name) == "\"name\" LIKE 'Park%'"
+
+    def test_ranges_and_lists(self) -> None:
+        """Test BETWEEN, IN, BBOX and IS NULL."""
+        assert condition_cql(
+            Condition("population", "BETWEEN", "10 and 20"), ATTRIBUTES["population"]
+        ) == '"population" BETWEEN 10 AND 20'
+        assert condition_cql(Condition("name", "IN", "a, b,"), ATTRIBUTES["name"]) == (
+            "\"name\" IN ('a', 'b')"
+        )
+        assert condition_cql(
+            Condition("the_geom", "BBOX", "-10, 35, 30, 60"), ATTRIBUTES["the_geom"]
+        ) == 'BBOX("the_geom", -10, 35, 30, 60)'
+        assert condition_cql(Condition("name", "IS NULL"), ATTRIBUTES["name"]) == (
+            '"name" IS NULL'
+        )
+
+    def test_bad_conditions(self) -> None:
+        """Test values that don't fit the attribute are refused."""
+        cases = [
+            (Condition("population", ">", "many"), "needs a number"),
+            (Condition("capital", "=", "yes"), "true or false"),
+            (Condition("name", "<", "a"), "doesn't apply"),
+            (Condition("name", "=", " "), "needs a value"),
+            (Condition("population", "BETWEEN", "10"), "two values"),
+            (Condition("the_geom", "BBOX", "1, 2, 3"), "minx, miny"),
+        ]
+        for condition, message in cases:
+            with pytest.raises(ValueError, match=message):
+                condition_cql(condition, ATTRIBUTES[condition.attribute])
+
+
+class TestBuildCql:
+    """Tests for combining conditions and filters."""
+
+    def test_match_all_and_any(self) -> None:
+        """Test conditions are ANDed or ORed."""
+        conditions = [Condition("population", ">", "5"), Condition("name", "=", "x")]
+        attributes = list(ATTRIBUTES.values())
+
+        assert build_cql(conditions, attributes) == "\"population\" > 5 AND \"name\" = 'x'"
+        assert build_cql(conditions, attributes, MATCH_ANY) == (
+            "\"population\" > 5 OR \"name\" = 'x'"
+        )
+        assert build_cql([], attributes) is None
+
+    def test_unknown_attribute(self) -> None:
+        """Test conditions on missing attributes are refused."""
+        with pytest.raises(ValueError, match="Unknown attribute"):
+            build_cql([Condition("area", ">", "1")], list(ATTRIBUTES.values()))
+
+    def test_combine_filters(self) -> None:
+        """Test filters are parenthesised when there is more than one."""
+        assert combine_filters(None, " ", "a = 1") == "a = 1"
+        assert combine_filters("a = 1 OR b = 2", "c = 3") == "(a = 1 OR b = 2) AND (c = 3)"
+        assert combine_filters(None, "") is None
+
+    def test_attribute_table_applies_filter(self) -> None:
+        """Test the attribute table ANDs a built filter with its column filters."""
+        client = _client()
+        client.get_features.return_value = {"features": []}
+
+        read_attribute_table(
+            client, "topp", "states", filters={"name": "a"}, cql="population > 5 OR capital = true"
+        )
+
+        assert client.get_features.call_args.kwargs["cql_filter"] == (
+            "(population > 5 OR capital = true) AND (\"name\" ILIKE '%a%')"
+        )
+
+
+class TestSuggestValues:
+    """Tests for suggesting attribute values."""
+
+    def test_distinct_values(self) -> None:
+        """Test repeated and missing values are dropped and the sample is sorted."""
+        client = _client("Ann", None, "Ann", "Bob", "Cy")
+
+        assert suggest_values(client, "topp", "states", "name", limit=2) == ["Ann", "Bob"]
+        kwargs = client.get_features.call_args.kwargs
+        assert kwargs["sort_by"] == "name ASC"
+        assert kwargs["property_names"] == ["name"]
+        assert kwargs["cql_filter"] is None
+
+    def test_text_prefix(self) -> None:
+        """Test a text prefix narrows the WFS request with wildcards escaped."""
+        client = _client("50%_off", "500")
+
+        assert suggest_values(client, "topp", "states", "name", prefix="50%") == ["50%_off"]
+        assert client.get_features.call_args.kwargs["cql_filter"] == (
+            "\"name\" ILIKE '50\\%%'"
+        )
+
+    def test_booleans_lowercased(self) -> None:
+        """Test boolean values read like CQL literals."""
+        client = _client(True, False)
+
+        assert suggest_values(client, "topp", "states", "capital") == ["true", "false"]
+
+    def test_geometry_refused(self) -> None:
+        """Test geometry attributes have no suggestions."""
+        with pytest.raises(ValueError, match="Geometry"):
+            suggest_values(_client(), "topp", "states", "the_geom")
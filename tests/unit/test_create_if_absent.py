@@ -0,0 +1,87 @@
+"""Unit tests for creating GeoServer resources only if they don't exist yet."""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.capabilities import build_capabilities
+from apps.geoserver.client import CreateResult
+
+
+def _responses(*statuses: int) -> list[MagicMock]:
+    """Responses with the given statuses, for requests to answer in turn."""
+    return [MagicMock(status_code=s, text="") for s in statuses]
+
+
+class TestCreateIfAbsent:
+    """Test the if_absent flag of the client's create calls."""
+
+    def test_existing_workspace_kept(self, make_geoserver_client) -> None:
+        """Test an existing workspace is reported without being posted."""
+        client = make_geoserver_client()
+        client._request.side_effect = _responses(200)
+
+        with patch("apps.geoserver.client.managed_state") as state:
+            result = client.create_workspace("topp", if_absent=True)
+
+        assert result == CreateResult.ALREADY_EXISTS
+        client._request.assert_called_once_with("GET", "/rest/workspaces/topp.json")
+        state.add.assert_not_called()
+
+    def test_missing_workspace_created(self, make_geoserver_client) -> None:
+        """Test a workspace that isn't there yet is created and managed."""
+        client = make_geoserver_client()
+        client._request.side_effect = _responses(404, 201)
+
+        with patch("apps.geoserver.client.managed_state") as state:
+            with patch("apps.geoserver.client.hooks"):
+                result = client.create_workspace("topp", if_absent=True)
+
+        assert result == CreateResult.CREATED
+        assert client._request.call_args.args == ("POST", "/rest/workspaces.json")
+        state.add.assert_called_once()
+
+    def test_created_meanwhile(self, make_geoserver_client) -> None:
+        """Test a conflict on a resource created since the check isn't an error."""
+        client = make_geoserver_client()
+        client._request.side_effect = _responses(404, 500, 200)
+        client._capabilities = build_capabilities("2.24.0")
+
+        with patch("apps.geoserver.client.managed_state") as state:
+            result = client.create_datastore("topp", "roads", {"dbtype": "postgis"}, if_absent=True)
+
+        assert result == CreateResult.ALREADY_EXISTS
+        assert client._request.call_args.args == (
+            "GET",
+            "/rest/workspaces/topp/datastores/roads.json",
+        )
+        state.add.assert_not_called()
+
+    def test_other_failures_raise(self, make_geoserver_client) -> None:
+        """Test failures that don't leave the resource behind still raise."""
+        client = make_geoserver_client()
+        client._request.side_effect = _responses(404, 500, 404)
+
+        with pytest.raises(GeoServerError, match="Failed to create layer group"):
+            client.create_layergroup("basemap", ["topp:roads"], workspace="topp", if_absent=True)
+
+    def test_without_flag(self, make_geoserver_client) -> None:
+        """Test a conflict fails as before without the flag, with no lookup."""
+        client = make_geoserver_client()
+        client._request.side_effect = _responses(500)
+
+        with pytest.raises(GeoServerError, match="Failed to create style"):
+            client.create_style("roads", "<sld/>", workspace="topp")
+
+        client._request.assert_called_once()
+
+    def test_existing_style_keeps_content(self, make_geoserver_client) -> None:
+        """Test an existing global style is found and its content left alone."""
+        client = make_geoserver_client()
+        client._request.side_effect = _responses(200)
+
+        result = client.create_style("roads", "<sld/>", if_absent=True)
+
+        assert result == CreateResult.ALREADY_EXISTS
+        client._request.assert_called_once_with("GET", "/rest/styles/roads.json")
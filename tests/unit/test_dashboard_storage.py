@@ -0,0 +1,170 @@
+"""Unit tests for the dashboard storage figures."""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.dashboard.storage import connection_storage
+from apps.geoserver.datadir import directory_size
+from apps.gwc.usage import count_seed_tasks, parse_disk_quota, quota_bytes
+
+
+class TestDiskQuota:
+    """Tests for reading GWC disk quota documents."""
+
+    def test_quota_bytes(self) -> None:
+        """Test both quota notations are converted to bytes."""
+        assert quota_bytes({"value": "500", "units": "MiB"}) == 500 * 1024**2
+        assert quota_bytes({"value": 1.5, "units": "GiB"}) == int(1.5 * 1024**3)
+        assert quota_bytes({"bytes": "2048"}) == 2048
+        assert quota_bytes(None) is None
+        assert quota_bytes({"value": "lots"}) is None
+
+    def test_global_quota(self) -> None:
+        """Test the global limit and usage give a percentage."""
+        usage = parse_disk_quota(
+            {
+                "enabled": True,
+                "globalQuota": {"value": 1, "units": "GiB"},
+                "globalUsage": {"bytes": 256 * 1024**2},
+                "globalExpirationPolicyName": "LFU",
+            }
+        )
+
+        assert usage.enabled
+        assert usage.limit_bytes == 1024**3
+        assert usage.used_pct == 25.0
+        assert usage.to_dict()["expirationPolicy"] == "LFU"
+
+    def test_layer_usage_adds_up(self) -> None:
+        """Test per-layer usage is summed when the global usage isn't reported."""
+        usage = parse_disk_quota(
+            {
+                "enabled": "true",
+                "globalQuota": {"value": 100, "units": "MiB"},
+                "layerQuotas": {
+                    "LayerQuota": [
+                        {
+                            "layer": "topp:roads",
+                            "quota": {"value": 10, "units": "MiB"},
+                            "usedQuota": {"value": 4, "units": "MiB"},
+                        },
+                        {"layer": "topp:states", "usedQuota": {"value": 6, "units": "MiB"}},
+                    ]
+                },
+            }
+        )
+
+        assert [layer.layer for layer in usage.layers] == ["topp:roads", "topp:states"]
+        assert usage.layers[0].limit_bytes == 10 * 1024**2
+        assert usage.used_bytes == 10 * 1024**2
+        assert usage.used_pct == 10.0
+
+    def test_usage_unknown(self) -> None:
+        """Test a quota without usage reports no percentage."""
+        usage = parse_disk_quota({"enabled": False, "globalQuota": {"value": 1, "units": "GiB"}})
+
+        assert not usage.enabled
+        assert usage.used_bytes is None
+        assert usage.used_pct is None
+
+
+class TestSeedTasks:
+    """Tests for counting seed tasks."""
+
+    def test_counts_by_state(self) -> None:
+        """Test running and pending tasks are counted and finished ones ignored."""
+        gwc_client = MagicMock()
+        gwc_client.list_seed_tasks.return_value = [
+            [10, 100, 5, 1, 1],
+            [0, 100, -1, 2, 0],
+            [100, 100, 0, 3, 2],
+            [20, 100, 8, 4, 1],
+        ]
+
+        counts = count_seed_tasks(gwc_client)
+
+        assert counts.to_dict() == {"running": 2, "pending": 1}
+
+
+def _resource_client(tree: dict) -> MagicMock:
+    """Client whose resource API serves a nested dict; ints are file sizes."""
+
+    def lookup(path: str):
+        node = tree
+        for part in filter(None, path.split("/")):
+            node = node[part]
+        return node
+
+    client = MagicMock()
+    client.list_resource_directory.side_effect = lambda path: list(lookup(path))
+    client.get_resource_info.side_effect = lambda path: (
+        {"type": "directory", "size": 0}
+        if isinstance(lookup(path), dict)
+        else {"type": "resource", "size": lookup(path)}
+    )
+    return client
+
+
+class TestDataDirectory:
+    """Tests for walking the data directory."""
+
+    def test_adds_up_files(self) -> None:
+        """Test file sizes are summed across folders."""
+        client = _resource_client(
+            {"global.xml": 100, "styles": {"point.sld": 50, "line.sld": 25}, "empty": {}}
+        )
+
+        size = directory_size(client)
+
+        assert (size.bytes, size.files, size.directories) == (175, 3, 3)
+        assert size.complete
+
+    def test_budget_gives_partial_size(self) -> None:
+        """Test the walk stops at the request budget and says so."""
+        client = _resource_client({"a": 1, "b": 2, "c": {"d": 3}})
+
+        size = directory_size(client, budget=3)
+
+        assert size.bytes == 3
+        assert not size.complete
+
+    def test_no_resource_api(self) -> None:
+        """Test a failing root listing is reported."""
+        client = MagicMock()
+        client.list_resource_directory.side_effect = GeoServerError("Not found", 404)
+
+        with pytest.raises(GeoServerError):
+            directory_size(client)
+
+
+class TestConnectionStorage:
+    """Tests for collecting a connection's storage figures."""
+
+    @patch("apps.dashboard.storage.get_gwc_client")
+    @patch("apps.dashboard.storage.get_geoserver_client")
+    def test_failures_are_per_figure(self, get_client, get_gwc) -> None:
+        """Test one unavailable figure doesn't hide the others."""
+        get_client.return_value = _resource_client({"global.xml": 10})
+        get_gwc.return_value.get_disk_quota.side_effect = GeoServerError("No disk quota", 404)
+        get_gwc.return_value.list_seed_tasks.return_value = []
+
+        storage = connection_storage("conn_1").to_dict()
+
+        assert storage["diskQuota"] is None
+        assert storage["diskQuotaError"] == "No disk quota"
+        assert storage["seedTasks"] == {"running": 0, "pending": 0}
+        assert storage["dataDirectory"]["bytes"] == 10
+
+    @patch("apps.dashboard.storage.get_gwc_client")
+    @patch("apps.dashboard.storage.get_geoserver_client")
+    def test_skip_data_directory(self, get_client, get_gwc) -> None:
+        """Test the data directory walk can be skipped."""
+        get_gwc.return_value.get_disk_quota.return_value = {"enabled": False}
+        get_gwc.return_value.list_seed_tasks.return_value = []
+
+        storage = connection_storage("conn_1", include_data_directory=False)
+
+        assert storage.data_directory is None
+        get_client.return_value.list_resource_directory.assert_not_called()
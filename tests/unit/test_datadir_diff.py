@@ -0,0 +1,123 @@
+"""Unit tests for comparing data directory configuration files around a change."""
+
+from unittest.mock import MagicMock
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.datadir_diff import DataDirWatch, compare_snapshots, take_snapshot
+
+
+def _client(files: dict[str, str]) -> MagicMock:
+    """A client reading a data directory holding the given files."""
+    client = MagicMock()
+
+    def children(folder):
+        prefix = f"{folder}/" if folder else ""
+        names = {p[len(prefix):].split("/")[0] for p in files if p.startswith(prefix)}
+        if not names:
+            raise GeoServerError("Not found", 404)
+        return sorted(names)
+
+    def info(path):
+        if path in files:
+            return {"type": "resource", "size": len(files[path])}
+        if any(p.startswith(f"{path}/") for p in files) or not path:
+            return {"type": "directory", "size": 0}
+        return {"type": "undefined", "size": 0}
+
+    client.list_resource_directory.side_effect = children
+    client.get_resource_info.side_effect = info
+    client.download_resource.side_effect = lambda path: files[path].encode()
+    return client
+
+
+FILES = {
+    "global.xml": "<global/>",
+    "workspaces/default.xml": "<workspace>topp</workspace>",
+    "workspaces/topp/workspace.xml": "<workspace><name>topp</name></workspace>",
+    "workspaces/topp/roads/datastore.xml": "<dataStore/>",
+    "workspaces/topp/roads/roads.shp": "binary",
+    "workspaces/topp/roads/roads/featuretype.xml": (
+        "<featureType>\n<title>Roads</title>\n</featureType>"
+    ),
+}
+
+
+class TestTakeSnapshot:
+    """Test reading the configuration files of some folders."""
+
+    def test_configuration_files(self):
+        snapshot = take_snapshot(_client(FILES), ["workspaces/topp"], shallow=["workspaces"])
+
+        assert sorted(snapshot.files) == [
+            "workspaces/default.xml",
+            "workspaces/topp/roads/datastore.xml",
+            "workspaces/topp/roads/roads/featuretype.xml",
+            "workspaces/topp/workspace.xml",
+        ]
+        assert snapshot.complete
+
+    def test_missing_folder(self):
+        snapshot = take_snapshot(_client(FILES), ["workspaces/nurc"])
+
+        assert snapshot.files == {}
+
+    def test_budget(self):
+        snapshot = take_snapshot(_client(FILES), ["workspaces/topp"], budget=3)
+
+        assert not snapshot.complete
+        assert len(snapshot.files) <= 2
+
+
+class TestCompareSnapshots:
+    """Test finding the files a change wrote."""
+
+    def test_changes(self):
+        before = take_snapshot(_client(FILES), ["workspaces/topp"])
+        after_files = dict(FILES)
+        after_files["workspaces/topp/roads/roads/featuretype.xml"] = (
+            "<featureType>\n<title>Main roads</title>\n</featureType>"
+        )
+        del after_files["workspaces/topp/roads/datastore.xml"]
+        after_files["workspaces/topp/styles/line.xml"] = "<style/>"
+        after = take_snapshot(_client(after_files), ["workspaces/topp"])
+
+        changes = compare_snapshots(before, after)
+
+        assert [(c.path, c.change) for c in changes] == [
+            ("workspaces/topp/roads/datastore.xml", "removed"),
+            ("workspaces/topp/roads/roads/featuretype.xml", "changed"),
+            ("workspaces/topp/styles/line.xml", "added"),
+        ]
+        diff = changes[1].diff
+        assert "-<title>Roads</title>" in diff
+        assert "+<title>Main roads</title>" in diff
+        assert diff.startswith("--- a/workspaces/topp/roads/roads/featuretype.xml")
+
+
+class TestDataDirWatch:
+    """Test snapshots taken around a change."""
+
+    def test_watch(self):
+        files = dict(FILES)
+        client = _client(files)
+        watch = DataDirWatch(client, ["workspaces/topp"], shallow=["workspaces"])
+
+        watch.start()
+        files["workspaces/default.xml"] = "<workspace>nurc</workspace>"
+        diff = watch.finish()
+
+        assert [c.path for c in diff.changes] == ["workspaces/default.xml"]
+        assert diff.to_dict()["folders"] == ["workspaces/topp", "workspaces"]
+        assert diff.error is None
+
+    def test_without_resource_api(self):
+        client = MagicMock()
+        client.get_resource_info.side_effect = GeoServerError("Requires 2.9", 501)
+        watch = DataDirWatch(client, ["workspaces/topp"])
+
+        watch.start()
+        diff = watch.finish()
+
+        assert diff.error == "Requires 2.9"
+        assert diff.changes == []
+        client.list_resource_directory.assert_not_called()
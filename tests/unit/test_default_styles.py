@@ -0,0 +1,225 @@
+"""Unit tests for default styles of newly published layers.
+
+Tests choosing a workspace's style by geometry type, setting it when a
+feature type or coverage is published and checking edited rules.
+"""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.config import DefaultStyleRule
+from apps.geoserver.default_styles import (
+    feature_type_geometry,
+    parse_rules,
+    rules_for,
+    style_for,
+)
+
+RULES = [
+    DefaultStyleRule(workspace="*", point="poi", line="line"),
+    DefaultStyleRule(workspace="roads", line="roads:road_lines", raster="dem"),
+    DefaultStyleRule(workspace="roads", polygon="areas", connections=["other"]),
+]
+
+
+def _featuretype(*bindings: str) -> dict:
+    return {
+        "attributes": {
+            "attribute": [{"name": f"a{i}", "binding": b} for i, b in enumerate(bindings)]
+        }
+    }
+
+
+class TestStyleFor:
+    """Test picking a workspace's style for a geometry type."""
+
+    def test_workspace_before_any(self) -> None:
+        """Test a workspace's own rule wins, falling back to "*" per geometry."""
+        rules = rules_for("conn", "roads", RULES)
+
+        assert style_for(rules, "line") == "roads:road_lines"
+        assert style_for(rules, "point") == "poi"
+        assert style_for(rules, "raster") == "dem"
+
+    def test_connections(self) -> None:
+        """Test rules limited to other connections are left out."""
+        assert style_for(rules_for("conn", "roads", RULES), "polygon") is None
+        assert style_for(rules_for("other", "roads", RULES), "polygon") == "areas"
+
+    def test_other_workspace(self) -> None:
+        """Test a workspace without a rule only gets those for "*"."""
+        rules = rules_for("conn", "topp", RULES)
+
+        assert style_for(rules, "line") == "line"
+        assert style_for(rules, "raster") is None
+        assert style_for(rules, None) is None
+
+
+class TestFeatureTypeGeometry:
+    """Test reading the geometry type of a feature type."""
+
+    def test_bindings(self) -> None:
+        """Test JTS classes of current and old GeoServer releases."""
+        ft = _featuretype("java.lang.String", "org.locationtech.jts.geom.MultiPolygon")
+
+        assert feature_type_geometry(ft) == "polygon"
+        assert feature_type_geometry(_featuretype("com.vividsolutions.jts.geom.Point")) == "point"
+
+    def test_generic_or_none(self) -> None:
+        """Test generic geometries and tables without one have no type."""
+        assert feature_type_geometry(_featuretype("org.locationtech.jts.geom.Geometry")) is None
+        assert feature_type_geometry({}) is None
+
+
+def _store(before: tuple[str, ...] = (), after: tuple[str, ...] = ()):
+    """Answer requests like a store of line feature types.
+
+    The store lists the feature types in before until a file is
+    uploaded into it, and those in after from then on.
+    """
+    names = list(before)
+
+    def request(method: str, path: str, **kwargs) -> MagicMock:
+        if method != "GET":
+            if "/file." in path:
+                names[:] = after
+            return MagicMock(status_code=201)
+        response = MagicMock(status_code=200)
+        if path.endswith("/featuretypes.json"):
+            response.json.return_value = {
+                "featureTypes": {"featureType": [{"name": name} for name in names]}
+            }
+        else:
+            response.json.return_value = {
+                "featureType": _featuretype("org.locationtech.jts.geom.MultiLineString")
+            }
+        return response
+
+    return request
+
+
+def _styled(client) -> list[tuple[str, str]]:
+    """The layers the client set a default style on, with the style."""
+    return [
+        (args[1], kwargs["json"]["layer"]["defaultStyle"]["name"])
+        for args, kwargs in client._request.call_args_list
+        if args[0] == "PUT" and "/layers/" in args[1]
+    ]
+
+
+class TestApplyDefaultStyle:
+    """Test setting the default style of a newly published layer."""
+
+    def test_feature_type(self, make_geoserver_client) -> None:
+        """Test a feature type's geometry is read and its style set."""
+        client = make_geoserver_client()
+        client._request.side_effect = _store()
+
+        with patch("apps.geoserver.default_styles.config_manager") as manager:
+            manager.config.default_styles = RULES
+            style = client._apply_default_style("roads", "highways", datastore="osm")
+
+        assert style == "roads:road_lines"
+        assert client._request.call_args_list[0].args == (
+            "GET",
+            "/rest/workspaces/roads/datastores/osm/featuretypes/highways.json",
+        )
+        assert _styled(client) == [
+            ("/rest/workspaces/roads/layers/highways.json", "roads:road_lines")
+        ]
+
+    def test_no_rules(self, make_geoserver_client) -> None:
+        """Test nothing is read or changed without rules."""
+        client = make_geoserver_client()
+
+        with patch("apps.geoserver.default_styles.config_manager") as manager:
+            manager.config.default_styles = []
+            assert client._apply_default_style("roads", "dem", "raster") is None
+
+        client._request.assert_not_called()
+
+    def test_failure_logged(self, make_geoserver_client) -> None:
+        """Test a style that can't be set leaves the layer published."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=404, text="No such style")
+
+        with patch("apps.geoserver.default_styles.config_manager") as manager:
+            manager.config.default_styles = RULES
+            assert client._apply_default_style("roads", "dem", "raster") is None
+
+    def test_applied_on_publish(self, make_geoserver_client) -> None:
+        """Test publishing a feature type applies the default style."""
+        client = make_geoserver_client()
+        client._request.side_effect = _store()
+
+        with patch("apps.geoserver.default_styles.config_manager") as manager:
+            manager.config.default_styles = RULES
+            client.create_featuretype("roads", "osm", "highways")
+
+        assert _styled(client) == [
+            ("/rest/workspaces/roads/layers/highways.json", "roads:road_lines")
+        ]
+
+    def test_applied_on_upload(self, make_geoserver_client) -> None:
+        """Test an upload into a store styles what it published, not what was there."""
+        client = make_geoserver_client()
+        client._request.side_effect = _store(before=("roads",), after=("roads", "rivers"))
+
+        with patch("apps.geoserver.default_styles.config_manager") as manager:
+            manager.config.default_styles = RULES
+            client.upload_geopackage("roads", "osm", b"gpkg", configure="all", update="append")
+
+        assert _styled(client) == [
+            ("/rest/workspaces/roads/layers/rivers.json", "roads:road_lines")
+        ]
+
+    def test_not_applied_unpublished_upload(self, make_geoserver_client) -> None:
+        """Test an upload that publishes nothing, or without rules, reads nothing."""
+        client = make_geoserver_client()
+        client._request.side_effect = _store(after=("roads",))
+
+        with patch("apps.geoserver.default_styles.config_manager") as manager:
+            manager.config.default_styles = RULES
+            client.upload_shapefile("roads", "osm", b"zip", configure="none")
+        with patch("apps.geoserver.default_styles.config_manager") as manager:
+            manager.config.default_styles = []
+            client.upload_shapefile("roads", "osm", b"zip")
+
+        assert [args[0] for args, _ in client._request.call_args_list] == ["PUT", "PUT"]
+
+    def test_new_feature_types_styled(self, make_geoserver_client) -> None:
+        """Test only feature types missing before the upload get a style."""
+        client = make_geoserver_client()
+        client._request.side_effect = _store(before=("roads", "rivers"))
+
+        with patch("apps.geoserver.default_styles.config_manager") as manager:
+            manager.config.default_styles = RULES
+            client._style_uploaded_featuretypes("roads", "osm", {"roads"})
+
+        assert _styled(client) == [
+            ("/rest/workspaces/roads/layers/rivers.json", "roads:road_lines")
+        ]
+
+
+class TestParseRules:
+    """Test checking rules edited in the web UI or TUI."""
+
+    def test_valid(self) -> None:
+        """Test rules are read with their workspace trimmed."""
+        rules = parse_rules([{"workspace": " roads ", "line": "road_lines"}, {"point": "poi"}])
+
+        assert [(r.workspace, r.line, r.point) for r in rules] == [
+            ("roads", "road_lines", ""),
+            ("*", "", "poi"),
+        ]
+
+    def test_no_workspace(self) -> None:
+        """Test a rule without a workspace is refused."""
+        with pytest.raises(ValueError, match="needs a workspace"):
+            parse_rules([{"workspace": " ", "point": "poi"}])
+
+    def test_no_style(self) -> None:
+        """Test a rule naming no style is refused."""
+        with pytest.raises(ValueError, match="roads names no style"):
+            parse_rules([{"workspace": "roads", "connections": ["conn"]}])
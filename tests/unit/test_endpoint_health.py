@@ -0,0 +1,107 @@
+"""Unit tests for checking a layer's OGC endpoints."""
+
+from itertools import count
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.endpoint_health import FAIL, PASS, SKIP, center_bbox, verify_endpoints
+from apps.gwc.endpoints import EndpointCheck
+
+LATLON = {"minx": 10.0, "miny": 40.0, "maxx": 12.0, "maxy": 41.0}
+NATIVE = {"minx": 500000.0, "miny": 4400000.0, "maxx": 700000.0, "maxy": 4500000.0}
+
+
+def mock_client(vector: bool = True, srs: str = "EPSG:32633") -> MagicMock:
+    """A client serving one layer with the given resource."""
+    kind = "featureType" if vector else "coverage"
+    client = MagicMock()
+    client.get_layer.return_value = {
+        "name": "roads",
+        "resource": {"@class": kind, "href": f"http://gs/rest/{kind}s/roads.json"},
+    }
+    client.get_href.return_value = {
+        kind: {"latLonBoundingBox": LATLON, "nativeBoundingBox": NATIVE, "srs": srs}
+    }
+    client.get_map.return_value = b"PNG"
+    client.get_layer_feature_count.return_value = 42
+    client.get_legend_graphic.return_value = b"LEGEND"
+    return client
+
+
+def statuses(report) -> dict[str, str]:
+    return {result.service: result.status for result in report.results}
+
+
+class TestCenterBbox:
+    """Test the small box the GetMap requests cover."""
+
+    def test_centred(self):
+        assert list(center_bbox(LATLON, 0.5)) == pytest.approx([10.5, 40.25, 11.5, 40.75])
+
+    def test_point_bounds_keep_an_area(self):
+        minx, miny, maxx, maxy = center_bbox({"minx": 5, "miny": 5, "maxx": 5, "maxy": 5})
+        assert minx < maxx and miny < maxy
+
+
+class TestVerifyEndpoints:
+    """Test the pass/fail result of each endpoint."""
+
+    def test_all_pass(self):
+        client = mock_client()
+        gwc = MagicMock()
+        tile = EndpointCheck(layer="topp:roads", grid_set="EPSG:4326", format="image/png", size=9)
+        with patch("apps.geoserver.endpoint_health.check_endpoints", return_value=tile):
+            report = verify_endpoints(client, "topp", "roads", gwc, clock=count().__next__)
+
+        assert statuses(report) == {
+            "wms": PASS,
+            "wms-native": PASS,
+            "wfs": PASS,
+            "wmts": PASS,
+            "legend": PASS,
+        }
+        assert report.ok
+        assert report.results[2].detail == "42 features"
+        assert report.results[0].elapsed_ms == 1000
+        assert client.get_map.call_args_list[0].kwargs["srs"] == "EPSG:4326"
+        assert client.get_map.call_args_list[1].kwargs["srs"] == "EPSG:32633"
+        assert report.record() == {"layer": "topp:roads", **statuses(report)}
+
+    def test_disabled_service_fails(self):
+        client = mock_client()
+        client.get_layer_feature_count.side_effect = GeoServerError("Service WFS is disabled")
+
+        report = verify_endpoints(client, "topp", "roads")
+
+        assert report.results[2].status == FAIL
+        assert report.results[2].detail == "Service WFS is disabled"
+        assert not report.ok
+        assert report.to_dict()["failed"] == 1
+
+    def test_raster_skips_wfs_and_lonlat_skips_native(self):
+        report = verify_endpoints(mock_client(vector=False, srs="EPSG:4326"), "topp", "dem")
+
+        assert statuses(report)["wfs"] == SKIP
+        assert statuses(report)["wms-native"] == SKIP
+        assert statuses(report)["wmts"] == SKIP  # No GWC client
+        assert report.ok
+
+    def test_uncached_layer_skips_wmts(self):
+        uncached = EndpointCheck(layer="topp:roads", issues=["topp:roads isn't cached"])
+        with patch("apps.geoserver.endpoint_health.check_endpoints", return_value=uncached):
+            report = verify_endpoints(mock_client(), "topp", "roads", MagicMock())
+
+        assert statuses(report)["wmts"] == SKIP
+        assert report.results[3].detail == "topp:roads isn't cached"
+
+    def test_broken_resource_fails_getmap(self):
+        client = mock_client()
+        client.get_href.side_effect = GeoServerError("Store not found", 404)
+
+        report = verify_endpoints(client, "topp", "roads")
+
+        assert statuses(report)["wms"] == FAIL
+        assert "no lat/lon bounds" in report.results[0].detail
+        client.get_map.assert_not_called()
@@ -0,0 +1,106 @@
+"""Unit tests for WFS feature counting."""
+
+import json
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import parse_feature_count
+
+
+def _response(text: str, content_type: str = "text/xml", status_code: int = 200) -> MagicMock:
+    response = MagicMock()
+    response.status_code = status_code
+    response.text = text
+    response.headers = {"content-type": content_type}
+    if "json" in content_type:
+        response.json.side_effect = lambda: json.loads(text)
+    return response
+
+
+class TestParseFeatureCount:
+    """Tests for reading hits responses."""
+
+    def test_json_number_matched(self) -> None:
+        """Test GeoJSON hits responses."""
+        response = _response('{"numberMatched": 49, "features": []}', "application/json")
+        assert parse_feature_count(response) == 49
+
+    def test_json_total_features(self) -> None:
+        """Test older GeoServer JSON using totalFeatures."""
+        response = _response('{"totalFeatures": 12}', "application/json;charset=UTF-8")
+        assert parse_feature_count(response) == 12
+
+    def test_wfs2_xml(self) -> None:
+        """Test WFS 2.0 XML hits responses."""
+        response = _response(
+            '<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0" '
+            'numberMatched="3" numberReturned="0"/>'
+        )
+        assert parse_feature_count(response) == 3
+
+    def test_wfs11_xml(self) -> None:
+        """Test WFS 1.1 XML hits responses."""
+        response = _response(
+            '<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs" numberOfFeatures="7"/>'
+        )
+        assert parse_feature_count(response) == 7
+
+    def test_unknown_count(self) -> None:
+        """Test numberMatched="unknown" yields no count."""
+        response = _response(
+            '<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0" numberMatched="unknown"/>'
+        )
+        assert parse_feature_count(response) is None
+
+    def test_exception_report(self) -> None:
+        """Test OWS exceptions are raised as errors."""
+        response = _response(
+            '<ows:ExceptionReport xmlns:ows="http://www.opengis.net/ows/1.1">'
+            "<ows:Exception><ows:ExceptionText>Bad filter</ows:ExceptionText></ows:Exception>"
+            "</ows:ExceptionReport>"
+        )
+        with pytest.raises(GeoServerError, match="Bad filter"):
+            parse_feature_count(response)
+
+    def test_http_error(self) -> None:
+        """Test HTTP errors are raised."""
+        with pytest.raises(GeoServerError):
+            parse_feature_count(_response("oops", status_code=500))
+
+
+class TestGetLayerFeatureCount:
+    """Tests for the feature count request."""
+
+    def test_falls_back_to_xml(self, make_geoserver_client) -> None:
+        """Test the XML request is used when JSON output is unavailable."""
+        client = make_geoserver_client()
+        client._ows_request = MagicMock(
+            side_effect=[
+                _response("<ows:ExceptionReport xmlns:ows='http://www.opengis.net/ows/1.1'/>"),
+                _response('<FeatureCollection numberMatched="5"/>'),
+            ]
+        )
+
+        assert client.get_layer_feature_count("topp", "states") == 5
+        assert "outputFormat" not in client._ows_request.call_args.kwargs["params"]
+
+    def test_filters_are_passed(self, make_geoserver_client) -> None:
+        """Test CQL filter and bbox parameters."""
+        client = make_geoserver_client()
+        client._ows_request = MagicMock(
+            return_value=_response('{"numberMatched": 1}', "application/json")
+        )
+        client.get_layer_feature_count("topp", "states", cql_filter="A > 1")
+        assert client._ows_request.call_args.kwargs["params"]["CQL_FILTER"] == "A > 1"
+
+        client.get_layer_feature_count("topp", "states", bbox=[0, 1, 2, 3], bbox_srs="EPSG:4326")
+        assert client._ows_request.call_args.kwargs["params"]["bbox"] == "0.0,1.0,2.0,3.0,EPSG:4326"
+
+    def test_filter_and_bbox_conflict(self, make_geoserver_client) -> None:
+        """Test combining a CQL filter with a bbox is rejected."""
+        with pytest.raises(ValueError):
+            make_geoserver_client().get_layer_feature_count(
+                "topp", "states", cql_filter="A > 1", bbox=[0, 0, 1, 1]
+            )
@@ -0,0 +1,163 @@
+"""Unit tests for feature type schemas."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.geoserver.schema import (
+    parse_feature_type,
+    parse_srid,
+    publish_attributes,
+    published_attributes,
+)
+
+RESPONSE = {
+    "elementFormDefault": "qualified",
+    "targetNamespace": "http://www.openplans.org/topp",
+    "targetPrefix": "topp",
+    "featureTypes": [
+        {
+            "typeName": "states",
+            "properties": [
+                {
+                    "name": "the_geom",
+                    "maxOccurs": 1,
+                    "minOccurs": 0,
+                    "nillable": True,
+                    "type": "gml:MultiPolygon",
+                    "localType": "MultiPolygon",
+                },
+                {
+                    "name": "STATE_NAME",
+                    "maxOccurs": 1,
+                    "minOccurs": 1,
+                    "nillable": False,
+                    "type": "xsd:string",
+                    "localType": "string",
+                },
+                {
+                    "name": "tags",
+                    "maxOccurs": "unbounded",
+                    "minOccurs": 0,
+                    "type": "xsd:string",
+                    "localType": "string",
+                },
+                {"name": "PERSONS", "nillable": True, "type": "xsd:double", "localType": "double"},
+            ],
+        }
+    ],
+}
+
+SCHEMA = parse_feature_type(RESPONSE)
+
+
+class TestParseFeatureType:
+    """Tests for reading DescribeFeatureType responses."""
+
+    def test_attributes(self) -> None:
+        """Test names, types, nullability and occurrence are read."""
+        name = SCHEMA.attribute("STATE_NAME")
+
+        assert SCHEMA.type_name == "states"
+        assert SCHEMA.namespace == "http://www.openplans.org/topp"
+        assert [a.name for a in SCHEMA.attributes] == ["the_geom", "STATE_NAME", "tags", "PERSONS"]
+        assert name is not None and not name.nillable and name.min_occurs == 1
+        assert SCHEMA.attribute("tags").max_occurs == 1
+
+    def test_geometry(self) -> None:
+        """Test the first geometry attribute is the layer's geometry."""
+        assert SCHEMA.geometry_attribute.name == "the_geom"
+        assert SCHEMA.geometry_type == "MultiPolygon"
+        assert [a.name for a in SCHEMA.fields] == ["STATE_NAME", "tags", "PERSONS"]
+
+    def test_empty_response(self) -> None:
+        """Test a response without feature types has no attributes."""
+        schema = parse_feature_type({})
+
+        assert schema.attributes == [] and schema.geometry_type is None
+
+    def test_srid(self) -> None:
+        """Test SRS codes in the forms GeoServer uses."""
+        assert parse_srid("EPSG:4326") == 4326
+        assert parse_srid("urn:ogc:def:crs:EPSG::3857") == 3857
+        assert parse_srid("CRS:84") == 84
+        assert parse_srid(None) is None
+        assert parse_srid("LOCAL") is None
+
+    def test_serialized(self) -> None:
+        """Test the API form carries the geometry and SRID."""
+        SCHEMA.srs = "EPSG:4326"
+        try:
+            data = SCHEMA.to_dict()
+        finally:
+            SCHEMA.srs = None
+
+        assert data["geometryAttribute"] == "the_geom"
+        assert data["srid"] == 4326
+        assert data["attributes"][1]["nillable"] is False
+
+
+class TestPublishedAttributes:
+    """Tests for choosing the attributes a layer publishes."""
+
+    def test_payload(self) -> None:
+        """Test kept attributes carry their bindings in the given order."""
+        payload = published_attributes(SCHEMA, ["the_geom", "PERSONS"])
+
+        assert payload == {
+            "attribute": [
+                {
+                    "name": "the_geom",
+                    "minOccurs": 0,
+                    "maxOccurs": 1,
+                    "nillable": True,
+                    "binding": "org.locationtech.jts.geom.MultiPolygon",
+                },
+                {
+                    "name": "PERSONS",
+                    "minOccurs": 0,
+                    "maxOccurs": 1,
+                    "nillable": True,
+                    "binding": "java.lang.Double",
+                },
+            ]
+        }
+
+    def test_unknown_attribute(self) -> None:
+        """Test names missing from the schema are refused."""
+        with pytest.raises(ValueError, match="Unknown attribute"):
+            published_attributes(SCHEMA, ["the_geom", "AREA"])
+
+    def test_update_through_store(self) -> None:
+        """Test the feature type is updated in the layer's data store."""
+        client = MagicMock()
+        client.describe_feature_type.return_value = SCHEMA
+        client.get_layer_metadata.return_value = {
+            "store": {"workspace": "topp", "type": "datastores", "name": "states_shp"}
+        }
+
+        publish_attributes(client, "topp", "states", ["the_geom", "STATE_NAME"])
+
+        args = client.update_featuretype.call_args.args
+        assert args[:3] == ("topp", "states_shp", "states")
+        assert [a["name"] for a in args[3]["attributes"]["attribute"]] == ["the_geom", "STATE_NAME"]
+
+    def test_geometry_required(self) -> None:
+        """Test the geometry can't be left out."""
+        client = MagicMock()
+        client.describe_feature_type.return_value = SCHEMA
+
+        with pytest.raises(ValueError, match="geometry"):
+            publish_attributes(client, "topp", "states", ["STATE_NAME"])
+        client.update_featuretype.assert_not_called()
+
+    def test_not_a_data_store(self) -> None:
+        """Test layers outside data stores are refused."""
+        client = MagicMock()
+        client.describe_feature_type.return_value = SCHEMA
+        client.get_layer_metadata.return_value = {
+            "store": {"workspace": "topp", "type": "wmsstores", "name": "cascade"}
+        }
+
+        with pytest.raises(ValueError, match="data store"):
+            publish_attributes(client, "topp", "states", ["the_geom"])
@@ -0,0 +1,140 @@
+"""Unit tests for previewing local files before upload."""
+
+import json
+from pathlib import Path
+from unittest.mock import MagicMock, patch
+
+from apps.upload.preview import FileSummary, describe_file, gdal_path
+
+GDALINFO = {
+    "driverShortName": "GTiff",
+    "size": [4000, 3000],
+    "coordinateSystem": {"wkt": 'PROJCRS["WGS 84 / UTM zone 33N",BASEGEOGCRS[]]'},
+    "stac": {"proj:epsg": 32633},
+    "cornerCoordinates": {
+        "upperLeft": [500000.0, 4650000.0],
+        "lowerLeft": [500000.0, 4620000.0],
+        "upperRight": [540000.0, 4650000.0],
+        "lowerRight": [540000.0, 4620000.0],
+    },
+    "bands": [
+        {"band": 1, "type": "Float32", "description": "elevation"},
+        {"band": 2, "type": "Byte", "colorInterpretation": "Alpha"},
+    ],
+}
+
+OGRINFO = {
+    "driverShortName": "GPKG",
+    "layers": [
+        {
+            "name": "roads",
+            "featureCount": 120,
+            "geometryFields": [
+                {
+                    "type": "LineString",
+                    "extent": [10.0, 40.0, 11.0, 41.0],
+                    "coordinateSystem": {"projjson": {"id": {"authority": "EPSG", "code": 4326}}},
+                }
+            ],
+            "fields": [{"name": "name", "type": "String"}, {"name": "lanes", "type": "Integer"}],
+        },
+        {
+            "name": "towns",
+            "featureCount": 8,
+            "geometryFields": [{"type": "Point", "extent": [9.5, 40.5, 10.5, 42.0]}],
+            "fields": [],
+        },
+    ],
+}
+
+
+def _completed(output: dict, returncode: int = 0, stderr: str = "") -> MagicMock:
+    return MagicMock(returncode=returncode, stdout=json.dumps(output), stderr=stderr)
+
+
+class TestDescribeFile:
+    """Tests for summarising local files."""
+
+    @patch("apps.upload.preview.subprocess.run")
+    def test_raster(self, run: MagicMock, tmp_path: Path) -> None:
+        """Test gdalinfo output gives the driver, CRS, extent and bands."""
+        run.return_value = _completed(GDALINFO)
+
+        summary = describe_file(tmp_path / "dem.tif")
+
+        assert run.call_args.args[0][:2] == ["gdalinfo", "-json"]
+        assert (summary.driver, summary.crs, summary.size) == ("GTiff", "EPSG:32633", (4000, 3000))
+        assert summary.extent == (500000.0, 4620000.0, 540000.0, 4650000.0)
+        assert summary.bands == ["Band 1: Float32 (elevation)", "Band 2: Byte (Alpha)"]
+
+    @patch("apps.upload.preview.subprocess.run")
+    def test_vector_layers(self, run: MagicMock, tmp_path: Path) -> None:
+        """Test ogrinfo output gives each layer's fields and the combined extent."""
+        run.return_value = _completed(OGRINFO)
+
+        summary = describe_file(tmp_path / "osm.gpkg")
+
+        assert summary.crs == "EPSG:4326"
+        assert summary.extent == (9.5, 40.0, 11.0, 42.0)
+        roads = summary.layers[0]
+        assert (roads.geometry_type, roads.feature_count) == ("LineString", 120)
+        assert roads.fields == [("name", "String"), ("lanes", "Integer")]
+
+    @patch("apps.upload.preview.subprocess.run")
+    def test_zipped_shapefile_read_in_place(self, run: MagicMock, tmp_path: Path) -> None:
+        """Test zipped shapefiles are opened through /vsizip/."""
+        run.return_value = _completed({"layers": []})
+
+        describe_file(tmp_path / "roads.zip")
+
+        assert run.call_args.args[0][-1] == f"/vsizip/{tmp_path / 'roads.zip'}"
+
+    @patch("apps.upload.preview.subprocess.run", side_effect=FileNotFoundError)
+    def test_gdal_missing(self, _run: MagicMock, tmp_path: Path) -> None:
+        """Test a missing GDAL is reported on the summary."""
+        summary = describe_file(tmp_path / "dem.tif")
+
+        assert summary.error == "gdalinfo not found. Please install GDAL."
+
+    @patch("apps.upload.preview.subprocess.run")
+    def test_old_gdal(self, run: MagicMock, tmp_path: Path) -> None:
+        """Test ogrinfo without JSON output asks for a newer GDAL."""
+        run.return_value = MagicMock(returncode=0, stdout="INFO: Open of roads.gpkg", stderr="")
+
+        summary = describe_file(tmp_path / "roads.gpkg")
+
+        assert "GDAL 3.7" in (summary.error or "")
+
+    def test_style_files(self, tmp_path: Path) -> None:
+        """Test SLD and CSS styles are read as text for highlighting."""
+        (tmp_path / "line.sld").write_text("<StyledLayerDescriptor/>")
+        (tmp_path / "line.css").write_text("* { stroke: red; }")
+
+        sld = describe_file(tmp_path / "line.sld")
+        css = describe_file(tmp_path / "line.css")
+
+        assert (sld.style_text, sld.style_language) == ("<StyledLayerDescriptor/>", "xml")
+        assert (css.kind, css.style_language) == ("style", "css")
+
+    def test_unsupported(self, tmp_path: Path) -> None:
+        """Test other files are reported as unsupported."""
+        summary = describe_file(tmp_path / "notes.txt")
+
+        assert summary.error == "Unsupported file type: notes.txt"
+
+
+class TestSummary:
+    """Tests for serializing summaries."""
+
+    def test_to_dict(self) -> None:
+        """Test summaries serialize with camelCase keys."""
+        summary = FileSummary(name="dem.tif", kind="geotiff", extent=(0, 0, 1, 1), size=(2, 3))
+
+        data = summary.to_dict()
+
+        assert data["extent"] == [0, 0, 1, 1] and data["size"] == [2, 3]
+        assert data["styleText"] is None and data["layers"] == []
+
+    def test_gdal_path(self) -> None:
+        """Test only zip files get a virtual path."""
+        assert gdal_path(Path("/data/dem.tif")) == "/data/dem.tif"
@@ -0,0 +1,195 @@
+"""Unit tests for the GeoFence rule client."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.geofence import GeofenceClient, GeofenceRule, normalize_area
+
+
+def _response(status_code: int = 200, body: dict | None = None, text: str = "") -> MagicMock:
+    response = MagicMock(status_code=status_code, text=text)
+    response.json.return_value = body or {}
+    return response
+
+
+def _geofence(responses: list[MagicMock]) -> tuple[GeofenceClient, MagicMock]:
+    client = MagicMock()
+    client._request.side_effect = responses
+    client.capabilities.supports.return_value = True
+    return GeofenceClient(client), client
+
+
+class TestGeofenceRule:
+    """Tests for validating and converting rules."""
+
+    def test_polygon_becomes_multipolygon_in_4326(self) -> None:
+        """Test a plain polygon is stored as an EWKT multipolygon."""
+        assert normalize_area("POLYGON ((0 0, 1 0, 1 1, 0 0))") == (
+            "SRID=4326;MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)))"
+        )
+        assert normalize_area("SRID=3857;MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)))") == (
+            "SRID=3857;MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)))"
+        )
+
+    def test_area_must_be_a_polygon(self) -> None:
+        """Test other geometries are refused."""
+        with pytest.raises(ValueError, match="POLYGON"):
+            normalize_area("POINT (0 0)")
+
+    def test_from_dict_treats_empty_and_star_as_any(self) -> None:
+        """Test empty fields and "*" match anything."""
+        rule = GeofenceRule.from_dict(
+            {"access": "deny", "priority": "3", "user": "*", "role": "", "service": "wms"}
+        )
+
+        assert rule.access == "DENY"
+        assert rule.priority == 3
+        assert rule.user is None
+        assert rule.role is None
+        assert rule.service == "WMS"
+
+    @pytest.mark.parametrize(
+        ("data", "message"),
+        [
+            ({"access": "MAYBE"}, "Unknown access"),
+            ({"layer": "roads"}, "needs its workspace"),
+            ({"addressRange": "10.0.0.300/8"}, "Invalid address range"),
+            ({"access": "ALLOW", "area": "POLYGON ((0 0, 1 0, 1 1, 0 0))"}, "Only LIMIT"),
+            ({"access": "LIMIT", "catalogMode": "SHOW"}, "Unknown catalog mode"),
+            ({"priority": "first"}, "whole number"),
+        ],
+    )
+    def test_from_dict_refuses_invalid_rules(self, data: dict, message: str) -> None:
+        """Test invalid fields are reported."""
+        with pytest.raises(ValueError, match=message):
+            GeofenceRule.from_dict(data)
+
+    def test_api_round_trip_keeps_limits(self) -> None:
+        """Test a LIMIT rule is written and read back with its limits."""
+        rule = GeofenceRule.from_dict(
+            {
+                "access": "LIMIT",
+                "priority": 1,
+                "role": "ROLE_PARTNER",
+                "workspace": "topp",
+                "layer": "roads",
+                "area": "POLYGON ((0 0, 1 0, 1 1, 0 0))",
+                "catalogMode": "HIDE",
+            }
+        )
+
+        api = rule.to_api()
+
+        assert api["roleName"] == "ROLE_PARTNER"
+        assert api["userName"] is None
+        assert api["limits"] == {
+            "allowedArea": "SRID=4326;MULTIPOLYGON (((0 0, 1 0, 1 1, 0 0)))",
+            "catalogMode": "HIDE",
+        }
+        assert GeofenceRule.from_api({**api, "id": 7}) == GeofenceRule(**{**vars(rule), "id": 7})
+
+    def test_clears_detects_removed_fields(self) -> None:
+        """Test a rule leaving out a field it had set clears it."""
+        previous = GeofenceRule(id=1, role="ROLE_EDITOR", workspace="topp")
+
+        assert GeofenceRule(id=1, workspace="topp").clears(previous)
+        assert not GeofenceRule(id=1, role="ROLE_ADMIN", workspace="topp").clears(previous)
+
+
+class TestGeofenceClient:
+    """Tests for the GeoFence rule API calls."""
+
+    def test_not_available_without_the_extension(self) -> None:
+        """Test servers without GeoFence in their manifests aren't probed."""
+        geofence, client = _geofence([])
+        client.capabilities.supports.return_value = False
+
+        assert geofence.is_available() is False
+        client._request.assert_not_called()
+
+    def test_availability_probe_is_cached(self) -> None:
+        """Test the REST API is probed once."""
+        geofence, client = _geofence([_response(200, text="4")])
+
+        assert geofence.is_available() is True
+        assert geofence.is_available() is True
+        assert client._request.call_count == 1
+
+    def test_list_rules_in_priority_order(self) -> None:
+        """Test rules are sorted by priority, then id."""
+        geofence, _ = _geofence(
+            [
+                _response(
+                    200,
+                    {
+                        "count": 3,
+                        "rules": [
+                            {"id": 5, "priority": 2, "access": "ALLOW"},
+                            {"id": 9, "priority": 0, "access": "DENY", "layer": "roads"},
+                            {"id": 4, "priority": 2, "access": "LIMIT"},
+                        ],
+                    },
+                )
+            ]
+        )
+
+        rules = geofence.list_rules()
+
+        assert [rule.id for rule in rules] == [9, 4, 5]
+        assert rules[0].layer == "roads"
+
+    def test_list_rules_accepts_a_single_rule(self) -> None:
+        """Test a lone rule written as an object is listed."""
+        geofence, _ = _geofence([_response(200, {"rules": {"id": 1, "priority": 0}})])
+
+        assert [rule.id for rule in geofence.list_rules()] == [1]
+
+    def test_create_rule_reads_the_text_id(self) -> None:
+        """Test the new rule's id is read from the plain text answer."""
+        geofence, client = _geofence([_response(201, text="12\n")])
+        rule = GeofenceRule(access="DENY", workspace="topp")
+
+        assert geofence.create_rule(rule) == 12
+        method, path = client._request.call_args.args
+        assert (method, path) == ("POST", "/rest/geofence/rules")
+        assert client._request.call_args.kwargs["json"]["Rule"]["workspace"] == "topp"
+        assert rule.id == 12
+
+    def test_update_rule_posts_changes(self) -> None:
+        """Test a rule keeping its fields is updated in place."""
+        geofence, client = _geofence(
+            [
+                _response(200, {"id": 3, "priority": 1, "access": "ALLOW", "workspace": "topp"}),
+                _response(200),
+            ]
+        )
+
+        rule = GeofenceRule(id=3, priority=1, access="DENY", workspace="topp")
+
+        assert geofence.update_rule(rule) == 3
+        assert client._request.call_args.args == ("POST", "/rest/geofence/rules/id/3")
+
+    def test_update_rule_replaces_a_rule_losing_a_field(self) -> None:
+        """Test clearing a field replaces the rule at the same priority."""
+        geofence, client = _geofence(
+            [
+                _response(200, {"id": 3, "priority": 1, "access": "ALLOW", "roleName": "R"}),
+                _response(201, text="8"),
+                _response(200),
+            ]
+        )
+
+        assert geofence.update_rule(GeofenceRule(id=3, priority=1)) == 8
+        calls = [call.args for call in client._request.call_args_list]
+        assert calls[1] == ("POST", "/rest/geofence/rules")
+        assert calls[2] == ("DELETE", "/rest/geofence/rules/id/3")
+
+    def test_missing_rule_is_not_found(self) -> None:
+        """Test a missing rule is reported as 404."""
+        geofence, _ = _geofence([_response(404, text="Rule not found")])
+
+        with pytest.raises(GeoServerError) as exc_info:
+            geofence.delete_rule(42)
+        assert exc_info.value.status_code == 404
@@ -0,0 +1,101 @@
+"""Unit tests for publishing the tables of a GeoPackage."""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.publish import NamingRules, plan_layer_names, publish_feature_types
+from apps.upload.batch import BatchItem, publish_item
+
+
+def _client(*tables: str, layers: tuple[str, ...] = ()) -> MagicMock:
+    client = MagicMock()
+    client.list_available_featuretypes.return_value = list(tables)
+    client.list_layers.return_value = [{"name": name} for name in layers]
+    return client
+
+
+class TestNamingRules:
+    """Tests for naming layers after tables."""
+
+    def test_prefix_and_suffix(self) -> None:
+        """Test the table name is wrapped and cleaned."""
+        rules = NamingRules.from_dict({"prefix": "OSM ", "suffix": "-2024"})
+
+        assert rules.apply("Roads (main)") == "osm_roads_main_2024"
+
+    def test_unsanitized(self) -> None:
+        """Test names are left as they are when cleaning is off."""
+        rules = NamingRules.from_dict({"prefix": "osm_", "sanitize": False})
+
+        assert rules.apply("Roads") == "osm_Roads"
+
+    def test_defaults(self) -> None:
+        """Test missing rules only clean the table name."""
+        assert NamingRules.from_dict(None).apply("Rivers") == "rivers"
+
+    def test_taken_names_numbered(self) -> None:
+        """Test names used in the workspace or earlier in the batch are numbered."""
+        names = plan_layer_names(["roads", "Roads", "rivers"], NamingRules(), {"roads"})
+
+        assert names == {"roads": "roads_2", "Roads": "roads_3", "rivers": "rivers"}
+
+
+class TestPublishFeatureTypes:
+    """Tests for publishing the tables of a data store."""
+
+    def test_all_tables(self) -> None:
+        """Test every unpublished table is published in its native SRS."""
+        client = _client("roads", "rivers")
+
+        results = publish_feature_types(client, "topp", "osm", rules=NamingRules(prefix="osm_"))
+
+        assert [r["layer"] for r in results] == ["osm_roads", "osm_rivers"]
+        client.create_featuretype.assert_any_call(
+            "topp", "osm", "osm_roads", native_name="roads", title="roads", srs=None
+        )
+
+    def test_selected_tables(self) -> None:
+        """Test only the chosen tables are published."""
+        client = _client("roads", "rivers")
+
+        results = publish_feature_types(client, "topp", "osm", ["rivers"])
+
+        assert [r["table"] for r in results] == ["rivers"]
+        assert client.create_featuretype.call_count == 1
+
+    def test_unknown_table(self) -> None:
+        """Test tables the store doesn't have are refused before publishing."""
+        client = _client("roads")
+
+        with pytest.raises(ValueError, match="lakes"):
+            publish_feature_types(client, "topp", "osm", ["roads", "lakes"])
+        client.create_featuretype.assert_not_called()
+
+    def test_failures_are_isolated(self) -> None:
+        """Test one failing table doesn't stop the rest."""
+        client = _client("roads", "rivers")
+        client.create_featuretype.side_effect = [GeoServerError("no geometry", 500), None]
+
+        results = publish_feature_types(client, "topp", "osm")
+
+        assert [r["status"] for r in results] == ["error", "published"]
+        assert results[0]["error"] == "no geometry"
+
+
+class TestBatchGeoPackage:
+    """Tests for GeoPackages in directory batch uploads."""
+
+    @patch("apps.upload.batch.invalidate_store_cache", return_value=[])
+    def test_every_table_published(self, _invalidate: MagicMock) -> None:
+        """Test the GeoPackage is uploaded unconfigured and each table published."""
+        client = _client("roads", "rivers")
+        client.create_featuretype.side_effect = [None, GeoServerError("bad table", 500)]
+        item = BatchItem("osm.gpkg", "geopackage", "topp", store="osm")
+
+        result = publish_item(client, "conn", item, b"gpkg")
+
+        client.upload_geopackage.assert_called_once_with("topp", "osm", b"gpkg", configure="none")
+        assert result["layers"] == ["roads"]
+        assert "rivers: bad table" in result["warning"]
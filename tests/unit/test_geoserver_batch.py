@@ -0,0 +1,108 @@
+"""Unit tests for batch actions on marked layers."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.batch import BatchRunner, export_document, run_batch, split_layer_name
+
+
+class TestSplitLayerName:
+    """Tests for qualified layer names."""
+
+    def test_split(self) -> None:
+        """Test a qualified name splits into workspace and layer."""
+        assert split_layer_name("topp:roads") == ("topp", "roads")
+
+    def test_unqualified_rejected(self) -> None:
+        """Test a name without a workspace is rejected."""
+        with pytest.raises(ValueError):
+            split_layer_name("roads")
+
+
+class TestBatchRunner:
+    """Tests for running one action on several layers."""
+
+    def test_delete_continues_after_failure(self) -> None:
+        """Test one failing layer doesn't stop the others and progress is reported."""
+        client = MagicMock()
+        client.delete_layer.side_effect = [None, GeoServerError("in use", 403), None]
+        progress = MagicMock()
+
+        results = run_batch(
+            client,
+            None,
+            "delete",
+            ["topp:roads", "topp:rivers", "topp:lakes"],
+            {"recurse": True},
+            progress,
+        )
+
+        assert [r.ok for r in results] == [True, False, True]
+        assert results[1].message == "in use"
+        client.delete_layer.assert_any_call("topp", "roads", recurse=True)
+        assert [c.args[:2] for c in progress.call_args_list] == [(1, 3), (2, 3), (3, 3)]
+
+    def test_truncate_without_cache_is_ok(self) -> None:
+        """Test layers without a tile cache count as done."""
+        gwc = MagicMock()
+        gwc.truncate_entire_layer.side_effect = [None, GeoServerError("no layer", 404)]
+
+        results = BatchRunner(MagicMock(), gwc).run("truncate", ["topp:roads", "topp:rivers"])
+
+        assert all(r.ok for r in results)
+        assert results[1].message == "No tile cache"
+
+    def test_truncate_needs_gwc(self) -> None:
+        """Test truncating fails per layer without a GWC client."""
+        results = BatchRunner(MagicMock()).run("truncate", ["topp:roads"])
+
+        assert not results[0].ok
+
+    def test_export_includes_resource(self) -> None:
+        """Test exported configs hold the layer and its resource."""
+        client = MagicMock()
+        client.get_layer.return_value = {
+            "name": "roads",
+            "resource": {"href": "http://gs/rest/workspaces/topp/featuretypes/roads.json"},
+        }
+        client.get_href.return_value = {"featureType": {"name": "roads"}}
+
+        results = BatchRunner(client).run("export", ["topp:roads"])
+
+        document = export_document(results)
+        assert document["topp:roads"]["resource"] == {"featureType": {"name": "roads"}}
+
+    def test_group_uses_shared_workspace(self) -> None:
+        """Test a group of layers from one workspace is created in that workspace."""
+        client = MagicMock()
+
+        results = BatchRunner(client).run(
+            "group", ["topp:roads", "topp:rivers"], {"name": "base", "title": "Base"}
+        )
+
+        assert len(results) == 1
+        assert results[0].ok and results[0].target == "topp:base"
+        client.create_layergroup.assert_called_once_with(
+            "base", ["topp:roads", "topp:rivers"], workspace="topp", title="Base"
+        )
+
+    def test_group_across_workspaces_is_global(self) -> None:
+        """Test a group of layers from several workspaces is created globally."""
+        client = MagicMock()
+
+        results = BatchRunner(client).run("group", ["topp:roads", "sf:rivers"], {"name": "mix"})
+
+        assert results[0].target == "mix"
+        assert client.create_layergroup.call_args.kwargs["workspace"] is None
+
+    def test_group_requires_name(self) -> None:
+        """Test creating a group without a name is rejected."""
+        with pytest.raises(ValueError):
+            BatchRunner(MagicMock()).run("group", ["topp:roads"])
+
+    def test_unknown_action(self) -> None:
+        """Test an unknown action is rejected."""
+        with pytest.raises(ValueError):
+            BatchRunner(MagicMock()).run("rename", ["topp:roads"])
@@ -0,0 +1,78 @@
+"""Unit tests for copyable resource links."""
+
+from urllib.parse import parse_qs, urlparse
+
+from apps.core.config import Connection
+from apps.geoserver.links import copy_targets, rest_url, wfs_getfeature_url, wms_getmap_url
+
+
+def _connection() -> Connection:
+    return Connection(
+        id="conn_1",
+        name="Test",
+        url="http://internal:8080/geoserver",
+        username="admin",
+        password="geoserver",
+        public_url="https://maps.example.com/geoserver",
+    )
+
+
+class TestUrls:
+    """Tests for the individual URL builders."""
+
+    def test_getmap_follows_bounds(self) -> None:
+        """Test the GetMap link frames the layer bounds with a matching aspect ratio."""
+        url = wms_getmap_url(
+            "https://gs/geoserver", "topp", "roads", {"minx": 0, "miny": 0, "maxx": 20, "maxy": 10}
+        )
+
+        parsed = urlparse(url)
+        params = parse_qs(parsed.query)
+        assert parsed.path == "/geoserver/topp/wms"
+        assert params["layers"] == ["topp:roads"]
+        assert params["bbox"] == ["0.0,0.0,20.0,10.0"]
+        assert (params["width"], params["height"]) == (["768"], ["384"])
+
+    def test_getfeature(self) -> None:
+        """Test the GetFeature link asks for GeoJSON from the workspace service."""
+        url = wfs_getfeature_url("https://gs/geoserver", "topp", "roads")
+
+        assert url.startswith("https://gs/geoserver/topp/wfs?")
+        assert "typeNames=topp:roads" in url
+        assert "outputFormat=application/json" in url
+
+    def test_rest_urls(self) -> None:
+        """Test REST URLs for workspace and global resources."""
+        base = "http://gs/geoserver"
+        assert rest_url(base, "layer", "topp", "roads") == (
+            "http://gs/geoserver/rest/workspaces/topp/layers/roads.json"
+        )
+        assert rest_url(base, "style", None, "line") == "http://gs/geoserver/rest/styles/line.json"
+        assert rest_url(base, "layers", "topp") is None
+
+
+class TestCopyTargets:
+    """Tests for the strings offered per node type."""
+
+    def test_layer_targets(self) -> None:
+        """Test a layer offers its name, OWS links and REST URL."""
+        targets = copy_targets(_connection(), "layer", "topp", "roads")
+
+        assert [t.kind for t in targets] == ["name", "wms", "wfs", "wmts", "rest"]
+        assert targets[0].value == "topp:roads"
+        # OWS links use the public URL, REST the API URL
+        assert targets[1].value.startswith("https://maps.example.com/geoserver/topp/wms?")
+        assert targets[-1].value.startswith("http://internal:8080/geoserver/rest/")
+
+    def test_coverage_layer_has_no_wfs(self) -> None:
+        """Test raster layers get no WFS link."""
+        targets = copy_targets(_connection(), "layer", "nurc", "dem", store_type="coveragestore")
+
+        assert "wfs" not in [t.kind for t in targets]
+
+    def test_connection_targets(self) -> None:
+        """Test a connection offers its URL and WMTS capabilities."""
+        targets = copy_targets(_connection(), "connection")
+
+        assert targets[0].value == "https://maps.example.com/geoserver"
+        assert [t.kind for t in targets] == ["url", "wmts", "rest"]
@@ -0,0 +1,148 @@
+"""Unit tests for bounded seeds.
+
+Tests reading seed bounds and converting them to a grid set's SRS.
+"""
+
+import subprocess
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.config import CacheSchedule
+from apps.gwc.bounds import gridset_bounds, parse_bounds, transform_bounds
+from apps.gwc.scheduler import CacheScheduler, validate_schedule
+
+# A Web Mercator grid set, as GWC returns it
+EPSG_900913 = {
+    "name": "EPSG:900913",
+    "srs": {"number": 900913},
+    "extent": {"coords": [-20037508.34, -20037508.34, 20037508.34, 20037508.34]},
+    "resolutions": [156543.03392804097 / (2**z) for z in range(20)],
+}
+
+
+class TestParseBounds:
+    """Tests for reading bounds from requests."""
+
+    def test_list_and_dict(self) -> None:
+        """Test both the list and the GWCBounds shape are read."""
+        assert parse_bounds(["1", 2, 3, 4.5]) == (1.0, 2.0, 3.0, 4.5)
+        assert parse_bounds({"minX": 1, "minY": 2, "maxX": 3, "maxY": 4, "srs": "EPSG:4326"}) == (
+            1.0,
+            2.0,
+            3.0,
+            4.0,
+        )
+
+    @pytest.mark.parametrize(
+        "value", [[1, 2, 3], ["a", 2, 3, 4], [3, 2, 1, 4], [1, 2, 3, 2], None, {"minX": 1}]
+    )
+    def test_invalid(self, value: object) -> None:
+        """Test malformed and empty bounds are rejected."""
+        with pytest.raises(ValueError):
+            parse_bounds(value)
+
+
+class TestTransformBounds:
+    """Tests for converting bounds between SRS."""
+
+    def test_lonlat_to_web_mercator_and_back(self) -> None:
+        """Test Web Mercator aliases are converted without GDAL."""
+        mercator = transform_bounds((-10.0, -20.0, 10.0, 20.0), "EPSG:4326", "EPSG:900913")
+
+        assert mercator[2] == pytest.approx(1113194.9, rel=1e-6)
+        assert mercator[3] == pytest.approx(2273030.9, rel=1e-6)
+        lonlat = transform_bounds(mercator, "EPSG:3857", "CRS:84")
+        assert lonlat == pytest.approx((-10.0, -20.0, 10.0, 20.0))
+
+    def test_same_srs(self) -> None:
+        """Test bounds already in the target SRS are kept."""
+        assert transform_bounds((1, 2, 3, 4), "epsg:2193", "EPSG:2193") == (1, 2, 3, 4)
+
+    def test_other_srs_through_gdal(self) -> None:
+        """Test other SRS use gdaltransform, covering all sampled edge points."""
+        output = "1000 5000 0\n3000 4000 0\ninf inf 0\n2000 6000 0\n"
+        result = subprocess.CompletedProcess([], 0, stdout=output, stderr="")
+
+        with patch("apps.gwc.bounds.subprocess.run", return_value=result) as run:
+            bounds = transform_bounds((170.0, -45.0, 175.0, -40.0), "EPSG:4326", "EPSG:2193")
+
+        assert bounds == (1000.0, 4000.0, 3000.0, 6000.0)
+        command = run.call_args.args[0]
+        assert command == ["gdaltransform", "-s_srs", "EPSG:4326", "-t_srs", "EPSG:2193"]
+
+    def test_gdal_missing(self) -> None:
+        """Test a missing gdaltransform explains how to seed anyway."""
+        with patch("apps.gwc.bounds.subprocess.run", side_effect=FileNotFoundError):
+            with pytest.raises(ValueError, match="enter them in EPSG:2193"):
+                transform_bounds((170.0, -45.0, 175.0, -40.0), "EPSG:4326", "EPSG:2193")
+
+    def test_gridset_bounds(self) -> None:
+        """Test bounds are converted to the SRS of the seeded grid set."""
+        client = MagicMock()
+        client.get_gridset.return_value = EPSG_900913
+
+        bounds = gridset_bounds(client, "EPSG:900913", (0.0, 0.0, 10.0, 20.0))
+
+        client.get_gridset.assert_called_once_with("EPSG:900913")
+        assert bounds[2] == pytest.approx(1113194.9, rel=1e-6)
+
+
+class TestBoundedSeeds:
+    """Tests for passing bounds to GWC."""
+
+    def test_seed_request_bounds(self, make_gwc_client) -> None:
+        """Test bounds are sent in GWC's seedRequest shape."""
+        client = make_gwc_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        client.seed_layer("topp:roads", bounds=(1.0, 2.0, 3.0, 4.0))
+
+        payload = client._request.call_args.kwargs["json"]["seedRequest"]
+        assert payload["bounds"] == {"coords": {"double": [1.0, 2.0, 3.0, 4.0]}}
+
+    def test_schedule_seeds_area(self) -> None:
+        """Test a schedule's lon/lat area is converted before seeding."""
+        schedule = CacheSchedule(
+            name="Nightly",
+            connection_id="conn_1",
+            layer="topp:roads",
+            cron="0 2 * * *",
+            bounds=[0.0, 0.0, 10.0, 20.0],
+        )
+        validate_schedule(schedule)
+        config = MagicMock()
+        config.get_cache_schedule.return_value = schedule
+        client = MagicMock()
+        client.get_gridset.return_value = EPSG_900913
+
+        with patch("apps.gwc.scheduler.get_config", return_value=config), patch(
+            "apps.gwc.scheduler.get_gwc_client", return_value=client
+        ):
+            CacheScheduler().run_schedule(schedule.id)
+
+        bounds = client.seed_layer.call_args.kwargs["bounds"]
+        assert bounds[3] == pytest.approx(2273030.9, rel=1e-6)
+
+    def test_schedule_truncates_area_through_seed(self) -> None:
+        """Test truncating an area uses the seed endpoint, which takes bounds."""
+        schedule = CacheSchedule(
+            name="Clear",
+            connection_id="conn_1",
+            layer="topp:roads",
+            cron="0 2 * * *",
+            operation="truncate",
+            bounds=[0.0, 0.0, 10.0, 20.0],
+        )
+        config = MagicMock()
+        config.get_cache_schedule.return_value = schedule
+        client = MagicMock()
+        client.get_gridset.return_value = EPSG_900913
+
+        with patch("apps.gwc.scheduler.get_config", return_value=config), patch(
+            "apps.gwc.scheduler.get_gwc_client", return_value=client
+        ):
+            CacheScheduler().run_schedule(schedule.id)
+
+        client.truncate_layer.assert_not_called()
+        assert client.seed_layer.call_args.kwargs["seed_type"] == "truncate"
@@ -0,0 +1,208 @@
+"""Unit tests for tile service endpoints.
+
+Tests building client URLs for a cached layer and checking them with a
+sample tile.
+"""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.gwc.endpoints import (
+    check_endpoints,
+    parse_wmts_layer,
+    tile_endpoints,
+    tms_extension,
+)
+
+CAPABILITIES = b"""<?xml version="1.0" encoding="UTF-8"?>
+<Capabilities xmlns="http://www.opengis.net/wmts/1.0"
+    xmlns:ows="http://www.opengis.net/ows/1.1" version="1.0.0">
+  <Contents>
+    <Layer>
+      <ows:Title>roads</ows:Title>
+      <ows:Identifier>topp:roads</ows:Identifier>
+      <Style isDefault="true"><ows:Identifier>line</ows:Identifier></Style>
+      <Format>image/png</Format>
+      <Format>image/jpeg</Format>
+      <TileMatrixSetLink>
+        <TileMatrixSet>EPSG:900913</TileMatrixSet>
+        <TileMatrixSetLimits>
+          <TileMatrixLimits>
+            <TileMatrix>EPSG:900913:0</TileMatrix>
+            <MinTileRow>0</MinTileRow>
+            <MaxTileRow>0</MaxTileRow>
+            <MinTileCol>0</MinTileCol>
+            <MaxTileCol>0</MaxTileCol>
+          </TileMatrixLimits>
+          <TileMatrixLimits>
+            <TileMatrix>EPSG:900913:1</TileMatrix>
+            <MinTileRow>1</MinTileRow>
+            <MaxTileRow>1</MaxTileRow>
+            <MinTileCol>0</MinTileCol>
+            <MaxTileCol>1</MaxTileCol>
+          </TileMatrixLimits>
+        </TileMatrixSetLimits>
+      </TileMatrixSetLink>
+      <TileMatrixSetLink>
+        <TileMatrixSet>EPSG:4326</TileMatrixSet>
+      </TileMatrixSetLink>
+    </Layer>
+  </Contents>
+</Capabilities>
+"""
+
+
+def _response(status_code: int = 200, content: bytes = b"", content_type: str = "") -> MagicMock:
+    response = MagicMock(status_code=status_code, content=content, headers={})
+    response.text = content.decode(errors="replace")
+    if content_type:
+        response.headers["content-type"] = content_type
+    return response
+
+
+def _client(*responses: MagicMock) -> MagicMock:
+    client = MagicMock()
+    client.connection.public_base_url = "https://maps.example.com/geoserver"
+    client.get_tile_service.side_effect = list(responses)
+    return client
+
+
+class TestTileEndpoints:
+    """Tests for building client URLs."""
+
+    def test_urls(self) -> None:
+        """Test every service is listed with the layer, grid set and format."""
+        targets = tile_endpoints(
+            "https://maps.example.com/geoserver/", "topp:roads", "EPSG:900913", "image/png"
+        )
+        urls = {target.label: target.value for target in targets}
+        base = "https://maps.example.com/geoserver/gwc/service"
+
+        assert [target.kind for target in targets] == ["wmts", "wmts", "xyz", "tms", "tms", "wmsc"]
+        assert urls["WMTS capabilities URL (REST)"] == f"{base}/wmts/rest/WMTSCapabilities.xml"
+        assert urls["XYZ tile URL template"].startswith(f"{base}/wmts?")
+        assert "layer=topp:roads" in urls["XYZ tile URL template"]
+        assert "tilematrix=EPSG:900913:{z}&tilerow={y}&tilecol={x}" in urls[
+            "XYZ tile URL template"
+        ]
+        assert "format=image/png" in urls["XYZ tile URL template"]
+        assert urls["TMS tile URL template"] == (
+            f"{base}/tms/1.0.0/topp:roads@EPSG:900913@png/{{z}}/{{x}}/{{-y}}.png"
+        )
+        assert "tiled=true" in urls["WMS-C capabilities URL"]
+
+    @pytest.mark.parametrize(
+        "tile_format,extension",
+        [
+            ("image/png", "png"),
+            ("image/png; mode=8bit", "png"),
+            ("application/vnd.mapbox-vector-tile", "pbf"),
+            ("image/vnd.jpeg-png", "jpeg-png"),
+        ],
+    )
+    def test_tms_extension(self, tile_format: str, extension: str) -> None:
+        """Test TMS URLs use GWC's file extension for the format."""
+        assert tms_extension(tile_format) == extension
+
+
+class TestParseWMTSLayer:
+    """Tests for reading a layer from the WMTS capabilities."""
+
+    def test_layer(self) -> None:
+        """Test formats and a sample tile per grid set are read."""
+        layer = parse_wmts_layer(CAPABILITIES, "topp:roads")
+
+        assert layer is not None
+        assert layer.formats == ["image/png", "image/jpeg"]
+        assert layer.sample_tiles == {
+            "EPSG:900913": ("EPSG:900913:0", 0, 0),
+            "EPSG:4326": ("EPSG:4326:0", 0, 0),
+        }
+
+    def test_missing_layer(self) -> None:
+        """Test a layer that isn't advertised gives None."""
+        assert parse_wmts_layer(CAPABILITIES, "topp:states") is None
+
+    @pytest.mark.parametrize("text", [b"not xml", b"<ServiceExceptionReport/>"])
+    def test_not_capabilities(self, text: bytes) -> None:
+        """Test other documents are rejected."""
+        with pytest.raises(ValueError):
+            parse_wmts_layer(text, "topp:roads")
+
+
+class TestCheckEndpoints:
+    """Tests for checking a layer with a sample tile."""
+
+    def test_ok(self) -> None:
+        """Test a cached layer serving PNG tiles passes."""
+        client = _client(
+            _response(content=CAPABILITIES),
+            _response(content=b"\x89PNG....", content_type="image/png"),
+        )
+
+        check = check_endpoints(client, "topp:roads")
+
+        assert check.ok
+        assert (check.grid_set, check.format) == ("EPSG:900913", "image/png")
+        assert check.size == 8
+        params = client.get_tile_service.call_args.args[1]
+        assert params["tilematrix"] == "EPSG:900913:0"
+        assert check.tile_url.startswith("https://maps.example.com/geoserver/gwc/service/wmts?")
+        assert check.to_dict()["ok"] is True
+
+    def test_format_and_gridset_mismatch(self) -> None:
+        """Test a grid set or format the layer doesn't cache is reported."""
+        client = _client(_response(content=CAPABILITIES))
+
+        check = check_endpoints(client, "topp:roads", "EPSG:3857", "image/webp")
+
+        assert not check.ok
+        assert "Grid set EPSG:3857 isn't cached (cached: EPSG:900913, EPSG:4326)" in check.issues
+        assert "Format image/webp isn't cached (cached: image/png, image/jpeg)" in check.issues
+        assert client.get_tile_service.call_count == 1
+
+    def test_unadvertised_layer(self) -> None:
+        """Test a layer without a tile cache is reported."""
+        check = check_endpoints(_client(_response(content=CAPABILITIES)), "topp:states")
+
+        assert not check.ok
+        assert "isn't in the WMTS capabilities" in check.issues[0]
+
+    def test_tile_failure(self) -> None:
+        """Test a failing tile reports the server's exception."""
+        report = (
+            b'<ows:ExceptionReport xmlns:ows="http://www.opengis.net/ows/1.1">'
+            b"<ows:Exception><ows:ExceptionText>Unknown style</ows:ExceptionText>"
+            b"</ows:Exception></ows:ExceptionReport>"
+        )
+        client = _client(
+            _response(content=CAPABILITIES),
+            _response(400, report, "application/vnd.ogc.se_xml"),
+        )
+
+        check = check_endpoints(client, "topp:roads")
+
+        assert check.status_code == 400
+        assert check.issues == ["The sample tile failed with HTTP 400: Unknown style"]
+
+    def test_wrong_content_type(self) -> None:
+        """Test a tile served in another format is reported."""
+        client = _client(
+            _response(content=CAPABILITIES),
+            _response(content=b"<html/>", content_type="text/html; charset=utf-8"),
+        )
+
+        check = check_endpoints(client, "topp:roads", tile_format="image/jpeg")
+
+        assert check.issues == ["The sample tile came back as text/html, not image/jpeg"]
+
+    def test_capabilities_unavailable(self) -> None:
+        """Test unreadable capabilities raise with the server's status."""
+        client = _client(_response(503, b"Service unavailable"))
+
+        with pytest.raises(GeoServerError) as exc_info:
+            check_endpoints(client, "topp:roads")
+
+        assert exc_info.value.status_code == 503
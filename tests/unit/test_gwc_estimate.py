@@ -0,0 +1,109 @@
+"""Unit tests for GWC seed estimates.
+
+Tests tile range calculation against the standard GeoWebCache grid sets.
+"""
+
+import pytest
+
+from apps.gwc.estimate import (
+    DEFAULT_TILE_BYTES,
+    estimate_seed,
+    lonlat_to_web_mercator,
+    parse_gridset,
+)
+
+WORLD_MERCATOR = 20037508.34
+
+# EPSG:4326 grid set: 2x1 tiles at zoom 0
+EPSG_4326 = {
+    "name": "EPSG:4326",
+    "srs": {"number": 4326},
+    "extent": {"coords": {"double": [-180.0, -90.0, 180.0, 90.0]}},
+    "alignTopLeft": False,
+    "resolutions": {"double": [0.703125 / (2**z) for z in range(22)]},
+    "tileWidth": 256,
+    "tileHeight": 256,
+}
+
+# EPSG:900913 grid set defined by scale denominators
+EPSG_900913 = {
+    "name": "EPSG:900913",
+    "srs": {"number": 900913},
+    "extent": {"coords": [-WORLD_MERCATOR, -WORLD_MERCATOR, WORLD_MERCATOR, WORLD_MERCATOR]},
+    "alignTopLeft": False,
+    "scaleDenominators": [559082264.0287178 / (2**z) for z in range(20)],
+    "metersPerUnit": 1.0,
+    "pixelSize": 0.00028,
+    "tileWidth": 256,
+    "tileHeight": 256,
+}
+
+
+class TestParseGridSet:
+    """Tests for parse_gridset."""
+
+    def test_parse_resolutions(self) -> None:
+        """Test parsing a grid set with explicit resolutions."""
+        gridset = parse_gridset(EPSG_4326)
+        assert gridset.srs == "EPSG:4326"
+        assert gridset.extent == (-180.0, -90.0, 180.0, 90.0)
+        assert len(gridset.resolutions) == 22
+
+    def test_parse_scale_denominators(self) -> None:
+        """Test resolutions are derived from scale denominators."""
+        gridset = parse_gridset(EPSG_900913)
+        assert gridset.resolutions[0] == pytest.approx(156543.03, rel=1e-4)
+
+    def test_missing_extent_raises(self) -> None:
+        """Test a grid set without an extent is rejected."""
+        with pytest.raises(ValueError):
+            parse_gridset({"name": "broken", "resolutions": [1.0]})
+
+
+class TestEstimateSeed:
+    """Tests for estimate_seed."""
+
+    def test_world_4326(self) -> None:
+        """Test full-world tile counts in EPSG:4326."""
+        estimate = estimate_seed(EPSG_4326, 0, 2)
+        assert [z.tiles for z in estimate.zoom_levels] == [2, 8, 32]
+        assert estimate.total_tiles == 42
+        assert estimate.bounds_source == "gridSetExtent"
+
+    def test_world_900913(self) -> None:
+        """Test full-world tile counts in Web Mercator."""
+        estimate = estimate_seed(EPSG_900913, 0, 3)
+        assert [z.tiles for z in estimate.zoom_levels] == [1, 4, 16, 64]
+
+    def test_layer_bounds_in_4326(self) -> None:
+        """Test bounds from the layer's lat/lon bbox limit the tile range."""
+        bbox = {"minx": 0.0, "miny": 0.0, "maxx": 10.0, "maxy": 10.0}
+        estimate = estimate_seed(EPSG_4326, 0, 1, latlon_bbox=bbox)
+        assert estimate.bounds_source == "latLonBoundingBox"
+        assert [z.tiles for z in estimate.zoom_levels] == [1, 1]
+
+    def test_layer_bounds_projected_to_mercator(self) -> None:
+        """Test lat/lon bounds are projected for Web Mercator grid sets."""
+        bbox = {"minx": 1.0, "miny": 1.0, "maxx": 2.0, "maxy": 2.0}
+        estimate = estimate_seed(EPSG_900913, 1, 1, latlon_bbox=bbox)
+        assert estimate.zoom_levels[0].tiles == 1
+
+    def test_zoom_range_is_clamped(self) -> None:
+        """Test zoom levels beyond the grid set are ignored."""
+        estimate = estimate_seed(EPSG_900913, 18, 30, bounds=(0.0, 0.0, 1.0, 1.0))
+        assert [z.zoom for z in estimate.zoom_levels] == [18, 19]
+
+    def test_estimated_bytes(self) -> None:
+        """Test size is tile count times the average tile size."""
+        estimate = estimate_seed(EPSG_4326, 0, 0, tile_format="unknown/type")
+        assert estimate.avg_tile_bytes == DEFAULT_TILE_BYTES
+        assert estimate.estimated_bytes == 2 * DEFAULT_TILE_BYTES
+
+        estimate = estimate_seed(EPSG_4326, 0, 0, avg_tile_bytes=1000)
+        assert estimate.estimated_bytes == 2000
+
+    def test_lonlat_to_web_mercator(self) -> None:
+        """Test projecting the world bbox to Web Mercator."""
+        minx, miny, maxx, maxy = lonlat_to_web_mercator((-180.0, -90.0, 180.0, 90.0))
+        assert minx == pytest.approx(-WORLD_MERCATOR, rel=1e-6)
+        assert maxy == pytest.approx(WORLD_MERCATOR, rel=1e-6)
@@ -0,0 +1,137 @@
+"""Unit tests for layer group caches.
+
+Tests reading what a layer group draws, its extent, finding cached
+groups and turning on caching.
+"""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import layergroup_entries
+from apps.gwc.groups import cached_groups
+
+# A group drawing a layer, a style-only entry and a nested group, as GeoServer returns it
+BASEMAP = {
+    "name": "basemap",
+    "bounds": {"minx": 0, "miny": 0, "maxx": 1, "maxy": 1, "crs": "EPSG:3857"},
+    "publishables": {
+        "published": [
+            {"@type": "layer", "name": "topp:roads"},
+            None,
+            {"@type": "layerGroup", "name": "topp:water"},
+        ]
+    },
+}
+
+WATER = {"name": "water", "publishables": {"published": {"@type": "layer", "name": "topp:lakes"}}}
+
+
+def _bbox(minx: float, miny: float, maxx: float, maxy: float) -> dict:
+    return {"latLonBoundingBox": {"minx": minx, "miny": miny, "maxx": maxx, "maxy": maxy}}
+
+
+class TestLayerGroupEntries:
+    """Tests for reading what a layer group draws."""
+
+    def test_entries(self) -> None:
+        """Test layers and nested groups are listed, skipping style-only entries."""
+        assert layergroup_entries(BASEMAP) == [
+            {"type": "layer", "name": "topp:roads"},
+            {"type": "layerGroup", "name": "topp:water"},
+        ]
+
+    def test_single_entry(self) -> None:
+        """Test a single entry collapsed into an object is read."""
+        assert layergroup_entries(WATER) == [{"type": "layer", "name": "topp:lakes"}]
+        assert layergroup_entries({"name": "empty"}) == []
+
+
+class TestLayerGroupBounds:
+    """Tests for a layer group's lon/lat extent."""
+
+    def test_covers_layers_and_nested_groups(self, make_geoserver_client) -> None:
+        """Test the extent covers every layer, nested groups included."""
+        client = make_geoserver_client()
+        client.get_layergroup = MagicMock(
+            side_effect=lambda name, ws=None: BASEMAP if name == "basemap" else WATER
+        )
+        client.get_layer_bounds = MagicMock(
+            side_effect=lambda ws, name: _bbox(10, 20, 30, 40)
+            if name == "roads"
+            else _bbox(-5, 25, 15, 45)
+        )
+
+        bounds = client.get_layergroup_bounds("basemap", "topp")
+
+        assert bounds["nativeBoundingBox"] == BASEMAP["bounds"]
+        assert bounds["latLonBoundingBox"] == {
+            "minx": -5,
+            "miny": 20,
+            "maxx": 30,
+            "maxy": 45,
+            "crs": "EPSG:4326",
+        }
+
+    def test_unreadable_layers(self, make_geoserver_client) -> None:
+        """Test layers that can't be read are left out of the extent."""
+        client = make_geoserver_client()
+        client.get_layergroup = MagicMock(return_value=WATER)
+        client.get_layer_bounds = MagicMock(side_effect=GeoServerError("gone", status_code=404))
+
+        assert client.get_layergroup_bounds("water", "topp")["latLonBoundingBox"] is None
+
+
+class TestCachedGroups:
+    """Tests for finding cached layer groups."""
+
+    def test_cached_groups(self) -> None:
+        """Test only cached groups are listed, with the layers they draw."""
+        client = MagicMock()
+        client.list_layergroups.return_value = [{"name": "basemap"}, {"name": "water"}]
+        client.get_layergroup.return_value = BASEMAP
+
+        groups = cached_groups(client, "topp", ["topp:roads", "topp:basemap"])
+
+        assert [g.to_dict() for g in groups] == [
+            {"name": "topp:basemap", "layers": layergroup_entries(BASEMAP)}
+        ]
+        client.get_layergroup.assert_called_once_with("basemap", "topp")
+
+    def test_unreadable_group(self) -> None:
+        """Test a group that can't be read is still listed."""
+        client = MagicMock()
+        client.list_layergroups.return_value = [{"name": "basemap"}]
+        client.get_layergroup.side_effect = GeoServerError("denied", status_code=403)
+
+        groups = cached_groups(client, "topp", ["topp:basemap"])
+
+        assert groups[0].name == "topp:basemap"
+        assert groups[0].layers == []
+
+
+class TestEnableLayer:
+    """Tests for turning on caching."""
+
+    def test_defaults(self, make_gwc_client) -> None:
+        """Test a new cache gets GeoServer's default grid sets and formats."""
+        client = make_gwc_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        client.enable_layer("topp:basemap")
+
+        args, kwargs = client._request.call_args
+        assert args == ("PUT", "/gwc/rest/layers/topp%3Abasemap.xml")
+        payload = kwargs["content"]
+        assert "<name>topp:basemap</name>" in payload
+        assert "<string>image/png</string><string>image/jpeg</string>" in payload
+        assert "<gridSetName>EPSG:900913</gridSetName>" in payload
+
+    def test_failure(self, make_gwc_client) -> None:
+        """Test a refused layer raises with the server's status."""
+        client = make_gwc_client()
+        client._request.return_value = MagicMock(status_code=400, text="exists")
+
+        with pytest.raises(GeoServerError, match="Failed to enable tile caching: exists"):
+            client.enable_layer("topp:basemap", ["EPSG:4326"], ["image/png"])
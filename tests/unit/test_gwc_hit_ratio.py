@@ -0,0 +1,176 @@
+"""Unit tests for tile cache hit ratios.
+
+Tests sampling a cached layer's tiles per zoom level and suggesting the
+levels worth pre-seeding.
+"""
+
+import random
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.gwc.estimate import TileRange, parse_gridset, tile_ranges
+from apps.gwc.hit_ratio import (
+    MAX_PROBES,
+    HitRatioReport,
+    ZoomHitRatio,
+    analyze_hit_ratio,
+    parse_runtime_stats,
+    sample_tiles,
+)
+
+# EPSG:4326 grid set: 2x1 tiles at zoom 0, origin at the bottom left
+EPSG_4326 = {
+    "name": "EPSG:4326",
+    "srs": {"number": 4326},
+    "extent": {"coords": {"double": [-180.0, -90.0, 180.0, 90.0]}},
+    "alignTopLeft": False,
+    "resolutions": {"double": [0.703125 / (2**z) for z in range(22)]},
+    "tileWidth": 256,
+    "tileHeight": 256,
+}
+
+HOME_PAGE = """<html><body>
+<h3>Runtime Statistics</h3>
+<table>
+<tr><td>Total number of requests:</td><td>12,345</td></tr>
+<tr><td>Cache hit ratio:</td><td>61.25% of requests</td></tr>
+</table>
+</body></html>"""
+
+
+def _tile(cache_result: str = "", status_code: int = 200) -> MagicMock:
+    headers = {"geowebcache-cache-result": cache_result} if cache_result else {}
+    return MagicMock(status_code=status_code, headers=headers)
+
+
+def _clients(*tiles: MagicMock) -> tuple[MagicMock, MagicMock]:
+    gwc = MagicMock()
+    gwc.get_layer.return_value = {
+        "gridSubsets": [{"gridSetName": "EPSG:4326"}],
+        "mimeFormats": ["image/jpeg", "image/png"],
+    }
+    gwc.get_gridset.return_value = EPSG_4326
+    gwc.get_home_page.return_value = HOME_PAGE
+    gwc.get_tile_service.side_effect = list(tiles)
+    gs = MagicMock()
+    gs.get_layer_bounds.return_value = {
+        "latLonBoundingBox": {"minx": -180.0, "miny": -90.0, "maxx": 180.0, "maxy": 90.0},
+        "nativeBoundingBox": None,
+    }
+    return gwc, gs
+
+
+class TestTileRanges:
+    """Tests for tile_ranges."""
+
+    def test_rows_count_from_the_top(self) -> None:
+        """Test rows of a bottom-left grid are numbered from the top, as in WMTS."""
+        gridset = parse_gridset(EPSG_4326)
+        (level,) = tile_ranges(gridset, (0.0, 50.0, 10.0, 60.0), 2, 2)
+        # 45 degree tiles: 4 rows, the band 45-90N is the top one
+        assert (level.first_row, level.last_row) == (0, 0)
+        assert (level.first_column, level.last_column) == (4, 4)
+
+
+class TestRuntimeStats:
+    """Tests for parse_runtime_stats."""
+
+    def test_parse(self) -> None:
+        """Test reading the request count and hit ratio."""
+        stats = parse_runtime_stats(HOME_PAGE)
+        assert stats is not None
+        assert stats.requests == 12345
+        assert stats.hit_ratio == pytest.approx(0.6125)
+
+    def test_statistics_off(self) -> None:
+        """Test a page without statistics gives None."""
+        assert parse_runtime_stats("<html><body>GeoWebCache</body></html>") is None
+
+
+class TestSampleTiles:
+    """Tests for sample_tiles."""
+
+    def test_all_tiles_when_few(self) -> None:
+        """Test every tile is picked when there are no more than asked for."""
+        tiles = sample_tiles(TileRange(1, 2, 3, 0, 0), 5, random.Random(0))
+        assert tiles == [(0, 2), (0, 3)]
+
+    def test_distinct_tiles_within_range(self) -> None:
+        """Test sampled tiles are distinct and inside the range."""
+        tiles = sample_tiles(TileRange(5, 10, 19, 4, 13), 8, random.Random(0))
+        assert len(set(tiles)) == 8
+        assert all(4 <= row <= 13 and 10 <= col <= 19 for row, col in tiles)
+
+
+class TestSuggestions:
+    """Tests for HitRatioReport.suggestions."""
+
+    def test_consecutive_levels_are_grouped(self) -> None:
+        """Test missing levels merge into ranges per grid set."""
+        report = HitRatioReport("topp:roads", "image/png")
+        report.zooms = [
+            ZoomHitRatio("EPSG:4326", 0, 2, sampled=2, hits=0, misses=2),
+            ZoomHitRatio("EPSG:4326", 1, 8, sampled=2, hits=0, misses=2),
+            ZoomHitRatio("EPSG:4326", 2, 32, sampled=2, hits=2, misses=0),
+            ZoomHitRatio("EPSG:4326", 3, 128, sampled=2, hits=0, misses=2),
+            ZoomHitRatio("EPSG:900913", 4, 256, sampled=2, hits=0, misses=2),
+        ]
+        suggestions = [(s.grid_set, s.zoom_start, s.zoom_stop, s.tiles) for s in report.suggestions()]
+        assert suggestions == [
+            ("EPSG:4326", 0, 1, 10),
+            ("EPSG:4326", 3, 3, 128),
+            ("EPSG:900913", 4, 4, 256),
+        ]
+
+    def test_large_and_unanswered_levels_are_skipped(self) -> None:
+        """Test levels with too many tiles or no results aren't suggested."""
+        report = HitRatioReport("topp:roads", "image/png")
+        report.zooms = [
+            ZoomHitRatio("EPSG:4326", 12, 10_000_000, sampled=2, misses=2),
+            ZoomHitRatio("EPSG:4326", 13, 10, sampled=2, errors=2),
+        ]
+        assert report.suggestions() == []
+        assert report.hit_ratio == 0.0
+
+
+class TestAnalyzeHitRatio:
+    """Tests for analyze_hit_ratio."""
+
+    def test_counts_hits_and_misses(self) -> None:
+        """Test cache results are counted per zoom level."""
+        gwc, gs = _clients(_tile("HIT"), _tile("MISS"), _tile("HIT"), _tile(status_code=500))
+        report = analyze_hit_ratio(gwc, gs, "topp", "roads", zoom_start=0, zoom_stop=1, samples=2)
+
+        assert report.format == "image/png"
+        assert report.runtime is not None and report.runtime.requests == 12345
+        level0, level1 = report.zooms
+        assert (level0.tiles, level0.hits, level0.misses) == (2, 1, 1)
+        assert (level1.tiles, level1.hits, level1.errors) == (8, 1, 1)
+        assert report.hit_ratio == pytest.approx(2 / 3)
+
+        params = gwc.get_tile_service.call_args_list[0].args[1]
+        assert params["layer"] == "topp:roads"
+        assert params["tilematrix"] == "EPSG:4326:0"
+
+    def test_statistics_are_optional(self) -> None:
+        """Test the analysis runs when the home page can't be read."""
+        gwc, gs = _clients(_tile("MISS"), _tile("MISS"))
+        gwc.get_home_page.side_effect = GeoServerError("nope")
+        report = analyze_hit_ratio(gwc, gs, "topp", "roads", zoom_start=0, zoom_stop=0)
+        assert report.runtime is None
+        assert report.zooms[0].worth_seeding
+
+    def test_uncached_grid_set(self) -> None:
+        """Test asking for a grid set the layer doesn't cache fails."""
+        gwc, gs = _clients()
+        with pytest.raises(ValueError, match="EPSG:3857"):
+            analyze_hit_ratio(gwc, gs, "topp", "roads", grid_sets=["EPSG:3857"])
+
+    def test_too_many_probes(self) -> None:
+        """Test runs beyond the request limit are refused before sampling."""
+        gwc, gs = _clients()
+        with pytest.raises(ValueError, match=str(MAX_PROBES)):
+            analyze_hit_ratio(gwc, gs, "topp", "roads", zoom_start=0, zoom_stop=20, samples=20)
+        gwc.get_tile_service.assert_not_called()
@@ -0,0 +1,202 @@
+"""Unit tests for automatic tile cache invalidation."""
+
+from unittest.mock import MagicMock, patch
+
+from apps.core.config import Connection
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.capabilities import build_capabilities
+from apps.gwc.invalidation import (
+    find_style_layers,
+    invalidate_layer_cache,
+    invalidate_store_cache,
+    invalidate_style_cache,
+    truncate_style_layers,
+)
+
+
+def _config(auto_truncate: bool) -> MagicMock:
+    config = MagicMock()
+    config.get_connection.return_value = Connection(
+        id="conn_1",
+        name="Test",
+        url="http://localhost:8080/geoserver",
+        username="admin",
+        password="geoserver",
+        auto_truncate_cache=auto_truncate,
+    )
+    return config
+
+
+class TestCacheInvalidation:
+    """Tests for cache invalidation on data changes."""
+
+    def test_disabled_connection_is_untouched(self) -> None:
+        """Test nothing is truncated when auto-truncate is off."""
+        gwc = MagicMock()
+        with patch("apps.gwc.invalidation.get_config", return_value=_config(False)), patch(
+            "apps.gwc.invalidation.get_gwc_client", return_value=gwc
+        ):
+            assert invalidate_layer_cache("conn_1", "topp", "roads") == []
+        gwc.truncate_entire_layer.assert_not_called()
+
+    def test_store_layers_are_truncated(self) -> None:
+        """Test every layer of a re-uploaded store is truncated."""
+        geoserver = MagicMock()
+        geoserver.list_featuretypes.return_value = [{"name": "roads"}, {"name": "rivers"}]
+        gwc = MagicMock()
+
+        with patch("apps.gwc.invalidation.get_config", return_value=_config(True)), patch(
+            "apps.gwc.invalidation.get_geoserver_client", return_value=geoserver
+        ), patch("apps.gwc.invalidation.get_gwc_client", return_value=gwc):
+            truncated = invalidate_store_cache("conn_1", "topp", "hydro", "datastore")
+
+        assert truncated == ["topp:roads", "topp:rivers"]
+        geoserver.list_featuretypes.assert_called_once_with("topp", "hydro")
+
+    def test_coverage_store_uses_coverages(self) -> None:
+        """Test coverage stores are resolved through their coverages."""
+        geoserver = MagicMock()
+        geoserver.list_coverages.return_value = [{"name": "dem"}]
+        gwc = MagicMock()
+
+        with patch("apps.gwc.invalidation.get_config", return_value=_config(True)), patch(
+            "apps.gwc.invalidation.get_geoserver_client", return_value=geoserver
+        ), patch("apps.gwc.invalidation.get_gwc_client", return_value=gwc):
+            truncated = invalidate_store_cache("conn_1", "topp", "elevation", "coveragestore")
+
+        assert truncated == ["topp:dem"]
+
+    def test_uncached_layers_are_skipped(self) -> None:
+        """Test layers without a tile cache don't fail the invalidation."""
+        gwc = MagicMock()
+        gwc.truncate_entire_layer.side_effect = GeoServerError("Unknown layer", status_code=400)
+
+        with patch("apps.gwc.invalidation.get_config", return_value=_config(True)), patch(
+            "apps.gwc.invalidation.get_gwc_client", return_value=gwc
+        ):
+            assert invalidate_layer_cache("conn_1", "topp", "roads") == []
+
+
+def _geoserver_with_layers(layers: dict[str, dict]) -> MagicMock:
+    geoserver = MagicMock()
+    geoserver.list_layers.return_value = [{"name": name} for name in layers]
+    geoserver.get_layer.side_effect = lambda workspace, name: layers[name]
+    return geoserver
+
+
+class TestStyleCacheInvalidation:
+    """Tests for style-aware cache invalidation."""
+
+    LAYERS = {
+        "roads": {"defaultStyle": {"name": "topp:roads"}},
+        "rivers": {
+            "defaultStyle": {"name": "line"},
+            "styles": {"style": [{"name": "polygon"}, {"name": "topp:roads"}]},
+        },
+        "lakes": {"defaultStyle": {"name": "polygon"}, "styles": {"style": {"name": "line"}}},
+    }
+
+    def test_find_style_layers(self) -> None:
+        """Test default and additional style usages are detected."""
+        geoserver = _geoserver_with_layers(self.LAYERS)
+        with patch("apps.gwc.invalidation.get_geoserver_client", return_value=geoserver):
+            affected = find_style_layers("conn_1", "topp", "roads")
+
+        assert affected == [
+            {"layer": "topp:roads", "usage": "default"},
+            {"layer": "topp:rivers", "usage": "additional"},
+        ]
+
+    def test_find_global_style_layers(self) -> None:
+        """Test a global style is found on the layers of every workspace."""
+        layers = {
+            "topp:roads": {"defaultStyle": {"name": "line"}},
+            "osm:rivers": {
+                "defaultStyle": {"name": "polygon"},
+                "styles": {"style": {"name": "line"}},
+            },
+            "osm:lakes": {"defaultStyle": {"name": "osm:line"}},
+        }
+        geoserver = MagicMock()
+        geoserver.list_layers.return_value = [{"name": name} for name in layers]
+        geoserver.get_layer.side_effect = lambda workspace, name: layers[f"{workspace}:{name}"]
+        with patch("apps.gwc.invalidation.get_geoserver_client", return_value=geoserver):
+            affected = find_style_layers("conn_1", "", "line")
+
+        geoserver.list_layers.assert_called_once_with("")
+        assert affected == [
+            {"layer": "topp:roads", "usage": "default"},
+            {"layer": "osm:rivers", "usage": "additional"},
+        ]
+
+    def test_truncate_uses_styles_parameter_for_additional(self) -> None:
+        """Test additional usages only truncate the matching STYLES tiles."""
+        geoserver = MagicMock()
+        geoserver.capabilities = build_capabilities("2.25.0")
+        gwc = MagicMock()
+        affected = [
+            {"layer": "topp:roads", "usage": "default"},
+            {"layer": "topp:rivers", "usage": "additional"},
+        ]
+        with patch("apps.gwc.invalidation.get_geoserver_client", return_value=geoserver), patch(
+            "apps.gwc.invalidation.get_gwc_client", return_value=gwc
+        ):
+            truncated = truncate_style_layers("conn_1", "topp", "roads", affected)
+
+        assert truncated == ["topp:roads", "topp:rivers"]
+        gwc.truncate_entire_layer.assert_called_once_with("topp:roads")
+        gwc.truncate_layer_parameters.assert_called_once_with(
+            "topp:rivers", {"STYLES": "topp:roads"}
+        )
+
+    def test_truncate_whole_layer_without_parameter_support(self) -> None:
+        """Test old servers have additional usages truncated entirely."""
+        geoserver = MagicMock()
+        geoserver.capabilities = build_capabilities("2.12.0")
+        gwc = MagicMock()
+        affected = [{"layer": "topp:rivers", "usage": "additional"}]
+        with patch("apps.gwc.invalidation.get_geoserver_client", return_value=geoserver), patch(
+            "apps.gwc.invalidation.get_gwc_client", return_value=gwc
+        ):
+            truncated = truncate_style_layers("conn_1", "topp", "roads", affected)
+
+        assert truncated == ["topp:rivers"]
+        gwc.truncate_entire_layer.assert_called_once_with("topp:rivers")
+        gwc.truncate_layer_parameters.assert_not_called()
+
+    def test_global_style_parameter(self) -> None:
+        """Test a global style is truncated by its bare name."""
+        geoserver = MagicMock()
+        geoserver.capabilities = build_capabilities("2.25.0")
+        gwc = MagicMock()
+        affected = [{"layer": "topp:rivers", "usage": "additional"}]
+        with patch("apps.gwc.invalidation.get_geoserver_client", return_value=geoserver), patch(
+            "apps.gwc.invalidation.get_gwc_client", return_value=gwc
+        ):
+            truncate_style_layers("conn_1", "", "roads", affected)
+
+        gwc.truncate_layer_parameters.assert_called_once_with("topp:rivers", {"STYLES": "roads"})
+
+    def test_truncate_whole_layer_when_capabilities_unknown(self) -> None:
+        """Test additional usages are truncated entirely if the server can't be asked."""
+        gwc = MagicMock()
+        affected = [{"layer": "topp:rivers", "usage": "additional"}]
+        with patch(
+            "apps.gwc.invalidation.get_geoserver_client", side_effect=ValueError("gone")
+        ), patch("apps.gwc.invalidation.get_gwc_client", return_value=gwc):
+            truncate_style_layers("conn_1", "topp", "roads", affected)
+
+        gwc.truncate_entire_layer.assert_called_once_with("topp:rivers")
+
+    def test_style_change_without_auto_truncate_only_reports(self) -> None:
+        """Test affected layers are reported but not truncated when disabled."""
+        geoserver = _geoserver_with_layers(self.LAYERS)
+        gwc = MagicMock()
+        with patch("apps.gwc.invalidation.get_config", return_value=_config(False)), patch(
+            "apps.gwc.invalidation.get_geoserver_client", return_value=geoserver
+        ), patch("apps.gwc.invalidation.get_gwc_client", return_value=gwc):
+            affected, truncated = invalidate_style_cache("conn_1", "topp", "line")
+
+        assert [a["layer"] for a in affected] == ["topp:rivers", "topp:lakes"]
+        assert truncated == []
+        gwc.truncate_entire_layer.assert_not_called()
@@ -0,0 +1,128 @@
+"""Unit tests for truncating the tile caches of many layers."""
+
+import threading
+from unittest.mock import MagicMock, patch
+
+from apps.core.exceptions import GeoServerError
+from apps.core.oplog import OperationLog
+from apps.gwc.truncate import (
+    MassTruncateJobManager,
+    mass_truncate,
+    matching_layers,
+    summarize,
+)
+
+
+def _client(errors: dict[str, GeoServerError]) -> MagicMock:
+    """A GWC client failing to truncate some layers."""
+    client = MagicMock()
+
+    def truncate(layer: str) -> dict:
+        if layer in errors:
+            raise errors[layer]
+        return {"status": "truncated", "layer": layer}
+
+    client.truncate_entire_layer.side_effect = truncate
+    return client
+
+
+class TestMatchingLayers:
+    """Tests for choosing the layers to truncate."""
+
+    def test_workspace_and_pattern(self) -> None:
+        """Test layers are filtered by workspace and by name pattern."""
+        client = MagicMock()
+        client.list_layers.return_value = [
+            "topp:roads_a",
+            "topp:rivers",
+            "sf:roads_b",
+            "topp:roads_c",
+        ]
+
+        assert matching_layers(client, "topp", "roads_*") == ["topp:roads_a", "topp:roads_c"]
+        assert matching_layers(client, pattern="roads_*") == [
+            "sf:roads_b",
+            "topp:roads_a",
+            "topp:roads_c",
+        ]
+
+
+class TestMassTruncate:
+    """Tests for concurrent truncation."""
+
+    def test_continues_past_failures(self) -> None:
+        """Test every layer gets a result, in order, whatever fails."""
+        client = _client(
+            {
+                "topp:rivers": GeoServerError("Server error", 500),
+                "topp:lakes": GeoServerError("No such layer", 404),
+            }
+        )
+        progress = MagicMock()
+
+        results = mass_truncate(
+            client, ["topp:roads", "topp:rivers", "topp:lakes", "topp:parks"], progress=progress
+        )
+
+        assert [(r.target, r.ok) for r in results] == [
+            ("topp:roads", True),
+            ("topp:rivers", False),
+            ("topp:lakes", True),
+            ("topp:parks", True),
+        ]
+        assert results[2].message == "No tile cache"
+        assert summarize(results) == {"total": 4, "truncated": 2, "skipped": 1, "failed": 1}
+        assert sorted(call.args[0] for call in progress.call_args_list) == [1, 2, 3, 4]
+
+    def test_runs_concurrently(self) -> None:
+        """Test several layers are truncated at once."""
+        barrier = threading.Barrier(3, timeout=5)
+        client = MagicMock()
+        client.truncate_entire_layer.side_effect = lambda layer: barrier.wait()
+
+        results = mass_truncate(client, ["topp:a", "topp:b", "topp:c"], max_workers=3)
+
+        assert all(r.ok for r in results)
+
+
+class TestMassTruncateJobs:
+    """Tests for background mass truncations."""
+
+    def _wait(self, job) -> None:
+        for _ in range(100):
+            if job.status == "completed":
+                break
+            threading.Event().wait(0.01)
+
+    def test_job_reports_progress_and_summary(self, tmp_path) -> None:
+        """Test a job collects results and completes."""
+        client = _client({"topp:rivers": GeoServerError("Server error", 500)})
+        manager = MassTruncateJobManager()
+
+        with patch("apps.gwc.truncate.operation_log", OperationLog(tmp_path)):
+            job = manager.start("conn1", client, ["topp:roads", "topp:rivers"])
+            self._wait(job)
+
+        data = manager.get_job(job.id).to_dict()
+        assert data["status"] == "completed"
+        assert data["done"] == data["total"] == 2
+        assert data["summary"]["failed"] == 1
+
+    def test_resume_truncates_what_was_left(self, tmp_path) -> None:
+        """Test a failed layer stays in the log and a resume only truncates it."""
+        log = OperationLog(tmp_path)
+        client = _client({"topp:rivers": GeoServerError("Connection reset", 502)})
+        manager = MassTruncateJobManager()
+
+        with patch("apps.gwc.truncate.operation_log", log):
+            job = manager.start("conn1", client, ["topp:roads", "topp:rivers"])
+            self._wait(job)
+            client.truncate_entire_layer.side_effect = None
+            client.truncate_entire_layer.reset_mock()
+
+            resumed = manager.start("conn1", client, [], operation=log.resume(job.operation_id))
+            self._wait(resumed)
+
+        client.truncate_entire_layer.assert_called_once_with("topp:rivers")
+        assert resumed.layers == ["topp:rivers"]
+        assert log.interrupted() == []
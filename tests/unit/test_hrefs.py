@@ -0,0 +1,115 @@
+"""Unit tests for REST href handling behind proxies."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.config import Connection
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.hrefs import rest_path, store_from_href
+
+
+class TestRestPath:
+    """Tests for reducing hrefs to REST paths."""
+
+    def test_host_and_prefix_are_dropped(self) -> None:
+        """Test hrefs from any host or prefix resolve to the same path."""
+        hrefs = [
+            "http://localhost:8080/geoserver/rest/workspaces/topp.json",
+            "https://proxy.example.com/maps/geoserver/rest/workspaces/topp.json",
+            "https://geo.example.com/rest/workspaces/topp.json",
+            "/geoserver/rest/workspaces/topp.json",
+        ]
+
+        assert {rest_path(h) for h in hrefs} == {"/rest/workspaces/topp.json"}
+
+    def test_query_string_ignored(self) -> None:
+        """Test query strings added by proxies are dropped."""
+        href = "https://proxy/geoserver/rest/layers/roads.json?token=abc"
+        assert rest_path(href) == "/rest/layers/roads.json"
+
+    def test_prefix_containing_rest(self) -> None:
+        """Test a prefix that itself contains a rest segment."""
+        href = "https://proxy/rest/geoserver/rest/styles/line.json"
+        assert rest_path(href) == "/rest/styles/line.json"
+
+    def test_non_rest_href(self) -> None:
+        """Test hrefs outside the REST API are rejected."""
+        assert rest_path("https://proxy/geoserver/wms") is None
+        assert rest_path("") is None
+
+
+class TestStoreFromHref:
+    """Tests for extracting stores from resource hrefs."""
+
+    def test_feature_type_href(self) -> None:
+        """Test a feature type href names its data store."""
+        href = "https://proxy/maps/rest/workspaces/topp/datastores/roads/featuretypes/roads.json"
+        assert store_from_href(href) == ("topp", "datastores", "roads")
+
+    def test_coverage_href(self) -> None:
+        """Test a coverage href names its coverage store."""
+        href = "http://gs/geoserver/rest/workspaces/nurc/coveragestores/dem/coverages/dem.json"
+        assert store_from_href(href) == ("nurc", "coveragestores", "dem")
+
+    def test_store_href_with_extension(self) -> None:
+        """Test a store's own href drops the format extension."""
+        href = "http://gs/geoserver/rest/workspaces/topp/datastores/roads.json"
+        assert store_from_href(href) == ("topp", "datastores", "roads")
+
+    def test_encoded_names(self) -> None:
+        """Test percent-encoded names are decoded."""
+        href = "http://gs/geoserver/rest/workspaces/my%20ws/datastores/my%20store.json"
+        assert store_from_href(href) == ("my ws", "datastores", "my store")
+
+    def test_href_without_store(self) -> None:
+        """Test hrefs that don't name a store return None."""
+        assert store_from_href("http://gs/geoserver/rest/layers/roads.json") is None
+
+
+class TestClientHrefs:
+    """Tests for the client following hrefs."""
+
+    def test_get_href_uses_connection_url(self, make_geoserver_client) -> None:
+        """Test hrefs are fetched by path, not by the reported host."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=200)
+        client._request.return_value.json.return_value = {"featureType": {}}
+
+        client.get_href("https://proxy/maps/rest/layers/roads.json")
+
+        client._request.assert_called_once_with("GET", "/rest/layers/roads.json")
+
+    def test_get_href_rejects_non_rest_links(self, make_geoserver_client) -> None:
+        """Test non-REST hrefs raise instead of being fetched."""
+        client = make_geoserver_client()
+
+        with pytest.raises(GeoServerError):
+            client.get_href("https://proxy/geoserver/wms")
+
+        client._request.assert_not_called()
+
+
+class TestPublicUrl:
+    """Tests for the public base URL override."""
+
+    def _connection(self, public_url: str = "") -> Connection:
+        return Connection(
+            name="Test",
+            url="http://geoserver:8080/geoserver/",
+            username="admin",
+            password="geoserver",
+            public_url=public_url,
+        )
+
+    def test_defaults_to_connection_url(self) -> None:
+        """Test the REST URL is used when no public URL is set."""
+        assert self._connection().public_base_url == "http://geoserver:8080/geoserver"
+
+    def test_override(self, make_geoserver_client) -> None:
+        """Test the public URL replaces the REST URL for OWS links."""
+        client = make_geoserver_client(public_url="https://maps.example.com/geoserver/")
+
+        url = client.service_url("wms", "topp")
+
+        assert url == "https://maps.example.com/geoserver/topp/wms"
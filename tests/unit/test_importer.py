@@ -0,0 +1,199 @@
+"""Unit tests for the GeoServer Importer client."""
+
+import io
+import zipfile
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.importer import (
+    ImporterClient,
+    ImportResult,
+    ImportTask,
+    archive_datasets,
+    needs_importer,
+)
+
+
+def _zip(*names: str) -> bytes:
+    buffer = io.BytesIO()
+    with zipfile.ZipFile(buffer, "w") as archive:
+        for name in names:
+            archive.writestr(name, b"")
+    return buffer.getvalue()
+
+
+def _response(status_code: int = 200, body: dict | None = None) -> MagicMock:
+    response = MagicMock(status_code=status_code, content=b"{}" if body is not None else b"")
+    response.json.return_value = body or {}
+    return response
+
+
+class FakeClock:
+    """Manually advanced clock; sleeping advances time."""
+
+    def __init__(self) -> None:
+        self.now = 0.0
+
+    def __call__(self) -> float:
+        return self.now
+
+    def sleep(self, seconds: float) -> None:
+        self.now += seconds
+
+
+class TestArchiveDetection:
+    """Tests for deciding when to use the importer."""
+
+    def test_many_shapefiles(self) -> None:
+        """Test a zip of several shapefiles needs the importer."""
+        data = _zip("roads.shp", "roads.dbf", "rivers.shp", "rivers.dbf")
+
+        assert archive_datasets("data.zip", data) == ["roads.shp", "rivers.shp"]
+        assert needs_importer("data.zip", data)
+
+    def test_mixed_formats(self) -> None:
+        """Test a zip mixing vector and raster data needs the importer."""
+        assert needs_importer("data.zip", _zip("roads.shp", "dem.tif"))
+
+    def test_single_shapefile(self) -> None:
+        """Test a single shapefile keeps using the file upload."""
+        assert not needs_importer("roads.zip", _zip("roads.shp", "roads.dbf", "roads.prj"))
+
+    def test_macos_metadata_ignored(self) -> None:
+        """Test __MACOSX resource forks aren't counted as datasets."""
+        assert not needs_importer("roads.zip", _zip("roads.shp", "__MACOSX/._roads.shp"))
+
+    def test_non_zip_files(self) -> None:
+        """Test non-archives and corrupt archives never need the importer."""
+        assert not needs_importer("dem.tif", b"II*\x00")
+        assert not needs_importer("broken.zip", b"not a zip")
+
+
+class TestImportModels:
+    """Tests for task and result parsing."""
+
+    def test_task_from_json(self) -> None:
+        """Test target store and layer are read from a task document."""
+        task = ImportTask.from_json(
+            {
+                "id": 1,
+                "state": "COMPLETE",
+                "layer": {"name": "dem"},
+                "target": {"coverageStore": {"name": "dem"}},
+            }
+        )
+
+        assert (task.layer, task.store, task.store_type) == ("dem", "dem", "coverageStore")
+
+    def test_result_summary(self) -> None:
+        """Test completed and failed tasks are separated."""
+        result = ImportResult(
+            id=3,
+            state="PENDING",
+            tasks=[
+                ImportTask(0, "COMPLETE", layer="roads", store="data"),
+                ImportTask(1, "NO_CRS", layer="rivers"),
+            ],
+        )
+
+        data = result.to_dict()
+        assert data["importedLayers"] == ["roads"]
+        assert data["importedStores"] == ["data"]
+        assert [t["layer"] for t in data["failedTasks"]] == ["rivers"]
+
+
+class TestImporterClient:
+    """Tests for the importer REST calls."""
+
+    def _importer(self, responses: list[MagicMock]) -> tuple[ImporterClient, MagicMock]:
+        client = MagicMock()
+        client._request.side_effect = responses
+        clock = FakeClock()
+        return ImporterClient(client, clock=clock, sleep=clock.sleep), client
+
+    def test_availability_is_cached(self) -> None:
+        """Test the extension is probed once."""
+        importer, client = self._importer([_response(404)])
+
+        assert not importer.is_available()
+        assert not importer.is_available()
+        assert client._request.call_count == 1
+
+    def test_create_import_targets_workspace_and_store(self) -> None:
+        """Test the import context names the target workspace and store."""
+        importer, client = self._importer([_response(201, {"import": {"id": 7}})])
+
+        assert importer.create_import("topp", "roads") == 7
+
+        payload = client._request.call_args.kwargs["json"]["import"]
+        assert payload["targetWorkspace"] == {"workspace": {"name": "topp"}}
+        assert payload["targetStore"] == {"dataStore": {"name": "roads"}}
+
+    def test_add_file_returns_all_tasks(self) -> None:
+        """Test every dataset in an uploaded archive becomes a task."""
+        body = {"tasks": [{"id": 0, "state": "READY"}, {"id": 1, "state": "NO_CRS"}]}
+        importer, client = self._importer([_response(201, body)])
+
+        tasks = importer.add_file(7, "data.zip", b"zip")
+
+        assert [t.state for t in tasks] == ["READY", "NO_CRS"]
+        filename, data = client._request.call_args.kwargs["files"]["filedata"]
+        assert (filename, data.read()) == ("data.zip", b"zip")
+
+    def test_wait_reports_progress_until_finished(self) -> None:
+        """Test polling stops once no task is left to run."""
+        running = [{"id": 0, "state": "COMPLETE"}, {"id": 1, "state": "RUNNING"}]
+        finished = [{"id": 0, "state": "COMPLETE"}, {"id": 1, "state": "NO_CRS"}]
+        importer, _ = self._importer(
+            [
+                _response(200, {"import": {"state": "RUNNING", "tasks": running}}),
+                _response(200, {"import": {"state": "PENDING", "tasks": finished}}),
+            ]
+        )
+        progress = []
+
+        context = importer.wait_for_import(7, on_progress=lambda d, t: progress.append((d, t)))
+
+        assert context["state"] == "PENDING"
+        assert progress == [(1, 2), (2, 2)]
+
+    def test_wait_times_out(self) -> None:
+        """Test a stuck import raises after the timeout."""
+        running = {"import": {"state": "RUNNING", "tasks": [{"id": 0, "state": "RUNNING"}]}}
+        importer, _ = self._importer([_response(200, running) for _ in range(5)])
+
+        with pytest.raises(GeoServerError):
+            importer.wait_for_import(7, timeout=2, interval=1)
+
+    def test_import_file_assigns_missing_srs(self) -> None:
+        """Test tasks without a CRS get the requested SRS before running."""
+        importer, client = self._importer(
+            [
+                _response(201, {"import": {"id": 7}}),
+                _response(201, {"task": {"id": 0, "state": "NO_CRS"}}),
+                _response(200, {}),  # set SRS
+                _response(204),  # run
+                _response(200, {"import": {"state": "COMPLETE", "tasks": [{"id": 0}]}}),
+                _response(200, {"task": {"id": 0, "state": "COMPLETE", "layer": {"name": "a"}}}),
+            ]
+        )
+
+        result = importer.import_file("topp", "data.zip", b"zip", srs="EPSG:4326")
+
+        assert result.layers == ["a"]
+        srs_call = client._request.call_args_list[2]
+        assert srs_call.args[1] == "/rest/imports/7/tasks/0/layer.json"
+        assert srs_call.kwargs["json"] == {"layer": {"srs": "EPSG:4326"}}
+
+    def test_failed_import_is_deleted(self) -> None:
+        """Test the import context is removed when adding files fails."""
+        importer, client = self._importer(
+            [_response(201, {"import": {"id": 7}}), _response(400), _response(204)]
+        )
+
+        with pytest.raises(GeoServerError):
+            importer.import_file("topp", "data.zip", b"zip")
+
+        assert client._request.call_args.args == ("DELETE", "/rest/imports/7")
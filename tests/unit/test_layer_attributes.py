@@ -0,0 +1,144 @@
+"""Unit tests for layer attribute tables."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.geoserver.attributes import (
+    Attribute,
+    attribute_filter,
+    column_filter,
+    export_attribute_csv,
+    parse_column_filters,
+    read_attribute_table,
+    read_attributes,
+)
+from apps.geoserver.schema import parse_feature_type
+
+PROPERTIES = [
+    {"name": "the_geom", "type": "gml:MultiPolygon", "localType": "MultiPolygon"},
+    {"name": "name", "type": "xsd:string", "localType": "string"},
+    {"name": "population", "type": "xsd:int", "localType": "int"},
+]
+
+SCHEMA = parse_feature_type({"featureTypes": [{"typeName": "states", "properties": PROPERTIES}]})
+
+
+def _features(*names: str, matched: int | None = None) -> dict:
+    data: dict = {
+        "type": "FeatureCollection",
+        "features": [
+            {"type": "Feature", "geometry": None, "properties": {"name": n, "population": 1}}
+            for n in names
+        ],
+    }
+    if matched is not None:
+        data["numberMatched"] = matched
+    return data
+
+
+def _client(*pages: dict) -> MagicMock:
+    client = MagicMock()
+    client.describe_feature_type.return_value = SCHEMA
+    client.get_features.side_effect = list(pages)
+    return client
+
+
+class TestColumnFilters:
+    """Tests for turning column filters into CQL."""
+
+    def test_geometry_left_out(self) -> None:
+        """Test geometry attributes aren't table columns."""
+        assert [a.name for a in read_attributes(SCHEMA)] == ["name", "population"]
+
+    def test_text_matches_anywhere(self) -> None:
+        """Test plain text becomes a case-insensitive substring match."""
+        assert column_filter(Attribute("name", "string"), " o'hara ") == (
+            "\"name\" ILIKE '%o''hara%'"
+        )
+
+    def test_numeric_comparison(self) -> None:
+        """Test comparisons on numeric columns compare numbers."""
+        population = Attribute("population", "int")
+
+        assert column_filter(population, ">= 1000") == '"population" >= 1000'
+        assert column_filter(Attribute("name", "string"), "!= closed") == "\"name\" != 'closed'"
+
+    def test_filters_combined(self) -> None:
+        """Test empty filters are skipped and the rest are ANDed."""
+        attributes = read_attributes(SCHEMA)
+
+        assert attribute_filter(attributes, {"name": "park", "population": "< 5"}) == (
+            "\"name\" ILIKE '%park%' AND \"population\" < 5"
+        )
+        assert attribute_filter(attributes, {"name": "", "population": " "}) is None
+
+    def test_unknown_filter_column(self) -> None:
+        """Test filters on missing columns are refused."""
+        with pytest.raises(ValueError, match="Unknown column"):
+            attribute_filter(read_attributes(SCHEMA), {"the_geom": "1"})
+
+    def test_parse_typed_filters(self) -> None:
+        """Test the TUI filter syntax."""
+        assert parse_column_filters("name: park; population: >= 1000;") == {
+            "name": "park",
+            "population": ">= 1000",
+        }
+        with pytest.raises(ValueError, match="column: value"):
+            parse_column_filters("park")
+
+
+class TestAttributeTable:
+    """Tests for paging through a layer's attributes."""
+
+    def test_page_request(self) -> None:
+        """Test sorting, filters and attribute names are sent to WFS."""
+        client = _client(_features("a", "b", matched=12))
+
+        page = read_attribute_table(
+            client,
+            "topp",
+            "states",
+            limit=2,
+            offset=4,
+            sort="name",
+            descending=True,
+            filters={"name": "a"},
+        )
+
+        kwargs = client.get_features.call_args.kwargs
+        assert kwargs["count"] == 2 and kwargs["start_index"] == 4
+        assert kwargs["sort_by"] == "name DESC"
+        assert kwargs["cql_filter"] == "\"name\" ILIKE '%a%'"
+        assert kwargs["property_names"] == ["name", "population"]
+        assert page.to_dict()["rows"] == [
+            {"name": "a", "population": 1},
+            {"name": "b", "population": 1},
+        ]
+        assert page.total == 12 and page.has_more
+
+    def test_unknown_sort_column(self) -> None:
+        """Test sorting by a missing column is refused before querying."""
+        client = _client()
+
+        with pytest.raises(ValueError, match="Unknown column"):
+            read_attribute_table(client, "topp", "states", sort="the_geom")
+        client.get_features.assert_not_called()
+
+    def test_uncounted_total(self) -> None:
+        """Test a full page without a match count assumes more rows."""
+        client = _client(_features("a", "b"))
+
+        page = read_attribute_table(client, "topp", "states", limit=2)
+
+        assert page.has_more
+
+    def test_export_pages_through_rows(self) -> None:
+        """Test the export fetches pages until the rows run out."""
+        client = _client(_features("a", "b", matched=3), _features("c", matched=3))
+
+        csv = export_attribute_csv(client, "topp", "states", max_rows=10)
+
+        assert csv.splitlines() == ["name,population", "a,1", "b,1", "c,1"]
+        assert client.describe_feature_type.call_count == 1
+        assert client.get_features.call_args.kwargs["start_index"] == 2
@@ -0,0 +1,84 @@
+"""Unit tests for layer usage figures."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.capabilities import build_capabilities
+from apps.geoserver.monitor import count_layer_requests, layer_request_stats
+from apps.gwc.usage import get_layer_quota_usage
+
+
+def _request(start: str, *resources: str) -> dict:
+    return {"startTime": start, "resources": list(resources)}
+
+
+class TestLayerRequests:
+    """Tests for counting monitored requests per layer."""
+
+    def test_counts_qualified_and_bare_names(self) -> None:
+        """Test requests naming the layer either way are counted."""
+        requests = [
+            _request("2026-10-15T10:00:00", "topp:roads"),
+            _request("2026-10-14T10:00:00", "topp:states"),
+            _request("2026-10-13T10:00:00", "roads", "states"),
+        ]
+
+        stats = count_layer_requests(requests, "topp", "roads", sample=10)
+
+        assert stats.requests == 2
+        assert stats.last_request == "2026-10-15T10:00:00"
+        assert stats.complete
+
+    def test_wrapped_resources(self) -> None:
+        """Test XStream-wrapped resource lists are read."""
+        requests = [{"startTime": "2026-10-15", "resources": {"string": "topp:roads"}}]
+
+        assert count_layer_requests(requests, "topp", "roads").requests == 1
+
+    def test_full_sample_is_incomplete(self) -> None:
+        """Test a sample that filled up gives a lower bound."""
+        requests = [_request("2026-10-15", "topp:roads")] * 3
+
+        stats = count_layer_requests(requests, "topp", "roads", sample=3)
+
+        assert stats.requests == 3
+        assert not stats.complete
+        assert stats.to_dict()["complete"] is False
+
+    def test_needs_monitoring_extension(self) -> None:
+        """Test the monitor isn't queried without the extension."""
+        client = MagicMock()
+        client.capabilities = build_capabilities("2.24.1", extensions={"monitoring": False})
+
+        with pytest.raises(GeoServerError, match="Monitoring extension"):
+            layer_request_stats(client, "topp", "roads")
+        client.list_monitor_requests.assert_not_called()
+
+
+class TestLayerQuotaUsage:
+    """Tests for a layer's cached tile size."""
+
+    def test_finds_layer(self) -> None:
+        """Test the layer's quota entry is returned."""
+        gwc = MagicMock()
+        gwc.get_disk_quota.return_value = {
+            "layerQuotas": [
+                {"layer": "topp:states", "usedQuota": {"bytes": 10}},
+                {"layer": "topp:roads", "usedQuota": {"value": 2, "units": "MiB"}},
+            ]
+        }
+
+        usage = get_layer_quota_usage(gwc, "topp:roads")
+
+        assert usage.used_bytes == 2 * 1024**2
+
+    def test_unlisted_layer_is_unknown(self) -> None:
+        """Test a layer without a quota entry has unknown figures."""
+        gwc = MagicMock()
+        gwc.get_disk_quota.return_value = {"layerQuotas": []}
+
+        usage = get_layer_quota_usage(gwc, "topp:roads")
+
+        assert usage.to_dict() == {"layer": "topp:roads", "limitBytes": None, "usedBytes": None}
@@ -0,0 +1,215 @@
+"""Unit tests for OWS load tests."""
+
+import threading
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.load_test import (
+    FEATURE_COUNT,
+    LoadSample,
+    LoadTestReport,
+    StageStats,
+    format_chart,
+    histogram,
+    parse_ramp,
+    run_load_test,
+    tile_for_point,
+)
+from apps.geoserver.render_benchmark import ORIGIN_SHIFT
+
+LATLON = {"minx": 16.0, "miny": -35.0, "maxx": 33.0, "maxy": -22.0}
+
+
+class FakeClock:
+    """Clock that each request advances by a fixed duration."""
+
+    def __init__(self, step: float) -> None:
+        self.now = 0.0
+        self.step = step
+        self._lock = threading.Lock()
+
+    def __call__(self) -> float:
+        return self.now
+
+    def request(self, *args, **kwargs) -> MagicMock:
+        with self._lock:
+            self.now += self.step
+        return MagicMock(status_code=200)
+
+
+def _clients(clock: FakeClock) -> tuple[MagicMock, MagicMock]:
+    client = MagicMock()
+    client.get_layer_bounds.return_value = {"latLonBoundingBox": LATLON}
+    client.get_map.side_effect = clock.request
+    client.get_features.side_effect = clock.request
+    gwc_client = MagicMock()
+    gwc_client.get_tile_service.side_effect = clock.request
+    return client, gwc_client
+
+
+class TestLoadTestHelpers:
+    """Tests for ramps, tiles and histograms."""
+
+    def test_parse_ramp(self) -> None:
+        """Test ramps are read from commas or spaces."""
+        assert parse_ramp("1,4, 8") == [1, 4, 8]
+        assert parse_ramp("2 16") == [2, 16]
+
+    @pytest.mark.parametrize(
+        ("text", "message"),
+        [("", "at least one"), ("1,x", "whole numbers"), ("0", "between 1"), ("100", "between 1")],
+    )
+    def test_invalid_ramps_are_refused(self, text: str, message: str) -> None:
+        """Test empty, non-numeric and out of range ramps are reported."""
+        with pytest.raises(ValueError, match=message):
+            parse_ramp(text)
+
+    def test_tile_rows_count_from_the_top(self) -> None:
+        """Test points map to WMTS rows and columns in EPSG:900913."""
+        assert tile_for_point(0.0, 0.0, 0) == (0, 0)
+        assert tile_for_point(-1.0, 1.0, 1) == (0, 0)
+        assert tile_for_point(1.0, -1.0, 1) == (1, 1)
+        assert tile_for_point(ORIGIN_SHIFT, -ORIGIN_SHIFT, 2) == (3, 3)
+
+    def test_histogram_buckets(self) -> None:
+        """Test latencies fall in the first bucket that holds them."""
+        counts = histogram([0.01, 0.05, 0.2, 9.0])
+
+        assert counts[0] == 2
+        assert counts[2] == 1
+        assert counts[-1] == 1
+        assert sum(counts) == 4
+
+    def test_stage_stats_skip_failed_requests(self) -> None:
+        """Test percentiles cover successful requests and errors are counted."""
+        samples = [LoadSample(0.1), LoadSample(0.3), LoadSample(5.0, error="GetMap failed")]
+
+        stage = StageStats.from_samples(4, 2.0, samples)
+
+        assert (stage.requests, stage.errors, stage.max) == (3, 1, 0.3)
+        assert stage.throughput == 1.5
+        assert stage.first_error == "GetMap failed"
+        assert stage.to_record()["error_rate"] == "33.3%"
+
+    def test_chart_draws_stages_and_histogram(self) -> None:
+        """Test the chart has a bar per stage and per histogram bucket."""
+        report = LoadTestReport("topp", "roads", "getmap", 1.0)
+        report.stages = [
+            StageStats.from_samples(1, 1.0, [LoadSample(0.1)]),
+            StageStats.from_samples(8, 1.0, [LoadSample(0.4), LoadSample(0.8, error="boom")]),
+        ]
+
+        chart = format_chart(report, width=10)
+
+        assert "   1 | " in chart
+        assert "50.0% errors" in chart
+        assert "Latency histogram" in chart
+
+
+class TestRunLoadTest:
+    """Tests for running the stages."""
+
+    def test_stages_follow_the_ramp(self) -> None:
+        """Test each stage runs for its duration at its concurrency."""
+        clock = FakeClock(0.125)
+        client, _ = _clients(clock)
+        stages = []
+
+        report = run_load_test(
+            client,
+            None,
+            "topp",
+            "roads",
+            ramp=[1, 2],
+            stage_seconds=1.0,
+            clock=clock,
+            on_stage=stages.append,
+        )
+
+        assert [stage.concurrency for stage in report.stages] == [1, 2]
+        assert report.stages[0].requests == 8
+        assert report.stages[0].p50 == pytest.approx(0.125)
+        assert report.stages[1].requests >= 8
+        assert stages == report.stages
+        assert report.zoom is not None
+        width = client.get_map.call_args.args[3]
+        assert width == 256
+
+    def test_failed_tiles_are_counted(self) -> None:
+        """Test GetTile errors are recorded, and tiles are asked for in the grid set."""
+        clock = FakeClock(0.25)
+        client, gwc_client = _clients(clock)
+
+        def missing(*args, **kwargs) -> MagicMock:
+            clock.request()
+            return MagicMock(status_code=404)
+
+        gwc_client.get_tile_service.side_effect = missing
+
+        report = run_load_test(
+            client,
+            gwc_client,
+            "topp",
+            "roads",
+            kind="gettile",
+            ramp=[1],
+            stage_seconds=1.0,
+            zoom=6,
+            clock=clock,
+        )
+
+        assert (report.stages[0].requests, report.stages[0].errors) == (4, 4)
+        assert report.error_rate == 1.0
+        service, params = gwc_client.get_tile_service.call_args.args
+        assert service == "wmts"
+        assert params["layer"] == "topp:roads"
+        assert params["tilematrix"] == "EPSG:900913:6"
+
+    def test_getfeature_needs_no_bounds(self) -> None:
+        """Test GetFeature requests ask for a page of features."""
+        clock = FakeClock(0.5)
+        client, _ = _clients(clock)
+
+        report = run_load_test(
+            client,
+            None,
+            "topp",
+            "roads",
+            kind="getfeature",
+            ramp=[1],
+            stage_seconds=1.0,
+            clock=clock,
+        )
+
+        client.get_layer_bounds.assert_not_called()
+        client.get_features.assert_called_with("topp", "roads", FEATURE_COUNT)
+        assert report.zoom is None
+        assert report.stages[0].requests == 2
+
+    @pytest.mark.parametrize(
+        ("kwargs", "message"),
+        [
+            ({"kind": "getcoverage"}, "Unknown request"),
+            ({"kind": "gettile"}, "GWC client"),
+            ({"ramp": [65]}, "between 1"),
+            ({"stage_seconds": 0}, "Stages must last"),
+            ({"zoom": 23}, "zoom level"),
+        ],
+    )
+    def test_invalid_settings_are_refused(self, kwargs: dict, message: str) -> None:
+        """Test invalid settings are reported before any request."""
+        client, _ = _clients(FakeClock(0.1))
+
+        with pytest.raises(ValueError, match=message):
+            run_load_test(client, None, "topp", "roads", **kwargs)
+        client.get_map.assert_not_called()
+
+    def test_layers_without_bounds_are_refused(self) -> None:
+        """Test GetMap load tests need the layer's bounds."""
+        client, _ = _clients(FakeClock(0.1))
+        client.get_layer_bounds.return_value = {}
+
+        with pytest.raises(GeoServerError):
+            run_load_test(client, None, "topp", "roads")
@@ -0,0 +1,179 @@
+"""Unit tests for the state of resources managed by the client.
+
+Tests recording the resources the client creates per connection, and
+syncs only updating and pruning those.
+"""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.config import SyncOptions
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.managed import ManagedState, content_hash, store_hash
+from apps.sync.services import SyncService
+
+
+class TestManagedState:
+    """Tests for ManagedState."""
+
+    def test_add_and_remove(self, tmp_path) -> None:
+        """Test resources are kept per connection until removed."""
+        state = ManagedState(tmp_path)
+        state.add("conn_1", "style", "topp", "roads", "abc")
+        state.add("conn_1", "style", None, "line")
+
+        assert state.is_managed("conn_1", "style", "topp", "roads")
+        assert not state.is_managed("conn_2", "style", "topp", "roads")
+        assert not state.is_managed("conn_1", "style", None, "roads")
+
+        state.remove("conn_1", "style", "topp", "roads")
+        assert [r.name for r in state.resources("conn_1")] == ["line"]
+
+    def test_touch_only_updates_managed(self, tmp_path) -> None:
+        """Test updating a hash doesn't take over unmanaged resources."""
+        state = ManagedState(tmp_path)
+        state.touch("conn_1", "style", "topp", "roads", "new")
+        assert state.resources("conn_1") == []
+
+        state.add("conn_1", "style", "topp", "roads", "old", adopted=True)
+        state.touch("conn_1", "style", "topp", "roads", "new")
+        resource = state.get("conn_1", "style", "topp", "roads")
+        assert resource is not None
+        assert (resource.hash, resource.adopted) == ("new", True)
+
+    def test_workspace_takes_its_contents(self, tmp_path) -> None:
+        """Test removing a workspace forgets what it held."""
+        state = ManagedState(tmp_path)
+        state.add("conn_1", "workspace", None, "topp")
+        state.add("conn_1", "datastore", "topp", "roads")
+        state.add("conn_1", "style", None, "line")
+
+        assert len(state.resources("conn_1", workspace="topp")) == 2
+        state.remove("conn_1", "workspace", None, "topp")
+        assert [r.name for r in state.resources("conn_1")] == ["line"]
+
+    def test_unreadable_file(self, tmp_path) -> None:
+        """Test a damaged state file reads as empty."""
+        state = ManagedState(tmp_path)
+        state.path("conn_1").write_text("not json")
+        assert state.resources("conn_1") == []
+
+
+class TestHashes:
+    """Tests for content_hash and store_hash."""
+
+    def test_payload_key_order_is_ignored(self) -> None:
+        """Test payloads hash the same whatever their key order."""
+        assert content_hash({"a": 1, "b": 2}) == content_hash({"b": 2, "a": 1})
+        assert content_hash("<sld/>") == content_hash(b"<sld/>")
+
+    def test_store_secrets_are_left_out(self) -> None:
+        """Test a changed password doesn't change a store's hash."""
+        first = store_hash({"host": "db", "passwd": "one"}, name="roads")
+        assert first == store_hash({"host": "db", "passwd": "two"}, name="roads")
+        assert first != store_hash({"host": "db2", "passwd": "one"}, name="roads")
+
+
+@pytest.fixture
+def sync(tmp_path):
+    """A sync service between two mocked servers sharing a state file."""
+    state = ManagedState(tmp_path)
+    source, dest = MagicMock(), MagicMock()
+    source.list_styles.return_value = [{"name": "roads"}, {"name": "rivers"}]
+    source.get_style_content.side_effect = lambda name, ws: (f"<sld>{name}</sld>", "sld")
+    dest.list_styles.return_value = [{"name": "roads"}, {"name": "old"}, {"name": "manual"}]
+    with patch("apps.sync.services.managed_state", state):
+        with patch("apps.sync.services.GeoServerClientManager") as manager:
+            manager.return_value.get_client.side_effect = lambda conn_id: (
+                source if conn_id == "src" else dest
+            )
+            yield SyncService(), state, dest
+
+
+class TestSyncStyles:
+    """Tests for SyncService.sync_styles."""
+
+    def test_unmanaged_styles_are_left_alone(self, sync) -> None:
+        """Test existing styles the client didn't create aren't updated or pruned."""
+        service, state, dest = sync
+        results = service.sync_styles("src", "dst", options=SyncOptions(prune=True))["styles"]
+
+        dest.create_style.assert_called_once_with(
+            "rivers", "<sld>rivers</sld>", "sld", None, if_absent=True
+        )
+        dest.update_style_content.assert_not_called()
+        dest.delete_style.assert_not_called()
+        assert results["unmanaged"] == ["roads", "manual", "old"]
+
+    def test_managed_styles_are_updated_and_pruned(self, sync) -> None:
+        """Test managed styles follow the source, unless it didn't change."""
+        service, state, dest = sync
+        state.add("dst", "style", None, "roads", content_hash("<sld>stale</sld>"))
+        state.add("dst", "style", None, "old")
+
+        results = service.sync_styles("src", "dst", options=SyncOptions(prune=True))["styles"]
+
+        dest.update_style_content.assert_called_once_with(
+            "roads", "<sld>roads</sld>", "sld", None
+        )
+        dest.delete_style.assert_called_once_with("old", None)
+        assert (results["updated"], results["deleted"]) == (1, 1)
+        assert results["unmanaged"] == ["manual"]
+
+    def test_unchanged_source_is_skipped(self, sync) -> None:
+        """Test a managed style whose source didn't change isn't sent again."""
+        service, state, dest = sync
+        state.add("dst", "style", None, "roads", content_hash("<sld>roads</sld>"))
+
+        results = service.sync_styles("src", "dst")["styles"]
+
+        dest.update_style_content.assert_not_called()
+        assert results["skipped"] == 1
+
+    def test_adopt(self, sync) -> None:
+        """Test adopting updates existing styles and takes them over."""
+        service, state, dest = sync
+        service.sync_styles("src", "dst", options=SyncOptions(adopt=True))
+
+        dest.update_style_content.assert_called_once()
+        resource = state.get("dst", "style", None, "roads")
+        assert resource is not None and resource.adopted
+
+
+class TestPromoteStyles:
+    """Tests for SyncService.promote_styles."""
+
+    def test_only_chosen_styles_that_differ(self, sync) -> None:
+        """Test chosen styles are compared by content and nothing else is touched."""
+        service, state, dest = sync
+        current = {"roads": ("<sld>roads</sld>", "sld"), "manual": ("<sld>edited</sld>", "sld")}
+
+        def content(name, ws):
+            if name not in current:
+                raise GeoServerError("Resource not found", status_code=404)
+            return current[name]
+
+        dest.get_style_content.side_effect = content
+        results = service.promote_styles("src", "dst", ["roads", "rivers", "manual"])["styles"]
+
+        dest.create_style.assert_called_once_with(
+            "rivers", "<sld>rivers</sld>", "sld", None, if_absent=True
+        )
+        dest.update_style_content.assert_called_once_with(
+            "manual", "<sld>manual</sld>", "sld", None
+        )
+        dest.delete_style.assert_not_called()
+        assert (results["created"], results["updated"], results["skipped"]) == (1, 1, 1)
+        resource = state.get("dst", "style", None, "manual")
+        assert resource is not None and resource.adopted
+
+    def test_errors_are_collected(self, sync) -> None:
+        """Test a style that can't be read doesn't stop the others."""
+        service, state, dest = sync
+        dest.get_style_content.side_effect = GeoServerError("Forbidden", status_code=403)
+
+        results = service.promote_styles("src", "dst", ["roads", "rivers"])["styles"]
+
+        assert [e["style"] for e in results["errors"]] == ["roads", "rivers"]
+        dest.update_style_content.assert_not_called()
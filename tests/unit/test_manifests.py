@@ -0,0 +1,98 @@
+"""Unit tests for detecting installed extensions."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.capabilities import build_capabilities
+from apps.geoserver.importer import ImporterClient
+from apps.geoserver.manifests import extension_features, installed_modules, module_name
+
+MANIFESTS = [
+    {"@name": "gt-main-30.1"},
+    {"@name": "gs-main-2.24.1", "Project-Version": "2.24.1"},
+    {"@name": "gs-importer-core-2.24.1", "Project-Version": "2.24.1"},
+    {"@name": "gs-importer-rest-2.24.1", "Project-Version": "2.24.1"},
+    {"@name": "gs-ogcapi-features-2.24-SNAPSHOT", "Project-Version": "2.24-SNAPSHOT"},
+    {"@name": "gs-wps-core-2.24.1", "Project-Version": "2.24.1"},
+]
+
+
+class TestInstalledModules:
+    """Tests for reading jar manifests."""
+
+    def test_module_name(self) -> None:
+        """Test versions are stripped from jar names."""
+        assert module_name("gs-css-2.24.1") == "gs-css"
+        assert module_name("gs-ogcapi-features-2.25-SNAPSHOT") == "gs-ogcapi-features"
+        assert module_name("gs-wps-core") == "gs-wps-core"
+
+    def test_plugins_reported_once(self) -> None:
+        """Test a plugin's jars are merged and core jars left out."""
+        modules = installed_modules(MANIFESTS)
+
+        assert [(m.label, m.kind) for m in modules] == [
+            ("Importer", "extension"),
+            ("WPS", "extension"),
+            ("OGC API", "community"),
+        ]
+        assert modules[0].to_dict() == {
+            "name": "gs-importer-core",
+            "label": "Importer",
+            "kind": "extension",
+            "version": "2.24.1",
+        }
+
+    def test_extension_features(self) -> None:
+        """Test plugin features follow the jars found."""
+        features = extension_features(MANIFESTS)
+
+        assert features["importer"]
+        assert not features["css_styles"]
+        assert not features["cog"]
+
+
+class TestExtensionGating:
+    """Tests for hiding features whose plugin is missing."""
+
+    def test_manifests_feed_capabilities(self) -> None:
+        """Test plugin features join the matrix and overrides still win."""
+        capabilities = build_capabilities(
+            "2.24.1",
+            overrides={"cog": True},
+            extensions={"css_styles": False, "cog": False},
+        )
+
+        assert not capabilities.supports("css_styles")
+        assert capabilities.supports("cog")
+        assert capabilities.supports("importer")  # Unknown plugins are assumed present
+
+        features = {f["name"]: f for f in capabilities.to_dict()["features"]}
+        assert features["css_styles"]["extension"] == "gs-css"
+
+    def test_css_style_refused_before_create(self, make_geoserver_client) -> None:
+        """Test a CSS style isn't created on a server without the CSS extension."""
+        client = make_geoserver_client()
+        client._capabilities = build_capabilities("2.24.1", extensions={"css_styles": False})
+
+        with pytest.raises(GeoServerError, match="CSS extension"):
+            client.create_style("roads", "* { stroke: red; }", "css")
+        client._request.assert_not_called()
+
+    def test_cog_store_refused(self, make_geoserver_client) -> None:
+        """Test cog:// stores need the COG plugin."""
+        client = make_geoserver_client()
+        client._capabilities = build_capabilities("2.24.1", extensions={"cog": False})
+
+        with pytest.raises(GeoServerError, match="COG plugin"):
+            client.create_coveragestore("topp", "dem", url="cog://https://example.com/dem.tif")
+        client._request.assert_not_called()
+
+    def test_importer_not_probed_without_plugin(self) -> None:
+        """Test the importer isn't probed when the manifests say it's missing."""
+        client = MagicMock()
+        client.capabilities = build_capabilities("2.24.1", extensions={"importer": False})
+
+        assert not ImporterClient(client).is_available()
+        client._request.assert_not_called()
@@ -0,0 +1,125 @@
+"""Unit tests for Mapbox GL styles and MapProxy snippets of published layers."""
+
+import json
+from pathlib import Path
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.gwc.map_export import (
+    ExportLayer,
+    collect_layers,
+    export_map,
+    mapbox_style,
+    mapproxy_config,
+    write_export,
+)
+
+BASE_URL = "https://maps.example.com/geoserver"
+
+ROADS = ExportLayer(
+    "topp", "roads", bbox={"minx": 10.0, "miny": 40.0, "maxx": 12.0, "maxy": 41.0}
+)
+BASEMAP = ExportLayer("topp", "basemap", kind="layergroup")
+
+
+class TestCollectLayers:
+    """Test looking up the layers to export."""
+
+    def test_layers_and_groups(self):
+        client = MagicMock()
+        client.list_layergroups.return_value = [{"name": "basemap"}]
+        client.get_layergroup_bounds.return_value = {"latLonBoundingBox": None}
+        client.get_layer_bounds.return_value = {"latLonBoundingBox": ROADS.bbox}
+
+        layers = collect_layers(client, ["topp:basemap", "topp:roads"])
+
+        assert [(layer.name, layer.kind) for layer in layers] == [
+            ("basemap", "layergroup"),
+            ("roads", "layer"),
+        ]
+        assert layers[1].bbox == ROADS.bbox
+        client.list_layergroups.assert_called_once_with("topp")
+
+    def test_unqualified_name(self):
+        with pytest.raises(ValueError, match="qualified"):
+            collect_layers(MagicMock(), ["roads"])
+
+
+class TestMapboxStyle:
+    """Test building Mapbox GL styles."""
+
+    def test_sources_and_layers(self):
+        style = mapbox_style(BASE_URL, [BASEMAP, ROADS], "City")
+
+        assert style["version"] == 8
+        assert style["name"] == "City"
+        assert [layer["id"] for layer in style["layers"]] == ["topp_basemap", "topp_roads"]
+        source = style["sources"]["topp_roads"]
+        assert source["type"] == "raster"
+        assert source["tileSize"] == 256
+        assert source["bounds"] == [10.0, 40.0, 12.0, 41.0]
+        tiles = source["tiles"][0]
+        assert tiles.startswith(f"{BASE_URL}/gwc/service/wmts?")
+        assert "layer=topp:roads" in tiles
+        assert "tilematrix=EPSG:900913:{z}" in tiles
+        assert "tilerow={y}" in tiles and "tilecol={x}" in tiles
+        assert "bounds" not in style["sources"]["topp_basemap"]
+
+    def test_center_and_zoom(self):
+        style = mapbox_style(BASE_URL, [ROADS], "City")
+
+        assert style["center"] == [11.0, 40.5]
+        assert style["zoom"] == 7
+
+    def test_geographic_grid_set(self):
+        with pytest.raises(ValueError, match="Web Mercator"):
+            mapbox_style(BASE_URL, [ROADS], "City", grid_set="EPSG:4326")
+
+
+class TestMapProxyConfig:
+    """Test building MapProxy snippets."""
+
+    def test_config(self):
+        config = mapproxy_config(BASE_URL, [ROADS], tile_format="image/jpeg")
+
+        assert config["layers"] == [
+            {"name": "topp_roads", "title": "topp:roads", "sources": ["topp_roads_cache"]}
+        ]
+        assert config["caches"]["topp_roads_cache"]["grids"] == ["GLOBAL_WEBMERCATOR"]
+        source = config["sources"]["topp_roads_tiles"]
+        assert source["type"] == "tile"
+        assert source["url"] == (
+            f"{BASE_URL}/gwc/service/tms/1.0.0/topp:roads@EPSG:900913@jpeg/%(tms_path)s.jpeg"
+        )
+        assert source["coverage"] == {"bbox": [10.0, 40.0, 12.0, 41.0], "srs": "EPSG:4326"}
+
+
+class TestExportMap:
+    """Test generating and writing exports."""
+
+    def test_mapbox(self, tmp_path: Path):
+        export = export_map(BASE_URL, [ROADS], "mapbox", "City Map")
+
+        assert export.filename == "city_map.style.json"
+        assert json.loads(export.content)["name"] == "City Map"
+
+        path = write_export(export, tmp_path)
+        assert path == tmp_path / "city_map.style.json"
+        assert path.read_text() == export.content
+        assert export.to_dict()["path"] == str(path)
+
+    def test_mapproxy(self):
+        export = export_map(BASE_URL, [ROADS], "mapproxy", "City")
+
+        assert export.filename == "city.mapproxy.yaml"
+        assert export.content.startswith("layers:\n  - name: \"topp_roads\"")
+        assert "GLOBAL_WEBMERCATOR" in export.content
+
+    def test_invalid(self, tmp_path: Path):
+        with pytest.raises(ValueError, match="Unknown format"):
+            export_map(BASE_URL, [ROADS], "openlayers", "City")
+        with pytest.raises(ValueError, match="No layers"):
+            export_map(BASE_URL, [], "mapbox", "City")
+        with pytest.raises(OSError, match="Folder not found"):
+            write_export(export_map(BASE_URL, [ROADS], "mapbox", "City"), tmp_path / "missing")
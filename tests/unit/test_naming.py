@@ -0,0 +1,114 @@
+"""Unit tests for naming conventions."""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.config import NamingConvention
+from apps.core.exceptions import NamingError
+from apps.geoserver.naming import check_name, validate_name
+from apps.upload.batch import BatchItem, publish_item
+from apps.upload.target import default_store_name, resolve_target
+
+LOWERCASE = NamingConvention(
+    resource_type="*",
+    pattern="[a-z][a-z0-9_]*",
+    description="lowercase letters, digits and underscores",
+)
+STORE_PREFIX = NamingConvention(resource_type="datastore", prefix="{workspace}_")
+
+
+def _configured(*conventions: NamingConvention, slugify: bool = True):
+    manager = MagicMock()
+    manager.config.naming_conventions = list(conventions)
+    manager.config.slugify_upload_names = slugify
+    return manager
+
+
+class TestCheckName:
+    """Tests for checking a name against conventions."""
+
+    def test_valid(self) -> None:
+        """Test a name following every convention has no problems."""
+        assert check_name("datastore", "topp_roads", "topp", [LOWERCASE, STORE_PREFIX]) == []
+
+    def test_pattern(self) -> None:
+        """Test a name not matching the whole pattern is described."""
+        problems = check_name("style", "Roads", conventions=[LOWERCASE])
+
+        assert problems == ["lowercase letters, digits and underscores"]
+
+    def test_pattern_without_description(self) -> None:
+        """Test the pattern itself is shown when it has no description."""
+        convention = NamingConvention(resource_type="layer", pattern="[a-z]+")
+
+        assert check_name("layer", "roads2", conventions=[convention]) == ["must match [a-z]+"]
+
+    def test_workspace_prefix(self) -> None:
+        """Test a prefix using {workspace} is filled in from the workspace."""
+        problems = check_name("datastore", "roads", "topp", [STORE_PREFIX])
+
+        assert problems == ["must start with 'topp_'"]
+
+    def test_prefix_for_other_types_ignored(self) -> None:
+        """Test a convention only applies to its resource type."""
+        assert check_name("coveragestore", "dem", "topp", [STORE_PREFIX]) == []
+
+    def test_forbidden_characters(self) -> None:
+        """Test each forbidden character is listed once."""
+        convention = NamingConvention(resource_type="*", forbidden_characters=" -")
+
+        assert check_name("layer", "main road - a", conventions=[convention]) == [
+            "must not contain ' ', '-'"
+        ]
+
+    def test_invalid_pattern(self) -> None:
+        """Test a broken pattern in the config is reported, not raised."""
+        convention = NamingConvention(resource_type="*", pattern="[a-z")
+
+        (problem,) = check_name("layer", "roads", conventions=[convention])
+        assert "is invalid" in problem
+
+
+class TestValidateName:
+    """Tests for refusing names against the configured conventions."""
+
+    def test_raises(self) -> None:
+        """Test a name breaking a convention is refused with a 400."""
+        with patch("apps.geoserver.naming.config_manager", _configured(LOWERCASE)):
+            with pytest.raises(NamingError, match="Invalid data store name 'Roads'") as error:
+                validate_name("datastore", "Roads", "topp")
+
+        assert error.value.status_code == 400
+
+    def test_no_conventions(self) -> None:
+        """Test any name passes without configured conventions."""
+        with patch("apps.geoserver.naming.config_manager", _configured()):
+            validate_name("workspace", "Any Name")
+
+
+class TestUploadNames:
+    """Tests for naming conventions and file-derived names in uploads."""
+
+    def test_target_breaking_convention(self) -> None:
+        """Test an upload into a store breaking a convention is refused."""
+        with patch("apps.geoserver.naming.config_manager", _configured(STORE_PREFIX)):
+            target = resolve_target("roads.zip", "topp", "roads", {})
+
+        assert "must start with 'topp_'" in target.error
+
+    def test_slugify_off(self) -> None:
+        """Test the file name is kept as it is when slugifying is turned off."""
+        with patch("apps.upload.target.config_manager", _configured(slugify=False)):
+            assert default_store_name("data/Roads 2024.shp.zip") == "Roads 2024"
+
+    def test_batch_item_checked_before_upload(self) -> None:
+        """Test a batch file breaking a convention is never uploaded."""
+        client = MagicMock()
+        item = BatchItem("roads.zip", "shapefile", "topp", "roads", "roads")
+
+        with patch("apps.geoserver.naming.config_manager", _configured(STORE_PREFIX)):
+            with pytest.raises(NamingError):
+                publish_item(client, "conn_1", item, b"data")
+
+        client.upload_shapefile.assert_not_called()
@@ -0,0 +1,195 @@
+"""Unit tests for OIDC bearer tokens.
+
+Tests the device sign-in, keeping refresh tokens in the keyring,
+refreshing access tokens and adding them to requests.
+"""
+
+from unittest.mock import MagicMock
+
+import httpx
+import pytest
+
+from apps.core.config import Connection
+from apps.core.exceptions import TokenError
+from apps.core.oidc import (
+    BearerAuth,
+    DeviceAuthorization,
+    access_token,
+    connection_auth,
+    poll_sign_in,
+    start_sign_in,
+)
+
+ENDPOINTS = {
+    "device_authorization_endpoint": "https://sso/device",
+    "token_endpoint": "https://sso/token",
+}
+
+
+class FakeKeyring:
+    """In-memory stand-in for the keyring package."""
+
+    class errors:
+        class KeyringError(Exception):
+            pass
+
+        class PasswordDeleteError(KeyringError):
+            pass
+
+    def __init__(self) -> None:
+        self.passwords: dict[tuple[str, str], str] = {}
+
+    def get_password(self, service: str, name: str) -> str | None:
+        return self.passwords.get((service, name))
+
+    def set_password(self, service: str, name: str, password: str) -> None:
+        self.passwords[(service, name)] = password
+
+    def delete_password(self, service: str, name: str) -> None:
+        if (service, name) not in self.passwords:
+            raise self.errors.PasswordDeleteError(name)
+        del self.passwords[(service, name)]
+
+
+def _connection(conn_id: str, auth_type: str = "oidc") -> Connection:
+    return Connection(
+        id=conn_id,
+        name="sso",
+        url="https://gs/geoserver",
+        username="",
+        password="",
+        auth_type=auth_type,
+        oidc_issuer="https://sso/realms/gis",
+        oidc_client_id="cloudbench",
+    )
+
+
+def _response(status_code: int, data: dict) -> MagicMock:
+    response = MagicMock(status_code=status_code)
+    response.json.return_value = data
+    return response
+
+
+def _provider(
+    monkeypatch: pytest.MonkeyPatch, *responses: MagicMock
+) -> tuple[MagicMock, FakeKeyring]:
+    """Answer token requests in turn and keep refresh tokens in memory."""
+    post = MagicMock(side_effect=list(responses))
+    keyring = FakeKeyring()
+    monkeypatch.setattr("apps.core.oidc._post", post)
+    monkeypatch.setattr("apps.core.oidc.discover", lambda issuer: ENDPOINTS)
+    monkeypatch.setattr("apps.core.oidc._keyring", lambda: keyring)
+    return post, keyring
+
+
+class TestDeviceSignIn:
+    """Tests for signing in with a code entered at the provider."""
+
+    def test_sign_in(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test polling waits, slows down when asked and keeps the refresh token."""
+        post, keyring = _provider(
+            monkeypatch,
+            _response(
+                200,
+                {
+                    "device_code": "dev-1",
+                    "user_code": "ABCD-EFGH",
+                    "verification_uri": "https://sso/device",
+                    "interval": 2,
+                    "expires_in": 600,
+                },
+            ),
+            _response(400, {"error": "authorization_pending"}),
+            _response(400, {"error": "slow_down"}),
+            _response(200, {"access_token": "at-1", "refresh_token": "rt-1", "expires_in": 300}),
+        )
+        conn = _connection("sign_in")
+
+        device = start_sign_in(conn)
+        assert device.to_dict()["userCode"] == "ABCD-EFGH"
+        assert post.call_args.args[1] == {
+            "client_id": "cloudbench",
+            "scope": "openid offline_access",
+        }
+
+        assert not poll_sign_in(conn, device)
+        assert not poll_sign_in(conn, device)
+        assert device.interval == 7
+        assert poll_sign_in(conn, device)
+
+        assert post.call_args.args[1]["device_code"] == "dev-1"
+        assert keyring.passwords == {("kartoza-cloudbench", "sign_in"): "rt-1"}
+        assert access_token(conn) == "at-1"
+
+    def test_denied(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test a refused sign-in reports the provider's reason."""
+        _provider(
+            monkeypatch,
+            _response(400, {"error": "access_denied", "error_description": "User refused"}),
+        )
+        device = DeviceAuthorization("dev-2", "WXYZ", "https://sso/device")
+
+        with pytest.raises(TokenError, match="User refused"):
+            poll_sign_in(_connection("denied"), device)
+
+
+class TestAccessToken:
+    """Tests for refreshing access tokens."""
+
+    def test_refresh(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test the refresh token is exchanged once and a rotated one kept."""
+        post, keyring = _provider(
+            monkeypatch,
+            _response(200, {"access_token": "at-2", "refresh_token": "rt-3", "expires_in": 300}),
+        )
+        keyring.set_password("kartoza-cloudbench", "refresh", "rt-2")
+        conn = _connection("refresh")
+
+        assert access_token(conn) == "at-2"
+        assert access_token(conn) == "at-2"
+
+        assert post.call_count == 1
+        assert post.call_args.args[1]["refresh_token"] == "rt-2"
+        assert keyring.get_password("kartoza-cloudbench", "refresh") == "rt-3"
+
+    def test_not_signed_in(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test a connection without a refresh token asks to sign in."""
+        _provider(monkeypatch)
+
+        with pytest.raises(TokenError, match="Sign in to 'sso'"):
+            access_token(_connection("not_signed_in"))
+
+    def test_session_ended(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test a refresh token the provider no longer accepts is forgotten."""
+        _, keyring = _provider(monkeypatch, _response(400, {"error": "invalid_grant"}))
+        keyring.set_password("kartoza-cloudbench", "ended", "rt-old")
+
+        with pytest.raises(TokenError, match="sign in again"):
+            access_token(_connection("ended"))
+
+        assert keyring.passwords == {}
+
+
+class TestBearerAuth:
+    """Tests for adding access tokens to requests."""
+
+    def test_retry_with_fresh_token(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test a refused token is refreshed and the request sent again."""
+        _, keyring = _provider(
+            monkeypatch,
+            _response(200, {"access_token": "at-old", "expires_in": 300}),
+            _response(200, {"access_token": "at-new", "expires_in": 300}),
+        )
+        keyring.set_password("kartoza-cloudbench", "bearer", "rt")
+        auth = connection_auth(_connection("bearer"))
+        assert isinstance(auth, BearerAuth)
+
+        flow = auth.auth_flow(httpx.Request("GET", "https://gs/geoserver/rest/about/version.json"))
+        request = next(flow)
+        assert request.headers["Authorization"] == "Bearer at-old"
+        request = flow.send(MagicMock(status_code=401))
+        assert request.headers["Authorization"] == "Bearer at-new"
+
+    def test_basic_auth(self) -> None:
+        """Test connections with a password keep using basic auth."""
+        assert connection_auth(_connection("basic", auth_type="basic")) is None
@@ -0,0 +1,99 @@
+"""Unit tests for the write-ahead log of batch operations.
+
+Tests logging a plan and its steps, resuming what an interrupted run
+left, and resuming a logged sync.
+"""
+
+from unittest.mock import MagicMock, patch
+
+from apps.core.config import SyncConfiguration, SyncOptions
+from apps.core.oplog import OperationLog
+from apps.sync.services import SyncService, begin_sync, operation_config
+
+
+class TestOperationLog:
+    """Tests for OperationLog."""
+
+    def test_complete_run_deletes_its_log(self, tmp_path) -> None:
+        """Test a run that finishes every step leaves nothing to resume."""
+        log = OperationLog(tmp_path)
+        operation = log.begin("truncate", "conn1", ["topp:roads", "topp:rivers"])
+        log.record(operation, "topp:roads", True)
+        log.record(operation, "topp:rivers", True)
+
+        assert log.close(operation)
+        assert list(tmp_path.iterdir()) == []
+
+    def test_interrupted_run_resumes_pending_steps(self, tmp_path) -> None:
+        """Test steps that failed or never ran are pending when the log is read back."""
+        log = OperationLog(tmp_path)
+        operation = log.begin("upload", "conn1", ["a.zip", "b.zip", "c.zip"], {"root": "/data"})
+        log.record(operation, "a.zip", True)
+        log.record(operation, "b.zip", False, "Connection refused")
+
+        assert log.interrupted() == []  # Still running in this process
+        assert not log.close(operation)
+
+        [found] = log.interrupted("conn1", "upload")
+        assert found.pending == ["b.zip", "c.zip"]
+        assert found.failed == {"b.zip": "Connection refused"}
+        assert found.params == {"root": "/data"}
+        assert log.interrupted("conn2") == []
+
+    def test_resume_claims_the_operation(self, tmp_path) -> None:
+        """Test an operation can't be resumed twice at once, nor discarded meanwhile."""
+        log = OperationLog(tmp_path)
+        operation = log.begin("truncate", "conn1", ["topp:roads"])
+        log.close(operation)
+
+        resumed = log.resume(operation.id)
+
+        assert resumed is not None
+        assert log.resume(operation.id) is None
+        assert not log.discard(operation.id)
+        log.close(resumed)
+        assert log.discard(operation.id)
+        assert log.get(operation.id) is None
+
+    def test_half_written_line_is_ignored(self, tmp_path) -> None:
+        """Test a line cut off by a crash doesn't lose the rest of the log."""
+        log = OperationLog(tmp_path)
+        operation = log.begin("truncate", "conn1", ["topp:roads", "topp:rivers"])
+        log.record(operation, "topp:roads", True)
+        log.close(operation)
+        with log.path(operation.id).open("a") as f:
+            f.write('{"step": "topp:riv')
+
+        assert log.get(operation.id).pending == ["topp:rivers"]
+
+
+class TestResumeSync:
+    """Tests for logging and resuming a sync."""
+
+    def test_done_steps_are_skipped(self, tmp_path) -> None:
+        """Test a resumed sync only syncs the resource types it didn't finish."""
+        log = OperationLog(tmp_path)
+        config = SyncConfiguration(
+            id="cfg",
+            name="Nightly",
+            source_id="src",
+            destination_ids=["dst"],
+            options=SyncOptions(),
+        )
+        with patch("apps.sync.services.operation_log", log):
+            operation = begin_sync(config)
+            log.record(operation, "dst/workspaces", True)
+            log.close(operation)
+
+            resumed = log.resume(operation.id)
+            with patch("apps.sync.services.GeoServerClientManager"):
+                service = SyncService()
+            service.job_manager = MagicMock()
+            service.sync_workspaces = MagicMock()
+            service.sync_styles = MagicMock(return_value={"styles": {"errors": []}})
+            service.run_sync(operation_config(resumed), "job", resumed)
+
+        service.sync_workspaces.assert_not_called()
+        service.sync_styles.assert_called_once()
+        assert operation_config(resumed).id == "cfg"
+        assert log.close(resumed)
@@ -0,0 +1,56 @@
+"""Unit tests for command output formatting."""
+
+import json
+
+import pytest
+
+from apps.core.output import format_csv, format_records, format_table, format_yaml
+
+RECORDS = [
+    {"id": "conn_1", "name": "Production", "active": True, "tags": ["a", "b"]},
+    {"id": "conn_2", "name": "Staging, EU", "active": False, "tags": []},
+]
+
+
+class TestFormats:
+    """Tests for each output format."""
+
+    def test_table(self) -> None:
+        """Test columns are aligned under an upper-case header."""
+        assert format_table(RECORDS, ["id", "name"]).splitlines() == [
+            "ID      NAME",
+            "conn_1  Production",
+            "conn_2  Staging, EU",
+        ]
+
+    def test_csv(self) -> None:
+        """Test values are quoted where needed and lists joined."""
+        assert format_csv(RECORDS).splitlines() == [
+            "id,name,active,tags",
+            'conn_1,Production,true,"a,b"',
+            'conn_2,"Staging, EU",false,',
+        ]
+
+    def test_json(self) -> None:
+        """Test JSON output round-trips."""
+        assert json.loads(format_records(RECORDS, "json")) == RECORDS
+
+    def test_yaml(self) -> None:
+        """Test nested lists and dicts are indented under their keys."""
+        assert format_yaml(RECORDS).splitlines() == [
+            '- id: "conn_1"',
+            '  name: "Production"',
+            "  active: true",
+            "  tags:",
+            '    - "a"',
+            '    - "b"',
+            '- id: "conn_2"',
+            '  name: "Staging, EU"',
+            "  active: false",
+            "  tags: []",
+        ]
+
+    def test_unknown_format(self) -> None:
+        """Test unknown formats are rejected."""
+        with pytest.raises(ValueError):
+            format_records(RECORDS, "xml")
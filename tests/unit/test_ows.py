@@ -0,0 +1,141 @@
+"""Unit tests for OGC service URL helpers."""
+
+import pytest
+
+from apps.geoserver.ows import (
+    parse_capabilities,
+    parse_exception_report,
+    parse_hits,
+    service_path,
+    service_url,
+)
+
+
+class TestServiceUrls:
+    """Tests for virtual service URL building."""
+
+    def test_global_service(self) -> None:
+        """Test the global service path."""
+        assert service_path("wms") == "/wms"
+
+    def test_workspace_virtual_service(self) -> None:
+        """Test workspace-scoped service paths."""
+        assert service_path("WFS", "topp") == "/topp/wfs"
+
+    def test_layer_virtual_service(self) -> None:
+        """Test layer-scoped service paths."""
+        assert service_path("wms", "topp", "states") == "/topp/states/wms"
+
+    def test_layer_requires_workspace(self) -> None:
+        """Test a layer service without a workspace is rejected."""
+        with pytest.raises(ValueError):
+            service_path("wms", layer="states")
+
+    def test_unknown_service(self) -> None:
+        """Test unknown services are rejected."""
+        with pytest.raises(ValueError):
+            service_path("wps")
+
+    def test_service_url_strips_trailing_slash(self) -> None:
+        """Test the base URL is joined without a double slash."""
+        assert (
+            service_url("http://localhost:8080/geoserver/", "wcs", "nurc")
+            == "http://localhost:8080/geoserver/nurc/wcs"
+        )
+
+
+class TestExceptionReports:
+    """Tests for OWS exception report parsing."""
+
+    def test_ows_exception_report(self) -> None:
+        """Test OWS 1.1 exception reports."""
+        report = parse_exception_report(
+            '<ows:ExceptionReport xmlns:ows="http://www.opengis.net/ows/1.1" version="2.0.0">'
+            '<ows:Exception exceptionCode="InvalidParameterValue" locator="typeName">'
+            "<ows:ExceptionText>Feature type topp:nope unknown</ows:ExceptionText>"
+            "</ows:Exception></ows:ExceptionReport>"
+        )
+
+        assert report is not None
+        assert report.exceptions[0].code == "InvalidParameterValue"
+        assert report.exceptions[0].locator == "typeName"
+        assert report.message == "Feature type topp:nope unknown"
+
+    def test_wms_service_exception_report(self) -> None:
+        """Test WMS service exception reports."""
+        report = parse_exception_report(
+            '<ServiceExceptionReport version="1.1.1">'
+            '<ServiceException code="LayerNotDefined">Could not find layer x</ServiceException>'
+            "</ServiceExceptionReport>"
+        )
+
+        assert report is not None
+        assert report.exceptions[0].code == "LayerNotDefined"
+        assert report.message == "Could not find layer x"
+
+    def test_non_exception_documents(self) -> None:
+        """Test other documents and invalid XML aren't reports."""
+        assert parse_exception_report("<FeatureCollection/>") is None
+        assert parse_exception_report("not xml") is None
+
+
+class TestHits:
+    """Tests for hits response parsing."""
+
+    def test_wfs2_hits(self) -> None:
+        """Test WFS 2.0 numberMatched."""
+        assert (
+            parse_hits(
+                '<wfs:FeatureCollection xmlns:wfs="http://www.opengis.net/wfs/2.0" '
+                'numberMatched="42" numberReturned="0"/>'
+            )
+            == 42
+        )
+
+    def test_wfs11_hits(self) -> None:
+        """Test WFS 1.1 numberOfFeatures."""
+        assert parse_hits('<FeatureCollection numberOfFeatures="9"/>') == 9
+
+    def test_unknown_hits(self) -> None:
+        """Test non-numeric counts."""
+        assert parse_hits('<FeatureCollection numberMatched="unknown"/>') is None
+
+
+class TestCapabilities:
+    """Tests for capabilities parsing."""
+
+    def test_wms_capabilities(self) -> None:
+        """Test WMS 1.3 capabilities with nested layers."""
+        caps = parse_capabilities(
+            '<WMS_Capabilities xmlns="http://www.opengis.net/wms" version="1.3.0">'
+            "<Service><Name>WMS</Name><Title>My WMS</Title></Service>"
+            "<Capability><Layer><Title>Root</Title>"
+            "<Layer><Name>topp:states</Name></Layer>"
+            "<Layer><Name>topp:roads</Name></Layer>"
+            "</Layer></Capability></WMS_Capabilities>"
+        )
+
+        assert caps is not None
+        assert caps.service == "WMS"
+        assert caps.version == "1.3.0"
+        assert caps.title == "My WMS"
+        assert caps.layers == ["topp:states", "topp:roads"]
+
+    def test_wfs_capabilities(self) -> None:
+        """Test WFS 2.0 capabilities."""
+        caps = parse_capabilities(
+            '<wfs:WFS_Capabilities xmlns:wfs="http://www.opengis.net/wfs/2.0" '
+            'xmlns:ows="http://www.opengis.net/ows/1.1" version="2.0.0">'
+            "<ows:ServiceIdentification><ows:Title>My WFS</ows:Title></ows:ServiceIdentification>"
+            "<wfs:FeatureTypeList><wfs:FeatureType><wfs:Name>topp:states</wfs:Name>"
+            "</wfs:FeatureType></wfs:FeatureTypeList></wfs:WFS_Capabilities>"
+        )
+
+        assert caps is not None
+        assert caps.service == "WFS"
+        assert caps.title == "My WFS"
+        assert caps.layers == ["topp:states"]
+
+    def test_not_capabilities(self) -> None:
+        """Test other documents aren't capabilities."""
+        assert parse_capabilities("<FeatureCollection/>") is None
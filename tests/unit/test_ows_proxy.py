@@ -0,0 +1,121 @@
+"""Unit tests for forwarding OGC requests to GeoServer."""
+
+from unittest.mock import ANY, MagicMock
+
+import pytest
+
+from apps.geoserver.ows_proxy import check_request, forwarded_headers, proxy_path
+
+
+class TestProxyPath:
+    """Test which GeoServer paths can be proxied."""
+
+    def test_services(self):
+        assert proxy_path("") == "/ows"
+        assert proxy_path("wms") == "/wms"
+        assert proxy_path("topp/wfs/") == "/topp/wfs"
+        assert proxy_path("topp/roads/wms") == "/topp/roads/wms"
+        assert proxy_path("gwc/service/wmts") == "/gwc/service/wmts"
+
+    @pytest.mark.parametrize(
+        "target",
+        [
+            "rest/workspaces",
+            "rest/wms",
+            "../rest/wms",
+            "topp/../wms",
+            "web/",
+            "a/b/c/wms",
+            "gwc/rest/seed",
+            "topp/wms/extra",
+        ],
+    )
+    def test_refused(self, target):
+        with pytest.raises(ValueError, match="Not an OWS service"):
+            proxy_path(target)
+
+
+class TestCheckRequest:
+    """Test forwarding only read requests."""
+
+    def test_reads(self):
+        check_request("/ows", {"SERVICE": ["WMS"], "REQUEST": ["GetMap"]})
+        check_request("/topp/wfs", {"service": ["WFS"], "request": ["GetFeature"]})
+        check_request("/topp/wms", {"request": ["GetLegendGraphic"]})
+        check_request("/gwc/service/wmts", {"REQUEST": ["GetTile"]})
+
+    @pytest.mark.parametrize(
+        "path,params",
+        [
+            ("/ows", {"service": ["WFS"], "Request": ["Transaction"]}),
+            ("/ows", {"service": ["WPS"], "request": ["Execute"]}),
+            ("/wfs", {"request": ["GetMap"]}),
+            ("/ows", {"service": ["WMS"], "request": ["GetMap", "Transaction"]}),
+        ],
+    )
+    def test_refused(self, path, params):
+        with pytest.raises(ValueError, match="can't be proxied"):
+            check_request(path, params)
+
+    @pytest.mark.parametrize(
+        "path,params",
+        [
+            ("/ows", {"request": ["GetMap"]}),
+            ("/wms", {"service": ["WFS"], "request": ["GetFeature"]}),
+            ("/wms", {"service": ["WMS"]}),
+        ],
+    )
+    def test_service_and_request_needed(self, path, params):
+        with pytest.raises(ValueError, match="Requests need"):
+            check_request(path, params)
+
+
+class TestForwardedHeaders:
+    """Test passing on headers."""
+
+    def test_case_insensitive(self):
+        headers = {"content-type": "image/png", "content-length": "12", "ETag": "abc"}
+
+        assert forwarded_headers(headers, ("Content-Type", "ETag")) == {
+            "Content-Type": "image/png",
+            "ETag": "abc",
+        }
+
+
+class TestOpenOws:
+    """Test streaming an OWS response."""
+
+    def test_stream(self, make_geoserver_client):
+        client = make_geoserver_client()
+        client._open = MagicMock()
+        response = MagicMock(status_code=200)
+        response.iter_bytes.return_value = iter([b"png", b"data"])
+        client._open.return_value.__enter__.return_value = response
+
+        opened, body = client.open_ows(
+            "/topp/wms", [("LAYERS", "topp:roads")], {"If-None-Match": "abc"}
+        )
+
+        assert opened is response
+        client._open.return_value.__exit__.assert_not_called()
+        assert b"".join(body) == b"pngdata"
+        client._open.return_value.__exit__.assert_called_once()
+        body.close()
+        client._open.return_value.__exit__.assert_called_once()
+        client._open.assert_called_once_with(
+            "GET",
+            "/topp/wms",
+            params=[("LAYERS", "topp:roads")],
+            headers={"If-None-Match": "abc"},
+            timeout=ANY,
+        )
+
+    def test_closed_before_reading(self, make_geoserver_client):
+        client = make_geoserver_client()
+        client._open = MagicMock()
+        client._open.return_value.__enter__.return_value = MagicMock(status_code=200)
+
+        _, body = client.open_ows("/wms", [])
+        body.close()
+
+        client._open.return_value.__exit__.assert_called_once()
@@ -0,0 +1,91 @@
+"""Unit tests for probing what a connection's credentials may do.
+
+Tests telling administrators, accounts the server only lets read and
+read-only connections apart without changing anything on the server.
+"""
+
+from unittest.mock import MagicMock
+
+from apps.core.config import Connection
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.permissions import probe_permissions
+
+
+def _client(statuses: dict[tuple[str, str], int], read_only: bool = False) -> MagicMock:
+    """A client answering (method, path prefix) with a status, 404 otherwise."""
+    client = MagicMock()
+    client.connection = Connection(
+        name="prod", url="http://gs/geoserver", username="viewer", password="x", read_only=read_only
+    )
+
+    def request(method: str, path: str, **kwargs: object) -> MagicMock:
+        status = next(
+            (s for (m, prefix), s in statuses.items() if m == method and path.startswith(prefix)),
+            404,
+        )
+        return MagicMock(status_code=status)
+
+    client._request.side_effect = request
+    return client
+
+
+class TestProbePermissions:
+    """Tests for telling which credentials may change the server."""
+
+    def test_admin(self) -> None:
+        """Test reading the REST security rules shows an administrator."""
+        client = _client({("GET", "/rest/security/acl/rest"): 200})
+
+        permissions = probe_permissions(client)
+
+        assert (permissions.can_write, permissions.is_admin) == (True, True)
+        assert client._request.call_count == 1
+
+    def test_writer(self) -> None:
+        """Test a write that reaches the catalog shows changes are allowed."""
+        client = _client(
+            {("GET", "/rest/security"): 403, ("GET", "/rest/workspaces.json"): 200}
+        )
+
+        permissions = probe_permissions(client)
+
+        assert permissions.to_dict() == {
+            "canRead": True,
+            "canWrite": True,
+            "isAdmin": False,
+            "reason": None,
+        }
+        method, path = client._request.call_args.args
+        assert method == "PUT"
+        assert path.startswith("/rest/workspaces/cloudbench-permission-probe-")
+
+    def test_reader(self) -> None:
+        """Test a write refused by the security filter greys out changes."""
+        client = _client(
+            {
+                ("GET", "/rest/security"): 403,
+                ("GET", "/rest/workspaces.json"): 200,
+                ("PUT", "/rest/workspaces/"): 403,
+            }
+        )
+
+        permissions = probe_permissions(client)
+
+        assert (permissions.can_read, permissions.can_write) == (True, False)
+        assert permissions.reason == "The server only lets viewer read (HTTP 403 on a test write)"
+
+    def test_read_only_connection(self) -> None:
+        """Test a read-only connection isn't probed at all."""
+        client = _client({}, read_only=True)
+
+        permissions = probe_permissions(client)
+
+        assert not permissions.can_write
+        client._request.assert_not_called()
+
+    def test_unreachable(self) -> None:
+        """Test an unreachable server leaves the actions enabled."""
+        client = _client({})
+        client._request.side_effect = GeoServerError("HTTP error: connection refused")
+
+        assert probe_permissions(client).can_write
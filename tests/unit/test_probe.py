@@ -0,0 +1,176 @@
+"""Unit tests for probing a connection's latency and throughput."""
+
+from contextlib import contextmanager
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.probe import (
+    MIN_THROUGHPUT_BYTES,
+    ProbeResult,
+    ProbeStep,
+    pick_layer,
+    run_probe,
+)
+
+
+class FakeClock:
+    """Monotonic seconds that advance only when told to."""
+
+    def __init__(self) -> None:
+        self.now = 0.0
+
+    def __call__(self) -> float:
+        return self.now
+
+    def advance(self, ms: float) -> None:
+        self.now += ms / 1000
+
+
+def _client(clock: FakeClock, rest_ms: float = 20, map_ms: float = 100) -> MagicMock:
+    client = MagicMock()
+    client.connection.name = "Production"
+    client.list_layers.return_value = [{"name": "topp:dem"}, {"name": "topp:roads"}]
+    client.get_layer.side_effect = lambda ws, name: {
+        "type": "VECTOR" if name == "roads" else "RASTER"
+    }
+    client.get_layer_bounds.return_value = {
+        "latLonBoundingBox": {"minx": 0, "miny": 0, "maxx": 1, "maxy": 1}
+    }
+
+    def request(method: str, path: str) -> MagicMock:
+        clock.advance(rest_ms)
+        return MagicMock(status_code=200, content=b"{}")
+
+    def get_map(*args, **kwargs) -> bytes:
+        clock.advance(map_ms)
+        return b"png"
+
+    client._request.side_effect = request
+    client.get_map.side_effect = get_map
+    return client
+
+
+def _download(client: MagicMock, clock: FakeClock, wait_ms: float, chunks: list[tuple]) -> None:
+    """Serve GetFeature after a wait, as (bytes, ms) chunks."""
+
+    def iter_bytes():
+        clock.advance(wait_ms)
+        for size, ms in chunks:
+            yield b"x" * size
+            clock.advance(ms)
+
+    @contextmanager
+    def open_(method, path, params=None):
+        yield MagicMock(status_code=200, iter_bytes=iter_bytes)
+
+    client._open.side_effect = open_
+
+
+class TestPickLayer:
+    """Tests for choosing the sample layer."""
+
+    def test_prefers_vector_layers(self) -> None:
+        """Test the first vector layer is taken over an earlier raster."""
+        client = _client(FakeClock())
+
+        assert pick_layer(client) == ("topp:roads", True)
+
+    def test_falls_back_to_first_layer(self) -> None:
+        """Test a catalog without vector layers still gets a GetMap."""
+        client = _client(FakeClock())
+        client.get_layer.side_effect = GeoServerError("Not found", status_code=404)
+
+        assert pick_layer(client) == ("topp:dem", False)
+
+    def test_no_layers(self) -> None:
+        """Test an empty catalog has nothing to probe."""
+        client = _client(FakeClock())
+        client.list_layers.return_value = []
+
+        assert pick_layer(client) is None
+
+
+class TestRunProbe:
+    """Tests for timing a connection's requests."""
+
+    def test_times_every_step(self) -> None:
+        """Test the round trip, render and download are timed apart."""
+        clock = FakeClock()
+        client = _client(clock, rest_ms=20, map_ms=100)
+        _download(client, clock, 50, [(MIN_THROUGHPUT_BYTES, 100), (MIN_THROUGHPUT_BYTES, 100)])
+
+        result = run_probe(client, clock=clock)
+
+        rest, getmap, features = result.steps
+        assert result.layer == "topp:roads"
+        assert rest.ms == pytest.approx(20)
+        assert getmap.ms == pytest.approx(100)
+        assert features.first_byte_ms == pytest.approx(50)
+        assert features.ms == pytest.approx(250)
+        assert features.bytes == 2 * MIN_THROUGHPUT_BYTES
+        assert features.throughput == pytest.approx(2 * MIN_THROUGHPUT_BYTES / 0.2)
+        assert result.findings() == ["The link and the server respond normally"]
+
+    def test_raster_layer_skips_download(self) -> None:
+        """Test a raster sample layer is rendered but not downloaded."""
+        clock = FakeClock()
+        client = _client(clock)
+
+        result = run_probe(client, "topp:dem", clock=clock)
+
+        assert result.steps[1].ok
+        assert not client._open.called
+        assert result.steps[2].error == "topp:dem is not a vector layer"
+
+    def test_failures_stay_on_their_step(self) -> None:
+        """Test a failed GetMap doesn't stop the download being timed."""
+        clock = FakeClock()
+        client = _client(clock)
+        client.get_map.side_effect = GeoServerError("Rendering failed", status_code=500)
+        _download(client, clock, 50, [(100, 10)])
+
+        result = run_probe(client, clock=clock)
+
+        assert result.steps[1].error == "Rendering failed"
+        assert result.steps[2].ok
+        assert "respond normally" not in " ".join(result.findings())
+
+
+class TestFindings:
+    """Tests for explaining what slows a connection down."""
+
+    def _result(self, *steps: ProbeStep) -> ProbeResult:
+        return ProbeResult("Production", "topp:roads", list(steps))
+
+    def test_slow_link(self) -> None:
+        """Test slow round trips blame the link, not the renderer."""
+        findings = self._result(
+            ProbeStep("rest", "REST round trip", ms=500),
+            ProbeStep("getmap", "GetMap render", ms=900),
+        ).findings()
+
+        assert len(findings) == 1
+        assert "link" in findings[0]
+
+    def test_slow_server(self) -> None:
+        """Test time beyond a round trip blames the server."""
+        findings = self._result(
+            ProbeStep("rest", "REST round trip", ms=20),
+            ProbeStep("getmap", "GetMap render", ms=2000),
+            ProbeStep("getfeature", "WFS download", ms=3100, first_byte_ms=3000, bytes=100),
+        ).findings()
+
+        assert [f.split(" ")[0] for f in findings] == ["Rendering", "Features"]
+
+    def test_low_throughput(self) -> None:
+        """Test slow downloads blame the bandwidth."""
+        findings = self._result(
+            ProbeStep("rest", "REST round trip", ms=20),
+            ProbeStep(
+                "getfeature", "WFS download", ms=2050, first_byte_ms=50, bytes=MIN_THROUGHPUT_BYTES
+            ),
+        ).findings()
+
+        assert findings == ["Downloads run at 128.0 KB/s: bandwidth limits large responses"]
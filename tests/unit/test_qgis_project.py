@@ -0,0 +1,276 @@
+"""Unit tests for reading QGIS projects and publishing them to GeoServer."""
+
+import xml.etree.ElementTree as ET
+import zipfile
+from pathlib import Path
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.sld import SLD_NS
+from apps.qgis.project import (
+    parse_file_source,
+    parse_postgres_source,
+    parse_project,
+    read_project,
+)
+from apps.qgis.publish import publish_project
+from apps.qgis.symbology import qgis_color, renderer_to_sld, to_pixels
+
+PROJECT = """<qgis projectname="City" version="3.34.0">
+  <title>City Map</title>
+  <layer-tree-group>
+    <layer-tree-layer id="roads_1" name="Roads"/>
+    <layer-tree-group name="Base">
+      <layer-tree-layer id="parcels_1" name="Parcels"/>
+      <layer-tree-layer id="osm_1" name="OSM"/>
+    </layer-tree-group>
+  </layer-tree-group>
+  <projectlayers>
+    <maplayer type="vector" geometry="Line">
+      <id>roads_1</id>
+      <layername>Roads</layername>
+      <provider>postgres</provider>
+      <datasource>dbname='gis' host=db port=5432 user='me' password='secret' srid=4326 \
+type=LineString table="transport"."roads" (geom)</datasource>
+      <srs><spatialrefsys><authid>EPSG:4326</authid></spatialrefsys></srs>
+      <renderer-v2 type="singleSymbol">
+        <symbols>
+          <symbol type="line" name="0">
+            <layer class="SimpleLine">
+              <Option type="Map">
+                <Option name="line_color" value="255,0,0,255" type="QString"/>
+                <Option name="line_width" value="0.5" type="QString"/>
+              </Option>
+            </layer>
+          </symbol>
+        </symbols>
+      </renderer-v2>
+    </maplayer>
+    <maplayer type="vector">
+      <id>parcels_1</id>
+      <layername>Parcels</layername>
+      <provider>ogr</provider>
+      <datasource>./data/city.gpkg|layername=parcels</datasource>
+      <renderer-v2 type="categorizedSymbol" attr="zone">
+        <categories>
+          <category value="R" label="Residential" symbol="0" render="true"/>
+          <category value="" label="Other" symbol="1" render="true"/>
+        </categories>
+        <symbols>
+          <symbol type="fill" name="0">
+            <layer class="SimpleFill">
+              <prop k="color" v="0,128,0,128"/>
+              <prop k="outline_color" v="0,0,0,255"/>
+            </layer>
+          </symbol>
+          <symbol type="fill" name="1">
+            <layer class="SimpleFill">
+              <prop k="color" v="200,200,200,255"/>
+            </layer>
+          </symbol>
+        </symbols>
+      </renderer-v2>
+    </maplayer>
+    <maplayer type="raster">
+      <id>osm_1</id>
+      <layername>OSM</layername>
+      <provider>wms</provider>
+      <datasource>type=xyz&amp;url=https://tile.openstreetmap.org/{z}/{x}/{y}.png</datasource>
+    </maplayer>
+  </projectlayers>
+</qgis>
+"""
+
+
+def _sld_tags(sld: str, tag: str) -> list[ET.Element]:
+    return ET.fromstring(sld).findall(f".//{{{SLD_NS}}}{tag}")
+
+
+class TestSources:
+    """Test reading layers' data sources."""
+
+    def test_postgres_source(self):
+        source = parse_postgres_source(
+            "dbname='gis' host=db port=5433 user='me' sslmode=disable "
+            'table="transport"."roads" (geom) sql="type" = 1'
+        )
+
+        assert source.database == "gis"
+        assert source.host == "db"
+        assert source.port == "5433"
+        assert source.user == "me"
+        assert source.schema == "transport"
+        assert source.table == "roads"
+        assert source.geometry_column == "geom"
+
+    def test_postgres_service_without_schema(self):
+        source = parse_postgres_source("service='gis' table=roads")
+
+        assert source.service == "gis"
+        assert source.schema == "public"
+        assert source.table == "roads"
+        assert source.geometry_column == ""
+
+    def test_file_source_is_relative_to_project(self, tmp_path: Path):
+        source = parse_file_source("./data/city.gpkg|layername=parcels", tmp_path)
+
+        assert source.path == (tmp_path / "data" / "city.gpkg").resolve()
+        assert source.layer == "parcels"
+        assert source.extension == "gpkg"
+
+
+class TestReadProject:
+    """Test reading project files."""
+
+    def test_layers_and_tree(self):
+        project = parse_project(PROJECT, Path("/maps/city.qgs"))
+
+        assert project.title == "City Map"
+        assert set(project.layers) == {"roads_1", "parcels_1", "osm_1"}
+        assert project.layers["roads_1"].srs == "EPSG:4326"
+        # The geometry of older projects comes from their symbols
+        assert project.layers["parcels_1"].geometry == "Polygon"
+        assert project.tree.layer_ids() == ["roads_1", "parcels_1", "osm_1"]
+        assert project.tree.children[1].name == "Base"
+
+    def test_qgz(self, tmp_path: Path):
+        path = tmp_path / "city.qgz"
+        with zipfile.ZipFile(path, "w") as archive:
+            archive.writestr("city.qgs", PROJECT)
+
+        assert read_project(path).title == "City Map"
+
+    def test_not_a_project(self, tmp_path: Path):
+        path = tmp_path / "city.qgs"
+        path.write_text("<html/>")
+
+        with pytest.raises(ValueError, match="not a QGIS project"):
+            read_project(path)
+
+
+class TestSymbology:
+    """Test converting QGIS renderers to SLD."""
+
+    def test_helpers(self):
+        assert qgis_color("255,0,0,128") == ("#ff0000", 0.5)
+        assert qgis_color("red") is None
+        assert to_pixels("1", "MM", 0) == pytest.approx(3.78, abs=0.01)
+        assert to_pixels("3", "Pixel", 0) == 3
+
+    def test_single_symbol(self):
+        project = parse_project(PROJECT, Path("city.qgs"))
+        sld = renderer_to_sld("roads", project.layers["roads_1"].renderer)
+
+        assert sld is not None
+        strokes = {p.get("name"): p.text for p in _sld_tags(sld, "CssParameter")}
+        assert strokes["stroke"] == "#ff0000"
+        assert strokes["stroke-width"] == str(to_pixels("0.5", "MM", 0))
+        assert len(_sld_tags(sld, "Rule")) == 1
+
+    def test_categorized_with_else(self):
+        project = parse_project(PROJECT, Path("city.qgs"))
+        sld = renderer_to_sld("parcels", project.layers["parcels_1"].renderer)
+
+        assert sld is not None
+        rules = _sld_tags(sld, "Rule")
+        assert len(rules) == 2
+        assert "PropertyIsEqualTo" in ET.tostring(rules[0], encoding="unicode")
+        assert rules[1].find(f"{{{SLD_NS}}}ElseFilter") is not None
+        assert len(_sld_tags(sld, "PolygonSymbolizer")) == 2
+
+    def test_unsupported(self):
+        renderer = ET.fromstring('<renderer-v2 type="heatmapRenderer"/>')
+        assert renderer_to_sld("heat", renderer) is None
+
+        renderer = ET.fromstring(
+            '<renderer-v2 type="singleSymbol"><symbols><symbol name="0">'
+            '<layer class="SvgMarker"/></symbol></symbols></renderer-v2>'
+        )
+        assert renderer_to_sld("svg", renderer) is None
+
+
+def _client() -> MagicMock:
+    client = MagicMock()
+    client.list_layers.return_value = [{"name": "roads"}]
+    client.list_layergroups.return_value = []
+    client.list_datastores.return_value = []
+    client.list_coveragestores.return_value = []
+    client.list_styles.return_value = []
+    return client
+
+
+class TestPublishProject:
+    """Test publishing a project's layers and layer tree."""
+
+    def test_publish(self, tmp_path: Path):
+        (tmp_path / "data").mkdir()
+        (tmp_path / "data" / "city.gpkg").write_bytes(b"gpkg")
+        project = parse_project(PROJECT, tmp_path / "city.qgs")
+        client = _client()
+
+        result = publish_project(client, project, "city")
+
+        outcomes = {outcome.source: outcome for outcome in result.layers}
+        # A layer of that name already exists
+        assert outcomes["Roads"].layer == "roads_2"
+        assert outcomes["Roads"].store == "gis_transport"
+        assert outcomes["Roads"].style == "roads_2"
+        assert outcomes["Parcels"].store == "city"
+        assert outcomes["OSM"].status == "skipped"
+        assert result.count("published") == 2
+
+        params = client.create_datastore.call_args.args[2]
+        assert params["dbtype"] == "postgis"
+        assert params["schema"] == "transport"
+        client.upload_geopackage.assert_called_once_with(
+            "city", "city", b"gpkg", configure="none"
+        )
+        client.create_featuretype.assert_any_call(
+            "city", "city", "parcels", native_name="parcels", title="Parcels", srs=None
+        )
+        client.update_layer_styles.assert_any_call("city", "roads_2", "city:roads_2")
+
+        # Inner groups first; members bottom of the layer panel first
+        assert result.groups == ["city:base", "city:city_map"]
+        inner, outer = client.create_layergroup.call_args_list
+        assert inner.args[:3] == ("base", ["city:parcels"], "city")
+        assert outer.args[1] == ["city:base", "city:roads_2"]
+        assert outer.kwargs["nested"] == {"city:base"}
+
+    def test_existing_postgis_store_is_reused(self, tmp_path: Path):
+        project = parse_project(PROJECT, tmp_path / "city.qgs")
+        client = _client()
+        client.list_datastores.return_value = [{"name": "transport"}]
+        client.get_datastore.return_value = {
+            "connectionParameters": {
+                "entry": [
+                    {"@key": "dbtype", "$": "postgis"},
+                    {"@key": "host", "$": "db"},
+                    {"@key": "port", "$": "5432"},
+                    {"@key": "database", "$": "gis"},
+                    {"@key": "schema", "$": "transport"},
+                ]
+            }
+        }
+
+        result = publish_project(client, project, "city")
+
+        assert result.layers[0].store == "transport"
+        client.create_datastore.assert_not_called()
+
+    def test_failures_are_recorded(self, tmp_path: Path):
+        project = parse_project(PROJECT, tmp_path / "city.qgs")
+        client = _client()
+        client.create_datastore.side_effect = GeoServerError("Store exists", 500)
+
+        result = publish_project(client, project, "city")
+
+        outcomes = {outcome.source: outcome for outcome in result.layers}
+        assert outcomes["Roads"].status == "error"
+        assert outcomes["Roads"].message == "Store exists"
+        assert outcomes["Parcels"].status == "error"
+        assert "File not found" in outcomes["Parcels"].message
+        assert result.groups == []
+        client.create_layergroup.assert_not_called()
@@ -0,0 +1,134 @@
+"""Unit tests for per-connection request limiting."""
+
+import threading
+import time
+
+import pytest
+
+from apps.core.managers import ClientManager
+from apps.core.ratelimit import RequestLimiter, TokenBucket
+
+
+class FakeClock:
+    """Manually advanced clock; sleeping advances time."""
+
+    def __init__(self) -> None:
+        self.now = 0.0
+        self.sleeps: list[float] = []
+
+    def __call__(self) -> float:
+        return self.now
+
+    def sleep(self, seconds: float) -> None:
+        self.sleeps.append(seconds)
+        self.now += seconds
+
+
+class TestTokenBucket:
+    """Tests for the token bucket."""
+
+    def test_burst_is_served_immediately(self) -> None:
+        """Test a full bucket doesn't block."""
+        clock = FakeClock()
+        bucket = TokenBucket(5, clock=clock, sleep=clock.sleep)
+
+        for _ in range(5):
+            assert bucket.acquire() == 0
+
+        assert clock.sleeps == []
+
+    def test_waits_when_empty(self) -> None:
+        """Test acquiring from an empty bucket waits for a refill."""
+        clock = FakeClock()
+        bucket = TokenBucket(2, burst=1, clock=clock, sleep=clock.sleep)
+
+        bucket.acquire()
+        waited = bucket.acquire()
+
+        assert waited == pytest.approx(0.5)
+        assert clock.now == pytest.approx(0.5)
+
+    def test_refills_over_time(self) -> None:
+        """Test tokens accumulate while idle, up to capacity."""
+        clock = FakeClock()
+        bucket = TokenBucket(1, burst=3, clock=clock, sleep=clock.sleep)
+
+        for _ in range(3):
+            bucket.acquire()
+        clock.now += 10
+
+        for _ in range(3):
+            assert bucket.acquire() == 0
+        assert bucket.acquire() == pytest.approx(1.0)
+
+    def test_rejects_non_positive_rate(self) -> None:
+        """Test a zero rate is rejected."""
+        with pytest.raises(ValueError):
+            TokenBucket(0)
+
+
+class TestRequestLimiter:
+    """Tests for the request limiter."""
+
+    def test_disabled_by_default(self) -> None:
+        """Test zero limits disable limiting."""
+        limiter = RequestLimiter()
+        assert not limiter.enabled
+        with limiter.slot():
+            pass
+
+    def test_caps_concurrency(self) -> None:
+        """Test no more than max_concurrent slots are held at once."""
+        limiter = RequestLimiter(max_concurrent=2)
+        active = 0
+        peak = 0
+        lock = threading.Lock()
+
+        def work() -> None:
+            nonlocal active, peak
+            with limiter.slot():
+                with lock:
+                    active += 1
+                    peak = max(peak, active)
+                time.sleep(0.02)
+                with lock:
+                    active -= 1
+
+        threads = [threading.Thread(target=work) for _ in range(6)]
+        for t in threads:
+            t.start()
+        for t in threads:
+            t.join()
+
+        assert peak == 2
+
+    def test_slot_released_on_error(self) -> None:
+        """Test a failing request releases its slot."""
+        limiter = RequestLimiter(max_concurrent=1)
+
+        with pytest.raises(RuntimeError):
+            with limiter.slot():
+                raise RuntimeError("boom")
+
+        with limiter.slot():
+            pass
+
+
+class TestClientManagerLimiter:
+    """Tests for limiter sharing in the client manager."""
+
+    def test_limiter_shared_per_connection(self) -> None:
+        """Test clients of one connection share a limiter."""
+        manager = ClientManager()
+        first = manager.get_limiter("conn_rl", 2, 5)
+        assert manager.get_limiter("conn_rl", 2, 5) is first
+        manager.remove_client("conn_rl")
+
+    def test_limiter_rebuilt_when_limits_change(self) -> None:
+        """Test changing the limits replaces the limiter."""
+        manager = ClientManager()
+        first = manager.get_limiter("conn_rl", 2, 5)
+        second = manager.get_limiter("conn_rl", 4, 5)
+        assert second is not first
+        assert second.max_concurrent == 4
+        manager.remove_client("conn_rl")
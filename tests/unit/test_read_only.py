@@ -0,0 +1,71 @@
+"""Unit tests for read-only connections."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.config import Connection
+from apps.core.exceptions import ReadOnlyConnectionError
+from apps.core.read_only import check_writable
+
+
+def _connection(read_only: bool) -> Connection:
+    return Connection(
+        name="prod", url="http://gs/geoserver", username="admin", password="x", read_only=read_only
+    )
+
+
+class TestCheckWritable:
+    """Tests for refusing changes to read-only connections."""
+
+    def test_reads_allowed(self) -> None:
+        """Test reads go through on a read-only connection."""
+        for method in ("GET", "head", "OPTIONS"):
+            check_writable(_connection(True), method, "/rest/workspaces")
+
+    def test_changes_refused(self) -> None:
+        """Test changes are refused with a 403 naming the connection."""
+        for method in ("POST", "PUT", "DELETE", "patch"):
+            with pytest.raises(ReadOnlyConnectionError):
+                check_writable(_connection(True), method, "/rest/workspaces/topp")
+
+        error = ReadOnlyConnectionError("prod", "DELETE", "/rest/workspaces/topp")
+        assert error.status_code == 403
+        assert "'prod' is read-only" in error.message
+
+    def test_writable_connection(self) -> None:
+        """Test changes go through when the connection isn't read-only."""
+        check_writable(_connection(False), "DELETE", "/rest/workspaces/topp")
+
+
+class TestClientsRefuseChanges:
+    """Tests for the clients' request guard."""
+
+    def test_geoserver_change_not_sent(self, make_geoserver_client) -> None:
+        """Test a GeoServer change is refused before reaching the server."""
+        client = make_geoserver_client(mock_requests=False, read_only=True)
+
+        with pytest.raises(ReadOnlyConnectionError):
+            client._request("DELETE", "/workspaces/topp")
+
+        client._client.stream.assert_not_called()
+
+    def test_geoserver_read_sent(self, make_geoserver_client) -> None:
+        """Test a GeoServer read still goes out."""
+        client = make_geoserver_client(mock_requests=False, read_only=True)
+        response = MagicMock(status_code=200, headers={})
+        response.iter_bytes.return_value = iter([b"{}"])
+        client._client.stream.return_value.__enter__.return_value = response
+
+        client._request("GET", "/workspaces.json")
+
+        client._client.stream.assert_called_once_with("GET", "/rest/workspaces.json")
+
+    def test_gwc_change_not_sent(self, make_gwc_client) -> None:
+        """Test a GWC seed is refused before reaching the server."""
+        client = make_gwc_client(mock_requests=False, read_only=True)
+
+        with pytest.raises(ReadOnlyConnectionError):
+            client._request("POST", "/seed/topp:roads.json")
+
+        client._client.stream.assert_not_called()
@@ -0,0 +1,159 @@
+"""Unit tests for recently added and modified layers and styles.
+
+Tests reading GeoServer's timestamps, the local activity log and merging
+the two into the lists shown in the tree.
+"""
+
+import json
+from datetime import datetime, timezone
+from pathlib import Path
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.recent import ActivityLog, parse_timestamp, recent_items
+
+
+def _client(layers: dict[str, dict], styles: dict[str, dict]) -> MagicMock:
+    """A client for one workspace, "topp", with the given layer and style details."""
+    client = MagicMock()
+    client.connection.id = "local"
+    client.list_workspaces.return_value = [{"name": "topp"}]
+    client.list_layers.return_value = [{"name": name} for name in layers]
+    client.list_styles.side_effect = lambda ws=None: (
+        [{"name": name} for name in styles] if ws else []
+    )
+    client.get_layer.side_effect = lambda ws, name: layers[name]
+    client.get_style.side_effect = lambda name, ws=None: styles[name]
+    return client
+
+
+class TestParseTimestamp:
+    """Tests for reading GeoServer timestamps."""
+
+    @pytest.mark.parametrize(
+        "value,expected",
+        [
+            ("2024-05-23 09:39:21.478 UTC", datetime(2024, 5, 23, 9, 39, 21, 478000)),
+            ("2024-05-23 09:39:21 UTC", datetime(2024, 5, 23, 9, 39, 21)),
+            ("2024-05-23T09:39:21Z", datetime(2024, 5, 23, 9, 39, 21)),
+        ],
+    )
+    def test_formats(self, value: str, expected: datetime) -> None:
+        """Test GeoServer's format and ISO 8601 are read as UTC."""
+        assert parse_timestamp(value) == expected.replace(tzinfo=timezone.utc)
+
+    @pytest.mark.parametrize("value", [None, "", "yesterday", 1716457161])
+    def test_not_a_timestamp(self, value: object) -> None:
+        """Test missing and unreadable values give None."""
+        assert parse_timestamp(value) is None
+
+
+class TestActivityLog:
+    """Tests for the local activity log."""
+
+    def test_record_and_forget(self, tmp_path: Path) -> None:
+        """Test added items are also modified, and deleted ones are dropped."""
+        log = ActivityLog(tmp_path / "recent.json")
+        log.record("local", "layer", "topp", "roads", added=True)
+        log.record("local", "style", None, "line")
+
+        assert [i.name for i in log.items("local", "added")] == ["roads"]
+        assert {i.name for i in log.items("local", "modified")} == {"roads", "line"}
+        assert log.items("other", "modified") == []
+
+        log.forget("local", "layer", "topp", "roads")
+
+        assert [i.name for i in log.items("local", "modified")] == ["line"]
+
+    def test_unreadable_file(self, tmp_path: Path) -> None:
+        """Test a damaged log is started afresh."""
+        path = tmp_path / "recent.json"
+        path.write_text("not json")
+        log = ActivityLog(path)
+
+        log.record("local", "layer", "topp", "roads")
+
+        assert list(json.loads(path.read_text())["local"]) == ["layer:topp:roads"]
+
+
+class TestRecentItems:
+    """Tests for merging GeoServer's timestamps with the local log."""
+
+    def test_newest_first(self, tmp_path: Path) -> None:
+        """Test items are sorted newest first, limited, and never-modified ones count."""
+        client = _client(
+            {
+                "roads": {
+                    "dateCreated": "2024-01-01 10:00:00.000 UTC",
+                    "dateModified": "2024-03-01 10:00:00.000 UTC",
+                },
+                "lakes": {"dateCreated": "2024-02-01 10:00:00.000 UTC"},
+                "old": {},
+            },
+            {"line": {"dateCreated": "2024-01-15 10:00:00.000 UTC"}},
+        )
+
+        items = recent_items(client, limit=2, log=ActivityLog(tmp_path / "recent.json"))
+
+        assert [i.name for i in items["modified"]] == ["roads", "lakes"]
+        assert [i.name for i in items["added"]] == ["lakes", "line"]
+        assert items["added"][1].to_dict() == {
+            "type": "style",
+            "name": "line",
+            "workspace": "topp",
+            "time": "2024-01-15T10:00:00+00:00",
+            "source": "server",
+        }
+
+    def test_local_changes_win_when_later(self, tmp_path: Path) -> None:
+        """Test a change made through the client outranks an older server time."""
+        client = _client({"roads": {"dateModified": "2024-03-01 10:00:00.000 UTC"}}, {})
+        log = ActivityLog(tmp_path / "recent.json")
+        log.record("local", "layer", "topp", "roads")
+
+        items = recent_items(client, log=log)
+
+        assert [(i.name, i.source) for i in items["modified"]] == [("roads", "local")]
+
+    def test_server_unavailable(self, tmp_path: Path) -> None:
+        """Test the local log is still listed when GeoServer can't be read."""
+        client = MagicMock()
+        client.connection.id = "local"
+        client.list_workspaces.side_effect = GeoServerError("down", status_code=503)
+        log = ActivityLog(tmp_path / "recent.json")
+        log.record("local", "style", "topp", "line", added=True)
+
+        items = recent_items(client, log=log)
+
+        assert [i.name for i in items["added"]] == ["line"]
+
+
+class TestClientRecording:
+    """Tests for changes made through the client being logged."""
+
+    def test_changes_logged(self, tmp_path: Path, make_geoserver_client) -> None:
+        """Test creating and deleting a layer updates the log."""
+        log = ActivityLog(tmp_path / "recent.json")
+        client = make_geoserver_client(id="local")
+        client._request.return_value = MagicMock(status_code=201)
+
+        with patch("apps.geoserver.client.activity_log", log):
+            client.create_featuretype("topp", "db", "roads")
+            assert [i.name for i in log.items("local", "added")] == ["roads"]
+
+            client.delete_layer("topp", "roads")
+            assert log.items("local", "modified") == []
+
+    def test_failed_change_not_logged(self, tmp_path: Path, make_geoserver_client) -> None:
+        """Test a change GeoServer refused isn't logged."""
+        log = ActivityLog(tmp_path / "recent.json")
+        client = make_geoserver_client(id="local")
+        client._request.return_value = MagicMock(status_code=404, text="no")
+
+        with patch("apps.geoserver.client.activity_log", log):
+            with pytest.raises(GeoServerError):
+                client.update_layer("topp", "roads", enabled=False)
+
+        assert log.items("local", "modified") == []
@@ -0,0 +1,134 @@
+"""Unit tests for the render time benchmark."""
+
+import random
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.render_benchmark import (
+    MAX_REQUESTS,
+    fitting_zoom,
+    percentile,
+    plan_zooms,
+    run_benchmark,
+    sample_bboxes,
+    zoom_resolution,
+)
+
+# Roughly South Africa, in lon/lat
+LATLON = {"minx": 16.0, "miny": -35.0, "maxx": 33.0, "maxy": -22.0}
+
+
+class FakeClock:
+    """Clock advanced by each GetMap request by the next of a list of durations."""
+
+    def __init__(self, durations: list[float]) -> None:
+        self.now = 0.0
+        self.durations = iter(durations)
+
+    def __call__(self) -> float:
+        return self.now
+
+    def render(self, *args, **kwargs) -> bytes:
+        self.now += next(self.durations, 0.0)
+        return b"x" * 1000
+
+
+def _client(clock: FakeClock) -> MagicMock:
+    client = MagicMock()
+    client.get_layer_bounds.return_value = {"latLonBoundingBox": LATLON}
+    client.get_map.side_effect = clock.render
+    return client
+
+
+class TestRenderBenchmark:
+    """Tests for planning and timing GetMap requests."""
+
+    def test_percentile_uses_nearest_rank(self) -> None:
+        """Test p50 and p95 pick measured values."""
+        values = [float(v) for v in range(1, 21)]
+
+        assert percentile(values, 0.5) == 10.0
+        assert percentile(values, 0.95) == 19.0
+        assert percentile([], 0.5) is None
+
+    def test_fitting_zoom_frames_the_bounds(self) -> None:
+        """Test the layer fits at the zoom level and not at the next one."""
+        bounds = (0.0, 0.0, 2_000_000.0, 1_000_000.0)
+        zoom = fitting_zoom(bounds, 512)
+
+        assert zoom_resolution(zoom) * 512 >= 2_000_000
+        assert zoom_resolution(zoom + 1) * 512 < 2_000_000
+
+    def test_plan_zooms_defaults_and_limits(self) -> None:
+        """Test zoom levels come from the layer unless given, and are checked."""
+        bounds = (0.0, 0.0, 2_000_000.0, 1_000_000.0)
+        start = fitting_zoom(bounds, 512)
+
+        assert plan_zooms(bounds, 512, None) == [start, start + 2, start + 4]
+        assert plan_zooms(bounds, 512, [12, 8, 12]) == [8, 12]
+        with pytest.raises(ValueError, match="between 0"):
+            plan_zooms(bounds, 512, [30])
+
+    def test_sampled_extents_stay_in_the_bounds(self) -> None:
+        """Test deep zoom samples are centered inside the layer."""
+        bounds = (0.0, 0.0, 1_000_000.0, 1_000_000.0)
+
+        extents = sample_bboxes(bounds, 12, 256, 5, random.Random(1))
+
+        assert len(extents) == 5
+        for minx, miny, maxx, maxy in extents:
+            assert 0 <= (minx + maxx) / 2 <= 1_000_000
+            assert maxx - minx == pytest.approx(zoom_resolution(12) * 256)
+
+    def test_shallow_zoom_takes_one_sample(self) -> None:
+        """Test a zoom level showing the whole layer is rendered once."""
+        bounds = (0.0, 0.0, 1000.0, 1000.0)
+
+        assert len(sample_bboxes(bounds, 2, 256, 5, random.Random(1))) == 1
+
+    def test_run_reports_times_per_zoom(self) -> None:
+        """Test each request is timed after an untimed warm-up."""
+        clock = FakeClock([9.0, 0.2, 0.4, 1.5, 2.5])
+        client = _client(clock)
+
+        report = run_benchmark(
+            client, "topp", "roads", style="fancy", zooms=[10, 12], samples=2, clock=clock
+        )
+
+        assert [s.seconds for s in report.samples] == pytest.approx([0.2, 0.4, 1.5, 2.5])
+        assert client.get_map.call_count == 5
+        assert client.get_map.call_args.kwargs["style"] == "fancy"
+        stats = report.zoom_stats()
+        assert [(z.zoom, z.slow) for z in stats] == [(10, False), (12, True)]
+        assert (stats[1].p50, stats[1].p95) == pytest.approx((1.5, 2.5))
+        assert stats[0].mean_bytes == 1000
+
+    def test_failed_renders_are_counted(self) -> None:
+        """Test errors are recorded without stopping the run."""
+        clock = FakeClock([0.1, 0.1])
+        client = _client(clock)
+        client.get_map.side_effect = [b"warm", b"ok", GeoServerError("GetMap failed: boom")]
+
+        report = run_benchmark(client, "topp", "roads", zooms=[12], samples=2, clock=clock)
+
+        stats = report.zoom_stats()[0]
+        assert (stats.requests, stats.errors) == (2, 1)
+        assert report.samples[1].error == "GetMap failed: boom"
+
+    def test_run_refuses_too_many_requests(self) -> None:
+        """Test runs over the request limit are refused before rendering."""
+        client = _client(FakeClock([]))
+
+        with pytest.raises(ValueError, match=str(MAX_REQUESTS)):
+            run_benchmark(client, "topp", "roads", zooms=list(range(10, 21)), samples=10)
+        client.get_map.assert_not_called()
+
+    def test_run_needs_bounds(self) -> None:
+        """Test layers without lat/lon bounds can't be benchmarked."""
+        client = _client(FakeClock([]))
+        client.get_layer_bounds.return_value = {"latLonBoundingBox": None}
+
+        with pytest.raises(GeoServerError):
+            run_benchmark(client, "topp", "roads")
@@ -0,0 +1,67 @@
+"""Unit tests for response size limits.
+
+Tests refusing responses bigger than a connection allows, before and
+while reading them, and keeping only the start of error responses.
+"""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import ResponseTooLargeError
+from apps.core.response_limits import ERROR_BODY_BYTES, read_limited
+
+MB = 1024 * 1024
+
+
+def _response(status_code: int, chunks: list[bytes], length: int | None = None) -> MagicMock:
+    """A streamed response yielding its body in chunks."""
+    response = MagicMock(status_code=status_code)
+    response.headers = {"content-length": str(length)} if length is not None else {}
+    response.request.url = "http://gs/geoserver/rest/layers.json"
+    response.iter_bytes.return_value = iter(chunks)
+    return response
+
+
+class TestReadLimited:
+    """Tests for reading response bodies up to a limit."""
+
+    def test_within_limit(self) -> None:
+        """Test a body under the limit is read whole."""
+        response = _response(200, [b"{}", b"[]"], length=4)
+
+        assert read_limited(response, 1)._content == b"{}[]"
+
+    def test_content_length_over_limit(self) -> None:
+        """Test a declared size over the limit is refused without reading."""
+        response = _response(200, [b"x"], length=80 * MB)
+
+        with pytest.raises(ResponseTooLargeError, match="was 80.0 MB, more than the 64 MB"):
+            read_limited(response, 64)
+
+        response.iter_bytes.assert_not_called()
+
+    def test_stream_over_limit(self) -> None:
+        """Test a body without a size stops being read once over the limit."""
+        chunks = iter([b"x" * MB, b"x" * MB, b"never read"])
+        response = _response(200, [])
+        response.iter_bytes.return_value = chunks
+
+        with pytest.raises(ResponseTooLargeError, match="was more than the 1.5 MB") as error:
+            read_limited(response, 1.5)
+
+        assert error.value.status_code == 502
+        assert next(chunks) == b"never read"
+
+    def test_no_limit(self) -> None:
+        """Test a limit of 0 reads any size."""
+        response = _response(200, [b"x" * MB] * 3, length=3 * MB)
+
+        assert len(read_limited(response, 0)._content) == 3 * MB
+
+    @pytest.mark.parametrize("max_response_mb", [0, 64])
+    def test_error_body_truncated(self, max_response_mb: float) -> None:
+        """Test an error response keeps only the start of its body."""
+        response = _response(500, [b"e" * ERROR_BODY_BYTES, b"e" * ERROR_BODY_BYTES], length=MB)
+
+        assert read_limited(response, max_response_mb)._content == b"e" * ERROR_BODY_BYTES
@@ -0,0 +1,142 @@
+"""Unit tests for talking XML to the GeoServer REST API.
+
+Tests reading XML responses in the shape of GeoServer's JSON, writing
+request bodies as XML and falling back to XML when JSON can't be read.
+"""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.rest_xml import encode_rest_xml, is_core_path, parse_rest_xml, xml_path
+
+
+def _response(content: bytes, content_type: str) -> MagicMock:
+    response = MagicMock(status_code=200, content=content, headers={"content-type": content_type})
+    response.json.side_effect = ValueError("Expecting value")
+    return response
+
+
+class TestParseRestXml:
+    """Tests for reading XML as GeoServer's JSON."""
+
+    def test_single_item_collection(self) -> None:
+        """Test a collection of one is still a list, as in JSON."""
+        data = parse_rest_xml(
+            b"<workspaces><workspace><name>topp</name>"
+            b"<href>http://gs/rest/workspaces/topp.xml</href></workspace></workspaces>"
+        )
+
+        assert data == {
+            "workspaces": {
+                "workspace": [{"name": "topp", "href": "http://gs/rest/workspaces/topp.xml"}]
+            }
+        }
+
+    def test_empty_collection(self) -> None:
+        """Test an empty collection is an empty string, as in JSON."""
+        assert parse_rest_xml(b"<layers/>") == {"layers": ""}
+
+    def test_attributes_booleans_and_numbers(self) -> None:
+        """Test entries keep their key, and flags and bounds aren't text."""
+        data = parse_rest_xml(
+            b"<dataStore><name>roads</name><enabled>false</enabled>"
+            b'<connectionParameters><entry key="host">db</entry></connectionParameters>'
+            b"<bbox><minx>-180.0</minx><maxy>90</maxy></bbox></dataStore>"
+        )
+
+        store = data["dataStore"]
+        assert store["enabled"] is False
+        assert store["connectionParameters"] == {"entry": [{"@key": "host", "$": "db"}]}
+        assert store["bbox"] == {"minx": -180.0, "maxy": 90}
+
+    def test_not_xml(self) -> None:
+        """Test a body that isn't XML is a ValueError, like bad JSON."""
+        with pytest.raises(ValueError):
+            parse_rest_xml(b"<html")
+
+
+class TestEncodeRestXml:
+    """Tests for writing request bodies as XML."""
+
+    def test_round_trip(self) -> None:
+        """Test lists repeat their element and attributes come from "@" keys."""
+        payload = {
+            "layerGroup": {
+                "name": "base",
+                "publishables": {
+                    "published": [
+                        {"@type": "layer", "name": "topp:roads"},
+                        {"@type": "layer", "name": "topp:rivers"},
+                    ]
+                },
+                "enabled": True,
+                "abstract": None,
+            }
+        }
+
+        body = encode_rest_xml(payload)
+
+        assert b'<published type="layer"><name>topp:roads</name></published>' in body
+        assert b"<abstract" not in body
+        del payload["layerGroup"]["abstract"]
+        assert parse_rest_xml(body) == payload
+
+    def test_escaped(self) -> None:
+        """Test text is escaped."""
+        body = encode_rest_xml({"workspace": {"name": "a&b <c>"}})
+
+        assert parse_rest_xml(body) == {"workspace": {"name": "a&b <c>"}}
+
+    def test_one_root(self) -> None:
+        """Test a body needs a single root element."""
+        with pytest.raises(ValueError):
+            encode_rest_xml({"workspace": {}, "style": {}})
+
+
+class TestXmlRequests:
+    """Tests for the client talking XML."""
+
+    def test_core_paths(self) -> None:
+        """Test only core catalog resources are switched to XML."""
+        assert is_core_path("/rest/workspaces/topp/datastores.json")
+        assert not is_core_path("/rest/imports/3.json")
+        assert xml_path("/rest/layers.json") == "/rest/layers.xml"
+        assert xml_path("/rest/styles/roads") == "/rest/styles/roads"
+
+    def test_writes_xml(self, make_geoserver_client) -> None:
+        """Test a JSON body is sent as XML to the .xml path."""
+        client = make_geoserver_client(mock_requests=False, rest_format="xml")
+        response = MagicMock(status_code=201, headers={})
+        response.iter_bytes.return_value = iter([b""])
+        client._client.stream.return_value.__enter__.return_value = response
+
+        client._request("POST", "/rest/workspaces.json", json={"workspace": {}})
+
+        args, kwargs = client._client.stream.call_args
+        assert args == ("POST", "/rest/workspaces.xml")
+        assert kwargs["content"] == encode_rest_xml({"workspace": {}})
+        assert kwargs["headers"] == {"Content-Type": "application/xml"}
+
+    def test_auto_falls_back(self, make_geoserver_client) -> None:
+        """Test unreadable JSON switches an auto connection to XML and retries."""
+        client = make_geoserver_client(rest_format="auto")
+        client._request.side_effect = [
+            _response(b'{"workspaces": {"workspace": [', "application/json"),
+            _response(b"<workspaces/>", "application/xml"),
+        ]
+
+        assert client._get_json("/rest/workspaces.json") == {"workspaces": ""}
+        assert client._rest_xml is True
+
+    def test_json_only_reports_error(self, make_geoserver_client) -> None:
+        """Test a connection fixed to JSON reports unreadable JSON instead."""
+        client = make_geoserver_client(rest_format="json")
+        client._request.return_value = _response(b"{", "application/json")
+
+        with pytest.raises(GeoServerError) as error:
+            client._get_json("/rest/workspaces.json")
+
+        assert error.value.status_code == 502
+        assert client._request.call_count == 1
@@ -0,0 +1,186 @@
+"""Unit tests for point clouds and 3D Tiles in S3 buckets.
+
+Conversions are tested with PDAL and py3dtiles stubbed out.
+"""
+
+import json
+import math
+import subprocess
+from pathlib import Path
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.exceptions import S3Error
+from apps.s3.three_d import (
+    ThreeDAsset,
+    conversion_target,
+    convert_point_cloud,
+    convert_to_copc,
+    detect_3d_kind,
+    find_3d_assets,
+    tileset_item,
+    tileset_items,
+    tileset_rectangle,
+)
+
+REGION = [math.radians(d) for d in (10.0, 40.0, 12.0, 41.0)] + [0.0, 100.0]
+TILESET = {"asset": {"version": "1.0"}, "root": {"boundingVolume": {"region": REGION}}}
+
+
+def listing(*keys: str) -> dict:
+    """A single page of list_objects results."""
+    return {
+        "objects": [{"key": key, "size": 10} for key in keys],
+        "isTruncated": False,
+        "nextContinuationToken": None,
+    }
+
+
+class TestDetection:
+    """Test recognising 3D data in a bucket."""
+
+    def test_detect_3d_kind(self):
+        assert detect_3d_kind("lidar/city.LAZ") == "pointcloud"
+        assert detect_3d_kind("lidar/city.las") == "pointcloud"
+        assert detect_3d_kind("lidar/city.copc.laz") == "copc"
+        assert detect_3d_kind("lidar/city_3dtiles/tileset.json") == "3dtiles"
+        assert detect_3d_kind("lidar/readme.json") is None
+
+    def test_conversion_targets(self):
+        assert conversion_target("lidar/city.laz", "copc") == "lidar/city.copc.laz"
+        assert conversion_target("lidar/city.laz", "3dtiles") == (
+            "lidar/city_3dtiles/tileset.json"
+        )
+        assert conversion_target("lidar/city.copc.laz", "3dtiles") == (
+            "lidar/city_3dtiles/tileset.json"
+        )
+
+    @pytest.mark.parametrize(
+        ("key", "target_format", "message"),
+        [
+            ("lidar/city.laz", "ept", "Unknown format"),
+            ("imagery/city.tif", "copc", "isn't a LAS or LAZ"),
+            ("lidar/city.copc.laz", "copc", "already a COPC"),
+        ],
+    )
+    def test_refused_conversions(self, key, target_format, message):
+        with pytest.raises(ValueError, match=message):
+            conversion_target(key, target_format)
+
+    def test_nested_tilesets_left_out(self):
+        s3 = MagicMock()
+        s3.list_objects.return_value = listing(
+            "city.laz",
+            "city_3dtiles/tileset.json",
+            "city_3dtiles/r0/tileset.json",
+            "city_3dtiles/r0.pnts",
+            "town/tileset.json",
+        )
+
+        assets = find_3d_assets(s3, "lidar")
+
+        assert [(a.kind, a.key) for a in assets] == [
+            ("pointcloud", "city.laz"),
+            ("3dtiles", "city_3dtiles/tileset.json"),
+            ("3dtiles", "town/tileset.json"),
+        ]
+        assert assets[1].name == "city_3dtiles"
+
+    def test_pages_followed(self):
+        s3 = MagicMock()
+        s3.list_objects.side_effect = [
+            {"objects": [{"key": "a.laz"}], "isTruncated": True, "nextContinuationToken": "t"},
+            listing("b.laz"),
+        ]
+
+        assert [a.key for a in find_3d_assets(s3, "lidar", "survey/")] == ["a.laz", "b.laz"]
+        assert s3.list_objects.call_args.kwargs["continuation_token"] == "t"
+
+
+class TestConversion:
+    """Test converting point clouds with the tools stubbed."""
+
+    def test_copc_failure(self, tmp_path: Path):
+        failed = subprocess.CompletedProcess([], 1, "", "PDAL: readers.las: Invalid file\n")
+        with patch("apps.s3.three_d.subprocess.run", return_value=failed):
+            with pytest.raises(S3Error, match="Invalid file"):
+                convert_to_copc(tmp_path / "city.laz", tmp_path / "city.copc.laz")
+
+    def test_missing_tool(self, tmp_path: Path):
+        with patch("apps.s3.three_d.subprocess.run", side_effect=FileNotFoundError):
+            with pytest.raises(S3Error, match="PDAL not found"):
+                convert_to_copc(tmp_path / "city.laz", tmp_path / "city.copc.laz")
+
+    def test_copc_uploaded_beside_the_source(self, tmp_path: Path):
+        s3 = MagicMock()
+        s3.get_object_stream.return_value = MagicMock(read=MagicMock(side_effect=[b"LASF", b""]))
+
+        def pdal(command, **kwargs):
+            Path(command[-1]).write_bytes(b"COPC")
+            return subprocess.CompletedProcess(command, 0, "", "")
+
+        with (
+            patch("apps.s3.three_d.get_cache_dir", return_value=tmp_path),
+            patch("apps.s3.three_d.subprocess.run", side_effect=pdal),
+        ):
+            key = convert_point_cloud(s3, "lidar", "survey/city.laz", "copc")
+
+        assert key == "survey/city.copc.laz"
+        bucket, uploaded, _ = s3.put_object.call_args.args
+        assert (bucket, uploaded) == ("lidar", "survey/city.copc.laz")
+
+    def test_tileset_uploaded_file_by_file(self, tmp_path: Path):
+        s3 = MagicMock()
+        s3.get_object_stream.return_value = MagicMock(read=MagicMock(side_effect=[b"LASF", b""]))
+
+        def py3dtiles(command, **kwargs):
+            out = Path(command[command.index("--out") + 1])
+            (out / "r").mkdir(parents=True)
+            (out / "tileset.json").write_text(json.dumps(TILESET))
+            (out / "r" / "0.pnts").write_bytes(b"pnts")
+            return subprocess.CompletedProcess(command, 0, "", "")
+
+        with (
+            patch("apps.s3.three_d.get_cache_dir", return_value=tmp_path),
+            patch("apps.s3.three_d.subprocess.run", side_effect=py3dtiles),
+        ):
+            key = convert_point_cloud(s3, "lidar", "city.laz", "3dtiles")
+
+        assert key == "city_3dtiles/tileset.json"
+        uploads = {c.args[1]: c.kwargs["content_type"] for c in s3.put_object.call_args_list}
+        assert uploads["city_3dtiles/tileset.json"] == "application/json"
+        assert "city_3dtiles/r/0.pnts" in uploads
+
+
+class TestTerriaItems:
+    """Test catalog items of tilesets."""
+
+    def test_rectangle_from_region(self):
+        extent = tileset_rectangle(TILESET)
+        assert [extent[k] for k in ("west", "south", "east", "north")] == pytest.approx(
+            [10.0, 40.0, 12.0, 41.0]
+        )
+
+    def test_no_region(self):
+        assert tileset_rectangle({"root": {"boundingVolume": {"box": [0] * 12}}}) is None
+
+    def test_item(self):
+        asset = ThreeDAsset("3dtiles", "city_3dtiles/tileset.json")
+
+        item = tileset_item(asset, "https://s3.example.com/lidar/city_3dtiles/tileset.json")
+
+        assert item["type"] == "3d-tiles"
+        assert item["name"] == "city_3dtiles"
+        assert item["url"].endswith("/tileset.json")
+        assert "rectangle" not in item
+
+    def test_items_of_a_bucket(self):
+        s3 = MagicMock()
+        s3.list_objects.return_value = listing("city.laz", "city_3dtiles/tileset.json")
+        s3.get_object.return_value = json.dumps(TILESET).encode()
+
+        items = tileset_items(s3, "lidar", "", lambda key: f"/proxy/{key}")
+
+        assert [item["url"] for item in items] == ["/proxy/city_3dtiles/tileset.json"]
+        assert items[0]["rectangle"]["west"] == pytest.approx(10.0)
@@ -0,0 +1,66 @@
+"""Unit tests for the public GeoServer client SDK.
+
+Tests building clients from keyword options and that the SDK can be
+imported without Django.
+"""
+
+import subprocess
+import sys
+
+import pytest
+
+import kartoza_geoserver
+from kartoza_geoserver import GeoServerClient, GWCClient, connect, connect_gwc, connection
+
+
+class TestConnect:
+    """Tests for building clients."""
+
+    def test_options(self) -> None:
+        """Test keyword options set the connection's fields."""
+        client = connect(
+            "https://maps.example.com/geoserver/",
+            "admin",
+            "secret",
+            read_only=True,
+            response_timeout=60,
+        )
+
+        assert isinstance(client, GeoServerClient)
+        assert client.connection.url == "https://maps.example.com/geoserver"
+        assert client.connection.read_only is True
+        assert client.connection.response_timeout == 60
+
+    def test_unknown_option(self) -> None:
+        """Test a misspelt option is refused rather than ignored."""
+        with pytest.raises(TypeError, match="read_onl"):
+            connection("https://maps.example.com/geoserver", read_onl=True)
+
+    def test_separate_connections(self) -> None:
+        """Test each connection gets its own ID, so clients aren't shared."""
+        first = connection("https://a.example.com/geoserver")
+        second = connection("https://b.example.com/geoserver")
+
+        assert first.id != second.id
+
+    def test_gwc(self) -> None:
+        """Test a GeoWebCache client is built the same way."""
+        assert isinstance(connect_gwc("https://maps.example.com/geoserver"), GWCClient)
+
+
+class TestPublicAPI:
+    """Tests for what the SDK exports."""
+
+    def test_exports(self) -> None:
+        """Test every exported name exists."""
+        for name in kartoza_geoserver.__all__:
+            assert hasattr(kartoza_geoserver, name)
+
+    def test_without_django(self) -> None:
+        """Test importing the SDK doesn't load Django."""
+        code = "import sys, kartoza_geoserver; print('django' in sys.modules)"
+        result = subprocess.run(
+            [sys.executable, "-c", code], capture_output=True, text=True, check=True
+        )
+
+        assert result.stdout.strip() == "False"
@@ -0,0 +1,96 @@
+"""Unit tests for comparing server configurations."""
+
+from unittest.mock import MagicMock
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.comparison import (
+    ServerSnapshot,
+    compare_snapshots,
+    flatten_settings,
+    take_snapshot,
+)
+from apps.geoserver.manifests import InstalledModule
+
+
+def _client(conn_id: str, wfs: dict, modules: list[InstalledModule]) -> MagicMock:
+    client = MagicMock()
+    client.connection.id = conn_id
+    client.connection.name = conn_id.title()
+    client.get_about.return_value = {
+        "about": {"resource": [{"@name": "GeoServer", "Version": "2.24.1"}]}
+    }
+    client.get_installed_modules.return_value = modules
+    client.get_global_settings.return_value = {"settings": {"charset": "UTF-8"}}
+
+    def service_settings(service: str) -> dict:
+        if service == "wfs":
+            return wfs
+        raise GeoServerError("Resource not found", 404)
+
+    client.get_service_settings.side_effect = service_settings
+    return client
+
+
+class TestFlattenSettings:
+    """Tests for flattening settings documents."""
+
+    def test_nested_keys_and_lists(self) -> None:
+        """Test nesting becomes dotted keys and lists are joined."""
+        flat = flatten_settings(
+            {
+                "enabled": True,
+                "title": "My WMS",
+                "jai": {"memoryCapacity": 0.5},
+                "versions": {
+                    "org.geotools.util.Version": [{"version": "1.1.1"}, {"version": "1.3.0"}]
+                },
+            }
+        )
+
+        assert flat == {
+            "enabled": "true",
+            "jai.memoryCapacity": "0.5",
+            "versions.org.geotools.util.Version": "1.1.1, 1.3.0",
+        }
+
+
+class TestCompareServers:
+    """Tests for lining up servers."""
+
+    def test_snapshot_records_unreadable_sections(self) -> None:
+        """Test a failing section is recorded while the others are read."""
+        snapshot = take_snapshot(_client("prod", {"maxFeatures": 1000}, []))
+
+        assert snapshot.sections["Server"] == {"GeoServer": "2.24.1"}
+        assert snapshot.sections["WFS"] == {"maxFeatures": "1000"}
+        assert snapshot.sections["Global"] == {"settings.charset": "UTF-8"}
+        assert "WMS" in snapshot.errors
+
+    def test_differences_flagged(self) -> None:
+        """Test differing settings and extensions are flagged and equal ones aren't."""
+        importer = InstalledModule("gs-importer-core", "Importer", "extension", "2.24.1")
+        comparison = compare_snapshots(
+            [
+                take_snapshot(_client("prod", {"maxFeatures": 1000}, [importer])),
+                take_snapshot(_client("staging", {"maxFeatures": 50}, [])),
+            ]
+        )
+
+        differing = {(row.section, row.key) for row in comparison.differences}
+        assert differing == {("Extensions", "Importer"), ("WFS", "maxFeatures")}
+        importer_row = next(r for r in comparison.rows if r.key == "Importer")
+        assert importer_row.values == {"prod": "2.24.1", "staging": ""}
+        assert comparison.to_dict()["differenceCount"] == 2
+
+    def test_unreadable_values_not_differences(self) -> None:
+        """Test a server whose section couldn't be read doesn't count as differing."""
+        comparison = compare_snapshots(
+            [
+                ServerSnapshot("a", "A", sections={"Global": {"charset": "UTF-8"}}),
+                ServerSnapshot("b", "B", errors={"Global": "Forbidden"}),
+            ]
+        )
+
+        assert comparison.rows[0].values == {"a": "UTF-8", "b": None}
+        assert not comparison.differences
+        assert comparison.to_dict()["errors"] == {"b": {"Global": "Forbidden"}}
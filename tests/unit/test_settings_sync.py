@@ -0,0 +1,156 @@
+"""Unit tests for syncing contact details and service metadata.
+
+Tests listing the fields that differ from a source server's and writing
+only those to each target.
+"""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.settings_sync import (
+    apply_settings_sync,
+    diff_server,
+    plan_settings_sync,
+    read_section,
+)
+
+
+def _server(conn_id: str, contact: dict, wms: dict | None = None) -> MagicMock:
+    """A client whose contact and WMS settings are given; other services fail."""
+    client = MagicMock()
+    client.connection.id = conn_id
+    client.connection.name = conn_id.title()
+    client.get_contact.return_value = contact
+
+    def service_settings(service: str) -> dict:
+        if service == "wms" and wms is not None:
+            return wms
+        raise GeoServerError(f"No {service} service", 404)
+
+    client.get_service_settings.side_effect = service_settings
+    return client
+
+
+SOURCE = _server(
+    "main",
+    {"contactOrganization": "Kartoza", "contactEmail": "gis@example.com", "contactVoice": ""},
+    {"title": "Kartoza WMS", "keywords": {"string": ["maps"]}, "maxBuffer": 25},
+)
+
+
+class TestReadSection:
+    """Test reading the synced fields of a server."""
+
+    def test_only_set_fields(self) -> None:
+        """Test empty fields and settings that aren't synced are left out."""
+        assert read_section(SOURCE, "contact") == {
+            "contactOrganization": "Kartoza",
+            "contactEmail": "gis@example.com",
+        }
+        assert read_section(SOURCE, "wms") == {
+            "title": "Kartoza WMS",
+            "keywords": {"string": ["maps"]},
+        }
+
+
+class TestDiffServer:
+    """Test comparing a target's settings with the source's."""
+
+    def test_changes_and_errors(self) -> None:
+        """Test differing fields are listed, and unreadable sections recorded."""
+        target = _server(
+            "prod",
+            {"contactOrganization": "Kartoza", "contactEmail": "old@example.com"},
+        )
+        source = {
+            "contact": read_section(SOURCE, "contact"),
+            "wms": read_section(SOURCE, "wms"),
+        }
+
+        diff = diff_server(source, target)
+
+        assert [c.to_dict() for c in diff.changes] == [
+            {
+                "section": "contact",
+                "key": "contactEmail",
+                "current": "old@example.com",
+                "value": "gis@example.com",
+            }
+        ]
+        assert diff.errors == {"wms": "No wms service"}
+
+    def test_keywords_compared_as_values(self) -> None:
+        """Test equal keywords aren't a change, and new ones render as text."""
+        target = _server("prod", {}, {"title": "Kartoza WMS", "keywords": {"string": ["old"]}})
+
+        diff = diff_server({"wms": read_section(SOURCE, "wms")}, target)
+
+        assert [(c.key, c.to_dict()["current"], c.to_dict()["value"]) for c in diff.changes] == [
+            ("keywords", "old", "maps")
+        ]
+
+
+class TestPlanAndApply:
+    """Test planning a sync to several servers and writing it."""
+
+    def test_plan_and_apply(self) -> None:
+        """Test only differing fields are written, per section, and failures kept."""
+        prod = _server("prod", {"contactOrganization": "Other"}, {"title": "Prod"})
+        dev = _server("dev", {"contactOrganization": "Kartoza"}, {"title": "Kartoza WMS"})
+        dev.get_service_settings.side_effect = None
+        dev.get_service_settings.return_value = {
+            "title": "Kartoza WMS",
+            "keywords": {"string": ["maps"]},
+        }
+        prod.update_service_settings.side_effect = GeoServerError("Forbidden", 403)
+        clients = {"main": SOURCE, "prod": prod, "dev": dev}
+
+        with patch(
+            "apps.geoserver.settings_sync.get_geoserver_client", side_effect=clients.__getitem__
+        ):
+            plan = plan_settings_sync("main", ["prod", "dev", "main"], ["contact", "wms"])
+            apply_settings_sync(plan)
+
+        assert [s.connection_id for s in plan.servers] == ["prod", "dev"]
+        assert plan.change_count == 5
+        prod.update_contact.assert_called_once_with(
+            {"contactOrganization": "Kartoza", "contactEmail": "gis@example.com"}
+        )
+        prod.update_service_settings.assert_called_once_with(
+            "wms", {"title": "Kartoza WMS", "keywords": {"string": ["maps"]}}
+        )
+        assert plan.servers[0].errors == {"wms": "Forbidden"}
+        dev.update_contact.assert_called_once_with({"contactEmail": "gis@example.com"})
+        dev.update_service_settings.assert_not_called()
+
+    def test_unknown_section(self) -> None:
+        """Test sections other than contact and the services are refused."""
+        with pytest.raises(ValueError, match="Unknown section"):
+            plan_settings_sync("main", ["prod"], ["logging"])
+
+
+class TestClientSettings:
+    """Test the client's contact and service settings updates."""
+
+    def test_update_contact(self, make_geoserver_client) -> None:
+        """Test the contact fields are sent under "contact"."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        client.update_contact({"contactEmail": "gis@example.com"})
+
+        client._request.assert_called_once_with(
+            "PUT",
+            "/rest/settings/contact.json",
+            json={"contact": {"contactEmail": "gis@example.com"}},
+        )
+
+    def test_update_service_settings_failure(self, make_geoserver_client) -> None:
+        """Test a refused update raises with the service's name."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=403, text="Forbidden")
+
+        with pytest.raises(GeoServerError, match="Failed to update WFS settings"):
+            client.update_service_settings("wfs", {"title": "WFS"})
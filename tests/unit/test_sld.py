@@ -0,0 +1,151 @@
+"""Unit tests for SLD 1.0 and 1.1 style handling."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.geoserver.sld import SLD_10, SLD_11, convert_sld, detect_sld_version
+
+SLD_10_STYLE = """<?xml version="1.0" encoding="UTF-8"?>
+<StyledLayerDescriptor version="1.0.0" xmlns="http://www.opengis.net/sld"
+  xmlns:ogc="http://www.opengis.net/ogc">
+  <NamedLayer>
+    <Name>roads</Name>
+    <UserStyle>
+      <Title>Roads</Title>
+      <Abstract>Main roads</Abstract>
+      <FeatureTypeStyle>
+        <Rule>
+          <!-- Highways only -->
+          <ogc:Filter>
+            <ogc:PropertyIsEqualTo>
+              <ogc:PropertyName>type</ogc:PropertyName>
+              <ogc:Literal>highway</ogc:Literal>
+            </ogc:PropertyIsEqualTo>
+          </ogc:Filter>
+          <LineSymbolizer>
+            <Stroke>
+              <CssParameter name="stroke">#ff0000</CssParameter>
+            </Stroke>
+          </LineSymbolizer>
+        </Rule>
+      </FeatureTypeStyle>
+    </UserStyle>
+  </NamedLayer>
+</StyledLayerDescriptor>
+"""
+
+SLD_11_STYLE = """<StyledLayerDescriptor xmlns="http://www.opengis.net/sld"
+  xmlns:se="http://www.opengis.net/se">
+  <NamedLayer>
+    <se:Name>roads</se:Name>
+  </NamedLayer>
+</StyledLayerDescriptor>
+"""
+
+
+class TestDetectVersion:
+    """Tests for reading the SLD version of a document."""
+
+    def test_version_attribute(self) -> None:
+        """Test the version attribute of the root element decides."""
+        assert detect_sld_version(SLD_10_STYLE) == SLD_10
+        assert detect_sld_version(SLD_10_STYLE.replace('"1.0.0"', '"1.1.0"')) == SLD_11
+
+    def test_se_namespace(self) -> None:
+        """Test a document without a version using SE elements is 1.1."""
+        assert detect_sld_version(SLD_11_STYLE) == SLD_11
+
+    def test_not_xml(self) -> None:
+        """Test content that isn't XML is taken as GeoServer's default, 1.0."""
+        assert detect_sld_version("* { fill: red; }") == SLD_10
+
+
+class TestConvert:
+    """Tests for converting styles between SLD versions."""
+
+    def test_to_11(self) -> None:
+        """Test symbolizers move to SE and titles into a Description."""
+        converted = convert_sld(SLD_10_STYLE, SLD_11)
+
+        assert detect_sld_version(converted) == SLD_11
+        assert "<se:Name>roads</se:Name>" in converted
+        assert "<UserStyle>" in converted
+        assert "<se:Description>" in converted
+        assert '<se:SvgParameter name="stroke">#ff0000</se:SvgParameter>' in converted
+        assert "<ogc:PropertyName>type</ogc:PropertyName>" in converted
+        assert "<!-- Highways only -->" in converted
+
+    def test_round_trip(self) -> None:
+        """Test converting to 1.1 and back gives the same structure."""
+        back = convert_sld(convert_sld(SLD_10_STYLE, SLD_11), SLD_10)
+
+        assert detect_sld_version(back) == SLD_10
+        assert "se:" not in back
+        assert "<Title>Roads</Title>" in back
+        assert "<Abstract>Main roads</Abstract>" in back
+        assert "Description" not in back
+        assert '<CssParameter name="stroke">#ff0000</CssParameter>' in back
+
+    def test_same_version_unchanged(self) -> None:
+        """Test content already in the version is returned as it is."""
+        assert convert_sld(SLD_10_STYLE, SLD_10) is SLD_10_STYLE
+
+    def test_invalid(self) -> None:
+        """Test unknown versions and documents that aren't SLDs are refused."""
+        with pytest.raises(ValueError, match="Unknown SLD version"):
+            convert_sld(SLD_10_STYLE, "2.0.0")
+        with pytest.raises(ValueError, match="expected StyledLayerDescriptor"):
+            convert_sld("<style/>", SLD_11)
+
+
+class TestClientVersions:
+    """Tests for reading and writing styles in their own SLD version."""
+
+    def test_update_sends_se_content_type(self, make_geoserver_client) -> None:
+        """Test SLD 1.1 content is sent as Symbology Encoding."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        client.update_style_content("roads", SLD_11_STYLE, "sld", "topp")
+
+        args, kwargs = client._request.call_args
+        assert args == ("PUT", "/rest/workspaces/topp/styles/roads")
+        assert kwargs["headers"] == {"Content-Type": "application/vnd.ogc.se+xml"}
+
+    def test_update_sld_10(self, make_geoserver_client) -> None:
+        """Test SLD 1.0 content keeps the .sld resource and content type."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        client.update_style_content("roads", SLD_10_STYLE, "sld")
+
+        args, kwargs = client._request.call_args
+        assert args == ("PUT", "/rest/styles/roads.sld")
+        assert kwargs["headers"] == {"Content-Type": "application/vnd.ogc.sld+xml"}
+
+    def test_create_records_version(self, make_geoserver_client) -> None:
+        """Test a new style is created with the version of its content."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=201)
+
+        client.create_style("roads", SLD_11_STYLE)
+
+        payload = client._request.call_args_list[0].kwargs["json"]
+        assert payload["style"]["languageVersion"] == {"version": SLD_11}
+
+    def test_read_11_as_stored(self, make_geoserver_client) -> None:
+        """Test a 1.1 style is read as SE rather than converted to 1.0."""
+        client = make_geoserver_client()
+        style = MagicMock(status_code=200)
+        style.json.return_value = {
+            "style": {"format": "sld", "languageVersion": {"version": SLD_11}}
+        }
+        client._request.side_effect = [style, MagicMock(status_code=200, text=SLD_11_STYLE)]
+
+        content, style_format = client.get_style_content("roads")
+
+        assert (content, style_format) == (SLD_11_STYLE, "sld")
+        args, kwargs = client._request.call_args
+        assert args == ("GET", "/rest/styles/roads")
+        assert kwargs["headers"] == {"Accept": "application/vnd.ogc.se+xml"}
@@ -0,0 +1,292 @@
+"""Unit tests for dated snapshots of catalog resources.
+
+Tests capturing layers, styles and stores into files laid out like the REST
+API, the manifest describing them, downloading layer data, and restoring
+a snapshot's missing resources.
+"""
+
+import io
+import json
+import zipfile
+from datetime import datetime, timezone
+from pathlib import Path
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.checksum import sha256_bytes
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import CreateResult
+from apps.geoserver.snapshot import MANIFEST_FILE, build_snapshot, restore_snapshot
+
+CREATED = datetime(2024, 5, 23, 9, 39, 21, tzinfo=timezone.utc)
+FOLDER = "snapshot-local-20240523-093921"
+ROADS_HREF = "http://gs/rest/workspaces/topp/datastores/db/featuretypes/roads.json"
+
+
+def _client() -> MagicMock:
+    """A client with a vector layer "topp:roads" and a style "topp:line"."""
+    client = MagicMock()
+    client.connection.id = "local"
+    client.connection.name = "Local"
+    client.connection.url = "http://gs"
+    client.get_layer.return_value = {
+        "name": "roads",
+        "type": "VECTOR",
+        "resource": {"href": ROADS_HREF},
+    }
+    client.get_href.return_value = {"featureType": {"name": "roads"}}
+    client.get_layer_shapefile.return_value = b"PK shapefile"
+    client.get_style.return_value = {"name": "line", "format": "css"}
+    client.get_style_content.return_value = ("* { stroke: black; }", "css")
+    return client
+
+
+class TestBuildSnapshot:
+    """Tests for capturing resources."""
+
+    def test_layer(self) -> None:
+        """Test a layer is captured with its feature type under its store."""
+        snapshot = build_snapshot(
+            _client(), [{"type": "layer", "workspace": "topp", "name": "roads"}], created=CREATED
+        )
+
+        assert snapshot.folder == FOLDER
+        assert snapshot.resources[0].files == [
+            "workspaces/topp/layers/roads.json",
+            "workspaces/topp/datastores/db/featuretypes/roads.json",
+        ]
+        featuretype = snapshot.files["workspaces/topp/datastores/db/featuretypes/roads.json"]
+        assert json.loads(featuretype) == {"featureType": {"name": "roads"}}
+
+    def test_layer_data(self) -> None:
+        """Test vector layers bring a shapefile when data is included."""
+        client = _client()
+
+        snapshot = build_snapshot(
+            client,
+            [{"type": "layer", "workspace": "topp", "name": "roads"}],
+            include_data=True,
+        )
+
+        assert snapshot.files["workspaces/topp/layers/roads.zip"] == b"PK shapefile"
+        client.get_layer_shapefile.assert_called_once_with("topp", "roads")
+        client.get_coverage_geotiff.assert_not_called()
+
+    def test_styles(self) -> None:
+        """Test styles are saved with their content, global ones outside workspaces."""
+        snapshot = build_snapshot(
+            _client(),
+            [
+                {"type": "style", "workspace": "topp", "name": "line"},
+                {"type": "style", "name": "line"},
+            ],
+        )
+
+        assert snapshot.resources[0].files == [
+            "workspaces/topp/styles/line.json",
+            "workspaces/topp/styles/line.css",
+        ]
+        assert snapshot.resources[1].files == ["styles/line.json", "styles/line.css"]
+        assert snapshot.files["styles/line.css"] == b"* { stroke: black; }"
+
+    def test_duplicates_captured_once(self) -> None:
+        """Test a resource asked for twice is captured once."""
+        client = _client()
+        target = {"type": "layer", "workspace": "topp", "name": "roads"}
+
+        snapshot = build_snapshot(client, [target, dict(target)])
+
+        assert len(snapshot.resources) == 1
+        client.get_layer.assert_called_once()
+
+    def test_failure_recorded(self) -> None:
+        """Test a resource that can't be read is recorded and the rest captured."""
+        client = _client()
+        client.get_layer.side_effect = GeoServerError("No such layer", status_code=404)
+
+        snapshot = build_snapshot(
+            client,
+            [
+                {"type": "layer", "workspace": "topp", "name": "gone"},
+                {"type": "style", "workspace": "topp", "name": "line"},
+            ],
+        )
+
+        assert [r.name for r in snapshot.failed] == ["gone"]
+        assert snapshot.manifest()["resources"][0]["error"] == "No such layer"
+        assert "workspaces/topp/styles/line.json" in snapshot.files
+
+    @pytest.mark.parametrize(
+        "targets,match",
+        [
+            ([], "No resources"),
+            ([{"type": "connection", "name": "local"}], "Can't snapshot a connection"),
+            ([{"type": "layer", "name": "roads"}], "needs a workspace"),
+        ],
+    )
+    def test_invalid_targets(self, targets: list[dict], match: str) -> None:
+        """Test requests for nothing, or for what can't be captured, are refused."""
+        with pytest.raises(ValueError, match=match):
+            build_snapshot(_client(), targets)
+
+
+class TestSnapshotOutput:
+    """Tests for the snapshot folder and its manifest."""
+
+    def test_zip(self) -> None:
+        """Test the zip holds the folder with the manifest and its checksums."""
+        snapshot = build_snapshot(
+            _client(), [{"type": "style", "name": "line"}], created=CREATED
+        )
+
+        with zipfile.ZipFile(io.BytesIO(snapshot.to_zip())) as archive:
+            names = set(archive.namelist())
+            manifest = json.loads(archive.read(f"{FOLDER}/{MANIFEST_FILE}"))
+
+        assert names == {
+            f"{FOLDER}/{MANIFEST_FILE}",
+            f"{FOLDER}/styles/line.json",
+            f"{FOLDER}/styles/line.css",
+        }
+        assert manifest["created"] == "2024-05-23T09:39:21+00:00"
+        assert manifest["connection"] == {"id": "local", "name": "Local", "url": "http://gs"}
+        assert manifest["files"]["styles/line.css"] == {
+            "size": 20,
+            "sha256": sha256_bytes(b"* { stroke: black; }"),
+        }
+
+    def test_write_to(self, tmp_path: Path) -> None:
+        """Test the folder is written into a directory."""
+        snapshot = build_snapshot(
+            _client(), [{"type": "style", "workspace": "topp", "name": "line"}], created=CREATED
+        )
+
+        root = snapshot.write_to(tmp_path)
+
+        assert root == tmp_path / FOLDER
+        assert (root / "workspaces/topp/styles/line.css").read_text() == "* { stroke: black; }"
+        assert json.loads((root / MANIFEST_FILE).read_text())["includeData"] is False
+
+
+def _snapshot_zip(files: dict[str, object]) -> bytes:
+    """A snapshot zip of JSON (dicts) and text files."""
+    buffer = io.BytesIO()
+    with zipfile.ZipFile(buffer, "w") as archive:
+        archive.writestr(f"{FOLDER}/{MANIFEST_FILE}", "{}")
+        for path, content in files.items():
+            data = json.dumps(content) if isinstance(content, dict) else content
+            archive.writestr(f"{FOLDER}/{path}", data)
+    return buffer.getvalue()
+
+
+class TestRestoreSnapshot:
+    """Tests for recreating a snapshot's missing resources."""
+
+    ARCHIVE = {
+        "workspaces/topp/workspace.json": {"workspace": {"name": "topp"}},
+        "workspaces/topp/layers/roads.json": {"layer": {"name": "roads"}},
+        "workspaces/topp/datastores/db/featuretypes/roads.json": {
+            "featureType": {"name": "roads", "dateCreated": "2024-05-23"}
+        },
+        "workspaces/topp/datastores/db/datastore.json": {
+            "dataStore": {"name": "db", "featureTypes": "http://gs/..."}
+        },
+        "styles/line.json": {"style": {"name": "line", "format": "css"}},
+        "styles/line.css": "* { stroke: black; }",
+        "workspaces/topp/layers/roads.zip": "PK shapefile",
+    }
+
+    def _client(self, existing: set[str] = frozenset()) -> MagicMock:
+        client = MagicMock()
+        client._exists.side_effect = lambda path: path in existing
+        client._request.return_value = MagicMock(status_code=201)
+        client.create_style.return_value = CreateResult.CREATED
+        return client
+
+    def test_dependency_order(self) -> None:
+        """Test resources are sent after those they refer to, read-only fields left out."""
+        client = self._client()
+
+        report = restore_snapshot(client, _snapshot_zip(self.ARCHIVE))
+
+        sent = [(c.args[0], c.args[1]) for c in client._request.call_args_list]
+        assert sent == [
+            ("POST", "/rest/workspaces.json"),
+            ("POST", "/rest/workspaces/topp/datastores.json"),
+            ("POST", "/rest/workspaces/topp/datastores/db/featuretypes.json"),
+            ("PUT", "/rest/workspaces/topp/layers/roads.json"),
+        ]
+        store = client._request.call_args_list[1].kwargs["json"]
+        assert store == {"dataStore": {"name": "db"}}
+        client.create_style.assert_called_once_with(
+            "line", "* { stroke: black; }", "css", None, if_absent=True
+        )
+        assert report.failed == {}
+        assert len(report.created) == 5
+
+    def test_existing_left_alone(self) -> None:
+        """Test existing resources and their layers aren't changed."""
+        client = self._client(
+            {
+                "/rest/workspaces/topp.json",
+                "/rest/workspaces/topp/datastores/db.json",
+                "/rest/workspaces/topp/datastores/db/featuretypes/roads.json",
+                "/rest/workspaces/topp/layers/roads.json",
+            }
+        )
+        client.create_style.return_value = CreateResult.ALREADY_EXISTS
+
+        report = restore_snapshot(client, _snapshot_zip(self.ARCHIVE))
+
+        client._request.assert_not_called()
+        assert report.created == []
+        assert len(report.existing) == 5
+
+    def test_failure_recorded(self) -> None:
+        """Test a resource that fails doesn't stop the rest."""
+        client = self._client()
+        client._request.side_effect = [
+            MagicMock(status_code=201),
+            MagicMock(status_code=500, text="Store connection refused"),
+            MagicMock(status_code=201),
+            MagicMock(status_code=201),
+        ]
+
+        report = restore_snapshot(client, _snapshot_zip(self.ARCHIVE))
+
+        assert list(report.failed) == ["workspaces/topp/datastores/db/datastore.json"]
+        assert "Store connection refused" in report.failed[
+            "workspaces/topp/datastores/db/datastore.json"
+        ]
+        assert "workspaces/topp/layers/roads.json" in report.created
+
+    def test_not_a_snapshot(self) -> None:
+        """Test an archive without a manifest is refused."""
+        buffer = io.BytesIO()
+        with zipfile.ZipFile(buffer, "w") as archive:
+            archive.writestr("other/file.json", "{}")
+
+        with pytest.raises(ValueError, match="no snapshot manifest"):
+            restore_snapshot(MagicMock(), buffer.getvalue())
+
+
+class TestOwsDownload:
+    """Tests for downloading layer data through OWS services."""
+
+    def test_exception_report(self, make_geoserver_client) -> None:
+        """Test an exception report sent with status 200 is raised as an error."""
+        client = make_geoserver_client()
+        client._ows_request = MagicMock(
+            return_value=MagicMock(
+                status_code=200,
+                headers={"content-type": "application/xml"},
+                content=b"<ows:ExceptionReport/>",
+                text="<ows:ExceptionReport/>",
+            )
+        )
+
+        with pytest.raises(GeoServerError, match="Shapefile download failed") as exc:
+            client.get_layer_shapefile("topp", "roads")
+
+        assert exc.value.status_code == 400
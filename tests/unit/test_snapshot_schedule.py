@@ -0,0 +1,202 @@
+"""Unit tests for scheduled catalog snapshots.
+
+Tests the retention policy, storing snapshots in a folder or a bucket and
+running a schedule, with the GeoServer catalog mocked.
+"""
+
+from datetime import datetime, timedelta
+from pathlib import Path
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.config import SnapshotSchedule
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.snapshot_schedule import (
+    LocalArchives,
+    S3Archives,
+    SnapshotArchive,
+    SnapshotScheduler,
+    catalog_targets,
+    parse_archive_name,
+    retained,
+    validate_snapshot_schedule,
+)
+
+
+def _archive(created: datetime, connection: str = "conn-1") -> SnapshotArchive:
+    name = f"snapshot-{connection}-{created.strftime('%Y%m%d-%H%M%S')}.zip"
+    return SnapshotArchive(name, connection, created)
+
+
+def _schedule(tmp_path: Path, **kwargs) -> SnapshotSchedule:
+    defaults = {"name": "Nightly", "connection_ids": ["conn-1"], "directory": str(tmp_path)}
+    defaults.update(kwargs)
+    return SnapshotSchedule(**defaults)
+
+
+class TestArchiveNames:
+    """Tests for reading stored snapshot names."""
+
+    def test_connection_with_dashes(self) -> None:
+        """Test the connection ID keeps its dashes."""
+        archive = parse_archive_name("snapshot-prod-eu-1-20240523-020000.zip", 10)
+        assert archive.connection_id == "prod-eu-1"
+        assert archive.created == datetime(2024, 5, 23, 2, 0)
+        assert archive.size == 10
+
+    @pytest.mark.parametrize(
+        "name",
+        ["snapshot-conn-20240523.zip", "snapshot-conn-20241399-020000.zip", "notes.txt"],
+    )
+    def test_other_files(self, name: str) -> None:
+        """Test files that aren't snapshots are ignored."""
+        assert parse_archive_name(name) is None
+
+
+class TestRetention:
+    """Tests for the dailies and weeklies a policy keeps."""
+
+    def test_newest_of_each_day(self) -> None:
+        """Test only the newest snapshot of each recent day is kept."""
+        start = datetime(2024, 5, 20, 2, 0)  # A Monday
+        archives = [_archive(start + timedelta(days=d, hours=h)) for d in range(5) for h in (0, 6)]
+
+        kept = retained(archives, keep_daily=3, keep_weekly=0)
+
+        assert [a.created for a in kept] == [
+            datetime(2024, 5, 24, 8, 0),
+            datetime(2024, 5, 23, 8, 0),
+            datetime(2024, 5, 22, 8, 0),
+        ]
+
+    def test_weeklies_reach_further_back(self) -> None:
+        """Test the newest snapshot of each earlier week is kept as well."""
+        start = datetime(2024, 4, 1, 2, 0)  # A Monday
+        archives = [_archive(start + timedelta(days=d)) for d in range(28)]
+
+        kept = retained(archives, keep_daily=2, keep_weekly=3)
+
+        assert [a.created.date().isoformat() for a in kept] == [
+            "2024-04-28",
+            "2024-04-27",
+            "2024-04-21",
+            "2024-04-14",
+        ]
+
+
+class TestArchiveStores:
+    """Tests for storing snapshots in a folder and a bucket."""
+
+    def test_local(self, tmp_path: Path) -> None:
+        """Test a zip is written with its checksum, listed and deleted."""
+        store = LocalArchives(tmp_path / "snapshots")
+        name = "snapshot-conn-1-20240523-020000.zip"
+
+        store.write(name, b"PK")
+
+        assert [a.name for a in store.list()] == [name]
+        assert (tmp_path / "snapshots" / f"{name}.sha256").read_text().endswith(f"  {name}\n")
+        store.delete(name)
+        assert list((tmp_path / "snapshots").iterdir()) == []
+
+    def test_s3_prefix(self) -> None:
+        """Test snapshots are listed and deleted under the key prefix."""
+        s3 = MagicMock()
+        s3.list_objects.return_value = {
+            "objects": [
+                {"key": "gs/snapshot-conn-1-20240523-020000.zip", "size": 2},
+                {"key": "gs/snapshot-conn-1-20240523-020000.zip.sha256", "size": 1},
+            ],
+            "isTruncated": False,
+        }
+        store = S3Archives(s3, "backups", "/gs/")
+
+        archives = store.list()
+        store.delete(archives[0].name)
+
+        assert [(a.connection_id, a.size) for a in archives] == [("conn-1", 2)]
+        assert [c.args[1] for c in s3.delete_object.call_args_list] == [
+            "gs/snapshot-conn-1-20240523-020000.zip",
+            "gs/snapshot-conn-1-20240523-020000.zip.sha256",
+        ]
+
+
+class TestSnapshotSchedules:
+    """Tests for schedule validation and running."""
+
+    def test_validate(self, tmp_path: Path) -> None:
+        """Test schedule validation."""
+        config = MagicMock()
+        with patch("apps.geoserver.snapshot_schedule.get_config", return_value=config):
+            validate_snapshot_schedule(_schedule(tmp_path))
+            with pytest.raises(ValueError, match="folder"):
+                validate_snapshot_schedule(_schedule(tmp_path, directory=""))
+            with pytest.raises(ValueError, match="bucket"):
+                validate_snapshot_schedule(_schedule(tmp_path, destination="s3"))
+            with pytest.raises(ValueError, match="daily"):
+                validate_snapshot_schedule(_schedule(tmp_path, keep_daily=0))
+            config.get_connection.return_value = None
+            with pytest.raises(ValueError, match="Connection not found"):
+                validate_snapshot_schedule(_schedule(tmp_path))
+
+    def test_catalog_targets(self) -> None:
+        """Test the whole catalog is every workspace and global style."""
+        client = MagicMock()
+        client.list_workspaces.return_value = [{"name": "topp"}]
+        client.list_styles.return_value = [{"name": "line"}]
+
+        assert catalog_targets(client) == [
+            {"type": "workspace", "name": "topp"},
+            {"type": "style", "name": "line"},
+        ]
+
+    def test_run_stores_and_prunes(self, tmp_path: Path) -> None:
+        """Test a run stores a snapshot and prunes those past the retention policy."""
+        schedule = _schedule(tmp_path, keep_daily=1, keep_weekly=0)
+        old = "snapshot-conn-1-20240101-020000.zip"
+        (tmp_path / old).write_bytes(b"PK old")
+        config = MagicMock()
+        config.get_snapshot_schedule.return_value = schedule
+        snapshot = MagicMock(folder="snapshot-conn-1-20240523-020000", failed=[])
+        snapshot.to_zip.return_value = b"PK new"
+
+        with (
+            patch("apps.geoserver.snapshot_schedule.get_config", return_value=config),
+            patch("apps.geoserver.snapshot_schedule.get_geoserver_client"),
+            patch("apps.geoserver.snapshot_schedule.catalog_targets", return_value=[]),
+            patch("apps.geoserver.snapshot_schedule.build_snapshot", return_value=snapshot),
+        ):
+            result = SnapshotScheduler().run_schedule(schedule.id)
+
+        assert result.last_status == "success"
+        assert sorted(p.name for p in tmp_path.iterdir()) == [
+            "snapshot-conn-1-20240523-020000.zip",
+            "snapshot-conn-1-20240523-020000.zip.sha256",
+        ]
+        config.update_snapshot_schedule.assert_called_once_with(schedule)
+
+    def test_run_records_each_failure(self, tmp_path: Path) -> None:
+        """Test a connection that fails doesn't stop the others."""
+        schedule = _schedule(tmp_path, connection_ids=["down", "conn-1"])
+        config = MagicMock()
+        config.get_snapshot_schedule.return_value = schedule
+        snapshot = MagicMock(folder="snapshot-conn-1-20240523-020000", failed=[])
+        snapshot.to_zip.return_value = b"PK"
+
+        def catalog(client):
+            if client == "down":
+                raise GeoServerError("Connection refused")
+            return []
+
+        with (
+            patch("apps.geoserver.snapshot_schedule.get_config", return_value=config),
+            patch("apps.geoserver.snapshot_schedule.get_geoserver_client", side_effect=str),
+            patch("apps.geoserver.snapshot_schedule.catalog_targets", side_effect=catalog),
+            patch("apps.geoserver.snapshot_schedule.build_snapshot", return_value=snapshot),
+        ):
+            result = SnapshotScheduler().run_schedule(schedule.id)
+
+        assert result.last_status == "failed"
+        assert result.last_error == "down: Connection refused"
+        assert (tmp_path / "snapshot-conn-1-20240523-020000.zip").exists()
@@ -0,0 +1,105 @@
+"""Unit tests for STAC catalog generation.
+
+Tests the pure helpers and catalog assembly without touching S3 or GDAL.
+"""
+
+from unittest.mock import MagicMock, patch
+
+from apps.s3.stac import (
+    STACCatalogGenerator,
+    bbox_to_polygon,
+    detect_asset_kind,
+    item_id_for_key,
+    merge_bboxes,
+    parse_tiff_datetime,
+    polygon_bbox,
+)
+from apps.s3.views import _split_stac_path
+
+
+class TestSTACHelpers:
+    """Tests for STAC helper functions."""
+
+    def test_detect_asset_kind(self) -> None:
+        """Test asset kind detection from object keys."""
+        assert detect_asset_kind("imagery/scene.tif") == "cog"
+        assert detect_asset_kind("imagery/SCENE.TIFF") == "cog"
+        assert detect_asset_kind("vectors/roads.parquet") == "geoparquet"
+        assert detect_asset_kind("vectors/roads.geoparquet") == "geoparquet"
+        assert detect_asset_kind("docs/readme.txt") is None
+
+    def test_item_id_strips_prefix_and_extension(self) -> None:
+        """Test item IDs are relative to the prefix and path-safe."""
+        assert item_id_for_key("imagery/2024/scene.tif", "imagery/") == "2024__scene"
+        assert item_id_for_key("scene.tif") == "scene"
+
+    def test_bbox_polygon_round_trip(self) -> None:
+        """Test converting a bbox to a polygon and back."""
+        bbox = [10.0, -5.0, 20.0, 5.0]
+        assert polygon_bbox(bbox_to_polygon(bbox)) == bbox
+
+    def test_merge_bboxes(self) -> None:
+        """Test merging bboxes into their union."""
+        merged = merge_bboxes([[0, 0, 1, 1], [-1, 0.5, 0.5, 2]])
+        assert merged == [-1, 0, 1, 2]
+        assert merge_bboxes([]) == [-180.0, -90.0, 180.0, 90.0]
+
+    def test_parse_tiff_datetime(self) -> None:
+        """Test parsing TIFFTAG_DATETIME values."""
+        assert parse_tiff_datetime("2024:03:01 12:30:00") == "2024-03-01T12:30:00Z"
+        assert parse_tiff_datetime("not a date") is None
+
+
+class TestSTACCatalogGenerator:
+    """Tests for STACCatalogGenerator."""
+
+    def _client(self) -> MagicMock:
+        client = MagicMock()
+        client.list_objects.return_value = {
+            "objects": [
+                {"key": "imagery/scene.tif", "size": 100, "lastModified": "2024-01-01T00:00:00"},
+                {"key": "imagery/notes.txt", "size": 5, "lastModified": "2024-01-01T00:00:00"},
+            ],
+            "isTruncated": False,
+        }
+        client.generate_presigned_url.return_value = "https://example.com/scene.tif"
+        return client
+
+    def test_generate_builds_catalog_collection_and_items(self) -> None:
+        """Test the generated document set for a prefix."""
+        generator = STACCatalogGenerator(self._client(), "data", prefix="imagery")
+        metadata = {"bbox": [0.0, 0.0, 1.0, 1.0], "datetime": "2024-02-01T00:00:00Z"}
+
+        with patch("apps.s3.stac.extract_cog_metadata", return_value=metadata):
+            documents = generator.generate()
+
+        assert set(documents) == {"catalog.json", "collection.json", "items/scene.json"}
+        item = documents["items/scene.json"]
+        assert item["bbox"] == [0.0, 0.0, 1.0, 1.0]
+        assert item["properties"]["datetime"] == "2024-02-01T00:00:00Z"
+        assert item["assets"]["data"]["href"] == "s3://data/imagery/scene.tif"
+
+        collection = documents["collection.json"]
+        assert collection["extent"]["spatial"]["bbox"] == [[0.0, 0.0, 1.0, 1.0]]
+
+    def test_write_puts_documents_under_prefix(self) -> None:
+        """Test documents are written back under the catalog prefix."""
+        client = self._client()
+        generator = STACCatalogGenerator(client, "data", prefix="imagery/")
+
+        written = generator.write({"catalog.json": {"type": "Catalog"}})
+
+        assert written == ["imagery/catalog.json"]
+        client.put_object.assert_called_once()
+
+
+class TestSTACPath:
+    """Test splitting a dynamic STAC path into prefix and document."""
+
+    def test_documents_and_prefixes(self) -> None:
+        """Test documents, items and bare prefixes."""
+        assert _split_stac_path("imagery/collection.json") == ("imagery", "collection.json")
+        assert _split_stac_path("imagery/items/scene.json") == ("imagery", "items/scene.json")
+        assert _split_stac_path("imagery") == ("imagery", "catalog.json")
+        assert _split_stac_path("imagery/2024/") == ("imagery/2024", "catalog.json")
+        assert _split_stac_path("") == ("", "catalog.json")
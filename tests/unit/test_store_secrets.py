@@ -0,0 +1,153 @@
+"""Unit tests for passwords among data store connection parameters.
+
+Tests masking them in store details, keeping them when an edit sends the
+mask back and reading them from the environment or keyring on create.
+"""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.capabilities import build_capabilities
+from apps.geoserver.store_secrets import (
+    MASK,
+    keep_secrets,
+    mask_datastore,
+    resolve_secret,
+    resolve_secrets,
+)
+
+
+class FakeKeyring:
+    """In-memory stand-in for the keyring package."""
+
+    class errors:
+        class KeyringError(Exception):
+            pass
+
+    def __init__(self, passwords: dict[tuple[str, str], str]) -> None:
+        self.passwords = passwords
+
+    def get_password(self, service: str, name: str) -> str | None:
+        return self.passwords.get((service, name))
+
+
+def _store(**params: str) -> dict:
+    return {
+        "name": "roads",
+        "connectionParameters": {"entry": [{"@key": k, "$": v} for k, v in params.items()]},
+    }
+
+
+class TestMaskDatastore:
+    """Test hiding secrets in store details."""
+
+    def test_secrets_masked(self) -> None:
+        """Test passwords are masked and the other parameters kept."""
+        store = _store(host="db", passwd="s3cret")
+
+        masked = mask_datastore(store)
+
+        assert masked["connectionParameters"]["entry"] == [
+            {"@key": "host", "$": "db"},
+            {"@key": "passwd", "$": MASK},
+        ]
+        assert store["connectionParameters"]["entry"][1]["$"] == "s3cret"
+
+    def test_single_entry_and_empty_password(self) -> None:
+        """Test one entry not in a list, and an empty password left empty."""
+        store = {"connectionParameters": {"entry": {"@key": "passwd", "$": ""}}}
+
+        assert mask_datastore(store) == store
+
+
+class TestKeepSecrets:
+    """Test sending the mask back when editing a store."""
+
+    def test_mask_keeps_current_value(self) -> None:
+        """Test a masked password is replaced by the stored one."""
+        params = {"host": "db2", "passwd": MASK}
+
+        kept = keep_secrets(params, _store(host="db", passwd="crypt1:abc"))
+
+        assert kept == {"host": "db2", "passwd": "crypt1:abc"}
+
+    def test_mask_without_current_value_refused(self) -> None:
+        """Test a mask for a parameter the store doesn't have is an error."""
+        with pytest.raises(GeoServerError, match="passwd is masked") as error:
+            keep_secrets({"passwd": MASK}, _store(host="db"))
+
+        assert error.value.status_code == 400
+
+
+class TestResolveSecrets:
+    """Test reading passwords named by env: and keyring: references."""
+
+    def test_env(self, monkeypatch) -> None:
+        """Test an env: reference reads the variable."""
+        monkeypatch.setenv("GSCLIENT_STORE_PROJECT", "from-env")
+
+        assert resolve_secret("env:GSCLIENT_STORE_PROJECT") == "from-env"
+
+    def test_env_missing(self, monkeypatch) -> None:
+        """Test an unset variable is an error, not an empty password."""
+        monkeypatch.delenv("GSCLIENT_STORE_PROJECT", raising=False)
+
+        with pytest.raises(ValueError, match="GSCLIENT_STORE_PROJECT is not set"):
+            resolve_secret("env:GSCLIENT_STORE_PROJECT")
+
+    def test_env_other_variable_refused(self, monkeypatch) -> None:
+        """Test variables without the store prefix can't be read."""
+        monkeypatch.setenv("CLOUDBENCH_ENCRYPTION_KEY", "backend-secret")
+
+        with pytest.raises(ValueError, match="GSCLIENT_STORE_\\* variables only"):
+            resolve_secret("env:CLOUDBENCH_ENCRYPTION_KEY")
+
+    def test_keyring(self) -> None:
+        """Test keyring: references read the stores' service only."""
+        keyring = FakeKeyring(
+            {
+                ("kartoza-cloudbench-stores", "geoserver"): "one",
+                ("kartoza-cloudbench", "conn1"): "refresh-token",
+            }
+        )
+
+        with patch("apps.geoserver.store_secrets._keyring", return_value=keyring):
+            assert resolve_secret("keyring:geoserver") == "one"
+            with pytest.raises(ValueError, match="No password for conn1"):
+                resolve_secret("keyring:conn1")
+            with pytest.raises(ValueError, match="give only the user"):
+                resolve_secret("keyring:kartoza-cloudbench/conn1")
+
+    def test_only_secrets_resolved(self, monkeypatch) -> None:
+        """Test references are only read in secret parameters."""
+        monkeypatch.setenv("GSCLIENT_STORE_PG", "pw")
+
+        reference = "env:GSCLIENT_STORE_PG"
+
+        resolved = resolve_secrets({"schema": reference, "passwd": reference})
+
+        assert resolved == {"schema": reference, "passwd": "pw"}
+
+    def test_mask_rejected(self) -> None:
+        """Test the mask isn't created as a password."""
+        with pytest.raises(ValueError, match="passwd is masked"):
+            resolve_secrets({"passwd": MASK})
+
+    def test_create_datastore(self, monkeypatch, make_geoserver_client) -> None:
+        """Test a store is created with the password, and a bad reference fails it."""
+        monkeypatch.setenv("GSCLIENT_STORE_PG", "pw")
+        client = make_geoserver_client()
+        client._capabilities = build_capabilities("2.24.0")
+        client._request.return_value = MagicMock(status_code=201)
+        params = {"passwd": "env:GSCLIENT_STORE_PG"}
+
+        client.create_datastore("topp", "roads", params)
+        monkeypatch.delenv("GSCLIENT_STORE_PG")
+        with pytest.raises(GeoServerError, match="GSCLIENT_STORE_PG is not set") as raised:
+            client.create_datastore("topp", "roads", params)
+
+        entries = client._request.call_args.kwargs["json"]["dataStore"]["connectionParameters"]
+        assert entries == {"entry": [{"@key": "passwd", "$": "pw"}]}
+        assert raised.value.status_code == 400
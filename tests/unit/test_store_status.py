@@ -0,0 +1,64 @@
+"""Unit tests for store enabled flags.
+
+Tests reading whether each store of a workspace is enabled, and skipping
+the per-store requests for very large workspaces.
+"""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.store_status import add_enabled, list_stores_with_status
+
+
+def _client() -> MagicMock:
+    client = MagicMock()
+    client.list_datastores.return_value = [{"name": "on"}, {"name": "off"}]
+    client.get_datastore.side_effect = lambda ws, name: {"enabled": name == "on"}
+    client.list_coveragestores.return_value = [{"name": "dem"}]
+    client.get_coveragestore.return_value = {"name": "dem"}
+    return client
+
+
+class TestAddEnabled:
+    """Tests for add_enabled."""
+
+    def test_flags_in_order(self) -> None:
+        """Test each store gets its flag, keeping the listing order."""
+        client = _client()
+        stores = list_stores_with_status(client, "topp", "datastore")
+        assert [(s["name"], s["enabled"]) for s in stores] == [("on", True), ("off", False)]
+
+    def test_missing_flag_means_enabled(self) -> None:
+        """Test a store without an enabled field counts as enabled, as in GeoServer."""
+        stores = list_stores_with_status(_client(), "topp", "coveragestore")
+        assert stores == [{"name": "dem", "enabled": True}]
+
+    def test_listed_flags_are_kept(self) -> None:
+        """Test entries already carrying the flag aren't read again."""
+        client = _client()
+        stores = add_enabled(client, "topp", "datastore", [{"name": "off", "enabled": True}])
+        assert stores[0]["enabled"] is True
+        client.get_datastore.assert_not_called()
+
+    def test_unreadable_store(self) -> None:
+        """Test a store that can't be read is left unknown."""
+        client = _client()
+        client.get_datastore.side_effect = GeoServerError("boom", status_code=500)
+        stores = add_enabled(client, "topp", "datastore", [{"name": "on"}])
+        assert stores == [{"name": "on", "enabled": None}]
+
+    def test_large_workspace_is_skipped(self) -> None:
+        """Test workspaces beyond the limit are listed without reading stores."""
+        client = _client()
+        listing = [{"name": f"s{i}"} for i in range(3)]
+        stores = add_enabled(client, "topp", "datastore", listing, limit=2)
+        assert [s["enabled"] for s in stores] == [None, None, None]
+        client.get_datastore.assert_not_called()
+        assert "enabled" not in listing[0]
+
+    def test_unknown_kind(self) -> None:
+        """Test only data and coverage stores are understood."""
+        with pytest.raises(ValueError):
+            list_stores_with_status(_client(), "topp", "wmsstore")
@@ -0,0 +1,178 @@
+"""Unit tests for the graphics kept next to styles."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.style_assets import (
+    clean_asset_path,
+    list_assets,
+    move_asset,
+    rewrite_graphic_hrefs,
+    upload_asset,
+)
+
+SLD = """<StyledLayerDescriptor version="1.0.0" xmlns="http://www.opengis.net/sld"
+  xmlns:xlink="http://www.w3.org/1999/xlink">
+  <NamedLayer><UserStyle><FeatureTypeStyle><Rule>
+    <PointSymbolizer><Graphic>
+      <ExternalGraphic>
+        <OnlineResource xlink:type="simple" xlink:href="{href}"/>
+        <Format>image/png</Format>
+      </ExternalGraphic>
+    </Graphic></PointSymbolizer>
+  </Rule></FeatureTypeStyle></UserStyle></NamedLayer>
+</StyledLayerDescriptor>
+"""
+
+
+def _client(styles: dict[str, str]) -> MagicMock:
+    """Mock a server whose topp styles folder holds icons/hospital.png and pin.svg."""
+    client = MagicMock()
+    folders = {
+        "workspaces/topp/styles": ["hospitals.sld", "icons", "pin.svg"],
+        "workspaces/topp/styles/icons": ["hospital.png"],
+    }
+    client.list_resource_directory.side_effect = lambda path: folders[path]
+    client.get_resource_info.side_effect = lambda path: (
+        {"type": "directory", "size": 0}
+        if path.endswith("/icons")
+        else {"type": "resource", "size": 512}
+    )
+    client.list_styles.return_value = [{"name": name} for name in styles]
+    client.get_style_content.side_effect = lambda name, workspace: (styles[name], "sld")
+    return client
+
+
+class TestCleanAssetPath:
+    """Tests for paths of graphics within a styles folder."""
+
+    def test_normalized(self) -> None:
+        """Test separators and redundant segments are cleaned up."""
+        assert clean_asset_path("/icons\\./hospital.png") == "icons/hospital.png"
+
+    def test_refused(self) -> None:
+        """Test paths leaving the folder and files that aren't images are refused."""
+        with pytest.raises(ValueError, match="inside the styles folder"):
+            clean_asset_path("../global/pin.png")
+        with pytest.raises(ValueError, match="not an image"):
+            clean_asset_path("roads.sld")
+
+
+class TestListAssets:
+    """Tests for listing graphics and the styles using them."""
+
+    def test_usage_and_unresolved(self) -> None:
+        """Test each graphic lists its styles and broken references are reported."""
+        client = _client(
+            {
+                "hospitals": SLD.format(href="icons/hospital.png"),
+                "clinics": SLD.format(href="file:icons/hospital.png"),
+                "schools": SLD.format(href="icons/school.png"),
+                "local": SLD.format(href="file:///opt/icons/pin.png"),
+                "remote": SLD.format(href="https://example.com/pin.png"),
+            }
+        )
+
+        listing = list_assets(client, "topp")
+
+        assert listing.folder == "workspaces/topp/styles"
+        assert [a.to_dict() for a in listing.assets] == [
+            {"path": "icons/hospital.png", "size": 512, "styles": ["clinics", "hospitals"]},
+            {"path": "pin.svg", "size": 512, "styles": []},
+        ]
+        assert [u.to_dict() for u in listing.unresolved] == [
+            {"style": "local", "href": "file:///opt/icons/pin.png", "reason": "absolute"},
+            {"style": "schools", "href": "icons/school.png", "reason": "missing"},
+        ]
+        # Style files themselves aren't looked at
+        client.get_resource_info.assert_any_call("workspaces/topp/styles/pin.svg")
+        assert all(
+            not c.args[0].endswith(".sld") for c in client.get_resource_info.call_args_list
+        )
+
+    def test_budget(self) -> None:
+        """Test a walk cut short is incomplete and doesn't report missing graphics."""
+        client = _client({"schools": SLD.format(href="icons/school.png")})
+
+        listing = list_assets(client, "topp", budget=2)
+
+        assert not listing.complete
+        assert listing.unresolved == []
+
+
+class TestUpload:
+    """Tests for uploading graphics."""
+
+    def test_png(self) -> None:
+        """Test a PNG is written into the workspace's styles folder."""
+        client = MagicMock()
+        data = b"\x89PNG\r\n\x1a\nrest"
+
+        assert upload_asset(client, "icons/pin.png", data, "topp") == "icons/pin.png"
+        client.upload_resource.assert_called_once_with(
+            "workspaces/topp/styles/icons/pin.png", data, "image/png"
+        )
+
+    def test_contents_checked(self) -> None:
+        """Test files whose contents don't match their extension are refused."""
+        client = MagicMock()
+
+        with pytest.raises(ValueError, match="not a PNG"):
+            upload_asset(client, "pin.png", b"<svg/>")
+        with pytest.raises(ValueError, match="only PNG and SVG"):
+            upload_asset(client, "pin.gif", b"GIF89a")
+        client.upload_resource.assert_not_called()
+
+
+class TestMoveAsset:
+    """Tests for moving graphics and the references to them."""
+
+    def test_rewrite_keeps_document(self) -> None:
+        """Test only matching references change, keeping a file: prefix."""
+        content = SLD.format(href="file:icons/hospital.png")
+
+        rewritten, count = rewrite_graphic_hrefs(content, "icons/hospital.png", "pois/h.png")
+
+        assert count == 1
+        assert rewritten == SLD.format(href="file:pois/h.png")
+        assert rewrite_graphic_hrefs(content, "pin.svg", "x.svg") == (content, 0)
+
+    def test_styles_follow(self) -> None:
+        """Test the graphic moves and each style using it is updated."""
+        client = _client(
+            {
+                "hospitals": SLD.format(href="icons/hospital.png"),
+                "clinics": SLD.format(href="icons/hospital.png"),
+                "schools": SLD.format(href="pin.svg"),
+            }
+        )
+        client.update_style_content.side_effect = [
+            GeoServerError("locked", status_code=403),
+            None,
+        ]
+
+        move = move_asset(client, "icons/hospital.png", "pois/hospital.png", "topp")
+
+        client.move_resource.assert_called_once_with(
+            "workspaces/topp/styles/icons/hospital.png",
+            "workspaces/topp/styles/pois/hospital.png",
+        )
+        assert move.updated == ["hospitals"]
+        assert move.failed == {"clinics": "locked"}
+        client.update_style_content.assert_called_with(
+            "hospitals", SLD.format(href="pois/hospital.png"), "sld", "topp"
+        )
+
+    def test_client_move_request(self, make_geoserver_client) -> None:
+        """Test the resource API is asked to move the source to the target path."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        client.move_resource("styles/a.png", "styles/icons/a.png")
+
+        args, kwargs = client._request.call_args
+        assert args == ("PUT", "/rest/resource/styles/icons/a.png")
+        assert kwargs["params"] == {"operation": "move"}
+        assert kwargs["content"] == b"/styles/a.png"
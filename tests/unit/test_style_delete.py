@@ -0,0 +1,133 @@
+"""Unit tests for reassigning layers when deleting a style."""
+
+from unittest.mock import MagicMock, call, patch
+
+import pytest
+
+from apps.geoserver.style_delete import delete_style
+
+
+def _layer(default: str, additional: list[str]) -> MagicMock:
+    """A layer response with the given default and additional styles."""
+    response = MagicMock(status_code=200)
+    response.json.return_value = {
+        "layer": {
+            "defaultStyle": {"name": default},
+            "styles": {"style": [{"name": name} for name in additional]},
+        }
+    }
+    return response
+
+
+def _sent_styles(client) -> tuple[str, list[str]]:
+    """The default and additional styles the client last put on a layer."""
+    payload = client._request.call_args.kwargs["json"]["layer"]
+    return payload["defaultStyle"]["name"], [s["name"] for s in payload["styles"]["style"]]
+
+
+class TestReplaceLayerStyle:
+    """Tests for swapping a style on a layer."""
+
+    def test_default_style_replaced(self, make_geoserver_client) -> None:
+        """Test the replacement becomes the default when the old style was."""
+        client = make_geoserver_client()
+        client._request.side_effect = [_layer("topp:roads", ["line"]), MagicMock(status_code=200)]
+
+        client.replace_layer_style("topp", "roads", "roads", "topp:highways")
+
+        assert client._request.call_args.args == ("PUT", "/rest/workspaces/topp/layers/roads.json")
+        assert _sent_styles(client) == ("topp:highways", ["line"])
+
+    def test_additional_style_replaced(self, make_geoserver_client) -> None:
+        """Test an additional usage is swapped for the replacement."""
+        client = make_geoserver_client()
+        client._request.side_effect = [
+            _layer("line", ["roads", "topp:dashed"]),
+            MagicMock(status_code=200),
+        ]
+
+        client.replace_layer_style("topp", "roads", "dashed", "topp:dotted")
+
+        assert _sent_styles(client) == ("line", ["roads", "topp:dotted"])
+
+    def test_replacement_not_duplicated(self, make_geoserver_client) -> None:
+        """Test a replacement that is already the default isn't added again."""
+        client = make_geoserver_client()
+        client._request.side_effect = [
+            _layer("topp:dotted", ["topp:dashed"]),
+            MagicMock(status_code=200),
+        ]
+
+        client.replace_layer_style("topp", "roads", "dashed", "topp:dotted")
+
+        assert _sent_styles(client) == ("topp:dotted", [])
+
+    def test_emptied_additional_styles_are_sent(self, make_geoserver_client) -> None:
+        """Test removing the last additional style clears the list on the server."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        client.update_layer_styles("topp", "roads", "line", [])
+
+        payload = client._request.call_args.kwargs["json"]
+        assert payload["layer"]["styles"] == {"style": []}
+
+
+class TestDeleteStyle:
+    """Tests for the style delete request."""
+
+    def test_recurse_removes_references(self, make_geoserver_client) -> None:
+        """Test recurse is passed so styles in use can be deleted."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=200)
+
+        client.delete_style("roads", "topp", purge=True, recurse=True)
+
+        client._request.assert_called_once_with(
+            "DELETE",
+            "/rest/workspaces/topp/styles/roads",
+            params={"purge": "true", "recurse": "true"},
+        )
+
+
+class TestDeleteStyleWithLayers:
+    """Tests for deleting a style that layers use."""
+
+    AFFECTED = [
+        {"layer": "topp:roads", "usage": "default"},
+        {"layer": "topp:rivers", "usage": "additional"},
+    ]
+
+    def test_layers_reassigned_before_delete(self) -> None:
+        """Test each layer gets the replacement before the style is deleted."""
+        client = MagicMock()
+        with (
+            patch("apps.geoserver.style_delete.get_geoserver_client", return_value=client),
+            patch("apps.geoserver.style_delete.find_style_layers", return_value=self.AFFECTED),
+            patch("apps.geoserver.style_delete.is_auto_truncate_enabled", return_value=False),
+        ):
+            deletion = delete_style("conn", "topp", "line", reassign_to="topp:dashed")
+
+        assert client.replace_layer_style.call_args_list == [
+            call("topp", "roads", "line", "topp:dashed"),
+            call("topp", "rivers", "line", "topp:dashed"),
+        ]
+        client.delete_style.assert_called_once_with("line", "topp", purge=False, recurse=True)
+        assert deletion.reassigned == ["topp:roads", "topp:rivers"]
+
+    def test_unused_style_deleted_without_recurse(self) -> None:
+        """Test a style no layer uses is deleted as it is."""
+        client = MagicMock()
+        with (
+            patch("apps.geoserver.style_delete.get_geoserver_client", return_value=client),
+            patch("apps.geoserver.style_delete.find_style_layers", return_value=[]),
+        ):
+            deletion = delete_style("conn", "topp", "line", purge=True)
+
+        client.delete_style.assert_called_once_with("line", "topp", purge=True, recurse=False)
+        assert deletion.to_dict()["affectedLayers"] == []
+
+    def test_reassign_to_itself_refused(self) -> None:
+        """Test the style being deleted can't be the replacement."""
+        with pytest.raises(ValueError, match="being deleted"):
+            delete_style("conn", "topp", "line", reassign_to="topp:line")
@@ -0,0 +1,105 @@
+"""Unit tests for diffing styles."""
+
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.managed import content_hash
+from apps.geoserver.style_diff import (
+    diff_connection_styles,
+    diff_lines,
+    diff_local_style,
+)
+from apps.geoserver.style_import import StyleFile
+
+OLD = "<sld>\n  <fill>#ff0000</fill>\n  <stroke>1</stroke>\n</sld>\n"
+NEW = "<sld>\n  <fill>#00ff00</fill>\n  <stroke>1</stroke>\n</sld>\n"
+
+
+def _client(name: str, styles: dict[str, tuple[str, str]]) -> MagicMock:
+    client = MagicMock()
+    client.connection.name = name
+
+    def content(style: str, workspace: str | None = None) -> tuple[str, str]:
+        if style not in styles:
+            raise GeoServerError("Resource not found", status_code=404)
+        return styles[style]
+
+    client.get_style_content.side_effect = content
+    client.list_styles.return_value = [{"name": style} for style in styles]
+    return client
+
+
+class TestDiffLines:
+    """Tests for unified diffs of style text."""
+
+    def test_marks_changed_lines(self) -> None:
+        """Test removed and added lines are marked with their headers."""
+        lines = diff_lines(OLD, NEW, "server", "local")
+
+        assert lines[:2] == ["--- server", "+++ local"]
+        assert "-  <fill>#ff0000</fill>" in lines
+        assert "+  <fill>#00ff00</fill>" in lines
+
+    def test_ignores_line_endings(self) -> None:
+        """Test a file saved with CRLF line endings isn't changed."""
+        assert diff_lines(OLD, OLD.replace("\n", "\r\n"), "a", "b") == []
+
+
+class TestDiffLocalStyle:
+    """Tests for diffing files against a server's styles."""
+
+    def test_changed_style(self) -> None:
+        """Test the server's style is the old side."""
+        client = _client("Production", {"roads": (OLD, "sld")})
+
+        diff = diff_local_style(client, StyleFile("roads.sld", "roads", "sld", NEW), "topp")
+
+        assert diff.status == "changed"
+        assert diff.old_label == "Production/topp/roads"
+        assert (diff.additions, diff.deletions) == (1, 1)
+        assert diff.to_dict()["diff"].startswith("--- Production/topp/roads\n+++ roads.sld")
+
+    def test_new_style_adds_every_line(self) -> None:
+        """Test a style the server lacks is new, with every line added."""
+        client = _client("Production", {})
+
+        diff = diff_local_style(client, StyleFile("roads.sld", "roads", "sld", NEW))
+
+        assert diff.status == "new"
+        assert (diff.additions, diff.deletions) == (4, 0)
+
+    def test_format_change_counts(self) -> None:
+        """Test the same text in another format is still a change."""
+        client = _client("Production", {"roads": (OLD, "css")})
+
+        diff = diff_local_style(client, StyleFile("roads.sld", "roads", "sld", OLD))
+
+        assert diff.lines == []
+        assert diff.status == "changed"
+
+    def test_other_errors_raise(self) -> None:
+        """Test a server that can't be read isn't taken for a missing style."""
+        client = _client("Production", {})
+        client.get_style_content.side_effect = GeoServerError("Denied", status_code=403)
+
+        with pytest.raises(GeoServerError):
+            diff_local_style(client, StyleFile("roads.sld", "roads", "sld", NEW))
+
+
+class TestDiffConnectionStyles:
+    """Tests for diffing the styles a sync would copy."""
+
+    def test_destination_is_the_old_side(self) -> None:
+        """Test every source style is compared with the destination's, in name order."""
+        source = _client("Staging", {"roads": (NEW, "sld"), "rivers": (OLD, "sld")})
+        dest = _client("Production", {"roads": (OLD, "sld"), "parks": (OLD, "sld")})
+
+        diffs = diff_connection_styles(source, dest)
+
+        assert [(d.name, d.status) for d in diffs] == [("rivers", "new"), ("roads", "changed")]
+        assert diffs[1].old_label == "Production/roads"
+        assert diffs[1].new_label == "Staging/roads"
+        assert diffs[0].old_hash is None
+        assert diffs[1].to_dict()["toHash"] == content_hash(NEW)
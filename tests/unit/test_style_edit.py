@@ -0,0 +1,111 @@
+"""Unit tests for editing styles in an external editor."""
+
+from pathlib import Path
+from unittest.mock import MagicMock, patch
+
+from apps.geoserver.style_edit import ExternalStyleEdit, editor_command, validate_style
+
+SLD = (
+    '<StyledLayerDescriptor xmlns="http://www.opengis.net/sld" version="1.0.0">'
+    "<NamedLayer><Name>roads</Name></NamedLayer>"
+    "</StyledLayerDescriptor>"
+)
+
+
+class TestEditorCommand:
+    """Tests for choosing the editor."""
+
+    def test_visual_wins_and_keeps_arguments(self) -> None:
+        """Test $VISUAL is preferred and may carry arguments."""
+        command = editor_command(Path("/tmp/s.sld"), {"VISUAL": "code -w", "EDITOR": "nano"})
+
+        assert command == ["code", "-w", "/tmp/s.sld"]
+
+    def test_editor_used_without_visual(self) -> None:
+        """Test $EDITOR is used when $VISUAL is unset."""
+        assert editor_command(Path("/tmp/s.sld"), {"EDITOR": "nano"}) == ["nano", "/tmp/s.sld"]
+
+
+class TestValidateStyle:
+    """Tests for checking style content before upload."""
+
+    def test_valid_sld(self) -> None:
+        """Test a well-formed SLD passes."""
+        assert validate_style(SLD, "sld") == []
+
+    def test_malformed_sld(self) -> None:
+        """Test broken XML is reported."""
+        errors = validate_style("<StyledLayerDescriptor>", "sld")
+
+        assert errors and errors[0].startswith("Invalid XML")
+
+    def test_sld_wrong_root(self) -> None:
+        """Test XML that isn't an SLD is reported."""
+        assert validate_style("<html/>", "sld")
+
+    def test_css_braces(self) -> None:
+        """Test unbalanced CSS braces are reported."""
+        assert validate_style("* { stroke: black; }", "css") == []
+        assert validate_style("* { stroke: black;", "css") == ["Unclosed '{'"]
+        assert validate_style("}", "css") == ["Line 1: unexpected '}'"]
+
+    def test_mbstyle_needs_layers(self) -> None:
+        """Test a MapBox style without layers is reported."""
+        assert validate_style('{"version": 8, "layers": []}', "mbstyle") == []
+        assert validate_style('{"version": 8}', "mbstyle")
+
+    def test_empty(self) -> None:
+        """Test empty content is reported."""
+        assert validate_style("  \n", "sld") == ["The style is empty"]
+
+
+class TestExternalStyleEdit:
+    """Tests for the download, edit and upload cycle."""
+
+    def _edit(self) -> tuple[ExternalStyleEdit, MagicMock]:
+        client = MagicMock()
+        client.get_style_content.return_value = (SLD, "sld")
+        edit = ExternalStyleEdit(client, "conn_1", "roads", "topp")
+        edit.download()
+        return edit, client
+
+    def test_unchanged_not_uploaded(self) -> None:
+        """Test an untouched file is discarded without uploading."""
+        edit, client = self._edit()
+        path = edit.path
+
+        result = edit.finish()
+
+        assert result.status == "unchanged"
+        client.update_style_content.assert_not_called()
+        assert not path.exists()
+
+    def test_invalid_kept_for_next_edit(self) -> None:
+        """Test invalid content isn't uploaded and the file is kept."""
+        edit, client = self._edit()
+        edit.path.write_text("<StyledLayerDescriptor>")
+
+        result = edit.finish()
+
+        assert result.status == "invalid"
+        client.update_style_content.assert_not_called()
+        assert edit.path.exists()
+        edit.cleanup()
+
+    def test_changed_uploaded(self) -> None:
+        """Test valid changes are uploaded and the tile cache invalidated."""
+        edit, client = self._edit()
+        changed = SLD.replace("roads", "streets")
+        edit.path.write_text(changed)
+
+        with patch(
+            "apps.geoserver.style_edit.invalidate_style_cache",
+            return_value=([{"name": "topp:roads"}], []),
+        ) as invalidate:
+            result = edit.finish()
+
+        assert result.status == "uploaded"
+        assert result.affected_layers == [{"name": "topp:roads"}]
+        client.update_style_content.assert_called_once_with("roads", changed, "sld", "topp")
+        invalidate.assert_called_once_with("conn_1", "topp", "roads")
+        assert edit.path is None
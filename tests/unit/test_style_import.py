@@ -0,0 +1,267 @@
+"""Unit tests for importing a local folder of styles.
+
+Tests naming styles after their files, scanning folders, downloading
+styles by URL and skipping styles whose content hasn't changed.
+"""
+
+from pathlib import Path
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.style_import import (
+    StyleFile,
+    _check_host,
+    content_hash,
+    count_results,
+    fetch_style,
+    import_styles,
+    raw_url,
+    scan_style_directory,
+    style_name,
+)
+
+SLD = (
+    '<?xml version="1.0"?>\n'
+    '<StyledLayerDescriptor version="1.0.0"><NamedLayer/></StyledLayerDescriptor>\n'
+)
+
+
+def _no_cache_invalidation(monkeypatch: pytest.MonkeyPatch) -> MagicMock:
+    """Keep updated styles from looking for tile caches to truncate."""
+    invalidate = MagicMock(return_value=([], []))
+    monkeypatch.setattr("apps.geoserver.style_import.invalidate_style_cache", invalidate)
+    return invalidate
+
+
+def _client(existing: dict[str, tuple[str, str]]) -> MagicMock:
+    """A client whose workspace holds styles as name -> (content, format)."""
+    client = MagicMock()
+    client.connection.id = "local"
+    client.list_styles.return_value = [{"name": name} for name in existing]
+    client.get_style_content.side_effect = lambda name, ws=None: existing[name]
+    return client
+
+
+class TestStyleName:
+    """Tests for inferring style names and comparing content."""
+
+    @pytest.mark.parametrize(
+        "filename,expected",
+        [
+            ("roads.sld", "roads"),
+            ("sub/Main Roads (v2).css", "Main_Roads_v2"),
+            ("land-use.v1.sld", "land-use.v1"),
+        ],
+    )
+    def test_from_filename(self, filename: str, expected: str) -> None:
+        """Test names keep letters, digits, dots and dashes."""
+        assert style_name(filename) == expected
+
+    def test_hash_ignores_whitespace(self) -> None:
+        """Test line endings and trailing whitespace don't count as changes."""
+        assert content_hash("a {\r\n  b  \r\n}\r\n") == content_hash("a {\n  b\n}")
+        assert content_hash("a { b }") != content_hash("a { c }")
+
+
+class TestScanStyleDirectory:
+    """Tests for reading a folder of style files."""
+
+    def test_scan(self, tmp_path: Path) -> None:
+        """Test only style files are read, and subfolders only when asked."""
+        (tmp_path / "roads.sld").write_text(SLD)
+        (tmp_path / "water.CSS").write_text("* { fill: blue; }")
+        (tmp_path / "notes.txt").write_text("not a style")
+        (tmp_path / ".hidden.sld").write_text(SLD)
+        (tmp_path / "extra").mkdir()
+        (tmp_path / "extra" / "parks.sld").write_text(SLD)
+
+        files = scan_style_directory(tmp_path)
+        assert [(f.path, f.name, f.format) for f in files] == [
+            ("roads.sld", "roads", "sld"),
+            ("water.CSS", "water", "css"),
+        ]
+
+        files = scan_style_directory(tmp_path, recursive=True)
+        assert [f.path for f in files] == ["extra/parks.sld", "roads.sld", "water.CSS"]
+
+    def test_not_a_folder(self, tmp_path: Path) -> None:
+        """Test a missing folder is refused."""
+        with pytest.raises(ValueError, match="Not a folder"):
+            scan_style_directory(tmp_path / "missing")
+
+    def test_not_utf8(self) -> None:
+        """Test binary content is refused."""
+        with pytest.raises(ValueError, match="not UTF-8"):
+            StyleFile.from_bytes("roads.sld", b"\xff\xfe\x00")
+
+
+class TestImportStyles:
+    """Tests for creating, updating and skipping styles."""
+
+    def test_statuses(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test new styles are created, changed ones updated and the rest skipped."""
+        invalidate = _no_cache_invalidation(monkeypatch)
+        client = _client(
+            {
+                "roads": (SLD.replace("\n", "\r\n"), "sld"),
+                "water": ("* { fill: red; }", "css"),
+            }
+        )
+        files = [
+            StyleFile("roads.sld", "roads", "sld", SLD),
+            StyleFile("water.css", "water", "css", "* { fill: blue; }"),
+            StyleFile("parks.sld", "parks", "sld", SLD),
+        ]
+
+        results = import_styles(client, files, "topp")
+
+        assert [(r.name, r.status) for r in results] == [
+            ("roads", "skipped"),
+            ("water", "updated"),
+            ("parks", "created"),
+        ]
+        client.update_style_content.assert_called_once_with(
+            "water", "* { fill: blue; }", "css", "topp"
+        )
+        client.create_style.assert_called_once_with("parks", SLD, "sld", "topp")
+        invalidate.assert_called_once_with("local", "topp", "water")
+        assert count_results(results) == {"created": 1, "updated": 1, "skipped": 1, "failed": 0}
+
+    def test_format_change_updates(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test a style whose format changed is uploaded even with the same text."""
+        _no_cache_invalidation(monkeypatch)
+        client = _client({"roads": ("* { stroke: black; }", "sld")})
+
+        results = import_styles(
+            client, [StyleFile("roads.css", "roads", "css", "* { stroke: black; }")]
+        )
+
+        assert results[0].status == "updated"
+        client.list_styles.assert_called_once_with(None)
+
+    def test_failures(self) -> None:
+        """Test invalid files, clashing names and refusals fail on their own."""
+        client = _client({})
+
+        def create_style(name: str, *args: object) -> None:
+            if name == "water":
+                raise GeoServerError("CSS styles need the CSS extension")
+
+        client.create_style.side_effect = create_style
+        files = [
+            StyleFile("broken.sld", "broken", "sld", "<StyledLayerDescriptor>"),
+            StyleFile("roads.sld", "roads", "sld", SLD),
+            StyleFile("roads.css", "roads", "css", "* { stroke: black; }"),
+            StyleFile("water.css", "water", "css", "* { fill: blue; }"),
+        ]
+
+        results = import_styles(client, files, "topp")
+
+        assert [r.status for r in results] == ["failed", "created", "failed", "failed"]
+        assert results[0].error.startswith("Invalid XML")
+        assert results[2].error == "roads.sld is also named roads"
+        assert results[3].to_dict() == {
+            "path": "water.css",
+            "name": "water",
+            "status": "failed",
+            "error": "CSS styles need the CSS extension",
+        }
+
+
+class TestFetchStyle:
+    """Tests for creating styles from a URL."""
+
+    @pytest.mark.parametrize(
+        "url,expected",
+        [
+            (
+                "https://github.com/org/styles/blob/main/sld/roads.sld",
+                "https://raw.githubusercontent.com/org/styles/main/sld/roads.sld",
+            ),
+            (
+                "https://gitlab.example.com/gis/styles/-/blob/v2/roads.css",
+                "https://gitlab.example.com/gis/styles/-/raw/v2/roads.css",
+            ),
+            (
+                "https://raw.githubusercontent.com/org/styles/main/roads.sld",
+                "https://raw.githubusercontent.com/org/styles/main/roads.sld",
+            ),
+        ],
+    )
+    def test_raw_url(self, url: str, expected: str) -> None:
+        """Test Git file pages are turned into raw file URLs."""
+        assert raw_url(url) == expected
+
+    def test_fetch(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test the style is named after the URL and the header is sent."""
+        download = MagicMock(return_value=(SLD.encode(), "text/plain"))
+        monkeypatch.setattr("apps.geoserver.style_import._download", download)
+
+        style_file = fetch_style(
+            "https://github.com/org/styles/blob/main/Main%20Roads.sld", "token abc"
+        )
+
+        assert (style_file.name, style_file.format) == ("Main_Roads", "sld")
+        assert style_file.path == "https://github.com/org/styles/blob/main/Main%20Roads.sld"
+        url, headers = download.call_args.args
+        assert url == "https://raw.githubusercontent.com/org/styles/main/Main%20Roads.sld"
+        assert headers["Authorization"] == "token abc"
+
+    @pytest.mark.parametrize(
+        "data,content_type,expected",
+        [
+            (SLD.encode(), "text/plain", "sld"),
+            (b"* { stroke: black; }", "text/plain", "css"),
+            (b"* { stroke: black; }", "application/xml", "sld"),
+        ],
+    )
+    def test_format_without_extension(
+        self,
+        monkeypatch: pytest.MonkeyPatch,
+        data: bytes,
+        content_type: str,
+        expected: str,
+    ) -> None:
+        """Test the format comes from the content type or content without an extension."""
+        download = MagicMock(return_value=(data, content_type))
+        monkeypatch.setattr("apps.geoserver.style_import._download", download)
+
+        style_file = fetch_style("https://styles.example.com/api/roads")
+
+        assert (style_file.name, style_file.format) == ("roads", expected)
+
+    def test_authorization_from_environment(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test the environment supplies the header to trusted hosts only."""
+        download = MagicMock(return_value=(SLD.encode(), ""))
+        monkeypatch.setattr("apps.geoserver.style_import._download", download)
+        monkeypatch.setenv("CLOUDBENCH_STYLE_AUTHORIZATION", "Bearer xyz")
+        monkeypatch.setenv("CLOUDBENCH_STYLE_HOSTS", "gitlab.example.com")
+
+        fetch_style("https://gitlab.example.com/gis/styles/-/blob/main/roads.sld")
+        assert download.call_args.args[1]["Authorization"] == "Bearer xyz"
+
+        fetch_style("https://styles.example.com/roads.sld")
+        assert "Authorization" not in download.call_args.args[1]
+
+    @pytest.mark.parametrize(
+        "url",
+        ["http://127.0.0.1:8000/roads.sld", "http://169.254.169.254/latest", "http://[::1]/a"],
+    )
+    def test_private_address_refused(self, url: str) -> None:
+        """Test URLs of the server's own network are refused."""
+        with pytest.raises(ValueError, match="private address"):
+            fetch_style(url)
+
+    def test_trusted_private_host(self, monkeypatch: pytest.MonkeyPatch) -> None:
+        """Test a host configured as trusted may have a private address."""
+        monkeypatch.setenv("CLOUDBENCH_STYLE_HOSTS", "127.0.0.1")
+        monkeypatch.setattr("apps.geoserver.style_import.socket.getaddrinfo", MagicMock())
+
+        _check_host("http://127.0.0.1/roads.sld")
+
+    def test_not_http(self) -> None:
+        """Test file and other URLs are refused."""
+        with pytest.raises(ValueError, match="Not an http"):
+            fetch_style("file:///etc/passwd")
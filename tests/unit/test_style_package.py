@@ -0,0 +1,141 @@
+"""Unit tests for zipped SLD packages."""
+
+import io
+import zipfile
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.geoserver.style_import import StyleFile, import_styles
+from apps.geoserver.style_package import external_graphics, read_style_package
+
+SLD = """<StyledLayerDescriptor version="1.0.0" xmlns="http://www.opengis.net/sld"
+  xmlns:xlink="http://www.w3.org/1999/xlink">
+  <NamedLayer><UserStyle><FeatureTypeStyle><Rule>
+    <PointSymbolizer><Graphic>
+      <ExternalGraphic>
+        <OnlineResource xlink:type="simple" xlink:href="icons/hospital.png"/>
+        <Format>image/png</Format>
+      </ExternalGraphic>
+      <ExternalGraphic>
+        <OnlineResource xlink:type="simple" xlink:href="file:school.svg"/>
+        <Format>image/svg+xml</Format>
+      </ExternalGraphic>
+      <ExternalGraphic>
+        <OnlineResource xlink:type="simple" xlink:href="https://example.com/pin.png"/>
+        <Format>image/png</Format>
+      </ExternalGraphic>
+      <ExternalGraphic>
+        <OnlineResource xlink:type="simple" xlink:href="icons/${kind}.png"/>
+        <Format>image/png</Format>
+      </ExternalGraphic>
+    </Graphic></PointSymbolizer>
+  </Rule></FeatureTypeStyle></UserStyle></NamedLayer>
+</StyledLayerDescriptor>
+"""
+
+
+def _zip(files: dict[str, str]) -> bytes:
+    buffer = io.BytesIO()
+    with zipfile.ZipFile(buffer, "w") as archive:
+        for name, content in files.items():
+            archive.writestr(name, content)
+    return buffer.getvalue()
+
+
+class TestExternalGraphics:
+    """Tests for finding the graphics an SLD refers to."""
+
+    def test_references(self) -> None:
+        """Test every ExternalGraphic is listed except attribute-driven ones."""
+        assert external_graphics(SLD) == [
+            "icons/hospital.png",
+            "file:school.svg",
+            "https://example.com/pin.png",
+        ]
+
+    def test_invalid(self) -> None:
+        """Test content that isn't XML is refused."""
+        with pytest.raises(ValueError, match="Invalid XML"):
+            external_graphics("<StyledLayerDescriptor>")
+
+
+class TestReadPackage:
+    """Tests for reading a style package."""
+
+    def test_missing_graphics(self) -> None:
+        """Test relative references resolve from the SLD's folder in the zip."""
+        package = read_style_package(
+            _zip({"markers/markers.sld": SLD, "markers/icons/hospital.png": "png"})
+        )
+
+        assert package.sld_path == "markers/markers.sld"
+        assert package.files == ["markers/icons/hospital.png"]
+        # Remote graphics aren't expected in the package
+        assert package.missing == ["file:school.svg"]
+
+    def test_complete(self) -> None:
+        """Test a package holding every graphic has none missing."""
+        package = read_style_package(
+            _zip({"markers.sld": SLD, "icons/hospital.png": "png", "school.svg": "<svg/>"})
+        )
+
+        assert package.missing == []
+
+    def test_needs_one_sld(self) -> None:
+        """Test packages without an SLD, or with several, are refused."""
+        with pytest.raises(ValueError, match="found none"):
+            read_style_package(_zip({"icon.png": "png"}))
+        with pytest.raises(ValueError, match="a.sld, b.sld"):
+            read_style_package(_zip({"a.sld": SLD, "b.sld": SLD}))
+        with pytest.raises(ValueError, match="Not a zip"):
+            read_style_package(b"not a zip")
+
+
+class TestImportPackage:
+    """Tests for importing style packages."""
+
+    def test_style_file_from_zip(self) -> None:
+        """Test a .zip is read as an SLD carrying its package."""
+        data = _zip({"markers.sld": SLD})
+
+        style_file = StyleFile.from_bytes("markers.zip", data)
+
+        assert (style_file.name, style_file.format) == ("markers", "sld")
+        assert style_file.package == data
+        assert style_file.missing_graphics == ["icons/hospital.png", "file:school.svg"]
+
+    def test_uploaded_with_warning(self) -> None:
+        """Test a package is uploaded whole, warning about missing graphics."""
+        client = MagicMock()
+        client.list_styles.return_value = []
+        style_file = StyleFile.from_bytes("markers.zip", _zip({"markers.sld": SLD}))
+
+        (result,) = import_styles(client, [style_file], "topp")
+
+        client.upload_style_package.assert_called_once_with(
+            "markers", style_file.package, "topp", exists=False
+        )
+        client.create_style.assert_not_called()
+        assert result.status == "created"
+        assert result.to_dict()["warning"] == (
+            "Graphics missing from the package: icons/hospital.png, file:school.svg"
+        )
+
+    def test_client_creates_and_updates(self, make_geoserver_client) -> None:
+        """Test packages are posted as new styles and put over existing ones."""
+        client = make_geoserver_client()
+        client._request.return_value = MagicMock(status_code=201)
+
+        client.upload_style_package("markers", b"zip", "topp")
+        client.upload_style_package("markers", b"zip", exists=True)
+
+        create, update = client._request.call_args_list
+        assert create.args == ("POST", "/rest/workspaces/topp/styles")
+        assert create.kwargs["params"] == {"name": "markers"}
+        assert create.kwargs["headers"] == {
+            "Content-Type": "application/zip",
+            "Content-Length": "3",
+        }
+        assert b"".join(create.kwargs["content"]) == b"zip"
+        assert update.args == ("PUT", "/rest/styles/markers")
@@ -0,0 +1,215 @@
+"""Unit tests for terminal map previews."""
+
+import struct
+import zlib
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.core.raster import Image, decode_png, encode_png
+from apps.geoserver.terminal_map import (
+    ORIGIN_SHIFT,
+    MapViewport,
+    detect_graphics,
+    encode_sixel,
+    render_ascii,
+    render_basemap,
+    render_halfblock,
+    render_preview,
+)
+
+RED = (255, 0, 0, 255)
+BLUE = (0, 0, 255, 255)
+CLEAR = (0, 0, 0, 0)
+
+
+def _checker(width: int = 4, height: int = 4) -> Image:
+    image = Image.blank(width, height, RED)
+    for y in range(height):
+        for x in range(width):
+            if (x + y) % 2:
+                image.set(x, y, BLUE)
+    return image
+
+
+def _palette_png(indexes: list[int], depth: int) -> bytes:
+    """Build a one-row palette PNG at a given bit depth."""
+
+    def chunk(kind: bytes, body: bytes) -> bytes:
+        return struct.pack(">I", len(body)) + kind + body + struct.pack(">I", 0)
+
+    per_byte = 8 // depth
+    row = bytearray((len(indexes) + per_byte - 1) // per_byte)
+    for x, index in enumerate(indexes):
+        row[x // per_byte] |= index << (8 - depth * (x % per_byte + 1))
+    header = struct.pack(">IIBBBBB", len(indexes), 1, depth, 3, 0, 0, 0)
+    return (
+        b"\x89PNG\r\n\x1a\n"
+        + chunk(b"IHDR", header)
+        + chunk(b"PLTE", bytes((255, 0, 0, 0, 0, 255)))
+        + chunk(b"tRNS", bytes((255, 0)))
+        + chunk(b"IDAT", zlib.compress(b"\x00" + bytes(row)))
+        + chunk(b"IEND", b"")
+    )
+
+
+class TestRaster:
+    """Tests for PNG decoding and image operations."""
+
+    def test_round_trip(self) -> None:
+        """Test an encoded RGBA image decodes to the same pixels."""
+        image = _checker()
+
+        decoded = decode_png(encode_png(image))
+
+        assert (decoded.width, decoded.height) == (4, 4)
+        assert decoded.pixels == image.pixels
+
+    def test_palette_with_transparency(self) -> None:
+        """Test 1-bit palette images use the palette and tRNS alpha."""
+        decoded = decode_png(_palette_png([0, 1, 1, 0], depth=1))
+
+        assert [decoded.get(x, 0) for x in range(4)] == [
+            (255, 0, 0, 255),
+            (0, 0, 255, 0),
+            (0, 0, 255, 0),
+            (255, 0, 0, 255),
+        ]
+
+    def test_rejects_other_data(self) -> None:
+        """Test data that isn't a PNG is refused."""
+        with pytest.raises(ValueError):
+            decode_png(b"<ServiceExceptionReport/>")
+
+    def test_composite_keeps_base_under_transparency(self) -> None:
+        """Test transparent pixels show the base and opaque ones cover it."""
+        layer = Image.blank(2, 1, CLEAR)
+        layer.set(1, 0, RED)
+
+        result = layer.composite_over(Image.blank(2, 1, BLUE))
+
+        assert result.get(0, 0) == BLUE
+        assert result.get(1, 0) == RED
+
+    def test_resize(self) -> None:
+        """Test nearest-neighbour resampling doubles pixels."""
+        result = _checker(2, 2).resized(4, 4)
+
+        assert result.get(0, 0) == result.get(1, 1) == RED
+        assert result.get(2, 0) == BLUE
+
+
+class TestViewport:
+    """Tests for viewport math."""
+
+    def test_world_bounds(self) -> None:
+        """Test framing the world centers the view on 0,0."""
+        viewport = MapViewport.from_latlon_bbox(
+            {"minx": -180, "miny": -85, "maxx": 180, "maxy": 85}, 256, 256
+        )
+
+        assert viewport.center_x == pytest.approx(0.0, abs=1e-6)
+        assert viewport.center_y == pytest.approx(0.0, abs=1e-6)
+        minx, _, maxx, _ = viewport.bbox(256, 256)
+        assert minx < -ORIGIN_SHIFT * 0.99 and maxx > ORIGIN_SHIFT * 0.99
+
+    def test_zoom_and_pan(self) -> None:
+        """Test zooming halves the extent and panning moves by a fraction of it."""
+        viewport = MapViewport(0.0, 0.0, 100.0)
+
+        viewport.zoom(2.0)
+        viewport.pan(0.25, -0.5, 100, 50)
+
+        assert viewport.resolution == 50.0
+        assert viewport.bbox(100, 50) == (-1250.0, -2500.0, 3750.0, 0.0)
+
+    def test_tile_zoom(self) -> None:
+        """Test the basemap zoom level follows the resolution."""
+        viewport = MapViewport()
+        assert viewport.tile_zoom() == 0
+
+        viewport.zoom(8.0)
+        assert viewport.tile_zoom() == 3
+
+
+class TestRendering:
+    """Tests for composing and drawing previews."""
+
+    def test_preview_over_basemap(self) -> None:
+        """Test the layer is drawn over basemap tiles sampled for the view."""
+        layer = Image.blank(4, 2, CLEAR)
+        layer.set(0, 0, RED)
+        client = MagicMock()
+        client.get_map.return_value = encode_png(layer)
+        tiles = MagicMock(return_value=Image.blank(256, 256, BLUE))
+
+        preview = render_preview(client, "topp", "roads", MapViewport(), 4, 2, basemap=tiles)
+
+        client.get_map.assert_called_once()
+        assert client.get_map.call_args.args[:2] == ("topp", "roads")
+        assert preview.image.get(0, 0) == RED
+        assert preview.image.get(1, 0) == BLUE
+        assert preview.basemap_error is None
+
+    def test_preview_rejects_undecodable_image(self) -> None:
+        """Test an image that can't be decoded is reported as a GeoServer error."""
+        client = MagicMock()
+        client.get_map.return_value = b"GIF89a"
+
+        with pytest.raises(GeoServerError):
+            render_preview(client, "topp", "roads", MapViewport(), 4, 2)
+
+    def test_basemap_outside_world_is_background(self) -> None:
+        """Test pixels north of the world aren't fetched."""
+        fetch = MagicMock(return_value=None)
+        viewport = MapViewport(0.0, ORIGIN_SHIFT, 1000.0)
+
+        render_basemap(viewport, 2, 2, fetch)
+
+        # Only the bottom row is inside the world
+        assert fetch.call_count == 2
+
+    def test_halfblock_uses_two_rows_per_line(self) -> None:
+        """Test each character cell carries the colors of two pixels."""
+        text = render_halfblock(_checker(2, 2))
+
+        assert "\n" not in text
+        assert text.count("▀") == 2
+        assert "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m" in text
+
+    def test_ascii(self) -> None:
+        """Test dark pixels use dense characters and light ones blank space."""
+        image = Image.blank(2, 2, (255, 255, 255, 255))
+        image.set(1, 0, (0, 0, 0, 255))
+        image.set(1, 1, (0, 0, 0, 255))
+
+        assert render_ascii(image) == " @"
+
+    def test_sixel(self) -> None:
+        """Test sixel output declares the raster size and the colors used."""
+        sixel = encode_sixel(_checker(4, 4))
+
+        assert sixel.startswith('\x1bPq"1;1;4;4')
+        assert sixel.endswith("\x1b\\")
+        assert "#180;2;100;0;0" in sixel
+        assert "#5;2;0;0;100" in sixel
+
+
+class TestDetectGraphics:
+    """Tests for terminal graphics detection."""
+
+    def test_override(self) -> None:
+        """Test the environment variable wins."""
+        assert detect_graphics({"CLOUDBENCH_GRAPHICS": "ascii", "TERM": "foot"}) == "ascii"
+
+    def test_sixel_terminals(self) -> None:
+        """Test known sixel terminals are detected."""
+        assert detect_graphics({"TERM": "xterm-256color", "TERM_PROGRAM": "WezTerm"}) == "sixel"
+        assert detect_graphics({"TERM": "foot"}) == "sixel"
+
+    def test_plain_terminals(self) -> None:
+        """Test other terminals get half blocks, and colorless ones characters."""
+        assert detect_graphics({"TERM": "xterm-256color"}) == "halfblock"
+        assert detect_graphics({"TERM": "xterm", "NO_COLOR": "1"}) == "ascii"
+        assert detect_graphics({"TERM": "dumb"}) == "ascii"
@@ -0,0 +1,209 @@
+"""Unit tests for Terria init files of a connection's layers."""
+
+import json
+from pathlib import Path
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.exceptions import GeoServerError
+from apps.terria.init_file import (
+    TILESETS_GROUP,
+    WORLD,
+    InitFile,
+    build_init,
+    generate_init,
+    legend_url,
+    rectangle,
+    union_rectangle,
+    upload_init,
+    write_init,
+)
+
+BASE_URL = "https://maps.example.com/geoserver"
+
+ROADS_BBOX = {"minx": 10.0, "miny": 40.0, "maxx": 12.0, "maxy": 41.0, "crs": "EPSG:4326"}
+
+
+def make_client(layers: dict[str, list[str]], resources: dict[str, dict] | None = None):
+    """A client whose workspaces hold the layers given, each a feature type."""
+    resources = resources or {}
+    client = MagicMock()
+    client.list_workspaces.return_value = [{"name": ws} for ws in layers]
+    client.list_layers.side_effect = lambda ws: [{"name": name} for name in layers[ws]]
+    client.get_layer.side_effect = lambda ws, name: {
+        "name": name,
+        "defaultStyle": {"name": "line"},
+        "resource": {
+            "@class": "featureType",
+            "href": f"{BASE_URL}/rest/workspaces/{ws}/featuretypes/{name}.json",
+        },
+    }
+    client.get_href.side_effect = lambda href: {
+        "featureType": resources.get(href.rsplit("/", 1)[1].removesuffix(".json"), {})
+    }
+    return client
+
+
+class TestRectangle:
+    """Test converting bounding boxes into Terria rectangles."""
+
+    def test_bounding_box(self):
+        assert rectangle(ROADS_BBOX) == {"west": 10.0, "south": 40.0, "east": 12.0, "north": 41.0}
+
+    def test_clamped_to_the_globe(self):
+        bbox = {"minx": -200, "miny": -95, "maxx": 190, "maxy": 95}
+        assert rectangle(bbox) == WORLD
+
+    @pytest.mark.parametrize(
+        "bbox", [None, {}, {"minx": 1}, {"minx": "a", "miny": 0, "maxx": 1, "maxy": 1}]
+    )
+    def test_unusable(self, bbox):
+        assert rectangle(bbox) is None
+
+    def test_union(self):
+        a = {"west": 10.0, "south": 40.0, "east": 12.0, "north": 41.0}
+        b = {"west": -5.0, "south": 45.0, "east": 0.0, "north": 50.0}
+        assert union_rectangle([a, b]) == {"west": -5.0, "south": 40.0, "east": 12.0, "north": 50.0}
+
+    def test_union_of_nothing_is_the_globe(self):
+        assert union_rectangle([]) == WORLD
+
+
+class TestLegendUrl:
+    """Test legend URLs of layers."""
+
+    def test_workspace_wms(self):
+        url = legend_url(BASE_URL, "topp", "roads", "line")
+        assert url.startswith(f"{BASE_URL}/topp/wms?")
+        assert "request=GetLegendGraphic" in url
+        assert "layer=topp%3Aroads" in url
+        assert "style=line" in url
+
+    def test_without_style(self):
+        assert "style=" not in legend_url(BASE_URL, "topp", "roads")
+
+
+class TestBuildInit:
+    """Test building the init JSON."""
+
+    def test_group_per_workspace(self):
+        client = make_client(
+            {"topp": ["roads", "rivers"], "tiger": ["poi"], "empty": []},
+            {
+                "roads": {
+                    "title": "Roads",
+                    "abstract": "Main roads",
+                    "latLonBoundingBox": ROADS_BBOX,
+                }
+            },
+        )
+
+        init = build_init(client, BASE_URL, "Production")
+
+        top = init["catalog"][0]
+        assert top["name"] == "Production"
+        assert [group["name"] for group in top["members"]] == ["topp", "tiger"]
+        roads = top["members"][0]["members"][0]
+        assert roads["type"] == "wms"
+        assert roads["name"] == "Roads"
+        assert roads["description"] == "Main roads"
+        assert roads["url"] == f"{BASE_URL}/topp/wms"
+        assert roads["layers"] == "roads"
+        assert "style=line" in roads["legends"][0]["url"]
+        assert roads["rectangle"] == rectangle(ROADS_BBOX)
+        rivers = top["members"][0]["members"][1]
+        assert rivers["name"] == "rivers"
+        assert "rectangle" not in rivers
+
+    def test_camera_on_the_layers(self):
+        client = make_client({"topp": ["roads"]}, {"roads": {"latLonBoundingBox": ROADS_BBOX}})
+
+        init = build_init(client, BASE_URL, "Production")
+
+        assert init["homeCamera"] == rectangle(ROADS_BBOX)
+        assert init["initialCamera"] == init["homeCamera"]
+
+    def test_camera_on_the_globe_without_extents(self):
+        init = build_init(make_client({"topp": ["roads"]}), BASE_URL, "Production")
+        assert init["homeCamera"] == WORLD
+
+    def test_selected_workspaces(self):
+        client = make_client({"topp": ["roads"], "tiger": ["poi"]})
+
+        init = build_init(client, BASE_URL, "Production", ["tiger"])
+
+        assert [group["name"] for group in init["catalog"][0]["members"]] == ["tiger"]
+        client.list_workspaces.assert_not_called()
+
+    def test_unreadable_resource(self):
+        client = make_client({"topp": ["roads"]})
+        client.get_href.side_effect = GeoServerError("Not found", 404)
+
+        init = build_init(client, BASE_URL, "Production")
+
+        roads = init["catalog"][0]["members"][0]["members"][0]
+        assert roads["name"] == "roads"
+        assert "rectangle" not in roads
+
+
+    def test_tilesets_after_the_workspaces(self):
+        tileset = {
+            "type": "3d-tiles",
+            "name": "city_3dtiles",
+            "url": "https://s3.example.com/lidar/city_3dtiles/tileset.json",
+            "rectangle": {"west": -5.0, "south": 45.0, "east": 0.0, "north": 50.0},
+        }
+        client = make_client({"topp": ["roads"]}, {"roads": {"latLonBoundingBox": ROADS_BBOX}})
+
+        init = build_init(client, BASE_URL, "Production", tilesets=[tileset])
+
+        groups = init["catalog"][0]["members"]
+        assert [group["name"] for group in groups] == ["topp", TILESETS_GROUP]
+        assert groups[1]["members"] == [tileset]
+        assert init["homeCamera"] == {"west": -5.0, "south": 40.0, "east": 12.0, "north": 50.0}
+
+
+class TestGenerateInit:
+    """Test generating and writing init files."""
+
+    def test_file(self):
+        client = make_client({"topp": ["roads", "rivers"]})
+
+        init = generate_init(client, BASE_URL, "Production Maps")
+
+        assert init.filename == "production_maps.json"
+        assert init.layers == 2
+        assert json.loads(init.content)["catalog"][0]["name"] == "Production Maps"
+
+    def test_no_layers(self):
+        with pytest.raises(ValueError, match="No layers"):
+            generate_init(make_client({"topp": []}), BASE_URL, "Production")
+
+    def test_write(self, tmp_path: Path):
+        init = InitFile("city.json", "{}\n", 1)
+
+        path = write_init(init, tmp_path)
+
+        assert path.read_text() == "{}\n"
+        assert init.location == str(path)
+
+    def test_write_missing_folder(self, tmp_path: Path):
+        with pytest.raises(OSError, match="Folder not found"):
+            write_init(InitFile("city.json", "{}\n"), tmp_path / "missing")
+
+    def test_upload(self):
+        s3 = MagicMock()
+        init = InitFile("city.json", "{}\n", 1)
+
+        location = upload_init(init, s3, "maps", "/terria/init/")
+
+        assert location == "s3://maps/terria/init/city.json"
+        s3.put_object.assert_called_once_with(
+            "maps", "terria/init/city.json", b"{}\n", content_type="application/json"
+        )
+        assert init.to_dict()["location"] == location
+
+    def test_upload_without_prefix(self):
+        init = InitFile("city.json", "{}\n")
+        assert upload_init(init, MagicMock(), "maps") == "s3://maps/city.json"
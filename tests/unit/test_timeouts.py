@@ -0,0 +1,100 @@
+"""Unit tests for timeouts by kind of request.
+
+Tests reading each kind's timeout from the config and sending uploads in
+chunks of a known length.
+"""
+
+from unittest.mock import MagicMock, patch
+
+import httpx
+
+from apps.core.config import Connection, TimeoutSettings
+from apps.core.oidc import BearerAuth
+from apps.core.timeouts import CONNECT_TIMEOUT, UPLOAD_CHUNK_SIZE, request_timeout, upload_body
+
+
+def _config(**timeouts) -> MagicMock:
+    manager = MagicMock()
+    manager.config.timeouts = TimeoutSettings(**timeouts)
+    return manager
+
+
+class TestRequestTimeout:
+    """Test timeouts for uploads, downloads and OWS requests."""
+
+    def test_from_config(self) -> None:
+        """Test each kind waits as long as the config says."""
+        with (
+            patch("apps.core.timeouts.config_manager", _config(upload=900)),
+            patch("apps.core.timeouts.httpx.Timeout") as timeout,
+        ):
+            request_timeout("upload")
+            request_timeout("ows")
+
+        assert [c.args for c in timeout.call_args_list] == [(900,), (60,)]
+        timeout.assert_called_with(60, connect=CONNECT_TIMEOUT)
+
+    def test_zero_waits_indefinitely(self) -> None:
+        """Test 0 leaves reads and writes without a timeout, but not connecting."""
+        with (
+            patch("apps.core.timeouts.config_manager", _config(download=0)),
+            patch("apps.core.timeouts.httpx.Timeout") as timeout,
+        ):
+            request_timeout("download")
+
+        timeout.assert_called_once_with(None, connect=CONNECT_TIMEOUT)
+
+
+class TestUploadBody:
+    """Test sending an upload in chunks."""
+
+    def test_chunks_with_length(self) -> None:
+        """Test the body is sent in chunks with its length, not chunked encoding."""
+        data = b"x" * (UPLOAD_CHUNK_SIZE * 2 + 5)
+
+        with patch("apps.core.timeouts.request_timeout") as timeout:
+            body = upload_body(data, {"Content-Type": "image/tiff"})
+
+        chunks = list(body["content"])
+        assert [len(c) for c in chunks] == [UPLOAD_CHUNK_SIZE, UPLOAD_CHUNK_SIZE, 5]
+        assert b"".join(chunks) == data
+        assert body["headers"] == {"Content-Type": "image/tiff", "Content-Length": str(len(data))}
+        assert body["timeout"] is timeout.return_value
+        timeout.assert_called_once_with("upload")
+
+    def test_sent_again_after_token_refresh(self) -> None:
+        """Test an upload refused for an expired token is sent again in full."""
+        data = b"x" * (UPLOAD_CHUNK_SIZE + 5)
+        bodies = []
+
+        class Transport(httpx.BaseTransport):
+            """Reads the body as it streams, as sending it over a socket does."""
+
+            def handle_request(self, request: httpx.Request) -> httpx.Response:
+                bodies.append(b"".join(request.stream))
+                return httpx.Response(401 if len(bodies) == 1 else 201)
+
+        def access_token(connection, force_refresh=False):
+            return "at-new" if force_refresh else "at-old"
+
+        connection = Connection(
+            id="sso",
+            name="SSO",
+            url="https://gs/geoserver",
+            username="",
+            password="",
+            auth_type="oidc",
+        )
+        with (
+            patch("apps.core.oidc.access_token", access_token),
+            patch("apps.core.timeouts.request_timeout", return_value=None),
+            httpx.Client(transport=Transport(), auth=BearerAuth(connection)) as client,
+        ):
+            response = client.put(
+                "https://gs/geoserver/rest/workspaces/topp/datastores/roads/file.shp",
+                **upload_body(data, {"Content-Type": "application/zip"}),
+            )
+
+        assert response.status_code == 201
+        assert response.request.headers["Authorization"] == "Bearer at-new"
+        assert bodies == [data, data]
@@ -0,0 +1,56 @@
+"""Unit tests for the TUI command palette's history."""
+
+from pathlib import Path
+
+from tui.command_history import MAX_RECENT, RECENT_BOOST, CommandHistory
+
+
+class TestCommandHistory:
+    """Test remembering the commands run from the palette."""
+
+    def test_most_recent_first(self, tmp_path: Path) -> None:
+        """Test a command run again moves to the front."""
+        history = CommandHistory(tmp_path / "history.json")
+
+        history.add("Map Preview")
+        history.add("Create Workspace")
+        history.add("Map Preview")
+
+        assert history.recent == ["Map Preview", "Create Workspace"]
+
+    def test_kept_between_sessions(self, tmp_path: Path) -> None:
+        """Test the history is read back from its file, up to the limit."""
+        path = tmp_path / "history.json"
+        history = CommandHistory(path)
+        for i in range(MAX_RECENT + 5):
+            history.add(f"Command {i}")
+
+        recent = CommandHistory(path).recent
+
+        assert len(recent) == MAX_RECENT
+        assert recent[0] == f"Command {MAX_RECENT + 4}"
+
+    def test_unreadable_file(self, tmp_path: Path) -> None:
+        """Test a missing or corrupt file gives an empty history."""
+        path = tmp_path / "history.json"
+        assert CommandHistory(path).recent == []
+
+        path.write_text("{not json")
+        assert CommandHistory(path).recent == []
+
+    def test_order_and_boost(self, tmp_path: Path) -> None:
+        """Test recent commands are listed first and ranked higher."""
+        history = CommandHistory(tmp_path / "history.json")
+        history.add("Open Settings")
+        history.add("Map Preview")
+
+        names = ["Create Workspace", "Open Settings", "Map Preview", "Gone"]
+        assert history.order(names) == [
+            "Map Preview",
+            "Open Settings",
+            "Create Workspace",
+            "Gone",
+        ]
+        assert history.boost("Map Preview") == RECENT_BOOST
+        assert 0 < history.boost("Open Settings") < RECENT_BOOST
+        assert history.boost("Create Workspace") == 0.0
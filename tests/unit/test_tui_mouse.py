@@ -0,0 +1,69 @@
+"""Unit tests for TUI mouse interaction."""
+
+import asyncio
+
+from textual.app import App, ComposeResult
+from textual.containers import Horizontal
+from textual.widgets import Static
+
+from tui.widgets import ResourceTreeWidget, Splitter
+
+
+class _SplitApp(App):
+    """Two panels with a splitter between them."""
+
+    def compose(self) -> ComposeResult:
+        """Lay out the panels."""
+        with Horizontal():
+            yield Static("left", id="left")
+            yield Splitter()
+            yield Static("right", id="right")
+
+
+class _TreeApp(App):
+    """A resource tree with one expandable node."""
+
+    def compose(self) -> ComposeResult:
+        """Add the tree."""
+        tree = ResourceTreeWidget(id="tree")
+        tree.root.expand()
+        tree.add_resource_node(tree.root, "topp", "workspace", expandable=True)
+        yield tree
+
+
+class TestSplitter:
+    """Tests for dragging the panel splitter."""
+
+    def test_resize_clamped(self) -> None:
+        """Test the left panel width stays within the limits."""
+
+        async def run() -> None:
+            app = _SplitApp()
+            async with app.run_test(size=(100, 20)):
+                splitter = app.query_one(Splitter)
+                assert splitter.resize_to(30) == 30
+                assert splitter.resize_to(2) == splitter.min_width
+                assert splitter.resize_to(500) == 99 - splitter.min_remaining
+
+        asyncio.run(run())
+
+
+class TestResourceTree:
+    """Tests for clicking tree nodes."""
+
+    def test_double_click_expands(self) -> None:
+        """Test a single click selects and a double click expands."""
+
+        async def run() -> None:
+            app = _TreeApp()
+            async with app.run_test() as pilot:
+                tree = app.query_one(ResourceTreeWidget)
+                node = tree.root.children[0]
+
+                await pilot.click("#tree", offset=(10, 2))
+                assert not node.is_expanded
+
+                await pilot.click("#tree", offset=(10, 2))
+                assert node.is_expanded
+
+        asyncio.run(run())
@@ -0,0 +1,54 @@
+"""Unit tests for the TUI notification center's log."""
+
+from tui.notification_log import NotificationEntry, NotificationLog
+
+
+class TestNotificationLog:
+    """Test keeping the notifications shown this session."""
+
+    def test_newest_first(self) -> None:
+        """Test notifications are listed newest first and counted unread."""
+        log = NotificationLog()
+        log.add("Upload finished")
+        log.add("Seed failed", "error", "topp:roads")
+
+        entries = log.entries()
+
+        assert [entry.message for entry in entries] == ["Seed failed", "Upload finished"]
+        assert entries[0].title == "topp:roads"
+        assert entries[0].time >= entries[1].time
+        assert log.unread == 2
+
+        log.mark_read()
+        assert log.unread == 0
+
+    def test_problems_only(self) -> None:
+        """Test warnings and errors can be listed alone."""
+        log = NotificationLog()
+        log.add("Refreshed")
+        log.add("Sync skipped 2 styles", "warning")
+        log.add("Seed failed", "error")
+
+        assert [entry.severity for entry in log.entries(problems_only=True)] == [
+            "error",
+            "warning",
+        ]
+
+    def test_oldest_dropped(self) -> None:
+        """Test only the most recent notifications are kept."""
+        log = NotificationLog(max_entries=3)
+        for i in range(5):
+            log.add(f"Job {i}")
+
+        assert [entry.message for entry in log.entries()] == ["Job 4", "Job 3", "Job 2"]
+        assert log.unread == 3
+
+        log.clear()
+        assert log.entries() == []
+        assert log.unread == 0
+
+    def test_text(self) -> None:
+        """Test plain mode's line of text spells out the severity."""
+        assert NotificationEntry("Refreshed").text == "Refreshed"
+        entry = NotificationEntry("Seed failed", "error", "topp:roads")
+        assert entry.text == "Error: topp:roads: Seed failed"
@@ -0,0 +1,34 @@
+"""Unit tests for TUI themes."""
+
+from apps.core.config import ThemeConfig
+from tui.styles.themes import BUILTIN_THEMES, DEFAULT_THEME, get_theme, theme_names
+
+
+class TestThemes:
+    """Tests for built-in and user-defined themes."""
+
+    def test_builtin_themes(self) -> None:
+        """Test the built-in themes are offered first."""
+        names = theme_names()
+        assert names[:2] == ["default", "dark"]
+        assert {"light", "high-contrast", "solarized"} <= set(names)
+
+    def test_custom_theme_fills_missing_colors(self) -> None:
+        """Test a user theme keeps its colors and borrows the rest."""
+        custom = ThemeConfig(name="ocean", primary="#006994", dark=False)
+
+        theme = get_theme("ocean", [custom])
+
+        assert theme.primary == "#006994"
+        assert theme.background == BUILTIN_THEMES["light"].background
+        assert "ocean" in theme_names([custom])
+
+    def test_unknown_theme_falls_back(self) -> None:
+        """Test an unknown theme name gives the default theme."""
+        assert get_theme("missing") == BUILTIN_THEMES[DEFAULT_THEME]
+
+    def test_css_variables(self) -> None:
+        """Test every theme generates the variables screens use."""
+        for theme in BUILTIN_THEMES.values():
+            variables = theme.css_variables()
+            assert variables["primary"] and variables["surface"] and variables["text-muted"]
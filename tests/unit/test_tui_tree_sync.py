@@ -0,0 +1,99 @@
+"""Unit tests for updating the TUI resource tree in place."""
+
+import asyncio
+
+from textual.app import App, ComposeResult
+
+from tui.widgets import ResourceTreeWidget
+
+
+class _MarkableTree(ResourceTreeWidget):
+    """A resource tree whose layers can be marked."""
+
+    MARKABLE_TYPES = frozenset({"layer"})
+
+
+class _TreeApp(App):
+    """An empty resource tree."""
+
+    def compose(self) -> ComposeResult:
+        """Add the tree."""
+        tree = _MarkableTree(id="tree")
+        tree.root.expand()
+        yield tree
+
+
+def _layers(*names: str) -> list[tuple[str, dict, bool]]:
+    return [(name, {"type": "layer", "workspace": "topp", "name": name}, False) for name in names]
+
+
+def _labels(tree: ResourceTreeWidget) -> list[str]:
+    return [str(node.label) for node in tree.root.children]
+
+
+class TestSyncChildren:
+    """Tests for ResourceTreeWidget.sync_children."""
+
+    def test_adds_and_removes_in_order(self) -> None:
+        """Test new children are added in listing order and gone ones removed."""
+
+        async def run() -> None:
+            app = _TreeApp()
+            async with app.run_test():
+                tree = app.query_one(_MarkableTree)
+                tree.sync_children(tree.root, _layers("b", "d"))
+                kept = tree.root.children[1]
+
+                nodes = tree.sync_children(tree.root, _layers("a", "c", "d", "e"))
+
+                assert _labels(tree) == ["a", "c", "d", "e"]
+                assert nodes[2] is kept
+
+        asyncio.run(run())
+
+    def test_kept_nodes_keep_state(self) -> None:
+        """Test listed nodes keep their expansion, children and marks."""
+
+        async def run() -> None:
+            app = _TreeApp()
+            async with app.run_test():
+                tree = app.query_one(_MarkableTree)
+                ws = tree.sync_children(
+                    tree.root, [("topp", {"type": "workspace", "name": "topp"}, True)]
+                )[0]
+                tree.sync_children(ws, _layers("roads", "rivers"))
+                ws.expand()
+                tree.toggle_mark(ws.children[0])
+                tree.toggle_mark(ws.children[1])
+
+                tree.sync_children(
+                    tree.root, [("topp (2)", {"type": "workspace", "name": "topp"}, True)]
+                )
+                tree.sync_children(ws, _layers("roads"))
+
+                assert tree.root.children == [ws]
+                assert ws.is_expanded
+                assert str(ws.label) == "topp (2)"
+                assert tree.marked_nodes == [ws.children[0]]
+
+        asyncio.run(run())
+
+    def test_cursor_follows_its_node(self) -> None:
+        """Test the cursor stays on its node when nodes above it are added."""
+
+        async def run() -> None:
+            app = _TreeApp()
+            async with app.run_test() as pilot:
+                tree = app.query_one(_MarkableTree)
+                tree.sync_children(tree.root, _layers("m", "z"))
+                await pilot.pause()
+                target = tree.root.children[1]
+                tree.move_cursor(target)
+
+                with tree.keeping_cursor():
+                    tree.sync_children(tree.root, _layers("a", "b", "m", "z"))
+                await pilot.pause()
+
+                assert tree.cursor_node is target
+
+        asyncio.run(run())
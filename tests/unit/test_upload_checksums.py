@@ -0,0 +1,148 @@
+"""Unit tests for checksum verification of uploads and exports."""
+
+import hashlib
+import io
+import zipfile
+from pathlib import Path
+from unittest.mock import MagicMock
+
+import pytest
+
+from apps.core.checksum import checksum_path, verify_checksum_file, write_checksum_file
+from apps.core.exceptions import GeoServerError
+from apps.upload.verify import expected_files, store_location, verify_upload
+
+
+def _zip(**members: bytes) -> bytes:
+    buffer = io.BytesIO()
+    with zipfile.ZipFile(buffer, "w") as archive:
+        for name, data in members.items():
+            archive.writestr(f"roads/{name.replace('_', '.')}", data)
+    return buffer.getvalue()
+
+
+def _client(files: dict[str, bytes]) -> MagicMock:
+    """A client whose data directory holds some files."""
+    client = MagicMock()
+
+    def info(path: str) -> dict:
+        if path not in files:
+            return {"type": "undefined", "size": 0}
+        return {"type": "resource", "size": len(files[path])}
+
+    client.get_resource_info.side_effect = info
+    # Downloads arrive in pieces
+    client.iter_resource.side_effect = lambda path: iter([files[path][:3], files[path][3:]])
+    return client
+
+
+class TestChecksumFiles:
+    """Tests for checksum files written next to exports."""
+
+    def test_round_trip(self, tmp_path: Path) -> None:
+        """Test a written checksum file verifies, in sha256sum's format."""
+        path = tmp_path / "backup.zip"
+        path.write_bytes(b"archive")
+
+        written = write_checksum_file(path)
+
+        digest = hashlib.sha256(b"archive").hexdigest()
+        assert written == checksum_path(path)
+        assert written.read_text() == f"{digest}  backup.zip\n"
+        assert verify_checksum_file(path) is True
+
+    def test_changed_file(self, tmp_path: Path) -> None:
+        """Test a file changed after export fails."""
+        path = tmp_path / "backup.zip"
+        path.write_bytes(b"archive")
+        write_checksum_file(path)
+        path.write_bytes(b"archivf")
+
+        assert verify_checksum_file(path) is False
+
+    def test_missing_and_invalid(self, tmp_path: Path) -> None:
+        """Test files without a checksum file and unreadable checksum files."""
+        path = tmp_path / "backup.zip"
+        path.write_bytes(b"archive")
+
+        assert verify_checksum_file(path) is None
+        checksum_path(path).write_text("not a checksum")
+        with pytest.raises(ValueError, match="not a SHA-256"):
+            verify_checksum_file(path)
+
+
+class TestExpectedFiles:
+    """Tests for listing what an upload stores."""
+
+    def test_zip_members(self) -> None:
+        """Test zipped shapefiles are listed by member, without folders."""
+        files = expected_files("roads.zip", _zip(roads_shp=b"shp", roads_dbf=b"dbf"))
+
+        assert [(f.name, f.size) for f in files] == [("roads.dbf", 3), ("roads.shp", 3)]
+        assert files[1].expected == hashlib.sha256(b"shp").hexdigest()
+
+
+class TestVerifyUpload:
+    """Tests for comparing uploads with the stored files."""
+
+    def test_geotiff_renamed_by_geoserver(self) -> None:
+        """Test the coverage store's file is compared whatever it's called."""
+        client = _client({"data/topp/dem/dem.geotiff": b"tif"})
+        client.get_coveragestore.return_value = {"url": "file:data/topp/dem/dem.geotiff"}
+
+        verification = verify_upload(client, "topp", "dem", "coveragestore", "DEM.tif", b"tif")
+
+        assert verification.verified
+        assert verification.files[0].name == "dem.geotiff"
+
+    def test_shapefile_members(self) -> None:
+        """Test each unpacked shapefile file is compared, and differences reported."""
+        client = _client({"data/topp/roads/roads.shp": b"shp", "data/topp/roads/roads.dbf": b"dbx"})
+        client.get_datastore.return_value = {
+            "connectionParameters": {"entry": [{"@key": "url", "$": "file:data/topp/roads/"}]}
+        }
+        data = _zip(roads_shp=b"shp", roads_dbf=b"dbf", roads_prj=b"prj")
+
+        verification = verify_upload(client, "topp", "roads", "datastore", "roads.zip", data)
+
+        statuses = {f.name: f.status for f in verification.files}
+        assert statuses == {"roads.dbf": "mismatch", "roads.prj": "missing", "roads.shp": "match"}
+        assert not verification.verified
+
+    def test_size_checked_before_download(self) -> None:
+        """Test files of the wrong size aren't downloaded."""
+        client = _client({"data/topp/osm/osm.gpkg": b"short"})
+        entry = {"@key": "database", "$": "file:data/topp/osm/osm.gpkg"}
+        client.get_datastore.return_value = {"connectionParameters": {"entry": entry}}
+
+        verification = verify_upload(client, "topp", "osm", "datastore", "osm.gpkg", b"longer")
+
+        assert verification.files[0].status == "mismatch"
+        client.iter_resource.assert_not_called()
+
+    def test_outside_data_directory(self) -> None:
+        """Test stores pointing outside the data directory can't be checked."""
+        client = MagicMock()
+        client.get_coveragestore.return_value = {"url": "file:/mnt/rasters/dem.tif"}
+
+        verification = verify_upload(client, "topp", "dem", "coveragestore", "dem.tif", b"tif")
+
+        assert verification.error == "The store's files are outside the data directory"
+        assert not verification.verified
+
+    def test_resource_api_unavailable(self) -> None:
+        """Test servers without the resource API report why nothing was checked."""
+        client = MagicMock()
+        client.get_coveragestore.return_value = {"url": "file:data/topp/dem/dem.geotiff"}
+        client.get_resource_info.side_effect = GeoServerError("Needs GeoServer 2.9", 501)
+
+        verification = verify_upload(client, "topp", "dem", "coveragestore", "dem.tif", b"tif")
+
+        assert verification.error == "Needs GeoServer 2.9"
+
+    def test_store_location_rejects_parent_paths(self) -> None:
+        """Test relative paths climbing out of the data directory are refused."""
+        client = MagicMock()
+        client.get_coveragestore.return_value = {"url": "file:data/../../etc/dem.tif"}
+
+        assert store_location(client, "topp", "dem", "coveragestore") is None
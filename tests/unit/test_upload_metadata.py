@@ -0,0 +1,160 @@
+"""Unit tests for suggested layer metadata of uploads."""
+
+from unittest.mock import MagicMock, patch
+
+from apps.core.exceptions import GeoServerError
+from apps.upload.batch import BatchItem, publish_item
+from apps.upload.metadata import LayerMetadata, apply_metadata, prettify_name, suggest_metadata
+from apps.upload.preview import FileSummary, LayerSummary
+
+
+class TestPrettifyName:
+    """Tests for titles made from file names."""
+
+    def test_separators_and_extensions(self) -> None:
+        """Test separators become spaces and upload extensions are dropped."""
+        assert prettify_name("data/main_roads-2024.shp.zip") == "Main Roads 2024"
+        assert prettify_name("dem.tiff") == "Dem"
+
+    def test_camel_case_and_acronyms(self) -> None:
+        """Test camel case is split and words in capitals are kept."""
+        assert prettify_name("OSM_landUse.gpkg") == "OSM Land Use"
+
+
+class TestSuggestMetadata:
+    """Tests for suggesting a layer's title, abstract and keywords."""
+
+    def test_from_name_only(self) -> None:
+        """Test a file that wasn't read is described by its name and folders."""
+        metadata = suggest_metadata("data/Transport/roads.zip", "shapefile")
+
+        assert metadata.title == "Roads"
+        assert metadata.abstract == "Published from roads.zip."
+        # Generic folders say nothing about the data
+        assert metadata.keywords == ["transport", "vector", "shapefile"]
+
+    def test_vector_summary(self) -> None:
+        """Test feature counts, geometry, CRS and extent go into the abstract."""
+        summary = FileSummary(
+            name="roads.zip",
+            kind="shapefile",
+            crs="EPSG:4326",
+            extent=(18.0, -34.5, 19.5, -33.25),
+            layers=[LayerSummary("roads", "LineString", 1200)],
+        )
+
+        metadata = suggest_metadata("roads.zip", "shapefile", summary)
+
+        assert metadata.abstract == (
+            "Published from roads.zip. 1,200 LineString features. "
+            "Coordinate reference system: EPSG:4326. Extent: 18, -34.5, 19.5, -33.25."
+        )
+        assert metadata.keywords == ["vector", "shapefile", "linestring"]
+
+    def test_raster_summary(self) -> None:
+        """Test the raster size and bands are described."""
+        summary = FileSummary(
+            name="dem.tif", kind="geotiff", size=(4000, 3000), bands=["Band 1: Float32"]
+        )
+
+        metadata = suggest_metadata("dem.tif", "geotiff", summary)
+
+        assert "Raster of 4,000 x 3,000 pixels in 1 band(s)." in metadata.abstract
+
+    def test_unreadable_file(self) -> None:
+        """Test a file GDAL couldn't read only gets its name in the abstract."""
+        summary = FileSummary(name="dem.tif", kind="geotiff", crs="EPSG:4326", error="bad")
+
+        assert suggest_metadata("dem.tif", "geotiff", summary).abstract == (
+            "Published from dem.tif."
+        )
+
+
+class TestLayerMetadata:
+    """Tests for metadata sent by clients."""
+
+    def test_from_dict(self) -> None:
+        """Test fields are trimmed and keywords may be a comma-separated string."""
+        metadata = LayerMetadata.from_dict(
+            {"title": " Roads ", "keywords": "roads, , transport"}
+        )
+
+        assert metadata.title == "Roads"
+        assert metadata.abstract == ""
+        assert metadata.keywords == ["roads", "transport"]
+
+    def test_merged_keeps_edits(self) -> None:
+        """Test only the empty fields are filled from a suggestion."""
+        edited = LayerMetadata(title="Main roads")
+        suggestion = LayerMetadata("Roads", "Published from roads.zip.", ["vector"])
+
+        assert edited.merged(suggestion) == LayerMetadata(
+            "Main roads", "Published from roads.zip.", ["vector"]
+        )
+
+    def test_to_resource(self) -> None:
+        """Test empty fields are left out of the update."""
+        assert LayerMetadata(keywords=["roads"]).to_resource() == {
+            "keywords": {"string": ["roads"]}
+        }
+        assert LayerMetadata().is_empty
+
+
+class TestApplyMetadata:
+    """Tests for setting metadata on published layers."""
+
+    def test_every_coverage_of_store(self) -> None:
+        """Test each coverage of a store is updated when no layer is named."""
+        client = MagicMock()
+        client.list_coverages.return_value = [{"name": "dem"}]
+
+        names = apply_metadata(
+            client, "topp", "dem", "coveragestore", LayerMetadata(title="Elevation")
+        )
+
+        assert names == ["dem"]
+        client.update_coverage.assert_called_once_with(
+            "topp", "dem", "dem", {"title": "Elevation"}
+        )
+
+    def test_empty_metadata(self) -> None:
+        """Test nothing is sent when there is nothing to set."""
+        client = MagicMock()
+
+        assert apply_metadata(client, "topp", "roads", "datastore", LayerMetadata()) == []
+        client.list_featuretypes.assert_not_called()
+
+
+class TestBatchMetadata:
+    """Tests for metadata of files published in a batch."""
+
+    @patch("apps.upload.batch.invalidate_store_cache", return_value=[])
+    def test_edited_title_kept(self, _invalidate: MagicMock) -> None:
+        """Test a title edited in the mapping is set, with the rest suggested."""
+        client = MagicMock()
+        item = BatchItem("dem.tif", "geotiff", "topp", "dem", "dem", title="Elevation")
+
+        publish_item(client, "conn", item, b"tif")
+
+        client.update_coverage.assert_called_once_with(
+            "topp",
+            "dem",
+            "dem",
+            {
+                "title": "Elevation",
+                "abstract": "Published from dem.tif.",
+                "keywords": {"string": ["raster", "geotiff"]},
+            },
+        )
+
+    @patch("apps.upload.batch.invalidate_store_cache", return_value=[])
+    def test_failure_is_a_warning(self, _invalidate: MagicMock) -> None:
+        """Test a layer that couldn't be updated doesn't fail the upload."""
+        client = MagicMock()
+        client.update_coverage.side_effect = GeoServerError("denied", status_code=403)
+        item = BatchItem("dem.tif", "geotiff", "topp", "dem", "dem")
+
+        result = publish_item(client, "conn", item, b"tif")
+
+        assert result["warning"] == "Layer metadata was not set: denied"
+        assert result["store"] == "dem"
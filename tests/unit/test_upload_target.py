@@ -0,0 +1,91 @@
+"""Unit tests for choosing the store an upload goes into."""
+
+from unittest.mock import MagicMock
+
+from apps.core.exceptions import GeoServerError
+from apps.upload.batch import BatchItem
+from apps.upload.target import (
+    MODE_APPEND,
+    MODE_CREATE,
+    MODE_REPLACE,
+    check_targets,
+    default_store_name,
+    existing_stores,
+    resolve_target,
+)
+
+EXISTING = {"roads": "datastore", "dem": "coveragestore"}
+
+
+class TestResolveTarget:
+    """Tests for previewing where an upload goes."""
+
+    def test_new_store_named_after_file(self) -> None:
+        """Test an empty store name creates a store named after the file."""
+        target = resolve_target("Main Roads.shp.zip", "topp", "", EXISTING)
+
+        assert (target.store, target.mode) == ("main_roads", MODE_CREATE)
+        assert target.layer == "Main Roads" and target.error is None
+
+    def test_append_to_data_store(self) -> None:
+        """Test files chosen for an existing data store are appended."""
+        target = resolve_target("rivers.zip", "topp", "roads", EXISTING)
+
+        assert target.mode == MODE_APPEND and target.layer == "rivers"
+
+    def test_replace_coverage_store(self) -> None:
+        """Test a GeoTIFF chosen for an existing coverage store replaces its file."""
+        target = resolve_target("dem_2024.tif", "topp", "dem", EXISTING)
+
+        assert target.mode == MODE_REPLACE and target.layer == "dem"
+
+    def test_geopackage_layers_chosen_later(self) -> None:
+        """Test GeoPackages have no layer to preview."""
+        target = resolve_target("osm.gpkg", "topp", "osm", EXISTING)
+
+        assert target.store_type == "datastore" and target.layer is None
+
+    def test_name_taken_by_other_store_type(self) -> None:
+        """Test store names are unique across data and coverage stores."""
+        target = resolve_target("roads.tif", "topp", "roads", EXISTING)
+
+        assert target.error == "roads is already a data store in topp"
+
+    def test_invalid_names_and_files(self) -> None:
+        """Test bad store names and unsupported files are reported."""
+        assert "letter or underscore" in resolve_target("a.zip", "topp", "1 roads", {}).error
+        assert "Unsupported" in resolve_target("notes.txt", "topp", "", {}).error
+
+    def test_default_store_name(self) -> None:
+        """Test file suffixes are dropped and the name cleaned."""
+        assert default_store_name("data/DEM 2024.tiff") == "dem_2024"
+
+
+class TestExistingStores:
+    """Tests for reading the stores of a workspace."""
+
+    def test_both_store_types(self) -> None:
+        """Test data and coverage stores are listed together."""
+        client = MagicMock()
+        client.list_datastores.return_value = [{"name": "roads"}]
+        client.list_coveragestores.return_value = [{"name": "dem"}]
+
+        assert existing_stores(client, "topp") == EXISTING
+
+    def test_batch_targets(self) -> None:
+        """Test each batch file is checked, styles skipped, new workspaces empty."""
+        client = MagicMock()
+        client.list_datastores.side_effect = [[{"name": "roads"}], GeoServerError("gone", 404)]
+        client.list_coveragestores.return_value = []
+        items = [
+            BatchItem("roads.zip", "shapefile", "topp", store="roads", layer="roads"),
+            BatchItem("line.sld", "style", "topp", layer="line"),
+            BatchItem("dem.tif", "geotiff", "new", store="dem", layer="dem"),
+        ]
+
+        targets = check_targets(client, items)
+
+        assert {path: t.mode for path, t in targets.items()} == {
+            "roads.zip": MODE_APPEND,
+            "dem.tif": MODE_CREATE,
+        }
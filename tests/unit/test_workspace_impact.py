@@ -0,0 +1,92 @@
+"""Unit tests for recursive delete impact reports and cache cleanup."""
+
+from unittest.mock import MagicMock, patch
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.impact import WorkspaceImpact, workspace_impact
+from apps.gwc.invalidation import (
+    cached_workspace_layers,
+    clear_store_caches,
+    clear_workspace_caches,
+)
+
+
+class TestWorkspaceImpact:
+    """Tests for collecting what a workspace delete removes."""
+
+    def test_collects_all_resources(self) -> None:
+        """Test stores, layers, styles, groups and caches are listed with counts."""
+        client = MagicMock()
+        client.list_datastores.return_value = [{"name": "roads"}]
+        client.list_coveragestores.return_value = [{"name": "dem"}]
+        client.list_layers.return_value = [{"name": "roads"}, {"name": "dem"}]
+        client.list_styles.return_value = [{"name": "line"}]
+        client.list_layergroups.return_value = []
+
+        impact = workspace_impact(client, "topp", ["topp:roads"])
+        data = impact.to_dict()
+
+        assert data["stores"] == [
+            {"name": "roads", "type": "datastore"},
+            {"name": "dem", "type": "coveragestore"},
+        ]
+        assert data["layers"] == ["dem", "roads"]
+        assert data["counts"] == {
+            "stores": 2,
+            "layers": 2,
+            "styles": 1,
+            "layerGroups": 0,
+            "caches": 1,
+        }
+
+    def test_empty_workspace(self) -> None:
+        """Test a workspace without resources is reported empty."""
+        assert WorkspaceImpact("topp").is_empty
+        assert not WorkspaceImpact("topp", styles=["line"]).is_empty
+
+
+class TestCacheCleanup:
+    """Tests for clearing tile caches before recursive deletes."""
+
+    def test_cached_layers_filtered_by_workspace(self) -> None:
+        """Test only the workspace's tile layers are picked."""
+        gwc = MagicMock()
+        gwc.list_layers.return_value = ["topp:roads", "topp2:roads", "topp:group", "nurc:dem"]
+
+        with patch("apps.gwc.invalidation.get_gwc_client", return_value=gwc):
+            assert cached_workspace_layers("conn_1", "topp") == ["topp:roads", "topp:group"]
+
+    def test_workspace_caches_cleared(self) -> None:
+        """Test seeding stops and each cached layer is truncated."""
+        gwc = MagicMock()
+        gwc.list_layers.return_value = ["topp:roads", "topp:rivers"]
+        gwc.kill_seed_tasks.side_effect = GeoServerError("not seeding", status_code=400)
+
+        with patch("apps.gwc.invalidation.get_gwc_client", return_value=gwc):
+            cleared = clear_workspace_caches("conn_1", "topp")
+
+        assert cleared == ["topp:roads", "topp:rivers"]
+        assert gwc.kill_seed_tasks.call_count == 2
+        gwc.truncate_entire_layer.assert_any_call("topp:rivers")
+
+    def test_workspace_cleanup_never_raises(self) -> None:
+        """Test an unreachable GWC doesn't block the delete."""
+        gwc = MagicMock()
+        gwc.list_layers.side_effect = GeoServerError("GWC HTTP error")
+
+        with patch("apps.gwc.invalidation.get_gwc_client", return_value=gwc):
+            assert clear_workspace_caches("conn_1", "topp") == []
+
+    def test_store_caches_cleared(self) -> None:
+        """Test the layers of a coverage store have their caches cleared."""
+        geoserver = MagicMock()
+        geoserver.list_coverages.return_value = [{"name": "dem"}]
+        gwc = MagicMock()
+
+        with patch("apps.gwc.invalidation.get_geoserver_client", return_value=geoserver), patch(
+            "apps.gwc.invalidation.get_gwc_client", return_value=gwc
+        ):
+            cleared = clear_store_caches("conn_1", "nurc", "dem", "coveragestore")
+
+        assert cleared == ["nurc:dem"]
+        gwc.truncate_entire_layer.assert_called_once_with("nurc:dem")
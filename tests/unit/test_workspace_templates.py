@@ -0,0 +1,164 @@
+"""Unit tests for creating workspaces from templates."""
+
+from unittest.mock import MagicMock, patch
+
+import pytest
+
+from apps.core.config import TemplateDataStore, WorkspaceTemplate
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import CreateResult
+from apps.geoserver.workspace_templates import (
+    BUILTIN_TEMPLATES,
+    create_workspace_from_template,
+    get_template,
+    list_templates,
+    template_to_dict,
+)
+
+
+def _template(**fields) -> WorkspaceTemplate:
+    return WorkspaceTemplate(name="project", **fields)
+
+
+class TestTemplateLookup:
+    """Tests for listing configured and built-in templates."""
+
+    def test_builtin_listed(self) -> None:
+        """Test the built-in template is offered without configured ones."""
+        with patch("apps.geoserver.workspace_templates.config_manager") as manager:
+            manager.config.workspace_templates = []
+
+            assert [t.name for t in list_templates()] == ["standard project"]
+
+    def test_configured_replaces_builtin(self) -> None:
+        """Test a configured template hides the built-in one of the same name."""
+        custom = WorkspaceTemplate(name="standard project", services=["wms"])
+        with patch("apps.geoserver.workspace_templates.config_manager") as manager:
+            manager.config.workspace_templates = [custom, _template()]
+
+            assert [t.name for t in list_templates()] == ["standard project", "project"]
+            assert get_template("standard project") is custom
+            assert get_template("missing") is None
+
+    def test_serialized_without_passwords(self) -> None:
+        """Test a template's stores are listed by name only."""
+        data = template_to_dict(BUILTIN_TEMPLATES[0])
+
+        assert data["datastores"] == ["postgis"]
+        assert "passwd" not in str(data)
+
+
+class TestCreateFromTemplate:
+    """Tests for setting up a workspace as a template describes."""
+
+    def test_all_steps(self) -> None:
+        """Test services, contact, stores and styles are set up in order."""
+        client = MagicMock()
+        client.get_style_content.return_value = ("<sld/>", "sld")
+        template = _template(
+            isolated=True,
+            services=["wms"],
+            contact={"contactOrganization": "Kartoza"},
+            datastores=[
+                TemplateDataStore(name="postgis", connection_parameters={"schema": "{workspace}"})
+            ],
+            styles=["line"],
+        )
+
+        steps = create_workspace_from_template(client, "roads", template)
+
+        client.create_workspace.assert_called_once_with("roads", isolated=True, if_absent=False)
+        client.update_workspace_service.assert_any_call("roads", "wms", True)
+        client.update_workspace_service.assert_any_call("roads", "wfs", False)
+        client.update_workspace_contact.assert_called_once_with(
+            "roads", {"contactOrganization": "Kartoza"}
+        )
+        client.create_datastore.assert_called_once_with(
+            "roads",
+            "postgis",
+            {"schema": "roads"},
+            description="",
+            enabled=False,
+            if_absent=False,
+        )
+        client.create_style.assert_called_once_with(
+            "line", "<sld/>", "sld", workspace="roads", if_absent=False
+        )
+        assert [(s.kind, s.name) for s in steps] == [
+            ("workspace", "roads"),
+            ("service", "wms"),
+            ("service", "wfs"),
+            ("service", "wcs"),
+            ("service", "wmts"),
+            ("contact", "roads"),
+            ("datastore", "postgis"),
+            ("style", "line"),
+        ]
+        assert all(s.ok for s in steps)
+
+    def test_overrides(self) -> None:
+        """Test isolation and services given by the caller win over the template's."""
+        client = MagicMock()
+
+        create_workspace_from_template(
+            client, "roads", _template(isolated=True), isolated=False, services=["wcs"]
+        )
+
+        client.create_workspace.assert_called_once_with(
+            "roads", isolated=False, if_absent=False
+        )
+        client.update_workspace_service.assert_any_call("roads", "wms", False)
+        client.update_workspace_service.assert_any_call("roads", "wcs", True)
+
+    def test_failed_step_recorded(self) -> None:
+        """Test a failed step is reported and the rest still run."""
+        client = MagicMock()
+        client.get_style_content.side_effect = [
+            GeoServerError("No such style: house", 404),
+            ("<sld/>", "sld"),
+        ]
+
+        steps = create_workspace_from_template(
+            client, "roads", _template(styles=["house", "line"])
+        )
+
+        failed = [s.to_dict() for s in steps if not s.ok]
+        assert failed == [
+            {"kind": "style", "name": "house", "ok": False, "error": "No such style: house"}
+        ]
+        client.create_style.assert_called_once_with(
+            "line", "<sld/>", "sld", workspace="roads", if_absent=False
+        )
+
+    def test_workspace_failure_raises(self) -> None:
+        """Test nothing else is attempted when the workspace can't be created."""
+        client = MagicMock()
+        client.create_workspace.side_effect = GeoServerError("already exists", 409)
+
+        with pytest.raises(GeoServerError, match="already exists"):
+            create_workspace_from_template(client, "topp", _template(styles=["line"]))
+
+        client.update_workspace_service.assert_not_called()
+        client.create_style.assert_not_called()
+
+    def test_applied_again(self) -> None:
+        """Test with if_absent an existing workspace only gets what it lacks."""
+        client = MagicMock()
+        client.get_style_content.return_value = ("<sld/>", "sld")
+        client.create_workspace.return_value = CreateResult.ALREADY_EXISTS
+        client.create_style.side_effect = [CreateResult.ALREADY_EXISTS, CreateResult.CREATED]
+
+        steps = create_workspace_from_template(
+            client, "roads", _template(styles=["house", "line"]), if_absent=True
+        )
+
+        existed = [(s.kind, s.name) for s in steps if s.existed]
+        assert existed == [("workspace", "roads"), ("style", "house")]
+        assert all(s.ok for s in steps)
+        assert steps[0].to_dict() == {
+            "kind": "workspace",
+            "name": "roads",
+            "ok": True,
+            "existed": True,
+        }
+        client.create_workspace.assert_called_once_with("roads", isolated=False, if_absent=True)
@@ -0,0 +1,113 @@
+"""Unit tests for per-workspace storage usage."""
+
+from unittest.mock import MagicMock
+
+from apps.core.exceptions import GeoServerError
+from apps.dashboard.workspace_usage import workspace_usage
+from apps.gwc.usage import DiskQuotaUsage, LayerQuotaUsage
+
+FILES = {
+    "data/topp/dem/dem.geotiff": 5000,
+    "data/topp/roads.gpkg": 700,
+    "data/nurc/mosaic/a.tif": 300,
+    "data/nurc/mosaic/b.tif": 200,
+}
+
+
+def _resource_info(path: str) -> dict:
+    if path in FILES:
+        return {"type": "resource", "size": FILES[path]}
+    if path in ("", "data/nurc/mosaic"):
+        return {"type": "directory", "size": 0}
+    return {"type": "undefined", "size": 0}
+
+
+def _client() -> MagicMock:
+    client = MagicMock()
+    client.list_workspaces.return_value = [{"name": "topp"}, {"name": "nurc"}]
+    client.list_coveragestores.side_effect = lambda ws: (
+        [{"name": "dem"}] if ws == "topp" else [{"name": "mosaic"}]
+    )
+    client.list_datastores.side_effect = lambda ws: (
+        [{"name": "roads"}, {"name": "postgis"}] if ws == "topp" else []
+    )
+    client.get_coveragestore.side_effect = lambda ws, name: {
+        "url": "file:data/topp/dem/dem.geotiff" if name == "dem" else "file:data/nurc/mosaic"
+    }
+
+    def datastore(ws, name):
+        location = "file:data/topp/roads.gpkg" if name == "roads" else "gis"
+        return {"connectionParameters": {"entry": [{"@key": "database", "$": location}]}}
+
+    client.get_datastore.side_effect = datastore
+    client.get_resource_info.side_effect = _resource_info
+    client.list_resource_directory.side_effect = lambda path: ["a.tif", "b.tif"]
+    return client
+
+
+def _quota() -> DiskQuotaUsage:
+    return DiskQuotaUsage(
+        enabled=True,
+        layers=[
+            LayerQuotaUsage("topp:roads", used_bytes=100),
+            LayerQuotaUsage("nurc:mosaic", used_bytes=9000),
+            LayerQuotaUsage("nurc:unknown"),
+            LayerQuotaUsage("basemap", used_bytes=50),
+        ],
+    )
+
+
+class TestWorkspaceUsage:
+    """Test adding up tile caches and store files per workspace."""
+
+    def test_usage(self):
+        report = workspace_usage(_client(), _quota(), "conn_1")
+
+        by_name = {usage.workspace: usage for usage in report.workspaces}
+        assert [usage.workspace for usage in report.workspaces] == ["nurc", "topp", ""]
+
+        topp = by_name["topp"]
+        assert topp.tile_cache_bytes == 100
+        assert topp.store_bytes == 5700
+        assert topp.sized_stores == 2
+        assert topp.external_stores == 1
+        assert topp.complete
+
+        nurc = by_name["nurc"]
+        assert nurc.tile_cache_bytes == 9000
+        assert nurc.cached_layers == 1
+        assert nurc.store_bytes == 500
+        assert nurc.total_bytes == 9500
+
+        # Global layer groups only have a tile cache
+        assert by_name[""].total_bytes == 50
+        assert report.total_bytes == 9500 + 5800 + 50
+        assert report.to_dict()["workspaces"][0]["totalBytes"] == 9500
+
+    def test_without_disk_quota(self):
+        report = workspace_usage(_client(), None, tile_cache_error="Disk quota disabled")
+
+        assert report.tile_cache_error == "Disk quota disabled"
+        assert all(usage.tile_cache_bytes == 0 for usage in report.workspaces)
+        assert report.total_bytes == 6200
+
+    def test_without_resource_api(self):
+        client = _client()
+        client.get_resource_info.side_effect = GeoServerError("Requires 2.9", 501)
+
+        report = workspace_usage(client, _quota())
+
+        assert report.store_error == "Requires 2.9"
+        assert report.total_bytes == 9150
+        client.list_coveragestores.assert_not_called()
+
+    def test_unreadable_store(self):
+        client = _client()
+        client.get_coveragestore.side_effect = GeoServerError("Forbidden", 403)
+
+        report = workspace_usage(client, None)
+
+        topp = next(usage for usage in report.workspaces if usage.workspace == "topp")
+        assert not topp.complete
+        assert topp.store_bytes == 700
+        assert report.errors == ["topp:dem: Forbidden", "nurc:mosaic: Forbidden"]
@@ -1,8 +1,12 @@
 #!/usr/bin/env python
 """Entry point for the Kartoza CloudBench TUI."""
 
+import os
+
 import click
 
+from apps.core.config import PLAIN_ENV
+
 from .app import CloudBenchApp
 
 
@@ -11,8 +15,29 @@ from .app import CloudBenchApp
     "--config",
     "-c",
     type=click.Path(),
+    envvar="CLOUDBENCH_CONFIG",
     help="Path to config file (default: ~/.config/kartoza-cloudbench/config.json)",
 )
+@click.option(
+    "--profile",
+    "-p",
+    envvar="CLOUDBENCH_PROFILE",
+    help="Named config profile, stored in ~/.config/kartoza-cloudbench/profiles/",
+)
+@click.option("--gs-url", help="GeoServer URL for a one-off connection that is not saved")
+@click.option("--gs-user", default="", help="Username for --gs-url")
+@click.option(
+    "--gs-password",
+    default="",
+    help="Password for --gs-url (prefer the GSCLIENT_PASSWORD environment variable)",
+)
+@click.option(
+    "--plain",
+    is_flag=True,
+    envvar=PLAIN_ENV,
+    help="Screen reader friendly: no animations or glyphs, full-screen dialogs, "
+    "notifications as text lines",
+)
 @click.option(
     "--debug",
     "-d",
@@ -20,7 +45,15 @@ from .app import CloudBenchApp
     help="Enable debug mode with verbose logging",
 )
 @click.version_option(version="0.3.0", prog_name="kartoza-cloudbench-tui")
-def main(config: str | None, debug: bool):
+def main(
+    config: str | None,
+    profile: str | None,
+    gs_url: str | None,
+    gs_user: str,
+    gs_password: str,
+    plain: bool,
+    debug: bool,
+):
     """Kartoza CloudBench - Geospatial Infrastructure Management TUI.
 
     A beautiful terminal interface for managing GeoServer, PostgreSQL/PostGIS,
@@ -28,7 +61,11 @@ def main(config: str | None, debug: bool):
 
     Made with love by Kartoza | https://kartoza.com
     """
-    app = CloudBenchApp(config_path=config, debug=debug)
+    if plain:
+        os.environ[PLAIN_ENV] = "1"
+    app = CloudBenchApp(config_path=config, profile=profile, debug=debug)
+    if gs_url:
+        app.config_manager.set_flag_connection(gs_url, gs_user, gs_password)
     app.run()
 
 
@@ -2,18 +2,32 @@
 
 from textual.app import App, ComposeResult
 from textual.binding import Binding
+from textual.notifications import SeverityLevel
 from textual.containers import Container, Horizontal, Vertical
 from textual.widgets import Footer, Header, Static, Tree
 from textual.widgets.tree import TreeNode
 
-from apps.core.config import ConfigManager, Connection
+from apps.core.config import DEFAULT_PROFILE, ConfigManager, Connection
+from apps.core.connection_groups import group_connections
 
+from .notification_log import NotificationEntry, NotificationLog
+from .palette import CloudBenchCommands
+from .screens.batch_upload import BatchUploadScreen
+from .screens.cache_schedules import CacheSchedulesScreen
+from .screens.compare import CompareScreen
 from .screens.connections import ConnectionsScreen
+from .screens.default_styles import DefaultStylesScreen
 from .screens.geoserver import GeoServerScreen
 from .screens.home import HomeScreen
+from .screens.hooks import HooksScreen
+from .screens.lint import LintScreen
+from .screens.notifications import NotificationsScreen
 from .screens.postgres import PostgresScreen
 from .screens.s3 import S3Screen
 from .screens.settings import SettingsScreen
+from .screens.settings_sync import SettingsSyncScreen
+from .screens.snapshot_schedules import SnapshotSchedulesScreen
+from .styles import accessible_mode, connection_label, get_theme, group_label
 
 
 class Sidebar(Container):
@@ -88,7 +102,8 @@ class StatusBar(Static):
 
     def compose(self) -> ComposeResult:
         """Create status bar content."""
-        yield Static("Ready | Made with \u2764 by Kartoza", id="status-text")
+        love = "love" if accessible_mode() else "\u2764"
+        yield Static(f"Ready | Made with {love} by Kartoza", id="status-text")
 
 
 class CloudBenchApp(App):
@@ -122,6 +137,34 @@ class CloudBenchApp(App):
     .hidden {
         display: none;
     }
+
+    /* Plain mode: dialogs fill the screen and read top to bottom instead of
+       floating over it, toasts become lines of text and bars are left out */
+    App.-plain ModalScreen {
+        align: left top;
+        background: $background;
+    }
+
+    App.-plain ModalScreen > Vertical, App.-plain ModalScreen > VerticalScroll {
+        width: 100%;
+        height: 100%;
+        border: none;
+    }
+
+    App.-plain ToastRack {
+        align: left bottom;
+    }
+
+    App.-plain Toast {
+        width: 100%;
+        max-width: 100%;
+        border: none;
+        margin: 0;
+    }
+
+    App.-plain ProgressBar Bar, App.-plain HeaderIcon, App.-plain HeaderClock {
+        display: none;
+    }
     """
 
     BINDINGS = [
@@ -131,37 +174,85 @@ class CloudBenchApp(App):
         Binding("g", "push_screen('geoserver')", "GeoServer", show=True),
         Binding("p", "push_screen('postgres')", "PostgreSQL", show=True),
         Binding("s", "push_screen('s3')", "S3 Storage", show=True),
+        Binding("k", "push_screen('cache_schedules')", "Cache Schedules", show=True),
+        Binding("n", "push_screen('snapshot_schedules')", "Snapshots", show=True),
+        Binding("u", "push_screen('batch_upload')", "Batch Upload", show=True),
+        Binding("l", "push_screen('lint')", "Catalog Check", show=True),
+        Binding("v", "push_screen('compare')", "Compare Servers", show=True),
+        Binding("m", "push_screen('settings_sync')", "Sync Settings", show=True),
+        Binding("e", "push_screen('hooks')", "Event Hooks", show=True),
+        Binding("y", "push_screen('default_styles')", "Default Styles", show=True),
         Binding("?", "push_screen('settings')", "Settings", show=True),
         Binding("r", "refresh", "Refresh", show=True),
+        Binding("ctrl+p", "command_palette", "Commands", show=True),
+        Binding("ctrl+n", "notifications", "Notifications", show=True),
         Binding("f1", "toggle_sidebar", "Toggle Sidebar", show=False),
     ]
 
+    # Textual's own commands, plus every screen, action and connection
+    COMMANDS = App.COMMANDS | {CloudBenchCommands}
+
     SCREENS = {
         "home": HomeScreen,
         "connections": ConnectionsScreen,
         "geoserver": GeoServerScreen,
         "postgres": PostgresScreen,
         "s3": S3Screen,
+        "cache_schedules": CacheSchedulesScreen,
+        "snapshot_schedules": SnapshotSchedulesScreen,
+        "batch_upload": BatchUploadScreen,
+        "lint": LintScreen,
+        "compare": CompareScreen,
+        "settings_sync": SettingsSyncScreen,
+        "hooks": HooksScreen,
+        "default_styles": DefaultStylesScreen,
         "settings": SettingsScreen,
     }
 
-    def __init__(self, config_path: str | None = None, debug: bool = False):
+    def __init__(
+        self,
+        config_path: str | None = None,
+        debug: bool = False,
+        profile: str | None = None,
+    ):
         """Initialize the CloudBench TUI.
 
         Args:
             config_path: Optional path to config file
             debug: Enable debug mode
+            profile: Optional named config profile
         """
         super().__init__()
         self.config_path = config_path
         self.debug_mode = debug
+        self.notification_log = NotificationLog()
         self._config_manager = ConfigManager()
+        if config_path:
+            self._config_manager.use_config_file(config_path)
+        elif profile:
+            self._config_manager.use_profile(profile)
+        # Plain output for screen readers, asked for with --plain
+        self.plain = self._config_manager.plain_mode
+        if self.plain:
+            self.animation_level = "none"
+        self.set_class(self.plain, "-plain")
 
     @property
     def config_manager(self) -> ConfigManager:
         """Get the configuration manager."""
         return self._config_manager
 
+    def get_css_variables(self) -> dict[str, str]:
+        """Get CSS variables from the configured theme."""
+        config = self._config_manager.config
+        theme = get_theme(config.theme, config.custom_themes)
+        return {**super().get_css_variables(), **theme.css_variables()}
+
+    def apply_theme(self) -> None:
+        """Restyle the application after the theme or accessibility setting changed."""
+        self.refresh_css()
+        self._refresh_sidebar()
+
     def compose(self) -> ComposeResult:
         """Create the application layout."""
         yield Header()
@@ -174,6 +265,7 @@ class CloudBenchApp(App):
 
     def on_mount(self) -> None:
         """Initialize the application when mounted."""
+        self._update_sub_title()
         # Load connections into the sidebar tree
         self._refresh_sidebar()
 
@@ -182,21 +274,26 @@ class CloudBenchApp(App):
         tree = self.query_one("#nav-tree", Tree)
 
         # Clear existing connection nodes and re-add
-        config = self._config_manager.config
-
         # Find the GeoServer connections node
         for node in tree.root.children:
             if node.data and node.data.get("type") == "geoserver":
                 # Remove existing connection children
                 node.remove_children()
 
-                # Add connections
-                for conn in config.connections:
-                    icon = "\u2713 " if conn.is_active else "  "
-                    node.add_leaf(
-                        f"{icon}{conn.name}",
-                        data={"type": "connection", "id": conn.id},
-                    )
+                # Add connections, grouped ones in folders
+                connections = self._config_manager.list_connections()
+                for group, members in group_connections(connections):
+                    parent = node
+                    if group:
+                        parent = node.add(group_label(group), data={"type": "group"})
+                        parent.expand()
+                    for conn in members:
+                        parent.add_leaf(
+                            connection_label(
+                                conn.name, conn.is_active, conn.badge, conn.environment
+                            ),
+                            data={"type": "connection", "id": conn.id},
+                        )
 
                 node.add_leaf("Add Connection...", data={"type": "add_connection"})
                 break
@@ -211,8 +308,7 @@ class CloudBenchApp(App):
 
         if node_type == "connection":
             # Show GeoServer connection details
-            conn_id = node_data.get("id")
-            self.push_screen("geoserver")
+            self.open_connection(node_data.get("id"))
 
         elif node_type == "add_connection":
             # Show add connection dialog
@@ -224,6 +320,43 @@ class CloudBenchApp(App):
         elif node_type == "s3":
             self.push_screen("s3")
 
+    def open_connection(self, conn_id: str) -> None:
+        """Browse a connection on the GeoServer screen."""
+        screen = self.get_screen("geoserver")
+        if self.screen is not screen:
+            self.push_screen(screen)
+        screen.select_connection(conn_id)
+
+    def notify(
+        self,
+        message: str,
+        *,
+        title: str = "",
+        severity: SeverityLevel = "information",
+        **kwargs,
+    ) -> None:
+        """Show a toast, and keep it for the notification center.
+
+        In plain mode the toast is one line of text with the severity in
+        words, so a screen reader announces it as it appears.
+        """
+        self.notification_log.add(message, severity, title)
+        self._update_title()
+        if self.plain:
+            message, title = NotificationEntry(message, severity, title).text, ""
+        super().notify(message, title=title, severity=severity, **kwargs)
+
+    def action_notifications(self) -> None:
+        """List the notifications shown this session."""
+        self.push_screen(
+            NotificationsScreen(self.notification_log), lambda _: self._update_title()
+        )
+
+    def _update_title(self) -> None:
+        """Count unread notifications in the header."""
+        unread = self.notification_log.unread
+        self.title = f"{self.TITLE} ({unread} new)" if unread else self.TITLE
+
     def action_toggle_sidebar(self) -> None:
         """Toggle the sidebar visibility."""
         sidebar = self.query_one("#sidebar")
@@ -234,15 +367,32 @@ class CloudBenchApp(App):
         self._refresh_sidebar()
         self.notify("Refreshed", severity="information")
 
+    def switch_profile(self, name: str) -> None:
+        """Load another config profile and refresh the views."""
+        self._config_manager.use_profile(name)
+        self._update_sub_title()
+        self.apply_theme()
+
+    def _update_sub_title(self) -> None:
+        """Show the profile in the header unless it is the default one."""
+        profile = self._config_manager.profile
+        if profile and profile != DEFAULT_PROFILE:
+            self.sub_title = f"Profile: {profile}"
+        else:
+            self.sub_title = self.SUB_TITLE
+
 
-def run_app(config_path: str | None = None, debug: bool = False) -> None:
+def run_app(
+    config_path: str | None = None, debug: bool = False, profile: str | None = None
+) -> None:
     """Run the CloudBench TUI application.
 
     Args:
         config_path: Optional path to config file
         debug: Enable debug mode
+        profile: Optional named config profile
     """
-    app = CloudBenchApp(config_path=config_path, debug=debug)
+    app = CloudBenchApp(config_path=config_path, debug=debug, profile=profile)
     app.run()
 
 
@@ -0,0 +1,75 @@
+"""Commands recently run from the TUI command palette.
+
+The palette lists them first when nothing is typed, and ranks them above
+equally good matches when something is, so the actions someone uses
+most are a keystroke or two away. The history is kept between sessions.
+"""
+
+import json
+import logging
+from pathlib import Path
+
+from apps.core.config import get_state_dir
+
+logger = logging.getLogger(__name__)
+
+HISTORY_FILE = "palette-history.json"
+
+# Commands remembered, most recent first
+MAX_RECENT = 20
+
+# Score added to the most recent command's match; older ones get less
+RECENT_BOOST = 0.3
+
+
+class CommandHistory:
+    """Names of the commands run from the palette, most recent first."""
+
+    def __init__(self, path: Path | None = None):
+        """Initialize the history.
+
+        Args:
+            path: JSON file to keep the history in; the state directory by default
+        """
+        self._path = path
+        self._recent: list[str] | None = None
+
+    @property
+    def path(self) -> Path:
+        return self._path or get_state_dir() / HISTORY_FILE
+
+    @property
+    def recent(self) -> list[str]:
+        if self._recent is None:
+            self._recent = self._load()
+        return self._recent
+
+    def _load(self) -> list[str]:
+        try:
+            data = json.loads(self.path.read_text())
+        except (OSError, ValueError):
+            return []
+        if not isinstance(data, list):
+            return []
+        return [name for name in data if isinstance(name, str)][:MAX_RECENT]
+
+    def add(self, name: str) -> None:
+        """Move a command to the front of the history and save it."""
+        recent = [name] + [other for other in self.recent if other != name]
+        self._recent = recent[:MAX_RECENT]
+        try:
+            self.path.write_text(json.dumps(self._recent))
+        except OSError as e:
+            # The history is a convenience; never fail the command that was run
+            logger.warning("Could not save the command history: %s", e)
+
+    def boost(self, name: str) -> float:
+        """Score to add to a command's match, more for more recent commands."""
+        if name not in self.recent:
+            return 0.0
+        return RECENT_BOOST * (MAX_RECENT - self.recent.index(name)) / MAX_RECENT
+
+    def order(self, names: list[str]) -> list[str]:
+        """Recent commands first, most recent first, then the others as given."""
+        recent = [name for name in self.recent if name in names]
+        return recent + [name for name in names if name not in recent]
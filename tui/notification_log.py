@@ -0,0 +1,67 @@
+"""Notifications the TUI has shown this session.
+
+Toasts disappear after a few seconds, and a burst of them from uploads,
+seeds and syncs finishing at once is easily missed. Every notification
+is therefore also kept here, with the time it was shown, for the
+notification center to list.
+"""
+
+from collections import deque
+from dataclasses import dataclass, field
+from datetime import datetime
+
+# Notifications kept; the oldest are dropped first
+MAX_ENTRIES = 200
+
+# Severities that count as problems, for the errors-only view
+PROBLEM_SEVERITIES = ("warning", "error")
+
+
+@dataclass
+class NotificationEntry:
+    """A notification and when it was shown."""
+
+    message: str
+    severity: str = "information"  # "information", "warning" or "error"
+    title: str = ""
+    time: datetime = field(default_factory=datetime.now)
+
+    @property
+    def is_problem(self) -> bool:
+        return self.severity in PROBLEM_SEVERITIES
+
+    @property
+    def text(self) -> str:
+        """The notification as one line with its severity in words, for plain mode."""
+        prefix = {"warning": "Warning: ", "error": "Error: "}.get(self.severity, "")
+        title = f"{self.title}: " if self.title else ""
+        return f"{prefix}{title}{self.message}"
+
+
+class NotificationLog:
+    """Notifications shown this session, newest first, and how many are unread."""
+
+    def __init__(self, max_entries: int = MAX_ENTRIES):
+        """Initialize the log.
+
+        Args:
+            max_entries: Notifications kept before the oldest are dropped
+        """
+        self._entries: deque[NotificationEntry] = deque(maxlen=max_entries)
+        self.unread = 0
+
+    def add(self, message: str, severity: str = "information", title: str = "") -> None:
+        """Record a notification."""
+        self._entries.appendleft(NotificationEntry(message, severity, title))
+        self.unread = min(self.unread + 1, len(self._entries))
+
+    def entries(self, problems_only: bool = False) -> list[NotificationEntry]:
+        """The notifications, newest first."""
+        return [entry for entry in self._entries if entry.is_problem or not problems_only]
+
+    def mark_read(self) -> None:
+        self.unread = 0
+
+    def clear(self) -> None:
+        self._entries.clear()
+        self.unread = 0
@@ -0,0 +1,125 @@
+"""Command palette for Kartoza CloudBench TUI.
+
+Ctrl+P lists every action at hand: the screens to open, the current
+screen's key bindings and buttons, and the connections to browse, so an
+action can be found by typing part of its name instead of remembering
+its key. Commands run recently are listed first.
+"""
+
+from collections.abc import Callable
+from dataclasses import dataclass
+from functools import partial
+from typing import Any
+
+from textual.actions import parse
+from textual.binding import Binding
+from textual.command import DiscoveryHit, Hit, Hits, Provider
+from textual.widget import Widget
+from textual.widgets import Button
+
+from .command_history import CommandHistory
+
+# Shared by every palette opened this session
+history = CommandHistory()
+
+
+@dataclass
+class PaletteCommand:
+    """An action the palette can run."""
+
+    name: str
+    run: Callable[[], Any]
+    help: str = ""
+
+
+def make_bindings(bindings: list[Any]) -> list[Binding]:
+    """Bindings declared as (key, action, description) tuples or Binding objects."""
+    return [binding if isinstance(binding, Binding) else Binding(*binding) for binding in bindings]
+
+
+def is_shown(widget: Widget) -> bool:
+    """Whether a widget and every container it is in are displayed."""
+    return all(node.display for node in widget.ancestors_with_self if isinstance(node, Widget))
+
+
+class CloudBenchCommands(Provider):
+    """Screens, the current screen's actions and connections."""
+
+    async def startup(self) -> None:
+        """Gather the commands available where the palette was opened."""
+        self.commands: list[PaletteCommand] = []
+        names: set[str] = set()
+
+        def add(command: PaletteCommand) -> None:
+            # A button and a binding often do the same thing; list it once
+            if command.name and command.name not in names:
+                names.add(command.name)
+                self.commands.append(command)
+
+        for command in self._screen_commands():
+            add(command)
+        for command in self._app_commands():
+            add(command)
+        for conn in self.app.config_manager.list_connections():
+            add(
+                PaletteCommand(
+                    f"Browse {conn.name}", partial(self.app.open_connection, conn.id), conn.url
+                )
+            )
+
+    def _app_commands(self) -> list[PaletteCommand]:
+        """Screens to open and the application's own actions."""
+        commands = []
+        for binding in make_bindings(self.app.BINDINGS):
+            if binding.action == "command_palette":
+                continue
+            name = binding.description
+            if binding.action.startswith("push_screen"):
+                name = f"Open {name}"
+            commands.append(
+                PaletteCommand(name, partial(self.app.run_action, binding.action), binding.key)
+            )
+        return commands
+
+    def _screen_commands(self) -> list[PaletteCommand]:
+        """Key bindings and buttons of the screen the palette was opened on."""
+        screen = self.screen
+        commands = []
+        # Only bindings the screen declares itself, not Textual's focus keys
+        for binding in make_bindings(vars(type(screen)).get("BINDINGS", [])):
+            namespace, action, parameters = parse(binding.action)
+            if not namespace and not screen.check_action(action, parameters):
+                continue
+            commands.append(
+                PaletteCommand(
+                    binding.description, partial(screen.run_action, binding.action), binding.key
+                )
+            )
+        for button in screen.query(Button):
+            if is_shown(button) and not button.disabled:
+                commands.append(PaletteCommand(str(button.label), button.press, "Button"))
+        return commands
+
+    def _run(self, command: PaletteCommand) -> Any:
+        history.add(command.name)
+        return command.run()
+
+    async def discover(self) -> Hits:
+        """List recent commands first, then every other command."""
+        by_name = {command.name: command for command in self.commands}
+        for name in history.order(list(by_name)):
+            command = by_name[name]
+            yield DiscoveryHit(command.name, partial(self._run, command), help=command.help)
+
+    async def search(self, query: str) -> Hits:
+        """Fuzzy-match commands, ranking recent ones higher."""
+        matcher = self.matcher(query)
+        for command in self.commands:
+            score = matcher.match(command.name)
+            if score > 0:
+                yield Hit(
+                    min(1.0, score + history.boost(command.name)),
+                    matcher.highlight(command.name),
+                    partial(self._run, command),
+                    help=command.help,
+                )
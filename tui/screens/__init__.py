@@ -1,11 +1,47 @@
 """TUI screens for Kartoza CloudBench."""
 
+from .attributes import AttributeTableScreen
+from .batch_action import BatchActionScreen
+from .batch_upload import BatchUploadScreen
+from .branding import BrandingScreen
+from .cache_schedules import CacheSchedulesScreen
+from .compare import CompareScreen
+from .confirm import ConfirmScreen
 from .connections import ConnectionsScreen
+from .cql_builder import CqlBuilderScreen
+from .datadir_diff import DataDirDiffScreen
+from .default_styles import DefaultStylesScreen
+from .endpoint_health import EndpointHealthScreen
+from .file_preview import FilePreviewScreen
+from .geofence import GeofenceRulesScreen
 from .geoserver import GeoServerScreen
+from .hit_ratio import HitRatioScreen
 from .home import HomeScreen
+from .hooks import HooksScreen
+from .layergroup_preview import LayerGroupPreviewScreen
+from .lint import LintScreen
+from .map_export import MapExportScreen
+from .map_preview import MapPreviewScreen
+from .notifications import NotificationsScreen
+from .oidc_sign_in import OIDCSignInScreen
+from .picker import PickerScreen
 from .postgres import PostgresScreen
+from .qgis_publish import QGISPublishScreen
+from .render_benchmark import RenderBenchmarkScreen
 from .s3 import S3Screen
+from .seed_estimate import SeedEstimateScreen
 from .settings import SettingsScreen
+from .settings_sync import SettingsSyncScreen
+from .snapshot_schedules import SnapshotSchedulesScreen
+from .stac_catalog import StacCatalogScreen
+from .style_assets import StyleAssetsScreen
+from .style_delete import StyleDeleteScreen
+from .style_diff import StyleDiffScreen
+from .style_import import StyleImportScreen
+from .terria_init import TerriaInitScreen
+from .tile_endpoints import TileEndpointsScreen
+from .workspace_create import WorkspaceCreateScreen
+from .workspace_usage import WorkspaceUsageScreen
 
 __all__ = [
     "HomeScreen",
@@ -14,4 +50,40 @@ __all__ = [
     "PostgresScreen",
     "S3Screen",
     "SettingsScreen",
+    "CacheSchedulesScreen",
+    "SnapshotSchedulesScreen",
+    "BatchUploadScreen",
+    "BatchActionScreen",
+    "LintScreen",
+    "MapPreviewScreen",
+    "PickerScreen",
+    "ConfirmScreen",
+    "CompareScreen",
+    "SettingsSyncScreen",
+    "AttributeTableScreen",
+    "CqlBuilderScreen",
+    "FilePreviewScreen",
+    "TileEndpointsScreen",
+    "StyleImportScreen",
+    "StyleAssetsScreen",
+    "StyleDiffScreen",
+    "GeofenceRulesScreen",
+    "BrandingScreen",
+    "RenderBenchmarkScreen",
+    "HitRatioScreen",
+    "HooksScreen",
+    "DefaultStylesScreen",
+    "OIDCSignInScreen",
+    "WorkspaceCreateScreen",
+    "NotificationsScreen",
+    "StacCatalogScreen",
+    "SeedEstimateScreen",
+    "StyleDeleteScreen",
+    "LayerGroupPreviewScreen",
+    "QGISPublishScreen",
+    "MapExportScreen",
+    "WorkspaceUsageScreen",
+    "DataDirDiffScreen",
+    "TerriaInitScreen",
+    "EndpointHealthScreen",
 ]
@@ -0,0 +1,258 @@
+"""Layer attribute table screen for Kartoza CloudBench TUI."""
+
+from pathlib import Path
+
+from rich.text import Text
+from textual.app import ComposeResult
+from textual.screen import Screen
+from textual.widgets import DataTable, Input, Static
+
+from apps.geoserver.attributes import (
+    AttributePage,
+    export_attribute_csv,
+    parse_column_filters,
+    read_attribute_table,
+)
+from apps.geoserver.client import get_geoserver_client
+
+from .cql_builder import CqlBuilderScreen
+
+# Rows fetched per page
+PAGE_SIZE = 100
+
+
+class AttributeTableScreen(Screen):
+    """Screen paging through the attributes of a vector layer over WFS."""
+
+    DEFAULT_CSS = """
+    AttributeTableScreen {
+        layout: vertical;
+    }
+
+    .screen-header {
+        height: 3;
+        padding: 1;
+        background: $primary;
+    }
+
+    .attribute-filter {
+        margin: 1 1 0 1;
+    }
+
+    .attribute-table {
+        height: 1fr;
+        margin: 1;
+    }
+
+    .attribute-status {
+        height: 2;
+        padding: 0 1;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "app.pop_screen", "Back"),
+        ("n", "page(1)", "Next Page"),
+        ("p", "page(-1)", "Previous Page"),
+        ("s", "sort", "Sort Column"),
+        ("f", "focus_filter", "Filter"),
+        ("c", "build_filter", "CQL Filter"),
+        ("x", "export", "Export CSV"),
+        ("r", "refresh", "Refresh"),
+    ]
+
+    def __init__(self, conn_id: str, workspace: str, layer: str, **kwargs) -> None:
+        """Initialize the attribute table.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace of the layer
+            layer: Layer name
+        """
+        super().__init__(**kwargs)
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self.layer = layer
+        self.offset = 0
+        self.sort: str | None = None
+        self.descending = False
+        self.filters: dict[str, str] = {}
+        self.cql = ""
+        self._page: AttributePage | None = None
+
+    def compose(self) -> ComposeResult:
+        """Create the attribute table layout."""
+        yield Static(f"Attributes of {self.workspace}:{self.layer}", classes="screen-header")
+        yield Input(
+            placeholder="Filter: name: park; population: >= 1000 (Enter to apply)",
+            id="attribute-filter",
+            classes="attribute-filter",
+        )
+        yield DataTable(id="attribute-table", classes="attribute-table", zebra_stripes=True)
+        yield Static("Loading features...", id="status", classes="attribute-status")
+
+    def on_mount(self) -> None:
+        """Load the first page."""
+        self.query_one("#attribute-table", DataTable).focus()
+        self._load()
+
+    def _load(self) -> None:
+        """Fetch the current page in a background thread."""
+        self.query_one("#status", Static).update("Loading features...")
+        offset, sort, descending, filters = self.offset, self.sort, self.descending, self.filters
+        cql = self.cql
+
+        def load() -> None:
+            try:
+                client = get_geoserver_client(self.conn_id)
+                page = read_attribute_table(
+                    client,
+                    self.workspace,
+                    self.layer,
+                    limit=PAGE_SIZE,
+                    offset=offset,
+                    sort=sort,
+                    descending=descending,
+                    filters=filters,
+                    cql=cql or None,
+                )
+            except Exception as e:
+                self.app.call_from_thread(
+                    self.query_one("#status", Static).update, f"Could not load features: {e}"
+                )
+                return
+            self.app.call_from_thread(self._show_page, page)
+
+        self.run_worker(load, thread=True, group="attributes", exclusive=True)
+
+    def _show_page(self, page: AttributePage) -> None:
+        """Fill the table with a page of rows."""
+        self._page = page
+        table = self.query_one("#attribute-table", DataTable)
+        table.clear(columns=True)
+        for attribute in page.attributes:
+            label = attribute.name
+            if not attribute.nillable:
+                label += "*"
+            if attribute.name == self.sort:
+                label += " ▼" if self.descending else " ▲"
+            table.add_column(label, key=attribute.name)
+        for row in page.rows:
+            table.add_row(
+                *[
+                    Text("∅", style="dim") if row[a.name] is None else str(row[a.name])
+                    for a in page.attributes
+                ]
+            )
+
+        names = [a.name for a in page.attributes]
+        if self.sort in names:
+            # Keep the cursor on the sorted column so s can cycle its order
+            table.move_cursor(column=names.index(self.sort))
+
+        if page.rows:
+            status = f"Rows {page.offset + 1}-{page.offset + len(page.rows)} of {page.total:,}"
+            if self.filters or self.cql:
+                status += " (filtered)"
+        elif self.filters or self.cql:
+            status = "No features match the filters"
+        else:
+            status = "The layer has no features"
+        if page.geometry_type:
+            status += f" | {page.geometry_type}"
+        if self.cql:
+            status += f" | CQL: {self.cql}"
+        if any(not a.nillable for a in page.attributes):
+            status += " | * not null"
+        status += "\nn/p page, s sort, f column filters, c CQL filter, x export CSV"
+        self.query_one("#status", Static).update(status)
+
+    def action_page(self, step: int) -> None:
+        """Move to the next or previous page."""
+        if not self._page:
+            return
+        if step > 0 and not self._page.has_more:
+            return
+        offset = max(0, self.offset + step * PAGE_SIZE)
+        if offset != self.offset:
+            self.offset = offset
+            self._load()
+
+    def action_sort(self) -> None:
+        """Sort by the column under the cursor: ascending, descending, then unsorted."""
+        if not self._page or not self._page.attributes:
+            return
+        table = self.query_one("#attribute-table", DataTable)
+        self._sort_by(self._page.attributes[table.cursor_column].name)
+
+    def on_data_table_header_selected(self, event: DataTable.HeaderSelected) -> None:
+        """Sort by a clicked column header."""
+        self._sort_by(str(event.column_key.value))
+
+    def _sort_by(self, column: str) -> None:
+        if self.sort != column:
+            self.sort, self.descending = column, False
+        elif not self.descending:
+            self.descending = True
+        else:
+            self.sort, self.descending = None, False
+        self.offset = 0
+        self._load()
+
+    def action_focus_filter(self) -> None:
+        """Move to the filter input."""
+        self.query_one("#attribute-filter", Input).focus()
+
+    def on_input_submitted(self, event: Input.Submitted) -> None:
+        """Apply the typed column filters."""
+        try:
+            filters = parse_column_filters(event.value)
+        except ValueError as e:
+            self.app.notify(str(e), severity="error")
+            return
+        self.filters = filters
+        self.offset = 0
+        self.query_one("#attribute-table", DataTable).focus()
+        self._load()
+
+    def action_build_filter(self) -> None:
+        """Build a CQL filter from the layer's attributes."""
+
+        def apply(cql: str | None) -> None:
+            if cql is None:
+                return
+            self.cql = cql
+            self.offset = 0
+            self._load()
+
+        self.app.push_screen(
+            CqlBuilderScreen(self.conn_id, self.workspace, self.layer, self.cql), apply
+        )
+
+    def action_refresh(self) -> None:
+        """Reload the current page."""
+        self._load()
+
+    def action_export(self) -> None:
+        """Save the filtered, sorted table as CSV in the working directory."""
+        path = Path.cwd() / f"{self.workspace}_{self.layer}.csv"
+        sort, descending, filters, cql = self.sort, self.descending, self.filters, self.cql
+        self.app.notify(f"Exporting {self.workspace}:{self.layer}...", severity="information")
+
+        def export() -> None:
+            try:
+                client = get_geoserver_client(self.conn_id)
+                csv = export_attribute_csv(
+                    client, self.workspace, self.layer, sort, descending, filters, cql or None
+                )
+                path.write_text(csv, encoding="utf-8")
+            except Exception as e:
+                self.app.call_from_thread(
+                    self.app.notify, f"Export failed: {str(e)}", severity="error"
+                )
+                return
+            self.app.call_from_thread(
+                self.app.notify, f"Saved {path.name}", severity="information"
+            )
+
+        self.run_worker(export, thread=True)
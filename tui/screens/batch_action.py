@@ -0,0 +1,275 @@
+"""Batch action dialog for Kartoza CloudBench TUI."""
+
+import json
+import time
+from pathlib import Path
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, Checkbox, DataTable, Input, ProgressBar, Static
+
+from apps.core.checksum import write_checksum_file
+from apps.core.config import config_manager
+from apps.core.confirmation import batch_confirmation_text, requires_typed_confirmation
+from apps.core.oplog import operation_log
+from apps.geoserver.batch import ACTIONS, BatchResult, export_document, run_batch
+from apps.geoserver.client import get_geoserver_client
+from apps.gwc.client import get_gwc_client
+from apps.gwc.truncate import mass_truncate, summarize
+
+from .confirm import ConfirmScreen
+from .layergroup_preview import LayerGroupPreviewScreen
+
+
+class BatchActionScreen(ModalScreen[bool]):
+    """Dialog that runs one action on marked layers and shows the outcome.
+
+    Dismisses with True when the catalog may have changed, so the caller
+    knows to refresh its tree.
+    """
+
+    DEFAULT_CSS = """
+    BatchActionScreen {
+        align: center middle;
+    }
+
+    .batch-dialog {
+        width: 80%;
+        height: 80%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .batch-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .batch-options {
+        height: auto;
+    }
+
+    .batch-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .batch-buttons {
+        height: 3;
+        align-horizontal: right;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "close", "Close"),
+    ]
+
+    def __init__(self, conn_id: str, action: str, layers: list[str]) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            action: One of apps.geoserver.batch.ACTIONS
+            layers: Qualified names of the marked layers
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.action = action
+        self.layers = layers
+        self._running = False
+        self._finished = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="batch-dialog"):
+            yield Static(
+                f"{ACTIONS[self.action]}: {len(self.layers)} layer(s)", classes="batch-title"
+            )
+            with Vertical(classes="batch-options"):
+                if self.action == "group":
+                    yield Input(placeholder="Layer group name", id="input-group-name")
+                    yield Input(placeholder="Title (optional)", id="input-group-title")
+                elif self.action == "delete":
+                    yield Checkbox(
+                        "Also delete the underlying feature types and coverages",
+                        id="input-recurse",
+                    )
+            total = 1 if self.action == "group" else len(self.layers)
+            yield ProgressBar(total=total, id="progress")
+            table = DataTable(id="results-table", classes="batch-table", cursor_type="row")
+            for label in ("Layer", "Result", "Details"):
+                table.add_column(label, key=label)
+            for layer in self.layers:
+                table.add_row(layer, "pending", "", key=layer)
+            yield table
+            yield Static("", id="summary")
+            with Horizontal(classes="batch-buttons"):
+                variant = "error" if self.action == "delete" else "primary"
+                if self.action == "group":
+                    yield Button("Preview", id="btn-preview")
+                yield Button("Run", id="btn-run", variant=variant)
+                yield Button("Close", id="btn-close")
+
+    def _options(self) -> dict:
+        """Collect the action options from the form."""
+        if self.action == "group":
+            return {
+                "name": self.query_one("#input-group-name", Input).value.strip(),
+                "title": self.query_one("#input-group-title", Input).value.strip() or None,
+            }
+        if self.action == "delete":
+            return {"recurse": self.query_one("#input-recurse", Checkbox).value}
+        return {}
+
+    def _start(self) -> None:
+        """Confirm the action if the connection asks for it, then run it."""
+        if self._running or self._finished:
+            return
+        options = self._options()
+        if self.action == "group" and not options["name"]:
+            self.app.notify("Enter a layer group name", severity="error")
+            return
+
+        conn = config_manager.get_connection(self.conn_id)
+        if self.action in ("delete", "truncate") and conn and requires_typed_confirmation(conn):
+
+            def confirmed(ok: bool | None) -> None:
+                if ok:
+                    self._run_in_background(options)
+
+            self.app.push_screen(
+                ConfirmScreen(
+                    f"{ACTIONS[self.action]}: {len(self.layers)} layer(s)",
+                    f"Connection {conn.name} asks for this to be typed first.",
+                    expected=batch_confirmation_text(self.action, len(self.layers)),
+                    confirm_label=ACTIONS[self.action],
+                ),
+                confirmed,
+            )
+            return
+        self._run_in_background(options)
+
+    def _run_in_background(self, options: dict) -> None:
+        """Run the action in a background thread."""
+        self._running = True
+        self.query_one("#btn-run", Button).disabled = True
+        self.run_worker(lambda: self._run(options), thread=True)
+
+    def _run(self, options: dict) -> None:
+        """Run the action and report each result back to the UI thread."""
+        def progress(done: int, total: int, result: BatchResult) -> None:
+            self.app.call_from_thread(self._show_result, done, result)
+
+        try:
+            if self.action == "truncate":
+                # Tile caches are truncated several at a time; logged so that
+                # an interrupted run can be resumed with `cloudbench operations`
+                gwc_client = get_gwc_client(self.conn_id)
+                operation = operation_log.begin("truncate", self.conn_id, self.layers)
+                try:
+                    results = mass_truncate(
+                        gwc_client, self.layers, progress=progress, operation=operation
+                    )
+                finally:
+                    operation_log.close(operation)
+            else:
+                client = get_geoserver_client(self.conn_id)
+                results = run_batch(client, None, self.action, self.layers, options, progress)
+        except Exception as e:
+            self.app.call_from_thread(self._finish, [], str(e))
+            return
+        self.app.call_from_thread(self._finish, results, None)
+
+    def _show_result(self, done: int, result: BatchResult) -> None:
+        """Record one result in the table."""
+        self.query_one("#progress", ProgressBar).update(progress=done)
+        table = self.query_one("#results-table", DataTable)
+        if self.action == "group":
+            # One result for the whole group replaces the per-layer rows
+            table.clear()
+        status = "ok" if result.ok else "failed"
+        if result.target in table.rows:
+            table.update_cell(result.target, "Result", status)
+            table.update_cell(result.target, "Details", result.message)
+        else:
+            table.add_row(result.target, status, result.message, key=result.target)
+
+    def _finish(self, results: list[BatchResult], error: str | None) -> None:
+        """Summarize the run."""
+        self._running = False
+        self._finished = True
+        summary = self.query_one("#summary", Static)
+        if error:
+            summary.update(f"Failed: {error}")
+            return
+
+        failed = sum(1 for r in results if not r.ok)
+        text = f"{len(results) - failed} succeeded, {failed} failed"
+        if self.action == "truncate":
+            counts = summarize(results)
+            text = (
+                f"{counts['truncated']} truncated, {counts['skipped']} without a tile cache, "
+                f"{counts['failed']} failed"
+            )
+        if self.action == "export" and results:
+            path = self._save_export(results)
+            if path:
+                text += f"\nConfigurations saved to {path}"
+        summary.update(text)
+
+    def _save_export(self, results: list[BatchResult]) -> Path | None:
+        """Write exported configurations to a JSON file in the working directory.
+
+        A .sha256 checksum file is written next to it.
+        """
+        document = export_document(results)
+        if not document:
+            return None
+        path = Path.cwd() / f"layer-configs-{self.conn_id}-{time.strftime('%Y%m%d-%H%M%S')}.json"
+        try:
+            path.write_text(json.dumps(document, indent=2))
+            write_checksum_file(path)
+        except OSError as e:
+            self.app.notify(f"Could not save configurations: {str(e)}", severity="error")
+            return None
+        return path
+
+    def _preview_group(self) -> None:
+        """Preview the marked layers drawn as a group, and let the user reorder them."""
+        if self._running or self._finished:
+            return
+        workspaces = {layer.partition(":")[0] for layer in self.layers}
+        if len(workspaces) != 1:
+            self.app.notify("Only layers of one workspace can be previewed", severity="warning")
+            return
+
+        def reordered(layers: list[str] | None) -> None:
+            if layers is None:
+                return
+            self.layers = layers
+            table = self.query_one("#results-table", DataTable)
+            table.clear()
+            for layer in layers:
+                table.add_row(layer, "pending", "", key=layer)
+
+        self.app.push_screen(
+            LayerGroupPreviewScreen(self.conn_id, workspaces.pop(), self.layers), reordered
+        )
+
+    def action_close(self) -> None:
+        """Close the dialog unless the action is still running."""
+        if self._running:
+            self.app.notify("Wait for the action to finish", severity="warning")
+            return
+        self.dismiss(self._finished and self.action in ("delete", "group"))
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-run":
+            self._start()
+        elif event.button.id == "btn-preview":
+            self._preview_group()
+        elif event.button.id == "btn-close":
+            self.action_close()
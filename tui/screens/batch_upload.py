@@ -0,0 +1,351 @@
+"""Directory batch upload screen for Kartoza CloudBench TUI."""
+
+from pathlib import Path
+from typing import Any
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal
+from textual.screen import Screen
+from textual.widgets import Button, Checkbox, DataTable, Input, Label, Select, Static
+
+from apps.core.config import config_manager
+from apps.core.oplog import Operation, operation_log
+from apps.geoserver.client import get_geoserver_client
+from apps.upload.batch import (
+    BatchItem,
+    begin_batch,
+    propose_mapping,
+    run_batch,
+    walk_directory,
+)
+from apps.upload.target import MODE_CREATE, UploadTarget, check_targets
+
+from .file_preview import FilePreviewScreen
+
+
+class BatchUploadScreen(Screen):
+    """Screen for uploading a directory of files with an editable mapping."""
+
+    DEFAULT_CSS = """
+    BatchUploadScreen {
+        layout: vertical;
+    }
+
+    .screen-header {
+        height: 3;
+        padding: 1;
+        background: $primary;
+    }
+
+    .form-row {
+        height: auto;
+        padding: 0 1;
+    }
+
+    .form-label {
+        width: 12;
+        padding: 1 0;
+    }
+
+    .batch-table {
+        height: 1fr;
+        margin: 1;
+    }
+
+    .action-bar {
+        height: 3;
+        padding: 0 1;
+        background: $surface;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "app.pop_screen", "Back"),
+        ("enter", "edit_item", "Edit"),
+        ("p", "preview_item", "Preview"),
+    ]
+
+    def __init__(self) -> None:
+        """Initialize the batch upload screen."""
+        super().__init__()
+        self._root: Path | None = None
+        self._items: list[BatchItem] = []
+        self._status: dict[str, str] = {}
+        self._uploading = False
+
+    def compose(self) -> ComposeResult:
+        """Create the batch upload screen layout."""
+        yield Static("Batch Upload", classes="screen-header")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Connection:", classes="form-label")
+            yield Select([], id="select-connection", prompt="Select a connection...")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Directory:", classes="form-label")
+            yield Input(placeholder="/path/to/data", id="input-directory")
+            yield Button("Scan", id="btn-scan")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Workspace:", classes="form-label")
+            yield Input(placeholder="workspace", id="input-workspace")
+            yield Checkbox("Subfolders as workspaces", id="check-folder-workspaces")
+            yield Checkbox("Verify checksums", id="check-verify")
+
+        table = DataTable(id="batch-table", classes="batch-table", cursor_type="row")
+        table.add_columns("File", "Type", "Workspace", "Store", "Layer/Style", "Title", "Status")
+        yield table
+
+        with Horizontal(classes="form-row"):
+            yield Input(placeholder="workspace", id="edit-workspace")
+            yield Input(placeholder="store", id="edit-store")
+            yield Input(placeholder="layer/style", id="edit-layer")
+            yield Input(placeholder="layer title", id="edit-title")
+            yield Button("Apply", id="btn-apply")
+
+        with Horizontal(classes="action-bar"):
+            yield Button("Upload", id="btn-upload", variant="primary")
+            yield Button("Back", id="btn-back")
+
+    def on_mount(self) -> None:
+        """Load connections when screen mounts."""
+        select = self.query_one("#select-connection", Select)
+        select.set_options([(conn.name, conn.id) for conn in config_manager.config.connections])
+
+    def _refresh_table(self) -> None:
+        """Refresh the mapping table."""
+        table = self.query_one("#batch-table", DataTable)
+        cursor = table.cursor_row
+        table.clear()
+        for item in self._items:
+            table.add_row(
+                item.path,
+                item.kind,
+                item.workspace,
+                item.store or "-",
+                item.layer or "-",
+                item.title or "-",
+                self._status.get(item.path, "pending"),
+                key=item.path,
+            )
+        if self._items:
+            table.move_cursor(row=min(cursor, len(self._items) - 1))
+
+    def _scan(self) -> None:
+        """Walk the directory and propose a mapping."""
+        directory = self.query_one("#input-directory", Input).value.strip()
+        workspace = self.query_one("#input-workspace", Input).value.strip()
+        if not directory or not workspace:
+            self.app.notify("Please enter a directory and workspace", severity="error")
+            return
+
+        root = Path(directory).expanduser()
+        if not root.is_dir():
+            self.app.notify(f"Not a directory: {root}", severity="error")
+            return
+
+        folder_workspaces = self.query_one("#check-folder-workspaces", Checkbox).value
+        self._root = root
+        self._items = propose_mapping(walk_directory(root), workspace, folder_workspaces)
+        self._status = {}
+        self._refresh_table()
+        self.app.notify(f"Found {len(self._items)} file(s)", severity="information")
+        self._check_targets()
+
+    def _check_targets(self) -> None:
+        """Check the mapped stores against the server in a background thread."""
+        conn_id = self.query_one("#select-connection", Select).value
+        if conn_id == Select.BLANK or not self._items or self._uploading:
+            return
+        items = list(self._items)
+
+        def check() -> None:
+            try:
+                targets = check_targets(get_geoserver_client(str(conn_id)), items)
+            except Exception:
+                # Only advice; the upload reports real failures
+                return
+            self.app.call_from_thread(self._on_targets, targets)
+
+        self.run_worker(check, thread=True)
+
+    def _on_targets(self, targets: dict[str, UploadTarget]) -> None:
+        """Show whether each file creates a store or goes into an existing one."""
+        if self._uploading:
+            return
+        for path, target in targets.items():
+            if target.error:
+                self._status[path] = f"! {target.error}"
+            elif target.mode != MODE_CREATE:
+                self._status[path] = f"existing store ({target.mode})"
+            else:
+                self._status.pop(path, None)
+        self._refresh_table()
+
+    def _selected_item(self) -> BatchItem | None:
+        """Get the item under the table cursor."""
+        table = self.query_one("#batch-table", DataTable)
+        if table.row_count == 0:
+            return None
+        return self._items[table.cursor_row]
+
+    def on_data_table_row_highlighted(self, event: DataTable.RowHighlighted) -> None:
+        """Load the highlighted item into the edit inputs."""
+        item = self._selected_item()
+        if item:
+            self.query_one("#edit-workspace", Input).value = item.workspace
+            self.query_one("#edit-store", Input).value = item.store
+            self.query_one("#edit-layer", Input).value = item.layer
+            self.query_one("#edit-title", Input).value = item.title
+
+    def action_edit_item(self) -> None:
+        """Focus the edit inputs for the selected item."""
+        self.query_one("#edit-workspace", Input).focus()
+
+    def action_preview_item(self) -> None:
+        """Show what the selected file holds."""
+        item = self._selected_item()
+        if item and self._root is not None:
+            self.app.push_screen(FilePreviewScreen(self._root / item.path))
+
+    def _apply_edit(self) -> None:
+        """Apply the edit inputs to the selected item."""
+        item = self._selected_item()
+        if not item:
+            return
+
+        edited = BatchItem(
+            path=item.path,
+            kind=item.kind,
+            workspace=self.query_one("#edit-workspace", Input).value.strip(),
+            store=self.query_one("#edit-store", Input).value.strip(),
+            layer=self.query_one("#edit-layer", Input).value.strip(),
+            title=self.query_one("#edit-title", Input).value.strip(),
+            abstract=item.abstract,
+            keywords=item.keywords,
+        )
+        try:
+            edited.validate()
+        except ValueError as e:
+            self.app.notify(str(e), severity="error")
+            return
+
+        self._items[self._items.index(item)] = edited
+        self._refresh_table()
+        self._check_targets()
+
+    def _upload(self) -> None:
+        """Publish the mapped files in a background thread."""
+        conn_id = self.query_one("#select-connection", Select).value
+        if conn_id == Select.BLANK:
+            self.app.notify("Please select a connection", severity="error")
+            return
+        if not self._items or self._root is None:
+            self.app.notify("Scan a directory first", severity="warning")
+            return
+        if self._uploading:
+            return
+
+        self._uploading = True
+        self._status = {item.path: "uploading" for item in self._items}
+        self._refresh_table()
+        verify = self.query_one("#check-verify", Checkbox).value
+        self.run_worker(lambda: self._run_batch(str(conn_id), verify), thread=True)
+
+    def _interrupted(self, conn_id: str) -> Operation | None:
+        """An interrupted upload of the same files to the same connection, taken over."""
+        paths = {item.path for item in self._items}
+        for operation in operation_log.interrupted(conn_id, "upload"):
+            if operation.params.get("root") == str(self._root) and set(operation.steps) == paths:
+                return operation_log.resume(operation.id)
+        return None
+
+    def _run_batch(self, conn_id: str, verify: bool) -> None:
+        """Run the batch, reporting each result to the UI thread.
+
+        Files an interrupted upload of the folder already published are skipped.
+        """
+        root = self._root
+        operation = self._interrupted(conn_id)
+        if operation is None:
+            operation = begin_batch(conn_id, root, self._items, verify)
+            items = list(self._items)
+        else:
+            items = [item for item in self._items if item.path not in operation.done]
+            self.app.call_from_thread(self._on_resumed, list(operation.done))
+        try:
+            client = get_geoserver_client(conn_id)
+            results = run_batch(
+                client,
+                conn_id,
+                items,
+                read_file=lambda item: (root / item.path).read_bytes(),
+                on_result=lambda result: self.app.call_from_thread(self._on_result, result),
+                verify=verify,
+                operation=operation,
+            )
+        except Exception as e:
+            self.app.call_from_thread(self._on_finished, [], str(e))
+            return
+        finally:
+            operation_log.close(operation)
+        self.app.call_from_thread(self._on_finished, results, None)
+
+    def _on_resumed(self, done: list[str]) -> None:
+        """Mark the files an interrupted upload already published."""
+        for path in done:
+            self._status[path] = "done earlier"
+        self._refresh_table()
+        self.app.notify(
+            f"Resuming an interrupted upload: {len(done)} file(s) already published",
+            severity="information",
+        )
+
+    def _on_result(self, result: dict[str, Any]) -> None:
+        """Show one file's result."""
+        if result["status"] != "success":
+            status = f"failed: {result.get('error', '')}"
+        elif "layers" in result:
+            # GeoPackages publish a layer per table
+            status = f"done, {len(result['layers'])} layer(s)"
+            if result.get("warning"):
+                status += ", some tables failed"
+        else:
+            status = "done"
+        verification = result.get("verification")
+        if verification:
+            if verification["verified"]:
+                status += ", verified"
+            elif verification["error"]:
+                status += f", not verified: {verification['error']}"
+            else:
+                status += ", CHECKSUM MISMATCH"
+        self._status[result["path"]] = status
+        self._refresh_table()
+
+    def _on_finished(self, results: list[dict[str, Any]], error: str | None) -> None:
+        """Summarize the batch."""
+        self._uploading = False
+        if error:
+            self.app.notify(f"Batch upload failed: {error}", severity="error")
+            return
+        failed = sum(1 for r in results if r["status"] != "success")
+        if failed:
+            self.app.notify(
+                f"{len(results) - failed} uploaded, {failed} failed", severity="warning"
+            )
+        else:
+            self.app.notify(f"{len(results)} file(s) uploaded", severity="information")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        button_id = event.button.id
+
+        if button_id == "btn-scan":
+            self._scan()
+        elif button_id == "btn-apply":
+            self._apply_edit()
+        elif button_id == "btn-upload":
+            self._upload()
+        elif button_id == "btn-back":
+            self.app.pop_screen()
@@ -0,0 +1,221 @@
+"""Layer attribution and WMS watermark dialog for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, Input, Label, Select, Static, Switch
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.branding import WATERMARK_POSITIONS, LayerAttribution, Watermark
+from apps.geoserver.client import get_geoserver_client
+
+# Attribution inputs: input id -> (field in API requests, placeholder)
+_ATTRIBUTION_INPUTS = {
+    "input-attribution-title": ("attributionTitle", "Attribution title, e.g. Data by ..."),
+    "input-attribution-href": ("attributionHref", "Attribution link (https://...)"),
+    "input-attribution-logo": ("attributionLogo", "Logo URL (https://...png)"),
+    "input-attribution-logo-width": ("attributionLogoWidth", "Logo width (px)"),
+    "input-attribution-logo-height": ("attributionLogoHeight", "Logo height (px)"),
+}
+
+
+class BrandingScreen(ModalScreen[None]):
+    """Dialog editing a layer's attribution and the WMS watermark."""
+
+    DEFAULT_CSS = """
+    BrandingScreen {
+        align: center middle;
+    }
+
+    .branding-dialog {
+        width: 90;
+        height: auto;
+        max-height: 90%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .branding-title {
+        text-style: bold;
+        margin-bottom: 1;
+    }
+
+    .branding-section {
+        text-style: bold;
+        margin-top: 1;
+    }
+
+    .branding-row {
+        height: auto;
+    }
+
+    .branding-row Input, .branding-row Select {
+        width: 1fr;
+    }
+
+    .branding-row Label {
+        padding: 1 1 0 0;
+    }
+
+    .branding-status {
+        height: auto;
+        margin-top: 1;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, workspace: str | None = None, layer: str | None = None):
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace of the layer whose attribution to edit
+            layer: Layer whose attribution to edit; only the watermark without one
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self.layer = layer
+        self._busy = False
+        # Whether a save is running, which closing would leave half done
+        self._saving = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="branding-dialog"):
+            yield Static("Branding", classes="branding-title")
+            if self.layer:
+                yield Static(
+                    f"Attribution of {self.workspace}:{self.layer}", classes="branding-section"
+                )
+                for input_id in ("input-attribution-title", "input-attribution-href"):
+                    yield Input(placeholder=_ATTRIBUTION_INPUTS[input_id][1], id=input_id)
+                with Horizontal(classes="branding-row"):
+                    for input_id in (
+                        "input-attribution-logo",
+                        "input-attribution-logo-width",
+                        "input-attribution-logo-height",
+                    ):
+                        yield Input(placeholder=_ATTRIBUTION_INPUTS[input_id][1], id=input_id)
+            yield Static("WMS watermark, drawn on every map", classes="branding-section")
+            with Horizontal(classes="branding-row"):
+                yield Label("Enabled")
+                yield Switch(id="switch-watermark")
+                yield Select(
+                    [(position, position) for position in WATERMARK_POSITIONS],
+                    value="BOT_RIGHT",
+                    allow_blank=False,
+                    id="select-watermark-position",
+                )
+                yield Input(placeholder="Transparency (0-100 %)", id="input-watermark-transparency")
+            yield Input(
+                placeholder="Watermark image URL, or a path in the data directory",
+                id="input-watermark-url",
+            )
+            yield Static("Loading...", id="branding-status", classes="branding-status")
+            with Horizontal(classes="branding-row"):
+                # Enabled once loaded, so a failed load can't save over the settings
+                yield Button("Save", id="btn-save", variant="primary", disabled=True)
+                yield Button("Close", id="btn-close")
+
+    def on_mount(self) -> None:
+        """Read the current settings."""
+        self._busy = True
+        self.run_worker(self._load, thread=True)
+
+    def _load(self) -> None:
+        """Fetch the attribution and watermark in a background thread."""
+        try:
+            client = get_geoserver_client(self.conn_id)
+            attribution = (
+                client.get_layer_attribution(self.workspace, self.layer) if self.layer else None
+            )
+            watermark = client.get_wms_watermark()
+        except (GeoServerError, ValueError) as e:
+            self.app.call_from_thread(self._on_failed, str(e))
+            return
+        self.app.call_from_thread(self._show, attribution, watermark)
+
+    def _show(self, attribution: LayerAttribution | None, watermark: Watermark) -> None:
+        """Fill the form."""
+        self._busy = False
+        if attribution:
+            values = attribution.to_dict()
+            for input_id, (name, _) in _ATTRIBUTION_INPUTS.items():
+                value = values[name]
+                self.query_one(f"#{input_id}", Input).value = "" if value is None else str(value)
+        self.query_one("#switch-watermark", Switch).value = watermark.enabled
+        self.query_one("#select-watermark-position", Select).value = watermark.position
+        self.query_one("#input-watermark-transparency", Input).value = str(watermark.transparency)
+        self.query_one("#input-watermark-url", Input).value = watermark.url or ""
+        self.query_one("#branding-status", Static).update("")
+        self.query_one("#btn-save", Button).disabled = False
+
+    def _save(self) -> None:
+        """Validate the form and save it in a background thread."""
+        if self._busy:
+            return
+        try:
+            attribution = None
+            if self.layer:
+                attribution = LayerAttribution.from_dict(
+                    {
+                        name: self.query_one(f"#{input_id}", Input).value
+                        for input_id, (name, _) in _ATTRIBUTION_INPUTS.items()
+                    }
+                )
+            watermark = Watermark.from_dict(
+                {
+                    "enabled": self.query_one("#switch-watermark", Switch).value,
+                    "url": self.query_one("#input-watermark-url", Input).value,
+                    "position": self.query_one("#select-watermark-position", Select).value,
+                    "transparency": self.query_one("#input-watermark-transparency", Input).value,
+                }
+            )
+        except ValueError as e:
+            self.app.notify(str(e), severity="error")
+            return
+
+        self._busy = self._saving = True
+        self.query_one("#branding-status", Static).update("Saving...")
+
+        def save() -> None:
+            try:
+                client = get_geoserver_client(self.conn_id)
+                if attribution is not None:
+                    client.update_layer_attribution(self.workspace, self.layer, attribution)
+                client.update_wms_watermark(watermark)
+            except (GeoServerError, ValueError) as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._on_saved)
+
+        self.run_worker(save, thread=True)
+
+    def _on_saved(self) -> None:
+        """Report the saved settings."""
+        self._busy = self._saving = False
+        self.query_one("#branding-status", Static).update("Saved")
+        self.app.notify("Branding saved")
+
+    def _on_failed(self, error: str) -> None:
+        """Report a failed call."""
+        self._busy = self._saving = False
+        self.query_one("#branding-status", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-save":
+            self._save()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless a save is running."""
+        if self._saving:
+            self.app.notify("Wait for the save to finish", severity="warning")
+            return
+        self.dismiss(None)
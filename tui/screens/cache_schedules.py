@@ -0,0 +1,274 @@
+"""Scheduled tile cache tasks screen for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Container, Horizontal
+from textual.screen import Screen
+from textual.widgets import Button, DataTable, Input, Label, Select, Static
+
+from apps.core.config import CacheSchedule, config_manager
+from apps.gwc.bounds import parse_bounds
+from apps.gwc.scheduler import get_cache_scheduler, next_run_time, validate_schedule
+
+from ..styles import check_label
+
+
+class ScheduleForm(Container):
+    """Form for adding a cache schedule."""
+
+    DEFAULT_CSS = """
+    ScheduleForm {
+        layout: vertical;
+        padding: 1;
+        background: $surface;
+        border: solid $primary;
+        height: auto;
+    }
+
+    ScheduleForm .form-row {
+        height: auto;
+        margin: 0 0 1 0;
+    }
+
+    ScheduleForm .form-label {
+        width: 15;
+    }
+
+    ScheduleForm .buttons {
+        height: 3;
+        margin-top: 1;
+    }
+    """
+
+    def compose(self) -> ComposeResult:
+        """Create form content."""
+        with Horizontal(classes="form-row"):
+            yield Label("Name:", classes="form-label")
+            yield Input(placeholder="Nightly reseed", id="input-name")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Connection:", classes="form-label")
+            yield Select([], id="select-connection", prompt="Select a connection...")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Layer:", classes="form-label")
+            yield Input(placeholder="workspace:layer", id="input-layer")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Cron:", classes="form-label")
+            yield Input(value="0 2 * * *", placeholder="0 2 * * *", id="input-cron")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Operation:", classes="form-label")
+            yield Select(
+                [("Reseed", "reseed"), ("Seed", "seed"), ("Truncate", "truncate")],
+                value="reseed",
+                id="select-operation",
+                allow_blank=False,
+            )
+
+        with Horizontal(classes="form-row"):
+            yield Label("Grid set:", classes="form-label")
+            yield Input(value="EPSG:900913", id="input-gridset")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Format:", classes="form-label")
+            yield Input(value="image/png", id="input-format")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Zoom:", classes="form-label")
+            yield Input(value="0", placeholder="start", id="input-zoom-start")
+            yield Input(value="10", placeholder="stop", id="input-zoom-stop")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Area:", classes="form-label")
+            yield Input(
+                placeholder="minlon, minlat, maxlon, maxlat (optional, whole layer if empty)",
+                id="input-area",
+            )
+
+        with Horizontal(classes="buttons"):
+            yield Button("Save", id="btn-save", variant="primary")
+            yield Button("Cancel", id="btn-cancel")
+
+
+class CacheSchedulesScreen(Screen):
+    """Screen for managing scheduled GWC seed/truncate tasks."""
+
+    DEFAULT_CSS = """
+    CacheSchedulesScreen {
+        layout: vertical;
+    }
+
+    .screen-header {
+        height: 3;
+        padding: 1;
+        background: $primary;
+    }
+
+    .schedules-table {
+        height: 1fr;
+        margin: 1;
+    }
+
+    .action-bar {
+        height: 3;
+        padding: 0 1;
+        background: $surface;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "app.pop_screen", "Back"),
+        ("a", "add_schedule", "Add"),
+        ("d", "delete_schedule", "Delete"),
+        ("e", "toggle_schedule", "Enable/Disable"),
+        ("x", "run_schedule", "Run Now"),
+    ]
+
+    def compose(self) -> ComposeResult:
+        """Create the cache schedules screen layout."""
+        yield Static("Scheduled Cache Tasks", classes="screen-header")
+
+        table = DataTable(id="schedules-table", classes="schedules-table", cursor_type="row")
+        table.add_columns(
+            "Name", "Connection", "Layer", "Operation", "Cron", "Next Run", "Last Run", "Enabled"
+        )
+        yield table
+
+        with Horizontal(classes="action-bar"):
+            yield Button("Add", id="btn-add", variant="primary")
+            yield Button("Run Now", id="btn-run")
+            yield Button("Enable/Disable", id="btn-toggle")
+            yield Button("Delete", id="btn-delete", variant="error")
+
+        yield ScheduleForm(id="schedule-form", classes="hidden")
+
+    def on_mount(self) -> None:
+        """Load schedules when screen mounts."""
+        connections = config_manager.list_connections()
+        select = self.query_one("#select-connection", Select)
+        select.set_options([(conn.name, conn.id) for conn in connections])
+        self._refresh_table()
+
+    def _refresh_table(self) -> None:
+        """Refresh the schedules table."""
+        table = self.query_one("#schedules-table", DataTable)
+        table.clear()
+
+        for schedule in config_manager.list_cache_schedules():
+            conn = config_manager.get_connection(schedule.connection_id)
+            next_run = next_run_time(schedule)
+            last_run = "-"
+            if schedule.last_run_at:
+                last_run = f"{schedule.last_run_at[:16].replace('T', ' ')} ({schedule.last_status})"
+            table.add_row(
+                schedule.name,
+                conn.name if conn else schedule.connection_id,
+                schedule.layer,
+                schedule.operation,
+                schedule.cron,
+                next_run[:16].replace("T", " ") if next_run else "-",
+                last_run,
+                check_label(schedule.enabled),
+                key=schedule.id,
+            )
+
+    def _selected_schedule(self) -> CacheSchedule | None:
+        """Get the schedule under the table cursor."""
+        table = self.query_one("#schedules-table", DataTable)
+        if table.row_count == 0:
+            self.app.notify("No schedule selected", severity="warning")
+            return None
+
+        row_key, _ = table.coordinate_to_cell_key(table.cursor_coordinate)
+        return config_manager.get_cache_schedule(str(row_key.value))
+
+    def action_add_schedule(self) -> None:
+        """Show the add schedule form."""
+        self.query_one("#schedule-form").remove_class("hidden")
+
+    def action_delete_schedule(self) -> None:
+        """Delete the selected schedule."""
+        schedule = self._selected_schedule()
+        if schedule:
+            config_manager.remove_cache_schedule(schedule.id)
+            self.app.notify(f"Schedule '{schedule.name}' deleted", severity="information")
+            self._refresh_table()
+
+    def action_toggle_schedule(self) -> None:
+        """Enable or disable the selected schedule."""
+        schedule = self._selected_schedule()
+        if schedule:
+            schedule.enabled = not schedule.enabled
+            config_manager.update_cache_schedule(schedule)
+            state = "enabled" if schedule.enabled else "disabled"
+            self.app.notify(f"Schedule '{schedule.name}' {state}", severity="information")
+            self._refresh_table()
+
+    def action_run_schedule(self) -> None:
+        """Run the selected schedule immediately."""
+        schedule = self._selected_schedule()
+        if not schedule:
+            return
+
+        result = get_cache_scheduler().run_schedule(schedule.id)
+        if result and result.last_status == "failed":
+            self.app.notify(f"Run failed: {result.last_error}", severity="error")
+        else:
+            self.app.notify(f"Schedule '{schedule.name}' started", severity="information")
+        self._refresh_table()
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        button_id = event.button.id
+
+        if button_id == "btn-add":
+            self.action_add_schedule()
+        elif button_id == "btn-run":
+            self.action_run_schedule()
+        elif button_id == "btn-toggle":
+            self.action_toggle_schedule()
+        elif button_id == "btn-delete":
+            self.action_delete_schedule()
+        elif button_id == "btn-save":
+            self._save_schedule()
+        elif button_id == "btn-cancel":
+            self.query_one("#schedule-form").add_class("hidden")
+
+    def _save_schedule(self) -> None:
+        """Save a schedule from form values."""
+        name = self.query_one("#input-name", Input).value.strip()
+        conn_id = self.query_one("#select-connection", Select).value
+        layer = self.query_one("#input-layer", Input).value.strip()
+        cron = self.query_one("#input-cron", Input).value.strip()
+
+        if not name or not layer or not cron or conn_id == Select.BLANK:
+            self.app.notify("Please fill in name, connection, layer and cron", severity="error")
+            return
+
+        area = self.query_one("#input-area", Input).value.replace(",", " ").split()
+        try:
+            schedule = CacheSchedule(
+                name=name,
+                connection_id=str(conn_id),
+                layer=layer,
+                cron=cron,
+                operation=str(self.query_one("#select-operation", Select).value),
+                grid_set=self.query_one("#input-gridset", Input).value.strip(),
+                format=self.query_one("#input-format", Input).value.strip(),
+                zoom_start=int(self.query_one("#input-zoom-start", Input).value),
+                zoom_stop=int(self.query_one("#input-zoom-stop", Input).value),
+                bounds=list(parse_bounds(area)) if area else None,
+            )
+            validate_schedule(schedule)
+        except ValueError as e:
+            self.app.notify(f"Invalid schedule: {e}", severity="error")
+            return
+
+        config_manager.add_cache_schedule(schedule)
+        self.app.notify(f"Schedule '{name}' saved", severity="information")
+        self.query_one("#input-name", Input).value = ""
+        self.query_one("#input-layer", Input).value = ""
+        self.query_one("#input-area", Input).value = ""
+        self.query_one("#schedule-form").add_class("hidden")
+        self._refresh_table()
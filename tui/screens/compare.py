@@ -0,0 +1,109 @@
+"""Server comparison screen for Kartoza CloudBench TUI."""
+
+from rich.text import Text
+from textual.app import ComposeResult
+from textual.screen import Screen
+from textual.widgets import DataTable, Static
+
+from apps.core.config import config_manager
+from apps.geoserver.comparison import ServerComparison, compare_servers
+
+
+class CompareScreen(Screen):
+    """Screen lining up versions, extensions and settings of all connections."""
+
+    DEFAULT_CSS = """
+    CompareScreen {
+        layout: vertical;
+    }
+
+    .screen-header {
+        height: 3;
+        padding: 1;
+        background: $primary;
+    }
+
+    .compare-table {
+        height: 1fr;
+        margin: 1;
+    }
+
+    .compare-status {
+        height: 3;
+        padding: 0 1;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "app.pop_screen", "Back"),
+        ("x", "run_compare", "Compare"),
+        ("d", "toggle_differences", "Differences Only"),
+    ]
+
+    def __init__(self) -> None:
+        """Initialize the comparison screen."""
+        super().__init__()
+        self._comparison: ServerComparison | None = None
+        self._differences_only = True
+
+    def compose(self) -> ComposeResult:
+        """Create the comparison screen layout."""
+        yield Static("Compare Servers", classes="screen-header")
+        yield DataTable(id="compare-table", classes="compare-table", cursor_type="row")
+        yield Static("Press x to compare all connections", id="status", classes="compare-status")
+
+    def action_run_compare(self) -> None:
+        """Compare every configured connection."""
+        conn_ids = [conn.id for conn in config_manager.list_connections()]
+        if len(conn_ids) < 2:
+            self.app.notify("Add at least two connections to compare", severity="error")
+            return
+
+        self.query_one("#status", Static).update("Reading server settings...")
+        self.run_worker(lambda: self._compare(conn_ids), thread=True)
+
+    def _compare(self, conn_ids: list[str]) -> None:
+        """Read the servers in a background thread."""
+        try:
+            comparison = compare_servers(conn_ids)
+        except Exception as e:
+            self.app.call_from_thread(
+                self.app.notify, f"Comparison failed: {str(e)}", severity="error"
+            )
+            return
+        self.app.call_from_thread(self._show_comparison, comparison)
+
+    def action_toggle_differences(self) -> None:
+        """Switch between all settings and the differing ones."""
+        self._differences_only = not self._differences_only
+        if self._comparison:
+            self._show_comparison(self._comparison)
+
+    def _show_comparison(self, comparison: ServerComparison) -> None:
+        """Fill the table, highlighting differing values."""
+        self._comparison = comparison
+        table = self.query_one("#compare-table", DataTable)
+        table.clear(columns=True)
+        table.add_column("Section", key="section")
+        table.add_column("Setting", key="setting")
+        for conn_id, name in comparison.connections:
+            table.add_column(name, key=conn_id)
+
+        rows = comparison.differences if self._differences_only else comparison.rows
+        for row in rows:
+            style = "bold yellow" if row.differs else ""
+            cells = [
+                Text("unreadable", style="dim italic")
+                if value is None
+                else Text(value or "-", style=style)
+                for value in row.values.values()
+            ]
+            table.add_row(row.section, row.key, *cells)
+
+        status = f"{len(comparison.differences)} difference(s)"
+        if self._differences_only:
+            status += " - press d to show all settings"
+        for conn_id, name in comparison.connections:
+            if conn_id in comparison.errors:
+                status += f"\n{name}: couldn't read {', '.join(comparison.errors[conn_id])}"
+        self.query_one("#status", Static).update(status)
@@ -0,0 +1,121 @@
+"""Confirmation dialog for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, Input, Static
+
+from apps.core.confirmation import confirmation_matches
+
+
+class ConfirmScreen(ModalScreen[bool]):
+    """Dialog that asks before a destructive operation.
+
+    With expected text, the confirm button stays disabled until that text,
+    usually the name of what is being deleted, is typed. Dismisses with
+    True when confirmed.
+    """
+
+    DEFAULT_CSS = """
+    ConfirmScreen {
+        align: center middle;
+    }
+
+    .confirm-dialog {
+        width: 70;
+        height: auto;
+        padding: 1 2;
+        background: $surface;
+        border: thick $error;
+    }
+
+    .confirm-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .confirm-message {
+        height: auto;
+        margin-bottom: 1;
+    }
+
+    .confirm-buttons {
+        height: 3;
+        align-horizontal: right;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "cancel", "Cancel"),
+    ]
+
+    def __init__(
+        self,
+        title: str,
+        message: str,
+        expected: str = "",
+        confirm_label: str = "Delete",
+    ) -> None:
+        """Initialize the dialog.
+
+        Args:
+            title: What is about to happen
+            message: Details shown above the buttons
+            expected: Text to type before confirming; a plain confirmation when empty
+            confirm_label: Label of the confirm button
+        """
+        super().__init__()
+        self.confirm_title = title
+        self.message = message
+        self.expected = expected
+        self.confirm_label = confirm_label
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="confirm-dialog"):
+            yield Static(self.confirm_title, classes="confirm-title")
+            yield Static(self.message, classes="confirm-message", markup=False)
+            if self.expected:
+                yield Static(f"Type {self.expected} to confirm:", markup=False)
+                yield Input(placeholder=self.expected, id="input-confirm")
+            with Horizontal(classes="confirm-buttons"):
+                yield Button(
+                    self.confirm_label,
+                    id="btn-confirm",
+                    variant="error",
+                    disabled=bool(self.expected),
+                )
+                yield Button("Cancel", id="btn-cancel")
+
+    def on_mount(self) -> None:
+        """Focus the input when typing is required, else the cancel button."""
+        if self.expected:
+            self.query_one("#input-confirm", Input).focus()
+        else:
+            self.query_one("#btn-cancel", Button).focus()
+
+    def _matches(self) -> bool:
+        """Whether the typed text confirms the operation."""
+        if not self.expected:
+            return True
+        return confirmation_matches(self.expected, self.query_one("#input-confirm", Input).value)
+
+    def on_input_changed(self, event: Input.Changed) -> None:
+        """Enable confirming once the expected text is typed."""
+        self.query_one("#btn-confirm", Button).disabled = not self._matches()
+
+    def on_input_submitted(self, event: Input.Submitted) -> None:
+        """Confirm with Enter once the expected text is typed."""
+        if self._matches():
+            self.dismiss(True)
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-confirm" and self._matches():
+            self.dismiss(True)
+        elif event.button.id == "btn-cancel":
+            self.dismiss(False)
+
+    def action_cancel(self) -> None:
+        """Close without confirming."""
+        self.dismiss(False)
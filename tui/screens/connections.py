@@ -3,11 +3,32 @@
 from textual.app import ComposeResult
 from textual.containers import Container, Horizontal, Vertical
 from textual.screen import Screen
-from textual.widgets import Button, DataTable, Input, Label, Static
+from textual.widgets import Button, Checkbox, DataTable, Input, Label, Select, Static
 
 import httpx
 
 from apps.core.config import Connection, config_manager
+from apps.core.confirmation import (
+    CONFIRM_AUTO,
+    CONFIRM_POLICIES,
+    CONFIRM_SIMPLE,
+    CONFIRM_TYPED,
+)
+from apps.core.connection_groups import (
+    ENVIRONMENTS,
+    MAX_BADGE_LENGTH,
+    normalize_environment,
+    validate_organization,
+)
+from apps.core.exceptions import TokenError
+from apps.core.oidc import AUTH_BASIC, AUTH_OIDC, DEFAULT_SCOPE, connection_auth, is_signed_in
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.cluster import ClusterResult, run_on_nodes
+from apps.geoserver.probe import ProbeResult, format_rate, run_probe
+from apps.geoserver.rest_xml import REST_FORMATS
+
+from ..styles import accessible_mode
+from .oidc_sign_in import OIDCSignInScreen
 
 
 class ConnectionForm(Container):
@@ -54,13 +75,121 @@ class ConnectionForm(Container):
             )
 
         with Horizontal(classes="form-row"):
+            yield Label("Public URL:", classes="form-label")
+            yield Input(placeholder="Optional, for proxied OWS links", id="input-public-url")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Cluster URLs:", classes="form-label")
+            yield Input(placeholder="Optional, comma-separated node URLs", id="input-cluster-urls")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Primary node:", classes="form-label")
+            yield Input(placeholder="First cluster URL when empty", id="input-cluster-primary")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Sign in with:", classes="form-label")
+            yield Select(
+                [("Username and password", AUTH_BASIC), ("OpenID Connect", AUTH_OIDC)],
+                value=AUTH_BASIC,
+                allow_blank=False,
+                id="input-auth-type",
+            )
+
+        with Horizontal(classes="form-row oidc-row"):
+            yield Label("Issuer URL:", classes="form-label")
+            yield Input(placeholder="https://sso.example.com/realms/gis", id="input-oidc-issuer")
+
+        with Horizontal(classes="form-row oidc-row"):
+            yield Label("Client ID:", classes="form-label")
+            yield Input(placeholder="Allowed to use the device flow", id="input-oidc-client-id")
+
+        with Horizontal(classes="form-row oidc-row"):
+            yield Label("Scope:", classes="form-label")
+            yield Input(placeholder=DEFAULT_SCOPE, id="input-oidc-scope")
+
+        with Horizontal(classes="form-row basic-row"):
             yield Label("Username:", classes="form-label")
             yield Input(placeholder="admin", id="input-username")
 
-        with Horizontal(classes="form-row"):
+        with Horizontal(classes="form-row basic-row"):
             yield Label("Password:", classes="form-label")
             yield Input(placeholder="geoserver", password=True, id="input-password")
 
+        with Horizontal(classes="form-row"):
+            yield Label("Group:", classes="form-label")
+            yield Input(placeholder="Folder in the sidebar, optional", id="input-group")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Environment:", classes="form-label")
+            yield Input(placeholder=", ".join(ENVIRONMENTS), id="input-environment")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Badge:", classes="form-label")
+            yield Input(
+                placeholder=f"Emoji or up to {MAX_BADGE_LENGTH} letters",
+                max_length=MAX_BADGE_LENGTH,
+                id="input-badge",
+            )
+
+        with Horizontal(classes="form-row"):
+            yield Label("Color:", classes="form-label")
+            yield Input(placeholder="#e53e3e, defaults to the environment's", id="input-color")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Max parallel:", classes="form-label")
+            yield Input(placeholder="0 = unlimited", type="integer", id="input-max-concurrent")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Max req/sec:", classes="form-label")
+            yield Input(placeholder="0 = unlimited", type="number", id="input-max-rps")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Keep-alive:", classes="form-label")
+            yield Input(placeholder="10", type="integer", id="input-pool-max-idle")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Idle timeout:", classes="form-label")
+            yield Input(placeholder="5 seconds", type="number", id="input-pool-idle-timeout")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Timeout:", classes="form-label")
+            yield Input(placeholder="30 seconds", type="number", id="input-response-timeout")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Max response:", classes="form-label")
+            yield Input(placeholder="64 MB, 0 = unlimited", type="number", id="input-max-response")
+
+        yield Checkbox("Use HTTP/2", id="input-http2")
+        yield Checkbox("Compress requests and responses", value=True, id="input-http-compression")
+        yield Checkbox("Truncate tile cache on data changes", id="input-auto-truncate")
+        yield Checkbox("Read-only (refuse changes to this server)", id="input-read-only")
+
+        with Horizontal(classes="form-row"):
+            yield Label("REST format:", classes="form-label")
+            yield Select(
+                [
+                    ("JSON, XML if JSON can't be read", "auto"),
+                    ("JSON", "json"),
+                    ("XML", "xml"),
+                ],
+                value="auto",
+                allow_blank=False,
+                id="input-rest-format",
+            )
+
+        with Horizontal(classes="form-row"):
+            yield Label("Confirm deletes:", classes="form-label")
+            yield Select(
+                [
+                    ("By typing the name on production", CONFIRM_AUTO),
+                    ("Always by typing the name", CONFIRM_TYPED),
+                    ("With a single key press", CONFIRM_SIMPLE),
+                ],
+                value=CONFIRM_AUTO,
+                allow_blank=False,
+                id="input-confirm-policy",
+            )
+
         with Horizontal(classes="buttons"):
             yield Button("Test", id="btn-test", variant="default")
             yield Button("Save", id="btn-save", variant="primary")
@@ -91,13 +220,22 @@ class ConnectionsScreen(Screen):
         padding: 0 1;
         background: $surface;
     }
+
+    .probe-results {
+        height: auto;
+        margin: 0 1;
+    }
     """
 
     BINDINGS = [
         ("escape", "app.pop_screen", "Back"),
         ("a", "add_connection", "Add"),
+        ("e", "edit_connection", "Edit"),
         ("d", "delete_connection", "Delete"),
         ("t", "test_connection", "Test"),
+        ("s", "sign_in", "Sign In"),
+        ("p", "probe_connection", "Probe"),
+        ("r", "reload_catalog", "Reload"),
     ]
 
     def compose(self) -> ComposeResult:
@@ -105,17 +243,24 @@ class ConnectionsScreen(Screen):
         yield Static("GeoServer Connections", classes="screen-header")
 
         table = DataTable(id="connections-table", classes="connections-table")
-        table.add_columns("Name", "URL", "Username", "Status")
+        table.add_columns("Name", "Group", "Environment", "URL", "Username", "Status")
         yield table
+        yield Static(id="probe-results", classes="probe-results")
 
         with Horizontal(classes="action-bar"):
             yield Button("Add", id="btn-add", variant="primary")
             yield Button("Edit", id="btn-edit")
             yield Button("Delete", id="btn-delete", variant="error")
             yield Button("Test", id="btn-test-selected")
+            yield Button("Sign In", id="btn-sign-in")
+            yield Button("Probe", id="btn-probe")
 
         yield ConnectionForm(id="connection-form", classes="hidden")
 
+    def __init__(self) -> None:
+        super().__init__()
+        self._editing_id: str | None = None  # Connection loaded into the form for editing
+
     def on_mount(self) -> None:
         """Load connections when screen mounts."""
         self._refresh_table()
@@ -125,16 +270,87 @@ class ConnectionsScreen(Screen):
         table = self.query_one("#connections-table", DataTable)
         table.clear()
 
-        config = config_manager.config
-        for conn in config.connections:
-            status = "\u2713 Active" if conn.is_active else "Inactive"
-            table.add_row(conn.name, conn.url, conn.username, status, key=conn.id)
+        for conn in config_manager.list_connections():
+            status = "Inactive"
+            if conn.is_active:
+                status = "Active" if accessible_mode() else "\u2713 Active"
+            if conn.source != "config":
+                status = f"From {conn.source} (not saved)"
+            if conn.read_only:
+                status = f"{status}, read-only"
+            if conn.auth_type == AUTH_OIDC:
+                signed_in = "signed in" if is_signed_in(conn) else "not signed in"
+                status = f"{status}, {signed_in}"
+            label = f"{conn.badge} {conn.name}" if conn.badge else conn.name
+            table.add_row(
+                label,
+                conn.group,
+                conn.environment,
+                conn.url,
+                conn.username,
+                status,
+                key=conn.id,
+            )
 
     def action_add_connection(self) -> None:
         """Show the add connection form."""
+        self._editing_id = None
+        self._clear_form()
         form = self.query_one("#connection-form")
         form.remove_class("hidden")
 
+    def action_edit_connection(self) -> None:
+        """Load the selected connection into the form for editing."""
+        table = self.query_one("#connections-table", DataTable)
+        if table.cursor_row is None or not table.row_count:
+            self.app.notify("No connection selected", severity="warning")
+            return
+
+        conn_id = str(list(table._data.keys())[table.cursor_row])
+        conn = config_manager.get_connection(conn_id)
+        if not conn:
+            return
+        if conn.source != "config":
+            self.app.notify(
+                f"Connection '{conn.name}' is not saved and can't be edited",
+                severity="warning",
+            )
+            return
+
+        self._editing_id = conn.id
+        self.query_one("#input-name", Input).value = conn.name
+        self.query_one("#input-url", Input).value = conn.url
+        self.query_one("#input-public-url", Input).value = conn.public_url
+        self.query_one("#input-cluster-urls", Input).value = conn.cluster_urls
+        self.query_one("#input-cluster-primary", Input).value = conn.cluster_primary
+        self.query_one("#input-username", Input).value = conn.username
+        self.query_one("#input-password", Input).value = ""
+        self.query_one("#input-group", Input).value = conn.group
+        self.query_one("#input-environment", Input).value = conn.environment
+        self.query_one("#input-badge", Input).value = conn.badge
+        self.query_one("#input-color", Input).value = conn.color
+        self.query_one("#input-max-concurrent", Input).value = str(conn.max_concurrent_requests)
+        self.query_one("#input-max-rps", Input).value = str(conn.max_requests_per_second)
+        self.query_one("#input-pool-max-idle", Input).value = str(conn.pool_max_idle)
+        self.query_one("#input-pool-idle-timeout", Input).value = str(conn.pool_idle_timeout)
+        self.query_one("#input-response-timeout", Input).value = str(conn.response_timeout)
+        self.query_one("#input-max-response", Input).value = f"{conn.max_response_mb:g}"
+        self.query_one("#input-http2", Checkbox).value = conn.http2
+        self.query_one("#input-http-compression", Checkbox).value = conn.http_compression
+        self.query_one("#input-auto-truncate", Checkbox).value = conn.auto_truncate_cache
+        self.query_one("#input-read-only", Checkbox).value = conn.read_only
+        rest_format = conn.rest_format if conn.rest_format in REST_FORMATS else "auto"
+        self.query_one("#input-rest-format", Select).value = rest_format
+        policy = conn.confirm_policy if conn.confirm_policy in CONFIRM_POLICIES else CONFIRM_AUTO
+        self.query_one("#input-confirm-policy", Select).value = policy
+        self.query_one("#input-auth-type", Select).value = conn.auth_type
+        self.query_one("#input-oidc-issuer", Input).value = conn.oidc_issuer
+        self.query_one("#input-oidc-client-id", Input).value = conn.oidc_client_id
+        self.query_one("#input-oidc-scope", Input).value = conn.oidc_scope
+        self._show_auth_fields(conn.auth_type)
+        self.query_one("#input-password", Input).placeholder = "(unchanged)"
+        self.query_one("#connection-form").remove_class("hidden")
+
     def on_button_pressed(self, event: Button.Pressed) -> None:
         """Handle button presses."""
         button_id = event.button.id
@@ -142,9 +358,13 @@ class ConnectionsScreen(Screen):
         if button_id == "btn-add":
             self.action_add_connection()
 
+        elif button_id == "btn-edit":
+            self.action_edit_connection()
+
         elif button_id == "btn-cancel":
             form = self.query_one("#connection-form")
             form.add_class("hidden")
+            self._editing_id = None
             self._clear_form()
 
         elif button_id == "btn-test":
@@ -159,15 +379,62 @@ class ConnectionsScreen(Screen):
         elif button_id == "btn-test-selected":
             self._test_selected()
 
+        elif button_id == "btn-sign-in":
+            self.action_sign_in()
+
+        elif button_id == "btn-probe":
+            self.action_probe_connection()
+
     def _clear_form(self) -> None:
         """Clear the form inputs."""
         self.query_one("#input-name", Input).value = ""
         self.query_one("#input-url", Input).value = ""
+        self.query_one("#input-public-url", Input).value = ""
+        self.query_one("#input-cluster-urls", Input).value = ""
+        self.query_one("#input-cluster-primary", Input).value = ""
         self.query_one("#input-username", Input).value = ""
         self.query_one("#input-password", Input).value = ""
+        self.query_one("#input-password", Input).placeholder = "geoserver"
+        self.query_one("#input-group", Input).value = ""
+        self.query_one("#input-environment", Input).value = ""
+        self.query_one("#input-badge", Input).value = ""
+        self.query_one("#input-color", Input).value = ""
+        self.query_one("#input-max-concurrent", Input).value = ""
+        self.query_one("#input-max-rps", Input).value = ""
+        self.query_one("#input-pool-max-idle", Input).value = ""
+        self.query_one("#input-pool-idle-timeout", Input).value = ""
+        self.query_one("#input-response-timeout", Input).value = ""
+        self.query_one("#input-max-response", Input).value = ""
+        self.query_one("#input-http2", Checkbox).value = False
+        self.query_one("#input-http-compression", Checkbox).value = True
+        self.query_one("#input-auto-truncate", Checkbox).value = False
+        self.query_one("#input-read-only", Checkbox).value = False
+        self.query_one("#input-rest-format", Select).value = "auto"
+        self.query_one("#input-confirm-policy", Select).value = CONFIRM_AUTO
+        self.query_one("#input-auth-type", Select).value = AUTH_BASIC
+        self.query_one("#input-oidc-issuer", Input).value = ""
+        self.query_one("#input-oidc-client-id", Input).value = ""
+        self.query_one("#input-oidc-scope", Input).value = ""
+        self._show_auth_fields(AUTH_BASIC)
+
+    def _show_auth_fields(self, auth_type: str) -> None:
+        """Show the password or the OIDC fields, whichever the auth type needs."""
+        for row in self.query(".basic-row"):
+            row.display = auth_type != AUTH_OIDC
+        for row in self.query(".oidc-row"):
+            row.display = auth_type == AUTH_OIDC
+
+    def on_select_changed(self, event: Select.Changed) -> None:
+        """Switch the form between password and OIDC sign-in."""
+        if event.select.id == "input-auth-type":
+            self._show_auth_fields(str(event.value))
 
     def _test_connection(self) -> None:
         """Test the connection from form values."""
+        if self.query_one("#input-auth-type", Select).value == AUTH_OIDC:
+            self.app.notify("Save the connection and sign in to test it", severity="warning")
+            return
+
         url = self.query_one("#input-url", Input).value
         username = self.query_one("#input-username", Input).value
         password = self.query_one("#input-password", Input).value
@@ -209,15 +476,102 @@ class ConnectionsScreen(Screen):
         url = self.query_one("#input-url", Input).value
         username = self.query_one("#input-username", Input).value
         password = self.query_one("#input-password", Input).value
-
-        if not all([name, url, username, password]):
+        existing = config_manager.get_connection(self._editing_id) if self._editing_id else None
+        if existing and not password:
+            password = existing.password  # Left empty to keep it
+
+        auth_type = str(self.query_one("#input-auth-type", Select).value)
+        oidc_issuer = self.query_one("#input-oidc-issuer", Input).value.strip()
+        oidc_client_id = self.query_one("#input-oidc-client-id", Input).value.strip()
+        oidc_scope = self.query_one("#input-oidc-scope", Input).value.strip() or DEFAULT_SCOPE
+        if auth_type == AUTH_OIDC:
+            credentials = [oidc_issuer, oidc_client_id]
+        else:
+            credentials = [username, password]
+
+        if not all([name, url, *credentials]):
             self.app.notify("Please fill in all fields", severity="error")
             return
 
-        conn = Connection(name=name, url=url, username=username, password=password)
-        config_manager.add_connection(conn)
+        group = self.query_one("#input-group", Input).value.strip()
+        environment = normalize_environment(self.query_one("#input-environment", Input).value)
+        badge = self.query_one("#input-badge", Input).value.strip()
+        color = self.query_one("#input-color", Input).value.strip()
+        errors = validate_organization(color, badge)
+        if errors:
+            self.app.notify("\n".join(errors), severity="error")
+            return
+
+        auto_truncate = self.query_one("#input-auto-truncate", Checkbox).value
+        max_concurrent = self.query_one("#input-max-concurrent", Input).value
+        max_rps = self.query_one("#input-max-rps", Input).value
+        pool_max_idle = self.query_one("#input-pool-max-idle", Input).value
+        pool_idle_timeout = self.query_one("#input-pool-idle-timeout", Input).value
+        response_timeout = self.query_one("#input-response-timeout", Input).value
+        max_response = self.query_one("#input-max-response", Input).value
+        http2 = self.query_one("#input-http2", Checkbox).value
+        http_compression = self.query_one("#input-http-compression", Checkbox).value
+        public_url = self.query_one("#input-public-url", Input).value.strip()
+        cluster_urls = ",".join(
+            u.strip() for u in self.query_one("#input-cluster-urls", Input).value.split(",")
+            if u.strip()
+        )
+        cluster_primary = self.query_one("#input-cluster-primary", Input).value.strip().rstrip("/")
+        nodes = [u.rstrip("/") for u in cluster_urls.split(",")]
+        if cluster_primary and cluster_primary not in nodes:
+            self.app.notify("The primary node must be one of the cluster URLs", severity="error")
+            return
+        read_only = self.query_one("#input-read-only", Checkbox).value
+        confirm_policy = str(self.query_one("#input-confirm-policy", Select).value)
+        rest_format = str(self.query_one("#input-rest-format", Select).value)
+
+        try:
+            max_concurrent_requests = max(0, int(max_concurrent or 0))
+            max_requests_per_second = max(0.0, float(max_rps or 0))
+            pool_max_idle_value = max(0, int(pool_max_idle or 10))
+            pool_idle_timeout_value = max(0.0, float(pool_idle_timeout or 5))
+            response_timeout_value = max(1.0, float(response_timeout or 30))
+            max_response_mb = max(0.0, float(max_response or 64))
+        except ValueError:
+            self.app.notify("Request limits and timeouts must be numbers", severity="error")
+            return
+
+        fields = {
+            "name": name,
+            "url": url,
+            "username": username,
+            "password": password,
+            "auto_truncate_cache": auto_truncate,
+            "max_concurrent_requests": max_concurrent_requests,
+            "max_requests_per_second": max_requests_per_second,
+            "pool_max_idle": pool_max_idle_value,
+            "pool_idle_timeout": pool_idle_timeout_value,
+            "http2": http2,
+            "response_timeout": response_timeout_value,
+            "http_compression": http_compression,
+            "rest_format": rest_format,
+            "max_response_mb": max_response_mb,
+            "public_url": public_url,
+            "cluster_urls": cluster_urls,
+            "cluster_primary": cluster_primary,
+            "group": group,
+            "environment": environment,
+            "badge": badge,
+            "color": color,
+            "read_only": read_only,
+            "confirm_policy": confirm_policy,
+            "auth_type": auth_type,
+            "oidc_issuer": oidc_issuer,
+            "oidc_client_id": oidc_client_id,
+            "oidc_scope": oidc_scope,
+        }
+        if existing:
+            config_manager.update_connection(existing.model_copy(update=fields))
+        else:
+            config_manager.add_connection(Connection(**fields))
 
         self.app.notify(f"Connection '{name}' saved", severity="information")
+        self._editing_id = None
         self._clear_form()
         self.query_one("#connection-form").add_class("hidden")
         self._refresh_table()
@@ -233,10 +587,36 @@ class ConnectionsScreen(Screen):
         if row_key:
             # Get connection ID from row key
             conn_id = str(list(table._data.keys())[table.cursor_row])
+            conn = config_manager.get_connection(conn_id)
+            if conn and conn.source != "config":
+                self.app.notify(
+                    f"Connection '{conn.name}' is not saved and can't be deleted",
+                    severity="warning",
+                )
+                return
             config_manager.remove_connection(conn_id)
             self.app.notify("Connection deleted", severity="information")
             self._refresh_table()
 
+    def action_sign_in(self) -> None:
+        """Sign the selected OIDC connection in to its provider."""
+        table = self.query_one("#connections-table", DataTable)
+        if table.cursor_row is None or not table.row_count:
+            self.app.notify("No connection selected", severity="warning")
+            return
+
+        conn_id = str(list(table._data.keys())[table.cursor_row])
+        conn = config_manager.get_connection(conn_id)
+        if not conn:
+            return
+        if conn.auth_type != AUTH_OIDC:
+            self.app.notify(
+                f"Connection '{conn.name}' signs in with a password", severity="warning"
+            )
+            return
+
+        self.app.push_screen(OIDCSignInScreen(conn), lambda _: self._refresh_table())
+
     def _test_selected(self) -> None:
         """Test the selected connection."""
         table = self.query_one("#connections-table", DataTable)
@@ -255,7 +635,7 @@ class ConnectionsScreen(Screen):
             with httpx.Client(timeout=10.0) as client:
                 response = client.get(
                     f"{base_url}/rest/about/version.json",
-                    auth=httpx.BasicAuth(conn.username, conn.password),
+                    auth=connection_auth(conn) or httpx.BasicAuth(conn.username, conn.password),
                 )
 
                 if response.status_code == 200:
@@ -267,5 +647,78 @@ class ConnectionsScreen(Screen):
                         f"Connection failed: {response.status_code}", severity="error"
                     )
 
+        except TokenError as e:
+            self.app.notify(e.message, severity="error")
         except Exception as e:
             self.app.notify(f"Error: {str(e)}", severity="error")
+
+    def action_probe_connection(self) -> None:
+        """Measure the selected connection's latency, render time and throughput."""
+        table = self.query_one("#connections-table", DataTable)
+        if table.cursor_row is None or not table.row_count:
+            self.app.notify("No connection selected", severity="warning")
+            return
+
+        conn_id = str(list(table._data.keys())[table.cursor_row])
+        conn = config_manager.get_connection(conn_id)
+        if not conn:
+            return
+        results = self.query_one("#probe-results", Static)
+        results.update(f"Probing '{conn.name}'...")
+
+        def probe() -> None:
+            try:
+                result = run_probe(get_geoserver_client(conn_id))
+            except Exception as e:
+                self.app.call_from_thread(results.update, f"[red]Probe failed: {e}[/]")
+                return
+            self.app.call_from_thread(self._show_probe, result)
+
+        self.run_worker(probe, thread=True, group="probe", exclusive=True)
+
+    def _show_probe(self, result: ProbeResult) -> None:
+        """Show a probe's steps and findings below the table."""
+        heading = f"[b]Probe of {result.connection}[/b]"
+        lines = [f"{heading} ({result.layer})" if result.layer else heading]
+        for step in result.steps:
+            if step.error:
+                lines.append(f"  {step.label}: [red]{step.error}[/]")
+                continue
+            line = f"  {step.label}: {step.ms:.0f} ms"
+            if step.first_byte_ms is not None:
+                line += f", first byte {step.first_byte_ms:.0f} ms"
+            if step.throughput is not None:
+                line += f", {format_rate(step.throughput)}"
+            lines.append(f"{line} [dim]({step.detail})[/]")
+        lines.extend(f"  [yellow]\u2022[/] {finding}" for finding in result.findings())
+        self.query_one("#probe-results", Static).update("\n".join(lines))
+
+    def action_reload_catalog(self) -> None:
+        """Reload the selected connection's catalog on each of its cluster nodes."""
+        table = self.query_one("#connections-table", DataTable)
+        if table.cursor_row is None or not table.row_count:
+            self.app.notify("No connection selected", severity="warning")
+            return
+
+        conn_id = str(list(table._data.keys())[table.cursor_row])
+        conn = config_manager.get_connection(conn_id)
+        if not conn:
+            return
+        results = self.query_one("#probe-results", Static)
+        results.update(f"Reloading '{conn.name}'...")
+
+        def reload() -> None:
+            result = run_on_nodes(get_geoserver_client(conn_id), "reload")
+            self.app.call_from_thread(self._show_cluster_result, conn.name, result)
+
+        self.run_worker(reload, thread=True, group="reload", exclusive=True)
+
+    def _show_cluster_result(self, name: str, result: ClusterResult) -> None:
+        """Show each node's reload outcome below the table."""
+        lines = [f"[b]Reload of {name}[/b]"]
+        for node in result.nodes:
+            if node.ok:
+                lines.append(f"  {node.url}: [green]reloaded[/] in {node.seconds:.1f} s")
+            else:
+                lines.append(f"  {node.url}: [red]{node.error}[/]")
+        self.query_one("#probe-results", Static).update("\n".join(lines))
@@ -0,0 +1,291 @@
+"""CQL filter builder dialog for Kartoza CloudBench TUI."""
+
+import asyncio
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.suggester import Suggester
+from textual.widgets import Button, Input, Select, Static
+
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.cql import (
+    KIND_GEOMETRY,
+    MATCH_ALL,
+    MATCH_ANY,
+    UNARY_OPERATORS,
+    Condition,
+    FilterAttribute,
+    build_cql,
+    describe_filter_attributes,
+    suggest_values,
+    validate_cql,
+)
+
+
+class ValueSuggester(Suggester):
+    """Completes condition values from a sample of the layer's features."""
+
+    def __init__(self, builder: "CqlBuilderScreen") -> None:
+        # Values are cached per attribute by the builder, not per typed text
+        super().__init__(use_cache=False, case_sensitive=True)
+        self.builder = builder
+
+    async def get_suggestion(self, value: str) -> str | None:
+        """Suggest the first sampled value starting with the typed text."""
+        attribute = self.builder.attribute
+        if attribute is None or attribute.kind == KIND_GEOMETRY or not value:
+            return None
+        try:
+            values = await asyncio.to_thread(self.builder.values_for, attribute.name, value)
+        except Exception:
+            return None
+        return next((v for v in values if v.lower().startswith(value.lower())), None)
+
+
+class CqlBuilderScreen(ModalScreen[str | None]):
+    """Dialog that builds a CQL filter from a layer's attributes.
+
+    Dismisses with the filter, an empty string to clear it, or None when
+    cancelled.
+    """
+
+    DEFAULT_CSS = """
+    CqlBuilderScreen {
+        align: center middle;
+    }
+
+    .cql-dialog {
+        width: 100;
+        height: auto;
+        max-height: 90%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .cql-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .cql-row {
+        height: auto;
+        margin-bottom: 1;
+    }
+
+    .cql-row Select {
+        width: 28;
+    }
+
+    .cql-row Input {
+        width: 1fr;
+    }
+
+    .cql-hint, .cql-conditions, .cql-status {
+        height: auto;
+        margin-bottom: 1;
+    }
+
+    .cql-hint {
+        color: $text-muted;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "cancel", "Cancel"),
+    ]
+
+    def __init__(self, conn_id: str, workspace: str, layer: str, cql: str = "") -> None:
+        """Initialize the builder.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace of the layer
+            layer: Layer name
+            cql: Filter to start from
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self.layer = layer
+        self.initial_cql = cql
+        self.attributes: list[FilterAttribute] = []
+        self.attribute: FilterAttribute | None = None
+        self.conditions: list[Condition] = []
+        self.match = MATCH_ALL
+        self._values: dict[tuple[str, str], list[str]] = {}
+
+    def compose(self) -> ComposeResult:
+        """Create the builder layout."""
+        with Vertical(classes="cql-dialog"):
+            yield Static(f"Filter {self.workspace}:{self.layer}", classes="cql-title")
+            with Horizontal(classes="cql-row"):
+                yield Select([], prompt="Attribute", id="cql-attribute")
+                yield Select([], prompt="Operator", id="cql-operator")
+                yield Input(placeholder="Value", id="cql-value", suggester=ValueSuggester(self))
+            yield Static("Loading attributes...", id="cql-hint", classes="cql-hint")
+            with Horizontal(classes="cql-row"):
+                yield Button("Add Condition", id="btn-add", variant="primary")
+                yield Button("Remove Last", id="btn-remove")
+                yield Button("Match All", id="btn-match")
+            yield Static("", id="cql-conditions", classes="cql-conditions")
+            yield Input(
+                value=self.initial_cql,
+                placeholder="CQL filter; edit it directly or add conditions above",
+                id="cql-expression",
+            )
+            yield Static("", id="cql-status", classes="cql-status")
+            with Horizontal(classes="cql-row"):
+                yield Button("Validate", id="btn-validate")
+                yield Button("Apply", id="btn-apply", variant="success")
+                yield Button("Clear Filter", id="btn-clear", variant="warning")
+                yield Button("Cancel", id="btn-cancel")
+
+    def on_mount(self) -> None:
+        """Load the layer's attributes."""
+
+        def load() -> None:
+            try:
+                client = get_geoserver_client(self.conn_id)
+                attributes = describe_filter_attributes(client, self.workspace, self.layer)
+            except Exception as e:
+                self.app.call_from_thread(self._set_hint, f"Could not read attributes: {e}")
+                return
+            self.app.call_from_thread(self._show_attributes, attributes)
+
+        self.run_worker(load, thread=True)
+
+    def _set_hint(self, text: str) -> None:
+        self.query_one("#cql-hint", Static).update(text)
+
+    def _show_attributes(self, attributes: list[FilterAttribute]) -> None:
+        self.attributes = attributes
+        self.query_one("#cql-attribute", Select).set_options(
+            [(f"{a.name} ({a.type})", a.name) for a in attributes]
+        )
+        self._set_hint("Pick an attribute; typing a value completes it from the layer's features")
+
+    def values_for(self, attribute: str, prefix: str) -> list[str]:
+        """Sample values of an attribute starting with a prefix, cached per prefix."""
+        key = (attribute, prefix.lower())
+        if key not in self._values:
+            client = get_geoserver_client(self.conn_id)
+            self._values[key] = suggest_values(
+                client, self.workspace, self.layer, attribute, prefix=prefix
+            )
+        return self._values[key]
+
+    def on_select_changed(self, event: Select.Changed) -> None:
+        """Offer the operators of the picked attribute."""
+        if event.select.id == "cql-attribute":
+            by_name = {a.name: a for a in self.attributes}
+            self.attribute = by_name.get(event.value) if event.value != Select.BLANK else None
+            operators = self.attribute.operators if self.attribute else []
+            self.query_one("#cql-operator", Select).set_options([(op, op) for op in operators])
+            if self.attribute:
+                self._show_sample(self.attribute)
+        elif event.select.id == "cql-operator":
+            unary = event.value in UNARY_OPERATORS
+            self.query_one("#cql-value", Input).disabled = unary
+
+    def _show_sample(self, attribute: FilterAttribute) -> None:
+        """List a few values of the attribute under the inputs."""
+        if attribute.kind == KIND_GEOMETRY:
+            self._set_hint("BBOX takes minx, miny, maxx, maxy in the layer's SRS")
+            return
+        self._set_hint(f"Sampling values of {attribute.name}...")
+
+        def sample() -> None:
+            try:
+                values = self.values_for(attribute.name, "")
+            except Exception as e:
+                text = f"Could not sample values: {e}"
+            else:
+                shown = ", ".join(values[:10]) or "none"
+                text = f"Values of {attribute.name} include: {shown}"
+            self.app.call_from_thread(self._set_hint, text)
+
+        self.run_worker(sample, thread=True, group="cql-sample", exclusive=True)
+
+    def _rebuild(self) -> None:
+        """Show the conditions and the CQL they make."""
+        lines = [
+            f"{i}. {c.attribute} {c.operator} {c.value}".rstrip()
+            for i, c in enumerate(self.conditions, 1)
+        ]
+        self.query_one("#cql-conditions", Static).update("\n".join(lines))
+        self.query_one("#btn-match", Button).label = (
+            "Match All" if self.match == MATCH_ALL else "Match Any"
+        )
+        try:
+            cql = build_cql(self.conditions, self.attributes, self.match) or ""
+        except ValueError as e:
+            self._set_status(str(e))
+            return
+        self.query_one("#cql-expression", Input).value = cql
+
+    def _set_status(self, text: str) -> None:
+        self.query_one("#cql-status", Static).update(text)
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle the dialog buttons."""
+        button_id = event.button.id
+        if button_id == "btn-add":
+            self._add_condition()
+        elif button_id == "btn-remove" and self.conditions:
+            self.conditions.pop()
+            self._rebuild()
+        elif button_id == "btn-match":
+            self.match = MATCH_ANY if self.match == MATCH_ALL else MATCH_ALL
+            self._rebuild()
+        elif button_id == "btn-validate":
+            self._validate()
+        elif button_id == "btn-apply":
+            self.dismiss(self.query_one("#cql-expression", Input).value.strip())
+        elif button_id == "btn-clear":
+            self.dismiss("")
+        elif button_id == "btn-cancel":
+            self.dismiss(None)
+
+    def _add_condition(self) -> None:
+        operator = self.query_one("#cql-operator", Select).value
+        if self.attribute is None or operator == Select.BLANK:
+            self._set_status("Pick an attribute and an operator first")
+            return
+        condition = Condition(
+            self.attribute.name, str(operator), self.query_one("#cql-value", Input).value
+        )
+        try:
+            build_cql([condition], self.attributes)
+        except ValueError as e:
+            self._set_status(str(e))
+            return
+        self.conditions.append(condition)
+        self.query_one("#cql-value", Input).value = ""
+        self._set_status("")
+        self._rebuild()
+
+    def _validate(self) -> None:
+        """Check the filter with GeoServer and count its matches."""
+        cql = self.query_one("#cql-expression", Input).value.strip()
+        if not cql:
+            self._set_status("The filter is empty")
+            return
+        self._set_status("Checking the filter...")
+
+        def check() -> None:
+            try:
+                client = get_geoserver_client(self.conn_id)
+                count = validate_cql(client, self.workspace, self.layer, cql)
+            except Exception as e:
+                text = f"Invalid filter: {e}"
+            else:
+                text = f"Valid: {count:,} matching feature{'' if count == 1 else 's'}"
+            self.app.call_from_thread(self._set_status, text)
+
+        self.run_worker(check, thread=True, group="cql-validate", exclusive=True)
+
+    def action_cancel(self) -> None:
+        """Close without changing the filter."""
+        self.dismiss(None)
@@ -0,0 +1,89 @@
+"""Data directory diff dialog for Kartoza CloudBench TUI."""
+
+from rich.text import Text
+from textual.app import ComposeResult
+from textual.containers import Vertical, VerticalScroll
+from textual.screen import ModalScreen
+from textual.widgets import Static
+
+from apps.geoserver.datadir_diff import DataDirDiff
+
+from .style_diff import append_diff_lines
+
+# Color of each kind of change
+CHANGE_STYLES = {"added": "green", "changed": "yellow", "removed": "red"}
+
+
+def render_datadir_diff(diff: DataDirDiff) -> Text:
+    """Color the files a change wrote, one after another."""
+    text = Text()
+    if not diff.changes:
+        text.append("No configuration file changed", style="dim")
+        return text
+    for change in diff.changes:
+        text.append(change.path, style="bold")
+        text.append(f" {change.change}\n", style=CHANGE_STYLES[change.change])
+        append_diff_lines(text, change.diff.splitlines())
+        text.append("\n")
+    return text
+
+
+class DataDirDiffScreen(ModalScreen[None]):
+    """Dialog showing the configuration files GeoServer wrote while watched."""
+
+    DEFAULT_CSS = """
+    DataDirDiffScreen {
+        align: center middle;
+    }
+
+    .datadir-dialog {
+        width: 90%;
+        height: 85%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .datadir-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .datadir-body {
+        height: 1fr;
+        margin: 1 0;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, diff: DataDirDiff) -> None:
+        """Initialize the dialog.
+
+        Args:
+            diff: The files added, removed or changed
+        """
+        super().__init__()
+        self.diff = diff
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        counts = {kind: 0 for kind in CHANGE_STYLES}
+        for change in self.diff.changes:
+            counts[change.change] += 1
+        summary = ", ".join(f"{count} {kind}" for kind, count in counts.items())
+        if self.diff.error:
+            summary = f"[red]{self.diff.error}[/]"
+        elif not self.diff.complete:
+            summary += " (incomplete: the folders hold more files than were read)"
+
+        with Vertical(classes="datadir-dialog"):
+            folders = ", ".join(folder or "/" for folder in self.diff.folders)
+            yield Static(f"Data directory changes in {folders}", classes="datadir-title")
+            yield Static(summary, id="datadir-summary")
+            with VerticalScroll(classes="datadir-body"):
+                yield Static(render_datadir_diff(self.diff), id="datadir-text")
+
+    def action_close(self) -> None:
+        """Close the dialog."""
+        self.dismiss(None)
@@ -0,0 +1,224 @@
+"""Default styles screen for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Container, Horizontal
+from textual.screen import Screen
+from textual.widgets import Button, DataTable, Input, Label, Static
+
+from apps.core.config import DefaultStyleRule, config_manager
+from apps.geoserver.default_styles import GEOMETRY_TYPES, parse_rules, save_rules
+
+
+class DefaultStyleForm(Container):
+    """Form for adding or editing a default style rule."""
+
+    DEFAULT_CSS = """
+    DefaultStyleForm {
+        layout: vertical;
+        padding: 1;
+        background: $surface;
+        border: solid $primary;
+        height: auto;
+    }
+
+    DefaultStyleForm .form-row {
+        height: auto;
+        margin: 0 0 1 0;
+    }
+
+    DefaultStyleForm .form-label {
+        width: 15;
+    }
+
+    DefaultStyleForm .buttons {
+        height: 3;
+        margin-top: 1;
+    }
+    """
+
+    def compose(self) -> ComposeResult:
+        """Create form content."""
+        with Horizontal(classes="form-row"):
+            yield Label("Workspace:", classes="form-label")
+            yield Input(value="*", placeholder="roads, or * for any", id="input-workspace")
+
+        for geometry in GEOMETRY_TYPES:
+            with Horizontal(classes="form-row"):
+                yield Label(f"{geometry.title()}:", classes="form-label")
+                yield Input(placeholder="style or workspace:style", id=f"input-{geometry}")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Connections:", classes="form-label")
+            yield Input(
+                placeholder="Names or IDs, comma-separated; all when empty",
+                id="input-connections",
+            )
+
+        with Horizontal(classes="buttons"):
+            yield Button("Save", id="btn-save", variant="primary")
+            yield Button("Cancel", id="btn-cancel")
+
+
+class DefaultStylesScreen(Screen):
+    """Screen for the styles newly published layers get.
+
+    Each rule names a style per geometry type for a workspace, or for
+    "*" when a workspace has no rule of its own.
+    """
+
+    DEFAULT_CSS = """
+    DefaultStylesScreen {
+        layout: vertical;
+    }
+
+    .screen-header {
+        height: 3;
+        padding: 1;
+        background: $primary;
+    }
+
+    .rules-table {
+        height: 1fr;
+        margin: 1;
+    }
+
+    .action-bar {
+        height: 3;
+        padding: 0 1;
+        background: $surface;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "app.pop_screen", "Back"),
+        ("a", "add_rule", "Add"),
+        ("e", "edit_rule", "Edit"),
+        ("d", "delete_rule", "Delete"),
+    ]
+
+    def __init__(self) -> None:
+        """Initialize the screen."""
+        super().__init__()
+        self._editing: int | None = None
+
+    def compose(self) -> ComposeResult:
+        """Create the default styles screen layout."""
+        yield Static("Default Styles of New Layers", classes="screen-header")
+
+        table = DataTable(id="rules-table", classes="rules-table", cursor_type="row")
+        table.add_columns("Workspace", *(g.title() for g in GEOMETRY_TYPES), "Connections")
+        yield table
+
+        with Horizontal(classes="action-bar"):
+            yield Button("Add", id="btn-add", variant="primary")
+            yield Button("Edit", id="btn-edit")
+            yield Button("Delete", id="btn-delete", variant="error")
+
+        yield DefaultStyleForm(id="rule-form", classes="hidden")
+
+    def on_mount(self) -> None:
+        """Load rules when the screen mounts."""
+        self._refresh_table()
+
+    def _connection_name(self, conn_id: str) -> str:
+        conn = config_manager.get_connection(conn_id)
+        return conn.name if conn else conn_id
+
+    def _refresh_table(self) -> None:
+        """Refresh the rules table."""
+        table = self.query_one("#rules-table", DataTable)
+        table.clear()
+        for index, rule in enumerate(config_manager.config.default_styles):
+            table.add_row(
+                rule.workspace,
+                *(getattr(rule, g) or "-" for g in GEOMETRY_TYPES),
+                ", ".join(self._connection_name(c) for c in rule.connections) or "all",
+                key=str(index),
+            )
+
+    def _selected_index(self) -> int | None:
+        """Get the position of the rule under the table cursor."""
+        table = self.query_one("#rules-table", DataTable)
+        if table.row_count == 0:
+            self.app.notify("No rule selected", severity="warning")
+            return None
+        row_key, _ = table.coordinate_to_cell_key(table.cursor_coordinate)
+        return int(str(row_key.value))
+
+    def _show_form(self, rule: DefaultStyleRule) -> None:
+        """Fill the form with a rule and show it."""
+        self.query_one("#input-workspace", Input).value = rule.workspace
+        for geometry in GEOMETRY_TYPES:
+            self.query_one(f"#input-{geometry}", Input).value = getattr(rule, geometry)
+        self.query_one("#input-connections", Input).value = ", ".join(
+            self._connection_name(c) for c in rule.connections
+        )
+        self.query_one("#rule-form").remove_class("hidden")
+        self.query_one("#input-workspace", Input).focus()
+
+    def action_add_rule(self) -> None:
+        """Show the form for a new rule."""
+        self._editing = None
+        self._show_form(DefaultStyleRule())
+
+    def action_edit_rule(self) -> None:
+        """Show the form for the selected rule."""
+        index = self._selected_index()
+        if index is not None:
+            self._editing = index
+            self._show_form(config_manager.config.default_styles[index])
+
+    def action_delete_rule(self) -> None:
+        """Delete the selected rule."""
+        index = self._selected_index()
+        if index is not None:
+            rules = list(config_manager.config.default_styles)
+            rule = rules.pop(index)
+            save_rules(rules)
+            self.app.notify(f"Rule for '{rule.workspace}' deleted", severity="information")
+            self._refresh_table()
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        button_id = event.button.id
+
+        if button_id == "btn-add":
+            self.action_add_rule()
+        elif button_id == "btn-edit":
+            self.action_edit_rule()
+        elif button_id == "btn-delete":
+            self.action_delete_rule()
+        elif button_id == "btn-save":
+            self._save_rule()
+        elif button_id == "btn-cancel":
+            self.query_one("#rule-form").add_class("hidden")
+
+    def _connection_ids(self, refs: str) -> list[str]:
+        """Connection IDs for comma-separated names or IDs; unknown ones kept as given."""
+        by_name = {conn.name: conn.id for conn in config_manager.list_connections()}
+        return [by_name.get(ref, ref) for ref in (r.strip() for r in refs.split(",")) if ref]
+
+    def _save_rule(self) -> None:
+        """Save the rule from form values, in place of the one being edited."""
+        values = {
+            geometry: self.query_one(f"#input-{geometry}", Input).value.strip()
+            for geometry in GEOMETRY_TYPES
+        }
+        values["workspace"] = self.query_one("#input-workspace", Input).value
+        values["connections"] = self._connection_ids(
+            self.query_one("#input-connections", Input).value
+        )
+        rules = [rule.model_dump() for rule in config_manager.config.default_styles]
+        if self._editing is None:
+            rules.append(values)
+        else:
+            rules[self._editing] = values
+        try:
+            save_rules(parse_rules(rules))
+        except ValueError as e:
+            self.app.notify(f"Invalid rule: {e}", severity="error")
+            return
+
+        self.app.notify(f"Rule for '{values['workspace'].strip()}' saved", severity="information")
+        self.query_one("#rule-form").add_class("hidden")
+        self._refresh_table()
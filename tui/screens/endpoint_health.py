@@ -0,0 +1,184 @@
+"""Layer endpoint check dialog for Kartoza CloudBench TUI."""
+
+from rich.text import Text
+from textual.app import ComposeResult
+from textual.containers import Vertical
+from textual.screen import ModalScreen
+from textual.widgets import DataTable, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.endpoint_health import (
+    FAIL,
+    PASS,
+    SERVICES,
+    SKIP,
+    EndpointReport,
+    verify_endpoints,
+)
+from apps.gwc.client import get_gwc_client
+
+# Color of each endpoint status in the matrix
+STATUS_STYLES = {PASS: "green", FAIL: "bold red", SKIP: "dim"}
+
+
+class EndpointHealthScreen(ModalScreen[None]):
+    """Dialog sending each layer's WMS, WFS, WMTS and legend endpoints a request.
+
+    The matrix fills in a layer at a time; the cell under the cursor shows
+    what GeoServer answered for that endpoint.
+    """
+
+    DEFAULT_CSS = """
+    EndpointHealthScreen {
+        align: center middle;
+    }
+
+    .endpoints-dialog {
+        width: 90%;
+        height: 85%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .endpoints-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .endpoints-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .endpoints-detail {
+        height: auto;
+        max-height: 6;
+    }
+
+    .endpoints-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close"), ("r", "refresh", "Check Again")]
+
+    def __init__(self, conn_id: str, workspace: str, layer: str | None = None) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace name
+            layer: Layer to check; every layer of the workspace if None
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self.layer = layer
+        self._reports: list[EndpointReport] = []
+        # Bumped on every check so rows of a superseded check are dropped
+        self._generation = 0
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        target = f"{self.workspace}:{self.layer}" if self.layer else self.workspace
+        with Vertical(classes="endpoints-dialog"):
+            yield Static(f"Verify Endpoints: {target}", classes="endpoints-title")
+            table = DataTable(id="endpoints-table", classes="endpoints-table", cursor_type="cell")
+            table.add_column("Layer", key="layer")
+            for service, label in SERVICES.items():
+                table.add_column(label, key=service)
+            yield table
+            yield Static("", id="endpoints-detail", classes="endpoints-detail", markup=False)
+            yield Static("Checking...", id="endpoints-summary", classes="endpoints-summary")
+
+    def on_mount(self) -> None:
+        """Start checking."""
+        self.action_refresh()
+
+    def action_refresh(self) -> None:
+        """Check the layers again in a background thread."""
+        self._generation += 1
+        generation = self._generation
+        self._reports = []
+        self.query_one("#endpoints-table", DataTable).clear()
+        self.query_one("#endpoints-detail", Static).update("")
+        self.query_one("#endpoints-summary", Static).update("Checking...")
+        self.run_worker(lambda: self._check(generation), thread=True)
+
+    def _check(self, generation: int) -> None:
+        """Check each layer, adding it to the matrix as it finishes."""
+        try:
+            client = get_geoserver_client(self.conn_id)
+            gwc_client = get_gwc_client(self.conn_id)
+            if self.layer:
+                layers = [self.layer]
+            else:
+                # Workspace layer lists name layers without their workspace
+                layers = [
+                    entry.get("name", "").rpartition(":")[2]
+                    for entry in client.list_layers(self.workspace)
+                ]
+        except GeoServerError as e:
+            self.app.call_from_thread(self._on_failed, e.message)
+            return
+
+        for layer in filter(None, layers):
+            try:
+                report = verify_endpoints(client, self.workspace, layer, gwc_client)
+            except GeoServerError as e:
+                self.app.call_from_thread(
+                    self.app.notify, f"Cannot read {layer}: {e.message}", severity="error"
+                )
+                continue
+            self.app.call_from_thread(self._add_report, generation, report)
+        self.app.call_from_thread(self._finish, generation)
+
+    def _add_report(self, generation: int, report: EndpointReport) -> None:
+        """Add a layer's row to the matrix, unless a newer check has started."""
+        if generation != self._generation:
+            return
+        self._reports.append(report)
+        statuses = {result.service: result.status for result in report.results}
+        self.query_one("#endpoints-table", DataTable).add_row(
+            report.layer,
+            *(
+                Text(statuses.get(service, ""), style=STATUS_STYLES.get(statuses.get(service), ""))
+                for service in SERVICES
+            ),
+        )
+
+    def _finish(self, generation: int) -> None:
+        """Count the layers with a failing endpoint."""
+        if generation != self._generation:
+            return
+        failing = [report.layer for report in self._reports if not report.ok]
+        text = f"{len(self._reports) - len(failing)} of {len(self._reports)} layer(s) pass"
+        if failing:
+            text += f"; [red]failing: {', '.join(failing)}[/]"
+        self.query_one("#endpoints-summary", Static).update(text)
+
+    def on_data_table_cell_highlighted(self, event: DataTable.CellHighlighted) -> None:
+        """Show what GeoServer answered for the endpoint under the cursor."""
+        row, column = event.coordinate.row, event.coordinate.column
+        if not 0 <= row < len(self._reports) or column == 0:
+            return
+        report = self._reports[row]
+        service = list(SERVICES)[column - 1]
+        result = next((r for r in report.results if r.service == service), None)
+        if result is None:
+            return
+        self.query_one("#endpoints-detail", Static).update(
+            f"{report.layer} {SERVICES[service]}: {result.status} in {result.elapsed_ms} ms"
+            + (f"\n{result.detail}" if result.detail else "")
+        )
+
+    def _on_failed(self, error: str) -> None:
+        """Report layers that can't be listed."""
+        self.query_one("#endpoints-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def action_close(self) -> None:
+        """Close the dialog."""
+        self.dismiss(None)
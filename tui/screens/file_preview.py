@@ -0,0 +1,128 @@
+"""Local file preview dialog for Kartoza CloudBench TUI."""
+
+from pathlib import Path
+
+from rich.console import Group
+from rich.syntax import Syntax
+from rich.text import Text
+from textual.app import ComposeResult
+from textual.containers import VerticalScroll
+from textual.screen import ModalScreen
+from textual.widgets import Static
+
+from apps.geoserver.terminal_map import render_halfblock
+from apps.upload.cog import gdal_available
+from apps.upload.preview import FileSummary, describe_file, render_thumbnail
+
+# Thumbnail size in pixels; two pixel rows per terminal line
+THUMBNAIL_WIDTH = 48
+THUMBNAIL_HEIGHT = 24
+
+
+def format_summary(summary: FileSummary) -> Text:
+    """Lay out a file summary as labelled lines."""
+    text = Text()
+    if summary.error:
+        text.append(summary.error, style="red")
+        return text
+
+    def line(label: str, value: str) -> None:
+        text.append(f"{label:<8}", style="bold")
+        text.append(f"{value}\n")
+
+    line("Driver", summary.driver or "-")
+    line("CRS", summary.crs or "none")
+    if summary.extent:
+        line("Extent", ", ".join(f"{v:.6g}" for v in summary.extent))
+    if summary.size:
+        line("Size", f"{summary.size[0]} x {summary.size[1]} px")
+    for band in summary.bands:
+        text.append(f"  {band}\n")
+    for layer in summary.layers:
+        count = f", {layer.feature_count} features" if layer.feature_count is not None else ""
+        text.append(f"{layer.name}", style="bold")
+        text.append(f" ({layer.geometry_type or 'no geometry'}{count})\n")
+        for name, type_ in layer.fields:
+            text.append(f"  {name}: {type_}\n", style="dim")
+    return text
+
+
+class FilePreviewScreen(ModalScreen[None]):
+    """Dialog showing what a local file holds before it is uploaded."""
+
+    DEFAULT_CSS = """
+    FilePreviewScreen {
+        align: center middle;
+    }
+
+    .preview-dialog {
+        width: 90%;
+        height: 80%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .preview-title {
+        text-style: bold;
+        height: 2;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "close", "Close"),
+        ("p", "close", "Close"),
+    ]
+
+    def __init__(self, path: Path) -> None:
+        """Initialize the preview.
+
+        Args:
+            path: Local file to preview
+        """
+        super().__init__()
+        self.path = path
+
+    def compose(self) -> ComposeResult:
+        """Create the preview layout."""
+        with VerticalScroll(classes="preview-dialog"):
+            yield Static(str(self.path), classes="preview-title")
+            yield Static("Reading file...", id="preview-body")
+
+    def on_mount(self) -> None:
+        """Describe the file in a background thread."""
+        self.run_worker(self._describe, thread=True)
+
+    def _describe(self) -> None:
+        """Run GDAL on the file and show the result."""
+        summary = describe_file(self.path)
+        thumbnail = None
+        if not summary.error and summary.kind != "style" and gdal_available():
+            try:
+                image = render_thumbnail(self.path, summary, THUMBNAIL_WIDTH, THUMBNAIL_HEIGHT)
+                thumbnail = Text.from_ansi(render_halfblock(image))
+            except ValueError:
+                # The summary is still worth showing
+                pass
+        self.app.call_from_thread(self._show, summary, thumbnail)
+
+    def _show(self, summary: FileSummary, thumbnail: Text | None) -> None:
+        """Show the summary, thumbnail or style text."""
+        body = self.query_one("#preview-body", Static)
+        if summary.style_text is not None:
+            body.update(
+                Syntax(
+                    summary.style_text,
+                    summary.style_language or "xml",
+                    line_numbers=True,
+                    word_wrap=True,
+                )
+            )
+        elif thumbnail is not None:
+            body.update(Group(thumbnail, Text(), format_summary(summary)))
+        else:
+            body.update(format_summary(summary))
+
+    def action_close(self) -> None:
+        """Close the preview."""
+        self.dismiss(None)
@@ -0,0 +1,302 @@
+"""GeoFence rules dialog for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, DataTable, Input, Select, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.geofence import ACCESS_TYPES, CATALOG_MODES, SERVICES, GeofenceRule
+
+# Form inputs: input id -> (rule field in API requests, placeholder)
+_INPUTS = {
+    "input-priority": ("priority", "Priority (lower first)"),
+    "input-user": ("user", "User (any)"),
+    "input-role": ("role", "Role (any)"),
+    "input-request": ("request", "Request (any), e.g. GetMap"),
+    "input-workspace": ("workspace", "Workspace (any)"),
+    "input-layer": ("layer", "Layer (any)"),
+    "input-address": ("addressRange", "Address range (any), e.g. 10.0.0.0/8"),
+    "input-area": ("area", "Allowed area WKT (LIMIT only)"),
+}
+
+
+def _any(value: str | None) -> str:
+    return value if value is not None else "*"
+
+
+class GeofenceRulesScreen(ModalScreen[None]):
+    """Dialog listing, adding, editing and deleting GeoFence data security rules."""
+
+    DEFAULT_CSS = """
+    GeofenceRulesScreen {
+        align: center middle;
+    }
+
+    .geofence-dialog {
+        width: 95%;
+        height: 90%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .geofence-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .geofence-row {
+        height: auto;
+    }
+
+    .geofence-row Input, .geofence-row Select {
+        width: 1fr;
+    }
+
+    .geofence-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .geofence-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self._rules: dict[int, GeofenceRule] = {}
+        self._busy = False
+        # Rule whose Delete was pressed once, awaiting the second press
+        self._pending_delete: int | None = None
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="geofence-dialog"):
+            yield Static("GeoFence Rules", classes="geofence-title")
+            table = DataTable(id="geofence-table", classes="geofence-table", cursor_type="row")
+            table.add_columns(
+                "Id", "Priority", "Access", "User", "Role", "Service", "Layer", "Address"
+            )
+            yield table
+            with Horizontal(classes="geofence-row"):
+                yield Input(placeholder=_INPUTS["input-priority"][1], id="input-priority")
+                yield Select(
+                    [(access, access) for access in ACCESS_TYPES],
+                    value=ACCESS_TYPES[0],
+                    allow_blank=False,
+                    id="select-access",
+                )
+                yield Select(
+                    [(service, service) for service in SERVICES],
+                    prompt="Any service",
+                    id="select-service",
+                )
+                yield Input(placeholder=_INPUTS["input-request"][1], id="input-request")
+            with Horizontal(classes="geofence-row"):
+                for input_id in ("input-user", "input-role", "input-workspace", "input-layer"):
+                    yield Input(placeholder=_INPUTS[input_id][1], id=input_id)
+            with Horizontal(classes="geofence-row"):
+                yield Input(placeholder=_INPUTS["input-address"][1], id="input-address")
+                yield Input(placeholder=_INPUTS["input-area"][1], id="input-area")
+                yield Select(
+                    [(mode, mode) for mode in CATALOG_MODES],
+                    prompt="Default catalog mode",
+                    id="select-catalog-mode",
+                )
+            yield Static(
+                "Select a rule to edit it. Empty fields match anything.",
+                id="geofence-summary",
+                classes="geofence-summary",
+            )
+            with Horizontal(classes="geofence-row"):
+                yield Button("Add", id="btn-add", variant="primary")
+                yield Button("Save", id="btn-save")
+                yield Button("Delete", id="btn-delete", variant="error")
+                yield Button("Clear", id="btn-clear")
+                yield Button("Close", id="btn-close")
+
+    def on_mount(self) -> None:
+        """List the rules."""
+        self._load()
+
+    def _run(self, message: str, work) -> None:
+        """Run a server call in a background thread, one at a time."""
+        if self._busy:
+            return
+        self._busy = True
+        self.query_one("#geofence-summary", Static).update(message)
+
+        def run() -> None:
+            try:
+                work()
+            except (GeoServerError, ValueError) as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+
+        self.run_worker(run, thread=True)
+
+    def _load(self) -> None:
+        """Fetch the rules in a background thread."""
+
+        def load() -> None:
+            geofence = get_geoserver_client(self.conn_id).geofence
+            if not geofence.is_available():
+                raise GeoServerError("The GeoFence extension isn't installed on this server")
+            rules = geofence.list_rules()
+            self.app.call_from_thread(self._show_rules, rules)
+
+        self._run("Loading rules...", load)
+
+    def _show_rules(self, rules: list[GeofenceRule]) -> None:
+        """Fill the table in priority order."""
+        self._busy = False
+        self._rules = {rule.id: rule for rule in rules if rule.id is not None}
+        table = self.query_one("#geofence-table", DataTable)
+        table.clear()
+        for rule in rules:
+            access = rule.access
+            if rule.area:
+                access += " (area)"
+            table.add_row(
+                str(rule.id),
+                str(rule.priority),
+                access,
+                _any(rule.user),
+                _any(rule.role),
+                " ".join(filter(None, [_any(rule.service), rule.request])),
+                f"{_any(rule.workspace)}:{_any(rule.layer)}",
+                _any(rule.address_range),
+                key=str(rule.id),
+            )
+        self.query_one("#geofence-summary", Static).update(
+            f"{len(rules)} rule(s); the first match by priority decides"
+        )
+
+    def on_data_table_row_highlighted(self, event: DataTable.RowHighlighted) -> None:
+        """Load the highlighted rule into the form."""
+        self._pending_delete = None
+        rule = self._rules.get(int(event.row_key.value)) if event.row_key.value else None
+        if rule is None:
+            return
+        values = rule.to_dict()
+        for input_id, (name, _) in _INPUTS.items():
+            value = values[name]
+            self.query_one(f"#{input_id}", Input).value = "" if value is None else str(value)
+        self.query_one("#select-access", Select).value = rule.access
+        self.query_one("#select-service", Select).value = rule.service or Select.BLANK
+        self.query_one("#select-catalog-mode", Select).value = rule.catalog_mode or Select.BLANK
+
+    def _form(self) -> dict:
+        """Read the form as an API request body."""
+        data = {
+            name: self.query_one(f"#{input_id}", Input).value
+            for input_id, (name, _) in _INPUTS.items()
+        }
+        for select_id, name in (
+            ("#select-access", "access"),
+            ("#select-service", "service"),
+            ("#select-catalog-mode", "catalogMode"),
+        ):
+            value = self.query_one(select_id, Select).value
+            data[name] = None if value is Select.BLANK else value
+        return data
+
+    def _selected_id(self) -> int | None:
+        """Get the id of the highlighted rule."""
+        table = self.query_one("#geofence-table", DataTable)
+        if not table.row_count:
+            return None
+        key = table.coordinate_to_cell_key(table.cursor_coordinate).row_key.value
+        return int(key) if key else None
+
+    def _save(self, rule_id: int | None) -> None:
+        """Add the form as a new rule, or save it over the selected one."""
+        try:
+            rule = GeofenceRule.from_dict(self._form(), rule_id)
+        except ValueError as e:
+            self.app.notify(str(e), severity="error")
+            return
+
+        def save() -> None:
+            geofence = get_geoserver_client(self.conn_id).geofence
+            saved_id = geofence.update_rule(rule) if rule_id else geofence.create_rule(rule)
+            self.app.call_from_thread(self._on_saved, saved_id, rule_id is None)
+
+        self._run("Saving rule...", save)
+
+    def _on_saved(self, rule_id: int, added: bool) -> None:
+        """Report the saved rule and list the rules again."""
+        self._busy = False
+        self.app.notify(f"Rule {rule_id} {'added' if added else 'saved'}")
+        self._load()
+
+    def _delete(self) -> None:
+        """Delete the selected rule on the second press."""
+        rule_id = self._selected_id()
+        if rule_id is None:
+            return
+        if self._pending_delete != rule_id:
+            self._pending_delete = rule_id
+            self.app.notify(f"Press Delete again to delete rule {rule_id}", severity="warning")
+            return
+        self._pending_delete = None
+
+        def delete() -> None:
+            get_geoserver_client(self.conn_id).geofence.delete_rule(rule_id)
+            self.app.call_from_thread(self._on_deleted, rule_id)
+
+        self._run(f"Deleting rule {rule_id}...", delete)
+
+    def _on_deleted(self, rule_id: int) -> None:
+        """Report the deletion and list the rules again."""
+        self._busy = False
+        self.app.notify(f"Rule {rule_id} deleted")
+        self._load()
+
+    def _clear(self) -> None:
+        """Empty the form for a new rule."""
+        for input_id in _INPUTS:
+            self.query_one(f"#{input_id}", Input).value = ""
+        self.query_one("#select-access", Select).value = ACCESS_TYPES[0]
+        self.query_one("#select-service", Select).value = Select.BLANK
+        self.query_one("#select-catalog-mode", Select).value = Select.BLANK
+
+    def _on_failed(self, error: str) -> None:
+        """Report a failed call."""
+        self._busy = False
+        self.query_one("#geofence-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-add":
+            self._save(None)
+        elif event.button.id == "btn-save":
+            rule_id = self._selected_id()
+            if rule_id is None:
+                self.app.notify("Select a rule to save over", severity="warning")
+            else:
+                self._save(rule_id)
+        elif event.button.id == "btn-delete":
+            self._delete()
+        elif event.button.id == "btn-clear":
+            self._clear()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless a call is running."""
+        if self._busy:
+            self.app.notify("Wait for the current operation to finish", severity="warning")
+            return
+        self.dismiss(None)
@@ -1,18 +1,78 @@
 """GeoServer browser screen for Kartoza CloudBench TUI."""
 
+import subprocess
+import time
+from pathlib import Path
+
 from textual.app import ComposeResult
 from textual.containers import Container, Horizontal, Vertical
 from textual.screen import Screen
 from textual.widgets import Button, Label, Select, Static, Tree
 from textual.widgets.tree import TreeNode
 
+from apps.core.checksum import write_checksum_file
 from apps.core.config import config_manager
-from apps.geoserver.client import GeoServerClient
+from apps.core.confirmation import requires_typed_confirmation
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.backup import Execution
+from apps.geoserver.client import GeoServerClient, get_geoserver_client, layergroup_entries
+from apps.geoserver.datadir_diff import GLOBAL_FOLDERS, DataDirWatch
+from apps.geoserver.impact import workspace_impact
+from apps.geoserver.links import copy_targets
+from apps.geoserver.managed import managed_state
+from apps.geoserver.monitor import layer_request_stats
+from apps.geoserver.recent import RecentItem, recent_items
+from apps.geoserver.snapshot import build_snapshot
+from apps.geoserver.store_status import MAX_STATUS_STORES, list_stores_with_status
+from apps.geoserver.style_diff import StyleDiff, diff_connection_style, diff_connection_styles
+from apps.geoserver.style_edit import ExternalStyleEdit
+from apps.gwc.client import get_gwc_client
+from apps.gwc.invalidation import cached_workspace_layers, clear_workspace_caches
+from apps.gwc.usage import get_layer_quota_usage
+from apps.sync.services import get_sync_service
+
+from ..styles import node_label, size_label
+from ..widgets import ResourceTreeWidget, Splitter
+from .attributes import AttributeTableScreen
+from .batch_action import BatchActionScreen
+from .branding import BrandingScreen
+from .confirm import ConfirmScreen
+from .datadir_diff import DataDirDiffScreen
+from .endpoint_health import EndpointHealthScreen
+from .geofence import GeofenceRulesScreen
+from .hit_ratio import HitRatioScreen
+from .map_export import MapExportScreen
+from .map_preview import MapPreviewScreen
+from .picker import PickerScreen
+from .qgis_publish import QGISPublishScreen
+from .render_benchmark import RenderBenchmarkScreen
+from .seed_estimate import SeedEstimateScreen
+from .style_assets import StyleAssetsScreen
+from .style_delete import StyleDeleteScreen
+from .style_diff import StyleDiffScreen
+from .style_import import StyleImportScreen
+from .terria_init import TerriaInitScreen
+from .tile_endpoints import TileEndpointsScreen
+from .workspace_create import WorkspaceCreateScreen
+
+# Tree node type -> action of the recent items it lists
+RECENT_NODES = {"recentmodified": "modified", "recentadded": "added"}
 
+# Node type, label and whether it expands, for the nodes under each workspace
+WORKSPACE_CATEGORIES = (
+    ("datastores", "Data Stores", False),
+    ("coveragestores", "Coverage Stores", False),
+    ("layers", "Layers", True),
+    ("styles", "Styles", True),
+    ("layergroups", "Layer Groups", True),
+)
 
-class ResourceTree(Tree):
+
+class ResourceTree(ResourceTreeWidget):
     """Tree widget for browsing GeoServer resources."""
 
+    MARKABLE_TYPES = frozenset({"layer", "style", "layergroup"})
+
     DEFAULT_CSS = """
     ResourceTree {
         background: $surface;
@@ -52,11 +112,10 @@ class GeoServerScreen(Screen):
 
     .resource-tree {
         width: 40%;
-        border-right: solid $primary;
     }
 
     .detail-panel {
-        width: 60%;
+        width: 1fr;
         padding: 1;
     }
 
@@ -65,11 +124,73 @@ class GeoServerScreen(Screen):
         padding: 0 1;
         background: $surface;
     }
+
+    .batch-bar {
+        height: 3;
+        padding: 0 1;
+        background: $primary-darken-2;
+        display: none;
+    }
+
+    .batch-bar.-active {
+        display: block;
+    }
+
+    .batch-bar Label {
+        padding: 1 1 0 0;
+    }
     """
 
+    # Actions and buttons that change the server, hidden for read-only connections
+    WRITE_ACTIONS = frozenset(
+        {
+            "edit_style",
+            "delete_style",
+            "backup_workspace",
+            "enable_cache",
+            "seed_cache",
+            "import_styles",
+            "publish_qgis",
+            "style_assets",
+            "branding",
+        }
+    )
+    WRITE_BUTTONS = (
+        "#btn-create-ws",
+        "#btn-upload",
+        "#btn-delete",
+        "#btn-batch-delete",
+        "#btn-batch-truncate",
+        "#btn-batch-group",
+    )
+
     BINDINGS = [
         ("escape", "app.pop_screen", "Back"),
         ("r", "refresh", "Refresh"),
+        ("left_square_bracket", "resize_tree(-4)", "Narrower"),
+        ("right_square_bracket", "resize_tree(4)", "Wider"),
+        ("left_curly_bracket", "collapse('tree')", "Hide Tree"),
+        ("right_curly_bracket", "collapse('details')", "Hide Details"),
+        ("e", "edit_style", "Edit in $EDITOR"),
+        ("x", "delete_style", "Delete Style"),
+        ("d", "diff_style", "Diff Style"),
+        ("y", "copy", "Copy"),
+        ("m", "preview_map", "Map Preview"),
+        ("t", "attribute_table", "Attribute Table"),
+        ("w", "tile_endpoints", "Tile URLs"),
+        ("k", "enable_cache", "Cache Layer"),
+        ("s", "seed_cache", "Seed Cache"),
+        ("b", "backup_workspace", "Backup Workspace"),
+        ("i", "import_styles", "Import Styles"),
+        ("p", "publish_qgis", "Publish QGIS Project"),
+        ("a", "style_assets", "Style Graphics"),
+        ("f", "geofence_rules", "GeoFence Rules"),
+        ("n", "branding", "Branding"),
+        ("z", "render_benchmark", "Render Benchmark"),
+        ("o", "hit_ratio", "Cache Hit Ratio"),
+        ("g", "watch_datadir", "Watch Data Directory"),
+        ("c", "terria_init", "Terria Catalog"),
+        ("v", "verify_endpoints", "Verify Endpoints"),
     ]
 
     def __init__(self, **kwargs):
@@ -77,6 +198,23 @@ class GeoServerScreen(Screen):
         super().__init__(**kwargs)
         self.current_connection_id: str | None = None
         self.client: GeoServerClient | None = None
+        self.selected_workspace: str | None = None
+        # Workspace whose impact report is shown, awaiting a second Delete
+        self._pending_delete: str | None = None
+        # (workspace, style) -> edit whose content failed validation, resumed on the next edit
+        self._style_edits: dict[tuple[str, str], ExternalStyleEdit] = {}
+        # Whether the server has the Backup and Restore plugin
+        self._can_backup = False
+        # Whether the server has the GeoFence extension
+        self._can_geofence = False
+        # Why the server refuses changes from these credentials, if it does
+        self._write_denied: str | None = None
+        # Data directory snapshot taken by `g`, compared on the next press
+        self._datadir_watch: DataDirWatch | None = None
+        # Layer whose usage figures the detail panel is showing
+        self._usage_layer: str | None = None
+        # Connection to select once the screen has listed the connections
+        self._initial_connection: str | None = None
 
     def compose(self) -> ComposeResult:
         """Create the GeoServer screen layout."""
@@ -93,32 +231,274 @@ class GeoServerScreen(Screen):
 
         with Container(classes="browser-container"):
             yield ResourceTree(id="resource-tree", classes="resource-tree")
+            yield Splitter()
             yield Container(
                 Static("Select a resource to view details", id="detail-content"),
                 classes="detail-panel",
             )
 
+        with Horizontal(classes="batch-bar"):
+            yield Label("", id="marked-count")
+            yield Button("Delete", id="btn-batch-delete", variant="error")
+            yield Button("Truncate Cache", id="btn-batch-truncate")
+            yield Button("Export Configs", id="btn-batch-export")
+            yield Button("New Group", id="btn-batch-group")
+            yield Button("Web Map", id="btn-map-export")
+            yield Button("Snapshot", id="btn-snapshot")
+            yield Button("Clear Marks", id="btn-batch-clear")
+
         with Horizontal(classes="action-bar"):
             yield Button("Create Workspace", id="btn-create-ws")
             yield Button("Upload Data", id="btn-upload")
+            yield Button("System Status", id="btn-system-status")
             yield Button("Delete", id="btn-delete", variant="error")
 
     def on_mount(self) -> None:
         """Load connections when screen mounts."""
+        self._apply_layout()
         self._refresh_connections()
 
+    def _apply_layout(self) -> None:
+        """Restore the saved panel widths and collapsed panel."""
+        layout = config_manager.config.tui_layout
+        tree = self.query_one("#resource-tree", ResourceTree)
+        details = self.query_one(".detail-panel", Container)
+        splitter = self.query_one(Splitter)
+
+        tree.display = layout.collapsed != "tree"
+        details.display = layout.collapsed != "details"
+        splitter.display = not layout.collapsed
+        if layout.collapsed == "details":
+            tree.styles.width = "1fr"  # A lone tree takes the full width
+        else:
+            tree.styles.width = layout.split_width or "40%"
+
+    def action_resize_tree(self, delta: int) -> None:
+        """Make the tree panel wider or narrower."""
+        layout = config_manager.config.tui_layout
+        if layout.collapsed:
+            return
+        tree = self.query_one("#resource-tree", ResourceTree)
+        layout.split_width = self.query_one(Splitter).resize_to(tree.size.width + delta)
+        config_manager.save()
+
+    def action_collapse(self, panel: str) -> None:
+        """Hide a panel, or show it again if it is hidden."""
+        layout = config_manager.config.tui_layout
+        layout.collapsed = "" if layout.collapsed == panel else panel
+        config_manager.save()
+        self._apply_layout()
+
+    def on_splitter_resized(self, event: Splitter.Resized) -> None:
+        """Remember the width the splitter was dragged to."""
+        config_manager.config.tui_layout.split_width = event.width
+        config_manager.save()
+
+    def _node_path(self, node: TreeNode) -> str:
+        """Path of a node made of the names along its branch."""
+        names = []
+        while node is not None and node.data:
+            names.append(node.data.get("name") or node.data.get("type", ""))
+            node = node.parent
+        return "/".join(reversed(names))
+
+    def _save_expanded(self, node: TreeNode, expanded: bool) -> None:
+        """Record that a node was expanded or collapsed for this connection."""
+        if not self.current_connection_id or not node.data:
+            return
+        paths = config_manager.config.tui_layout.expanded_paths
+        current = paths.setdefault(self.current_connection_id, [])
+        path = self._node_path(node)
+        if expanded and path not in current:
+            current.append(path)
+        elif not expanded and path in current:
+            current.remove(path)
+        config_manager.save()
+
+    def on_tree_node_expanded(self, event: Tree.NodeExpanded) -> None:
+        """Remember expanded nodes and load layers on first expansion."""
+        self._save_expanded(event.node, True)
+        data = event.node.data or {}
+        if data.get("type") in ("layers", "styles", "layergroups") and not event.node.children:
+            self._load_item_nodes(event.node, data["type"], data["workspace"])
+        elif data.get("type") in RECENT_NODES and not event.node.children:
+            self._load_recent_nodes(event.node, RECENT_NODES[data["type"]])
+
+    def on_tree_node_collapsed(self, event: Tree.NodeCollapsed) -> None:
+        """Forget collapsed nodes."""
+        self._save_expanded(event.node, False)
+
+    def _restore_expanded(self, tree: "ResourceTree") -> None:
+        """Expand the nodes that were expanded last session."""
+        paths = config_manager.config.tui_layout.expanded_paths
+        saved = set(paths.get(self.current_connection_id or "", []))
+        if not saved:
+            return
+
+        def walk(node: TreeNode) -> None:
+            for child in node.children:
+                if child.allow_expand and self._node_path(child) in saved:
+                    child.expand()
+                    walk(child)
+
+        walk(tree.root)
+
+    def _load_item_nodes(self, parent: TreeNode, category: str, workspace: str) -> None:
+        """Add a node per layer, style or layer group of a workspace, so they can be acted on."""
+        if not self.client:
+            return
+        try:
+            if category == "layers":
+                items = self.client.list_layers(workspace)
+            elif category == "layergroups":
+                items = self.client.list_layergroups(workspace)
+            else:
+                items = self.client.list_styles(workspace)
+        except Exception as e:
+            self.app.notify(f"Error loading {category}: {str(e)}", severity="error")
+            return
+        node_type = category.rstrip("s")
+        children = []
+        for item in items:
+            name = item.get("name", "Unknown")
+            data = {"type": node_type, "workspace": workspace, "name": name}
+            children.append((node_label(node_type, name), data, False))
+        self.query_one("#resource-tree", ResourceTree).sync_children(parent, children)
+
+    def _load_recent_nodes(self, parent: TreeNode, action: str) -> None:
+        """Add a node per recently added or modified layer and style, once they load."""
+        if not self.client:
+            return
+        client = self.client
+
+        def add(items: list[RecentItem]) -> None:
+            children: list[tuple[str, dict | None, bool]] = []
+            for item in items:
+                qualified = f"{item.workspace}:{item.name}" if item.workspace else item.name
+                when = item.time.astimezone().strftime("%Y-%m-%d %H:%M")
+                data = {"type": item.type, "workspace": item.workspace, "name": item.name}
+                children.append((node_label(item.type, f"{qualified} ({when})"), data, False))
+            if not items:
+                children.append(("Nothing yet", None, False))
+            tree = self.query_one("#resource-tree", ResourceTree)
+            with tree.keeping_cursor():
+                tree.sync_children(parent, children)
+
+        def load() -> None:
+            try:
+                items = recent_items(client)[action]
+            except Exception as e:
+                self.app.call_from_thread(
+                    self.app.notify, f"Error loading recent items: {str(e)}", severity="error"
+                )
+                return
+            self.app.call_from_thread(add, items)
+
+        self.run_worker(load, thread=True, group="recent-items")
+
+    def on_resource_tree_widget_marks_changed(
+        self, event: ResourceTreeWidget.MarksChanged
+    ) -> None:
+        """Show the batch actions while nodes are marked."""
+        bar = self.query_one(".batch-bar", Horizontal)
+        bar.set_class(bool(event.marked), "-active")
+        self.query_one("#marked-count", Label).update(f"{len(event.marked)} marked:")
+
+    def _open_batch(self, action: str) -> None:
+        """Open the batch dialog for the marked layers."""
+        tree = self.query_one("#resource-tree", ResourceTree)
+        layers = [
+            f"{n.data['workspace']}:{n.data['name']}"
+            for n in tree.marked_nodes
+            if n.data["type"] == "layer"
+        ]
+        if not self.current_connection_id or not layers:
+            self.app.notify("Mark layers with space first", severity="warning")
+            return
+
+        def done(changed: bool | None) -> None:
+            if changed:
+                self._update_tree()
+
+        self.app.push_screen(BatchActionScreen(self.current_connection_id, action, layers), done)
+
+    def _open_map_export(self) -> None:
+        """Open the web map export for the marked layers and layer groups."""
+        tree = self.query_one("#resource-tree", ResourceTree)
+        layers = [
+            f"{n.data['workspace']}:{n.data['name']}"
+            for n in tree.marked_nodes
+            if n.data["type"] in ("layer", "layergroup")
+        ]
+        if not self.current_connection_id or not layers:
+            self.app.notify("Mark layers or groups with space first", severity="warning")
+            return
+        self.app.push_screen(MapExportScreen(self.current_connection_id, layers))
+
+    def _open_snapshot(self) -> None:
+        """Ask whether to include data, then snapshot the marked nodes."""
+        tree = self.query_one("#resource-tree", ResourceTree)
+        targets = [dict(n.data) for n in tree.marked_nodes]
+        if not self.client or not targets:
+            self.app.notify("Mark layers, styles or groups with space first", severity="warning")
+            return
+        client = self.client
+
+        def chosen(contents: str | None) -> None:
+            if contents:
+                include_data = contents == "data"
+                self.query_one("#detail-content", Static).update("Taking snapshot...")
+                self.run_worker(
+                    lambda: self._snapshot(client, targets, include_data), thread=True
+                )
+
+        options = [("Configurations", "config"), ("Configurations and data", "data")]
+        self.app.push_screen(PickerScreen("Snapshot the marked nodes", options), chosen)
+
+    def _snapshot(self, client: GeoServerClient, targets: list[dict], include_data: bool) -> None:
+        """Capture a snapshot into a dated folder in the working directory."""
+        try:
+            snapshot = build_snapshot(client, targets, include_data=include_data)
+            folder = snapshot.write_to(Path.cwd())
+        except Exception as e:
+            self.app.call_from_thread(
+                self.app.notify, f"Snapshot failed: {str(e)}", severity="error"
+            )
+            return
+
+        lines = [f"Snapshot saved to {folder}", f"{len(snapshot.files)} file(s) captured"]
+        for resource in snapshot.failed:
+            lines.append(f"  \u2022 {resource.type} {resource.name}: {resource.error}")
+        self.app.call_from_thread(
+            self.query_one("#detail-content", Static).update, "\n".join(lines)
+        )
+        severity = "warning" if snapshot.failed else "information"
+        self.app.call_from_thread(
+            self.app.notify, f"Snapshot saved to {folder.name}", severity=severity
+        )
+
     def _refresh_connections(self) -> None:
         """Refresh the connection selector."""
         select = self.query_one("#connection-select", Select)
-        config = config_manager.config
+        connections = config_manager.list_connections()
 
-        options = [(conn.name, conn.id) for conn in config.connections]
+        options = [(conn.name, conn.id) for conn in connections]
         select.set_options(options)
 
+        initial, self._initial_connection = self._initial_connection, None
+        if initial in [conn.id for conn in connections]:
+            select.value = initial
         # Auto-select if only one connection
-        if len(config.connections) == 1:
-            select.value = config.connections[0].id
-            self._load_connection(config.connections[0].id)
+        elif len(connections) == 1:
+            select.value = connections[0].id
+            self._load_connection(connections[0].id)
+
+    def select_connection(self, conn_id: str) -> None:
+        """Browse a connection, picked from the sidebar or the command palette."""
+        if not self.is_mounted:
+            self._initial_connection = conn_id
+            return
+        self.query_one("#connection-select", Select).value = conn_id
 
     def on_select_changed(self, event: Select.Changed) -> None:
         """Handle connection selection."""
@@ -133,53 +513,156 @@ class GeoServerScreen(Screen):
 
         self.current_connection_id = conn_id
         self.client = GeoServerClient(conn)
+        self._apply_capabilities()
+        self._write_denied = None
+        self._datadir_watch = None
+        self._apply_read_only()
+        self._probe_permissions()
 
         self._refresh_tree()
 
+    def _apply_capabilities(self) -> None:
+        """Hide actions the connected GeoServer version doesn't support."""
+        if not self.client:
+            return
+
+        capabilities = self.client.capabilities
+        self.query_one("#btn-system-status", Button).display = capabilities.supports(
+            "system_status"
+        )
+        self._can_backup = capabilities.supports("backup_restore")
+        self._can_geofence = capabilities.supports("geofence")
+        self.refresh_bindings()
+
+    @property
+    def read_only(self) -> bool:
+        """Whether the current connection refuses changes."""
+        return bool(self.client and self.client.connection.read_only)
+
+    def _probe_permissions(self) -> None:
+        """Find out in the background whether the credentials may change the server."""
+        client = self.client
+        if not client or client.connection.read_only:
+            return
+
+        def probe() -> None:
+            permissions = client.permissions
+            if not permissions.can_write:
+                self.app.call_from_thread(self._on_write_denied, client, permissions.reason)
+
+        self.run_worker(probe, thread=True, group="permissions", exclusive=True)
+
+    def _on_write_denied(self, client: GeoServerClient, reason: str | None) -> None:
+        """Grey out the write actions, unless another connection was picked meanwhile."""
+        if client is not self.client:
+            return
+        self._write_denied = reason or "The server refuses changes from these credentials"
+        self._apply_read_only()
+
+    def _apply_read_only(self) -> None:
+        """Hide the actions that would change a read-only server.
+
+        When it's the server that only lets these credentials read, the
+        actions stay visible but disabled, with the reason as their tooltip.
+        """
+        for selector in self.WRITE_BUTTONS:
+            button = self.query_one(selector, Button)
+            button.display = not self.read_only
+            button.disabled = self._write_denied is not None
+            button.tooltip = self._write_denied
+        self.refresh_bindings()
+
+    def check_action(self, action: str, parameters: tuple[object, ...]) -> bool | None:
+        """Hide bindings that would change a read-only server, and grey out refused ones."""
+        if action in self.WRITE_ACTIONS and self.read_only:
+            return False
+        if action in self.WRITE_ACTIONS and self._write_denied:
+            return None
+        if action == "backup_workspace" and not self._can_backup:
+            return False
+        if action == "geofence_rules" and not self._can_geofence:
+            return False
+        return True
+
+    def _workspace_children(self, workspace: str) -> list[tuple[str, dict, bool]]:
+        """Category nodes of a workspace, filled in when expanded or selected."""
+        return [
+            (node_label(node_type, label), {"type": node_type, "workspace": workspace}, expandable)
+            for node_type, label, expandable in WORKSPACE_CATEGORIES
+        ]
+
+    def _sync_root(self, tree: "ResourceTree", client: GeoServerClient) -> bool:
+        """Bring the recent items and workspace nodes in line with the server.
+
+        Returns:
+            Whether the workspaces could be listed
+        """
+        # Filled in when expanded, as finding them reads every layer and style
+        children = [
+            (node_label("recent", f"Recently {action.capitalize()}"), {"type": node_type}, True)
+            for node_type, action in RECENT_NODES.items()
+        ]
+        listed = True
+        try:
+            for ws in client.list_workspaces():
+                ws_name = ws.get("name", "Unknown")
+                children.append(
+                    (node_label("workspace", ws_name), {"type": "workspace", "name": ws_name}, True)
+                )
+        except Exception as e:
+            self.app.notify(f"Error loading workspaces: {str(e)}", severity="error")
+            if tree.root.children:
+                return False  # Keep the nodes there are rather than drop every workspace
+            listed = False
+
+        for node in tree.sync_children(tree.root, children):
+            if node.data["type"] == "workspace" and not node.children:
+                tree.sync_children(node, self._workspace_children(node.data["name"]))
+        return listed
+
     def _refresh_tree(self) -> None:
-        """Refresh the resource tree."""
+        """Rebuild the resource tree, expanding the nodes expanded last session."""
         if not self.client:
             return
 
         tree = self.query_one("#resource-tree", ResourceTree)
         tree.clear()
         tree.root.expand()
+        if self._sync_root(tree, self.client):
+            self._restore_expanded(tree)
 
-        try:
-            # Load workspaces
-            workspaces = self.client.list_workspaces()
+    def _update_tree(self) -> None:
+        """Re-read the workspaces and the lists loaded in the tree, updating it in place.
 
-            for ws in workspaces:
-                ws_name = ws.get("name", "Unknown")
-                ws_node = tree.root.add(
-                    f"\uf07b {ws_name}",
-                    data={"type": "workspace", "name": ws_name},
-                )
+        Nodes still on the server keep their expansion and marks, and the
+        cursor stays on its node. Lists under collapsed nodes are dropped
+        rather than read, and load again when expanded.
+        """
+        if not self.client:
+            return
 
-                # Add placeholder children (lazy loading)
-                ws_node.add_leaf(
-                    "\uf1c0 Data Stores",
-                    data={"type": "datastores", "workspace": ws_name},
-                )
-                ws_node.add_leaf(
-                    "\uf03e Coverage Stores",
-                    data={"type": "coveragestores", "workspace": ws_name},
-                )
-                ws_node.add_leaf(
-                    "\uf279 Layers",
-                    data={"type": "layers", "workspace": ws_name},
-                )
-                ws_node.add_leaf(
-                    "\uf1fc Styles",
-                    data={"type": "styles", "workspace": ws_name},
-                )
-                ws_node.add_leaf(
-                    "\uf5fd Layer Groups",
-                    data={"type": "layergroups", "workspace": ws_name},
-                )
+        tree = self.query_one("#resource-tree", ResourceTree)
+        with tree.keeping_cursor():
+            if not self._sync_root(tree, self.client):
+                return
 
-        except Exception as e:
-            self.app.notify(f"Error loading workspaces: {str(e)}", severity="error")
+            lists = []
+            for node in tree.root.children:
+                if node.data["type"] == "workspace":
+                    lists += node.children
+                else:
+                    lists.append(node)
+
+            for node in lists:
+                node_type = node.data["type"]
+                if not node.children:
+                    continue  # Not loaded yet
+                if not node.is_expanded:
+                    tree.sync_children(node, [])
+                elif node_type in RECENT_NODES:
+                    self._load_recent_nodes(node, RECENT_NODES[node_type])
+                else:
+                    self._load_item_nodes(node, node_type, node.data["workspace"])
 
     def on_tree_node_selected(self, event: Tree.NodeSelected) -> None:
         """Handle tree node selection."""
@@ -190,9 +673,32 @@ class GeoServerScreen(Screen):
         node_type = node_data.get("type")
         detail = self.query_one("#detail-content", Static)
 
+        self.selected_workspace = node_data.get("name") or node_data.get("workspace")
+        self._pending_delete = None
+
         if node_type == "workspace":
             ws_name = node_data.get("name")
-            detail.update(f"Workspace: {ws_name}\n\nDouble-click to expand")
+            detail.update(
+                f"Workspace: {ws_name}\n{self._managed_line('workspace', None, ws_name)}"
+                "\n\nDouble-click or press space to expand"
+            )
+
+        elif node_type == "layer":
+            self._show_layer_usage(node_data.get("workspace"), node_data.get("name"))
+
+        elif node_type == "layergroup":
+            self._show_layergroup(node_data.get("workspace"), node_data.get("name"))
+
+        elif node_type == "style":
+            ws_name = node_data.get("workspace")
+            name = node_data.get("name")
+            detail.update(
+                f"Style: {ws_name}:{name}\n{self._managed_line('style', ws_name, name)}"
+                "\n\nPress e to edit it in $EDITOR"
+            )
+
+        elif node_type in ("datastores", "coveragestores"):
+            self._show_stores(node_data.get("workspace"), node_type.rstrip("s"))
 
         elif node_type == "layers":
             ws_name = node_data.get("workspace")
@@ -202,6 +708,148 @@ class GeoServerScreen(Screen):
             ws_name = node_data.get("workspace")
             self._show_styles(ws_name)
 
+    def _managed_line(self, kind: str, workspace: str | None, name: str) -> str:
+        """Say whether the client created, or adopted, a resource."""
+        if not self.current_connection_id:
+            return ""
+        if managed_state.is_managed(self.current_connection_id, kind, workspace, name):
+            return "Managed by CloudBench"
+        return "Not managed: sync won't update or prune it"
+
+    def _show_layer_usage(self, workspace: str, name: str) -> None:
+        """Show a layer with its usage figures, filled in as each one loads."""
+        if not self.client or not self.current_connection_id:
+            return
+
+        layer = f"{workspace}:{name}"
+        self._usage_layer = layer
+        figures = {"Features": "loading...", "Cached tiles": "loading...", "Requests": "loading..."}
+        client, conn_id = self.client, self.current_connection_id
+        managed = self._managed_line("layer", workspace, name)
+
+        def render() -> None:
+            if self._usage_layer != layer:
+                return  # Another node was selected meanwhile
+            lines = [f"Layer: {layer}", managed, ""]
+            lines += [f"  {label}: {value}" for label, value in figures.items()]
+            lines += ["", "Press space to mark it for batch actions"]
+            self.query_one("#detail-content", Static).update("\n".join(lines))
+
+        def feature_count() -> str:
+            count = client.get_layer_feature_count(workspace, name)
+            return "unknown" if count is None else f"{count:,}"
+
+        def cached_tiles() -> str:
+            usage = get_layer_quota_usage(get_gwc_client(conn_id), layer)
+            if usage.used_bytes is None:
+                return "unknown"
+            text = size_label(usage.used_bytes)
+            if usage.limit_bytes is not None:
+                text += f" of {size_label(usage.limit_bytes)} quota"
+            return text
+
+        def requests() -> str:
+            stats = layer_request_stats(client, workspace, name)
+            text = f"{'>=' if not stats.complete else ''}{stats.requests:,} in {stats.days} days"
+            if stats.last_request:
+                text += f", last {stats.last_request}"
+            return text
+
+        def load() -> None:
+            for label, read in (
+                ("Features", feature_count),
+                ("Cached tiles", cached_tiles),
+                ("Requests", requests),
+            ):
+                try:
+                    figures[label] = read()
+                except Exception as e:
+                    figures[label] = f"unavailable ({e})"
+                self.app.call_from_thread(render)
+
+        render()
+        self.run_worker(load, thread=True, group="layer-usage", exclusive=True)
+
+    def _show_layergroup(self, workspace: str, name: str) -> None:
+        """Show the layers a group draws and its tile cache, once they load."""
+        if not self.client or not self.current_connection_id:
+            return
+
+        group = f"{workspace}:{name}"
+        self._usage_layer = group
+        client, conn_id = self.client, self.current_connection_id
+        detail = self.query_one("#detail-content", Static)
+        detail.update(f"Layer group: {group}\n\nLoading...")
+
+        def show(text: str) -> None:
+            if self._usage_layer == group:  # Unless another node was selected meanwhile
+                detail.update(text)
+
+        def load() -> None:
+            lines = [f"Layer group: {group}", ""]
+            try:
+                entries = layergroup_entries(client.get_layergroup(name, workspace))
+                lines.append("Draws:")
+                for entry in entries:
+                    kind = " (group)" if entry["type"] == "layerGroup" else ""
+                    lines.append(f"  \u2022 {entry['name']}{kind}")
+                if not entries:
+                    lines.append("  nothing")
+            except Exception as e:
+                lines.append(f"Layers unavailable ({e})")
+
+            lines.append("")
+            try:
+                cache = get_gwc_client(conn_id).get_layer(group)
+                grid_sets = [s.get("gridSetName", "") for s in cache.get("gridSubsets") or []]
+                lines.append(f"Tile cache: {', '.join(grid_sets) or 'no grid sets'}")
+                lines.append(f"  Formats: {', '.join(cache.get('mimeFormats') or []) or 'none'}")
+            except GeoServerError as e:
+                if e.status_code == 404:
+                    lines.append("Tile cache: off, press k to turn it on")
+                else:
+                    lines.append(f"Tile cache: unavailable ({e})")
+
+            lines += ["", "Press m to preview it or w for its tile URLs"]
+            self.app.call_from_thread(show, "\n".join(lines))
+
+        self.run_worker(load, thread=True, group="layer-usage", exclusive=True)
+
+    def _show_stores(self, workspace: str, kind: str) -> None:
+        """Show a workspace's data or coverage stores and whether each is enabled."""
+        if not self.client:
+            return
+
+        key = f"{kind}s:{workspace}"
+        self._usage_layer = key
+        client = self.client
+        title = "Data stores" if kind == "datastore" else "Coverage stores"
+        detail = self.query_one("#detail-content", Static)
+        detail.update(f"{title} in {workspace}:\n\nLoading...")
+
+        def show(text: str) -> None:
+            if self._usage_layer == key:  # Unless another node was selected meanwhile
+                detail.update(text)
+
+        def load() -> None:
+            try:
+                stores = list_stores_with_status(client, workspace, kind)
+            except GeoServerError as e:
+                self.app.call_from_thread(show, f"Error loading stores: {e}")
+                return
+            if not stores:
+                self.app.call_from_thread(show, f"Workspace '{workspace}' has no {title.lower()}")
+                return
+            lines = [f"{title} in {workspace}:", ""]
+            for store in stores:
+                status = " (disabled)" if store["enabled"] is False else ""
+                lines.append(f"  \u2022 {store.get('name', 'Unknown')}{status}")
+            if len(stores) > MAX_STATUS_STORES:
+                lines += ["", "Too many stores to check which are enabled"]
+            self.app.call_from_thread(show, "\n".join(lines))
+
+        self.run_worker(load, thread=True, group="layer-usage", exclusive=True)
+
     def _show_layers(self, workspace: str) -> None:
         """Show layers for a workspace."""
         if not self.client:
@@ -248,9 +896,563 @@ class GeoServerScreen(Screen):
         except Exception as e:
             detail.update(f"Error loading styles: {str(e)}")
 
+    def _show_system_status(self) -> None:
+        """Show system status metrics."""
+        if not self.client:
+            return
+
+        detail = self.query_one("#detail-content", Static)
+
+        try:
+            metrics = self.client.get_system_status()
+            text = "System Status:\n\n"
+            for metric in metrics:
+                if not metric.get("available", True):
+                    continue
+                name = metric.get("description") or metric.get("name", "Unknown")
+                unit = metric.get("unit", "")
+                text += f"  \u2022 {name}: {metric.get('value', '')} {unit}\n"
+
+            try:
+                modules = self.client.get_installed_modules()
+            except Exception as e:
+                text += f"\nInstalled extensions unavailable: {e}\n"
+            else:
+                text += "\nInstalled Extensions:\n\n"
+                for module in modules:
+                    kind = " (community)" if module.kind == "community" else ""
+                    text += f"  \u2022 {module.label}{kind} {module.version}\n"
+                if not modules:
+                    text += "  None found\n"
+
+            detail.update(text)
+
+        except Exception as e:
+            detail.update(f"Error loading system status: {str(e)}")
+
+    def _delete_workspace(self) -> None:
+        """Show what deleting the selected workspace removes, then delete on confirm."""
+        if not self.client or not self.current_connection_id:
+            return
+        workspace = self.selected_workspace
+        if not workspace:
+            self.app.notify("Select a workspace to delete", severity="warning")
+            return
+
+        detail = self.query_one("#detail-content", Static)
+
+        if self._pending_delete != workspace:
+            try:
+                cached = cached_workspace_layers(self.current_connection_id, workspace)
+            except Exception:
+                cached = []  # GWC may be disabled
+            try:
+                impact = workspace_impact(self.client, workspace, cached)
+            except Exception as e:
+                detail.update(f"Error collecting workspace contents: {str(e)}")
+                return
+
+            text = f"Delete workspace '{workspace}'?\n\n"
+            if impact.is_empty:
+                text += "The workspace is empty.\n"
+            else:
+                text += "The following will also be deleted:\n\n"
+                sections = [
+                    ("Stores", [store["name"] for store in impact.stores]),
+                    ("Layers", impact.layers),
+                    ("Styles", impact.styles),
+                    ("Layer groups", impact.layer_groups),
+                    ("Tile caches", impact.caches),
+                ]
+                for label, names in sections:
+                    if names:
+                        text += f"  {label} ({len(names)}): {', '.join(names)}\n"
+            text += "\nPress Delete again to confirm."
+            detail.update(text)
+            self._pending_delete = workspace
+            return
+
+        self._pending_delete = None
+        if requires_typed_confirmation(self.client.connection):
+
+            def confirmed(ok: bool | None) -> None:
+                if ok:
+                    self._remove_workspace(workspace)
+
+            self.app.push_screen(
+                ConfirmScreen(
+                    f"Delete workspace '{workspace}'",
+                    "Everything in the workspace is deleted with it. "
+                    f"Connection {self.client.connection.name} asks for the name first.",
+                    expected=workspace,
+                ),
+                confirmed,
+            )
+            return
+        self._remove_workspace(workspace)
+
+    def _remove_workspace(self, workspace: str) -> None:
+        """Delete a workspace with everything in it and its tile caches."""
+        if not self.client or not self.current_connection_id:
+            return
+        detail = self.query_one("#detail-content", Static)
+        try:
+            clear_workspace_caches(self.current_connection_id, workspace)
+            self.client.delete_workspace(workspace, recurse=True)
+        except Exception as e:
+            self.app.notify(f"Error deleting workspace: {str(e)}", severity="error")
+            return
+
+        self.selected_workspace = None
+        detail.update(f"Workspace '{workspace}' deleted")
+        self.app.notify(f"Workspace '{workspace}' deleted", severity="information")
+        self._update_tree()
+
+    def action_delete_style(self) -> None:
+        """Delete the style under the cursor, after listing the layers that use it."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") != "style" or not self.current_connection_id:
+            self.app.notify("Select a style to delete", severity="warning")
+            return
+
+        def deleted(ok: bool | None) -> None:
+            if ok:
+                self._update_tree()
+
+        self.app.push_screen(
+            StyleDeleteScreen(self.current_connection_id, data["workspace"], data["name"]),
+            deleted,
+        )
+
+    def action_edit_style(self) -> None:
+        """Edit the style under the cursor in the user's editor and upload it."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") != "style" or not self.client or not self.current_connection_id:
+            self.app.notify("Select a style to edit", severity="warning")
+            return
+
+        key = (data["workspace"], data["name"])
+        edit = self._style_edits.pop(key, None)
+        if edit is None:
+            edit = ExternalStyleEdit(
+                self.client, self.current_connection_id, data["name"], data["workspace"]
+            )
+            try:
+                edit.download()
+            except Exception as e:
+                self.app.notify(f"Error downloading style: {str(e)}", severity="error")
+                return
+
+        try:
+            with self.app.suspend():
+                subprocess.run(edit.command(), check=False)
+        except Exception as e:
+            # No editor found, or the terminal can't be handed over
+            self._style_edits[key] = edit
+            self.app.notify(f"Could not run editor: {str(e)}", severity="error")
+            return
+
+        detail = self.query_one("#detail-content", Static)
+        try:
+            result = edit.finish()
+        except Exception as e:
+            self._style_edits[key] = edit
+            detail.update(f"Error uploading style: {str(e)}\n\nPress e to edit it again")
+            return
+
+        if result.status == "unchanged":
+            self.app.notify("Style unchanged", severity="information")
+        elif result.status == "invalid":
+            self._style_edits[key] = edit
+            text = f"Style '{data['name']}' was not uploaded:\n\n"
+            text += "".join(f"  \u2022 {error}\n" for error in result.errors)
+            text += "\nPress e to fix it; restoring the original content discards the edit"
+            detail.update(text)
+            self.app.notify("Style is invalid, not uploaded", severity="error")
+        else:
+            text = f"Style '{data['name']}' uploaded"
+            if result.affected_layers:
+                text += f"\n\nUsed by {len(result.affected_layers)} layer(s)"
+            if result.truncated:
+                text += f", {len(result.truncated)} tile cache(s) truncated"
+            detail.update(text)
+            self.app.notify(f"Style '{data['name']}' uploaded", severity="information")
+
+    def action_copy(self) -> None:
+        """Copy a URL or name of the node under the cursor to the clipboard."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        if node is None or not self.client:
+            return
+
+        data = node.data or {"type": "connection"}
+        node_type = data.get("type", "")
+        if node_type == "workspace":
+            workspace, name = data.get("name"), None
+        else:
+            workspace, name = data.get("workspace"), data.get("name")
+
+        bbox = None
+        if node_type == "layer" and workspace and name:
+            try:
+                bbox = self.client.get_layer_bounds(workspace, name).get("latLonBoundingBox")
+            except Exception:
+                pass  # The GetMap link shows the whole world instead
+
+        targets = copy_targets(self.client.connection, node_type, workspace, name, bbox=bbox)
+        if not targets:
+            self.app.notify("Nothing to copy for this node", severity="warning")
+            return
+
+        labels = {target.value: target.label for target in targets}
+
+        def copy(value: str | None) -> None:
+            if value:
+                self.app.copy_to_clipboard(value)
+                self.app.notify(f"Copied {labels[value]}", severity="information")
+
+        if len(targets) == 1:
+            copy(targets[0].value)
+        else:
+            options = [(target.label, target.value) for target in targets]
+            self.app.push_screen(PickerScreen("Copy to clipboard", options), copy)
+
+    def action_diff_style(self) -> None:
+        """Diff a style, or a workspace's styles, against another connection's.
+
+        The dialog can then promote the styles that differ to that connection.
+        """
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") not in ("style", "styles") or not self.current_connection_id:
+            self.app.notify("Select a style or a workspace's styles to diff", severity="warning")
+            return
+        source_id = self.current_connection_id
+        options = [
+            (conn.name, conn.id)
+            for conn in config_manager.list_connections()
+            if conn.id != source_id
+        ]
+        if not options:
+            self.app.notify("Add another connection to diff against", severity="warning")
+            return
+        workspace = data.get("workspace")
+        name = data["name"] if data["type"] == "style" else None
+        label = name or f"the styles of {workspace or 'the server'}"
+
+        def chosen(dest_id: str | None) -> None:
+            if not dest_id:
+                return
+
+            def compare() -> list[StyleDiff]:
+                source = get_geoserver_client(source_id)
+                dest = get_geoserver_client(dest_id)
+                if name:
+                    return [diff_connection_style(source, dest, name, workspace)]
+                return diff_connection_styles(source, dest, workspace)
+
+            def promote(names: list[str]) -> dict:
+                return get_sync_service().promote_styles(source_id, dest_id, names, workspace)
+
+            dest_name = next(text for text, value in options if value == dest_id)
+            self.app.push_screen(
+                StyleDiffScreen(
+                    f"What syncing {label} to {dest_name} would change", compare, promote
+                )
+            )
+
+        self.app.push_screen(PickerScreen(f"Diff {label} against", options), chosen)
+
+    def action_preview_map(self) -> None:
+        """Draw the layer under the cursor on a map."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") not in ("layer", "layergroup") or not self.current_connection_id:
+            self.app.notify("Select a layer or layer group to preview", severity="warning")
+            return
+        self.app.push_screen(
+            MapPreviewScreen(
+                self.current_connection_id,
+                data["workspace"],
+                data["name"],
+                group=data["type"] == "layergroup",
+            )
+        )
+
+    def action_attribute_table(self) -> None:
+        """Page through the attributes of the layer under the cursor."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") != "layer" or not self.current_connection_id:
+            self.app.notify("Select a vector layer to view its attributes", severity="warning")
+            return
+        self.app.push_screen(
+            AttributeTableScreen(self.current_connection_id, data["workspace"], data["name"])
+        )
+
+    def action_branding(self) -> None:
+        """Edit the WMS watermark, and the attribution of the layer under the cursor."""
+        if not self.current_connection_id:
+            return
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") == "layer":
+            screen = BrandingScreen(self.current_connection_id, data["workspace"], data["name"])
+        else:
+            screen = BrandingScreen(self.current_connection_id)
+        self.app.push_screen(screen)
+
+    def action_render_benchmark(self) -> None:
+        """Time how long the layer under the cursor takes to render."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") != "layer" or not self.current_connection_id:
+            self.app.notify("Select a layer to benchmark", severity="warning")
+            return
+        self.app.push_screen(
+            RenderBenchmarkScreen(self.current_connection_id, data["workspace"], data["name"])
+        )
+
+    def action_hit_ratio(self) -> None:
+        """Sample the cache hit ratio of the layer under the cursor per zoom level."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") != "layer" or not self.current_connection_id:
+            self.app.notify("Select a cached layer to analyze", severity="warning")
+            return
+        self.app.push_screen(
+            HitRatioScreen(self.current_connection_id, data["workspace"], data["name"])
+        )
+
+    def action_watch_datadir(self) -> None:
+        """Snapshot the data directory, or show what changed since the snapshot.
+
+        The selected workspace's folder is watched, or the global styles
+        and layer groups when no workspace is selected.
+        """
+        if not self.client:
+            return
+        client = self.client
+        watch = self._datadir_watch
+        if watch is None:
+            workspace = self.selected_workspace
+            if workspace:
+                watch = DataDirWatch(client, [f"workspaces/{workspace}"])
+            else:
+                watch = DataDirWatch(client, list(GLOBAL_FOLDERS), [""])
+            self._datadir_watch = watch
+            self.app.notify("Reading the data directory...", severity="information")
+            self.run_worker(lambda: self._start_datadir_watch(watch), thread=True)
+            return
+        self._datadir_watch = None
+        self.app.notify("Comparing the data directory...", severity="information")
+        self.run_worker(lambda: self._finish_datadir_watch(watch), thread=True)
+
+    def _start_datadir_watch(self, watch: DataDirWatch) -> None:
+        """Read the watched files off the UI thread."""
+        watch.start()
+        if watch.diff.error:
+            self.app.call_from_thread(self._datadir_watch_failed, watch)
+            return
+        self.app.call_from_thread(
+            self.app.notify,
+            "Watching the data directory; make the change, then press g to see the files "
+            "GeoServer wrote",
+            severity="information",
+        )
+
+    def _datadir_watch_failed(self, watch: DataDirWatch) -> None:
+        """Drop a watch whose files couldn't be read."""
+        if self._datadir_watch is watch:
+            self._datadir_watch = None
+        self.app.notify(f"Cannot read the data directory: {watch.diff.error}", severity="error")
+
+    def _finish_datadir_watch(self, watch: DataDirWatch) -> None:
+        """Read the watched files again and compare them off the UI thread."""
+        diff = watch.finish()
+        self.app.call_from_thread(self.app.push_screen, DataDirDiffScreen(diff))
+
+    def action_verify_endpoints(self) -> None:
+        """Check the OGC endpoints of the layer under the cursor, or of a workspace's layers."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if not self.current_connection_id:
+            return
+        if data.get("type") == "layer":
+            screen = EndpointHealthScreen(
+                self.current_connection_id, data["workspace"], data["name"]
+            )
+        elif self.selected_workspace:
+            screen = EndpointHealthScreen(self.current_connection_id, self.selected_workspace)
+        else:
+            self.app.notify("Select a layer or workspace to verify", severity="warning")
+            return
+        self.app.push_screen(screen)
+
+    def action_tile_endpoints(self) -> None:
+        """List tile service URLs for the layer under the cursor and check them."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") not in ("layer", "layergroup") or not self.current_connection_id:
+            self.app.notify("Select a cached layer to list its tile URLs", severity="warning")
+            return
+        self.app.push_screen(
+            TileEndpointsScreen(
+                self.current_connection_id, f"{data['workspace']}:{data['name']}"
+            )
+        )
+
+    def action_enable_cache(self) -> None:
+        """Turn on tile caching for the layer or layer group under the cursor."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") not in ("layer", "layergroup") or not self.current_connection_id:
+            self.app.notify("Select a layer or layer group to cache", severity="warning")
+            return
+        layer = f"{data['workspace']}:{data['name']}"
+        conn_id = self.current_connection_id
+
+        def enable() -> None:
+            try:
+                get_gwc_client(conn_id).enable_layer(layer)
+            except Exception as e:
+                self.app.call_from_thread(
+                    self.app.notify, f"Could not cache {layer}: {e}", severity="error"
+                )
+                return
+            self.app.call_from_thread(
+                self.app.notify, f"Tile caching enabled for {layer}", severity="information"
+            )
+            if data["type"] == "layergroup":
+                self.app.call_from_thread(self._show_layergroup, data["workspace"], data["name"])
+
+        self.run_worker(enable, thread=True)
+
+    def action_seed_cache(self) -> None:
+        """Estimate the tiles of a seed of the layer under the cursor, then start it."""
+        node = self.query_one("#resource-tree", ResourceTree).cursor_node
+        data = (node.data if node else None) or {}
+        if data.get("type") != "layer" or not self.current_connection_id:
+            self.app.notify("Select a cached layer to seed", severity="warning")
+            return
+        self.app.push_screen(
+            SeedEstimateScreen(self.current_connection_id, data["workspace"], data["name"])
+        )
+
+    def _create_workspace(self) -> None:
+        """Create a workspace, optionally from a workspace template."""
+        if not self.current_connection_id:
+            self.app.notify("Select a connection first", severity="warning")
+            return
+
+        def on_close(name: str | None) -> None:
+            if name:
+                self._update_tree()
+
+        self.app.push_screen(WorkspaceCreateScreen(self.current_connection_id), on_close)
+
+    def action_import_styles(self) -> None:
+        """Import a local folder of styles into the selected workspace."""
+        if not self.current_connection_id:
+            return
+        workspace = self.selected_workspace
+        if not workspace:
+            self.app.notify("Select a workspace to import styles into", severity="warning")
+            return
+        self.app.push_screen(StyleImportScreen(self.current_connection_id, workspace))
+
+    def action_publish_qgis(self) -> None:
+        """Publish a QGIS project's layers into the selected workspace."""
+        if not self.current_connection_id:
+            return
+        workspace = self.selected_workspace
+        if not workspace:
+            self.app.notify("Select a workspace to publish the project into", severity="warning")
+            return
+
+        def published(ok: bool | None) -> None:
+            if ok:
+                self._update_tree()
+
+        self.app.push_screen(QGISPublishScreen(self.current_connection_id, workspace), published)
+
+    def action_style_assets(self) -> None:
+        """Manage the icons kept next to the selected workspace's styles."""
+        if not self.current_connection_id:
+            return
+        workspace = self.selected_workspace
+        if not workspace:
+            self.app.notify("Select a workspace to manage its style graphics", severity="warning")
+            return
+        self.app.push_screen(StyleAssetsScreen(self.current_connection_id, workspace))
+
+    def action_terria_init(self) -> None:
+        """Write a Terria init file of the selected workspace, or of every workspace."""
+        if not self.current_connection_id:
+            return
+        self.app.push_screen(TerriaInitScreen(self.current_connection_id, self.selected_workspace))
+
+    def action_geofence_rules(self) -> None:
+        """Manage the GeoFence data security rules of the connection's server."""
+        if not self.current_connection_id:
+            return
+        self.app.push_screen(GeofenceRulesScreen(self.current_connection_id))
+
+    def action_backup_workspace(self) -> None:
+        """Archive the selected workspace with the Backup and Restore plugin."""
+        if not self.client:
+            return
+        workspace = self.selected_workspace
+        if not workspace:
+            self.app.notify("Select a workspace to back up", severity="warning")
+            return
+
+        self.app.notify(f"Backing up '{workspace}' on the server...", severity="information")
+        self.run_worker(lambda: self._backup(workspace), thread=True)
+
+    def _backup(self, workspace: str) -> None:
+        """Run a backup in a background thread and download its archive."""
+        detail = self.query_one("#detail-content", Static)
+
+        def progress(execution: Execution) -> None:
+            text = f"Backing up {workspace}: {execution.status} {execution.progress}"
+            self.app.call_from_thread(detail.update, text)
+
+        try:
+            backup_restore = self.client.backup_restore
+            if not backup_restore.is_available():
+                raise ValueError("The Backup and Restore plugin isn't installed")
+            execution = backup_restore.backup_workspaces([workspace], on_progress=progress)
+            if not execution.succeeded:
+                raise ValueError(
+                    "; ".join(execution.errors) or f"Backup stopped: {execution.status}"
+                )
+            path = Path.cwd() / f"backup-{workspace}-{time.strftime('%Y%m%d-%H%M%S')}.zip"
+            # Written as it arrives, since the archive can be bigger than memory allows
+            try:
+                with open(path, "wb") as f:
+                    for chunk in backup_restore.iter_archive(execution.id):
+                        f.write(chunk)
+            except Exception:
+                path.unlink(missing_ok=True)
+                raise
+            write_checksum_file(path)
+        except Exception as e:
+            self.app.call_from_thread(
+                self.app.notify, f"Backup failed: {str(e)}", severity="error"
+            )
+            return
+
+        text = f"Backup of {workspace} saved to {path}\nServer copy: {execution.archive_file}"
+        self.app.call_from_thread(detail.update, text)
+        self.app.call_from_thread(
+            self.app.notify, f"Backup saved to {path}", severity="information"
+        )
+
     def action_refresh(self) -> None:
-        """Refresh the tree."""
-        self._refresh_tree()
+        """Refresh the tree in place."""
+        self._update_tree()
         self.app.notify("Refreshed", severity="information")
 
     def on_button_pressed(self, event: Button.Pressed) -> None:
@@ -259,5 +1461,21 @@ class GeoServerScreen(Screen):
             self.action_refresh()
         elif event.button.id == "btn-upload":
             self.app.notify("Upload feature coming soon", severity="information")
+        elif event.button.id == "btn-system-status":
+            self._show_system_status()
+        elif event.button.id == "btn-delete":
+            node = self.query_one("#resource-tree", ResourceTree).cursor_node
+            if node and (node.data or {}).get("type") == "style":
+                self.action_delete_style()
+            else:
+                self._delete_workspace()
+        elif event.button.id == "btn-snapshot":
+            self._open_snapshot()
+        elif event.button.id == "btn-map-export":
+            self._open_map_export()
+        elif event.button.id == "btn-batch-clear":
+            self.query_one("#resource-tree", ResourceTree).clear_marks()
+        elif event.button.id and event.button.id.startswith("btn-batch-"):
+            self._open_batch(event.button.id.removeprefix("btn-batch-"))
         elif event.button.id == "btn-create-ws":
-            self.app.notify("Create workspace feature coming soon", severity="information")
+            self._create_workspace()
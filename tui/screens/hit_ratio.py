@@ -0,0 +1,200 @@
+"""Tile cache hit ratio dialog for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, DataTable, Input, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.gwc.client import get_gwc_client
+from apps.gwc.hit_ratio import (
+    DEFAULT_SAMPLES,
+    DEFAULT_ZOOM_START,
+    DEFAULT_ZOOM_STOP,
+    HitRatioReport,
+    ZoomHitRatio,
+    analyze_hit_ratio,
+)
+
+
+def heat_cell(zoom: ZoomHitRatio) -> str:
+    """A hit ratio colored from red to green; levels worth seeding are marked with *."""
+    ratio = zoom.hit_ratio
+    if ratio is None:
+        return "[dim]  -  [/]"
+    if ratio < 0.25:
+        color = "red"
+    elif ratio < 0.5:
+        color = "dark_orange"
+    elif ratio < 0.75:
+        color = "yellow"
+    else:
+        color = "green"
+    mark = "*" if zoom.worth_seeding else " "
+    return f"[black on {color}] {ratio:>4.0%}{mark}[/]"
+
+
+class HitRatioScreen(ModalScreen[None]):
+    """Dialog sampling a cached layer's tiles to find its hit ratio per zoom level."""
+
+    DEFAULT_CSS = """
+    HitRatioScreen {
+        align: center middle;
+    }
+
+    .hit-ratio-dialog {
+        width: 90%;
+        height: 80%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .hit-ratio-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .hit-ratio-row {
+        height: auto;
+    }
+
+    .hit-ratio-row Input {
+        width: 1fr;
+    }
+
+    .hit-ratio-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .hit-ratio-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, workspace: str, layer: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace name
+            layer: Layer name
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self.layer = layer
+        self._running = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="hit-ratio-dialog"):
+            yield Static(
+                f"Cache Hit Ratio: {self.workspace}:{self.layer}", classes="hit-ratio-title"
+            )
+            with Horizontal(classes="hit-ratio-row"):
+                yield Input(placeholder=f"Zoom start ({DEFAULT_ZOOM_START})", id="input-start")
+                yield Input(placeholder=f"Zoom stop ({DEFAULT_ZOOM_STOP})", id="input-stop")
+                yield Input(placeholder=f"Tiles per zoom ({DEFAULT_SAMPLES})", id="input-samples")
+            yield DataTable(id="hit-ratio-table", classes="hit-ratio-table")
+            yield Static(
+                "Requests a few tiles per zoom level and grid set and counts those served "
+                "from the cache. Tiles that miss are rendered and cached.",
+                id="hit-ratio-summary",
+                classes="hit-ratio-summary",
+            )
+            with Horizontal(classes="hit-ratio-row"):
+                yield Button("Analyze", id="btn-analyze", variant="primary")
+                yield Button("Close", id="btn-close")
+
+    def _number(self, input_id: str, default: int) -> int:
+        """Read a whole number input, or its default when empty."""
+        text = self.query_one(input_id, Input).value.strip()
+        return int(text) if text else default
+
+    def _analyze(self) -> None:
+        """Sample the tiles in a background thread."""
+        if self._running:
+            return
+        try:
+            zoom_start = self._number("#input-start", DEFAULT_ZOOM_START)
+            zoom_stop = self._number("#input-stop", DEFAULT_ZOOM_STOP)
+            samples = self._number("#input-samples", DEFAULT_SAMPLES)
+        except ValueError:
+            self.app.notify("Zoom levels and tiles must be whole numbers", severity="error")
+            return
+
+        self._running = True
+        self.query_one("#hit-ratio-summary", Static).update("Sampling tiles...")
+
+        def run() -> None:
+            try:
+                report = analyze_hit_ratio(
+                    get_gwc_client(self.conn_id),
+                    get_geoserver_client(self.conn_id),
+                    self.workspace,
+                    self.layer,
+                    zoom_start=zoom_start,
+                    zoom_stop=zoom_stop,
+                    samples=samples,
+                )
+            except (GeoServerError, ValueError) as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._show_report, report)
+
+        self.run_worker(run, thread=True)
+
+    def _show_report(self, report: HitRatioReport) -> None:
+        """Fill the heat table: a row per grid set, a column per zoom level."""
+        self._running = False
+        table = self.query_one("#hit-ratio-table", DataTable)
+        table.clear(columns=True)
+        levels = sorted({zoom.zoom for zoom in report.zooms})
+        table.add_columns("Grid set", *(str(level) for level in levels))
+        for grid_set in dict.fromkeys(zoom.grid_set for zoom in report.zooms):
+            cells = {z.zoom: heat_cell(z) for z in report.zooms if z.grid_set == grid_set}
+            table.add_row(grid_set, *(cells.get(level, "") for level in levels))
+
+        ratio = report.hit_ratio
+        lines = [f"Sampled hit ratio: {'-' if ratio is None else f'{ratio:.0%}'} ({report.format})"]
+        if report.runtime and report.runtime.hit_ratio is not None:
+            lines.append(f"Server-wide hit ratio: {report.runtime.hit_ratio:.0%}")
+        suggestions = report.suggestions()
+        if suggestions:
+            lines.append("[b]Worth pre-seeding (*):[/]")
+            for s in suggestions:
+                zooms = f"{s.zoom_start}" if s.zoom_stop == s.zoom_start else (
+                    f"{s.zoom_start}-{s.zoom_stop}"
+                )
+                lines.append(f"  {s.grid_set} zoom {zooms}: {s.tiles:,} tiles")
+        else:
+            lines.append("No zoom levels need pre-seeding.")
+        errors = sum(zoom.errors for zoom in report.zooms)
+        if errors:
+            lines.append(f"[red]{errors} tile request(s) failed[/]")
+        self.query_one("#hit-ratio-summary", Static).update("\n".join(lines))
+
+    def _on_failed(self, error: str) -> None:
+        """Report a failed analysis."""
+        self._running = False
+        self.query_one("#hit-ratio-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-analyze":
+            self._analyze()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless an analysis is going."""
+        if self._running:
+            self.app.notify("Wait for the analysis to finish", severity="warning")
+            return
+        self.dismiss(None)
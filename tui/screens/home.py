@@ -3,7 +3,23 @@
 from textual.app import ComposeResult
 from textual.containers import Container, Grid, Horizontal, Vertical
 from textual.screen import Screen
-from textual.widgets import Button, Label, Static
+from textual.widgets import Button, DataTable, Input, Label, Select, Static
+
+from apps.dashboard.storage import ConnectionStorage, connection_storage
+
+from .workspace_usage import WorkspaceUsageScreen
+
+
+def _format_bytes(size: int | None) -> str:
+    """Format a byte count for display."""
+    if size is None:
+        return "?"
+    value = float(size)
+    for unit in ("B", "KB", "MB", "GB", "TB"):
+        if value < 1024 or unit == "TB":
+            return f"{value:.0f} {unit}" if unit == "B" else f"{value:.1f} {unit}"
+        value /= 1024
+    return f"{value:.1f} TB"
 
 
 class StatusCard(Static):
@@ -76,6 +92,36 @@ class HomeScreen(Screen):
         margin: 0 1;
     }
 
+    .profile-bar {
+        height: auto;
+        padding: 0 1;
+        margin: 1 1 0 1;
+    }
+
+    .profile-bar Label {
+        padding: 1 1 0 0;
+    }
+
+    .profile-bar Select {
+        width: 30;
+    }
+
+    .profile-bar Input {
+        width: 30;
+    }
+
+    .storage-title {
+        height: 1;
+        padding: 0 1;
+        text-style: bold;
+    }
+
+    .storage-table {
+        height: auto;
+        max-height: 12;
+        margin: 0 1;
+    }
+
     .footer-text {
         dock: bottom;
         height: 1;
@@ -86,12 +132,20 @@ class HomeScreen(Screen):
 
     BINDINGS = [
         ("escape", "app.pop_screen", "Back"),
+        ("t", "refresh_storage", "Refresh Storage"),
+        ("w", "workspace_usage", "Workspace Storage"),
     ]
 
     def compose(self) -> ComposeResult:
         """Create the home screen layout."""
         yield Static("Kartoza CloudBench Dashboard", classes="dashboard-header")
 
+        with Horizontal(classes="profile-bar"):
+            yield Label("Profile:")
+            yield Select([], id="select-profile", allow_blank=False)
+            yield Input(id="new-profile", placeholder="New profile name")
+            yield Button("Create", id="btn-create-profile")
+
         with Container(classes="dashboard-grid"):
             yield StatusCard("GeoServer Connections", "0", icon="\uf0ac")
             yield StatusCard("PostgreSQL Services", "0", icon="\uf1c0")
@@ -100,6 +154,12 @@ class HomeScreen(Screen):
             yield StatusCard("Cached Layers", "0", icon="\uf0c7")
             yield StatusCard("System Status", "OK", icon="\uf00c")
 
+        yield Static("GeoServer Storage", classes="storage-title")
+        table = DataTable(id="storage-table", classes="storage-table", cursor_type="row")
+        for label in ("Connection", "Tile Cache", "Seed Tasks", "Data Directory"):
+            table.add_column(label, key=label)
+        yield table
+
         with Horizontal(classes="quick-actions"):
             yield Button("Add Connection", id="btn-add-connection", variant="primary")
             yield Button("Upload Data", id="btn-upload", variant="success")
@@ -113,7 +173,43 @@ class HomeScreen(Screen):
 
     def on_mount(self) -> None:
         """Refresh dashboard when mounted."""
+        self._refresh_profiles()
         self._refresh_stats()
+        self.action_refresh_storage()
+
+    def _refresh_profiles(self) -> None:
+        """Fill the profile switcher."""
+        manager = self.app.config_manager
+        profiles = manager.list_profiles()
+        if manager.profile and manager.profile not in profiles:
+            profiles.append(manager.profile)  # Not saved yet
+
+        select = self.query_one("#select-profile", Select)
+        with self.prevent(Select.Changed):
+            select.set_options([(name, name) for name in profiles])
+            if manager.profile:
+                select.value = manager.profile
+            else:
+                select.disabled = True  # Config file given with --config
+
+    def _switch_profile(self, name: str) -> bool:
+        """Load a profile and refresh the dashboard."""
+        try:
+            self.app.switch_profile(name)
+        except ValueError as e:
+            self.app.notify(str(e), severity="error")
+            return False
+        self._refresh_profiles()
+        self._refresh_stats()
+        self.action_refresh_storage()
+        self.app.notify(f"Switched to profile '{name}'", severity="information")
+        return True
+
+    def on_select_changed(self, event: Select.Changed) -> None:
+        """Switch profile when another one is selected."""
+        if event.select.id == "select-profile" and event.value != Select.BLANK:
+            if event.value != self.app.config_manager.profile:
+                self._switch_profile(str(event.value))
 
     def _refresh_stats(self) -> None:
         """Refresh dashboard statistics."""
@@ -126,6 +222,74 @@ class HomeScreen(Screen):
             cards[1].card_value = str(len(config.pg_services))
             cards[2].card_value = str(len(config.s3_connections))
 
+    def action_refresh_storage(self) -> None:
+        """Load tile cache and data directory figures for every connection."""
+        table = self.query_one("#storage-table", DataTable)
+        table.clear()
+        connections = self.app.config_manager.config.connections
+        for conn in connections:
+            table.add_row(conn.name, "Loading...", "", "", key=conn.id)
+        for conn in connections:
+            self.run_worker(lambda conn_id=conn.id: self._load_storage(conn_id), thread=True)
+
+    def _load_storage(self, conn_id: str) -> None:
+        try:
+            storage = connection_storage(conn_id)
+        except Exception as e:
+            storage = ConnectionStorage(conn_id, disk_quota_error=str(e))
+        self.app.call_from_thread(self._show_storage, storage)
+
+    def _show_storage(self, storage: ConnectionStorage) -> None:
+        table = self.query_one("#storage-table", DataTable)
+        key = storage.connection_id
+        if key not in table.rows:
+            return  # Removed by a refresh or profile switch
+
+        quota = storage.disk_quota
+        if quota is None:
+            cache = f"Unavailable: {storage.disk_quota_error}"
+        elif not quota.enabled:
+            cache = "Quota disabled"
+        else:
+            limit = _format_bytes(quota.limit_bytes) if quota.limit_bytes else "no limit"
+            cache = f"{_format_bytes(quota.used_bytes)} / {limit}"
+            if quota.used_pct is not None:
+                cache += f" ({quota.used_pct:.0f}%)"
+
+        seeds = storage.seed_tasks
+        if seeds is None:
+            tasks = "?"
+        else:
+            tasks = f"{seeds.running} running"
+            if seeds.pending:
+                tasks += f", {seeds.pending} pending"
+
+        data_dir = storage.data_directory
+        if data_dir is None:
+            directory = "Resource API unavailable"
+        else:
+            prefix = "" if data_dir.complete else ">= "
+            directory = f"{prefix}{_format_bytes(data_dir.bytes)} in {data_dir.files} files"
+
+        table.update_cell(key, "Tile Cache", cache)
+        table.update_cell(key, "Seed Tasks", tasks)
+        table.update_cell(key, "Data Directory", directory)
+
+    def action_workspace_usage(self) -> None:
+        """Break down the storage of the connection under the cursor by workspace."""
+        table = self.query_one("#storage-table", DataTable)
+        if not table.row_count:
+            return
+        row_key, _ = table.coordinate_to_cell_key(table.cursor_coordinate)
+        conn = self.app.config_manager.get_connection(str(row_key.value))
+        if conn:
+            self.app.push_screen(WorkspaceUsageScreen(conn.id, conn.name))
+
+    def on_data_table_row_selected(self, event: DataTable.RowSelected) -> None:
+        """Open the workspace breakdown of a selected connection."""
+        if event.data_table.id == "storage-table":
+            self.action_workspace_usage()
+
     def on_button_pressed(self, event: Button.Pressed) -> None:
         """Handle button presses."""
         button_id = event.button.id
@@ -138,3 +302,12 @@ class HomeScreen(Screen):
             self.app.notify("Sync feature coming soon", severity="information")
         elif button_id == "btn-settings":
             self.app.push_screen("settings")
+        elif button_id == "btn-create-profile":
+            name_input = self.query_one("#new-profile", Input)
+            name = name_input.value.strip()
+            if not name:
+                self.app.notify("Please enter a profile name", severity="error")
+                return
+            if self._switch_profile(name):
+                name_input.value = ""
+                self.app.config_manager.save()
@@ -0,0 +1,162 @@
+"""Event hooks screen for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal
+from textual.screen import Screen
+from textual.widgets import Button, DataTable, Static
+
+from apps.core.config import EventHook, config_manager
+from apps.core.hooks import HookEvent, hook_runner
+
+from ..styles import check_label
+
+
+class HooksScreen(Screen):
+    """Screen listing the hooks run when resources change.
+
+    Hooks are written in the config file; here they can be switched on
+    and off and tried out with a sample event.
+    """
+
+    DEFAULT_CSS = """
+    HooksScreen {
+        layout: vertical;
+    }
+
+    .screen-header {
+        height: 3;
+        padding: 1;
+        background: $primary;
+    }
+
+    .hooks-table {
+        height: 1fr;
+        margin: 1;
+    }
+
+    .deliveries-table {
+        height: 1fr;
+        margin: 0 1 1 1;
+    }
+
+    .action-bar {
+        height: 3;
+        padding: 0 1;
+        background: $surface;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "app.pop_screen", "Back"),
+        ("e", "toggle_hook", "Enable/Disable"),
+        ("t", "test_hook", "Test"),
+        ("r", "refresh", "Refresh"),
+    ]
+
+    def compose(self) -> ComposeResult:
+        """Create the hooks screen layout."""
+        yield Static("Event Hooks", classes="screen-header")
+
+        hooks = DataTable(id="hooks-table", classes="hooks-table", cursor_type="row")
+        hooks.add_columns("Name", "Events", "Runs", "Connections", "Enabled")
+        yield hooks
+
+        deliveries = DataTable(id="deliveries-table", classes="deliveries-table")
+        deliveries.add_columns("Time", "Hook", "Event", "Result", "Message")
+        yield deliveries
+
+        with Horizontal(classes="action-bar"):
+            yield Button("Test", id="btn-test", variant="primary")
+            yield Button("Enable/Disable", id="btn-toggle")
+            yield Button("Refresh", id="btn-refresh")
+
+    def on_mount(self) -> None:
+        """Load hooks when the screen mounts."""
+        self.action_refresh()
+
+    def action_refresh(self) -> None:
+        """Reload the hooks and their recent deliveries."""
+        table = self.query_one("#hooks-table", DataTable)
+        table.clear()
+        if not config_manager.config.event_hooks:
+            self.app.notify(
+                "No hooks configured; add them under event_hooks in the config file",
+                severity="information",
+            )
+        for index, hook in enumerate(config_manager.config.event_hooks):
+            table.add_row(
+                hook.name,
+                ", ".join(hook.events),
+                hook.command or f"{hook.method} {hook.url}",
+                ", ".join(hook.connections) or "all",
+                check_label(hook.enabled),
+                key=str(index),
+            )
+
+        deliveries = self.query_one("#deliveries-table", DataTable)
+        deliveries.clear()
+        for result in hook_runner.recent():
+            deliveries.add_row(
+                result.time.astimezone().strftime("%H:%M:%S"),
+                result.hook,
+                result.event,
+                "OK" if result.success else "Failed",
+                result.message.splitlines()[0] if result.message else "",
+            )
+
+    def _selected_hook(self) -> EventHook | None:
+        """Get the hook under the table cursor."""
+        table = self.query_one("#hooks-table", DataTable)
+        if table.row_count == 0:
+            self.app.notify("No hook selected", severity="warning")
+            return None
+        row_key, _ = table.coordinate_to_cell_key(table.cursor_coordinate)
+        return config_manager.config.event_hooks[int(str(row_key.value))]
+
+    def action_toggle_hook(self) -> None:
+        """Enable or disable the selected hook."""
+        hook = self._selected_hook()
+        if hook:
+            hook.enabled = not hook.enabled
+            config_manager.save()
+            state = "enabled" if hook.enabled else "disabled"
+            self.app.notify(f"Hook '{hook.name}' {state}", severity="information")
+            self.action_refresh()
+
+    def action_test_hook(self) -> None:
+        """Run the selected hook with a sample event."""
+        hook = self._selected_hook()
+        if hook:
+            self.app.notify(f"Running hook '{hook.name}'...")
+            self.run_worker(lambda: self._test_hook(hook), thread=True)
+
+    def _test_hook(self, hook: EventHook) -> None:
+        """Run a hook off the UI thread."""
+        event = HookEvent(
+            name="layer.published",
+            connection="test",
+            workspace="workspace",
+            resource="layer",
+            details={"test": True},
+        )
+        result = hook_runner.test(hook, event)
+        if result.success:
+            self.app.call_from_thread(
+                self.app.notify, f"Hook '{hook.name}' ran in {result.duration_ms:.0f} ms"
+            )
+        else:
+            self.app.call_from_thread(
+                self.app.notify, f"Hook '{hook.name}' failed: {result.message}", severity="error"
+            )
+        self.app.call_from_thread(self.action_refresh)
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        button_id = event.button.id
+
+        if button_id == "btn-test":
+            self.action_test_hook()
+        elif button_id == "btn-toggle":
+            self.action_toggle_hook()
+        elif button_id == "btn-refresh":
+            self.action_refresh()
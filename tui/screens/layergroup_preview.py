@@ -0,0 +1,211 @@
+"""Layer group composition preview for Kartoza CloudBench TUI."""
+
+from rich.text import Text
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical, VerticalScroll
+from textual.screen import ModalScreen
+from textual.widgets import DataTable, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.core.raster import Image, decode_png
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.composition import render_composition
+from apps.geoserver.terminal_map import (
+    BACKGROUND,
+    detect_graphics,
+    render_ascii,
+    render_halfblock,
+)
+
+# Shown under the preview while it isn't rendering
+HINT = "u/d move the layer under the cursor; Enter keeps the order"
+
+
+def _draw(data: bytes, width: int | None = None, height: int | None = None) -> Text:
+    """Draw a PNG in the terminal, at a given size or its own."""
+    image = decode_png(data)
+    if width and height and (image.width, image.height) != (width, height):
+        image = image.resized(width, height)
+    image = image.composite_over(Image.blank(image.width, image.height, BACKGROUND))
+    if detect_graphics() == "ascii":
+        return Text(render_ascii(image))
+    return Text.from_ansi(render_halfblock(image))
+
+
+class LayerGroupPreviewScreen(ModalScreen[list[str] | None]):
+    """Dialog drawing layers over each other in order, with their legends.
+
+    The layers can be moved up and down to check the draw order before
+    the group is saved; the dialog is dismissed with the new order, or
+    None when closed with Escape.
+    """
+
+    DEFAULT_CSS = """
+    LayerGroupPreviewScreen {
+        align: center middle;
+    }
+
+    .group-preview-dialog {
+        width: 95%;
+        height: 90%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .group-preview-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .group-preview-body {
+        height: 1fr;
+    }
+
+    .group-preview-side {
+        width: 40;
+    }
+
+    .group-preview-order {
+        height: auto;
+        max-height: 12;
+    }
+
+    .group-preview-legends {
+        height: 1fr;
+        margin-top: 1;
+    }
+
+    .group-preview-map {
+        width: 1fr;
+        margin-left: 1;
+    }
+
+    .group-preview-status {
+        height: 1;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "cancel", "Cancel"),
+        ("enter", "accept", "Use Order"),
+        ("u", "move(1)", "Move Up"),
+        ("d", "move(-1)", "Move Down"),
+    ]
+
+    def __init__(self, conn_id: str, workspace: str, layers: list[str]) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace holding the layers
+            layers: Layer names, the first drawn at the bottom
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self.layers = list(layers)
+        # Bumped on every render so results of superseded renders are dropped
+        self._generation = 0
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="group-preview-dialog"):
+            yield Static(
+                f"Layer group preview: {len(self.layers)} layer(s) in {self.workspace}",
+                classes="group-preview-title",
+            )
+            with Horizontal(classes="group-preview-body"):
+                with Vertical(classes="group-preview-side"):
+                    table = DataTable(
+                        id="group-order", classes="group-preview-order", cursor_type="row"
+                    )
+                    table.add_columns("Draw order (top first)")
+                    yield table
+                    with VerticalScroll(classes="group-preview-legends"):
+                        yield Static("Loading legends...", id="group-legends")
+                yield Static("Rendering...", id="group-map", classes="group-preview-map")
+            yield Static(HINT, id="group-status", classes="group-preview-status")
+
+    def on_mount(self) -> None:
+        """List the layers and draw them."""
+        self._fill_order()
+        self.query_one("#group-order", DataTable).focus()
+        # The map's size is only known once the dialog is laid out
+        self.call_after_refresh(self._refresh)
+
+    def _fill_order(self, cursor: int = 0) -> None:
+        """List the layers top first, as they stack on the map."""
+        table = self.query_one("#group-order", DataTable)
+        table.clear()
+        for layer in reversed(self.layers):
+            table.add_row(layer)
+        table.move_cursor(row=cursor)
+
+    def _refresh(self) -> None:
+        """Render the map and the legends in a background thread."""
+        self._generation += 1
+        generation = self._generation
+        layers = list(self.layers)
+        size = self.query_one("#group-map", Static).size
+        width, height = max(size.width, 1), max(size.height * 2, 2)
+        self.query_one("#group-status", Static).update("Rendering...")
+        self.run_worker(lambda: self._render(generation, layers, width, height), thread=True)
+
+    def _render(self, generation: int, layers: list[str], width: int, height: int) -> None:
+        """Draw the composition and each layer's legend, top layer first."""
+        client = get_geoserver_client(self.conn_id)
+        try:
+            picture = _draw(
+                render_composition(client, self.workspace, layers, width, height),
+                width,
+                height,
+            )
+        except (GeoServerError, ValueError) as e:
+            picture = Text(f"Cannot render the group: {e}")
+
+        legends = Text()
+        for layer in reversed(layers):
+            legends.append(f"{layer}\n", style="bold")
+            try:
+                legends.append_text(
+                    _draw(client.get_legend_graphic(self.workspace, layer.rpartition(":")[2]))
+                )
+            except (GeoServerError, ValueError) as e:
+                legends.append(f"No legend: {e}")
+            legends.append("\n\n")
+        self.app.call_from_thread(self._show, generation, picture, legends)
+
+    def _show(self, generation: int, picture: Text, legends: Text) -> None:
+        """Show a finished render unless a newer one has started."""
+        if generation != self._generation:
+            return
+        self.query_one("#group-map", Static).update(picture)
+        self.query_one("#group-legends", Static).update(legends)
+        self.query_one("#group-status", Static).update(HINT)
+
+    def action_move(self, step: int) -> None:
+        """Move the layer under the cursor up (1) or down (-1) the stack."""
+        table = self.query_one("#group-order", DataTable)
+        if table.row_count < 2:
+            return
+        row = table.cursor_row
+        index = len(self.layers) - 1 - row
+        target = index + step
+        if not 0 <= target < len(self.layers):
+            return
+        self.layers[index], self.layers[target] = self.layers[target], self.layers[index]
+        self._fill_order(cursor=row - step)
+        self._refresh()
+
+    def on_data_table_row_selected(self, event: DataTable.RowSelected) -> None:
+        """Keep the order when Enter is pressed in the list."""
+        self.action_accept()
+
+    def action_accept(self) -> None:
+        """Close with the current order."""
+        self.dismiss(self.layers)
+
+    def action_cancel(self) -> None:
+        """Close without changing the order."""
+        self.dismiss(None)
@@ -0,0 +1,123 @@
+"""Catalog integrity check screen for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal
+from textual.screen import Screen
+from textual.widgets import Button, DataTable, Label, Select, Static
+
+from apps.core.config import config_manager
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.lint import LintIssue, lint_catalog
+from apps.gwc.client import get_gwc_client
+
+
+class LintScreen(Screen):
+    """Screen listing broken references in a connection's catalog."""
+
+    DEFAULT_CSS = """
+    LintScreen {
+        layout: vertical;
+    }
+
+    .screen-header {
+        height: 3;
+        padding: 1;
+        background: $primary;
+    }
+
+    .connection-selector {
+        height: 3;
+        padding: 0 1;
+        background: $surface;
+    }
+
+    .issues-table {
+        height: 1fr;
+        margin: 1;
+    }
+
+    .fix-panel {
+        height: 3;
+        padding: 0 1;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "app.pop_screen", "Back"),
+        ("x", "run_lint", "Run Check"),
+    ]
+
+    def __init__(self) -> None:
+        """Initialize the lint screen."""
+        super().__init__()
+        self._issues: list[LintIssue] = []
+
+    def compose(self) -> ComposeResult:
+        """Create the lint screen layout."""
+        yield Static("Catalog Check", classes="screen-header")
+
+        with Horizontal(classes="connection-selector"):
+            yield Label("Connection: ")
+            yield Select([], id="select-connection", prompt="Select a connection...")
+            yield Button("Run Check", id="btn-run", variant="primary")
+
+        table = DataTable(id="issues-table", classes="issues-table", cursor_type="row")
+        table.add_columns("Severity", "Check", "Resource", "Problem")
+        yield table
+
+        yield Static("Select an issue to see the suggested fix", id="fix", classes="fix-panel")
+
+    def on_mount(self) -> None:
+        """Load connections when screen mounts."""
+        select = self.query_one("#select-connection", Select)
+        select.set_options([(conn.name, conn.id) for conn in config_manager.list_connections()])
+
+    def action_run_lint(self) -> None:
+        """Scan the selected connection."""
+        conn_id = self.query_one("#select-connection", Select).value
+        if conn_id == Select.BLANK:
+            self.app.notify("Please select a connection", severity="error")
+            return
+
+        self.app.notify("Scanning catalog...", severity="information")
+        self.run_worker(lambda: self._lint(str(conn_id)), thread=True)
+
+    def _lint(self, conn_id: str) -> None:
+        """Run the checks in a background thread."""
+        try:
+            issues = lint_catalog(get_geoserver_client(conn_id), get_gwc_client(conn_id))
+        except Exception as e:
+            self.app.call_from_thread(
+                self.app.notify, f"Catalog check failed: {str(e)}", severity="error"
+            )
+            return
+        self.app.call_from_thread(self._show_issues, issues)
+
+    def _show_issues(self, issues: list[LintIssue]) -> None:
+        """Fill the issues table."""
+        self._issues = issues
+        table = self.query_one("#issues-table", DataTable)
+        table.clear()
+        for issue in issues:
+            table.add_row(issue.severity, issue.check, issue.resource, issue.message)
+
+        errors = sum(1 for i in issues if i.severity == "error")
+        if issues:
+            self.app.notify(
+                f"{errors} error(s), {len(issues) - errors} warning(s)",
+                severity="warning",
+            )
+        else:
+            self.query_one("#fix", Static).update("No broken references found")
+            self.app.notify("No broken references found", severity="information")
+
+    def on_data_table_row_highlighted(self, event: DataTable.RowHighlighted) -> None:
+        """Show the suggested fix of the highlighted issue."""
+        if 0 <= event.cursor_row < len(self._issues):
+            issue = self._issues[event.cursor_row]
+            self.query_one("#fix", Static).update(f"Suggested fix: {issue.fix}")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-run":
+            self.action_run_lint()
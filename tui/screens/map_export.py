@@ -0,0 +1,188 @@
+"""Web map export dialog for Kartoza CloudBench TUI."""
+
+from pathlib import Path
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical, VerticalScroll
+from textual.screen import ModalScreen
+from textual.widgets import Button, Input, Select, Static
+
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.gwc.map_export import (
+    DEFAULT_GRID_SET,
+    DEFAULT_TILE_FORMAT,
+    MapExport,
+    collect_layers,
+    export_map,
+    write_export,
+)
+
+# Formats offered, with the labels shown for them
+FORMAT_LABELS = (("Mapbox GL style", "mapbox"), ("MapProxy snippet", "mapproxy"))
+
+
+class MapExportScreen(ModalScreen[None]):
+    """Dialog writing a Mapbox GL style or MapProxy snippet for marked layers.
+
+    The layers are drawn in the order given, the first at the bottom.
+    """
+
+    DEFAULT_CSS = """
+    MapExportScreen {
+        align: center middle;
+    }
+
+    .map-export-dialog {
+        width: 90%;
+        height: 85%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .map-export-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .map-export-row {
+        height: auto;
+    }
+
+    .map-export-row Input, .map-export-row Select {
+        width: 1fr;
+    }
+
+    .map-export-content {
+        height: 1fr;
+        margin: 1 0;
+        border: solid $primary-darken-2;
+    }
+
+    .map-export-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, layers: list[str]) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            layers: Qualified layer and layer group names, bottom first
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.layers = layers
+        self._running = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        conn = config_manager.get_connection(self.conn_id)
+        with Vertical(classes="map-export-dialog"):
+            yield Static(f"Export Web Map: {len(self.layers)} layer(s)", classes="map-export-title")
+            with Horizontal(classes="map-export-row"):
+                yield Select(FORMAT_LABELS, value="mapbox", allow_blank=False, id="select-format")
+                yield Input(value=conn.name if conn else "", placeholder="Name", id="input-name")
+                yield Input(value=DEFAULT_GRID_SET, placeholder="Grid set", id="input-gridset")
+                yield Input(
+                    value=DEFAULT_TILE_FORMAT, placeholder="Tile format", id="input-tile-format"
+                )
+            with Horizontal(classes="map-export-row"):
+                yield Input(
+                    value=config_manager.config.last_local_path,
+                    placeholder="Folder to write to",
+                    id="input-folder",
+                )
+            with VerticalScroll(classes="map-export-content"):
+                yield Static("", id="map-export-content", markup=False)
+            yield Static(
+                "Layers: " + ", ".join(self.layers),
+                id="map-export-summary",
+                classes="map-export-summary",
+            )
+            with Horizontal(classes="map-export-row"):
+                yield Button("Preview", id="btn-preview", variant="primary")
+                yield Button("Write", id="btn-write")
+                yield Button("Close", id="btn-close")
+
+    def _generate(self, write: bool) -> None:
+        """Generate the configuration in a background thread, and write it if asked."""
+        if self._running:
+            return
+        export_format = str(self.query_one("#select-format", Select).value)
+        name = self.query_one("#input-name", Input).value.strip()
+        grid_set = self.query_one("#input-gridset", Input).value.strip() or DEFAULT_GRID_SET
+        tile_format = (
+            self.query_one("#input-tile-format", Input).value.strip() or DEFAULT_TILE_FORMAT
+        )
+        folder = Path(self.query_one("#input-folder", Input).value.strip() or ".").expanduser()
+        if not name:
+            self.app.notify("Enter a name for the map", severity="warning")
+            return
+        conn = config_manager.get_connection(self.conn_id)
+        if conn is None:
+            self.app.notify("Connection not found", severity="error")
+            return
+
+        self._running = True
+        self.query_one("#map-export-summary", Static).update("Reading the layers' bounds...")
+
+        def generate() -> None:
+            try:
+                export = export_map(
+                    conn.public_base_url,
+                    collect_layers(get_geoserver_client(self.conn_id), self.layers),
+                    export_format,
+                    name,
+                    grid_set,
+                    tile_format,
+                )
+                if write:
+                    write_export(export, folder)
+            except GeoServerError as e:
+                self.app.call_from_thread(self._on_failed, e.message)
+                return
+            except (OSError, ValueError) as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._show, export)
+
+        self.run_worker(generate, thread=True)
+
+    def _show(self, export: MapExport) -> None:
+        """Show the configuration and where it was written."""
+        self._running = False
+        self.query_one("#map-export-content", Static).update(export.content)
+        summary = self.query_one("#map-export-summary", Static)
+        if export.path:
+            summary.update(f"Wrote {export.path}")
+            self.app.notify(f"Wrote {export.path}", severity="information")
+        else:
+            summary.update(f"{export.filename}: press Write to save it")
+
+    def _on_failed(self, error: str) -> None:
+        """Report a configuration that can't be generated or written."""
+        self._running = False
+        self.query_one("#map-export-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-preview":
+            self._generate(write=False)
+        elif event.button.id == "btn-write":
+            self._generate(write=True)
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless the configuration is being generated."""
+        if self._running:
+            self.app.notify("Wait for the configuration to be generated", severity="warning")
+            return
+        self.dismiss(None)
@@ -0,0 +1,276 @@
+"""Layer map preview screen for Kartoza CloudBench TUI."""
+
+import shutil
+import sys
+from dataclasses import replace
+
+from rich.text import Text
+from textual.app import ComposeResult
+from textual.screen import Screen
+from textual.widgets import Static
+
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.terminal_map import (
+    BasemapTiles,
+    MapViewport,
+    detect_graphics,
+    encode_sixel,
+    render_ascii,
+    render_halfblock,
+    render_preview,
+)
+
+from .cql_builder import CqlBuilderScreen
+
+# Fraction of the view moved by one arrow key press
+PAN_STEP = 0.25
+
+
+class MapPreviewScreen(Screen):
+    """Screen drawing a WMS rendering of a layer in the terminal."""
+
+    DEFAULT_CSS = """
+    MapPreviewScreen {
+        layout: vertical;
+    }
+
+    .screen-header {
+        height: 3;
+        padding: 1;
+        background: $primary;
+    }
+
+    .map-view {
+        height: 1fr;
+    }
+
+    .map-status {
+        height: 1;
+        padding: 0 1;
+        background: $surface;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "app.pop_screen", "Back"),
+        ("plus,equals_sign", "zoom(2.0)", "Zoom In"),
+        ("minus", "zoom(0.5)", "Zoom Out"),
+        ("left", "pan(-1, 0)", "Pan"),
+        ("right", "pan(1, 0)", "Pan"),
+        ("up", "pan(0, 1)", "Pan"),
+        ("down", "pan(0, -1)", "Pan"),
+        ("b", "toggle_basemap", "Basemap"),
+        ("0", "reset", "Reset"),
+        ("s", "sixel", "Full Resolution"),
+        ("c", "filter", "CQL Filter"),
+    ]
+
+    def __init__(
+        self, conn_id: str, workspace: str, layer: str, group: bool = False, **kwargs
+    ) -> None:
+        """Initialize the preview.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace of the layer
+            layer: Layer or layer group name
+            group: Whether the layer is a layer group
+        """
+        super().__init__(**kwargs)
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self.layer = layer
+        self.group = group
+        self.graphics = detect_graphics()
+        self.show_basemap = True
+        self.cql_filter = ""
+        self._basemap = BasemapTiles()
+        self._bounds: dict | None = None
+        self._viewport: MapViewport | None = None
+        # Bumped on every render so results of superseded renders are dropped
+        self._generation = 0
+
+    def compose(self) -> ComposeResult:
+        """Create the preview layout."""
+        yield Static(f"Preview: {self.workspace}:{self.layer}", classes="screen-header")
+        yield Static("Loading...", id="map-view", classes="map-view")
+        yield Static("", id="map-status", classes="map-status")
+
+    def on_mount(self) -> None:
+        """Load the layer bounds, then draw the first view."""
+        self.run_worker(self._load_bounds, thread=True)
+
+    def on_resize(self) -> None:
+        """Redraw for the new size."""
+        if self._viewport is not None:
+            self._refresh_map()
+
+    def _load_bounds(self) -> None:
+        try:
+            client = get_geoserver_client(self.conn_id)
+            if self.group:
+                boxes = client.get_layergroup_bounds(self.layer, self.workspace)
+            else:
+                boxes = client.get_layer_bounds(self.workspace, self.layer)
+            bounds = boxes.get("latLonBoundingBox")
+        except Exception:
+            bounds = None  # Start from the whole world instead
+        self.app.call_from_thread(self._start, bounds)
+
+    def _start(self, bounds: dict | None) -> None:
+        self._bounds = bounds
+        width, height = self._pixel_size()
+        self._viewport = MapViewport.from_latlon_bbox(bounds, width, height)
+        self._refresh_map()
+
+    def _pixel_size(self) -> tuple[int, int]:
+        """Image size that fills the map view: one column and two rows per cell."""
+        size = self.query_one("#map-view", Static).size
+        return max(size.width, 1), max(size.height * 2, 2)
+
+    def _refresh_map(self) -> None:
+        """Render the current view in a background thread."""
+        if self._viewport is None:
+            return
+        self._generation += 1
+        generation = self._generation
+        viewport = replace(self._viewport)
+        width, height = self._pixel_size()
+        self._set_status("Rendering...")
+        self.run_worker(lambda: self._render(generation, viewport, width, height), thread=True)
+
+    def _render(self, generation: int, viewport: MapViewport, width: int, height: int) -> None:
+        try:
+            client = get_geoserver_client(self.conn_id)
+            preview = render_preview(
+                client,
+                self.workspace,
+                self.layer,
+                viewport,
+                width,
+                height,
+                basemap=self._basemap if self.show_basemap else None,
+                cql_filter=self.cql_filter or None,
+            )
+        except Exception as e:
+            self.app.call_from_thread(self._show_error, generation, str(e))
+            return
+        if self.graphics == "ascii":
+            text = Text(render_ascii(preview.image))
+        else:
+            text = Text.from_ansi(render_halfblock(preview.image))
+        self.app.call_from_thread(self._show, generation, text, preview.basemap_error)
+
+    def _show(self, generation: int, text: Text, basemap_error: str | None) -> None:
+        if generation != self._generation:
+            return
+        self.query_one("#map-view", Static).update(text)
+        status = self._describe_view()
+        if basemap_error:
+            status += f" | Basemap unavailable: {basemap_error}"
+        self._set_status(status)
+
+    def _show_error(self, generation: int, message: str) -> None:
+        if generation != self._generation:
+            return
+        self.query_one("#map-view", Static).update(f"Cannot render layer: {message}")
+        self._set_status(self._describe_view())
+
+    def _describe_view(self) -> str:
+        viewport = self._viewport
+        basemap = "on" if self.show_basemap else "off"
+        status = (
+            f"Zoom {viewport.tile_zoom()} | {viewport.resolution:,.1f} m/px | "
+            f"Basemap {basemap} | Graphics {self.graphics}"
+        )
+        if self.cql_filter:
+            status += f" | Filter: {self.cql_filter}"
+        return status
+
+    def _set_status(self, status: str) -> None:
+        self.query_one("#map-status", Static).update(status)
+
+    def action_zoom(self, factor: float) -> None:
+        """Zoom in or out around the center."""
+        if self._viewport is not None:
+            self._viewport.zoom(factor)
+            self._refresh_map()
+
+    def action_pan(self, dx: int, dy: int) -> None:
+        """Move the view by a step in a direction."""
+        if self._viewport is not None:
+            width, height = self._pixel_size()
+            self._viewport.pan(dx * PAN_STEP, dy * PAN_STEP, width, height)
+            self._refresh_map()
+
+    def action_toggle_basemap(self) -> None:
+        """Show or hide the basemap."""
+        self.show_basemap = not self.show_basemap
+        self._refresh_map()
+
+    def action_filter(self) -> None:
+        """Limit the preview to features matching a CQL filter."""
+
+        def apply(cql: str | None) -> None:
+            if cql is not None:
+                self.cql_filter = cql
+                self._refresh_map()
+
+        self.app.push_screen(
+            CqlBuilderScreen(self.conn_id, self.workspace, self.layer, self.cql_filter), apply
+        )
+
+    def action_reset(self) -> None:
+        """Go back to the layer's full extent."""
+        if self._viewport is not None:
+            self._start(self._bounds)
+
+    def action_sixel(self) -> None:
+        """Show the view at full resolution with sixel graphics outside the TUI."""
+        if self.graphics != "sixel":
+            self.app.notify(
+                "This terminal doesn't seem to support sixel graphics; "
+                "set CLOUDBENCH_GRAPHICS=sixel to force it",
+                severity="warning",
+            )
+            return
+        if self._viewport is None:
+            return
+
+        # Roughly 10x20 pixel cells, leaving a line for the prompt
+        columns, lines = shutil.get_terminal_size()
+        width, height = columns * 10, (lines - 1) * 20
+        viewport = MapViewport(
+            self._viewport.center_x,
+            self._viewport.center_y,
+            self._viewport.resolution * self._pixel_size()[0] / width,
+        )
+        self.app.notify("Rendering full resolution view...", severity="information")
+
+        def render() -> None:
+            try:
+                client = get_geoserver_client(self.conn_id)
+                preview = render_preview(
+                    client,
+                    self.workspace,
+                    self.layer,
+                    viewport,
+                    width,
+                    height,
+                    basemap=self._basemap if self.show_basemap else None,
+                    cql_filter=self.cql_filter or None,
+                )
+            except Exception as e:
+                self.app.call_from_thread(
+                    self.app.notify, f"Cannot render layer: {str(e)}", severity="error"
+                )
+                return
+            self.app.call_from_thread(self._show_sixel, encode_sixel(preview.image))
+
+        self.run_worker(render, thread=True)
+
+    def _show_sixel(self, sixel: str) -> None:
+        with self.app.suspend():
+            sys.stdout.write("\x1b[2J\x1b[H" + sixel + "\n")
+            sys.stdout.flush()
+            input("Press Enter to return")
@@ -0,0 +1,124 @@
+"""Notification center for Kartoza CloudBench TUI."""
+
+from rich.text import Text
+from textual.app import ComposeResult
+from textual.containers import Vertical
+from textual.screen import ModalScreen
+from textual.widgets import DataTable, Static
+
+from ..notification_log import NotificationEntry, NotificationLog
+
+# Severity -> how its label is colored
+SEVERITY_STYLES = {
+    "information": "green",
+    "warning": "yellow",
+    "error": "bold red",
+}
+
+
+def entry_row(entry: NotificationEntry) -> tuple[str, Text, str]:
+    """Time, colored severity and message of a notification."""
+    message = f"{entry.title}: {entry.message}" if entry.title else entry.message
+    severity = Text(entry.severity, style=SEVERITY_STYLES.get(entry.severity, ""))
+    return entry.time.strftime("%H:%M:%S"), severity, message
+
+
+class NotificationsScreen(ModalScreen[None]):
+    """Dialog listing the notifications shown this session, newest first."""
+
+    DEFAULT_CSS = """
+    NotificationsScreen {
+        align: center middle;
+    }
+
+    .notifications-dialog {
+        width: 90%;
+        height: 80%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .notifications-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .notifications-table {
+        height: 1fr;
+    }
+
+    .notifications-status {
+        height: auto;
+        margin-top: 1;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "close", "Close"),
+        ("e", "toggle_problems", "Errors Only"),
+        ("c", "clear", "Clear"),
+    ]
+
+    def __init__(self, log: NotificationLog) -> None:
+        """Initialize the dialog.
+
+        Args:
+            log: Notifications to list; they are marked read
+        """
+        super().__init__()
+        self.log = log
+        self.problems_only = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="notifications-dialog"):
+            yield Static("Notifications", classes="notifications-title")
+            yield DataTable(
+                id="notifications-table",
+                classes="notifications-table",
+                zebra_stripes=True,
+                cursor_type="row",
+            )
+            yield Static("", id="notifications-status", classes="notifications-status")
+
+    def on_mount(self) -> None:
+        """List the notifications and mark them read."""
+        table = self.query_one("#notifications-table", DataTable)
+        table.add_columns("Time", "Severity", "Message")
+        self.log.mark_read()
+        self._show()
+        table.focus()
+
+    def _show(self) -> None:
+        """Fill the table with the notifications."""
+        table = self.query_one("#notifications-table", DataTable)
+        table.clear()
+        entries = self.log.entries(problems_only=self.problems_only)
+        for entry in entries:
+            table.add_row(*entry_row(entry))
+
+        if entries:
+            status = f"{len(entries)} notifications"
+        elif self.problems_only:
+            status = "No warnings or errors"
+        else:
+            status = "No notifications yet"
+        if self.problems_only:
+            status += " | warnings and errors only"
+        status += "\ne errors only, c clear, Esc close"
+        self.query_one("#notifications-status", Static).update(status)
+
+    def action_toggle_problems(self) -> None:
+        """Switch between every notification and only warnings and errors."""
+        self.problems_only = not self.problems_only
+        self._show()
+
+    def action_clear(self) -> None:
+        """Forget every notification."""
+        self.log.clear()
+        self._show()
+
+    def action_close(self) -> None:
+        """Close the dialog."""
+        self.dismiss(None)
@@ -0,0 +1,169 @@
+"""OIDC device sign-in dialog for Kartoza CloudBench TUI."""
+
+import webbrowser
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, Static
+
+from apps.core.config import Connection
+from apps.core.exceptions import TokenError
+from apps.core.oidc import (
+    DeviceAuthorization,
+    is_signed_in,
+    sign_out,
+    start_sign_in,
+    wait_for_sign_in,
+)
+
+
+class OIDCSignInScreen(ModalScreen[bool]):
+    """Dialog signing a connection in to its OpenID Connect provider.
+
+    Shows the code to enter on the provider's page and waits in the
+    background until it has been entered. Dismissed with whether the
+    connection ended up signed in.
+    """
+
+    DEFAULT_CSS = """
+    OIDCSignInScreen {
+        align: center middle;
+    }
+
+    .signin-dialog {
+        width: 70;
+        height: auto;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .signin-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .signin-code {
+        text-style: bold;
+        color: $accent;
+        height: 2;
+    }
+
+    .signin-row {
+        height: auto;
+        margin-top: 1;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, connection: Connection) -> None:
+        """Initialize the dialog.
+
+        Args:
+            connection: Connection using OIDC
+        """
+        super().__init__()
+        self.connection = connection
+        self._device: DeviceAuthorization | None = None
+        self._closing = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="signin-dialog"):
+            yield Static(f"Sign in to {self.connection.name}", classes="signin-title")
+            yield Static("", id="signin-status")
+            yield Static("", id="signin-code", classes="signin-code")
+            with Horizontal(classes="signin-row"):
+                yield Button("Sign In", id="btn-sign-in", variant="primary")
+                yield Button("Open Browser", id="btn-open-browser")
+                yield Button("Sign Out", id="btn-sign-out", variant="error")
+                yield Button("Close", id="btn-close")
+
+    def on_mount(self) -> None:
+        """Show whether the connection is already signed in."""
+        self._show_signed_in(is_signed_in(self.connection))
+
+    def _show_signed_in(self, signed_in: bool) -> None:
+        """Offer to sign in or out."""
+        self.query_one("#signin-status", Static).update(
+            "Signed in; the refresh token is kept in the system keyring"
+            if signed_in
+            else f"Not signed in to {self.connection.oidc_issuer}"
+        )
+        self.query_one("#signin-code", Static).update("")
+        self.query_one("#btn-sign-in", Button).display = not signed_in
+        self.query_one("#btn-sign-out", Button).display = signed_in
+        self.query_one("#btn-open-browser", Button).display = False
+
+    def _sign_in(self) -> None:
+        """Start a device sign-in and wait for the code in a background thread."""
+        self.query_one("#btn-sign-in", Button).disabled = True
+        self.query_one("#signin-status", Static).update("Starting sign-in...")
+
+        def run() -> None:
+            try:
+                device = start_sign_in(self.connection)
+                self.app.call_from_thread(self._show_code, device)
+                signed_in = wait_for_sign_in(
+                    self.connection, device, cancelled=lambda: self._closing
+                )
+            except TokenError as e:
+                self.app.call_from_thread(self._on_failed, e.message)
+                return
+            if signed_in:
+                self.app.call_from_thread(self._on_signed_in)
+
+        self.run_worker(run, thread=True, group="oidc-sign-in", exclusive=True)
+
+    def _show_code(self, device: DeviceAuthorization) -> None:
+        """Show the code to enter and where to enter it."""
+        self._device = device
+        self.query_one("#signin-status", Static).update(
+            f"Enter this code at {device.verification_uri}"
+        )
+        self.query_one("#signin-code", Static).update(device.user_code)
+        self.query_one("#btn-open-browser", Button).display = True
+
+    def _on_signed_in(self) -> None:
+        """Report the sign-in."""
+        self.query_one("#btn-sign-in", Button).disabled = False
+        self._show_signed_in(True)
+        self.app.notify(f"Signed in to {self.connection.name}", severity="information")
+
+    def _on_failed(self, error: str) -> None:
+        """Report a sign-in that was refused or timed out."""
+        self.query_one("#btn-sign-in", Button).disabled = False
+        self.query_one("#btn-open-browser", Button).display = False
+        self.query_one("#signin-code", Static).update("")
+        self.query_one("#signin-status", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def _sign_out(self) -> None:
+        """Forget the connection's tokens."""
+        try:
+            sign_out(self.connection)
+        except TokenError as e:
+            self.app.notify(e.message, severity="error")
+            return
+        self._show_signed_in(False)
+        self.app.notify(f"Signed out of {self.connection.name}", severity="information")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-sign-in":
+            self._sign_in()
+        elif event.button.id == "btn-open-browser" and self._device:
+            webbrowser.open(
+                self._device.verification_uri_complete or self._device.verification_uri
+            )
+        elif event.button.id == "btn-sign-out":
+            self._sign_out()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog, giving up on a sign-in still waiting for the code."""
+        self._closing = True
+        self.dismiss(is_signed_in(self.connection))
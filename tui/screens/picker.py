@@ -0,0 +1,71 @@
+"""Option picker dialog for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Vertical
+from textual.screen import ModalScreen
+from textual.widgets import OptionList, Static
+from textual.widgets.option_list import Option
+
+
+class PickerScreen(ModalScreen[str | None]):
+    """Dialog that lets the user pick one of several values.
+
+    Dismisses with the picked value, or None when cancelled.
+    """
+
+    DEFAULT_CSS = """
+    PickerScreen {
+        align: center middle;
+    }
+
+    .picker-dialog {
+        width: 60;
+        height: auto;
+        max-height: 80%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .picker-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .picker-dialog OptionList {
+        height: auto;
+        max-height: 20;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "cancel", "Cancel"),
+    ]
+
+    def __init__(self, title: str, options: list[tuple[str, str]]) -> None:
+        """Initialize the picker.
+
+        Args:
+            title: What is being picked
+            options: (label, value) pairs
+        """
+        super().__init__()
+        self.picker_title = title
+        self.options = options
+
+    def compose(self) -> ComposeResult:
+        """Create the picker layout."""
+        with Vertical(classes="picker-dialog"):
+            yield Static(self.picker_title, classes="picker-title")
+            yield OptionList(
+                *[Option(label, id=str(i)) for i, (label, _) in enumerate(self.options)],
+                id="picker-options",
+            )
+
+    def on_option_list_option_selected(self, event: OptionList.OptionSelected) -> None:
+        """Return the picked value."""
+        self.dismiss(self.options[int(event.option.id or 0)][1])
+
+    def action_cancel(self) -> None:
+        """Close without picking."""
+        self.dismiss(None)
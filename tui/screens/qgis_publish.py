@@ -0,0 +1,185 @@
+"""QGIS project publish dialog for Kartoza CloudBench TUI."""
+
+from pathlib import Path
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, DataTable, Input, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.qgis.project import QGISProjectFile, read_project
+from apps.qgis.publish import STATUSES, ProjectPublishResult, publish_project
+
+
+class QGISPublishScreen(ModalScreen[bool]):
+    """Dialog publishing a .qgs or .qgz project's layers into a workspace.
+
+    Dismisses with True once something was published, so the caller
+    knows to refresh its tree.
+    """
+
+    DEFAULT_CSS = """
+    QGISPublishScreen {
+        align: center middle;
+    }
+
+    .qgis-dialog {
+        width: 90%;
+        height: 85%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .qgis-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .qgis-row {
+        height: auto;
+    }
+
+    .qgis-row Input {
+        width: 1fr;
+    }
+
+    .qgis-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .qgis-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, workspace: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace to publish into
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self._running = False
+        self._published = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="qgis-dialog"):
+            yield Static(f"Publish QGIS Project into {self.workspace}", classes="qgis-title")
+            with Horizontal(classes="qgis-row"):
+                yield Input(placeholder="Project file (.qgs or .qgz)", id="input-project")
+                yield Input(placeholder="Layer group title (project title)", id="input-group")
+            yield DataTable(id="qgis-table", classes="qgis-table")
+            yield Static(
+                "PostGIS, GeoPackage, shapefile and GeoTIFF layers are published with their "
+                "basic symbology as SLD, and grouped as the project's layer tree.",
+                id="qgis-summary",
+                classes="qgis-summary",
+            )
+            with Horizontal(classes="qgis-row"):
+                yield Button("Read", id="btn-read")
+                yield Button("Publish", id="btn-publish", variant="primary")
+                yield Button("Close", id="btn-close")
+
+    def _read(self) -> QGISProjectFile | None:
+        """Read the project file and list its layers."""
+        value = self.query_one("#input-project", Input).value.strip()
+        if not value:
+            self.app.notify("Enter the path of a .qgs or .qgz file", severity="warning")
+            return None
+        try:
+            project = read_project(Path(value).expanduser())
+        except (OSError, ValueError) as e:
+            self._on_failed(str(e))
+            return None
+
+        table = self.query_one("#qgis-table", DataTable)
+        table.clear(columns=True)
+        table.add_columns("Layer", "Kind", "Provider", "Data source")
+        for layer_id in dict.fromkeys(project.tree.layer_ids()):
+            layer = project.layers.get(layer_id)
+            if layer:
+                table.add_row(layer.name, layer.kind, layer.provider, layer.source)
+        self.query_one("#qgis-summary", Static).update(
+            f"{table.row_count} layer(s) in {project.title or value}"
+        )
+        return project
+
+    def _publish(self) -> None:
+        """Publish the project in a background thread."""
+        if self._running:
+            return
+        project = self._read()
+        if project is None:
+            return
+        group = self.query_one("#input-group", Input).value.strip() or None
+
+        self._running = True
+        self.query_one("#btn-publish", Button).disabled = True
+        self.query_one("#qgis-summary", Static).update("Publishing...")
+
+        def publish() -> None:
+            try:
+                result = publish_project(
+                    get_geoserver_client(self.conn_id), project, self.workspace, group
+                )
+            except GeoServerError as e:
+                self.app.call_from_thread(self._on_failed, e.message)
+                return
+            self.app.call_from_thread(self._show_result, result)
+
+        self.run_worker(publish, thread=True)
+
+    def _show_result(self, result: ProjectPublishResult) -> None:
+        """List what happened to each layer."""
+        self._running = False
+        self._published = True
+        self.query_one("#btn-publish", Button).disabled = False
+        table = self.query_one("#qgis-table", DataTable)
+        table.clear(columns=True)
+        table.add_columns("Layer", "Published as", "Store", "Status", "Style", "Message")
+        for outcome in result.layers:
+            table.add_row(
+                outcome.source,
+                outcome.layer,
+                outcome.store,
+                outcome.status,
+                outcome.style,
+                outcome.message,
+            )
+        lines = [", ".join(f"{result.count(status)} {status}" for status in STATUSES)]
+        lines += [f"Layer group {group}" for group in result.groups]
+        lines += [f"[red]{error}[/]" for error in result.errors]
+        self.query_one("#qgis-summary", Static).update("\n".join(lines))
+
+    def _on_failed(self, error: str) -> None:
+        """Report a project that can't be read or published."""
+        self._running = False
+        self.query_one("#btn-publish", Button).disabled = False
+        self.query_one("#qgis-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-read":
+            self._read()
+        elif event.button.id == "btn-publish":
+            self._publish()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless the project is being published."""
+        if self._running:
+            self.app.notify("Wait for the project to be published", severity="warning")
+            return
+        self.dismiss(self._published)
@@ -0,0 +1,201 @@
+"""Render benchmark dialog for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, DataTable, Input, Select, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.render_benchmark import (
+    DEFAULT_SAMPLES,
+    DEFAULT_SIZE,
+    IMAGE_SIZES,
+    BenchmarkReport,
+    RenderSample,
+    run_benchmark,
+)
+
+
+def _seconds(value: float | None) -> str:
+    if value is None:
+        return "-"
+    return f"{value * 1000:.0f} ms" if value < 1 else f"{value:.2f} s"
+
+
+class RenderBenchmarkScreen(ModalScreen[None]):
+    """Dialog timing GetMap requests of a layer across zoom levels."""
+
+    DEFAULT_CSS = """
+    RenderBenchmarkScreen {
+        align: center middle;
+    }
+
+    .benchmark-dialog {
+        width: 90%;
+        height: 85%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .benchmark-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .benchmark-row {
+        height: auto;
+    }
+
+    .benchmark-row Input, .benchmark-row Select {
+        width: 1fr;
+    }
+
+    .benchmark-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .benchmark-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, workspace: str, layer: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace name
+            layer: Layer name
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self.layer = layer
+        self._running = False
+        self._done = 0
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="benchmark-dialog"):
+            yield Static(
+                f"Render Benchmark: {self.workspace}:{self.layer}", classes="benchmark-title"
+            )
+            with Horizontal(classes="benchmark-row"):
+                yield Input(placeholder="Style (layer default)", id="input-style")
+                yield Input(placeholder="Zoom levels (automatic), e.g. 6,10,14", id="input-zooms")
+                yield Input(
+                    placeholder=f"Places per zoom ({DEFAULT_SAMPLES})", id="input-samples"
+                )
+                yield Select(
+                    [(f"{size} x {size}", size) for size in IMAGE_SIZES],
+                    value=DEFAULT_SIZE,
+                    allow_blank=False,
+                    id="select-size",
+                )
+            table = DataTable(id="benchmark-table", classes="benchmark-table")
+            table.add_columns("Zoom", "Scale", "p50", "p95", "Mean size", "Notes")
+            yield table
+            yield Static(
+                "Times uncached GetMap requests at places sampled in the layer.",
+                id="benchmark-summary",
+                classes="benchmark-summary",
+            )
+            with Horizontal(classes="benchmark-row"):
+                yield Button("Run", id="btn-run", variant="primary")
+                yield Button("Close", id="btn-close")
+
+    def _run(self) -> None:
+        """Read the settings and time the requests in a background thread."""
+        if self._running:
+            return
+        zooms_text = self.query_one("#input-zooms", Input).value
+        samples_text = self.query_one("#input-samples", Input).value.strip()
+        try:
+            zooms = [int(z) for z in zooms_text.replace(",", " ").split()] or None
+            samples = int(samples_text) if samples_text else DEFAULT_SAMPLES
+        except ValueError:
+            self.app.notify("Zoom levels and places must be whole numbers", severity="error")
+            return
+        style = self.query_one("#input-style", Input).value.strip()
+        size = self.query_one("#select-size", Select).value
+
+        self._running = True
+        self._done = 0
+        self.query_one("#benchmark-table", DataTable).clear()
+        self.query_one("#benchmark-summary", Static).update("Warming up...")
+
+        def run() -> None:
+            try:
+                report = run_benchmark(
+                    get_geoserver_client(self.conn_id),
+                    self.workspace,
+                    self.layer,
+                    style=style,
+                    zooms=zooms,
+                    samples=samples,
+                    size=size,
+                    on_sample=lambda sample: self.app.call_from_thread(self._on_sample, sample),
+                )
+            except (GeoServerError, ValueError) as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._show_report, report)
+
+        self.run_worker(run, thread=True)
+
+    def _on_sample(self, sample: RenderSample) -> None:
+        """Show progress."""
+        self._done += 1
+        self.query_one("#benchmark-summary", Static).update(
+            f"Rendered {self._done} map(s); last at zoom {sample.zoom} took "
+            f"{_seconds(sample.seconds)}"
+        )
+
+    def _show_report(self, report: BenchmarkReport) -> None:
+        """Fill the table with the statistics per zoom level."""
+        self._running = False
+        table = self.query_one("#benchmark-table", DataTable)
+        for stats in report.zoom_stats():
+            notes = []
+            if stats.slow:
+                notes.append("[yellow]slow[/]")
+            if stats.errors:
+                notes.append(f"[red]{stats.errors} of {stats.requests} failed[/]")
+            table.add_row(
+                str(stats.zoom),
+                f"1:{stats.scale:,.0f}",
+                _seconds(stats.p50),
+                _seconds(stats.p95),
+                f"{stats.mean_bytes / 1024:.1f} KB",
+                " ".join(notes),
+            )
+        summary = report.to_dict()
+        self.query_one("#benchmark-summary", Static).update(
+            f"{len(report.samples)} maps: p50 {_seconds(summary['p50'])}, "
+            f"p95 {_seconds(summary['p95'])}"
+        )
+
+    def _on_failed(self, error: str) -> None:
+        """Report a failed run."""
+        self._running = False
+        self.query_one("#benchmark-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-run":
+            self._run()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless a run is going."""
+        if self._running:
+            self.app.notify("Wait for the benchmark to finish", severity="warning")
+            return
+        self.dismiss(None)
@@ -7,6 +7,9 @@ from textual.widgets import Button, DataTable, Label, Select, Static, Tree
 
 from apps.core.config import config_manager
 
+from ..widgets import Splitter
+from .stac_catalog import StacCatalogScreen
+
 
 class S3Screen(Screen):
     """Screen for browsing S3-compatible storage."""
@@ -35,12 +38,11 @@ class S3Screen(Screen):
 
     .bucket-tree {
         width: 30%;
-        border-right: solid $primary;
         padding: 1;
     }
 
     .objects-panel {
-        width: 70%;
+        width: 1fr;
         padding: 1;
     }
 
@@ -54,8 +56,14 @@ class S3Screen(Screen):
     BINDINGS = [
         ("escape", "app.pop_screen", "Back"),
         ("r", "refresh", "Refresh"),
+        ("c", "stac_catalog", "STAC Catalog"),
     ]
 
+    def __init__(self, **kwargs):
+        """Initialize screen."""
+        super().__init__(**kwargs)
+        self.current_connection_id: str | None = None
+
     def compose(self) -> ComposeResult:
         """Create the S3 screen layout."""
         yield Static("S3 Storage Browser", classes="screen-header")
@@ -67,6 +75,7 @@ class S3Screen(Screen):
 
         with Container(classes="browser-container"):
             yield Tree("Buckets", id="bucket-tree", classes="bucket-tree")
+            yield Splitter()
             yield Container(
                 DataTable(id="objects-table"),
                 classes="objects-panel",
@@ -76,6 +85,7 @@ class S3Screen(Screen):
             yield Button("Create Bucket", id="btn-create-bucket")
             yield Button("Upload", id="btn-upload", variant="primary")
             yield Button("Download", id="btn-download")
+            yield Button("STAC Catalog", id="btn-stac")
             yield Button("Delete", id="btn-delete", variant="error")
 
     def on_mount(self) -> None:
@@ -97,7 +107,8 @@ class S3Screen(Screen):
     def on_select_changed(self, event: Select.Changed) -> None:
         """Handle connection selection."""
         if event.select.id == "s3-connection-select" and event.value:
-            self._load_buckets(str(event.value))
+            self.current_connection_id = str(event.value)
+            self._load_buckets(self.current_connection_id)
 
     def _load_buckets(self, conn_id: str) -> None:
         """Load buckets for a connection."""
@@ -118,9 +129,18 @@ class S3Screen(Screen):
         self._refresh_connections()
         self.app.notify("Refreshed", severity="information")
 
+    def action_stac_catalog(self) -> None:
+        """Generate a STAC catalog for a prefix of one of the connection's buckets."""
+        if not self.current_connection_id:
+            self.app.notify("Select an S3 connection first", severity="warning")
+            return
+        self.app.push_screen(StacCatalogScreen(self.current_connection_id))
+
     def on_button_pressed(self, event: Button.Pressed) -> None:
         """Handle button presses."""
         if event.button.id == "btn-refresh":
             self.action_refresh()
+        elif event.button.id == "btn-stac":
+            self.action_stac_catalog()
         else:
             self.app.notify("Feature coming soon", severity="information")
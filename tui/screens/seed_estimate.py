@@ -0,0 +1,249 @@
+"""Tile cache seed dialog for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, DataTable, Input, Select, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.gwc.client import get_gwc_client
+from apps.gwc.estimate import SeedEstimate, estimate_seed
+
+from ..styles import size_label
+from .confirm import ConfirmScreen
+
+# Tile formats offered, as GeoServer caches them by default
+TILE_FORMATS = ("image/png", "image/jpeg")
+
+
+class SeedEstimateScreen(ModalScreen[None]):
+    """Dialog estimating the tiles and storage of a seed, then starting it."""
+
+    DEFAULT_CSS = """
+    SeedEstimateScreen {
+        align: center middle;
+    }
+
+    .seed-dialog {
+        width: 90%;
+        height: 85%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .seed-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .seed-row {
+        height: auto;
+    }
+
+    .seed-row Input, .seed-row Select {
+        width: 1fr;
+    }
+
+    .seed-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .seed-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, workspace: str, layer: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace name
+            layer: Layer name
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self.layer = layer
+        self._running = False
+        # Settings last estimated and their estimate, confirmed before seeding
+        self._estimate: tuple[tuple[str, int, int, str], SeedEstimate] | None = None
+
+    @property
+    def layer_name(self) -> str:
+        """Full layer name, as GWC knows it."""
+        return f"{self.workspace}:{self.layer}"
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="seed-dialog"):
+            yield Static(f"Seed Tile Cache: {self.layer_name}", classes="seed-title")
+            with Horizontal(classes="seed-row"):
+                yield Input(value="EPSG:900913", placeholder="Grid set", id="input-gridset")
+                yield Input(value="0", placeholder="First zoom", id="input-zoom-start")
+                yield Input(value="10", placeholder="Last zoom", id="input-zoom-stop")
+                yield Select(
+                    [(fmt, fmt) for fmt in TILE_FORMATS],
+                    value=TILE_FORMATS[0],
+                    allow_blank=False,
+                    id="select-format",
+                )
+            table = DataTable(id="seed-table", classes="seed-table")
+            table.add_columns("Zoom", "Columns", "Rows", "Tiles", "Size")
+            yield table
+            yield Static(
+                "Estimate the tiles a seed renders before starting it.",
+                id="seed-summary",
+                classes="seed-summary",
+            )
+            with Horizontal(classes="seed-row"):
+                yield Button("Estimate", id="btn-estimate", variant="primary")
+                yield Button("Seed", id="btn-seed")
+                yield Button("Close", id="btn-close")
+
+    def _settings(self) -> tuple[str, int, int, str] | None:
+        """Read the grid set, zoom range and format, or None if they're invalid."""
+        grid_set = self.query_one("#input-gridset", Input).value.strip()
+        try:
+            zoom_start = int(self.query_one("#input-zoom-start", Input).value)
+            zoom_stop = int(self.query_one("#input-zoom-stop", Input).value)
+        except ValueError:
+            self.app.notify("Zoom levels must be whole numbers", severity="error")
+            return None
+        if not grid_set or zoom_stop < zoom_start:
+            self.app.notify(
+                "Give a grid set and a last zoom no lower than the first", severity="error"
+            )
+            return None
+        tile_format = str(self.query_one("#select-format", Select).value)
+        return grid_set, zoom_start, zoom_stop, tile_format
+
+    def _run_estimate(self) -> None:
+        """Compute the estimate in a background thread."""
+        if self._running:
+            return
+        settings = self._settings()
+        if settings is None:
+            return
+        grid_set, zoom_start, zoom_stop, tile_format = settings
+
+        self._running = True
+        self._estimate = None
+        self.query_one("#seed-table", DataTable).clear()
+        summary = self.query_one("#seed-summary", Static)
+        summary.update("Reading the grid set and layer bounds...")
+
+        def estimate() -> None:
+            try:
+                gridset_data = get_gwc_client(self.conn_id).get_gridset(grid_set)
+                bounds = get_geoserver_client(self.conn_id).get_layer_bounds(
+                    self.workspace, self.layer
+                )
+                result = estimate_seed(
+                    gridset_data,
+                    zoom_start,
+                    zoom_stop,
+                    tile_format=tile_format,
+                    latlon_bbox=bounds.get("latLonBoundingBox"),
+                    native_bbox=bounds.get("nativeBoundingBox"),
+                )
+            except (GeoServerError, ValueError) as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._show, settings, result)
+
+        self.run_worker(estimate, thread=True)
+
+    def _show(self, settings: tuple[str, int, int, str], estimate: SeedEstimate) -> None:
+        """Fill the table with the tiles per zoom level."""
+        self._running = False
+        self._estimate = (settings, estimate)
+        table = self.query_one("#seed-table", DataTable)
+        for zoom in estimate.zoom_levels:
+            table.add_row(
+                str(zoom.zoom),
+                f"{zoom.columns:,}",
+                f"{zoom.rows:,}",
+                f"{zoom.tiles:,}",
+                size_label(zoom.tiles * estimate.avg_tile_bytes),
+            )
+        self.query_one("#seed-summary", Static).update(
+            f"{estimate.total_tiles:,} tiles, about {size_label(estimate.estimated_bytes)} "
+            f"at {size_label(estimate.avg_tile_bytes)} a tile (bounds from "
+            f"{estimate.bounds_source})"
+        )
+
+    def _seed(self) -> None:
+        """Start the estimated seed once confirmed."""
+        if self._running:
+            return
+        settings = self._settings()
+        if settings is None:
+            return
+        if self._estimate is None or self._estimate[0] != settings:
+            self.app.notify("Estimate these settings before seeding", severity="warning")
+            return
+        grid_set, zoom_start, zoom_stop, tile_format = settings
+        estimate = self._estimate[1]
+
+        def confirmed(ok: bool | None) -> None:
+            if ok:
+                self.run_worker(
+                    lambda: self._start(grid_set, zoom_start, zoom_stop, tile_format),
+                    thread=True,
+                )
+
+        self.app.push_screen(
+            ConfirmScreen(
+                f"Seed {self.layer_name}",
+                f"Render {estimate.total_tiles:,} tiles, about "
+                f"{size_label(estimate.estimated_bytes)}, in {grid_set} "
+                f"at zoom {zoom_start} to {zoom_stop}?",
+                confirm_label="Seed",
+            ),
+            confirmed,
+        )
+
+    def _start(self, grid_set: str, zoom_start: int, zoom_stop: int, tile_format: str) -> None:
+        """Start the seed task off the UI thread."""
+        try:
+            get_gwc_client(self.conn_id).seed_layer(
+                self.layer_name,
+                grid_set=grid_set,
+                zoom_start=zoom_start,
+                zoom_stop=zoom_stop,
+                format=tile_format,
+            )
+        except GeoServerError as e:
+            self.app.call_from_thread(self._on_failed, str(e))
+            return
+        self.app.call_from_thread(
+            self.app.notify, f"Seeding {self.layer_name} started", severity="information"
+        )
+
+    def _on_failed(self, error: str) -> None:
+        """Report a failed estimate or seed."""
+        self._running = False
+        self.query_one("#seed-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-estimate":
+            self._run_estimate()
+        elif event.button.id == "btn-seed":
+            self._seed()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless an estimate is running."""
+        if self._running:
+            self.app.notify("Wait for the estimate to finish", severity="warning")
+            return
+        self.dismiss(None)
@@ -5,7 +5,12 @@ from textual.containers import Container, Horizontal, Vertical
 from textual.screen import Screen
 from textual.widgets import Button, Input, Label, Select, Static, Switch
 
-from apps.core.config import config_manager
+from apps.core.config import TimeoutSettings, config_manager
+
+from ..styles import theme_names
+
+# Timeout setting -> label
+TIMEOUT_LABELS = {"upload": "Uploads", "download": "Downloads", "ows": "OWS Requests"}
 
 
 class SettingsScreen(Screen):
@@ -75,11 +80,15 @@ class SettingsScreen(Screen):
             with Horizontal(classes="setting-row"):
                 yield Label("Theme:", classes="setting-label")
                 yield Select(
-                    [("Default", "default"), ("Dark", "dark"), ("Light", "light")],
+                    [(name.replace("-", " ").title(), name) for name in theme_names()],
                     id="theme-select",
                     value="default",
                 )
 
+            with Horizontal(classes="setting-row"):
+                yield Label("Accessible Mode:", classes="setting-label")
+                yield Switch(id="accessible-mode")
+
             yield Static("Monitoring", classes="section-header")
 
             with Horizontal(classes="setting-row"):
@@ -92,6 +101,14 @@ class SettingsScreen(Screen):
                 yield Label("Default Path:", classes="setting-label")
                 yield Input(id="default-path", placeholder="/home/user/data")
 
+            # Idle timeouts; catalog requests use each connection's response timeout
+            yield Static("Timeouts (seconds, 0 = none)", classes="section-header")
+
+            for kind, label in TIMEOUT_LABELS.items():
+                with Horizontal(classes="setting-row"):
+                    yield Label(f"{label}:", classes="setting-label")
+                    yield Input(id=f"timeout-{kind}", type="number")
+
         with Horizontal(classes="action-bar"):
             yield Button("Save", id="btn-save", variant="primary")
             yield Button("Reset to Defaults", id="btn-reset")
@@ -107,9 +124,16 @@ class SettingsScreen(Screen):
         config = config_manager.config
 
         # Set current values
-        self.query_one("#theme-select", Select).value = config.theme
+        themes = theme_names(config.custom_themes)
+        select = self.query_one("#theme-select", Select)
+        select.set_options([(name.replace("-", " ").title(), name) for name in themes])
+        select.value = config.theme if config.theme in themes else "default"
+        self.query_one("#accessible-mode", Switch).value = config.accessible_mode
         self.query_one("#ping-interval", Input).value = str(config.ping_interval_secs)
         self.query_one("#default-path", Input).value = config.last_local_path
+        for kind in TIMEOUT_LABELS:
+            value = getattr(config.timeouts, kind)
+            self.query_one(f"#timeout-{kind}", Input).value = f"{value:g}"
 
     def action_save(self) -> None:
         """Save settings."""
@@ -120,14 +144,20 @@ class SettingsScreen(Screen):
         theme = self.query_one("#theme-select", Select).value
         ping_interval = self.query_one("#ping-interval", Input).value
         default_path = self.query_one("#default-path", Input).value
+        accessible = self.query_one("#accessible-mode", Switch).value
 
         try:
             config = config_manager.config
             config.theme = str(theme) if theme else "default"
             config.ping_interval_secs = int(ping_interval) if ping_interval else 60
             config.last_local_path = default_path
+            config.accessible_mode = accessible
+            for kind in TIMEOUT_LABELS:
+                value = self.query_one(f"#timeout-{kind}", Input).value
+                setattr(config.timeouts, kind, max(0.0, float(value or 0)))
 
             config_manager.save()
+            self.app.apply_theme()
             self.app.notify("Settings saved", severity="information")
 
         except Exception as e:
@@ -141,5 +171,9 @@ class SettingsScreen(Screen):
             self.app.pop_screen()
         elif event.button.id == "btn-reset":
             self.query_one("#theme-select", Select).value = "default"
+            self.query_one("#accessible-mode", Switch).value = False
             self.query_one("#ping-interval", Input).value = "60"
+            for kind in TIMEOUT_LABELS:
+                default = getattr(TimeoutSettings(), kind)
+                self.query_one(f"#timeout-{kind}", Input).value = f"{default:g}"
             self.app.notify("Reset to defaults (not saved)", severity="information")
@@ -0,0 +1,214 @@
+"""Server settings sync screen for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal
+from textual.screen import Screen
+from textual.widgets import Button, Checkbox, DataTable, Input, Label, Select, Static
+
+from apps.core.config import config_manager
+from apps.geoserver.settings_sync import (
+    SECTIONS,
+    SettingsSyncPlan,
+    apply_settings_sync,
+    plan_settings_sync,
+)
+
+from .confirm import ConfirmScreen
+
+
+class SettingsSyncScreen(Screen):
+    """Screen copying contact details and service metadata between servers.
+
+    The fields that differ from the source's are listed first; only
+    those are written when the listed changes are applied.
+    """
+
+    DEFAULT_CSS = """
+    SettingsSyncScreen {
+        layout: vertical;
+    }
+
+    .screen-header {
+        height: 3;
+        padding: 1;
+        background: $primary;
+    }
+
+    .form-row {
+        height: auto;
+        margin: 0 1;
+    }
+
+    .form-label {
+        width: 12;
+        padding-top: 1;
+    }
+
+    .changes-table {
+        height: 1fr;
+        margin: 1;
+    }
+
+    .sync-status {
+        height: auto;
+        max-height: 6;
+        padding: 0 1;
+    }
+
+    .action-bar {
+        height: 3;
+        padding: 0 1;
+        background: $surface;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "app.pop_screen", "Back"),
+        ("x", "preview", "Preview"),
+        ("a", "apply", "Apply"),
+    ]
+
+    def __init__(self) -> None:
+        """Initialize the settings sync screen."""
+        super().__init__()
+        self._plan: SettingsSyncPlan | None = None
+
+    def compose(self) -> ComposeResult:
+        """Create the settings sync screen layout."""
+        yield Static("Sync Server Settings", classes="screen-header")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Source:", classes="form-label")
+            yield Select(
+                [(conn.name, conn.id) for conn in config_manager.list_connections()],
+                id="select-source",
+                prompt="Connection to copy from...",
+            )
+
+        with Horizontal(classes="form-row"):
+            yield Label("Targets:", classes="form-label")
+            yield Input(
+                placeholder="Names or IDs, comma-separated; every other connection when empty",
+                id="input-targets",
+            )
+
+        with Horizontal(classes="form-row"):
+            yield Label("Sections:", classes="form-label")
+            for section in SECTIONS:
+                yield Checkbox(section, value=True, id=f"check-{section}")
+
+        table = DataTable(id="changes-table", classes="changes-table", cursor_type="row")
+        table.add_columns("Connection", "Section", "Field", "Current", "New Value")
+        yield table
+
+        yield Static(
+            "Press x to list the fields that would change", id="status", classes="sync-status"
+        )
+
+        with Horizontal(classes="action-bar"):
+            yield Button("Preview", id="btn-preview", variant="primary")
+            yield Button("Apply", id="btn-apply", variant="warning")
+
+    def _connection_ids(self, refs: str) -> list[str]:
+        """Connection IDs for comma-separated names or IDs; unknown ones kept as given."""
+        by_name = {conn.name: conn.id for conn in config_manager.list_connections()}
+        return [by_name.get(ref, ref) for ref in (r.strip() for r in refs.split(",")) if ref]
+
+    def action_preview(self) -> None:
+        """List what copying the source's settings would change on the targets."""
+        source = self.query_one("#select-source", Select).value
+        if source == Select.BLANK:
+            self.app.notify("Choose the connection to copy from", severity="error")
+            return
+        source = str(source)
+        targets = self._connection_ids(self.query_one("#input-targets", Input).value)
+        if not targets:
+            targets = [c.id for c in config_manager.list_connections() if c.id != source]
+        sections = [s for s in SECTIONS if self.query_one(f"#check-{s}", Checkbox).value]
+        if not targets or not sections:
+            self.app.notify("Choose at least one target and one section", severity="error")
+            return
+
+        self._plan = None
+        self.query_one("#status", Static).update("Reading server settings...")
+        self.run_worker(lambda: self._preview(source, targets, sections), thread=True)
+
+    def _preview(self, source: str, targets: list[str], sections: list[str]) -> None:
+        """Read the servers off the UI thread."""
+        try:
+            plan = plan_settings_sync(source, targets, sections)
+        except Exception as e:
+            self.app.call_from_thread(
+                self.app.notify, f"Can't read the settings: {e}", severity="error"
+            )
+            return
+        self.app.call_from_thread(self._show_plan, plan, False)
+
+    def _show_plan(self, plan: SettingsSyncPlan, applied: bool) -> None:
+        """List a plan's changes, with the sections that couldn't be read or written."""
+        self._plan = None if applied else plan
+        table = self.query_one("#changes-table", DataTable)
+        table.clear()
+        for server in plan.servers:
+            for change in server.changes:
+                row = change.to_dict()
+                table.add_row(
+                    server.name, row["section"], row["key"], row["current"] or "-", row["value"]
+                )
+
+        verb = "changed" if applied else "would change"
+        lines = [f"{plan.change_count} field(s) {verb} on {len(plan.servers)} server(s)"]
+        if plan.source_errors:
+            lines.append(f"Source: couldn't read {', '.join(plan.source_errors)}")
+        for server in plan.servers:
+            for section, error in server.errors.items():
+                lines.append(f"{server.name}: {section}: {error}")
+        if not applied and plan.change_count:
+            lines[0] += " - press a to apply"
+        self.query_one("#status", Static).update("\n".join(lines))
+
+    def action_apply(self) -> None:
+        """Write the previewed changes after asking."""
+        plan = self._plan
+        if plan is None or not plan.change_count:
+            self.app.notify("Preview the changes first", severity="warning")
+            return
+
+        def confirmed(ok: bool | None) -> None:
+            if ok:
+                self.query_one("#status", Static).update("Writing server settings...")
+                self.run_worker(lambda: self._apply(plan), thread=True)
+
+        names = ", ".join(s.name for s in plan.servers if s.changes)
+        self.app.push_screen(
+            ConfirmScreen(
+                "Apply server settings",
+                f"{plan.change_count} field(s) will be written to {names}.",
+                confirm_label="Apply",
+            ),
+            confirmed,
+        )
+
+    def _apply(self, plan: SettingsSyncPlan) -> None:
+        """Write a plan off the UI thread."""
+        try:
+            apply_settings_sync(plan)
+        except Exception as e:
+            self.app.call_from_thread(self.app.notify, f"Sync failed: {e}", severity="error")
+            return
+        failed = any(server.errors for server in plan.servers)
+        self.app.call_from_thread(
+            self.app.notify,
+            "Some sections weren't written" if failed else "Server settings synced",
+            severity="warning" if failed else "information",
+        )
+        self.app.call_from_thread(self._show_plan, plan, True)
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        button_id = event.button.id
+
+        if button_id == "btn-preview":
+            self.action_preview()
+        elif button_id == "btn-apply":
+            self.action_apply()
@@ -0,0 +1,369 @@
+"""Scheduled catalog snapshots screen for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Container, Horizontal
+from textual.screen import Screen
+from textual.widgets import Button, Checkbox, DataTable, Input, Label, Select, Static
+
+from apps.core.config import SnapshotSchedule, config_manager
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.snapshot import restore_snapshot
+from apps.geoserver.snapshot_schedule import (
+    SnapshotArchive,
+    archive_store,
+    get_snapshot_scheduler,
+    list_snapshots,
+    next_run_time,
+    validate_snapshot_schedule,
+)
+
+from ..styles import check_label
+from .picker import PickerScreen
+
+
+class SnapshotScheduleForm(Container):
+    """Form for adding a snapshot schedule."""
+
+    DEFAULT_CSS = """
+    SnapshotScheduleForm {
+        layout: vertical;
+        padding: 1;
+        background: $surface;
+        border: solid $primary;
+        height: auto;
+    }
+
+    SnapshotScheduleForm .form-row {
+        height: auto;
+        margin: 0 0 1 0;
+    }
+
+    SnapshotScheduleForm .form-label {
+        width: 15;
+    }
+
+    SnapshotScheduleForm .buttons {
+        height: 3;
+        margin-top: 1;
+    }
+    """
+
+    def compose(self) -> ComposeResult:
+        """Create form content."""
+        with Horizontal(classes="form-row"):
+            yield Label("Name:", classes="form-label")
+            yield Input(placeholder="Nightly catalog", id="input-name")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Connections:", classes="form-label")
+            yield Input(placeholder="Names or IDs, comma-separated", id="input-connections")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Cron:", classes="form-label")
+            yield Input(value="0 2 * * *", placeholder="0 2 * * *", id="input-cron")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Destination:", classes="form-label")
+            yield Select(
+                [("Local folder", "local"), ("S3 bucket", "s3")],
+                value="local",
+                id="select-destination",
+                allow_blank=False,
+            )
+
+        with Horizontal(classes="form-row"):
+            yield Label("Folder:", classes="form-label")
+            yield Input(placeholder="/var/backups/geoserver (local)", id="input-directory")
+
+        with Horizontal(classes="form-row"):
+            yield Label("S3:", classes="form-label")
+            yield Select([], id="select-s3", prompt="S3 connection (s3)...")
+            yield Input(placeholder="bucket", id="input-bucket")
+            yield Input(placeholder="prefix (optional)", id="input-prefix")
+
+        with Horizontal(classes="form-row"):
+            yield Label("Keep:", classes="form-label")
+            yield Input(value="7", placeholder="dailies", id="input-keep-daily")
+            yield Input(value="4", placeholder="weeklies", id="input-keep-weekly")
+
+        with Horizontal(classes="form-row"):
+            yield Label("", classes="form-label")
+            yield Checkbox("Include layer data", id="check-include-data")
+
+        with Horizontal(classes="buttons"):
+            yield Button("Save", id="btn-save", variant="primary")
+            yield Button("Cancel", id="btn-cancel")
+
+
+class SnapshotSchedulesScreen(Screen):
+    """Screen for managing scheduled catalog snapshots and restoring them."""
+
+    DEFAULT_CSS = """
+    SnapshotSchedulesScreen {
+        layout: vertical;
+    }
+
+    .screen-header {
+        height: 3;
+        padding: 1;
+        background: $primary;
+    }
+
+    .schedules-table {
+        height: 1fr;
+        margin: 1;
+    }
+
+    .action-bar {
+        height: 3;
+        padding: 0 1;
+        background: $surface;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "app.pop_screen", "Back"),
+        ("a", "add_schedule", "Add"),
+        ("d", "delete_schedule", "Delete"),
+        ("e", "toggle_schedule", "Enable/Disable"),
+        ("x", "run_schedule", "Run Now"),
+        ("o", "restore_snapshot", "Restore"),
+    ]
+
+    def compose(self) -> ComposeResult:
+        """Create the snapshot schedules screen layout."""
+        yield Static("Scheduled Catalog Snapshots", classes="screen-header")
+
+        table = DataTable(id="schedules-table", classes="schedules-table", cursor_type="row")
+        table.add_columns(
+            "Name", "Connections", "Destination", "Cron", "Keep", "Next Run", "Last Run", "Enabled"
+        )
+        yield table
+
+        with Horizontal(classes="action-bar"):
+            yield Button("Add", id="btn-add", variant="primary")
+            yield Button("Run Now", id="btn-run")
+            yield Button("Restore", id="btn-restore")
+            yield Button("Enable/Disable", id="btn-toggle")
+            yield Button("Delete", id="btn-delete", variant="error")
+
+        yield SnapshotScheduleForm(id="schedule-form", classes="hidden")
+
+    def on_mount(self) -> None:
+        """Load schedules when screen mounts."""
+        select = self.query_one("#select-s3", Select)
+        select.set_options([(conn.name, conn.id) for conn in config_manager.list_s3_connections()])
+        self._refresh_table()
+
+    def _connection_name(self, conn_id: str) -> str:
+        conn = config_manager.get_connection(conn_id)
+        return conn.name if conn else conn_id
+
+    def _refresh_table(self) -> None:
+        """Refresh the schedules table."""
+        table = self.query_one("#schedules-table", DataTable)
+        table.clear()
+
+        for schedule in config_manager.list_snapshot_schedules():
+            if schedule.destination == "s3":
+                destination = f"s3://{schedule.bucket}/{schedule.prefix}".rstrip("/")
+            else:
+                destination = schedule.directory
+            next_run = next_run_time(schedule)
+            last_run = "-"
+            if schedule.last_run_at:
+                last_run = f"{schedule.last_run_at[:16].replace('T', ' ')} ({schedule.last_status})"
+            table.add_row(
+                schedule.name,
+                ", ".join(self._connection_name(c) for c in schedule.connection_ids),
+                destination,
+                schedule.cron,
+                f"{schedule.keep_daily}d / {schedule.keep_weekly}w",
+                next_run[:16].replace("T", " ") if next_run else "-",
+                last_run,
+                check_label(schedule.enabled),
+                key=schedule.id,
+            )
+
+    def _selected_schedule(self) -> SnapshotSchedule | None:
+        """Get the schedule under the table cursor."""
+        table = self.query_one("#schedules-table", DataTable)
+        if table.row_count == 0:
+            self.app.notify("No schedule selected", severity="warning")
+            return None
+
+        row_key, _ = table.coordinate_to_cell_key(table.cursor_coordinate)
+        return config_manager.get_snapshot_schedule(str(row_key.value))
+
+    def action_add_schedule(self) -> None:
+        """Show the add schedule form."""
+        self.query_one("#schedule-form").remove_class("hidden")
+
+    def action_delete_schedule(self) -> None:
+        """Delete the selected schedule; its stored snapshots are kept."""
+        schedule = self._selected_schedule()
+        if schedule:
+            config_manager.remove_snapshot_schedule(schedule.id)
+            self.app.notify(f"Schedule '{schedule.name}' deleted", severity="information")
+            self._refresh_table()
+
+    def action_toggle_schedule(self) -> None:
+        """Enable or disable the selected schedule."""
+        schedule = self._selected_schedule()
+        if schedule:
+            schedule.enabled = not schedule.enabled
+            config_manager.update_snapshot_schedule(schedule)
+            state = "enabled" if schedule.enabled else "disabled"
+            self.app.notify(f"Schedule '{schedule.name}' {state}", severity="information")
+            self._refresh_table()
+
+    def action_run_schedule(self) -> None:
+        """Snapshot the selected schedule's connections now."""
+        schedule = self._selected_schedule()
+        if schedule:
+            self.app.notify(f"Running schedule '{schedule.name}'...")
+            self.run_worker(lambda: self._run_schedule(schedule.id), thread=True)
+
+    def _run_schedule(self, schedule_id: str) -> None:
+        """Run a schedule off the UI thread."""
+        result = get_snapshot_scheduler().run_schedule(schedule_id)
+        if result and result.last_status == "failed":
+            self.app.call_from_thread(
+                self.app.notify, f"Snapshot failed: {result.last_error}", severity="error"
+            )
+        elif result:
+            self.app.call_from_thread(self.app.notify, f"Schedule '{result.name}' stored")
+        self.app.call_from_thread(self._refresh_table)
+
+    def action_restore_snapshot(self) -> None:
+        """Pick one of the selected schedule's snapshots to restore."""
+        schedule = self._selected_schedule()
+        if schedule:
+            self.run_worker(lambda: self._list_snapshots(schedule), thread=True)
+
+    def _list_snapshots(self, schedule: SnapshotSchedule) -> None:
+        """List the stored snapshots off the UI thread, then offer them."""
+        try:
+            archives = list_snapshots(schedule)
+        except Exception as e:
+            self.app.call_from_thread(
+                self.app.notify, f"Can't list snapshots: {e}", severity="error"
+            )
+            return
+        if not archives:
+            self.app.call_from_thread(
+                self.app.notify, "No snapshots stored yet", severity="warning"
+            )
+            return
+        self.app.call_from_thread(self._pick_snapshot, schedule, archives)
+
+    def _pick_snapshot(self, schedule: SnapshotSchedule, archives: list[SnapshotArchive]) -> None:
+        """Offer the snapshots, newest first, then the connection to restore onto."""
+        by_name = {archive.name: archive for archive in archives}
+
+        def snapshot_chosen(name: str | None) -> None:
+            if name:
+                self._pick_target(schedule, by_name[name])
+
+        options = [
+            (
+                f"{a.created:%Y-%m-%d %H:%M}  {self._connection_name(a.connection_id)}"
+                f"  ({a.size // 1024} KB)",
+                a.name,
+            )
+            for a in archives
+        ]
+        self.app.push_screen(PickerScreen("Restore snapshot", options), snapshot_chosen)
+
+    def _pick_target(self, schedule: SnapshotSchedule, archive: SnapshotArchive) -> None:
+        """Offer the connections to restore a snapshot onto, its own first."""
+
+        def target_chosen(conn_id: str | None) -> None:
+            if conn_id:
+                self.app.notify(f"Restoring {archive.name}...")
+                self.run_worker(
+                    lambda: self._restore(schedule, archive, conn_id), thread=True
+                )
+
+        connections = config_manager.list_connections()
+        options = [
+            (conn.name, conn.id)
+            for conn in sorted(connections, key=lambda c: c.id != archive.connection_id)
+        ]
+        self.app.push_screen(PickerScreen("Restore onto", options), target_chosen)
+
+    def _restore(self, schedule: SnapshotSchedule, archive: SnapshotArchive, conn_id: str) -> None:
+        """Recreate the resources a connection is missing, off the UI thread."""
+        try:
+            client = get_geoserver_client(conn_id)
+            report = restore_snapshot(client, archive_store(schedule).read(archive.name))
+        except Exception as e:
+            self.app.call_from_thread(self.app.notify, f"Restore failed: {e}", severity="error")
+            return
+        message = (
+            f"Restored {archive.name}: {len(report.created)} created, "
+            f"{len(report.existing)} already present, {len(report.failed)} failed"
+        )
+        self.app.call_from_thread(
+            self.app.notify, message, severity="warning" if report.failed else "information"
+        )
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        button_id = event.button.id
+
+        if button_id == "btn-add":
+            self.action_add_schedule()
+        elif button_id == "btn-run":
+            self.action_run_schedule()
+        elif button_id == "btn-restore":
+            self.action_restore_snapshot()
+        elif button_id == "btn-toggle":
+            self.action_toggle_schedule()
+        elif button_id == "btn-delete":
+            self.action_delete_schedule()
+        elif button_id == "btn-save":
+            self._save_schedule()
+        elif button_id == "btn-cancel":
+            self.query_one("#schedule-form").add_class("hidden")
+
+    def _connection_ids(self, refs: str) -> list[str]:
+        """Connection IDs for comma-separated names or IDs; unknown ones kept as given."""
+        by_name = {conn.name: conn.id for conn in config_manager.list_connections()}
+        return [by_name.get(ref, ref) for ref in (r.strip() for r in refs.split(",")) if ref]
+
+    def _save_schedule(self) -> None:
+        """Save a schedule from form values."""
+        name = self.query_one("#input-name", Input).value.strip()
+        if not name:
+            self.app.notify("Please fill in a name", severity="error")
+            return
+
+        s3_conn = self.query_one("#select-s3", Select).value
+        try:
+            schedule = SnapshotSchedule(
+                name=name,
+                connection_ids=self._connection_ids(
+                    self.query_one("#input-connections", Input).value
+                ),
+                cron=self.query_one("#input-cron", Input).value.strip(),
+                destination=str(self.query_one("#select-destination", Select).value),
+                directory=self.query_one("#input-directory", Input).value.strip(),
+                s3_connection_id="" if s3_conn == Select.BLANK else str(s3_conn),
+                bucket=self.query_one("#input-bucket", Input).value.strip(),
+                prefix=self.query_one("#input-prefix", Input).value.strip(),
+                include_data=self.query_one("#check-include-data", Checkbox).value,
+                keep_daily=int(self.query_one("#input-keep-daily", Input).value),
+                keep_weekly=int(self.query_one("#input-keep-weekly", Input).value),
+            )
+            validate_snapshot_schedule(schedule)
+        except ValueError as e:
+            self.app.notify(f"Invalid schedule: {e}", severity="error")
+            return
+
+        config_manager.add_snapshot_schedule(schedule)
+        self.app.notify(f"Schedule '{name}' saved", severity="information")
+        self.query_one("#input-name", Input).value = ""
+        self.query_one("#input-connections", Input).value = ""
+        self.query_one("#schedule-form").add_class("hidden")
+        self._refresh_table()
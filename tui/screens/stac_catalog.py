@@ -0,0 +1,190 @@
+"""STAC catalog dialog for Kartoza CloudBench TUI."""
+
+from typing import Any
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, DataTable, Input, Static
+
+from apps.s3.client import get_s3_client
+from apps.s3.stac import CATALOG_FILE, ITEMS_DIR, STACCatalogGenerator
+
+
+class StacCatalogScreen(ModalScreen[None]):
+    """Dialog generating a static STAC catalog for a bucket prefix."""
+
+    DEFAULT_CSS = """
+    StacCatalogScreen {
+        align: center middle;
+    }
+
+    .stac-dialog {
+        width: 90%;
+        height: 85%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .stac-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .stac-row {
+        height: auto;
+    }
+
+    .stac-row Input {
+        width: 1fr;
+    }
+
+    .stac-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .stac-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, bucket: str = "") -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: S3 connection ID
+            bucket: Bucket to fill in
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.bucket = bucket
+        self._running = False
+        # Generator and documents of the last preview, written by Write
+        self._generated: tuple[STACCatalogGenerator, dict[str, dict[str, Any]]] | None = None
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="stac-dialog"):
+            yield Static("STAC Catalog", classes="stac-title")
+            with Horizontal(classes="stac-row"):
+                yield Input(value=self.bucket, placeholder="Bucket", id="input-bucket")
+                yield Input(placeholder="Prefix, e.g. imagery/", id="input-prefix")
+                yield Input(placeholder="Title (catalog ID)", id="input-title")
+            table = DataTable(id="stac-table", classes="stac-table")
+            table.add_columns("Document", "Type", "Datetime", "Bounding box")
+            yield table
+            yield Static(
+                "Walks the prefix for Cloud Optimized GeoTIFF and GeoParquet objects.",
+                id="stac-summary",
+                classes="stac-summary",
+            )
+            with Horizontal(classes="stac-row"):
+                yield Button("Preview", id="btn-preview", variant="primary")
+                yield Button("Write to Bucket", id="btn-write")
+                yield Button("Close", id="btn-close")
+
+    def _generate(self) -> None:
+        """Walk the bucket prefix and build the catalog in a background thread."""
+        if self._running:
+            return
+        bucket = self.query_one("#input-bucket", Input).value.strip()
+        if not bucket:
+            self.app.notify("Enter a bucket", severity="warning")
+            return
+        prefix = self.query_one("#input-prefix", Input).value.strip()
+        title = self.query_one("#input-title", Input).value.strip() or None
+
+        self._running = True
+        self._generated = None
+        self.query_one("#stac-table", DataTable).clear()
+        self.query_one("#stac-summary", Static).update(f"Reading s3://{bucket}/{prefix}...")
+
+        def generate() -> None:
+            try:
+                generator = STACCatalogGenerator(
+                    get_s3_client(self.conn_id), bucket, prefix=prefix, title=title
+                )
+                documents = generator.generate()
+            except Exception as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._show, generator, documents)
+
+        self.run_worker(generate, thread=True)
+
+    def _show(
+        self, generator: STACCatalogGenerator, documents: dict[str, dict[str, Any]]
+    ) -> None:
+        """List the catalog's documents."""
+        self._running = False
+        self._generated = (generator, documents)
+        table = self.query_one("#stac-table", DataTable)
+        for path, document in documents.items():
+            bbox = document.get("bbox") or []
+            table.add_row(
+                path,
+                document.get("type", ""),
+                (document.get("properties") or {}).get("datetime") or "",
+                ", ".join(f"{value:.4f}" for value in bbox),
+            )
+        items = sum(1 for path in documents if path.startswith(ITEMS_DIR))
+        self.query_one("#stac-summary", Static).update(
+            f"{items} item(s) for s3://{generator.bucket}/{generator.prefix}{CATALOG_FILE}; "
+            "Write to Bucket saves them."
+        )
+
+    def _write(self) -> None:
+        """Write the previewed catalog into the bucket."""
+        if self._running:
+            return
+        if self._generated is None:
+            self.app.notify("Preview the catalog first", severity="warning")
+            return
+        generator, documents = self._generated
+        self._running = True
+        self.query_one("#stac-summary", Static).update("Writing the catalog...")
+
+        def write() -> None:
+            try:
+                written = generator.write(documents)
+            except Exception as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._on_written, generator, written)
+
+        self.run_worker(write, thread=True)
+
+    def _on_written(self, generator: STACCatalogGenerator, written: list[str]) -> None:
+        """Report the written catalog."""
+        self._running = False
+        url = f"s3://{generator.bucket}/{generator.prefix}{CATALOG_FILE}"
+        self.query_one("#stac-summary", Static).update(
+            f"[green]Wrote {len(written)} document(s); the catalog is {url}[/]"
+        )
+        self.app.notify(f"STAC catalog written to {url}", severity="information")
+
+    def _on_failed(self, error: str) -> None:
+        """Report a failed run."""
+        self._running = False
+        self.query_one("#stac-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-preview":
+            self._generate()
+        elif event.button.id == "btn-write":
+            self._write()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless a run is going."""
+        if self._running:
+            self.app.notify("Wait for the catalog to finish", severity="warning")
+            return
+        self.dismiss(None)
@@ -0,0 +1,248 @@
+"""Style graphics dialog for Kartoza CloudBench TUI."""
+
+from pathlib import Path
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, Checkbox, DataTable, Input, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.style_assets import (
+    AssetListing,
+    AssetMove,
+    list_assets,
+    move_asset,
+    styles_folder,
+    upload_asset,
+)
+
+
+class StyleAssetsScreen(ModalScreen[None]):
+    """Dialog listing, uploading and moving the icons kept next to styles."""
+
+    DEFAULT_CSS = """
+    StyleAssetsScreen {
+        align: center middle;
+    }
+
+    .assets-dialog {
+        width: 90%;
+        height: 80%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .assets-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .assets-row {
+        height: auto;
+    }
+
+    .assets-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .assets-summary {
+        height: auto;
+        max-height: 8;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, workspace: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace whose styles folder is shown unless global styles are chosen
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self._busy = False
+
+    @property
+    def _target_workspace(self) -> str | None:
+        """Get the workspace shown, None for the global styles."""
+        return None if self.query_one("#check-global", Checkbox).value else self.workspace
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="assets-dialog"):
+            yield Static(
+                f"Graphics in {styles_folder(self.workspace)}",
+                id="assets-title",
+                classes="assets-title",
+            )
+            with Horizontal(classes="assets-row"):
+                yield Checkbox("Global styles", id="check-global")
+                yield Button("Refresh", id="btn-refresh")
+            table = DataTable(id="assets-table", classes="assets-table", cursor_type="row")
+            table.add_columns("Graphic", "Size", "Used by")
+            yield table
+            with Horizontal(classes="assets-row"):
+                yield Input(placeholder="/path/to/icon.png or .svg", id="input-upload")
+                yield Input(value="icons", placeholder="Subfolder", id="input-folder")
+                yield Button("Upload", id="btn-upload")
+            with Horizontal(classes="assets-row"):
+                yield Input(
+                    placeholder="New path of the selected graphic, e.g. icons/pin.png",
+                    id="input-target",
+                )
+                yield Button("Move", id="btn-move", variant="primary")
+            yield Static("", id="assets-summary", classes="assets-summary")
+            with Horizontal(classes="assets-row"):
+                yield Button("Close", id="btn-close")
+
+    def on_mount(self) -> None:
+        """List the graphics."""
+        self._load()
+
+    def on_checkbox_changed(self, event: Checkbox.Changed) -> None:
+        """Switch between the workspace and the global styles folder."""
+        self.query_one("#assets-title", Static).update(
+            f"Graphics in {styles_folder(self._target_workspace)}"
+        )
+        self._load()
+
+    def on_data_table_row_highlighted(self, event: DataTable.RowHighlighted) -> None:
+        """Offer the highlighted graphic's path as the starting point of a move."""
+        if event.row_key.value:
+            self.query_one("#input-target", Input).value = event.row_key.value
+
+    def _run(self, message: str, work) -> None:
+        """Run a server call in a background thread, one at a time."""
+        if self._busy:
+            return
+        self._busy = True
+        self.query_one("#assets-summary", Static).update(message)
+
+        def run() -> None:
+            try:
+                work()
+            except (GeoServerError, ValueError, OSError) as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+
+        self.run_worker(run, thread=True)
+
+    def _load(self) -> None:
+        """List the graphics of the styles folder in a background thread."""
+        workspace = self._target_workspace
+
+        def load() -> None:
+            listing = list_assets(get_geoserver_client(self.conn_id), workspace)
+            self.app.call_from_thread(self._show_listing, listing)
+
+        self._run("Reading the styles folder...", load)
+
+    def _show_listing(self, listing: AssetListing) -> None:
+        """Fill the table and list the references that won't travel."""
+        self._busy = False
+        table = self.query_one("#assets-table", DataTable)
+        table.clear()
+        for asset in listing.assets:
+            used_by = ", ".join(asset.styles) or "unused"
+            table.add_row(asset.path, f"{asset.size:,} B", used_by, key=asset.path)
+
+        lines = [f"{len(listing.assets)} graphic(s)"]
+        if not listing.complete:
+            lines[0] += " (folder too large to list completely)"
+        for ref in listing.unresolved:
+            reason = "absolute path" if ref.reason == "absolute" else "not in the folder"
+            lines.append(f"[yellow]{ref.style}: {ref.href} ({reason})[/]")
+        self.query_one("#assets-summary", Static).update("\n".join(lines))
+
+    def _upload(self) -> None:
+        """Upload the local PNG or SVG file into the styles folder."""
+        source = Path(self.query_one("#input-upload", Input).value.strip()).expanduser()
+        if not source.is_file():
+            self.app.notify("Enter the path of a PNG or SVG file", severity="error")
+            return
+        folder = self.query_one("#input-folder", Input).value.strip().strip("/")
+        path = f"{folder}/{source.name}" if folder else source.name
+        workspace = self._target_workspace
+
+        def upload() -> None:
+            uploaded = upload_asset(
+                get_geoserver_client(self.conn_id), path, source.read_bytes(), workspace
+            )
+            self.app.call_from_thread(self._on_uploaded, uploaded)
+
+        self._run(f"Uploading {path}...", upload)
+
+    def _on_uploaded(self, path: str) -> None:
+        """Report the upload and list the folder again."""
+        self._busy = False
+        self.app.notify(f"Uploaded {path}")
+        self.query_one("#input-upload", Input).value = ""
+        self._load()
+
+    def _move(self) -> None:
+        """Move the selected graphic, updating the styles that use it."""
+        table = self.query_one("#assets-table", DataTable)
+        if not table.row_count:
+            return
+        source = table.coordinate_to_cell_key(table.cursor_coordinate).row_key.value
+        target = self.query_one("#input-target", Input).value.strip()
+        if not source or not target or target == source:
+            self.app.notify("Enter a new path for the selected graphic", severity="warning")
+            return
+        workspace = self._target_workspace
+
+        def move() -> None:
+            result = move_asset(get_geoserver_client(self.conn_id), source, target, workspace)
+            self.app.call_from_thread(self._on_moved, result)
+
+        self._run(f"Moving {source} to {target}...", move)
+
+    def _on_moved(self, move: AssetMove) -> None:
+        """Report the styles updated to the new path and list the folder again."""
+        self._busy = False
+        message = f"Moved {move.source} to {move.target}"
+        if move.updated:
+            message += f"; updated {', '.join(move.updated)}"
+        if move.failed:
+            failed = ", ".join(f"{style} ({error})" for style, error in move.failed.items())
+            self.app.notify(f"{message}; not updated: {failed}", severity="warning")
+        else:
+            self.app.notify(message)
+        self._load()
+
+    def _on_failed(self, error: str) -> None:
+        """Report a failed call."""
+        self._busy = False
+        self.query_one("#assets-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_input_submitted(self, event: Input.Submitted) -> None:
+        """Upload or move when Enter is pressed in their inputs."""
+        if event.input.id in ("input-upload", "input-folder"):
+            self._upload()
+        elif event.input.id == "input-target":
+            self._move()
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-refresh":
+            self._load()
+        elif event.button.id == "btn-upload":
+            self._upload()
+        elif event.button.id == "btn-move":
+            self._move()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless a call is running."""
+        if self._busy:
+            self.app.notify("Wait for the current operation to finish", severity="warning")
+            return
+        self.dismiss(None)
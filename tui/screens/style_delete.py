@@ -0,0 +1,191 @@
+"""Style delete dialog for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, Checkbox, DataTable, Select, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.style_delete import StyleDeletion, delete_style
+from apps.gwc.invalidation import find_style_layers
+
+
+class StyleDeleteScreen(ModalScreen[bool]):
+    """Dialog listing the layers using a style before deleting it.
+
+    The layers can be given another style in the same role; otherwise
+    GeoServer falls back to its default style for them. Dismissed with
+    True once the style is deleted.
+    """
+
+    DEFAULT_CSS = """
+    StyleDeleteScreen {
+        align: center middle;
+    }
+
+    .style-delete-dialog {
+        width: 80%;
+        height: 75%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $error;
+    }
+
+    .style-delete-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .style-delete-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .style-delete-row {
+        height: auto;
+    }
+
+    .style-delete-row Select {
+        width: 1fr;
+    }
+
+    .style-delete-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, workspace: str, style: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace of the style
+            style: Style name
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self.style = style
+        self._deleting = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="style-delete-dialog"):
+            yield Static(
+                f"Delete style {self.workspace}:{self.style}", classes="style-delete-title"
+            )
+            table = DataTable(id="style-delete-table", classes="style-delete-table")
+            table.add_columns("Layer", "Uses the style as")
+            yield table
+            yield Static(
+                "Finding the layers using the style...",
+                id="style-delete-summary",
+                classes="style-delete-summary",
+            )
+            with Horizontal(classes="style-delete-row"):
+                yield Select(
+                    [],
+                    prompt="Reassign the layers to... (GeoServer's default)",
+                    id="select-replacement",
+                )
+                yield Checkbox("Purge the style file", id="check-purge")
+            with Horizontal(classes="style-delete-row"):
+                yield Button("Delete", id="btn-delete", variant="error", disabled=True)
+                yield Button("Cancel", id="btn-close")
+
+    def on_mount(self) -> None:
+        """Find the layers and the other styles in a background thread."""
+        self.run_worker(self._load, thread=True)
+
+    def _load(self) -> None:
+        """Read the layers using the style and the styles to reassign them to."""
+        try:
+            affected = find_style_layers(self.conn_id, self.workspace, self.style)
+            client = get_geoserver_client(self.conn_id)
+            styles = [
+                f"{self.workspace}:{style['name']}"
+                for style in client.list_styles(self.workspace)
+                if style.get("name") != self.style
+            ]
+            styles += [style["name"] for style in client.list_styles() if style.get("name")]
+        except (GeoServerError, ValueError) as e:
+            self.app.call_from_thread(self._on_failed, str(e))
+            return
+        self.app.call_from_thread(self._show, affected, styles)
+
+    def _show(self, affected: list[dict[str, str]], styles: list[str]) -> None:
+        """List the layers and offer the styles."""
+        table = self.query_one("#style-delete-table", DataTable)
+        for item in affected:
+            table.add_row(item["layer"], item["usage"])
+        self.query_one("#select-replacement", Select).set_options(
+            [(style, style) for style in styles]
+        )
+        if affected:
+            text = (
+                f"{len(affected)} layer(s) use this style. Pick a style to give them "
+                "instead, or leave it empty for GeoServer's default style."
+            )
+        else:
+            text = "No layer uses this style."
+        self.query_one("#style-delete-summary", Static).update(text)
+        self.query_one("#btn-delete", Button).disabled = False
+
+    def _delete(self) -> None:
+        """Reassign the layers and delete the style in a background thread."""
+        if self._deleting:
+            return
+        replacement = self.query_one("#select-replacement", Select).value
+        reassign_to = "" if replacement is Select.BLANK else str(replacement)
+        purge = self.query_one("#check-purge", Checkbox).value
+
+        self._deleting = True
+        self.query_one("#btn-delete", Button).disabled = True
+        self.query_one("#style-delete-summary", Static).update("Deleting the style...")
+
+        def delete() -> None:
+            try:
+                deletion = delete_style(
+                    self.conn_id, self.workspace, self.style, purge, reassign_to
+                )
+            except (GeoServerError, ValueError) as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._on_deleted, deletion)
+
+        self.run_worker(delete, thread=True)
+
+    def _on_deleted(self, deletion: StyleDeletion) -> None:
+        """Report the deletion and close."""
+        message = f"Style '{self.style}' deleted"
+        if deletion.reassigned:
+            message += f"; {len(deletion.reassigned)} layer(s) reassigned"
+        if deletion.truncated:
+            message += f", {len(deletion.truncated)} tile cache(s) truncated"
+        self.app.notify(message, severity="information")
+        self.dismiss(True)
+
+    def _on_failed(self, error: str) -> None:
+        """Report a failed lookup or delete; a failed delete can be tried again."""
+        if self._deleting:
+            self._deleting = False
+            self.query_one("#btn-delete", Button).disabled = False
+        self.query_one("#style-delete-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-delete":
+            self._delete()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless the style is being deleted."""
+        if self._deleting:
+            self.app.notify("Wait for the style to be deleted", severity="warning")
+            return
+        self.dismiss(False)
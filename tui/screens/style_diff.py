@@ -0,0 +1,215 @@
+"""Style diff dialog for Kartoza CloudBench TUI."""
+
+from collections.abc import Callable
+from typing import Any
+
+from rich.text import Text
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical, VerticalScroll
+from textual.screen import ModalScreen
+from textual.widgets import Button, Checkbox, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.style_diff import StyleDiff
+
+STATUS_STYLES = {"new": "green", "changed": "yellow", "unchanged": "dim"}
+
+
+def append_diff_lines(text: Text, lines: list[str]) -> None:
+    """Append the lines of a unified diff, colored by what they do."""
+    for line in lines:
+        if line.startswith(("---", "+++")):
+            style = "bold"
+        elif line.startswith("@@"):
+            style = "cyan"
+        elif line.startswith("+"):
+            style = "green"
+        elif line.startswith("-"):
+            style = "red"
+        else:
+            style = ""
+        text.append(line + "\n", style=style)
+
+
+def render_diffs(diffs: list[StyleDiff], show_unchanged: bool = False) -> Text:
+    """Color unified diffs of styles, one after another."""
+    text = Text()
+    shown = [d for d in diffs if show_unchanged or d.status != "unchanged"]
+    if not shown:
+        text.append("No style would change", style="dim")
+        return text
+    for diff in shown:
+        text.append(diff.name, style="bold")
+        text.append(f" {diff.status}", style=STATUS_STYLES[diff.status])
+        if diff.old_format and diff.old_format != diff.new_format:
+            text.append(f" ({diff.old_format} -> {diff.new_format})", style="magenta")
+        text.append(f"  +{diff.additions}", style="green")
+        text.append(f" -{diff.deletions}\n", style="red")
+        append_diff_lines(text, diff.lines)
+        text.append("\n")
+    return text
+
+
+class StyleDiffScreen(ModalScreen[None]):
+    """Dialog showing what replacing styles with other versions would change."""
+
+    DEFAULT_CSS = """
+    StyleDiffScreen {
+        align: center middle;
+    }
+
+    .diff-dialog {
+        width: 90%;
+        height: 85%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .diff-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .diff-body {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .diff-row {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "close", "Close"),
+        ("u", "toggle_unchanged", "Show Unchanged"),
+        ("p", "promote", "Promote Changed"),
+    ]
+
+    def __init__(
+        self,
+        title: str,
+        compare: Callable[[], list[StyleDiff]],
+        promote: Callable[[list[str]], dict[str, Any]] | None = None,
+    ) -> None:
+        """Initialize the dialog.
+
+        Args:
+            title: What is compared
+            compare: Computes the diffs; run in a background thread
+            promote: Pushes the named styles to the destination and returns
+                the sync results; without it the styles can only be compared
+        """
+        super().__init__()
+        self.diff_title = title
+        self._compare = compare
+        self._promote = promote
+        self._diffs: list[StyleDiff] = []
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="diff-dialog"):
+            yield Static(self.diff_title, classes="diff-title")
+            yield Static("Comparing...", id="diff-summary")
+            with VerticalScroll(classes="diff-body"):
+                yield Static(id="diff-text")
+            with Horizontal(classes="diff-row"):
+                yield Checkbox("Show unchanged styles", id="check-unchanged")
+                if self._promote:
+                    yield Button("Promote Changed", id="btn-promote", variant="primary")
+                yield Button("Close", id="btn-close")
+
+    def on_mount(self) -> None:
+        """Start comparing."""
+
+        def run() -> None:
+            try:
+                diffs = self._compare()
+            except GeoServerError as e:
+                self.app.call_from_thread(self._on_failed, e.message)
+                return
+            except Exception as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._show, diffs)
+
+        self.run_worker(run, thread=True)
+
+    def _on_failed(self, error: str) -> None:
+        """Report styles that couldn't be compared."""
+        self.query_one("#diff-summary", Static).update(f"[red]Comparison failed: {error}[/]")
+
+    def _show(self, diffs: list[StyleDiff]) -> None:
+        """Show the diffs and count the changes."""
+        self._diffs = diffs
+        changed = [d for d in diffs if d.status != "unchanged"]
+        self.query_one("#diff-summary", Static).update(
+            f"{len(changed)} of {len(diffs)} style(s) would change: "
+            f"[green]+{sum(d.additions for d in diffs)}[/] "
+            f"[red]-{sum(d.deletions for d in diffs)}[/]"
+        )
+        self._render()
+
+    def _render(self) -> None:
+        show_unchanged = self.query_one("#check-unchanged", Checkbox).value
+        self.query_one("#diff-text", Static).update(render_diffs(self._diffs, show_unchanged))
+
+    def on_checkbox_changed(self, event: Checkbox.Changed) -> None:
+        """Show or hide the styles that wouldn't change."""
+        self._render()
+
+    def action_toggle_unchanged(self) -> None:
+        """Flip the unchanged styles checkbox."""
+        checkbox = self.query_one("#check-unchanged", Checkbox)
+        checkbox.value = not checkbox.value
+
+    def check_action(self, action: str, parameters: tuple[object, ...]) -> bool | None:
+        """Only offer promoting when the dialog was given a destination to push to."""
+        if action == "promote":
+            return self._promote is not None
+        return True
+
+    def action_promote(self) -> None:
+        """Push the styles that differ to the destination, leaving the others alone."""
+        names = [d.name for d in self._diffs if d.status != "unchanged"]
+        if not self._promote or not names:
+            self.app.notify("No changed styles to promote", severity="warning")
+            return
+        promote = self._promote
+        self.query_one("#diff-summary", Static).update(f"Promoting {len(names)} style(s)...")
+
+        def run() -> None:
+            try:
+                results = promote(names)["styles"]
+            except Exception as e:
+                self.app.call_from_thread(
+                    self.app.notify, f"Promoting failed: {e}", severity="error"
+                )
+                return
+            self.app.call_from_thread(self._promoted, results)
+
+        self.run_worker(run, thread=True)
+
+    def _promoted(self, results: dict[str, Any]) -> None:
+        """Report what promoting created, updated and skipped."""
+        errors = results["errors"]
+        summary = (
+            f"Promoted: {results['created']} created, {results['updated']} updated, "
+            f"{results['skipped']} skipped, {len(errors)} failed"
+        )
+        for error in errors:
+            summary += f"\n{error['style']}: {error['error']}"
+        self.query_one("#diff-summary", Static).update(Text(summary))
+        self.app.notify(summary, severity="warning" if errors else "information")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-close":
+            self.action_close()
+        elif event.button.id == "btn-promote":
+            self.action_promote()
+
+    def action_close(self) -> None:
+        """Close the dialog."""
+        self.dismiss(None)
@@ -0,0 +1,255 @@
+"""Style folder and URL import dialog for Kartoza CloudBench TUI."""
+
+from pathlib import Path
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, Checkbox, DataTable, Input, Static
+
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.style_diff import StyleDiff, diff_local_style
+from apps.geoserver.style_import import (
+    StyleFile,
+    StyleImportResult,
+    count_results,
+    fetch_style,
+    import_styles,
+    is_style_url,
+    read_style_file,
+    scan_style_directory,
+)
+
+from .style_diff import StyleDiffScreen
+
+
+class StyleImportScreen(ModalScreen[None]):
+    """Dialog importing a local folder of .sld, .css and .zip files, or a style URL, as styles."""
+
+    DEFAULT_CSS = """
+    StyleImportScreen {
+        align: center middle;
+    }
+
+    .import-dialog {
+        width: 90%;
+        height: 80%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .import-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .import-row {
+        height: auto;
+    }
+
+    .import-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .import-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, workspace: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace to import into unless global styles are chosen
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self._files: list[StyleFile] = []
+        self._importing = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="import-dialog"):
+            yield Static(f"Import styles into {self.workspace}", classes="import-title")
+            with Horizontal(classes="import-row"):
+                yield Input(
+                    placeholder="/path/to/styles, /path/to/package.zip or https://...",
+                    id="input-directory",
+                )
+                yield Button("Scan", id="btn-scan")
+            with Horizontal(classes="import-row"):
+                yield Input(
+                    placeholder="Authorization header for private URLs (optional)",
+                    password=True,
+                    id="input-authorization",
+                )
+            with Horizontal(classes="import-row"):
+                yield Checkbox("Global styles", id="check-global")
+                yield Checkbox("Include subfolders", id="check-recursive")
+            table = DataTable(id="import-table", classes="import-table", cursor_type="row")
+            table.add_columns("File", "Style", "Status")
+            yield table
+            yield Static(
+                "Each file becomes a style named after it; unchanged styles are skipped. "
+                "A .zip is a style package of an SLD and its icons",
+                id="import-summary",
+                classes="import-summary",
+            )
+            with Horizontal(classes="import-row"):
+                yield Button("Review Changes", id="btn-review")
+                yield Button("Import", id="btn-import", variant="primary")
+                yield Button("Close", id="btn-close")
+
+    def on_mount(self) -> None:
+        """Start in the directory input."""
+        self.query_one("#input-directory", Input).focus()
+
+    def on_input_submitted(self, event: Input.Submitted) -> None:
+        """Scan when Enter is pressed in the directory or authorization input."""
+        self._scan()
+
+    def on_checkbox_changed(self, event: Checkbox.Changed) -> None:
+        """Rescan when subfolders are included or left out."""
+        if event.checkbox.id == "check-recursive" and self._files:
+            self._scan()
+
+    def _scan(self) -> None:
+        """List the style files of the directory, or download the style at the URL."""
+        source = self.query_one("#input-directory", Input).value.strip()
+        if not source:
+            self.app.notify("Please enter a directory or URL", severity="error")
+            return
+        if is_style_url(source):
+            authorization = self.query_one("#input-authorization", Input).value.strip()
+            self.query_one("#import-summary", Static).update(f"Downloading {source}...")
+
+            def fetch() -> None:
+                try:
+                    files = [fetch_style(source, authorization or None)]
+                except ValueError as e:
+                    self.app.call_from_thread(self._on_scan_failed, str(e))
+                    return
+                self.app.call_from_thread(self._show_files, files)
+
+            self.run_worker(fetch, thread=True)
+            return
+
+        recursive = self.query_one("#check-recursive", Checkbox).value
+        path = Path(source).expanduser()
+        try:
+            if path.is_file():
+                files = [read_style_file(path)]
+            else:
+                files = scan_style_directory(path, recursive)
+        except ValueError as e:
+            self._on_scan_failed(str(e))
+            return
+        self._show_files(files)
+
+    def _on_scan_failed(self, error: str) -> None:
+        """Report a folder or URL that couldn't be read."""
+        self.query_one("#import-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def _show_files(self, files: list[StyleFile]) -> None:
+        """List the style files to import."""
+        self._files = files
+        table = self.query_one("#import-table", DataTable)
+        table.clear()
+        for style_file in self._files:
+            status = "pending"
+            if style_file.missing_graphics:
+                status += f" (missing {', '.join(style_file.missing_graphics)})"
+            table.add_row(style_file.path, style_file.name, status, key=style_file.path)
+        self.query_one("#import-summary", Static).update(
+            f"Found {len(self._files)} style file(s)"
+        )
+
+    def _review(self) -> None:
+        """Show what importing the scanned files would change in the server's styles."""
+        if not self._files:
+            self.app.notify("Scan a directory first", severity="warning")
+            return
+        workspace = None if self.query_one("#check-global", Checkbox).value else self.workspace
+        files = list(self._files)
+
+        def compare() -> list[StyleDiff]:
+            client = get_geoserver_client(self.conn_id)
+            return [diff_local_style(client, f, workspace) for f in files]
+
+        self.app.push_screen(
+            StyleDiffScreen(f"Changes to the styles of {workspace or 'the server'}", compare)
+        )
+
+    def _import(self) -> None:
+        """Import the scanned files in a background thread."""
+        if self._importing:
+            return
+        if not self._files:
+            self.app.notify("Scan a directory first", severity="warning")
+            return
+        self._importing = True
+        workspace = None if self.query_one("#check-global", Checkbox).value else self.workspace
+        files = list(self._files)
+        self.query_one("#import-summary", Static).update(f"Importing {len(files)} style(s)...")
+
+        def run() -> None:
+            try:
+                results = import_styles(
+                    get_geoserver_client(self.conn_id),
+                    files,
+                    workspace,
+                    on_result=lambda r: self.app.call_from_thread(self._on_result, r),
+                )
+            except Exception as e:
+                self.app.call_from_thread(self._on_finished, [], str(e))
+                return
+            self.app.call_from_thread(self._on_finished, results, None)
+
+        self.run_worker(run, thread=True)
+
+    def _on_result(self, result: StyleImportResult) -> None:
+        """Show the outcome of one file."""
+        table = self.query_one("#import-table", DataTable)
+        detail = result.error or result.warning
+        status = f"{result.status}: {detail}" if detail else result.status
+        table.update_cell(result.path, table.ordered_columns[2].key, status)
+
+    def _on_finished(self, results: list[StyleImportResult], error: str | None) -> None:
+        """Summarize the import."""
+        self._importing = False
+        summary = self.query_one("#import-summary", Static)
+        if error:
+            summary.update(f"[red]Import failed: {error}[/]")
+            self.app.notify(f"Import failed: {error}", severity="error")
+            return
+        counts = count_results(results)
+        text = ", ".join(f"{n} {status}" for status, n in counts.items())
+        summary.update(text)
+        self.app.notify(
+            f"Styles: {text}", severity="warning" if counts["failed"] else "information"
+        )
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-scan":
+            self._scan()
+        elif event.button.id == "btn-review":
+            self._review()
+        elif event.button.id == "btn-import":
+            self._import()
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless an import is running."""
+        if self._importing:
+            self.app.notify("Wait for the import to finish", severity="warning")
+            return
+        self.dismiss(None)
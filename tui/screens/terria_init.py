@@ -0,0 +1,219 @@
+"""Terria init file dialog for Kartoza CloudBench TUI."""
+
+from pathlib import Path
+
+from botocore.exceptions import ClientError
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical, VerticalScroll
+from textual.screen import ModalScreen
+from textual.widgets import Button, Input, Select, Static
+
+from apps.core.config import config_manager
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.s3.client import get_s3_client
+from apps.s3.three_d import tileset_items
+from apps.terria.init_file import InitFile, generate_init, upload_init, write_init
+
+# Destination value writing to a local folder rather than an S3 connection
+LOCAL_FOLDER = "local"
+
+
+class TerriaInitScreen(ModalScreen[None]):
+    """Dialog writing a Terria init file cataloguing a connection's layers.
+
+    The file goes to a local folder, such as a TerriaMap's wwwroot/init,
+    or to a bucket of an S3 connection.
+    """
+
+    DEFAULT_CSS = """
+    TerriaInitScreen {
+        align: center middle;
+    }
+
+    .terria-dialog {
+        width: 90%;
+        height: 85%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .terria-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .terria-row {
+        height: auto;
+    }
+
+    .terria-row Input, .terria-row Select {
+        width: 1fr;
+    }
+
+    .terria-content {
+        height: 1fr;
+        margin: 1 0;
+        border: solid $primary-darken-2;
+    }
+
+    .terria-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str, workspace: str | None = None) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            workspace: Workspace to start the catalog with; every workspace if None
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.workspace = workspace
+        self._running = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        conn = config_manager.get_connection(self.conn_id)
+        destinations = [("Local folder", LOCAL_FOLDER)] + [
+            (f"S3: {s3.name}", s3.id) for s3 in config_manager.list_s3_connections()
+        ]
+        with Vertical(classes="terria-dialog"):
+            yield Static("Terria Init File", classes="terria-title")
+            with Horizontal(classes="terria-row"):
+                yield Input(
+                    value=conn.name if conn else "", placeholder="Catalog name", id="input-name"
+                )
+                yield Input(
+                    value=self.workspace or "",
+                    placeholder="Workspaces, comma separated (all)",
+                    id="input-workspaces",
+                )
+            with Horizontal(classes="terria-row"):
+                yield Select(
+                    destinations, value=LOCAL_FOLDER, allow_blank=False, id="select-destination"
+                )
+                yield Input(
+                    value=config_manager.config.last_local_path,
+                    placeholder="Folder, or bucket on S3",
+                    id="input-location",
+                )
+                yield Input(placeholder="Key prefix on S3", id="input-prefix", disabled=True)
+            with Horizontal(classes="terria-row"):
+                yield Input(
+                    placeholder="3D Tiles tilesets: S3 connection/bucket/prefix (optional)",
+                    id="input-tilesets",
+                )
+            with VerticalScroll(classes="terria-content"):
+                yield Static("", id="terria-content", markup=False)
+            yield Static(
+                "A catalog group per workspace, a WMS item per layer with its legend "
+                "and extent, and a camera on the layers.",
+                id="terria-summary",
+                classes="terria-summary",
+            )
+            with Horizontal(classes="terria-row"):
+                yield Button("Preview", id="btn-preview", variant="primary")
+                yield Button("Write", id="btn-write")
+                yield Button("Close", id="btn-close")
+
+    def on_select_changed(self, event: Select.Changed) -> None:
+        """Offer the last local folder again when writing locally."""
+        if event.select.id == "select-destination":
+            location = self.query_one("#input-location", Input)
+            local = event.value == LOCAL_FOLDER
+            location.value = config_manager.config.last_local_path if local else ""
+            self.query_one("#input-prefix", Input).disabled = local
+
+    def _generate(self, write: bool) -> None:
+        """Generate the init file in a background thread, and write it if asked."""
+        if self._running:
+            return
+        conn = config_manager.get_connection(self.conn_id)
+        name = self.query_one("#input-name", Input).value.strip()
+        if conn is None or not name:
+            self.app.notify("Enter a name for the catalog", severity="warning")
+            return
+        value = self.query_one("#input-workspaces", Input).value
+        workspaces = [ws.strip() for ws in value.split(",") if ws.strip()] or None
+        tilesets_ref = self.query_one("#input-tilesets", Input).value.strip()
+        destination = str(self.query_one("#select-destination", Select).value)
+        location = self.query_one("#input-location", Input).value.strip()
+        prefix = self.query_one("#input-prefix", Input).value.strip()
+        if write and destination != LOCAL_FOLDER and not location:
+            self.app.notify("Enter the bucket to upload to", severity="warning")
+            return
+
+        self._running = True
+        self.query_one("#terria-summary", Static).update("Reading the layers...")
+
+        def generate() -> None:
+            try:
+                tilesets = None
+                if tilesets_ref:
+                    s3_conn, _, path = tilesets_ref.partition("/")
+                    bucket, _, tiles_prefix = path.partition("/")
+                    if not bucket:
+                        raise ValueError("Give tilesets as S3 connection/bucket/prefix")
+                    s3 = get_s3_client(s3_conn)
+                    tilesets = tileset_items(
+                        s3, bucket, tiles_prefix, lambda key: s3.object_url(bucket, key)
+                    )
+                init = generate_init(
+                    get_geoserver_client(conn.id),
+                    conn.public_base_url,
+                    name,
+                    workspaces,
+                    tilesets,
+                )
+                if write and destination == LOCAL_FOLDER:
+                    write_init(init, Path(location or ".").expanduser())
+                elif write:
+                    upload_init(init, get_s3_client(destination), location, prefix)
+            except GeoServerError as e:
+                self.app.call_from_thread(self._on_failed, e.message)
+                return
+            except (OSError, ValueError, ClientError) as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._show, init)
+
+        self.run_worker(generate, thread=True)
+
+    def _show(self, init: InitFile) -> None:
+        """Show the init file and where it was written."""
+        self._running = False
+        self.query_one("#terria-content", Static).update(init.content)
+        summary = self.query_one("#terria-summary", Static)
+        if init.location:
+            summary.update(f"Wrote {init.layers} layers to {init.location}")
+            self.app.notify(f"Wrote {init.location}", severity="information")
+        else:
+            summary.update(f"{init.filename}: {init.layers} layers; press Write to save it")
+
+    def _on_failed(self, error: str) -> None:
+        """Report an init file that can't be generated or written."""
+        self._running = False
+        self.query_one("#terria-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-preview":
+            self._generate(write=False)
+        elif event.button.id == "btn-write":
+            self._generate(write=True)
+        elif event.button.id == "btn-close":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless the init file is being generated."""
+        if self._running:
+            self.app.notify("Wait for the init file to be generated", severity="warning")
+            return
+        self.dismiss(None)
@@ -0,0 +1,123 @@
+"""Tile service URLs dialog for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Vertical
+from textual.screen import ModalScreen
+from textual.widgets import DataTable, Static
+
+from apps.gwc.client import get_gwc_client
+from apps.gwc.endpoints import EndpointCheck, check_endpoints, tile_endpoints
+
+
+def format_check(check: EndpointCheck) -> str:
+    """Describe the outcome of a tile service check."""
+    lines = [f"Grid set: {check.grid_set or '-'}   Format: {check.format or '-'}"]
+    if check.ok:
+        lines.append(f"[green]Sample tile served as {check.content_type} ({check.size:,} bytes)[/]")
+    else:
+        lines += [f"[red]{issue}[/]" for issue in check.issues]
+    return "\n".join(lines)
+
+
+class TileEndpointsScreen(ModalScreen[None]):
+    """Dialog listing a cached layer's client URLs, checked with a sample tile."""
+
+    DEFAULT_CSS = """
+    TileEndpointsScreen {
+        align: center middle;
+    }
+
+    .endpoints-dialog {
+        width: 90%;
+        height: 70%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .endpoints-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .endpoints-check {
+        height: auto;
+        margin-bottom: 1;
+    }
+
+    .endpoints-table {
+        height: 1fr;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "close", "Close"),
+        ("enter", "copy", "Copy"),
+        ("y", "copy", "Copy"),
+    ]
+
+    def __init__(self, conn_id: str, layer: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            layer: Full layer name (workspace:layer)
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.layer = layer
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="endpoints-dialog"):
+            yield Static(f"Tile services for {self.layer}", classes="endpoints-title")
+            yield Static(
+                "Fetching a sample tile...", id="endpoints-check", classes="endpoints-check"
+            )
+            table = DataTable(id="endpoints-table", classes="endpoints-table", cursor_type="row")
+            table.add_columns("URL", "Value")
+            yield table
+
+    def on_mount(self) -> None:
+        """Check the layer in a background thread."""
+        self.run_worker(self._check, thread=True)
+
+    def _check(self) -> None:
+        """Fetch the capabilities and a sample tile."""
+        try:
+            client = get_gwc_client(self.conn_id)
+            check = check_endpoints(client, self.layer)
+            endpoints = tile_endpoints(
+                client.connection.public_base_url,
+                self.layer,
+                check.grid_set or "EPSG:900913",
+                check.format or "image/png",
+            )
+        except Exception as e:
+            self.app.call_from_thread(
+                self.query_one("#endpoints-check", Static).update, f"[red]{e}[/]"
+            )
+            return
+        self.app.call_from_thread(self._show, check, endpoints)
+
+    def _show(self, check: EndpointCheck, endpoints: list) -> None:
+        """Show the check and the URLs."""
+        self.query_one("#endpoints-check", Static).update(format_check(check))
+        table = self.query_one("#endpoints-table", DataTable)
+        for endpoint in endpoints:
+            table.add_row(endpoint.label, endpoint.value, key=endpoint.value)
+        table.focus()
+
+    def action_copy(self) -> None:
+        """Copy the URL under the cursor."""
+        table = self.query_one("#endpoints-table", DataTable)
+        if table.row_count == 0:
+            return
+        row_key, _ = table.coordinate_to_cell_key(table.cursor_coordinate)
+        label = table.get_row(row_key)[0]
+        self.app.copy_to_clipboard(str(row_key.value))
+        self.app.notify(f"Copied {label}", severity="information")
+
+    def action_close(self) -> None:
+        """Close the dialog."""
+        self.dismiss(None)
@@ -0,0 +1,182 @@
+"""Create workspace dialog for Kartoza CloudBench TUI."""
+
+from textual.app import ComposeResult
+from textual.containers import Horizontal, Vertical
+from textual.screen import ModalScreen
+from textual.widgets import Button, Checkbox, Input, Select, Static
+
+from apps.core.exceptions import GeoServerError
+from apps.geoserver.client import get_geoserver_client
+from apps.geoserver.naming import check_name
+from apps.geoserver.workspace_templates import (
+    TemplateStep,
+    create_workspace_from_template,
+    get_template,
+    list_templates,
+)
+
+
+class WorkspaceCreateScreen(ModalScreen[str | None]):
+    """Dialog creating a workspace, empty or from a workspace template.
+
+    Dismisses with the new workspace's name, or None if nothing was created.
+    """
+
+    DEFAULT_CSS = """
+    WorkspaceCreateScreen {
+        align: center middle;
+    }
+
+    .create-dialog {
+        width: 70;
+        height: auto;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .create-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .create-row {
+        height: auto;
+        margin-top: 1;
+    }
+
+    .create-summary {
+        height: auto;
+        margin-top: 1;
+    }
+    """
+
+    BINDINGS = [("escape", "close", "Close")]
+
+    def __init__(self, conn_id: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self._creating = False
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="create-dialog"):
+            yield Static("Create workspace", classes="create-title")
+            yield Input(placeholder="Workspace name", id="input-name")
+            yield Select(
+                [(t.name, t.name) for t in list_templates()],
+                id="select-template",
+                prompt="No template (empty workspace)",
+                classes="create-row",
+            )
+            yield Checkbox("Isolated", id="check-isolated", classes="create-row")
+            yield Static("", id="create-summary", classes="create-summary")
+            with Horizontal(classes="create-row"):
+                yield Button("Create", id="btn-create", variant="primary")
+                yield Button("Cancel", id="btn-cancel")
+
+    def on_mount(self) -> None:
+        """Start in the name input."""
+        self.query_one("#input-name", Input).focus()
+
+    def on_select_changed(self, event: Select.Changed) -> None:
+        """Describe the chosen template and take its isolation setting."""
+        summary = self.query_one("#create-summary", Static)
+        template = get_template(event.value) if event.value != Select.BLANK else None
+        if template is None:
+            summary.update("")
+            return
+        self.query_one("#check-isolated", Checkbox).value = template.isolated
+        parts = [template.description, f"Services: {', '.join(template.services) or 'none'}"]
+        if template.datastores:
+            parts.append(f"Stores: {', '.join(s.name for s in template.datastores)}")
+        if template.styles:
+            parts.append(f"Styles: {', '.join(template.styles)}")
+        summary.update("\n".join(p for p in parts if p))
+
+    def on_input_changed(self, event: Input.Changed) -> None:
+        """Show how the name breaks the naming conventions while it's typed."""
+        name = event.value.strip()
+        problems = check_name("workspace", name) if name else []
+        self.query_one("#create-summary", Static).update(
+            f"[red]{'; '.join(problems)}[/]" if problems else ""
+        )
+
+    def on_input_submitted(self, event: Input.Submitted) -> None:
+        """Create when Enter is pressed in the name input."""
+        self._create()
+
+    def _create(self) -> None:
+        """Create the workspace in a background thread."""
+        if self._creating:
+            return
+        name = self.query_one("#input-name", Input).value.strip()
+        if not name:
+            self.app.notify("Please enter a workspace name", severity="error")
+            return
+        problems = check_name("workspace", name)
+        if problems:
+            self.app.notify(f"Invalid workspace name: {'; '.join(problems)}", severity="error")
+            return
+        value = self.query_one("#select-template", Select).value
+        template = get_template(value) if value != Select.BLANK else None
+        isolated = self.query_one("#check-isolated", Checkbox).value
+        self._creating = True
+        self.query_one("#create-summary", Static).update(f"Creating {name}...")
+
+        def run() -> None:
+            try:
+                client = get_geoserver_client(self.conn_id)
+                if template is None:
+                    client.create_workspace(name, isolated=isolated)
+                    steps = [TemplateStep("workspace", name)]
+                else:
+                    steps = create_workspace_from_template(
+                        client, name, template, isolated=isolated
+                    )
+            except GeoServerError as e:
+                self.app.call_from_thread(self._on_failed, e.message)
+                return
+            self.app.call_from_thread(self._on_created, name, steps)
+
+        self.run_worker(run, thread=True)
+
+    def _on_failed(self, error: str) -> None:
+        """Report a workspace that couldn't be created."""
+        self._creating = False
+        self.query_one("#create-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(f"Create failed: {error}", severity="error")
+
+    def _on_created(self, name: str, steps: list[TemplateStep]) -> None:
+        """Report the new workspace, and any template steps that failed."""
+        self._creating = False
+        failed = [step for step in steps if not step.ok]
+        if failed:
+            self.app.notify(
+                f"Workspace {name} created; failed: "
+                + "; ".join(f"{s.kind} {s.name}: {s.error}" for s in failed),
+                severity="warning",
+                timeout=10,
+            )
+        else:
+            self.app.notify(f"Workspace {name} created", severity="information")
+        self.dismiss(name)
+
+    def on_button_pressed(self, event: Button.Pressed) -> None:
+        """Handle button presses."""
+        if event.button.id == "btn-create":
+            self._create()
+        elif event.button.id == "btn-cancel":
+            self.action_close()
+
+    def action_close(self) -> None:
+        """Close the dialog unless a workspace is being created."""
+        if self._creating:
+            self.app.notify("Wait for the workspace to be created", severity="warning")
+            return
+        self.dismiss(None)
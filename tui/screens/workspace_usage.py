@@ -0,0 +1,193 @@
+"""Workspace storage report for Kartoza CloudBench TUI."""
+
+from pathlib import Path
+
+from textual.app import ComposeResult
+from textual.containers import Vertical
+from textual.screen import ModalScreen
+from textual.widgets import DataTable, Static
+
+from apps.core.output import format_csv
+from apps.dashboard.workspace_usage import (
+    COLUMNS,
+    WorkspaceUsage,
+    WorkspaceUsageReport,
+    connection_workspace_usage,
+)
+
+from ..styles import size_label
+
+# Table columns: key, label, and whether the figure is a byte count
+USAGE_COLUMNS = (
+    ("workspace", "Workspace", False),
+    ("tile_cache_bytes", "Tile Cache", True),
+    ("store_bytes", "Stores", True),
+    ("total_bytes", "Total", True),
+    ("cached_layers", "Cached Layers", False),
+    ("sized_stores", "Sized Stores", False),
+    ("external_stores", "External Stores", False),
+)
+
+
+class WorkspaceUsageScreen(ModalScreen[None]):
+    """Dialog listing the storage each workspace of a connection uses."""
+
+    DEFAULT_CSS = """
+    WorkspaceUsageScreen {
+        align: center middle;
+    }
+
+    .usage-dialog {
+        width: 90%;
+        height: 85%;
+        padding: 1 2;
+        background: $surface;
+        border: thick $primary;
+    }
+
+    .usage-title {
+        text-style: bold;
+        height: 2;
+    }
+
+    .usage-table {
+        height: 1fr;
+        margin: 1 0;
+    }
+
+    .usage-summary {
+        height: auto;
+    }
+    """
+
+    BINDINGS = [
+        ("escape", "close", "Close"),
+        ("s", "sort", "Sort"),
+        ("x", "export", "Save CSV"),
+        ("r", "refresh", "Refresh"),
+    ]
+
+    def __init__(self, conn_id: str, conn_name: str) -> None:
+        """Initialize the dialog.
+
+        Args:
+            conn_id: Connection ID
+            conn_name: Connection name, for the title
+        """
+        super().__init__()
+        self.conn_id = conn_id
+        self.conn_name = conn_name
+        self.sort: str | None = None
+        self.descending = False
+        self._report: WorkspaceUsageReport | None = None
+
+    def compose(self) -> ComposeResult:
+        """Create the dialog layout."""
+        with Vertical(classes="usage-dialog"):
+            yield Static(f"Workspace Storage: {self.conn_name}", classes="usage-title")
+            table = DataTable(id="usage-table", classes="usage-table", cursor_type="cell")
+            for key, label, _ in USAGE_COLUMNS:
+                table.add_column(label, key=key)
+            yield table
+            yield Static("Sizing workspaces...", id="usage-summary", classes="usage-summary")
+
+    def on_mount(self) -> None:
+        """Load the report."""
+        self.action_refresh()
+
+    def action_refresh(self) -> None:
+        """Size every workspace in a background thread."""
+        self.query_one("#usage-summary", Static).update("Sizing workspaces...")
+
+        def load() -> None:
+            try:
+                report = connection_workspace_usage(self.conn_id)
+            except Exception as e:
+                self.app.call_from_thread(self._on_failed, str(e))
+                return
+            self.app.call_from_thread(self._show, report)
+
+        self.run_worker(load, thread=True, exclusive=True)
+
+    def _show(self, report: WorkspaceUsageReport) -> None:
+        """Fill the table and summarize what couldn't be sized."""
+        self._report = report
+        self._fill()
+        lines = [
+            f"{len(report.workspaces)} workspace(s), {size_label(report.total_bytes)} in all; "
+            "s sorts by the column under the cursor, x saves the table as CSV."
+        ]
+        if not all(usage.complete for usage in report.workspaces):
+            lines.append("Sizes marked >= are lower bounds: a store was too large to walk.")
+        if report.tile_cache_error:
+            lines.append(f"[yellow]Tile caches not counted: {report.tile_cache_error}[/]")
+        if report.store_error:
+            lines.append(f"[yellow]Stores not sized: {report.store_error}[/]")
+        lines += [f"[red]{error}[/]" for error in report.errors]
+        self.query_one("#usage-summary", Static).update("\n".join(lines))
+
+    def _fill(self) -> None:
+        """List the workspaces in the chosen order."""
+        if self._report is None:
+            return
+        usages = list(self._report.workspaces)
+        sort = self.sort
+        if sort:
+            usages.sort(key=lambda usage: getattr(usage, sort), reverse=self.descending)
+        table = self.query_one("#usage-table", DataTable)
+        table.clear()
+        for usage in usages:
+            table.add_row(*self._cells(usage))
+
+    def _cells(self, usage: WorkspaceUsage) -> list[str]:
+        """Format a workspace's figures for the table."""
+        cells = []
+        for key, _, is_bytes in USAGE_COLUMNS:
+            value = getattr(usage, key)
+            if key == "workspace":
+                cells.append(value or "(global)")
+            elif is_bytes:
+                prefix = "" if usage.complete or key == "tile_cache_bytes" else ">= "
+                cells.append(f"{prefix}{size_label(value)}")
+            else:
+                cells.append(str(value))
+        return cells
+
+    def action_sort(self) -> None:
+        """Sort by the column under the cursor: ascending, descending, then as reported."""
+        table = self.query_one("#usage-table", DataTable)
+        self._sort_by(USAGE_COLUMNS[table.cursor_column][0])
+
+    def on_data_table_header_selected(self, event: DataTable.HeaderSelected) -> None:
+        """Sort by a clicked column header."""
+        self._sort_by(str(event.column_key.value))
+
+    def _sort_by(self, column: str) -> None:
+        if self.sort != column:
+            self.sort, self.descending = column, False
+        elif not self.descending:
+            self.descending = True
+        else:
+            self.sort, self.descending = None, False
+        self._fill()
+
+    def action_export(self) -> None:
+        """Save the report as CSV in the working directory."""
+        if self._report is None:
+            return
+        path = Path.cwd() / f"{self.conn_id}_usage.csv"
+        try:
+            path.write_text(format_csv(self._report.records(), COLUMNS) + "\n", encoding="utf-8")
+        except OSError as e:
+            self.app.notify(f"Export failed: {str(e)}", severity="error")
+            return
+        self.app.notify(f"Saved {path.name}", severity="information")
+
+    def _on_failed(self, error: str) -> None:
+        """Report workspaces that can't be listed."""
+        self.query_one("#usage-summary", Static).update(f"[red]{error}[/]")
+        self.app.notify(error, severity="error")
+
+    def action_close(self) -> None:
+        """Close the dialog."""
+        self.dismiss(None)
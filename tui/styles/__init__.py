@@ -0,0 +1,26 @@
+"""Themes and styles for Kartoza CloudBench TUI."""
+
+from .labels import (
+    accessible_mode,
+    check_label,
+    connection_label,
+    group_label,
+    marked_label,
+    node_label,
+    size_label,
+)
+from .themes import BUILTIN_THEMES, TuiTheme, get_theme, theme_names
+
+__all__ = [
+    "BUILTIN_THEMES",
+    "TuiTheme",
+    "accessible_mode",
+    "check_label",
+    "connection_label",
+    "get_theme",
+    "group_label",
+    "marked_label",
+    "node_label",
+    "size_label",
+    "theme_names",
+]
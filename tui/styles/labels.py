@@ -0,0 +1,111 @@
+"""Node labels for TUI trees.
+
+Icons are Nerd Font glyphs, and the active connection and nodes marked
+for a batch action get a check mark. In accessible mode, set with accessible_mode in the config
+file or the settings screen, both are replaced by words so state never
+depends on glyphs or color alone and screen readers can announce it.
+Running with --plain turns accessible mode on for that run.
+"""
+
+from apps.core.config import config_manager
+
+# Node type -> Nerd Font icon
+ICONS = {
+    "workspace": "\uf07b",  # folder
+    "group": "\uf07c",  # folder-open
+    "datastore": "\uf1c0",  # database
+    "datastores": "\uf1c0",
+    "coveragestore": "\uf03e",  # image
+    "coveragestores": "\uf03e",
+    "featuretype": "\uf0ac",  # globe
+    "coverage": "\uf279",  # map
+    "layer": "\uf5fd",  # layer-group
+    "layers": "\uf279",
+    "style": "\uf1fc",  # paint-brush
+    "styles": "\uf1fc",
+    "layergroup": "\uf5fd",  # layer-group
+    "layergroups": "\uf5fd",
+    "recent": "\uf017",  # clock
+    "bucket": "\uf0c2",  # cloud
+    "object": "\uf15b",  # file
+    "schema": "\uf0e8",  # sitemap
+    "table": "\uf0ce",  # table
+    "column": "\uf0db",  # columns
+    "default": "\uf15b",  # file
+}
+
+# Node type -> prefix used instead of the icon in accessible mode
+TEXT_LABELS = {
+    "workspace": "Workspace:",
+    "datastore": "Data store:",
+    "coveragestore": "Coverage store:",
+    "featuretype": "Feature type:",
+    "coverage": "Coverage:",
+    "layer": "Layer:",
+    "style": "Style:",
+    "layergroup": "Layer group:",
+    "bucket": "Bucket:",
+    "object": "Object:",
+    "schema": "Schema:",
+    "table": "Table:",
+    "column": "Column:",
+}
+
+
+def accessible_mode() -> bool:
+    """Whether accessible mode is on, in the config or for this run with --plain."""
+    return config_manager.plain_mode or config_manager.config.accessible_mode
+
+
+def node_label(node_type: str, label: str) -> str:
+    """Label a tree node with its type's icon, or in words in accessible mode."""
+    if accessible_mode():
+        prefix = TEXT_LABELS.get(node_type, "")
+        return f"{prefix} {label}" if prefix else label
+    return f"{ICONS.get(node_type, ICONS['default'])} {label}"
+
+
+def connection_label(name: str, active: bool, badge: str = "", environment: str = "") -> str:
+    """Label a connection node, marking the active one.
+
+    The connection's badge goes before its name and its environment after
+    it; in accessible mode the badge, often an emoji, is left out.
+    """
+    if accessible_mode():
+        label = f"{name}, {environment}" if environment else name
+        return f"{label} (active)" if active else label
+    label = f"{badge} {name}" if badge else name
+    if environment:
+        label = f"{label} ({environment})"
+    return f"\u2713 {label}" if active else f"  {label}"
+
+
+def group_label(name: str) -> str:
+    """Label a folder of connections."""
+    if accessible_mode():
+        return f"Group: {name}"
+    return f"{ICONS['group']} {name}"
+
+
+def marked_label(label: str) -> str:
+    """Label of a tree node marked for a batch action."""
+    if accessible_mode():
+        return f"{label} (marked)"
+    return f"\u2713 {label}"
+
+
+def check_label(enabled: bool) -> str:
+    """Table cell for an on/off setting: a check or cross, or yes/no in accessible mode."""
+    if accessible_mode():
+        return "yes" if enabled else "no"
+    return "\u2713" if enabled else "\u2717"
+
+
+def size_label(size: int) -> str:
+    """Table cell for a byte count, in the largest unit under 1024."""
+    value = float(size)
+    for unit in ("B", "KB", "MB", "GB", "TB"):
+        if value < 1024 or unit == "TB":
+            return f"{value:.0f} {unit}" if unit == "B" else f"{value:.1f} {unit}"
+        value /= 1024
+    return f"{value:.1f} TB"
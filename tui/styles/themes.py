@@ -0,0 +1,154 @@
+"""Color themes for Kartoza CloudBench TUI.
+
+A theme sets the base colors Textual derives its design variables from
+($primary, $surface, $text-muted, ...), so every screen's DEFAULT_CSS picks
+it up without changes. Built-in themes can be extended with user-defined
+ones in the config file's custom_themes list:
+
+    "custom_themes": [
+        {"name": "ocean", "primary": "#006994", "background": "#001f2b", "dark": true}
+    ]
+
+Colors left empty fall back to the default theme's.
+"""
+
+from dataclasses import dataclass, fields, replace
+from typing import TYPE_CHECKING
+
+from textual.design import ColorSystem
+
+if TYPE_CHECKING:
+    from apps.core.config import ThemeConfig
+
+DEFAULT_THEME = "default"
+
+
+@dataclass(frozen=True)
+class TuiTheme:
+    """Base colors of a TUI theme."""
+
+    name: str
+    primary: str
+    secondary: str
+    accent: str
+    background: str
+    surface: str
+    success: str
+    warning: str
+    error: str
+    dark: bool = True
+
+    def color_system(self) -> ColorSystem:
+        """Get the Textual color system for this theme."""
+        return ColorSystem(
+            primary=self.primary,
+            secondary=self.secondary,
+            accent=self.accent,
+            background=self.background,
+            surface=self.surface,
+            success=self.success,
+            warning=self.warning,
+            error=self.error,
+            dark=self.dark,
+        )
+
+    def css_variables(self) -> dict[str, str]:
+        """Get the CSS variables for this theme."""
+        return self.color_system().generate()
+
+
+BUILTIN_THEMES: dict[str, TuiTheme] = {
+    # Kartoza branding, as in app.tcss
+    "default": TuiTheme(
+        name="default",
+        primary="#1a73e8",
+        secondary="#34a853",
+        accent="#4d94ff",
+        background="#121212",
+        surface="#1e1e1e",
+        success="#34a853",
+        warning="#fbbc04",
+        error="#ea4335",
+    ),
+    "dark": TuiTheme(
+        name="dark",
+        primary="#5e81ac",
+        secondary="#81a1c1",
+        accent="#88c0d0",
+        background="#1b1e24",
+        surface="#262a33",
+        success="#a3be8c",
+        warning="#ebcb8b",
+        error="#bf616a",
+    ),
+    "light": TuiTheme(
+        name="light",
+        primary="#1a73e8",
+        secondary="#188038",
+        accent="#1557b0",
+        background="#f8f9fa",
+        surface="#ffffff",
+        success="#188038",
+        warning="#b06000",
+        error="#c5221f",
+        dark=False,
+    ),
+    # Maximum contrast, and colors that stay distinct with color blindness
+    "high-contrast": TuiTheme(
+        name="high-contrast",
+        primary="#ffff00",
+        secondary="#00ffff",
+        accent="#ffffff",
+        background="#000000",
+        surface="#000000",
+        success="#00ffff",
+        warning="#ffff00",
+        error="#ff00ff",
+    ),
+    "solarized": TuiTheme(
+        name="solarized",
+        primary="#268bd2",
+        secondary="#2aa198",
+        accent="#6c71c4",
+        background="#002b36",
+        surface="#073642",
+        success="#859900",
+        warning="#b58900",
+        error="#dc322f",
+    ),
+}
+
+
+def _from_config(custom: "ThemeConfig") -> TuiTheme:
+    colors = {
+        f.name: getattr(custom, f.name)
+        for f in fields(TuiTheme)
+        if f.name not in ("name", "dark") and getattr(custom, f.name, "")
+    }
+    base = BUILTIN_THEMES[DEFAULT_THEME if custom.dark else "light"]
+    return replace(base, name=custom.name, dark=custom.dark, **colors)
+
+
+def theme_names(custom_themes: list["ThemeConfig"] | None = None) -> list[str]:
+    """List built-in theme names followed by user-defined ones."""
+    names = list(BUILTIN_THEMES)
+    names.extend(t.name for t in custom_themes or [] if t.name not in BUILTIN_THEMES)
+    return names
+
+
+def get_theme(name: str, custom_themes: list["ThemeConfig"] | None = None) -> TuiTheme:
+    """Get a theme by name; the default theme when it doesn't exist.
+
+    Args:
+        name: Theme name
+        custom_themes: User-defined themes from the config file
+
+    Returns:
+        The theme
+    """
+    if name in BUILTIN_THEMES:
+        return BUILTIN_THEMES[name]
+    for custom in custom_themes or []:
+        if custom.name == name:
+            return _from_config(custom)
+    return BUILTIN_THEMES[DEFAULT_THEME]
@@ -1,9 +1,11 @@
 """Custom widgets for Kartoza CloudBench TUI."""
 
 from .progress import ProgressIndicator
+from .splitter import Splitter
 from .tree import ResourceTreeWidget
 
 __all__ = [
     "ResourceTreeWidget",
     "ProgressIndicator",
+    "Splitter",
 ]
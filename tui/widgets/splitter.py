@@ -0,0 +1,98 @@
+"""Draggable panel splitter for Kartoza CloudBench TUI."""
+
+from textual import events
+from textual.message import Message
+from textual.widget import Widget
+
+
+class Splitter(Widget):
+    """A vertical bar that resizes the panel to its left when dragged.
+
+    Place it between two panels in a horizontal container; the panel after
+    it should have a flexible width (1fr) to take up the rest.
+    """
+
+    DEFAULT_CSS = """
+    Splitter {
+        width: 1;
+        height: 100%;
+        background: $primary-darken-1;
+    }
+
+    Splitter:hover {
+        background: $accent;
+    }
+
+    Splitter.-dragging {
+        background: $accent;
+    }
+    """
+
+    class Resized(Message):
+        """Posted when the user has finished dragging the splitter."""
+
+        def __init__(self, splitter: "Splitter", width: int) -> None:
+            """Initialize message with the new width of the left panel in cells."""
+            super().__init__()
+            self.splitter = splitter
+            self.width = width
+
+    def __init__(self, min_width: int = 10, min_remaining: int = 20, **kwargs):
+        """Initialize the splitter.
+
+        Args:
+            min_width: Narrowest the left panel can get, in cells
+            min_remaining: Cells always left for the right panel
+            **kwargs: Additional arguments passed to Widget
+        """
+        super().__init__(**kwargs)
+        self.min_width = min_width
+        self.min_remaining = min_remaining
+        self._dragging = False
+
+    @property
+    def target(self) -> Widget | None:
+        """The panel resized by this splitter."""
+        siblings = list(self.parent.children) if self.parent else []
+        index = siblings.index(self) if self in siblings else 0
+        return siblings[index - 1] if index > 0 else None
+
+    def resize_to(self, width: int) -> int:
+        """Set the width of the left panel, within the limits.
+
+        Returns:
+            The width applied, in cells
+        """
+        target = self.target
+        if target is None or self.parent is None:
+            return 0
+        available = self.parent.size.width - self.size.width
+        width = max(self.min_width, min(width, available - self.min_remaining))
+        target.styles.width = width
+        return width
+
+    def on_mouse_down(self, event: events.MouseDown) -> None:
+        """Start dragging."""
+        if self.target is None:
+            return
+        self._dragging = True
+        self.add_class("-dragging")
+        self.capture_mouse()
+        event.stop()
+
+    def on_mouse_move(self, event: events.MouseMove) -> None:
+        """Follow the mouse while dragging."""
+        if self._dragging and self.target is not None:
+            self.resize_to(event.screen_x - self.target.region.x)
+            event.stop()
+
+    def on_mouse_up(self, event: events.MouseUp) -> None:
+        """Stop dragging and report the new width."""
+        if not self._dragging:
+            return
+        self._dragging = False
+        self.remove_class("-dragging")
+        self.release_mouse()
+        if self.target is not None:
+            self.post_message(self.Resized(self, self.target.size.width))
+        event.stop()
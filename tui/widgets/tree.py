@@ -1,15 +1,50 @@
 """Resource tree widget for Kartoza CloudBench TUI."""
 
+import time
+from collections.abc import Iterator
+from contextlib import contextmanager
+from typing import Any
+
+from textual import events
+from textual.binding import Binding
+from textual.message import Message
 from textual.widgets import Tree
-from textual.widgets.tree import TreeNode
+from textual.widgets.tree import TreeNode, UnknownNodeID
+
+from ..styles.labels import ICONS, marked_label, node_label
 
 
 class ResourceTreeWidget(Tree):
     """A tree widget for browsing geospatial resources.
 
-    Supports lazy loading of children and different node types.
+    Supports lazy loading of children and different node types. A click
+    selects a node and a double click expands or collapses it; from the
+    keyboard, enter selects and space toggles. On node types listed in
+    MARKABLE_TYPES, space instead marks the node for a batch action.
+    Children can be updated in place from a fresh listing with
+    sync_children, which keeps the expansion and marks of nodes still
+    listed.
     """
 
+    BINDINGS = [
+        Binding("space", "toggle_mark", "Mark", show=False),
+    ]
+
+    # Seconds between two clicks on a node for them to count as a double click
+    DOUBLE_CLICK_INTERVAL = 0.4
+
+    # Node types that can be marked for batch actions
+    MARKABLE_TYPES: frozenset[str] = frozenset()
+
+    class MarksChanged(Message):
+        """Posted when a node is marked or unmarked."""
+
+        def __init__(self, tree: "ResourceTreeWidget", marked: list[TreeNode]) -> None:
+            """Initialize message with the nodes now marked."""
+            super().__init__()
+            self.tree = tree
+            self.marked = marked
+
     DEFAULT_CSS = """
     ResourceTreeWidget {
         background: $surface;
@@ -22,22 +57,7 @@ class ResourceTreeWidget(Tree):
     """
 
     # Node type icons using Nerd Font codepoints
-    ICONS = {
-        "workspace": "\uf07b",  # folder
-        "datastore": "\uf1c0",  # database
-        "coveragestore": "\uf03e",  # image
-        "featuretype": "\uf0ac",  # globe
-        "coverage": "\uf279",  # map
-        "layer": "\uf5fd",  # layer-group
-        "style": "\uf1fc",  # paint-brush
-        "layergroup": "\uf5fd",  # layer-group
-        "bucket": "\uf0c2",  # cloud
-        "object": "\uf15b",  # file
-        "schema": "\uf0e8",  # sitemap
-        "table": "\uf0ce",  # table
-        "column": "\uf0db",  # columns
-        "default": "\uf15b",  # file
-    }
+    ICONS = ICONS
 
     def __init__(self, label: str = "Resources", **kwargs):
         """Initialize the resource tree.
@@ -47,6 +67,144 @@ class ResourceTreeWidget(Tree):
             **kwargs: Additional arguments passed to Tree
         """
         super().__init__(label, **kwargs)
+        self.auto_expand = False
+        self._last_click: tuple[int, float] | None = None
+        # Node ID -> (node, label before it was marked)
+        self._marked: dict[int, tuple[TreeNode, object]] = {}
+
+    @property
+    def marked_nodes(self) -> list[TreeNode]:
+        """Nodes marked for a batch action, in the order they were marked."""
+        return [node for node, _ in self._marked.values()]
+
+    def is_markable(self, node: TreeNode) -> bool:
+        """Check whether a node can be marked."""
+        return bool(node.data) and node.data.get("type") in self.MARKABLE_TYPES
+
+    def toggle_mark(self, node: TreeNode) -> None:
+        """Mark or unmark a node."""
+        if node.id in self._marked:
+            _, label = self._marked.pop(node.id)
+            node.set_label(label)
+        else:
+            self._marked[node.id] = (node, node.label)
+            node.set_label(marked_label(str(node.label)))
+        self.post_message(self.MarksChanged(self, self.marked_nodes))
+
+    def clear_marks(self) -> None:
+        """Unmark all nodes."""
+        for node, label in self._marked.values():
+            node.set_label(label)
+        self._marked.clear()
+        self.post_message(self.MarksChanged(self, []))
+
+    def clear(self):
+        """Remove all nodes, forgetting their marks."""
+        if self._marked:
+            self._marked.clear()
+            self.post_message(self.MarksChanged(self, []))
+        return super().clear()
+
+    @staticmethod
+    def node_key(label: object, data: dict[str, Any] | None) -> tuple:
+        """Key matching a node to its entry in a fresh listing."""
+        if not data:
+            return ("", "", str(label))
+        return (data.get("type", ""), data.get("workspace") or "", data.get("name") or "")
+
+    def sync_children(
+        self, parent: TreeNode, children: list[tuple[str, dict[str, Any] | None, bool]]
+    ) -> list[TreeNode]:
+        """Update a node's children in place to match a fresh listing.
+
+        Children still listed keep their expansion, children and marks and
+        get the new label and data; children no longer listed are removed
+        and new ones are added in listing order.
+
+        Args:
+            parent: Node whose children are updated
+            children: (label, data, expandable) per child, in order
+
+        Returns:
+            The child nodes, in listing order
+        """
+        wanted = {self.node_key(label, data) for label, data, _ in children}
+        existing: dict[tuple, TreeNode] = {}
+        for node in list(parent.children):
+            key = self.node_key(node.label, node.data)
+            if key in wanted and key not in existing:
+                existing[key] = node
+                continue
+            self._forget_marks(node)
+            node.remove()
+
+        nodes: list[TreeNode] = []
+        for label, data, expandable in children:
+            node = existing.get(self.node_key(label, data))
+            if node is not None:
+                node.data = data
+                if node.id in self._marked:
+                    self._marked[node.id] = (node, label)
+                    node.set_label(marked_label(label))
+                else:
+                    node.set_label(label)
+            else:
+                position: dict[str, Any] = {}
+                if nodes:
+                    position["after"] = nodes[-1]
+                elif parent.children:
+                    position["before"] = parent.children[0]
+                add = parent.add if expandable else parent.add_leaf
+                node = add(label, data=data, **position)
+            nodes.append(node)
+        return nodes
+
+    def _forget_marks(self, node: TreeNode) -> None:
+        """Unmark a node about to be removed, and the nodes below it."""
+        removed = [node]
+        while removed:
+            current = removed.pop()
+            removed.extend(current.children)
+            if self._marked.pop(current.id, None) is not None:
+                self.post_message(self.MarksChanged(self, self.marked_nodes))
+
+    @contextmanager
+    def keeping_cursor(self) -> Iterator[None]:
+        """Keep the cursor on its node while nodes above it are added or removed."""
+        node = self.cursor_node
+        yield
+        if node is None:
+            return
+        try:
+            self.get_node_by_id(node.id)
+        except UnknownNodeID:
+            return  # The node itself was removed
+        # Node lines are only renumbered when the tree is next drawn
+        self.call_after_refresh(self.move_cursor, node)
+
+    def action_toggle_mark(self) -> None:
+        """Mark the node under the cursor, or toggle it if it can't be marked."""
+        node = self.cursor_node
+        if node is None:
+            return
+        if self.is_markable(node):
+            self.toggle_mark(node)
+        else:
+            self.action_toggle_node()
+
+    def on_click(self, event: events.Click) -> None:
+        """Toggle the node under the cursor on a double click."""
+        node = self.cursor_node
+        if node is None:
+            return
+
+        now = time.monotonic()
+        last = self._last_click
+        if last and last[0] == node.id and now - last[1] < self.DOUBLE_CLICK_INTERVAL:
+            node.toggle()
+            self._last_click = None
+        else:
+            self._last_click = (node.id, now)
 
     def get_icon(self, node_type: str) -> str:
         """Get the icon for a node type.
@@ -79,8 +237,7 @@ class ResourceTreeWidget(Tree):
         Returns:
             The created TreeNode
         """
-        icon = self.get_icon(node_type)
-        full_label = f"{icon} {label}"
+        full_label = node_label(node_type, label)
 
         node_data = data or {}
         node_data["type"] = node_type
@@ -1,5 +1,11 @@
 import { API_BASE } from './common'
-import type { UploadResult } from '../types'
+import type {
+  CogAdvice,
+  FilePreview,
+  LayerMetadata,
+  UploadResult,
+  UploadTarget,
+} from '../types'
 
 // Helper to get CSRF token for XHR requests
 function getCSRFToken(): string {
@@ -16,6 +22,7 @@ export async function initUploadSession(
   filename: string,
   fileSize: number,
   chunkSize = CHUNK_SIZE,
+  storeName?: string,
 ): Promise<{ sessionId: string; chunkSize: number; totalChunks: number }> {
   const res = await fetch(`${API_BASE}/upload/init`, {
     method: 'POST',
@@ -24,7 +31,37 @@ export async function initUploadSession(
       'X-CSRFToken': getCSRFToken(),
     },
     credentials: 'include',
-    body: JSON.stringify({ connectionId: connId, workspace, filename, fileSize, chunkSize }),
+    body: JSON.stringify({
+      connectionId: connId,
+      workspace,
+      filename,
+      fileSize,
+      chunkSize,
+      storeName,
+    }),
+  })
+  if (!res.ok) {
+    const err = await res.json().catch(() => ({ error: 'Unknown error' }))
+    throw new Error(err.error || `HTTP ${res.status}`)
+  }
+  return res.json()
+}
+
+// Where a file would be uploaded to: a new or existing store, and its layer
+export async function resolveUploadTarget(
+  connId: string,
+  workspace: string,
+  filename: string,
+  storeName?: string,
+): Promise<UploadTarget> {
+  const res = await fetch(`${API_BASE}/upload/target`, {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/json',
+      'X-CSRFToken': getCSRFToken(),
+    },
+    credentials: 'include',
+    body: JSON.stringify({ connectionId: connId, workspace, filename, storeName }),
   })
   if (!res.ok) {
     const err = await res.json().catch(() => ({ error: 'Unknown error' }))
@@ -80,8 +117,30 @@ export async function uploadChunk(
   })
 }
 
-export async function completeUpload(sessionId: string): Promise<UploadResult> {
-  const res = await fetch(`${API_BASE}/upload/complete`, {
+// Summary and thumbnail of a file before it is uploaded
+export async function previewFile(file: File): Promise<FilePreview> {
+  const formData = new FormData()
+  formData.append('file', file)
+  // Folders the file was picked from become keywords of its layer
+  if (file.webkitRelativePath) formData.append('path', file.webkitRelativePath)
+  const res = await fetch(`${API_BASE}/upload/preview`, {
+    method: 'POST',
+    headers: {
+      'X-CSRFToken': getCSRFToken(),
+    },
+    credentials: 'include',
+    body: formData,
+  })
+  if (!res.ok) {
+    const err = await res.json().catch(() => ({ error: 'Unknown error' }))
+    throw new Error(err.error || `HTTP ${res.status}`)
+  }
+  return res.json()
+}
+
+// COG advice for an uploaded GeoTIFF, null for other files
+export async function inspectUpload(sessionId: string): Promise<CogAdvice | null> {
+  const res = await fetch(`${API_BASE}/upload/inspect`, {
     method: 'POST',
     headers: {
       'Content-Type': 'application/json',
@@ -94,6 +153,31 @@ export async function completeUpload(sessionId: string): Promise<UploadResult> {
     const err = await res.json().catch(() => ({ error: 'Unknown error' }))
     throw new Error(err.error || `HTTP ${res.status}`)
   }
+  const data = await res.json()
+  return data.cog
+}
+
+// cog: convert a GeoTIFF to a COG first; undefined leaves it to the size threshold
+// verify: compare checksums with the files GeoServer stored
+export async function completeUpload(
+  sessionId: string,
+  cog?: boolean,
+  verify?: boolean,
+  metadata?: LayerMetadata,
+): Promise<UploadResult> {
+  const res = await fetch(`${API_BASE}/upload/complete`, {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/json',
+      'X-CSRFToken': getCSRFToken(),
+    },
+    credentials: 'include',
+    body: JSON.stringify({ sessionId, cog, verify, metadata }),
+  })
+  if (!res.ok) {
+    const err = await res.json().catch(() => ({ error: 'Unknown error' }))
+    throw new Error(err.error || `HTTP ${res.status}`)
+  }
   return res.json()
 }
 
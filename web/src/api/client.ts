@@ -28,22 +28,49 @@
 import { API_BASE, handleResponse } from './common'
 import type {
   UploadResult,
+  AppSettings,
   PreviewRequest,
   GWCLayer,
+  GWCLayerList,
   GWCSeedRequest,
   GWCSeedTask,
   GWCGridSet,
   GWCDiskQuota,
+  GWCSeedEstimate,
+  GWCSeedEstimateRequest,
+  GWCHitRatio,
+  GWCHitRatioRequest,
+  GWCSchedule,
+  GWCScheduleCreate,
+  SnapshotSchedule,
+  SnapshotScheduleCreate,
+  StoredSnapshot,
+  SnapshotRestoreReport,
+  GWCStyleLayer,
+  GWCTileEndpoints,
+  MassTruncateJob,
+  MapExport,
+  MapExportRequest,
   GeoServerContact,
+  WmsWatermark,
+  WmsWatermarkSettings,
   SyncConfiguration,
   SyncTask,
   StartSyncRequest,
+  PromoteStylesRequest,
+  PromoteStylesResponse,
+  SyncSettingsRequest,
+  SyncSettingsResponse,
   DashboardData,
+  ConnectionStorage,
+  WorkspaceUsageReport,
+  ServerComparison,
   ServerStatus,
   ConversionJob,
   ConversionToolStatus,
   QGISProject,
   QGISProjectCreate,
+  QGISPublishResult,
   GeoNodeConnection,
   GeoNodeConnectionCreate,
   GeoNodeTestResult,
@@ -69,6 +96,9 @@ import type {
   MerginMapsConnectionCreate,
   MerginMapsTestResult,
   MerginMapsProjectsResponse,
+  BatchUploadItem,
+  BatchUploadPlan,
+  BatchUploadItemResult,
 } from '../types'
 
 // ============================================================================
@@ -111,6 +141,68 @@ export async function uploadFile(
   })
 }
 
+export async function planBatchUpload(
+  paths: string[],
+  workspace: string,
+  folderWorkspaces = false
+): Promise<BatchUploadPlan> {
+  const response = await fetch(`${API_BASE}/upload/batch/plan`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ paths, workspace, folderWorkspaces }),
+  })
+  return handleResponse<BatchUploadPlan>(response)
+}
+
+export async function prepareBatchUpload(
+  connId: string,
+  workspaces: string[]
+): Promise<{ created: string[] }> {
+  const response = await fetch(`${API_BASE}/upload/batch/prepare`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ connectionId: connId, workspaces }),
+  })
+  return handleResponse<{ created: string[] }>(response)
+}
+
+export async function uploadBatchItem(
+  connId: string,
+  item: BatchUploadItem,
+  file: File,
+  onProgress?: (progress: number) => void
+): Promise<BatchUploadItemResult> {
+  const formData = new FormData()
+  formData.append('file', file)
+  formData.append('connectionId', connId)
+  formData.append('item', JSON.stringify(item))
+
+  return new Promise((resolve, reject) => {
+    const xhr = new XMLHttpRequest()
+
+    xhr.upload.addEventListener('progress', (event) => {
+      if (event.lengthComputable && onProgress) {
+        onProgress(Math.round((event.loaded / event.total) * 100))
+      }
+    })
+
+    xhr.addEventListener('load', () => {
+      if (xhr.status >= 200 && xhr.status < 300) {
+        resolve(JSON.parse(xhr.responseText))
+      } else {
+        reject(new Error(JSON.parse(xhr.responseText).error || 'Upload failed'))
+      }
+    })
+
+    xhr.addEventListener('error', () => {
+      reject(new Error('Network error'))
+    })
+
+    xhr.open('POST', `${API_BASE}/upload/batch/item`)
+    xhr.send(formData)
+  })
+}
+
 // ============================================================================
 // Preview API
 // ============================================================================
@@ -143,42 +235,149 @@ export async function getLayerMetadata(): Promise<{ bounds: number[] }> {
 // GeoWebCache (GWC) API
 // ============================================================================
 
-export async function getGWCLayers(connId: string): Promise<GWCLayer[]> {
-  const response = await fetch(`${API_BASE}/gwc/layers/${connId}`)
-  return handleResponse<GWCLayer[]>(response)
+// GWC layer routes take the workspace and name of a qualified layer name as two segments
+function gwcLayerPath(layerName: string): string {
+  const [workspace, ...rest] = layerName.split(':')
+  return `${encodeURIComponent(workspace)}/${encodeURIComponent(rest.join(':'))}`
+}
+
+export async function getGWCLayers(connId: string, workspace: string): Promise<GWCLayerList> {
+  const response = await fetch(`${API_BASE}/gwc/layers/${connId}/${encodeURIComponent(workspace)}`)
+  return handleResponse<GWCLayerList>(response)
 }
 
 export async function getGWCLayer(connId: string, layerName: string): Promise<GWCLayer> {
-  const response = await fetch(`${API_BASE}/gwc/layers/${connId}/${layerName}`)
-  return handleResponse<GWCLayer>(response)
+  const response = await fetch(`${API_BASE}/gwc/layers/${connId}/${gwcLayerPath(layerName)}`)
+  const { layer } = await handleResponse<{
+    layer: {
+      name: string
+      enabled?: boolean
+      gridSubsets?: { gridSetName: string }[]
+      mimeFormats?: string[]
+    }
+  }>(response)
+  return {
+    name: layer.name,
+    enabled: layer.enabled !== false,
+    gridSubsets: layer.gridSubsets?.map((subset) => subset.gridSetName),
+    mimeFormats: layer.mimeFormats,
+  }
+}
+
+export async function enableGWCLayer(
+  connId: string,
+  layerName: string,
+  options?: { gridSets?: string[]; formats?: string[] }
+): Promise<{ status: string; layer: string }> {
+  const response = await fetch(`${API_BASE}/gwc/layers/${connId}/${gwcLayerPath(layerName)}`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(options || {}),
+  })
+  return handleResponse<{ status: string; layer: string }>(response)
+}
+
+// GWC reports task states as numbers
+const SEED_TASK_STATUS: Record<number, GWCSeedTask['status']> = {
+  [-1]: 'Aborted',
+  0: 'Pending',
+  1: 'Running',
+  2: 'Done',
 }
 
 export async function getGWCSeedStatus(connId: string, layerName: string): Promise<GWCSeedTask[]> {
-  const response = await fetch(`${API_BASE}/gwc/seed/${connId}/${layerName}`)
-  return handleResponse<GWCSeedTask[]>(response)
+  const response = await fetch(`${API_BASE}/gwc/seed/${connId}/${gwcLayerPath(layerName)}`)
+  const { tasks } = await handleResponse<{
+    tasks: {
+      tilesProcessed: number
+      totalTiles: number
+      remainingTime: number
+      taskId: number
+      status: number
+    }[]
+  }>(response)
+  return tasks.map((task) => ({
+    id: task.taskId,
+    tilesDone: task.tilesProcessed,
+    tilesTotal: task.totalTiles,
+    timeRemaining: task.remainingTime,
+    status: SEED_TASK_STATUS[task.status] || 'Unknown',
+    layerName,
+    progress: task.totalTiles > 0 ? Math.round((task.tilesProcessed / task.totalTiles) * 100) : 0,
+  }))
 }
 
 export async function seedLayer(
   connId: string,
   layerName: string,
   request: GWCSeedRequest
-): Promise<{ success: boolean; message: string }> {
-  const response = await fetch(`${API_BASE}/gwc/seed/${connId}/${layerName}`, {
+): Promise<{ status: string; layer: string }> {
+  const response = await fetch(`${API_BASE}/gwc/seed/${connId}/${gwcLayerPath(layerName)}`, {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(request),
   })
-  return handleResponse<{ success: boolean; message: string }>(response)
+  return handleResponse<{ status: string; layer: string }>(response)
+}
+
+export async function estimateGWCSeed(
+  connId: string,
+  workspace: string,
+  layerName: string,
+  request: GWCSeedEstimateRequest
+): Promise<GWCSeedEstimate> {
+  const response = await fetch(
+    `${API_BASE}/gwc/estimate/${connId}/${encodeURIComponent(workspace)}/${encodeURIComponent(layerName)}`,
+    {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(request),
+    }
+  )
+  return handleResponse<GWCSeedEstimate>(response)
+}
+
+export async function getGWCTileEndpoints(
+  connId: string,
+  workspace: string,
+  layerName: string,
+  gridSet?: string,
+  tileFormat?: string
+): Promise<GWCTileEndpoints> {
+  const params = new URLSearchParams()
+  if (gridSet) params.set('gridSet', gridSet)
+  if (tileFormat) params.set('tileFormat', tileFormat)
+  const response = await fetch(
+    `${API_BASE}/gwc/endpoints/${connId}/${encodeURIComponent(workspace)}/${encodeURIComponent(layerName)}?${params}`
+  )
+  return handleResponse<GWCTileEndpoints>(response)
+}
+
+export async function getGWCHitRatio(
+  connId: string,
+  workspace: string,
+  layerName: string,
+  request: GWCHitRatioRequest
+): Promise<GWCHitRatio> {
+  const response = await fetch(
+    `${API_BASE}/gwc/hitratio/${connId}/${encodeURIComponent(workspace)}/${encodeURIComponent(layerName)}`,
+    {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(request),
+    }
+  )
+  return handleResponse<GWCHitRatio>(response)
 }
 
 export async function terminateLayerSeed(
   connId: string,
   layerName: string
-): Promise<{ success: boolean; message: string }> {
-  const response = await fetch(`${API_BASE}/gwc/seed/${connId}/${layerName}`, {
+): Promise<{ status: string }> {
+  const response = await fetch(`${API_BASE}/gwc/seed/${connId}/${gwcLayerPath(layerName)}`, {
     method: 'DELETE',
   })
-  return handleResponse<{ success: boolean; message: string }>(response)
+  return handleResponse<{ status: string }>(response)
 }
 
 export async function terminateAllSeeds(
@@ -200,13 +399,72 @@ export async function truncateLayer(
     zoomStart?: number
     zoomStop?: number
   }
-): Promise<{ success: boolean; message: string }> {
-  const response = await fetch(`${API_BASE}/gwc/truncate/${connId}/${layerName}`, {
+): Promise<{ status: string; layer: string }> {
+  const params = new URLSearchParams()
+  if (options?.gridSetId) params.set('gridSet', options.gridSetId)
+  if (options?.format) params.set('format', options.format)
+  if (options?.zoomStart !== undefined) params.set('zoomStart', String(options.zoomStart))
+  if (options?.zoomStop !== undefined) params.set('zoomStop', String(options.zoomStop))
+  const response = await fetch(
+    `${API_BASE}/gwc/truncate/${connId}/${gwcLayerPath(layerName)}?${params}`,
+    { method: 'DELETE' }
+  )
+  return handleResponse<{ status: string; layer: string }>(response)
+}
+
+export async function startMassTruncate(
+  connId: string,
+  layers: string[]
+): Promise<MassTruncateJob> {
+  const response = await fetch(`${API_BASE}/gwc/masstruncate/${connId}`, {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
-    body: JSON.stringify(options || {}),
+    body: JSON.stringify({ layers }),
   })
-  return handleResponse<{ success: boolean; message: string }>(response)
+  return handleResponse<MassTruncateJob>(response)
+}
+
+export async function getMassTruncateJob(connId: string, jobId: string): Promise<MassTruncateJob> {
+  const response = await fetch(`${API_BASE}/gwc/masstruncate/${connId}/${jobId}`)
+  return handleResponse<MassTruncateJob>(response)
+}
+
+export async function exportWebMap(connId: string, request: MapExportRequest): Promise<MapExport> {
+  const response = await fetch(`${API_BASE}/gwc/mapexport/${connId}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(request),
+  })
+  return handleResponse<MapExport>(response)
+}
+
+export async function getGWCStyleLayers(
+  connId: string,
+  workspace: string,
+  style: string
+): Promise<GWCStyleLayer[]> {
+  const response = await fetch(
+    `${API_BASE}/gwc/styles/${connId}/${encodeURIComponent(workspace)}/${encodeURIComponent(style)}`
+  )
+  const data = await handleResponse<{ layers: GWCStyleLayer[] }>(response)
+  return data.layers
+}
+
+export async function truncateGWCStyleLayers(
+  connId: string,
+  workspace: string,
+  style: string,
+  layers?: string[]
+): Promise<{ truncatedLayers: string[] }> {
+  const response = await fetch(
+    `${API_BASE}/gwc/styles/${connId}/${encodeURIComponent(workspace)}/${encodeURIComponent(style)}`,
+    {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(layers ? { layers } : {}),
+    }
+  )
+  return handleResponse<{ truncatedLayers: string[] }>(response)
 }
 
 export async function getGWCGridSets(connId: string): Promise<GWCGridSet[]> {
@@ -236,6 +494,66 @@ export async function updateGWCDiskQuota(
   return handleResponse<{ success: boolean; message: string }>(response)
 }
 
+export async function getGWCSchedules(connId?: string): Promise<GWCSchedule[]> {
+  const query = connId ? `?connectionId=${encodeURIComponent(connId)}` : ''
+  const response = await fetch(`${API_BASE}/gwc/schedules${query}`)
+  const data = await handleResponse<{ schedules: GWCSchedule[] }>(response)
+  return data.schedules
+}
+
+export async function createGWCSchedule(schedule: GWCScheduleCreate): Promise<GWCSchedule> {
+  const response = await fetch(`${API_BASE}/gwc/schedules`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(schedule),
+  })
+  return handleResponse<GWCSchedule>(response)
+}
+
+export async function updateGWCSchedule(
+  id: string,
+  schedule: Partial<GWCScheduleCreate>
+): Promise<GWCSchedule> {
+  const response = await fetch(`${API_BASE}/gwc/schedules/${id}`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(schedule),
+  })
+  return handleResponse<GWCSchedule>(response)
+}
+
+export async function deleteGWCSchedule(id: string): Promise<void> {
+  const response = await fetch(`${API_BASE}/gwc/schedules/${id}`, {
+    method: 'DELETE',
+  })
+  return handleResponse<void>(response)
+}
+
+export async function runGWCSchedule(id: string): Promise<GWCSchedule> {
+  const response = await fetch(`${API_BASE}/gwc/schedules/${id}/run`, {
+    method: 'POST',
+  })
+  return handleResponse<GWCSchedule>(response)
+}
+
+// ============================================================================
+// Application Settings API
+// ============================================================================
+
+export async function getAppSettings(): Promise<AppSettings> {
+  const response = await fetch(`${API_BASE}/settings/`)
+  return handleResponse<AppSettings>(response)
+}
+
+export async function updateAppSettings(settings: Partial<AppSettings>): Promise<AppSettings> {
+  const response = await fetch(`${API_BASE}/settings/`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(settings),
+  })
+  return handleResponse<AppSettings>(response)
+}
+
 // ============================================================================
 // GeoServer Settings/Contact API
 // ============================================================================
@@ -257,6 +575,23 @@ export async function updateContact(
   return handleResponse<GeoServerContact>(response)
 }
 
+export async function getWmsWatermark(connId: string): Promise<WmsWatermarkSettings> {
+  const response = await fetch(`${API_BASE}/wmswatermark/${connId}`)
+  return handleResponse<WmsWatermarkSettings>(response)
+}
+
+export async function updateWmsWatermark(
+  connId: string,
+  watermark: WmsWatermark
+): Promise<WmsWatermark> {
+  const response = await fetch(`${API_BASE}/wmswatermark/${connId}`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(watermark),
+  })
+  return handleResponse<WmsWatermark>(response)
+}
+
 // ============================================================================
 // Server Sync API
 // ============================================================================
@@ -310,6 +645,26 @@ export async function startSync(request: StartSyncRequest): Promise<SyncTask[]>
   return handleResponse<SyncTask[]>(response)
 }
 
+export async function promoteStyles(
+  request: PromoteStylesRequest
+): Promise<PromoteStylesResponse> {
+  const response = await fetch(`${API_BASE}/sync/styles/promote`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(request),
+  })
+  return handleResponse<PromoteStylesResponse>(response)
+}
+
+export async function syncSettings(request: SyncSettingsRequest): Promise<SyncSettingsResponse> {
+  const response = await fetch(`${API_BASE}/sync/settings`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(request),
+  })
+  return handleResponse<SyncSettingsResponse>(response)
+}
+
 export async function getSyncStatus(): Promise<SyncTask[]> {
   const response = await fetch(`${API_BASE}/sync/status`)
   return handleResponse<SyncTask[]>(response)
@@ -348,6 +703,28 @@ export async function getServerStatus(connectionId: string): Promise<ServerStatu
   return handleResponse<ServerStatus>(response)
 }
 
+export async function getConnectionStorage(connectionId: string): Promise<ConnectionStorage> {
+  const response = await fetch(`${API_BASE}/dashboard/storage/${connectionId}`)
+  return handleResponse<ConnectionStorage>(response)
+}
+
+export async function getWorkspaceUsage(connectionId: string): Promise<WorkspaceUsageReport> {
+  const response = await fetch(`${API_BASE}/dashboard/usage/${connectionId}`)
+  return handleResponse<WorkspaceUsageReport>(response)
+}
+
+export function getWorkspaceUsageCsvUrl(connectionId: string): string {
+  return `${API_BASE}/dashboard/usage/${connectionId}?format=csv`
+}
+
+export async function getServerComparison(connectionIds?: string[]): Promise<ServerComparison> {
+  const params = connectionIds
+    ? `?connections=${connectionIds.map(encodeURIComponent).join(',')}`
+    : ''
+  const response = await fetch(`${API_BASE}/dashboard/compare${params}`)
+  return handleResponse<ServerComparison>(response)
+}
+
 // ============================================================================
 // Download API - Export resource configurations
 // ============================================================================
@@ -355,17 +732,111 @@ export async function getServerStatus(connectionId: string): Promise<ServerStatu
 export type DownloadResourceType = 'workspace' | 'datastore' | 'coveragestore' | 'layer' | 'style' | 'layergroup'
 export type DownloadDataType = 'shapefile' | 'geotiff'
 
+export interface SnapshotResource {
+  type: DownloadResourceType
+  workspace?: string
+  name: string
+}
+
+// Downloads a dated snapshot folder, zipped, with the configuration (and optionally
+// data) of each resource and a manifest.json; resolves to the number that failed
+export async function downloadResources(
+  connectionId: string,
+  resources: SnapshotResource[],
+  includeData = false
+): Promise<number> {
+  const response = await fetch(`${API_BASE}/snapshot/${connectionId}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ resources, includeData }),
+  })
+  if (!response.ok) {
+    await handleResponse(response)
+  }
+  const disposition = response.headers.get('Content-Disposition') || ''
+  const filename = disposition.match(/filename="([^"]+)"/)?.[1] || 'snapshot.zip'
+  const url = URL.createObjectURL(await response.blob())
+  const a = document.createElement('a')
+  a.href = url
+  a.download = filename
+  a.click()
+  URL.revokeObjectURL(url)
+  return Number(response.headers.get('X-Snapshot-Failed') || 0)
+}
+
+export async function getSnapshotSchedules(connId?: string): Promise<SnapshotSchedule[]> {
+  const query = connId ? `?connectionId=${encodeURIComponent(connId)}` : ''
+  const response = await fetch(`${API_BASE}/snapshots/schedules${query}`)
+  const data = await handleResponse<{ schedules: SnapshotSchedule[] }>(response)
+  return data.schedules
+}
+
+export async function createSnapshotSchedule(
+  schedule: SnapshotScheduleCreate
+): Promise<SnapshotSchedule> {
+  const response = await fetch(`${API_BASE}/snapshots/schedules`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(schedule),
+  })
+  return handleResponse<SnapshotSchedule>(response)
+}
+
+export async function updateSnapshotSchedule(
+  id: string,
+  schedule: Partial<SnapshotScheduleCreate>
+): Promise<SnapshotSchedule> {
+  const response = await fetch(`${API_BASE}/snapshots/schedules/${id}`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(schedule),
+  })
+  return handleResponse<SnapshotSchedule>(response)
+}
+
+export async function deleteSnapshotSchedule(id: string): Promise<void> {
+  const response = await fetch(`${API_BASE}/snapshots/schedules/${id}`, {
+    method: 'DELETE',
+  })
+  return handleResponse<void>(response)
+}
+
+export async function runSnapshotSchedule(id: string): Promise<SnapshotSchedule> {
+  const response = await fetch(`${API_BASE}/snapshots/schedules/${id}/run`, {
+    method: 'POST',
+  })
+  return handleResponse<SnapshotSchedule>(response)
+}
+
+// Snapshots a schedule has stored, newest first
+export async function getStoredSnapshots(id: string, connId?: string): Promise<StoredSnapshot[]> {
+  const query = connId ? `?connectionId=${encodeURIComponent(connId)}` : ''
+  const response = await fetch(`${API_BASE}/snapshots/schedules/${id}/snapshots${query}`)
+  const data = await handleResponse<{ snapshots: StoredSnapshot[] }>(response)
+  return data.snapshots
+}
+
+// Recreates the resources of a stored snapshot that the connection is missing
+export async function restoreSnapshot(
+  id: string,
+  name: string,
+  connectionId: string
+): Promise<SnapshotRestoreReport> {
+  const response = await fetch(`${API_BASE}/snapshots/schedules/${id}/restore`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ name, connectionId }),
+  })
+  return handleResponse<SnapshotRestoreReport>(response)
+}
+
 export function downloadResource(
   connectionId: string,
   resourceType: DownloadResourceType,
   workspace: string,
   name?: string
-): void {
-  let url = `${API_BASE}/download/${connectionId}/${resourceType}/${workspace}`
-  if (name) {
-    url += `/${name}`
-  }
-  window.open(url, '_blank')
+): Promise<number> {
+  return downloadResources(connectionId, [{ type: resourceType, workspace, name: name || workspace }])
 }
 
 export function downloadShapefile(
@@ -922,6 +1393,20 @@ export async function deleteQGISProject(id: string): Promise<void> {
   return handleResponse<void>(response)
 }
 
+export async function publishQGISProject(
+  id: string,
+  connectionId: string,
+  workspace: string,
+  groupName?: string
+): Promise<QGISPublishResult> {
+  const response = await fetch(`${API_BASE}/qgis/projects/${id}/publish`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ connectionId, workspace, groupName }),
+  })
+  return handleResponse<QGISPublishResult>(response)
+}
+
 export async function getQGISProjectFile(id: string): Promise<Blob> {
   const response = await fetch(`${API_BASE}/qgis/projects/${id}/file`)
   if (!response.ok) {
@@ -8,6 +8,17 @@ import type {
   ConnectionCreate,
   TestConnectionResult,
   ServerInfo,
+  ServerCapabilities,
+  ServerPermissions,
+  SignInStatus,
+  InstalledModule,
+  CatalogLintReport,
+  ProbeResult,
+  CopyTarget,
+  CopyTargetQuery,
+  RecentItems,
+  ClusterAction,
+  ClusterActionResult,
 } from '../types'
 
 export async function getConnections(): Promise<Connection[]> {
@@ -65,3 +76,78 @@ export async function getServerInfo(id: string): Promise<ServerInfo> {
   const response = await fetch(`${API_BASE}/connections/${id}/info`)
   return handleResponse<ServerInfo>(response)
 }
+
+export async function startSignIn(id: string): Promise<SignInStatus> {
+  const response = await fetch(`${API_BASE}/connections/${id}/signin`, { method: 'POST' })
+  return handleResponse<SignInStatus>(response)
+}
+
+export async function getSignInStatus(id: string): Promise<SignInStatus> {
+  const response = await fetch(`${API_BASE}/connections/${id}/signin`)
+  return handleResponse<SignInStatus>(response)
+}
+
+export async function signOut(id: string): Promise<void> {
+  const response = await fetch(`${API_BASE}/connections/${id}/signin`, { method: 'DELETE' })
+  return handleResponse<void>(response)
+}
+
+export async function getServerCapabilities(id: string): Promise<ServerCapabilities> {
+  const response = await fetch(`${API_BASE}/connections/${id}/capabilities`)
+  return handleResponse<ServerCapabilities>(response)
+}
+
+export async function getServerPermissions(id: string): Promise<ServerPermissions> {
+  const response = await fetch(`${API_BASE}/connections/${id}/permissions`)
+  return handleResponse<ServerPermissions>(response)
+}
+
+export async function getInstalledModules(id: string): Promise<InstalledModule[]> {
+  const response = await fetch(`${API_BASE}/connections/${id}/extensions`)
+  const data = await handleResponse<{ modules: InstalledModule[] }>(response)
+  return data.modules
+}
+
+// An empty layer probes the first vector layer found
+export async function probeConnection(id: string, layer?: string): Promise<ProbeResult> {
+  const response = await fetch(`${API_BASE}/connections/${id}/probe`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ layer: layer || undefined }),
+  })
+  return handleResponse<ProbeResult>(response)
+}
+
+export async function lintCatalog(id: string, checks?: string[]): Promise<CatalogLintReport> {
+  const params = checks?.length ? `?checks=${checks.join(',')}` : ''
+  const response = await fetch(`${API_BASE}/lint/${id}${params}`)
+  return handleResponse<CatalogLintReport>(response)
+}
+
+export async function getCopyTargets(query: CopyTargetQuery): Promise<CopyTarget[]> {
+  const params = new URLSearchParams({ type: query.type })
+  if (query.workspace) params.set('workspace', query.workspace)
+  if (query.name) params.set('name', query.name)
+  if (query.store) params.set('store', query.store)
+  if (query.storeType) params.set('storeType', query.storeType)
+  const response = await fetch(`${API_BASE}/links/${query.connectionId}?${params}`)
+  return handleResponse<CopyTarget[]>(response)
+}
+
+export async function getRecentItems(id: string, limit?: number): Promise<RecentItems> {
+  const params = limit ? `?limit=${limit}` : ''
+  const response = await fetch(`${API_BASE}/recent/${id}${params}`)
+  return handleResponse<RecentItems>(response)
+}
+
+export async function runClusterAction(
+  id: string,
+  action: ClusterAction
+): Promise<ClusterActionResult> {
+  const response = await fetch(`${API_BASE}/cluster/${id}/${action}`, { method: 'POST' })
+  // A node failing answers 502, still with every node's result
+  if (response.status === 502) {
+    return response.json()
+  }
+  return handleResponse<ClusterActionResult>(response)
+}
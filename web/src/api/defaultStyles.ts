@@ -0,0 +1,20 @@
+/**
+ * Default styles of newly published layers API
+ */
+
+import { API_BASE, handleResponse } from './common'
+import type { DefaultStyleRule, DefaultStyles } from '../types'
+
+export async function getDefaultStyles(): Promise<DefaultStyles> {
+  const response = await fetch(`${API_BASE}/default-styles`)
+  return handleResponse<DefaultStyles>(response)
+}
+
+export async function updateDefaultStyles(rules: DefaultStyleRule[]): Promise<DefaultStyles> {
+  const response = await fetch(`${API_BASE}/default-styles`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ rules }),
+  })
+  return handleResponse<DefaultStyles>(response)
+}
@@ -0,0 +1,44 @@
+/**
+ * GeoFence data security rules API
+ */
+
+import { API_BASE, handleResponse } from './common'
+import type { GeofenceRule, GeofenceRules } from '../types'
+
+export async function getGeofenceRules(connId: string): Promise<GeofenceRules> {
+  const response = await fetch(`${API_BASE}/geofence/${connId}/rules`)
+  return handleResponse<GeofenceRules>(response)
+}
+
+export async function createGeofenceRule(
+  connId: string,
+  rule: Omit<GeofenceRule, 'id'>
+): Promise<{ id: number }> {
+  const response = await fetch(`${API_BASE}/geofence/${connId}/rules`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(rule),
+  })
+  return handleResponse<{ id: number }>(response)
+}
+
+// The id changes when a field the rule had is cleared, as the rule is replaced
+export async function updateGeofenceRule(
+  connId: string,
+  id: number,
+  rule: Omit<GeofenceRule, 'id'>
+): Promise<{ id: number }> {
+  const response = await fetch(`${API_BASE}/geofence/${connId}/rules/${id}`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(rule),
+  })
+  return handleResponse<{ id: number }>(response)
+}
+
+export async function deleteGeofenceRule(connId: string, id: number): Promise<void> {
+  const response = await fetch(`${API_BASE}/geofence/${connId}/rules/${id}`, {
+    method: 'DELETE',
+  })
+  return handleResponse<void>(response)
+}
@@ -0,0 +1,35 @@
+/**
+ * Event hooks API
+ */
+
+import { API_BASE, handleResponse } from './common'
+import type { EventHook, EventHookDelivery, EventHooks } from '../types'
+
+function csrfToken(): string {
+  return document.cookie.match(/csrftoken=([^;]+)/)?.[1] || ''
+}
+
+export async function getEventHooks(): Promise<EventHooks> {
+  const response = await fetch(`${API_BASE}/hooks/`, { credentials: 'include' })
+  return handleResponse<EventHooks>(response)
+}
+
+export async function updateEventHooks(hooks: EventHook[]): Promise<EventHooks> {
+  const response = await fetch(`${API_BASE}/hooks/`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json', 'X-CSRFToken': csrfToken() },
+    credentials: 'include',
+    body: JSON.stringify({ hooks }),
+  })
+  return handleResponse<EventHooks>(response)
+}
+
+export async function testEventHook(name: string, event?: string): Promise<EventHookDelivery> {
+  const response = await fetch(`${API_BASE}/hooks/${encodeURIComponent(name)}/test/`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json', 'X-CSRFToken': csrfToken() },
+    credentials: 'include',
+    body: JSON.stringify({ event }),
+  })
+  return handleResponse<EventHookDelivery>(response)
+}
@@ -5,6 +5,11 @@
  * - common.ts - Shared utilities and base configuration
  * - connection.ts - GeoServer connection API
  * - workspace.ts - Workspace API
+ * - naming.ts - Naming conventions API
+ * - hooks.ts - Event hooks API
+ * - defaultStyles.ts - Default styles of new layers API
+ * - operations.ts - Interrupted batch operations API
+ * - geofence.ts - GeoFence data security rules API
  * - stores.ts - DataStore and CoverageStore API
  * - layer.ts - Layer, FeatureType, Coverage API
  * - style.ts - Style API
@@ -21,6 +26,11 @@ export * from './common'
 export * from './chunkedUpload'
 export * from './connection'
 export * from './workspace'
+export * from './naming'
+export * from './hooks'
+export * from './defaultStyles'
+export * from './operations'
+export * from './geofence'
 export * from './stores'
 export * from './layer'
 export * from './style'
@@ -10,6 +10,20 @@ import type {
   LayerMetadataUpdate,
   FeatureType,
   Coverage,
+  BatchAction,
+  BatchResponse,
+  LayerQuotaUsage,
+  LayerRequestStats,
+  RenderBenchmark,
+  RenderBenchmarkRequest,
+  LayerEndpointReport,
+  LayerAttributeTable,
+  LayerAttributeQuery,
+  FeatureTypeSchema,
+  FilterAttribute,
+  FilterCondition,
+  FilterMatch,
+  FilterValidation,
 } from '../types'
 
 // Layer API
@@ -45,12 +59,169 @@ export async function getLayerFullMetadata(connId: string, workspace: string, na
   return handleResponse<LayerMetadata>(response)
 }
 
-export async function getLayerFeatureCount(connId: string, workspace: string, name: string): Promise<number> {
-  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/count`)
+export interface FeatureCountOptions {
+  cqlFilter?: string
+  bbox?: [number, number, number, number]
+  bboxSrs?: string
+}
+
+export async function getLayerFeatureCount(
+  connId: string,
+  workspace: string,
+  name: string,
+  options: FeatureCountOptions = {}
+): Promise<number> {
+  const params = new URLSearchParams()
+  if (options.cqlFilter) params.set('cqlFilter', options.cqlFilter)
+  if (options.bbox) params.set('bbox', options.bbox.join(','))
+  if (options.bboxSrs) params.set('bboxSrs', options.bboxSrs)
+  const query = params.toString() ? `?${params}` : ''
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/count${query}`)
   const data = await handleResponse<{ count: number }>(response)
   return data.count
 }
 
+export async function getLayerCacheUsage(
+  connId: string,
+  workspace: string,
+  name: string
+): Promise<LayerQuotaUsage> {
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/cache-usage`)
+  return handleResponse<LayerQuotaUsage>(response)
+}
+
+export async function getLayerRequestStats(
+  connId: string,
+  workspace: string,
+  name: string
+): Promise<LayerRequestStats> {
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/requests`)
+  return handleResponse<LayerRequestStats>(response)
+}
+
+// Times GetMap requests across zoom levels; takes as long as the renders do
+export async function runLayerBenchmark(
+  connId: string,
+  workspace: string,
+  name: string,
+  request: RenderBenchmarkRequest
+): Promise<RenderBenchmark> {
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/benchmark`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(request),
+  })
+  return handleResponse<RenderBenchmark>(response)
+}
+
+// Sends the layer's WMS, WFS, WMTS and legend endpoints a lightweight request each
+export async function verifyLayerEndpoints(
+  connId: string,
+  workspace: string,
+  name: string
+): Promise<LayerEndpointReport> {
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/verify`, {
+    method: 'POST',
+  })
+  return handleResponse<LayerEndpointReport>(response)
+}
+
+function attributeQueryParams(query: LayerAttributeQuery): URLSearchParams {
+  const params = new URLSearchParams()
+  if (query.sort) {
+    params.set('sort', query.sort)
+    params.set('order', query.order ?? 'asc')
+  }
+  for (const [field, value] of Object.entries(query.filters ?? {})) {
+    if (value.trim()) params.set(`filter.${field}`, value)
+  }
+  if (query.cql?.trim()) params.set('cql', query.cql.trim())
+  return params
+}
+
+export async function getLayerAttributes(
+  connId: string,
+  workspace: string,
+  name: string,
+  limit: number,
+  offset: number,
+  query: LayerAttributeQuery = {}
+): Promise<LayerAttributeTable> {
+  const params = attributeQueryParams(query)
+  params.set('limit', String(limit))
+  params.set('offset', String(offset))
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/attributes?${params}`)
+  return handleResponse<LayerAttributeTable>(response)
+}
+
+export function getLayerAttributesExportUrl(
+  connId: string,
+  workspace: string,
+  name: string,
+  query: LayerAttributeQuery = {}
+): string {
+  return `${API_BASE}/layers/${connId}/${workspace}/${name}/attributes/export?${attributeQueryParams(query)}`
+}
+
+// Attribute names, types and nullability, with the geometry type and SRS
+export async function getLayerSchema(connId: string, workspace: string, name: string): Promise<FeatureTypeSchema> {
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/schema`)
+  return handleResponse<FeatureTypeSchema>(response)
+}
+
+// Publish only the named attributes; the geometry must be among them
+export async function updateLayerAttributes(
+  connId: string,
+  workspace: string,
+  name: string,
+  attributes: string[]
+): Promise<FeatureTypeSchema> {
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/schema`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ attributes }),
+  })
+  return handleResponse<FeatureTypeSchema>(response)
+}
+
+// CQL filter builder API
+export async function getFilterAttributes(
+  connId: string,
+  workspace: string,
+  name: string
+): Promise<FilterAttribute[]> {
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/filter/attributes`)
+  const data = await handleResponse<{ attributes: FilterAttribute[] }>(response)
+  return data.attributes
+}
+
+export async function getFilterValues(
+  connId: string,
+  workspace: string,
+  name: string,
+  attribute: string,
+  prefix = ''
+): Promise<string[]> {
+  const params = new URLSearchParams({ attribute, prefix })
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/filter/values?${params}`)
+  const data = await handleResponse<{ values: string[] }>(response)
+  return data.values
+}
+
+export async function validateFilter(
+  connId: string,
+  workspace: string,
+  name: string,
+  filter: { cql?: string; conditions?: FilterCondition[]; match?: FilterMatch }
+): Promise<FilterValidation> {
+  const response = await fetch(`${API_BASE}/layers/${connId}/${workspace}/${name}/filter/validate`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(filter),
+  })
+  return handleResponse<FilterValidation>(response)
+}
+
 export async function updateLayerMetadata(
   connId: string,
   workspace: string,
@@ -71,11 +242,17 @@ export async function getFeatureTypes(connId: string, workspace: string, store:
   return handleResponse<FeatureType[]>(response)
 }
 
-export async function publishFeatureType(connId: string, workspace: string, store: string, name: string): Promise<FeatureType> {
+export async function publishFeatureType(
+  connId: string,
+  workspace: string,
+  store: string,
+  name: string,
+  attributes?: string[] // Publish only these attributes
+): Promise<FeatureType> {
   const response = await fetch(`${API_BASE}/featuretypes/${connId}/${workspace}/${store}`, {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
-    body: JSON.stringify({ name }),
+    body: JSON.stringify({ name, attributes }),
   })
   return handleResponse<FeatureType>(response)
 }
@@ -94,3 +271,18 @@ export async function publishCoverage(connId: string, workspace: string, store:
   })
   return handleResponse<Coverage>(response)
 }
+
+// Batch actions on several layers
+export async function runLayerBatch(
+  connId: string,
+  action: BatchAction,
+  layers: string[],
+  options: Record<string, unknown> = {}
+): Promise<BatchResponse> {
+  const response = await fetch(`${API_BASE}/batch/${connId}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ action, layers, options }),
+  })
+  return handleResponse<BatchResponse>(response)
+}
@@ -57,3 +57,25 @@ export async function deleteLayerGroup(connId: string, workspace: string, name:
   })
   return handleResponse<void>(response)
 }
+
+/**
+ * Draw layers over each other as a draft layer group would, the first at the bottom.
+ * Returns a PNG image.
+ */
+export async function previewLayerGroup(
+  connId: string,
+  workspace: string,
+  layers: string[],
+  width: number,
+  height: number
+): Promise<Blob> {
+  const response = await fetch(`${API_BASE}/layergrouppreview/${connId}/${workspace}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ layers, width, height }),
+  })
+  if (!response.ok) {
+    await handleResponse(response)
+  }
+  return response.blob()
+}
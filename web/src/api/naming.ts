@@ -0,0 +1,24 @@
+/**
+ * Naming conventions API
+ */
+
+import { API_BASE, handleResponse } from './common'
+import type { NameCheck, NamingConventions, NamingResourceType } from '../types'
+
+export async function getNamingConventions(): Promise<NamingConventions> {
+  const response = await fetch(`${API_BASE}/naming-conventions`)
+  return handleResponse<NamingConventions>(response)
+}
+
+export async function checkName(
+  resourceType: NamingResourceType,
+  name: string,
+  workspace?: string
+): Promise<NameCheck> {
+  const response = await fetch(`${API_BASE}/naming-conventions`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ resourceType, name, workspace }),
+  })
+  return handleResponse<NameCheck>(response)
+}
@@ -0,0 +1,44 @@
+/**
+ * Interrupted batch operations API
+ */
+
+import { API_BASE, handleResponse } from './common'
+import type { InterruptedOperation, MassTruncateJob } from '../types'
+
+export async function getInterruptedOperations(
+  connectionId?: string
+): Promise<InterruptedOperation[]> {
+  const params = connectionId ? `?connection=${encodeURIComponent(connectionId)}` : ''
+  const response = await fetch(`${API_BASE}/operations/${params}`)
+  return (await handleResponse<{ operations: InterruptedOperation[] }>(response)).operations
+}
+
+export async function discardOperation(id: string): Promise<void> {
+  const response = await fetch(`${API_BASE}/operations/${encodeURIComponent(id)}/`, {
+    method: 'DELETE',
+  })
+  return handleResponse<void>(response)
+}
+
+// Truncates the layers an interrupted mass truncation didn't get to
+export async function resumeMassTruncate(
+  connId: string,
+  operationId: string
+): Promise<MassTruncateJob> {
+  const response = await fetch(`${API_BASE}/gwc/masstruncate/${connId}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ resume: operationId }),
+  })
+  return handleResponse<MassTruncateJob>(response)
+}
+
+// Syncs what an interrupted sync didn't
+export async function resumeSync(operationId: string): Promise<{ jobId: string }> {
+  const response = await fetch(`${API_BASE}/sync/start`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ resumeId: operationId }),
+  })
+  return handleResponse<{ jobId: string }>(response)
+}
@@ -15,6 +15,8 @@ import type {
   DuckDBTableInfo,
   DuckDBQueryRequest,
   DuckDBQueryResponse,
+  STACGenerateRequest,
+  STACGenerateResult,
 } from '../types'
 
 // S3 Connection API
@@ -307,6 +309,27 @@ export async function getS3PresignedURL(
   return handleResponse<{ url: string; expires: string }>(response)
 }
 
+// Generate a static STAC catalog for a bucket prefix and write it into the bucket
+export async function generateS3STACCatalog(
+  connectionId: string,
+  bucketName: string,
+  request: STACGenerateRequest = {}
+): Promise<STACGenerateResult> {
+  const response = await fetch(`${API_BASE}/s3/stac/${connectionId}/${bucketName}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(request),
+  })
+  return handleResponse<STACGenerateResult>(response)
+}
+
+// URL of the dynamically served STAC catalog for a bucket prefix
+export function getS3STACCatalogURL(connectionId: string, bucketName: string, prefix = ''): string {
+  const cleanPrefix = prefix.replace(/^\/+|\/+$/g, '')
+  const path = cleanPrefix ? `${cleanPrefix}/catalog.json` : 'catalog.json'
+  return `${API_BASE}/stac/${connectionId}/${bucketName}/${path}`
+}
+
 // Backward compatibility aliases
 export const getDuckDBTableInfo = getS3DuckDBTableInfo
 export const executeDuckDBQuery = executeS3DuckDBQuery
@@ -8,17 +8,22 @@ import type {
   CoverageStore,
   DataStoreCreate,
   CoverageStoreCreate,
+  LayerNamingRules,
 } from '../types'
 
 // Data Store API
-export async function getDataStores(connId: string, workspace: string): Promise<DataStore[]> {
-  const response = await fetch(`${API_BASE}/datastores/${connId}/${workspace}`)
+// withEnabled reads every store for its enabled flag; tree listings leave it off
+export async function getDataStores(connId: string, workspace: string, withEnabled = false): Promise<DataStore[]> {
+  const params = withEnabled ? '?enabled=true' : ''
+  const response = await fetch(`${API_BASE}/datastores/${connId}/${workspace}${params}`)
   return handleResponse<DataStore[]>(response)
 }
 
+// Passwords among the connection parameters come back masked
 export async function getDataStore(connId: string, workspace: string, name: string): Promise<DataStore> {
   const response = await fetch(`${API_BASE}/datastores/${connId}/${workspace}/${name}`)
-  return handleResponse<DataStore>(response)
+  const result = await handleResponse<{ dataStore: DataStore }>(response)
+  return result.dataStore
 }
 
 export async function createDataStore(connId: string, workspace: string, store: DataStoreCreate): Promise<DataStore> {
@@ -48,19 +53,39 @@ export async function publishFeatureTypes(
   connId: string,
   workspace: string,
   store: string,
-  featureTypes: string[]
+  featureTypes: string[],
+  naming?: LayerNamingRules
 ): Promise<{ published: string[]; errors: string[] }> {
   const response = await fetch(`${API_BASE}/datastores/${connId}/${workspace}/${store}/publish`, {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
-    body: JSON.stringify({ featureTypes }),
+    body: JSON.stringify({ featureTypes, naming }),
   })
   return handleResponse<{ published: string[]; errors: string[] }>(response)
 }
 
+// Layer names the tables would be published under, keyed by table
+export async function previewLayerNames(
+  connId: string,
+  workspace: string,
+  store: string,
+  featureTypes: string[],
+  naming: LayerNamingRules
+): Promise<Record<string, string>> {
+  const response = await fetch(`${API_BASE}/datastores/${connId}/${workspace}/${store}/publish`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ featureTypes, naming, dryRun: true }),
+  })
+  const result = await handleResponse<{ names: Record<string, string> }>(response)
+  return result.names
+}
+
 // Coverage Store API
-export async function getCoverageStores(connId: string, workspace: string): Promise<CoverageStore[]> {
-  const response = await fetch(`${API_BASE}/coveragestores/${connId}/${workspace}`)
+// withEnabled reads every store for its enabled flag; tree listings leave it off
+export async function getCoverageStores(connId: string, workspace: string, withEnabled = false): Promise<CoverageStore[]> {
+  const params = withEnabled ? '?enabled=true' : ''
+  const response = await fetch(`${API_BASE}/coveragestores/${connId}/${workspace}${params}`)
   return handleResponse<CoverageStore[]>(response)
 }
 
@@ -3,19 +3,35 @@
  */
 
 import { API_BASE, handleResponse } from './common'
-import type { Style } from '../types'
+import type { Style, GWCStyleLayer } from '../types'
 
 export async function getStyles(connId: string, workspace: string): Promise<Style[]> {
   const response = await fetch(`${API_BASE}/styles/${connId}/${workspace}`)
   return handleResponse<Style[]>(response)
 }
 
-export async function deleteStyle(connId: string, workspace: string, name: string, purge = false): Promise<void> {
-  const params = purge ? '?purge=true' : ''
-  const response = await fetch(`${API_BASE}/styles/${connId}/${workspace}/${name}${params}`, {
+// Layers that used a deleted style; absent when no layer used it
+export interface StyleDeleteResult {
+  affectedLayers: GWCStyleLayer[]
+  reassignedLayers: string[]
+  truncatedLayers: string[]
+}
+
+export async function deleteStyle(
+  connId: string,
+  workspace: string,
+  name: string,
+  purge = false,
+  reassignTo?: string
+): Promise<StyleDeleteResult | undefined> {
+  const params = new URLSearchParams()
+  if (purge) params.set('purge', 'true')
+  if (reassignTo) params.set('reassignTo', reassignTo)
+  const query = params.toString() ? `?${params}` : ''
+  const response = await fetch(`${API_BASE}/styles/${connId}/${workspace}/${name}${query}`, {
     method: 'DELETE',
   })
-  return handleResponse<void>(response)
+  return handleResponse<StyleDeleteResult | undefined>(response)
 }
 
 // Layer styles association
@@ -50,26 +66,39 @@ export interface StyleContent {
   workspace: string
   format: 'sld' | 'css' | 'mbstyle'
   content: string
+  sldVersion: SldVersion | null // Null for CSS and MapBox styles
 }
 
+// SLD 1.0, or SLD 1.1 with Symbology Encoding
+export type SldVersion = '1.0.0' | '1.1.0'
+
 export async function getStyleContent(connId: string, workspace: string, name: string): Promise<StyleContent> {
   const response = await fetch(`${API_BASE}/styles/${connId}/${workspace}/${name}`)
   return handleResponse<StyleContent>(response)
 }
 
+// Layers whose tile caches are affected by a style update
+export interface StyleUpdateResult {
+  message: string
+  affectedLayers: GWCStyleLayer[]
+  truncatedLayers: string[]
+  missingGraphics?: string[] // Referenced by a style package's SLD but not in the zip
+}
+
 export async function updateStyleContent(
   connId: string,
   workspace: string,
   name: string,
   content: string,
-  format: 'sld' | 'css' | 'mbstyle' = 'sld'
-): Promise<StyleContent> {
+  format: 'sld' | 'css' | 'mbstyle' = 'sld',
+  sldVersion?: SldVersion // SLDs are converted to it; kept in their own version when omitted
+): Promise<StyleUpdateResult> {
   const response = await fetch(`${API_BASE}/styles/${connId}/${workspace}/${name}`, {
     method: 'PUT',
     headers: { 'Content-Type': 'application/json' },
-    body: JSON.stringify({ content, format }),
+    body: JSON.stringify({ content, format, sldVersion }),
   })
-  return handleResponse<StyleContent>(response)
+  return handleResponse<StyleUpdateResult>(response)
 }
 
 export async function createStyle(
@@ -77,12 +106,193 @@ export async function createStyle(
   workspace: string,
   name: string,
   content: string,
-  format: 'sld' | 'css' | 'mbstyle' = 'sld'
+  format: 'sld' | 'css' | 'mbstyle' = 'sld',
+  sldVersion?: SldVersion
+): Promise<StyleContent> {
+  const response = await fetch(`${API_BASE}/styles/${connId}/${workspace}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ name, content, format, sldVersion }),
+  })
+  return handleResponse<StyleContent>(response)
+}
+
+// Styles kept in Git can be created or updated by URL; the server downloads
+// them, sending the authorization header for private repositories. GitHub
+// and GitLab file pages are turned into their raw file URLs.
+export async function createStyleFromUrl(
+  connId: string,
+  workspace: string,
+  url: string,
+  name?: string,
+  authorization?: string
 ): Promise<StyleContent> {
   const response = await fetch(`${API_BASE}/styles/${connId}/${workspace}`, {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
-    body: JSON.stringify({ name, content, format }),
+    body: JSON.stringify({ url, name: name || undefined, authorization: authorization || undefined }),
   })
   return handleResponse<StyleContent>(response)
 }
+
+export async function updateStyleFromUrl(
+  connId: string,
+  workspace: string,
+  name: string,
+  url: string,
+  authorization?: string
+): Promise<StyleUpdateResult> {
+  const response = await fetch(`${API_BASE}/styles/${connId}/${workspace}/${name}`, {
+    method: 'PUT',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ url, authorization: authorization || undefined }),
+  })
+  return handleResponse<StyleUpdateResult>(response)
+}
+
+// Outcome of importing one file of a folder of styles
+export interface StyleImportResult {
+  path: string
+  name: string
+  status: 'created' | 'updated' | 'skipped' | 'failed'
+  error?: string
+  warning?: string // E.g. graphics a style package's SLD refers to but doesn't hold
+}
+
+export interface StyleImportResponse {
+  results: StyleImportResult[]
+  counts: Record<StyleImportResult['status'], number>
+}
+
+// Styles are named after their files; unchanged ones are skipped.
+// An empty workspace imports into the global styles.
+export async function importStyles(
+  connId: string,
+  workspace: string,
+  files: File[]
+): Promise<StyleImportResponse> {
+  const formData = new FormData()
+  files.forEach((file) => formData.append('files', file))
+  formData.append('workspace', workspace)
+  const response = await fetch(`${API_BASE}/styleimport/${connId}`, {
+    method: 'POST',
+    body: formData,
+  })
+  return handleResponse<StyleImportResponse>(response)
+}
+
+// What replacing one version of a style with another would change.
+// The old side is the style there now: the server's, or the sync destination's.
+export interface StyleDiff {
+  name: string
+  workspace: string | null
+  from: string
+  to: string
+  fromFormat: string | null // null when the style doesn't exist yet
+  toFormat: string
+  fromHash: string | null // SHA-256 of the content, as managed resources record it
+  toHash: string
+  status: 'new' | 'changed' | 'unchanged'
+  additions: number
+  deletions: number
+  diff: string // Unified diff
+}
+
+// Diffs the server's styles against files about to be imported over them
+export async function diffStyleFiles(
+  connId: string,
+  workspace: string,
+  files: File[]
+): Promise<StyleDiff[]> {
+  const formData = new FormData()
+  files.forEach((file) => formData.append('files', file))
+  formData.append('workspace', workspace)
+  const response = await fetch(`${API_BASE}/stylediff/${connId}`, {
+    method: 'POST',
+    body: formData,
+  })
+  return (await handleResponse<{ diffs: StyleDiff[] }>(response)).diffs
+}
+
+// Diffs another connection's styles against the source's a sync would copy over them.
+// An empty workspace compares the global styles.
+export async function diffConnectionStyles(
+  connId: string,
+  against: string,
+  workspace = '',
+  name?: string
+): Promise<StyleDiff[]> {
+  const params = new URLSearchParams({ against })
+  if (workspace) params.set('workspace', workspace)
+  if (name) params.set('name', name)
+  const response = await fetch(`${API_BASE}/stylediff/${connId}?${params}`)
+  return (await handleResponse<{ diffs: StyleDiff[] }>(response)).diffs
+}
+
+// A graphic in a styles folder; paths are relative to the folder
+export interface StyleAsset {
+  path: string
+  size: number
+  styles: string[] // Styles drawing with it
+}
+
+// A style's graphic reference that no asset answers, or an absolute path
+// that won't exist on other servers
+export interface UnresolvedGraphic {
+  style: string
+  href: string
+  reason: 'missing' | 'absolute'
+}
+
+export interface StyleAssetListing {
+  folder: string
+  assets: StyleAsset[]
+  unresolved: UnresolvedGraphic[]
+  complete: boolean
+}
+
+export interface StyleAssetMove {
+  source: string
+  target: string
+  updated: string[]
+  failed: Record<string, string>
+}
+
+// An empty workspace stands for the global styles folder
+export async function getStyleAssets(connId: string, workspace: string): Promise<StyleAssetListing> {
+  const query = workspace ? `?workspace=${encodeURIComponent(workspace)}` : ''
+  const response = await fetch(`${API_BASE}/styleassets/${connId}${query}`)
+  return handleResponse<StyleAssetListing>(response)
+}
+
+export async function uploadStyleAssets(
+  connId: string,
+  workspace: string,
+  files: File[],
+  folder = ''
+): Promise<{ uploaded: string[] }> {
+  const formData = new FormData()
+  files.forEach((file) => formData.append('files', file))
+  formData.append('workspace', workspace)
+  formData.append('folder', folder)
+  const response = await fetch(`${API_BASE}/styleassets/${connId}`, {
+    method: 'POST',
+    body: formData,
+  })
+  return handleResponse<{ uploaded: string[] }>(response)
+}
+
+// Styles referring to the graphic are rewritten to its new path
+export async function moveStyleAsset(
+  connId: string,
+  workspace: string,
+  source: string,
+  target: string
+): Promise<StyleAssetMove> {
+  const response = await fetch(`${API_BASE}/styleassets/${connId}/move`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ workspace, source, target }),
+  })
+  return handleResponse<StyleAssetMove>(response)
+}
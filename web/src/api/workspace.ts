@@ -3,7 +3,17 @@
  */
 
 import { API_BASE, handleResponse } from './common'
-import type { Workspace, WorkspaceConfig } from '../types'
+import type {
+  Workspace,
+  WorkspaceConfig,
+  WorkspaceImpact,
+  WorkspaceTemplate,
+  WorkspaceCreateResult,
+  BackupKind,
+  BackupExecution,
+  BackupJobOptions,
+  BackupJobRequest,
+} from '../types'
 
 export async function getWorkspaces(connId: string): Promise<Workspace[]> {
   const response = await fetch(`${API_BASE}/workspaces/${connId}`)
@@ -15,13 +25,21 @@ export async function getWorkspace(connId: string, name: string): Promise<Worksp
   return handleResponse<WorkspaceConfig>(response)
 }
 
-export async function createWorkspace(connId: string, config: WorkspaceConfig): Promise<Workspace> {
+export async function createWorkspace(
+  connId: string,
+  config: WorkspaceConfig
+): Promise<WorkspaceCreateResult> {
   const response = await fetch(`${API_BASE}/workspaces/${connId}`, {
     method: 'POST',
     headers: { 'Content-Type': 'application/json' },
     body: JSON.stringify(config),
   })
-  return handleResponse<Workspace>(response)
+  return handleResponse<WorkspaceCreateResult>(response)
+}
+
+export async function getWorkspaceTemplates(): Promise<WorkspaceTemplate[]> {
+  const response = await fetch(`${API_BASE}/workspace-templates`)
+  return handleResponse<WorkspaceTemplate[]>(response)
 }
 
 export async function updateWorkspace(connId: string, name: string, config: WorkspaceConfig): Promise<WorkspaceConfig> {
@@ -40,3 +58,51 @@ export async function deleteWorkspace(connId: string, name: string, recurse = fa
   })
   return handleResponse<void>(response)
 }
+
+export async function getWorkspaceImpact(connId: string, name: string): Promise<WorkspaceImpact> {
+  const response = await fetch(`${API_BASE}/workspaces/${connId}/${name}/impact`)
+  return handleResponse<WorkspaceImpact>(response)
+}
+
+// Backup and Restore plugin: server-side archives of workspaces
+
+export async function getBackupJobOptions(
+  connId: string,
+  kind: BackupKind,
+  workspaces: string[] = [],
+): Promise<BackupJobOptions> {
+  const params = new URLSearchParams({ workspaces: workspaces.join(',') })
+  const response = await fetch(`${API_BASE}/br/${connId}/${kind}?${params}`)
+  return handleResponse<BackupJobOptions>(response)
+}
+
+export async function startBackupJob(
+  connId: string,
+  kind: BackupKind,
+  request: BackupJobRequest,
+): Promise<BackupExecution> {
+  const response = await fetch(`${API_BASE}/br/${connId}/${kind}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(request),
+  })
+  return handleResponse<BackupExecution>(response)
+}
+
+export async function getBackupExecution(
+  connId: string,
+  kind: BackupKind,
+  id: number,
+): Promise<BackupExecution> {
+  const response = await fetch(`${API_BASE}/br/${connId}/${kind}/${id}`)
+  return handleResponse<BackupExecution>(response)
+}
+
+export async function cancelBackupJob(connId: string, kind: BackupKind, id: number): Promise<void> {
+  const response = await fetch(`${API_BASE}/br/${connId}/${kind}/${id}`, { method: 'DELETE' })
+  return handleResponse<void>(response)
+}
+
+export function getBackupArchiveUrl(connId: string, id: number): string {
+  return `${API_BASE}/br/${connId}/backup/${id}/archive`
+}
@@ -38,10 +38,13 @@ import {
   FiEye,
   FiEyeOff,
   FiSettings,
+  FiColumns,
+  FiGrid,
+  FiFolder,
 } from 'react-icons/fi'
 import { SiPostgresql } from 'react-icons/si'
 import * as api from '../api'
-import type { ServerStatus } from '../types'
+import type { NodeHealth, ServerStatus } from '../types'
 import { useUIStore } from '../stores/uiStore'
 import { useTreeStore } from '../stores/treeStore'
 
@@ -320,6 +323,167 @@ function PGServiceCard({ service, onParse, isParsing, onToggleHidden, isToggling
   )
 }
 
+// Storage figures are slow to collect, so they refresh less often than the status
+const STORAGE_STALE_MS = 5 * 60 * 1000
+
+function StorageSection({ connectionId }: { connectionId: string }) {
+  const { data: storage, isLoading } = useQuery({
+    queryKey: ['dashboard-storage', connectionId],
+    queryFn: () => api.getConnectionStorage(connectionId),
+    staleTime: STORAGE_STALE_MS,
+    refetchInterval: STORAGE_STALE_MS,
+  })
+
+  if (isLoading) {
+    return (
+      <HStack spacing={2} fontSize="xs" color="gray.500">
+        <Spinner size="xs" />
+        <Text>Loading storage...</Text>
+      </HStack>
+    )
+  }
+  if (!storage) return null
+
+  const quota = storage.diskQuota
+  const dataDir = storage.dataDirectory
+  const seeds = storage.seedTasks
+
+  return (
+    <VStack align="start" spacing={2} w="100%">
+      {/* Tile cache disk quota */}
+      <Box w="100%">
+        <HStack justify="space-between" mb={1}>
+          <HStack spacing={1}>
+            <Icon as={FiGrid} boxSize={3} color="gray.500" />
+            <Text fontSize="xs" color="gray.500">Tile cache</Text>
+          </HStack>
+          <Tooltip label={storage.diskQuotaError || quota?.expirationPolicy || ''}>
+            <Text fontSize="xs" color="gray.600">
+              {!quota
+                ? 'Unavailable'
+                : !quota.enabled
+                  ? 'Quota disabled'
+                  : `${quota.usedBytes !== null ? formatBytes(quota.usedBytes) : '?'} / ${
+                      quota.limitBytes !== null ? formatBytes(quota.limitBytes) : 'no limit'
+                    }`}
+            </Text>
+          </Tooltip>
+        </HStack>
+        {quota?.usedPct != null && (
+          <Progress
+            value={quota.usedPct}
+            size="sm"
+            colorScheme={quota.usedPct > 90 ? 'red' : quota.usedPct > 75 ? 'yellow' : 'green'}
+            borderRadius="full"
+          />
+        )}
+        {quota && quota.layers.length > 0 && (
+          <Tooltip
+            label={quota.layers
+              .map((l) => `${l.layer}: ${l.usedBytes !== null ? formatBytes(l.usedBytes) : '?'}`
+                + ` / ${l.limitBytes !== null ? formatBytes(l.limitBytes) : 'no limit'}`)
+              .join('\n')}
+          >
+            <Text fontSize="xs" color="gray.500">
+              {quota.layers.length} layer quota{quota.layers.length === 1 ? '' : 's'}
+            </Text>
+          </Tooltip>
+        )}
+      </Box>
+
+      <SimpleGrid columns={2} spacing={2} w="100%" fontSize="xs" color="gray.600">
+        {/* Seed tasks */}
+        <Tooltip label={storage.seedTasksError || `${seeds?.pending ?? 0} pending`}>
+          <HStack spacing={1}>
+            <Icon as={FiActivity} boxSize={3} color="gray.500" />
+            <Text>
+              {seeds ? `${seeds.running} seeding` : 'Seeding unknown'}
+            </Text>
+          </HStack>
+        </Tooltip>
+
+        {/* Data directory size */}
+        <Tooltip
+          label={
+            storage.dataDirectoryError
+            || (dataDir && `${dataDir.files} files${dataDir.complete ? '' : ', walk stopped early'}`)
+          }
+        >
+          <HStack spacing={1}>
+            <Icon as={FiFolder} boxSize={3} color="gray.500" />
+            <Text>
+              {dataDir
+                ? `${dataDir.complete ? '' : '≥ '}${formatBytes(dataDir.bytes)}`
+                : 'Data dir n/a'}
+            </Text>
+          </HStack>
+        </Tooltip>
+      </SimpleGrid>
+    </VStack>
+  )
+}
+
+function memoryColor(pct: number): string {
+  return pct > 80 ? 'red' : pct > 60 ? 'yellow' : 'green'
+}
+
+// One row per node of a cluster connection, primary first
+function ClusterNodes({ nodes }: { nodes: NodeHealth[] }) {
+  const online = nodes.filter((node) => node.online).length
+  return (
+    <Box w="100%">
+      <HStack spacing={1} mb={1}>
+        <Icon as={FiServer} boxSize={3} color="gray.500" />
+        <Text fontSize="xs" color="gray.500">
+          Nodes: {online} of {nodes.length} online
+        </Text>
+      </HStack>
+      <VStack align="stretch" spacing={1}>
+        {nodes.map((node) => (
+          <HStack key={node.url} spacing={2} fontSize="xs">
+            <Tooltip label={node.online ? node.statusError || 'Online' : node.error || 'Offline'}>
+              <span>
+                <Icon
+                  as={node.online ? FiCheckCircle : FiXCircle}
+                  color={node.online ? 'green.500' : 'red.500'}
+                  boxSize={3}
+                />
+              </span>
+            </Tooltip>
+            <Text flex={1} noOfLines={1} title={node.url}>
+              {node.url.replace(/^https?:\/\//, '')}
+            </Text>
+            {node.primary && (
+              <Badge fontSize="2xs" colorScheme="purple">
+                primary
+              </Badge>
+            )}
+            {node.online && (
+              <>
+                {node.memoryTotal > 0 && (
+                  <Tooltip
+                    label={`Memory ${formatBytes(node.memoryUsed)} / ${formatBytes(node.memoryTotal)}, CPU ${node.cpuLoad}%`}
+                  >
+                    <Box w="50px">
+                      <Progress
+                        value={node.memoryUsedPct}
+                        size="xs"
+                        colorScheme={memoryColor(node.memoryUsedPct)}
+                        borderRadius="full"
+                      />
+                    </Box>
+                  </Tooltip>
+                )}
+                <Text color="gray.500">{node.responseTimeMs}ms</Text>
+              </>
+            )}
+          </HStack>
+        ))}
+      </VStack>
+    </Box>
+  )
+}
+
 interface ServerCardProps {
   server: ServerStatus
   isAlert?: boolean
@@ -371,11 +535,30 @@ function ServerCard({ server, isAlert = false }: ServerCardProps) {
         <HStack>
           <Icon as={FiServer} color="kartoza.500" boxSize={6} />
           <VStack align="start" spacing={0}>
-            <Text fontWeight="bold" fontSize="lg" noOfLines={1}>
-              {server.connectionName}
-            </Text>
+            <HStack spacing={2}>
+              {server.badge && (
+                <Text fontWeight="bold" fontSize="lg" color={server.badgeColor}>
+                  {server.badge}
+                </Text>
+              )}
+              <Text fontWeight="bold" fontSize="lg" noOfLines={1}>
+                {server.connectionName}
+              </Text>
+              {server.environment && (
+                <Badge
+                  fontSize="2xs"
+                  borderRadius="full"
+                  px={2}
+                  color="white"
+                  bg={server.badgeColor || 'gray.500'}
+                  textTransform="lowercase"
+                >
+                  {server.environment}
+                </Badge>
+              )}
+            </HStack>
             <Text fontSize="xs" color="gray.500" noOfLines={1}>
-              {server.url}
+              {server.group ? `${server.group} · ${server.url}` : server.url}
             </Text>
           </VStack>
         </HStack>
@@ -431,12 +614,16 @@ function ServerCard({ server, isAlert = false }: ServerCardProps) {
                 <Progress
                   value={server.memoryUsedPct}
                   size="sm"
-                  colorScheme={server.memoryUsedPct > 80 ? 'red' : server.memoryUsedPct > 60 ? 'yellow' : 'green'}
+                  colorScheme={memoryColor(server.memoryUsedPct)}
                   borderRadius="full"
                 />
               </Box>
             )}
 
+            {server.nodes && <ClusterNodes nodes={server.nodes} />}
+
+            <StorageSection connectionId={server.connectionId} />
+
             {/* Response time with sparkline */}
             <HStack spacing={2} fontSize="xs" color="gray.500" w="100%">
               <Icon as={FiClock} boxSize={3} />
@@ -623,6 +810,11 @@ export default function Dashboard() {
             <Icon as={FiActivity} boxSize={5} color="white" />
           </Box>
           <Text fontSize="xl" fontWeight="bold">Server Dashboard</Text>
+          {data?.profile && data.profile !== 'default' && (
+            <Badge colorScheme="whiteAlpha" variant="solid">
+              Profile: {data.profile}
+            </Badge>
+          )}
         </HStack>
         <HStack spacing={4}>
           {/* Summary stats */}
@@ -656,6 +848,18 @@ export default function Dashboard() {
               <Text color="whiteAlpha.800">{data?.pingIntervalSecs || 60}s</Text>
             </HStack>
           </HStack>
+          {(data?.servers.length || 0) > 1 && (
+            <Tooltip label="Compare servers">
+              <IconButton
+                aria-label="Compare servers"
+                icon={<FiColumns />}
+                variant="ghost"
+                color="white"
+                _hover={{ bg: 'whiteAlpha.200' }}
+                onClick={() => openDialog('compare')}
+              />
+            </Tooltip>
+          )}
           <Tooltip label="Settings">
             <IconButton
               aria-label="Settings"
@@ -22,11 +22,13 @@ import {
   MenuItem,
   Divider,
 } from '@chakra-ui/react'
-import { FiInfo, FiRefreshCw, FiX, FiDroplet, FiBox, FiGlobe, FiMap, FiChevronDown } from 'react-icons/fi'
+import { FiInfo, FiRefreshCw, FiX, FiDroplet, FiBox, FiGlobe, FiMap, FiChevronDown, FiFilter, FiCrop } from 'react-icons/fi'
 import maplibregl from 'maplibre-gl'
 import 'maplibre-gl/dist/maplibre-gl.css'
 import * as api from '../api'
 import { useUIStore } from '../stores/uiStore'
+import { serviceUrl } from '../utils/ows'
+import { CqlFilterBuilder } from './common'
 
 interface MapPreviewProps {
   previewUrl: string | null
@@ -64,6 +66,9 @@ interface LayerInfo {
   store_name: string
   store_type: string
   geoserver_url: string
+  wms_url?: string
+  data_url?: string
+  proxy_url?: string // OGC requests through the backend, which adds the credentials
   type: string
   use_cache: boolean
   grid_set?: string
@@ -87,7 +92,7 @@ function StyleLegendIcon({
   size?: number
 }) {
   const [hasError, setHasError] = useState(false)
-  const legendUrl = `${geoserverUrl}/${workspace}/wms?SERVICE=WMS&VERSION=1.1.1&REQUEST=GetLegendGraphic&LAYER=${workspace}:${layerName}&STYLE=${styleName}&FORMAT=image/png&WIDTH=${size}&HEIGHT=${size}&LEGEND_OPTIONS=forceLabels:off;fontAntiAliasing:true`
+  const legendUrl = `${serviceUrl(geoserverUrl, 'wms', workspace)}?SERVICE=WMS&VERSION=1.1.1&REQUEST=GetLegendGraphic&LAYER=${workspace}:${layerName}&STYLE=${styleName}&FORMAT=image/png&WIDTH=${size}&HEIGHT=${size}&LEGEND_OPTIONS=forceLabels:off;fontAntiAliasing:true`
 
   if (hasError) {
     return <Icon as={FiDroplet} color="pink.500" boxSize={`${size}px`} />
@@ -136,6 +141,11 @@ export default function MapPreview({
   const [availableStyles, setAvailableStyles] = useState<string[]>([])
   const [currentStyle, setCurrentStyle] = useState<string>('')
   const [defaultStyle, setDefaultStyle] = useState<string>('')
+  const [cqlFilter, setCqlFilter] = useState('')
+  const [showFilter, setShowFilter] = useState(false)
+  // Area drawn for a bounded seed, in lon/lat
+  const [drawingArea, setDrawingArea] = useState(false)
+  const [seedArea, setSeedArea] = useState<[number, number, number, number] | null>(null)
 
   const cardBg = useColorModeValue('white', 'gray.800')
   const borderColor = useColorModeValue('gray.200', 'gray.600')
@@ -143,6 +153,7 @@ export default function MapPreview({
 
   // Store for switching to 3D Globe preview
   const setPreviewMode = useUIStore((state) => state.setPreviewMode)
+  const openDialog = useUIStore((state) => state.openDialog)
 
   // Fetch layer info from preview server (includes geoserver_url)
   useEffect(() => {
@@ -195,6 +206,12 @@ export default function MapPreview({
     }
   }, [connectionId, workspace, layerName])
 
+  // A filter belongs to the layer it was built for
+  useEffect(() => {
+    setCqlFilter('')
+    setShowFilter(false)
+  }, [connectionId, workspace, layerName])
+
   // Check if two bounding boxes overlap
   const boundsOverlap = (
     a: [number, number, number, number],
@@ -223,16 +240,31 @@ export default function MapPreview({
   const buildWmsTileUrl = (info: LayerInfo, style?: string): string => {
     const layerFullName = `${info.workspace}:${info.name}`
 
-    // Check if we should use WMTS (cached tiles)
-    if (info.use_cache) {
+    // Check if we should use WMTS (cached tiles); cached tiles can't be filtered
+    if (info.use_cache && !cqlFilter) {
       const gridSet = info.grid_set || 'EPSG:900913'
       const tileFormat = info.tile_format || 'image/png'
+      if (info.proxy_url) {
+        // WMTS key-value GetTile, as the proxy only forwards service paths
+        const tileParams = new URLSearchParams({
+          SERVICE: 'WMTS',
+          VERSION: '1.0.0',
+          REQUEST: 'GetTile',
+          LAYER: layerFullName,
+          STYLE: '',
+          TILEMATRIXSET: gridSet,
+          FORMAT: tileFormat,
+        })
+        return `${info.proxy_url}/gwc/service/wmts?${tileParams.toString()}&TILEMATRIX=${encodeURIComponent(gridSet)}:{z}&TILEROW={y}&TILECOL={x}`
+      }
       // GeoServer WMTS REST URL pattern
       return `${info.geoserver_url}/gwc/service/wmts/rest/${encodeURIComponent(layerFullName)}/${gridSet}/${encodeURIComponent(tileFormat)}/{z}/{y}/{x}`
     }
 
-    // Use WMS (uncached) - construct URL using actual GeoServer URL
-    const wmsUrl = `${info.geoserver_url}/${info.workspace}/wms`
+    // Use WMS (uncached) on the workspace virtual service
+    const wmsUrl = info.proxy_url
+      ? serviceUrl(info.proxy_url, 'wms', info.workspace)
+      : info.wms_url || serviceUrl(info.geoserver_url, 'wms', info.workspace)
 
     // Build WMS tile URL for MapLibre
     // Note: We can't use URLSearchParams for the full URL because it encodes
@@ -252,6 +284,9 @@ export default function MapPreview({
     if (style) {
       params.set('STYLES', style)
     }
+    if (cqlFilter) {
+      params.set('CQL_FILTER', cqlFilter)
+    }
 
     // Append BBOX with the unencoded MapLibre placeholder
     return `${wmsUrl}?${params.toString()}&BBOX={bbox-epsg-3857}`
@@ -349,14 +384,17 @@ export default function MapPreview({
       tileSize: 256,
     })
 
-    map.current.addLayer({
-      id: 'wms-layer',
-      type: 'raster',
-      source: 'wms-layer',
-      paint: {
-        'raster-opacity': 1,
+    map.current.addLayer(
+      {
+        id: 'wms-layer',
+        type: 'raster',
+        source: 'wms-layer',
+        paint: {
+          'raster-opacity': 1,
+        },
       },
-    })
+      map.current.getLayer('seed-area-fill') ? 'seed-area-fill' : undefined
+    )
 
     // Handle bounds - only fit to new bounds if current view doesn't overlap
     if (metadata?.latlon_bbox) {
@@ -385,7 +423,100 @@ export default function MapPreview({
       console.log('[MapPreview] No bounds available in metadata')
     }
   // eslint-disable-next-line react-hooks/exhaustive-deps
-  }, [mapLoaded, layerInfo, currentStyle, metadata?.latlon_bbox])
+  }, [mapLoaded, layerInfo, currentStyle, cqlFilter, metadata?.latlon_bbox])
+
+  // Drag a rectangle on the map to pick a seed area
+  useEffect(() => {
+    const m = map.current
+    if (!m || !mapLoaded || !drawingArea) return
+
+    let start: maplibregl.LngLat | null = null
+    const areaFrom = (end: maplibregl.LngLat): [number, number, number, number] => [
+      Math.min(start!.lng, end.lng),
+      Math.min(start!.lat, end.lat),
+      Math.max(start!.lng, end.lng),
+      Math.max(start!.lat, end.lat),
+    ]
+    const onDown = (e: maplibregl.MapMouseEvent) => {
+      start = e.lngLat
+    }
+    const onMove = (e: maplibregl.MapMouseEvent) => {
+      if (start) setSeedArea(areaFrom(e.lngLat))
+    }
+    const onUp = (e: maplibregl.MapMouseEvent) => {
+      if (!start) return
+      const area = areaFrom(e.lngLat)
+      start = null
+      // A click without a drag leaves the previous area
+      if (area[0] < area[2] && area[1] < area[3]) setSeedArea(area)
+      setDrawingArea(false)
+    }
+
+    m.dragPan.disable()
+    m.getCanvas().style.cursor = 'crosshair'
+    m.on('mousedown', onDown)
+    m.on('mousemove', onMove)
+    m.on('mouseup', onUp)
+    return () => {
+      m.off('mousedown', onDown)
+      m.off('mousemove', onMove)
+      m.off('mouseup', onUp)
+      m.dragPan.enable()
+      m.getCanvas().style.cursor = ''
+    }
+  }, [drawingArea, mapLoaded])
+
+  // Outline the seed area
+  useEffect(() => {
+    const m = map.current
+    if (!m || !mapLoaded) return
+
+    const data: GeoJSON.FeatureCollection = { type: 'FeatureCollection', features: [] }
+    if (seedArea) {
+      const [minx, miny, maxx, maxy] = seedArea
+      data.features.push({
+        type: 'Feature',
+        properties: {},
+        geometry: {
+          type: 'Polygon',
+          coordinates: [[[minx, miny], [maxx, miny], [maxx, maxy], [minx, maxy], [minx, miny]]],
+        },
+      })
+    }
+
+    const source = m.getSource('seed-area') as maplibregl.GeoJSONSource | undefined
+    if (source) {
+      source.setData(data)
+      return
+    }
+    m.addSource('seed-area', { type: 'geojson', data })
+    m.addLayer({
+      id: 'seed-area-fill',
+      type: 'fill',
+      source: 'seed-area',
+      paint: { 'fill-color': '#dea037', 'fill-opacity': 0.15 },
+    })
+    m.addLayer({
+      id: 'seed-area-line',
+      type: 'line',
+      source: 'seed-area',
+      paint: { 'line-color': '#dea037', 'line-width': 2 },
+    })
+  }, [seedArea, mapLoaded])
+
+  const handleSeedArea = () => {
+    if (!seedArea || !connectionId) return
+    openDialog('info', {
+      mode: 'view',
+      title: 'Tile Cache',
+      data: {
+        connectionId,
+        workspace,
+        layerName,
+        bounds: seedArea,
+      },
+    })
+  }
 
   // Update view mode (2D/3D/Globe)
   useEffect(() => {
@@ -431,14 +562,17 @@ export default function MapPreview({
       tileSize: 256,
     })
 
-    map.current.addLayer({
-      id: 'wms-layer',
-      type: 'raster',
-      source: 'wms-layer',
-      paint: {
-        'raster-opacity': 1,
+    map.current.addLayer(
+      {
+        id: 'wms-layer',
+        type: 'raster',
+        source: 'wms-layer',
+        paint: {
+          'raster-opacity': 1,
+        },
       },
-    })
+      map.current.getLayer('seed-area-fill') ? 'seed-area-fill' : undefined
+    )
   }
 
   const handleStyleChange = (style: string) => {
@@ -465,7 +599,7 @@ export default function MapPreview({
                 Layer Preview
               </Heading>
               <Badge colorScheme="whiteAlpha" variant="solid" fontSize="xs">
-                {layerType === 'raster' ? 'Raster' : 'Vector'}
+                {layerType === 'raster' ? 'Raster' : layerType === 'group' ? 'Layer Group' : 'Vector'}
               </Badge>
             </HStack>
             <Text fontSize="xs" color="whiteAlpha.800">
@@ -503,7 +637,7 @@ export default function MapPreview({
                       <HStack>
                         {layerInfo?.geoserver_url ? (
                           <StyleLegendIcon
-                            geoserverUrl={layerInfo.geoserver_url}
+                            geoserverUrl={layerInfo.proxy_url || layerInfo.geoserver_url}
                             workspace={workspace}
                             layerName={layerName}
                             styleName={style}
@@ -560,6 +694,36 @@ export default function MapPreview({
               />
             </Tooltip>
 
+            {connectionId && layerType !== 'raster' && layerType !== 'group' && (
+              <Tooltip label={cqlFilter ? `Filter: ${cqlFilter}` : 'Filter features'}>
+                <IconButton
+                  aria-label="Filter"
+                  icon={<FiFilter />}
+                  size="sm"
+                  variant="ghost"
+                  color={cqlFilter ? 'yellow.300' : 'white'}
+                  _hover={{ bg: 'whiteAlpha.200' }}
+                  onClick={() => setShowFilter(!showFilter)}
+                  bg={showFilter ? 'whiteAlpha.200' : undefined}
+                />
+              </Tooltip>
+            )}
+
+            {connectionId && (
+              <Tooltip label={drawingArea ? 'Drag on the map to draw the area' : 'Draw an area to seed'}>
+                <IconButton
+                  aria-label="Draw seed area"
+                  icon={<FiCrop />}
+                  size="sm"
+                  variant="ghost"
+                  color={seedArea ? 'yellow.300' : 'white'}
+                  _hover={{ bg: 'whiteAlpha.200' }}
+                  onClick={() => setDrawingArea(!drawingArea)}
+                  bg={drawingArea ? 'whiteAlpha.200' : undefined}
+                />
+              </Tooltip>
+            )}
+
             <Divider orientation="vertical" h="24px" borderColor="whiteAlpha.400" />
 
             <Tooltip label="Refresh">
@@ -647,6 +811,24 @@ export default function MapPreview({
         </Box>
       )}
 
+      {/* CQL Filter Panel */}
+      {connectionId && (
+        <Collapse in={showFilter} animateOpacity>
+          <Box p={3} borderBottom="1px solid" borderColor={borderColor}>
+            <CqlFilterBuilder
+              connectionId={connectionId}
+              workspace={workspace}
+              layerName={layerName}
+              value={cqlFilter}
+              onApply={(next) => {
+                setCqlFilter(next)
+                setShowFilter(false)
+              }}
+            />
+          </Box>
+        </Collapse>
+      )}
+
       {/* Metadata Panel */}
       <Collapse in={showMetadata} animateOpacity>
         <Box bg={metaBg} p={4} borderBottom="1px solid" borderColor={borderColor}>
@@ -728,6 +910,22 @@ export default function MapPreview({
           right={0}
           bottom={0}
         />
+        {seedArea && !drawingArea && (
+          <Card position="absolute" top={2} left={2} p={3} shadow="md" fontSize="sm">
+            <Text fontWeight="600" mb={1}>Seed area (lon/lat)</Text>
+            <Text fontFamily="mono" fontSize="xs" color="gray.600" mb={2}>
+              {seedArea.map((value) => value.toFixed(4)).join(', ')}
+            </Text>
+            <HStack spacing={2}>
+              <Button size="xs" colorScheme="kartoza" onClick={handleSeedArea}>
+                Seed this area
+              </Button>
+              <Button size="xs" variant="ghost" onClick={() => setSeedArea(null)}>
+                Clear
+              </Button>
+            </HStack>
+          </Card>
+        )}
         {!mapLoaded && (
           <Box
             position="absolute"
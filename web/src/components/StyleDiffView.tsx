@@ -0,0 +1,87 @@
+import { Badge, Box, HStack, Text, VStack, useColorModeValue } from '@chakra-ui/react'
+import type { StyleDiff } from '../api'
+
+const STATUS_COLORS: Record<StyleDiff['status'], string> = {
+  new: 'green',
+  changed: 'orange',
+  unchanged: 'gray',
+}
+
+interface StyleDiffViewProps {
+  diffs: StyleDiff[]
+  showUnchanged?: boolean
+}
+
+function lineColor(line: string): string | undefined {
+  if (line.startsWith('+++') || line.startsWith('---')) return 'gray.400'
+  if (line.startsWith('@@')) return 'cyan.300'
+  if (line.startsWith('+')) return 'green.300'
+  if (line.startsWith('-')) return 'red.300'
+  return undefined
+}
+
+/**
+ * Colorized unified diffs of styles, one block per style.
+ */
+export default function StyleDiffView({ diffs, showUnchanged = false }: StyleDiffViewProps) {
+  const headerBg = useColorModeValue('gray.50', 'gray.700')
+  const shown = showUnchanged ? diffs : diffs.filter((d) => d.status !== 'unchanged')
+
+  if (shown.length === 0) {
+    return (
+      <Text fontSize="sm" color="gray.500" textAlign="center" py={4}>
+        No style would change
+      </Text>
+    )
+  }
+
+  return (
+    <VStack spacing={3} align="stretch">
+      {shown.map((diff) => (
+        <Box
+          key={`${diff.workspace ?? ''}:${diff.name}:${diff.to}`}
+          border="1px solid"
+          borderColor="gray.200"
+          borderRadius="md"
+          overflow="hidden"
+        >
+          <HStack px={3} py={2} bg={headerBg} spacing={2}>
+            <Text fontSize="sm" fontWeight="600">
+              {diff.name}
+            </Text>
+            <Badge colorScheme={STATUS_COLORS[diff.status]}>{diff.status}</Badge>
+            {diff.fromFormat && diff.fromFormat !== diff.toFormat && (
+              <Badge colorScheme="purple">
+                {diff.fromFormat} → {diff.toFormat}
+              </Badge>
+            )}
+            <Text fontSize="xs" color="green.500" ml="auto">
+              +{diff.additions}
+            </Text>
+            <Text fontSize="xs" color="red.500">
+              -{diff.deletions}
+            </Text>
+          </HStack>
+          {diff.diff && (
+            <Box
+              as="pre"
+              bg="gray.900"
+              color="gray.100"
+              fontFamily="mono"
+              fontSize="xs"
+              p={3}
+              maxH="300px"
+              overflow="auto"
+            >
+              {diff.diff.split('\n').map((line, i) => (
+                <Text as="span" key={i} display="block" color={lineColor(line)}>
+                  {line || ' '}
+                </Text>
+              ))}
+            </Box>
+          )}
+        </Box>
+      ))}
+    </VStack>
+  )
+}
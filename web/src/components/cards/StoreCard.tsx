@@ -15,7 +15,7 @@ import { useUIStore } from '../../stores/uiStore'
 interface StoreCardProps {
   name: string
   type: string
-  enabled: boolean
+  enabled?: boolean | null // No badge when unknown
   icon: React.ElementType
   connectionId: string
   workspace: string
@@ -66,9 +66,11 @@ export default function StoreCard({
               <Icon as={icon} color="kartoza.500" boxSize={5} />
               <Text fontWeight="medium" noOfLines={1}>{name}</Text>
             </HStack>
-            <Badge colorScheme={enabled ? 'green' : 'gray'} size="sm">
-              {enabled ? 'Enabled' : 'Disabled'}
-            </Badge>
+            {enabled != null && (
+              <Badge colorScheme={enabled ? 'green' : 'gray'} size="sm">
+                {enabled ? 'Enabled' : 'Disabled'}
+              </Badge>
+            )}
           </HStack>
           <Text fontSize="xs" color="gray.500">{type}</Text>
           <Button
@@ -0,0 +1,303 @@
+import { useState, useEffect } from 'react'
+import {
+  Box,
+  VStack,
+  HStack,
+  Text,
+  Select,
+  Input,
+  Textarea,
+  Button,
+  ButtonGroup,
+  IconButton,
+  Alert,
+  AlertIcon,
+  Spinner,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiPlus, FiTrash2, FiCheck, FiX } from 'react-icons/fi'
+import { useQuery, useMutation } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { FilterAttribute, FilterCondition, FilterMatch, FilterValidation } from '../../types'
+
+interface CqlFilterBuilderProps {
+  connectionId: string
+  workspace: string
+  layerName: string
+  value: string // Filter currently applied
+  onApply: (cql: string) => void
+}
+
+interface ConditionRowProps {
+  connectionId: string
+  workspace: string
+  layerName: string
+  attributes: FilterAttribute[]
+  condition: FilterCondition
+  onChange: (condition: FilterCondition) => void
+  onRemove: () => void
+}
+
+const NO_VALUE_OPERATORS = ['IS NULL', 'IS NOT NULL']
+
+const VALUE_HINTS: Record<string, string> = {
+  BETWEEN: 'low, high',
+  IN: 'a, b, c',
+  BBOX: 'minx, miny, maxx, maxy',
+}
+
+// One attribute test, with values suggested from the layer's features as they are typed
+function ConditionRow({
+  connectionId,
+  workspace,
+  layerName,
+  attributes,
+  condition,
+  onChange,
+  onRemove,
+}: ConditionRowProps) {
+  const attribute = attributes.find((a) => a.name === condition.attribute)
+  const [prefix, setPrefix] = useState('')
+
+  // Wait for a pause in typing before asking for suggestions
+  useEffect(() => {
+    const timer = setTimeout(() => setPrefix(condition.value), 300)
+    return () => clearTimeout(timer)
+  }, [condition.value])
+
+  const { data: suggestions } = useQuery({
+    queryKey: ['filterValues', connectionId, workspace, layerName, condition.attribute, prefix],
+    queryFn: () => api.getFilterValues(connectionId, workspace, layerName, condition.attribute, prefix),
+    enabled: !!attribute && attribute.kind !== 'geometry',
+    staleTime: 5 * 60 * 1000,
+    retry: false,
+  })
+
+  const listId = `cql-values-${workspace}-${layerName}-${condition.attribute}`
+  const needsValue = !NO_VALUE_OPERATORS.includes(condition.operator)
+
+  return (
+    <HStack spacing={2} align="start">
+      <Select
+        size="sm"
+        w="200px"
+        placeholder="Attribute"
+        value={condition.attribute}
+        onChange={(e) => {
+          const picked = attributes.find((a) => a.name === e.target.value)
+          onChange({ attribute: e.target.value, operator: picked?.operators[0] ?? '', value: '' })
+        }}
+      >
+        {attributes.map((a) => (
+          <option key={a.name} value={a.name}>
+            {a.name} ({a.type})
+          </option>
+        ))}
+      </Select>
+      <Select
+        size="sm"
+        w="140px"
+        value={condition.operator}
+        onChange={(e) => onChange({ ...condition, operator: e.target.value })}
+        isDisabled={!attribute}
+      >
+        {attribute?.operators.map((op) => (
+          <option key={op} value={op}>
+            {op}
+          </option>
+        ))}
+      </Select>
+      {needsValue && (
+        <>
+          <Input
+            size="sm"
+            flex={1}
+            list={listId}
+            value={condition.value}
+            onChange={(e) => onChange({ ...condition, value: e.target.value })}
+            placeholder={VALUE_HINTS[condition.operator] ?? 'Value'}
+            isDisabled={!attribute}
+            fontFamily="mono"
+          />
+          <datalist id={listId}>
+            {suggestions?.map((value) => (
+              <option key={value} value={value} />
+            ))}
+          </datalist>
+        </>
+      )}
+      <IconButton
+        aria-label="Remove condition"
+        icon={<FiTrash2 />}
+        size="sm"
+        variant="ghost"
+        onClick={onRemove}
+      />
+    </HStack>
+  )
+}
+
+// Builds a CQL filter from a layer's attributes; GeoServer checks it and counts its matches
+export default function CqlFilterBuilder({
+  connectionId,
+  workspace,
+  layerName,
+  value,
+  onApply,
+}: CqlFilterBuilderProps) {
+  const boxBg = useColorModeValue('gray.50', 'gray.700')
+  const [conditions, setConditions] = useState<FilterCondition[]>([])
+  const [match, setMatch] = useState<FilterMatch>('all')
+  const [cql, setCql] = useState(value)
+  // Set once the expression is typed by hand, so checking sends it rather than the conditions
+  const [edited, setEdited] = useState(!!value)
+  const [validation, setValidation] = useState<FilterValidation | null>(null)
+
+  useEffect(() => {
+    setCql(value)
+    setEdited(!!value)
+    setValidation(null)
+  }, [value])
+
+  const { data: attributes, isLoading, error } = useQuery({
+    queryKey: ['filterAttributes', connectionId, workspace, layerName],
+    queryFn: () => api.getFilterAttributes(connectionId, workspace, layerName),
+    staleTime: 5 * 60 * 1000,
+    retry: false,
+  })
+
+  const checkMutation = useMutation({
+    mutationFn: () =>
+      api.validateFilter(
+        connectionId,
+        workspace,
+        layerName,
+        edited || conditions.length === 0 ? { cql } : { conditions, match }
+      ),
+    onSuccess: (result) => {
+      setValidation(result)
+      if (result.cql) setCql(result.cql)
+    },
+  })
+
+  const updateConditions = (next: FilterCondition[]) => {
+    setConditions(next)
+    setEdited(false)
+    setValidation(null)
+  }
+
+  if (isLoading) {
+    return (
+      <HStack p={3}>
+        <Spinner size="sm" />
+        <Text fontSize="sm" color="gray.500">Reading attributes...</Text>
+      </HStack>
+    )
+  }
+
+  if (error || !attributes) {
+    return (
+      <Alert status="error" borderRadius="md" fontSize="sm">
+        <AlertIcon />
+        {(error as Error)?.message ?? 'Could not read the layer attributes'}
+      </Alert>
+    )
+  }
+
+  const checked = !!validation && validation.cql === cql.trim()
+
+  return (
+    <Box bg={boxBg} p={3} borderRadius="md">
+      <VStack align="stretch" spacing={3}>
+        <HStack justify="space-between">
+          <Text fontSize="sm" fontWeight="medium">Conditions</Text>
+          <ButtonGroup size="xs" isAttached variant="outline">
+            <Button isActive={match === 'all'} onClick={() => { setMatch('all'); setEdited(false) }}>
+              Match all
+            </Button>
+            <Button isActive={match === 'any'} onClick={() => { setMatch('any'); setEdited(false) }}>
+              Match any
+            </Button>
+          </ButtonGroup>
+        </HStack>
+
+        {conditions.map((condition, idx) => (
+          <ConditionRow
+            key={idx}
+            connectionId={connectionId}
+            workspace={workspace}
+            layerName={layerName}
+            attributes={attributes}
+            condition={condition}
+            onChange={(next) => updateConditions(conditions.map((c, i) => (i === idx ? next : c)))}
+            onRemove={() => updateConditions(conditions.filter((_, i) => i !== idx))}
+          />
+        ))}
+
+        <Button
+          size="xs"
+          variant="ghost"
+          leftIcon={<FiPlus />}
+          alignSelf="start"
+          onClick={() => {
+            const first = attributes.find((a) => a.kind !== 'geometry') ?? attributes[0]
+            if (!first) return
+            updateConditions([...conditions, { attribute: first.name, operator: first.operators[0], value: '' }])
+          }}
+        >
+          Add condition
+        </Button>
+
+        <Textarea
+          size="sm"
+          rows={2}
+          fontFamily="mono"
+          value={cql}
+          onChange={(e) => {
+            setCql(e.target.value)
+            setEdited(true)
+            setValidation(null)
+          }}
+          placeholder="CQL filter, built from the conditions or typed here"
+        />
+
+        {checked && validation.valid && (
+          <Text fontSize="sm" color="green.500">
+            Valid: {validation.count?.toLocaleString()} matching feature{validation.count === 1 ? '' : 's'}
+          </Text>
+        )}
+        {validation && !validation.valid && (
+          <Text fontSize="sm" color="red.500">{validation.error}</Text>
+        )}
+        {checkMutation.error && (
+          <Text fontSize="sm" color="red.500">{(checkMutation.error as Error).message}</Text>
+        )}
+
+        <HStack>
+          <Button
+            size="sm"
+            leftIcon={<FiCheck />}
+            onClick={() => checkMutation.mutate()}
+            isLoading={checkMutation.isPending}
+            isDisabled={!cql.trim() && conditions.length === 0}
+          >
+            Check
+          </Button>
+          <Button
+            size="sm"
+            colorScheme="kartoza"
+            onClick={() => onApply(cql.trim())}
+            isDisabled={!checked || !validation.valid}
+          >
+            Apply Filter
+          </Button>
+          {value && (
+            <Button size="sm" variant="ghost" leftIcon={<FiX />} onClick={() => onApply('')}>
+              Clear
+            </Button>
+          )}
+        </HStack>
+      </VStack>
+    </Box>
+  )
+}
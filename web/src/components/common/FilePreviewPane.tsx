@@ -0,0 +1,145 @@
+import {
+  Box,
+  HStack,
+  VStack,
+  Text,
+  Image,
+  Spinner,
+  SimpleGrid,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { useQuery } from '@tanstack/react-query'
+import CodeMirror from '@uiw/react-codemirror'
+import { xml } from '@codemirror/lang-xml'
+import { css } from '@codemirror/lang-css'
+import * as api from '../../api'
+
+// Matches PREVIEW_MAX_BYTES on the server
+const PREVIEW_MAX_BYTES = 200 * 1024 * 1024
+
+interface FilePreviewPaneProps {
+  file: File
+}
+
+function formatExtent(extent: [number, number, number, number]): string {
+  return extent.map((v) => (Math.abs(v) >= 1000 ? v.toFixed(0) : v.toFixed(4))).join(', ')
+}
+
+export default function FilePreviewPane({ file }: FilePreviewPaneProps) {
+  const isStyle = /\.(sld|css)$/i.test(file.name)
+  const tooLarge = !isStyle && file.size > PREVIEW_MAX_BYTES
+  const labelColor = useColorModeValue('gray.500', 'gray.400')
+
+  // Style files are read in the browser; data files are described by GDAL on the server
+  const { data: styleText } = useQuery({
+    queryKey: ['file-preview-style', file.name, file.size, file.lastModified],
+    queryFn: () => file.text(),
+    enabled: isStyle,
+  })
+
+  const { data: preview, isPending, error } = useQuery({
+    queryKey: ['file-preview', file.name, file.size, file.lastModified],
+    queryFn: () => api.previewFile(file),
+    enabled: !isStyle && !tooLarge,
+    staleTime: Infinity,
+  })
+
+  if (isStyle) {
+    return (
+      <Box borderWidth="1px" borderRadius="md" overflow="hidden" fontSize="xs">
+        <CodeMirror
+          value={styleText ?? ''}
+          height="200px"
+          extensions={/\.css$/i.test(file.name) ? [css()] : [xml()]}
+          editable={false}
+          theme="light"
+        />
+      </Box>
+    )
+  }
+
+  if (tooLarge) {
+    return (
+      <Text fontSize="xs" color={labelColor}>
+        Too large to preview
+      </Text>
+    )
+  }
+
+  if (isPending) {
+    return (
+      <HStack spacing={2}>
+        <Spinner size="xs" />
+        <Text fontSize="xs" color={labelColor}>
+          Reading file...
+        </Text>
+      </HStack>
+    )
+  }
+
+  if (error || !preview) {
+    return (
+      <Text fontSize="xs" color="red.500">
+        {(error as Error)?.message || 'Preview failed'}
+      </Text>
+    )
+  }
+
+  const { summary, thumbnail } = preview
+  if (summary.error) {
+    return (
+      <Text fontSize="xs" color="red.500">
+        {summary.error}
+      </Text>
+    )
+  }
+
+  return (
+    <HStack align="start" spacing={3}>
+      {thumbnail && (
+        <Image
+          src={thumbnail}
+          alt={`${file.name} thumbnail`}
+          boxSize="96px"
+          objectFit="contain"
+          borderWidth="1px"
+          borderRadius="md"
+          bg="gray.800"
+        />
+      )}
+      <VStack align="stretch" spacing={1} flex="1" fontSize="xs">
+        <SimpleGrid spacingX={2} templateColumns="auto 1fr">
+          <Text color={labelColor}>Driver</Text>
+          <Text>{summary.driver || '-'}</Text>
+          <Text color={labelColor}>CRS</Text>
+          <Text>{summary.crs || 'none'}</Text>
+          <Text color={labelColor}>Extent</Text>
+          <Text>{summary.extent ? formatExtent(summary.extent) : '-'}</Text>
+          {summary.size && (
+            <>
+              <Text color={labelColor}>Size</Text>
+              <Text>
+                {summary.size[0]} x {summary.size[1]} px
+              </Text>
+            </>
+          )}
+        </SimpleGrid>
+        {summary.bands.map((band) => (
+          <Text key={band}>{band}</Text>
+        ))}
+        {summary.layers.map((layer) => (
+          <Box key={layer.name}>
+            <Text fontWeight="500">
+              {layer.name}
+              {layer.geometryType && ` (${layer.geometryType})`}
+              {layer.featureCount !== null && `, ${layer.featureCount} features`}
+            </Text>
+            <Text color={labelColor} noOfLines={2}>
+              {layer.fields.map((f) => `${f.name}: ${f.type}`).join(', ') || 'No fields'}
+            </Text>
+          </Box>
+        ))}
+      </VStack>
+    </HStack>
+  )
+}
@@ -0,0 +1,72 @@
+import { useEffect } from 'react'
+import { VStack, HStack, Text, Input, Textarea, Spinner } from '@chakra-ui/react'
+import { useQuery } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { LayerMetadata } from '../../types'
+
+// Matches PREVIEW_MAX_BYTES on the server
+const PREVIEW_MAX_BYTES = 200 * 1024 * 1024
+
+interface LayerMetadataFieldsProps {
+  file: File
+  value: LayerMetadata | undefined // Undefined until the suggestion is in
+  onChange: (metadata: LayerMetadata) => void
+  isDisabled?: boolean
+}
+
+export default function LayerMetadataFields({
+  file,
+  value,
+  onChange,
+  isDisabled,
+}: LayerMetadataFieldsProps) {
+  // Shares the preview pane's query, so the file is only read once
+  const { data: preview, isFetching } = useQuery({
+    queryKey: ['file-preview', file.name, file.size, file.lastModified],
+    queryFn: () => api.previewFile(file),
+    enabled: file.size <= PREVIEW_MAX_BYTES,
+    staleTime: Infinity,
+  })
+
+  // Start from the suggestion, unless the fields were already edited
+  useEffect(() => {
+    if (value === undefined && preview?.metadata) onChange(preview.metadata)
+  }, [preview]) // eslint-disable-line react-hooks/exhaustive-deps
+
+  const metadata = value ?? { title: '', abstract: '', keywords: [] }
+  const update = (changes: Partial<LayerMetadata>) => onChange({ ...metadata, ...changes })
+
+  return (
+    <VStack align="stretch" spacing={1} mt={2}>
+      <HStack spacing={2}>
+        <Text fontSize="xs" color="gray.500" flexShrink={0}>
+          Layer
+        </Text>
+        <Input
+          size="xs"
+          placeholder="Title (suggested on upload)"
+          value={metadata.title}
+          onChange={(e) => update({ title: e.target.value })}
+          isDisabled={isDisabled}
+        />
+        {isFetching && <Spinner size="xs" />}
+      </HStack>
+      <Textarea
+        size="xs"
+        rows={2}
+        placeholder="Abstract (suggested on upload)"
+        value={metadata.abstract}
+        onChange={(e) => update({ abstract: e.target.value })}
+        isDisabled={isDisabled}
+      />
+      <Input
+        size="xs"
+        placeholder="Keywords, comma separated"
+        // Split without trimming so typing ", " isn't undone; the server trims
+        value={metadata.keywords.join(',')}
+        onChange={(e) => update({ keywords: e.target.value ? e.target.value.split(',') : [] })}
+        isDisabled={isDisabled}
+      />
+    </VStack>
+  )
+}
@@ -0,0 +1,49 @@
+import { FormControl, FormLabel, Input, Code, Text } from '@chakra-ui/react'
+import { useConnectionStore } from '../../stores/connectionStore'
+
+interface TypedConfirmationProps {
+  expected: string
+  value: string
+  onChange: (value: string) => void
+}
+
+// Whether any of these connections wants destructive operations confirmed by typing
+export function useTypedConfirmation(connectionIds: (string | undefined)[]): boolean {
+  return useConnectionStore((state) =>
+    state.connections.some((c) => connectionIds.includes(c.id) && !!c.typedConfirmation)
+  )
+}
+
+// Whether typed text confirms an operation; only surrounding spaces are forgiven
+export function confirmationMatches(expected: string, value: string): boolean {
+  return expected !== '' && value.trim() === expected
+}
+
+// Text to type before a batch action, as there is no single name to type
+export function batchConfirmationText(action: string, count: number): string {
+  return `${action} ${count} layer${count === 1 ? '' : 's'}`
+}
+
+// Asks the user to type a resource's name before a destructive operation
+export default function TypedConfirmation({ expected, value, onChange }: TypedConfirmationProps) {
+  const matches = confirmationMatches(expected, value)
+  return (
+    <FormControl mt={4}>
+      <FormLabel fontSize="sm" color="gray.700">
+        Type <Code colorScheme="red">{expected}</Code> to confirm
+      </FormLabel>
+      <Input
+        value={value}
+        onChange={(e) => onChange(e.target.value)}
+        placeholder={expected}
+        autoComplete="off"
+        spellCheck={false}
+        borderColor={matches ? 'green.400' : undefined}
+        fontFamily="mono"
+      />
+      <Text fontSize="xs" color="gray.500" mt={1}>
+        This connection asks for the name before anything is deleted.
+      </Text>
+    </FormControl>
+  )
+}
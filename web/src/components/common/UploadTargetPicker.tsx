@@ -0,0 +1,129 @@
+import { useState } from 'react'
+import {
+  HStack,
+  VStack,
+  Text,
+  Select,
+  Input,
+  Button,
+  ButtonGroup,
+  Spinner,
+} from '@chakra-ui/react'
+import { useQuery } from '@tanstack/react-query'
+import * as api from '../../api'
+
+interface UploadTargetPickerProps {
+  connectionId: string
+  workspace: string
+  file: File
+  value: string // Chosen store; empty to name it after the file
+  onChange: (store: string) => void
+  isDisabled?: boolean
+}
+
+// Coverage stores hold GeoTIFFs; everything else goes into data stores
+function storeTypeOf(filename: string): 'datastore' | 'coveragestore' {
+  return /\.tiff?$/i.test(filename) ? 'coveragestore' : 'datastore'
+}
+
+export default function UploadTargetPicker({
+  connectionId,
+  workspace,
+  file,
+  value,
+  onChange,
+  isDisabled,
+}: UploadTargetPickerProps) {
+  const [useExisting, setUseExisting] = useState(false)
+  const storeType = storeTypeOf(file.name)
+
+  const { data: stores } = useQuery({
+    queryKey: [storeType === 'datastore' ? 'datastores' : 'coveragestores', connectionId, workspace],
+    queryFn: () =>
+      storeType === 'datastore'
+        ? api.getDataStores(connectionId, workspace)
+        : api.getCoverageStores(connectionId, workspace),
+    enabled: useExisting,
+  })
+
+  const { data: target, isFetching } = useQuery({
+    queryKey: ['upload-target', connectionId, workspace, file.name, value],
+    queryFn: () => api.resolveUploadTarget(connectionId, workspace, file.name, value || undefined),
+    placeholderData: (previous) => previous,
+  })
+
+  const switchMode = (existing: boolean) => {
+    setUseExisting(existing)
+    onChange('')
+  }
+
+  let preview = ''
+  if (target && !target.error) {
+    const layer = target.layer ? `layer ${target.layer}` : 'layers chosen after upload'
+    if (target.mode === 'append') {
+      preview = `Appends to data store ${target.store}, ${layer}`
+    } else if (target.mode === 'replace') {
+      preview = `Replaces the GeoTIFF of ${target.store}, ${layer}`
+    } else {
+      preview = `Creates store ${target.store}, ${layer}`
+    }
+  }
+
+  return (
+    <VStack align="stretch" spacing={1} mt={2}>
+      <HStack spacing={2}>
+        <ButtonGroup size="xs" isAttached variant="outline" isDisabled={isDisabled}>
+          <Button
+            colorScheme={useExisting ? 'gray' : 'kartoza'}
+            onClick={() => switchMode(false)}
+          >
+            New store
+          </Button>
+          <Button
+            colorScheme={useExisting ? 'kartoza' : 'gray'}
+            onClick={() => switchMode(true)}
+          >
+            Existing store
+          </Button>
+        </ButtonGroup>
+        {useExisting ? (
+          <Select
+            size="xs"
+            placeholder={
+              storeType === 'datastore' ? 'Select a data store...' : 'Select a coverage store...'
+            }
+            value={value}
+            onChange={(e) => onChange(e.target.value)}
+            isDisabled={isDisabled}
+          >
+            {(stores || []).map((store) => (
+              <option key={store.name} value={store.name}>
+                {store.name}
+              </option>
+            ))}
+          </Select>
+        ) : (
+          <Input
+            size="xs"
+            placeholder={target?.store || 'store name'}
+            value={value}
+            onChange={(e) => onChange(e.target.value)}
+            isDisabled={isDisabled}
+          />
+        )}
+        {isFetching && <Spinner size="xs" />}
+      </HStack>
+      {target?.error ? (
+        <Text fontSize="xs" color="red.500">
+          {target.error}
+        </Text>
+      ) : (
+        preview && (
+          <Text fontSize="xs" color={target?.mode === 'create' ? 'gray.500' : 'orange.500'}>
+            {preview}
+          </Text>
+        )
+      )}
+    </VStack>
+  )
+}
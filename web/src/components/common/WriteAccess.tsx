@@ -0,0 +1,33 @@
+import { Box, Tooltip, type BoxProps } from '@chakra-ui/react'
+import { useQuery } from '@tanstack/react-query'
+import * as api from '../../api'
+import { useConnectionStore } from '../../stores/connectionStore'
+
+interface WriteAccessTooltipProps extends BoxProps {
+  reason: string | null
+}
+
+// Why the credentials of a GeoServer connection can't change its server, or null if they can
+export function useWriteDenied(connectionId: string | undefined): string | null {
+  const isGeoServer = useConnectionStore((state) =>
+    state.connections.some((c) => c.id === connectionId)
+  )
+  const { data: permissions } = useQuery({
+    queryKey: ['serverPermissions', connectionId],
+    queryFn: () => api.getServerPermissions(connectionId!),
+    enabled: !!connectionId && isGeoServer,
+    staleTime: 5 * 60 * 1000,
+  })
+  return permissions && !permissions.canWrite ? permissions.reason : null
+}
+
+// Explains a greyed-out action; disabled buttons get no mouse events, so the box carries the tooltip
+export default function WriteAccessTooltip({ reason, children, ...props }: WriteAccessTooltipProps) {
+  return (
+    <Tooltip label={reason} isDisabled={!reason} fontSize="xs" hasArrow>
+      <Box display="inline-flex" {...props}>
+        {children}
+      </Box>
+    </Tooltip>
+  )
+}
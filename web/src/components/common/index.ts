@@ -1 +1,12 @@
 export { default as ServerLocationMap } from './ServerLocationMap'
+export { default as CqlFilterBuilder } from './CqlFilterBuilder'
+export {
+  default as TypedConfirmation,
+  useTypedConfirmation,
+  confirmationMatches,
+  batchConfirmationText,
+} from './TypedConfirmation'
+export { default as UploadTargetPicker } from './UploadTargetPicker'
+export { default as FilePreviewPane } from './FilePreviewPane'
+export { default as LayerMetadataFields } from './LayerMetadataFields'
+export { default as WriteAccessTooltip, useWriteDenied } from './WriteAccess'
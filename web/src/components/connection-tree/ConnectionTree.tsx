@@ -1,8 +1,74 @@
 import { useEffect } from 'react'
-import { Box } from '@chakra-ui/react'
+import { Box, Button, ButtonGroup, Flex, Text, useColorModeValue } from '@chakra-ui/react'
+import { FiArchive, FiDownload, FiLayers, FiMap, FiScissors, FiTrash2, FiX } from 'react-icons/fi'
 import { useConnectionStore } from '../../stores/connectionStore'
+import { useTreeStore } from '../../stores/treeStore'
+import { useUIStore } from '../../stores/uiStore'
+import type { BatchAction } from '../../types'
 import { CloudBenchRootNode } from './nodes'
 
+function BatchBar() {
+  const markedCount = useTreeStore((state) => Object.keys(state.markedNodes).length)
+  const clearMarks = useTreeStore((state) => state.clearMarks)
+  const openDialog = useUIStore((state) => state.openDialog)
+  const bg = useColorModeValue('kartoza.50', 'kartoza.900')
+
+  if (markedCount === 0) return null
+
+  const open = (action: BatchAction) => openDialog('batch', { mode: 'view', data: { action } })
+
+  return (
+    <Flex
+      position="sticky"
+      top={0}
+      zIndex={1}
+      align="center"
+      wrap="wrap"
+      gap={2}
+      px={2}
+      py={2}
+      mb={1}
+      bg={bg}
+      borderRadius="md"
+    >
+      <Text fontSize="sm" fontWeight="600" flex="1">
+        {markedCount} marked
+      </Text>
+      <ButtonGroup size="xs" variant="ghost" spacing={1}>
+        <Button leftIcon={<FiTrash2 />} colorScheme="red" onClick={() => open('delete')}>
+          Delete
+        </Button>
+        <Button leftIcon={<FiScissors />} colorScheme="orange" onClick={() => open('truncate')}>
+          Truncate
+        </Button>
+        <Button leftIcon={<FiDownload />} colorScheme="kartoza" onClick={() => open('export')}>
+          Configs
+        </Button>
+        <Button leftIcon={<FiLayers />} colorScheme="kartoza" onClick={() => open('group')}>
+          Group
+        </Button>
+        <Button
+          leftIcon={<FiMap />}
+          colorScheme="kartoza"
+          onClick={() => openDialog('mapexport', { mode: 'view' })}
+        >
+          Web Map
+        </Button>
+        <Button
+          leftIcon={<FiArchive />}
+          colorScheme="kartoza"
+          onClick={() => openDialog('snapshot', { mode: 'view' })}
+        >
+          Snapshot
+        </Button>
+        <Button leftIcon={<FiX />} onClick={clearMarks}>
+          Clear
+        </Button>
+      </ButtonGroup>
+    </Flex>
+  )
+}
+
 export default function ConnectionTree() {
   const connections = useConnectionStore((state) => state.connections)
   const fetchConnections = useConnectionStore((state) => state.fetchConnections)
@@ -13,6 +79,8 @@ export default function ConnectionTree() {
 
   return (
     <Box>
+      {/* Actions on marked nodes */}
+      <BatchBar />
       {/* CloudBench Root Node */}
       <CloudBenchRootNode connections={connections} />
     </Box>
@@ -0,0 +1,71 @@
+import {
+  IconButton,
+  Menu,
+  MenuButton,
+  MenuItem,
+  MenuList,
+  Spinner,
+  Text,
+  Tooltip,
+  useToast,
+} from '@chakra-ui/react'
+import { useQuery } from '@tanstack/react-query'
+import { FiCopy } from 'react-icons/fi'
+import * as api from '../../api'
+import type { CopyTargetQuery } from '../../types'
+
+interface CopyMenuProps {
+  target: CopyTargetQuery
+  isOpen: boolean
+  onOpen: () => void
+  onClose: () => void
+}
+
+// Lists the URLs and names of a node and copies the chosen one
+export function CopyMenu({ target, isOpen, onOpen, onClose }: CopyMenuProps) {
+  const toast = useToast()
+
+  const { data: targets, isLoading, error } = useQuery({
+    queryKey: ['copy-targets', target],
+    queryFn: () => api.getCopyTargets(target),
+    enabled: isOpen,
+    staleTime: 5 * 60 * 1000,
+  })
+
+  const copy = (label: string, value: string) => {
+    navigator.clipboard.writeText(value).then(
+      () => toast({ title: 'Copied', description: label, status: 'success', duration: 2000 }),
+      () => toast({ title: 'Could not copy', description: value, status: 'error', duration: 5000 })
+    )
+  }
+
+  return (
+    <Menu isLazy placement="bottom-end" isOpen={isOpen} onOpen={onOpen} onClose={onClose}>
+      <Tooltip label="Copy (y)" fontSize="xs">
+        <MenuButton
+          as={IconButton}
+          aria-label="Copy"
+          icon={<FiCopy size={14} />}
+          size="xs"
+          variant="ghost"
+          colorScheme="gray"
+          onClick={(e: React.MouseEvent) => e.stopPropagation()}
+          _hover={{ bg: 'gray.100' }}
+        />
+      </Tooltip>
+      <MenuList minW="220px" fontSize="sm" onClick={(e) => e.stopPropagation()}>
+        {isLoading && (
+          <MenuItem isDisabled>
+            <Spinner size="xs" mr={2} /> Loading...
+          </MenuItem>
+        )}
+        {error && <MenuItem isDisabled>{(error as Error).message}</MenuItem>}
+        {targets?.map((t) => (
+          <MenuItem key={t.kind + t.label} onClick={() => copy(t.label, t.value)}>
+            <Text>{t.label}</Text>
+          </MenuItem>
+        ))}
+      </MenuList>
+    </Menu>
+  )
+}
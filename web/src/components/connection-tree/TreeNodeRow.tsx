@@ -7,11 +7,13 @@ import {
   Icon,
   Tooltip,
   Badge,
+  Checkbox,
   useColorModeValue,
   Menu,
   MenuButton,
   MenuList,
   MenuItem,
+  useDisclosure,
 } from '@chakra-ui/react'
 import {
   FiChevronRight,
@@ -30,8 +32,13 @@ import {
   FiRefreshCw,
   FiPlus,
   FiBook,
+  FiLock,
+  FiSend,
 } from 'react-icons/fi'
+import { useConnectionStore } from '../../stores/connectionStore'
 import { getNodeIconComponent, getNodeColor } from './utils'
+import { CopyMenu } from './CopyMenu'
+import WriteAccessTooltip, { useWriteDenied } from '../common/WriteAccess'
 import type { TreeNodeRowProps } from './types'
 
 export function TreeNodeRow({
@@ -40,6 +47,8 @@ export function TreeNodeRow({
   isSelected,
   isLoading,
   onClick,
+  isMarked,
+  onToggleMark,
   onAdd,
   onEdit,
   onDelete,
@@ -49,10 +58,13 @@ export function TreeNodeRow({
   onQuery,
   onShowData,
   onUpload,
+  onPublish,
   onRefresh,
   onDownloadConfig,
   onDownloadData,
   onJupyter,
+  copyTarget,
+  badge,
   downloadDataLabel,
   level,
   isLeaf,
@@ -69,6 +81,36 @@ export function TreeNodeRow({
   const chevronColor = useColorModeValue('gray.500', 'gray.400')
   const nodeColor = getNodeColor(node.type)
   const NodeIcon = getNodeIconComponent(node.type)
+  const copyMenu = useDisclosure()
+  // A read-only connection can still be edited itself, but nothing under it can change
+  const readOnly = useConnectionStore((state) =>
+    state.connections.some((c) => c.id === node.connectionId && c.readOnly)
+  )
+  const changesServer = readOnly && node.type !== 'connection'
+  // Credentials the server only lets read grey the actions out instead, saying why
+  const serverDenied = useWriteDenied(readOnly ? undefined : node.connectionId)
+  const writeDenied = node.type !== 'connection' ? serverDenied : null
+
+  const handleClick = (e: React.MouseEvent) => {
+    if (onToggleMark && (e.ctrlKey || e.metaKey)) {
+      onToggleMark()
+    } else {
+      onClick()
+    }
+  }
+
+  const handleKeyDown = (e: React.KeyboardEvent) => {
+    if (e.target !== e.currentTarget) return
+    if (e.key === ' ' && onToggleMark) {
+      e.preventDefault()
+      onToggleMark()
+    } else if (e.key === 'Enter') {
+      onClick()
+    } else if (e.key === 'y' && copyTarget) {
+      e.preventDefault()
+      copyMenu.onOpen()
+    }
+  }
 
   return (
     <Flex
@@ -86,7 +128,10 @@ export function TreeNodeRow({
       }}
       borderRadius="md"
       transition="all 0.15s ease"
-      onClick={onClick}
+      onClick={handleClick}
+      onKeyDown={handleKeyDown}
+      tabIndex={0}
+      _focusVisible={{ outline: '2px solid', outlineColor: borderColor }}
       role="group"
       mr={1}
       my={0.5}
@@ -103,6 +148,18 @@ export function TreeNodeRow({
         </Box>
       )}
       {isLeaf && <Box w={4} mr={2} />}
+      {onToggleMark && (
+        <Checkbox
+          isChecked={!!isMarked}
+          onChange={onToggleMark}
+          onClick={(e) => e.stopPropagation()}
+          colorScheme="kartoza"
+          size="sm"
+          mr={2}
+          aria-label={`Mark ${node.name}`}
+          tabIndex={-1}
+        />
+      )}
       <Box
         p={1.5}
         borderRadius="md"
@@ -117,6 +174,11 @@ export function TreeNodeRow({
           color={nodeColor}
         />
       </Box>
+      {badge?.label && (
+        <Text as="span" fontSize="sm" mr={1} color={badge.color} fontWeight="600">
+          {badge.label}
+        </Text>
+      )}
       <Text
         flex="1"
         fontSize="sm"
@@ -127,6 +189,29 @@ export function TreeNodeRow({
       >
         {node.name}
       </Text>
+      {(readOnly || serverDenied) && node.type === 'connection' && (
+        <Tooltip
+          label={serverDenied || 'Read-only: changes to this server are refused'}
+          fontSize="xs"
+        >
+          <span>
+            <Icon as={FiLock} boxSize={3} color="gray.500" mr={2} />
+          </span>
+        </Tooltip>
+      )}
+      {badge?.environment && (
+        <Badge
+          fontSize="2xs"
+          borderRadius="full"
+          px={2}
+          mr={2}
+          color="white"
+          bg={badge.color || 'gray.500'}
+          textTransform="lowercase"
+        >
+          {badge.environment}
+        </Badge>
+      )}
       {count !== undefined && count >= 0 && (
         <Badge
           colorScheme={nodeColor.split('.')[0]}
@@ -141,19 +226,21 @@ export function TreeNodeRow({
         </Badge>
       )}
       {/* Add button - always visible for root nodes */}
-      {onAdd && (
-        <Tooltip label="Add" fontSize="xs">
-          <IconButton
-            aria-label="Add"
-            icon={<FiPlus size={14} />}
-            size="xs"
-            variant="ghost"
-            colorScheme="green"
-            onClick={onAdd}
-            _hover={{ bg: 'green.50' }}
-            mr={1}
-          />
-        </Tooltip>
+      {onAdd && !readOnly && (
+        <WriteAccessTooltip reason={serverDenied} mr={1}>
+          <Tooltip label="Add" fontSize="xs" isDisabled={!!serverDenied}>
+            <IconButton
+              aria-label="Add"
+              icon={<FiPlus size={14} />}
+              size="xs"
+              variant="ghost"
+              colorScheme="green"
+              onClick={onAdd}
+              isDisabled={!!serverDenied}
+              _hover={{ bg: 'green.50' }}
+            />
+          </Tooltip>
+        </WriteAccessTooltip>
       )}
       {/* Admin link - always visible for connections */}
       {onOpenAdmin && (
@@ -172,10 +259,18 @@ export function TreeNodeRow({
       )}
       <Flex
         gap={1}
-        opacity={0}
+        opacity={copyMenu.isOpen ? 1 : 0}
         _groupHover={{ opacity: 1 }}
         transition="opacity 0.15s"
       >
+        {copyTarget && (
+          <CopyMenu
+            target={copyTarget}
+            isOpen={copyMenu.isOpen}
+            onOpen={copyMenu.onOpen}
+            onClose={copyMenu.onClose}
+          />
+        )}
         {(onDownloadConfig || onDownloadData) && (
           <Menu isLazy placement="bottom-end">
             <Tooltip label="Download" fontSize="xs">
@@ -230,15 +325,31 @@ export function TreeNodeRow({
             />
           </Tooltip>
         )}
-        {onUpload && (
-          <Tooltip label="Import Data" fontSize="xs">
+        {onUpload && !readOnly && (
+          <WriteAccessTooltip reason={serverDenied}>
+            <Tooltip label="Import Data" fontSize="xs" isDisabled={!!serverDenied}>
+              <IconButton
+                aria-label="Import Data"
+                icon={<FiUpload size={14} />}
+                size="xs"
+                variant="ghost"
+                colorScheme="green"
+                onClick={onUpload}
+                isDisabled={!!serverDenied}
+                _hover={{ bg: 'green.50' }}
+              />
+            </Tooltip>
+          </WriteAccessTooltip>
+        )}
+        {onPublish && (
+          <Tooltip label="Publish to GeoServer" fontSize="xs">
             <IconButton
-              aria-label="Import Data"
-              icon={<FiUpload size={14} />}
+              aria-label="Publish to GeoServer"
+              icon={<FiSend size={14} />}
               size="xs"
               variant="ghost"
               colorScheme="green"
-              onClick={onUpload}
+              onClick={onPublish}
               _hover={{ bg: 'green.50' }}
             />
           </Tooltip>
@@ -295,31 +406,37 @@ export function TreeNodeRow({
             />
           </Tooltip>
         )}
-        {onEdit && (
-          <Tooltip label="Edit" fontSize="xs">
-            <IconButton
-              aria-label="Edit"
-              icon={<FiEdit2 size={14} />}
-              size="xs"
-              variant="ghost"
-              colorScheme="kartoza"
-              onClick={onEdit}
-              _hover={{ bg: 'kartoza.100' }}
-            />
-          </Tooltip>
+        {onEdit && !changesServer && (
+          <WriteAccessTooltip reason={writeDenied}>
+            <Tooltip label="Edit" fontSize="xs" isDisabled={!!writeDenied}>
+              <IconButton
+                aria-label="Edit"
+                icon={<FiEdit2 size={14} />}
+                size="xs"
+                variant="ghost"
+                colorScheme="kartoza"
+                onClick={onEdit}
+                isDisabled={!!writeDenied}
+                _hover={{ bg: 'kartoza.100' }}
+              />
+            </Tooltip>
+          </WriteAccessTooltip>
         )}
-        {onDelete && (
-          <Tooltip label="Delete" fontSize="xs">
-            <IconButton
-              aria-label="Delete"
-              icon={<FiTrash2 size={14} />}
-              size="xs"
-              variant="ghost"
-              colorScheme="red"
-              onClick={onDelete}
-              _hover={{ bg: 'red.50' }}
-            />
-          </Tooltip>
+        {onDelete && !changesServer && (
+          <WriteAccessTooltip reason={writeDenied}>
+            <Tooltip label="Delete" fontSize="xs" isDisabled={!!writeDenied}>
+              <IconButton
+                aria-label="Delete"
+                icon={<FiTrash2 size={14} />}
+                size="xs"
+                variant="ghost"
+                colorScheme="red"
+                onClick={onDelete}
+                isDisabled={!!writeDenied}
+                _hover={{ bg: 'red.50' }}
+              />
+            </Tooltip>
+          </WriteAccessTooltip>
         )}
       </Flex>
     </Flex>
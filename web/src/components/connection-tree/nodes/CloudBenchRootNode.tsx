@@ -3,7 +3,7 @@ import { Box } from '@chakra-ui/react'
 import { useTreeStore } from '../../../stores/treeStore'
 import { useUIStore } from '../../../stores/uiStore'
 import { useProvidersStore } from '../../../stores/providersStore'
-import type { TreeNode } from '../../../types'
+import type { Connection, TreeNode } from '../../../types'
 import { TreeNodeRow } from '../TreeNodeRow'
 import { GeoServerRootNode } from './GeoServerRootNode'
 import { PostgreSQLRootNode } from './PostgreSQLRootNode'
@@ -15,7 +15,7 @@ import { QFieldCloudRootNode } from './QFieldCloudRootNode'
 import { MerginMapsRootNode } from './MerginMapsRootNode'
 
 interface CloudBenchRootNodeProps {
-  connections: { id: string; name: string; url: string }[]
+  connections: Connection[]
 }
 
 export function CloudBenchRootNode({ connections }: CloudBenchRootNodeProps) {
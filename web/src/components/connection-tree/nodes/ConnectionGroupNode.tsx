@@ -0,0 +1,62 @@
+import { Box } from '@chakra-ui/react'
+import { useTreeStore, generateNodeId } from '../../../stores/treeStore'
+import type { Connection, TreeNode } from '../../../types'
+import { TreeNodeRow } from '../TreeNodeRow'
+import { connectionBadge } from '../utils'
+import { ConnectionNode } from './ConnectionNode'
+
+interface ConnectionGroupNodeProps {
+  name: string
+  connections: Connection[]
+}
+
+// Folder of connections sharing a group name
+export function ConnectionGroupNode({ name, connections }: ConnectionGroupNodeProps) {
+  const nodeId = generateNodeId('connectiongroup', undefined, undefined, name)
+  const isExpanded = useTreeStore((state) => state.isExpanded(nodeId))
+  const toggleNode = useTreeStore((state) => state.toggleNode)
+  const selectNode = useTreeStore((state) => state.selectNode)
+  const selectedNode = useTreeStore((state) => state.selectedNode)
+
+  const node: TreeNode = {
+    id: nodeId,
+    name,
+    type: 'connectiongroup',
+  }
+
+  const isSelected = selectedNode?.id === nodeId
+
+  const handleClick = () => {
+    selectNode(node)
+    toggleNode(nodeId)
+  }
+
+  return (
+    <Box>
+      <TreeNodeRow
+        node={node}
+        isExpanded={isExpanded}
+        isSelected={isSelected}
+        isLoading={false}
+        onClick={handleClick}
+        level={2}
+        count={connections.length}
+      />
+      {isExpanded && (
+        // Connection rows have a fixed level, so indent the whole folder instead
+        <Box ml={4}>
+          {connections.map((conn) => (
+            <ConnectionNode
+              key={conn.id}
+              connectionId={conn.id}
+              name={conn.name}
+              url={conn.url}
+              unsaved={conn.source !== undefined && conn.source !== 'config'}
+              badge={connectionBadge(conn)}
+            />
+          ))}
+        </Box>
+      )}
+    </Box>
+  )
+}
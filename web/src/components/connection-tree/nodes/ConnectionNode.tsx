@@ -6,9 +6,16 @@ import type { TreeNode } from '../../../types'
 import * as api from '../../../api'
 import { TreeNodeRow } from '../TreeNodeRow'
 import { WorkspaceNode } from './WorkspaceNode'
+import { RecentNode } from './RecentNode'
 import type { ConnectionNodeProps } from '../types'
 
-export function ConnectionNode({ connectionId, name, url }: ConnectionNodeProps) {
+export function ConnectionNode({
+  connectionId,
+  name,
+  url,
+  unsaved,
+  badge,
+}: ConnectionNodeProps) {
   const nodeId = generateNodeId('connection', connectionId)
   const isExpanded = useTreeStore((state) => state.isExpanded(nodeId))
   const toggleNode = useTreeStore((state) => state.toggleNode)
@@ -66,12 +73,20 @@ export function ConnectionNode({ connectionId, name, url }: ConnectionNodeProps)
         isSelected={isSelected}
         isLoading={isLoading}
         onClick={handleClick}
-        onEdit={handleEdit}
-        onDelete={handleDelete}
+        onEdit={unsaved ? undefined : handleEdit}
+        onDelete={unsaved ? undefined : handleDelete}
         onOpenAdmin={handleOpenAdmin}
+        copyTarget={{ connectionId, type: 'connection' }}
+        badge={badge}
         level={2}
         count={workspaces?.length}
       />
+      {isExpanded && (
+        <>
+          <RecentNode connectionId={connectionId} action="modified" />
+          <RecentNode connectionId={connectionId} action="added" />
+        </>
+      )}
       {isExpanded && workspaces && workspaces.map((ws) => (
         <WorkspaceNode
           key={ws.name}
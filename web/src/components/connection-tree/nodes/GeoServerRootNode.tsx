@@ -2,12 +2,14 @@ import { useEffect } from 'react'
 import { Box, Text } from '@chakra-ui/react'
 import { useTreeStore } from '../../../stores/treeStore'
 import { useUIStore } from '../../../stores/uiStore'
-import type { TreeNode } from '../../../types'
+import type { Connection, TreeNode } from '../../../types'
 import { TreeNodeRow } from '../TreeNodeRow'
+import { connectionBadge, groupConnections } from '../utils'
 import { ConnectionNode } from './ConnectionNode'
+import { ConnectionGroupNode } from './ConnectionGroupNode'
 
 interface GeoServerRootNodeProps {
-  connections: { id: string; name: string; url: string }[]
+  connections: Connection[]
 }
 
 export function GeoServerRootNode({ connections }: GeoServerRootNodeProps) {
@@ -64,14 +66,22 @@ export function GeoServerRootNode({ connections }: GeoServerRootNodeProps) {
               </Text>
             </Box>
           ) : (
-            connections.map((conn) => (
-              <ConnectionNode
-                key={conn.id}
-                connectionId={conn.id}
-                name={conn.name}
-                url={conn.url}
-              />
-            ))
+            groupConnections(connections).map(([group, members]) =>
+              group ? (
+                <ConnectionGroupNode key={`group:${group}`} name={group} connections={members} />
+              ) : (
+                members.map((conn) => (
+                  <ConnectionNode
+                    key={conn.id}
+                    connectionId={conn.id}
+                    name={conn.name}
+                    url={conn.url}
+                    unsaved={conn.source !== undefined && conn.source !== 'config'}
+                    badge={connectionBadge(conn)}
+                  />
+                ))
+              )
+            )
           )}
         </>
       )}
@@ -9,12 +9,22 @@ import { DataStoreContentsNode } from './DataStoreContentsNode'
 import { CoverageStoreContentsNode } from './CoverageStoreContentsNode'
 import type { ItemNodeProps } from '../types'
 
-export function ItemNode({ connectionId, workspace, name, type, storeType }: ItemNodeProps) {
+export function ItemNode({
+  connectionId,
+  workspace,
+  name,
+  type,
+  storeType,
+  badge,
+  level = 5,
+}: ItemNodeProps) {
   const nodeId = generateNodeId(type, connectionId, workspace, name)
   const isExpanded = useTreeStore((state) => state.isExpanded(nodeId))
   const toggleNode = useTreeStore((state) => state.toggleNode)
   const selectNode = useTreeStore((state) => state.selectNode)
   const selectedNode = useTreeStore((state) => state.selectedNode)
+  const isMarked = useTreeStore((state) => !!state.markedNodes[nodeId])
+  const toggleMark = useTreeStore((state) => state.toggleMark)
   const openDialog = useUIStore((state) => state.openDialog)
   const setPreview = useUIStore((state) => state.setPreview)
   const setPreviewMode = useUIStore((state) => state.setPreviewMode)
@@ -83,12 +93,14 @@ export function ItemNode({ connectionId, workspace, name, type, storeType }: Ite
 
   const handlePreview = (e: React.MouseEvent) => {
     e.stopPropagation()
-    const layerType = storeType === 'coveragestore' ? 'raster' : 'vector'
+    const layerType =
+      type === 'layergroup' ? 'group' : storeType === 'coveragestore' ? 'raster' : 'vector'
+    const storeName = type === 'layergroup' ? undefined : name
     api.startPreview({
       connId: connectionId,
       workspace,
       layerName: name,
-      storeName: name,
+      storeName,
       storeType,
       layerType,
     }).then(({ url }) => {
@@ -97,7 +109,7 @@ export function ItemNode({ connectionId, workspace, name, type, storeType }: Ite
         layerName: name,
         workspace,
         connectionId,
-        storeName: name,
+        storeName,
         storeType,
         layerType,
       })
@@ -179,7 +191,9 @@ export function ItemNode({ connectionId, workspace, name, type, storeType }: Ite
     }
     const resourceType = resourceTypeMap[type]
     if (resourceType) {
-      api.downloadResource(connectionId, resourceType, workspace, name)
+      api.downloadResource(connectionId, resourceType, workspace, name).catch((err) => {
+        useUIStore.getState().setError(err.message)
+      })
     }
   }
 
@@ -213,14 +227,18 @@ export function ItemNode({ connectionId, workspace, name, type, storeType }: Ite
         isSelected={isSelected}
         isLoading={isLoading}
         onClick={handleClick}
+        isMarked={isMarked}
+        onToggleMark={canDownloadConfig ? () => toggleMark(node) : undefined}
         onEdit={canEdit ? handleEdit : undefined}
-        onPreview={type === 'layer' || type === 'datastore' || type === 'coveragestore' ? handlePreview : undefined}
+        onPreview={['layer', 'layergroup', 'datastore', 'coveragestore'].includes(type) ? handlePreview : undefined}
         onTerria={type === 'layer' || type === 'layergroup' ? handleTerria : undefined}
         onDownloadConfig={canDownloadConfig ? handleDownloadConfig : undefined}
         onDownloadData={canDownloadData ? handleDownloadData : undefined}
         downloadDataLabel={downloadDataLabel}
+        copyTarget={{ connectionId, type, workspace, name, storeType }}
         onDelete={handleDelete}
-        level={5}
+        badge={badge}
+        level={level}
         isLeaf={!isExpandable}
         count={totalCount}
       />
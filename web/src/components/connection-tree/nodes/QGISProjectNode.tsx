@@ -46,6 +46,14 @@ export function QGISProjectNode({ project }: QGISProjectNodeProps) {
     })
   }
 
+  const handlePublish = (e: React.MouseEvent) => {
+    e.stopPropagation()
+    openDialog('qgispublish', {
+      mode: 'create',
+      data: { projectId: project.id, projectName: project.name },
+    })
+  }
+
   const handleDelete = (e: React.MouseEvent) => {
     e.stopPropagation()
     openDialog('confirm', {
@@ -65,6 +73,7 @@ export function QGISProjectNode({ project }: QGISProjectNodeProps) {
         isLoading={false}
         onClick={handleClick}
         onPreview={handlePreview}
+        onPublish={handlePublish}
         onEdit={handleEdit}
         onDelete={handleDelete}
         level={2}
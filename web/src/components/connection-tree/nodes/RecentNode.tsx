@@ -0,0 +1,63 @@
+import { Box, Text } from '@chakra-ui/react'
+import { useQuery } from '@tanstack/react-query'
+import { useTreeStore, generateNodeId } from '../../../stores/treeStore'
+import type { TreeNode } from '../../../types'
+import * as api from '../../../api'
+import { TreeNodeRow } from '../TreeNodeRow'
+import { ItemNode } from './ItemNode'
+import type { RecentNodeProps } from '../types'
+
+export function RecentNode({ connectionId, action }: RecentNodeProps) {
+  const type = action === 'added' ? 'recentadded' : 'recentmodified'
+  const nodeId = generateNodeId(type, connectionId)
+  const isExpanded = useTreeStore((state) => state.isExpanded(nodeId))
+  const toggleNode = useTreeStore((state) => state.toggleNode)
+
+  // Both nodes share one request; finding the items reads every layer and style
+  const { data, isLoading, error } = useQuery({
+    queryKey: ['recent', connectionId],
+    queryFn: () => api.getRecentItems(connectionId),
+    enabled: isExpanded,
+    staleTime: 30000,
+  })
+
+  // The tree only browses workspaces, so global styles are left out
+  const items = data?.[action].filter((item) => item.workspace) ?? []
+
+  const node: TreeNode = {
+    id: nodeId,
+    name: action === 'added' ? 'Recently Added' : 'Recently Modified',
+    type,
+    connectionId,
+  }
+
+  return (
+    <Box>
+      <TreeNodeRow
+        node={node}
+        isExpanded={isExpanded}
+        isSelected={false}
+        isLoading={isExpanded && isLoading}
+        onClick={() => toggleNode(nodeId)}
+        level={3}
+        count={data ? items.length : undefined}
+      />
+      {isExpanded && error && (
+        <Text fontSize="xs" color="red.500" px={2} py={2} ml={6 * 4}>
+          Error loading recent items: {(error as Error).message}
+        </Text>
+      )}
+      {isExpanded && items.map((item) => (
+        <ItemNode
+          key={`${item.type}:${item.workspace}:${item.name}`}
+          connectionId={connectionId}
+          workspace={item.workspace!}
+          name={item.name}
+          type={item.type}
+          badge={{ environment: item.workspace! }}
+          level={4}
+        />
+      ))}
+    </Box>
+  )
+}
@@ -47,7 +47,9 @@ export function WorkspaceNode({ connectionId, workspace }: WorkspaceNodeProps) {
 
   const handleDownloadConfig = (e: React.MouseEvent) => {
     e.stopPropagation()
-    api.downloadResource(connectionId, 'workspace', workspace)
+    api.downloadResource(connectionId, 'workspace', workspace).catch((err) => {
+      useUIStore.getState().setError(err.message)
+    })
   }
 
   return (
@@ -60,6 +62,7 @@ export function WorkspaceNode({ connectionId, workspace }: WorkspaceNodeProps) {
         onClick={handleClick}
         onEdit={handleEdit}
         onDownloadConfig={handleDownloadConfig}
+        copyTarget={{ connectionId, type: 'workspace', workspace }}
         onDelete={handleDelete}
         level={3}
       />
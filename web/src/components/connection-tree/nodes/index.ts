@@ -3,8 +3,10 @@ export { GeoServerRootNode } from './GeoServerRootNode'
 export { PostgreSQLRootNode } from './PostgreSQLRootNode'
 export { S3StorageRootNode } from './S3StorageRootNode'
 export { ConnectionNode } from './ConnectionNode'
+export { ConnectionGroupNode } from './ConnectionGroupNode'
 export { WorkspaceNode } from './WorkspaceNode'
 export { CategoryNode } from './CategoryNode'
+export { RecentNode } from './RecentNode'
 export { ItemNode } from './ItemNode'
 export { PGServiceNode } from './PGServiceNode'
 export { PGSchemaNode } from './PGSchemaNode'
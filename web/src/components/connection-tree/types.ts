@@ -1,9 +1,11 @@
-import type { TreeNode, NodeType } from '../../types'
+import type { TreeNode, NodeType, CopyTargetQuery } from '../../types'
 
 export interface ConnectionNodeProps {
   connectionId: string
   name: string
   url: string
+  unsaved?: boolean // Defined by environment variables or CLI flags
+  badge?: ConnectionBadge
 }
 
 export interface WorkspaceNodeProps {
@@ -24,6 +26,13 @@ export interface ItemNodeProps {
   name: string
   type: NodeType
   storeType?: string
+  badge?: ConnectionBadge // Shown outside a workspace, e.g. the workspace name
+  level?: number
+}
+
+export interface RecentNodeProps {
+  connectionId: string
+  action: 'added' | 'modified'
 }
 
 export interface PGServiceNodeProps {
@@ -88,12 +97,22 @@ export interface DatasetRowProps {
   onPreview?: () => void
 }
 
+// Organization shown next to a connection's name
+export interface ConnectionBadge {
+  label?: string // Emoji or short text
+  color?: string // #rrggbb
+  environment?: string
+}
+
 export interface TreeNodeRowProps {
   node: TreeNode
   isExpanded: boolean
   isSelected: boolean
   isLoading: boolean
   onClick: () => void
+  // Marking for batch actions: space or ctrl/cmd-click toggles the mark
+  isMarked?: boolean
+  onToggleMark?: () => void
   onAdd?: (e: React.MouseEvent) => void
   onEdit?: (e: React.MouseEvent) => void
   onDelete?: (e: React.MouseEvent) => void
@@ -103,10 +122,14 @@ export interface TreeNodeRowProps {
   onQuery?: (e: React.MouseEvent) => void
   onShowData?: (e: React.MouseEvent) => void
   onUpload?: (e: React.MouseEvent) => void
+  onPublish?: (e: React.MouseEvent) => void
   onRefresh?: (e: React.MouseEvent) => void
   onDownloadConfig?: (e: React.MouseEvent) => void
   onDownloadData?: (e: React.MouseEvent) => void
   onJupyter?: (e: React.MouseEvent) => void
+  // Resource whose URLs and names the copy menu offers; y opens it
+  copyTarget?: CopyTargetQuery
+  badge?: ConnectionBadge
   downloadDataLabel?: string
   level: number
   isLeaf?: boolean
@@ -1,4 +1,5 @@
-import type { NodeType } from '../../types'
+import type { Connection, NodeType } from '../../types'
+import type { ConnectionBadge } from './types'
 import {
   FiEye,
   FiServer,
@@ -17,6 +18,7 @@ import {
   FiHardDrive,
   FiArchive,
   FiFile,
+  FiClock,
   FiBook,
   FiBarChart2,
   FiBox,
@@ -114,6 +116,9 @@ export function getNodeIconComponent(type: NodeType | 'featuretype' | 'coverage'
     case 'layergroups':
     case 'layergroup':
       return FiGrid
+    case 'recentmodified':
+    case 'recentadded':
+      return FiClock
     case 'featuretype':
       return FiFileText
     case 'coverage':
@@ -213,6 +218,9 @@ export function getNodeColor(type: NodeType | 'featuretype' | 'coverage'): strin
     case 'layergroups':
     case 'layergroup':
       return 'cyan.500'
+    case 'recentmodified':
+    case 'recentadded':
+      return 'yellow.600'
     case 'featuretype':
       return 'teal.500'
     case 'coverage':
@@ -221,3 +229,25 @@ export function getNodeColor(type: NodeType | 'featuretype' | 'coverage'): strin
       return 'gray.500'
   }
 }
+
+// Group connections by folder: named groups sorted by name, then ungrouped ones under ''
+export function groupConnections(connections: Connection[]): [string, Connection[]][] {
+  const groups = new Map<string, Connection[]>()
+  for (const conn of connections) {
+    const group = (conn.group ?? '').trim()
+    groups.set(group, [...(groups.get(group) ?? []), conn])
+  }
+  const named = [...groups.keys()]
+    .filter((name) => name)
+    .sort((a, b) => a.localeCompare(b, undefined, { sensitivity: 'base' }))
+  const result: [string, Connection[]][] = named.map((name) => [name, groups.get(name)!])
+  if (groups.has('')) {
+    result.push(['', groups.get('')!])
+  }
+  return result
+}
+
+// Badge shown next to a connection's name in the tree
+export function connectionBadge(conn: Connection): ConnectionBadge {
+  return { label: conn.badge, color: conn.badgeColor, environment: conn.environment }
+}
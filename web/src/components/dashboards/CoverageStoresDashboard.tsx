@@ -21,6 +21,7 @@ import { FiImage, FiPlus, FiUpload } from 'react-icons/fi'
 import { useQuery } from '@tanstack/react-query'
 import * as api from '../../api'
 import { useUIStore } from '../../stores/uiStore'
+import WriteAccessTooltip, { useWriteDenied } from '../common/WriteAccess'
 import StoreCard from '../cards/StoreCard'
 
 interface CoverageStoresDashboardProps {
@@ -33,11 +34,12 @@ export default function CoverageStoresDashboard({
   workspace,
 }: CoverageStoresDashboardProps) {
   const openDialog = useUIStore((state) => state.openDialog)
+  const writeDenied = useWriteDenied(connectionId)
   const cardBg = useColorModeValue('white', 'gray.800')
 
   const { data: coveragestores } = useQuery({
-    queryKey: ['coveragestores', connectionId, workspace],
-    queryFn: () => api.getCoverageStores(connectionId, workspace),
+    queryKey: ['coveragestores', connectionId, workspace, 'enabled'],
+    queryFn: () => api.getCoverageStores(connectionId, workspace, true),
   })
 
   return (
@@ -69,24 +71,32 @@ export default function CoverageStoresDashboard({
       </Card>
 
       <SimpleGrid columns={{ base: 1, md: 2 }} spacing={4}>
-        <Button
-          size="lg"
-          variant="accent"
-          leftIcon={<FiPlus />}
-          onClick={() => openDialog('coveragestore', { mode: 'create', data: { connectionId, workspace } })}
-          py={8}
-        >
-          Create New Coverage Store
-        </Button>
-        <Button
-          size="lg"
-          variant="outline"
-          leftIcon={<FiUpload />}
-          onClick={() => openDialog('upload', { mode: 'create', data: { connectionId, workspace } })}
-          py={8}
-        >
-          Upload GeoTIFF
-        </Button>
+        <WriteAccessTooltip reason={writeDenied}>
+          <Button
+            size="lg"
+            variant="accent"
+            leftIcon={<FiPlus />}
+            onClick={() => openDialog('coveragestore', { mode: 'create', data: { connectionId, workspace } })}
+            py={8}
+            w="100%"
+            isDisabled={!!writeDenied}
+          >
+            Create New Coverage Store
+          </Button>
+        </WriteAccessTooltip>
+        <WriteAccessTooltip reason={writeDenied}>
+          <Button
+            size="lg"
+            variant="outline"
+            leftIcon={<FiUpload />}
+            onClick={() => openDialog('upload', { mode: 'create', data: { connectionId, workspace } })}
+            py={8}
+            w="100%"
+            isDisabled={!!writeDenied}
+          >
+            Upload GeoTIFF
+          </Button>
+        </WriteAccessTooltip>
       </SimpleGrid>
 
       {coveragestores && coveragestores.length > 0 && (
@@ -21,6 +21,7 @@ import { FiDatabase, FiPlus, FiUpload } from 'react-icons/fi'
 import { useQuery } from '@tanstack/react-query'
 import * as api from '../../api'
 import { useUIStore } from '../../stores/uiStore'
+import WriteAccessTooltip, { useWriteDenied } from '../common/WriteAccess'
 import StoreCard from '../cards/StoreCard'
 
 interface DataStoresDashboardProps {
@@ -33,11 +34,12 @@ export default function DataStoresDashboard({
   workspace,
 }: DataStoresDashboardProps) {
   const openDialog = useUIStore((state) => state.openDialog)
+  const writeDenied = useWriteDenied(connectionId)
   const cardBg = useColorModeValue('white', 'gray.800')
 
   const { data: datastores } = useQuery({
-    queryKey: ['datastores', connectionId, workspace],
-    queryFn: () => api.getDataStores(connectionId, workspace),
+    queryKey: ['datastores', connectionId, workspace, 'enabled'],
+    queryFn: () => api.getDataStores(connectionId, workspace, true),
   })
 
   return (
@@ -71,24 +73,32 @@ export default function DataStoresDashboard({
 
       {/* Action Buttons */}
       <SimpleGrid columns={{ base: 1, md: 2 }} spacing={4}>
-        <Button
-          size="lg"
-          variant="accent"
-          leftIcon={<FiPlus />}
-          onClick={() => openDialog('datastore', { mode: 'create', data: { connectionId, workspace } })}
-          py={8}
-        >
-          Create New Data Store
-        </Button>
-        <Button
-          size="lg"
-          variant="outline"
-          leftIcon={<FiUpload />}
-          onClick={() => openDialog('upload', { mode: 'create', data: { connectionId, workspace } })}
-          py={8}
-        >
-          Upload Shapefile / GeoPackage
-        </Button>
+        <WriteAccessTooltip reason={writeDenied}>
+          <Button
+            size="lg"
+            variant="accent"
+            leftIcon={<FiPlus />}
+            onClick={() => openDialog('datastore', { mode: 'create', data: { connectionId, workspace } })}
+            py={8}
+            w="100%"
+            isDisabled={!!writeDenied}
+          >
+            Create New Data Store
+          </Button>
+        </WriteAccessTooltip>
+        <WriteAccessTooltip reason={writeDenied}>
+          <Button
+            size="lg"
+            variant="outline"
+            leftIcon={<FiUpload />}
+            onClick={() => openDialog('upload', { mode: 'create', data: { connectionId, workspace } })}
+            py={8}
+            w="100%"
+            isDisabled={!!writeDenied}
+          >
+            Upload Shapefile / GeoPackage
+          </Button>
+        </WriteAccessTooltip>
       </SimpleGrid>
 
       {/* Store List */}
@@ -22,6 +22,7 @@ import { FiGrid, FiPlus } from 'react-icons/fi'
 import { useQuery } from '@tanstack/react-query'
 import * as api from '../../api'
 import { useUIStore } from '../../stores/uiStore'
+import WriteAccessTooltip, { useWriteDenied } from '../common/WriteAccess'
 
 interface LayerGroupsDashboardProps {
   connectionId: string
@@ -34,12 +35,22 @@ export default function LayerGroupsDashboard({
 }: LayerGroupsDashboardProps) {
   const cardBg = useColorModeValue('white', 'gray.800')
   const openDialog = useUIStore((state) => state.openDialog)
+  const writeDenied = useWriteDenied(connectionId)
 
   const { data: layergroups } = useQuery({
     queryKey: ['layergroups', connectionId, workspace],
     queryFn: () => api.getLayerGroups(connectionId, workspace),
   })
 
+  // Cached groups, with the layers drawn into their tiles
+  const { data: gwcLayers } = useQuery({
+    queryKey: ['gwc-layers', connectionId, workspace],
+    queryFn: () => api.getGWCLayers(connectionId, workspace),
+  })
+  const cachedGroups = new Map(
+    (gwcLayers?.groups || []).map((group) => [group.name, group.layers])
+  )
+
   return (
     <VStack spacing={6} align="stretch">
       <Card
@@ -63,25 +74,32 @@ export default function LayerGroupsDashboard({
                 <StatNumber fontSize="3xl">{layergroups?.length ?? 0}</StatNumber>
                 <StatLabel color="whiteAlpha.800">Total Groups</StatLabel>
               </Stat>
+              <Text fontSize="sm" color="whiteAlpha.800">
+                {cachedGroups.size} with a tile cache
+              </Text>
             </VStack>
           </Flex>
         </CardBody>
       </Card>
 
-      <Button
-        size="lg"
-        variant="accent"
-        leftIcon={<FiPlus />}
-        py={8}
-        onClick={() =>
-          openDialog('layergroup', {
-            mode: 'create',
-            data: { connectionId, workspace },
-          })
-        }
-      >
-        Create Layer Group
-      </Button>
+      <WriteAccessTooltip reason={writeDenied}>
+        <Button
+          size="lg"
+          variant="accent"
+          leftIcon={<FiPlus />}
+          py={8}
+          onClick={() =>
+            openDialog('layergroup', {
+              mode: 'create',
+              data: { connectionId, workspace },
+            })
+          }
+          w="100%"
+          isDisabled={!!writeDenied}
+        >
+          Create Layer Group
+        </Button>
+      </WriteAccessTooltip>
 
       {layergroups && layergroups.length > 0 && (
         <Card bg={cardBg}>
@@ -90,19 +108,30 @@ export default function LayerGroupsDashboard({
               <Heading size="sm" color="gray.600">Existing Layer Groups</Heading>
               <Divider />
               <SimpleGrid columns={{ base: 1, md: 2, lg: 3 }} spacing={4}>
-                {layergroups.map((group) => (
-                  <Card key={group.name} variant="outline" size="sm">
-                    <CardBody py={3} px={4}>
-                      <HStack>
-                        <Icon as={FiGrid} color="kartoza.500" />
-                        <Text fontWeight="medium">{group.name}</Text>
-                        {group.mode && (
-                          <Badge colorScheme="purple" size="sm">{group.mode}</Badge>
+                {layergroups.map((group) => {
+                  const cachedLayers = cachedGroups.get(`${workspace}:${group.name}`)
+                  return (
+                    <Card key={group.name} variant="outline" size="sm">
+                      <CardBody py={3} px={4}>
+                        <HStack>
+                          <Icon as={FiGrid} color="kartoza.500" />
+                          <Text fontWeight="medium">{group.name}</Text>
+                          {group.mode && (
+                            <Badge colorScheme="purple" size="sm">{group.mode}</Badge>
+                          )}
+                          {cachedLayers && (
+                            <Badge colorScheme="green" size="sm">Cached</Badge>
+                          )}
+                        </HStack>
+                        {cachedLayers && cachedLayers.length > 0 && (
+                          <Text fontSize="xs" color="gray.500" mt={1} noOfLines={2}>
+                            Tiles draw {cachedLayers.map((layer) => layer.name).join(', ')}
+                          </Text>
                         )}
-                      </HStack>
-                    </CardBody>
-                  </Card>
-                ))}
+                      </CardBody>
+                    </Card>
+                  )
+                })}
               </SimpleGrid>
             </VStack>
           </CardBody>
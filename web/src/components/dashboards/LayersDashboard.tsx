@@ -21,6 +21,7 @@ import { FiLayers, FiUpload } from 'react-icons/fi'
 import { useQuery } from '@tanstack/react-query'
 import * as api from '../../api'
 import { useUIStore } from '../../stores/uiStore'
+import WriteAccessTooltip, { useWriteDenied } from '../common/WriteAccess'
 import LayerCard from '../cards/LayerCard'
 
 interface LayersDashboardProps {
@@ -33,6 +34,7 @@ export default function LayersDashboard({
   workspace,
 }: LayersDashboardProps) {
   const openDialog = useUIStore((state) => state.openDialog)
+  const writeDenied = useWriteDenied(connectionId)
   const cardBg = useColorModeValue('white', 'gray.800')
 
   const { data: layers } = useQuery({
@@ -68,15 +70,19 @@ export default function LayersDashboard({
         </CardBody>
       </Card>
 
-      <Button
-        size="lg"
-        variant="accent"
-        leftIcon={<FiUpload />}
-        onClick={() => openDialog('upload', { mode: 'create', data: { connectionId, workspace } })}
-        py={8}
-      >
-        Upload New Layer Data
-      </Button>
+      <WriteAccessTooltip reason={writeDenied}>
+        <Button
+          size="lg"
+          variant="accent"
+          leftIcon={<FiUpload />}
+          onClick={() => openDialog('upload', { mode: 'create', data: { connectionId, workspace } })}
+          py={8}
+          w="100%"
+          isDisabled={!!writeDenied}
+        >
+          Upload New Layer Data
+        </Button>
+      </WriteAccessTooltip>
 
       {layers && layers.length > 0 && (
         <Card bg={cardBg}>
@@ -19,11 +19,13 @@ import {
   Badge,
   useColorModeValue,
 } from '@chakra-ui/react'
-import { FiEdit3, FiPlus, FiUpload, FiDroplet } from 'react-icons/fi'
+import { FiEdit3, FiPlus, FiUpload, FiDroplet, FiFolder, FiImage } from 'react-icons/fi'
 import { useQuery } from '@tanstack/react-query'
 import * as api from '../../api'
 import { useUIStore } from '../../stores/uiStore'
+import WriteAccessTooltip, { useWriteDenied } from '../common/WriteAccess'
 import { useConnectionStore } from '../../stores/connectionStore'
+import { publicBaseUrl, serviceUrl } from '../../utils/ows'
 
 interface StyleLegendPreviewProps {
   connectionId: string
@@ -56,10 +58,9 @@ function StyleLegendPreview({
     return <Icon as={FiDroplet} color="pink.500" boxSize={`${size}px`} />
   }
 
-  // Build the GeoServer base URL from connection URL (remove /rest suffix)
-  const geoserverUrl = connection.url.replace(/\/rest\/?$/, '')
+  const geoserverUrl = publicBaseUrl(connection)
   // Use the layer we found with STYLE parameter to get the specific style's legend
-  const legendUrl = `${geoserverUrl}/${workspace}/wms?SERVICE=WMS&VERSION=1.1.1&REQUEST=GetLegendGraphic&LAYER=${workspace}:${layerWithStyle.name}&STYLE=${styleName}&FORMAT=image/png&WIDTH=${size}&HEIGHT=${size}&LEGEND_OPTIONS=forceLabels:off;fontAntiAliasing:true`
+  const legendUrl = `${serviceUrl(geoserverUrl, 'wms', workspace)}?SERVICE=WMS&VERSION=1.1.1&REQUEST=GetLegendGraphic&LAYER=${workspace}:${layerWithStyle.name}&STYLE=${styleName}&FORMAT=image/png&WIDTH=${size}&HEIGHT=${size}&LEGEND_OPTIONS=forceLabels:off;fontAntiAliasing:true`
 
   return (
     <Box
@@ -90,6 +91,7 @@ export default function StylesDashboard({
   workspace,
 }: StylesDashboardProps) {
   const openDialog = useUIStore((state) => state.openDialog)
+  const writeDenied = useWriteDenied(connectionId)
   const cardBg = useColorModeValue('white', 'gray.800')
 
   const { data: styles } = useQuery({
@@ -126,26 +128,58 @@ export default function StylesDashboard({
       </Card>
 
       <HStack spacing={4}>
-        <Button
-          size="lg"
-          variant="accent"
-          leftIcon={<FiPlus />}
-          onClick={() => openDialog('style', { mode: 'create', data: { connectionId, workspace } })}
-          py={8}
-          flex={1}
-        >
-          Create Style
-        </Button>
-        <Button
-          size="lg"
-          variant="outline"
-          leftIcon={<FiUpload />}
-          onClick={() => openDialog('upload', { mode: 'create', data: { connectionId, workspace } })}
-          py={8}
-          flex={1}
-        >
-          Upload SLD / CSS
-        </Button>
+        <WriteAccessTooltip reason={writeDenied} flex={1}>
+          <Button
+            size="lg"
+            variant="accent"
+            leftIcon={<FiPlus />}
+            onClick={() => openDialog('style', { mode: 'create', data: { connectionId, workspace } })}
+            py={8}
+            w="100%"
+            isDisabled={!!writeDenied}
+          >
+            Create Style
+          </Button>
+        </WriteAccessTooltip>
+        <WriteAccessTooltip reason={writeDenied} flex={1}>
+          <Button
+            size="lg"
+            variant="outline"
+            leftIcon={<FiUpload />}
+            onClick={() => openDialog('upload', { mode: 'create', data: { connectionId, workspace } })}
+            py={8}
+            w="100%"
+            isDisabled={!!writeDenied}
+          >
+            Upload SLD / CSS
+          </Button>
+        </WriteAccessTooltip>
+        <WriteAccessTooltip reason={writeDenied} flex={1}>
+          <Button
+            size="lg"
+            variant="outline"
+            leftIcon={<FiFolder />}
+            onClick={() => openDialog('styleimport', { mode: 'create', data: { connectionId, workspace } })}
+            py={8}
+            w="100%"
+            isDisabled={!!writeDenied}
+          >
+            Import Folder
+          </Button>
+        </WriteAccessTooltip>
+        <WriteAccessTooltip reason={writeDenied} flex={1}>
+          <Button
+            size="lg"
+            variant="outline"
+            leftIcon={<FiImage />}
+            onClick={() => openDialog('styleassets', { mode: 'edit', data: { connectionId, workspace } })}
+            py={8}
+            w="100%"
+            isDisabled={!!writeDenied}
+          >
+            Graphics
+          </Button>
+        </WriteAccessTooltip>
       </HStack>
 
       {styles && styles.length > 0 && (
@@ -15,10 +15,27 @@ import {
   Box,
   Icon,
   Divider,
+  NumberInput,
+  NumberInputField,
+  Badge,
 } from '@chakra-ui/react'
-import { FiSettings, FiEye } from 'react-icons/fi'
+import { FiSettings, FiEye, FiUploadCloud, FiZap, FiClock } from 'react-icons/fi'
 import { SiPostgresql } from 'react-icons/si'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
 import { useUIStore } from '../../stores/uiStore'
+import * as api from '../../api'
+import type { RequestTimeouts } from '../../types'
+import DefaultStylesSettings from './DefaultStylesSettings'
+
+const TIMEOUTS: { kind: keyof RequestTimeouts; label: string; help: string }[] = [
+  {
+    kind: 'upload',
+    label: 'Uploads',
+    help: 'Includes GeoServer ingesting the file before it answers',
+  },
+  { kind: 'download', label: 'Downloads', help: 'Data directory files, backups and exports' },
+  { kind: 'ows', label: 'OWS requests', help: 'WMS, WFS, WCS and WMTS requests and previews' },
+]
 
 export default function AppSettingsDialog() {
   const activeDialog = useUIStore((state) => state.activeDialog)
@@ -27,6 +44,33 @@ export default function AppSettingsDialog() {
   const setShowHiddenPGServices = useUIStore((state) => state.setShowHiddenPGServices)
 
   const isOpen = activeDialog === 'settings'
+  const queryClient = useQueryClient()
+
+  // Stored in the config file, so the TUI and batch uploads share it
+  const { data: appSettings } = useQuery({
+    queryKey: ['app-settings'],
+    queryFn: api.getAppSettings,
+    enabled: isOpen,
+  })
+  const updateSettings = useMutation({
+    mutationFn: api.updateAppSettings,
+    onSuccess: (data) => queryClient.setQueryData(['app-settings'], data),
+  })
+
+  // Hooks are written in the config file; here they can be switched and tried out
+  const { data: eventHooks } = useQuery({
+    queryKey: ['event-hooks'],
+    queryFn: api.getEventHooks,
+    enabled: isOpen,
+  })
+  const updateHooks = useMutation({
+    mutationFn: api.updateEventHooks,
+    onSuccess: (data) => queryClient.setQueryData(['event-hooks'], data),
+  })
+  const testHook = useMutation({
+    mutationFn: (name: string) => api.testEventHook(name),
+    onSettled: () => queryClient.invalidateQueries({ queryKey: ['event-hooks'] }),
+  })
 
   return (
     <Modal isOpen={isOpen} onClose={closeDialog} size="md" isCentered>
@@ -95,6 +139,178 @@ export default function AppSettingsDialog() {
                 They won't be used by applications but can be restored later.
               </Text>
             </Box>
+
+            <Divider />
+
+            {/* Uploads Section */}
+            <Box>
+              <HStack spacing={2} mb={4}>
+                <Icon as={FiUploadCloud} color="blue.600" />
+                <Text fontWeight="600" color="gray.700">
+                  Uploads
+                </Text>
+              </HStack>
+
+              <FormControl display="flex" alignItems="center" justifyContent="space-between">
+                <Box>
+                  <FormLabel htmlFor="cog-threshold" mb={0}>
+                    Convert GeoTIFFs to COG over (MB)
+                  </FormLabel>
+                  <Text fontSize="xs" color="gray.500">
+                    GeoTIFFs without tiles or overviews over this size are converted without
+                    asking; 0 always asks
+                  </Text>
+                </Box>
+                <NumberInput
+                  id="cog-threshold"
+                  size="sm"
+                  w="90px"
+                  min={0}
+                  value={appSettings?.cogAutoConvertMb ?? 0}
+                  onChange={(_, value) =>
+                    updateSettings.mutate({ cogAutoConvertMb: Number.isNaN(value) ? 0 : value })
+                  }
+                >
+                  <NumberInputField />
+                </NumberInput>
+              </FormControl>
+            </Box>
+
+            <Divider />
+
+            {/* Timeouts Section */}
+            <Box>
+              <HStack spacing={2} mb={1}>
+                <Icon as={FiClock} color="blue.600" />
+                <Text fontWeight="600" color="gray.700">
+                  Timeouts (seconds)
+                </Text>
+              </HStack>
+              <Text fontSize="xs" color="gray.500" mb={4}>
+                How long a request may go without sending or receiving data, so big transfers
+                that keep moving never time out; 0 waits as long as it takes. Catalog requests
+                use each connection's response timeout.
+              </Text>
+              <VStack spacing={3} align="stretch">
+                {TIMEOUTS.map(({ kind, label, help }) => (
+                  <FormControl
+                    key={kind}
+                    display="flex"
+                    alignItems="center"
+                    justifyContent="space-between"
+                  >
+                    <Box>
+                      <FormLabel htmlFor={`timeout-${kind}`} mb={0}>
+                        {label}
+                      </FormLabel>
+                      <Text fontSize="xs" color="gray.500">
+                        {help}
+                      </Text>
+                    </Box>
+                    <NumberInput
+                      id={`timeout-${kind}`}
+                      size="sm"
+                      w="90px"
+                      min={0}
+                      value={appSettings?.timeouts[kind] ?? 0}
+                      isDisabled={!appSettings}
+                      onChange={(_, value) =>
+                        appSettings &&
+                        updateSettings.mutate({
+                          timeouts: {
+                            ...appSettings.timeouts,
+                            [kind]: Number.isNaN(value) ? 0 : value,
+                          },
+                        })
+                      }
+                    >
+                      <NumberInputField />
+                    </NumberInput>
+                  </FormControl>
+                ))}
+              </VStack>
+            </Box>
+
+            <Divider />
+
+            {/* Default Styles Section */}
+            <DefaultStylesSettings isOpen={isOpen} />
+
+            <Divider />
+
+            {/* Hooks Section */}
+            <Box>
+              <HStack spacing={2} mb={4}>
+                <Icon as={FiZap} color="blue.600" />
+                <Text fontWeight="600" color="gray.700">
+                  Hooks
+                </Text>
+              </HStack>
+
+              {!eventHooks?.hooks.length && (
+                <Text fontSize="sm" color="gray.500">
+                  No hooks yet. Add commands or webhooks to run on events such as
+                  layer.published under "event_hooks" in the config file.
+                </Text>
+              )}
+              <VStack spacing={3} align="stretch">
+                {eventHooks?.hooks.map((hook) => (
+                  <HStack key={hook.name} justify="space-between">
+                    <Box minW={0}>
+                      <Text fontSize="sm" fontWeight="500" noOfLines={1}>
+                        {hook.name}
+                      </Text>
+                      <Text fontSize="xs" color="gray.500" noOfLines={1}>
+                        {hook.events.join(', ')} → {hook.command || `${hook.method} ${hook.url}`}
+                      </Text>
+                    </Box>
+                    <HStack spacing={2}>
+                      <Button
+                        size="xs"
+                        variant="outline"
+                        isLoading={testHook.isPending && testHook.variables === hook.name}
+                        onClick={() => testHook.mutate(hook.name)}
+                      >
+                        Test
+                      </Button>
+                      <Switch
+                        colorScheme="blue"
+                        isChecked={hook.enabled}
+                        onChange={(e) =>
+                          updateHooks.mutate(
+                            eventHooks.hooks.map((h) =>
+                              h.name === hook.name ? { ...h, enabled: e.target.checked } : h
+                            )
+                          )
+                        }
+                      />
+                    </HStack>
+                  </HStack>
+                ))}
+              </VStack>
+
+              {!!eventHooks?.deliveries.length && (
+                <Box mt={4}>
+                  <Text fontSize="xs" fontWeight="600" color="gray.600" mb={2}>
+                    Recent deliveries
+                  </Text>
+                  <VStack spacing={1} align="stretch">
+                    {eventHooks.deliveries.slice(0, 5).map((delivery) => (
+                      <HStack key={`${delivery.hook}-${delivery.time}`} spacing={2} fontSize="xs">
+                        <Badge colorScheme={delivery.success ? 'green' : 'red'}>
+                          {delivery.success ? 'OK' : 'Failed'}
+                        </Badge>
+                        <Text fontWeight="500">{delivery.hook}</Text>
+                        <Text color="gray.500">{delivery.event}</Text>
+                        <Text color="gray.500" noOfLines={1} title={delivery.message}>
+                          {delivery.message}
+                        </Text>
+                      </HStack>
+                    ))}
+                  </VStack>
+                </Box>
+              )}
+            </Box>
           </VStack>
         </ModalBody>
 
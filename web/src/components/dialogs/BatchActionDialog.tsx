@@ -0,0 +1,305 @@
+import { useState, useEffect } from 'react'
+import {
+  Modal,
+  ModalOverlay,
+  ModalContent,
+  ModalHeader,
+  ModalFooter,
+  ModalBody,
+  ModalCloseButton,
+  Button,
+  FormControl,
+  FormLabel,
+  Input,
+  Checkbox,
+  VStack,
+  HStack,
+  Box,
+  Text,
+  Icon,
+  Badge,
+  Progress,
+  Alert,
+  AlertIcon,
+} from '@chakra-ui/react'
+import { useQueryClient } from '@tanstack/react-query'
+import { FiCheckCircle, FiXCircle } from 'react-icons/fi'
+import { useUIStore } from '../../stores/uiStore'
+import { useTreeStore } from '../../stores/treeStore'
+import * as api from '../../api'
+import type { BatchAction, BatchResult } from '../../types'
+import {
+  TypedConfirmation,
+  useTypedConfirmation,
+  confirmationMatches,
+  batchConfirmationText,
+} from '../common'
+
+const ACTION_LABELS: Record<BatchAction, { title: string; button: string; color: string }> = {
+  delete: { title: 'Delete Layers', button: 'Delete', color: 'red' },
+  truncate: { title: 'Truncate Tile Caches', button: 'Truncate', color: 'orange' },
+  export: { title: 'Download Layer Configurations', button: 'Download', color: 'kartoza' },
+  group: { title: 'New Layer Group', button: 'Create Group', color: 'kartoza' },
+}
+
+function downloadText(text: string, filename: string, type: string) {
+  const blob = new Blob([text], { type })
+  const url = URL.createObjectURL(blob)
+  const a = document.createElement('a')
+  a.href = url
+  a.download = filename
+  a.click()
+  URL.revokeObjectURL(url)
+}
+
+// Saves the JSON with a sha256sum-style checksum file next to it, for checking before a restore
+async function downloadJson(data: unknown, filename: string) {
+  const text = JSON.stringify(data, null, 2)
+  downloadText(text, filename, 'application/json')
+  const digest = await crypto.subtle.digest('SHA-256', new TextEncoder().encode(text))
+  const hex = Array.from(new Uint8Array(digest), (b) => b.toString(16).padStart(2, '0')).join('')
+  downloadText(`${hex}  ${filename}\n`, `${filename}.sha256`, 'text/plain')
+}
+
+export default function BatchActionDialog() {
+  const activeDialog = useUIStore((state) => state.activeDialog)
+  const dialogData = useUIStore((state) => state.dialogData)
+  const closeDialog = useUIStore((state) => state.closeDialog)
+  const markedNodes = useTreeStore((state) => state.markedNodes)
+  const clearMarks = useTreeStore((state) => state.clearMarks)
+  const queryClient = useQueryClient()
+
+  const [groupName, setGroupName] = useState('')
+  const [groupTitle, setGroupTitle] = useState('')
+  const [recurse, setRecurse] = useState(false)
+  const [isRunning, setIsRunning] = useState(false)
+  const [done, setDone] = useState(0)
+  const [results, setResults] = useState<BatchResult[] | null>(null)
+  const [typed, setTyped] = useState('')
+
+  const isOpen = activeDialog === 'batch'
+  const action = (dialogData?.data?.action as BatchAction) || 'delete'
+  const labels = ACTION_LABELS[action]
+  // Stores, styles and groups can be marked for a snapshot; these actions are for layers
+  const marked = Object.values(markedNodes).filter(
+    (node) => node.type === 'layer' && node.connectionId && node.workspace
+  )
+  const connectionIds = [...new Set(marked.map((node) => node.connectionId as string))]
+  const total = action === 'group' ? 1 : marked.length
+  const typedConfirmation = useTypedConfirmation(connectionIds)
+  // Deletes and truncates on production connections are confirmed by typing
+  const confirmText =
+    typedConfirmation && (action === 'delete' || action === 'truncate')
+      ? batchConfirmationText(action, marked.length)
+      : ''
+
+  useEffect(() => {
+    if (isOpen) {
+      setGroupName('')
+      setGroupTitle('')
+      setRecurse(false)
+      setDone(0)
+      setResults(null)
+      setTyped('')
+    }
+  }, [isOpen])
+
+  const run = async () => {
+    setIsRunning(true)
+    const collected: BatchResult[] = []
+    const exported: Record<string, unknown> = {}
+
+    if (action === 'group') {
+      const layers = marked.map((node) => `${node.workspace}:${node.name}`)
+      try {
+        const response = await api.runLayerBatch(connectionIds[0], 'group', layers, {
+          name: groupName,
+          title: groupTitle || undefined,
+        })
+        collected.push(...response.results)
+      } catch (err) {
+        collected.push({ target: groupName, ok: false, message: (err as Error).message, data: {} })
+      }
+      setDone(1)
+    } else if (action === 'truncate') {
+      // One background job per connection truncates its layers several at a time
+      const byConnection = connectionIds.map((connId) => ({
+        connId,
+        layers: marked
+          .filter((node) => node.connectionId === connId)
+          .map((node) => `${node.workspace}:${node.name}`),
+      }))
+      const finished: BatchResult[][] = []
+      for (const { connId, layers } of byConnection) {
+        try {
+          let job = await api.startMassTruncate(connId, layers)
+          while (job.status === 'running') {
+            setDone(finished.flat().length + job.done)
+            setResults([...finished.flat(), ...job.results])
+            await new Promise((resolve) => setTimeout(resolve, 1000))
+            job = await api.getMassTruncateJob(connId, job.id)
+          }
+          finished.push(job.results)
+        } catch (err) {
+          const message = (err as Error).message
+          finished.push(layers.map((target) => ({ target, ok: false, message, data: {} })))
+        }
+        setDone(finished.flat().length)
+      }
+      collected.push(...finished.flat())
+    } else {
+      // One request per layer so the progress bar follows along
+      for (const node of marked) {
+        const layer = `${node.workspace}:${node.name}`
+        try {
+          const response = await api.runLayerBatch(node.connectionId as string, action, [layer], {
+            recurse,
+          })
+          for (const result of response.results) {
+            collected.push(result)
+            if (result.ok && action === 'export') {
+              exported[`${node.connectionId}/${result.target}`] = result.data
+            }
+          }
+        } catch (err) {
+          collected.push({ target: layer, ok: false, message: (err as Error).message, data: {} })
+        }
+        setDone(collected.length)
+        setResults([...collected])
+      }
+    }
+
+    if (action === 'export' && Object.keys(exported).length > 0) {
+      await downloadJson(exported, 'layer-configs.json')
+    }
+    if (action === 'delete' || action === 'group') {
+      for (const connId of connectionIds) {
+        queryClient.invalidateQueries({ queryKey: ['layers', connId] })
+        queryClient.invalidateQueries({ queryKey: ['layergroups', connId] })
+      }
+    }
+    if (action === 'delete' && collected.every((result) => result.ok)) {
+      clearMarks()
+    }
+
+    setResults(collected)
+    setIsRunning(false)
+  }
+
+  const okCount = results?.filter((r) => r.ok).length ?? 0
+  const failedCount = (results?.length ?? 0) - okCount
+  const canRun =
+    marked.length > 0 &&
+    !isRunning &&
+    results === null &&
+    (action !== 'group' || (groupName.trim() !== '' && connectionIds.length === 1)) &&
+    (confirmText === '' || confirmationMatches(confirmText, typed))
+
+  return (
+    <Modal isOpen={isOpen} onClose={closeDialog} size="lg" scrollBehavior="inside">
+      <ModalOverlay />
+      <ModalContent>
+        <ModalHeader>{labels.title}</ModalHeader>
+        <ModalCloseButton isDisabled={isRunning} />
+        <ModalBody>
+          <VStack align="stretch" spacing={4}>
+            <Text fontSize="sm">
+              {marked.length} marked layer{marked.length === 1 ? '' : 's'}
+              {connectionIds.length > 1 && ` on ${connectionIds.length} connections`}
+            </Text>
+
+            {action === 'group' && connectionIds.length > 1 && (
+              <Alert status="warning" borderRadius="md" fontSize="sm">
+                <AlertIcon />
+                A layer group can only contain layers of one connection.
+              </Alert>
+            )}
+
+            {action === 'group' && results === null && (
+              <>
+                <FormControl isRequired>
+                  <FormLabel fontSize="sm">Group Name</FormLabel>
+                  <Input value={groupName} onChange={(e) => setGroupName(e.target.value)} />
+                </FormControl>
+                <FormControl>
+                  <FormLabel fontSize="sm">Title</FormLabel>
+                  <Input value={groupTitle} onChange={(e) => setGroupTitle(e.target.value)} />
+                </FormControl>
+              </>
+            )}
+
+            {action === 'delete' && results === null && (
+              <Checkbox isChecked={recurse} onChange={(e) => setRecurse(e.target.checked)}>
+                Also delete the underlying feature types and coverages
+              </Checkbox>
+            )}
+
+            {confirmText && results === null && (
+              <TypedConfirmation expected={confirmText} value={typed} onChange={setTyped} />
+            )}
+
+            {(isRunning || results !== null) && (
+              <Box>
+                <Progress
+                  value={total > 0 ? (done / total) * 100 : 0}
+                  size="sm"
+                  colorScheme={failedCount > 0 ? 'orange' : 'kartoza'}
+                  borderRadius="full"
+                  mb={2}
+                />
+                <HStack spacing={2}>
+                  <Badge colorScheme="green">{okCount} succeeded</Badge>
+                  {failedCount > 0 && <Badge colorScheme="red">{failedCount} failed</Badge>}
+                  <Text fontSize="xs" color="gray.500">
+                    {done} of {total}
+                  </Text>
+                </HStack>
+              </Box>
+            )}
+
+            <VStack align="stretch" spacing={1} maxH="300px" overflowY="auto">
+              {results === null
+                ? marked.map((node) => (
+                    <Text key={node.id} fontSize="sm" fontFamily="mono">
+                      {node.workspace}:{node.name}
+                    </Text>
+                  ))
+                : results.map((result) => (
+                    <HStack key={result.target} spacing={2} align="start">
+                      <Icon
+                        as={result.ok ? FiCheckCircle : FiXCircle}
+                        color={result.ok ? 'green.500' : 'red.500'}
+                        mt={1}
+                      />
+                      <Box>
+                        <Text fontSize="sm" fontFamily="mono">
+                          {result.target}
+                        </Text>
+                        <Text fontSize="xs" color="gray.500">
+                          {result.message}
+                        </Text>
+                      </Box>
+                    </HStack>
+                  ))}
+            </VStack>
+          </VStack>
+        </ModalBody>
+        <ModalFooter>
+          <Button variant="ghost" mr={3} onClick={closeDialog} isDisabled={isRunning}>
+            {results === null ? 'Cancel' : 'Close'}
+          </Button>
+          {results === null && (
+            <Button
+              colorScheme={labels.color}
+              onClick={run}
+              isLoading={isRunning}
+              isDisabled={!canRun}
+            >
+              {labels.button}
+            </Button>
+          )}
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+  )
+}
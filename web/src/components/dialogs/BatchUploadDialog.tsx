@@ -0,0 +1,403 @@
+import { useState, useRef, useEffect } from 'react'
+import {
+  Modal,
+  ModalOverlay,
+  ModalContent,
+  ModalFooter,
+  ModalBody,
+  ModalCloseButton,
+  Button,
+  Box,
+  Text,
+  VStack,
+  HStack,
+  Progress,
+  Icon,
+  Badge,
+  Input,
+  Checkbox,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  Tooltip,
+  useToast,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiFolder, FiCheck, FiX, FiUploadCloud, FiLoader } from 'react-icons/fi'
+import { useQueryClient } from '@tanstack/react-query'
+import { useUIStore } from '../../stores/uiStore'
+import { useTreeStore } from '../../stores/treeStore'
+import { useConnectionStore } from '../../stores/connectionStore'
+import * as api from '../../api'
+import type { BatchUploadItem } from '../../types'
+
+// Files uploaded at once
+const CONCURRENCY = 3
+
+interface BatchRow {
+  item: BatchUploadItem
+  file: File
+  progress: number
+  status: 'pending' | 'uploading' | 'success' | 'error'
+  error?: string
+}
+
+// Path of a file relative to the picked folder, without the folder itself
+function relativePath(file: File): string {
+  const path = file.webkitRelativePath || file.name
+  const slash = path.indexOf('/')
+  return slash >= 0 ? path.slice(slash + 1) : path
+}
+
+export default function BatchUploadDialog() {
+  const activeDialog = useUIStore((state) => state.activeDialog)
+  const dialogData = useUIStore((state) => state.dialogData)
+  const closeDialog = useUIStore((state) => state.closeDialog)
+  const selectedNode = useTreeStore((state) => state.selectedNode)
+  const activeConnectionId = useConnectionStore((state) => state.activeConnectionId)
+  const queryClient = useQueryClient()
+  const toast = useToast()
+
+  const [rows, setRows] = useState<BatchRow[]>([])
+  const [skipped, setSkipped] = useState(0)
+  const [folderWorkspaces, setFolderWorkspaces] = useState(false)
+  const [isPlanning, setIsPlanning] = useState(false)
+  const [isUploading, setIsUploading] = useState(false)
+  const [uploadComplete, setUploadComplete] = useState(false)
+  const pickedFiles = useRef<File[]>([])
+  const folderInputRef = useRef<HTMLInputElement>(null)
+
+  const headerBg = useColorModeValue('gray.50', 'gray.700')
+
+  const isOpen = activeDialog === 'batchupload'
+  const connectionId =
+    (dialogData?.data?.connectionId as string) || selectedNode?.connectionId || activeConnectionId
+  const workspace = (dialogData?.data?.workspace as string) || selectedNode?.workspace || ''
+
+  useEffect(() => {
+    if (isOpen) {
+      setRows([])
+      setSkipped(0)
+      setUploadComplete(false)
+      pickedFiles.current = []
+    }
+  }, [isOpen])
+
+  const plan = async (files: File[], byFolder: boolean) => {
+    if (files.length === 0) return
+    setIsPlanning(true)
+    try {
+      const byPath = new Map(files.map((file) => [relativePath(file), file]))
+      const result = await api.planBatchUpload(Array.from(byPath.keys()), workspace, byFolder)
+      setRows(
+        result.items.map((item) => ({
+          item,
+          file: byPath.get(item.path)!,
+          progress: 0,
+          status: 'pending' as const,
+        }))
+      )
+      setSkipped(result.skipped)
+    } catch (err) {
+      toast({
+        title: 'Failed to scan folder',
+        description: (err as Error).message,
+        status: 'error',
+        duration: 5000,
+      })
+    } finally {
+      setIsPlanning(false)
+    }
+  }
+
+  const handleFolderSelect = (e: React.ChangeEvent<HTMLInputElement>) => {
+    if (!e.target.files) return
+    pickedFiles.current = Array.from(e.target.files)
+    setUploadComplete(false)
+    plan(pickedFiles.current, folderWorkspaces)
+  }
+
+  const handleFolderWorkspaces = (checked: boolean) => {
+    setFolderWorkspaces(checked)
+    plan(pickedFiles.current, checked)
+  }
+
+  const updateItem = (index: number, changes: Partial<BatchUploadItem>) => {
+    setRows((prev) =>
+      prev.map((row, i) => (i === index ? { ...row, item: { ...row.item, ...changes } } : row))
+    )
+  }
+
+  const updateRow = (index: number, changes: Partial<BatchRow>) => {
+    setRows((prev) => prev.map((row, i) => (i === index ? { ...row, ...changes } : row)))
+  }
+
+  const handleUpload = async () => {
+    if (!connectionId) return
+    const pending = rows
+      .map((row, index) => ({ row, index }))
+      .filter(({ row }) => row.status === 'pending' || row.status === 'error')
+    if (pending.length === 0) return
+
+    setIsUploading(true)
+    try {
+      await api.prepareBatchUpload(
+        connectionId,
+        Array.from(new Set(pending.map(({ row }) => row.item.workspace)))
+      )
+    } catch (err) {
+      toast({
+        title: 'Failed to create workspaces',
+        description: (err as Error).message,
+        status: 'error',
+        duration: 5000,
+      })
+      setIsUploading(false)
+      return
+    }
+
+    let next = 0
+    const worker = async () => {
+      while (next < pending.length) {
+        const { row, index } = pending[next++]
+        updateRow(index, { status: 'uploading', progress: 0, error: undefined })
+        try {
+          await api.uploadBatchItem(connectionId, row.item, row.file, (progress) =>
+            updateRow(index, { progress })
+          )
+          updateRow(index, { status: 'success', progress: 100 })
+        } catch (err) {
+          updateRow(index, { status: 'error', error: (err as Error).message })
+        }
+      }
+    }
+    await Promise.all(Array.from({ length: Math.min(CONCURRENCY, pending.length) }, worker))
+
+    setIsUploading(false)
+    setUploadComplete(true)
+
+    queryClient.invalidateQueries({ queryKey: ['workspaces', connectionId] })
+    for (const ws of new Set(pending.map(({ row }) => row.item.workspace))) {
+      queryClient.invalidateQueries({ queryKey: ['datastores', connectionId, ws] })
+      queryClient.invalidateQueries({ queryKey: ['coveragestores', connectionId, ws] })
+      queryClient.invalidateQueries({ queryKey: ['styles', connectionId, ws] })
+      queryClient.invalidateQueries({ queryKey: ['layers', connectionId, ws] })
+    }
+  }
+
+  const handleClose = () => {
+    setRows([])
+    pickedFiles.current = []
+    closeDialog()
+  }
+
+  const done = rows.filter((r) => r.status === 'success' || r.status === 'error').length
+  const failed = rows.filter((r) => r.status === 'error').length
+  const overall =
+    rows.length > 0 ? Math.round(rows.reduce((sum, r) => sum + r.progress, 0) / rows.length) : 0
+  const hasPending = rows.some((r) => r.status === 'pending' || r.status === 'error')
+  const mappingValid = rows.every(
+    (r) =>
+      r.item.workspace.trim() &&
+      (r.item.kind === 'style' ? r.item.layer.trim() : r.item.store.trim())
+  )
+
+  const statusIcon = (row: BatchRow) => {
+    switch (row.status) {
+      case 'success':
+        return <Icon as={FiCheck} color="green.500" />
+      case 'error':
+        return (
+          <Tooltip label={row.error}>
+            <span>
+              <Icon as={FiX} color="red.500" />
+            </span>
+          </Tooltip>
+        )
+      case 'uploading':
+        return <Icon as={FiLoader} color="kartoza.500" />
+      default:
+        return null
+    }
+  }
+
+  return (
+    <Modal isOpen={isOpen} onClose={handleClose} size="5xl" isCentered>
+      <ModalOverlay bg="blackAlpha.600" backdropFilter="blur(4px)" />
+      <ModalContent borderRadius="xl" overflow="hidden" maxH="85vh">
+        <Box
+          bg="linear-gradient(135deg, #0a3a50 0%, #175a77 50%, #2d7d9b 100%)"
+          px={6}
+          py={4}
+        >
+          <HStack spacing={3}>
+            <Box bg="whiteAlpha.200" p={2} borderRadius="lg">
+              <Icon as={FiFolder} boxSize={5} color="white" />
+            </Box>
+            <Box flex="1">
+              <Text color="white" fontWeight="600" fontSize="lg">
+                Upload Folder
+              </Text>
+              <Text color="whiteAlpha.800" fontSize="sm">
+                Review how each file is published before uploading
+              </Text>
+            </Box>
+          </HStack>
+        </Box>
+        <ModalCloseButton color="white" />
+
+        <ModalBody py={6} overflowY="auto">
+          <VStack spacing={4} align="stretch">
+            <HStack justify="space-between">
+              <HStack spacing={3}>
+                <Button
+                  leftIcon={<FiFolder />}
+                  onClick={() => folderInputRef.current?.click()}
+                  isLoading={isPlanning}
+                  isDisabled={isUploading}
+                  size="sm"
+                >
+                  Choose Folder
+                </Button>
+                <Checkbox
+                  isChecked={folderWorkspaces}
+                  onChange={(e) => handleFolderWorkspaces(e.target.checked)}
+                  isDisabled={isUploading}
+                  colorScheme="kartoza"
+                >
+                  <Text fontSize="sm">Use subfolders as workspaces</Text>
+                </Checkbox>
+              </HStack>
+              {rows.length > 0 && (
+                <HStack spacing={2}>
+                  <Badge>{rows.length} file(s)</Badge>
+                  {skipped > 0 && (
+                    <Tooltip label="Files that aren't shapefile zips, GeoTIFFs, GeoPackages or SLDs">
+                      <Badge colorScheme="orange">{skipped} skipped</Badge>
+                    </Tooltip>
+                  )}
+                </HStack>
+              )}
+              <input
+                ref={folderInputRef}
+                type="file"
+                style={{ display: 'none' }}
+                onChange={handleFolderSelect}
+                // @ts-expect-error webkitdirectory isn't in React's input attributes
+                webkitdirectory=""
+              />
+            </HStack>
+
+            {rows.length === 0 && !isPlanning && (
+              <Text fontSize="sm" color="gray.500" textAlign="center" py={8}>
+                Choose a folder containing shapefiles (.zip), GeoTIFFs, GeoPackages or SLD styles
+              </Text>
+            )}
+
+            {rows.length > 0 && (
+              <Box overflowX="auto">
+                <Table size="sm">
+                  <Thead bg={headerBg}>
+                    <Tr>
+                      <Th>File</Th>
+                      <Th>Type</Th>
+                      <Th>Workspace</Th>
+                      <Th>Store</Th>
+                      <Th>Layer / Style</Th>
+                      <Th w="120px">Progress</Th>
+                      <Th w="40px" />
+                    </Tr>
+                  </Thead>
+                  <Tbody>
+                    {rows.map((row, index) => {
+                      const locked = isUploading || row.status === 'success'
+                      return (
+                        <Tr key={row.item.path}>
+                          <Td maxW="220px">
+                            <Text fontSize="xs" noOfLines={1} title={row.item.path}>
+                              {row.item.path}
+                            </Text>
+                          </Td>
+                          <Td>
+                            <Badge fontSize="2xs">{row.item.kind}</Badge>
+                          </Td>
+                          <Td>
+                            <Input
+                              size="xs"
+                              value={row.item.workspace}
+                              onChange={(e) => updateItem(index, { workspace: e.target.value })}
+                              isDisabled={locked}
+                            />
+                          </Td>
+                          <Td>
+                            <Input
+                              size="xs"
+                              value={row.item.store}
+                              onChange={(e) => updateItem(index, { store: e.target.value })}
+                              isDisabled={locked || row.item.kind === 'style'}
+                            />
+                          </Td>
+                          <Td>
+                            <Input
+                              size="xs"
+                              value={row.item.layer}
+                              onChange={(e) => updateItem(index, { layer: e.target.value })}
+                              isDisabled={locked || row.item.kind === 'geopackage'}
+                              placeholder={row.item.kind === 'geopackage' ? 'All layers' : ''}
+                            />
+                          </Td>
+                          <Td>
+                            <Progress
+                              value={row.progress}
+                              size="sm"
+                              colorScheme={row.status === 'error' ? 'red' : 'kartoza'}
+                              borderRadius="full"
+                            />
+                          </Td>
+                          <Td>{statusIcon(row)}</Td>
+                        </Tr>
+                      )
+                    })}
+                  </Tbody>
+                </Table>
+              </Box>
+            )}
+
+            {(isUploading || uploadComplete) && (
+              <Box>
+                <HStack justify="space-between" mb={1}>
+                  <Text fontSize="sm" fontWeight="500">
+                    {done} of {rows.length} file(s) processed
+                  </Text>
+                  {failed > 0 && <Badge colorScheme="red">{failed} failed</Badge>}
+                </HStack>
+                <Progress value={overall} size="sm" colorScheme="kartoza" borderRadius="full" />
+              </Box>
+            )}
+          </VStack>
+        </ModalBody>
+
+        <ModalFooter gap={3} borderTop="1px solid" borderTopColor="gray.100" bg="gray.50">
+          <Button variant="ghost" onClick={handleClose} borderRadius="lg" isDisabled={isUploading}>
+            {uploadComplete ? 'Close' : 'Cancel'}
+          </Button>
+          <Button
+            colorScheme="kartoza"
+            onClick={handleUpload}
+            isLoading={isUploading}
+            loadingText="Uploading..."
+            isDisabled={!connectionId || !hasPending || !mappingValid}
+            leftIcon={<FiUploadCloud />}
+            borderRadius="lg"
+            px={6}
+          >
+            {failed > 0 && uploadComplete ? 'Retry Failed' : 'Upload'}
+          </Button>
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+  )
+}
@@ -39,13 +39,22 @@ import {
   Td,
   IconButton,
   Tooltip,
+  Input,
+  Switch,
 } from '@chakra-ui/react'
 import { useQuery, useQueryClient } from '@tanstack/react-query'
-import { FiDatabase, FiPlay, FiTrash2, FiStopCircle, FiRefreshCw } from 'react-icons/fi'
+import { FiDatabase, FiPlay, FiTrash2, FiStopCircle, FiRefreshCw, FiClock, FiCopy } from 'react-icons/fi'
 import { useUIStore } from '../../stores/uiStore'
 import { useTreeStore } from '../../stores/treeStore'
 import * as api from '../../api'
-import type { GWCSeedRequest, GWCSeedTask } from '../../types'
+import type {
+  GWCSeedRequest,
+  GWCSeedSuggestion,
+  GWCSeedTask,
+  GWCSchedule,
+  GWCScheduleOperation,
+} from '../../types'
+import CacheHitRatioTab from './CacheHitRatioTab'
 
 export default function CacheDialog() {
   const activeDialog = useUIStore((state) => state.activeDialog)
@@ -61,6 +70,12 @@ export default function CacheDialog() {
   const [threadCount, setThreadCount] = useState(2)
   const [seedType, setSeedType] = useState<'seed' | 'reseed'>('seed')
   const [isLoading, setIsLoading] = useState(false)
+  const [scheduleName, setScheduleName] = useState('')
+  const [scheduleCron, setScheduleCron] = useState('0 2 * * *')
+  const [scheduleOperation, setScheduleOperation] = useState<GWCScheduleOperation>('reseed')
+  // Optional area to seed, typed or drawn in the map preview, in lon/lat
+  const [limitArea, setLimitArea] = useState(false)
+  const [areaInput, setAreaInput] = useState(['', '', '', ''])
 
   const toast = useToast()
   const queryClient = useQueryClient()
@@ -70,12 +85,22 @@ export default function CacheDialog() {
   const connectionId = dialogData?.data?.connectionId as string || selectedNode?.connectionId || ''
   const workspace = dialogData?.data?.workspace as string || selectedNode?.workspace || ''
   const fullLayerName = workspace ? `${workspace}:${layerName}` : layerName
+  const drawnArea = dialogData?.data?.bounds as [number, number, number, number] | undefined
+
+  const areaValues = areaInput.map((value) => parseFloat(value))
+  const areaValid =
+    areaValues.every((value) => Number.isFinite(value)) &&
+    areaValues[0] < areaValues[2] &&
+    areaValues[1] < areaValues[3]
+  const area =
+    limitArea && areaValid ? (areaValues as [number, number, number, number]) : undefined
 
   // Fetch layer cache info
   const { data: layerCache, isLoading: isLoadingCache } = useQuery({
     queryKey: ['gwc-layer', connectionId, fullLayerName],
     queryFn: () => api.getGWCLayer(connectionId, fullLayerName),
     enabled: isOpen && !!connectionId && !!fullLayerName,
+    retry: false, // Not found means caching is off, offered below
   })
 
   // Fetch seed status (polling while seeding)
@@ -86,6 +111,59 @@ export default function CacheDialog() {
     refetchInterval: activeTab === 1 ? 2000 : false, // Poll every 2 seconds on progress tab
   })
 
+  // Estimate the seed size for the current parameters
+  const { data: seedEstimate, isFetching: isEstimating, error: estimateError } = useQuery({
+    queryKey: ['gwc-seed-estimate', connectionId, workspace, layerName, selectedGridSet, selectedFormat, zoomStart, zoomStop, area],
+    queryFn: () =>
+      api.estimateGWCSeed(connectionId, workspace, layerName, {
+        gridSet: selectedGridSet,
+        format: selectedFormat,
+        zoomStart,
+        zoomStop,
+        bounds: area,
+        boundsSrs: area ? 'EPSG:4326' : undefined,
+      }),
+    enabled: isOpen && !!connectionId && !!workspace && !!selectedGridSet && zoomStop >= zoomStart,
+    staleTime: 60000,
+  })
+
+  // Client URLs for the selected grid set and format, checked with a sample tile
+  const {
+    data: tileEndpoints,
+    isFetching: isCheckingEndpoints,
+    error: endpointsError,
+    refetch: recheckEndpoints,
+  } = useQuery({
+    queryKey: ['gwc-tile-endpoints', connectionId, workspace, layerName, selectedGridSet, selectedFormat],
+    queryFn: () =>
+      api.getGWCTileEndpoints(connectionId, workspace, layerName, selectedGridSet, selectedFormat),
+    enabled: isOpen && activeTab === 2 && !!connectionId && !!workspace && !!selectedGridSet,
+    staleTime: 60000,
+  })
+
+  const copyEndpoint = (label: string, value: string) => {
+    navigator.clipboard.writeText(value).then(
+      () => toast({ title: 'Copied', description: label, status: 'success', duration: 2000 }),
+      () => toast({ title: 'Could not copy', description: value, status: 'error', duration: 5000 })
+    )
+  }
+
+  // Fetch cache schedules for this layer
+  const { data: allSchedules, refetch: refetchSchedules } = useQuery({
+    queryKey: ['gwc-schedules', connectionId],
+    queryFn: () => api.getGWCSchedules(connectionId),
+    enabled: isOpen && !!connectionId,
+  })
+  const schedules = (allSchedules || []).filter((s) => s.layer === fullLayerName)
+
+  // Start with the area drawn in the map preview, if any
+  useEffect(() => {
+    if (isOpen) {
+      setLimitArea(!!drawnArea)
+      setAreaInput(drawnArea ? drawnArea.map((value) => value.toFixed(6)) : ['', '', '', ''])
+    }
+  }, [isOpen, drawnArea])
+
   // Set default grid set when data loads
   useEffect(() => {
     if (layerCache?.gridSubsets?.[0] && !selectedGridSet) {
@@ -99,6 +177,16 @@ export default function CacheDialog() {
     }
   }, [layerCache, selectedGridSet, selectedFormat])
 
+  // Fill in the seed form from a hit ratio suggestion, for review before seeding
+  const handleSeedSuggestion = (suggestion: GWCSeedSuggestion) => {
+    setSelectedGridSet(suggestion.gridSet)
+    setZoomStart(suggestion.zoomStart)
+    setZoomStop(suggestion.zoomStop)
+    setSeedType('seed')
+    setLimitArea(false)
+    setActiveTab(0)
+  }
+
   const handleSeed = async () => {
     if (!selectedGridSet || !selectedFormat) {
       toast({
@@ -108,6 +196,14 @@ export default function CacheDialog() {
       })
       return
     }
+    if (limitArea && !area) {
+      toast({
+        title: 'Enter the area as min/max longitude and latitude',
+        status: 'error',
+        duration: 3000,
+      })
+      return
+    }
 
     setIsLoading(true)
 
@@ -119,6 +215,13 @@ export default function CacheDialog() {
         zoomStop,
         type: seedType,
         threadCount,
+        bounds: area && {
+          minX: area[0],
+          minY: area[1],
+          maxX: area[2],
+          maxY: area[3],
+          srs: 'EPSG:4326',
+        },
       }
 
       await api.seedLayer(connectionId, fullLayerName, request)
@@ -176,6 +279,30 @@ export default function CacheDialog() {
     }
   }
 
+  const handleEnableCaching = async () => {
+    setIsLoading(true)
+
+    try {
+      await api.enableGWCLayer(connectionId, fullLayerName)
+      toast({
+        title: 'Tile caching enabled',
+        description: `Layer: ${fullLayerName}`,
+        status: 'success',
+        duration: 3000,
+      })
+      queryClient.invalidateQueries({ queryKey: ['gwc-layer', connectionId, fullLayerName] })
+    } catch (err) {
+      toast({
+        title: 'Failed to enable tile caching',
+        description: err instanceof Error ? err.message : 'Unknown error',
+        status: 'error',
+        duration: 5000,
+      })
+    } finally {
+      setIsLoading(false)
+    }
+  }
+
   const handleTerminateTask = async () => {
     try {
       await api.terminateLayerSeed(connectionId, fullLayerName)
@@ -195,6 +322,97 @@ export default function CacheDialog() {
     }
   }
 
+  const handleCreateSchedule = async () => {
+    if (!selectedGridSet || !selectedFormat || !scheduleCron.trim()) {
+      toast({
+        title: 'Select grid set, format and schedule',
+        status: 'error',
+        duration: 3000,
+      })
+      return
+    }
+
+    try {
+      await api.createGWCSchedule({
+        name: scheduleName.trim() || `${scheduleOperation} ${layerName}`,
+        connectionId,
+        layer: fullLayerName,
+        cron: scheduleCron.trim(),
+        operation: scheduleOperation,
+        gridSet: selectedGridSet,
+        format: selectedFormat,
+        zoomStart,
+        zoomStop,
+        threads: threadCount,
+        bounds: area ?? null,
+        enabled: true,
+      })
+      toast({
+        title: 'Schedule created',
+        status: 'success',
+        duration: 3000,
+      })
+      setScheduleName('')
+      refetchSchedules()
+    } catch (err) {
+      toast({
+        title: 'Failed to create schedule',
+        description: err instanceof Error ? err.message : 'Unknown error',
+        status: 'error',
+        duration: 5000,
+      })
+    }
+  }
+
+  const handleToggleSchedule = async (schedule: GWCSchedule) => {
+    try {
+      await api.updateGWCSchedule(schedule.id, { enabled: !schedule.enabled })
+      refetchSchedules()
+    } catch (err) {
+      toast({
+        title: 'Failed to update schedule',
+        description: err instanceof Error ? err.message : 'Unknown error',
+        status: 'error',
+        duration: 5000,
+      })
+    }
+  }
+
+  const handleRunSchedule = async (schedule: GWCSchedule) => {
+    try {
+      const result = await api.runGWCSchedule(schedule.id)
+      toast({
+        title: result.lastStatus === 'failed' ? 'Scheduled task failed' : 'Scheduled task started',
+        description: result.lastError || schedule.name,
+        status: result.lastStatus === 'failed' ? 'error' : 'success',
+        duration: 3000,
+      })
+      refetchSchedules()
+      refetchSeedStatus()
+    } catch (err) {
+      toast({
+        title: 'Failed to run schedule',
+        description: err instanceof Error ? err.message : 'Unknown error',
+        status: 'error',
+        duration: 5000,
+      })
+    }
+  }
+
+  const handleDeleteSchedule = async (schedule: GWCSchedule) => {
+    try {
+      await api.deleteGWCSchedule(schedule.id)
+      refetchSchedules()
+    } catch (err) {
+      toast({
+        title: 'Failed to delete schedule',
+        description: err instanceof Error ? err.message : 'Unknown error',
+        status: 'error',
+        duration: 5000,
+      })
+    }
+  }
+
   const formatTime = (seconds: number): string => {
     if (seconds < 0) return 'Unknown'
     if (seconds < 60) return `${seconds}s`
@@ -202,6 +420,18 @@ export default function CacheDialog() {
     return `${Math.floor(seconds / 3600)}h ${Math.floor((seconds % 3600) / 60)}m`
   }
 
+  const formatBytes = (bytes: number): string => {
+    if (bytes < 1024) return `${bytes} B`
+    const units = ['KB', 'MB', 'GB', 'TB', 'PB']
+    let value = bytes / 1024
+    let unit = 0
+    while (value >= 1024 && unit < units.length - 1) {
+      value /= 1024
+      unit++
+    }
+    return `${value.toFixed(1)} ${units[unit]}`
+  }
+
   const getStatusColor = (status: string): string => {
     switch (status) {
       case 'Running':
@@ -252,10 +482,25 @@ export default function CacheDialog() {
               <Text fontSize="sm" color="gray.500">Loading cache info...</Text>
             </HStack>
           ) : !layerCache ? (
-            <Alert status="warning" borderRadius="md">
-              <AlertIcon />
-              <Text fontSize="sm">No cache configuration found for this layer</Text>
-            </Alert>
+            <VStack spacing={3} align="stretch">
+              <Alert status="warning" borderRadius="md">
+                <AlertIcon />
+                <Text fontSize="sm">Tile caching is off for {fullLayerName}</Text>
+              </Alert>
+              <Text fontSize="sm" color="gray.600">
+                Caching it adds the EPSG:4326 and EPSG:900913 grid sets with PNG and JPEG
+                tiles, like GeoServer does for new layers.
+              </Text>
+              <Button
+                colorScheme="kartoza"
+                leftIcon={<FiDatabase />}
+                onClick={handleEnableCaching}
+                isLoading={isLoading}
+                alignSelf="flex-start"
+              >
+                Enable Tile Caching
+              </Button>
+            </VStack>
           ) : (
             <Tabs index={activeTab} onChange={setActiveTab} colorScheme="kartoza">
               <TabList>
@@ -269,6 +514,15 @@ export default function CacheDialog() {
                   )}
                 </Tab>
                 <Tab>Info</Tab>
+                <Tab>Hit Ratio</Tab>
+                <Tab>
+                  Schedules
+                  {schedules.length > 0 && (
+                    <Badge ml={2} colorScheme="purple" borderRadius="full">
+                      {schedules.length}
+                    </Badge>
+                  )}
+                </Tab>
               </TabList>
 
               <TabPanels>
@@ -365,6 +619,105 @@ export default function CacheDialog() {
                       </FormControl>
                     </HStack>
 
+                    <FormControl>
+                      <HStack justify="space-between">
+                        <FormLabel fontWeight="500" color="gray.700" mb={0}>
+                          Limit to an area
+                        </FormLabel>
+                        <Switch
+                          isChecked={limitArea}
+                          onChange={(e) => setLimitArea(e.target.checked)}
+                          colorScheme="kartoza"
+                        />
+                      </HStack>
+                      {limitArea && (
+                        <>
+                          <HStack spacing={2} mt={2}>
+                            {['Min lon', 'Min lat', 'Max lon', 'Max lat'].map((label, i) => (
+                              <Input
+                                key={label}
+                                placeholder={label}
+                                value={areaInput[i]}
+                                onChange={(e) =>
+                                  setAreaInput(areaInput.map((value, j) => (j === i ? e.target.value : value)))
+                                }
+                                isInvalid={areaInput[i] !== '' && !Number.isFinite(areaValues[i])}
+                                size="sm"
+                                fontFamily="mono"
+                                borderRadius="md"
+                              />
+                            ))}
+                          </HStack>
+                          <Text fontSize="xs" color={areaValid ? 'gray.500' : 'orange.500'} mt={1}>
+                            {areaValid
+                              ? 'Converted to the grid set\'s SRS when seeding. Draw an area with the crop tool in the map preview.'
+                              : 'Enter longitudes and latitudes with the minimum below the maximum.'}
+                          </Text>
+                        </>
+                      )}
+                    </FormControl>
+
+                    {/* Seed estimate */}
+                    <Box bg="gray.50" borderRadius="lg" p={3} borderWidth="1px" borderColor="gray.200">
+                      <HStack justify="space-between" mb={seedEstimate ? 2 : 0}>
+                        <Text fontWeight="500" color="gray.700" fontSize="sm">Estimate</Text>
+                        {isEstimating && <Spinner size="xs" color="kartoza.500" />}
+                      </HStack>
+                      {estimateError ? (
+                        <Text fontSize="sm" color="red.500">
+                          {estimateError instanceof Error ? estimateError.message : 'Could not estimate seed size'}
+                        </Text>
+                      ) : seedEstimate ? (
+                        <VStack spacing={2} align="stretch">
+                          <HStack spacing={6}>
+                            <Box>
+                              <Text fontSize="xs" color="gray.500">Tiles</Text>
+                              <Text fontWeight="600">{seedEstimate.totalTiles.toLocaleString()}</Text>
+                            </Box>
+                            <Box>
+                              <Text fontSize="xs" color="gray.500">Approx. size</Text>
+                              <Text fontWeight="600">{formatBytes(seedEstimate.estimatedBytes)}</Text>
+                            </Box>
+                            <Box>
+                              <Text fontSize="xs" color="gray.500">Avg. tile</Text>
+                              <Text fontWeight="600">{formatBytes(seedEstimate.avgTileBytes)}</Text>
+                            </Box>
+                          </HStack>
+                          {seedEstimate.boundsSource === 'gridSetExtent' && (
+                            <Text fontSize="xs" color="orange.500">
+                              Layer bounds unavailable in this grid set's SRS; using the full grid set extent.
+                            </Text>
+                          )}
+                          <Box maxH="150px" overflowY="auto">
+                            <Table size="sm">
+                              <Thead>
+                                <Tr>
+                                  <Th>Zoom</Th>
+                                  <Th isNumeric>Columns</Th>
+                                  <Th isNumeric>Rows</Th>
+                                  <Th isNumeric>Tiles</Th>
+                                </Tr>
+                              </Thead>
+                              <Tbody>
+                                {seedEstimate.zoomLevels.map((level) => (
+                                  <Tr key={level.zoom}>
+                                    <Td>{level.zoom}</Td>
+                                    <Td isNumeric>{level.columns.toLocaleString()}</Td>
+                                    <Td isNumeric>{level.rows.toLocaleString()}</Td>
+                                    <Td isNumeric>{level.tiles.toLocaleString()}</Td>
+                                  </Tr>
+                                ))}
+                              </Tbody>
+                            </Table>
+                          </Box>
+                        </VStack>
+                      ) : (
+                        !isEstimating && (
+                          <Text fontSize="sm" color="gray.500">Select a grid set and zoom range</Text>
+                        )
+                      )}
+                    </Box>
+
                     <HStack spacing={3} pt={4}>
                       <Button
                         leftIcon={<Icon as={FiPlay} />}
@@ -507,6 +860,213 @@ export default function CacheDialog() {
                         ))}
                       </HStack>
                     </Box>
+
+                    <Box p={4} bg="gray.50" borderRadius="lg">
+                      <HStack justify="space-between" mb={2}>
+                        <Text fontWeight="500">
+                          Client URLs ({selectedGridSet}, {selectedFormat})
+                        </Text>
+                        <Tooltip label="Fetch a sample tile again">
+                          <IconButton
+                            aria-label="Check again"
+                            icon={<FiRefreshCw />}
+                            size="xs"
+                            variant="ghost"
+                            onClick={() => recheckEndpoints()}
+                            isLoading={isCheckingEndpoints}
+                          />
+                        </Tooltip>
+                      </HStack>
+                      {endpointsError ? (
+                        <Text fontSize="sm" color="red.500">
+                          {endpointsError instanceof Error ? endpointsError.message : 'Could not check the tile services'}
+                        </Text>
+                      ) : tileEndpoints ? (
+                        <VStack spacing={2} align="stretch">
+                          {tileEndpoints.check.ok ? (
+                            <Alert status="success" borderRadius="md" py={2}>
+                              <AlertIcon />
+                              <Text fontSize="sm">
+                                Sample tile served as {tileEndpoints.check.contentType} (
+                                {formatBytes(tileEndpoints.check.size)})
+                              </Text>
+                            </Alert>
+                          ) : (
+                            <Alert status="warning" borderRadius="md" py={2} alignItems="start">
+                              <AlertIcon />
+                              <VStack align="stretch" spacing={1}>
+                                {tileEndpoints.check.issues.map((issue) => (
+                                  <Text key={issue} fontSize="sm">{issue}</Text>
+                                ))}
+                              </VStack>
+                            </Alert>
+                          )}
+                          {tileEndpoints.endpoints.map((endpoint) => (
+                            <HStack key={endpoint.label} spacing={2}>
+                              <Box flex="1" minW={0}>
+                                <Text fontSize="xs" color="gray.500">{endpoint.label}</Text>
+                                <Text fontSize="xs" fontFamily="mono" noOfLines={1} title={endpoint.value}>
+                                  {endpoint.value}
+                                </Text>
+                              </Box>
+                              <Tooltip label="Copy">
+                                <IconButton
+                                  aria-label={`Copy ${endpoint.label}`}
+                                  icon={<FiCopy />}
+                                  size="xs"
+                                  variant="ghost"
+                                  onClick={() => copyEndpoint(endpoint.label, endpoint.value)}
+                                />
+                              </Tooltip>
+                            </HStack>
+                          ))}
+                        </VStack>
+                      ) : (
+                        isCheckingEndpoints && <Spinner size="sm" color="kartoza.500" />
+                      )}
+                    </Box>
+                  </VStack>
+                </TabPanel>
+
+                {/* Hit Ratio Tab */}
+                <TabPanel px={0}>
+                  <CacheHitRatioTab
+                    connectionId={connectionId}
+                    workspace={workspace}
+                    layerName={layerName}
+                    format={selectedFormat}
+                    onSeed={handleSeedSuggestion}
+                  />
+                </TabPanel>
+
+                {/* Schedules Tab */}
+                <TabPanel px={0}>
+                  <VStack spacing={4} align="stretch">
+                    <Text fontSize="sm" color="gray.600">
+                      Run this layer's cache operation on a cron schedule using the grid set, format,
+                      zoom range, threads and area selected on the Seed tab.
+                    </Text>
+
+                    <HStack spacing={4}>
+                      <FormControl>
+                        <FormLabel fontWeight="500" color="gray.700">Name</FormLabel>
+                        <Input
+                          value={scheduleName}
+                          onChange={(e) => setScheduleName(e.target.value)}
+                          placeholder={`${scheduleOperation} ${layerName}`}
+                          borderRadius="lg"
+                        />
+                      </FormControl>
+                      <FormControl>
+                        <FormLabel fontWeight="500" color="gray.700">Operation</FormLabel>
+                        <Select
+                          value={scheduleOperation}
+                          onChange={(e) => setScheduleOperation(e.target.value as GWCScheduleOperation)}
+                          borderRadius="lg"
+                        >
+                          <option value="seed">Seed</option>
+                          <option value="reseed">Reseed</option>
+                          <option value="truncate">Truncate</option>
+                        </Select>
+                      </FormControl>
+                    </HStack>
+
+                    <HStack spacing={4} align="flex-end">
+                      <FormControl>
+                        <FormLabel fontWeight="500" color="gray.700">Cron expression</FormLabel>
+                        <Input
+                          value={scheduleCron}
+                          onChange={(e) => setScheduleCron(e.target.value)}
+                          placeholder="0 2 * * *"
+                          fontFamily="mono"
+                          borderRadius="lg"
+                        />
+                      </FormControl>
+                      <Button
+                        leftIcon={<Icon as={FiClock} />}
+                        colorScheme="kartoza"
+                        onClick={handleCreateSchedule}
+                        borderRadius="lg"
+                        flexShrink={0}
+                      >
+                        Add Schedule
+                      </Button>
+                    </HStack>
+                    <Text fontSize="xs" color="gray.500">
+                      minute hour day-of-month month day-of-week, e.g. "0 2 * * *" for 02:00 nightly, or @daily
+                    </Text>
+
+                    {schedules.length === 0 ? (
+                      <Box p={4} bg="gray.50" borderRadius="lg" textAlign="center">
+                        <Text color="gray.500" fontSize="sm">No schedules for this layer</Text>
+                      </Box>
+                    ) : (
+                      <Table size="sm">
+                        <Thead>
+                          <Tr>
+                            <Th>Name</Th>
+                            <Th>Schedule</Th>
+                            <Th>Next / Last run</Th>
+                            <Th>Enabled</Th>
+                            <Th></Th>
+                          </Tr>
+                        </Thead>
+                        <Tbody>
+                          {schedules.map((schedule) => (
+                            <Tr key={schedule.id}>
+                              <Td>
+                                <Text fontSize="sm">{schedule.name}</Text>
+                                <Text fontSize="xs" color="gray.500">
+                                  {schedule.operation} {schedule.gridSet} z{schedule.zoomStart}-{schedule.zoomStop}
+                                </Text>
+                              </Td>
+                              <Td fontFamily="mono" fontSize="xs">{schedule.cron}</Td>
+                              <Td fontSize="xs">
+                                <Text>{schedule.nextRunAt ? new Date(schedule.nextRunAt).toLocaleString() : '-'}</Text>
+                                {schedule.lastRunAt && (
+                                  <Tooltip label={schedule.lastError || ''} isDisabled={!schedule.lastError}>
+                                    <Text color={schedule.lastStatus === 'failed' ? 'red.500' : 'gray.500'}>
+                                      {new Date(schedule.lastRunAt).toLocaleString()}
+                                    </Text>
+                                  </Tooltip>
+                                )}
+                              </Td>
+                              <Td>
+                                <Switch
+                                  isChecked={schedule.enabled}
+                                  onChange={() => handleToggleSchedule(schedule)}
+                                  colorScheme="kartoza"
+                                  size="sm"
+                                />
+                              </Td>
+                              <Td>
+                                <HStack spacing={1}>
+                                  <Tooltip label="Run now">
+                                    <IconButton
+                                      aria-label="Run now"
+                                      icon={<FiPlay />}
+                                      size="xs"
+                                      variant="ghost"
+                                      onClick={() => handleRunSchedule(schedule)}
+                                    />
+                                  </Tooltip>
+                                  <Tooltip label="Delete schedule">
+                                    <IconButton
+                                      aria-label="Delete schedule"
+                                      icon={<FiTrash2 />}
+                                      size="xs"
+                                      variant="ghost"
+                                      colorScheme="red"
+                                      onClick={() => handleDeleteSchedule(schedule)}
+                                    />
+                                  </Tooltip>
+                                </HStack>
+                              </Td>
+                            </Tr>
+                          ))}
+                        </Tbody>
+                      </Table>
+                    )}
                   </VStack>
                 </TabPanel>
               </TabPanels>
@@ -0,0 +1,235 @@
+import { useState } from 'react'
+import {
+  VStack,
+  HStack,
+  Box,
+  Text,
+  Button,
+  Badge,
+  FormControl,
+  FormLabel,
+  FormHelperText,
+  NumberInput,
+  NumberInputField,
+  Alert,
+  AlertIcon,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  Tooltip,
+  useToast,
+} from '@chakra-ui/react'
+import { useMutation } from '@tanstack/react-query'
+import { FiActivity } from 'react-icons/fi'
+import * as api from '../../api'
+import type { GWCHitRatio, GWCSeedSuggestion, GWCZoomHitRatio } from '../../types'
+
+interface CacheHitRatioTabProps {
+  connectionId: string
+  workspace: string
+  layerName: string
+  format: string
+  // Fills in the seed form with a suggested grid set and zoom range
+  onSeed: (suggestion: GWCSeedSuggestion) => void
+}
+
+function formatRatio(ratio: number | null): string {
+  return ratio == null ? '–' : `${Math.round(ratio * 100)}%`
+}
+
+function heatColor(ratio: number | null): string {
+  if (ratio == null) return 'gray.100'
+  if (ratio < 0.25) return 'red.200'
+  if (ratio < 0.5) return 'orange.200'
+  if (ratio < 0.75) return 'yellow.200'
+  return 'green.200'
+}
+
+function cellLabel(zoom: GWCZoomHitRatio): string {
+  const parts = [
+    `Zoom ${zoom.zoom}: ${zoom.hits} of ${zoom.sampled} sampled tiles cached`,
+    `${zoom.tiles.toLocaleString()} tiles cover the layer`,
+  ]
+  if (zoom.errors > 0) parts.push(`${zoom.errors} failed`)
+  if (zoom.worthSeeding) parts.push('worth pre-seeding')
+  return parts.join(', ')
+}
+
+// Samples the layer's tiles to show which zoom levels are served from the cache
+export default function CacheHitRatioTab({
+  connectionId,
+  workspace,
+  layerName,
+  format,
+  onSeed,
+}: CacheHitRatioTabProps) {
+  const toast = useToast()
+  const [zoomStart, setZoomStart] = useState(0)
+  const [zoomStop, setZoomStop] = useState(14)
+  const [samples, setSamples] = useState(5)
+  const [report, setReport] = useState<GWCHitRatio | null>(null)
+
+  const analyzeMutation = useMutation({
+    mutationFn: () =>
+      api.getGWCHitRatio(connectionId, workspace, layerName, { zoomStart, zoomStop, samples, format }),
+    onSuccess: setReport,
+    onError: (err: Error) => {
+      toast({ title: 'Analysis failed', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  const gridSets = report ? [...new Set(report.zooms.map((zoom) => zoom.gridSet))] : []
+  const zoomLevels = report ? [...new Set(report.zooms.map((zoom) => zoom.zoom))].sort((a, b) => a - b) : []
+
+  return (
+    <VStack spacing={4} align="stretch">
+      <Text fontSize="sm" color="gray.600">
+        Request a few tiles at each zoom level of every grid set and count how many come from the
+        cache. Tiles that miss are rendered and cached as they would be for any client.
+      </Text>
+
+      <HStack spacing={4} align="flex-start">
+        <FormControl>
+          <FormLabel fontWeight="500" color="gray.700">Zoom Start</FormLabel>
+          <NumberInput value={zoomStart} min={0} max={30} onChange={(_, v) => setZoomStart(Number.isNaN(v) ? 0 : v)}>
+            <NumberInputField borderRadius="lg" />
+          </NumberInput>
+        </FormControl>
+        <FormControl>
+          <FormLabel fontWeight="500" color="gray.700">Zoom Stop</FormLabel>
+          <NumberInput value={zoomStop} min={0} max={30} onChange={(_, v) => setZoomStop(Number.isNaN(v) ? 0 : v)}>
+            <NumberInputField borderRadius="lg" />
+          </NumberInput>
+        </FormControl>
+        <FormControl>
+          <FormLabel fontWeight="500" color="gray.700">Tiles per Zoom</FormLabel>
+          <NumberInput value={samples} min={1} max={20} onChange={(_, v) => setSamples(Number.isNaN(v) ? 1 : v)}>
+            <NumberInputField borderRadius="lg" />
+          </NumberInput>
+          <FormHelperText>At most 300 tiles in all</FormHelperText>
+        </FormControl>
+      </HStack>
+
+      <Button
+        leftIcon={<FiActivity />}
+        colorScheme="kartoza"
+        onClick={() => analyzeMutation.mutate()}
+        isLoading={analyzeMutation.isPending}
+        loadingText="Sampling tiles..."
+        isDisabled={zoomStop < zoomStart}
+        alignSelf="flex-start"
+        borderRadius="lg"
+      >
+        Analyze Hit Ratio
+      </Button>
+
+      {report && (
+        <>
+          <HStack spacing={6}>
+            <Box>
+              <Text fontSize="xs" color="gray.500">Sampled hit ratio</Text>
+              <Text fontWeight="600">{formatRatio(report.hitRatio)}</Text>
+            </Box>
+            {report.runtime?.hitRatio != null && (
+              <Box>
+                <Text fontSize="xs" color="gray.500">Server-wide hit ratio</Text>
+                <Text fontWeight="600">
+                  {formatRatio(report.runtime.hitRatio)}
+                  {report.runtime.requests != null && (
+                    <Text as="span" fontWeight="normal" color="gray.500" fontSize="sm">
+                      {' '}of {report.runtime.requests.toLocaleString()} requests
+                    </Text>
+                  )}
+                </Text>
+              </Box>
+            )}
+            <Box>
+              <Text fontSize="xs" color="gray.500">Format</Text>
+              <Text fontWeight="600">{report.format}</Text>
+            </Box>
+          </HStack>
+
+          {/* Heat table: a row per grid set, a column per zoom level */}
+          <Box overflowX="auto">
+            <Table size="sm" variant="unstyled">
+              <Thead>
+                <Tr>
+                  <Th px={1}>Grid Set</Th>
+                  {zoomLevels.map((level) => (
+                    <Th key={level} px={1} textAlign="center">{level}</Th>
+                  ))}
+                </Tr>
+              </Thead>
+              <Tbody>
+                {gridSets.map((gridSet) => (
+                  <Tr key={gridSet}>
+                    <Td px={1} fontSize="xs" whiteSpace="nowrap">{gridSet}</Td>
+                    {zoomLevels.map((level) => {
+                      const zoom = report.zooms.find((z) => z.gridSet === gridSet && z.zoom === level)
+                      if (!zoom) return <Td key={level} px={1} />
+                      return (
+                        <Td key={level} px={1} py={1}>
+                          <Tooltip label={cellLabel(zoom)}>
+                            <Box
+                              bg={heatColor(zoom.hitRatio)}
+                              borderRadius="sm"
+                              borderWidth="2px"
+                              borderColor={zoom.worthSeeding ? 'kartoza.500' : 'transparent'}
+                              fontSize="xs"
+                              textAlign="center"
+                              minW="36px"
+                              py={1}
+                            >
+                              {formatRatio(zoom.hitRatio)}
+                            </Box>
+                          </Tooltip>
+                        </Td>
+                      )
+                    })}
+                  </Tr>
+                ))}
+              </Tbody>
+            </Table>
+          </Box>
+          <HStack spacing={2} fontSize="xs" color="gray.500" flexWrap="wrap">
+            <Badge bg="red.200">&lt; 25%</Badge>
+            <Badge bg="orange.200">&lt; 50%</Badge>
+            <Badge bg="yellow.200">&lt; 75%</Badge>
+            <Badge bg="green.200">75%+</Badge>
+            <Text>Outlined: worth pre-seeding</Text>
+          </HStack>
+
+          {report.seedSuggestions.length > 0 ? (
+            <Alert status="info" borderRadius="md" alignItems="start">
+              <AlertIcon />
+              <VStack align="stretch" spacing={2} flex={1}>
+                <Text fontSize="sm">
+                  These zoom levels hit the cache less than {formatRatio(report.seedHitRatio)} of
+                  the time and have at most {report.seedTileLimit.toLocaleString()} tiles, so
+                  seeding them is cheap:
+                </Text>
+                {report.seedSuggestions.map((suggestion) => (
+                  <HStack key={`${suggestion.gridSet}-${suggestion.zoomStart}`} justify="space-between">
+                    <Text fontSize="sm">
+                      {suggestion.gridSet}, zoom {suggestion.zoomStart}
+                      {suggestion.zoomStop > suggestion.zoomStart && `–${suggestion.zoomStop}`} (
+                      {suggestion.tiles.toLocaleString()} tiles)
+                    </Text>
+                    <Button size="xs" colorScheme="kartoza" variant="outline" onClick={() => onSeed(suggestion)}>
+                      Seed These
+                    </Button>
+                  </HStack>
+                ))}
+              </VStack>
+            </Alert>
+          ) : (
+            <Text fontSize="sm" color="gray.500">No zoom levels need pre-seeding.</Text>
+          )}
+        </>
+      )}
+    </VStack>
+  )
+}
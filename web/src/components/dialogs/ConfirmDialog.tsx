@@ -10,14 +10,45 @@ import {
   HStack,
   Box,
   Icon,
+  VStack,
+  Badge,
+  Checkbox,
+  Select,
+  Spinner,
   useToast,
 } from '@chakra-ui/react'
-import { useState } from 'react'
-import { useQueryClient } from '@tanstack/react-query'
+import { useState, useEffect } from 'react'
+import { useQuery, useQueryClient } from '@tanstack/react-query'
 import { FiAlertTriangle } from 'react-icons/fi'
 import { useUIStore } from '../../stores/uiStore'
 import { useConnectionStore } from '../../stores/connectionStore'
 import * as api from '../../api'
+import { TypedConfirmation, useTypedConfirmation, confirmationMatches } from '../common'
+
+// Names shown per category before collapsing into "+N more"
+const IMPACT_PREVIEW = 5
+
+function ImpactRow({ label, names }: { label: string; names: string[] }) {
+  if (names.length === 0) return null
+  const shown = names.slice(0, IMPACT_PREVIEW).join(', ')
+  const more = names.length - IMPACT_PREVIEW
+  return (
+    <HStack align="start" spacing={3}>
+      <Badge colorScheme="red" minW="28px" textAlign="center">
+        {names.length}
+      </Badge>
+      <Box>
+        <Text fontSize="sm" fontWeight="600">
+          {label}
+        </Text>
+        <Text fontSize="xs" color="gray.500">
+          {shown}
+          {more > 0 && ` +${more} more`}
+        </Text>
+      </Box>
+    </HStack>
+  )
+}
 
 export default function ConfirmDialog() {
   const activeDialog = useUIStore((state) => state.activeDialog)
@@ -28,12 +59,58 @@ export default function ConfirmDialog() {
   const toast = useToast()
 
   const [isLoading, setIsLoading] = useState(false)
+  const [reassign, setReassign] = useState(false)
+  const [reassignTo, setReassignTo] = useState('')
+  const [typed, setTyped] = useState('')
 
   const isOpen = activeDialog === 'confirm'
   const title = dialogData?.title || 'Confirm'
   const message = dialogData?.message || 'Are you sure?'
   const data = dialogData?.data as Record<string, unknown> | undefined
 
+  // Deleting a style breaks the layers using it, so list them first
+  const isStyleDelete = isOpen && data?.type === 'style' && !!data?.connectionId && !!data?.name
+  const styleConnId = data?.connectionId as string
+  const styleWorkspace = data?.workspace as string
+  const styleName = data?.name as string
+
+  const { data: styleUsage, isLoading: usageLoading } = useQuery({
+    queryKey: ['styleusage', styleConnId, styleWorkspace, styleName],
+    queryFn: () => api.getGWCStyleLayers(styleConnId, styleWorkspace, styleName),
+    enabled: isStyleDelete,
+  })
+
+  const { data: workspaceStyles } = useQuery({
+    queryKey: ['styles', styleConnId, styleWorkspace],
+    queryFn: () => api.getStyles(styleConnId, styleWorkspace),
+    enabled: isStyleDelete && !!styleUsage?.length,
+  })
+  const replacementStyles = (workspaceStyles || []).filter((s) => s.name !== styleName)
+
+  // Recursive workspace deletes remove everything inside, so summarize it
+  const isWorkspaceDelete =
+    isOpen && !!data?.connectionId && !!data?.workspace && !data?.name && !data?.type
+  const { data: impact, isLoading: impactLoading } = useQuery({
+    queryKey: ['workspaceimpact', data?.connectionId, data?.workspace],
+    queryFn: () =>
+      api.getWorkspaceImpact(data?.connectionId as string, data?.workspace as string),
+    enabled: isWorkspaceDelete,
+  })
+
+  // Production connections may ask for the name of the workspace or resource being deleted
+  const confirmName =
+    data?.connectionId && data?.workspace ? ((data.name || data.workspace) as string) : ''
+  const typedConfirmation = useTypedConfirmation([data?.connectionId as string | undefined])
+  const needsTyping = typedConfirmation && confirmName !== ''
+
+  useEffect(() => {
+    if (isOpen) {
+      setReassign(false)
+      setReassignTo('')
+      setTyped('')
+    }
+  }, [isOpen])
+
   const handleConfirm = async () => {
     setIsLoading(true)
 
@@ -144,6 +221,9 @@ export default function ConfirmDialog() {
           true // recurse
         )
         queryClient.invalidateQueries({ queryKey: ['workspaces', data.connectionId] })
+        queryClient.invalidateQueries({
+          queryKey: ['workspaceimpact', data.connectionId, data.workspace],
+        })
         toast({
           title: 'Workspace deleted',
           status: 'success',
@@ -169,10 +249,23 @@ export default function ConfirmDialog() {
             await api.deleteLayer(connId, workspace, name)
             queryClient.invalidateQueries({ queryKey: ['layers', connId, workspace] })
             break
-          case 'style':
-            await api.deleteStyle(connId, workspace, name, true)
+          case 'style': {
+            const target = reassign && reassignTo ? reassignTo : undefined
+            const result = await api.deleteStyle(connId, workspace, name, true, target)
             queryClient.invalidateQueries({ queryKey: ['styles', connId, workspace] })
+            queryClient.invalidateQueries({ queryKey: ['styleusage', connId, workspace, name] })
+            if (result?.affectedLayers.length) {
+              queryClient.invalidateQueries({ queryKey: ['layers', connId, workspace] })
+              toast({
+                title: target
+                  ? `${result.reassignedLayers.length} layer(s) now use ${target}`
+                  : `${result.affectedLayers.length} layer(s) fell back to the default style`,
+                status: 'info',
+                duration: 4000,
+              })
+            }
             break
+          }
           case 'layergroup':
             await api.deleteLayerGroup(connId, workspace, name)
             queryClient.invalidateQueries({ queryKey: ['layergroups', connId, workspace] })
@@ -234,6 +327,103 @@ export default function ConfirmDialog() {
           <Text color="gray.700" fontSize="md">
             {message}
           </Text>
+
+          {isWorkspaceDelete && impactLoading && (
+            <HStack spacing={2} mt={4} color="gray.500">
+              <Spinner size="sm" />
+              <Text fontSize="sm">Collecting workspace contents...</Text>
+            </HStack>
+          )}
+
+          {isWorkspaceDelete && impact && (
+            <Box mt={4}>
+              {impact.counts.stores + impact.counts.layers + impact.counts.styles +
+                impact.counts.layerGroups === 0 ? (
+                <Text fontSize="sm" color="gray.500">
+                  This workspace is empty.
+                </Text>
+              ) : (
+                <>
+                  <Text fontSize="sm" fontWeight="600" color="gray.700" mb={2}>
+                    The following will also be deleted:
+                  </Text>
+                  <VStack align="stretch" spacing={2}>
+                    <ImpactRow
+                      label="Stores"
+                      names={impact.stores.map((store) => store.name)}
+                    />
+                    <ImpactRow label="Layers" names={impact.layers} />
+                    <ImpactRow label="Styles" names={impact.styles} />
+                    <ImpactRow label="Layer groups" names={impact.layerGroups} />
+                    <ImpactRow label="Tile caches" names={impact.caches} />
+                  </VStack>
+                </>
+              )}
+            </Box>
+          )}
+
+          {isStyleDelete && usageLoading && (
+            <HStack spacing={2} mt={4} color="gray.500">
+              <Spinner size="sm" />
+              <Text fontSize="sm">Checking which layers use this style...</Text>
+            </HStack>
+          )}
+
+          {isStyleDelete && !usageLoading && styleUsage && styleUsage.length === 0 && (
+            <Text mt={4} fontSize="sm" color="gray.500">
+              No layers use this style.
+            </Text>
+          )}
+
+          {isStyleDelete && styleUsage && styleUsage.length > 0 && (
+            <Box mt={4}>
+              <Text fontSize="sm" fontWeight="600" color="gray.700" mb={2}>
+                {styleUsage.length} layer(s) use this style:
+              </Text>
+              <VStack align="stretch" spacing={1} maxH="160px" overflowY="auto" mb={3}>
+                {styleUsage.map((usage) => (
+                  <HStack key={usage.layer} justify="space-between">
+                    <Text fontSize="sm">{usage.layer}</Text>
+                    <Badge colorScheme={usage.usage === 'default' ? 'orange' : 'gray'}>
+                      {usage.usage}
+                    </Badge>
+                  </HStack>
+                ))}
+              </VStack>
+              <Checkbox
+                isChecked={reassign}
+                onChange={(e) => setReassign(e.target.checked)}
+                isDisabled={replacementStyles.length === 0}
+              >
+                <Text fontSize="sm">Reassign these layers to another style</Text>
+              </Checkbox>
+              {reassign && (
+                <Select
+                  mt={2}
+                  size="sm"
+                  placeholder="Select a style"
+                  value={reassignTo}
+                  onChange={(e) => setReassignTo(e.target.value)}
+                >
+                  {replacementStyles.map((s) => (
+                    <option key={s.name} value={`${styleWorkspace}:${s.name}`}>
+                      {s.name}
+                    </option>
+                  ))}
+                </Select>
+              )}
+              {!reassign && (
+                <Text mt={2} fontSize="xs" color="gray.500">
+                  Layers using it as their default style will fall back to GeoServer&apos;s
+                  default style.
+                </Text>
+              )}
+            </Box>
+          )}
+
+          {needsTyping && (
+            <TypedConfirmation expected={confirmName} value={typed} onChange={setTyped} />
+          )}
         </ModalBody>
 
         <ModalFooter
@@ -249,6 +439,10 @@ export default function ConfirmDialog() {
             colorScheme="red"
             onClick={handleConfirm}
             isLoading={isLoading}
+            isDisabled={
+              (isStyleDelete && reassign && !reassignTo) ||
+              (needsTyping && !confirmationMatches(confirmName, typed))
+            }
             borderRadius="lg"
             px={6}
           >
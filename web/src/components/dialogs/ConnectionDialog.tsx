@@ -24,12 +24,16 @@ import {
   useToast,
   Select,
   Divider,
+  Switch,
+  FormHelperText,
 } from '@chakra-ui/react'
 import { FiEye, FiEyeOff, FiServer, FiCheck, FiDatabase } from 'react-icons/fi'
 import { useUIStore } from '../../stores/uiStore'
 import { useConnectionStore } from '../../stores/connectionStore'
 import { createPGService, testPGService, testConnectionDirect, type PGServiceCreate } from '../../api'
 import { springs } from '../../utils/animations'
+import type { AuthType, ConfirmPolicy, RestFormat } from '../../types'
+import OIDCSignIn from './OIDCSignIn'
 
 type ConnectionType = 'geoserver' | 'postgresql'
 
@@ -68,9 +72,32 @@ export default function ConnectionDialog() {
   // GeoServer fields
   const [name, setName] = useState('')
   const [url, setUrl] = useState('')
+  const [publicUrl, setPublicUrl] = useState('')
+  const [clusterUrls, setClusterUrls] = useState('')
+  const [clusterPrimary, setClusterPrimary] = useState('')
   const [username, setUsername] = useState('')
   const [password, setPassword] = useState('')
   const [showPassword, setShowPassword] = useState(false)
+  const [autoTruncateCache, setAutoTruncateCache] = useState(false)
+  const [maxConcurrentRequests, setMaxConcurrentRequests] = useState(0)
+  const [maxRequestsPerSecond, setMaxRequestsPerSecond] = useState(0)
+  const [poolMaxIdle, setPoolMaxIdle] = useState(10)
+  const [poolIdleTimeout, setPoolIdleTimeout] = useState(5)
+  const [http2, setHttp2] = useState(false)
+  const [responseTimeout, setResponseTimeout] = useState(30)
+  const [httpCompression, setHttpCompression] = useState(true)
+  const [restFormat, setRestFormat] = useState<RestFormat>('auto')
+  const [maxResponseMb, setMaxResponseMb] = useState(64)
+  const [group, setGroup] = useState('')
+  const [environment, setEnvironment] = useState('')
+  const [badge, setBadge] = useState('')
+  const [color, setColor] = useState('')
+  const [readOnly, setReadOnly] = useState(false)
+  const [confirmPolicy, setConfirmPolicy] = useState<ConfirmPolicy>('auto')
+  const [authType, setAuthType] = useState<AuthType>('basic')
+  const [oidcIssuer, setOidcIssuer] = useState('')
+  const [oidcClientId, setOidcClientId] = useState('')
+  const [oidcScope, setOidcScope] = useState('openid offline_access')
 
   // PostgreSQL fields
   const [pgName, setPgName] = useState('')
@@ -86,10 +113,16 @@ export default function ConnectionDialog() {
   const [isTesting, setIsTesting] = useState(false)
   const [testResult, setTestResult] = useState<{ success: boolean; message: string } | null>(null)
 
+  const clusterNodes = clusterUrls
+    .split(',')
+    .map((node) => node.trim().replace(/\/+$/, ''))
+    .filter(Boolean)
+
   const toast = useToast()
   const isOpen = activeDialog === 'connection'
   const isEditMode = dialogData?.mode === 'edit'
   const connectionId = dialogData?.data?.connectionId as string | undefined
+  const existingGroups = [...new Set(connections.map((c) => c.group).filter(Boolean))] as string[]
 
   // Load existing data in edit mode
   useEffect(() => {
@@ -99,18 +132,63 @@ export default function ConnectionDialog() {
         setConnectionType('geoserver')
         setName(conn.name)
         setUrl(conn.url)
+        setPublicUrl(conn.publicUrl ?? '')
+        setClusterUrls(conn.clusterUrls ?? '')
+        setClusterPrimary(conn.clusterPrimary ?? '')
         setUsername(conn.username)
         setPassword(conn.password || '')
         setShowPassword(false)
+        setAutoTruncateCache(conn.autoTruncateCache ?? false)
+        setMaxConcurrentRequests(conn.maxConcurrentRequests ?? 0)
+        setMaxRequestsPerSecond(conn.maxRequestsPerSecond ?? 0)
+        setPoolMaxIdle(conn.poolMaxIdle ?? 10)
+        setPoolIdleTimeout(conn.poolIdleTimeout ?? 5)
+        setHttp2(conn.http2 ?? false)
+        setResponseTimeout(conn.responseTimeout ?? 30)
+        setHttpCompression(conn.httpCompression ?? true)
+        setRestFormat(conn.restFormat ?? 'auto')
+        setMaxResponseMb(conn.maxResponseMb ?? 64)
+        setGroup(conn.group ?? '')
+        setEnvironment(conn.environment ?? '')
+        setBadge(conn.badge ?? '')
+        setColor(conn.color ?? '')
+        setReadOnly(conn.readOnly ?? false)
+        setConfirmPolicy(conn.confirmPolicy ?? 'auto')
+        setAuthType(conn.authType ?? 'basic')
+        setOidcIssuer(conn.oidcIssuer ?? '')
+        setOidcClientId(conn.oidcClientId ?? '')
+        setOidcScope(conn.oidcScope || 'openid offline_access')
       }
     } else if (isOpen && !isEditMode) {
       // Reset all fields for new connection
       setConnectionType('geoserver')
       setName('')
       setUrl('')
+      setPublicUrl('')
+      setClusterUrls('')
+      setClusterPrimary('')
       setUsername('')
       setPassword('')
       setShowPassword(false)
+      setAutoTruncateCache(false)
+      setMaxConcurrentRequests(0)
+      setMaxRequestsPerSecond(0)
+      setPoolMaxIdle(10)
+      setPoolIdleTimeout(5)
+      setHttp2(false)
+      setResponseTimeout(30)
+      setHttpCompression(true)
+      setMaxResponseMb(64)
+      setGroup('')
+      setEnvironment('')
+      setBadge('')
+      setColor('')
+      setReadOnly(false)
+      setConfirmPolicy('auto')
+      setAuthType('basic')
+      setOidcIssuer('')
+      setOidcClientId('')
+      setOidcScope('openid offline_access')
       setPgName('')
       setPgHost('localhost')
       setPgPort('5432')
@@ -144,6 +222,9 @@ export default function ConnectionDialog() {
           // Existing connection - test using the saved connection
           const result = await testConnection(connectionId)
           setTestResult(result)
+        } else if (authType === 'oidc') {
+          // There's no token to test with until the connection is saved and signed in
+          setTestResult({ success: false, message: 'Save the connection and sign in to test it' })
         } else {
           // New connection - test directly without saving
           const result = await testConnectionDirect({
@@ -207,14 +288,70 @@ export default function ConnectionDialog() {
         }
 
         if (isEditMode && connectionId) {
-          await updateConnection(connectionId, { name, url, username, password: password || undefined })
+          await updateConnection(connectionId, {
+            name,
+            url,
+            username,
+            password: password || undefined,
+            autoTruncateCache,
+            maxConcurrentRequests,
+            maxRequestsPerSecond,
+            poolMaxIdle,
+            poolIdleTimeout,
+            http2,
+            responseTimeout,
+            httpCompression,
+            restFormat,
+            maxResponseMb,
+            publicUrl,
+            clusterUrls,
+            clusterPrimary: clusterNodes.includes(clusterPrimary) ? clusterPrimary : '',
+            group,
+            environment,
+            badge,
+            color,
+            readOnly,
+            confirmPolicy,
+            authType,
+            oidcIssuer,
+            oidcClientId,
+            oidcScope,
+          })
           toast({
             title: 'Connection updated',
             status: 'success',
             duration: 2000,
           })
         } else {
-          await addConnection({ name, url, username, password })
+          await addConnection({
+            name,
+            url,
+            username,
+            password,
+            autoTruncateCache,
+            maxConcurrentRequests,
+            maxRequestsPerSecond,
+            poolMaxIdle,
+            poolIdleTimeout,
+            http2,
+            responseTimeout,
+            httpCompression,
+            restFormat,
+            maxResponseMb,
+            publicUrl,
+            clusterUrls,
+            clusterPrimary: clusterNodes.includes(clusterPrimary) ? clusterPrimary : '',
+            group,
+            environment,
+            badge,
+            color,
+            readOnly,
+            confirmPolicy,
+            authType,
+            oidcIssuer,
+            oidcClientId,
+            oidcScope,
+          })
           toast({
             title: 'Connection added',
             status: 'success',
@@ -435,38 +572,426 @@ export default function ConnectionDialog() {
 
                     <motion.div variants={fieldVariants} style={{ width: '100%' }}>
                       <FormControl>
-                        <FormLabel fontWeight="500" color="gray.700">Username</FormLabel>
+                        <FormLabel fontWeight="500" color="gray.700">Public URL</FormLabel>
                         <Input
-                          value={username}
-                          onChange={(e) => setUsername(e.target.value)}
-                          placeholder="admin"
+                          value={publicUrl}
+                          onChange={(e) => setPublicUrl(e.target.value)}
+                          placeholder="https://maps.example.com/geoserver"
                           size="lg"
                           borderRadius="lg"
                         />
+                        <FormHelperText>
+                          Used for map previews and OGC service links when GeoServer is reached
+                          through a proxy. Leave empty to use the URL above.
+                        </FormHelperText>
                       </FormControl>
                     </motion.div>
 
                     <motion.div variants={fieldVariants} style={{ width: '100%' }}>
                       <FormControl>
-                        <FormLabel fontWeight="500" color="gray.700">Password</FormLabel>
-                        <InputGroup size="lg">
+                        <FormLabel fontWeight="500" color="gray.700">Cluster Node URLs</FormLabel>
+                        <Input
+                          value={clusterUrls}
+                          onChange={(e) => setClusterUrls(e.target.value)}
+                          placeholder="http://node1:8080/geoserver, http://node2:8080/geoserver"
+                          size="lg"
+                          borderRadius="lg"
+                        />
+                        <FormHelperText>
+                          Comma-separated URLs of each node of a clustered GeoServer. Catalog
+                          reloads, cache resets and tile cache truncations are sent to all of
+                          them.
+                        </FormHelperText>
+                      </FormControl>
+                    </motion.div>
+
+                    {clusterNodes.length > 0 && (
+                      <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                        <FormControl>
+                          <FormLabel fontWeight="500" color="gray.700">Primary Node</FormLabel>
+                          <Select
+                            value={clusterPrimary}
+                            onChange={(e) => setClusterPrimary(e.target.value)}
+                            placeholder="First node"
+                            borderRadius="lg"
+                          >
+                            {clusterNodes.map((node) => (
+                              <option key={node} value={node}>
+                                {node}
+                              </option>
+                            ))}
+                          </Select>
+                          <FormHelperText>
+                            Catalog changes are made through this node only.
+                          </FormHelperText>
+                        </FormControl>
+                      </motion.div>
+                    )}
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <HStack spacing={4} align="flex-start">
+                        <FormControl>
+                          <FormLabel fontWeight="500" color="gray.700">Group</FormLabel>
                           <Input
-                            type={showPassword ? 'text' : 'password'}
-                            value={password}
-                            onChange={(e) => setPassword(e.target.value)}
-                            placeholder={isEditMode ? '(unchanged)' : 'password'}
+                            value={group}
+                            onChange={(e) => setGroup(e.target.value)}
+                            placeholder="Client A"
+                            list="connection-groups"
                             borderRadius="lg"
                           />
-                          <InputRightElement h="full">
-                            <IconButton
-                              aria-label={showPassword ? 'Hide password' : 'Show password'}
-                              icon={showPassword ? <FiEyeOff /> : <FiEye />}
-                              variant="ghost"
-                              size="md"
-                              onClick={() => setShowPassword(!showPassword)}
+                          <datalist id="connection-groups">
+                            {existingGroups.map((g) => (
+                              <option key={g} value={g} />
+                            ))}
+                          </datalist>
+                        </FormControl>
+                        <FormControl>
+                          <FormLabel fontWeight="500" color="gray.700">Environment</FormLabel>
+                          <Select
+                            value={environment}
+                            onChange={(e) => setEnvironment(e.target.value)}
+                            placeholder="None"
+                            borderRadius="lg"
+                          >
+                            <option value="development">Development</option>
+                            <option value="staging">Staging</option>
+                            <option value="production">Production</option>
+                          </Select>
+                        </FormControl>
+                      </HStack>
+                      <HStack spacing={4} align="flex-start" mt={4}>
+                        <FormControl>
+                          <FormLabel fontWeight="500" color="gray.700">Badge</FormLabel>
+                          <Input
+                            value={badge}
+                            onChange={(e) => setBadge(e.target.value)}
+                            placeholder="🚀 or PRD"
+                            maxLength={4}
+                            borderRadius="lg"
+                          />
+                        </FormControl>
+                        <FormControl>
+                          <FormLabel fontWeight="500" color="gray.700">Color</FormLabel>
+                          <HStack>
+                            <Input
+                              type="color"
+                              value={color || '#718096'}
+                              onChange={(e) => setColor(e.target.value)}
+                              w="56px"
+                              p={1}
+                              borderRadius="lg"
                             />
-                          </InputRightElement>
-                        </InputGroup>
+                            {color && (
+                              <Button size="sm" variant="ghost" onClick={() => setColor('')}>
+                                Use environment color
+                              </Button>
+                            )}
+                          </HStack>
+                        </FormControl>
+                      </HStack>
+                      <Text fontSize="sm" color="gray.500" mt={2}>
+                        Groups become folders in the connection tree. The badge and environment
+                        tag are shown next to the connection's name.
+                      </Text>
+                    </motion.div>
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <FormControl>
+                        <FormLabel fontWeight="500" color="gray.700">Sign in with</FormLabel>
+                        <Select
+                          value={authType}
+                          onChange={(e) => setAuthType(e.target.value as AuthType)}
+                          borderRadius="lg"
+                        >
+                          <option value="basic">Username and password</option>
+                          <option value="oidc">OpenID Connect (Keycloak or another OAuth2 proxy)</option>
+                        </Select>
+                      </FormControl>
+                    </motion.div>
+
+                    {authType === 'basic' ? (
+                      <>
+                        <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                          <FormControl>
+                            <FormLabel fontWeight="500" color="gray.700">Username</FormLabel>
+                            <Input
+                              value={username}
+                              onChange={(e) => setUsername(e.target.value)}
+                              placeholder="admin"
+                              size="lg"
+                              borderRadius="lg"
+                            />
+                          </FormControl>
+                        </motion.div>
+
+                        <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                          <FormControl>
+                            <FormLabel fontWeight="500" color="gray.700">Password</FormLabel>
+                            <InputGroup size="lg">
+                              <Input
+                                type={showPassword ? 'text' : 'password'}
+                                value={password}
+                                onChange={(e) => setPassword(e.target.value)}
+                                placeholder={isEditMode ? '(unchanged)' : 'password'}
+                                borderRadius="lg"
+                              />
+                              <InputRightElement h="full">
+                                <IconButton
+                                  aria-label={showPassword ? 'Hide password' : 'Show password'}
+                                  icon={showPassword ? <FiEyeOff /> : <FiEye />}
+                                  variant="ghost"
+                                  size="md"
+                                  onClick={() => setShowPassword(!showPassword)}
+                                />
+                              </InputRightElement>
+                            </InputGroup>
+                          </FormControl>
+                        </motion.div>
+                      </>
+                    ) : (
+                      <>
+                        <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                          <FormControl isRequired>
+                            <FormLabel fontWeight="500" color="gray.700">Issuer URL</FormLabel>
+                            <Input
+                              value={oidcIssuer}
+                              onChange={(e) => setOidcIssuer(e.target.value)}
+                              placeholder="https://sso.example.com/realms/gis"
+                              size="lg"
+                              borderRadius="lg"
+                            />
+                          </FormControl>
+                        </motion.div>
+
+                        <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                          <HStack spacing={4} align="start">
+                            <FormControl isRequired>
+                              <FormLabel fontWeight="500" color="gray.700">Client ID</FormLabel>
+                              <Input
+                                value={oidcClientId}
+                                onChange={(e) => setOidcClientId(e.target.value)}
+                                placeholder="cloudbench"
+                                borderRadius="lg"
+                              />
+                            </FormControl>
+                            <FormControl>
+                              <FormLabel fontWeight="500" color="gray.700">Scope</FormLabel>
+                              <Input
+                                value={oidcScope}
+                                onChange={(e) => setOidcScope(e.target.value)}
+                                borderRadius="lg"
+                              />
+                            </FormControl>
+                          </HStack>
+                          <Text fontSize="sm" color="gray.500" mt={2}>
+                            The client must allow the device authorization grant. Requests carry
+                            a bearer token; the refresh token is kept in the system keyring.
+                          </Text>
+                        </motion.div>
+
+                        <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                          {isEditMode && connectionId ? (
+                            <OIDCSignIn connectionId={connectionId} />
+                          ) : (
+                            <Text fontSize="sm" color="gray.500">
+                              Save the connection, then edit it to sign in.
+                            </Text>
+                          )}
+                        </motion.div>
+                      </>
+                    )}
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <FormControl display="flex" flexDirection="column">
+                        <HStack justify="space-between">
+                          <FormLabel htmlFor="read-only" fontWeight="500" color="gray.700" mb={0}>
+                            Read-only
+                          </FormLabel>
+                          <Switch
+                            id="read-only"
+                            isChecked={readOnly}
+                            onChange={(e) => setReadOnly(e.target.checked)}
+                            colorScheme="red"
+                          />
+                        </HStack>
+                        <FormHelperText>
+                          Browse only: creating, editing and deleting anything on this server is
+                          refused. A guard rail for production servers.
+                        </FormHelperText>
+                      </FormControl>
+                    </motion.div>
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <FormControl>
+                        <FormLabel fontWeight="500" color="gray.700">Confirm deletes</FormLabel>
+                        <Select
+                          value={confirmPolicy}
+                          onChange={(e) => setConfirmPolicy(e.target.value as ConfirmPolicy)}
+                          borderRadius="lg"
+                        >
+                          <option value="auto">By typing the name on production connections</option>
+                          <option value="typed">Always by typing the name</option>
+                          <option value="simple">With a single click</option>
+                        </Select>
+                      </FormControl>
+                    </motion.div>
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <FormControl display="flex" flexDirection="column">
+                        <HStack justify="space-between">
+                          <FormLabel htmlFor="auto-truncate-cache" fontWeight="500" color="gray.700" mb={0}>
+                            Truncate tile cache on data changes
+                          </FormLabel>
+                          <Switch
+                            id="auto-truncate-cache"
+                            isChecked={autoTruncateCache}
+                            onChange={(e) => setAutoTruncateCache(e.target.checked)}
+                            colorScheme="kartoza"
+                          />
+                        </HStack>
+                        <FormHelperText>
+                          Clears cached tiles when data is re-uploaded to a store or a feature type is updated
+                        </FormHelperText>
+                      </FormControl>
+                    </motion.div>
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <HStack spacing={4} align="flex-start">
+                        <FormControl>
+                          <FormLabel fontWeight="500" color="gray.700">Max concurrent requests</FormLabel>
+                          <Input
+                            type="number"
+                            min={0}
+                            value={maxConcurrentRequests}
+                            onChange={(e) => setMaxConcurrentRequests(Math.max(0, parseInt(e.target.value) || 0))}
+                            borderRadius="lg"
+                          />
+                        </FormControl>
+                        <FormControl>
+                          <FormLabel fontWeight="500" color="gray.700">Max requests / second</FormLabel>
+                          <Input
+                            type="number"
+                            min={0}
+                            step={0.5}
+                            value={maxRequestsPerSecond}
+                            onChange={(e) => setMaxRequestsPerSecond(Math.max(0, parseFloat(e.target.value) || 0))}
+                            borderRadius="lg"
+                          />
+                        </FormControl>
+                      </HStack>
+                      <Text fontSize="sm" color="gray.500" mt={2}>
+                        Throttles bulk operations against small servers. 0 means unlimited.
+                      </Text>
+                    </motion.div>
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <HStack spacing={4} align="flex-start">
+                        <FormControl>
+                          <FormLabel fontWeight="500" color="gray.700">Keep-alive connections</FormLabel>
+                          <Input
+                            type="number"
+                            min={0}
+                            value={poolMaxIdle}
+                            onChange={(e) => setPoolMaxIdle(Math.max(0, parseInt(e.target.value) || 0))}
+                            borderRadius="lg"
+                          />
+                        </FormControl>
+                        <FormControl>
+                          <FormLabel fontWeight="500" color="gray.700">Idle timeout (s)</FormLabel>
+                          <Input
+                            type="number"
+                            min={0}
+                            value={poolIdleTimeout}
+                            onChange={(e) => setPoolIdleTimeout(Math.max(0, parseFloat(e.target.value) || 0))}
+                            borderRadius="lg"
+                          />
+                        </FormControl>
+                        <FormControl>
+                          <FormLabel fontWeight="500" color="gray.700">Response timeout (s)</FormLabel>
+                          <Input
+                            type="number"
+                            min={1}
+                            value={responseTimeout}
+                            onChange={(e) => setResponseTimeout(Math.max(1, parseFloat(e.target.value) || 30))}
+                            borderRadius="lg"
+                          />
+                        </FormControl>
+                      </HStack>
+                    </motion.div>
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <FormControl>
+                        <FormLabel fontWeight="500" color="gray.700">Response size limit (MB)</FormLabel>
+                        <Input
+                          type="number"
+                          min={0}
+                          value={maxResponseMb}
+                          onChange={(e) => setMaxResponseMb(Math.max(0, parseFloat(e.target.value) || 0))}
+                          borderRadius="lg"
+                        />
+                        <FormHelperText>
+                          Bigger responses are refused instead of read; 0 means no limit. Backups and
+                          data file downloads are streamed and not limited
+                        </FormHelperText>
+                      </FormControl>
+                    </motion.div>
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <FormControl display="flex" flexDirection="column">
+                        <HStack justify="space-between">
+                          <FormLabel htmlFor="http2" fontWeight="500" color="gray.700" mb={0}>
+                            Use HTTP/2
+                          </FormLabel>
+                          <Switch
+                            id="http2"
+                            isChecked={http2}
+                            onChange={(e) => setHttp2(e.target.checked)}
+                            colorScheme="kartoza"
+                          />
+                        </HStack>
+                        <FormHelperText>
+                          Multiplexes requests over one connection when the server supports it
+                        </FormHelperText>
+                      </FormControl>
+                    </motion.div>
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <FormControl display="flex" flexDirection="column">
+                        <HStack justify="space-between">
+                          <FormLabel htmlFor="http-compression" fontWeight="500" color="gray.700" mb={0}>
+                            Compress requests and responses
+                          </FormLabel>
+                          <Switch
+                            id="http-compression"
+                            isChecked={httpCompression}
+                            onChange={(e) => setHttpCompression(e.target.checked)}
+                            colorScheme="kartoza"
+                          />
+                        </HStack>
+                        <FormHelperText>
+                          Requests gzip catalog responses and gzips large style uploads
+                        </FormHelperText>
+                      </FormControl>
+                    </motion.div>
+
+                    <motion.div variants={fieldVariants} style={{ width: '100%' }}>
+                      <FormControl>
+                        <FormLabel htmlFor="rest-format" fontWeight="500" color="gray.700">
+                          REST format
+                        </FormLabel>
+                        <Select
+                          id="rest-format"
+                          value={restFormat}
+                          onChange={(e) => setRestFormat(e.target.value as RestFormat)}
+                          borderRadius="lg"
+                        >
+                          <option value="auto">JSON, switching to XML if JSON can't be read</option>
+                          <option value="json">JSON</option>
+                          <option value="xml">XML</option>
+                        </Select>
+                        <FormHelperText>
+                          XML works around proxies and GeoServer versions that mangle JSON
+                        </FormHelperText>
                       </FormControl>
                     </motion.div>
                   </VStack>
@@ -0,0 +1,152 @@
+import { useEffect, useState } from 'react'
+import {
+  Box,
+  Button,
+  HStack,
+  Icon,
+  IconButton,
+  Input,
+  SimpleGrid,
+  Text,
+  VStack,
+  useToast,
+} from '@chakra-ui/react'
+import { FiDroplet, FiPlus, FiTrash2 } from 'react-icons/fi'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { DefaultStyleGeometry, DefaultStyleRule } from '../../types'
+
+const GEOMETRIES: { key: DefaultStyleGeometry; label: string }[] = [
+  { key: 'point', label: 'Point' },
+  { key: 'line', label: 'Line' },
+  { key: 'polygon', label: 'Polygon' },
+  { key: 'raster', label: 'Raster' },
+]
+
+const NEW_RULE: DefaultStyleRule = {
+  workspace: '*',
+  point: '',
+  line: '',
+  polygon: '',
+  raster: '',
+  connections: [],
+}
+
+/**
+ * Settings section editing the styles newly published layers get,
+ * per workspace and geometry type. Rules are kept in the config file.
+ */
+export default function DefaultStylesSettings({ isOpen }: { isOpen: boolean }) {
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [rules, setRules] = useState<DefaultStyleRule[]>([])
+
+  const { data } = useQuery({
+    queryKey: ['default-styles'],
+    queryFn: api.getDefaultStyles,
+    enabled: isOpen,
+  })
+  useEffect(() => {
+    if (data) setRules(data.rules)
+  }, [data])
+
+  const save = useMutation({
+    mutationFn: api.updateDefaultStyles,
+    onSuccess: (saved) => {
+      queryClient.setQueryData(['default-styles'], saved)
+      toast({ title: 'Default styles saved', status: 'success', duration: 3000 })
+    },
+    onError: (error: Error) =>
+      toast({ title: 'Default styles not saved', description: error.message, status: 'error' }),
+  })
+
+  const update = (index: number, changes: Partial<DefaultStyleRule>) =>
+    setRules(rules.map((rule, i) => (i === index ? { ...rule, ...changes } : rule)))
+
+  const changed = JSON.stringify(rules) !== JSON.stringify(data?.rules ?? [])
+
+  return (
+    <Box>
+      <HStack spacing={2} mb={1}>
+        <Icon as={FiDroplet} color="blue.600" />
+        <Text fontWeight="600" color="gray.700">
+          Default Styles
+        </Text>
+      </HStack>
+      <Text fontSize="xs" color="gray.500" mb={4}>
+        Styles newly published layers get, by geometry type. A workspace's own rule comes
+        first, then the rule for *. Name workspace styles as workspace:style.
+      </Text>
+
+      <VStack spacing={3} align="stretch">
+        {rules.map((rule, index) => (
+          <Box key={index} borderWidth="1px" borderRadius="md" p={3}>
+            <HStack mb={2}>
+              <Input
+                size="sm"
+                placeholder="Workspace, or * for any"
+                value={rule.workspace}
+                onChange={(e) => update(index, { workspace: e.target.value })}
+              />
+              <IconButton
+                aria-label="Delete rule"
+                icon={<FiTrash2 />}
+                size="sm"
+                variant="ghost"
+                colorScheme="red"
+                onClick={() => setRules(rules.filter((_, i) => i !== index))}
+              />
+            </HStack>
+            <SimpleGrid columns={2} spacing={2}>
+              {GEOMETRIES.map(({ key, label }) => (
+                <Input
+                  key={key}
+                  size="sm"
+                  placeholder={`${label} style`}
+                  value={rule[key]}
+                  onChange={(e) =>
+                    update(index, { [key]: e.target.value } as Partial<DefaultStyleRule>)
+                  }
+                />
+              ))}
+            </SimpleGrid>
+            <Input
+              mt={2}
+              size="sm"
+              placeholder="Connection IDs, comma-separated; all when empty"
+              value={rule.connections.join(', ')}
+              onChange={(e) =>
+                update(index, {
+                  // Empty entries are kept while typing and dropped on save
+                  connections: e.target.value.split(',').map((id) => id.trim()),
+                })
+              }
+            />
+          </Box>
+        ))}
+      </VStack>
+
+      <HStack mt={3} justify="space-between">
+        <Button
+          size="sm"
+          variant="outline"
+          leftIcon={<FiPlus />}
+          onClick={() => setRules([...rules, { ...NEW_RULE }])}
+        >
+          Add rule
+        </Button>
+        <Button
+          size="sm"
+          colorScheme="blue"
+          isDisabled={!changed}
+          isLoading={save.isPending}
+          onClick={() =>
+            save.mutate(rules.map((r) => ({ ...r, connections: r.connections.filter(Boolean) })))
+          }
+        >
+          Save
+        </Button>
+      </HStack>
+    </Box>
+  )
+}
@@ -0,0 +1,237 @@
+import { useState, useEffect } from 'react'
+import {
+  Modal,
+  ModalOverlay,
+  ModalContent,
+  ModalHeader,
+  ModalFooter,
+  ModalBody,
+  ModalCloseButton,
+  Button,
+  FormControl,
+  FormLabel,
+  FormHelperText,
+  Input,
+  NumberInput,
+  NumberInputField,
+  Select,
+  SimpleGrid,
+  Textarea,
+  VStack,
+  useToast,
+} from '@chakra-ui/react'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { GeofenceAccess, GeofenceRule } from '../../types'
+
+interface GeofenceRuleDialogProps {
+  isOpen: boolean
+  onClose: () => void
+  connectionId: string
+  rule: GeofenceRule | null // null to add a rule
+  accessTypes: GeofenceAccess[]
+  catalogModes: string[]
+  services: string[]
+}
+
+const EMPTY_RULE: Omit<GeofenceRule, 'id'> = {
+  priority: 0,
+  access: 'ALLOW',
+  user: null,
+  role: null,
+  addressRange: null,
+  service: null,
+  request: null,
+  workspace: null,
+  layer: null,
+  area: null,
+  catalogMode: null,
+}
+
+// Add or edit a GeoFence rule; empty fields match anything
+export function GeofenceRuleDialog({
+  isOpen,
+  onClose,
+  connectionId,
+  rule,
+  accessTypes,
+  catalogModes,
+  services,
+}: GeofenceRuleDialogProps) {
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [form, setForm] = useState<Omit<GeofenceRule, 'id'>>(EMPTY_RULE)
+
+  useEffect(() => {
+    if (isOpen) {
+      const { id: _id, ...fields } = rule ?? { id: null, ...EMPTY_RULE }
+      setForm(fields)
+    }
+  }, [isOpen, rule])
+
+  const { data: workspaces } = useQuery({
+    queryKey: ['workspaces', connectionId],
+    queryFn: () => api.getWorkspaces(connectionId),
+    enabled: isOpen,
+  })
+
+  const { data: layers } = useQuery({
+    queryKey: ['layers', connectionId, form.workspace],
+    queryFn: () => api.getLayers(connectionId, form.workspace!),
+    enabled: isOpen && !!form.workspace,
+  })
+
+  const saveMutation = useMutation({
+    mutationFn: () =>
+      rule?.id != null
+        ? api.updateGeofenceRule(connectionId, rule.id, form)
+        : api.createGeofenceRule(connectionId, form),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['geofenceRules', connectionId] })
+      toast({ title: rule ? 'Rule saved' : 'Rule added', status: 'success', duration: 3000 })
+      onClose()
+    },
+    onError: (err: Error) => {
+      toast({ title: 'Failed to save rule', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  const set = (field: keyof Omit<GeofenceRule, 'id'>, value: string | number | null) => {
+    setForm((current) => ({ ...current, [field]: value === '' ? null : value }))
+  }
+
+  const isLimit = form.access === 'LIMIT'
+
+  return (
+    <Modal isOpen={isOpen} onClose={onClose} size="2xl" isCentered>
+      <ModalOverlay bg="blackAlpha.600" backdropFilter="blur(4px)" />
+      <ModalContent borderRadius="xl">
+        <ModalHeader>{rule ? `Edit Rule ${rule.id}` : 'Add GeoFence Rule'}</ModalHeader>
+        <ModalCloseButton />
+        <ModalBody>
+          <VStack spacing={4} align="stretch">
+            <SimpleGrid columns={2} spacing={4}>
+              <FormControl>
+                <FormLabel>Priority</FormLabel>
+                <NumberInput
+                  min={0}
+                  value={form.priority}
+                  onChange={(_, value) => set('priority', Number.isNaN(value) ? 0 : value)}
+                >
+                  <NumberInputField />
+                </NumberInput>
+                <FormHelperText>Lower is checked first; the first match decides</FormHelperText>
+              </FormControl>
+              <FormControl>
+                <FormLabel>Access</FormLabel>
+                <Select
+                  value={form.access}
+                  onChange={(e) => {
+                    const access = e.target.value as GeofenceAccess
+                    // Only LIMIT rules carry limits
+                    setForm((current) =>
+                      access === 'LIMIT' ? { ...current, access } : { ...current, access, area: null, catalogMode: null }
+                    )
+                  }}
+                >
+                  {accessTypes.map((access) => (
+                    <option key={access} value={access}>{access}</option>
+                  ))}
+                </Select>
+              </FormControl>
+              <FormControl>
+                <FormLabel>User</FormLabel>
+                <Input value={form.user ?? ''} onChange={(e) => set('user', e.target.value)} placeholder="Any" />
+              </FormControl>
+              <FormControl>
+                <FormLabel>Role</FormLabel>
+                <Input
+                  value={form.role ?? ''}
+                  onChange={(e) => set('role', e.target.value)}
+                  placeholder="Any, e.g. ROLE_EDITOR"
+                />
+              </FormControl>
+              <FormControl>
+                <FormLabel>Service</FormLabel>
+                <Select value={form.service ?? ''} onChange={(e) => set('service', e.target.value)}>
+                  <option value="">Any</option>
+                  {services.map((service) => (
+                    <option key={service} value={service}>{service}</option>
+                  ))}
+                </Select>
+              </FormControl>
+              <FormControl>
+                <FormLabel>Request</FormLabel>
+                <Input
+                  value={form.request ?? ''}
+                  onChange={(e) => set('request', e.target.value)}
+                  placeholder="Any, e.g. GetMap"
+                />
+              </FormControl>
+              <FormControl>
+                <FormLabel>Workspace</FormLabel>
+                <Select
+                  value={form.workspace ?? ''}
+                  onChange={(e) => setForm((current) => ({ ...current, workspace: e.target.value || null, layer: null }))}
+                >
+                  <option value="">Any</option>
+                  {workspaces?.map((ws) => (
+                    <option key={ws.name} value={ws.name}>{ws.name}</option>
+                  ))}
+                </Select>
+              </FormControl>
+              <FormControl isDisabled={!form.workspace}>
+                <FormLabel>Layer</FormLabel>
+                <Select value={form.layer ?? ''} onChange={(e) => set('layer', e.target.value)}>
+                  <option value="">Any</option>
+                  {layers?.map((layer) => (
+                    <option key={layer.name} value={layer.name}>{layer.name}</option>
+                  ))}
+                </Select>
+              </FormControl>
+              <FormControl>
+                <FormLabel>Address Range</FormLabel>
+                <Input
+                  value={form.addressRange ?? ''}
+                  onChange={(e) => set('addressRange', e.target.value)}
+                  placeholder="Any, e.g. 10.0.0.0/8"
+                />
+              </FormControl>
+              <FormControl isDisabled={!isLimit}>
+                <FormLabel>Catalog Mode</FormLabel>
+                <Select value={form.catalogMode ?? ''} onChange={(e) => set('catalogMode', e.target.value)}>
+                  <option value="">Default</option>
+                  {catalogModes.map((mode) => (
+                    <option key={mode} value={mode}>{mode}</option>
+                  ))}
+                </Select>
+              </FormControl>
+            </SimpleGrid>
+            <FormControl isDisabled={!isLimit}>
+              <FormLabel>Allowed Area</FormLabel>
+              <Textarea
+                value={form.area ?? ''}
+                onChange={(e) => set('area', e.target.value)}
+                placeholder="POLYGON ((18 -34, 19 -34, 19 -33, 18 -33, 18 -34))"
+                fontFamily="mono"
+                fontSize="sm"
+                rows={3}
+              />
+              <FormHelperText>
+                WKT polygon in EPSG:4326, or prefixed with SRID=code; for another CRS. LIMIT rules only.
+              </FormHelperText>
+            </FormControl>
+          </VStack>
+        </ModalBody>
+        <ModalFooter gap={3}>
+          <Button variant="ghost" onClick={onClose}>
+            Cancel
+          </Button>
+          <Button colorScheme="kartoza" onClick={() => saveMutation.mutate()} isLoading={saveMutation.isPending}>
+            {rule ? 'Save' : 'Add Rule'}
+          </Button>
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+  )
+}
@@ -0,0 +1,369 @@
+import { useState, useEffect } from 'react'
+import {
+  Modal,
+  ModalOverlay,
+  ModalContent,
+  ModalFooter,
+  ModalBody,
+  ModalCloseButton,
+  Button,
+  VStack,
+  HStack,
+  Text,
+  Icon,
+  Box,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  Badge,
+  Spinner,
+  Alert,
+  AlertIcon,
+  Select,
+  Input,
+  IconButton,
+  Tooltip,
+  Collapse,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import {
+  FiFilter,
+  FiList,
+  FiChevronLeft,
+  FiChevronRight,
+  FiRefreshCw,
+  FiDownload,
+  FiArrowUp,
+  FiArrowDown,
+} from 'react-icons/fi'
+import { useQuery } from '@tanstack/react-query'
+import { useUIStore } from '../../stores/uiStore'
+import * as api from '../../api'
+import type { LayerAttributeQuery } from '../../types'
+import { CqlFilterBuilder } from '../common'
+
+// Attribute table of a published vector layer, paged, sorted and filtered by the server over WFS
+export default function LayerAttributesDialog() {
+  const activeDialog = useUIStore((state) => state.activeDialog)
+  const dialogData = useUIStore((state) => state.dialogData)
+  const closeDialog = useUIStore((state) => state.closeDialog)
+  const headerBg = useColorModeValue('gray.50', 'gray.700')
+  const hoverBg = useColorModeValue('gray.50', 'gray.700')
+
+  const [offset, setOffset] = useState(0)
+  const [limit, setLimit] = useState(50)
+  const [sort, setSort] = useState<string | undefined>()
+  const [order, setOrder] = useState<'asc' | 'desc'>('asc')
+  // Filters are typed into drafts and applied on Enter, so each keystroke isn't a WFS request
+  const [draftFilters, setDraftFilters] = useState<Record<string, string>>({})
+  const [filters, setFilters] = useState<Record<string, string>>({})
+  const [cql, setCql] = useState('')
+  const [showBuilder, setShowBuilder] = useState(false)
+
+  const isOpen = activeDialog === 'attributes'
+  const connectionId = dialogData?.data?.connectionId as string | undefined
+  const workspace = dialogData?.data?.workspace as string | undefined
+  const layerName = dialogData?.data?.layerName as string | undefined
+
+  // Start each layer with a fresh table
+  useEffect(() => {
+    setOffset(0)
+    setSort(undefined)
+    setOrder('asc')
+    setDraftFilters({})
+    setFilters({})
+    setCql('')
+    setShowBuilder(false)
+  }, [connectionId, workspace, layerName])
+
+  const query: LayerAttributeQuery = { sort, order, filters, cql }
+
+  const { data, isLoading, isFetching, error, refetch } = useQuery({
+    queryKey: ['layerAttributes', connectionId, workspace, layerName, offset, limit, sort, order, filters, cql],
+    queryFn: () => api.getLayerAttributes(connectionId!, workspace!, layerName!, limit, offset, query),
+    enabled: isOpen && !!connectionId && !!workspace && !!layerName,
+    // Keep the current page on screen while the next one loads
+    placeholderData: (previous) => previous,
+    retry: false,
+  })
+
+  const handleSort = (field: string) => {
+    // Cycle ascending, descending, unsorted
+    if (sort !== field) {
+      setSort(field)
+      setOrder('asc')
+    } else if (order === 'asc') {
+      setOrder('desc')
+    } else {
+      setSort(undefined)
+    }
+    setOffset(0)
+  }
+
+  const applyFilters = () => {
+    setFilters(draftFilters)
+    setOffset(0)
+  }
+
+  const clearFilters = () => {
+    setDraftFilters({})
+    setFilters({})
+    setOffset(0)
+  }
+
+  const hasFilters = Object.values(filters).some((value) => value.trim())
+
+  const formatValue = (value: unknown): string => {
+    if (value === null || value === undefined) {
+      return '∅'
+    }
+    if (typeof value === 'object') {
+      return JSON.stringify(value)
+    }
+    return String(value)
+  }
+
+  return (
+    <Modal isOpen={isOpen} onClose={closeDialog} size="6xl" isCentered scrollBehavior="inside">
+      <ModalOverlay bg="blackAlpha.600" backdropFilter="blur(4px)" />
+      <ModalContent borderRadius="xl" overflow="hidden" maxH="85vh">
+        {/* Gradient Header */}
+        <Box
+          bg="linear-gradient(135deg, #0a3a50 0%, #175a77 50%, #2d7d9b 100%)"
+          p={4}
+        >
+          <HStack spacing={3} justify="space-between" pr={8}>
+            <HStack spacing={3}>
+              <Box bg="whiteAlpha.200" p={2} borderRadius="lg">
+                <Icon as={FiList} boxSize={5} color="white" />
+              </Box>
+              <Box>
+                <Text color="white" fontWeight="600" fontSize="lg">
+                  Attribute Table
+                </Text>
+                <Text color="whiteAlpha.800" fontSize="sm">
+                  {workspace}:{layerName}
+                </Text>
+              </Box>
+            </HStack>
+            <HStack spacing={2}>
+              <Button
+                size="sm"
+                variant="ghost"
+                color="white"
+                _hover={{ bg: 'whiteAlpha.200' }}
+                leftIcon={<FiFilter />}
+                onClick={() => setShowBuilder(!showBuilder)}
+                isActive={showBuilder}
+              >
+                {cql ? 'CQL Filter (on)' : 'CQL Filter'}
+              </Button>
+              <Button
+                as="a"
+                href={
+                  connectionId && workspace && layerName
+                    ? api.getLayerAttributesExportUrl(connectionId, workspace, layerName, query)
+                    : undefined
+                }
+                size="sm"
+                variant="ghost"
+                color="white"
+                _hover={{ bg: 'whiteAlpha.200' }}
+                leftIcon={<FiDownload />}
+                isDisabled={!data || data.total === 0}
+              >
+                Export CSV
+              </Button>
+              <Tooltip label="Refresh data">
+                <IconButton
+                  aria-label="Refresh"
+                  icon={<FiRefreshCw />}
+                  size="sm"
+                  variant="ghost"
+                  color="white"
+                  _hover={{ bg: 'whiteAlpha.200' }}
+                  onClick={() => refetch()}
+                  isLoading={isFetching}
+                />
+              </Tooltip>
+            </HStack>
+          </HStack>
+        </Box>
+        <ModalCloseButton color="white" />
+
+        <ModalBody py={4} overflowY="auto">
+          {connectionId && workspace && layerName && (
+            <Collapse in={showBuilder} animateOpacity>
+              <Box mb={4}>
+                <CqlFilterBuilder
+                  connectionId={connectionId}
+                  workspace={workspace}
+                  layerName={layerName}
+                  value={cql}
+                  onApply={(next) => {
+                    setCql(next)
+                    setOffset(0)
+                    setShowBuilder(false)
+                  }}
+                />
+              </Box>
+            </Collapse>
+          )}
+
+          {isLoading && (
+            <VStack py={8}>
+              <Spinner size="lg" color="kartoza.500" />
+              <Text color="gray.500">Loading features...</Text>
+            </VStack>
+          )}
+
+          {error && (
+            <Alert status="error" borderRadius="lg" mb={4}>
+              <AlertIcon />
+              <Text fontSize="sm">{(error as Error).message}</Text>
+            </Alert>
+          )}
+
+          {data && (
+            <VStack spacing={4} align="stretch">
+              <HStack justify="space-between">
+                <HStack spacing={2}>
+                  <Badge colorScheme="blue">{data.total.toLocaleString()} features</Badge>
+                  <Badge colorScheme="gray">{data.fields.length} attributes</Badge>
+                  {data.geometryType && <Badge colorScheme="cyan">{data.geometryType}</Badge>}
+                  {hasFilters && (
+                    <Button size="xs" variant="link" onClick={clearFilters}>
+                      Clear filters
+                    </Button>
+                  )}
+                </HStack>
+                <HStack spacing={2}>
+                  <Text fontSize="sm" color="gray.500">Rows per page:</Text>
+                  <Select
+                    size="sm"
+                    width="80px"
+                    value={limit}
+                    onChange={(e) => {
+                      setLimit(Number(e.target.value))
+                      setOffset(0)
+                    }}
+                  >
+                    <option value={25}>25</option>
+                    <option value={50}>50</option>
+                    <option value={100}>100</option>
+                    <option value={250}>250</option>
+                  </Select>
+                </HStack>
+              </HStack>
+
+              <Box borderWidth="1px" borderRadius="lg" overflow="auto" maxH="50vh">
+                <Table size="sm">
+                  <Thead bg={headerBg} position="sticky" top={0} zIndex={1}>
+                    <Tr>
+                      {data.fields.map((field) => (
+                        <Th
+                          key={field}
+                          cursor="pointer"
+                          whiteSpace="nowrap"
+                          textTransform="none"
+                          onClick={() => handleSort(field)}
+                        >
+                          <HStack spacing={1}>
+                            <Text>{field}</Text>
+                            {sort === field && <Icon as={order === 'asc' ? FiArrowUp : FiArrowDown} />}
+                          </HStack>
+                          <Text fontSize="2xs" fontWeight="normal" color="gray.500">
+                            {data.types[field]}
+                            {data.nillable[field] === false && ', not null'}
+                          </Text>
+                        </Th>
+                      ))}
+                    </Tr>
+                    <Tr>
+                      {data.fields.map((field) => (
+                        <Th key={field} py={1}>
+                          <Input
+                            size="xs"
+                            minW="90px"
+                            placeholder="Filter"
+                            value={draftFilters[field] ?? ''}
+                            onChange={(e) => setDraftFilters({ ...draftFilters, [field]: e.target.value })}
+                            onKeyDown={(e) => e.key === 'Enter' && applyFilters()}
+                          />
+                        </Th>
+                      ))}
+                    </Tr>
+                  </Thead>
+                  <Tbody>
+                    {data.rows.map((row, idx) => (
+                      <Tr key={offset + idx} _hover={{ bg: hoverBg }}>
+                        {data.fields.map((field) => (
+                          <Td key={field}>
+                            <Text
+                              fontSize="sm"
+                              maxW="200px"
+                              isTruncated
+                              color={row[field] === null ? 'gray.400' : undefined}
+                            >
+                              {formatValue(row[field])}
+                            </Text>
+                          </Td>
+                        ))}
+                      </Tr>
+                    ))}
+                  </Tbody>
+                </Table>
+                {data.rows.length === 0 && (
+                  <Text textAlign="center" py={6} fontSize="sm" color="gray.500">
+                    No features match the filters
+                  </Text>
+                )}
+              </Box>
+
+              <Text fontSize="xs" color="gray.500">
+                Click a column to sort. Filters match text anywhere in the value, or compare
+                with =, !=, &lt;, &lt;=, &gt; or &gt;= (e.g. "&gt;= 100"). Press Enter to apply.
+              </Text>
+
+              {/* Pagination */}
+              <HStack justify="center" spacing={4}>
+                <IconButton
+                  aria-label="Previous page"
+                  icon={<FiChevronLeft />}
+                  size="sm"
+                  isDisabled={offset === 0}
+                  onClick={() => setOffset(Math.max(0, offset - limit))}
+                />
+                <Text fontSize="sm" color="gray.600">
+                  {data.rows.length === 0
+                    ? 'No rows'
+                    : `Showing ${offset + 1} - ${offset + data.rows.length} of ${data.total.toLocaleString()}`}
+                </Text>
+                <IconButton
+                  aria-label="Next page"
+                  icon={<FiChevronRight />}
+                  size="sm"
+                  isDisabled={!data.hasMore}
+                  onClick={() => setOffset(offset + limit)}
+                />
+              </HStack>
+            </VStack>
+          )}
+        </ModalBody>
+
+        <ModalFooter
+          borderTop="1px solid"
+          borderTopColor="gray.100"
+          bg="gray.50"
+        >
+          <Button onClick={closeDialog} borderRadius="lg">
+            Close
+          </Button>
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+  )
+}
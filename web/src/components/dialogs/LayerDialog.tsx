@@ -19,6 +19,8 @@ import {
   Icon,
   Badge,
   SimpleGrid,
+  NumberInput,
+  NumberInputField,
   Divider,
   Spinner,
   Tabs,
@@ -43,11 +45,19 @@ import {
   Radio,
   RadioGroup,
   Stack,
+  Collapse,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
 } from '@chakra-ui/react'
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
-import { FiLayers, FiEye, FiSearch, FiInfo, FiGlobe, FiLink, FiPlus, FiTrash2, FiDroplet, FiStar, FiEdit3, FiRefreshCw } from 'react-icons/fi'
+import { FiLayers, FiEye, FiSearch, FiInfo, FiGlobe, FiLink, FiPlus, FiTrash2, FiDroplet, FiStar, FiEdit3, FiRefreshCw, FiFilter, FiList } from 'react-icons/fi'
 import { useUIStore } from '../../stores/uiStore'
 import { useTreeStore } from '../../stores/treeStore'
+import { CqlFilterBuilder } from '../common'
 import * as api from '../../api'
 import type { LayerMetadataUpdate, MetadataLink } from '../../types'
 
@@ -78,6 +88,14 @@ export default function LayerDialog() {
   const [additionalStyles, setAdditionalStyles] = useState<string[]>([])
   const [stylesChanged, setStylesChanged] = useState(false)
 
+  // Filtered feature count state
+  const [countFilterInput, setCountFilterInput] = useState('')
+  const [countFilter, setCountFilter] = useState('')
+  const [showCountBuilder, setShowCountBuilder] = useState(false)
+
+  // Attributes ticked for publishing; null until the selection is changed
+  const [keptAttributes, setKeptAttributes] = useState<string[] | null>(null)
+
   const isOpen = activeDialog === 'layer'
 
   const connectionId = (dialogData?.data?.connectionId as string) || selectedNode?.connectionId || ''
@@ -105,6 +123,41 @@ export default function LayerDialog() {
     enabled: isOpen && !!connectionId && !!workspace && !!layerName,
   })
 
+  // Feature counts share the tree's cache key so reopening the dialog is instant
+  const isVector = !!metadata && metadata.storeType !== 'coveragestore'
+  const { data: featureCount, isFetching: loadingCount } = useQuery({
+    queryKey: ['feature-count', connectionId, workspace, layerName],
+    queryFn: () => api.getLayerFeatureCount(connectionId, workspace, layerName),
+    enabled: isOpen && isVector,
+    staleTime: 5 * 60 * 1000,
+    gcTime: 10 * 60 * 1000,
+    retry: false,
+  })
+
+  const {
+    data: filteredCount,
+    isFetching: loadingFilteredCount,
+    error: filteredCountError,
+  } = useQuery({
+    queryKey: ['feature-count', connectionId, workspace, layerName, countFilter],
+    queryFn: () => api.getLayerFeatureCount(connectionId, workspace, layerName, { cqlFilter: countFilter }),
+    enabled: isOpen && isVector && !!countFilter,
+    staleTime: 5 * 60 * 1000,
+    gcTime: 10 * 60 * 1000,
+    retry: false,
+  })
+
+  const { data: schema, isLoading: loadingSchema, error: schemaError } = useQuery({
+    queryKey: ['layerSchema', connectionId, workspace, layerName],
+    queryFn: () => api.getLayerSchema(connectionId, workspace, layerName),
+    enabled: isOpen && isVector,
+    retry: false,
+  })
+
+  useEffect(() => {
+    setKeptAttributes(null)
+  }, [connectionId, workspace, layerName])
+
   useEffect(() => {
     if (metadata) {
       setFormData({
@@ -117,6 +170,9 @@ export default function LayerDialog() {
         queryable: metadata.queryable,
         attributionTitle: metadata.attributionTitle || '',
         attributionHref: metadata.attributionHref || '',
+        attributionLogo: metadata.attributionLogo || '',
+        attributionLogoWidth: metadata.attributionLogoWidth ?? null,
+        attributionLogoHeight: metadata.attributionLogoHeight ?? null,
       })
       setMetadataLinks(metadata.metadataLinks || [])
     }
@@ -159,6 +215,31 @@ export default function LayerDialog() {
     },
   })
 
+  const updateAttributesMutation = useMutation({
+    mutationFn: (attributes: string[]) =>
+      api.updateLayerAttributes(connectionId, workspace, layerName, attributes),
+    onSuccess: (updated) => {
+      queryClient.setQueryData(['layerSchema', connectionId, workspace, layerName], updated)
+      queryClient.invalidateQueries({ queryKey: ['layerAttributes', connectionId, workspace, layerName] })
+      queryClient.invalidateQueries({ queryKey: ['filterAttributes', connectionId, workspace, layerName] })
+      setKeptAttributes(null)
+      toast({
+        title: 'Attributes updated',
+        description: `The layer now publishes ${updated.attributes.length} attributes.`,
+        status: 'success',
+        duration: 3000,
+      })
+    },
+    onError: (error: Error) => {
+      toast({
+        title: 'Error updating attributes',
+        description: error.message,
+        status: 'error',
+        duration: 5000,
+      })
+    },
+  })
+
   const updateStylesMutation = useMutation({
     mutationFn: () =>
       api.updateLayerStyles(connectionId, workspace, layerName, defaultStyle, additionalStyles),
@@ -329,6 +410,9 @@ export default function LayerDialog() {
                 <Tab><HStack spacing={2}><Icon as={FiDroplet} /><Text>Styles</Text></HStack></Tab>
                 <Tab><HStack spacing={2}><Icon as={FiGlobe} /><Text>Description</Text></HStack></Tab>
                 <Tab><HStack spacing={2}><Icon as={FiLink} /><Text>Attribution</Text></HStack></Tab>
+                {isVector && (
+                  <Tab><HStack spacing={2}><Icon as={FiList} /><Text>Schema</Text></HStack></Tab>
+                )}
               </TabList>
 
               <TabPanels>
@@ -448,8 +532,80 @@ export default function LayerDialog() {
                               <Text fontSize="xs" color="gray.500">Declared SRS</Text>
                               <Code fontSize="sm">{metadata?.srs || 'Unknown'}</Code>
                             </Box>
+                            {isVector && (
+                              <Box>
+                                <Text fontSize="xs" color="gray.500">Features</Text>
+                                {loadingCount ? (
+                                  <Spinner size="xs" />
+                                ) : (
+                                  <Text fontWeight="medium">
+                                    {featureCount !== undefined ? featureCount.toLocaleString() : 'Unknown'}
+                                  </Text>
+                                )}
+                              </Box>
+                            )}
                           </SimpleGrid>
 
+                          {isVector && (
+                            <Box mt={4}>
+                              <Text fontSize="xs" color="gray.500" mb={2}>Count features matching a CQL filter</Text>
+                              <HStack>
+                                <Input
+                                  size="sm"
+                                  fontFamily="mono"
+                                  value={countFilterInput}
+                                  onChange={(e) => setCountFilterInput(e.target.value)}
+                                  onKeyDown={(e) => e.key === 'Enter' && setCountFilter(countFilterInput.trim())}
+                                  placeholder="e.g. PERSONS > 1000000 or BBOX(the_geom, -10, 35, 30, 60)"
+                                />
+                                <Button
+                                  size="sm"
+                                  leftIcon={<FiSearch />}
+                                  onClick={() => setCountFilter(countFilterInput.trim())}
+                                  isLoading={loadingFilteredCount}
+                                  isDisabled={!countFilterInput.trim()}
+                                >
+                                  Count
+                                </Button>
+                                <Button
+                                  size="sm"
+                                  variant="ghost"
+                                  leftIcon={<FiFilter />}
+                                  onClick={() => setShowCountBuilder(!showCountBuilder)}
+                                  isActive={showCountBuilder}
+                                >
+                                  Build
+                                </Button>
+                              </HStack>
+                              <Collapse in={showCountBuilder} animateOpacity>
+                                <Box mt={2}>
+                                  <CqlFilterBuilder
+                                    connectionId={connectionId}
+                                    workspace={workspace}
+                                    layerName={layerName}
+                                    value={countFilter}
+                                    onApply={(next) => {
+                                      setCountFilterInput(next)
+                                      setCountFilter(next)
+                                      setShowCountBuilder(false)
+                                    }}
+                                  />
+                                </Box>
+                              </Collapse>
+                              {countFilter && !loadingFilteredCount && (
+                                filteredCountError ? (
+                                  <Text fontSize="sm" color="red.500" mt={2}>
+                                    {(filteredCountError as Error).message}
+                                  </Text>
+                                ) : filteredCount !== undefined && (
+                                  <Text fontSize="sm" mt={2}>
+                                    <strong>{filteredCount.toLocaleString()}</strong> matching feature{filteredCount === 1 ? '' : 's'}
+                                  </Text>
+                                )
+                              )}
+                            </Box>
+                          )}
+
                           {metadata?.nativeBoundingBox && (
                             <Box mt={4}>
                               <Text fontSize="xs" color="gray.500" mb={2}>Native Bounding Box</Text>
@@ -764,6 +920,43 @@ export default function LayerDialog() {
                       />
                     </FormControl>
 
+                    <FormControl>
+                      <FormLabel fontWeight="500">Logo (URL)</FormLabel>
+                      <Input
+                        value={formData.attributionLogo || ''}
+                        onChange={(e) => handleChange('attributionLogo', e.target.value)}
+                        placeholder="https://example.com/logo.png"
+                        bg="gray.50"
+                        borderRadius="lg"
+                      />
+                      <Text fontSize="xs" color="gray.500" mt={1}>
+                        PNG, JPEG, GIF or SVG image shown with the attribution
+                      </Text>
+                    </FormControl>
+
+                    <SimpleGrid columns={2} spacing={4}>
+                      <FormControl isDisabled={!formData.attributionLogo}>
+                        <FormLabel fontWeight="500">Logo Width (px)</FormLabel>
+                        <NumberInput
+                          min={1}
+                          value={formData.attributionLogoWidth ?? ''}
+                          onChange={(_, value) => handleChange('attributionLogoWidth', Number.isNaN(value) ? null : value)}
+                        >
+                          <NumberInputField bg="gray.50" borderRadius="lg" />
+                        </NumberInput>
+                      </FormControl>
+                      <FormControl isDisabled={!formData.attributionLogo}>
+                        <FormLabel fontWeight="500">Logo Height (px)</FormLabel>
+                        <NumberInput
+                          min={1}
+                          value={formData.attributionLogoHeight ?? ''}
+                          onChange={(_, value) => handleChange('attributionLogoHeight', Number.isNaN(value) ? null : value)}
+                        >
+                          <NumberInputField bg="gray.50" borderRadius="lg" />
+                        </NumberInput>
+                      </FormControl>
+                    </SimpleGrid>
+
                     <Divider />
 
                     <Box>
@@ -850,6 +1043,99 @@ export default function LayerDialog() {
                     </Box>
                   </VStack>
                 </TabPanel>
+
+                {/* Schema Tab */}
+                {isVector && (
+                  <TabPanel px={0} py={4}>
+                    <VStack spacing={4} align="stretch">
+                      <Box p={4} bg="blue.50" borderRadius="lg" borderLeft="4px solid" borderLeftColor="blue.400">
+                        <Text fontSize="sm" color="blue.700">
+                          <strong>Schema</strong> lists the attributes the layer publishes, from WFS DescribeFeatureType. Untick attributes to hide them from WFS, feature info and the attribute table. Hidden attributes can only be brought back by republishing the layer.
+                        </Text>
+                      </Box>
+
+                      {loadingSchema && (
+                        <HStack justify="center" py={4}>
+                          <Spinner size="sm" color="kartoza.500" />
+                          <Text fontSize="sm" color="gray.500">Reading schema...</Text>
+                        </HStack>
+                      )}
+
+                      {schemaError && (
+                        <Text fontSize="sm" color="red.500">{(schemaError as Error).message}</Text>
+                      )}
+
+                      {schema && (
+                        <>
+                          <HStack spacing={2}>
+                            <Badge colorScheme="gray">{schema.attributes.length} attributes</Badge>
+                            {schema.geometryType && <Badge colorScheme="cyan">{schema.geometryType}</Badge>}
+                            {schema.srs && <Badge colorScheme="purple">{schema.srs}</Badge>}
+                          </HStack>
+
+                          <Box borderWidth="1px" borderRadius="lg" overflow="hidden">
+                            <Table size="sm">
+                              <Thead bg="gray.50">
+                                <Tr>
+                                  <Th width="60px">Publish</Th>
+                                  <Th>Name</Th>
+                                  <Th>Type</Th>
+                                  <Th width="80px">Nullable</Th>
+                                </Tr>
+                              </Thead>
+                              <Tbody>
+                                {schema.attributes.map((attribute) => {
+                                  const kept = keptAttributes ?? schema.attributes.map((a) => a.name)
+                                  return (
+                                    <Tr key={attribute.name}>
+                                      <Td>
+                                        <Checkbox
+                                          isChecked={kept.includes(attribute.name)}
+                                          // The geometry is needed to draw the layer
+                                          isDisabled={attribute.geometry}
+                                          onChange={(e) =>
+                                            setKeptAttributes(
+                                              e.target.checked
+                                                ? schema.attributes.map((a) => a.name).filter((n) => n === attribute.name || kept.includes(n))
+                                                : kept.filter((n) => n !== attribute.name)
+                                            )
+                                          }
+                                        />
+                                      </Td>
+                                      <Td>
+                                        <Text fontWeight="500">{attribute.name}</Text>
+                                      </Td>
+                                      <Td>
+                                        <Badge colorScheme={attribute.geometry ? 'cyan' : 'gray'} fontFamily="mono" fontSize="xs">
+                                          {attribute.localType}
+                                        </Badge>
+                                      </Td>
+                                      <Td>
+                                        <Text fontSize="sm" color="gray.600">{attribute.nillable ? 'Yes' : 'No'}</Text>
+                                      </Td>
+                                    </Tr>
+                                  )
+                                })}
+                              </Tbody>
+                            </Table>
+                          </Box>
+
+                          <HStack justify="flex-end">
+                            <Button
+                              size="sm"
+                              colorScheme="kartoza"
+                              onClick={() => keptAttributes && updateAttributesMutation.mutate(keptAttributes)}
+                              isLoading={updateAttributesMutation.isPending}
+                              isDisabled={!keptAttributes || keptAttributes.length === schema.attributes.length}
+                            >
+                              Publish Selected Attributes
+                            </Button>
+                          </HStack>
+                        </>
+                      )}
+                    </VStack>
+                  </TabPanel>
+                )}
               </TabPanels>
             </Tabs>
           )}
@@ -22,25 +22,35 @@ import {
   Spinner,
   Alert,
   AlertIcon,
+  IconButton,
+  Tooltip,
   useToast,
 } from '@chakra-ui/react'
 import { useQuery, useQueryClient } from '@tanstack/react-query'
-import { FiGrid, FiLayers } from 'react-icons/fi'
+import { FiArrowDown, FiArrowUp, FiGrid, FiLayers } from 'react-icons/fi'
 import { useUIStore } from '../../stores/uiStore'
 import { useTreeStore } from '../../stores/treeStore'
+import { useConnectionStore } from '../../stores/connectionStore'
+import { publicBaseUrl, serviceUrl } from '../../utils/ows'
 import * as api from '../../api'
 
+// Size of the composition preview; GeoServer fits the layers' extent into it
+const PREVIEW_WIDTH = 512
+const PREVIEW_HEIGHT = 320
+
 export default function LayerGroupDialog() {
   const activeDialog = useUIStore((state) => state.activeDialog)
   const dialogData = useUIStore((state) => state.dialogData)
   const closeDialog = useUIStore((state) => state.closeDialog)
   const selectedNode = useTreeStore((state) => state.selectedNode)
+  const connections = useConnectionStore((state) => state.connections)
 
   const [name, setName] = useState('')
   const [title, setTitle] = useState('')
   const [mode, setMode] = useState<'SINGLE' | 'NAMED' | 'CONTAINER' | 'EO'>('SINGLE')
   const [selectedLayers, setSelectedLayers] = useState<string[]>([])
   const [isLoading, setIsLoading] = useState(false)
+  const [previewUrl, setPreviewUrl] = useState<string | null>(null)
 
   const toast = useToast()
   const queryClient = useQueryClient()
@@ -59,6 +69,32 @@ export default function LayerGroupDialog() {
     enabled: isOpen && !!connectionId && !!workspace,
   })
 
+  // Composited GetMap of the current draw order, refetched whenever it changes
+  const { data: previewImage, isFetching: isFetchingPreview, error: previewError } = useQuery({
+    queryKey: ['layergroup-preview', connectionId, workspace, selectedLayers],
+    queryFn: () =>
+      api.previewLayerGroup(connectionId, workspace, selectedLayers, PREVIEW_WIDTH, PREVIEW_HEIGHT),
+    enabled: isOpen && !!connectionId && !!workspace && selectedLayers.length > 0,
+    staleTime: 60000,
+    retry: false,
+  })
+
+  useEffect(() => {
+    if (!previewImage) {
+      setPreviewUrl(null)
+      return
+    }
+    const url = URL.createObjectURL(previewImage)
+    setPreviewUrl(url)
+    return () => URL.revokeObjectURL(url)
+  }, [previewImage])
+
+  const connection = connections.find((c) => c.id === connectionId)
+  const legendUrl = (layer: string) =>
+    connection
+      ? `${serviceUrl(publicBaseUrl(connection), 'wms', workspace)}?SERVICE=WMS&VERSION=1.1.1&REQUEST=GetLegendGraphic&LAYER=${workspace}:${layer}&FORMAT=image/png&WIDTH=20&HEIGHT=20&LEGEND_OPTIONS=forceLabels:on;fontAntiAliasing:true`
+      : null
+
   useEffect(() => {
     if (isOpen) {
       if (isEditMode && dialogData?.data) {
@@ -154,6 +190,17 @@ export default function LayerGroupDialog() {
     )
   }
 
+  // Layers are drawn in list order, so a later layer is drawn over an earlier one
+  const handleMove = (index: number, offset: number) => {
+    const target = index + offset
+    if (target < 0 || target >= selectedLayers.length) return
+    setSelectedLayers((prev) => {
+      const next = [...prev]
+      ;[next[index], next[target]] = [next[target], next[index]]
+      return next
+    })
+  }
+
   const handleSelectAll = () => {
     if (layers) {
       if (selectedLayers.length === layers.length) {
@@ -167,7 +214,7 @@ export default function LayerGroupDialog() {
   if (!isOpen) return null
 
   return (
-    <Modal isOpen={isOpen} onClose={closeDialog} size="lg" isCentered>
+    <Modal isOpen={isOpen} onClose={closeDialog} size="xl" isCentered>
       <ModalOverlay bg="blackAlpha.600" backdropFilter="blur(4px)" />
       <ModalContent borderRadius="xl" overflow="hidden" maxH="85vh">
         {/* Gradient Header */}
@@ -308,6 +355,120 @@ export default function LayerGroupDialog() {
                 )}
               </Box>
             </FormControl>
+
+            {selectedLayers.length > 0 && (
+              <FormControl>
+                <FormLabel fontWeight="500" color="gray.700">
+                  Draw Order &amp; Preview
+                </FormLabel>
+                <Text fontSize="xs" color="gray.500" mb={2}>
+                  Top of the list is drawn last, over the layers below it
+                </Text>
+                <HStack align="start" spacing={3}>
+                  <Stack
+                    spacing={1}
+                    flex="0 0 200px"
+                    border="1px solid"
+                    borderColor="gray.200"
+                    borderRadius="lg"
+                    p={2}
+                    maxH={`${PREVIEW_HEIGHT}px`}
+                    overflowY="auto"
+                  >
+                    {selectedLayers
+                      .map((layer, index) => ({ layer, index }))
+                      .reverse()
+                      .map(({ layer, index }) => {
+                        const legend = legendUrl(layer)
+                        return (
+                          <Box key={layer} p={1} borderRadius="md" _hover={{ bg: 'gray.50' }}>
+                            <HStack spacing={1}>
+                              <Text fontSize="sm" fontWeight="500" flex={1} noOfLines={1}>
+                                {layer}
+                              </Text>
+                              <Tooltip label="Draw above">
+                                <IconButton
+                                  aria-label="Move up"
+                                  icon={<Icon as={FiArrowUp} />}
+                                  size="xs"
+                                  variant="ghost"
+                                  isDisabled={index === selectedLayers.length - 1}
+                                  onClick={() => handleMove(index, 1)}
+                                />
+                              </Tooltip>
+                              <Tooltip label="Draw below">
+                                <IconButton
+                                  aria-label="Move down"
+                                  icon={<Icon as={FiArrowDown} />}
+                                  size="xs"
+                                  variant="ghost"
+                                  isDisabled={index === 0}
+                                  onClick={() => handleMove(index, -1)}
+                                />
+                              </Tooltip>
+                            </HStack>
+                            {legend && (
+                              <Box
+                                as="img"
+                                src={legend}
+                                alt={`${layer} legend`}
+                                maxW="100%"
+                                mt={1}
+                                onError={(e: React.SyntheticEvent<HTMLImageElement>) => {
+                                  e.currentTarget.style.display = 'none'
+                                }}
+                              />
+                            )}
+                          </Box>
+                        )
+                      })}
+                  </Stack>
+                  <Box
+                    flex={1}
+                    h={`${PREVIEW_HEIGHT}px`}
+                    border="1px solid"
+                    borderColor="gray.200"
+                    borderRadius="lg"
+                    overflow="hidden"
+                    position="relative"
+                    bg="gray.50"
+                  >
+                    {previewUrl && (
+                      <Box
+                        as="img"
+                        src={previewUrl}
+                        alt="Layer group preview"
+                        w="100%"
+                        h="100%"
+                        objectFit="contain"
+                        opacity={isFetchingPreview ? 0.5 : 1}
+                      />
+                    )}
+                    {isFetchingPreview && (
+                      <HStack position="absolute" top={2} right={2}>
+                        <Spinner size="sm" color="kartoza.500" />
+                      </HStack>
+                    )}
+                    {previewError && !isFetchingPreview && (
+                      <Alert
+                        status="warning"
+                        borderRadius="md"
+                        position="absolute"
+                        bottom={2}
+                        left={2}
+                        right={2}
+                        w="auto"
+                      >
+                        <AlertIcon />
+                        <Text fontSize="sm">
+                          {previewError instanceof Error ? previewError.message : 'Preview failed'}
+                        </Text>
+                      </Alert>
+                    )}
+                  </Box>
+                </HStack>
+              </FormControl>
+            )}
           </VStack>
         </ModalBody>
 
@@ -0,0 +1,251 @@
+import { useState, useEffect } from 'react'
+import {
+  Modal,
+  ModalOverlay,
+  ModalContent,
+  ModalFooter,
+  ModalBody,
+  ModalCloseButton,
+  Button,
+  Box,
+  Text,
+  VStack,
+  HStack,
+  Icon,
+  FormControl,
+  FormLabel,
+  FormHelperText,
+  Input,
+  Select,
+  Textarea,
+  Alert,
+  AlertIcon,
+  useToast,
+} from '@chakra-ui/react'
+import { FiCopy, FiMap } from 'react-icons/fi'
+import { useQuery } from '@tanstack/react-query'
+import { useUIStore } from '../../stores/uiStore'
+import { useTreeStore } from '../../stores/treeStore'
+import { useConnectionStore } from '../../stores/connectionStore'
+import * as api from '../../api'
+import type { MapExport, MapExportFormat } from '../../types'
+
+const FORMAT_LABELS: Record<MapExportFormat, string> = {
+  mapbox: 'Mapbox GL style (JSON)',
+  mapproxy: 'MapProxy configuration (YAML)',
+}
+
+// Mapbox GL and MapProxy's GLOBAL_WEBMERCATOR grid only draw in Web Mercator
+const GRID_SETS = ['EPSG:900913', 'EPSG:3857', 'WebMercatorQuad']
+
+export default function MapExportDialog() {
+  const activeDialog = useUIStore((state) => state.activeDialog)
+  const closeDialog = useUIStore((state) => state.closeDialog)
+  const markedNodes = useTreeStore((state) => state.markedNodes)
+  const connections = useConnectionStore((state) => state.connections)
+  const toast = useToast()
+
+  const [format, setFormat] = useState<MapExportFormat>('mapbox')
+  const [name, setName] = useState('')
+  const [gridSet, setGridSet] = useState(GRID_SETS[0])
+  const [directory, setDirectory] = useState('')
+  const [isExporting, setIsExporting] = useState(false)
+  const [result, setResult] = useState<MapExport | null>(null)
+  const [error, setError] = useState<string | null>(null)
+
+  const isOpen = activeDialog === 'mapexport'
+  // Marked in tree order, which is the draw order from the bottom
+  const marked = Object.values(markedNodes).filter(
+    (node) =>
+      (node.type === 'layer' || node.type === 'layergroup') && node.connectionId && node.workspace
+  )
+  const connectionIds = [...new Set(marked.map((node) => node.connectionId as string))]
+  const connection = connections.find((c) => c.id === connectionIds[0])
+
+  const { data: appSettings } = useQuery({
+    queryKey: ['app-settings'],
+    queryFn: api.getAppSettings,
+    enabled: isOpen,
+  })
+
+  useEffect(() => {
+    if (isOpen) {
+      setFormat('mapbox')
+      setName('')
+      setGridSet(GRID_SETS[0])
+      setResult(null)
+      setError(null)
+    }
+  }, [isOpen])
+
+  useEffect(() => {
+    if (isOpen && appSettings) setDirectory(appSettings.lastLocalPath)
+  }, [isOpen, appSettings])
+
+  const handleExport = async () => {
+    setIsExporting(true)
+    setError(null)
+    try {
+      const response = await api.exportWebMap(connectionIds[0], {
+        layers: marked.map((node) => `${node.workspace}:${node.name}`),
+        format,
+        name: name || undefined,
+        gridSet,
+        directory: directory || undefined,
+      })
+      setResult(response)
+    } catch (err) {
+      setError((err as Error).message)
+    } finally {
+      setIsExporting(false)
+    }
+  }
+
+  const copyContent = () => {
+    if (!result) return
+    navigator.clipboard.writeText(result.content).then(
+      () =>
+        toast({ title: 'Copied', description: result.filename, status: 'success', duration: 2000 }),
+      () => toast({ title: 'Could not copy', status: 'error', duration: 5000 })
+    )
+  }
+
+  return (
+    <Modal isOpen={isOpen} onClose={closeDialog} size="2xl" isCentered>
+      <ModalOverlay bg="blackAlpha.600" backdropFilter="blur(4px)" />
+      <ModalContent borderRadius="xl" overflow="hidden" maxH="85vh">
+        <Box
+          bg="linear-gradient(135deg, #0a3a50 0%, #175a77 50%, #2d7d9b 100%)"
+          px={6}
+          py={4}
+        >
+          <HStack spacing={3}>
+            <Box bg="whiteAlpha.200" p={2} borderRadius="lg">
+              <Icon as={FiMap} boxSize={5} color="white" />
+            </Box>
+            <Box flex="1">
+              <Text color="white" fontWeight="600" fontSize="lg">
+                Export a Web Map
+              </Text>
+              <Text color="whiteAlpha.800" fontSize="sm">
+                {marked.length} marked layer{marked.length === 1 ? '' : 's'} drawn from their
+                cached tiles, first marked at the bottom
+              </Text>
+            </Box>
+          </HStack>
+        </Box>
+        <ModalCloseButton color="white" isDisabled={isExporting} />
+
+        <ModalBody py={6} overflowY="auto">
+          <VStack spacing={4} align="stretch">
+            {marked.length === 0 && (
+              <Alert status="info" borderRadius="md">
+                <AlertIcon />
+                Mark layers or layer groups in the tree to export them
+              </Alert>
+            )}
+            {connectionIds.length > 1 && (
+              <Alert status="warning" borderRadius="md">
+                <AlertIcon />
+                The marked layers belong to several connections; mark layers of one only
+              </Alert>
+            )}
+
+            <HStack spacing={4} align="flex-start">
+              <FormControl isDisabled={isExporting}>
+                <FormLabel fontSize="sm">Format</FormLabel>
+                <Select
+                  size="sm"
+                  value={format}
+                  onChange={(e) => setFormat(e.target.value as MapExportFormat)}
+                >
+                  {(Object.keys(FORMAT_LABELS) as MapExportFormat[]).map((f) => (
+                    <option key={f} value={f}>
+                      {FORMAT_LABELS[f]}
+                    </option>
+                  ))}
+                </Select>
+              </FormControl>
+              <FormControl isDisabled={isExporting}>
+                <FormLabel fontSize="sm">Grid Set</FormLabel>
+                <Select size="sm" value={gridSet} onChange={(e) => setGridSet(e.target.value)}>
+                  {GRID_SETS.map((g) => (
+                    <option key={g} value={g}>
+                      {g}
+                    </option>
+                  ))}
+                </Select>
+              </FormControl>
+            </HStack>
+            <FormControl isDisabled={isExporting}>
+              <FormLabel fontSize="sm">Name</FormLabel>
+              <Input
+                size="sm"
+                value={name}
+                onChange={(e) => setName(e.target.value)}
+                placeholder={connection?.name}
+              />
+              <FormHelperText>Names the style and the file written</FormHelperText>
+            </FormControl>
+            <FormControl isDisabled={isExporting}>
+              <FormLabel fontSize="sm">Folder</FormLabel>
+              <Input
+                size="sm"
+                fontFamily="mono"
+                value={directory}
+                onChange={(e) => setDirectory(e.target.value)}
+              />
+              <FormHelperText>
+                The file browser's folder by default; a file of the same name is replaced
+              </FormHelperText>
+            </FormControl>
+
+            {error && (
+              <Text fontSize="sm" color="red.500">
+                {error}
+              </Text>
+            )}
+
+            {result && (
+              <VStack spacing={2} align="stretch">
+                <HStack>
+                  <Text fontSize="sm" flex="1" fontFamily="mono" wordBreak="break-all">
+                    Wrote {result.path}
+                  </Text>
+                  <Button size="xs" leftIcon={<FiCopy />} onClick={copyContent}>
+                    Copy
+                  </Button>
+                </HStack>
+                <Textarea
+                  value={result.content}
+                  isReadOnly
+                  fontFamily="mono"
+                  fontSize="xs"
+                  rows={14}
+                />
+              </VStack>
+            )}
+          </VStack>
+        </ModalBody>
+
+        <ModalFooter gap={3} borderTop="1px solid" borderTopColor="gray.100" bg="gray.50">
+          <Button variant="ghost" onClick={closeDialog} borderRadius="lg" isDisabled={isExporting}>
+            {result ? 'Close' : 'Cancel'}
+          </Button>
+          <Button
+            colorScheme="kartoza"
+            onClick={handleExport}
+            isLoading={isExporting}
+            loadingText="Exporting..."
+            isDisabled={marked.length === 0 || connectionIds.length !== 1}
+            leftIcon={<FiMap />}
+            borderRadius="lg"
+            px={6}
+          >
+            Export
+          </Button>
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+  )
+}
@@ -0,0 +1,122 @@
+import { useEffect, useRef } from 'react'
+import {
+  Box,
+  Button,
+  HStack,
+  Link,
+  Text,
+  Code,
+  Badge,
+  useToast,
+} from '@chakra-ui/react'
+import { FiLogIn, FiLogOut, FiExternalLink } from 'react-icons/fi'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import * as api from '../../api'
+import { useConnectionStore } from '../../stores/connectionStore'
+
+interface OIDCSignInProps {
+  connectionId: string
+}
+
+// Device sign-in of a saved OIDC connection: shows the code to enter at the provider and waits
+export default function OIDCSignIn({ connectionId }: OIDCSignInProps) {
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const fetchConnections = useConnectionStore((state) => state.fetchConnections)
+  const queryKey = ['signIn', connectionId]
+
+  // Checking the status also polls a waiting sign-in, at the interval the provider asks for
+  const { data: signIn, error } = useQuery({
+    queryKey,
+    queryFn: () => api.getSignInStatus(connectionId),
+    refetchInterval: (query) => {
+      const pending = query.state.data?.pending
+      return pending ? pending.interval * 1000 : false
+    },
+  })
+
+  // The tree shows the sign-in, and what the connection may do depends on who signed in
+  const signedIn = !!signIn?.signedIn
+  const wasSignedIn = useRef<boolean | null>(null)
+  useEffect(() => {
+    if (wasSignedIn.current !== null && wasSignedIn.current !== signedIn) {
+      fetchConnections()
+      queryClient.invalidateQueries({ queryKey: ['serverPermissions', connectionId] })
+    }
+    if (signIn) wasSignedIn.current = signedIn
+  }, [signIn, signedIn, connectionId, fetchConnections, queryClient])
+
+  const startMutation = useMutation({
+    mutationFn: () => api.startSignIn(connectionId),
+    onSuccess: (status) => {
+      queryClient.setQueryData(queryKey, status)
+      const uri = status.pending?.verificationUriComplete
+      if (uri) window.open(uri, '_blank', 'noopener')
+    },
+    onError: (err: Error) => {
+      toast({ title: 'Sign-in failed', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  const signOutMutation = useMutation({
+    mutationFn: () => api.signOut(connectionId),
+    onSuccess: () => {
+      queryClient.setQueryData(queryKey, { signedIn: false, pending: null })
+    },
+  })
+
+  const pending = signIn?.pending
+
+  return (
+    <Box p={4} borderWidth="1px" borderRadius="lg" w="100%">
+      <HStack justify="space-between" mb={pending || error ? 3 : 0}>
+        <HStack>
+          <Text fontWeight="500" color="gray.700">
+            Sign-in
+          </Text>
+          <Badge colorScheme={signedIn ? 'green' : 'gray'}>
+            {signedIn ? 'Signed in' : pending ? 'Waiting for code' : 'Not signed in'}
+          </Badge>
+        </HStack>
+        {signedIn ? (
+          <Button
+            size="sm"
+            variant="outline"
+            leftIcon={<FiLogOut />}
+            onClick={() => signOutMutation.mutate()}
+            isLoading={signOutMutation.isPending}
+          >
+            Sign Out
+          </Button>
+        ) : (
+          <Button
+            size="sm"
+            colorScheme="kartoza"
+            leftIcon={<FiLogIn />}
+            onClick={() => startMutation.mutate()}
+            isLoading={startMutation.isPending}
+          >
+            {pending ? 'New Code' : 'Sign In'}
+          </Button>
+        )}
+      </HStack>
+      {pending && (
+        <Text fontSize="sm">
+          Enter <Code fontSize="md">{pending.userCode}</Code> at{' '}
+          <Link
+            href={pending.verificationUriComplete || pending.verificationUri}
+            isExternal
+            color="kartoza.600"
+          >
+            {pending.verificationUri} <FiExternalLink style={{ display: 'inline' }} />
+          </Link>
+        </Text>
+      )}
+      {error && (
+        <Text fontSize="sm" color="red.500">
+          {(error as Error).message}
+        </Text>
+      )}
+    </Box>
+  )
+}
@@ -0,0 +1,268 @@
+import { useState, useEffect } from 'react'
+import {
+  Modal,
+  ModalOverlay,
+  ModalContent,
+  ModalFooter,
+  ModalBody,
+  ModalCloseButton,
+  Button,
+  Box,
+  Text,
+  VStack,
+  HStack,
+  Icon,
+  Badge,
+  FormControl,
+  FormLabel,
+  FormHelperText,
+  Input,
+  Select,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiSend } from 'react-icons/fi'
+import { useQuery, useQueryClient } from '@tanstack/react-query'
+import { useUIStore } from '../../stores/uiStore'
+import { useConnectionStore } from '../../stores/connectionStore'
+import * as api from '../../api'
+import type { QGISPublishOutcome, QGISPublishResult } from '../../types'
+
+const STATUS_COLORS: Record<QGISPublishOutcome['status'], string> = {
+  published: 'green',
+  skipped: 'gray',
+  error: 'red',
+}
+
+export default function QGISPublishDialog() {
+  const activeDialog = useUIStore((state) => state.activeDialog)
+  const dialogData = useUIStore((state) => state.dialogData)
+  const closeDialog = useUIStore((state) => state.closeDialog)
+  const connections = useConnectionStore((state) => state.connections)
+  const queryClient = useQueryClient()
+
+  const [connectionId, setConnectionId] = useState('')
+  const [workspace, setWorkspace] = useState('')
+  const [groupName, setGroupName] = useState('')
+  const [isPublishing, setIsPublishing] = useState(false)
+  const [result, setResult] = useState<QGISPublishResult | null>(null)
+  const [error, setError] = useState<string | null>(null)
+
+  const headerBg = useColorModeValue('gray.50', 'gray.700')
+
+  const isOpen = activeDialog === 'qgispublish'
+  const projectId = (dialogData?.data?.projectId as string) || ''
+  const projectName = (dialogData?.data?.projectName as string) || ''
+  const writable = connections.filter((c) => !c.readOnly)
+
+  useEffect(() => {
+    if (isOpen) {
+      setConnectionId('')
+      setWorkspace('')
+      setGroupName('')
+      setResult(null)
+      setError(null)
+    }
+  }, [isOpen])
+
+  const { data: workspaces } = useQuery({
+    queryKey: ['workspaces', connectionId],
+    queryFn: () => api.getWorkspaces(connectionId),
+    enabled: isOpen && !!connectionId,
+  })
+
+  const handlePublish = async () => {
+    setIsPublishing(true)
+    setError(null)
+    try {
+      const response = await api.publishQGISProject(
+        projectId,
+        connectionId,
+        workspace,
+        groupName || undefined
+      )
+      setResult(response)
+      queryClient.invalidateQueries({ queryKey: ['layers', connectionId, workspace] })
+      queryClient.invalidateQueries({ queryKey: ['layergroups', connectionId, workspace] })
+    } catch (err) {
+      setError((err as Error).message)
+    } finally {
+      setIsPublishing(false)
+    }
+  }
+
+  return (
+    <Modal isOpen={isOpen} onClose={closeDialog} size="3xl" isCentered>
+      <ModalOverlay bg="blackAlpha.600" backdropFilter="blur(4px)" />
+      <ModalContent borderRadius="xl" overflow="hidden" maxH="85vh">
+        <Box
+          bg="linear-gradient(135deg, #0a3a50 0%, #175a77 50%, #2d7d9b 100%)"
+          px={6}
+          py={4}
+        >
+          <HStack spacing={3}>
+            <Box bg="whiteAlpha.200" p={2} borderRadius="lg">
+              <Icon as={FiSend} boxSize={5} color="white" />
+            </Box>
+            <Box flex="1">
+              <Text color="white" fontWeight="600" fontSize="lg">
+                Publish {projectName} to GeoServer
+              </Text>
+              <Text color="whiteAlpha.800" fontSize="sm">
+                Publishes its PostGIS, GeoPackage, shapefile and GeoTIFF layers with their
+                symbology, grouped as in the layer panel
+              </Text>
+            </Box>
+          </HStack>
+        </Box>
+        <ModalCloseButton color="white" isDisabled={isPublishing} />
+
+        <ModalBody py={6} overflowY="auto">
+          <VStack spacing={4} align="stretch">
+            <HStack spacing={4} align="flex-start">
+              <FormControl isRequired isDisabled={isPublishing || result !== null}>
+                <FormLabel fontSize="sm">Connection</FormLabel>
+                <Select
+                  size="sm"
+                  placeholder="Choose a connection"
+                  value={connectionId}
+                  onChange={(e) => {
+                    setConnectionId(e.target.value)
+                    setWorkspace('')
+                  }}
+                >
+                  {writable.map((c) => (
+                    <option key={c.id} value={c.id}>
+                      {c.name}
+                    </option>
+                  ))}
+                </Select>
+              </FormControl>
+              <FormControl isRequired isDisabled={!connectionId || isPublishing || result !== null}>
+                <FormLabel fontSize="sm">Workspace</FormLabel>
+                <Select
+                  size="sm"
+                  placeholder="Choose a workspace"
+                  value={workspace}
+                  onChange={(e) => setWorkspace(e.target.value)}
+                >
+                  {workspaces?.map((ws) => (
+                    <option key={ws.name} value={ws.name}>
+                      {ws.name}
+                    </option>
+                  ))}
+                </Select>
+              </FormControl>
+            </HStack>
+            <FormControl isDisabled={isPublishing || result !== null}>
+              <FormLabel fontSize="sm">Layer Group</FormLabel>
+              <Input
+                size="sm"
+                value={groupName}
+                onChange={(e) => setGroupName(e.target.value)}
+                placeholder="The project's title"
+              />
+              <FormHelperText>
+                Files are published only when they are stored next to the project
+              </FormHelperText>
+            </FormControl>
+
+            {error && (
+              <Text fontSize="sm" color="red.500">
+                {error}
+              </Text>
+            )}
+
+            {result && (
+              <>
+                <HStack spacing={2} wrap="wrap">
+                  {(Object.keys(STATUS_COLORS) as QGISPublishOutcome['status'][]).map((status) => (
+                    <Badge key={status} colorScheme={STATUS_COLORS[status]}>
+                      {result.counts[status]} {status}
+                    </Badge>
+                  ))}
+                  {result.groups.length > 0 && (
+                    <Text fontSize="sm" color="gray.500">
+                      Layer group {result.groups[result.groups.length - 1]}
+                    </Text>
+                  )}
+                </HStack>
+                {result.errors.map((message) => (
+                  <Text key={message} fontSize="sm" color="red.500">
+                    {message}
+                  </Text>
+                ))}
+                <Box overflowX="auto">
+                  <Table size="sm">
+                    <Thead bg={headerBg}>
+                      <Tr>
+                        <Th>Project Layer</Th>
+                        <Th>Layer</Th>
+                        <Th>Store</Th>
+                        <Th>Result</Th>
+                      </Tr>
+                    </Thead>
+                    <Tbody>
+                      {result.layers.map((outcome, i) => (
+                        <Tr key={`${outcome.source}-${i}`}>
+                          <Td>
+                            <Text fontSize="xs">{outcome.source}</Text>
+                          </Td>
+                          <Td>
+                            <Text fontSize="xs">{outcome.layer}</Text>
+                          </Td>
+                          <Td>
+                            <Text fontSize="xs">{outcome.store}</Text>
+                          </Td>
+                          <Td>
+                            <HStack spacing={2}>
+                              <Badge fontSize="2xs" colorScheme={STATUS_COLORS[outcome.status]}>
+                                {outcome.status}
+                              </Badge>
+                              {outcome.message && (
+                                <Text
+                                  fontSize="xs"
+                                  color={outcome.status === 'error' ? 'red.500' : 'gray.500'}
+                                  noOfLines={2}
+                                >
+                                  {outcome.message}
+                                </Text>
+                              )}
+                            </HStack>
+                          </Td>
+                        </Tr>
+                      ))}
+                    </Tbody>
+                  </Table>
+                </Box>
+              </>
+            )}
+          </VStack>
+        </ModalBody>
+
+        <ModalFooter gap={3} borderTop="1px solid" borderTopColor="gray.100" bg="gray.50">
+          <Button variant="ghost" onClick={closeDialog} borderRadius="lg" isDisabled={isPublishing}>
+            {result ? 'Close' : 'Cancel'}
+          </Button>
+          <Button
+            colorScheme="kartoza"
+            onClick={handlePublish}
+            isLoading={isPublishing}
+            loadingText="Publishing..."
+            isDisabled={!projectId || !connectionId || !workspace || result !== null}
+            leftIcon={<FiSend />}
+            borderRadius="lg"
+            px={6}
+          >
+            Publish
+          </Button>
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+  )
+}
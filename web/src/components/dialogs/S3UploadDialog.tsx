@@ -229,6 +229,8 @@ export default function S3UploadDialog() {
         return toolStatus.gdal?.available || false
       case 'copc':
         return toolStatus.pdal?.available || false
+      case '3dtiles':
+        return toolStatus.py3dtiles?.available || false
       case 'geoparquet':
         return toolStatus.ogr2ogr?.available || false
       default:
@@ -384,6 +386,9 @@ export default function S3UploadDialog() {
                         <option value="copc" disabled={!canConvert('copc')}>
                           COPC {!canConvert('copc') && '- unavailable'}
                         </option>
+                        <option value="3dtiles" disabled={!canConvert('3dtiles')}>
+                          3D Tiles {!canConvert('3dtiles') && '- unavailable'}
+                        </option>
                         <option value="geoparquet" disabled={!canConvert('geoparquet')}>
                           GeoParquet {!canConvert('geoparquet') && '- unavailable'}
                         </option>
@@ -0,0 +1,203 @@
+import { useState, Fragment } from 'react'
+import {
+  Modal,
+  ModalOverlay,
+  ModalContent,
+  ModalBody,
+  ModalFooter,
+  ModalCloseButton,
+  Button,
+  VStack,
+  HStack,
+  Text,
+  Switch,
+  FormControl,
+  FormLabel,
+  Box,
+  Icon,
+  Badge,
+  Spinner,
+  Alert,
+  AlertIcon,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  Tooltip,
+} from '@chakra-ui/react'
+import { useQuery } from '@tanstack/react-query'
+import { FiColumns, FiRefreshCw } from 'react-icons/fi'
+import * as api from '../../api'
+import { useUIStore } from '../../stores/uiStore'
+import type { ComparisonRow } from '../../types'
+
+function ValueCell({ value, differs, error }: { value: string | null; differs: boolean; error?: string }) {
+  if (value === null) {
+    return (
+      <Td>
+        <Tooltip label={error}>
+          <Text as="span" fontStyle="italic" color="gray.400">
+            unreadable
+          </Text>
+        </Tooltip>
+      </Td>
+    )
+  }
+  return (
+    <Td bg={differs ? 'orange.50' : undefined} fontFamily="mono" fontSize="xs" wordBreak="break-all">
+      {value === '' ? <Text as="span" color="gray.400">—</Text> : value}
+    </Td>
+  )
+}
+
+export default function ServerComparisonDialog() {
+  const activeDialog = useUIStore((state) => state.activeDialog)
+  const closeDialog = useUIStore((state) => state.closeDialog)
+  const [differencesOnly, setDifferencesOnly] = useState(true)
+
+  const isOpen = activeDialog === 'compare'
+
+  const { data, isLoading, isFetching, error, refetch } = useQuery({
+    queryKey: ['serverComparison'],
+    queryFn: () => api.getServerComparison(),
+    enabled: isOpen,
+    staleTime: 60 * 1000,
+  })
+
+  const rows = (data?.rows ?? []).filter((row) => !differencesOnly || row.differs)
+  const sections: Record<string, ComparisonRow[]> = {}
+  for (const row of rows) {
+    sections[row.section] = [...(sections[row.section] ?? []), row]
+  }
+
+  return (
+    <Modal isOpen={isOpen} onClose={closeDialog} size="6xl" scrollBehavior="inside">
+      <ModalOverlay bg="blackAlpha.600" backdropFilter="blur(4px)" />
+      <ModalContent borderRadius="xl" overflow="hidden">
+        {/* Header */}
+        <Box
+          bg="linear-gradient(135deg, #0a3a50 0%, #175a77 50%, #2d7d9b 100%)"
+          px={6}
+          py={4}
+        >
+          <HStack spacing={3}>
+            <Box bg="whiteAlpha.200" p={2} borderRadius="lg">
+              <Icon as={FiColumns} boxSize={5} color="white" />
+            </Box>
+            <Box>
+              <Text color="white" fontWeight="600" fontSize="lg">
+                Compare Servers
+              </Text>
+              <Text color="whiteAlpha.800" fontSize="sm">
+                Versions, extensions and settings side by side
+              </Text>
+            </Box>
+          </HStack>
+        </Box>
+        <ModalCloseButton color="white" />
+
+        <ModalBody py={6}>
+          <VStack spacing={4} align="stretch">
+            <HStack justify="space-between">
+              <FormControl display="flex" alignItems="center" w="auto">
+                <Switch
+                  id="differences-only"
+                  isChecked={differencesOnly}
+                  onChange={(e) => setDifferencesOnly(e.target.checked)}
+                  mr={2}
+                />
+                <FormLabel htmlFor="differences-only" mb={0}>
+                  Differences only
+                </FormLabel>
+              </FormControl>
+              {data && (
+                <Badge colorScheme={data.differenceCount ? 'orange' : 'green'}>
+                  {data.differenceCount} difference(s)
+                </Badge>
+              )}
+            </HStack>
+
+            {error && (
+              <Alert status="error" borderRadius="md">
+                <AlertIcon />
+                {(error as Error).message}
+              </Alert>
+            )}
+
+            {data &&
+              Object.entries(data.errors).map(([connectionId, sections]) => (
+                <Alert key={connectionId} status="warning" borderRadius="md" fontSize="sm">
+                  <AlertIcon />
+                  {data.connections.find((c) => c.id === connectionId)?.name ?? connectionId}:
+                  couldn't read {Object.keys(sections).join(', ')}
+                </Alert>
+              ))}
+
+            {isLoading ? (
+              <HStack justify="center" py={8}>
+                <Spinner />
+                <Text color="gray.500">Reading server settings...</Text>
+              </HStack>
+            ) : data && rows.length === 0 ? (
+              <Text color="gray.500" textAlign="center" py={8}>
+                {differencesOnly ? 'The servers agree on every compared setting' : 'Nothing to compare'}
+              </Text>
+            ) : data ? (
+              <Box overflowX="auto">
+                <Table size="sm">
+                  <Thead>
+                    <Tr>
+                      <Th>Setting</Th>
+                      {data.connections.map((conn) => (
+                        <Th key={conn.id}>{conn.name}</Th>
+                      ))}
+                    </Tr>
+                  </Thead>
+                  <Tbody>
+                    {Object.entries(sections).map(([section, sectionRows]) => (
+                      <Fragment key={section}>
+                        <Tr>
+                          <Td colSpan={data.connections.length + 1} bg="gray.50" fontWeight="600">
+                            {section}
+                          </Td>
+                        </Tr>
+                        {sectionRows.map((row) => (
+                          <Tr key={`${section}:${row.key}`}>
+                            <Td fontSize="sm">{row.key}</Td>
+                            {data.connections.map((conn) => (
+                              <ValueCell
+                                key={conn.id}
+                                value={row.values[conn.id] ?? null}
+                                differs={row.differs}
+                                error={data.errors[conn.id]?.[section]}
+                              />
+                            ))}
+                          </Tr>
+                        ))}
+                      </Fragment>
+                    ))}
+                  </Tbody>
+                </Table>
+              </Box>
+            ) : null}
+          </VStack>
+        </ModalBody>
+
+        <ModalFooter>
+          <Button
+            leftIcon={<FiRefreshCw />}
+            variant="ghost"
+            mr={3}
+            onClick={() => refetch()}
+            isLoading={isFetching}
+          >
+            Refresh
+          </Button>
+          <Button onClick={closeDialog}>Close</Button>
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+  )
+}
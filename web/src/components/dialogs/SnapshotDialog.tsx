@@ -0,0 +1,153 @@
+import { useState, useEffect } from 'react'
+import {
+  Modal,
+  ModalOverlay,
+  ModalContent,
+  ModalHeader,
+  ModalFooter,
+  ModalBody,
+  ModalCloseButton,
+  Button,
+  Checkbox,
+  VStack,
+  HStack,
+  Text,
+  Icon,
+} from '@chakra-ui/react'
+import { FiCheckCircle, FiAlertTriangle, FiXCircle } from 'react-icons/fi'
+import { useUIStore } from '../../stores/uiStore'
+import { useTreeStore } from '../../stores/treeStore'
+import * as api from '../../api'
+
+const SNAPSHOT_TYPES = ['datastore', 'coveragestore', 'layer', 'style', 'layergroup']
+
+interface SnapshotOutcome {
+  connectionId: string
+  failed: number
+  error?: string
+}
+
+export default function SnapshotDialog() {
+  const activeDialog = useUIStore((state) => state.activeDialog)
+  const closeDialog = useUIStore((state) => state.closeDialog)
+  const markedNodes = useTreeStore((state) => state.markedNodes)
+
+  const [includeData, setIncludeData] = useState(false)
+  const [isRunning, setIsRunning] = useState(false)
+  const [outcomes, setOutcomes] = useState<SnapshotOutcome[] | null>(null)
+
+  const isOpen = activeDialog === 'snapshot'
+  const marked = Object.values(markedNodes).filter(
+    (node) => node.connectionId && node.workspace && SNAPSHOT_TYPES.includes(node.type)
+  )
+  const connectionIds = [...new Set(marked.map((node) => node.connectionId as string))]
+
+  useEffect(() => {
+    if (isOpen) {
+      setIncludeData(false)
+      setOutcomes(null)
+    }
+  }, [isOpen])
+
+  // One snapshot per connection, each downloaded as its own zip
+  const run = async () => {
+    setIsRunning(true)
+    const collected: SnapshotOutcome[] = []
+    for (const connectionId of connectionIds) {
+      const resources = marked
+        .filter((node) => node.connectionId === connectionId)
+        .map((node) => ({
+          type: node.type as api.DownloadResourceType,
+          workspace: node.workspace,
+          name: node.name,
+        }))
+      try {
+        const failed = await api.downloadResources(connectionId, resources, includeData)
+        collected.push({ connectionId, failed })
+      } catch (err) {
+        collected.push({ connectionId, failed: resources.length, error: (err as Error).message })
+      }
+      setOutcomes([...collected])
+    }
+    setIsRunning(false)
+  }
+
+  return (
+    <Modal isOpen={isOpen} onClose={closeDialog} size="lg" scrollBehavior="inside">
+      <ModalOverlay />
+      <ModalContent>
+        <ModalHeader>Download Snapshot</ModalHeader>
+        <ModalCloseButton isDisabled={isRunning} />
+        <ModalBody>
+          <VStack align="stretch" spacing={4}>
+            <Text fontSize="sm">
+              {marked.length} marked resource{marked.length === 1 ? '' : 's'}
+              {connectionIds.length > 1 && ` on ${connectionIds.length} connections`}. Their
+              configurations are saved in a dated folder laid out like the catalog, with a
+              manifest.json listing every file and its checksum.
+            </Text>
+
+            {outcomes === null && (
+              <Checkbox isChecked={includeData} onChange={(e) => setIncludeData(e.target.checked)}>
+                Include layer data (shapefiles and GeoTIFFs)
+              </Checkbox>
+            )}
+
+            <VStack align="stretch" spacing={1} maxH="300px" overflowY="auto">
+              {outcomes === null
+                ? marked.map((node) => (
+                    <Text key={node.id} fontSize="sm" fontFamily="mono">
+                      {node.type} {node.workspace}:{node.name}
+                    </Text>
+                  ))
+                : outcomes.map((outcome) => (
+                    <HStack key={outcome.connectionId} spacing={2} align="start">
+                      <Icon
+                        as={
+                          outcome.error
+                            ? FiXCircle
+                            : outcome.failed > 0
+                            ? FiAlertTriangle
+                            : FiCheckCircle
+                        }
+                        color={
+                          outcome.error
+                            ? 'red.500'
+                            : outcome.failed > 0
+                            ? 'orange.500'
+                            : 'green.500'
+                        }
+                        mt={1}
+                      />
+                      <Text fontSize="sm">
+                        {outcome.connectionId}:{' '}
+                        {outcome.error
+                          ? outcome.error
+                          : outcome.failed > 0
+                          ? `${outcome.failed} resource(s) couldn't be read; see manifest.json`
+                          : 'downloaded'}
+                      </Text>
+                    </HStack>
+                  ))}
+            </VStack>
+          </VStack>
+        </ModalBody>
+        <ModalFooter>
+          <Button variant="ghost" mr={3} onClick={closeDialog} isDisabled={isRunning}>
+            {outcomes === null ? 'Cancel' : 'Close'}
+          </Button>
+          {outcomes === null && (
+            <Button
+              colorScheme="kartoza"
+              onClick={run}
+              isLoading={isRunning}
+              isDisabled={marked.length === 0}
+            >
+              Download
+            </Button>
+          )}
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+  )
+}
@@ -27,7 +27,7 @@ import { FiDatabase, FiImage, FiFolder, FiInfo } from 'react-icons/fi'
 import { useUIStore } from '../../stores/uiStore'
 import { useTreeStore } from '../../stores/treeStore'
 import * as api from '../../api'
-import type { CoverageStore } from '../../types'
+import type { CoverageStore, DataStore } from '../../types'
 
 export default function StoreDialog() {
   const activeDialog = useUIStore((state) => state.activeDialog)
@@ -172,6 +172,14 @@ export default function StoreDialog() {
                         <Text fontSize="sm" color="gray.500">Enabled:</Text>
                         <Code fontSize="sm">{store.enabled ? 'true' : 'false'}</Code>
                       </HStack>
+                      {/* Passwords arrive masked from the server */}
+                      {isDataStore &&
+                        (store as DataStore).connectionParameters?.entry.map((param) => (
+                          <HStack key={param['@key']} justify="space-between">
+                            <Text fontSize="sm" color="gray.500">{param['@key']}:</Text>
+                            <Code fontSize="sm">{String(param.$)}</Code>
+                          </HStack>
+                        ))}
                     </VStack>
                   </AccordionPanel>
                 </AccordionItem>
@@ -0,0 +1,319 @@
+import { useState, useRef, useEffect } from 'react'
+import {
+  Modal,
+  ModalOverlay,
+  ModalContent,
+  ModalFooter,
+  ModalBody,
+  ModalCloseButton,
+  Button,
+  Box,
+  Text,
+  VStack,
+  HStack,
+  Icon,
+  IconButton,
+  Badge,
+  Checkbox,
+  Input,
+  Spinner,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  useToast,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiCheck, FiEdit2, FiImage, FiUpload, FiX } from 'react-icons/fi'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import { useUIStore } from '../../stores/uiStore'
+import * as api from '../../api'
+
+const UPLOAD_EXTENSIONS = ['.png', '.svg']
+
+function formatSize(bytes: number): string {
+  if (bytes < 1024) return `${bytes} B`
+  if (bytes < 1024 * 1024) return `${(bytes / 1024).toFixed(1)} KB`
+  return `${(bytes / (1024 * 1024)).toFixed(1)} MB`
+}
+
+export default function StyleAssetsDialog() {
+  const activeDialog = useUIStore((state) => state.activeDialog)
+  const dialogData = useUIStore((state) => state.dialogData)
+  const closeDialog = useUIStore((state) => state.closeDialog)
+  const queryClient = useQueryClient()
+  const toast = useToast()
+
+  const [global, setGlobal] = useState(false)
+  const [folder, setFolder] = useState('icons')
+  // Path of the asset being moved and the path typed for it
+  const [moving, setMoving] = useState<string | null>(null)
+  const [target, setTarget] = useState('')
+  const fileInputRef = useRef<HTMLInputElement>(null)
+
+  const headerBg = useColorModeValue('gray.50', 'gray.700')
+
+  const isOpen = activeDialog === 'styleassets'
+  const connectionId = (dialogData?.data?.connectionId as string) || ''
+  const workspace = global ? '' : (dialogData?.data?.workspace as string) || ''
+
+  useEffect(() => {
+    if (isOpen) {
+      setGlobal(false)
+      setMoving(null)
+    }
+  }, [isOpen])
+
+  const queryKey = ['styleassets', connectionId, workspace]
+  const { data: listing, isLoading, error } = useQuery({
+    queryKey,
+    queryFn: () => api.getStyleAssets(connectionId, workspace),
+    enabled: isOpen && !!connectionId,
+  })
+
+  const uploadMutation = useMutation({
+    mutationFn: (files: File[]) => api.uploadStyleAssets(connectionId, workspace, files, folder),
+    onSuccess: (result) => {
+      queryClient.invalidateQueries({ queryKey })
+      toast({ title: `Uploaded ${result.uploaded.join(', ')}`, status: 'success', duration: 3000 })
+    },
+    onError: (err: Error) => {
+      toast({ title: 'Upload failed', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  const moveMutation = useMutation({
+    mutationFn: ({ source, target }: { source: string; target: string }) =>
+      api.moveStyleAsset(connectionId, workspace, source, target),
+    onSuccess: (move) => {
+      setMoving(null)
+      queryClient.invalidateQueries({ queryKey })
+      const failed = Object.entries(move.failed)
+      toast({
+        title: `Moved ${move.source} to ${move.target}`,
+        description: failed.length
+          ? `Not updated: ${failed.map(([style, message]) => `${style} (${message})`).join(', ')}`
+          : move.updated.length
+            ? `Updated styles: ${move.updated.join(', ')}`
+            : 'No style referred to it',
+        status: failed.length ? 'warning' : 'success',
+        duration: failed.length ? 8000 : 4000,
+      })
+    },
+    onError: (err: Error) => {
+      toast({ title: 'Move failed', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  const handleFileSelect = (e: React.ChangeEvent<HTMLInputElement>) => {
+    const files = Array.from(e.target.files || [])
+    if (files.length > 0) {
+      uploadMutation.mutate(files)
+    }
+    e.target.value = ''
+  }
+
+  const startMove = (path: string) => {
+    setMoving(path)
+    setTarget(path)
+  }
+
+  const location = global ? 'global styles folder' : `styles folder of ${workspace}`
+
+  return (
+    <Modal isOpen={isOpen} onClose={closeDialog} size="3xl" isCentered>
+      <ModalOverlay bg="blackAlpha.600" backdropFilter="blur(4px)" />
+      <ModalContent borderRadius="xl" overflow="hidden" maxH="85vh">
+        <Box
+          bg="linear-gradient(135deg, #0a3a50 0%, #175a77 50%, #2d7d9b 100%)"
+          px={6}
+          py={4}
+        >
+          <HStack spacing={3}>
+            <Box bg="whiteAlpha.200" p={2} borderRadius="lg">
+              <Icon as={FiImage} boxSize={5} color="white" />
+            </Box>
+            <Box flex="1">
+              <Text color="white" fontWeight="600" fontSize="lg">
+                Style Graphics
+              </Text>
+              <Text color="whiteAlpha.800" fontSize="sm">
+                Icons in the {listing?.folder ?? location} and the styles drawing with them
+              </Text>
+            </Box>
+          </HStack>
+        </Box>
+        <ModalCloseButton color="white" />
+
+        <ModalBody py={6} overflowY="auto">
+          <VStack spacing={4} align="stretch">
+            <HStack spacing={3}>
+              <Checkbox
+                isChecked={global}
+                onChange={(e) => setGlobal(e.target.checked)}
+                colorScheme="kartoza"
+              >
+                <Text fontSize="sm">Global styles</Text>
+              </Checkbox>
+              <Input
+                size="sm"
+                maxW="160px"
+                value={folder}
+                onChange={(e) => setFolder(e.target.value)}
+                placeholder="Subfolder"
+              />
+              <Button
+                leftIcon={<FiUpload />}
+                onClick={() => fileInputRef.current?.click()}
+                isLoading={uploadMutation.isPending}
+                size="sm"
+              >
+                Upload PNG / SVG
+              </Button>
+              <input
+                ref={fileInputRef}
+                type="file"
+                multiple
+                accept={UPLOAD_EXTENSIONS.join(',')}
+                style={{ display: 'none' }}
+                onChange={handleFileSelect}
+              />
+            </HStack>
+
+            {isLoading && (
+              <HStack justify="center" py={8}>
+                <Spinner size="sm" />
+                <Text fontSize="sm" color="gray.500">Reading the styles folder...</Text>
+              </HStack>
+            )}
+
+            {error && (
+              <Text fontSize="sm" color="red.500">
+                {(error as Error).message}
+              </Text>
+            )}
+
+            {listing && !listing.complete && (
+              <Text fontSize="xs" color="orange.500">
+                The folder is too large to list completely; some graphics may be missing.
+              </Text>
+            )}
+
+            {listing && listing.assets.length === 0 && (
+              <Text fontSize="sm" color="gray.500" textAlign="center" py={8}>
+                No graphics in the {location} yet
+              </Text>
+            )}
+
+            {listing && listing.assets.length > 0 && (
+              <Box overflowX="auto">
+                <Table size="sm">
+                  <Thead bg={headerBg}>
+                    <Tr>
+                      <Th>Graphic</Th>
+                      <Th isNumeric>Size</Th>
+                      <Th>Used by</Th>
+                      <Th />
+                    </Tr>
+                  </Thead>
+                  <Tbody>
+                    {listing.assets.map((asset) => (
+                      <Tr key={asset.path}>
+                        <Td maxW="260px">
+                          {moving === asset.path ? (
+                            <Input
+                              size="xs"
+                              value={target}
+                              onChange={(e) => setTarget(e.target.value)}
+                              autoFocus
+                            />
+                          ) : (
+                            <Text fontSize="xs" noOfLines={1} title={asset.path}>
+                              {asset.path}
+                            </Text>
+                          )}
+                        </Td>
+                        <Td isNumeric>
+                          <Text fontSize="xs">{formatSize(asset.size)}</Text>
+                        </Td>
+                        <Td>
+                          {asset.styles.length > 0 ? (
+                            <HStack spacing={1} flexWrap="wrap">
+                              {asset.styles.map((style) => (
+                                <Badge key={style} fontSize="2xs" colorScheme="blue">
+                                  {style}
+                                </Badge>
+                              ))}
+                            </HStack>
+                          ) : (
+                            <Text fontSize="xs" color="gray.500">unused</Text>
+                          )}
+                        </Td>
+                        <Td>
+                          {moving === asset.path ? (
+                            <HStack spacing={1}>
+                              <IconButton
+                                aria-label="Move"
+                                icon={<FiCheck />}
+                                size="xs"
+                                colorScheme="kartoza"
+                                isLoading={moveMutation.isPending}
+                                isDisabled={!target.trim() || target.trim() === asset.path}
+                                onClick={() => moveMutation.mutate({ source: asset.path, target: target.trim() })}
+                              />
+                              <IconButton
+                                aria-label="Cancel"
+                                icon={<FiX />}
+                                size="xs"
+                                variant="ghost"
+                                onClick={() => setMoving(null)}
+                              />
+                            </HStack>
+                          ) : (
+                            <IconButton
+                              aria-label="Move or rename"
+                              title="Move or rename, updating the styles using it"
+                              icon={<FiEdit2 />}
+                              size="xs"
+                              variant="ghost"
+                              isDisabled={moving !== null}
+                              onClick={() => startMove(asset.path)}
+                            />
+                          )}
+                        </Td>
+                      </Tr>
+                    ))}
+                  </Tbody>
+                </Table>
+              </Box>
+            )}
+
+            {listing && listing.unresolved.length > 0 && (
+              <VStack align="stretch" spacing={1}>
+                <Text fontSize="sm" fontWeight="medium" color="orange.500">
+                  References that won't travel with the styles
+                </Text>
+                {listing.unresolved.map((ref) => (
+                  <Text key={`${ref.style}:${ref.href}`} fontSize="xs">
+                    {ref.style}: {ref.href}{' '}
+                    <Text as="span" color="gray.500">
+                      ({ref.reason === 'absolute' ? 'absolute path' : 'not in the folder'})
+                    </Text>
+                  </Text>
+                ))}
+              </VStack>
+            )}
+          </VStack>
+        </ModalBody>
+
+        <ModalFooter gap={3} borderTop="1px solid" borderTopColor="gray.100" bg="gray.50">
+          <Button variant="ghost" onClick={closeDialog} borderRadius="lg">
+            Close
+          </Button>
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+  )
+}
@@ -0,0 +1,316 @@
+import { useState, useRef, useEffect } from 'react'
+import {
+  Modal,
+  ModalOverlay,
+  ModalContent,
+  ModalFooter,
+  ModalBody,
+  ModalCloseButton,
+  Button,
+  Box,
+  Text,
+  VStack,
+  HStack,
+  Icon,
+  Badge,
+  Checkbox,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiFileText, FiFolder, FiPackage, FiUploadCloud } from 'react-icons/fi'
+import { useQueryClient } from '@tanstack/react-query'
+import { useUIStore } from '../../stores/uiStore'
+import * as api from '../../api'
+import StyleDiffView from '../StyleDiffView'
+
+// .zip files are style packages: an SLD with the icons and fonts it uses
+const STYLE_EXTENSIONS = ['.sld', '.css', '.zip']
+
+const STATUS_COLORS: Record<api.StyleImportResult['status'], string> = {
+  created: 'green',
+  updated: 'blue',
+  skipped: 'gray',
+  failed: 'red',
+}
+
+function isStyleFile(file: File): boolean {
+  const name = file.name.toLowerCase()
+  return !name.startsWith('.') && STYLE_EXTENSIONS.some((ext) => name.endsWith(ext))
+}
+
+export default function StyleImportDialog() {
+  const activeDialog = useUIStore((state) => state.activeDialog)
+  const dialogData = useUIStore((state) => state.dialogData)
+  const closeDialog = useUIStore((state) => state.closeDialog)
+  const queryClient = useQueryClient()
+
+  const [files, setFiles] = useState<File[]>([])
+  const [global, setGlobal] = useState(false)
+  const [isImporting, setIsImporting] = useState(false)
+  const [result, setResult] = useState<api.StyleImportResponse | null>(null)
+  const [error, setError] = useState<string | null>(null)
+  const [diffs, setDiffs] = useState<api.StyleDiff[] | null>(null)
+  const [isDiffing, setIsDiffing] = useState(false)
+  const folderInputRef = useRef<HTMLInputElement>(null)
+  const fileInputRef = useRef<HTMLInputElement>(null)
+
+  const headerBg = useColorModeValue('gray.50', 'gray.700')
+
+  const isOpen = activeDialog === 'styleimport'
+  const connectionId = (dialogData?.data?.connectionId as string) || ''
+  const workspace = (dialogData?.data?.workspace as string) || ''
+
+  useEffect(() => {
+    if (isOpen) {
+      setFiles([])
+      setGlobal(false)
+      setResult(null)
+      setError(null)
+      setDiffs(null)
+    }
+  }, [isOpen])
+
+  const handleFolderSelect = (e: React.ChangeEvent<HTMLInputElement>) => {
+    // Only the top of the folder is imported, like the command line default
+    const picked = Array.from(e.target.files || []).filter(
+      (file) => isStyleFile(file) && file.webkitRelativePath.split('/').length <= 2
+    )
+    setFiles(picked.sort((a, b) => a.name.localeCompare(b.name)))
+    setResult(null)
+    setError(null)
+    setDiffs(null)
+    e.target.value = ''
+  }
+
+  const handleFileSelect = (e: React.ChangeEvent<HTMLInputElement>) => {
+    const picked = Array.from(e.target.files || []).filter(isStyleFile)
+    setFiles(picked.sort((a, b) => a.name.localeCompare(b.name)))
+    setResult(null)
+    setError(null)
+    setDiffs(null)
+    e.target.value = ''
+  }
+
+  const handleReview = async () => {
+    setIsDiffing(true)
+    setError(null)
+    try {
+      setDiffs(await api.diffStyleFiles(connectionId, global ? '' : workspace, files))
+    } catch (err) {
+      setError((err as Error).message)
+    } finally {
+      setIsDiffing(false)
+    }
+  }
+
+  const handleImport = async () => {
+    setIsImporting(true)
+    setError(null)
+    try {
+      const response = await api.importStyles(connectionId, global ? '' : workspace, files)
+      setResult(response)
+      queryClient.invalidateQueries({ queryKey: ['styles', connectionId, workspace] })
+    } catch (err) {
+      setError((err as Error).message)
+    } finally {
+      setIsImporting(false)
+    }
+  }
+
+  const target = global ? 'global styles' : `workspace ${workspace}`
+  // Files are listed as picked until the server reports what it did with them
+  const rows: Array<Partial<api.StyleImportResult> & { path: string; name: string }> =
+    result?.results ??
+    files.map((file) => ({ path: file.name, name: file.name.replace(/\.[^.]+$/, '') }))
+
+  return (
+    <Modal isOpen={isOpen} onClose={closeDialog} size="3xl" isCentered>
+      <ModalOverlay bg="blackAlpha.600" backdropFilter="blur(4px)" />
+      <ModalContent borderRadius="xl" overflow="hidden" maxH="85vh">
+        <Box
+          bg="linear-gradient(135deg, #0a3a50 0%, #175a77 50%, #2d7d9b 100%)"
+          px={6}
+          py={4}
+        >
+          <HStack spacing={3}>
+            <Box bg="whiteAlpha.200" p={2} borderRadius="lg">
+              <Icon as={FiFolder} boxSize={5} color="white" />
+            </Box>
+            <Box flex="1">
+              <Text color="white" fontWeight="600" fontSize="lg">
+                Import Styles
+              </Text>
+              <Text color="whiteAlpha.800" fontSize="sm">
+                Each .sld, .css and .zip package becomes a style named after it; unchanged ones are skipped
+              </Text>
+            </Box>
+          </HStack>
+        </Box>
+        <ModalCloseButton color="white" isDisabled={isImporting} />
+
+        <ModalBody py={6} overflowY="auto">
+          <VStack spacing={4} align="stretch">
+            <HStack justify="space-between">
+              <HStack spacing={3}>
+                <Button
+                  leftIcon={<FiFolder />}
+                  onClick={() => folderInputRef.current?.click()}
+                  isDisabled={isImporting}
+                  size="sm"
+                >
+                  Choose Folder
+                </Button>
+                <Button
+                  leftIcon={<FiPackage />}
+                  onClick={() => fileInputRef.current?.click()}
+                  isDisabled={isImporting}
+                  size="sm"
+                >
+                  Choose Files
+                </Button>
+                <Checkbox
+                  isChecked={global}
+                  onChange={(e) => {
+                    setGlobal(e.target.checked)
+                    setDiffs(null)
+                  }}
+                  isDisabled={isImporting || result !== null}
+                  colorScheme="kartoza"
+                >
+                  <Text fontSize="sm">Import as global styles</Text>
+                </Checkbox>
+              </HStack>
+              {files.length > 0 && <Badge>{files.length} file(s)</Badge>}
+              <input
+                ref={folderInputRef}
+                type="file"
+                style={{ display: 'none' }}
+                onChange={handleFolderSelect}
+                // @ts-expect-error webkitdirectory isn't in React's input attributes
+                webkitdirectory=""
+              />
+              <input
+                ref={fileInputRef}
+                type="file"
+                multiple
+                accept={STYLE_EXTENSIONS.join(',')}
+                style={{ display: 'none' }}
+                onChange={handleFileSelect}
+              />
+            </HStack>
+
+            {files.length === 0 && (
+              <Text fontSize="sm" color="gray.500" textAlign="center" py={8}>
+                Choose a folder or files of SLD or CSS styles, or zipped SLD packages with their
+                icons, to import into the {target}
+              </Text>
+            )}
+
+            {error && (
+              <Text fontSize="sm" color="red.500">
+                {error}
+              </Text>
+            )}
+
+            {result && (
+              <HStack spacing={2}>
+                {(Object.keys(STATUS_COLORS) as api.StyleImportResult['status'][]).map((status) => (
+                  <Badge key={status} colorScheme={STATUS_COLORS[status]}>
+                    {result.counts[status]} {status}
+                  </Badge>
+                ))}
+              </HStack>
+            )}
+
+            {diffs && !result && <StyleDiffView diffs={diffs} />}
+
+            {files.length > 0 && (
+              <Box overflowX="auto">
+                <Table size="sm">
+                  <Thead bg={headerBg}>
+                    <Tr>
+                      <Th>File</Th>
+                      <Th>Style</Th>
+                      <Th>Result</Th>
+                    </Tr>
+                  </Thead>
+                  <Tbody>
+                    {rows.map((row) => (
+                      <Tr key={row.path}>
+                        <Td maxW="260px">
+                          <Text fontSize="xs" noOfLines={1} title={row.path}>
+                            {row.path}
+                          </Text>
+                        </Td>
+                        <Td>
+                          <Text fontSize="xs">{row.name}</Text>
+                        </Td>
+                        <Td>
+                          {row.status ? (
+                            <HStack spacing={2}>
+                              <Badge fontSize="2xs" colorScheme={STATUS_COLORS[row.status]}>
+                                {row.status}
+                              </Badge>
+                              {row.error && (
+                                <Text fontSize="xs" color="red.500" noOfLines={2}>
+                                  {row.error}
+                                </Text>
+                              )}
+                              {row.warning && (
+                                <Text fontSize="xs" color="orange.500" noOfLines={2}>
+                                  {row.warning}
+                                </Text>
+                              )}
+                            </HStack>
+                          ) : (
+                            <Text fontSize="xs" color="gray.500">
+                              pending
+                            </Text>
+                          )}
+                        </Td>
+                      </Tr>
+                    ))}
+                  </Tbody>
+                </Table>
+              </Box>
+            )}
+          </VStack>
+        </ModalBody>
+
+        <ModalFooter gap={3} borderTop="1px solid" borderTopColor="gray.100" bg="gray.50">
+          <Button variant="ghost" onClick={closeDialog} borderRadius="lg" isDisabled={isImporting}>
+            {result ? 'Close' : 'Cancel'}
+          </Button>
+          <Button
+            variant="outline"
+            onClick={handleReview}
+            isLoading={isDiffing}
+            loadingText="Comparing..."
+            isDisabled={!connectionId || files.length === 0 || isImporting || result !== null}
+            leftIcon={<FiFileText />}
+            borderRadius="lg"
+          >
+            Review Changes
+          </Button>
+          <Button
+            colorScheme="kartoza"
+            onClick={handleImport}
+            isLoading={isImporting}
+            loadingText="Importing..."
+            isDisabled={!connectionId || files.length === 0 || result !== null}
+            leftIcon={<FiUploadCloud />}
+            borderRadius="lg"
+            px={6}
+          >
+            Import
+          </Button>
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+  )
+}
@@ -27,6 +27,7 @@ import {
   SimpleGrid,
   Spinner,
   Collapse,
+  ButtonGroup,
   useDisclosure,
 } from '@chakra-ui/react'
 import { keyframes, css } from '@emotion/react'
@@ -56,11 +57,21 @@ import {
   FiActivity,
   FiDownload,
   FiX,
+  FiUpload,
+  FiSliders,
 } from 'react-icons/fi'
 import * as api from '../../api'
-import type { Connection, SyncConfiguration, SyncTask, SyncOptions, StartSyncRequest } from '../../types'
+import type {
+  Connection,
+  SyncConfiguration,
+  SyncTask,
+  SyncOptions,
+  StartSyncRequest,
+  SettingsSection,
+} from '../../types'
 import { useUIStore } from '../../stores/uiStore'
 import { useConnectionStore } from '../../stores/connectionStore'
+import StyleDiffView from '../StyleDiffView'
 
 // Keyframe animation definitions using Chakra-compatible format
 const pulseOutKeyframes = keyframes`
@@ -444,11 +455,455 @@ function SyncOptionsPanel({ options, onChange }: SyncOptionsProps) {
             </HStack>
           </Checkbox>
         </SimpleGrid>
+
+        {/* Only resources this client created, or adopted, are replaced or deleted */}
+        <VStack
+          align="stretch"
+          spacing={2}
+          mt={3}
+          pt={3}
+          borderTop="1px solid"
+          borderColor="gray.200"
+        >
+          <Checkbox
+            isChecked={!!options.prune}
+            onChange={() => handleToggle('prune')}
+            colorScheme="red"
+          >
+            <Text fontSize="sm">Prune managed resources missing from the source</Text>
+          </Checkbox>
+          <Checkbox
+            isChecked={!!options.adopt}
+            onChange={() => handleToggle('adopt')}
+            colorScheme="kartoza"
+          >
+            <Text fontSize="sm">Adopt and update existing resources created by others</Text>
+          </Checkbox>
+        </VStack>
       </Collapse>
     </Box>
   )
 }
 
+interface StyleChangesPanelProps {
+  sourceId: string
+  destinations: Connection[]
+}
+
+// Diffs of the global styles a sync would copy over each destination's, read when opened
+function StyleChangesPanel({ sourceId, destinations }: StyleChangesPanelProps) {
+  const { isOpen, onToggle } = useDisclosure({ defaultIsOpen: false })
+  const [destId, setDestId] = useState(destinations[0]?.id ?? '')
+
+  useEffect(() => {
+    if (!destinations.some(d => d.id === destId)) {
+      setDestId(destinations[0]?.id ?? '')
+    }
+  }, [destinations, destId])
+
+  const { data: diffs, isFetching, error, refetch } = useQuery({
+    queryKey: ['styleDiff', sourceId, destId],
+    queryFn: () => api.diffConnectionStyles(sourceId, destId),
+    enabled: isOpen && !!destId,
+    staleTime: 30000,
+  })
+
+  const changed = diffs?.filter(d => d.status !== 'unchanged').length
+
+  return (
+    <Box border="1px solid" borderColor="gray.200" borderRadius="md" overflow="hidden">
+      <Flex
+        justify="space-between"
+        align="center"
+        p={2}
+        bg="gray.50"
+        cursor="pointer"
+        onClick={onToggle}
+      >
+        <HStack>
+          <Icon as={FiEdit3} color="pink.500" />
+          <Text fontWeight="bold" fontSize="sm">Style Changes</Text>
+          {changed !== undefined && (
+            <Badge colorScheme={changed ? 'orange' : 'green'} fontSize="xs">
+              {changed} of {diffs?.length} would change
+            </Badge>
+          )}
+        </HStack>
+        <Icon as={isOpen ? FiChevronUp : FiChevronDown} />
+      </Flex>
+
+      <Collapse in={isOpen}>
+        <VStack align="stretch" spacing={3} p={3}>
+          <HStack>
+            <Text fontSize="sm" color="gray.600">Compare with</Text>
+            <Select
+              size="sm"
+              maxW="250px"
+              value={destId}
+              onChange={(e) => setDestId(e.target.value)}
+            >
+              {destinations.map(conn => (
+                <option key={conn.id} value={conn.id}>{conn.name}</option>
+              ))}
+            </Select>
+            <Tooltip label="Compare again" fontSize="xs">
+              <IconButton
+                aria-label="Compare again"
+                icon={<FiRefreshCw size={14} />}
+                size="sm"
+                variant="ghost"
+                onClick={() => refetch()}
+                isLoading={isFetching}
+              />
+            </Tooltip>
+          </HStack>
+          {error && (
+            <Text fontSize="sm" color="red.500">{(error as Error).message}</Text>
+          )}
+          {isFetching && !diffs ? (
+            <HStack justify="center" py={4}>
+              <Spinner size="sm" />
+              <Text fontSize="sm" color="gray.500">Comparing styles...</Text>
+            </HStack>
+          ) : (
+            diffs && <StyleDiffView diffs={diffs} />
+          )}
+        </VStack>
+      </Collapse>
+    </Box>
+  )
+}
+
+interface PromoteStylesPanelProps {
+  sourceId: string
+  destinations: Connection[]
+}
+
+// Styles whose content hash differs from a destination's, to push chosen ones without
+// touching stores or layers
+function PromoteStylesPanel({ sourceId, destinations }: PromoteStylesPanelProps) {
+  const toast = useToast()
+  const [destId, setDestId] = useState(destinations[0]?.id ?? '')
+  const [workspace, setWorkspace] = useState('')
+  const [selected, setSelected] = useState<string[]>([])
+
+  useEffect(() => {
+    if (!destinations.some(d => d.id === destId)) {
+      setDestId(destinations[0]?.id ?? '')
+    }
+  }, [destinations, destId])
+
+  const { data: workspaces = [] } = useQuery({
+    queryKey: ['workspaces', sourceId],
+    queryFn: () => api.getWorkspaces(sourceId),
+  })
+
+  const { data: diffs, isFetching, error, refetch } = useQuery({
+    queryKey: ['styleDiff', sourceId, destId, workspace],
+    queryFn: () => api.diffConnectionStyles(sourceId, destId, workspace),
+    enabled: !!destId,
+    staleTime: 30000,
+  })
+
+  const changed = (diffs ?? []).filter(d => d.status !== 'unchanged')
+
+  // A new comparison starts with every changed or new style chosen
+  useEffect(() => {
+    setSelected((diffs ?? []).filter(d => d.status !== 'unchanged').map(d => d.name))
+  }, [diffs])
+
+  const promoteMutation = useMutation({
+    mutationFn: () =>
+      api.promoteStyles({ sourceId, destinationIds: [destId], workspace, styles: selected }),
+    onSuccess: (response) => {
+      const counts = response.results[destId]?.styles
+      const failed = counts?.errors.length ?? 0
+      toast({
+        title: failed ? `${failed} style(s) failed to promote` : 'Styles promoted',
+        description: counts
+          ? `${counts.created} created, ${counts.updated} updated, ${counts.skipped} unchanged` +
+            (failed ? `: ${counts.errors.map(e => `${e.style} (${e.error})`).join(', ')}` : '')
+          : undefined,
+        status: failed ? 'warning' : 'success',
+        duration: 5000,
+      })
+      refetch()
+    },
+    onError: (err: Error) => {
+      toast({
+        title: 'Failed to promote styles',
+        description: err.message,
+        status: 'error',
+        duration: 5000,
+      })
+    },
+  })
+
+  const toggle = (name: string) =>
+    setSelected(prev => (prev.includes(name) ? prev.filter(n => n !== name) : [...prev, name]))
+
+  return (
+    <Box border="1px solid" borderColor="gray.200" borderRadius="md" p={3}>
+      <VStack align="stretch" spacing={3}>
+        <HStack>
+          <Icon as={FiEdit3} color="pink.500" />
+          <Text fontWeight="bold" fontSize="sm">Promote Styles</Text>
+          {diffs && (
+            <Badge colorScheme={changed.length ? 'orange' : 'green'} fontSize="xs">
+              {changed.length} of {diffs.length} differ
+            </Badge>
+          )}
+        </HStack>
+        <HStack>
+          <Text fontSize="sm" color="gray.600">To</Text>
+          <Select
+            size="sm"
+            maxW="250px"
+            value={destId}
+            onChange={(e) => setDestId(e.target.value)}
+          >
+            {destinations.map(conn => (
+              <option key={conn.id} value={conn.id}>{conn.name}</option>
+            ))}
+          </Select>
+          <Select
+            size="sm"
+            maxW="200px"
+            value={workspace}
+            onChange={(e) => setWorkspace(e.target.value)}
+          >
+            <option value="">Global styles</option>
+            {workspaces.map(ws => (
+              <option key={ws.name} value={ws.name}>{ws.name}</option>
+            ))}
+          </Select>
+          <Tooltip label="Compare again" fontSize="xs">
+            <IconButton
+              aria-label="Compare again"
+              icon={<FiRefreshCw size={14} />}
+              size="sm"
+              variant="ghost"
+              onClick={() => refetch()}
+              isLoading={isFetching}
+            />
+          </Tooltip>
+        </HStack>
+        {error && (
+          <Text fontSize="sm" color="red.500">{(error as Error).message}</Text>
+        )}
+        {isFetching && !diffs ? (
+          <HStack justify="center" py={4}>
+            <Spinner size="sm" />
+            <Text fontSize="sm" color="gray.500">Comparing styles...</Text>
+          </HStack>
+        ) : (
+          diffs && (
+            <>
+              {changed.length > 0 && (
+                <SimpleGrid columns={{ base: 1, md: 2, lg: 3 }} spacing={2}>
+                  {changed.map(diff => (
+                    <Checkbox
+                      key={diff.name}
+                      isChecked={selected.includes(diff.name)}
+                      onChange={() => toggle(diff.name)}
+                      colorScheme="kartoza"
+                    >
+                      <HStack spacing={1}>
+                        <Text fontSize="sm">{diff.name}</Text>
+                        <Badge
+                          colorScheme={diff.status === 'new' ? 'green' : 'orange'}
+                          fontSize="xs"
+                        >
+                          {diff.status}
+                        </Badge>
+                      </HStack>
+                    </Checkbox>
+                  ))}
+                </SimpleGrid>
+              )}
+              <StyleDiffView diffs={changed.filter(d => selected.includes(d.name))} />
+              <HStack justify="flex-end">
+                <Button
+                  leftIcon={<FiUpload />}
+                  colorScheme="kartoza"
+                  size="sm"
+                  onClick={() => promoteMutation.mutate()}
+                  isDisabled={selected.length === 0}
+                  isLoading={promoteMutation.isPending}
+                >
+                  Promote {selected.length} Style{selected.length === 1 ? '' : 's'}
+                </Button>
+              </HStack>
+            </>
+          )
+        )}
+      </VStack>
+    </Box>
+  )
+}
+
+const SETTINGS_SECTIONS: { value: SettingsSection; label: string }[] = [
+  { value: 'contact', label: 'Contact' },
+  { value: 'wms', label: 'WMS' },
+  { value: 'wfs', label: 'WFS' },
+  { value: 'wcs', label: 'WCS' },
+  { value: 'wmts', label: 'WMTS' },
+]
+
+interface ServerSettingsPanelProps {
+  sourceId: string
+  destinations: Connection[]
+}
+
+// Contact details and service titles, abstracts and keywords that differ from the source's on
+// each destination, to apply to all of them at once
+function ServerSettingsPanel({ sourceId, destinations }: ServerSettingsPanelProps) {
+  const toast = useToast()
+  const [sections, setSections] = useState<SettingsSection[]>(
+    SETTINGS_SECTIONS.map(s => s.value)
+  )
+  const destinationIds = destinations.map(d => d.id)
+
+  const { data: plan, isFetching, error, refetch } = useQuery({
+    queryKey: ['settingsSync', sourceId, destinationIds, sections],
+    queryFn: () =>
+      api.syncSettings({ sourceId, destinationIds, sections, dryRun: true }),
+    enabled: destinationIds.length > 0 && sections.length > 0,
+    staleTime: 30000,
+  })
+
+  const applyMutation = useMutation({
+    mutationFn: () => api.syncSettings({ sourceId, destinationIds, sections }),
+    onSuccess: (response) => {
+      const failed = response.servers.filter(s => Object.keys(s.errors).length > 0)
+      toast({
+        title: failed.length ? `Settings failed on ${failed.length} server(s)` : 'Settings applied',
+        description: failed.length
+          ? failed.map(s => `${s.name}: ${Object.keys(s.errors).join(', ')}`).join('; ')
+          : `${response.changeCount} field(s) changed`,
+        status: failed.length ? 'warning' : 'success',
+        duration: 5000,
+      })
+      refetch()
+    },
+    onError: (err: Error) => {
+      toast({
+        title: 'Failed to apply settings',
+        description: err.message,
+        status: 'error',
+        duration: 5000,
+      })
+    },
+  })
+
+  const toggle = (section: SettingsSection) =>
+    setSections(prev =>
+      prev.includes(section) ? prev.filter(s => s !== section) : [...prev, section]
+    )
+
+  return (
+    <Box border="1px solid" borderColor="gray.200" borderRadius="md" p={3}>
+      <VStack align="stretch" spacing={3}>
+        <HStack>
+          <Icon as={FiSliders} color="purple.500" />
+          <Text fontWeight="bold" fontSize="sm">Server Settings</Text>
+          {plan && (
+            <Badge colorScheme={plan.changeCount ? 'orange' : 'green'} fontSize="xs">
+              {plan.changeCount} field(s) differ
+            </Badge>
+          )}
+          <Box flex={1} />
+          <Tooltip label="Compare again" fontSize="xs">
+            <IconButton
+              aria-label="Compare again"
+              icon={<FiRefreshCw size={14} />}
+              size="sm"
+              variant="ghost"
+              onClick={() => refetch()}
+              isLoading={isFetching}
+            />
+          </Tooltip>
+        </HStack>
+        <HStack spacing={4}>
+          {SETTINGS_SECTIONS.map(section => (
+            <Checkbox
+              key={section.value}
+              size="sm"
+              isChecked={sections.includes(section.value)}
+              onChange={() => toggle(section.value)}
+              colorScheme="kartoza"
+            >
+              {section.label}
+            </Checkbox>
+          ))}
+        </HStack>
+        {error && (
+          <Text fontSize="sm" color="red.500">{(error as Error).message}</Text>
+        )}
+        {plan && Object.entries(plan.sourceErrors).map(([section, message]) => (
+          <Text key={section} fontSize="xs" color="orange.500">
+            Source {section} not read: {message}
+          </Text>
+        ))}
+        {isFetching && !plan ? (
+          <HStack justify="center" py={4}>
+            <Spinner size="sm" />
+            <Text fontSize="sm" color="gray.500">Comparing settings...</Text>
+          </HStack>
+        ) : (
+          plan && (
+            <>
+              {plan.servers.map(server => (
+                <Box key={server.connectionId}>
+                  <HStack mb={1}>
+                    <Text fontSize="sm" fontWeight="500">{server.name}</Text>
+                    <Badge colorScheme={server.changes.length ? 'orange' : 'green'} fontSize="xs">
+                      {server.changes.length ? `${server.changes.length} differ` : 'same'}
+                    </Badge>
+                  </HStack>
+                  {server.changes.map(change => (
+                    <HStack
+                      key={`${change.section}.${change.key}`}
+                      fontSize="xs"
+                      align="start"
+                      spacing={2}
+                    >
+                      <Badge fontSize="xs">{change.section}</Badge>
+                      <Text fontWeight="500" minW="140px">{change.key}</Text>
+                      <Text color="red.500" textDecoration="line-through" noOfLines={2}>
+                        {change.current || '(empty)'}
+                      </Text>
+                      <Icon as={FiArrowRight} mt="2px" />
+                      <Text color="green.600" noOfLines={2}>{change.value}</Text>
+                    </HStack>
+                  ))}
+                  {Object.entries(server.errors).map(([section, message]) => (
+                    <Text key={section} fontSize="xs" color="red.500">
+                      {section}: {message}
+                    </Text>
+                  ))}
+                </Box>
+              ))}
+              <HStack justify="flex-end">
+                <Button
+                  leftIcon={<FiUpload />}
+                  colorScheme="kartoza"
+                  size="sm"
+                  onClick={() => applyMutation.mutate()}
+                  isDisabled={plan.changeCount === 0}
+                  isLoading={applyMutation.isPending}
+                >
+                  Apply to {destinations.length} Server{destinations.length === 1 ? '' : 's'}
+                </Button>
+              </HStack>
+            </>
+          )
+        )}
+      </VStack>
+    </Box>
+  )
+}
+
 interface SyncLogPanelProps {
   tasks: SyncTask[]
 }
@@ -558,6 +1013,8 @@ export function SyncDialog() {
   const [hoveredSource, setHoveredSource] = useState(false)
   const [configName, setConfigName] = useState('')
   const [selectedConfigId, setSelectedConfigId] = useState<string | null>(null)
+  // A full sync, or only pushing chosen styles
+  const [mode, setMode] = useState<'full' | 'styles' | 'settings'>('full')
 
   // Queries
   const { data: syncConfigs = [], refetch: refetchConfigs } = useQuery({
@@ -944,8 +1401,67 @@ export function SyncDialog() {
 
             <Divider />
 
-            {/* Sync Options */}
-            <SyncOptionsPanel options={options} onChange={setOptions} />
+            <ButtonGroup size="sm" isAttached variant="outline" alignSelf="center">
+              <Button
+                leftIcon={<FiRefreshCw />}
+                isActive={mode === 'full'}
+                onClick={() => setMode('full')}
+              >
+                Full Sync
+              </Button>
+              <Button
+                leftIcon={<FiEdit3 />}
+                isActive={mode === 'styles'}
+                onClick={() => setMode('styles')}
+              >
+                Promote Styles
+              </Button>
+              <Button
+                leftIcon={<FiSliders />}
+                isActive={mode === 'settings'}
+                onClick={() => setMode('settings')}
+              >
+                Server Settings
+              </Button>
+            </ButtonGroup>
+
+            {mode === 'full' ? (
+              <>
+                {/* Sync Options */}
+                <SyncOptionsPanel options={options} onChange={setOptions} />
+
+                {/* What the sync would change in the destinations' styles */}
+                {options.styles && sourceId && destinationIds.length > 0 && (
+                  <StyleChangesPanel
+                    sourceId={sourceId}
+                    destinations={destinationIds
+                      .map(id => getConnection(id))
+                      .filter((c): c is Connection => !!c)}
+                  />
+                )}
+              </>
+            ) : sourceId && destinationIds.length > 0 ? (
+              mode === 'styles' ? (
+                <PromoteStylesPanel
+                  sourceId={sourceId}
+                  destinations={destinationIds
+                    .map(id => getConnection(id))
+                    .filter((c): c is Connection => !!c)}
+                />
+              ) : (
+                <ServerSettingsPanel
+                  sourceId={sourceId}
+                  destinations={destinationIds
+                    .map(id => getConnection(id))
+                    .filter((c): c is Connection => !!c)}
+                />
+              )
+            ) : (
+              <Text fontSize="sm" color="gray.500" textAlign="center">
+                Choose a source and a destination to compare their{' '}
+                {mode === 'styles' ? 'styles' : 'settings'}
+              </Text>
+            )}
 
             {/* Activity Log */}
             <SyncLogPanel tasks={runningTasks} />
@@ -1001,22 +1517,24 @@ export function SyncDialog() {
               <Button variant="ghost" onClick={closeDialog}>
                 Close
               </Button>
-              <Button
-                leftIcon={isAnyRunning ? <Spinner size="sm" /> : <FiPlay />}
-                colorScheme="kartoza"
-                size="lg"
-                onClick={handleStartSync}
-                isDisabled={!sourceId || destinationIds.length === 0 || isAnyRunning}
-                isLoading={startSyncMutation.isPending}
-                px={8}
-                _hover={{
-                  transform: 'scale(1.02)',
-                  boxShadow: 'lg',
-                }}
-                transition="all 0.2s ease"
-              >
-                {isAnyRunning ? 'Syncing...' : 'Start Sync'}
-              </Button>
+              {mode === 'full' && (
+                <Button
+                  leftIcon={isAnyRunning ? <Spinner size="sm" /> : <FiPlay />}
+                  colorScheme="kartoza"
+                  size="lg"
+                  onClick={handleStartSync}
+                  isDisabled={!sourceId || destinationIds.length === 0 || isAnyRunning}
+                  isLoading={startSyncMutation.isPending}
+                  px={8}
+                  _hover={{
+                    transform: 'scale(1.02)',
+                    boxShadow: 'lg',
+                  }}
+                  transition="all 0.2s ease"
+                >
+                  {isAnyRunning ? 'Syncing...' : 'Start Sync'}
+                </Button>
+              )}
             </HStack>
           </HStack>
         </ModalFooter>
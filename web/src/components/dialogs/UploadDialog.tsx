@@ -22,17 +22,34 @@ import {
   Checkbox,
   Divider,
   Spinner,
+  Tooltip,
+  Input,
+  Switch,
+  FormControl,
+  FormLabel,
+  SimpleGrid,
+  NumberInput,
+  NumberInputField,
 } from '@chakra-ui/react'
-import { FiFile, FiCheck, FiX, FiUploadCloud, FiLayers, FiDatabase, FiPause, FiPlay } from 'react-icons/fi'
-import { useQueryClient } from '@tanstack/react-query'
+import { FiFile, FiCheck, FiX, FiUploadCloud, FiLayers, FiDatabase, FiPause, FiPlay, FiFolder } from 'react-icons/fi'
+import { useQuery, useQueryClient } from '@tanstack/react-query'
 import { useUIStore } from '../../stores/uiStore'
 import { useTreeStore } from '../../stores/treeStore'
 import { useConnectionStore } from '../../stores/connectionStore'
 import * as api from '../../api'
 import { useChunkedUpload } from '../../hooks/useChunkedUpload'
+import type {
+  CogAdvice,
+  LayerMetadata,
+  LayerNamingRules,
+  UploadVerification,
+} from '../../types'
+import { FilePreviewPane, LayerMetadataFields, UploadTargetPicker } from '../common'
 
 interface FileUpload {
   file: File
+  targetStore: string // Store chosen before upload; empty names it after the file
+  metadata?: LayerMetadata // Title, abstract and keywords of the layer a new store publishes
   progress: number
   chunkProgress: number
   status: 'pending' | 'uploading' | 'paused' | 'success' | 'error' | 'cancelled'
@@ -45,6 +62,11 @@ interface FileUpload {
   error?: string
   storeName?: string
   storeType?: string
+  importedLayers?: string[]
+  failedTasks?: number
+  convertedToCog?: boolean
+  verification?: UploadVerification
+  metadataWarning?: string
 }
 
 interface AvailableLayer {
@@ -59,6 +81,19 @@ function formatSpeed(bps: number): string {
   return `${(bps / (1024 * 1024)).toFixed(1)} MB/s`
 }
 
+function formatSize(bytes: number): string {
+  if (bytes < 1024 * 1024) return `${(bytes / 1024).toFixed(0)} KB`
+  if (bytes < 1024 * 1024 * 1024) return `${(bytes / (1024 * 1024)).toFixed(1)} MB`
+  return `${(bytes / (1024 * 1024 * 1024)).toFixed(2)} GB`
+}
+
+// A GeoTIFF waiting on the choice to convert it to a COG
+interface CogQuestion {
+  fileName: string
+  advice: CogAdvice
+  resolve: (convert: boolean) => void
+}
+
 function formatEta(seconds: number): string {
   if (!isFinite(seconds) || seconds <= 0) return ''
   if (seconds < 60) return `${Math.ceil(seconds)}s`
@@ -70,6 +105,7 @@ function formatEta(seconds: number): string {
 export default function UploadDialog() {
   const activeDialog = useUIStore((state) => state.activeDialog)
   const closeDialog = useUIStore((state) => state.closeDialog)
+  const openDialog = useUIStore((state) => state.openDialog)
   const selectedNode = useTreeStore((state) => state.selectedNode)
   const activeConnectionId = useConnectionStore((state) => state.activeConnectionId)
   const queryClient = useQueryClient()
@@ -82,7 +118,13 @@ export default function UploadDialog() {
   const [loadingLayers, setLoadingLayers] = useState(false)
   const [publishingLayers, setPublishingLayers] = useState(false)
   const [currentStore, setCurrentStore] = useState<{ name: string; type: string } | null>(null)
+  const [naming, setNaming] = useState<LayerNamingRules>({ prefix: '', suffix: '', sanitize: true })
+  const [cogQuestion, setCogQuestion] = useState<CogQuestion | null>(null)
+  const [alwaysConvert, setAlwaysConvert] = useState(false)
+  const [convertOverMb, setConvertOverMb] = useState(100)
   const [currentFileIndex, setCurrentFileIndex] = useState<number>(-1)
+  const [previewing, setPreviewing] = useState<File | null>(null)
+  const [verifyChecksums, setVerifyChecksums] = useState(false)
   const fileInputRef = useRef<HTMLInputElement>(null)
 
   const dropzoneBg = useColorModeValue('gray.50', 'gray.700')
@@ -175,6 +217,7 @@ export default function UploadDialog() {
       ...prev,
       ...validFiles.map((file) => ({
         file,
+        targetStore: '',
         progress: 0,
         chunkProgress: 0,
         status: 'pending' as const,
@@ -231,7 +274,12 @@ export default function UploadDialog() {
       )
 
       try {
-        const result = await chunkedUpload.start(connectionId, workspace, files[i].file)
+        const result = await chunkedUpload.start(connectionId, workspace, files[i].file, {
+          storeName: files[i].targetStore || undefined,
+          onCogAdvice: askCog,
+          verify: verifyChecksums,
+          metadata: files[i].metadata,
+        })
 
         setFiles((prev) =>
           prev.map((f, idx) =>
@@ -243,13 +291,19 @@ export default function UploadDialog() {
                   chunkProgress: 100,
                   storeName: result.storeName,
                   storeType: result.storeType,
+                  importedLayers: result.importedLayers,
+                  failedTasks: result.failedTasks?.length,
+                  convertedToCog: result.convertedToCog,
+                  verification: result.verification,
+                  metadataWarning: result.metadataWarning,
                 }
               : f
           )
         )
 
         if (files[i].file.name.toLowerCase().endsWith('.gpkg') && result.storeName) {
-          lastGpkgStore = { name: result.storeName, type: result.storeType || 'datastore' }
+          // A GeoPackage is uploaded as a data store with its tables unpublished
+          lastGpkgStore = { name: result.storeName, type: 'datastore' }
         }
       } catch (err) {
         const errMsg = (err as Error).message
@@ -294,6 +348,33 @@ export default function UploadDialog() {
     }
   }
 
+  const setTargetStore = (index: number, store: string) => {
+    setFiles((prev) => prev.map((f, i) => (i === index ? { ...f, targetStore: store } : f)))
+  }
+
+  const setMetadata = (index: number, metadata: LayerMetadata) => {
+    setFiles((prev) => prev.map((f, i) => (i === index ? { ...f, metadata } : f)))
+  }
+
+  const askCog = (file: File, advice: CogAdvice) =>
+    new Promise<boolean>((resolve) => {
+      setAlwaysConvert(false)
+      setCogQuestion({ fileName: file.name, advice, resolve })
+    })
+
+  const answerCog = async (convert: boolean) => {
+    if (!cogQuestion) return
+    if (convert && alwaysConvert) {
+      try {
+        await api.updateAppSettings({ cogAutoConvertMb: convertOverMb })
+      } catch (err) {
+        console.error('Failed to save the COG conversion threshold:', err)
+      }
+    }
+    cogQuestion.resolve(convert)
+    setCogQuestion(null)
+  }
+
   const loadAvailableLayers = async (storeName: string) => {
     if (!connectionId || !workspace) return
 
@@ -346,7 +427,13 @@ export default function UploadDialog() {
 
     setPublishingLayers(true)
     try {
-      const result = await api.publishFeatureTypes(connectionId, workspace, currentStore.name, selectedLayers)
+      const result = await api.publishFeatureTypes(
+        connectionId,
+        workspace,
+        currentStore.name,
+        selectedLayers,
+        naming
+      )
 
       if (result.published.length > 0) {
         toast({
@@ -356,8 +443,10 @@ export default function UploadDialog() {
           duration: 3000,
         })
 
+        // Published layers are named by the rules, not after their tables
+        const published = new Set(result.published)
         setAvailableLayers((prev) =>
-          prev.filter((l) => !result.published.includes(l.name))
+          prev.filter((l) => !published.has(layerNames?.[l.name] ?? l.name))
         )
 
         queryClient.invalidateQueries({ queryKey: ['layers', connectionId, workspace] })
@@ -384,10 +473,13 @@ export default function UploadDialog() {
   }
 
   const handleClose = () => {
+    cogQuestion?.resolve(false)
+    setCogQuestion(null)
     setFiles([])
     setUploadComplete(false)
     setAvailableLayers([])
     setCurrentStore(null)
+    setNaming({ prefix: '', suffix: '', sanitize: true })
     setCurrentFileIndex(-1)
     chunkedUpload.reset()
     closeDialog()
@@ -422,6 +514,21 @@ export default function UploadDialog() {
   const hasPendingUploads = files.some((f) => f.status === 'pending')
   const selectedLayerCount = availableLayers.filter((l) => l.selected).length
 
+  // Layer names the naming rules give, resolved against the workspace
+  const { data: layerNames } = useQuery({
+    queryKey: ['layer-names', connectionId, workspace, currentStore?.name, availableLayers, naming],
+    queryFn: () =>
+      api.previewLayerNames(
+        connectionId!,
+        workspace!,
+        currentStore!.name,
+        availableLayers.filter((l) => l.selected).map((l) => l.name),
+        naming
+      ),
+    enabled: !!connectionId && !!workspace && !!currentStore && selectedLayerCount > 0,
+    placeholderData: (previous) => previous,
+  })
+
   const uploadingFiles = files.filter((f) => f.status === 'uploading' || f.status === 'paused')
   const totalFiles = files.length
   const completedFiles = files.filter((f) => f.status === 'success' || f.status === 'error' || f.status === 'cancelled').length
@@ -513,6 +620,23 @@ export default function UploadDialog() {
               </Box>
             )}
 
+            {/* Integrity check of what GeoServer stored */}
+            {files.length > 0 && !uploadComplete && (
+              <FormControl display="flex" alignItems="center">
+                <Switch
+                  size="sm"
+                  colorScheme="kartoza"
+                  isChecked={verifyChecksums}
+                  onChange={(e) => setVerifyChecksums(e.target.checked)}
+                  isDisabled={isUploading}
+                  mr={2}
+                />
+                <FormLabel fontSize="sm" mb={0}>
+                  Verify checksums after upload
+                </FormLabel>
+              </FormControl>
+            )}
+
             {/* Overall progress when uploading multiple files */}
             {isUploading && totalFiles > 1 && (
               <Box w="100%" p={3} bg={dropzoneBg} borderRadius="lg" border="1px solid" borderColor="gray.200">
@@ -563,6 +687,18 @@ export default function UploadDialog() {
                         <Text flex="1" fontSize="sm" noOfLines={1} fontWeight="500">
                           {upload.file.name}
                         </Text>
+                        {upload.status === 'pending' && (
+                          <Button
+                            size="xs"
+                            variant={previewing === upload.file ? 'solid' : 'ghost'}
+                            onClick={() =>
+                              setPreviewing(previewing === upload.file ? null : upload.file)
+                            }
+                            borderRadius="md"
+                          >
+                            Preview
+                          </Button>
+                        )}
                         {upload.status === 'pending' && (
                           <Button
                             size="xs"
@@ -577,6 +713,53 @@ export default function UploadDialog() {
                         {upload.status === 'success' && (
                           <Badge colorScheme="green" borderRadius="md">Complete</Badge>
                         )}
+                        {upload.status === 'success' && upload.importedLayers && (
+                          <Tooltip label={upload.importedLayers.join(', ')}>
+                            <Badge colorScheme="blue" borderRadius="md">
+                              {upload.importedLayers.length} layer(s) imported
+                            </Badge>
+                          </Tooltip>
+                        )}
+                        {upload.status === 'success' && upload.convertedToCog && (
+                          <Badge colorScheme="purple" borderRadius="md">COG</Badge>
+                        )}
+                        {upload.status === 'success' && upload.verification && (
+                          <Tooltip
+                            label={
+                              upload.verification.error ||
+                              upload.verification.files
+                                .map((f) => `${f.name}: ${f.status}`)
+                                .join(', ')
+                            }
+                          >
+                            <Badge
+                              colorScheme={
+                                upload.verification.verified
+                                  ? 'green'
+                                  : upload.verification.error
+                                  ? 'gray'
+                                  : 'red'
+                              }
+                              borderRadius="md"
+                            >
+                              {upload.verification.verified
+                                ? 'Verified'
+                                : upload.verification.error
+                                ? 'Not verified'
+                                : 'Checksum mismatch'}
+                            </Badge>
+                          </Tooltip>
+                        )}
+                        {upload.status === 'success' && upload.metadataWarning && (
+                          <Tooltip label={upload.metadataWarning}>
+                            <Badge colorScheme="orange" borderRadius="md">No metadata</Badge>
+                          </Tooltip>
+                        )}
+                        {upload.status === 'success' && !!upload.failedTasks && (
+                          <Badge colorScheme="orange" borderRadius="md">
+                            {upload.failedTasks} skipped
+                          </Badge>
+                        )}
                         {upload.status === 'cancelled' && (
                           <Badge colorScheme="orange" borderRadius="md">Cancelled</Badge>
                         )}
@@ -619,6 +802,35 @@ export default function UploadDialog() {
                         )}
                       </Box>
 
+                      {/* What the file holds, before it is sent */}
+                      {upload.status === 'pending' && previewing === upload.file && (
+                        <FilePreviewPane file={upload.file} />
+                      )}
+
+                      {/* Store to upload into, with the layer it publishes */}
+                      {upload.status === 'pending' && connectionId && workspace &&
+                        !/\.(sld|css)$/i.test(upload.file.name) && (
+                        <UploadTargetPicker
+                          connectionId={connectionId}
+                          workspace={workspace}
+                          file={upload.file}
+                          value={upload.targetStore}
+                          onChange={(store) => setTargetStore(index, store)}
+                          isDisabled={isUploading}
+                        />
+                      )}
+
+                      {/* Metadata of the layer a shapefile or GeoTIFF publishes */}
+                      {upload.status === 'pending' &&
+                        /\.(zip|shp|tiff?)$/i.test(upload.file.name) && (
+                        <LayerMetadataFields
+                          file={upload.file}
+                          value={upload.metadata}
+                          onChange={(metadata) => setMetadata(index, metadata)}
+                          isDisabled={isUploading}
+                        />
+                      )}
+
                       {/* GeoServer upload progress (finalizing phase) */}
                       {(upload.status === 'uploading' || upload.status === 'paused') &&
                         upload.chunksUploaded >= upload.chunksTotal &&
@@ -717,6 +929,51 @@ export default function UploadDialog() {
               </List>
             )}
 
+            {/* COG conversion offer for GeoTIFFs without tiles or overviews */}
+            {cogQuestion && (
+              <Box w="100%" p={3} bg="orange.50" borderRadius="lg" border="1px solid" borderColor="orange.200">
+                <Text fontWeight="600" fontSize="sm" mb={1}>
+                  Convert {cogQuestion.fileName} to a Cloud Optimized GeoTIFF?
+                </Text>
+                <Text fontSize="sm" color="gray.600">
+                  {cogQuestion.advice.reasons.join('. ')}, so GeoServer will read more of the file
+                  than each map needs.
+                </Text>
+                <Text fontSize="xs" color="gray.500" mt={1}>
+                  About {formatSize(cogQuestion.advice.estimatedSize)} after conversion, taking
+                  roughly {formatEta(cogQuestion.advice.estimatedSeconds)}.
+                </Text>
+                <HStack mt={3} spacing={2}>
+                  <Checkbox
+                    size="sm"
+                    isChecked={alwaysConvert}
+                    onChange={(e) => setAlwaysConvert(e.target.checked)}
+                  >
+                    <Text fontSize="xs">Always convert files over</Text>
+                  </Checkbox>
+                  <NumberInput
+                    size="xs"
+                    w="80px"
+                    min={1}
+                    value={convertOverMb}
+                    onChange={(_, value) => setConvertOverMb(Number.isNaN(value) ? 1 : value)}
+                    isDisabled={!alwaysConvert}
+                  >
+                    <NumberInputField />
+                  </NumberInput>
+                  <Text fontSize="xs">MB</Text>
+                </HStack>
+                <HStack mt={3} justify="flex-end">
+                  <Button size="sm" variant="ghost" onClick={() => answerCog(false)}>
+                    Upload As Is
+                  </Button>
+                  <Button size="sm" colorScheme="kartoza" onClick={() => answerCog(true)}>
+                    Convert to COG
+                  </Button>
+                </HStack>
+              </Box>
+            )}
+
             {/* Available layers section */}
             {uploadComplete && availableLayers.length > 0 && (
               <>
@@ -737,8 +994,38 @@ export default function UploadDialog() {
                     </HStack>
                   </HStack>
                   <Text fontSize="sm" color="gray.500" mb={3}>
-                    The GeoPackage contains multiple layers. Select which layers to publish:
+                    Select the GeoPackage tables to publish as layers:
                   </Text>
+                  <SimpleGrid columns={3} spacing={3} mb={3} alignItems="end">
+                    <FormControl size="sm">
+                      <FormLabel fontSize="xs" mb={1}>Prefix</FormLabel>
+                      <Input
+                        size="sm"
+                        value={naming.prefix}
+                        onChange={(e) => setNaming({ ...naming, prefix: e.target.value })}
+                        placeholder="e.g. osm_"
+                      />
+                    </FormControl>
+                    <FormControl size="sm">
+                      <FormLabel fontSize="xs" mb={1}>Suffix</FormLabel>
+                      <Input
+                        size="sm"
+                        value={naming.suffix}
+                        onChange={(e) => setNaming({ ...naming, suffix: e.target.value })}
+                        placeholder="e.g. _2024"
+                      />
+                    </FormControl>
+                    <FormControl display="flex" alignItems="center" pb={1}>
+                      <Switch
+                        size="sm"
+                        colorScheme="kartoza"
+                        isChecked={naming.sanitize}
+                        onChange={(e) => setNaming({ ...naming, sanitize: e.target.checked })}
+                        mr={2}
+                      />
+                      <FormLabel fontSize="xs" mb={0}>Clean names</FormLabel>
+                    </FormControl>
+                  </SimpleGrid>
                   <VStack align="stretch" spacing={2} maxH="200px" overflowY="auto">
                     {availableLayers.map((layer, index) => (
                       <Box
@@ -757,6 +1044,11 @@ export default function UploadDialog() {
                           <HStack spacing={2}>
                             <Icon as={FiDatabase} color="gray.500" boxSize={4} />
                             <Text fontSize="sm">{layer.name}</Text>
+                            {layerNames?.[layer.name] && layerNames[layer.name] !== layer.name && (
+                              <Text fontSize="xs" color="gray.500">
+                                → {layerNames[layer.name]}
+                              </Text>
+                            )}
                           </HStack>
                         </Checkbox>
                       </Box>
@@ -782,6 +1074,18 @@ export default function UploadDialog() {
           borderTopColor="gray.100"
           bg="gray.50"
         >
+          <Button
+            variant="ghost"
+            leftIcon={<FiFolder />}
+            onClick={() =>
+              openDialog('batchupload', { mode: 'create', data: { connectionId, workspace } })
+            }
+            isDisabled={isUploading || !connectionId}
+            borderRadius="lg"
+            mr="auto"
+          >
+            Upload Folder...
+          </Button>
           <Button variant="ghost" onClick={handleClose} borderRadius="lg">
             {uploadComplete ? 'Close' : 'Cancel'}
           </Button>
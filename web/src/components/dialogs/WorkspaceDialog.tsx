@@ -9,7 +9,10 @@ import {
   Button,
   FormControl,
   FormLabel,
+  FormHelperText,
+  FormErrorMessage,
   Input,
+  Select,
   Switch,
   VStack,
   HStack,
@@ -26,7 +29,8 @@ import { useUIStore } from '../../stores/uiStore'
 import { useTreeStore } from '../../stores/treeStore'
 import { useConnectionStore } from '../../stores/connectionStore'
 import * as api from '../../api'
-import type { WorkspaceConfig } from '../../types'
+import { useNameCheck } from '../../hooks/useNameCheck'
+import type { WorkspaceConfig, WorkspaceTemplate } from '../../types'
 
 const defaultConfig: WorkspaceConfig = {
   name: '',
@@ -40,6 +44,14 @@ const defaultConfig: WorkspaceConfig = {
   wfsEnabled: true,
 }
 
+// Service switches a template's service list turns on or off
+const templateServices: [string, 'wmsEnabled' | 'wfsEnabled' | 'wcsEnabled' | 'wmtsEnabled'][] = [
+  ['wms', 'wmsEnabled'],
+  ['wfs', 'wfsEnabled'],
+  ['wcs', 'wcsEnabled'],
+  ['wmts', 'wmtsEnabled'],
+]
+
 export default function WorkspaceDialog() {
   const activeDialog = useUIStore((state) => state.activeDialog)
   const dialogData = useUIStore((state) => state.dialogData)
@@ -65,6 +77,19 @@ export default function WorkspaceDialog() {
     enabled: isOpen && isEditMode && !!connectionId && !!workspaceName,
   })
 
+  const { data: templates } = useQuery({
+    queryKey: ['workspaceTemplates'],
+    queryFn: api.getWorkspaceTemplates,
+    enabled: isOpen && !isEditMode,
+  })
+  const selectedTemplate = templates?.find((t) => t.name === config.template)
+  const nameProblems = useNameCheck(
+    'workspace',
+    config.name,
+    undefined,
+    isOpen && (!isEditMode || config.name !== workspaceName)
+  )
+
   useEffect(() => {
     if (isOpen) {
       if (isEditMode && existingConfig) {
@@ -79,6 +104,20 @@ export default function WorkspaceDialog() {
     setConfig((prev) => ({ ...prev, [field]: value }))
   }
 
+  const applyTemplate = (name: string) => {
+    const template: WorkspaceTemplate | undefined = templates?.find((t) => t.name === name)
+    setConfig((prev) => {
+      const next: WorkspaceConfig = { ...prev, template: template?.name }
+      if (template) {
+        next.isolated = template.isolated
+        for (const [service, field] of templateServices) {
+          next[field] = template.services.includes(service)
+        }
+      }
+      return next
+    })
+  }
+
   const handleSubmit = async () => {
     if (!connectionId) {
       toast({
@@ -111,12 +150,29 @@ export default function WorkspaceDialog() {
           duration: 2000,
         })
       } else {
-        await api.createWorkspace(connectionId, config)
-        toast({
-          title: 'Workspace created',
-          status: 'success',
-          duration: 2000,
-        })
+        const services = templateServices
+          .filter(([, field]) => config[field])
+          .map(([service]) => service)
+        const result = await api.createWorkspace(
+          connectionId,
+          config.template ? { ...config, services } : config
+        )
+        const failed = (result.steps ?? []).filter((step) => !step.ok)
+        if (failed.length > 0) {
+          toast({
+            title: 'Workspace created with problems',
+            description: failed.map((step) => `${step.kind} ${step.name}: ${step.error}`).join('\n'),
+            status: 'warning',
+            duration: 8000,
+            isClosable: true,
+          })
+        } else {
+          toast({
+            title: 'Workspace created',
+            status: 'success',
+            duration: 2000,
+          })
+        }
       }
       queryClient.invalidateQueries({ queryKey: ['workspaces', connectionId] })
       closeDialog()
@@ -160,7 +216,7 @@ export default function WorkspaceDialog() {
 
         <ModalBody py={6}>
           <VStack spacing={5} align="stretch">
-            <FormControl isRequired>
+            <FormControl isRequired isInvalid={nameProblems.length > 0}>
               <FormLabel fontWeight="500" color="gray.700">Workspace Name</FormLabel>
               <Input
                 value={config.name}
@@ -170,8 +226,36 @@ export default function WorkspaceDialog() {
                 size="lg"
                 borderRadius="lg"
               />
+              <FormErrorMessage>{nameProblems.join('; ')}</FormErrorMessage>
             </FormControl>
 
+            {!isEditMode && templates && templates.length > 0 && (
+              <FormControl>
+                <FormLabel fontWeight="500" color="gray.700">Template</FormLabel>
+                <Select
+                  value={config.template ?? ''}
+                  onChange={(e) => applyTemplate(e.target.value)}
+                  borderRadius="lg"
+                >
+                  <option value="">None (empty workspace)</option>
+                  {templates.map((t) => (
+                    <option key={t.name} value={t.name}>
+                      {t.name}
+                    </option>
+                  ))}
+                </Select>
+                {selectedTemplate && (
+                  <FormHelperText>
+                    {selectedTemplate.description}
+                    {selectedTemplate.datastores.length > 0 &&
+                      ` Stores: ${selectedTemplate.datastores.join(', ')}.`}
+                    {selectedTemplate.styles.length > 0 &&
+                      ` Styles: ${selectedTemplate.styles.join(', ')}.`}
+                  </FormHelperText>
+                )}
+              </FormControl>
+            )}
+
             <Box
               p={4}
               bg="gray.50"
@@ -2,11 +2,16 @@ import ConnectionDialog from './ConnectionDialog'
 import WorkspaceDialog from './WorkspaceDialog'
 import ConfirmDialog from './ConfirmDialog'
 import UploadDialog from './UploadDialog'
+import BatchUploadDialog from './BatchUploadDialog'
+import BatchActionDialog from './BatchActionDialog'
+import SnapshotDialog from './SnapshotDialog'
 import LayerGroupDialog from './LayerGroupDialog'
 import CacheDialog from './CacheDialog'
 import LayerDialog from './LayerDialog'
 import StoreDialog from './StoreDialog'
 import AppSettingsDialog from './AppSettingsDialog'
+import ServerComparisonDialog from './ServerComparisonDialog'
+import LayerAttributesDialog from './LayerAttributesDialog'
 import DataViewerDialog from './DataViewerDialog'
 import PGServiceDashboardDialog from './PGServiceDashboardDialog'
 import PGUploadDialog from './PGUploadDialog'
@@ -14,6 +19,8 @@ import S3ConnectionDialog from './S3ConnectionDialog'
 import S3UploadDialog from './S3UploadDialog'
 import QGISProjectDialog from './QGISProjectDialog'
 import QGISPreviewDialog from './QGISPreviewDialog'
+import QGISPublishDialog from './QGISPublishDialog'
+import MapExportDialog from './MapExportDialog'
 import GeoNodeConnectionDialog from './GeoNodeConnectionDialog'
 import GeoNodeUploadDialog from './GeoNodeUploadDialog'
 import IcebergConnectionDialog from './IcebergConnectionDialog'
@@ -27,6 +34,8 @@ import MerginMapsConnectionDialog from './MerginMapsConnectionDialog'
 import { SettingsDialog } from './SettingsDialog'
 import { SyncDialog } from './SyncDialog'
 import { StyleDialog } from './StyleDialog'
+import StyleImportDialog from './StyleImportDialog'
+import StyleAssetsDialog from './StyleAssetsDialog'
 import { Globe3DDialog } from './Globe3DDialog'
 
 export default function Dialogs() {
@@ -36,14 +45,21 @@ export default function Dialogs() {
       <WorkspaceDialog />
       <ConfirmDialog />
       <UploadDialog />
+      <BatchUploadDialog />
+      <BatchActionDialog />
+      <SnapshotDialog />
       <LayerGroupDialog />
       <CacheDialog />
       <LayerDialog />
       <StoreDialog />
       <SyncDialog />
       <StyleDialog />
+      <StyleImportDialog />
+      <StyleAssetsDialog />
       <Globe3DDialog />
       <AppSettingsDialog />
+      <ServerComparisonDialog />
+      <LayerAttributesDialog />
       <DataViewerDialog />
       <PGServiceDashboardDialog />
       <PGUploadDialog />
@@ -51,6 +67,8 @@ export default function Dialogs() {
       <S3UploadDialog />
       <QGISProjectDialog />
       <QGISPreviewDialog />
+      <QGISPublishDialog />
+      <MapExportDialog />
       <GeoNodeConnectionDialog />
       <GeoNodeUploadDialog />
       <IcebergConnectionDialog />
@@ -3,6 +3,7 @@ import {
   Modal,
   ModalOverlay,
   ModalContent,
+  ModalHeader,
   ModalBody,
   ModalFooter,
   ModalCloseButton,
@@ -12,6 +13,7 @@ import {
   VStack,
   HStack,
   FormControl,
+  FormErrorMessage,
   FormLabel,
   Input,
   Select,
@@ -52,9 +54,12 @@ import {
   FiChevronDown,
   FiChevronUp,
   FiImage,
+  FiLink,
 } from 'react-icons/fi'
 import { useUIStore } from '../../../stores/uiStore'
 import * as api from '../../../api'
+import { useNameCheck } from '../../../hooks/useNameCheck'
+import type { GWCStyleLayer } from '../../../types'
 
 // Import from refactored modules
 import type { ClassificationMethod, StyleRule } from './types'
@@ -94,12 +99,20 @@ export function StyleDialog() {
   // State
   const [name, setName] = useState('')
   const [format, setFormat] = useState<'sld' | 'css'>('sld')
+  // SLD version saved; the editor works in 1.0 and the server converts to 1.1
+  const [sldVersion, setSldVersion] = useState<api.SldVersion>('1.0.0')
+  const nameProblems = useNameCheck('style', name, workspace, isOpen && !isEditMode)
   const [content, setContent] = useState('')
   const [rules, setRules] = useState<StyleRule[]>([])
   const [activeTab, setActiveTab] = useState(0)
   const [hasChanges, setHasChanges] = useState(false)
   const [validationError, setValidationError] = useState<string | null>(null)
   const [previewUrl, setPreviewUrl] = useState<string | null>(null)
+  // Layers whose cached tiles still render the previous version of the style
+  const [staleCacheLayers, setStaleCacheLayers] = useState<GWCStyleLayer[]>([])
+  // Style kept elsewhere, e.g. a raw file in a Git repository
+  const [sourceUrl, setSourceUrl] = useState('')
+  const [sourceAuth, setSourceAuth] = useState('')
 
   // Classification wizard state
   const { isOpen: classifyPanelOpen, onToggle: toggleClassifyPanel } = useDisclosure()
@@ -128,6 +141,16 @@ export function StyleDialog() {
   const headerBg = useColorModeValue('linear-gradient(135deg, #0a3a50 0%, #175a77 50%, #2d7d9b 100%)', 'linear-gradient(135deg, #0a3a50 0%, #175a77 50%, #2d7d9b 100%)')
 
   // Fetch style content when editing
+  const { data: capabilities } = useQuery({
+    queryKey: ['serverCapabilities', connectionId],
+    queryFn: () => api.getServerCapabilities(connectionId),
+    staleTime: 5 * 60 * 1000,
+    enabled: !!connectionId,
+  })
+  // Unknown until the capabilities load, so only disable CSS once they say no
+  const cssSupported =
+    capabilities?.features.find((f) => f.name === 'css_styles')?.supported ?? true
+
   const { data: styleData, isLoading } = useQuery({
     queryKey: ['style', connectionId, workspace, styleName],
     queryFn: () => api.getStyleContent(connectionId, workspace, styleName),
@@ -141,6 +164,7 @@ export function StyleDialog() {
     if (isEditMode && styleData) {
       setName(styleData.name)
       setFormat(styleData.format as 'sld' | 'css')
+      setSldVersion(styleData.sldVersion ?? '1.0.0')
       setContent(styleData.content)
       if (styleData.format === 'sld') {
         setRules(parseSLDRules(styleData.content))
@@ -149,11 +173,14 @@ export function StyleDialog() {
       // New style
       setName('')
       setFormat('sld')
+      setSldVersion('1.0.0')
       setContent(DEFAULT_SLD)
       setRules(parseSLDRules(DEFAULT_SLD))
     }
     setHasChanges(false)
     setValidationError(null)
+    setSourceUrl('')
+    setSourceAuth('')
   }, [isOpen, isEditMode, styleData])
 
   // Sync visual editor changes to code
@@ -191,20 +218,68 @@ export function StyleDialog() {
 
   // Mutations
   const updateMutation = useMutation({
-    mutationFn: () => api.updateStyleContent(connectionId, workspace, styleName, content, format),
-    onSuccess: () => {
+    mutationFn: () =>
+      api.updateStyleContent(
+        connectionId,
+        workspace,
+        styleName,
+        content,
+        format,
+        format === 'sld' ? sldVersion : undefined
+      ),
+    onSuccess: (result) => {
       queryClient.invalidateQueries({ queryKey: ['styles', connectionId, workspace] })
       queryClient.invalidateQueries({ queryKey: ['style', connectionId, workspace, styleName] })
-      toast({ title: 'Style updated', status: 'success', duration: 3000 })
+      const truncated = result.truncatedLayers ?? []
+      toast({
+        title: 'Style updated',
+        description: truncated.length > 0
+          ? `Tile cache truncated for ${truncated.length} layer${truncated.length === 1 ? '' : 's'}`
+          : undefined,
+        status: 'success',
+        duration: 3000,
+      })
       setHasChanges(false)
+      if (truncated.length === 0) {
+        setStaleCacheLayers(result.affectedLayers ?? [])
+      }
     },
     onError: (error: Error) => {
       toast({ title: 'Failed to update style', description: error.message, status: 'error', duration: 5000 })
     },
   })
 
+  const truncateCacheMutation = useMutation({
+    mutationFn: () => api.truncateGWCStyleLayers(
+      connectionId,
+      workspace,
+      styleName,
+      staleCacheLayers.map((l) => l.layer)
+    ),
+    onSuccess: (result) => {
+      toast({
+        title: 'Tile cache truncated',
+        description: `${result.truncatedLayers.length} layer${result.truncatedLayers.length === 1 ? '' : 's'} truncated`,
+        status: 'success',
+        duration: 3000,
+      })
+      setStaleCacheLayers([])
+    },
+    onError: (error: Error) => {
+      toast({ title: 'Failed to truncate tile cache', description: error.message, status: 'error', duration: 5000 })
+    },
+  })
+
   const createMutation = useMutation({
-    mutationFn: () => api.createStyle(connectionId, workspace, name, content, format),
+    mutationFn: () =>
+      api.createStyle(
+        connectionId,
+        workspace,
+        name,
+        content,
+        format,
+        format === 'sld' ? sldVersion : undefined
+      ),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['styles', connectionId, workspace] })
       toast({ title: 'Style created', status: 'success', duration: 3000 })
@@ -215,6 +290,40 @@ export function StyleDialog() {
     },
   })
 
+  const urlMutation = useMutation({
+    mutationFn: async () => {
+      if (isEditMode) {
+        return api.updateStyleFromUrl(connectionId, workspace, styleName, sourceUrl, sourceAuth)
+      }
+      await api.createStyleFromUrl(connectionId, workspace, sourceUrl, name.trim(), sourceAuth)
+      return undefined
+    },
+    onSuccess: (result) => {
+      queryClient.invalidateQueries({ queryKey: ['styles', connectionId, workspace] })
+      if (!result) {
+        toast({ title: 'Style created from URL', status: 'success', duration: 3000 })
+        closeDialog()
+        return
+      }
+      queryClient.invalidateQueries({ queryKey: ['style', connectionId, workspace, styleName] })
+      toast({ title: 'Style updated from URL', status: 'success', duration: 3000 })
+      if (result.missingGraphics?.length) {
+        toast({
+          title: 'Graphics missing from the style package',
+          description: result.missingGraphics.join(', '),
+          status: 'warning',
+          duration: 8000,
+        })
+      }
+      if ((result.truncatedLayers ?? []).length === 0) {
+        setStaleCacheLayers(result.affectedLayers ?? [])
+      }
+    },
+    onError: (error: Error) => {
+      toast({ title: 'Failed to load style from URL', description: error.message, status: 'error', duration: 5000 })
+    },
+  })
+
   const handleSave = () => {
     if (!validateContent()) {
       toast({ title: 'Please fix validation errors', status: 'warning', duration: 3000 })
@@ -431,6 +540,7 @@ export function StyleDialog() {
   const isLoading_ = isLoading || updateMutation.isPending || createMutation.isPending
 
   return (
+    <>
     <Modal isOpen={isOpen} onClose={closeDialog} size="6xl" scrollBehavior="inside">
       <ModalOverlay bg="blackAlpha.600" backdropFilter="blur(4px)" />
       <ModalContent maxH="90vh" borderRadius="xl" overflow="hidden">
@@ -478,7 +588,7 @@ export function StyleDialog() {
               <Box w="250px" borderRight="1px solid" borderColor="gray.200" p={4} overflowY="auto">
                 <VStack spacing={4} align="stretch">
                   {!isEditMode && (
-                    <FormControl isRequired>
+                    <FormControl isRequired isInvalid={nameProblems.length > 0}>
                       <FormLabel>Style Name</FormLabel>
                       <Input
                         value={name}
@@ -488,6 +598,7 @@ export function StyleDialog() {
                         }}
                         placeholder="my-style"
                       />
+                      <FormErrorMessage>{nameProblems.join('; ')}</FormErrorMessage>
                     </FormControl>
                   )}
 
@@ -498,10 +609,62 @@ export function StyleDialog() {
                       onChange={(e) => handleFormatChange(e.target.value as 'sld' | 'css')}
                     >
                       <option value="sld">SLD (Styled Layer Descriptor)</option>
-                      <option value="css">CSS (GeoServer CSS)</option>
+                      <option value="css" disabled={!cssSupported && format !== 'css'}>
+                        {cssSupported ? 'CSS (GeoServer CSS)' : 'CSS (needs the CSS extension)'}
+                      </option>
                     </Select>
                   </FormControl>
 
+                  {format === 'sld' && (
+                    <FormControl>
+                      <FormLabel>SLD Version</FormLabel>
+                      <Select
+                        value={sldVersion}
+                        onChange={(e) => {
+                          setSldVersion(e.target.value as api.SldVersion)
+                          setHasChanges(true)
+                        }}
+                      >
+                        <option value="1.0.0">SLD 1.0</option>
+                        <option value="1.1.0">SLD 1.1 / Symbology Encoding</option>
+                      </Select>
+                    </FormControl>
+                  )}
+
+                  <FormControl>
+                    <FormLabel>From URL</FormLabel>
+                    <VStack spacing={2} align="stretch">
+                      <Input
+                        size="sm"
+                        value={sourceUrl}
+                        onChange={(e) => setSourceUrl(e.target.value)}
+                        placeholder="https://raw.githubusercontent.com/.../roads.sld"
+                      />
+                      <Input
+                        size="sm"
+                        type="password"
+                        value={sourceAuth}
+                        onChange={(e) => setSourceAuth(e.target.value)}
+                        placeholder="Authorization header (optional)"
+                      />
+                      <Button
+                        size="sm"
+                        variant="outline"
+                        leftIcon={<Icon as={FiLink} />}
+                        onClick={() => urlMutation.mutate()}
+                        isLoading={urlMutation.isPending}
+                        isDisabled={!/^https?:\/\//.test(sourceUrl.trim())}
+                      >
+                        {isEditMode ? 'Update from URL' : 'Create from URL'}
+                      </Button>
+                      <Text fontSize="xs" color="gray.500">
+                        {isEditMode
+                          ? 'Replaces the content with the SLD or CSS file at the URL'
+                          : 'Named after the file in the URL unless a name is given above'}
+                      </Text>
+                    </VStack>
+                  </FormControl>
+
                   <Divider />
 
                   {format === 'sld' && (
@@ -999,5 +1162,53 @@ export function StyleDialog() {
         </ModalFooter>
       </ModalContent>
     </Modal>
+
+    <Modal
+      isOpen={staleCacheLayers.length > 0}
+      onClose={() => setStaleCacheLayers([])}
+      size="md"
+      isCentered
+    >
+      <ModalOverlay />
+      <ModalContent>
+        <ModalHeader>Truncate cached tiles?</ModalHeader>
+        <ModalCloseButton />
+        <ModalBody>
+          <VStack align="stretch" spacing={3}>
+            <Text fontSize="sm">
+              These layers have cached tiles rendered with the previous version of{' '}
+              <strong>{styleName}</strong>:
+            </Text>
+            <VStack align="stretch" spacing={1} maxH="200px" overflowY="auto">
+              {staleCacheLayers.map((l) => (
+                <HStack key={l.layer} justify="space-between">
+                  <Text fontSize="sm" fontFamily="mono">{l.layer}</Text>
+                  <Badge colorScheme={l.usage === 'default' ? 'blue' : 'gray'}>
+                    {l.usage === 'default' ? 'default style' : 'additional style'}
+                  </Badge>
+                </HStack>
+              ))}
+            </VStack>
+            <Text fontSize="xs" color="gray.500">
+              Layers using this as their default style are truncated entirely; for the
+              others only tiles requested with this style are removed.
+            </Text>
+          </VStack>
+        </ModalBody>
+        <ModalFooter gap={3}>
+          <Button variant="ghost" onClick={() => setStaleCacheLayers([])}>
+            Keep Cache
+          </Button>
+          <Button
+            colorScheme="red"
+            onClick={() => truncateCacheMutation.mutate()}
+            isLoading={truncateCacheMutation.isPending}
+          >
+            Truncate
+          </Button>
+        </ModalFooter>
+      </ModalContent>
+    </Modal>
+    </>
   )
 }
@@ -1,6 +1,7 @@
 import type { StyleRule } from './types'
 
-// Parse SLD to extract style rules for visual editing
+// Parse SLD to extract style rules for visual editing; SLD 1.1 names its
+// parameters SvgParameter instead of CssParameter
 export function parseSLDRules(sldContent: string): StyleRule[] {
   const rules: StyleRule[] = []
 
@@ -20,13 +21,13 @@ export function parseSLDRules(sldContent: string): StyleRule[] {
       const polySymb = ruleEl.querySelector('PolygonSymbolizer')
       if (polySymb) {
         rule.symbolizer.type = 'polygon'
-        const fillParams = polySymb.querySelectorAll('Fill CssParameter')
+        const fillParams = polySymb.querySelectorAll('Fill CssParameter, Fill SvgParameter')
         fillParams.forEach(param => {
           const name = param.getAttribute('name')
           if (name === 'fill') rule.symbolizer.fill = param.textContent || '#3388ff'
           if (name === 'fill-opacity') rule.symbolizer.fillOpacity = parseFloat(param.textContent || '1')
         })
-        const strokeParams = polySymb.querySelectorAll('Stroke CssParameter')
+        const strokeParams = polySymb.querySelectorAll('Stroke CssParameter, Stroke SvgParameter')
         strokeParams.forEach(param => {
           const name = param.getAttribute('name')
           if (name === 'stroke') rule.symbolizer.stroke = param.textContent || '#2266cc'
@@ -39,7 +40,7 @@ export function parseSLDRules(sldContent: string): StyleRule[] {
       const lineSymb = ruleEl.querySelector('LineSymbolizer')
       if (lineSymb) {
         rule.symbolizer.type = 'line'
-        const strokeParams = lineSymb.querySelectorAll('Stroke CssParameter')
+        const strokeParams = lineSymb.querySelectorAll('Stroke CssParameter, Stroke SvgParameter')
         strokeParams.forEach(param => {
           const name = param.getAttribute('name')
           if (name === 'stroke') rule.symbolizer.stroke = param.textContent || '#3388ff'
@@ -55,13 +56,13 @@ export function parseSLDRules(sldContent: string): StyleRule[] {
         rule.symbolizer.pointShape = 'circle'
         rule.symbolizer.pointSize = 8
 
-        const fillParams = pointSymb.querySelectorAll('Fill CssParameter')
+        const fillParams = pointSymb.querySelectorAll('Fill CssParameter, Fill SvgParameter')
         fillParams.forEach(param => {
           const name = param.getAttribute('name')
           if (name === 'fill') rule.symbolizer.fill = param.textContent || '#3388ff'
           if (name === 'fill-opacity') rule.symbolizer.fillOpacity = parseFloat(param.textContent || '1')
         })
-        const strokeParams = pointSymb.querySelectorAll('Stroke CssParameter')
+        const strokeParams = pointSymb.querySelectorAll('Stroke CssParameter, Stroke SvgParameter')
         strokeParams.forEach(param => {
           const name = param.getAttribute('name')
           if (name === 'stroke') rule.symbolizer.stroke = param.textContent || '#2266cc'
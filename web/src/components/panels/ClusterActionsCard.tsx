@@ -0,0 +1,114 @@
+import {
+  Card,
+  CardBody,
+  HStack,
+  Box,
+  Text,
+  Button,
+  Spacer,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  Icon,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiCheckCircle, FiRefreshCw, FiTrash2, FiXCircle } from 'react-icons/fi'
+import { useMutation } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { ClusterAction } from '../../types'
+
+interface ClusterActionsCardProps {
+  connectionId: string
+  nodeCount: number
+  readOnly: boolean
+}
+
+// Catalog reload and cache reset, sent to every node of a clustered GeoServer
+export default function ClusterActionsCard({
+  connectionId,
+  nodeCount,
+  readOnly,
+}: ClusterActionsCardProps) {
+  const cardBg = useColorModeValue('white', 'gray.800')
+
+  const mutation = useMutation({
+    mutationFn: (action: ClusterAction) => api.runClusterAction(connectionId, action),
+  })
+  const running = mutation.isPending ? mutation.variables : null
+  const data = mutation.data
+
+  return (
+    <Card bg={cardBg}>
+      <CardBody>
+        <HStack mb={data || mutation.error ? 3 : 0}>
+          <Box>
+            <Text fontWeight="semibold">Reload and Reset</Text>
+            <Text fontSize="sm" color="gray.500">
+              {nodeCount > 1
+                ? `Sent to all ${nodeCount} cluster nodes at once`
+                : 'Picks up changes made directly in the data directory'}
+            </Text>
+          </Box>
+          <Spacer />
+          <Button
+            size="sm"
+            variant="outline"
+            leftIcon={<FiTrash2 />}
+            onClick={() => mutation.mutate('reset')}
+            isLoading={running === 'reset'}
+            isDisabled={readOnly || mutation.isPending}
+            loadingText="Resetting"
+          >
+            Reset Caches
+          </Button>
+          <Button
+            size="sm"
+            variant="outline"
+            leftIcon={<FiRefreshCw />}
+            onClick={() => mutation.mutate('reload')}
+            isLoading={running === 'reload'}
+            isDisabled={readOnly || mutation.isPending}
+            loadingText="Reloading"
+          >
+            Reload Catalog
+          </Button>
+        </HStack>
+
+        {mutation.error && (
+          <Text fontSize="sm" color="red.500">
+            {(mutation.error as Error).message}
+          </Text>
+        )}
+
+        {data && (
+          <Table size="sm">
+            <Thead>
+              <Tr>
+                <Th>Node</Th>
+                <Th isNumeric>Time</Th>
+                <Th>Result</Th>
+              </Tr>
+            </Thead>
+            <Tbody>
+              {data.nodes.map((node) => (
+                <Tr key={node.url}>
+                  <Td>{node.url}</Td>
+                  <Td isNumeric>{node.seconds.toFixed(2)} s</Td>
+                  <Td color={node.ok ? 'green.500' : 'red.500'}>
+                    <HStack spacing={1}>
+                      <Icon as={node.ok ? FiCheckCircle : FiXCircle} />
+                      <Text>{node.ok ? (data.action === 'reload' ? 'Reloaded' : 'Reset') : node.error}</Text>
+                    </HStack>
+                  </Td>
+                </Tr>
+              ))}
+            </Tbody>
+          </Table>
+        )}
+      </CardBody>
+    </Card>
+  )
+}
@@ -16,15 +16,26 @@ import {
   StatLabel,
   StatNumber,
   StatHelpText,
+  Tooltip,
+  Wrap,
+  WrapItem,
+  Spinner,
   useColorModeValue,
   useDisclosure,
 } from '@chakra-ui/react'
-import { FiServer, FiSettings, FiPlus, FiUpload } from 'react-icons/fi'
+import { FiServer, FiSettings, FiPlus, FiUpload, FiCheckCircle, FiAlertCircle } from 'react-icons/fi'
 import { useQuery } from '@tanstack/react-query'
 import * as api from '../../api'
 import { useConnectionStore } from '../../stores/connectionStore'
 import { useUIStore } from '../../stores/uiStore'
 import { SettingsDialog } from '../dialogs/SettingsDialog'
+import GeofenceRulesCard from './GeofenceRulesCard'
+import WmsWatermarkCard from './WmsWatermarkCard'
+import ConnectionProbeCard from './ConnectionProbeCard'
+import ClusterActionsCard from './ClusterActionsCard'
+import WorkspaceUsageCard from './WorkspaceUsageCard'
+import SnapshotScheduleCard from './SnapshotScheduleCard'
+import InterruptedOperationsCard from './InterruptedOperationsCard'
 
 interface ConnectionPanelProps {
   connectionId: string
@@ -42,11 +53,35 @@ export default function ConnectionPanel({ connectionId }: ConnectionPanelProps)
     queryFn: () => api.getServerInfo(connectionId),
   })
 
+  const { data: capabilities } = useQuery({
+    queryKey: ['serverCapabilities', connectionId],
+    queryFn: () => api.getServerCapabilities(connectionId),
+    staleTime: 5 * 60 * 1000,
+  })
+
+  const { data: installedModules, error: modulesError } = useQuery({
+    queryKey: ['installedModules', connectionId],
+    queryFn: () => api.getInstalledModules(connectionId),
+    staleTime: 5 * 60 * 1000,
+  })
+
   const { data: workspaces } = useQuery({
     queryKey: ['workspaces', connectionId],
     queryFn: () => api.getWorkspaces(connectionId),
   })
 
+  // Scanning the whole catalog is slow, so it only runs on request
+  const {
+    data: lintReport,
+    isFetching: isLinting,
+    error: lintError,
+    refetch: runLint,
+  } = useQuery({
+    queryKey: ['catalogLint', connectionId],
+    queryFn: () => api.lintCatalog(connectionId),
+    enabled: false,
+  })
+
   if (!connection) return null
 
   return (
@@ -130,6 +165,162 @@ export default function ConnectionPanel({ connectionId }: ConnectionPanelProps)
         )}
       </SimpleGrid>
 
+      {/* Server Features */}
+      {capabilities && (
+        <Card bg={cardBg}>
+          <CardBody>
+            <Text fontWeight="semibold" mb={3}>Server Features</Text>
+            <Wrap spacing={2}>
+              {capabilities.features.map((feature) => (
+                <WrapItem key={feature.name}>
+                  <Tooltip
+                    label={
+                      feature.extension
+                        ? `${feature.description} (needs ${feature.extension})`
+                        : `${feature.description} (GeoServer ${feature.minVersion}+)`
+                    }
+                  >
+                    <Badge colorScheme={feature.supported ? 'green' : 'gray'} px={2} py={1}>
+                      {feature.name.replace(/_/g, ' ')}
+                    </Badge>
+                  </Tooltip>
+                </WrapItem>
+              ))}
+            </Wrap>
+          </CardBody>
+        </Card>
+      )}
+
+      {/* Installed Extensions */}
+      <Card bg={cardBg}>
+        <CardBody>
+          <Text fontWeight="semibold" mb={3}>Installed Extensions</Text>
+          {modulesError ? (
+            <Text fontSize="sm" color="gray.500">
+              {(modulesError as Error).message}
+            </Text>
+          ) : !installedModules ? (
+            <Spinner size="sm" />
+          ) : installedModules.length === 0 ? (
+            <Text fontSize="sm" color="gray.500">
+              No extensions or community modules found
+            </Text>
+          ) : (
+            <Wrap spacing={2}>
+              {installedModules.map((module) => (
+                <WrapItem key={module.name}>
+                  <Tooltip label={`${module.name} ${module.version}`.trim()}>
+                    <Badge
+                      colorScheme={module.kind === 'extension' ? 'blue' : 'purple'}
+                      px={2}
+                      py={1}
+                    >
+                      {module.label}
+                      {module.kind === 'community' && ' (community)'}
+                    </Badge>
+                  </Tooltip>
+                </WrapItem>
+              ))}
+            </Wrap>
+          )}
+        </CardBody>
+      </Card>
+
+      {/* Mass truncations, bulk publishes and syncs to resume */}
+      <InterruptedOperationsCard connectionId={connectionId} />
+
+      {/* Connection Probe */}
+      <ConnectionProbeCard connectionId={connectionId} />
+
+      {/* Workspace Usage */}
+      <WorkspaceUsageCard connectionId={connectionId} />
+
+      {/* Scheduled catalog snapshots and restores */}
+      <SnapshotScheduleCard connectionId={connectionId} />
+
+      {/* Catalog reload and cache reset, on every cluster node */}
+      <ClusterActionsCard
+        connectionId={connectionId}
+        nodeCount={(connection?.clusterUrls ?? '').split(',').filter((url) => url.trim()).length}
+        readOnly={!!connection?.readOnly}
+      />
+
+      {/* WMS Watermark */}
+      <WmsWatermarkCard connectionId={connectionId} />
+
+      {/* GeoFence Rules, when the extension is installed */}
+      <GeofenceRulesCard connectionId={connectionId} />
+
+      {/* Catalog Check */}
+      <Card bg={cardBg}>
+        <CardBody>
+          <HStack mb={lintReport || lintError ? 3 : 0}>
+            <Box>
+              <Text fontWeight="semibold">Catalog Check</Text>
+              <Text fontSize="sm" color="gray.500">
+                Find layer groups, styles, stores and tile caches with broken references
+              </Text>
+            </Box>
+            <Spacer />
+            {lintReport && (
+              <HStack spacing={2}>
+                <Badge colorScheme="red">{lintReport.counts.error} errors</Badge>
+                <Badge colorScheme="orange">{lintReport.counts.warning} warnings</Badge>
+              </HStack>
+            )}
+            <Button
+              size="sm"
+              variant="outline"
+              onClick={() => runLint()}
+              isLoading={isLinting}
+              loadingText="Checking..."
+            >
+              {lintReport ? 'Check Again' : 'Run Check'}
+            </Button>
+          </HStack>
+
+          {lintError && (
+            <Text fontSize="sm" color="red.500">
+              {(lintError as Error).message}
+            </Text>
+          )}
+
+          {isLinting && !lintReport && (
+            <HStack spacing={2} color="gray.500">
+              <Spinner size="sm" />
+              <Text fontSize="sm">Scanning catalog...</Text>
+            </HStack>
+          )}
+
+          {lintReport && lintReport.issues.length === 0 && (
+            <HStack spacing={2} color="green.500">
+              <Icon as={FiCheckCircle} />
+              <Text fontSize="sm">No broken references found</Text>
+            </HStack>
+          )}
+
+          {lintReport && lintReport.issues.length > 0 && (
+            <VStack align="stretch" spacing={2} maxH="320px" overflowY="auto">
+              {lintReport.issues.map((issue, index) => (
+                <HStack key={`${issue.check}-${issue.resource}-${index}`} align="start" spacing={3}>
+                  <Icon
+                    as={FiAlertCircle}
+                    mt={1}
+                    color={issue.severity === 'error' ? 'red.500' : 'orange.400'}
+                  />
+                  <Box>
+                    <Text fontSize="sm">{issue.message}</Text>
+                    <Text fontSize="xs" color="gray.500">
+                      Suggested fix: {issue.fix}
+                    </Text>
+                  </Box>
+                </HStack>
+              ))}
+            </VStack>
+          )}
+        </CardBody>
+      </Card>
+
       {/* Actions */}
       <SimpleGrid columns={{ base: 1, md: 2 }} spacing={4}>
         <Button
@@ -0,0 +1,117 @@
+import {
+  Card,
+  CardBody,
+  HStack,
+  Box,
+  Text,
+  Button,
+  Spacer,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  VStack,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiActivity } from 'react-icons/fi'
+import { useQuery } from '@tanstack/react-query'
+import * as api from '../../api'
+
+interface ConnectionProbeCardProps {
+  connectionId: string
+}
+
+function formatMs(ms: number | null): string {
+  return ms === null ? '—' : `${Math.round(ms)} ms`
+}
+
+function formatRate(bytesPerSecond: number | null): string {
+  if (bytesPerSecond === null) return '—'
+  const units = ['B', 'KB', 'MB']
+  let rate = bytesPerSecond
+  for (const unit of units) {
+    if (rate < 1024) return `${rate.toFixed(1)} ${unit}/s`
+    rate /= 1024
+  }
+  return `${rate.toFixed(1)} GB/s`
+}
+
+// Measures REST latency, GetMap render time and WFS download throughput on demand
+export default function ConnectionProbeCard({ connectionId }: ConnectionProbeCardProps) {
+  const cardBg = useColorModeValue('white', 'gray.800')
+
+  const { data, error, isFetching, refetch } = useQuery({
+    queryKey: ['connectionProbe', connectionId],
+    queryFn: () => api.probeConnection(connectionId),
+    enabled: false,
+    retry: false,
+  })
+
+  return (
+    <Card bg={cardBg}>
+      <CardBody>
+        <HStack mb={data || error ? 3 : 0}>
+          <Box>
+            <Text fontWeight="semibold">Connection Probe</Text>
+            <Text fontSize="sm" color="gray.500">
+              Tells a slow link from a slow server
+            </Text>
+          </Box>
+          <Spacer />
+          <Button
+            size="sm"
+            variant="outline"
+            leftIcon={<FiActivity />}
+            onClick={() => refetch()}
+            isLoading={isFetching}
+            loadingText="Probing"
+          >
+            Run Probe
+          </Button>
+        </HStack>
+
+        {error && (
+          <Text fontSize="sm" color="red.500">
+            {(error as Error).message}
+          </Text>
+        )}
+
+        {data && (
+          <VStack align="stretch" spacing={3}>
+            <Table size="sm">
+              <Thead>
+                <Tr>
+                  <Th>Step</Th>
+                  <Th isNumeric>Time</Th>
+                  <Th isNumeric>First Byte</Th>
+                  <Th isNumeric>Throughput</Th>
+                  <Th>Detail</Th>
+                </Tr>
+              </Thead>
+              <Tbody>
+                {data.steps.map((step) => (
+                  <Tr key={step.name}>
+                    <Td>{step.label}</Td>
+                    <Td isNumeric>{formatMs(step.ms)}</Td>
+                    <Td isNumeric>{formatMs(step.firstByteMs)}</Td>
+                    <Td isNumeric>{formatRate(step.throughput)}</Td>
+                    <Td color={step.error ? 'red.500' : 'gray.500'}>{step.error || step.detail}</Td>
+                  </Tr>
+                ))}
+              </Tbody>
+            </Table>
+            <Box>
+              {data.findings.map((finding) => (
+                <Text key={finding} fontSize="sm">
+                  • {finding}
+                </Text>
+              ))}
+            </Box>
+          </VStack>
+        )}
+      </CardBody>
+    </Card>
+  )
+}
@@ -0,0 +1,114 @@
+import { useState } from 'react'
+import {
+  Card,
+  CardBody,
+  VStack,
+  HStack,
+  Box,
+  Text,
+  Heading,
+  Divider,
+  Button,
+  Badge,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  useToast,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiCheckCircle } from 'react-icons/fi'
+import { useMutation } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { EndpointResult, LayerEndpointReport } from '../../types'
+
+interface EndpointHealthCardProps {
+  connectionId: string
+  workspace: string
+  layerName: string
+}
+
+const STATUS_BADGES: Record<EndpointResult['status'], { label: string; color: string }> = {
+  pass: { label: 'Pass', color: 'green' },
+  fail: { label: 'Fail', color: 'red' },
+  skip: { label: 'Skipped', color: 'gray' },
+}
+
+// Checks the layer's OGC endpoints the way clients use them, since REST can look fine while they fail
+export default function EndpointHealthCard({ connectionId, workspace, layerName }: EndpointHealthCardProps) {
+  const cardBg = useColorModeValue('white', 'gray.800')
+  const headerBg = useColorModeValue('gray.50', 'gray.700')
+  const toast = useToast()
+  const [report, setReport] = useState<LayerEndpointReport | null>(null)
+
+  const verifyMutation = useMutation({
+    mutationFn: () => api.verifyLayerEndpoints(connectionId, workspace, layerName),
+    onSuccess: setReport,
+    onError: (err: Error) => {
+      toast({ title: 'Verification failed', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  return (
+    <Card bg={cardBg}>
+      <CardBody>
+        <VStack align="stretch" spacing={4}>
+          <Heading size="sm" color="gray.600">Endpoint Health</Heading>
+          <Divider />
+          <Text fontSize="sm" color="gray.600">
+            Send the layer's WMS, WFS, WMTS and legend endpoints a small request each, to catch
+            disabled services, broken stores and SRS problems the REST API doesn't show.
+          </Text>
+          <HStack>
+            <Button
+              size="sm"
+              colorScheme="kartoza"
+              leftIcon={<FiCheckCircle />}
+              onClick={() => verifyMutation.mutate()}
+              isLoading={verifyMutation.isPending}
+              loadingText="Checking..."
+            >
+              Verify Endpoints
+            </Button>
+            {report && (
+              <Text fontSize="sm" color="gray.600">
+                {report.passed} passed, {report.failed} failed, {report.skipped} skipped
+              </Text>
+            )}
+          </HStack>
+
+          {report && (
+            <Box overflowX="auto">
+              <Table size="sm">
+                <Thead bg={headerBg}>
+                  <Tr>
+                    <Th>Endpoint</Th>
+                    <Th>Result</Th>
+                    <Th isNumeric>Time</Th>
+                    <Th>Detail</Th>
+                  </Tr>
+                </Thead>
+                <Tbody>
+                  {report.results.map((result) => (
+                    <Tr key={result.service}>
+                      <Td>{result.label}</Td>
+                      <Td>
+                        <Badge colorScheme={STATUS_BADGES[result.status].color}>
+                          {STATUS_BADGES[result.status].label}
+                        </Badge>
+                      </Td>
+                      <Td isNumeric>{result.status === 'skip' ? '–' : `${result.elapsedMs} ms`}</Td>
+                      <Td fontSize="xs" color="gray.600" whiteSpace="normal">{result.detail}</Td>
+                    </Tr>
+                  ))}
+                </Tbody>
+              </Table>
+            </Box>
+          )}
+        </VStack>
+      </CardBody>
+    </Card>
+  )
+}
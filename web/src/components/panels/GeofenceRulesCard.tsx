@@ -0,0 +1,223 @@
+import { useState } from 'react'
+import {
+  Card,
+  CardBody,
+  VStack,
+  HStack,
+  Box,
+  Text,
+  Button,
+  IconButton,
+  Badge,
+  Spacer,
+  Spinner,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  Tooltip,
+  useDisclosure,
+  useToast,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiEdit2, FiPlus, FiTrash2 } from 'react-icons/fi'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { GeofenceAccess, GeofenceRule } from '../../types'
+import { useConnectionStore } from '../../stores/connectionStore'
+import { GeofenceRuleDialog } from '../dialogs/GeofenceRuleDialog'
+
+interface GeofenceRulesCardProps {
+  connectionId: string
+}
+
+const ACCESS_COLORS: Record<GeofenceAccess, string> = {
+  ALLOW: 'green',
+  DENY: 'red',
+  LIMIT: 'orange',
+}
+
+function orAny(value: string | null): string {
+  return value ?? '*'
+}
+
+// Data security rules of servers running the GeoFence extension
+export default function GeofenceRulesCard({ connectionId }: GeofenceRulesCardProps) {
+  const cardBg = useColorModeValue('white', 'gray.800')
+  const headerBg = useColorModeValue('gray.50', 'gray.700')
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const ruleDisclosure = useDisclosure()
+  const [editing, setEditing] = useState<GeofenceRule | null>(null)
+  // Rule whose delete button was pressed once, waiting for the second press
+  const [deleting, setDeleting] = useState<number | null>(null)
+  const readOnly = useConnectionStore((state) =>
+    state.connections.some((c) => c.id === connectionId && c.readOnly)
+  )
+
+  const { data: capabilities } = useQuery({
+    queryKey: ['serverCapabilities', connectionId],
+    queryFn: () => api.getServerCapabilities(connectionId),
+    staleTime: 5 * 60 * 1000,
+  })
+  const supported = capabilities?.features.find((f) => f.name === 'geofence')?.supported
+
+  const { data, isLoading, error } = useQuery({
+    queryKey: ['geofenceRules', connectionId],
+    queryFn: () => api.getGeofenceRules(connectionId),
+    enabled: !!supported,
+  })
+
+  const deleteMutation = useMutation({
+    mutationFn: (id: number) => api.deleteGeofenceRule(connectionId, id),
+    onSuccess: () => {
+      setDeleting(null)
+      queryClient.invalidateQueries({ queryKey: ['geofenceRules', connectionId] })
+    },
+    onError: (err: Error) => {
+      toast({ title: 'Failed to delete rule', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  if (!supported || data?.available === false) return null
+
+  const openRule = (rule: GeofenceRule | null) => {
+    setEditing(rule)
+    ruleDisclosure.onOpen()
+  }
+
+  return (
+    <Card bg={cardBg}>
+      <CardBody>
+        <HStack mb={3}>
+          <Box>
+            <Text fontWeight="semibold">GeoFence Rules</Text>
+            <Text fontSize="sm" color="gray.500">
+              The first rule matching a request, lowest priority first, allows, denies or limits it
+            </Text>
+          </Box>
+          <Spacer />
+          {!readOnly && (
+            <Button size="sm" variant="outline" leftIcon={<FiPlus />} onClick={() => openRule(null)}>
+              Add Rule
+            </Button>
+          )}
+        </HStack>
+
+        {isLoading && <Spinner size="sm" />}
+
+        {error && (
+          <Text fontSize="sm" color="red.500">
+            {(error as Error).message}
+          </Text>
+        )}
+
+        {data && data.rules.length === 0 && (
+          <Text fontSize="sm" color="gray.500">
+            No rules yet; GeoFence allows or denies everything by its default setting
+          </Text>
+        )}
+
+        {data && data.rules.length > 0 && (
+          <Box overflowX="auto" maxH="420px" overflowY="auto">
+            <Table size="sm">
+              <Thead bg={headerBg}>
+                <Tr>
+                  <Th isNumeric>Priority</Th>
+                  <Th>Access</Th>
+                  <Th>User / Role</Th>
+                  <Th>Service</Th>
+                  <Th>Layer</Th>
+                  <Th>Address</Th>
+                  {!readOnly && <Th />}
+                </Tr>
+              </Thead>
+              <Tbody>
+                {data.rules.map((rule) => (
+                  <Tr key={rule.id}>
+                    <Td isNumeric>{rule.priority}</Td>
+                    <Td>
+                      <VStack align="start" spacing={0}>
+                        <Badge colorScheme={ACCESS_COLORS[rule.access]}>{rule.access}</Badge>
+                        {rule.area && (
+                          <Tooltip label={rule.area}>
+                            <Text fontSize="2xs" color="gray.500">area</Text>
+                          </Tooltip>
+                        )}
+                        {rule.catalogMode && (
+                          <Text fontSize="2xs" color="gray.500">{rule.catalogMode}</Text>
+                        )}
+                      </VStack>
+                    </Td>
+                    <Td>
+                      <Text fontSize="xs">{orAny(rule.user)} / {orAny(rule.role)}</Text>
+                    </Td>
+                    <Td>
+                      <Text fontSize="xs">
+                        {orAny(rule.service)}
+                        {rule.request && ` ${rule.request}`}
+                      </Text>
+                    </Td>
+                    <Td>
+                      <Text fontSize="xs">{orAny(rule.workspace)}:{orAny(rule.layer)}</Text>
+                    </Td>
+                    <Td>
+                      <Text fontSize="xs">{orAny(rule.addressRange)}</Text>
+                    </Td>
+                    {!readOnly && (
+                      <Td>
+                        <HStack spacing={1}>
+                          <IconButton
+                            aria-label="Edit rule"
+                            icon={<FiEdit2 />}
+                            size="xs"
+                            variant="ghost"
+                            onClick={() => openRule(rule)}
+                          />
+                          {deleting === rule.id ? (
+                            <Button
+                              size="xs"
+                              colorScheme="red"
+                              isLoading={deleteMutation.isPending}
+                              onClick={() => deleteMutation.mutate(rule.id!)}
+                              onBlur={() => setDeleting(null)}
+                            >
+                              Delete?
+                            </Button>
+                          ) : (
+                            <IconButton
+                              aria-label="Delete rule"
+                              icon={<FiTrash2 />}
+                              size="xs"
+                              variant="ghost"
+                              colorScheme="red"
+                              onClick={() => setDeleting(rule.id)}
+                            />
+                          )}
+                        </HStack>
+                      </Td>
+                    )}
+                  </Tr>
+                ))}
+              </Tbody>
+            </Table>
+          </Box>
+        )}
+      </CardBody>
+
+      {data && (
+        <GeofenceRuleDialog
+          isOpen={ruleDisclosure.isOpen}
+          onClose={ruleDisclosure.onClose}
+          connectionId={connectionId}
+          rule={editing}
+          accessTypes={data.accessTypes}
+          catalogModes={data.catalogModes}
+          services={data.services}
+        />
+      )}
+    </Card>
+  )
+}
@@ -0,0 +1,138 @@
+import {
+  Card,
+  CardBody,
+  VStack,
+  HStack,
+  Box,
+  Text,
+  Button,
+  Badge,
+  Tooltip,
+  useToast,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiPlay, FiRotateCw, FiTrash2 } from 'react-icons/fi'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { InterruptedOperation } from '../../types'
+
+interface InterruptedOperationsCardProps {
+  connectionId: string
+}
+
+const KIND_LABELS: Record<InterruptedOperation['kind'], string> = {
+  truncate: 'Mass truncate',
+  upload: 'Bulk publish',
+  sync: 'Sync',
+}
+
+// Mass truncations, bulk publishes and syncs cut short on this connection, to resume from the
+// last finished step; hidden when there are none
+export default function InterruptedOperationsCard({
+  connectionId,
+}: InterruptedOperationsCardProps) {
+  const cardBg = useColorModeValue('white', 'gray.800')
+  const toast = useToast()
+  const queryClient = useQueryClient()
+
+  const { data: operations } = useQuery({
+    queryKey: ['interruptedOperations', connectionId],
+    queryFn: () => api.getInterruptedOperations(connectionId),
+    refetchInterval: 10000,
+  })
+
+  const refresh = () =>
+    queryClient.invalidateQueries({ queryKey: ['interruptedOperations', connectionId] })
+
+  const resumeMutation = useMutation({
+    mutationFn: async (operation: InterruptedOperation) => {
+      if (operation.kind === 'truncate') {
+        await api.resumeMassTruncate(connectionId, operation.id)
+      } else {
+        await api.resumeSync(operation.id)
+      }
+    },
+    onSuccess: () => {
+      toast({ title: 'Resumed', status: 'success', duration: 3000 })
+      refresh()
+    },
+    onError: (err: Error) => {
+      toast({ title: 'Resume failed', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  const discardMutation = useMutation({
+    mutationFn: (id: string) => api.discardOperation(id),
+    onSuccess: refresh,
+  })
+
+  if (!operations?.length) return null
+
+  return (
+    <Card bg={cardBg}>
+      <CardBody>
+        <HStack mb={3}>
+          <Text fontWeight="semibold">Interrupted Operations</Text>
+          <Badge colorScheme="orange">{operations.length}</Badge>
+        </HStack>
+        <VStack align="stretch" spacing={3}>
+          {operations.map((operation) => {
+            const errors = Object.entries(operation.failed)
+            return (
+              <Box key={operation.id}>
+                <HStack justify="space-between">
+                  <Box>
+                    <Text fontSize="sm" fontWeight="500">
+                      {KIND_LABELS[operation.kind]} ·{' '}
+                      {new Date(operation.createdAt).toLocaleString()}
+                    </Text>
+                    <Text fontSize="xs" color="gray.500">
+                      {operation.done} of {operation.total} done, {operation.pending.length} left
+                    </Text>
+                  </Box>
+                  <HStack>
+                    {operation.kind === 'upload' ? (
+                      // The files are in a local folder the TUI or CLI reads again
+                      <Tooltip
+                        label={`Scan the folder again in the TUI, or run cloudbench operations --resume ${operation.id}`}
+                      >
+                        <Button size="xs" leftIcon={<FiPlay />} isDisabled>
+                          Resume
+                        </Button>
+                      </Tooltip>
+                    ) : (
+                      <Button
+                        size="xs"
+                        leftIcon={<FiRotateCw />}
+                        onClick={() => resumeMutation.mutate(operation)}
+                        isLoading={
+                          resumeMutation.isPending && resumeMutation.variables?.id === operation.id
+                        }
+                      >
+                        Resume
+                      </Button>
+                    )}
+                    <Button
+                      size="xs"
+                      variant="ghost"
+                      colorScheme="red"
+                      leftIcon={<FiTrash2 />}
+                      onClick={() => discardMutation.mutate(operation.id)}
+                    >
+                      Discard
+                    </Button>
+                  </HStack>
+                </HStack>
+                {errors.slice(0, 3).map(([step, message]) => (
+                  <Text key={step} fontSize="xs" color="red.500">
+                    {step}: {message}
+                  </Text>
+                ))}
+              </Box>
+            )
+          })}
+        </VStack>
+      </CardBody>
+    </Card>
+  )
+}
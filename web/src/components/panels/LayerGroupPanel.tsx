@@ -40,6 +40,12 @@ export default function LayerGroupPanel({
     queryFn: () => api.getLayerGroup(connectionId, workspace, groupName),
   })
 
+  const { data: groupCache, isError: notCached } = useQuery({
+    queryKey: ['gwc-layer', connectionId, `${workspace}:${groupName}`],
+    queryFn: () => api.getGWCLayer(connectionId, `${workspace}:${groupName}`),
+    retry: false,
+  })
+
   const handlePreview = async () => {
     try {
       const { url } = await api.startPreview({
@@ -249,6 +255,24 @@ export default function LayerGroupPanel({
                   Manage tile cache for this layer group. Seed tiles for faster map viewing,
                   or truncate the cache to regenerate tiles.
                 </Text>
+                {groupCache ? (
+                  <HStack flexWrap="wrap" gap={2}>
+                    <Badge colorScheme="green">Cached</Badge>
+                    {groupCache.gridSubsets?.map((gs) => (
+                      <Badge key={gs} colorScheme="blue" variant="subtle">{gs}</Badge>
+                    ))}
+                    {groupCache.mimeFormats?.map((fmt) => (
+                      <Badge key={fmt} colorScheme="purple" variant="subtle">{fmt}</Badge>
+                    ))}
+                  </HStack>
+                ) : notCached ? (
+                  <HStack>
+                    <Badge>Not cached</Badge>
+                    <Text fontSize="sm" color="gray.500">
+                      Open the cache manager to enable tile caching for this group.
+                    </Text>
+                  </HStack>
+                ) : null}
                 <SimpleGrid columns={{ base: 1, md: 2 }} spacing={4}>
                   <Button
                     colorScheme="kartoza"
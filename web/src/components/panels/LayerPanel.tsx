@@ -13,12 +13,57 @@ import {
   Badge,
   SimpleGrid,
   Divider,
+  Spinner,
+  Stat,
+  StatLabel,
+  StatNumber,
+  StatHelpText,
   useColorModeValue,
 } from '@chakra-ui/react'
-import { FiLayers, FiMap, FiDatabase, FiEdit3 } from 'react-icons/fi'
+import type { ReactNode } from 'react'
+import { FiLayers, FiMap, FiDatabase, FiEdit3, FiList } from 'react-icons/fi'
 import { useQuery } from '@tanstack/react-query'
 import * as api from '../../api'
 import { useUIStore } from '../../stores/uiStore'
+import RenderBenchmarkCard from './RenderBenchmarkCard'
+import EndpointHealthCard from './EndpointHealthCard'
+
+function formatBytes(bytes: number): string {
+  if (bytes === 0) return '0 B'
+  const k = 1024
+  const sizes = ['B', 'KB', 'MB', 'GB', 'TB']
+  const i = Math.floor(Math.log(bytes) / Math.log(k))
+  return parseFloat((bytes / Math.pow(k, i)).toFixed(1)) + ' ' + sizes[i]
+}
+
+interface UsageStatProps {
+  label: string
+  isLoading: boolean
+  error: unknown
+  value?: ReactNode
+  help?: ReactNode
+}
+
+// One usage figure; each loads on its own so a slow one doesn't hold up the rest
+function UsageStat({ label, isLoading, error, value, help }: UsageStatProps) {
+  return (
+    <Stat>
+      <StatLabel color="gray.500">{label}</StatLabel>
+      {isLoading ? (
+        <Spinner size="sm" mt={2} />
+      ) : error ? (
+        <Text fontSize="sm" color="gray.500" mt={1}>
+          {(error as Error).message}
+        </Text>
+      ) : (
+        <>
+          <StatNumber fontSize="xl" color="kartoza.700">{value}</StatNumber>
+          {help && <StatHelpText mb={0}>{help}</StatHelpText>}
+        </>
+      )}
+    </Stat>
+  )
+}
 
 interface LayerPanelProps {
   connectionId: string
@@ -40,6 +85,29 @@ export default function LayerPanel({
     queryFn: () => api.getLayer(connectionId, workspace, layerName),
   })
 
+  const isVector = !!layer && layer.storeType !== 'coveragestore'
+
+  const featureCount = useQuery({
+    queryKey: ['layerFeatureCount', connectionId, workspace, layerName],
+    queryFn: () => api.getLayerFeatureCount(connectionId, workspace, layerName),
+    enabled: isVector,
+    staleTime: 60 * 1000,
+  })
+
+  const cacheUsage = useQuery({
+    queryKey: ['layerCacheUsage', connectionId, workspace, layerName],
+    queryFn: () => api.getLayerCacheUsage(connectionId, workspace, layerName),
+    staleTime: 60 * 1000,
+    retry: false,
+  })
+
+  const requestStats = useQuery({
+    queryKey: ['layerRequestStats', connectionId, workspace, layerName],
+    queryFn: () => api.getLayerRequestStats(connectionId, workspace, layerName),
+    staleTime: 60 * 1000,
+    retry: false,
+  })
+
   const handlePreview = async () => {
     try {
       const { url } = await api.startPreview({
@@ -156,6 +224,22 @@ export default function LayerPanel({
               >
                 Manage Cache
               </Button>
+              {isVector && (
+                <Button
+                  size="lg"
+                  variant="outline"
+                  color="white"
+                  borderColor="whiteAlpha.400"
+                  _hover={{ bg: 'whiteAlpha.200' }}
+                  leftIcon={<FiList />}
+                  onClick={() => openDialog('attributes', {
+                    mode: 'view',
+                    data: { connectionId, workspace, layerName }
+                  })}
+                >
+                  Attribute Table
+                </Button>
+              )}
               <Button
                 size="lg"
                 variant="outline"
@@ -204,6 +288,56 @@ export default function LayerPanel({
         </Card>
       )}
 
+      {/* Usage */}
+      <Card bg={cardBg}>
+        <CardBody>
+          <VStack align="stretch" spacing={3}>
+            <Heading size="sm" color="gray.600">Usage</Heading>
+            <Divider />
+            <SimpleGrid columns={{ base: 1, md: 3 }} spacing={4}>
+              {isVector && (
+                <UsageStat
+                  label="Features"
+                  isLoading={featureCount.isLoading}
+                  error={featureCount.error}
+                  value={featureCount.data?.toLocaleString() ?? 'Unknown'}
+                />
+              )}
+              <UsageStat
+                label="Cached Tiles"
+                isLoading={cacheUsage.isLoading}
+                error={cacheUsage.error}
+                value={
+                  cacheUsage.data?.usedBytes != null ? formatBytes(cacheUsage.data.usedBytes) : 'Unknown'
+                }
+                help={
+                  cacheUsage.data?.limitBytes != null
+                    ? `of ${formatBytes(cacheUsage.data.limitBytes)} quota`
+                    : 'Size tracked by the disk quota'
+                }
+              />
+              <UsageStat
+                label={`Requests (${requestStats.data?.days ?? 30} days)`}
+                isLoading={requestStats.isLoading}
+                error={requestStats.error}
+                value={
+                  requestStats.data &&
+                  `${requestStats.data.complete ? '' : '≥ '}${requestStats.data.requests.toLocaleString()}`
+                }
+                help={
+                  requestStats.data?.lastRequest
+                    ? `Last: ${new Date(requestStats.data.lastRequest).toLocaleString()}`
+                    : 'No recent requests'
+                }
+              />
+            </SimpleGrid>
+          </VStack>
+        </CardBody>
+      </Card>
+
+      <RenderBenchmarkCard connectionId={connectionId} workspace={workspace} layerName={layerName} />
+      <EndpointHealthCard connectionId={connectionId} workspace={workspace} layerName={layerName} />
+
       {/* Quick Actions Card */}
       <Card bg={cardBg}>
         <CardBody>
@@ -0,0 +1,197 @@
+import { useState } from 'react'
+import {
+  Card,
+  CardBody,
+  VStack,
+  HStack,
+  Box,
+  Text,
+  Heading,
+  Divider,
+  Button,
+  Badge,
+  FormControl,
+  FormLabel,
+  FormHelperText,
+  Input,
+  NumberInput,
+  NumberInputField,
+  Select,
+  SimpleGrid,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  useToast,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiActivity } from 'react-icons/fi'
+import { useQuery, useMutation } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { RenderBenchmark } from '../../types'
+
+interface RenderBenchmarkCardProps {
+  connectionId: string
+  workspace: string
+  layerName: string
+}
+
+function formatSeconds(seconds: number | null): string {
+  if (seconds == null) return '–'
+  return seconds < 1 ? `${Math.round(seconds * 1000)} ms` : `${seconds.toFixed(2)} s`
+}
+
+function formatKB(bytes: number): string {
+  return `${(bytes / 1024).toFixed(1)} KB`
+}
+
+// Times GetMap requests of the layer to find styles too slow to render uncached
+export default function RenderBenchmarkCard({ connectionId, workspace, layerName }: RenderBenchmarkCardProps) {
+  const cardBg = useColorModeValue('white', 'gray.800')
+  const headerBg = useColorModeValue('gray.50', 'gray.700')
+  const toast = useToast()
+  const [style, setStyle] = useState('')
+  const [zooms, setZooms] = useState('')
+  const [samples, setSamples] = useState(3)
+  const [size, setSize] = useState(512)
+  const [report, setReport] = useState<RenderBenchmark | null>(null)
+
+  const { data: layerStyles } = useQuery({
+    queryKey: ['layerStyles', connectionId, workspace, layerName],
+    queryFn: () => api.getLayerStyles(connectionId, workspace, layerName),
+  })
+
+  const runMutation = useMutation({
+    mutationFn: () =>
+      api.runLayerBenchmark(connectionId, workspace, layerName, {
+        style,
+        zooms: zooms
+          .split(/[\s,]+/)
+          .filter(Boolean)
+          .map(Number),
+        samples,
+        size,
+      }),
+    onSuccess: setReport,
+    onError: (err: Error) => {
+      toast({ title: 'Benchmark failed', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  const styles = layerStyles
+    ? [layerStyles.defaultStyle, ...layerStyles.additionalStyles].filter(Boolean)
+    : []
+
+  return (
+    <Card bg={cardBg}>
+      <CardBody>
+        <VStack align="stretch" spacing={4}>
+          <Heading size="sm" color="gray.600">Render Benchmark</Heading>
+          <Divider />
+          <Text fontSize="sm" color="gray.600">
+            Time uncached GetMap requests at a few zoom levels and places in the layer, to find
+            styles that are slow to render before caching or publishing it.
+          </Text>
+          <SimpleGrid columns={{ base: 1, md: 4 }} spacing={4}>
+            <FormControl>
+              <FormLabel fontSize="sm">Style</FormLabel>
+              <Select size="sm" value={style} onChange={(e) => setStyle(e.target.value)}>
+                <option value="">Default style</option>
+                {styles.map((name) => (
+                  <option key={name} value={name}>{name}</option>
+                ))}
+              </Select>
+            </FormControl>
+            <FormControl>
+              <FormLabel fontSize="sm">Zoom Levels</FormLabel>
+              <Input size="sm" value={zooms} onChange={(e) => setZooms(e.target.value)} placeholder="Automatic" />
+              <FormHelperText>e.g. 6, 10, 14</FormHelperText>
+            </FormControl>
+            <FormControl>
+              <FormLabel fontSize="sm">Places per Zoom</FormLabel>
+              <NumberInput
+                size="sm"
+                min={1}
+                max={20}
+                value={samples}
+                onChange={(_, value) => setSamples(Number.isNaN(value) ? 1 : value)}
+              >
+                <NumberInputField />
+              </NumberInput>
+            </FormControl>
+            <FormControl>
+              <FormLabel fontSize="sm">Image Size</FormLabel>
+              <Select size="sm" value={size} onChange={(e) => setSize(Number(e.target.value))}>
+                {[256, 512, 1024].map((value) => (
+                  <option key={value} value={value}>{value} × {value}</option>
+                ))}
+              </Select>
+            </FormControl>
+          </SimpleGrid>
+          <HStack>
+            <Button
+              size="sm"
+              colorScheme="kartoza"
+              leftIcon={<FiActivity />}
+              onClick={() => runMutation.mutate()}
+              isLoading={runMutation.isPending}
+              loadingText="Rendering..."
+            >
+              Run Benchmark
+            </Button>
+            {report && (
+              <Text fontSize="sm" color="gray.600">
+                p50 {formatSeconds(report.p50)}, p95 {formatSeconds(report.p95)} over{' '}
+                {report.samples.length} maps
+              </Text>
+            )}
+          </HStack>
+
+          {report && (
+            <Box overflowX="auto">
+              <Table size="sm">
+                <Thead bg={headerBg}>
+                  <Tr>
+                    <Th isNumeric>Zoom</Th>
+                    <Th isNumeric>Scale</Th>
+                    <Th isNumeric>p50</Th>
+                    <Th isNumeric>p95</Th>
+                    <Th isNumeric>Mean Size</Th>
+                    <Th />
+                  </Tr>
+                </Thead>
+                <Tbody>
+                  {report.zooms.map((zoom) => (
+                    <Tr key={zoom.zoom}>
+                      <Td isNumeric>{zoom.zoom}</Td>
+                      <Td isNumeric>1:{zoom.scale.toLocaleString()}</Td>
+                      <Td isNumeric>{formatSeconds(zoom.p50)}</Td>
+                      <Td isNumeric>{formatSeconds(zoom.p95)}</Td>
+                      <Td isNumeric>{formatKB(zoom.meanBytes)}</Td>
+                      <Td>
+                        <HStack spacing={1}>
+                          {zoom.slow && <Badge colorScheme="orange">Slow</Badge>}
+                          {zoom.errors > 0 && (
+                            <Badge colorScheme="red">{zoom.errors} of {zoom.requests} failed</Badge>
+                          )}
+                        </HStack>
+                      </Td>
+                    </Tr>
+                  ))}
+                </Tbody>
+              </Table>
+              {report.zooms.some((zoom) => zoom.slow) && (
+                <Text fontSize="xs" color="gray.500" mt={2}>
+                  Maps taking over {report.slowSeconds} s at p95 are worth caching, or simplifying
+                  the style with scale rules at those zoom levels.
+                </Text>
+              )}
+            </Box>
+          )}
+        </VStack>
+      </CardBody>
+    </Card>
+  )
+}
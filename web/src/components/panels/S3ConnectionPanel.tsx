@@ -28,6 +28,7 @@ import {
   FiAlertCircle,
   FiArchive,
   FiRefreshCw,
+  FiMap,
 } from 'react-icons/fi'
 import { SiAmazons3 } from 'react-icons/si'
 import { useQuery, useQueryClient } from '@tanstack/react-query'
@@ -89,6 +90,7 @@ interface S3ConnectionPanelProps {
 export default function S3ConnectionPanel({ connectionId }: S3ConnectionPanelProps) {
   const [newBucketName, setNewBucketName] = useState('')
   const [isCreatingBucket, setIsCreatingBucket] = useState(false)
+  const [stacBucket, setStacBucket] = useState<string | null>(null)
   const cardBg = useColorModeValue('white', 'gray.800')
   const openDialog = useUIStore((state) => state.openDialog)
   const toast = useToast()
@@ -150,6 +152,29 @@ export default function S3ConnectionPanel({ connectionId }: S3ConnectionPanelPro
     queryClient.invalidateQueries({ queryKey: ['s3buckets', connectionId] })
   }
 
+  const handleGenerateSTAC = async (bucketName: string) => {
+    setStacBucket(bucketName)
+    try {
+      const result = await api.generateS3STACCatalog(connectionId, bucketName)
+      toast({
+        title: 'STAC catalog generated',
+        description: `${result.itemCount} items written to ${result.catalogUrl}`,
+        status: 'success',
+        duration: 5000,
+      })
+      queryClient.invalidateQueries({ queryKey: ['s3objects', connectionId, bucketName] })
+    } catch (err) {
+      toast({
+        title: 'Failed to generate STAC catalog',
+        description: (err as Error).message,
+        status: 'error',
+        duration: 5000,
+      })
+    } finally {
+      setStacBucket(null)
+    }
+  }
+
   if (loadingConnection) {
     return (
       <Center h="400px">
@@ -370,6 +395,16 @@ export default function S3ConnectionPanel({ connectionId }: S3ConnectionPanelPro
                     >
                       Upload
                     </Button>
+                    <Button
+                      size="sm"
+                      variant="ghost"
+                      colorScheme="teal"
+                      leftIcon={<FiMap />}
+                      onClick={() => handleGenerateSTAC(bucket.name)}
+                      isLoading={stacBucket === bucket.name}
+                    >
+                      Generate STAC
+                    </Button>
                   </HStack>
                 </Box>
               ))}
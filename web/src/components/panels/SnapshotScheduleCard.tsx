@@ -0,0 +1,416 @@
+import { useState } from 'react'
+import {
+  Card,
+  CardBody,
+  VStack,
+  HStack,
+  Box,
+  Text,
+  Heading,
+  Divider,
+  Button,
+  IconButton,
+  Badge,
+  Checkbox,
+  FormControl,
+  FormLabel,
+  FormHelperText,
+  Input,
+  NumberInput,
+  NumberInputField,
+  Select,
+  SimpleGrid,
+  Wrap,
+  WrapItem,
+  useToast,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiArchive, FiPlay, FiPlus, FiRotateCcw, FiTrash2 } from 'react-icons/fi'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { SnapshotRestoreReport, SnapshotSchedule, SnapshotScheduleCreate } from '../../types'
+import { useConnectionStore } from '../../stores/connectionStore'
+
+interface SnapshotScheduleCardProps {
+  connectionId: string
+}
+
+function formatKB(bytes: number): string {
+  return `${(bytes / 1024).toFixed(1)} KB`
+}
+
+function emptySchedule(connectionId: string): SnapshotScheduleCreate {
+  return {
+    name: 'Nightly catalog snapshot',
+    connectionIds: [connectionId],
+    cron: '0 2 * * *',
+    destination: 'local',
+    directory: '',
+    s3ConnectionId: '',
+    bucket: '',
+    prefix: '',
+    includeData: false,
+    keepDaily: 7,
+    keepWeekly: 4,
+    enabled: true,
+  }
+}
+
+// Stored snapshots of a schedule, with a button to restore each onto this connection
+function RestorePicker({
+  schedule,
+  connectionId,
+  readOnly,
+}: {
+  schedule: SnapshotSchedule
+  connectionId: string
+  readOnly: boolean
+}) {
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [report, setReport] = useState<SnapshotRestoreReport | null>(null)
+
+  const { data: snapshots, isLoading, error } = useQuery({
+    queryKey: ['storedSnapshots', schedule.id, connectionId],
+    queryFn: () => api.getStoredSnapshots(schedule.id, connectionId),
+  })
+
+  const restoreMutation = useMutation({
+    mutationFn: (name: string) => api.restoreSnapshot(schedule.id, name, connectionId),
+    onSuccess: (result) => {
+      setReport(result)
+      queryClient.invalidateQueries({ queryKey: ['workspaces', connectionId] })
+    },
+    onError: (err: Error) => {
+      toast({ title: 'Restore failed', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  if (isLoading) return <Text fontSize="sm" color="gray.500">Listing snapshots...</Text>
+  if (error) return <Text fontSize="sm" color="red.500">{(error as Error).message}</Text>
+  if (!snapshots?.length) return <Text fontSize="sm" color="gray.500">No snapshots stored yet.</Text>
+
+  return (
+    <VStack align="stretch" spacing={1}>
+      {snapshots.map((snapshot) => (
+        <HStack key={snapshot.name} justify="space-between">
+          <Text fontSize="sm" fontFamily="mono">
+            {new Date(snapshot.created).toLocaleString()} · {formatKB(snapshot.size)}
+          </Text>
+          {!readOnly && (
+            <Button
+              size="xs"
+              leftIcon={<FiRotateCcw />}
+              onClick={() => restoreMutation.mutate(snapshot.name)}
+              isLoading={restoreMutation.isPending && restoreMutation.variables === snapshot.name}
+              isDisabled={restoreMutation.isPending}
+            >
+              Restore
+            </Button>
+          )}
+        </HStack>
+      ))}
+      {report && (
+        <Box fontSize="sm" pt={2}>
+          <Text>
+            {report.created.length} created, {report.existing.length} already present,{' '}
+            {Object.keys(report.failed).length} failed
+          </Text>
+          {Object.entries(report.failed).map(([path, message]) => (
+            <Text key={path} fontSize="xs" color="red.500">
+              {path}: {message}
+            </Text>
+          ))}
+        </Box>
+      )}
+    </VStack>
+  )
+}
+
+// Scheduled catalog snapshots of the connection, kept under a retention policy, and restoring them
+export default function SnapshotScheduleCard({ connectionId }: SnapshotScheduleCardProps) {
+  const cardBg = useColorModeValue('white', 'gray.800')
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const connections = useConnectionStore((state) => state.connections)
+  const readOnly = connections.some((c) => c.id === connectionId && c.readOnly)
+  const [draft, setDraft] = useState<SnapshotScheduleCreate | null>(null)
+  const [restoring, setRestoring] = useState<string | null>(null)
+
+  const { data: schedules } = useQuery({
+    queryKey: ['snapshotSchedules', connectionId],
+    queryFn: () => api.getSnapshotSchedules(connectionId),
+  })
+
+  const { data: s3Connections } = useQuery({
+    queryKey: ['s3connections'],
+    queryFn: () => api.getS3Connections(),
+    enabled: draft?.destination === 's3',
+  })
+
+  const refresh = () => queryClient.invalidateQueries({ queryKey: ['snapshotSchedules'] })
+  const onError = (title: string) => (err: Error) => {
+    toast({ title, description: err.message, status: 'error', duration: 5000 })
+  }
+
+  const createMutation = useMutation({
+    mutationFn: (schedule: SnapshotScheduleCreate) => api.createSnapshotSchedule(schedule),
+    onSuccess: () => {
+      setDraft(null)
+      refresh()
+    },
+    onError: onError('Could not save the schedule'),
+  })
+
+  const runMutation = useMutation({
+    mutationFn: (id: string) => api.runSnapshotSchedule(id),
+    onSuccess: (schedule) => {
+      refresh()
+      queryClient.invalidateQueries({ queryKey: ['storedSnapshots', schedule.id] })
+      toast({
+        title: schedule.lastStatus === 'success' ? 'Snapshot stored' : 'Snapshot failed',
+        description: schedule.lastError || undefined,
+        status: schedule.lastStatus === 'success' ? 'success' : 'error',
+        duration: 5000,
+      })
+    },
+    onError: onError('Could not run the schedule'),
+  })
+
+  const deleteMutation = useMutation({
+    mutationFn: (id: string) => api.deleteSnapshotSchedule(id),
+    onSuccess: refresh,
+    onError: onError('Could not delete the schedule'),
+  })
+
+  const update = (changes: Partial<SnapshotScheduleCreate>) =>
+    setDraft((current) => (current ? { ...current, ...changes } : current))
+
+  return (
+    <Card bg={cardBg}>
+      <CardBody>
+        <VStack align="stretch" spacing={4}>
+          <HStack justify="space-between">
+            <Heading size="sm" color="gray.600">Scheduled Snapshots</Heading>
+            {!draft && (
+              <Button size="xs" leftIcon={<FiPlus />} onClick={() => setDraft(emptySchedule(connectionId))}>
+                New Schedule
+              </Button>
+            )}
+          </HStack>
+          <Divider />
+          <Text fontSize="sm" color="gray.600">
+            Snapshot the whole catalog on a schedule into a folder or bucket, keeping the newest
+            snapshot of each recent day and week, and restore resources from any of them.
+          </Text>
+
+          {schedules?.length === 0 && !draft && (
+            <Text fontSize="sm" color="gray.500">No snapshots are scheduled for this connection.</Text>
+          )}
+
+          {schedules?.map((schedule) => (
+            <Box key={schedule.id} borderWidth="1px" borderRadius="md" p={3}>
+              <HStack justify="space-between" align="start">
+                <VStack align="start" spacing={0}>
+                  <HStack>
+                    <Text fontWeight="medium" fontSize="sm">{schedule.name}</Text>
+                    {!schedule.enabled && <Badge>Disabled</Badge>}
+                    {schedule.lastStatus && (
+                      <Badge colorScheme={schedule.lastStatus === 'success' ? 'green' : 'red'}>
+                        {schedule.lastStatus}
+                      </Badge>
+                    )}
+                  </HStack>
+                  <Text fontSize="xs" color="gray.500" fontFamily="mono">
+                    {schedule.cron} →{' '}
+                    {schedule.destination === 's3'
+                      ? `s3://${schedule.bucket}/${schedule.prefix}`
+                      : schedule.directory}
+                  </Text>
+                  <Text fontSize="xs" color="gray.500">
+                    Keeps {schedule.keepDaily} dailies and {schedule.keepWeekly} weeklies
+                    {schedule.nextRunAt && `; next at ${new Date(schedule.nextRunAt).toLocaleString()}`}
+                  </Text>
+                  {schedule.lastError && (
+                    <Text fontSize="xs" color="red.500">{schedule.lastError}</Text>
+                  )}
+                </VStack>
+                <HStack spacing={1}>
+                  <IconButton
+                    aria-label="Restore from a snapshot"
+                    icon={<FiArchive />}
+                    size="xs"
+                    variant={restoring === schedule.id ? 'solid' : 'ghost'}
+                    onClick={() => setRestoring(restoring === schedule.id ? null : schedule.id)}
+                  />
+                  <IconButton
+                    aria-label="Snapshot now"
+                    icon={<FiPlay />}
+                    size="xs"
+                    variant="ghost"
+                    onClick={() => runMutation.mutate(schedule.id)}
+                    isLoading={runMutation.isPending && runMutation.variables === schedule.id}
+                  />
+                  <IconButton
+                    aria-label="Delete schedule"
+                    icon={<FiTrash2 />}
+                    size="xs"
+                    variant="ghost"
+                    colorScheme="red"
+                    onClick={() => deleteMutation.mutate(schedule.id)}
+                  />
+                </HStack>
+              </HStack>
+              {restoring === schedule.id && (
+                <Box pt={3}>
+                  <RestorePicker schedule={schedule} connectionId={connectionId} readOnly={readOnly} />
+                </Box>
+              )}
+            </Box>
+          ))}
+
+          {draft && (
+            <VStack align="stretch" spacing={3} borderWidth="1px" borderRadius="md" p={3}>
+              <SimpleGrid columns={{ base: 1, md: 2 }} spacing={3}>
+                <FormControl>
+                  <FormLabel fontSize="sm">Name</FormLabel>
+                  <Input size="sm" value={draft.name} onChange={(e) => update({ name: e.target.value })} />
+                </FormControl>
+                <FormControl>
+                  <FormLabel fontSize="sm">Schedule</FormLabel>
+                  <Input
+                    size="sm"
+                    fontFamily="mono"
+                    value={draft.cron}
+                    onChange={(e) => update({ cron: e.target.value })}
+                  />
+                  <FormHelperText>Cron expression; nightly at 2am by default</FormHelperText>
+                </FormControl>
+                <FormControl>
+                  <FormLabel fontSize="sm">Store In</FormLabel>
+                  <Select
+                    size="sm"
+                    value={draft.destination}
+                    onChange={(e) => update({ destination: e.target.value as 'local' | 's3' })}
+                  >
+                    <option value="local">Local folder</option>
+                    <option value="s3">S3 bucket</option>
+                  </Select>
+                </FormControl>
+                {draft.destination === 'local' ? (
+                  <FormControl>
+                    <FormLabel fontSize="sm">Folder</FormLabel>
+                    <Input
+                      size="sm"
+                      fontFamily="mono"
+                      value={draft.directory}
+                      onChange={(e) => update({ directory: e.target.value })}
+                      placeholder="~/geoserver-snapshots"
+                    />
+                  </FormControl>
+                ) : (
+                  <FormControl>
+                    <FormLabel fontSize="sm">S3 Connection</FormLabel>
+                    <Select
+                      size="sm"
+                      value={draft.s3ConnectionId}
+                      onChange={(e) => update({ s3ConnectionId: e.target.value })}
+                      placeholder="Choose a connection"
+                    >
+                      {s3Connections?.map((conn) => (
+                        <option key={conn.id} value={conn.id}>{conn.name}</option>
+                      ))}
+                    </Select>
+                  </FormControl>
+                )}
+                {draft.destination === 's3' && (
+                  <>
+                    <FormControl>
+                      <FormLabel fontSize="sm">Bucket</FormLabel>
+                      <Input size="sm" value={draft.bucket} onChange={(e) => update({ bucket: e.target.value })} />
+                    </FormControl>
+                    <FormControl>
+                      <FormLabel fontSize="sm">Prefix</FormLabel>
+                      <Input
+                        size="sm"
+                        fontFamily="mono"
+                        value={draft.prefix}
+                        onChange={(e) => update({ prefix: e.target.value })}
+                        placeholder="geoserver/snapshots"
+                      />
+                    </FormControl>
+                  </>
+                )}
+                <FormControl>
+                  <FormLabel fontSize="sm">Daily Snapshots Kept</FormLabel>
+                  <NumberInput
+                    size="sm"
+                    min={1}
+                    value={draft.keepDaily}
+                    onChange={(_, value) => update({ keepDaily: Number.isNaN(value) ? 1 : value })}
+                  >
+                    <NumberInputField />
+                  </NumberInput>
+                </FormControl>
+                <FormControl>
+                  <FormLabel fontSize="sm">Weekly Snapshots Kept</FormLabel>
+                  <NumberInput
+                    size="sm"
+                    min={0}
+                    value={draft.keepWeekly}
+                    onChange={(_, value) => update({ keepWeekly: Number.isNaN(value) ? 0 : value })}
+                  >
+                    <NumberInputField />
+                  </NumberInput>
+                </FormControl>
+              </SimpleGrid>
+              <FormControl>
+                <FormLabel fontSize="sm">Connections</FormLabel>
+                <Wrap spacing={3}>
+                  {connections.map((conn) => (
+                    <WrapItem key={conn.id}>
+                      <Checkbox
+                        size="sm"
+                        isChecked={draft.connectionIds.includes(conn.id)}
+                        onChange={(e) =>
+                          update({
+                            connectionIds: e.target.checked
+                              ? [...draft.connectionIds, conn.id]
+                              : draft.connectionIds.filter((id) => id !== conn.id),
+                          })
+                        }
+                      >
+                        {conn.name}
+                      </Checkbox>
+                    </WrapItem>
+                  ))}
+                </Wrap>
+              </FormControl>
+              <Checkbox
+                size="sm"
+                isChecked={draft.includeData}
+                onChange={(e) => update({ includeData: e.target.checked })}
+              >
+                Include layer data (shapefiles and GeoTIFFs)
+              </Checkbox>
+              <HStack justify="flex-end">
+                <Button size="sm" variant="ghost" onClick={() => setDraft(null)}>
+                  Cancel
+                </Button>
+                <Button
+                  size="sm"
+                  colorScheme="kartoza"
+                  onClick={() => createMutation.mutate(draft)}
+                  isLoading={createMutation.isPending}
+                  isDisabled={!draft.name.trim() || draft.connectionIds.length === 0}
+                >
+                  Save Schedule
+                </Button>
+              </HStack>
+            </VStack>
+          )}
+        </VStack>
+      </CardBody>
+    </Card>
+  )
+}
@@ -0,0 +1,155 @@
+import { useState, useEffect } from 'react'
+import {
+  Card,
+  CardBody,
+  HStack,
+  Box,
+  Text,
+  Button,
+  FormControl,
+  FormLabel,
+  FormHelperText,
+  Input,
+  Select,
+  SimpleGrid,
+  Slider,
+  SliderTrack,
+  SliderFilledTrack,
+  SliderThumb,
+  Spacer,
+  Spinner,
+  Switch,
+  useToast,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { WmsWatermark } from '../../types'
+import { useConnectionStore } from '../../stores/connectionStore'
+
+interface WmsWatermarkCardProps {
+  connectionId: string
+}
+
+// Image the WMS service draws on every map, e.g. a data provider's logo
+export default function WmsWatermarkCard({ connectionId }: WmsWatermarkCardProps) {
+  const cardBg = useColorModeValue('white', 'gray.800')
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [form, setForm] = useState<WmsWatermark | null>(null)
+  const readOnly = useConnectionStore((state) =>
+    state.connections.some((c) => c.id === connectionId && c.readOnly)
+  )
+
+  const { data, isLoading, error } = useQuery({
+    queryKey: ['wmsWatermark', connectionId],
+    queryFn: () => api.getWmsWatermark(connectionId),
+  })
+
+  useEffect(() => {
+    if (data) {
+      const { positions: _positions, ...watermark } = data
+      setForm(watermark)
+    }
+  }, [data])
+
+  const saveMutation = useMutation({
+    mutationFn: (watermark: WmsWatermark) => api.updateWmsWatermark(connectionId, watermark),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['wmsWatermark', connectionId] })
+      toast({ title: 'Watermark saved', status: 'success', duration: 3000 })
+    },
+    onError: (err: Error) => {
+      toast({ title: 'Failed to save watermark', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  const set = <K extends keyof WmsWatermark>(field: K, value: WmsWatermark[K]) => {
+    setForm((current) => (current ? { ...current, [field]: value } : current))
+  }
+
+  return (
+    <Card bg={cardBg}>
+      <CardBody>
+        <HStack mb={form ? 3 : 0}>
+          <Box>
+            <Text fontWeight="semibold">WMS Watermark</Text>
+            <Text fontSize="sm" color="gray.500">
+              An image drawn on every map the WMS service renders
+            </Text>
+          </Box>
+          <Spacer />
+          {form && (
+            <Switch
+              isChecked={form.enabled}
+              isDisabled={readOnly}
+              onChange={(e) => set('enabled', e.target.checked)}
+            />
+          )}
+        </HStack>
+
+        {isLoading && <Spinner size="sm" />}
+
+        {error && (
+          <Text fontSize="sm" color="red.500">
+            {(error as Error).message}
+          </Text>
+        )}
+
+        {form && data && (
+          <>
+            <SimpleGrid columns={{ base: 1, md: 2 }} spacing={4}>
+              <FormControl isDisabled={readOnly}>
+                <FormLabel fontSize="sm">Image</FormLabel>
+                <Input
+                  size="sm"
+                  value={form.url ?? ''}
+                  onChange={(e) => set('url', e.target.value || null)}
+                  placeholder="https://example.com/logo.png"
+                />
+                <FormHelperText>A URL, or a path in the data directory</FormHelperText>
+              </FormControl>
+              <FormControl isDisabled={readOnly}>
+                <FormLabel fontSize="sm">Position</FormLabel>
+                <Select size="sm" value={form.position} onChange={(e) => set('position', e.target.value)}>
+                  {data.positions.map((position) => (
+                    <option key={position} value={position}>
+                      {position.replace('BOT', 'BOTTOM').replace('MID', 'MIDDLE').replace('_', ' ').toLowerCase()}
+                    </option>
+                  ))}
+                </Select>
+              </FormControl>
+              <FormControl isDisabled={readOnly}>
+                <FormLabel fontSize="sm">Transparency: {form.transparency}%</FormLabel>
+                <Slider
+                  min={0}
+                  max={100}
+                  value={form.transparency}
+                  onChange={(value) => set('transparency', value)}
+                >
+                  <SliderTrack>
+                    <SliderFilledTrack />
+                  </SliderTrack>
+                  <SliderThumb />
+                </Slider>
+              </FormControl>
+            </SimpleGrid>
+            {!readOnly && (
+              <HStack mt={3}>
+                <Spacer />
+                <Button
+                  size="sm"
+                  colorScheme="kartoza"
+                  onClick={() => saveMutation.mutate(form)}
+                  isLoading={saveMutation.isPending}
+                >
+                  Save Watermark
+                </Button>
+              </HStack>
+            )}
+          </>
+        )}
+      </CardBody>
+    </Card>
+  )
+}
@@ -0,0 +1,218 @@
+import { useState, useEffect } from 'react'
+import {
+  Card,
+  CardBody,
+  VStack,
+  HStack,
+  Heading,
+  Divider,
+  Text,
+  Input,
+  FormControl,
+  FormLabel,
+  FormHelperText,
+  Checkbox,
+  Button,
+  ButtonGroup,
+  Badge,
+  Link,
+  Alert,
+  AlertIcon,
+  useToast,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiArchive, FiDownload, FiRotateCcw, FiX } from 'react-icons/fi'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { BackupExecution, BackupKind } from '../../types'
+import { useConnectionStore } from '../../stores/connectionStore'
+import TypedConfirmation, { confirmationMatches, useTypedConfirmation } from '../common/TypedConfirmation'
+
+interface WorkspaceBackupCardProps {
+  connectionId: string
+  workspace: string
+}
+
+// Server-side backup and restore of one workspace through the Backup and Restore plugin
+export default function WorkspaceBackupCard({ connectionId, workspace }: WorkspaceBackupCardProps) {
+  const cardBg = useColorModeValue('white', 'gray.800')
+  const toast = useToast()
+  const queryClient = useQueryClient()
+  const [kind, setKind] = useState<BackupKind>('backup')
+  const [archiveFile, setArchiveFile] = useState('')
+  const [options, setOptions] = useState<Record<string, boolean>>({})
+  const [execution, setExecution] = useState<BackupExecution | null>(null)
+  const [typed, setTyped] = useState('')
+  const needsTyping = useTypedConfirmation([connectionId])
+  // Starting a job is a POST, which read-only connections refuse
+  const readOnly = useConnectionStore((state) =>
+    state.connections.some((c) => c.id === connectionId && c.readOnly)
+  )
+
+  const { data: capabilities } = useQuery({
+    queryKey: ['serverCapabilities', connectionId],
+    queryFn: () => api.getServerCapabilities(connectionId),
+    staleTime: 5 * 60 * 1000,
+  })
+  const supported = capabilities?.features.find((f) => f.name === 'backup_restore')?.supported
+
+  const { data: jobOptions } = useQuery({
+    queryKey: ['backupJobOptions', connectionId, kind, workspace],
+    queryFn: () => api.getBackupJobOptions(connectionId, kind, [workspace]),
+    enabled: !!supported && !readOnly,
+  })
+
+  useEffect(() => {
+    if (jobOptions && kind === 'backup') setArchiveFile(jobOptions.archiveFile)
+    setOptions(kind === 'restore' ? { BK_DRY_RUN: true } : {})
+    setTyped('')
+  }, [jobOptions, kind])
+
+  // Follow the running job until it stops
+  const { data: polled } = useQuery({
+    queryKey: ['backupExecution', connectionId, execution?.kind, execution?.id],
+    queryFn: () => api.getBackupExecution(connectionId, execution!.kind, execution!.id),
+    enabled: !!execution && !execution.finished,
+    refetchInterval: 2000,
+  })
+  const current = polled ?? execution
+
+  useEffect(() => {
+    if (!polled?.finished) return
+    toast({
+      title: polled.succeeded ? `${polled.kind === 'backup' ? 'Backup' : 'Restore'} finished` : polled.status,
+      description: polled.errors.join('\n') || undefined,
+      status: polled.succeeded ? 'success' : 'error',
+      duration: 5000,
+    })
+    if (polled.succeeded && polled.kind === 'restore' && !options.BK_DRY_RUN) {
+      queryClient.invalidateQueries({ queryKey: ['workspaces', connectionId] })
+    }
+  }, [polled?.finished])
+
+  const startMutation = useMutation({
+    mutationFn: () =>
+      api.startBackupJob(connectionId, kind, { archiveFile, workspaces: [workspace], options }),
+    onSuccess: (started) => setExecution(started),
+    onError: (err: Error) => {
+      toast({ title: `Could not start ${kind}`, description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  const cancelMutation = useMutation({
+    mutationFn: () => api.cancelBackupJob(connectionId, current!.kind, current!.id),
+    onError: (err: Error) => {
+      toast({ title: 'Could not stop the job', description: err.message, status: 'error', duration: 5000 })
+    },
+  })
+
+  if (!supported || readOnly) return null
+
+  const running = !!current && !current.finished
+  const confirming = kind === 'restore' && !options.BK_DRY_RUN && needsTyping
+  const canStart =
+    archiveFile.trim() !== '' && !running && (!confirming || confirmationMatches(workspace, typed))
+
+  return (
+    <Card bg={cardBg}>
+      <CardBody>
+        <VStack align="stretch" spacing={4}>
+          <HStack justify="space-between">
+            <Heading size="sm" color="gray.600">Server-side Backup</Heading>
+            <ButtonGroup size="xs" isAttached variant="outline">
+              <Button isActive={kind === 'backup'} onClick={() => setKind('backup')} isDisabled={running}>
+                Backup
+              </Button>
+              <Button isActive={kind === 'restore'} onClick={() => setKind('restore')} isDisabled={running}>
+                Restore
+              </Button>
+            </ButtonGroup>
+          </HStack>
+          <Divider />
+
+          <FormControl>
+            <FormLabel fontSize="sm">Archive on the server</FormLabel>
+            <Input
+              value={archiveFile}
+              onChange={(e) => setArchiveFile(e.target.value)}
+              placeholder="/path/to/backup.zip"
+              fontFamily="mono"
+              size="sm"
+            />
+            <FormHelperText>
+              {kind === 'backup'
+                ? 'The Backup and Restore plugin writes the workspace, its stores, layers and styles here.'
+                : `Only the ${workspace} workspace is restored from the archive.`}
+            </FormHelperText>
+          </FormControl>
+
+          <VStack align="start" spacing={1}>
+            {jobOptions?.options.map((option) => (
+              <Checkbox
+                key={option.name}
+                size="sm"
+                isChecked={!!options[option.name]}
+                onChange={(e) => setOptions({ ...options, [option.name]: e.target.checked })}
+              >
+                {option.description}
+              </Checkbox>
+            ))}
+          </VStack>
+
+          {confirming && <TypedConfirmation expected={workspace} value={typed} onChange={setTyped} />}
+
+          {current && (
+            <Alert
+              status={running ? 'info' : current.succeeded ? 'success' : 'error'}
+              borderRadius="md"
+              fontSize="sm"
+            >
+              <AlertIcon />
+              <VStack align="start" spacing={1} flex={1}>
+                <HStack>
+                  <Badge>{current.status}</Badge>
+                  {current.progress && <Text>Step {current.progress}</Text>}
+                </HStack>
+                {current.errors.map((error) => (
+                  <Text key={error}>{error}</Text>
+                ))}
+                {current.kind === 'backup' && current.succeeded && (
+                  <Link href={api.getBackupArchiveUrl(connectionId, current.id)} isExternal>
+                    <HStack spacing={1}>
+                      <FiDownload />
+                      <Text>Download archive</Text>
+                    </HStack>
+                  </Link>
+                )}
+              </VStack>
+            </Alert>
+          )}
+
+          <HStack>
+            <Button
+              variant="accent"
+              size="sm"
+              leftIcon={kind === 'backup' ? <FiArchive /> : <FiRotateCcw />}
+              onClick={() => startMutation.mutate()}
+              isLoading={startMutation.isPending || running}
+              isDisabled={!canStart}
+            >
+              {kind === 'backup' ? 'Back Up Workspace' : 'Restore Workspace'}
+            </Button>
+            {running && (
+              <Button
+                size="sm"
+                variant="ghost"
+                leftIcon={<FiX />}
+                onClick={() => cancelMutation.mutate()}
+                isLoading={cancelMutation.isPending}
+              >
+                Stop
+              </Button>
+            )}
+          </HStack>
+        </VStack>
+      </CardBody>
+    </Card>
+  )
+}
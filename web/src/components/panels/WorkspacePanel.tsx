@@ -21,6 +21,7 @@ import { FiFolder, FiDatabase, FiImage, FiLayers, FiUpload, FiPlus } from 'react
 import { useQuery } from '@tanstack/react-query'
 import * as api from '../../api'
 import { useUIStore } from '../../stores/uiStore'
+import WorkspaceBackupCard from './WorkspaceBackupCard'
 
 interface WorkspacePanelProps {
   connectionId: string
@@ -178,6 +179,9 @@ export default function WorkspacePanel({
           </CardBody>
         </Card>
       )}
+
+      {/* Backup and Restore plugin */}
+      <WorkspaceBackupCard connectionId={connectionId} workspace={workspace} />
     </VStack>
   )
 }
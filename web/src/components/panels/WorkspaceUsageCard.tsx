@@ -0,0 +1,182 @@
+import { useState } from 'react'
+import {
+  Card,
+  CardBody,
+  HStack,
+  Box,
+  Text,
+  Button,
+  Spacer,
+  Table,
+  Thead,
+  Tbody,
+  Tr,
+  Th,
+  Td,
+  Icon,
+  Tooltip,
+  VStack,
+  useColorModeValue,
+} from '@chakra-ui/react'
+import { FiArrowDown, FiArrowUp, FiDownload, FiHardDrive } from 'react-icons/fi'
+import { useQuery } from '@tanstack/react-query'
+import * as api from '../../api'
+import type { WorkspaceUsage } from '../../types'
+
+interface WorkspaceUsageCardProps {
+  connectionId: string
+}
+
+type SortKey = 'workspace' | 'tileCacheBytes' | 'storeBytes' | 'totalBytes'
+
+const COLUMNS: { key: SortKey; label: string; numeric: boolean }[] = [
+  { key: 'workspace', label: 'Workspace', numeric: false },
+  { key: 'tileCacheBytes', label: 'Tile Cache', numeric: true },
+  { key: 'storeBytes', label: 'Stores', numeric: true },
+  { key: 'totalBytes', label: 'Total', numeric: true },
+]
+
+function formatBytes(bytes: number): string {
+  if (bytes === 0) return '0 B'
+  const k = 1024
+  const sizes = ['B', 'KB', 'MB', 'GB', 'TB']
+  const i = Math.floor(Math.log(bytes) / Math.log(k))
+  return parseFloat((bytes / Math.pow(k, i)).toFixed(1)) + ' ' + sizes[i]
+}
+
+function compare(a: WorkspaceUsage, b: WorkspaceUsage, key: SortKey): number {
+  return key === 'workspace' ? a.workspace.localeCompare(b.workspace) : a[key] - b[key]
+}
+
+// Per-workspace tile cache and store sizes, measured on demand, for chargeback on shared servers
+export default function WorkspaceUsageCard({ connectionId }: WorkspaceUsageCardProps) {
+  const cardBg = useColorModeValue('white', 'gray.800')
+  const [sort, setSort] = useState<SortKey>('totalBytes')
+  const [descending, setDescending] = useState(true)
+
+  const { data, error, isFetching, refetch } = useQuery({
+    queryKey: ['workspaceUsage', connectionId],
+    queryFn: () => api.getWorkspaceUsage(connectionId),
+    enabled: false,
+    retry: false,
+  })
+
+  const handleSort = (key: SortKey) => {
+    if (sort === key) {
+      setDescending(!descending)
+    } else {
+      setSort(key)
+      // Sizes read best largest first, names from A
+      setDescending(key !== 'workspace')
+    }
+  }
+
+  const rows = [...(data?.workspaces ?? [])].sort(
+    (a, b) => compare(a, b, sort) * (descending ? -1 : 1)
+  )
+  const notes = [
+    data?.tileCacheError && `Tile caches not counted: ${data.tileCacheError}`,
+    data?.storeError && `Stores not sized: ${data.storeError}`,
+    ...(data?.errors ?? []).map((e) => `Not sized: ${e}`),
+  ].filter(Boolean) as string[]
+
+  return (
+    <Card bg={cardBg}>
+      <CardBody>
+        <HStack mb={data || error ? 3 : 0}>
+          <Box>
+            <Text fontWeight="semibold">Workspace Usage</Text>
+            <Text fontSize="sm" color="gray.500">
+              Tile cache and store sizes per workspace
+            </Text>
+          </Box>
+          <Spacer />
+          {data && (
+            <Button
+              as="a"
+              href={api.getWorkspaceUsageCsvUrl(connectionId)}
+              size="sm"
+              variant="ghost"
+              leftIcon={<FiDownload />}
+            >
+              CSV
+            </Button>
+          )}
+          <Button
+            size="sm"
+            variant="outline"
+            leftIcon={<FiHardDrive />}
+            onClick={() => refetch()}
+            isLoading={isFetching}
+            loadingText="Measuring"
+          >
+            Measure
+          </Button>
+        </HStack>
+
+        {error && (
+          <Text fontSize="sm" color="red.500">
+            {(error as Error).message}
+          </Text>
+        )}
+
+        {data && (
+          <VStack align="stretch" spacing={3}>
+            <Table size="sm">
+              <Thead>
+                <Tr>
+                  {COLUMNS.map((column) => (
+                    <Th
+                      key={column.key}
+                      isNumeric={column.numeric}
+                      cursor="pointer"
+                      onClick={() => handleSort(column.key)}
+                    >
+                      <HStack spacing={1} justify={column.numeric ? 'flex-end' : 'flex-start'}>
+                        <Text>{column.label}</Text>
+                        {sort === column.key && (
+                          <Icon as={descending ? FiArrowDown : FiArrowUp} />
+                        )}
+                      </HStack>
+                    </Th>
+                  ))}
+                </Tr>
+              </Thead>
+              <Tbody>
+                {rows.map((usage) => (
+                  <Tr key={usage.workspace}>
+                    <Td>{usage.workspace || <Text as="i">global layer groups</Text>}</Td>
+                    <Td isNumeric>
+                      <Tooltip label={`${usage.cachedLayers} cached layers`}>
+                        {formatBytes(usage.tileCacheBytes)}
+                      </Tooltip>
+                    </Td>
+                    <Td isNumeric>
+                      <Tooltip
+                        label={`${usage.sizedStores} sized, ${usage.externalStores} outside the data directory`}
+                      >
+                        {`${usage.complete ? '' : '≥ '}${formatBytes(usage.storeBytes)}`}
+                      </Tooltip>
+                    </Td>
+                    <Td isNumeric fontWeight="semibold">
+                      {formatBytes(usage.totalBytes)}
+                    </Td>
+                  </Tr>
+                ))}
+              </Tbody>
+            </Table>
+            <Text fontSize="sm" color="gray.500">
+              {formatBytes(data.totalBytes)} across {data.workspaces.length} workspaces. PostGIS
+              and other stores outside the data directory aren't sized.
+            </Text>
+            {notes.map((note) => (
+              <Text key={note} fontSize="sm" color="orange.500">
+                {note}
+              </Text>
+            ))}
+          </VStack>
+        )}
+      </CardBody>
+    </Card>
+  )
+}
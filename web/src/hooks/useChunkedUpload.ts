@@ -3,11 +3,12 @@ import {
   initUploadSession,
   uploadChunk,
   completeUpload,
+  inspectUpload,
   cancelUpload,
   getGeoServerProgress,
   CHUNK_SIZE,
 } from '../api/chunkedUpload'
-import type { UploadResult } from '../types'
+import type { CogAdvice, LayerMetadata, UploadResult } from '../types'
 
 export type UploadStatus = 'idle' | 'uploading' | 'paused' | 'completed' | 'error' | 'cancelled'
 
@@ -25,9 +26,24 @@ export interface ChunkUploadState {
   error?: string
 }
 
+// Asked whether to convert a GeoTIFF to a COG before it's published
+export type CogPrompt = (file: File, advice: CogAdvice) => Promise<boolean>
+
+export interface UploadOptions {
+  storeName?: string // Existing or new store; named after the file when empty
+  onCogAdvice?: CogPrompt
+  verify?: boolean // Compare checksums with the files GeoServer stored
+  metadata?: LayerMetadata // Of the layer a new store publishes; blanks are suggested
+}
+
 export interface UseChunkedUpload {
   state: ChunkUploadState
-  start: (connId: string, workspace: string, file: File) => Promise<UploadResult>
+  start: (
+    connId: string,
+    workspace: string,
+    file: File,
+    options?: UploadOptions
+  ) => Promise<UploadResult>
   pause: () => void
   resume: () => void
   cancel: () => Promise<void>
@@ -98,7 +114,13 @@ export function useChunkedUpload(): UseChunkedUpload {
   }, [])
 
   const start = useCallback(
-    async (connId: string, workspace: string, file: File): Promise<UploadResult> => {
+    async (
+      connId: string,
+      workspace: string,
+      file: File,
+      options: UploadOptions = {}
+    ): Promise<UploadResult> => {
+      const { storeName, onCogAdvice, verify, metadata } = options
       isPaused.current = false
       isCancelled.current = false
       sessionIdRef.current = null
@@ -119,6 +141,7 @@ export function useChunkedUpload(): UseChunkedUpload {
         file.name,
         totalBytes,
         chunkSize,
+        storeName,
       )
       sessionIdRef.current = sessionId
 
@@ -168,6 +191,16 @@ export function useChunkedUpload(): UseChunkedUpload {
         throw new Error('Upload cancelled')
       }
 
+      // Offer to convert GeoTIFFs without tiles or overviews, unless they're
+      // over the size the server converts without asking
+      let convertToCog: boolean | undefined
+      if (onCogAdvice && /\.tiff?$/i.test(file.name)) {
+        const advice = await inspectUpload(sessionId)
+        if (advice?.needsConversion && advice.gdalAvailable && !advice.autoConvert) {
+          convertToCog = await onCogAdvice(file, advice)
+        }
+      }
+
       let pollHandle: ReturnType<typeof setInterval> | null = setInterval(async () => {
         try {
           const p = await getGeoServerProgress(sessionId)
@@ -186,7 +219,7 @@ export function useChunkedUpload(): UseChunkedUpload {
 
       let result
       try {
-        result = await completeUpload(sessionId)
+        result = await completeUpload(sessionId, convertToCog, verify, metadata)
       } finally {
         if (pollHandle) {
           clearInterval(pollHandle)
@@ -0,0 +1,36 @@
+import { useEffect, useState } from 'react'
+import { useQuery } from '@tanstack/react-query'
+import { checkName } from '../api/naming'
+import type { NamingResourceType } from '../types'
+
+// Wait for typing to pause before asking the server
+const CHECK_DELAY_MS = 300
+
+/**
+ * Check a name being typed against the configured naming conventions.
+ *
+ * Returns how the name breaks them; empty while the name is blank, being
+ * typed or fine. The server checks again when the resource is created.
+ */
+export function useNameCheck(
+  resourceType: NamingResourceType,
+  name: string,
+  workspace?: string,
+  enabled = true
+): string[] {
+  const [settled, setSettled] = useState(name)
+
+  useEffect(() => {
+    const timer = setTimeout(() => setSettled(name.trim()), CHECK_DELAY_MS)
+    return () => clearTimeout(timer)
+  }, [name])
+
+  const { data } = useQuery({
+    queryKey: ['nameCheck', resourceType, workspace ?? '', settled],
+    queryFn: () => checkName(resourceType, settled, workspace),
+    enabled: enabled && !!settled,
+    staleTime: 60_000,
+  })
+
+  return enabled && settled && settled === name.trim() ? data?.problems ?? [] : []
+}
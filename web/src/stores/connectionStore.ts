@@ -1,5 +1,5 @@
 import { create } from 'zustand'
-import type { Connection } from '../types'
+import type { Connection, ConnectionCreate } from '../types'
 import * as api from '../api'
 import type { PGService } from '../api'
 
@@ -12,8 +12,8 @@ interface ConnectionState {
 
   // Actions
   fetchConnections: () => Promise<void>
-  addConnection: (conn: { name: string; url: string; username: string; password: string }) => Promise<Connection>
-  updateConnection: (id: string, conn: Partial<{ name: string; url: string; username: string; password: string }>) => Promise<void>
+  addConnection: (conn: ConnectionCreate) => Promise<Connection>
+  updateConnection: (id: string, conn: Partial<ConnectionCreate>) => Promise<void>
   removeConnection: (id: string) => Promise<void>
   setActiveConnection: (id: string | null) => void
   testConnection: (id: string) => Promise<{ success: boolean; message: string }>
@@ -5,6 +5,8 @@ import { setNodeUrlParam, clearNodeUrlParam, getParentNodeIds } from '../utils/n
 interface TreeState {
   expandedNodes: Set<string>
   selectedNode: TreeNode | null
+  // Nodes marked for a batch action, by node ID
+  markedNodes: Record<string, TreeNode>
 
   // Actions
   toggleNode: (nodeId: string) => void
@@ -14,12 +16,15 @@ interface TreeState {
   restoreNode: (node: TreeNode) => void
   isExpanded: (nodeId: string) => boolean
   clearSelection: () => void
+  toggleMark: (node: TreeNode) => void
+  clearMarks: () => void
   reset: () => void
 }
 
 export const useTreeStore = create<TreeState>((set, get) => ({
   expandedNodes: new Set<string>(),
   selectedNode: null,
+  markedNodes: {},
 
   toggleNode: (nodeId: string) => {
     set(state => {
@@ -78,8 +83,24 @@ export const useTreeStore = create<TreeState>((set, get) => ({
     clearNodeUrlParam()
   },
 
+  toggleMark: (node: TreeNode) => {
+    set(state => {
+      const newMarked = { ...state.markedNodes }
+      if (newMarked[node.id]) {
+        delete newMarked[node.id]
+      } else {
+        newMarked[node.id] = node
+      }
+      return { markedNodes: newMarked }
+    })
+  },
+
+  clearMarks: () => {
+    set({ markedNodes: {} })
+  },
+
   reset: () => {
-    set({ expandedNodes: new Set(), selectedNode: null })
+    set({ expandedNodes: new Set(), selectedNode: null, markedNodes: {} })
   },
 }))
 
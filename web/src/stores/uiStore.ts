@@ -16,7 +16,12 @@ export type DialogType =
   | 'layer'
   | 'layergroup'
   | 'style'
+  | 'styleimport'
+  | 'styleassets'
   | 'upload'
+  | 'batchupload'
+  | 'batch'
+  | 'snapshot'
   | 'confirm'
   | 'info'
   | 'sync'
@@ -30,6 +35,8 @@ export type DialogType =
   | 'pointcloud'
   | 'qgisproject'
   | 'qgispreview'
+  | 'qgispublish'
+  | 'mapexport'
   | 'geonode'
   | 'geonodeupload'
   | 'icebergconnection'
@@ -40,6 +47,8 @@ export type DialogType =
   | 'icebergquery'
   | 'qfieldcloud'
   | 'merginmaps'
+  | 'compare'
+  | 'attributes'
   | null
 
 export type DialogMode = 'create' | 'edit' | 'delete' | 'view'
@@ -16,6 +16,7 @@ export const handlers = [
       theme: 'default',
       pingIntervalSecs: 60,
       lastLocalPath: '/home/user',
+      timeouts: { upload: 300, download: 120, ows: 60 },
     })
   }),
 
@@ -25,6 +26,7 @@ export const handlers = [
       theme: body.theme ?? 'default',
       pingIntervalSecs: body.pingIntervalSecs ?? 60,
       lastLocalPath: body.lastLocalPath ?? '/home/user',
+      timeouts: body.timeouts ?? { upload: 300, download: 120, ows: 60 },
     })
   }),
 
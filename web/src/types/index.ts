@@ -1,4 +1,13 @@
 // Connection types
+// How destructive operations are confirmed; 'auto' types on production connections
+export type ConfirmPolicy = 'auto' | 'simple' | 'typed'
+
+// How the REST API is spoken; 'auto' is JSON, switching to XML when JSON can't be read
+export type RestFormat = 'auto' | 'json' | 'xml'
+
+// 'oidc' sends a bearer token from an OpenID Connect provider instead of a password
+export type AuthType = 'basic' | 'oidc'
+
 export interface Connection {
   id: string
   name: string
@@ -6,6 +15,34 @@ export interface Connection {
   username: string
   password: string
   isActive: boolean
+  autoTruncateCache?: boolean
+  maxConcurrentRequests?: number
+  maxRequestsPerSecond?: number
+  poolMaxIdle?: number
+  poolIdleTimeout?: number
+  http2?: boolean
+  responseTimeout?: number
+  httpCompression?: boolean
+  restFormat?: RestFormat
+  maxResponseMb?: number // Bigger responses are refused; 0 = unlimited
+  capabilityOverrides?: Record<string, boolean>
+  publicUrl?: string
+  clusterUrls?: string // Comma-separated URLs of a cluster's nodes
+  clusterPrimary?: string // Node catalog changes go to; the first cluster URL when empty
+  group?: string // Folder in the connections tree; ungrouped when empty
+  environment?: string // e.g. 'development', 'staging' or 'production'
+  badge?: string // Short emoji or text shown next to the name
+  color?: string // Badge color as #rrggbb
+  badgeColor?: string // color, or the environment's default color
+  readOnly?: boolean // Changes to the server are refused
+  confirmPolicy?: ConfirmPolicy
+  typedConfirmation?: boolean // Destructive operations need the resource name typed
+  authType?: AuthType
+  oidcIssuer?: string // e.g. https://sso.example.com/realms/gis
+  oidcClientId?: string
+  oidcScope?: string
+  signedIn?: boolean | null // Whether a refresh token is kept; null for basic auth
+  source?: 'config' | 'env' | 'flag' // env/flag connections are not saved
 }
 
 export interface ConnectionCreate {
@@ -13,6 +50,30 @@ export interface ConnectionCreate {
   url: string
   username: string
   password: string
+  autoTruncateCache?: boolean
+  maxConcurrentRequests?: number
+  maxRequestsPerSecond?: number
+  poolMaxIdle?: number
+  poolIdleTimeout?: number
+  http2?: boolean
+  responseTimeout?: number
+  httpCompression?: boolean
+  restFormat?: RestFormat
+  maxResponseMb?: number // Bigger responses are refused; 0 = unlimited
+  capabilityOverrides?: Record<string, boolean>
+  publicUrl?: string
+  clusterUrls?: string
+  clusterPrimary?: string
+  group?: string
+  environment?: string
+  badge?: string
+  color?: string
+  readOnly?: boolean
+  confirmPolicy?: ConfirmPolicy
+  authType?: AuthType
+  oidcIssuer?: string
+  oidcClientId?: string
+  oidcScope?: string
 }
 
 export interface ServerInfo {
@@ -23,6 +84,130 @@ export interface ServerInfo {
   GeoWebCacheVersion: string
 }
 
+export interface ServerFeature {
+  name: string
+  description: string
+  minVersion: string | null // null for features provided by a plugin
+  extension?: string // Jar name prefix of the plugin providing the feature
+  supported: boolean
+}
+
+export interface ServerCapabilities {
+  version: string | null
+  features: ServerFeature[]
+}
+
+// Device sign-in waiting for the user to enter the code at the provider
+export interface DeviceSignIn {
+  userCode: string
+  verificationUri: string
+  verificationUriComplete: string | null
+  interval: number // Seconds between checks
+  expiresIn: number // Seconds left to enter the code
+}
+
+export interface SignInStatus {
+  signedIn: boolean
+  pending: DeviceSignIn | null
+}
+
+// What a connection's credentials may do, probed on the server
+export interface ServerPermissions {
+  canRead: boolean
+  canWrite: boolean
+  isAdmin: boolean
+  reason: string | null // Why changes are refused, when they are
+}
+
+// Extension or community module found in the server's jar manifests
+export interface InstalledModule {
+  name: string
+  label: string
+  kind: 'extension' | 'community'
+  version: string
+}
+
+// One timed request of a connection probe
+export interface ProbeStep {
+  name: 'rest' | 'getmap' | 'getfeature'
+  label: string
+  ms: number | null // Median for REST round trips
+  firstByteMs: number | null
+  bytes: number
+  throughput: number | null // Bytes per second after the first byte
+  detail: string
+  error: string
+}
+
+// Latency, render time and throughput of a connection, with what they point at
+export interface ProbeResult {
+  connection: string
+  layer: string | null
+  steps: ProbeStep[]
+  findings: string[]
+}
+
+// Broken catalog reference found by the integrity checker
+export interface CatalogLintIssue {
+  check: string
+  severity: 'error' | 'warning'
+  resource: string
+  message: string
+  fix: string
+}
+
+export interface CatalogLintReport {
+  issues: CatalogLintIssue[]
+  counts: { error: number; warning: number }
+  checks: { name: string; description: string }[]
+}
+
+// Action run on several marked layers at once
+export type BatchAction = 'delete' | 'truncate' | 'export' | 'group'
+
+export interface BatchResult {
+  target: string
+  ok: boolean
+  message: string
+  data: Record<string, unknown>
+}
+
+export interface BatchResponse {
+  action: BatchAction
+  results: BatchResult[]
+  counts: { ok: number; failed: number }
+}
+
+// URL or name offered by the copy action on a tree node
+export interface CopyTarget {
+  kind: 'name' | 'wms' | 'wfs' | 'wmts' | 'xyz' | 'tms' | 'wmsc' | 'rest' | 'url'
+  label: string
+  value: string
+}
+
+export interface CopyTargetQuery {
+  connectionId: string
+  type: string
+  workspace?: string
+  name?: string
+  store?: string
+  storeType?: string
+}
+
+// Layer or style recently added or changed, from GeoServer's timestamps or the local log
+export interface RecentItem {
+  type: 'layer' | 'style'
+  name: string
+  workspace: string | null
+  time: string
+  source: 'server' | 'local'
+}
+
+export interface RecentItems {
+  added: RecentItem[]
+  modified: RecentItem[]
+}
+
 export interface TestConnectionResult {
   success: boolean
   message: string
@@ -46,20 +231,156 @@ export interface WorkspaceConfig {
   wcsEnabled: boolean
   wpsEnabled: boolean
   wfsEnabled: boolean
+  // Create from a workspace template, enabling only the listed services
+  template?: string
+  services?: string[]
+}
+
+// Named setup a new workspace can be created from
+export interface WorkspaceTemplate {
+  name: string
+  description: string
+  isolated: boolean
+  services: string[]
+  contact: Record<string, string>
+  datastores: string[]
+  styles: string[]
+}
+
+// One step of setting up a workspace from a template
+export interface WorkspaceTemplateStep {
+  kind: 'workspace' | 'service' | 'contact' | 'datastore' | 'style'
+  name: string
+  ok: boolean
+  error?: string
+  existed?: boolean
+}
+
+export interface WorkspaceCreateResult {
+  message: string
+  alreadyExists: boolean
+  steps?: WorkspaceTemplateStep[]
+}
+
+// Naming conventions new and renamed resources must follow
+export type NamingResourceType =
+  | 'workspace'
+  | 'datastore'
+  | 'coveragestore'
+  | 'layer'
+  | 'style'
+  | 'layergroup'
+
+export interface NamingConvention {
+  resourceType: NamingResourceType | '*'
+  pattern: string
+  forbiddenCharacters: string
+  prefix: string
+  description: string
+}
+
+export interface NamingConventions {
+  conventions: NamingConvention[]
+  slugifyUploadNames: boolean
+}
+
+export interface NameCheck {
+  valid: boolean
+  problems: string[]
+}
+
+// What a recursive workspace delete removes
+// Server-side job of the Backup and Restore plugin
+export type BackupKind = 'backup' | 'restore'
+
+export interface BackupExecution {
+  id: number
+  kind: BackupKind
+  status: string // Spring Batch status, e.g. STARTED, COMPLETED, FAILED
+  progress: string // Steps done, e.g. "3/9"
+  archiveFile: string
+  errors: string[]
+  finished: boolean
+  succeeded: boolean
+}
+
+export interface BackupJobOptions {
+  options: { name: string; description: string }[]
+  archiveFile: string // Suggested path on the server
+}
+
+export type GeofenceAccess = 'ALLOW' | 'DENY' | 'LIMIT'
+
+// A GeoFence data security rule; null match fields mean any
+export interface GeofenceRule {
+  id: number | null
+  priority: number // Lower is checked first
+  access: GeofenceAccess
+  user: string | null
+  role: string | null
+  addressRange: string | null // CIDR, e.g. 10.0.0.0/8
+  service: string | null
+  request: string | null
+  workspace: string | null
+  layer: string | null
+  area: string | null // EWKT multipolygon; LIMIT rules only
+  catalogMode: string | null // LIMIT rules only
+}
+
+export interface GeofenceRules {
+  available: boolean // False without the GeoFence extension
+  rules: GeofenceRule[]
+  accessTypes: GeofenceAccess[]
+  catalogModes: string[]
+  services: string[]
+}
+
+export interface BackupJobRequest {
+  archiveFile?: string
+  workspaces?: string[]
+  storeFilter?: string
+  layerFilter?: string
+  options?: Record<string, boolean>
+}
+
+export interface WorkspaceImpact {
+  workspace: string
+  stores: { name: string; type: 'datastore' | 'coveragestore' }[]
+  layers: string[]
+  styles: string[]
+  layerGroups: string[]
+  caches: string[]
+  counts: {
+    stores: number
+    layers: number
+    styles: number
+    layerGroups: number
+    caches: number
+  }
 }
 
 // Store types
 export interface DataStore {
   name: string
   type?: string
-  enabled: boolean
+  // Only listed when asked for; null when unknown
+  enabled?: boolean | null
   workspace: string
+  // Only in a store's details; passwords are masked
+  connectionParameters?: { entry: DataStoreParameter[] }
+}
+
+// A connection parameter as GeoServer lists it
+export interface DataStoreParameter {
+  '@key': string
+  $: string
 }
 
 export interface CoverageStore {
   name: string
   type?: string
-  enabled: boolean
+  // Only listed when asked for; null when unknown
+  enabled?: boolean | null
   workspace: string
   description?: string
 }
@@ -76,6 +397,13 @@ export interface CoverageStoreCreate {
   url: string
 }
 
+// How layer names are made from the tables of a data store
+export interface LayerNamingRules {
+  prefix: string
+  suffix: string
+  sanitize: boolean
+}
+
 // Layer types
 export interface Layer {
   name: string
@@ -131,6 +459,8 @@ export interface LayerMetadata {
   attributionTitle?: string
   attributionHref?: string
   attributionLogo?: string
+  attributionLogoWidth?: number | null
+  attributionLogoHeight?: number | null
   metadataLinks?: MetadataLink[]
   defaultStyle?: string
   maxFeatures?: number
@@ -148,6 +478,9 @@ export interface LayerMetadataUpdate {
   queryable?: boolean
   attributionTitle?: string
   attributionHref?: string
+  attributionLogo?: string
+  attributionLogoWidth?: number | null
+  attributionLogoHeight?: number | null
   metadataLinks?: MetadataLink[]
 }
 
@@ -224,6 +557,189 @@ export interface UploadResult {
   message: string
   storeName?: string
   storeType?: string
+  // Set when a multi-dataset archive went through the Importer extension
+  importId?: number
+  importedLayers?: string[]
+  importedStores?: string[]
+  failedTasks?: ImportTaskFailure[]
+  convertedToCog?: boolean
+  mode?: UploadTarget['mode']
+  verification?: UploadVerification // Set when checksums were asked for
+  metadata?: LayerMetadata // Set on the layer a new store published
+  metadataWarning?: string
+}
+
+// Title, abstract and keywords of a layer published from an upload
+export interface LayerMetadata {
+  title: string
+  abstract: string
+  keywords: string[]
+}
+
+// SHA-256 of the uploaded files compared with what GeoServer stored
+export interface UploadVerification {
+  verified: boolean
+  files: {
+    name: string
+    expected: string
+    actual: string | null
+    status: 'match' | 'mismatch' | 'missing'
+  }[]
+  error: string | null // Why the files couldn't be checked
+}
+
+// The store an upload goes into and the layer it publishes
+export interface UploadTarget {
+  workspace: string
+  store: string
+  kind: string | null
+  storeType: 'datastore' | 'coveragestore' | null
+  mode: 'create' | 'append' | 'replace'
+  layer: string | null // null when GeoPackage tables are chosen after upload
+  error: string | null
+}
+
+// Whether an uploaded GeoTIFF should become a Cloud Optimized GeoTIFF first
+export interface CogAdvice {
+  fileSize: number
+  layout: {
+    width: number
+    height: number
+    tiled: boolean
+    overviews: number
+    compressed: boolean
+    bands: number
+    bits: number
+    cog: boolean
+  } | null
+  needsConversion: boolean
+  reasons: string[]
+  estimatedSize: number
+  estimatedSeconds: number
+  autoConvert: boolean // Over the threshold in app settings
+  gdalAvailable: boolean
+}
+
+// What a local file holds, from gdalinfo/ogrinfo or the style text
+export interface FileSummary {
+  name: string
+  kind: string | null
+  driver: string | null
+  crs: string | null
+  extent: [number, number, number, number] | null // minx, miny, maxx, maxy
+  size: [number, number] | null // Raster width, height
+  bands: string[]
+  layers: {
+    name: string
+    geometryType: string | null
+    featureCount: number | null
+    fields: { name: string; type: string }[]
+  }[]
+  styleText: string | null
+  styleLanguage: 'xml' | 'css' | null
+  error: string | null
+}
+
+export interface FilePreview {
+  summary: FileSummary
+  thumbnail: string | null // PNG data URL, when GDAL can draw the file
+  metadata: LayerMetadata | null // Suggested for the published layer; null for styles
+}
+
+// Application-wide settings shared with the TUI
+export interface AppSettings {
+  theme: string
+  pingIntervalSecs: number
+  lastLocalPath: string
+  cogAutoConvertMb: number // 0 = always ask
+  timeouts: RequestTimeouts
+}
+
+// Seconds a request may go without sending or receiving a byte; 0 = no limit
+export interface RequestTimeouts {
+  upload: number
+  download: number
+  ows: number
+}
+
+// A command or webhook run when resources change
+export interface EventHook {
+  name: string
+  events: string[] // e.g. 'layer.published', 'style.*' or '*'
+  command: string
+  url: string
+  method: string
+  headers: Record<string, string>
+  body: string // Webhook body template; the event as JSON when empty
+  connections: string[] // Connection IDs; all when empty
+  enabled: boolean
+  timeout: number
+}
+
+export interface EventHookDelivery {
+  hook: string
+  event: string
+  success: boolean
+  message: string
+  durationMs: number
+  time: string
+}
+
+export interface EventHooks {
+  hooks: EventHook[]
+  events: { name: string; description: string }[]
+  deliveries: EventHookDelivery[]
+}
+
+// Styles newly published layers get in a workspace, by geometry type
+export interface DefaultStyleRule {
+  workspace: string // Workspace name, or '*' for workspaces without a rule of their own
+  point: string
+  line: string
+  polygon: string
+  raster: string
+  connections: string[] // Connection IDs; all when empty
+}
+
+export type DefaultStyleGeometry = 'point' | 'line' | 'polygon' | 'raster'
+
+export interface DefaultStyles {
+  rules: DefaultStyleRule[]
+  geometryTypes: DefaultStyleGeometry[]
+}
+
+export type BatchFileKind = 'shapefile' | 'geotiff' | 'geopackage' | 'style'
+
+// How one file of a directory upload is published
+export interface BatchUploadItem {
+  path: string
+  kind: BatchFileKind
+  workspace: string
+  store: string
+  layer: string
+}
+
+export interface BatchUploadPlan {
+  items: BatchUploadItem[]
+  skipped: number
+}
+
+export interface BatchUploadItemResult {
+  path: string
+  kind: BatchFileKind
+  status: 'success' | 'error'
+  workspace?: string
+  store?: string
+  layer?: string
+  style?: string
+  error?: string
+}
+
+export interface ImportTaskFailure {
+  id: number
+  state: string
+  layer: string
+  error: string
 }
 
 // Preview types
@@ -251,6 +767,9 @@ export type NodeType =
   | 'iceberg'        // "Apache Iceberg" container
   | 'qfieldcloud'    // "QFieldCloud" container
   | 'connection'     // GeoServer connection
+  | 'connectiongroup' // Folder of GeoServer connections
+  | 'recentmodified' // Recently modified layers and styles of a connection
+  | 'recentadded'    // Recently added layers and styles of a connection
   | 'pgservice'      // pg_service.conf entry
   | 'pgschema'       // PostgreSQL schema
   | 'pgtable'        // Database table
@@ -367,6 +886,17 @@ export interface GWCLayer {
   mimeFormats?: string[]
 }
 
+// A cached layer group and the layers drawn into its tiles
+export interface GWCCachedGroup {
+  name: string
+  layers: { type: string; name: string }[]
+}
+
+export interface GWCLayerList {
+  layers: string[]
+  groups: GWCCachedGroup[]
+}
+
 export interface GWCSeedRequest {
   gridSetId: string
   zoomStart: number
@@ -385,6 +915,35 @@ export interface GWCBounds {
   srs: string
 }
 
+export interface GWCSeedEstimateRequest {
+  gridSet: string
+  zoomStart: number
+  zoomStop: number
+  format: string
+  bounds?: [number, number, number, number]
+  // SRS of the bounds when it isn't the grid set's
+  boundsSrs?: string
+  avgTileBytes?: number
+}
+
+export interface GWCZoomEstimate {
+  zoom: number
+  columns: number
+  rows: number
+  tiles: number
+}
+
+export interface GWCSeedEstimate {
+  gridSet: string
+  format: string
+  bounds: [number, number, number, number]
+  boundsSource: 'request' | 'latLonBoundingBox' | 'nativeBoundingBox' | 'gridSetExtent'
+  avgTileBytes: number
+  totalTiles: number
+  estimatedBytes: number
+  zoomLevels: GWCZoomEstimate[]
+}
+
 export interface GWCSeedTask {
   id: number
   tilesDone: number
@@ -414,6 +973,190 @@ export interface GWCDiskQuota {
   globalQuota?: string
 }
 
+export type GWCScheduleOperation = 'seed' | 'reseed' | 'truncate'
+
+export interface GWCSchedule {
+  id: string
+  name: string
+  connectionId: string
+  layer: string
+  cron: string
+  operation: GWCScheduleOperation
+  gridSet: string
+  format: string
+  zoomStart: number
+  zoomStop: number
+  threads: number
+  // Lon/lat area to seed, the whole layer when null
+  bounds?: [number, number, number, number] | null
+  enabled: boolean
+  createdAt: string
+  lastRunAt?: string | null
+  lastStatus?: 'success' | 'failed' | null
+  lastError?: string | null
+  nextRunAt?: string | null
+}
+
+export type GWCScheduleCreate = Omit<
+  GWCSchedule,
+  'id' | 'createdAt' | 'lastRunAt' | 'lastStatus' | 'lastError' | 'nextRunAt'
+>
+
+// Nightly snapshot of connections' catalogs into a folder or bucket
+export interface SnapshotSchedule {
+  id: string
+  name: string
+  connectionIds: string[]
+  cron: string
+  destination: 'local' | 's3'
+  directory: string // Folder, for local
+  s3ConnectionId: string // For s3
+  bucket: string
+  prefix: string
+  includeData: boolean
+  keepDaily: number // Newest snapshot of each of the last N days
+  keepWeekly: number // Newest snapshot of each of the last M weeks
+  enabled: boolean
+  createdAt: string
+  lastRunAt?: string | null
+  lastStatus?: 'success' | 'failed' | null
+  lastError?: string | null
+  nextRunAt?: string | null
+}
+
+export type SnapshotScheduleCreate = Omit<
+  SnapshotSchedule,
+  'id' | 'createdAt' | 'lastRunAt' | 'lastStatus' | 'lastError' | 'nextRunAt'
+>
+
+export interface StoredSnapshot {
+  name: string // e.g. snapshot-conn_123-20240101-020000.zip
+  connectionId: string
+  created: string
+  size: number
+}
+
+// Snapshot files whose resources were created, already existed or failed (path -> error)
+export interface SnapshotRestoreReport {
+  folder: string
+  created: string[]
+  existing: string[]
+  failed: Record<string, string>
+}
+
+// A cached layer checked the way a tile client would use it
+export interface GWCEndpointCheck {
+  layer: string
+  gridSet: string
+  format: string
+  gridSets: string[]
+  formats: string[]
+  tileUrl: string
+  statusCode: number | null
+  contentType: string
+  size: number
+  issues: string[]
+  ok: boolean
+}
+
+export interface GWCTileEndpoints {
+  check: GWCEndpointCheck
+  endpoints: CopyTarget[]
+}
+
+export interface GWCHitRatioRequest {
+  // All the layer's grid sets when omitted
+  gridSets?: string[]
+  zoomStart?: number
+  zoomStop?: number
+  samples?: number
+  format?: string
+}
+
+export interface GWCZoomHitRatio {
+  gridSet: string
+  zoom: number
+  tiles: number
+  sampled: number
+  hits: number
+  misses: number
+  errors: number
+  hitRatio: number | null // 0-1, null when no tile was answered
+  worthSeeding: boolean
+}
+
+export interface GWCSeedSuggestion {
+  gridSet: string
+  zoomStart: number
+  zoomStop: number
+  tiles: number
+}
+
+export interface GWCHitRatio {
+  layer: string
+  format: string
+  hitRatio: number | null
+  // Server-wide figures from GWC's runtime statistics, when it keeps them
+  runtime: { requests: number | null; hitRatio: number | null } | null
+  zooms: GWCZoomHitRatio[]
+  seedSuggestions: GWCSeedSuggestion[]
+  seedHitRatio: number
+  seedTileLimit: number
+}
+
+export interface GWCStyleLayer {
+  layer: string
+  usage: 'default' | 'additional'
+}
+
+// Background truncation of many tile caches, polled for progress
+export interface MassTruncateJob {
+  id: string
+  connectionId: string
+  operationId: string // In the operation log while layers are left to truncate
+  status: 'running' | 'completed'
+  total: number
+  done: number
+  results: BatchResult[]
+  summary: { total: number; truncated: number; skipped: number; failed: number }
+  createdAt: string
+  completedAt: string
+}
+
+// A mass truncation, bulk publish or sync that was cut short or had failed steps,
+// which can be resumed from the last finished step
+export interface InterruptedOperation {
+  id: string
+  kind: 'truncate' | 'upload' | 'sync'
+  connectionId: string // The source connection of a sync
+  total: number
+  done: number
+  pending: string[] // Layer names, file paths, or destination/resource of a sync
+  failed: Record<string, string> // Step -> last error
+  params: Record<string, unknown>
+  createdAt: string
+}
+
+// A Mapbox GL style or MapProxy snippet drawing layers from their cached tiles
+export type MapExportFormat = 'mapbox' | 'mapproxy'
+
+export interface MapExportRequest {
+  layers: string[] // Qualified names, bottom first
+  format: MapExportFormat
+  name?: string
+  gridSet?: string
+  tileFormat?: string
+  directory?: string // The file browser's folder by default
+  write?: boolean
+}
+
+export interface MapExport {
+  format: MapExportFormat
+  filename: string
+  content: string
+  path: string | null // Where it was written
+}
+
 // GeoServer Contact/Settings types
 export interface GeoServerContact {
   contactPerson?: string
@@ -432,6 +1175,18 @@ export interface GeoServerContact {
   welcome?: string
 }
 
+// WMS watermark drawn on every map
+export interface WmsWatermark {
+  enabled: boolean
+  url: string | null // URL, or a path in the data directory
+  position: string
+  transparency: number // Percent; 0 draws the image as it is
+}
+
+export interface WmsWatermarkSettings extends WmsWatermark {
+  positions: string[]
+}
+
 // Sync types
 export type DataStoreSyncStrategy = 'same_connection' | 'geopackage_copy' | 'skip'
 
@@ -444,6 +1199,8 @@ export interface SyncOptions {
   layergroups: boolean
   workspace_filter?: string[]
   datastore_strategy?: DataStoreSyncStrategy
+  prune?: boolean // Delete managed resources the source no longer has
+  adopt?: boolean // Also update existing resources the client didn't create
 }
 
 export interface SyncConfiguration {
@@ -481,11 +1238,69 @@ export interface StartSyncRequest {
   options?: SyncOptions
 }
 
+// Pushes chosen styles only; an empty workspace promotes global styles
+export interface PromoteStylesRequest {
+  sourceId: string
+  destinationIds: string[]
+  workspace?: string
+  styles: string[]
+}
+
+export interface StylePromotionCounts {
+  created: number
+  updated: number
+  skipped: number // Already the same as the source's
+  errors: { style: string; error: string }[]
+}
+
+export interface PromoteStylesResponse {
+  sourceId: string
+  destinationIds: string[]
+  results: Record<string, { styles: StylePromotionCounts }> // By destination ID
+}
+
+export type SettingsSection = 'contact' | 'wms' | 'wfs' | 'wcs' | 'wmts'
+
+// Copies contact details and service metadata; dryRun only lists the differences
+export interface SyncSettingsRequest {
+  sourceId: string
+  destinationIds: string[]
+  sections?: SettingsSection[]
+  dryRun?: boolean
+}
+
+export interface SettingChange {
+  section: SettingsSection
+  key: string
+  current: string // Empty when the server doesn't set it
+  value: string
+}
+
+export interface ServerSettingsDiff {
+  connectionId: string
+  name: string
+  changes: SettingChange[]
+  errors: Record<string, string> // Sections that couldn't be read or written
+}
+
+export interface SyncSettingsResponse {
+  sourceId: string
+  sections: SettingsSection[]
+  sourceErrors: Record<string, string>
+  servers: ServerSettingsDiff[]
+  changeCount: number
+  applied: boolean
+}
+
 // Dashboard types
 export interface ServerStatus {
   connectionId: string
   connectionName: string
   url: string
+  group: string
+  environment: string
+  badge: string
+  badgeColor: string
   online: boolean
   responseTimeMs: number
   memoryUsed: number
@@ -500,6 +1315,21 @@ export interface ServerStatus {
   styleCount: number
   error?: string
   geoserverVersion?: string
+  nodes?: NodeHealth[] // Cluster connections only, primary first
+}
+
+// Health of one node of a cluster connection
+export interface NodeHealth {
+  url: string
+  primary: boolean
+  online: boolean
+  responseTimeMs: number
+  memoryUsed: number
+  memoryTotal: number
+  memoryUsedPct: number
+  cpuLoad: number
+  error: string
+  statusError: string // Why memory and CPU couldn't be read from an online node
 }
 
 export interface DashboardData {
@@ -510,6 +1340,224 @@ export interface DashboardData {
   totalStores: number
   alertServers: ServerStatus[]
   pingIntervalSecs: number // Dashboard refresh interval from settings
+  profile: string // Config profile in use; empty when started with --config
+}
+
+export interface LayerQuotaUsage {
+  layer: string
+  limitBytes: number | null
+  usedBytes: number | null
+}
+
+// Recent requests for a layer recorded by the Monitoring extension
+export interface LayerRequestStats {
+  layer: string
+  days: number
+  requests: number
+  lastRequest: string | null
+  complete: boolean // False when only the newest requests were counted
+}
+
+// GetMap render times of a layer, from a benchmark run
+export interface RenderBenchmarkRequest {
+  style?: string // The layer's default style when empty
+  zooms?: number[] // Three zoom levels from the one fitting the layer when empty
+  samples?: number // Places per zoom level
+  size?: number // Image width and height: 256, 512 or 1024
+  seed?: number
+}
+
+export interface RenderBenchmarkZoom {
+  zoom: number
+  scale: number // Scale denominator
+  requests: number
+  errors: number
+  p50: number | null // Seconds
+  p95: number | null
+  meanBytes: number
+  slow: boolean // p95 above slowSeconds
+}
+
+export interface RenderBenchmark {
+  workspace: string
+  layer: string
+  style: string
+  size: number
+  p50: number | null
+  p95: number | null
+  slowSeconds: number
+  zooms: RenderBenchmarkZoom[]
+  samples: {
+    zoom: number
+    bbox: [number, number, number, number]
+    seconds: number
+    bytes: number
+    error: string | null
+  }[]
+}
+
+export interface EndpointResult {
+  service: 'wms' | 'wms-native' | 'wfs' | 'wmts' | 'legend'
+  label: string // e.g. "WMS GetMap (EPSG:4326)"
+  status: 'pass' | 'fail' | 'skip'
+  detail: string // What came back, GeoServer's error, or why it was skipped
+  elapsedMs: number
+}
+
+export interface LayerEndpointReport {
+  workspace: string
+  layer: string
+  ok: boolean // No endpoint failed
+  passed: number
+  failed: number
+  skipped: number
+  results: EndpointResult[]
+}
+
+export interface LayerAttributeTable {
+  fields: string[]
+  types: Record<string, string> // Attribute type by field, e.g. "string" or "int"
+  nillable: Record<string, boolean>
+  geometryType: string | null // e.g. "MultiPolygon"
+  rows: Record<string, unknown>[]
+  total: number
+  limit: number
+  offset: number
+  hasMore: boolean
+}
+
+export interface LayerAttributeQuery {
+  sort?: string
+  order?: 'asc' | 'desc'
+  filters?: Record<string, string> // Text to match, or a comparison such as ">= 10"
+  cql?: string // Applied along with the column filters
+}
+
+export interface SchemaAttribute {
+  name: string
+  type: string // Qualified type, e.g. "xsd:string" or "gml:MultiPolygon"
+  localType: string
+  nillable: boolean
+  minOccurs: number
+  maxOccurs: number
+  geometry: boolean
+}
+
+export interface FeatureTypeSchema {
+  typeName: string
+  namespace: string | null
+  attributes: SchemaAttribute[]
+  geometryAttribute: string | null
+  geometryType: string | null
+  srs: string | null
+  srid: number | null
+}
+
+export type FilterAttributeKind = 'text' | 'number' | 'date' | 'boolean' | 'geometry'
+
+export interface FilterAttribute {
+  name: string
+  type: string
+  kind: FilterAttributeKind
+  nillable: boolean
+  operators: string[]
+}
+
+export interface FilterCondition {
+  attribute: string
+  operator: string
+  value: string
+}
+
+export type FilterMatch = 'all' | 'any'
+
+export interface FilterValidation {
+  cql: string
+  valid: boolean
+  error: string | null
+  count: number | null // Matching features when valid
+}
+
+export interface DiskQuotaUsage {
+  enabled: boolean
+  limitBytes: number | null
+  usedBytes: number | null // Only reported by some GeoWebCache versions
+  usedPct: number | null
+  expirationPolicy: string
+  layers: LayerQuotaUsage[]
+}
+
+export interface DataDirectorySize {
+  bytes: number
+  files: number
+  directories: number
+  complete: boolean // False when the walk stopped early; bytes is then a lower bound
+}
+
+export interface ConnectionStorage {
+  connectionId: string
+  diskQuota: DiskQuotaUsage | null
+  diskQuotaError: string | null
+  seedTasks: { running: number; pending: number } | null
+  seedTasksError: string | null
+  dataDirectory: DataDirectorySize | null
+  dataDirectoryError: string | null
+  timestamp: string
+}
+
+// Storage a workspace uses: its layers' tile caches and its stores' files
+export interface WorkspaceUsage {
+  workspace: string // Empty for global layer groups
+  tileCacheBytes: number
+  storeBytes: number
+  totalBytes: number
+  cachedLayers: number
+  sizedStores: number
+  externalStores: number // PostGIS and other stores outside the data directory
+  complete: boolean // False when a store couldn't be sized in full
+}
+
+export interface WorkspaceUsageReport {
+  connectionId: string
+  workspaces: WorkspaceUsage[] // Largest first
+  totalBytes: number
+  tileCacheError: string | null
+  storeError: string | null
+  errors: string[]
+  timestamp: string
+}
+
+export type ClusterAction = 'reload' | 'reset'
+
+// Outcome of a reload or reset on one node
+export interface ClusterNodeResult {
+  url: string
+  ok: boolean
+  seconds: number
+  error: string
+}
+
+export interface ClusterActionResult {
+  connectionId: string
+  action: ClusterAction
+  ok: boolean // Every node succeeded
+  nodes: ClusterNodeResult[]
+}
+
+// One setting across the compared servers; a null value couldn't be read
+export interface ComparisonRow {
+  section: string
+  key: string
+  values: Record<string, string | null>
+  differs: boolean
+}
+
+export interface ServerComparison {
+  connections: { id: string; name: string }[]
+  rows: ComparisonRow[]
+  differenceCount: number
+  errors: Record<string, Record<string, string>> // Connection ID -> section -> error
+  timestamp: string
 }
 
 // ============================================================================
@@ -562,7 +1610,7 @@ export interface S3Object {
 }
 
 // Cloud-native format types
-export type CloudNativeFormat = 'cog' | 'copc' | 'geoparquet' | 'parquet' | 'unknown'
+export type CloudNativeFormat = 'cog' | 'copc' | '3dtiles' | 'geoparquet' | 'parquet' | 'unknown'
 
 // Conversion job status
 export type ConversionJobStatus = 'pending' | 'running' | 'completed' | 'failed' | 'cancelled'
@@ -596,13 +1644,14 @@ export interface ConversionToolInfo {
 export interface ConversionToolStatus {
   gdal?: ConversionToolInfo
   pdal?: ConversionToolInfo
+  py3dtiles?: ConversionToolInfo
   ogr2ogr?: ConversionToolInfo
 }
 
 // S3 Upload options
 export interface S3UploadOptions {
   convert?: boolean // Whether to suggest/perform cloud-native conversion
-  targetFormat?: 'cog' | 'copc' | 'geoparquet'
+  targetFormat?: 'cog' | 'copc' | '3dtiles' | 'geoparquet'
 }
 
 // S3 Upload result
@@ -648,6 +1697,23 @@ export interface S3AttributeTableResponse {
   hasMore: boolean
 }
 
+// STAC catalog generation request
+export interface STACGenerateRequest {
+  prefix?: string
+  catalogId?: string
+  title?: string
+  description?: string
+  write?: boolean  // Write catalog files back into the bucket (default true)
+}
+
+// STAC catalog generation result
+export interface STACGenerateResult {
+  catalogId: string
+  itemCount: number
+  written: string[]
+  catalogUrl: string
+}
+
 // ============================================================================
 // DuckDB Query Types
 // ============================================================================
@@ -701,6 +1767,24 @@ export interface QGISProject {
   size: number
 }
 
+// What publishing one layer of a QGIS project to GeoServer did
+export interface QGISPublishOutcome {
+  source: string // Layer name in the project
+  layer: string
+  store: string
+  status: 'published' | 'skipped' | 'error'
+  style: string // Converted style; empty when the layer keeps the default
+  message: string
+}
+
+export interface QGISPublishResult {
+  workspace: string
+  layers: QGISPublishOutcome[]
+  groups: string[] // Innermost first, the whole project's last
+  errors: string[]
+  counts: Record<QGISPublishOutcome['status'], number>
+}
+
 // QGIS Project create/add request
 export interface QGISProjectCreate {
   name: string
@@ -0,0 +1,26 @@
+// OGC service URL helpers.
+//
+// GeoServer exposes each service globally (/wms) and as virtual services
+// scoped to a workspace (/{workspace}/wms) or a single layer
+// (/{workspace}/{layer}/wms). Virtual services only see the workspace's own
+// layers, so identically named layers in different workspaces don't clash.
+
+export type OWSService = 'wms' | 'wfs' | 'wcs' | 'wmts' | 'ows'
+
+export function serviceUrl(
+  geoserverUrl: string,
+  service: OWSService,
+  workspace?: string,
+  layer?: string
+): string {
+  const parts = [workspace, workspace ? layer : undefined, service]
+    .filter((p): p is string => !!p)
+    .map(encodeURIComponent)
+  return `${geoserverUrl.replace(/\/+$/, '')}/${parts.join('/')}`
+}
+
+// Base URL for OGC service and preview links. Behind a proxy this can differ
+// from the URL the backend uses for REST calls.
+export function publicBaseUrl(connection: { url: string; publicUrl?: string }): string {
+  return (connection.publicUrl || connection.url).replace(/\/rest\/?$/, '').replace(/\/+$/, '')
+}